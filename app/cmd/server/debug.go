@@ -0,0 +1,103 @@
+package main
+
+import (
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// defaultGoroutineStacks is how many of the most common goroutine stacks /debug/goroutines lists
+// unless changed with ?top=.
+const defaultGoroutineStacks = 10
+
+// GoroutineStack is a stack shared by Count goroutines, as "function file:line" frames from the
+// innermost call out.
+type GoroutineStack struct {
+	Count  int      `json:"count"`
+	Frames []string `json:"frames"`
+}
+
+// GoroutineSummary counts the goroutines and lists the stacks most of them are in, to spot a leak
+// at a glance without reading a full goroutine dump.
+type GoroutineSummary struct {
+	Count     int              `json:"count"`
+	TopStacks []GoroutineStack `json:"top_stacks"`
+}
+
+// DebugHandler serves the net/http/pprof profiles under /debug/pprof/, the expvar variables at
+// /debug/vars, and a goroutine summary at /debug/goroutines. It is only served on the debug port,
+// never on the public listener.
+func DebugHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.Handle("/debug/vars", expvar.Handler())
+	mux.HandleFunc("/debug/goroutines", serveGoroutines)
+	return mux
+}
+
+// serveGoroutines writes SummarizeGoroutines as JSON, listing as many stacks as ?top= asks for.
+func serveGoroutines(w http.ResponseWriter, r *http.Request) {
+	top := defaultGoroutineStacks
+	if value := r.URL.Query().Get("top"); value != "" {
+		var err error
+		if top, err = strconv.Atoi(value); err != nil || top < 1 {
+			http.Error(w, fmt.Sprintf("invalid top %q: not a positive whole number", value), http.StatusBadRequest)
+			return
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(SummarizeGoroutines(top)); err != nil {
+		http.Error(w, "Failed to encode goroutine summary", http.StatusInternalServerError)
+	}
+}
+
+// SummarizeGoroutines groups the running goroutines by stack and returns the top stacks with the
+// most goroutines, most first.
+func SummarizeGoroutines(top int) GoroutineSummary {
+	// The goroutine count can grow between sizing the records and taking the profile
+	records := make([]runtime.StackRecord, runtime.NumGoroutine()+16)
+	n, ok := runtime.GoroutineProfile(records)
+	for !ok {
+		records = make([]runtime.StackRecord, n+16)
+		n, ok = runtime.GoroutineProfile(records)
+	}
+
+	byStack := make(map[string]*GoroutineStack)
+	var stacks []*GoroutineStack
+	for _, record := range records[:n] {
+		var frames []string
+		callers := runtime.CallersFrames(record.Stack())
+		for {
+			frame, more := callers.Next()
+			frames = append(frames, fmt.Sprintf("%s %s:%d", frame.Function, frame.File, frame.Line))
+			if !more {
+				break
+			}
+		}
+		key := strings.Join(frames, "\n")
+		if stack, found := byStack[key]; found {
+			stack.Count++
+			continue
+		}
+		stack := &GoroutineStack{Count: 1, Frames: frames}
+		byStack[key] = stack
+		stacks = append(stacks, stack)
+	}
+	sort.SliceStable(stacks, func(i, j int) bool { return stacks[i].Count > stacks[j].Count })
+
+	summary := GoroutineSummary{Count: n, TopStacks: []GoroutineStack{}}
+	for i := 0; i < len(stacks) && i < top; i++ {
+		summary.TopStacks = append(summary.TopStacks, *stacks[i])
+	}
+	return summary
+}
@@ -0,0 +1,74 @@
+package main_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	main "github.com/CharlRitter/brewsource-mcp/app/cmd/server"
+)
+
+// parkGoroutine blocks until release is closed, giving the goroutine summary a stack to count.
+func parkGoroutine(release <-chan struct{}) {
+	<-release
+}
+
+func TestDebugHandler(t *testing.T) {
+	server := httptest.NewServer(main.DebugHandler())
+	defer server.Close()
+
+	release := make(chan struct{})
+	defer close(release)
+	for i := 0; i < 25; i++ {
+		go parkGoroutine(release)
+	}
+
+	get := func(path string) (int, string) {
+		t.Helper()
+		resp, err := http.Get(server.URL + path)
+		if err != nil {
+			t.Fatalf("Request to %s failed: %v", path, err)
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", path, err)
+		}
+		return resp.StatusCode, string(body)
+	}
+
+	status, body := get("/debug/goroutines?top=3")
+	if status != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", status, body)
+	}
+	var summary main.GoroutineSummary
+	if err := json.Unmarshal([]byte(body), &summary); err != nil {
+		t.Fatalf("Failed to decode goroutine summary: %v", err)
+	}
+	if summary.Count < 25 {
+		t.Errorf("Expected at least 25 goroutines, got %d", summary.Count)
+	}
+	if len(summary.TopStacks) == 0 || len(summary.TopStacks) > 3 {
+		t.Fatalf("Expected 1 to 3 stacks, got %d", len(summary.TopStacks))
+	}
+	top := summary.TopStacks[0]
+	if top.Count < 25 || !strings.Contains(strings.Join(top.Frames, "\n"), ".parkGoroutine ") {
+		t.Errorf("Expected the parked goroutines to top the summary, got %d in %v", top.Count, top.Frames)
+	}
+
+	if status, _ = get("/debug/goroutines?top=none"); status != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an invalid top, got %d", status)
+	}
+	if status, body = get("/debug/vars"); status != http.StatusOK || !strings.Contains(body, `"memstats"`) {
+		t.Errorf("Expected expvar variables, got status %d", status)
+	}
+	if status, body = get("/debug/pprof/"); status != http.StatusOK || !strings.Contains(body, "goroutine") {
+		t.Errorf("Expected the pprof index, got status %d", status)
+	}
+	if status, _ = get("/debug/pprof/goroutine?debug=1"); status != http.StatusOK {
+		t.Errorf("Expected the goroutine profile, got status %d", status)
+	}
+}
@@ -4,62 +4,122 @@ package main
 import (
 	"context"
 	"errors"
+	"expvar"
 	"flag"
 	"fmt"
+	"io"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
+	_ "time/tzdata" // venue opening hours need time zones; the container image has no zoneinfo
 
+	"github.com/CharlRitter/brewsource-mcp/app/internal/config"
 	"github.com/CharlRitter/brewsource-mcp/app/internal/handlers"
 	"github.com/CharlRitter/brewsource-mcp/app/internal/mcp"
 	"github.com/CharlRitter/brewsource-mcp/app/internal/models"
 	"github.com/CharlRitter/brewsource-mcp/app/internal/services"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/version"
 	"github.com/CharlRitter/brewsource-mcp/app/pkg/data"
 	"github.com/jmoiron/sqlx"
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
 	"github.com/redis/go-redis/v9"
 	"github.com/sirupsen/logrus"
 )
 
 const (
-	// Database connection pool settings.
-	maxOpenConns    = 25
-	maxIdleConns    = 5
-	connMaxLifetime = 5 * time.Minute
-
 	// Network timeout settings.
 	redisTimeout    = 5 * time.Second
-	readTimeout     = 30 * time.Second
-	writeTimeout    = 30 * time.Second
-	idleTimeout     = 120 * time.Second
 	shutdownTimeout = 30 * time.Second
+	// Startup retries the database with attempts starting connectBackoff apart, doubling up to
+	// maxConnectBackoff, until DB_CONNECT_TIMEOUT runs out.
+	connectBackoff    = 250 * time.Millisecond
+	maxConnectBackoff = 10 * time.Second
 )
 
 func main() {
 	// Command line flags
-	port := flag.String("port", "8080", "Port for HTTP server")
+	configFlags := config.RegisterFlags(flag.CommandLine)
+	printConfig := flag.Bool("print-config", false, "Print the effective configuration, secrets redacted, and exit")
+	importBreweries := flag.String("import-breweries", "", "Import breweries from a .csv or .json file and exit")
+	importBeers := flag.String("import-beers", "", "Import beers from a .csv or .json file and exit")
+	importUpsert := flag.Bool("import-upsert", false, "Update existing breweries and beers instead of skipping them")
+	importStrict := flag.Bool("import-strict", false, "Import nothing and exit non-zero if any row fails")
+	syncBreweries := flag.Bool("sync-breweries", false, "Sync breweries from Open Brewery DB and exit")
+	seed := flag.Bool("seed", false, "Seed the missing sample breweries and beers and exit")
+	seedFile := flag.String("seed-file", "", "Seed the missing breweries and beers of a JSON seed file and exit")
+	reseed := flag.Bool("reseed", false, "Delete every brewery and beer and seed them again, with -force, and exit")
+	force := flag.Bool("force", false, "Confirm -reseed")
+	validateData := flag.Bool("validate-data", false, "Load and validate the BJCP style and ingredient data and exit")
+	migrate := flag.Bool("migrate", false, "Apply pending database migrations and exit")
+	migrateDown := flag.Int("migrate-down", 0, "Revert the last n applied database migrations and exit")
+	showVersion := flag.Bool("version", false, "Print version information and exit")
 	flag.Parse()
 
-	// Initialize logger
-	logrus.SetFormatter(&logrus.JSONFormatter{})
-	if os.Getenv("LOG_LEVEL") == "debug" {
-		logrus.SetLevel(logrus.DebugLevel)
+	if *showVersion {
+		fmt.Printf("brewsource-mcp %s\n", version.Get())
+		return
+	}
+
+	// Load configuration: defaults, then -config, then the environment, then flags
+	cfg, err := config.Load(configFlags, os.Getenv)
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+	if *printConfig {
+		if err = cfg.Redacted().WriteYAML(os.Stdout); err != nil {
+			log.Fatalf("Failed to print configuration: %v", err)
+		}
+		return
+	}
+	cfg.ApplyLogging()
+	// The seed and style data are looked up in the environment, also when reloaded on SIGHUP
+	for name, path := range cfg.DataPathEnv() {
+		if err = os.Setenv(name, path); err != nil {
+			log.Fatalf("Failed to set %s: %v", name, err)
+		}
+	}
+
+	if *importBreweries != "" || *importBeers != "" {
+		opts := models.ImportOptions{Upsert: *importUpsert, Strict: *importStrict}
+		RunImport(cfg, *importBreweries, *importBeers, opts)
+		return
+	}
+	if *syncBreweries {
+		RunBrewerySync(cfg)
+		return
+	}
+	if *seed || *seedFile != "" || *reseed {
+		if *reseed && !*force {
+			log.Fatal("-reseed deletes every brewery and beer; pass -force to confirm")
+		}
+		RunSeed(cfg, *seedFile, *reseed)
+		return
+	}
+	if *validateData {
+		RunDataValidation()
+		return
+	}
+	if *migrate || *migrateDown != 0 {
+		RunMigrations(cfg, *migrateDown)
+		return
 	}
 
 	// Initialize database
-	db, err := InitDatabase()
+	db, err := InitDatabase(cfg)
 	if err != nil {
 		log.Fatalf("Failed to initialize database: %v", err)
 	}
 
 	// Initialize Redis (optional)
-	var redisClient *redis.Client
-	if redisURL := os.Getenv("REDIS_URL"); redisURL != "" {
-		redisClient = InitRedis(redisURL)
-	}
+	redisClient := InitRedis(cfg)
 
 	// Cleanup function for early exits and normal execution
 	cleanup := func() {
@@ -74,45 +134,164 @@ func main() {
 	}
 
 	// Load BJCP data
-	bjcpData, err := data.LoadBJCPData()
+	guidelines, err := data.LoadBJCPGuidelines()
 	if err != nil {
 		cleanup()
 		log.Fatalf("Failed to load BJCP data: %v", err)
 	}
+	bjcpData := guidelines[data.DefaultGuidelineVersion]
+	ingredientService, err := data.NewIngredientService()
+	if err != nil {
+		cleanup()
+		log.Fatalf("Failed to load ingredient data: %v", err)
+	}
 
 	// Initialize services
 	beerService := services.NewBeerService(db, redisClient)
 	breweryService := services.NewBreweryService(db, redisClient)
-
-	// Initialize handlers
+	ConfigureServices(cfg, beerService, breweryService)
+	relevance := models.HasTrigramSearch(db)
+	beerService.EnableRelevanceSearch(relevance)
+	breweryService.EnableRelevanceSearch(relevance)
+	breweryService.EnableDistanceSearch(models.HasDistanceSearch(db))
+	// Seeding may have changed the catalog since a previous run cached its results
+	InvalidateSearchCaches(beerService, breweryService, redisClient)
+
+	// Initialize handlers, sharing one style guide so that a reload reaches them all
+	bjcpService := data.NewBJCPServiceFromGuidelines(guidelines)
+	logrus.Infof("Loaded BJCP guidelines: %s", strings.Join(bjcpService.Versions(), ", "))
+	if err = RecordBJCPSource(context.Background(), db, bjcpService); err != nil {
+		logrus.Warnf("Failed to record the BJCP data source: %v", err)
+	}
 	toolHandlers := handlers.NewToolHandlers(bjcpData, beerService, breweryService)
+	toolHandlers.SetBJCPService(bjcpService)
+	toolHandlers.SetIngredientService(ingredientService)
+	toolHandlers.SetAdminToken(cfg.AdminToken)
+	toolHandlers.SetBrewerySyncer(NewBrewerySyncer(cfg, db, redisClient))
+	toolHandlers.SetCatalogLoader(&CatalogLoader{
+		DB: db, BeerService: beerService, BreweryService: breweryService, Redis: redisClient,
+		SeedTimeout: cfg.Seed.Timeout,
+	})
+	exportStore := NewExportStore(cfg, redisClient)
+	if exportStore != nil {
+		toolHandlers.SetExportStore(exportStore, handlers.ExportOptions{
+			TTL:      cfg.Export.TTL,
+			MaxBytes: cfg.Export.MaxBytes,
+			MaxRows:  cfg.Export.MaxRows,
+			BaseURL:  cfg.Export.BaseURL,
+		})
+	}
 	resourceHandlers := handlers.NewResourceHandlers(bjcpData, beerService, breweryService)
+	resourceHandlers.SetBJCPService(bjcpService)
+	resourceHandlers.SetIngredientService(ingredientService)
+	resourceHandlers.SetCache(redisClient, cfg.Cache.ResourceTTL)
+	usageLog := NewUsageLog(cfg, db, redisClient)
+	if usageLog != nil {
+		resourceHandlers.SetUsageLog(usageLog, cfg.AdminToken)
+	}
 	webHandlers := handlers.NewWebHandlers(db, redisClient)
+	// InitDatabase has migrated the schema, or startup would have stopped
+	webHandlers.SetMigrated(true)
+	webHandlers.SetBJCPService(bjcpService)
+	webHandlers.SetServices(beerService, breweryService)
+	if exportStore != nil {
+		webHandlers.SetExportStore(exportStore)
+	}
 
 	// Initialize MCP server
-	mcpServer := mcp.NewServer(toolHandlers, resourceHandlers)
+	mcpServer := mcp.NewServer(toolHandlers, resourceHandlers, cfg)
+	toolHandlers.SetResourceNotifier(mcpServer.NotifyResourceUpdated)
+	toolHandlers.SetProgressNotifier(mcpServer.NotifyProgress)
+	// Served at /debug/vars: the calls running and queued of each tool with a concurrency limit
+	expvar.Publish("tool_load", expvar.Func(func() any { return mcpServer.ToolLoad() }))
+	stopUsage := func() {}
+	if usageLog != nil {
+		mcpServer.SetUsageRecorder(handlers.NewUsageRecorder(usageLog))
+		stopUsage = StartUsageLog(usageLog)
+		// Served at /debug/vars, to watch for events dropped under load
+		expvar.Publish("usage_events_dropped", expvar.Func(func() any { return usageLog.Dropped() }))
+		expvar.Publish("usage_events_failed", expvar.Func(func() any { return usageLog.Failed() }))
+	}
+
+	// Reload the style guide on SIGHUP
+	reloadSignals := make(chan os.Signal, 1)
+	signal.Notify(reloadSignals, syscall.SIGHUP)
+	go ReloadOnSignal(reloadSignals, bjcpService, mcpServer.NotifyResourceUpdated)
 
 	// Run server
-	RunHTTPServer(mcpServer, webHandlers, *port)
+	RunHTTPServer(mcpServer, webHandlers, cfg)
+	stopUsage()
 	cleanup()
 }
 
-// RunHTTPServer starts the HTTP server for MCP connections over HTTP POST.
-func RunHTTPServer(mcpServer *mcp.Server, webHandlers *handlers.WebHandlers, port string) {
+// RunHTTPServer starts the HTTP server for MCP connections over HTTP POST on the configured port.
+// With a TLS certificate configured it serves HTTPS, reloading the certificate on SIGHUP, and can
+// redirect a plain HTTP port to it; with a client CA, /mcp requires a client certificate. The debug
+// endpoints, when enabled, are served on their own port on localhost, apart from the captured MCP
+// exchanges at /debug/mcp-trace, which need the admin token instead.
+func RunHTTPServer(mcpServer *mcp.Server, webHandlers *handlers.WebHandlers, cfg *config.Config) {
 	mux := http.NewServeMux()
 	mux.HandleFunc("/", webHandlers.ServeHome)
 	mux.Handle("/static/", http.StripPrefix("/static/", http.HandlerFunc(webHandlers.ServeStatic)))
 	mux.HandleFunc("/api", webHandlers.ServeAPI)
+	webHandlers.RegisterAPIRoutes(mux)
 	mux.HandleFunc("/health", webHandlers.ServeHealth)
+	mux.HandleFunc("/healthz", webHandlers.ServeLiveness)
+	mux.HandleFunc("/readyz", webHandlers.ServeReadiness)
 	mux.HandleFunc("/version", webHandlers.ServeVersion)
-	mux.HandleFunc("/mcp", mcpServer.HandleHTTP)
+	mux.HandleFunc("GET /exports/{token}", webHandlers.ServeExport)
+	var mcpHandler http.Handler = http.HandlerFunc(mcpServer.HandleHTTP)
+	if cfg.TLS.ClientCAFile != "" {
+		mcpHandler = RequireClientCert(mcpHandler)
+	}
+	mux.Handle("/mcp", mcpHandler)
+	mux.Handle("/debug/mcp-trace", mcpServer.TraceHandler(cfg.AdminToken))
 
 	server := &http.Server{
-		Addr:         ":" + port,
-		Handler:      mux,
-		ReadTimeout:  readTimeout,
-		WriteTimeout: writeTimeout,
-		IdleTimeout:  idleTimeout,
+		Addr:         ":" + strconv.Itoa(cfg.Port),
+		Handler:      handlers.AccessLog(webHandlers.Recover(handlers.Compress(mux))),
+		ReadTimeout:  cfg.HTTP.ReadTimeout,
+		WriteTimeout: cfg.HTTP.WriteTimeout,
+		IdleTimeout:  cfg.HTTP.IdleTimeout,
+	}
+	scheme := "http"
+	// The redirect and debug listeners, shut down along with server
+	var secondary []*http.Server
+	if cfg.TLS.Enabled() {
+		scheme = "https"
+		reloader, err := NewCertificateReloader(cfg.TLS.CertFile, cfg.TLS.KeyFile)
+		if err != nil {
+			log.Fatalf("Failed to load TLS certificate: %v", err)
+		}
+		if server.TLSConfig, err = ServerTLSConfig(cfg.TLS, reloader); err != nil {
+			log.Fatalf("Failed to configure TLS: %v", err)
+		}
+		reloadSignals := make(chan os.Signal, 1)
+		signal.Notify(reloadSignals, syscall.SIGHUP)
+		go ReloadCertificateOnSignal(reloadSignals, reloader)
+
+		if cfg.TLS.RedirectPort != 0 {
+			redirect := &http.Server{
+				Addr:         ":" + strconv.Itoa(cfg.TLS.RedirectPort),
+				Handler:      RedirectToHTTPS(cfg.Port),
+				ReadTimeout:  cfg.HTTP.ReadTimeout,
+				WriteTimeout: cfg.HTTP.WriteTimeout,
+				IdleTimeout:  cfg.HTTP.IdleTimeout,
+			}
+			secondary = append(secondary, redirect)
+			go serveSecondary("HTTPS redirect", redirect)
+		}
+	}
+	if cfg.Debug.Enabled {
+		debug := &http.Server{
+			Addr:              net.JoinHostPort("127.0.0.1", strconv.Itoa(cfg.Debug.Port)),
+			Handler:           DebugHandler(),
+			ReadHeaderTimeout: cfg.HTTP.ReadTimeout,
+			IdleTimeout:       cfg.HTTP.IdleTimeout,
+			// No write timeout, so that CPU profiles and traces may run longer than HTTP_WRITE_TIMEOUT
+		}
+		secondary = append(secondary, debug)
+		go serveSecondary("debug endpoints", debug)
 	}
 
 	go func() {
@@ -120,58 +299,665 @@ func RunHTTPServer(mcpServer *mcp.Server, webHandlers *handlers.WebHandlers, por
 		signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
 		<-sigChan
 
-		logrus.Info("Shutting down HTTP server...")
-		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		drainDelay := cfg.HTTP.ShutdownDrainDelay
+		ctx, cancel := context.WithTimeout(context.Background(), drainDelay+shutdownTimeout)
 		defer cancel()
 
-		if err := server.Shutdown(ctx); err != nil {
+		if err := DrainAndShutdown(ctx, server, webHandlers, drainDelay); err != nil {
 			logrus.Errorf("HTTP server shutdown error: %v", err)
 		}
+		for _, other := range secondary {
+			if err := other.Shutdown(ctx); err != nil {
+				logrus.Errorf("HTTP server shutdown error on %s: %v", other.Addr, err)
+			}
+		}
 	}()
 
-	logrus.Infof("Starting HTTP MCP server on port %s", port)
-	logrus.Infof("MCP endpoint: http://localhost:%s/mcp", port)
+	logrus.Infof("Starting HTTP MCP server on port %d", cfg.Port)
+	logrus.Infof("MCP endpoint: %s://localhost:%d/mcp", scheme, cfg.Port)
 
-	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+	var err error
+	if cfg.TLS.Enabled() {
+		// The certificate comes from server.TLSConfig.GetCertificate
+		err = server.ListenAndServeTLS("", "")
+	} else {
+		err = server.ListenAndServe()
+	}
+	if err != nil && err != http.ErrServerClosed {
 		log.Fatalf("HTTP server failed to start: %v", err)
 	}
 }
 
-// InitDatabase initializes and configures the PostgreSQL database connection.
-func InitDatabase() (*sqlx.DB, error) {
-	databaseURL := os.Getenv("DATABASE_URL")
-	if databaseURL == "" {
-		return nil, errors.New("DATABASE_URL environment variable is required")
+// ReloadOnSignal reloads the BJCP style guide each time a signal arrives on signals, until it is
+// closed. A failed reload is logged and the previous data kept.
+func ReloadOnSignal(signals <-chan os.Signal, bjcpService *data.BJCPService, notify func(uri string) bool) {
+	for sig := range signals {
+		logrus.Infof("Received %s, reloading BJCP data", sig)
+		_ = handlers.ReloadBJCPData(context.Background(), bjcpService, notify)
+	}
+}
+
+// DrainAndShutdown marks the server as draining, so that readiness probes fail and load balancers
+// stop sending traffic, waits drainDelay for them to notice while still serving requests, and then
+// shuts the server down gracefully.
+func DrainAndShutdown(
+	ctx context.Context,
+	server *http.Server,
+	webHandlers *handlers.WebHandlers,
+	drainDelay time.Duration,
+) error {
+	logrus.Infof("Draining HTTP server for %s before shutdown...", drainDelay)
+	webHandlers.StartDraining()
+
+	timer := time.NewTimer(drainDelay)
+	select {
+	case <-ctx.Done():
+		timer.Stop()
+	case <-timer.C:
 	}
 
-	db, err := sqlx.Connect("postgres", databaseURL)
+	logrus.Info("Shutting down HTTP server...")
+	return server.Shutdown(ctx)
+}
+
+// serveSecondary serves a listener other than the public one, such as the HTTPS redirect or the
+// debug endpoints, until it is shut down. A listener that fails is logged without stopping the server.
+func serveSecondary(name string, server *http.Server) {
+	logrus.Infof("Serving %s on %s", name, server.Addr)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logrus.Errorf("Serving %s failed: %v", name, err)
+	}
+}
+
+// DBConnector opens a connection to a database, failing when it cannot be reached. Startup retries
+// it while the database is not ready yet.
+type DBConnector interface {
+	Connect(ctx context.Context, driverName, dataSourceName string) (*sqlx.DB, error)
+}
+
+// SQLConnector is the DBConnector of the server, connecting with sqlx.
+type SQLConnector struct{}
+
+// Connect opens the database and pings it.
+func (SQLConnector) Connect(ctx context.Context, driverName, dataSourceName string) (*sqlx.DB, error) {
+	return sqlx.ConnectContext(ctx, driverName, dataSourceName)
+}
+
+// InitDatabase initializes and configures the database connection and, unless
+// SEED_ON_STARTUP is false, seeds the sample data missing from the catalog.
+func InitDatabase(cfg *config.Config) (*sqlx.DB, error) {
+	return InitDatabaseWith(cfg, SQLConnector{})
+}
+
+// InitDatabaseWith is InitDatabase connecting with connector. Starting before the database is
+// ready, as under docker-compose or during a rollout, the connection and then the migrations are
+// retried with exponential backoff for up to DB_CONNECT_TIMEOUT.
+func InitDatabaseWith(cfg *config.Config, connector DBConnector) (*sqlx.DB, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Database.ConnectTimeout)
+	defer cancel()
+
+	driverName, dataSourceName, err := databaseSource(cfg.DatabaseURL)
+	if err != nil {
+		return nil, err
+	}
+	var db *sqlx.DB
+	err = retryStartup(ctx, "Database connection", func(ctx context.Context) error {
+		db, err = connector.Connect(ctx, driverName, dataSourceName)
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
+	ConfigurePool(db, cfg.Database)
 
-	// Configure connection pool
-	db.SetMaxOpenConns(maxOpenConns)
-	db.SetMaxIdleConns(maxIdleConns)
-	db.SetConnMaxLifetime(connMaxLifetime)
+	err = retryStartup(ctx, "Database migration", func(ctx context.Context) error {
+		_, err := models.Migrate(ctx, db)
+		return err
+	})
+	if err != nil {
+		if closeErr := db.Close(); closeErr != nil {
+			logrus.Warnf("Failed to close database: %v", closeErr)
+		}
+		return nil, fmt.Errorf("failed to migrate database: %w", err)
+	}
+
+	// Seed database with initial data
+	if cfg.Seed.OnStartup {
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.Seed.Timeout)
+		if seedErr := models.SeedDatabase(ctx, db); seedErr != nil {
+			logrus.Warnf("Failed to seed database: %v", seedErr)
+			// Don't fail startup if seeding fails
+		}
+		cancel()
+	} else {
+		logrus.Info("Skipping database seeding; SEED_ON_STARTUP is false")
+	}
+
+	logrus.Info("Database initialized successfully")
+	return db, nil
+}
+
+// ConnectDatabase connects to the database named by DATABASE_URL and applies its pending migrations.
+func ConnectDatabase(cfg *config.Config) (*sqlx.DB, error) {
+	db, err := OpenDatabase(cfg)
+	if err != nil {
+		return nil, err
+	}
 
 	// Auto-migrate database schema
 	if migrationErr := models.MigrateDatabase(db); migrationErr != nil {
 		return nil, fmt.Errorf("failed to migrate database: %w", migrationErr)
 	}
+	return db, nil
+}
 
-	// Seed database with initial data
-	if seedErr := models.SeedDatabase(db); seedErr != nil {
-		logrus.Warnf("Failed to seed database: %v", seedErr)
-		// Don't fail startup if seeding fails
+// OpenDatabase connects to the database named by DATABASE_URL, leaving its schema as it is. A
+// sqlite:// URL opens SQLite, and any other PostgreSQL.
+func OpenDatabase(cfg *config.Config) (*sqlx.DB, error) {
+	driverName, dataSourceName, err := databaseSource(cfg.DatabaseURL)
+	if err != nil {
+		return nil, err
 	}
 
-	logrus.Info("Database initialized successfully")
+	db, err := sqlx.Connect(driverName, dataSourceName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	ConfigurePool(db, cfg.Database)
 	return db, nil
 }
 
-// InitRedis initializes and configures the Redis client connection.
-func InitRedis(redisURL string) *redis.Client {
-	opts, err := redis.ParseURL(redisURL)
+// databaseSource returns the driver and data source names for DATABASE_URL. A PostgreSQL URL is
+// parsed here, so that a malformed one fails at once rather than being retried as unreachable.
+func databaseSource(databaseURL string) (driverName, dataSourceName string, err error) {
+	if databaseURL == "" {
+		return "", "", errors.New("DATABASE_URL is required; set it in the environment or the config file")
+	}
+	driverName, dataSourceName, err = models.ParseDatabaseURL(databaseURL)
+	if err == nil && driverName == "postgres" {
+		_, err = pq.NewConnector(dataSourceName)
+	}
+	if err != nil {
+		return "", "", fmt.Errorf("invalid DATABASE_URL: %w", err)
+	}
+	return driverName, dataSourceName, nil
+}
+
+// retryStartup runs a startup step until it succeeds, waiting between attempts with a backoff that
+// starts at connectBackoff and doubles up to maxConnectBackoff. Each failure is logged. It gives up
+// with the last error when the next attempt would start after ctx's deadline.
+func retryStartup(ctx context.Context, step string, attempt func(context.Context) error) error {
+	backoff := connectBackoff
+	for n := 1; ; n++ {
+		err := attempt(ctx)
+		if err == nil {
+			if n > 1 {
+				logrus.Infof("%s succeeded on attempt %d", step, n)
+			}
+			return nil
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < backoff {
+			return fmt.Errorf("gave up after %d attempt(s): %w", n, err)
+		}
+		logrus.Warnf("%s attempt %d failed, retrying in %s: %v", step, n, backoff, err)
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("gave up after %d attempt(s): %w", n, err)
+		case <-timer.C:
+		}
+		backoff = min(2*backoff, maxConnectBackoff)
+	}
+}
+
+// RunMigrations applies the pending database migrations, or reverts the last downSteps applied ones
+// when downSteps is not zero, and exits non-zero if that fails. The server is not started.
+func RunMigrations(cfg *config.Config, downSteps int) {
+	db, err := OpenDatabase(cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize database: %v", err)
+	}
+	summary, migrateErr := MigrateSchema(context.Background(), db, downSteps)
+	if closeErr := db.Close(); closeErr != nil {
+		logrus.Warnf("Failed to close database: %v", closeErr)
+	}
+	if migrateErr != nil {
+		log.Fatalf("Migration failed: %v", migrateErr)
+	}
+	logrus.Info(summary)
+}
+
+// MigrateSchema applies the pending migrations, or reverts the last downSteps applied ones when
+// downSteps is not zero, and describes the change and the resulting schema version.
+func MigrateSchema(ctx context.Context, db *sqlx.DB, downSteps int) (string, error) {
+	var changed []models.Migration
+	var err error
+	verb := "Applied"
+	if downSteps != 0 {
+		verb = "Reverted"
+		changed, err = models.MigrateDown(ctx, db, downSteps)
+	} else {
+		changed, err = models.Migrate(ctx, db)
+	}
+	if err != nil {
+		return "", err
+	}
+	applied, err := models.AppliedMigrations(ctx, db)
+	if err != nil {
+		return "", err
+	}
+
+	version := 0
+	if len(applied) > 0 {
+		version = applied[len(applied)-1].Version
+	}
+	if len(changed) == 0 && downSteps == 0 {
+		return fmt.Sprintf("Database schema is up to date at version %d", version), nil
+	}
+	return fmt.Sprintf("%s %d migration(s); the database schema is at version %d", verb, len(changed), version), nil
+}
+
+// RunImport imports the given brewery and beer files into the database and exits non-zero if the
+// import fails. Search caches are dropped afterwards when REDIS_URL is set.
+func RunImport(cfg *config.Config, breweriesPath, beersPath string, opts models.ImportOptions) {
+	db, err := ConnectDatabase(cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize database: %v", err)
+	}
+	_, importErr := ImportFiles(context.Background(), db, breweriesPath, beersPath, opts)
+
+	if redisClient := InitRedis(cfg); redisClient != nil {
+		beerService := services.NewBeerService(db, redisClient)
+		InvalidateSearchCaches(beerService, services.NewBreweryService(db, redisClient), redisClient)
+		if closeErr := redisClient.Close(); closeErr != nil {
+			logrus.Warnf("Failed to close Redis client: %v", closeErr)
+		}
+	}
+	if closeErr := db.Close(); closeErr != nil {
+		logrus.Warnf("Failed to close database: %v", closeErr)
+	}
+	if importErr != nil {
+		log.Fatalf("Import failed: %v", importErr)
+	}
+}
+
+// RunSeed seeds the database and exits non-zero if the seed data is invalid or seeding fails. It
+// seeds from the seed file at path, see services.LoadSeedFile, or from the bundled seed data when
+// path is empty. With reseed, the catalog is deleted first. Search caches are dropped afterwards
+// when REDIS_URL is set.
+func RunSeed(cfg *config.Config, path string, reseed bool) {
+	db, err := ConnectDatabase(cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize database: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Seed.Timeout)
+	summary, seedErr := SeedCatalog(ctx, db, path, reseed)
+	cancel()
+
+	if redisClient := InitRedis(cfg); redisClient != nil {
+		beerService := services.NewBeerService(db, redisClient)
+		InvalidateSearchCaches(beerService, services.NewBreweryService(db, redisClient), redisClient)
+		if closeErr := redisClient.Close(); closeErr != nil {
+			logrus.Warnf("Failed to close Redis client: %v", closeErr)
+		}
+	}
+	if closeErr := db.Close(); closeErr != nil {
+		logrus.Warnf("Failed to close database: %v", closeErr)
+	}
+	if seedErr != nil {
+		log.Fatalf("Seeding failed: %v", seedErr)
+	}
+	logrus.Info(summary)
+}
+
+// SeedCatalog loads and validates the seed file at path, or the bundled seed data when path is
+// empty, and inserts its breweries and beers missing from the database, describing the counts. With
+// reseed, every brewery and beer is deleted first, in the same transaction; see models.Reseed.
+func SeedCatalog(ctx context.Context, db *sqlx.DB, path string, reseed bool) (string, error) {
+	var seed *services.SeedData
+	var err error
+	source := "the bundled seed data"
+	if path != "" {
+		source = path
+		seed, err = services.LoadSeedFile(path)
+	} else {
+		seed, err = services.LoadSeedData()
+	}
+	if err != nil {
+		return "", err
+	}
+
+	verb := "Seeded"
+	var breweries, beers models.SeedResult
+	if reseed {
+		verb = "Reseeded"
+		breweries, beers, err = models.Reseed(ctx, db, seed)
+	} else {
+		breweries, beers, err = models.Seed(ctx, db, seed)
+	}
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s from %s: breweries %s; beers %s", verb, source, breweries, beers), nil
+}
+
+// RunBrewerySync copies breweries from Open Brewery DB into the database and exits non-zero if the
+// sync fails. Search caches are dropped afterwards when REDIS_URL is set.
+func RunBrewerySync(cfg *config.Config) {
+	db, err := ConnectDatabase(cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize database: %v", err)
+	}
+	redisClient := InitRedis(cfg)
+
+	result, syncErr := NewBrewerySyncer(cfg, db, redisClient).Sync(context.Background())
+	if redisClient != nil {
+		if closeErr := redisClient.Close(); closeErr != nil {
+			logrus.Warnf("Failed to close Redis client: %v", closeErr)
+		}
+	}
+	if closeErr := db.Close(); closeErr != nil {
+		logrus.Warnf("Failed to close database: %v", closeErr)
+	}
+	if syncErr != nil {
+		log.Fatalf("Brewery sync failed: %v", syncErr)
+	}
+	logrus.Infof("Synced breweries from Open Brewery DB: %s", result)
+}
+
+// RunDataValidation loads the BJCP style data and the ingredient data as startup does, validating
+// them, and exits non-zero naming every problem found. It needs no database, so it suits CI checks
+// on data file changes.
+func RunDataValidation() {
+	summaries, err := ValidateStyleData()
+	if err != nil {
+		log.Fatalf("BJCP data is invalid: %v", err)
+	}
+	ingredientSummary, err := ValidateIngredientData()
+	if err != nil {
+		log.Fatalf("Ingredient data is invalid: %v", err)
+	}
+	for _, summary := range append(summaries, ingredientSummary) {
+		logrus.Info(summary)
+	}
+}
+
+// ValidateStyleData loads and validates every BJCP guideline version available, see
+// data.LoadBJCPGuidelines, and describes each valid version, newest first.
+func ValidateStyleData() ([]string, error) {
+	guidelines, err := data.LoadBJCPGuidelines()
+	if err != nil {
+		return nil, err
+	}
+	bjcpService := data.NewBJCPServiceFromGuidelines(guidelines)
+	summaries := make([]string, 0, len(guidelines))
+	for _, version := range bjcpService.Versions() {
+		bjcpData := guidelines[version]
+		summaries = append(summaries, fmt.Sprintf("BJCP %s guidelines are valid: %d styles in %d categories",
+			version, len(bjcpData.Styles), len(bjcpData.Categories)))
+	}
+	return summaries, nil
+}
+
+// ValidateIngredientData loads and validates the ingredient data, see data.LoadIngredientData, and
+// describes it.
+func ValidateIngredientData() (string, error) {
+	ingredientData, err := data.LoadIngredientData()
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Ingredient data is valid: %d hops, %d fermentables, and %d yeast strains",
+		len(ingredientData.Hops), len(ingredientData.Fermentables), len(ingredientData.Yeasts)), nil
+}
+
+// RecordBJCPSource records the default guidelines of bjcpService as the services.BJCPSource data
+// source, with their style count and version, dated by the data's last_updated date when it has one.
+func RecordBJCPSource(ctx context.Context, db sqlx.ExecerContext, bjcpService *data.BJCPService) error {
+	metadata := bjcpService.GetMetadata()
+	updated, err := time.Parse(time.DateOnly, metadata.LastUpdated)
+	if err != nil {
+		updated = time.Now()
+	}
+	return services.RecordDataSource(ctx, db, services.DataSource{
+		Source:    services.BJCPSource,
+		UpdatedAt: updated,
+		Records:   len(bjcpService.GetAllStyles()),
+		Version:   metadata.Version,
+	})
+}
+
+type importFunc func(
+	context.Context, *sqlx.DB, io.Reader, models.ImportFormat, models.ImportOptions,
+) (*models.ImportSummary, error)
+
+// ImportFiles imports breweries and then beers, so that beer rows can name breweries from the same
+// run, logging a summary, with the rate of a committed import, and each failed row, and describing
+// the counts of each file. An empty path is skipped. Each file is imported in its own transaction; a
+// failed brewery import stops before the beers. Each file imported is reported with
+// services.ReportProgress. A successful import is recorded as the services.ImportSource data
+// source, with the rows read and the file names.
+func ImportFiles(
+	ctx context.Context, db *sqlx.DB, breweriesPath, beersPath string, opts models.ImportOptions,
+) (string, error) {
+	source := services.DataSource{Source: services.ImportSource}
+	var files, summaries []string
+	imports := []struct {
+		kind, path string
+		run        importFunc
+	}{
+		{"breweries", breweriesPath, models.ImportBreweriesFromReader},
+		{"beers", beersPath, models.ImportBeersFromReader},
+	}
+	total := 0
+	for _, imp := range imports {
+		if imp.path != "" {
+			total++
+		}
+	}
+	for _, imp := range imports {
+		if imp.path == "" {
+			continue
+		}
+		format, err := models.ImportFormatFromPath(imp.path)
+		if err != nil {
+			return "", err
+		}
+		file, err := os.Open(imp.path)
+		if err != nil {
+			return "", fmt.Errorf("failed to open %s import: %w", imp.kind, err)
+		}
+		summary, err := imp.run(ctx, db, file, format, opts)
+		_ = file.Close()
+		if summary != nil {
+			for _, rowErr := range summary.Errors {
+				logrus.Warnf("%s: %v", imp.path, rowErr)
+			}
+			rate := ""
+			if summary.Elapsed > 0 {
+				rate = fmt.Sprintf(" in %s (%.0f rows/s)",
+					summary.Elapsed.Round(time.Millisecond), summary.RowsPerSecond())
+			}
+			logrus.Infof("Imported %s from %s: %s%s", imp.kind, imp.path, summary, rate)
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to import %s from %s: %w", imp.kind, imp.path, err)
+		}
+		source.Records += summary.Rows()
+		files = append(files, filepath.Base(imp.path))
+		summaries = append(summaries, fmt.Sprintf("%s from %s: %s", imp.kind, filepath.Base(imp.path), summary))
+		services.ReportProgress(ctx, float64(len(files)), float64(total), "Imported "+strings.Join(summaries, "; "))
+	}
+	if len(files) == 0 {
+		return "Nothing to import", nil
+	}
+	source.UpdatedAt = time.Now()
+	source.Version = strings.Join(files, ", ")
+	if err := services.RecordDataSource(ctx, db, source); err != nil {
+		logrus.Warnf("Failed to record the import: %v", err)
+	}
+	return "Imported " + strings.Join(summaries, "; "), nil
+}
+
+// ConfigureServices applies the search cache TTL and the query timeouts and retries of cfg to the
+// services.
+func ConfigureServices(
+	cfg *config.Config,
+	beerService *services.BeerService,
+	breweryService *services.BreweryService,
+) {
+	beerService.SetCacheTTL(cfg.Cache.SearchTTL)
+	breweryService.SetCacheTTL(cfg.Cache.SearchTTL)
+	beerService.SetQueryTimeout(cfg.Query.Timeout)
+	breweryService.SetQueryTimeout(cfg.Query.Timeout)
+	beerService.SetSlowQueryThreshold(cfg.Query.SlowThreshold)
+	breweryService.SetSlowQueryThreshold(cfg.Query.SlowThreshold)
+	beerService.SetQueryRetries(cfg.Query.RetryAttempts, cfg.Query.RetryBackoff)
+	breweryService.SetQueryRetries(cfg.Query.RetryAttempts, cfg.Query.RetryBackoff)
+}
+
+// NewBrewerySyncer creates the Open Brewery DB syncer, pacing its requests as cfg sets.
+func NewBrewerySyncer(cfg *config.Config, db *sqlx.DB, redisClient *redis.Client) *services.BrewerySyncer {
+	syncer := services.NewBrewerySyncer(db, redisClient, nil)
+	syncer.SetRequestInterval(cfg.Sync.RequestInterval)
+	return syncer
+}
+
+// CatalogLoader seeds and imports the catalog for the seed_catalog and import_catalog admin tools,
+// as -seed-file and -import-breweries do, dropping the search caches afterwards.
+type CatalogLoader struct {
+	DB             *sqlx.DB
+	BeerService    *services.BeerService
+	BreweryService *services.BreweryService
+	Redis          *redis.Client
+	SeedTimeout    time.Duration // bounds seeding, as SEED_TIMEOUT does at startup
+}
+
+// Seed seeds the catalog, see SeedCatalog.
+func (l *CatalogLoader) Seed(ctx context.Context, path string, reseed bool) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, l.SeedTimeout)
+	defer cancel()
+	summary, err := SeedCatalog(ctx, l.DB, path, reseed)
+	if err != nil {
+		return "", err
+	}
+	InvalidateSearchCaches(l.BeerService, l.BreweryService, l.Redis)
+	return summary, nil
+}
+
+// Import imports brewery and beer files, see ImportFiles.
+func (l *CatalogLoader) Import(
+	ctx context.Context, breweriesPath, beersPath string, upsert, strict bool,
+) (string, error) {
+	opts := models.ImportOptions{Upsert: upsert, Strict: strict}
+	summary, err := ImportFiles(ctx, l.DB, breweriesPath, beersPath, opts)
+	// A failed beer import follows a committed brewery import, so the caches are dropped either way
+	InvalidateSearchCaches(l.BeerService, l.BreweryService, l.Redis)
+	return summary, err
+}
+
+// NewUsageLog creates the usage log writing to the sink cfg sets, or returns nil when usage is not
+// recorded: with USAGE_SINK=off, or with the Redis sink while Redis is unavailable.
+func NewUsageLog(cfg *config.Config, db *sqlx.DB, redisClient *redis.Client) *services.UsageLog {
+	var sink services.UsageSink
+	switch cfg.Usage.Sink {
+	case services.UsageSinkDatabase:
+		sink = services.NewDBUsageSink(db)
+	case services.UsageSinkRedis:
+		if redisClient == nil {
+			logrus.Warn("Redis is unavailable, usage will not be recorded")
+			return nil
+		}
+		sink = services.NewRedisUsageSink(redisClient)
+	default:
+		return nil
+	}
+	return services.NewUsageLog(sink, cfg.Usage.BufferSize, cfg.Usage.FlushInterval)
+}
+
+// NewExportStore creates the store cfg sets for the files of export_results, or returns nil when
+// exports are disabled: when the export directory cannot be created, or with the Redis store while
+// Redis is unavailable.
+func NewExportStore(cfg *config.Config, redisClient *redis.Client) services.ExportStore {
+	if cfg.Export.Store == services.ExportStoreRedis {
+		if redisClient == nil {
+			logrus.Warn("Redis is unavailable, exports are disabled")
+			return nil
+		}
+		return services.NewRedisExportStore(redisClient)
+	}
+	store, err := services.NewFileExportStore(cfg.Export.Dir)
+	if err != nil {
+		logrus.Warnf("Exports are disabled: %v", err)
+		return nil
+	}
+	return store
+}
+
+// StartUsageLog writes the events of usageLog in the background until the returned function is
+// called, which writes the events still queued before returning.
+func StartUsageLog(usageLog *services.UsageLog) (stop func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		usageLog.Run(ctx)
+	}()
+	return func() {
+		cancel()
+		<-done
+		if dropped, failed := usageLog.Dropped(), usageLog.Failed(); dropped+failed > 0 {
+			logrus.Warnf("Usage events lost: %d dropped under load, %d failed to write", dropped, failed)
+		}
+	}
+}
+
+// ConfigurePool sizes the database connection pool as cfg sets. SQLite connections are kept open
+// however long they live or idle.
+func ConfigurePool(db *sqlx.DB, cfg config.DatabaseConfig) {
+	lifetime, idleTime := cfg.ConnMaxLifetime, cfg.ConnMaxIdleTime
+	if models.IsSQLite(db) {
+		// Connections to SQLite are cheap to keep, and an in-memory database is lost when the last
+		// one closes
+		lifetime, idleTime = 0, 0
+	}
+	// database/sql lowers the idle limit to the open limit when it is higher
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(lifetime)
+	db.SetConnMaxIdleTime(idleTime)
+}
+
+// InvalidateSearchCaches drops cached beer and brewery searches and resource contents after the
+// catalog changes.
+func InvalidateSearchCaches(
+	beerService *services.BeerService,
+	breweryService *services.BreweryService,
+	redisClient *redis.Client,
+) {
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+
+	if err := beerService.InvalidateBeerCache(ctx); err != nil {
+		logrus.Warnf("Failed to invalidate beer search cache: %v", err)
+	}
+	if err := breweryService.InvalidateBreweryCache(ctx); err != nil {
+		logrus.Warnf("Failed to invalidate brewery search cache: %v", err)
+	}
+	if err := handlers.InvalidateResourceCache(ctx, redisClient); err != nil {
+		logrus.Warnf("Failed to invalidate resource cache: %v", err)
+	}
+}
+
+// InitRedis initializes and configures the Redis client connection to REDIS_URL. It returns nil,
+// leaving searches uncached, when REDIS_URL is not set or Redis cannot be reached.
+func InitRedis(cfg *config.Config) *redis.Client {
+	if cfg.RedisURL == "" {
+		return nil
+	}
+	opts, err := redis.ParseURL(cfg.RedisURL)
 	if err != nil {
 		logrus.Warnf("Failed to parse Redis URL: %v", err)
 		return nil
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/config"
+	"github.com/sirupsen/logrus"
+)
+
+// CertificateReloader serves the certificate of a certificate and key file pair to TLS handshakes,
+// loading the files again on Reload, so that a renewed certificate is picked up without a restart.
+type CertificateReloader struct {
+	certFile, keyFile string
+	cert              atomic.Pointer[tls.Certificate]
+}
+
+// NewCertificateReloader loads the certificate and key files, failing if they are not a valid pair.
+func NewCertificateReloader(certFile, keyFile string) (*CertificateReloader, error) {
+	r := &CertificateReloader{certFile: certFile, keyFile: keyFile}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Reload loads the certificate and key files again. When they are not a valid pair, such as while
+// only one of them has been replaced, the previous certificate is kept.
+func (r *CertificateReloader) Reload() error {
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load TLS certificate %s: %w", r.certFile, err)
+	}
+	r.cert.Store(&cert)
+	return nil
+}
+
+// GetCertificate returns the certificate last loaded, for tls.Config.GetCertificate.
+func (r *CertificateReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return r.cert.Load(), nil
+}
+
+// ReloadCertificateOnSignal reloads the TLS certificate each time a signal arrives on signals, until
+// it is closed. A failed reload is logged and the previous certificate kept.
+func ReloadCertificateOnSignal(signals <-chan os.Signal, reloader *CertificateReloader) {
+	for sig := range signals {
+		logrus.Infof("Received %s, reloading TLS certificate", sig)
+		if err := reloader.Reload(); err != nil {
+			logrus.Errorf("Keeping the previous TLS certificate: %v", err)
+		}
+	}
+}
+
+// ServerTLSConfig returns the TLS configuration of the HTTPS server, serving the certificate of
+// reloader. With a client CA file, clients may present a certificate, which must then be issued by
+// one of its CAs; RequireClientCert restricts a handler to clients that did.
+func ServerTLSConfig(cfg config.TLSConfig, reloader *CertificateReloader) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: reloader.GetCertificate,
+	}
+	if cfg.ClientCAFile == "" {
+		return tlsConfig, nil
+	}
+	raw, err := os.ReadFile(filepath.Clean(cfg.ClientCAFile)) // #nosec G304 - the path is deployment configuration
+	if err != nil {
+		return nil, fmt.Errorf("failed to read TLS client CA file: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(raw) {
+		return nil, fmt.Errorf("no PEM certificates found in TLS client CA file %s", cfg.ClientCAFile)
+	}
+	tlsConfig.ClientCAs = pool
+	// Verified when given, so that health probes without a certificate still reach /healthz and /readyz
+	tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+	return tlsConfig, nil
+}
+
+// RequireClientCert rejects requests with 403 Forbidden unless the client presented a certificate
+// that was verified against the client CAs, see ServerTLSConfig.
+func RequireClientCert(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 {
+			http.Error(w, "A client certificate is required", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RedirectToHTTPS redirects every request to the same host and path on the HTTPS port. The redirect
+// is permanent and keeps the method, so that MCP clients retry their POST over HTTPS.
+func RedirectToHTTPS(httpsPort int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		if httpsPort != 443 {
+			host = net.JoinHostPort(host, strconv.Itoa(httpsPort))
+		} else if ip := net.ParseIP(host); ip != nil && ip.To4() == nil {
+			host = "[" + host + "]"
+		}
+		http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusPermanentRedirect)
+	})
+}
@@ -0,0 +1,295 @@
+package main_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"log"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	main "github.com/CharlRitter/brewsource-mcp/app/cmd/server"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/config"
+)
+
+// Certificates generated by TestMain: a CA, a server certificate for 127.0.0.1 and a client
+// certificate issued by the CA, and a self-signed client certificate the CA did not issue.
+var (
+	tlsDir          string
+	caFile          string
+	serverCertFile  string
+	serverKeyFile   string
+	caPool          *x509.CertPool
+	clientCert      tls.Certificate
+	untrustedClient tls.Certificate
+	caCert          *x509.Certificate
+	caKey           *ecdsa.PrivateKey
+)
+
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "brewsource-tls")
+	if err != nil {
+		log.Fatalf("Failed to create certificate directory: %v", err)
+	}
+	tlsDir = dir
+	caFile = filepath.Join(dir, "ca.pem")
+	serverCertFile = filepath.Join(dir, "server.pem")
+	serverKeyFile = filepath.Join(dir, "server-key.pem")
+
+	var caPEM []byte
+	caCert, caKey, caPEM, _ = newCertificate(&x509.Certificate{
+		Subject:               pkix.Name{CommonName: "Brewsource Test CA"},
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}, nil, nil)
+	caPool = x509.NewCertPool()
+	caPool.AddCert(caCert)
+	writeServerCertificate(1)
+	if err = os.WriteFile(caFile, caPEM, 0o600); err != nil {
+		log.Fatalf("Failed to write CA certificate: %v", err)
+	}
+
+	_, _, certPEM, keyPEM := newCertificate(&x509.Certificate{
+		Subject:     pkix.Name{CommonName: "mcp-client"},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, caCert, caKey)
+	if clientCert, err = tls.X509KeyPair(certPEM, keyPEM); err != nil {
+		log.Fatalf("Failed to load client certificate: %v", err)
+	}
+	_, _, certPEM, keyPEM = newCertificate(&x509.Certificate{
+		Subject:     pkix.Name{CommonName: "stranger"},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, nil, nil)
+	if untrustedClient, err = tls.X509KeyPair(certPEM, keyPEM); err != nil {
+		log.Fatalf("Failed to load untrusted client certificate: %v", err)
+	}
+
+	code := m.Run()
+	_ = os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// newCertificate creates a certificate from template, issued by parent or self-signed when parent
+// is nil, returning it, its key, and both PEM encoded.
+func newCertificate(
+	template, parent *x509.Certificate,
+	parentKey *ecdsa.PrivateKey,
+) (*x509.Certificate, *ecdsa.PrivateKey, []byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		log.Fatalf("Failed to generate key: %v", err)
+	}
+	if template.SerialNumber == nil {
+		template.SerialNumber = big.NewInt(time.Now().UnixNano())
+	}
+	template.NotBefore = time.Now().Add(-time.Hour)
+	template.NotAfter = time.Now().Add(time.Hour)
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		log.Fatalf("Failed to create certificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		log.Fatalf("Failed to parse certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		log.Fatalf("Failed to marshal key: %v", err)
+	}
+	return cert, key,
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+// writeServerCertificate writes a server certificate for 127.0.0.1 with the given serial number,
+// issued by the test CA, to serverCertFile and serverKeyFile.
+func writeServerCertificate(serial int64) {
+	_, _, certPEM, keyPEM := newCertificate(&x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "127.0.0.1"},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}, caCert, caKey)
+	if err := os.WriteFile(serverCertFile, certPEM, 0o600); err != nil {
+		log.Fatalf("Failed to write server certificate: %v", err)
+	}
+	if err := os.WriteFile(serverKeyFile, keyPEM, 0o600); err != nil {
+		log.Fatalf("Failed to write server key: %v", err)
+	}
+}
+
+// startTLSServer serves handler over TLS as configured by tlsConfig and returns its base URL.
+func startTLSServer(t *testing.T, tlsConfig *tls.Config, handler http.Handler) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	server := &http.Server{Handler: handler, ReadHeaderTimeout: time.Second}
+	go func() { _ = server.Serve(tls.NewListener(listener, tlsConfig)) }()
+	t.Cleanup(func() { _ = server.Close() })
+	return "https://" + listener.Addr().String()
+}
+
+// getTLS requests url on a new connection, trusting the test CA and presenting clientCerts.
+func getTLS(url string, clientCerts ...tls.Certificate) (*http.Response, error) {
+	client := &http.Client{Transport: &http.Transport{
+		TLSClientConfig: &tls.Config{RootCAs: caPool, Certificates: clientCerts, MinVersion: tls.VersionTLS12},
+	}}
+	defer client.CloseIdleConnections()
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	return resp, nil
+}
+
+// Test CertificateReloader picking up a renewed certificate and keeping it over invalid files.
+func TestCertificateReloader(t *testing.T) {
+	t.Cleanup(func() { writeServerCertificate(1) })
+	reloader, err := main.NewCertificateReloader(serverCertFile, serverKeyFile)
+	if err != nil {
+		t.Fatalf("Failed to load certificate: %v", err)
+	}
+	tlsConfig, err := main.ServerTLSConfig(config.TLSConfig{CertFile: serverCertFile, KeyFile: serverKeyFile}, reloader)
+	if err != nil {
+		t.Fatalf("Failed to configure TLS: %v", err)
+	}
+	url := startTLSServer(t, tlsConfig, http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+
+	serial := func() int64 {
+		t.Helper()
+		resp, getErr := getTLS(url)
+		if getErr != nil {
+			t.Fatalf("Request failed: %v", getErr)
+		}
+		return resp.TLS.PeerCertificates[0].SerialNumber.Int64()
+	}
+	if got := serial(); got != 1 {
+		t.Errorf("Expected the server certificate with serial 1, got %d", got)
+	}
+
+	writeServerCertificate(2)
+	if got := serial(); got != 1 {
+		t.Errorf("Expected the certificate to be kept until reloaded, got serial %d", got)
+	}
+	if err = reloader.Reload(); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	if got := serial(); got != 2 {
+		t.Errorf("Expected the renewed certificate with serial 2, got %d", got)
+	}
+
+	if err = os.WriteFile(serverKeyFile, []byte("not a key"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err = reloader.Reload(); err == nil {
+		t.Error("Expected an invalid key file to fail the reload")
+	}
+	if got := serial(); got != 2 {
+		t.Errorf("Expected the previous certificate to be kept, got serial %d", got)
+	}
+
+	if _, err = main.NewCertificateReloader(filepath.Join(tlsDir, "missing.pem"), serverKeyFile); err == nil {
+		t.Error("Expected a missing certificate file to fail")
+	}
+}
+
+// Test that with a client CA, a handler behind RequireClientCert needs a certificate it issued,
+// while other handlers serve clients without one.
+func TestServerTLSConfig_ClientCertificates(t *testing.T) {
+	reloader, err := main.NewCertificateReloader(serverCertFile, serverKeyFile)
+	if err != nil {
+		t.Fatalf("Failed to load certificate: %v", err)
+	}
+	tlsConfig, err := main.ServerTLSConfig(config.TLSConfig{
+		CertFile: serverCertFile, KeyFile: serverKeyFile, ClientCAFile: caFile,
+	}, reloader)
+	if err != nil {
+		t.Fatalf("Failed to configure TLS: %v", err)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(http.ResponseWriter, *http.Request) {})
+	mux.Handle("/mcp", main.RequireClientCert(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})))
+	url := startTLSServer(t, tlsConfig, mux)
+
+	tests := []struct {
+		name        string
+		path        string
+		clientCerts []tls.Certificate
+		wantStatus  int
+	}{
+		{"health probe without a certificate", "/healthz", nil, http.StatusOK},
+		{"MCP without a certificate", "/mcp", nil, http.StatusForbidden},
+		{"MCP with a certificate from the CA", "/mcp", []tls.Certificate{clientCert}, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, getErr := getTLS(url+tt.path, tt.clientCerts...)
+			if getErr != nil {
+				t.Fatalf("Request failed: %v", getErr)
+			}
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, resp.StatusCode)
+			}
+		})
+	}
+
+	// Clients only offer certificates issued by a CA the server accepts, so present it regardless
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{
+		RootCAs:    caPool,
+		MinVersion: tls.VersionTLS12,
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return &untrustedClient, nil
+		},
+	}}}
+	defer client.CloseIdleConnections()
+	if resp, getErr := client.Get(url + "/mcp"); getErr == nil {
+		resp.Body.Close()
+		t.Error("Expected a client certificate from another CA to fail the handshake")
+	}
+
+	for _, file := range []string{filepath.Join(tlsDir, "missing.pem"), serverKeyFile} {
+		_, err = main.ServerTLSConfig(config.TLSConfig{ClientCAFile: file}, reloader)
+		if err == nil {
+			t.Errorf("Expected client CA file %s to be rejected", file)
+		}
+	}
+}
+
+func TestRedirectToHTTPS(t *testing.T) {
+	tests := []struct {
+		host      string
+		httpsPort int
+		want      string
+	}{
+		{"example.com:8080", 8443, "https://example.com:8443/mcp?debug=1"},
+		{"example.com", 443, "https://example.com/mcp?debug=1"},
+		{"[::1]:8080", 443, "https://[::1]/mcp?debug=1"},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPost, "/mcp?debug=1", nil)
+		req.Host = tt.host
+		rec := httptest.NewRecorder()
+		main.RedirectToHTTPS(tt.httpsPort).ServeHTTP(rec, req)
+		if rec.Code != http.StatusPermanentRedirect || rec.Header().Get("Location") != tt.want {
+			t.Errorf("Expected a %d redirect to %s, got %d to %s",
+				http.StatusPermanentRedirect, tt.want, rec.Code, rec.Header().Get("Location"))
+		}
+	}
+}
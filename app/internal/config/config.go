@@ -0,0 +1,597 @@
+// Package config loads the configuration of the Brewsource MCP server. Each setting starts at its
+// default and may be overridden by an optional YAML file, then by an environment variable, and then,
+// for a few, by a command line flag.
+package config
+
+import (
+	"bytes"
+	"encoding"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/services"
+	"github.com/CharlRitter/brewsource-mcp/app/pkg/data"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// DefaultPort is the port the HTTP server listens on unless changed with PORT or -port.
+	DefaultPort = 8080
+	// DefaultMaxRequestBytes bounds the body of an MCP request unless changed with
+	// MCP_MAX_REQUEST_BYTES.
+	DefaultMaxRequestBytes = 1 << 20
+	// DefaultToolQueueTimeout is how long a tool call waits for a concurrency slot before it is
+	// refused as busy, unless changed with MCP_TOOL_QUEUE_TIMEOUT.
+	DefaultToolQueueTimeout = 2 * time.Second
+	// DefaultCaptureRedact are the fields and query parameters whose values captured MCP exchanges
+	// leave out, unless changed with MCP_CAPTURE_REDACT.
+	DefaultCaptureRedact = "admin_token,token,password,secret,api_key,authorization"
+	// DefaultDebugPort is the port of the debug endpoints unless changed with DEBUG_PORT.
+	DefaultDebugPort = 6060
+
+	// redacted replaces secrets in printed configuration, as url.URL.Redacted does passwords.
+	redacted = "xxxxx"
+)
+
+// Config is the configuration of the server.
+type Config struct {
+	Port        int    `yaml:"port"`
+	DatabaseURL string `yaml:"database_url"` // required to use the database; sqlite:// URLs open SQLite
+	RedisURL    string `yaml:"redis_url"`    // optional; searches are not cached without it
+	AdminToken  string `yaml:"admin_token"`  // optional; enables the admin tools for callers passing it
+
+	Log      LogConfig      `yaml:"log"`
+	Database DatabaseConfig `yaml:"database"`
+	Query    QueryConfig    `yaml:"query"`
+	Cache    CacheConfig    `yaml:"cache"`
+	HTTP     HTTPConfig     `yaml:"http"`
+	TLS      TLSConfig      `yaml:"tls"`
+	MCP      MCPConfig      `yaml:"mcp"`
+	Debug    DebugConfig    `yaml:"debug"`
+	Seed     SeedConfig     `yaml:"seed"`
+	Data     DataConfig     `yaml:"data"`
+	Sync     SyncConfig     `yaml:"sync"`
+	Usage    UsageConfig    `yaml:"usage"`
+	Export   ExportConfig   `yaml:"export"`
+}
+
+// LogConfig configures logging.
+type LogConfig struct {
+	Level  string `yaml:"level"`  // a logrus level, such as "info" or "debug"
+	Format string `yaml:"format"` // "json" or "text"
+}
+
+// DatabaseConfig sizes the database connection pool and bounds the wait for the database on
+// startup.
+type DatabaseConfig struct {
+	MaxOpenConns    int           `yaml:"max_open_conns"`
+	MaxIdleConns    int           `yaml:"max_idle_conns"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime"`
+	ConnMaxIdleTime time.Duration `yaml:"conn_max_idle_time"`
+	ConnectTimeout  time.Duration `yaml:"connect_timeout"`
+}
+
+// QueryConfig bounds and retries catalog queries; see services.BeerService.SetQueryTimeout and
+// SetQueryRetries.
+type QueryConfig struct {
+	Timeout       time.Duration `yaml:"timeout"`
+	SlowThreshold time.Duration `yaml:"slow_threshold"`
+	RetryAttempts int           `yaml:"retry_attempts"`
+	RetryBackoff  time.Duration `yaml:"retry_backoff"`
+}
+
+// CacheConfig configures the search and resource caches in Redis.
+type CacheConfig struct {
+	SearchTTL   time.Duration `yaml:"search_ttl"`
+	ResourceTTL time.Duration `yaml:"resource_ttl"` // of the catalog resources; style resources last until reloaded
+}
+
+// HTTPConfig bounds the requests of the HTTP server and its shutdown.
+type HTTPConfig struct {
+	ReadTimeout        time.Duration `yaml:"read_timeout"`
+	WriteTimeout       time.Duration `yaml:"write_timeout"`
+	IdleTimeout        time.Duration `yaml:"idle_timeout"`
+	ShutdownDrainDelay time.Duration `yaml:"shutdown_drain_delay"` // how long readiness fails before shutdown
+}
+
+// TLSConfig makes the server terminate TLS itself, for deployments without an ingress doing so.
+type TLSConfig struct {
+	CertFile     string `yaml:"cert_file"` // with KeyFile, serves HTTPS; both are reloaded on SIGHUP
+	KeyFile      string `yaml:"key_file"`
+	ClientCAFile string `yaml:"client_ca_file"` // optional; /mcp then requires a client certificate it issued
+	RedirectPort int    `yaml:"redirect_port"`  // optional; a plain HTTP port redirecting to HTTPS
+}
+
+// Enabled reports whether the server serves HTTPS.
+func (c TLSConfig) Enabled() bool {
+	return c.CertFile != ""
+}
+
+// MCPConfig limits the requests of the MCP endpoint.
+type MCPConfig struct {
+	MaxRequestBytes int64 `yaml:"max_request_bytes"`
+	// ToolLimits caps the concurrent calls of the tools it names; the others are unlimited
+	ToolLimits ToolLimits `yaml:"tool_limits,omitempty"`
+	// ToolQueueTimeout is how long a queued tool call waits for a slot before it is refused as busy
+	ToolQueueTimeout time.Duration `yaml:"tool_queue_timeout"`
+	Capture          CaptureConfig `yaml:"capture"`
+}
+
+// CaptureConfig configures logging the requests and responses of the MCP endpoint for debugging.
+type CaptureConfig struct {
+	// Enabled captures every exchange; clients may also turn it on for themselves with
+	// logging/setLevel debug
+	Enabled bool `yaml:"enabled"`
+	// Redact lists, comma-separated, the fields and query parameters whose values are replaced
+	Redact    string `yaml:"redact"`
+	MaxBytes  int    `yaml:"max_bytes"`  // requests and responses are cut to this many bytes
+	TraceSize int    `yaml:"trace_size"` // the exchanges kept for /debug/mcp-trace
+}
+
+// ToolLimit caps the concurrent calls of one tool. Up to Queue more calls wait for a slot, and
+// calls beyond those are refused as busy at once.
+type ToolLimit struct {
+	Concurrency int `yaml:"concurrency"`
+	Queue       int `yaml:"queue"`
+}
+
+// ToolLimits are the concurrency limits of tools by name.
+type ToolLimits map[string]ToolLimit
+
+// UnmarshalText parses limits written as MCP_TOOL_LIMITS takes them: comma-separated
+// name=concurrency entries, each optionally followed by :queue, such as
+// "search_beers=4:8,find_breweries=4". The limits replace any set before.
+func (l *ToolLimits) UnmarshalText(text []byte) error {
+	limits := ToolLimits{}
+	for _, entry := range strings.Split(string(text), ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok || name == "" {
+			return fmt.Errorf("%q is not name=concurrency[:queue]", entry)
+		}
+		concurrency, queue, queued := strings.Cut(value, ":")
+		var limit ToolLimit
+		var err error
+		if limit.Concurrency, err = strconv.Atoi(concurrency); err != nil {
+			return fmt.Errorf("%q: concurrency %q is not a whole number", entry, concurrency)
+		}
+		if queued {
+			if limit.Queue, err = strconv.Atoi(queue); err != nil {
+				return fmt.Errorf("%q: queue %q is not a whole number", entry, queue)
+			}
+		}
+		limits[name] = limit
+	}
+	*l = limits
+	return nil
+}
+
+// DebugConfig enables the pprof, expvar and goroutine endpoints, served on their own port on
+// localhost so that they are never reachable through the public listener.
+type DebugConfig struct {
+	Enabled bool `yaml:"enabled"`
+	Port    int  `yaml:"port"`
+}
+
+// SeedConfig configures seeding the sample data on startup.
+type SeedConfig struct {
+	OnStartup     bool          `yaml:"on_startup"`
+	Timeout       time.Duration `yaml:"timeout"`
+	BreweriesPath string        `yaml:"breweries_path"` // optional; in place of the embedded seed breweries
+	BeersPath     string        `yaml:"beers_path"`     // optional; in place of the embedded seed beers
+	TagsPath      string        `yaml:"tags_path"`      // optional; in place of the embedded flavour tags
+}
+
+// DataConfig names style data files to load in place of the embedded BJCP guidelines.
+type DataConfig struct {
+	BJCPPath     string `yaml:"bjcp_path"`
+	BJCP2015Path string `yaml:"bjcp_2015_path"`
+}
+
+// SyncConfig configures the Open Brewery DB sync.
+type SyncConfig struct {
+	RequestInterval time.Duration `yaml:"request_interval"` // the least time between requests to the API
+}
+
+// UsageConfig configures the usage analytics of tool calls and resource reads; see
+// services.UsageLog.
+type UsageConfig struct {
+	Sink          string        `yaml:"sink"`           // "off", "database", or "redis"
+	BufferSize    int           `yaml:"buffer_size"`    // events held for the writer before new ones are dropped
+	FlushInterval time.Duration `yaml:"flush_interval"` // the longest an event waits to be written
+}
+
+// ExportConfig configures the downloadable files of the export_results tool; see
+// services.ExportStore.
+type ExportConfig struct {
+	Store    string        `yaml:"store"`     // "file" or "redis"
+	Dir      string        `yaml:"dir"`       // where the file store keeps exports
+	TTL      time.Duration `yaml:"ttl"`       // how long an export can be downloaded
+	MaxBytes int64         `yaml:"max_bytes"` // the largest export; results beyond it are left out
+	MaxRows  int           `yaml:"max_rows"`  // the most rows of an export
+	BaseURL  string        `yaml:"base_url"`  // prefixes download links, which are relative without it
+}
+
+// Default returns the configuration used when nothing is overridden.
+func Default() *Config {
+	return &Config{
+		Port: DefaultPort,
+		Log:  LogConfig{Level: "info", Format: "json"},
+		Database: DatabaseConfig{
+			MaxOpenConns:    25,
+			MaxIdleConns:    5,
+			ConnMaxLifetime: 5 * time.Minute,
+			ConnMaxIdleTime: 2 * time.Minute,
+			ConnectTimeout:  60 * time.Second,
+		},
+		Query: QueryConfig{
+			Timeout:       services.DefaultQueryTimeout,
+			SlowThreshold: services.DefaultSlowQueryThreshold,
+			RetryAttempts: services.DefaultQueryAttempts,
+			RetryBackoff:  services.DefaultQueryRetryBackoff,
+		},
+		Cache: CacheConfig{SearchTTL: services.DefaultCacheTTL, ResourceTTL: 30 * time.Second},
+		HTTP: HTTPConfig{
+			ReadTimeout:        30 * time.Second,
+			WriteTimeout:       30 * time.Second,
+			IdleTimeout:        120 * time.Second,
+			ShutdownDrainDelay: 5 * time.Second,
+		},
+		MCP: MCPConfig{
+			MaxRequestBytes:  DefaultMaxRequestBytes,
+			ToolQueueTimeout: DefaultToolQueueTimeout,
+			Capture:          CaptureConfig{Redact: DefaultCaptureRedact, MaxBytes: 4096, TraceSize: 100},
+		},
+		Debug: DebugConfig{Port: DefaultDebugPort},
+		Seed:  SeedConfig{OnStartup: true, Timeout: 2 * time.Minute},
+		Sync:  SyncConfig{RequestInterval: services.DefaultSyncRequestInterval},
+		Usage: UsageConfig{
+			Sink:          services.UsageSinkDatabase,
+			BufferSize:    services.DefaultUsageBufferSize,
+			FlushInterval: services.DefaultUsageFlushInterval,
+		},
+		Export: ExportConfig{
+			Store:    services.ExportStoreFile,
+			Dir:      filepath.Join(os.TempDir(), "brewsource-exports"),
+			TTL:      services.DefaultExportTTL,
+			MaxBytes: services.DefaultExportMaxBytes,
+			MaxRows:  services.DefaultExportMaxRows,
+		},
+	}
+}
+
+// Flags are the command line flags that override the configuration, defined by RegisterFlags.
+type Flags struct {
+	fs        *flag.FlagSet
+	path      string
+	port      int
+	logLevel  string
+	logFormat string
+}
+
+// RegisterFlags defines -config, naming the YAML configuration file, and the flags that override
+// the configuration on fs. Pass the result to Load once fs is parsed.
+func RegisterFlags(fs *flag.FlagSet) *Flags {
+	f := &Flags{fs: fs}
+	fs.StringVar(&f.path, "config", "", "Load configuration from a YAML file, overridden by environment variables")
+	fs.IntVar(&f.port, "port", DefaultPort, "Port for HTTP server")
+	fs.StringVar(&f.logLevel, "log-level", "info", "Log level: debug, info, warn, or error")
+	fs.StringVar(&f.logFormat, "log-format", "json", "Log format: json or text")
+	return f
+}
+
+// apply overrides c with the flags given on the command line.
+func (f *Flags) apply(c *Config) {
+	f.fs.Visit(func(fl *flag.Flag) {
+		switch fl.Name {
+		case "port":
+			c.Port = f.port
+		case "log-level":
+			c.Log.Level = f.logLevel
+		case "log-format":
+			c.Log.Format = f.logFormat
+		}
+	})
+}
+
+// Load returns the default configuration overridden by the file named by -config, then by the
+// environment variables looked up with getenv, and then by the flags given, and validates it. flags
+// may be nil, for no file and no flags.
+func Load(flags *Flags, getenv func(string) string) (*Config, error) {
+	c := Default()
+	if flags != nil && flags.path != "" {
+		if err := c.loadFile(flags.path); err != nil {
+			return nil, err
+		}
+	}
+	if err := c.loadEnv(getenv); err != nil {
+		return nil, err
+	}
+	if flags != nil {
+		flags.apply(c)
+	}
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// loadFile overrides c with the settings of a YAML file. Unknown settings are rejected, so that a
+// misspelt one is not silently ignored.
+func (c *Config) loadFile(path string) error {
+	raw, err := os.ReadFile(filepath.Clean(path)) // #nosec G304 - the path is given by the operator
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(raw))
+	decoder.KnownFields(true)
+	if err = decoder.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// envVar binds an environment variable to the setting it overrides, a *string, *int, *int64,
+// *bool, or *time.Duration field of a Config, or one parsing itself as an encoding.TextUnmarshaler.
+type envVar struct {
+	name    string
+	setting interface{}
+}
+
+// envVars lists the environment variables overriding the settings of c.
+func (c *Config) envVars() []envVar {
+	return []envVar{
+		{"PORT", &c.Port},
+		{"DATABASE_URL", &c.DatabaseURL},
+		{"REDIS_URL", &c.RedisURL},
+		{"ADMIN_TOKEN", &c.AdminToken},
+		{"LOG_LEVEL", &c.Log.Level},
+		{"LOG_FORMAT", &c.Log.Format},
+		{"DB_MAX_OPEN_CONNS", &c.Database.MaxOpenConns},
+		{"DB_MAX_IDLE_CONNS", &c.Database.MaxIdleConns},
+		{"DB_CONN_MAX_LIFETIME", &c.Database.ConnMaxLifetime},
+		{"DB_CONN_MAX_IDLE_TIME", &c.Database.ConnMaxIdleTime},
+		{"DB_CONNECT_TIMEOUT", &c.Database.ConnectTimeout},
+		{"QUERY_TIMEOUT", &c.Query.Timeout},
+		{"SLOW_QUERY_THRESHOLD", &c.Query.SlowThreshold},
+		{"QUERY_RETRY_ATTEMPTS", &c.Query.RetryAttempts},
+		{"QUERY_RETRY_BACKOFF", &c.Query.RetryBackoff},
+		{"SEARCH_CACHE_TTL", &c.Cache.SearchTTL},
+		{"RESOURCE_CACHE_TTL", &c.Cache.ResourceTTL},
+		{"HTTP_READ_TIMEOUT", &c.HTTP.ReadTimeout},
+		{"HTTP_WRITE_TIMEOUT", &c.HTTP.WriteTimeout},
+		{"HTTP_IDLE_TIMEOUT", &c.HTTP.IdleTimeout},
+		{"SHUTDOWN_DRAIN_DELAY", &c.HTTP.ShutdownDrainDelay},
+		{"TLS_CERT_FILE", &c.TLS.CertFile},
+		{"TLS_KEY_FILE", &c.TLS.KeyFile},
+		{"TLS_CLIENT_CA_FILE", &c.TLS.ClientCAFile},
+		{"TLS_REDIRECT_PORT", &c.TLS.RedirectPort},
+		{"MCP_MAX_REQUEST_BYTES", &c.MCP.MaxRequestBytes},
+		{"MCP_TOOL_LIMITS", &c.MCP.ToolLimits},
+		{"MCP_TOOL_QUEUE_TIMEOUT", &c.MCP.ToolQueueTimeout},
+		{"MCP_CAPTURE", &c.MCP.Capture.Enabled},
+		{"MCP_CAPTURE_REDACT", &c.MCP.Capture.Redact},
+		{"MCP_CAPTURE_MAX_BYTES", &c.MCP.Capture.MaxBytes},
+		{"MCP_TRACE_SIZE", &c.MCP.Capture.TraceSize},
+		{"ENABLE_DEBUG_ENDPOINTS", &c.Debug.Enabled},
+		{"DEBUG_PORT", &c.Debug.Port},
+		{"SEED_ON_STARTUP", &c.Seed.OnStartup},
+		{"SEED_TIMEOUT", &c.Seed.Timeout},
+		{services.SeedBreweriesPathEnv, &c.Seed.BreweriesPath},
+		{services.SeedBeersPathEnv, &c.Seed.BeersPath},
+		{services.BeerTagsPathEnv, &c.Seed.TagsPath},
+		{data.BJCPDataPathEnv, &c.Data.BJCPPath},
+		{data.GuidelineDataPathEnv("2015"), &c.Data.BJCP2015Path},
+		{"SYNC_REQUEST_INTERVAL", &c.Sync.RequestInterval},
+		{"USAGE_SINK", &c.Usage.Sink},
+		{"USAGE_BUFFER_SIZE", &c.Usage.BufferSize},
+		{"USAGE_FLUSH_INTERVAL", &c.Usage.FlushInterval},
+		{"EXPORT_STORE", &c.Export.Store},
+		{"EXPORT_DIR", &c.Export.Dir},
+		{"EXPORT_TTL", &c.Export.TTL},
+		{"EXPORT_MAX_BYTES", &c.Export.MaxBytes},
+		{"EXPORT_MAX_ROWS", &c.Export.MaxRows},
+		{"EXPORT_BASE_URL", &c.Export.BaseURL},
+	}
+}
+
+// loadEnv overrides c with the environment variables that are set and not empty.
+func (c *Config) loadEnv(getenv func(string) string) error {
+	var errs []error
+	for _, v := range c.envVars() {
+		if value := getenv(v.name); value != "" {
+			errs = append(errs, v.set(value))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// set parses value into the setting of v.
+func (v envVar) set(value string) error {
+	var err error
+	switch setting := v.setting.(type) {
+	case *string:
+		*setting = value
+	case *int:
+		if *setting, err = strconv.Atoi(value); err != nil {
+			return fmt.Errorf("invalid %s %q: not a whole number", v.name, value)
+		}
+	case *int64:
+		if *setting, err = strconv.ParseInt(value, 10, 64); err != nil {
+			return fmt.Errorf("invalid %s %q: not a whole number", v.name, value)
+		}
+	case *bool:
+		if *setting, err = strconv.ParseBool(value); err != nil {
+			return fmt.Errorf("invalid %s %q: not true or false", v.name, value)
+		}
+	case *time.Duration:
+		if *setting, err = time.ParseDuration(value); err != nil {
+			return fmt.Errorf("invalid %s %q: not a duration such as 30s", v.name, value)
+		}
+	case encoding.TextUnmarshaler:
+		if err = setting.UnmarshalText([]byte(value)); err != nil {
+			return fmt.Errorf("invalid %s: %w", v.name, err)
+		}
+	}
+	return nil
+}
+
+// Validate checks that the ports are in range and distinct, that every count and duration is
+// positive, including the tool concurrency limits, that the log level, log format, usage sink, and
+// export store are known, and that the TLS files are given together, naming every problem.
+// DATABASE_URL is not required here, as only the commands that use the database need it.
+func (c *Config) Validate() error {
+	var errs []error
+	if c.Port < 1 || c.Port > 65535 {
+		errs = append(errs, fmt.Errorf("PORT must be between 1 and 65535 (got %d)", c.Port))
+	}
+	errs = append(errs, c.TLS.validate(c.Port))
+	if c.Debug.Port < 1 || c.Debug.Port > 65535 {
+		errs = append(errs, fmt.Errorf("DEBUG_PORT must be between 1 and 65535 (got %d)", c.Debug.Port))
+	} else if c.Debug.Enabled && (c.Debug.Port == c.Port || c.Debug.Port == c.TLS.RedirectPort) {
+		errs = append(errs, fmt.Errorf("DEBUG_PORT must differ from PORT and TLS_REDIRECT_PORT (got %d)", c.Debug.Port))
+	}
+	for _, v := range c.envVars() {
+		var positive bool
+		switch setting := v.setting.(type) {
+		case *int:
+			positive = *setting > 0
+		case *int64:
+			positive = *setting > 0
+		case *time.Duration:
+			positive = *setting > 0
+		default:
+			continue
+		}
+		if !positive && !strings.HasSuffix(v.name, "PORT") {
+			errs = append(errs, fmt.Errorf("%s must be greater than zero", v.name))
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(c.MCP.ToolLimits)) {
+		if limit := c.MCP.ToolLimits[name]; limit.Concurrency < 1 || limit.Queue < 0 {
+			errs = append(errs, fmt.Errorf("MCP_TOOL_LIMITS for %s must allow at least one call and queue none or "+
+				"more (got %d:%d)", name, limit.Concurrency, limit.Queue))
+		}
+	}
+	if _, err := logrus.ParseLevel(c.Log.Level); err != nil {
+		errs = append(errs, fmt.Errorf("LOG_LEVEL %q is not a log level such as info or debug", c.Log.Level))
+	}
+	if c.Log.Format != "json" && c.Log.Format != "text" {
+		errs = append(errs, fmt.Errorf("LOG_FORMAT %q must be json or text", c.Log.Format))
+	}
+	switch {
+	case !slices.Contains(services.UsageSinks, c.Usage.Sink):
+		errs = append(errs, fmt.Errorf("USAGE_SINK %q must be one of: %s", c.Usage.Sink,
+			strings.Join(services.UsageSinks, ", ")))
+	case c.Usage.Sink == services.UsageSinkRedis && c.RedisURL == "":
+		errs = append(errs, errors.New("USAGE_SINK redis needs REDIS_URL"))
+	}
+	switch {
+	case !slices.Contains(services.ExportStores, c.Export.Store):
+		errs = append(errs, fmt.Errorf("EXPORT_STORE %q must be one of: %s", c.Export.Store,
+			strings.Join(services.ExportStores, ", ")))
+	case c.Export.Store == services.ExportStoreRedis && c.RedisURL == "":
+		errs = append(errs, errors.New("EXPORT_STORE redis needs REDIS_URL"))
+	}
+	return errors.Join(errs...)
+}
+
+// validate checks that the certificate and key are given together, that a client CA or redirect
+// port is only given with them, and that the redirect port is in range and not the HTTPS port.
+func (c TLSConfig) validate(port int) error {
+	var errs []error
+	if (c.CertFile == "") != (c.KeyFile == "") {
+		errs = append(errs, errors.New("TLS_CERT_FILE and TLS_KEY_FILE must be given together"))
+	}
+	if !c.Enabled() && c.ClientCAFile != "" {
+		errs = append(errs, errors.New("TLS_CLIENT_CA_FILE needs TLS_CERT_FILE and TLS_KEY_FILE"))
+	}
+	if c.RedirectPort != 0 {
+		switch {
+		case !c.Enabled():
+			errs = append(errs, errors.New("TLS_REDIRECT_PORT needs TLS_CERT_FILE and TLS_KEY_FILE"))
+		case c.RedirectPort < 1 || c.RedirectPort > 65535:
+			errs = append(errs, fmt.Errorf("TLS_REDIRECT_PORT must be between 1 and 65535 (got %d)", c.RedirectPort))
+		case c.RedirectPort == port:
+			errs = append(errs, fmt.Errorf("TLS_REDIRECT_PORT must differ from PORT (both %d)", port))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// DataPathEnv returns the environment variables naming the seed, flavour tag, and style data files that are set,
+// for the packages that look them up there, including when the style data is reloaded on SIGHUP.
+func (c *Config) DataPathEnv() map[string]string {
+	env := make(map[string]string)
+	for _, v := range c.envVars() {
+		switch v.name {
+		case services.SeedBreweriesPathEnv, services.SeedBeersPathEnv, services.BeerTagsPathEnv,
+			data.BJCPDataPathEnv, data.GuidelineDataPathEnv("2015"):
+			if path := *v.setting.(*string); path != "" {
+				env[v.name] = path
+			}
+		}
+	}
+	return env
+}
+
+// ApplyLogging sets the logrus level and format.
+func (c *Config) ApplyLogging() {
+	if level, err := logrus.ParseLevel(c.Log.Level); err == nil {
+		logrus.SetLevel(level)
+	}
+	if c.Log.Format == "text" {
+		logrus.SetFormatter(&logrus.TextFormatter{})
+	} else {
+		logrus.SetFormatter(&logrus.JSONFormatter{})
+	}
+}
+
+// Redacted returns a copy of c that is safe to print: the admin token and the passwords of the
+// database and Redis URLs are replaced.
+func (c *Config) Redacted() *Config {
+	r := *c
+	r.DatabaseURL = redactURL(c.DatabaseURL)
+	r.RedisURL = redactURL(c.RedisURL)
+	if r.AdminToken != "" {
+		r.AdminToken = redacted
+	}
+	return &r
+}
+
+// WriteYAML writes c as YAML, in the format of a config file.
+func (c *Config) WriteYAML(w io.Writer) error {
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(c); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// dsnPassword matches the password of a key=value PostgreSQL connection string.
+var dsnPassword = regexp.MustCompile(`(?i)(password\s*=\s*)('(?:[^'\\]|\\.)*'|[^\s&]+)`)
+
+// redactURL replaces the password of a URL, or of a key=value connection string.
+func redactURL(value string) string {
+	if u, err := url.Parse(value); err == nil && u.User != nil {
+		if _, ok := u.User.Password(); ok {
+			return u.Redacted()
+		}
+	}
+	if strings.Contains(value, "=") {
+		return dsnPassword.ReplaceAllString(value, "${1}"+redacted)
+	}
+	return value
+}
@@ -0,0 +1,271 @@
+package config_test
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// env returns a getenv looking variables up in vars.
+func env(vars map[string]string) func(string) string {
+	return func(name string) string { return vars[name] }
+}
+
+// writeConfigFile writes a YAML config file and returns its path.
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "brewsource.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+// parseFlags registers the config flags on a new flag set and parses args.
+func parseFlags(t *testing.T, args ...string) *config.Flags {
+	t.Helper()
+	fs := flag.NewFlagSet("brewsource-mcp", flag.ContinueOnError)
+	flags := config.RegisterFlags(fs)
+	require.NoError(t, fs.Parse(args))
+	return flags
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	cfg, err := config.Load(nil, env(nil))
+	require.NoError(t, err)
+	assert.Equal(t, config.Default(), cfg)
+	assert.Equal(t, 8080, cfg.Port)
+	assert.Equal(t, 25, cfg.Database.MaxOpenConns)
+	assert.Equal(t, 60*time.Second, cfg.Database.ConnectTimeout)
+	assert.True(t, cfg.Seed.OnStartup)
+
+	// Flags left at their defaults do not override the environment
+	cfg, err = config.Load(parseFlags(t), env(map[string]string{"PORT": "9000"}))
+	require.NoError(t, err)
+	assert.Equal(t, 9000, cfg.Port)
+}
+
+func TestLoad_Layering(t *testing.T) {
+	path := writeConfigFile(t, `
+port: 9000
+database_url: postgres://brewsource@db:5432/brewsource
+log:
+  level: debug
+database:
+  max_open_conns: 10
+  connect_timeout: 2m
+query:
+  timeout: 3s
+seed:
+  on_startup: false
+`)
+	flags := parseFlags(t, "-config", path, "-port", "9200", "-log-format", "text")
+	cfg, err := config.Load(flags, env(map[string]string{
+		"PORT":              "9100",
+		"DB_MAX_OPEN_CONNS": "40",
+		"SEARCH_CACHE_TTL":  "1m",
+	}))
+	require.NoError(t, err)
+
+	assert.Equal(t, 9200, cfg.Port, "flags override the environment")
+	assert.Equal(t, 40, cfg.Database.MaxOpenConns, "the environment overrides the file")
+	assert.Equal(t, "postgres://brewsource@db:5432/brewsource", cfg.DatabaseURL)
+	assert.Equal(t, "debug", cfg.Log.Level)
+	assert.Equal(t, "text", cfg.Log.Format)
+	assert.Equal(t, 2*time.Minute, cfg.Database.ConnectTimeout)
+	assert.Equal(t, 3*time.Second, cfg.Query.Timeout)
+	assert.Equal(t, time.Minute, cfg.Cache.SearchTTL)
+	assert.False(t, cfg.Seed.OnStartup)
+	assert.Equal(t, 5, cfg.Database.MaxIdleConns, "settings set nowhere keep their defaults")
+	assert.False(t, cfg.TLS.Enabled())
+
+	cfg, err = config.Load(nil, env(map[string]string{
+		"PORT": "8443", "TLS_CERT_FILE": "/tls/tls.crt", "TLS_KEY_FILE": "/tls/tls.key", "TLS_REDIRECT_PORT": "8080",
+	}))
+	require.NoError(t, err)
+	assert.True(t, cfg.TLS.Enabled())
+	assert.Equal(t, 8080, cfg.TLS.RedirectPort)
+}
+
+func TestLoad_ToolLimits(t *testing.T) {
+	path := writeConfigFile(t, `
+mcp:
+  tool_limits:
+    search_beers: {concurrency: 4, queue: 8}
+  tool_queue_timeout: 1s
+`)
+	cfg, err := config.Load(parseFlags(t, "-config", path), env(nil))
+	require.NoError(t, err)
+	assert.Equal(t, config.ToolLimits{"search_beers": {Concurrency: 4, Queue: 8}}, cfg.MCP.ToolLimits)
+	assert.Equal(t, time.Second, cfg.MCP.ToolQueueTimeout)
+
+	cfg, err = config.Load(parseFlags(t, "-config", path), env(map[string]string{
+		"MCP_TOOL_LIMITS": "find_breweries=2, search_beers=3:5",
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, config.ToolLimits{
+		"find_breweries": {Concurrency: 2},
+		"search_beers":   {Concurrency: 3, Queue: 5},
+	}, cfg.MCP.ToolLimits, "the environment replaces the limits of the file")
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		env     map[string]string
+		wantErr []string
+	}{
+		{
+			name:    "port out of range",
+			env:     map[string]string{"PORT": "70000"},
+			wantErr: []string{"PORT must be between 1 and 65535 (got 70000)"},
+		},
+		{
+			name: "values that do not parse",
+			env: map[string]string{
+				"DB_MAX_OPEN_CONNS": "many", "QUERY_TIMEOUT": "soon", "SEED_ON_STARTUP": "maybe",
+			},
+			wantErr: []string{
+				`invalid DB_MAX_OPEN_CONNS "many"`, `invalid QUERY_TIMEOUT "soon"`, `invalid SEED_ON_STARTUP "maybe"`,
+			},
+		},
+		{
+			name: "non-positive values",
+			env:  map[string]string{"DB_MAX_OPEN_CONNS": "-1", "DB_CONN_MAX_LIFETIME": "0s"},
+			wantErr: []string{
+				"DB_MAX_OPEN_CONNS must be greater than zero", "DB_CONN_MAX_LIFETIME must be greater than zero",
+			},
+		},
+		{
+			name:    "unknown log level and format",
+			env:     map[string]string{"LOG_LEVEL": "loud", "LOG_FORMAT": "xml"},
+			wantErr: []string{`LOG_LEVEL "loud"`, `LOG_FORMAT "xml" must be json or text`},
+		},
+		{
+			name:    "TLS settings without a certificate and key",
+			env:     map[string]string{"TLS_KEY_FILE": "/tls/tls.key", "TLS_CLIENT_CA_FILE": "/tls/ca.crt"},
+			wantErr: []string{"must be given together", "TLS_CLIENT_CA_FILE needs TLS_CERT_FILE"},
+		},
+		{
+			name: "TLS redirect port on the HTTPS port",
+			env: map[string]string{
+				"TLS_CERT_FILE": "/tls/tls.crt", "TLS_KEY_FILE": "/tls/tls.key", "TLS_REDIRECT_PORT": "8080",
+			},
+			wantErr: []string{"TLS_REDIRECT_PORT must differ from PORT (both 8080)"},
+		},
+		{
+			name:    "debug endpoints on the public port",
+			env:     map[string]string{"ENABLE_DEBUG_ENDPOINTS": "true", "DEBUG_PORT": "8080"},
+			wantErr: []string{"DEBUG_PORT must differ from PORT"},
+		},
+		{
+			name:    "tool limits that do not parse",
+			env:     map[string]string{"MCP_TOOL_LIMITS": "search_beers=many"},
+			wantErr: []string{`invalid MCP_TOOL_LIMITS: "search_beers=many": concurrency "many" is not a whole number`},
+		},
+		{
+			name:    "tool limits allowing no calls",
+			env:     map[string]string{"MCP_TOOL_LIMITS": "search_beers=0,find_breweries=2:-1"},
+			wantErr: []string{"MCP_TOOL_LIMITS for find_breweries", "MCP_TOOL_LIMITS for search_beers"},
+		},
+		{
+			name: "unknown export store",
+			env:  map[string]string{"EXPORT_STORE": "s3", "EXPORT_MAX_ROWS": "0"},
+			wantErr: []string{
+				`EXPORT_STORE "s3" must be one of: file, redis`, "EXPORT_MAX_ROWS must be greater than zero",
+			},
+		},
+		{
+			name:    "Redis export store without Redis",
+			env:     map[string]string{"EXPORT_STORE": "redis"},
+			wantErr: []string{"EXPORT_STORE redis needs REDIS_URL"},
+		},
+		{
+			name:    "invalid file value",
+			file:    "query:\n  retry_attempts: 0\n",
+			wantErr: []string{"QUERY_RETRY_ATTEMPTS must be greater than zero"},
+		},
+		{
+			name:    "unknown file setting",
+			file:    "query:\n  timeuot: 3s\n",
+			wantErr: []string{"failed to parse config file", "field timeuot not found"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var args []string
+			if tt.file != "" {
+				args = []string{"-config", writeConfigFile(t, tt.file)}
+			}
+			_, err := config.Load(parseFlags(t, args...), env(tt.env))
+			require.Error(t, err)
+			for _, want := range tt.wantErr {
+				assert.ErrorContains(t, err, want)
+			}
+		})
+	}
+
+	_, err := config.Load(parseFlags(t, "-config", filepath.Join(t.TempDir(), "missing.yaml")), env(nil))
+	assert.ErrorContains(t, err, "failed to read config file")
+}
+
+func TestRedacted(t *testing.T) {
+	tests := []struct {
+		databaseURL string
+		want        string
+	}{
+		{"postgres://brewsource:s3cret@db:5432/brewsource", "postgres://brewsource:xxxxx@db:5432/brewsource"},
+		{"postgres://db/brewsource?user=brewsource&password=s3cret&sslmode=disable",
+			"postgres://db/brewsource?user=brewsource&password=xxxxx&sslmode=disable"},
+		{"host=db user=brewsource password='s3 cret' dbname=brewsource",
+			"host=db user=brewsource password=xxxxx dbname=brewsource"},
+		{"postgres://brewsource@db:5432/brewsource", "postgres://brewsource@db:5432/brewsource"},
+		{"sqlite:///var/lib/brewsource.db", "sqlite:///var/lib/brewsource.db"},
+	}
+	for _, tt := range tests {
+		cfg := config.Default()
+		cfg.DatabaseURL = tt.databaseURL
+		assert.Equal(t, tt.want, cfg.Redacted().DatabaseURL)
+	}
+
+	cfg := config.Default()
+	cfg.DatabaseURL = "postgres://brewsource:s3cret@db:5432/brewsource"
+	cfg.RedisURL = "redis://:hunter2@redis:6379/0"
+	cfg.AdminToken = "letmein"
+	redacted := cfg.Redacted()
+	assert.Equal(t, "redis://:xxxxx@redis:6379/0", redacted.RedisURL)
+	assert.Equal(t, "xxxxx", redacted.AdminToken)
+	assert.Equal(t, "letmein", cfg.AdminToken, "the configuration itself is left as it is")
+
+	var out bytes.Buffer
+	require.NoError(t, redacted.WriteYAML(&out))
+	for _, secret := range []string{"s3cret", "hunter2", "letmein"} {
+		assert.NotContains(t, out.String(), secret)
+	}
+	assert.Contains(t, out.String(), "connect_timeout: 1m0s")
+
+	// The printed configuration can be loaded as a config file
+	loaded, err := config.Load(parseFlags(t, "-config", writeConfigFile(t, out.String())), env(nil))
+	require.NoError(t, err)
+	assert.Equal(t, redacted, loaded)
+}
+
+func TestDataPathEnv(t *testing.T) {
+	cfg := config.Default()
+	assert.Empty(t, cfg.DataPathEnv())
+
+	cfg.Seed.BeersPath = "/data/beers.json"
+	cfg.Seed.TagsPath = "/data/tags.json"
+	cfg.Data.BJCP2015Path = "/data/bjcp_2015.json"
+	assert.Equal(t, map[string]string{
+		"SEED_BEERS_PATH":     "/data/beers.json",
+		"BEER_TAGS_PATH":      "/data/tags.json",
+		"BJCP_2015_DATA_PATH": "/data/bjcp_2015.json",
+	}, cfg.DataPathEnv())
+}
@@ -0,0 +1,145 @@
+// Package conformance checks the MCP wire protocol of the server against golden files. Its tests
+// replay the JSON-RPC requests recorded in testdata/requests through mcp.Server.ProcessMessage and
+// compare each response with the one in testdata/golden, so that a change to a response's shape
+// (tool content, error data, the initialize result) shows up as a diff to review rather than after
+// a deploy. After a deliberate change, rewrite the golden files and review their diff:
+//
+//	go test ./internal/conformance -update
+//
+// The server is the one the binary serves, see NewServer, answering from in-memory fakes of the
+// catalog services with a fixed clock and version, so that its responses are the same on every run.
+package conformance
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/handlers"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/mcp"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/services"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/services/servicestest"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/version"
+	"github.com/CharlRitter/brewsource-mcp/app/pkg/data"
+)
+
+// AdminToken enables the admin tools of the server.
+const AdminToken = "conformance-admin-token"
+
+// Beer IDs whose lookup fails, to record the errors of a failing catalog.
+const (
+	FailingBeerID = 500 // fails as a broken database connection does
+	TimeoutBeerID = 504 // fails as a query cancelled by its deadline does
+)
+
+// Now is the time the server's clock is fixed at, a Saturday afternoon in Cape Town.
+var Now = time.Date(2025, 3, 8, 14, 30, 0, 0, time.UTC)
+
+// Version is the build information the server reports in its initialize result.
+var Version = version.Info{Version: "1.0.0-conformance", Commit: "0000000", GoVersion: "go1.x"}
+
+// NewServer returns an MCP server with the tool and resource handlers of the server binary,
+// answering from fakes of the catalog services holding the beers and breweries of Catalog, with
+// the clock fixed at Now and the version at Version.
+func NewServer() (*mcp.Server, error) {
+	guidelines, err := data.LoadBJCPGuidelines()
+	if err != nil {
+		return nil, err
+	}
+	ingredientService, err := data.NewIngredientService()
+	if err != nil {
+		return nil, err
+	}
+	bjcpData := guidelines[data.DefaultGuidelineVersion]
+	bjcpService := data.NewBJCPServiceFromGuidelines(guidelines)
+	beerService, breweryService := Catalog()
+	clock := func() time.Time { return Now }
+
+	toolHandlers := handlers.NewToolHandlers(bjcpData, beerService, breweryService)
+	toolHandlers.SetBJCPService(bjcpService)
+	toolHandlers.SetIngredientService(ingredientService)
+	toolHandlers.SetAdminToken(AdminToken)
+	toolHandlers.SetClock(clock)
+	resourceHandlers := handlers.NewResourceHandlers(bjcpData, beerService, breweryService)
+	resourceHandlers.SetBJCPService(bjcpService)
+	resourceHandlers.SetIngredientService(ingredientService)
+	resourceHandlers.SetClock(clock)
+
+	server := mcp.NewServer(toolHandlers, resourceHandlers, nil)
+	server.SetVersionInfo(Version)
+	toolHandlers.SetResourceNotifier(server.NotifyResourceUpdated)
+	return server, nil
+}
+
+// Catalog returns fakes of the beer and brewery services holding two Cape Town breweries and three
+// of their beers. Looking up the beer with FailingBeerID or TimeoutBeerID fails.
+func Catalog() (services.BeerServiceInterface, services.BreweryServiceInterface) {
+	latitude, longitude := -33.9275, 18.4571
+	devilsPeak := services.Brewery{
+		ID: 1, Name: "Devil's Peak Brewing Company", BreweryType: "micro", Street: "1st Floor, The Old Warehouse",
+		City: "Cape Town", State: "Western Cape", PostalCode: "7925", Country: "South Africa",
+		WebsiteURL: "https://www.devilspeakbrewing.co.za", Latitude: &latitude, Longitude: &longitude,
+		FoundedYear: 2012, Instagram: "devilspeakbeer",
+	}
+	jackBlacks := services.Brewery{
+		ID: 2, Name: "Jack Black's Brewing Company", BreweryType: "micro", City: "Cape Town",
+		State: "Western Cape", Country: "South Africa", WebsiteURL: "https://www.jackblackbeer.com",
+	}
+	beers := []*services.BeerDetail{
+		{ID: 1, Name: "King's Blockhouse IPA", Style: "American IPA", ABV: 6.0, IBU: 52, SRM: 8,
+			Description: "A hop-forward IPA with citrus and pine.", Tags: services.TagList{"citrus", "pine"},
+			BreweryID: 1, Brewery: devilsPeak.Name, BreweryCity: devilsPeak.City, BreweryState: devilsPeak.State,
+			Country: devilsPeak.Country},
+		{ID: 2, Name: "Woodhead Amber Ale", Style: "American Amber Ale", ABV: 5.0, IBU: 30, SRM: 14,
+			BreweryID: 1, Brewery: devilsPeak.Name, BreweryCity: devilsPeak.City, BreweryState: devilsPeak.State,
+			Country: devilsPeak.Country},
+		{ID: 3, Name: "Skeleton Coast IPA", Style: "American IPA", ABV: 5.8, IBU: 45, SRM: 7,
+			BreweryID: 2, Brewery: jackBlacks.Name, BreweryCity: jackBlacks.City, BreweryState: jackBlacks.State,
+			Country: jackBlacks.Country},
+	}
+
+	beerService := &servicestest.BeerService{Beers: beers}
+	breweryService := &servicestest.BreweryService{
+		Breweries: []*services.BreweryDetail{{Brewery: devilsPeak, BeerCount: 2}, {Brewery: jackBlacks, BeerCount: 1}},
+		Beers:     map[int][]*services.BreweryBeer{},
+		Venues: map[int][]*services.Venue{1: {{
+			ID: 1, BreweryID: 1, Name: "Devil's Peak Taproom", Street: devilsPeak.Street, City: devilsPeak.City,
+			Country: devilsPeak.Country, Latitude: &latitude, Longitude: &longitude, IsPrimary: true,
+			Hours: services.VenueHours{"mon": "closed", "tue": "12:00-22:00", "wed": "12:00-22:00",
+				"thu": "12:00-22:00", "fri": "12:00-23:00", "sat": "11:00-23:00", "sun": "11:00-18:00"},
+		}}},
+		DataSources: []*services.DataSource{
+			{Source: services.SeedSource, UpdatedAt: Now.AddDate(0, -1, 0), Records: len(beers), Version: "bundled"},
+		},
+	}
+	for _, beer := range beers {
+		beerService.Results = append(beerService.Results, &services.BeerSearchResult{
+			ID: beer.ID, Name: beer.Name, Style: beer.Style, Brewery: beer.Brewery, City: beer.BreweryCity,
+			Country: beer.Country, ABV: beer.ABV, IBU: beer.IBU,
+		})
+		breweryService.Beers[beer.BreweryID] = append(breweryService.Beers[beer.BreweryID],
+			&services.BreweryBeer{ID: beer.ID, Name: beer.Name, Style: beer.Style, ABV: beer.ABV, IBU: beer.IBU})
+	}
+	for _, brewery := range breweryService.Breweries {
+		breweryService.Results = append(breweryService.Results, &services.BrewerySearchResult{
+			ID: brewery.ID, Name: brewery.Name, BreweryType: brewery.BreweryType, Street: brewery.Street,
+			City: brewery.City, State: brewery.State, PostalCode: brewery.PostalCode, Country: brewery.Country,
+		})
+	}
+	return &failingBeerService{beerService}, breweryService
+}
+
+// failingBeerService fails the lookups of FailingBeerID and TimeoutBeerID.
+type failingBeerService struct {
+	*servicestest.BeerService
+}
+
+func (s *failingBeerService) GetBeerByID(ctx context.Context, id int) (*services.BeerDetail, error) {
+	switch id {
+	case FailingBeerID:
+		return nil, errors.New("dial tcp 127.0.0.1:5432: connection refused")
+	case TimeoutBeerID:
+		return nil, services.ErrQueryTimeout
+	}
+	return s.BeerService.GetBeerByID(ctx, id)
+}
@@ -0,0 +1,91 @@
+package conformance_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/conformance"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/mcp"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/requestid"
+)
+
+// updateGolden rewrites the golden files from the current responses: go test
+// ./internal/conformance -update. Review the diff before committing it.
+var updateGolden = flag.Bool("update", false, "rewrite the golden files of the conformance tests")
+
+// Test each request in testdata/requests against the response in testdata/golden with the same
+// name. Every request is sent to a new server, as the first message of its session, in a context
+// carrying the request ID conformance-<name> to show where error data holds it.
+func TestConformance(t *testing.T) {
+	requests, err := filepath.Glob(filepath.Join("testdata", "requests", "*.json"))
+	if err != nil || len(requests) == 0 {
+		t.Fatalf("Expected request fixtures in testdata/requests, got %v (%v)", requests, err)
+	}
+
+	for _, path := range requests {
+		name := strings.TrimSuffix(filepath.Base(path), ".json")
+		t.Run(name, func(t *testing.T) {
+			request, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("Failed to read the request: %v", err)
+			}
+			server, err := conformance.NewServer()
+			if err != nil {
+				t.Fatalf("Failed to create the server: %v", err)
+			}
+			ctx := requestid.NewContext(context.Background(), "conformance-"+name)
+			got := marshalResponse(t, server.ProcessMessage(ctx, bytes.TrimSpace(request)))
+
+			golden := filepath.Join("testdata", "golden", name+".json")
+			if *updateGolden {
+				if err = os.WriteFile(golden, got, 0o600); err != nil {
+					t.Fatalf("Failed to update the golden file: %v", err)
+				}
+				return
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("Failed to read the golden file, run with -update to create it: %v", err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("Response differs from %s, run with -update and review the diff if the change is "+
+					"deliberate.\nGot:\n%s\nWant:\n%s", golden, got, want)
+			}
+		})
+	}
+}
+
+// Test every golden response is one to a request, so a removed fixture takes its golden file along.
+func TestConformance_GoldenFilesHaveRequests(t *testing.T) {
+	goldens, err := filepath.Glob(filepath.Join("testdata", "golden", "*.json"))
+	if err != nil {
+		t.Fatalf("Failed to list the golden files: %v", err)
+	}
+	for _, golden := range goldens {
+		request := filepath.Join("testdata", "requests", filepath.Base(golden))
+		if _, err = os.Stat(request); err != nil {
+			t.Errorf("Expected a request for %s: %v", golden, err)
+		}
+	}
+}
+
+// marshalResponse renders response as it is sent, indented so that the golden files diff well.
+func marshalResponse(t *testing.T, response *mcp.Message) []byte {
+	t.Helper()
+	encoded, err := json.Marshal(response)
+	if err != nil {
+		t.Fatalf("Failed to marshal the response: %v", err)
+	}
+	var out bytes.Buffer
+	if err = json.Indent(&out, encoded, "", "  "); err != nil {
+		t.Fatalf("Failed to indent the response: %v", err)
+	}
+	out.WriteByte('\n')
+	return out.Bytes()
+}
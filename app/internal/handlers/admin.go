@@ -0,0 +1,402 @@
+// Package handlers provides HTTP handlers for the Brewsource MCP server, including health checks and resource endpoints.
+package handlers
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/mcp"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/requestid"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/services"
+	"github.com/CharlRitter/brewsource-mcp/app/pkg/data"
+	"github.com/sirupsen/logrus"
+)
+
+// SetAdminToken enables the admin tools, which write to the catalog, for callers that pass token as
+// their admin_token argument. The tools stay disabled while the token is empty.
+func (h *ToolHandlers) SetAdminToken(token string) {
+	h.adminToken = token
+}
+
+// SetBrewerySyncer enables the sync_breweries admin tool.
+func (h *ToolHandlers) SetBrewerySyncer(syncer services.BrewerySyncerInterface) {
+	h.brewerySyncer = syncer
+}
+
+// SetResourceNotifier sets how reload_data tells subscribed clients that bjcp://styles changed,
+// typically mcp.Server.NotifyResourceUpdated.
+func (h *ToolHandlers) SetResourceNotifier(notify func(uri string) bool) {
+	h.notifyResource = notify
+}
+
+// registerAdminTools registers the catalog write tools.
+func (h *ToolHandlers) registerAdminTools(server *mcp.Server) {
+	server.RegisterToolHandler("add_brewery", h.AddBrewery)
+	server.RegisterToolHandler("add_beer", h.AddBeer)
+	server.RegisterToolHandler("delete_brewery", h.DeleteBrewery)
+	server.RegisterToolHandler("restore_brewery", h.RestoreBrewery)
+	server.RegisterToolHandler("sync_breweries", h.SyncBreweries)
+	server.RegisterToolHandler("seed_catalog", h.SeedCatalog)
+	server.RegisterToolHandler("import_catalog", h.ImportCatalog)
+	server.RegisterToolHandler("get_job_status", h.GetJobStatus)
+	server.RegisterToolHandler("reload_data", h.ReloadData)
+}
+
+// adminToolDefinitions describes the catalog write tools.
+func adminToolDefinitions() []mcp.Tool {
+	adminToken := mcp.StringSchema("Admin token configured with ADMIN_TOKEN on the server", true)
+	return []mcp.Tool{
+		{
+			Name:        "add_brewery",
+			Description: "Add a brewery to the catalog (admin only; disabled unless the server sets ADMIN_TOKEN)",
+			InputSchema: mcp.ObjectSchema(map[string]interface{}{
+				"admin_token": adminToken,
+				"name":        mcp.StringSchema("Brewery name", true),
+				"brewery_type": map[string]interface{}{
+					"type":        "string",
+					"description": "Brewery type",
+					"enum":        services.BreweryTypes,
+				},
+				"street":      mcp.StringSchema("Street address", false),
+				"city":        mcp.StringSchema("City", false),
+				"state":       mcp.StringSchema("State or province", false),
+				"postal_code": mcp.StringSchema("Postal code", false),
+				"country":     mcp.StringSchema("Country", false),
+				"phone":       mcp.StringSchema("Phone number", false),
+				"website_url": mcp.StringSchema("Website URL", false),
+				"latitude":    mcp.NumberSchema("Latitude in decimal degrees; give with longitude"),
+				"longitude":   mcp.NumberSchema("Longitude in decimal degrees; give with latitude"),
+				"description": mcp.StringSchema("What the brewery is known for", false),
+				"founded_year": mcp.IntegerSchema(
+					fmt.Sprintf("Year the brewery was founded, %d or later", services.MinFoundedYear),
+				),
+				"instagram": mcp.StringSchema("Instagram handle", false),
+				"facebook":  mcp.StringSchema("Facebook page name", false),
+				"twitter":   mcp.StringSchema("X/Twitter handle", false),
+				"email":     mcp.StringSchema("Contact email address", false),
+			}, []string{"admin_token", "name", "brewery_type"}),
+		},
+		{
+			Name: "add_beer",
+			Description: "Add a beer to a brewery in the catalog " +
+				"(admin only; disabled unless the server sets ADMIN_TOKEN)",
+			InputSchema: mcp.ObjectSchema(map[string]interface{}{
+				"admin_token":  adminToken,
+				"brewery_id":   mcp.IntegerSchema("ID of the brewery that makes the beer"),
+				"brewery_name": mcp.StringSchema("Brewery name, used when brewery_id is not given", false),
+				"name":         mcp.StringSchema("Beer name", true),
+				"style":        mcp.StringSchema("Beer style", false),
+				"abv":          mcp.NumberSchema("Alcohol by volume in percent, 0 to 20"),
+				"ibu":          mcp.IntegerSchema("Bitterness in IBU, 0 to 200"),
+				"srm":          mcp.NumberSchema("Colour in SRM"),
+				"description":  mcp.StringSchema("Tasting notes or other description", false),
+			}, []string{"admin_token", "name"}),
+		},
+		{
+			Name: "delete_brewery",
+			Description: "Hide a closed brewery and its beers from searches and lookups without removing them; " +
+				"restore_brewery undoes it (admin only; disabled unless the server sets ADMIN_TOKEN)",
+			InputSchema: mcp.ObjectSchema(map[string]interface{}{
+				"admin_token": adminToken,
+				"id":          mcp.IntegerSchema("Brewery ID"),
+			}, []string{"admin_token", "id"}),
+		},
+		{
+			Name: "restore_brewery",
+			Description: "Make a brewery hidden by delete_brewery visible again " +
+				"(admin only; disabled unless the server sets ADMIN_TOKEN)",
+			InputSchema: mcp.ObjectSchema(map[string]interface{}{
+				"admin_token": adminToken,
+				"id":          mcp.IntegerSchema("Brewery ID"),
+			}, []string{"admin_token", "id"}),
+		},
+		{
+			Name: "sync_breweries",
+			Description: "Queue a job copying breweries from Open Brewery DB into the catalog, returning its job " +
+				"ID, and report the last sync (admin only; disabled unless the server sets ADMIN_TOKEN)",
+			InputSchema: mcp.ObjectSchema(map[string]interface{}{
+				"admin_token": adminToken,
+			}, []string{"admin_token"}),
+		},
+		{
+			Name: "seed_catalog",
+			Description: "Queue a job seeding the breweries and beers missing from the catalog from a seed file " +
+				"on the server, returning its job ID (admin only; disabled unless the server sets ADMIN_TOKEN)",
+			InputSchema: mcp.ObjectSchema(map[string]interface{}{
+				"admin_token": adminToken,
+				"seed_file": mcp.StringSchema(
+					"Path of a JSON seed file on the server; the bundled seed data when omitted", false),
+				"reseed": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Delete every brewery and beer before seeding; needs force",
+				},
+				"force": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Confirm a reseed",
+				},
+			}, []string{"admin_token"}),
+		},
+		{
+			Name: "import_catalog",
+			Description: "Queue a job importing brewery and beer .csv or .json files on the server into the " +
+				"catalog, returning its job ID (admin only; disabled unless the server sets ADMIN_TOKEN)",
+			InputSchema: mcp.ObjectSchema(map[string]interface{}{
+				"admin_token":    adminToken,
+				"breweries_file": mcp.StringSchema("Path of a brewery file on the server", false),
+				"beers_file": mcp.StringSchema(
+					"Path of a beer file on the server, imported after the breweries", false),
+				"upsert": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Update rows matching existing records instead of skipping them",
+				},
+				"strict": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Import nothing if any row is invalid",
+				},
+			}, []string{"admin_token"}),
+		},
+		{
+			Name: "get_job_status",
+			Description: "Report the state of a job queued by sync_breweries, seed_catalog, or import_catalog: " +
+				"queued, running, done with its result, or failed with its error; lists recent jobs without a " +
+				"job_id (admin only; disabled unless the server sets ADMIN_TOKEN)",
+			InputSchema: mcp.ObjectSchema(map[string]interface{}{
+				"admin_token": adminToken,
+				"job_id":      mcp.StringSchema("Job ID returned by the tool that queued the job", false),
+			}, []string{"admin_token"}),
+		},
+		{
+			Name: "reload_data",
+			Description: "Reload the BJCP style guide from its data file without restarting the server " +
+				"(admin only; disabled unless the server sets ADMIN_TOKEN)",
+			InputSchema: mcp.ObjectSchema(map[string]interface{}{
+				"admin_token": adminToken,
+			}, []string{"admin_token"}),
+		},
+	}
+}
+
+// checkAdminToken rejects admin tool calls while the tools are disabled or when admin_token does not
+// match the configured token.
+func (h *ToolHandlers) checkAdminToken(args map[string]interface{}) error {
+	if h.adminToken == "" {
+		return &mcp.Error{Code: mcp.InvalidRequest, Message: "admin tools are disabled on this server"}
+	}
+	token, _ := args["admin_token"].(string)
+	if subtle.ConstantTimeCompare([]byte(token), []byte(h.adminToken)) != 1 {
+		return &mcp.Error{Code: mcp.InvalidRequest, Message: "invalid admin_token"}
+	}
+	return nil
+}
+
+// AddBrewery adds a brewery to the catalog and returns its ID.
+func (h *ToolHandlers) AddBrewery(ctx context.Context, args map[string]interface{}) (*mcp.ToolResult, error) {
+	if err := h.checkAdminToken(args); err != nil {
+		return nil, err
+	}
+	var brewery services.Brewery
+	for key, field := range map[string]*string{
+		"name": &brewery.Name, "brewery_type": &brewery.BreweryType, "street": &brewery.Street,
+		"city": &brewery.City, "state": &brewery.State, "postal_code": &brewery.PostalCode,
+		"country": &brewery.Country, "phone": &brewery.Phone, "website_url": &brewery.WebsiteURL,
+		"description": &brewery.Description, "instagram": &brewery.Instagram, "facebook": &brewery.Facebook,
+		"twitter": &brewery.Twitter, "email": &brewery.Email,
+	} {
+		*field, _ = args[key].(string)
+	}
+	var err error
+	if brewery.FoundedYear, _, err = parseOptionalInt(args, "founded_year"); err != nil {
+		return nil, err
+	}
+	if brewery.Latitude, err = parseOptionalFloat(args, "latitude"); err != nil {
+		return nil, err
+	}
+	if brewery.Longitude, err = parseOptionalFloat(args, "longitude"); err != nil {
+		return nil, err
+	}
+
+	id, err := h.breweryService.CreateBrewery(ctx, brewery)
+	if err != nil {
+		return nil, writeError(err, "brewery")
+	}
+	return mcp.NewToolResult(fmt.Sprintf("Added brewery **%s** with ID %d.", brewery.Name, id)), nil
+}
+
+// AddBeer adds a beer to the catalog and returns its ID. The brewery is given by brewery_id or
+// brewery_name, as for brewery_beers.
+func (h *ToolHandlers) AddBeer(ctx context.Context, args map[string]interface{}) (*mcp.ToolResult, error) {
+	if err := h.checkAdminToken(args); err != nil {
+		return nil, err
+	}
+	breweryID, err := h.resolveBreweryArg(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	beer := services.Beer{BreweryID: breweryID}
+	beer.Name, _ = args["name"].(string)
+	beer.Style, _ = args["style"].(string)
+	beer.Description, _ = args["description"].(string)
+	for key, field := range map[string]*float64{"abv": &beer.ABV, "srm": &beer.SRM} {
+		value, parseErr := parseOptionalFloat(args, key)
+		if parseErr != nil {
+			return nil, parseErr
+		}
+		if value != nil {
+			*field = *value
+		}
+	}
+	if beer.IBU, _, err = parseOptionalInt(args, "ibu"); err != nil {
+		return nil, err
+	}
+
+	id, err := h.beerService.CreateBeer(ctx, beer)
+	if err != nil {
+		return nil, writeError(err, "beer")
+	}
+	return mcp.NewToolResult(fmt.Sprintf("Added beer **%s** with ID %d.", beer.Name, id)), nil
+}
+
+// DeleteBrewery soft-deletes a brewery, hiding it and its beers until RestoreBrewery is called.
+func (h *ToolHandlers) DeleteBrewery(ctx context.Context, args map[string]interface{}) (*mcp.ToolResult, error) {
+	if err := h.checkAdminToken(args); err != nil {
+		return nil, err
+	}
+	id, err := parseRecordID(args)
+	if err != nil {
+		return nil, err
+	}
+	if err = h.breweryService.SoftDeleteBrewery(ctx, id); err != nil {
+		return nil, breweryStateError(err, id, "delete", "deleted")
+	}
+	return mcp.NewToolResult(fmt.Sprintf(
+		"Deleted brewery %d. It and its beers are hidden until restored with restore_brewery.", id)), nil
+}
+
+// RestoreBrewery makes a soft-deleted brewery and its beers visible again.
+func (h *ToolHandlers) RestoreBrewery(ctx context.Context, args map[string]interface{}) (*mcp.ToolResult, error) {
+	if err := h.checkAdminToken(args); err != nil {
+		return nil, err
+	}
+	id, err := parseRecordID(args)
+	if err != nil {
+		return nil, err
+	}
+	if err = h.breweryService.RestoreBrewery(ctx, id); err != nil {
+		return nil, breweryStateError(err, id, "restore", "visible")
+	}
+	return mcp.NewToolResult(fmt.Sprintf("Restored brewery %d.", id)), nil
+}
+
+// SyncBreweries queues a brewery sync from Open Brewery DB as a job unless one is already queued or
+// running, reporting the job and the outcome of the last sync.
+func (h *ToolHandlers) SyncBreweries(ctx context.Context, args map[string]interface{}) (*mcp.ToolResult, error) {
+	if err := h.checkAdminToken(args); err != nil {
+		return nil, err
+	}
+	if h.brewerySyncer == nil {
+		return nil, &mcp.Error{Code: mcp.InvalidRequest, Message: "brewery sync is not available on this server"}
+	}
+	last, err := h.brewerySyncer.LastSync(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var sb strings.Builder
+	if job, active := h.jobs.ActiveJob(jobSyncBreweries); active {
+		fmt.Fprintf(&sb, "A brewery sync from Open Brewery DB is already %s as job **%s**.\n", job.State, job.ID)
+	} else {
+		syncer := h.brewerySyncer
+		job, err = h.startJob(ctx, jobSyncBreweries, func(ctx context.Context) (string, error) {
+			result, syncErr := syncer.Sync(ctx)
+			if syncErr != nil {
+				return "", syncErr
+			}
+			return result.String(), nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		sb.WriteString(jobStarted(ctx, job, "a brewery sync from Open Brewery DB") + "\n")
+	}
+
+	switch {
+	case last == nil:
+		sb.WriteString("\nNo earlier sync has run.")
+	case last.LastError != "":
+		fmt.Fprintf(&sb, "\nLast attempt (%s) failed: %s", last.LastAttemptAt.Format(time.RFC3339), last.LastError)
+		if last.LastSuccessAt != nil {
+			fmt.Fprintf(&sb, "\nLast success: %s, %d breweries written.",
+				last.LastSuccessAt.Format(time.RFC3339), last.Records)
+		}
+	case last.LastSuccessAt != nil:
+		fmt.Fprintf(&sb, "\nLast sync: %s, %d breweries written.",
+			last.LastSuccessAt.Format(time.RFC3339), last.Records)
+	}
+	return mcp.NewToolResult(sb.String()), nil
+}
+
+// ReloadData reloads the BJCP style guide, see ReloadBJCPData. A failed reload reports the error and
+// leaves the previous data in service.
+func (h *ToolHandlers) ReloadData(ctx context.Context, args map[string]interface{}) (*mcp.ToolResult, error) {
+	if err := h.checkAdminToken(args); err != nil {
+		return nil, err
+	}
+	if err := ReloadBJCPData(ctx, h.bjcpService, h.notifyResource); err != nil {
+		return nil, &mcp.Error{
+			Code: mcp.InternalError,
+			Message: fmt.Sprintf("failed to reload BJCP data, still serving version %s: %v",
+				h.bjcpService.GetMetadata().Version, err),
+		}
+	}
+	metadata := h.bjcpService.GetMetadata()
+	return mcp.NewToolResult(fmt.Sprintf("Reloaded the BJCP style guide: version %s, %d styles.",
+		metadata.Version, len(h.bjcpService.GetAllStyles()))), nil
+}
+
+// ReloadBJCPData reloads the style data of bjcpService, see data.BJCPService.Reload, and on success
+// calls notify, when set, for bjcp://styles so subscribed clients learn of the change. The outcome
+// is logged.
+func ReloadBJCPData(ctx context.Context, bjcpService *data.BJCPService, notify func(uri string) bool) error {
+	logger := requestid.Logger(ctx)
+	if err := bjcpService.Reload(); err != nil {
+		logger.WithError(err).Error("BJCP data reload failed; keeping the previous data")
+		return err
+	}
+	metadata := bjcpService.GetMetadata()
+	logger.WithFields(logrus.Fields{
+		"version": metadata.Version,
+		"styles":  len(bjcpService.GetAllStyles()),
+	}).Info("Reloaded BJCP data")
+	if notify != nil {
+		notify("bjcp://styles")
+	}
+	return nil
+}
+
+// breweryStateError reports a brewery that cannot be deleted or restored, because it does not
+// exist or is already in that state, as InvalidParams.
+func breweryStateError(err error, id int, action, state string) error {
+	if errors.Is(err, services.ErrNotFound) {
+		return &mcp.Error{
+			Code:    mcp.InvalidParams,
+			Message: fmt.Sprintf("brewery %d not found or already %s", id, state),
+			Data:    map[string]interface{}{"id": id},
+		}
+	}
+	return serviceError(err, "failed to "+action+" brewery")
+}
+
+// writeError turns validation failures, duplicates, and missing breweries into InvalidParams errors.
+func writeError(err error, kind string) error {
+	switch {
+	case errors.Is(err, services.ErrInvalidRecord), errors.Is(err, services.ErrDuplicate):
+		return &mcp.Error{Code: mcp.InvalidParams, Message: err.Error()}
+	case errors.Is(err, services.ErrNotFound):
+		return recordLookupError(err, "brewery")
+	}
+	return serviceError(err, "failed to add "+kind)
+}
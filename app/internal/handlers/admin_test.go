@@ -0,0 +1,328 @@
+package handlers_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/handlers"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/mcp"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/services"
+	"github.com/CharlRitter/brewsource-mcp/app/pkg/data"
+)
+
+const testAdminToken = "s3cret"
+
+func newAdminHandlers() *handlers.ToolHandlers {
+	toolHandlers := handlers.NewToolHandlers(nil, newBeerService(), newBreweryService())
+	toolHandlers.SetAdminToken(testAdminToken)
+	return toolHandlers
+}
+
+func expectMCPError(t *testing.T, err error, code int, wantContains string) {
+	t.Helper()
+	var mcpErr *mcp.Error
+	if !errors.As(err, &mcpErr) {
+		t.Fatalf("expected *mcp.Error, got %v", err)
+	}
+	if mcpErr.Code != code || !strings.Contains(mcpErr.Message, wantContains) {
+		t.Errorf("expected code %d containing %q, got %d %q", code, wantContains, mcpErr.Code, mcpErr.Message)
+	}
+}
+
+func TestAdminTools_TokenCheck(t *testing.T) {
+	ctx := context.Background()
+	args := map[string]interface{}{"admin_token": testAdminToken, "name": "New Brewery", "brewery_type": "micro"}
+
+	disabled := handlers.NewToolHandlers(nil, newBeerService(), newBreweryService())
+	_, err := disabled.AddBrewery(ctx, args)
+	expectMCPError(t, err, mcp.InvalidRequest, "admin tools are disabled")
+	_, err = disabled.AddBeer(ctx, map[string]interface{}{"admin_token": "", "brewery_id": 1, "name": "New Beer"})
+	expectMCPError(t, err, mcp.InvalidRequest, "admin tools are disabled")
+
+	toolHandlers := newAdminHandlers()
+	for _, token := range []interface{}{nil, "", "wrong", 42} {
+		_, err = toolHandlers.AddBrewery(ctx, map[string]interface{}{
+			"admin_token": token, "name": "New Brewery", "brewery_type": "micro",
+		})
+		expectMCPError(t, err, mcp.InvalidRequest, "invalid admin_token")
+	}
+}
+
+func TestAddBrewery(t *testing.T) {
+	ctx := context.Background()
+	toolHandlers := newAdminHandlers()
+
+	result, err := toolHandlers.AddBrewery(ctx, map[string]interface{}{
+		"admin_token": testAdminToken, "name": "New Brewery", "brewery_type": "brewpub",
+		"city": "Stellenbosch", "latitude": -33.93, "longitude": 18.86,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := result.Content[0].Text; got != "Added brewery **New Brewery** with ID 7." {
+		t.Errorf("Unexpected add_brewery output: %s", got)
+	}
+
+	tests := []struct {
+		name         string
+		args         map[string]interface{}
+		wantContains string
+	}{
+		{"duplicate name", map[string]interface{}{"name": "Test Brewery", "brewery_type": "micro"}, "already exists"},
+		{"unknown type", map[string]interface{}{"name": "New Brewery", "brewery_type": "winery"}, "must be one of"},
+		{"missing name", map[string]interface{}{"brewery_type": "micro"}, "name is required"},
+		{
+			"non-numeric latitude",
+			map[string]interface{}{"name": "New Brewery", "brewery_type": "micro", "latitude": "north"},
+			"latitude must be a number",
+		},
+		{
+			"non-numeric founded year",
+			map[string]interface{}{"name": "New Brewery", "brewery_type": "micro", "founded_year": "2012a"},
+			"founded_year must be an integer",
+		},
+		{
+			"invalid email",
+			map[string]interface{}{"name": "New Brewery", "brewery_type": "micro", "email": "not an address"},
+			"is not an email address",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.args["admin_token"] = testAdminToken
+			_, err := toolHandlers.AddBrewery(ctx, tt.args)
+			expectMCPError(t, err, mcp.InvalidParams, tt.wantContains)
+		})
+	}
+}
+
+func TestAddBeer(t *testing.T) {
+	ctx := context.Background()
+	toolHandlers := newAdminHandlers()
+
+	result, err := toolHandlers.AddBeer(ctx, map[string]interface{}{
+		"admin_token": testAdminToken, "brewery_name": "test brewery", "name": "New Beer",
+		"style": "Saison", "abv": 6.5, "ibu": float64(28),
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := result.Content[0].Text; got != "Added beer **New Beer** with ID 42." {
+		t.Errorf("Unexpected add_beer output: %s", got)
+	}
+
+	tests := []struct {
+		name         string
+		args         map[string]interface{}
+		wantContains string
+	}{
+		{"duplicate name", map[string]interface{}{"brewery_id": 1, "name": "Test Beer"}, "already has a beer"},
+		{"missing brewery", map[string]interface{}{"brewery_id": 99, "name": "New Beer"}, "brewery 99 not found"},
+		{"no brewery given", map[string]interface{}{"name": "New Beer"}, "brewery_id or brewery_name is required"},
+		{"ABV out of range", map[string]interface{}{"brewery_id": 1, "name": "New Beer", "abv": 25}, "abv must be"},
+		{"IBU out of range", map[string]interface{}{"brewery_id": 1, "name": "New Beer", "ibu": 500}, "ibu must be"},
+		{"non-integer IBU", map[string]interface{}{"brewery_id": 1, "name": "New Beer", "ibu": "lots"}, "ibu must be"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.args["admin_token"] = testAdminToken
+			_, err := toolHandlers.AddBeer(ctx, tt.args)
+			expectMCPError(t, err, mcp.InvalidParams, tt.wantContains)
+		})
+	}
+
+	t.Run("service errors", func(t *testing.T) {
+		errorHandlers := handlers.NewToolHandlers(nil, failingBeerService(nil), newBreweryService())
+		errorHandlers.SetAdminToken(testAdminToken)
+		_, err := errorHandlers.AddBeer(ctx, map[string]interface{}{
+			"admin_token": testAdminToken, "brewery_id": 1, "name": "New Beer",
+		})
+		var mcpErr *mcp.Error
+		if err == nil || errors.As(err, &mcpErr) {
+			t.Errorf("Expected a plain error for a database failure, got %v", err)
+		}
+	})
+}
+
+// mockBrewerySyncer reports a failed last sync, and counts syncs, each of which runs until release
+// is closed.
+type mockBrewerySyncer struct {
+	release chan struct{}
+	synced  atomic.Int32
+}
+
+func (m *mockBrewerySyncer) Sync(ctx context.Context) (*services.SyncResult, error) {
+	m.synced.Add(1)
+	services.ReportProgress(ctx, 1, 0, "1 pages: 0 inserted, 2 updated, 0 skipped")
+	<-m.release
+	return &services.SyncResult{Pages: 2, Updated: 3}, nil
+}
+
+func (m *mockBrewerySyncer) LastSync(_ context.Context) (*services.SyncState, error) {
+	succeeded := time.Date(2026, 1, 2, 3, 0, 0, 0, time.UTC)
+	return &services.SyncState{
+		Source:        services.OpenBreweryDBSource,
+		LastAttemptAt: succeeded.Add(24 * time.Hour),
+		LastSuccessAt: &succeeded,
+		Records:       8123,
+		LastError:     "api.openbrewerydb.org returned 503 Service Unavailable",
+	}, nil
+}
+
+func TestDeleteBrewery(t *testing.T) {
+	ctx := context.Background()
+	breweryService := newBreweryService()
+	toolHandlers := handlers.NewToolHandlers(nil, newBeerService(), breweryService)
+	toolHandlers.SetAdminToken(testAdminToken)
+	resourceHandlers := handlers.NewResourceHandlers(nil, nil, breweryService)
+	args := map[string]interface{}{"admin_token": testAdminToken, "id": 1}
+	visible := func() (bool, bool) {
+		t.Helper()
+		found, err := toolHandlers.FindBreweries(ctx, map[string]interface{}{"name": "Test Brewery"})
+		if err != nil {
+			t.Fatalf("Unexpected find_breweries error: %v", err)
+		}
+		directory, err := readResource(resourceHandlers, "breweries://directory")
+		if err != nil {
+			t.Fatalf("Unexpected directory error: %v", err)
+		}
+		return strings.Contains(found.Content[0].Text, "**1. Test Brewery**"), strings.Contains(directory.Text, "Test Brewery")
+	}
+
+	result, err := toolHandlers.DeleteBrewery(ctx, args)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := result.Content[0].Text; !strings.Contains(got, "Deleted brewery 1.") {
+		t.Errorf("Unexpected delete_brewery output: %s", got)
+	}
+	if inSearch, inDirectory := visible(); inSearch || inDirectory {
+		t.Errorf("Expected the deleted brewery to be hidden, got search %v and directory %v", inSearch, inDirectory)
+	}
+	_, err = toolHandlers.GetBrewery(ctx, map[string]interface{}{"id": 1})
+	expectMCPError(t, err, mcp.InvalidParams, "not found")
+	_, err = toolHandlers.DeleteBrewery(ctx, args)
+	expectMCPError(t, err, mcp.InvalidParams, "brewery 1 not found or already deleted")
+
+	if result, err = toolHandlers.RestoreBrewery(ctx, args); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := result.Content[0].Text; got != "Restored brewery 1." {
+		t.Errorf("Unexpected restore_brewery output: %s", got)
+	}
+	if inSearch, inDirectory := visible(); !inSearch || !inDirectory {
+		t.Errorf("Expected the restored brewery to be listed, got search %v and directory %v", inSearch, inDirectory)
+	}
+	_, err = toolHandlers.RestoreBrewery(ctx, args)
+	expectMCPError(t, err, mcp.InvalidParams, "brewery 1 not found or already visible")
+	_, err = toolHandlers.DeleteBrewery(ctx, map[string]interface{}{"admin_token": "wrong", "id": 1})
+	expectMCPError(t, err, mcp.InvalidRequest, "invalid admin_token")
+}
+
+func TestSyncBreweries(t *testing.T) {
+	ctx := context.Background()
+	args := map[string]interface{}{"admin_token": testAdminToken}
+
+	_, err := newAdminHandlers().SyncBreweries(ctx, args)
+	expectMCPError(t, err, mcp.InvalidRequest, "brewery sync is not available")
+
+	toolHandlers := newAdminHandlers()
+	syncer := &mockBrewerySyncer{release: make(chan struct{})}
+	toolHandlers.SetBrewerySyncer(syncer)
+	_, err = toolHandlers.SyncBreweries(ctx, map[string]interface{}{"admin_token": "wrong"})
+	expectMCPError(t, err, mcp.InvalidRequest, "invalid admin_token")
+
+	result, err := toolHandlers.SyncBreweries(ctx, args)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	text := result.Content[0].Text
+	for _, want := range []string{
+		"Queued a brewery sync from Open Brewery DB as job **",
+		"call get_job_status with the job ID",
+		"Last attempt (2026-01-03T03:00:00Z) failed: api.openbrewerydb.org returned 503",
+		"Last success: 2026-01-02T03:00:00Z, 8123 breweries written.",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected sync_breweries output to contain %q, got: %s", want, text)
+		}
+	}
+	jobID := jobIDPattern.FindStringSubmatch(text)[1]
+
+	// A second call reports the job already queued or running rather than queueing another
+	result, err = toolHandlers.SyncBreweries(ctx, args)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(result.Content[0].Text, "A brewery sync from Open Brewery DB is already") ||
+		!strings.Contains(result.Content[0].Text, jobID) {
+		t.Errorf("Expected the sync job %s reported, got: %s", jobID, result.Content[0].Text)
+	}
+
+	close(syncer.release)
+	job := waitForJobState(t, toolHandlers, jobID, services.JobDone)
+	if job.Result != "2 pages: 0 inserted, 3 updated, 0 skipped" || syncer.synced.Load() != 1 {
+		t.Errorf("Expected one sync done, got %+v after %d syncs", job, syncer.synced.Load())
+	}
+}
+
+func TestReloadData(t *testing.T) {
+	ctx := context.Background()
+	args := map[string]interface{}{"admin_token": testAdminToken}
+	bjcpService := data.NewBJCPServiceFromData(&data.BJCPData{
+		Styles:   map[string]data.BJCPStyle{"21A": {Code: "21A", Name: "American IPA"}},
+		Metadata: data.Metadata{Version: "2021"},
+	})
+	toolHandlers := newAdminHandlers()
+	toolHandlers.SetBJCPService(bjcpService)
+	resourceHandlers := handlers.NewResourceHandlers(nil, nil, nil)
+	resourceHandlers.SetBJCPService(bjcpService)
+	var notified []string
+	toolHandlers.SetResourceNotifier(func(uri string) bool {
+		notified = append(notified, uri)
+		return true
+	})
+
+	_, err := toolHandlers.ReloadData(ctx, map[string]interface{}{"admin_token": "wrong"})
+	expectMCPError(t, err, mcp.InvalidRequest, "invalid admin_token")
+
+	stylesFile := filepath.Join(t.TempDir(), "styles.json")
+	styles := `{"styles": {"99Z": {"code": "99Z", "name": "House Ale"}}, "metadata": {"version": "house-1"}}`
+	if err = os.WriteFile(stylesFile, []byte(styles), 0o600); err != nil {
+		t.Fatalf("Failed to write styles: %v", err)
+	}
+	t.Setenv(data.BJCPDataPathEnv, stylesFile)
+	result, err := toolHandlers.ReloadData(ctx, args)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if text := result.Content[0].Text; !strings.Contains(text, "version house-1, 1 styles") {
+		t.Errorf("Expected the reloaded version in the result, got: %s", text)
+	}
+	if len(notified) != 1 || notified[0] != "bjcp://styles" {
+		t.Errorf("Expected subscribers to bjcp://styles to be notified, got %v", notified)
+	}
+	// Handlers sharing the service serve the new data
+	if _, err = readResource(resourceHandlers, "bjcp://styles/99Z"); err != nil {
+		t.Errorf("Expected the resources to serve the reloaded style, got: %v", err)
+	}
+
+	if err = os.WriteFile(stylesFile, []byte(`{"styles": {"99Z": `), 0o600); err != nil {
+		t.Fatalf("Failed to write styles: %v", err)
+	}
+	_, err = toolHandlers.ReloadData(ctx, args)
+	expectMCPError(t, err, mcp.InternalError,
+		"still serving version house-1: BJCP 2021 guidelines: failed to parse BJCP data")
+	if len(notified) != 1 {
+		t.Errorf("Expected no notification for a failed reload, got %v", notified)
+	}
+	if _, err = bjcpService.GetStyleByCode("99Z"); err != nil {
+		t.Errorf("Expected the previous data to stay in service, got: %v", err)
+	}
+}
@@ -0,0 +1,283 @@
+package handlers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/mcp"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/requestid"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/services"
+	"github.com/CharlRitter/brewsource-mcp/app/pkg/data"
+)
+
+// APIResponse is the envelope of every /api/v1 response. Data is null and Error is set when the
+// request fails.
+type APIResponse struct {
+	Data  interface{} `json:"data"`
+	Meta  APIMeta     `json:"meta"`
+	Error *APIError   `json:"error"`
+}
+
+// APIMeta describes the data in an APIResponse.
+type APIMeta struct {
+	Count int `json:"count"`
+}
+
+// APIError describes a failed /api/v1 request. Field names the query or path parameter at fault,
+// when there is one.
+type APIError struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	Field   string `json:"field,omitempty"`
+}
+
+func badRequest(field, message string) *APIError {
+	return &APIError{Status: http.StatusBadRequest, Message: message, Field: field}
+}
+
+// SetServices gives the REST API the beer and brewery catalogs; the style guide comes from
+// SetBJCPData.
+func (w *WebHandlers) SetServices(
+	beerService services.BeerServiceInterface,
+	breweryService services.BreweryServiceInterface,
+) {
+	w.beerService = beerService
+	w.breweryService = breweryService
+}
+
+// RegisterAPIRoutes adds the /api/v1 REST endpoints, which mirror the bjcp_lookup, search_beers,
+// and find_breweries tools for clients that do not speak MCP, and their OpenAPI document and docs
+// page.
+func (w *WebHandlers) RegisterAPIRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/openapi.json", w.ServeOpenAPI)
+	mux.HandleFunc("GET /api/docs", w.ServeAPIDocs)
+	mux.HandleFunc("GET /api/v1/styles/{code}", w.ServeStyle)
+	mux.HandleFunc("GET /api/v1/styles", w.ServeStyles)
+	mux.HandleFunc("GET /api/v1/beers", w.ServeBeers)
+	mux.HandleFunc("GET /api/v1/breweries", w.ServeBreweries)
+}
+
+// ServeStyle handles GET /api/v1/styles/{code}, returning one BJCP style.
+func (w *WebHandlers) ServeStyle(writer http.ResponseWriter, r *http.Request) {
+	bjcp, apiErr := w.styleGuide()
+	if apiErr != nil {
+		writeAPIError(writer, apiErr)
+		return
+	}
+	code := data.NormalizeStyleCode(r.PathValue("code"))
+	if !data.IsStyleCode(code) {
+		writeAPIError(writer, badRequest("code", "invalid style code; expected a code such as 21A"))
+		return
+	}
+	style, err := bjcp.GetStyleByCode(code)
+	if err != nil {
+		writeAPIError(writer, &APIError{
+			Status:  http.StatusNotFound,
+			Message: fmt.Sprintf("BJCP style not found: %s", code),
+			Field:   "code",
+		})
+		return
+	}
+	writeAPIData(writer, r, bjcp.Fingerprint(), style, 1)
+}
+
+// ServeStyles handles GET /api/v1/styles, returning every BJCP style, or those in the category
+// given by ?category=, ordered by code.
+func (w *WebHandlers) ServeStyles(writer http.ResponseWriter, r *http.Request) {
+	bjcp, apiErr := w.styleGuide()
+	if apiErr != nil {
+		writeAPIError(writer, apiErr)
+		return
+	}
+	styles := []data.BJCPStyle{}
+	if category := strings.TrimSpace(r.URL.Query().Get("category")); category != "" {
+		styles = bjcp.GetStylesByCategory(category)
+		if len(styles) == 0 {
+			writeAPIError(writer, &APIError{
+				Status:  http.StatusNotFound,
+				Message: fmt.Sprintf("BJCP category not found: %s", category),
+				Field:   "category",
+			})
+			return
+		}
+	} else {
+		for _, style := range bjcp.GetAllStyles() {
+			styles = append(styles, style)
+		}
+	}
+	slices.SortFunc(styles, func(a, b data.BJCPStyle) int { return data.CompareStyleCodes(a.Code, b.Code) })
+	writeAPIData(writer, r, bjcp.Fingerprint(), styles, len(styles))
+}
+
+// ServeBeers handles GET /api/v1/beers?name=&style=&limit=, searching beers like the search_beers
+// tool. At least one of name and style is required.
+func (w *WebHandlers) ServeBeers(writer http.ResponseWriter, r *http.Request) {
+	if w.beerService == nil {
+		writeAPIError(writer, unavailable("beer catalog"))
+		return
+	}
+	params := r.URL.Query()
+	query := services.BeerSearchQuery{
+		Name:  strings.TrimSpace(params.Get("name")),
+		Style: strings.TrimSpace(params.Get("style")),
+	}
+	if query.Name == "" && query.Style == "" {
+		writeAPIError(writer, badRequest("name", "at least one of name or style is required"))
+		return
+	}
+	limit, apiErr := apiLimit(params)
+	if apiErr != nil {
+		writeAPIError(writer, apiErr)
+		return
+	}
+	query.Limit = limit
+
+	beers, err := w.beerService.SearchBeers(r.Context(), query)
+	if err != nil {
+		writeAPIError(writer, apiServiceError(r.Context(), err, "failed to search beers"))
+		return
+	}
+	if beers == nil {
+		beers = []*services.BeerSearchResult{}
+	}
+	writeAPIData(writer, r, "", beers, len(beers))
+}
+
+// ServeBreweries handles GET /api/v1/breweries?city=&country=&limit=, searching breweries like the
+// find_breweries tool. At least one of city and country is required; each may be repeated to match
+// any of several.
+func (w *WebHandlers) ServeBreweries(writer http.ResponseWriter, r *http.Request) {
+	if w.breweryService == nil {
+		writeAPIError(writer, unavailable("brewery catalog"))
+		return
+	}
+	params := r.URL.Query()
+	query := services.BrewerySearchQuery{City: params["city"], Country: params["country"]}
+	if !hasText(query.City...) && !hasText(query.Country...) {
+		writeAPIError(writer, badRequest("city", "at least one of city or country is required"))
+		return
+	}
+	limit, apiErr := apiLimit(params)
+	if apiErr != nil {
+		writeAPIError(writer, apiErr)
+		return
+	}
+	query.Limit = limit
+
+	breweries, err := w.breweryService.SearchBreweries(r.Context(), query)
+	if err != nil {
+		writeAPIError(writer, apiServiceError(r.Context(), err, "failed to search breweries"))
+		return
+	}
+	if breweries == nil {
+		breweries = []*services.BrewerySearchResult{}
+	}
+	writeAPIData(writer, r, "", breweries, len(breweries))
+}
+
+func (w *WebHandlers) styleGuide() (*data.BJCPService, *APIError) {
+	bjcpService := w.bjcpService.Load()
+	if bjcpService == nil {
+		return nil, unavailable("BJCP style guide")
+	}
+	return bjcpService, nil
+}
+
+// apiLimit reads ?limit= with the same default and range as the MCP search tools.
+func apiLimit(params url.Values) (int, *APIError) {
+	value := params.Get("limit")
+	if value == "" {
+		return defaultSearchLimit, nil
+	}
+	limit, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, badRequest("limit", "limit must be an integer")
+	}
+	if limit <= 0 || limit > maxSearchLimit {
+		return 0, badRequest("limit", fmt.Sprintf("limit must be between 1 and %d", maxSearchLimit))
+	}
+	return limit, nil
+}
+
+func unavailable(what string) *APIError {
+	return &APIError{Status: http.StatusServiceUnavailable, Message: what + " is not available"}
+}
+
+// apiServiceError maps a service error to a response: invalid queries are 400s, lookups that
+// matched nothing 404s, and timeouts 504s. Other errors are logged with the request ID carried by
+// ctx and returned as 500s without their detail.
+func apiServiceError(ctx context.Context, err error, action string) *APIError {
+	switch {
+	case errors.Is(err, services.ErrInvalidLimit):
+		return badRequest("limit", err.Error())
+	case errors.Is(err, services.ErrInvalidSearchQuery):
+		return badRequest("", err.Error())
+	case errors.Is(err, services.ErrNotFound):
+		return &APIError{Status: http.StatusNotFound, Message: err.Error()}
+	case errors.Is(err, services.ErrQueryTimeout), errors.Is(err, context.DeadlineExceeded):
+		return &APIError{
+			Status:  http.StatusGatewayTimeout,
+			Message: "the catalog query took too long; try narrower filters or a smaller limit",
+		}
+	default:
+		requestid.Logger(ctx).WithError(err).Error(action)
+		return &APIError{Status: http.StatusInternalServerError, Message: action}
+	}
+}
+
+// writeAPIData writes payload with an ETag: etag, for data versioned such as by the style guide's
+// fingerprint, or else a hash of the response. A request whose If-None-Match holds the ETag gets a
+// 304 Not Modified without a body instead.
+func writeAPIData(writer http.ResponseWriter, r *http.Request, etag string, payload interface{}, count int) {
+	ifNoneMatch := r.Header.Get("If-None-Match")
+	if etag != "" && mcp.ETagMatches(ifNoneMatch, etag) {
+		writeNotModified(writer, etag)
+		return
+	}
+	jsonBytes, err := json.Marshal(APIResponse{Data: payload, Meta: APIMeta{Count: count}})
+	if err != nil {
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if etag == "" {
+		sum := sha256.Sum256(jsonBytes)
+		etag = hex.EncodeToString(sum[:16])
+		if mcp.ETagMatches(ifNoneMatch, etag) {
+			writeNotModified(writer, etag)
+			return
+		}
+	}
+	writer.Header().Set("ETag", `"`+etag+`"`)
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(http.StatusOK)
+	_, _ = writer.Write(jsonBytes)
+}
+
+func writeNotModified(writer http.ResponseWriter, etag string) {
+	writer.Header().Set("ETag", `"`+etag+`"`)
+	writer.WriteHeader(http.StatusNotModified)
+}
+
+func writeAPIError(writer http.ResponseWriter, apiErr *APIError) {
+	writeAPIResponse(writer, apiErr.Status, APIResponse{Error: apiErr})
+}
+
+func writeAPIResponse(writer http.ResponseWriter, status int, response APIResponse) {
+	jsonBytes, err := json.Marshal(response)
+	if err != nil {
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(status)
+	_, _ = writer.Write(jsonBytes)
+}
@@ -0,0 +1,229 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	handlers "github.com/CharlRitter/brewsource-mcp/app/internal/handlers"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/services"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/services/servicestest"
+	"github.com/CharlRitter/brewsource-mcp/app/pkg/data"
+)
+
+type apiResponse struct {
+	Data  json.RawMessage    `json:"data"`
+	Meta  handlers.APIMeta   `json:"meta"`
+	Error *handlers.APIError `json:"error"`
+}
+
+func testAPIBJCPData() *data.BJCPData {
+	return &data.BJCPData{
+		Styles: map[string]data.BJCPStyle{
+			"21A": {Code: "21A", Name: "American IPA", Category: "IPA"},
+			"21B": {Code: "21B", Name: "Specialty IPA", Category: "IPA"},
+			"2A":  {Code: "2A", Name: "International Pale Lager", Category: "International Lager"},
+		},
+		Categories: []string{"International Lager", "IPA"},
+	}
+}
+
+// newAPIMux serves the REST API from the fake services.
+func newAPIMux(beerService *servicestest.BeerService, breweryService *servicestest.BreweryService) *http.ServeMux {
+	webHandlers := handlers.NewWebHandlers(nil, nil)
+	webHandlers.SetBJCPData(testAPIBJCPData())
+	webHandlers.SetServices(beerService, breweryService)
+	mux := http.NewServeMux()
+	webHandlers.RegisterAPIRoutes(mux)
+	return mux
+}
+
+func getAPI(t *testing.T, mux *http.ServeMux, target string) (int, apiResponse) {
+	t.Helper()
+	recorder := httptest.NewRecorder()
+	mux.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, target, nil))
+
+	if contentType := recorder.Header().Get("Content-Type"); contentType != "application/json" {
+		t.Errorf("Expected Content-Type application/json, got %q", contentType)
+	}
+	var response apiResponse
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Invalid API response %q: %v", recorder.Body.String(), err)
+	}
+	return recorder.Code, response
+}
+
+func TestAPIRoutes(t *testing.T) {
+	mux := newAPIMux(newBeerService(), newBreweryService())
+
+	tests := []struct {
+		name          string
+		target        string
+		expectedCode  int
+		expectedCount int
+		expectedField string
+		expectedData  string
+	}{
+		{"style by code", "/api/v1/styles/21a", http.StatusOK, 1, "", `"American IPA"`},
+		{"invalid style code", "/api/v1/styles/IPA", http.StatusBadRequest, 0, "code", ""},
+		{"unknown style code", "/api/v1/styles/99Z", http.StatusNotFound, 0, "code", ""},
+		{"all styles", "/api/v1/styles", http.StatusOK, 3, "", `"2A"`},
+		{"styles in a category", "/api/v1/styles?category=ipa", http.StatusOK, 2, "", `"Specialty IPA"`},
+		{"unknown category", "/api/v1/styles?category=Mead", http.StatusNotFound, 0, "category", ""},
+		{"beers", "/api/v1/beers?name=test&limit=5", http.StatusOK, 1, "", `"Test Beer"`},
+		{"beers without a filter", "/api/v1/beers?limit=5", http.StatusBadRequest, 0, "name", ""},
+		{"beers limit not a number", "/api/v1/beers?style=ipa&limit=ten", http.StatusBadRequest, 0, "limit", ""},
+		{"beers limit too large", "/api/v1/beers?style=ipa&limit=101", http.StatusBadRequest, 0, "limit", ""},
+		{"breweries", "/api/v1/breweries?city=Test%20City", http.StatusOK, 1, "", `"Test Brewery"`},
+		{"breweries without a filter", "/api/v1/breweries?limit=5", http.StatusBadRequest, 0, "city", ""},
+		{"breweries limit zero", "/api/v1/breweries?country=ZA&limit=0", http.StatusBadRequest, 0, "limit", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, response := getAPI(t, mux, tt.target)
+			if code != tt.expectedCode {
+				t.Errorf("Expected status code %d, got %d", tt.expectedCode, code)
+			}
+			if response.Meta.Count != tt.expectedCount {
+				t.Errorf("Expected meta.count %d, got %d", tt.expectedCount, response.Meta.Count)
+			}
+			if tt.expectedCode != http.StatusOK {
+				if response.Error == nil {
+					t.Fatal("Expected an error in the response")
+				}
+				if response.Error.Field != tt.expectedField {
+					t.Errorf("Expected error field %q, got %q", tt.expectedField, response.Error.Field)
+				}
+				if string(response.Data) != "null" {
+					t.Errorf("Expected null data with an error, got %s", response.Data)
+				}
+				return
+			}
+			if response.Error != nil {
+				t.Errorf("Unexpected error: %+v", response.Error)
+			}
+			if !json.Valid(response.Data) || !strings.Contains(string(response.Data), tt.expectedData) {
+				t.Errorf("Expected data containing %s, got %s", tt.expectedData, response.Data)
+			}
+		})
+	}
+}
+
+// Test that styles are listed by category number, so that 2A comes before 21A.
+func TestAPIStylesOrder(t *testing.T) {
+	_, response := getAPI(t, newAPIMux(newBeerService(), newBreweryService()), "/api/v1/styles")
+	var styles []data.BJCPStyle
+	if err := json.Unmarshal(response.Data, &styles); err != nil {
+		t.Fatalf("Invalid styles: %v", err)
+	}
+	var codes []string
+	for _, style := range styles {
+		codes = append(codes, style.Code)
+	}
+	if fmt.Sprint(codes) != "[2A 21A 21B]" {
+		t.Errorf("Expected styles ordered 2A, 21A, 21B, got %v", codes)
+	}
+}
+
+// Test that the search routes pass the filters and limit through to the services.
+func TestAPISearchQueries(t *testing.T) {
+	beerService, breweryService := newBeerService(), newBreweryService()
+	mux := newAPIMux(beerService, breweryService)
+
+	getAPI(t, mux, "/api/v1/beers?name=hazy&style=IPA")
+	getAPI(t, mux, "/api/v1/breweries?city=Cape%20Town&city=Durban&country=ZA&limit=7")
+
+	if len(beerService.Queries) != 1 || len(breweryService.Queries) != 1 {
+		t.Fatalf("Expected one search of each, got %d and %d", len(beerService.Queries), len(breweryService.Queries))
+	}
+	beerQuery := beerService.Queries[0]
+	if beerQuery.Name != "hazy" || beerQuery.Style != "IPA" || beerQuery.Limit != services.DefaultSearchLimit {
+		t.Errorf("Unexpected beer query: %+v", beerQuery)
+	}
+	breweryQuery := breweryService.Queries[0]
+	if fmt.Sprint(breweryQuery.City, breweryQuery.Country) != "[Cape Town Durban] [ZA]" || breweryQuery.Limit != 7 {
+		t.Errorf("Unexpected brewery query: %+v", breweryQuery)
+	}
+}
+
+// Test the ETags of the API responses and the 304s answering a matching If-None-Match.
+func TestAPIETags(t *testing.T) {
+	mux := newAPIMux(newBeerService(), newBreweryService())
+	get := func(target, ifNoneMatch string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodGet, target, nil)
+		if ifNoneMatch != "" {
+			request.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		recorder := httptest.NewRecorder()
+		mux.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	styleTag := `"` + data.NewBJCPServiceFromData(testAPIBJCPData()).Fingerprint() + `"`
+	for _, target := range []string{"/api/v1/styles", "/api/v1/styles/21A", "/api/v1/beers?name=ipa"} {
+		first := get(target, "")
+		etag := first.Header().Get("ETag")
+		if first.Code != http.StatusOK || !strings.HasPrefix(etag, `"`) {
+			t.Fatalf("%s: expected a 200 with a quoted ETag, got %d with %q", target, first.Code, etag)
+		}
+		if strings.HasPrefix(target, "/api/v1/styles") && etag != styleTag {
+			t.Errorf("%s: expected the style guide's fingerprint %s, got %s", target, styleTag, etag)
+		}
+
+		cached := get(target, `"stale", `+etag)
+		if cached.Code != http.StatusNotModified || cached.Body.Len() != 0 || cached.Header().Get("ETag") != etag {
+			t.Errorf("%s: expected an empty 304 with ETag %s, got %d with %q", target, etag, cached.Code, cached.Body)
+		}
+		if stale := get(target, `"stale"`); stale.Code != http.StatusOK {
+			t.Errorf("%s: expected a 200 for a stale ETag, got %d", target, stale.Code)
+		}
+	}
+}
+
+func TestAPIServiceErrors(t *testing.T) {
+	tests := []struct {
+		name         string
+		err          error
+		expectedCode int
+	}{
+		{
+			"invalid query",
+			fmt.Errorf("%w: abv_min exceeds abv_max", services.ErrInvalidSearchQuery),
+			http.StatusBadRequest,
+		},
+		{"query timeout", services.ErrQueryTimeout, http.StatusGatewayTimeout},
+		{"database failure", errors.New("connection refused"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := newAPIMux(failingBeerService(tt.err), &servicestest.BreweryService{Err: tt.err})
+			for _, target := range []string{"/api/v1/beers?name=ipa", "/api/v1/breweries?country=ZA"} {
+				code, response := getAPI(t, mux, target)
+				if code != tt.expectedCode {
+					t.Errorf("%s: expected status code %d, got %d", target, tt.expectedCode, code)
+				}
+				if response.Error == nil || response.Error.Status != tt.expectedCode {
+					t.Errorf("%s: expected an error with status %d, got %+v", target, tt.expectedCode, response.Error)
+				}
+			}
+		})
+	}
+}
+
+// Test the routes before the catalogs are set.
+func TestAPIUnavailable(t *testing.T) {
+	mux := http.NewServeMux()
+	handlers.NewWebHandlers(nil, nil).RegisterAPIRoutes(mux)
+
+	for _, target := range []string{"/api/v1/styles/21A", "/api/v1/styles", "/api/v1/beers?name=ipa"} {
+		if code, _ := getAPI(t, mux, target); code != http.StatusServiceUnavailable {
+			t.Errorf("%s: expected status code %d, got %d", target, http.StatusServiceUnavailable, code)
+		}
+	}
+}
@@ -0,0 +1,477 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/mcp"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/units"
+	"github.com/CharlRitter/brewsource-mcp/app/pkg/brewing"
+)
+
+const (
+	// defaultBrewhouseEfficiency is the efficiency brew_day_sheet assumes when none is given, in
+	// percent, typical of all-grain batch sparging.
+	defaultBrewhouseEfficiency = 72.0
+	// defaultGrainTempF and defaultMashTempF are the mash brew_day_sheet plans when none is given:
+	// room-temperature grain and a middle-of-the-range saccharification rest.
+	defaultGrainTempF = 68.0
+	defaultMashTempF  = 152.0
+)
+
+// brewDaySheet is the JSON content of brew_day_sheet. Each section carries its results or, when its
+// calculation failed, the error; a failed section does not fail the others unless they depend on it.
+// Values are in the sheet's units, except gravities, colour, and bitterness, which have none.
+type brewDaySheet struct {
+	BatchSize  float64           `json:"batch_size"`
+	Units      string            `json:"units"`
+	Gravity    brewDayGravity    `json:"gravity"`
+	Bitterness brewDayBitterness `json:"bitterness"`
+	Color      brewDayColor      `json:"color"`
+	Mash       brewDayMash       `json:"mash"`
+	Yeast      brewDayYeast      `json:"yeast"`
+	Notes      []string          `json:"notes,omitempty"`
+}
+
+// brewDayGravity is the original gravity the grain bill is predicted to reach.
+type brewDayGravity struct {
+	Efficiency      float64 `json:"efficiency"` // percent
+	OriginalGravity float64 `json:"original_gravity,omitempty"`
+	Plato           float64 `json:"plato,omitempty"`
+	Error           string  `json:"error,omitempty"`
+}
+
+// brewDayBitterness is the Tinseth estimate of the hop schedule's bitterness.
+type brewDayBitterness struct {
+	Hops  []brewDayHop `json:"hops,omitempty"`
+	IBU   float64      `json:"ibu,omitempty"`
+	Error string       `json:"error,omitempty"`
+}
+
+// brewDayHop is one hop addition and the bitterness it contributes.
+type brewDayHop struct {
+	Name      string  `json:"name"`
+	AlphaAcid float64 `json:"alpha_acid"`
+	Form      string  `json:"form"`
+	Weight    float64 `json:"weight"`
+	Timing    string  `json:"timing"`
+	IBU       float64 `json:"ibu"`
+}
+
+// brewDayColor is the Morey estimate of the beer's colour.
+type brewDayColor struct {
+	SRM   float64 `json:"srm,omitempty"`
+	EBC   float64 `json:"ebc,omitempty"`
+	Error string  `json:"error,omitempty"`
+}
+
+// brewDayMash is the strike water for a single-infusion mash of the grain bill.
+type brewDayMash struct {
+	GrainWeight     float64 `json:"grain_weight,omitempty"`
+	GrainTemp       float64 `json:"grain_temp,omitempty"`
+	MashTemp        float64 `json:"mash_temp,omitempty"`
+	StrikeTemp      float64 `json:"strike_temp,omitempty"`
+	StrikeVolume    float64 `json:"strike_volume,omitempty"`
+	GrainAbsorption float64 `json:"grain_absorption,omitempty"`
+	Error           string  `json:"error,omitempty"`
+}
+
+// brewDayYeast is the number of cells to pitch for the batch.
+type brewDayYeast struct {
+	Name        string  `json:"name,omitempty"`
+	Type        string  `json:"type,omitempty"` // ale, hybrid, or lager pitch rate
+	PitchRate   float64 `json:"pitch_rate,omitempty"`
+	TargetCells float64 `json:"target_cells,omitempty"` // billions
+	Error       string  `json:"error,omitempty"`
+}
+
+// sheetFermentable is one entry of brew_day_sheet's grain bill, as given.
+type sheetFermentable struct {
+	name     string
+	weight   float64
+	lovibond *float64
+	ppg      *float64
+}
+
+// BrewDaySheet runs a minimal recipe through the calculators in turn, predicting the original
+// gravity, bitterness, colour, strike water, and yeast pitch, and formats them as one sheet. A
+// section whose inputs are missing or out of range reports why in its place, as do the bitterness
+// and yeast sections when there is no original gravity to work from, and the rest of the sheet is
+// still calculated. Only an invalid batch size or unit system fails the tool.
+func (h *ToolHandlers) BrewDaySheet(ctx context.Context, args map[string]interface{}) (*mcp.ToolResult, error) {
+	system, err := parseUnitSystem(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	batchSize, err := requireFloat(args, "batch_size")
+	if err != nil {
+		return nil, err
+	}
+	if batchSize <= 0 {
+		return nil, &mcp.Error{Code: mcp.InvalidParams, Message: "batch_size must be greater than zero"}
+	}
+
+	sheet := &brewDaySheet{BatchSize: batchSize, Units: system}
+	fermentables, fermentablesErr := parseSheetFermentables(args)
+	sheet.Gravity = h.sheetGravity(sheet, args, fermentables, fermentablesErr)
+	sheet.Bitterness = h.sheetBitterness(sheet, args)
+	sheet.Color = h.sheetColor(sheet, fermentables, fermentablesErr)
+	sheet.Mash = h.sheetMash(sheet, args, fermentables, fermentablesErr)
+	sheet.Yeast = h.sheetYeast(sheet, args)
+
+	sheetJSON, err := json.Marshal(sheet)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal brew day sheet: %w", err)
+	}
+	return &mcp.ToolResult{
+		Content: []mcp.ToolContent{
+			{Type: "text", Text: formatBrewDaySheet(sheet)},
+			{Type: "text", Text: string(sheetJSON)},
+		},
+	}, nil
+}
+
+// parseSheetFermentables extracts the 'fermentables' array, leaving the colour and yield of each to
+// the sections that need them.
+func parseSheetFermentables(args map[string]interface{}) ([]sheetFermentable, error) {
+	items, ok := args["fermentables"].([]interface{})
+	if !ok || len(items) == 0 {
+		return nil, &mcp.Error{
+			Code:    mcp.InvalidParams,
+			Message: "'fermentables' parameter must be a non-empty array of {name, weight} objects",
+		}
+	}
+	fermentables := make([]sheetFermentable, 0, len(items))
+	for i, item := range items {
+		itemArgs, isObject := item.(map[string]interface{})
+		if !isObject {
+			return nil, &mcp.Error{
+				Code:    mcp.InvalidParams,
+				Message: fmt.Sprintf("fermentables[%d] must be an object", i),
+			}
+		}
+		fermentable := sheetFermentable{}
+		fermentable.name, _ = itemArgs["name"].(string)
+		fermentable.name = strings.TrimSpace(fermentable.name)
+		var err error
+		if fermentable.weight, err = requireFloat(itemArgs, "weight"); err == nil {
+			if fermentable.lovibond, err = parseOptionalFloat(itemArgs, "lovibond"); err == nil {
+				fermentable.ppg, err = parseOptionalFloat(itemArgs, "ppg")
+			}
+		}
+		if err != nil {
+			return nil, &mcp.Error{
+				Code:    mcp.InvalidParams,
+				Message: fmt.Sprintf("fermentables[%d]: %s", i, err.Error()),
+			}
+		}
+		fermentables = append(fermentables, fermentable)
+	}
+	return fermentables, nil
+}
+
+// displayName names fermentables[i] for the sheet.
+func (f sheetFermentable) displayName(i int) string {
+	if f.name == "" {
+		return fmt.Sprintf("Fermentable %d", i+1)
+	}
+	return f.name
+}
+
+// sheetGravity predicts the original gravity from the grain bill's yield at the given efficiency.
+func (h *ToolHandlers) sheetGravity(
+	sheet *brewDaySheet, args map[string]interface{}, fermentables []sheetFermentable, fermentablesErr error,
+) brewDayGravity {
+	section := brewDayGravity{Efficiency: defaultBrewhouseEfficiency}
+	efficiency, err := parseOptionalFloat(args, "efficiency")
+	if err != nil {
+		section.Error = err.Error()
+		return section
+	}
+	if efficiency != nil {
+		section.Efficiency = *efficiency
+	}
+	if fermentablesErr != nil {
+		section.Error = fermentablesErr.Error()
+		return section
+	}
+
+	calc := brewing.EfficiencyCalculation{BatchSize: sheet.BatchSize, Units: brewing.UnitSystem(sheet.Units)}
+	for i, fermentable := range fermentables {
+		grain := brewing.EfficiencyGrain{Weight: fermentable.weight}
+		if fermentable.ppg != nil {
+			grain.PPG = *fermentable.ppg
+		} else {
+			known, lookupErr := h.findFermentable(fermentable.name, i, "ppg")
+			if lookupErr != nil {
+				section.Error = lookupErr.Error()
+				return section
+			}
+			grain.PPG = known.PPG
+			sheet.Notes = append(sheet.Notes, fmt.Sprintf("%s: yield not given; used %s PPG for %s.",
+				fermentable.name, formatNumber(known.PPG), known.Name))
+		}
+		calc.Grains = append(calc.Grains, grain)
+	}
+	og, err := calc.OriginalGravity(section.Efficiency)
+	if err != nil {
+		section.Error = err.Error()
+		return section
+	}
+	section.OriginalGravity = og
+	section.Plato = brewing.GravityToPlato(og)
+	return section
+}
+
+// sheetBitterness estimates the hop schedule's bitterness at the predicted original gravity.
+func (h *ToolHandlers) sheetBitterness(sheet *brewDaySheet, args map[string]interface{}) brewDayBitterness {
+	section := brewDayBitterness{}
+	hops, notes, err := h.parseHopAdditions(args)
+	if err != nil {
+		section.Error = err.Error()
+		return section
+	}
+	sheet.Notes = append(sheet.Notes, notes...)
+	if sheet.Gravity.OriginalGravity == 0 {
+		section.Error = "needs the original gravity, which could not be predicted"
+		return section
+	}
+
+	for i, hop := range hops {
+		hop.Units = brewing.UnitSystem(sheet.Units)
+		ibu, calcErr := hop.CalculateIBU(sheet.BatchSize, sheet.Gravity.OriginalGravity)
+		if calcErr != nil {
+			section.Error = calcErr.Error()
+			section.Hops, section.IBU = nil, 0
+			return section
+		}
+		name := hop.Name
+		if name == "" {
+			name = fmt.Sprintf("Hop %d", i+1)
+		}
+		section.Hops = append(section.Hops, brewDayHop{
+			Name:      name,
+			AlphaAcid: hop.AlphaAcid,
+			Form:      string(hop.Form),
+			Weight:    hop.Weight,
+			Timing:    formatHopTiming(hop, sheet.Units),
+			IBU:       ibu,
+		})
+		section.IBU += ibu
+	}
+	return section
+}
+
+// sheetColor estimates the colour of the grain bill.
+func (h *ToolHandlers) sheetColor(
+	sheet *brewDaySheet, fermentables []sheetFermentable, fermentablesErr error,
+) brewDayColor {
+	section := brewDayColor{}
+	if fermentablesErr != nil {
+		section.Error = fermentablesErr.Error()
+		return section
+	}
+
+	calc := brewing.ColorCalculation{BatchSize: sheet.BatchSize, Units: brewing.UnitSystem(sheet.Units)}
+	for i, fermentable := range fermentables {
+		grain := brewing.ColorGrain{Weight: fermentable.weight}
+		if fermentable.lovibond != nil {
+			grain.Lovibond = *fermentable.lovibond
+		} else {
+			known, lookupErr := h.findFermentable(fermentable.name, i, "lovibond")
+			if lookupErr != nil {
+				section.Error = lookupErr.Error()
+				return section
+			}
+			grain.Lovibond = known.Lovibond
+			sheet.Notes = append(sheet.Notes, fmt.Sprintf("%s: colour not given; used %s °L for %s.",
+				fermentable.name, formatNumber(known.Lovibond), known.Name))
+		}
+		calc.Grains = append(calc.Grains, grain)
+	}
+	result, err := calc.Calculate()
+	if err != nil {
+		section.Error = err.Error()
+		return section
+	}
+	section.SRM, section.EBC = result.SRM, result.EBC
+	return section
+}
+
+// sheetMash plans the strike water for the fermentables that are mashed: all but the sugars and
+// extracts the ingredient data knows, which go into the kettle.
+func (h *ToolHandlers) sheetMash(
+	sheet *brewDaySheet, args map[string]interface{}, fermentables []sheetFermentable, fermentablesErr error,
+) brewDayMash {
+	section := brewDayMash{}
+	if fermentablesErr != nil {
+		section.Error = fermentablesErr.Error()
+		return section
+	}
+
+	metric := sheet.Units == unitsMetric
+	mashArgs := map[string]interface{}{"grain_temp": defaultGrainTempF, "target_temp": defaultMashTempF}
+	if metric {
+		mashArgs["grain_temp"] = brewing.FahrenheitToCelsius(defaultGrainTempF)
+		mashArgs["target_temp"] = brewing.FahrenheitToCelsius(defaultMashTempF)
+	}
+	for key, mashKey := range map[string]string{
+		"grain_temp": "grain_temp", "mash_temp": "target_temp", "water_to_grist_ratio": "water_to_grist_ratio",
+	} {
+		if value, given := args[key]; given {
+			mashArgs[mashKey] = value
+		}
+	}
+	grainWeight := 0.0
+	for i, fermentable := range fermentables {
+		if h.ingredientService != nil && fermentable.name != "" {
+			if known, err := h.ingredientService.FindFermentable(fermentable.name); err == nil &&
+				(known.Type == "sugar" || known.Type == "extract") {
+				sheet.Notes = append(sheet.Notes, fmt.Sprintf("%s: %s, so added to the kettle rather than mashed.",
+					fermentable.displayName(i), known.Type))
+				continue
+			}
+		}
+		grainWeight += fermentable.weight
+	}
+	mashArgs["grain_weight"] = grainWeight
+
+	calc, err := parseStrikeWaterCalculation(mashArgs, sheet.Units)
+	if err != nil {
+		section.Error = err.Error()
+		return section
+	}
+	result, err := calc.Calculate()
+	if err != nil {
+		section.Error = err.Error()
+		return section
+	}
+	section.GrainWeight = grainWeight
+	section.GrainTemp, section.MashTemp, section.StrikeTemp = calc.GrainTemp, calc.TargetMashTemp, result.StrikeTemp
+	section.StrikeVolume, section.GrainAbsorption = result.StrikeVolume, result.GrainAbsorption
+	if metric {
+		section.GrainTemp = brewing.FahrenheitToCelsius(section.GrainTemp)
+		section.MashTemp = brewing.FahrenheitToCelsius(section.MashTemp)
+		section.StrikeTemp = brewing.FahrenheitToCelsius(section.StrikeTemp)
+		section.StrikeVolume *= brewing.LitersPerGallon
+		section.GrainAbsorption *= brewing.LitersPerGallon
+	}
+	return section
+}
+
+// sheetYeast calculates the cells to pitch at the predicted original gravity. The pitch rate follows
+// the yeast's type, given or else that of the named strain in the ingredient data, defaulting to ale.
+func (h *ToolHandlers) sheetYeast(sheet *brewDaySheet, args map[string]interface{}) brewDayYeast {
+	section := brewDayYeast{}
+	var yeastType string
+	switch yeast := args["yeast"].(type) {
+	case string:
+		section.Name = strings.TrimSpace(yeast)
+	case map[string]interface{}:
+		section.Name, _ = yeast["name"].(string)
+		section.Name = strings.TrimSpace(section.Name)
+		yeastType, _ = yeast["type"].(string)
+	case nil:
+	default:
+		section.Error = "yeast must be a strain name or a {name, type} object"
+		return section
+	}
+
+	section.Type = strings.ToLower(strings.TrimSpace(yeastType))
+	if section.Type == "" {
+		section.Type = "ale"
+		if section.Name != "" && h.ingredientService != nil {
+			if strain, err := h.ingredientService.FindYeast(section.Name); err == nil {
+				section.Name = strain.Label()
+				if strain.Type == "lager" {
+					section.Type = "lager"
+				}
+			}
+		}
+	}
+	switch section.Type {
+	case "ale":
+		section.PitchRate = brewing.PitchRateAle
+	case "hybrid":
+		section.PitchRate = brewing.PitchRateHybrid
+	case "lager":
+		section.PitchRate = brewing.PitchRateLager
+	default:
+		section.Error = "yeast type must be 'ale', 'hybrid', or 'lager'"
+		return section
+	}
+	if sheet.Gravity.OriginalGravity == 0 {
+		section.Error = "needs the original gravity, which could not be predicted"
+		return section
+	}
+
+	cells, err := brewing.YeastPitchingRate{
+		BatchVolume:     sheet.BatchSize,
+		OriginalGravity: sheet.Gravity.OriginalGravity,
+		PitchRate:       section.PitchRate,
+		Units:           brewing.UnitSystem(sheet.Units),
+	}.TargetCells()
+	if err != nil {
+		section.Error = err.Error()
+		return section
+	}
+	section.TargetCells = cells
+	return section
+}
+
+// formatBrewDaySheet renders the sheet section by section, a failed section giving its error.
+func formatBrewDaySheet(sheet *brewDaySheet) string {
+	format := units.New(brewing.UnitSystem(sheet.Units))
+	volumeUnit, weightUnit, tempUnit := format.VolumeUnit(), format.WeightUnit(), "°F"
+	if format.Metric() {
+		tempUnit = "°C"
+	}
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("**Brew Day Sheet:** %.2f %s at %s%% efficiency\n",
+		sheet.BatchSize, volumeUnit, formatNumber(sheet.Gravity.Efficiency)))
+
+	writeSheetSection(&response, "Gravity", sheet.Gravity.Error, func() {
+		response.WriteString(fmt.Sprintf("- **Original Gravity:** %.3f (%.1f °P)\n",
+			sheet.Gravity.OriginalGravity, sheet.Gravity.Plato))
+	})
+	writeSheetSection(&response, "Bitterness (Tinseth)", sheet.Bitterness.Error, func() {
+		for _, hop := range sheet.Bitterness.Hops {
+			response.WriteString(fmt.Sprintf("- **%s** (%.1f%% AA %s, %s): %.1f IBU\n",
+				hop.Name, hop.AlphaAcid, hop.Form, hop.Timing, hop.IBU))
+		}
+		response.WriteString(fmt.Sprintf("- **Total:** %.1f IBU\n", sheet.Bitterness.IBU))
+	})
+	writeSheetSection(&response, "Colour (Morey)", sheet.Color.Error, func() {
+		response.WriteString(fmt.Sprintf("- **Colour:** %.1f SRM (%.1f EBC)\n", sheet.Color.SRM, sheet.Color.EBC))
+	})
+	writeSheetSection(&response, "Mash", sheet.Mash.Error, func() {
+		response.WriteString(fmt.Sprintf("- **Grain:** %.2f %s at %.1f%s, resting at %.1f%s\n",
+			sheet.Mash.GrainWeight, weightUnit, sheet.Mash.GrainTemp, tempUnit, sheet.Mash.MashTemp, tempUnit))
+		response.WriteString(fmt.Sprintf("- **Strike Water:** %.2f %s at %.1f%s\n",
+			sheet.Mash.StrikeVolume, volumeUnit, sheet.Mash.StrikeTemp, tempUnit))
+		response.WriteString(fmt.Sprintf("- **Grain Absorption:** %.2f %s\n", sheet.Mash.GrainAbsorption, volumeUnit))
+	})
+	writeSheetSection(&response, "Yeast", sheet.Yeast.Error, func() {
+		if sheet.Yeast.Name != "" {
+			response.WriteString(fmt.Sprintf("- **Strain:** %s\n", sheet.Yeast.Name))
+		}
+		response.WriteString(fmt.Sprintf("- **Pitch:** %.0f billion cells (%s pitch rate, %s million cells/ml/°P)\n",
+			sheet.Yeast.TargetCells, sheet.Yeast.Type, formatNumber(sheet.Yeast.PitchRate)))
+	})
+	writeCalculatorNotes(&response, sheet.Notes)
+	return response.String()
+}
+
+// writeSheetSection writes a section of the brew day sheet headed by title: its error, when its
+// calculation failed, or else what write writes.
+func writeSheetSection(response *strings.Builder, title, sectionErr string, write func()) {
+	response.WriteString(fmt.Sprintf("\n**%s**\n\n", title))
+	if sectionErr != "" {
+		response.WriteString(fmt.Sprintf("- _Not calculated: %s._\n", sectionErr))
+		return
+	}
+	write()
+}
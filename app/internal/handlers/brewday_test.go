@@ -0,0 +1,258 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/mcp"
+)
+
+// brewDaySection is the outcome of one section of the brew_day_sheet JSON.
+type brewDaySection struct {
+	Error string `json:"error"`
+}
+
+// brewDaySheetJSON is the part of the brew_day_sheet JSON the tests check.
+type brewDaySheetJSON struct {
+	Gravity struct {
+		brewDaySection
+		OriginalGravity float64 `json:"original_gravity"`
+	} `json:"gravity"`
+	Bitterness struct {
+		brewDaySection
+		IBU float64 `json:"ibu"`
+	} `json:"bitterness"`
+	Color struct {
+		brewDaySection
+		SRM float64 `json:"srm"`
+	} `json:"color"`
+	Mash struct {
+		brewDaySection
+		GrainWeight float64 `json:"grain_weight"`
+		StrikeTemp  float64 `json:"strike_temp"`
+	} `json:"mash"`
+	Yeast struct {
+		brewDaySection
+		Type        string  `json:"type"`
+		TargetCells float64 `json:"target_cells"`
+	} `json:"yeast"`
+}
+
+// callBrewDaySheet calls brew_day_sheet through server, returning its markdown and JSON.
+func callBrewDaySheet(t *testing.T, server *mcp.Server, args map[string]interface{}) (string, brewDaySheetJSON) {
+	t.Helper()
+	msg := mcp.NewMessage("tools/call", mcp.CallToolRequest{Name: "brew_day_sheet", Arguments: args})
+	msgData, _ := json.Marshal(msg)
+	response := server.ProcessMessage(context.Background(), msgData)
+	if response.Error != nil {
+		t.Fatalf("unexpected error: %v", response.Error)
+	}
+	result := response.Result.(*mcp.ToolResult)
+	if len(result.Content) != 2 {
+		t.Fatalf("expected markdown and JSON content, got %d blocks", len(result.Content))
+	}
+	var sheet brewDaySheetJSON
+	if err := json.Unmarshal([]byte(result.Content[1].Text), &sheet); err != nil {
+		t.Fatalf("expected JSON content, got error: %v", err)
+	}
+	return result.Content[0].Text, sheet
+}
+
+// brewDayRecipe is a 5 gallon pale ale named from the ingredient data.
+func brewDayRecipe() map[string]interface{} {
+	return map[string]interface{}{
+		"batch_size": 5.0,
+		"fermentables": []interface{}{
+			map[string]interface{}{"name": "Pale 2-Row", "weight": 10.0},
+			map[string]interface{}{"name": "Crystal 60", "weight": 1.0},
+			map[string]interface{}{"name": "Corn Sugar", "weight": 0.5},
+		},
+		"hops": []interface{}{
+			map[string]interface{}{"name": "Magnum", "alpha_acid": 10.0, "weight": 1.0, "boil_time": 60.0},
+			map[string]interface{}{"name": "Cascade", "weight": 1.0, "boil_time": 5.0, "form": "pellet"},
+		},
+		"yeast": "WLP830",
+	}
+}
+
+func TestBrewDaySheet(t *testing.T) {
+	text, sheet := callBrewDaySheet(t, newIngredientServer(t), brewDayRecipe())
+
+	// 10 lb at 37 PPG, 1 lb at 34, and 0.5 lb at 42 in 5 gallons at 72% is 1.061.
+	for _, want := range []string{
+		"**Brew Day Sheet:** 5.00 gal at 72% efficiency",
+		"**Gravity**\n\n- **Original Gravity:** 1.061 (15.0 °P)",
+		"- **Magnum** (10.0% AA whole, 60 min boil): 31.2 IBU",
+		"- **Cascade** (5.8% AA pellet, 5 min boil): 3.9 IBU",
+		"- **Total:** 35.2 IBU",
+		"- **Colour:** 9.8 SRM (19.3 EBC)",
+		"- **Grain:** 11.00 lb at 68.0°F, resting at 152.0°F",
+		"- **Strike Water:** 4.12 gal at 163.2°F",
+		"- **Strain:** White Labs WLP830 German Lager",
+		"(lager pitch rate, 1.5 million cells/ml/°P)",
+		"_Corn Sugar: sugar, so added to the kettle rather than mashed._",
+		"_Cascade: alpha acid not given; assumed 5.75%",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "Not calculated") {
+		t.Errorf("expected every section calculated, got:\n%s", text)
+	}
+
+	if sheet.Gravity.OriginalGravity < 1.061 || sheet.Gravity.OriginalGravity > 1.062 {
+		t.Errorf("expected an OG of about 1.061, got %.4f", sheet.Gravity.OriginalGravity)
+	}
+	if sheet.Bitterness.IBU < 35 || sheet.Color.SRM == 0 || sheet.Mash.GrainWeight != 11 ||
+		sheet.Yeast.Type != "lager" || sheet.Yeast.TargetCells == 0 {
+		t.Errorf("unexpected sheet: %+v", sheet)
+	}
+}
+
+// Test a section that cannot be calculated reports why in its place, and fails only the sections
+// that depend on it.
+func TestBrewDaySheet_PartialFailures(t *testing.T) {
+	server := newIngredientServer(t)
+	tests := []struct {
+		name   string
+		modify func(args map[string]interface{})
+		// failed maps the failing sections to their errors; the rest must succeed.
+		failed map[string]string
+	}{
+		{
+			name:   "no hops",
+			modify: func(args map[string]interface{}) { delete(args, "hops") },
+			failed: map[string]string{"Bitterness (Tinseth)": "'hops' parameter must be a non-empty array"},
+		},
+		{
+			name: "unknown fermentable without a yield",
+			modify: func(args map[string]interface{}) {
+				args["fermentables"] = []interface{}{
+					map[string]interface{}{"name": "Mystery Malt", "weight": 10.0, "lovibond": 3.0},
+				}
+			},
+			failed: map[string]string{
+				"Gravity": "fermentables[0]: 'ppg' parameter is required, as Mystery Malt is not a known " +
+					"fermentable",
+				"Bitterness (Tinseth)": "needs the original gravity, which could not be predicted",
+				"Yeast":                "needs the original gravity, which could not be predicted",
+			},
+		},
+		{
+			name:   "no fermentables",
+			modify: func(args map[string]interface{}) { delete(args, "fermentables") },
+			failed: map[string]string{
+				"Gravity":              "'fermentables' parameter must be a non-empty array",
+				"Bitterness (Tinseth)": "needs the original gravity",
+				"Colour (Morey)":       "'fermentables' parameter must be a non-empty array",
+				"Mash":                 "'fermentables' parameter must be a non-empty array",
+				"Yeast":                "needs the original gravity",
+			},
+		},
+		{
+			name:   "efficiency out of range",
+			modify: func(args map[string]interface{}) { args["efficiency"] = 120.0 },
+			failed: map[string]string{
+				"Gravity":              "efficiency must be between 0 and 100 (got 120)",
+				"Bitterness (Tinseth)": "needs the original gravity",
+				"Yeast":                "needs the original gravity",
+			},
+		},
+		{
+			name:   "mash too cold",
+			modify: func(args map[string]interface{}) { args["mash_temp"] = -5.0 },
+			failed: map[string]string{"Mash": "target mash temp must be greater than zero"},
+		},
+		{
+			name: "unknown yeast type",
+			modify: func(args map[string]interface{}) {
+				args["yeast"] = map[string]interface{}{"name": "House Strain", "type": "pilsner"}
+			},
+			failed: map[string]string{"Yeast": "yeast type must be 'ale', 'hybrid', or 'lager'"},
+		},
+	}
+	sections := []string{"Gravity", "Bitterness (Tinseth)", "Colour (Morey)", "Mash", "Yeast"}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := brewDayRecipe()
+			tt.modify(args)
+			text, sheet := callBrewDaySheet(t, server, args)
+
+			jsonErrors := map[string]string{
+				"Gravity": sheet.Gravity.Error, "Bitterness (Tinseth)": sheet.Bitterness.Error,
+				"Colour (Morey)": sheet.Color.Error, "Mash": sheet.Mash.Error, "Yeast": sheet.Yeast.Error,
+			}
+			for _, section := range sections {
+				start := strings.Index(text, "**"+section+"**\n\n")
+				if start < 0 {
+					t.Fatalf("expected a %s section in:\n%s", section, text)
+				}
+				body := strings.SplitN(text[start+len(section)+6:], "\n\n", 2)[0]
+				want, fails := tt.failed[section]
+				switch {
+				case fails && (!strings.HasPrefix(body, "- _Not calculated: ") || !strings.Contains(body, want)):
+					t.Errorf("expected %s not calculated because %q, got %q", section, want, body)
+				case !fails && strings.Contains(body, "Not calculated"):
+					t.Errorf("expected %s calculated, got %q", section, body)
+				}
+				if fails != (jsonErrors[section] != "") || !strings.Contains(jsonErrors[section], want) {
+					t.Errorf("expected the JSON %s error to match %q, got %q", section, want, jsonErrors[section])
+				}
+			}
+		})
+	}
+}
+
+func TestBrewDaySheet_Metric(t *testing.T) {
+	text, sheet := callBrewDaySheet(t, newIngredientServer(t), map[string]interface{}{
+		"batch_size": 20.0,
+		"units":      "metric",
+		"efficiency": 75.0,
+		"fermentables": []interface{}{
+			map[string]interface{}{"name": "Pilsner Malt", "weight": 4.5},
+		},
+		"hops": []interface{}{
+			map[string]interface{}{"name": "Saaz", "alpha_acid": 3.5, "weight": 50.0, "boil_time": 60.0},
+		},
+		"yeast":     map[string]interface{}{"type": "lager"},
+		"mash_temp": 65.0,
+	})
+	for _, want := range []string{
+		"**Brew Day Sheet:** 20.00 L at 75% efficiency",
+		"- **Grain:** 4.50 kg at 20.0°C, resting at 65.0°C",
+		"L at ",
+		"(lager pitch rate, 1.5 million cells/ml/°P)",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in:\n%s", want, text)
+		}
+	}
+	if sheet.Gravity.OriginalGravity < 1.040 || sheet.Gravity.OriginalGravity > 1.060 {
+		t.Errorf("expected a pilsner OG, got %.4f", sheet.Gravity.OriginalGravity)
+	}
+	if sheet.Mash.StrikeTemp < 65 || sheet.Mash.StrikeTemp > 80 {
+		t.Errorf("expected a strike temperature in °C, got %.1f", sheet.Mash.StrikeTemp)
+	}
+}
+
+func TestBrewDaySheet_InvalidParams(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         map[string]interface{}
+		wantContains string
+	}{
+		{"no batch size", map[string]interface{}{}, "'batch_size' parameter is required"},
+		{"empty batch", map[string]interface{}{"batch_size": 0.0}, "batch_size must be greater than zero"},
+		{"bad units", map[string]interface{}{"batch_size": 5.0, "units": "cubits"}, "units must be"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, mcpErr := callCalculator(t, "brew_day_sheet", tt.args)
+			expectInvalidParams(t, mcpErr, tt.wantContains)
+		})
+	}
+}
@@ -0,0 +1,1688 @@
+// Package handlers provides HTTP handlers for the Brewsource MCP server, including health checks and resource endpoints.
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"strconv"
+	"strings"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/mcp"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/units"
+	"github.com/CharlRitter/brewsource-mcp/app/pkg/brewing"
+	"github.com/CharlRitter/brewsource-mcp/app/pkg/data"
+)
+
+// registerCalculatorTools registers the brewing calculator tools.
+func (h *ToolHandlers) registerCalculatorTools(server *mcp.Server) {
+	server.RegisterToolHandler("unit_convert", h.UnitConvert)
+	server.RegisterToolHandler("mash_water", h.MashWater)
+	server.RegisterToolHandler("carbonation_calculator", h.CarbonationCalculator)
+	server.RegisterToolHandler("refractometer_correction", h.RefractometerCorrection)
+	server.RegisterToolHandler("hydrometer_correction", h.HydrometerCorrection)
+	server.RegisterToolHandler("ibu_calculator", h.IBUCalculator)
+	server.RegisterToolHandler("srm_calculator", h.SRMCalculator)
+	server.RegisterToolHandler("volume_calculator", h.VolumeCalculator)
+	server.RegisterToolHandler("abv_calculator", h.ABVCalculator)
+	server.RegisterToolHandler("attenuation_calculator", h.AttenuationCalculator)
+	server.RegisterToolHandler("yeast_starter", h.YeastStarter)
+	server.RegisterToolHandler("water_profile", h.WaterProfile)
+	server.RegisterToolHandler("brew_day_sheet", h.BrewDaySheet)
+}
+
+// calculatorToolDefinitions returns the definitions for the brewing calculator tools.
+func calculatorToolDefinitions() []mcp.Tool {
+	return []mcp.Tool{
+		{
+			Name:        "unit_convert",
+			Description: "Convert brewing units: gravity, temperature, volume, weight, colour, and CO2",
+			InputSchema: mcp.ObjectSchema(map[string]interface{}{
+				"value": mcp.NumberSchema("Value to convert"),
+				"from_unit": mcp.StringSchema(
+					"Unit to convert from (sg, plato, brix, points, f, c, gal, qt, l, ml, oz, lb, g, kg, srm, ebc, "+
+						"lovibond, volumes, psi)",
+					true,
+				),
+				"to_unit":          mcp.StringSchema("Unit to convert to (same dimension as from_unit)", true),
+				"temperature":      mcp.NumberSchema("Beer temperature, required for psi ↔ CO2 volumes"),
+				"temperature_unit": mcp.StringSchema("Unit of temperature: 'f' (default) or 'c'", false),
+			}, []string{"value", "from_unit", "to_unit"}),
+		},
+		{
+			Name:        "mash_water",
+			Description: "Calculate strike water, step infusions, and pre-boil volume for a mash",
+			InputSchema: mcp.ObjectSchema(map[string]interface{}{
+				"grain_weight":         mcp.NumberSchema("Grain bill weight (lb, or kg when units is metric)"),
+				"grain_temp":           mcp.NumberSchema("Grain temperature (°F, or °C when units is metric)"),
+				"target_temp":          mcp.NumberSchema("Target temperature of the first mash rest"),
+				"water_to_grist_ratio": mcp.NumberSchema("Mash thickness (qt/lb or L/kg, default: 1.5 qt/lb)"),
+				"step_temps": map[string]interface{}{
+					"type":        "array",
+					"items":       map[string]interface{}{"type": "number"},
+					"description": "Additional rest temperatures reached by boiling-water infusions",
+				},
+				"infusion_water_temp": mcp.NumberSchema("Temperature of infusion water (default: 210°F / 99°C)"),
+				"sparge_volume":       mcp.NumberSchema("Sparge water volume (gal or L)"),
+				"grain_absorption": mcp.NumberSchema(
+					"Water retained by grain (gal/lb or L/kg, default: 0.125 gal/lb)"),
+				"units": mcp.StringSchema(unitsDescription, false),
+			}, []string{"grain_weight", "grain_temp", "target_temp"}),
+		},
+		{
+			Name:        "carbonation_calculator",
+			Description: "Calculate priming sugar for bottling or regulator pressure for kegging",
+			InputSchema: mcp.ObjectSchema(map[string]interface{}{
+				"beer_volume": mcp.NumberSchema("Volume of beer to carbonate (gal, or L when units is metric)"),
+				"beer_temp": mcp.NumberSchema(
+					"Beer temperature (°F or °C): warmest since fermentation for priming, " +
+						"serving temperature for kegs"),
+				"target_co2_volumes": mcp.NumberSchema("Target carbonation in volumes of CO2 (0.5-5.0)"),
+				"method":             mcp.StringSchema("Carbonation method: 'priming' (default) or 'keg'", false),
+				"sugar_type": mcp.StringSchema(
+					"Priming sugar: corn_sugar (default), table_sugar, dme, or honey", false),
+				"units": mcp.StringSchema(unitsDescription, false),
+			}, []string{"beer_volume", "beer_temp", "target_co2_volumes"}),
+		},
+		{
+			Name:        "refractometer_correction",
+			Description: "Convert refractometer Brix to OG and correct final readings for alcohol",
+			InputSchema: mcp.ObjectSchema(map[string]interface{}{
+				"original_brix": mcp.NumberSchema("Refractometer reading of the unfermented wort (°Brix)"),
+				"final_brix":    mcp.NumberSchema("Refractometer reading after fermentation (°Brix)"),
+				"wort_correction_factor": mcp.NumberSchema(
+					"Refractometer wort correction factor (default: 1.04)"),
+				"formula": mcp.StringSchema("Final gravity correction: 'cubic' (default) or 'linear'", false),
+			}, []string{"original_brix"}),
+		},
+		{
+			Name:        "hydrometer_correction",
+			Description: "Correct a hydrometer reading taken away from the hydrometer's calibration temperature",
+			InputSchema: mcp.ObjectSchema(map[string]interface{}{
+				"measured_gravity": mcp.NumberSchema("Specific gravity read from the hydrometer"),
+				"sample_temp":      mcp.NumberSchema("Temperature of the sample when read"),
+				"calibration_temp": mcp.NumberSchema("Hydrometer calibration temperature (default: 60°F)"),
+				"temperature_unit": mcp.StringSchema(
+					"Unit of both temperatures: 'f' or 'c' (default: 'c' when units is metric, else 'f')", false),
+				"units": mcp.StringSchema(unitsDescription, false),
+			}, []string{"measured_gravity", "sample_temp"}),
+		},
+		{
+			Name:        "ibu_calculator",
+			Description: "Estimate IBUs for hop additions using the Tinseth, Rager, or Garetz formula",
+			InputSchema: mcp.ObjectSchema(map[string]interface{}{
+				"batch_size":       mcp.NumberSchema("Post-boil batch volume (gal, or L when units is metric)"),
+				"original_gravity": mcp.NumberSchema("Wort original gravity (e.g. 1.050)"),
+				"hops": map[string]interface{}{
+					"type":        "array",
+					"description": "Hop additions",
+					"items": mcp.ObjectSchema(map[string]interface{}{
+						"name": mcp.StringSchema(
+							"Hop variety; fills in alpha_acid from the ingredient data when it is omitted", false),
+						"alpha_acid": mcp.NumberSchema(
+							"Alpha acid percentage (default: the middle of the named variety's range)"),
+						"weight":    mcp.NumberSchema("Hop weight (oz, or g when units is metric)"),
+						"boil_time": mcp.NumberSchema("Boil minutes, or steep minutes for whirlpool additions"),
+						"steep_temp": mcp.NumberSchema(
+							"Whirlpool/hop stand temperature (°F or °C); omit for boil additions"),
+						"form": mcp.StringSchema("Hop form: 'whole' (default), 'pellet', or 'extract'", false),
+						"dry_hop": map[string]interface{}{
+							"type":        "boolean",
+							"description": "Dry hop addition (contributes no IBU)",
+						},
+					}, []string{"weight", "boil_time"}),
+				},
+				"formula": mcp.StringSchema("IBU formula: 'tinseth' (default), 'rager', or 'garetz'", false),
+				"units": mcp.StringSchema(
+					"Unit system: 'imperial' or 'metric'; when omitted, the client's preference, or else metric "+
+						"if batch_size is over 15 or any hop weight is over 8", false),
+			}, []string{"batch_size", "original_gravity", "hops"}),
+		},
+		{
+			Name:        "srm_calculator",
+			Description: "Estimate beer colour in SRM and EBC from the grain bill using the Morey equation",
+			InputSchema: mcp.ObjectSchema(map[string]interface{}{
+				"batch_size": mcp.NumberSchema("Post-boil batch volume (gal, or L when units is metric)"),
+				"fermentables": map[string]interface{}{
+					"type":        "array",
+					"description": "Grain bill",
+					"items": mcp.ObjectSchema(map[string]interface{}{
+						"name": mcp.StringSchema(
+							"Fermentable name; fills in lovibond from the ingredient data when it is omitted "+
+								"(e.g., 'Crystal 60')", false),
+						"weight":   mcp.NumberSchema("Weight (lb, or kg when units is metric)"),
+						"lovibond": mcp.NumberSchema("Colour (°L; default: the named fermentable's colour)"),
+					}, []string{"weight"}),
+				},
+				"units": mcp.StringSchema(unitsDescription, false),
+			}, []string{"batch_size", "fermentables"}),
+		},
+		{
+			Name:        "volume_calculator",
+			Description: "Plan pre-boil volume, dilute wort to a target gravity, or blend worts",
+			InputSchema: mcp.ObjectSchema(map[string]interface{}{
+				"calculation": mcp.StringSchema("Calculation: 'boil' (default), 'dilution', or 'blend'", false),
+				"target_volume": mcp.NumberSchema(
+					"boil: volume wanted in the fermenter (gal, or L when units is metric)"),
+				"boil_time":        mcp.NumberSchema("boil: boil length in minutes (default: 60)"),
+				"evaporation_rate": mcp.NumberSchema("boil: evaporation rate per hour"),
+				"evaporation_unit": mcp.StringSchema(
+					"boil: 'percent' (default, % of pre-boil volume) or 'volume' (gal/hr or L/hr)", false),
+				"shrinkage":      mcp.NumberSchema("boil: cooling shrinkage percent (default: 4)"),
+				"trub_loss":      mcp.NumberSchema("boil: volume left in the kettle"),
+				"volume":         mcp.NumberSchema("dilution: current wort volume"),
+				"gravity":        mcp.NumberSchema("dilution: current wort gravity"),
+				"target_gravity": mcp.NumberSchema("dilution: gravity to dilute down to"),
+				"worts": map[string]interface{}{
+					"type":        "array",
+					"description": "blend: worts to mix",
+					"items": mcp.ObjectSchema(map[string]interface{}{
+						"volume":  mcp.NumberSchema("Wort volume"),
+						"gravity": mcp.NumberSchema("Wort gravity"),
+					}, []string{"volume", "gravity"}),
+				},
+				"units": mcp.StringSchema(unitsDescription, false),
+			}, nil),
+		},
+		{
+			Name:        "abv_calculator",
+			Description: "Calculate ABV, ABW, and calories from original and final gravity",
+			InputSchema: mcp.ObjectSchema(map[string]interface{}{
+				"original_gravity": mcp.NumberSchema("Original gravity (e.g. 1.050)"),
+				"final_gravity":    mcp.NumberSchema("Final gravity (e.g. 1.010)"),
+				"serving_size": mcp.NumberSchema(
+					"Serving size for calories (oz, or ml when units is metric; default: 12 oz)"),
+				"units": mcp.StringSchema(unitsDescription, false),
+			}, []string{"original_gravity", "final_gravity"}),
+		},
+		{
+			Name:        "attenuation_calculator",
+			Description: "Calculate apparent and real attenuation, ABV, ABW, and calories from OG and FG",
+			InputSchema: mcp.ObjectSchema(map[string]interface{}{
+				"original_gravity": mcp.NumberSchema("Original gravity (e.g. 1.050)"),
+				"final_gravity":    mcp.NumberSchema("Final gravity (e.g. 1.010)"),
+			}, []string{"original_gravity", "final_gravity"}),
+		},
+		{
+			Name:        "yeast_starter",
+			Description: "Plan a yeast starter: pitch target from batch size and gravity, and cell growth per step",
+			InputSchema: mcp.ObjectSchema(map[string]interface{}{
+				"initial_cells":    mcp.NumberSchema("Viable cells available to pitch into the starter (billions)"),
+				"target_cells":     mcp.NumberSchema("Cells needed (billions); calculated from the batch when omitted"),
+				"batch_volume":     mcp.NumberSchema("Batch volume for the pitch target (gal, or L if metric)"),
+				"original_gravity": mcp.NumberSchema("Batch original gravity for the pitch target"),
+				"yeast_type":       mcp.StringSchema("Pitch rate: 'ale' (default), 'hybrid', or 'lager'", false),
+				"steps": map[string]interface{}{
+					"type":        "array",
+					"description": "Starter steps to evaluate; planned automatically from the target when omitted",
+					"items": mcp.ObjectSchema(map[string]interface{}{
+						"volume":  mcp.NumberSchema("Starter volume (L)"),
+						"gravity": mcp.NumberSchema("Starter gravity (1.020-1.060, default: 1.036)"),
+					}, []string{"volume"}),
+				},
+				"model": mcp.StringSchema(
+					"Growth model: 'braukaiser' (stir plate, default) or 'white' (simple starter)", false),
+				"units": mcp.StringSchema(
+					"Unit system: 'imperial' or 'metric'; when omitted, the client's preference, or else metric "+
+						"if batch_volume is over 15", false),
+			}, []string{"initial_cells"}),
+		},
+		{
+			Name:        "water_profile",
+			Description: "Calculate brewing salt additions toward a target water profile and estimate mash pH",
+			InputSchema: mcp.ObjectSchema(map[string]interface{}{
+				"calcium":     mcp.NumberSchema("Source calcium (ppm, default: 0)"),
+				"magnesium":   mcp.NumberSchema("Source magnesium (ppm, default: 0)"),
+				"sodium":      mcp.NumberSchema("Source sodium (ppm, default: 0)"),
+				"sulfate":     mcp.NumberSchema("Source sulfate (ppm, default: 0)"),
+				"chloride":    mcp.NumberSchema("Source chloride (ppm, default: 0)"),
+				"bicarbonate": mcp.NumberSchema("Source bicarbonate (ppm, default: 0)"),
+				"target": mcp.StringSchema(
+					"Target preset: "+strings.Join(brewing.WaterProfileNames(), ", "), false),
+				"target_profile": mcp.ObjectSchema(map[string]interface{}{
+					"calcium":     mcp.NumberSchema("Calcium (ppm)"),
+					"magnesium":   mcp.NumberSchema("Magnesium (ppm)"),
+					"sodium":      mcp.NumberSchema("Sodium (ppm)"),
+					"sulfate":     mcp.NumberSchema("Sulfate (ppm)"),
+					"chloride":    mcp.NumberSchema("Chloride (ppm)"),
+					"bicarbonate": mcp.NumberSchema("Bicarbonate (ppm)"),
+				}, nil),
+				"volume": mcp.NumberSchema("Water volume to treat (gal, or L if metric)"),
+				"grains": map[string]interface{}{
+					"type":        "array",
+					"description": "Grain bill for a mash pH estimate with the treated water",
+					"items": mcp.ObjectSchema(map[string]interface{}{
+						"weight":   mcp.NumberSchema("Grain weight (lb, or kg if metric)"),
+						"lovibond": mcp.NumberSchema("Grain colour (°L)"),
+						"type": mcp.StringSchema(
+							"'base', 'crystal', or 'roasted'; inferred from colour when omitted", false),
+					}, []string{"weight", "lovibond"}),
+				},
+				"water_to_grist_ratio": mcp.NumberSchema("Mash thickness (qt/lb or L/kg, default: 1.5 qt/lb)"),
+				"target_ph":            mcp.NumberSchema("Target mash pH for acid additions (default: 5.4)"),
+				"units":                mcp.StringSchema(unitsDescription, false),
+			}, []string{"volume"}),
+		},
+		{
+			Name: "brew_day_sheet",
+			Description: "Run a recipe through the calculators for a brew day sheet: predicted OG, IBU, colour, " +
+				"strike water, and yeast pitch",
+			InputSchema: mcp.ObjectSchema(map[string]interface{}{
+				"batch_size": mcp.NumberSchema("Volume into the fermenter (gal, or L when units is metric)"),
+				"efficiency": mcp.NumberSchema("Brewhouse efficiency in percent (default: 72)"),
+				"fermentables": map[string]interface{}{
+					"type":        "array",
+					"description": "Grain bill",
+					"items": mcp.ObjectSchema(map[string]interface{}{
+						"name": mcp.StringSchema(
+							"Fermentable name; fills in lovibond and ppg from the ingredient data when they are "+
+								"omitted", false),
+						"weight":   mcp.NumberSchema("Weight (lb, or kg when units is metric)"),
+						"lovibond": mcp.NumberSchema("Colour (°L; default: the named fermentable's colour)"),
+						"ppg": mcp.NumberSchema(
+							"Yield in gravity points per pound per gallon (default: the named fermentable's yield)"),
+					}, []string{"weight"}),
+				},
+				"hops": map[string]interface{}{
+					"type":        "array",
+					"description": "Hop schedule, as for ibu_calculator",
+					"items": mcp.ObjectSchema(map[string]interface{}{
+						"name":       mcp.StringSchema("Hop variety; fills in alpha_acid when it is omitted", false),
+						"alpha_acid": mcp.NumberSchema("Alpha acid percentage"),
+						"weight":     mcp.NumberSchema("Hop weight (oz, or g when units is metric)"),
+						"boil_time":  mcp.NumberSchema("Boil minutes, or steep minutes for whirlpool additions"),
+						"steep_temp": mcp.NumberSchema("Whirlpool temperature; omit for boil additions"),
+						"form":       mcp.StringSchema("Hop form: 'whole' (default), 'pellet', or 'extract'", false),
+						"dry_hop": map[string]interface{}{
+							"type":        "boolean",
+							"description": "Dry hop addition (contributes no IBU)",
+						},
+					}, []string{"weight", "boil_time"}),
+				},
+				"yeast": mcp.ObjectSchema(map[string]interface{}{
+					"name": mcp.StringSchema("Yeast strain; sets the pitch rate from the ingredient data", false),
+					"type": mcp.StringSchema("Pitch rate: 'ale' (default), 'hybrid', or 'lager'", false),
+				}, nil),
+				"grain_temp":           mcp.NumberSchema("Grain temperature (default: 68°F / 20°C)"),
+				"mash_temp":            mcp.NumberSchema("Mash rest temperature (default: 152°F / 66.7°C)"),
+				"water_to_grist_ratio": mcp.NumberSchema("Mash thickness (qt/lb or L/kg, default: 1.5 qt/lb)"),
+				"units":                mcp.StringSchema(unitsDescription, false),
+			}, []string{"batch_size", "fermentables"}),
+		},
+	}
+}
+
+// UnitConvert converts a value between two brewing units of the same dimension.
+func (h *ToolHandlers) UnitConvert(_ context.Context, args map[string]interface{}) (*mcp.ToolResult, error) {
+	value, err := requireFloat(args, "value")
+	if err != nil {
+		return nil, err
+	}
+	fromUnit, err := requireString(args, "from_unit")
+	if err != nil {
+		return nil, err
+	}
+	toUnit, err := requireString(args, "to_unit")
+	if err != nil {
+		return nil, err
+	}
+	temperatureF, err := parseTemperatureF(args, "temperature", "temperature_unit")
+	if err != nil {
+		return nil, err
+	}
+
+	converted, err := brewing.ConvertUnits(value, fromUnit, toUnit, brewing.Conditions{TemperatureF: temperatureF})
+	if err != nil {
+		return nil, conversionError(err)
+	}
+
+	return mcp.NewToolResult(fmt.Sprintf("**Conversion:** %s %s = %s %s",
+		formatUnitValue(value, fromUnit), brewing.NormalizeUnit(fromUnit),
+		formatUnitValue(converted, toUnit), brewing.NormalizeUnit(toUnit))), nil
+}
+
+// calculationError maps pkg/brewing calculation failures onto InvalidParams errors, naming the
+// offending field in the error data when the calculation reports one.
+func calculationError(err error) error {
+	mcpErr := &mcp.Error{
+		Code:    mcp.InvalidParams,
+		Message: err.Error(),
+	}
+	var validationErr *brewing.ValidationError
+	if errors.As(err, &validationErr) {
+		mcpErr.Data = map[string]interface{}{
+			"field":      validationErr.Field,
+			"value":      validationErr.Value,
+			"constraint": validationErr.Constraint,
+		}
+	}
+	return mcpErr
+}
+
+// conversionError maps unit conversion failures onto InvalidParams errors.
+func conversionError(err error) error {
+	var unsupported *brewing.UnsupportedConversionError
+	if errors.As(err, &unsupported) {
+		return &mcp.Error{
+			Code: mcp.InvalidParams,
+			Message: fmt.Sprintf("unsupported unit conversion from %q to %q; supported units: %s",
+				unsupported.From, unsupported.To, strings.Join(unsupported.Supported, ", ")),
+			Data: map[string]interface{}{
+				"supported_units": unsupported.Supported,
+			},
+		}
+	}
+	return &mcp.Error{
+		Code:    mcp.InvalidParams,
+		Message: err.Error(),
+	}
+}
+
+// formatUnitValue renders a value with the conventional precision for its unit.
+func formatUnitValue(value float64, unit string) string {
+	return strconv.FormatFloat(value, 'f', brewing.UnitPrecision(unit), 64)
+}
+
+// requireFloat extracts a required numeric argument.
+func requireFloat(args map[string]interface{}, key string) (float64, error) {
+	value, err := parseOptionalFloat(args, key)
+	if err != nil {
+		return 0, err
+	}
+	if value == nil {
+		return 0, &mcp.Error{
+			Code:    mcp.InvalidParams,
+			Message: fmt.Sprintf("'%s' parameter is required", key),
+		}
+	}
+	return *value, nil
+}
+
+// requireString extracts a required, non-empty string argument.
+func requireString(args map[string]interface{}, key string) (string, error) {
+	value, ok := args[key].(string)
+	if !ok || strings.TrimSpace(value) == "" {
+		return "", &mcp.Error{
+			Code:    mcp.InvalidParams,
+			Message: fmt.Sprintf("'%s' parameter is required", key),
+		}
+	}
+	return strings.TrimSpace(value), nil
+}
+
+// parseTemperatureF reads an optional temperature argument and converts it to °F using its unit argument.
+func parseTemperatureF(args map[string]interface{}, valueKey, unitKey string) (*float64, error) {
+	temperature, err := parseOptionalFloat(args, valueKey)
+	if err != nil || temperature == nil {
+		return nil, err
+	}
+	unit, _ := args[unitKey].(string)
+	switch brewing.NormalizeUnit(unit) {
+	case "", "f":
+		return temperature, nil
+	case "c":
+		f := brewing.CelsiusToFahrenheit(*temperature)
+		return &f, nil
+	default:
+		return nil, &mcp.Error{
+			Code:    mcp.InvalidParams,
+			Message: fmt.Sprintf("%s must be 'f' or 'c'", unitKey),
+		}
+	}
+}
+
+const (
+	unitsImperial = string(brewing.UnitsImperial)
+	unitsMetric   = string(brewing.UnitsMetric)
+	// unitsDescription describes the 'units' argument of the tools taking one.
+	unitsDescription = "Unit system: 'imperial' or 'metric' (default: the client's preference, else imperial)"
+	// metricVolumeThreshold is the batch volume above which omitted units are taken to be metric.
+	// Few homebrew batches exceed 15 gallons, while 19-23 L batches are typical.
+	metricVolumeThreshold = 15.0
+	// metricHopWeightThreshold is the single hop addition above which omitted units are taken to be
+	// metric. Eight ounces in one addition is rare, while 10-50 g is typical.
+	metricHopWeightThreshold = 8.0
+	// defaultWaterToGristRatio is the mash thickness used when none is given, in quarts per pound.
+	defaultWaterToGristRatio = 1.5
+)
+
+// MashWater calculates the strike and infusion water plan for a mash.
+func (h *ToolHandlers) MashWater(ctx context.Context, args map[string]interface{}) (*mcp.ToolResult, error) {
+	system, err := parseUnitSystem(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	calc, err := parseStrikeWaterCalculation(args, system)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := calc.Calculate()
+	if err != nil {
+		return nil, calculationError(err)
+	}
+
+	return mcp.NewToolResult(formatMashWater(result, system)), nil
+}
+
+// inferUnitSystem reads the optional 'units' argument. When it is omitted, the client's preferred
+// system is used, and without one the system is guessed from the inputs: metric if volume is above
+// metricVolumeThreshold or any weight is above metricHopWeightThreshold, otherwise imperial. The
+// second return value reports whether the system was guessed.
+func inferUnitSystem(
+	ctx context.Context, args map[string]interface{}, volume float64, weights ...float64,
+) (string, bool, error) {
+	if name, _ := args["units"].(string); strings.TrimSpace(name) != "" || units.FromContext(ctx) != "" {
+		system, err := parseUnitSystem(ctx, args)
+		return system, false, err
+	}
+	if volume > metricVolumeThreshold {
+		return unitsMetric, true, nil
+	}
+	for _, weight := range weights {
+		if weight > metricHopWeightThreshold {
+			return unitsMetric, true, nil
+		}
+	}
+	return unitsImperial, true, nil
+}
+
+// parseUnitSystem reads the optional 'units' argument, defaulting to the system the client
+// prefers (see units.FromContext) and then to imperial.
+func parseUnitSystem(ctx context.Context, args map[string]interface{}) (string, error) {
+	name, _ := args["units"].(string)
+	system, err := units.Parse(name)
+	if err != nil {
+		return "", &mcp.Error{Code: mcp.InvalidParams, Message: err.Error()}
+	}
+	if system == "" {
+		system = units.FromContext(ctx)
+	}
+	if system == "" {
+		return unitsImperial, nil
+	}
+	return string(system), nil
+}
+
+func parseStrikeWaterCalculation(args map[string]interface{}, system string) (brewing.StrikeWaterCalculation, error) {
+	calc := brewing.StrikeWaterCalculation{}
+	fields := []struct {
+		key      string
+		field    *float64
+		required bool
+	}{
+		{"grain_weight", &calc.GrainWeight, true},
+		{"grain_temp", &calc.GrainTemp, true},
+		{"target_temp", &calc.TargetMashTemp, true},
+		{"water_to_grist_ratio", &calc.WaterToGristRatio, false},
+		{"infusion_water_temp", &calc.InfusionWaterTemp, false},
+		{"sparge_volume", &calc.SpargeVolume, false},
+		{"grain_absorption", &calc.GrainAbsorption, false},
+	}
+	for _, f := range fields {
+		if f.required {
+			value, err := requireFloat(args, f.key)
+			if err != nil {
+				return calc, err
+			}
+			*f.field = value
+			continue
+		}
+		value, err := parseOptionalFloat(args, f.key)
+		if err != nil {
+			return calc, err
+		}
+		if value != nil {
+			*f.field = *value
+		}
+	}
+	steps, err := parseFloatList(args, "step_temps")
+	if err != nil {
+		return calc, err
+	}
+	calc.StepTemps = steps
+
+	if system == unitsMetric {
+		calc.GrainWeight = calc.GrainWeight * 1000 / brewing.GramsPerPound
+		calc.GrainTemp = brewing.CelsiusToFahrenheit(calc.GrainTemp)
+		calc.TargetMashTemp = brewing.CelsiusToFahrenheit(calc.TargetMashTemp)
+		calc.WaterToGristRatio = litersPerKgToQuartsPerPound(calc.WaterToGristRatio)
+		if calc.InfusionWaterTemp != 0 {
+			calc.InfusionWaterTemp = brewing.CelsiusToFahrenheit(calc.InfusionWaterTemp)
+		}
+		calc.SpargeVolume /= brewing.LitersPerGallon
+		calc.GrainAbsorption = litersPerKgToQuartsPerPound(calc.GrainAbsorption) / 4 // quarts to gallons per pound
+		for i, step := range calc.StepTemps {
+			calc.StepTemps[i] = brewing.CelsiusToFahrenheit(step)
+		}
+	}
+	if _, given := args["water_to_grist_ratio"]; !given {
+		calc.WaterToGristRatio = defaultWaterToGristRatio
+	}
+	return calc, nil
+}
+
+// litersPerKgToQuartsPerPound converts a metric mash thickness to quarts per pound.
+func litersPerKgToQuartsPerPound(ratio float64) float64 {
+	return ratio * (4 / brewing.LitersPerGallon) * (brewing.GramsPerPound / 1000)
+}
+
+// parseFloatList extracts an optional array of numbers.
+func parseFloatList(args map[string]interface{}, key string) ([]float64, error) {
+	raw, exists := args[key]
+	if !exists || raw == nil {
+		return nil, nil
+	}
+	items, ok := raw.([]interface{})
+	if !ok {
+		return nil, &mcp.Error{
+			Code:    mcp.InvalidParams,
+			Message: fmt.Sprintf("%s must be an array of numbers", key),
+		}
+	}
+	values := make([]float64, 0, len(items))
+	for i := range items {
+		value, err := parseOptionalFloat(map[string]interface{}{key: items[i]}, key)
+		if err != nil || value == nil {
+			return nil, &mcp.Error{
+				Code:    mcp.InvalidParams,
+				Message: fmt.Sprintf("%s must be an array of numbers", key),
+			}
+		}
+		values = append(values, *value)
+	}
+	return values, nil
+}
+
+func formatMashWater(result *brewing.MashWaterResult, system string) string {
+	format := units.New(brewing.UnitSystem(system))
+	temp, volume := format.Temperature, format.Volume
+
+	var response strings.Builder
+	response.WriteString("**Mash Water Plan:**\n\n")
+	response.WriteString(fmt.Sprintf("- **Strike Temperature:** %s\n", temp(result.StrikeTemp)))
+	response.WriteString(fmt.Sprintf("- **Strike Volume:** %s\n", volume(result.StrikeVolume)))
+	for i, infusion := range result.Infusions {
+		response.WriteString(fmt.Sprintf("- **Infusion %d:** %s of boiling water to reach %s\n",
+			i+1, volume(infusion.Volume), temp(infusion.TargetTemp)))
+	}
+	if result.SpargeVolume > 0 {
+		response.WriteString(fmt.Sprintf("- **Sparge Volume:** %s\n", volume(result.SpargeVolume)))
+	}
+	response.WriteString(fmt.Sprintf("- **Total Water:** %s\n", volume(result.TotalWater)))
+	response.WriteString(fmt.Sprintf("- **Grain Absorption:** %s\n", volume(result.GrainAbsorption)))
+	response.WriteString(fmt.Sprintf("- **Estimated Pre-Boil Volume:** %s\n", volume(result.PreBoilVolume)))
+	return response.String()
+}
+
+const (
+	carbonationPriming = "priming"
+	carbonationKeg     = "keg"
+)
+
+// CarbonationCalculator calculates priming sugar or keg pressure for a carbonation target.
+func (h *ToolHandlers) CarbonationCalculator(
+	ctx context.Context, args map[string]interface{},
+) (*mcp.ToolResult, error) {
+	system, err := parseUnitSystem(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	method, _ := args["method"].(string)
+	method = strings.ToLower(strings.TrimSpace(method))
+	switch method {
+	case "":
+		method = carbonationPriming
+	case carbonationPriming, carbonationKeg:
+	default:
+		return nil, &mcp.Error{
+			Code:    mcp.InvalidParams,
+			Message: "method must be 'priming' or 'keg'",
+		}
+	}
+
+	calc := brewing.CarbonationCalculation{}
+	if calc.BeerVolume, err = requireFloat(args, "beer_volume"); err != nil {
+		return nil, err
+	}
+	if calc.BeerTemp, err = requireFloat(args, "beer_temp"); err != nil {
+		return nil, err
+	}
+	if calc.TargetCO2Volumes, err = requireFloat(args, "target_co2_volumes"); err != nil {
+		return nil, err
+	}
+	sugarName, _ := args["sugar_type"].(string)
+	if calc.SugarType, err = brewing.ParseSugarType(sugarName); err != nil {
+		return nil, &mcp.Error{
+			Code:    mcp.InvalidParams,
+			Message: err.Error(),
+			Data: map[string]interface{}{
+				"supported_sugar_types": brewing.SugarTypes(),
+			},
+		}
+	}
+	if system == unitsMetric {
+		calc.BeerVolume /= brewing.LitersPerGallon
+		calc.BeerTemp = brewing.CelsiusToFahrenheit(calc.BeerTemp)
+	}
+
+	result, err := calc.Calculate()
+	if err != nil {
+		return nil, calculationError(err)
+	}
+
+	return mcp.NewToolResult(formatCarbonation(calc, result, method, system)), nil
+}
+
+func formatCarbonation(
+	calc brewing.CarbonationCalculation, result *brewing.CarbonationResult, method, system string,
+) string {
+	format := units.New(brewing.UnitSystem(system))
+
+	var response strings.Builder
+	response.WriteString("**Carbonation Plan:**\n\n")
+	response.WriteString(fmt.Sprintf("- **Beer:** %s at %s\n", format.Volume(calc.BeerVolume),
+		format.Temperature(calc.BeerTemp)))
+	response.WriteString(fmt.Sprintf("- **Target:** %.2f volumes CO2\n", calc.TargetCO2Volumes))
+	if method == carbonationKeg {
+		response.WriteString(fmt.Sprintf("- **Regulator Pressure:** %.1f psi\n", result.KegPSI))
+		return response.String()
+	}
+	response.WriteString(fmt.Sprintf("- **Residual CO2:** %.2f volumes\n", result.ResidualCO2))
+	response.WriteString(fmt.Sprintf("- **Priming Sugar (%s):** %.1f g (%.2f oz)\n",
+		strings.ReplaceAll(string(result.SugarType), "_", " "), result.SugarGrams, result.SugarOunces))
+	if result.SugarGrams == 0 {
+		response.WriteString("\nThe beer already holds at least the target carbonation; no priming sugar is needed.\n")
+	}
+	return response.String()
+}
+
+// RefractometerCorrection converts refractometer readings to corrected gravities.
+func (h *ToolHandlers) RefractometerCorrection(
+	_ context.Context, args map[string]interface{},
+) (*mcp.ToolResult, error) {
+	calc := brewing.RefractometerCorrection{}
+	var err error
+	if calc.OriginalBrix, err = requireFloat(args, "original_brix"); err != nil {
+		return nil, err
+	}
+	if calc.FinalBrix, err = parseOptionalFloat(args, "final_brix"); err != nil {
+		return nil, err
+	}
+	wcf, err := parseOptionalFloat(args, "wort_correction_factor")
+	if err != nil {
+		return nil, err
+	}
+	if wcf != nil {
+		if *wcf <= 0 {
+			return nil, &mcp.Error{
+				Code:    mcp.InvalidParams,
+				Message: "wort_correction_factor must be greater than zero",
+			}
+		}
+		calc.WortCorrectionFactor = *wcf
+	}
+	formula, _ := args["formula"].(string)
+	calc.Formula = brewing.RefractometerFormula(strings.ToLower(strings.TrimSpace(formula)))
+
+	result, err := calc.Calculate()
+	if err != nil {
+		return nil, calculationError(err)
+	}
+
+	var response strings.Builder
+	response.WriteString("**Refractometer Correction:**\n\n")
+	response.WriteString(fmt.Sprintf("- **Original Gravity:** %.3f (%.1f °Brix)\n",
+		result.OriginalGravity, calc.OriginalBrix))
+	if result.FinalGravity != nil {
+		response.WriteString(fmt.Sprintf("- **Final Gravity:** %.3f (%.1f °Brix, corrected)\n",
+			*result.FinalGravity, *calc.FinalBrix))
+		response.WriteString(fmt.Sprintf("- **ABV:** %.1f%%\n", *result.ABV))
+	}
+	return mcp.NewToolResult(response.String()), nil
+}
+
+// HydrometerCorrection adjusts a hydrometer reading for sample temperature.
+func (h *ToolHandlers) HydrometerCorrection(ctx context.Context, args map[string]interface{}) (*mcp.ToolResult, error) {
+	system, err := parseUnitSystem(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	if _, given := args["temperature_unit"]; !given && system == unitsMetric {
+		args = maps.Clone(args)
+		args["temperature_unit"] = "c"
+	}
+	measured, err := requireFloat(args, "measured_gravity")
+	if err != nil {
+		return nil, err
+	}
+	if _, err = requireFloat(args, "sample_temp"); err != nil {
+		return nil, err
+	}
+	sampleF, err := parseTemperatureF(args, "sample_temp", "temperature_unit")
+	if err != nil {
+		return nil, err
+	}
+	calibrationF, err := parseTemperatureF(args, "calibration_temp", "temperature_unit")
+	if err != nil {
+		return nil, err
+	}
+	if calibrationF == nil {
+		defaultCalibration := float64(brewing.DefaultHydrometerCalibrationF)
+		calibrationF = &defaultCalibration
+	}
+
+	corrected, err := brewing.HydrometerCorrection(measured, *sampleF, *calibrationF)
+	if err != nil {
+		return nil, calculationError(err)
+	}
+
+	format := units.New(brewing.UnitSystem(system))
+	return mcp.NewToolResult(fmt.Sprintf(
+		"**Hydrometer Correction:** %.3f read at %s = **%.3f** at %s calibration (%+.3f)",
+		measured, format.Temperature(*sampleF), corrected, format.Temperature(*calibrationF),
+		corrected-measured)), nil
+}
+
+// IBUCalculator estimates the bitterness contributed by a set of hop additions.
+func (h *ToolHandlers) IBUCalculator(ctx context.Context, args map[string]interface{}) (*mcp.ToolResult, error) {
+	batchSize, err := requireFloat(args, "batch_size")
+	if err != nil {
+		return nil, err
+	}
+	og, err := requireFloat(args, "original_gravity")
+	if err != nil {
+		return nil, err
+	}
+	formulaName, _ := args["formula"].(string)
+	formula := brewing.IBUFormula(strings.ToLower(strings.TrimSpace(formulaName)))
+	if formula == "" {
+		formula = brewing.IBUTinseth
+	}
+	hops, notes, err := h.parseHopAdditions(args)
+	if err != nil {
+		return nil, err
+	}
+	weights := make([]float64, 0, len(hops))
+	for _, hop := range hops {
+		weights = append(weights, hop.Weight)
+	}
+	system, inferred, err := inferUnitSystem(ctx, args, batchSize, weights...)
+	if err != nil {
+		return nil, err
+	}
+	for i := range hops {
+		hops[i].Units = brewing.UnitSystem(system)
+	}
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("**IBU Estimate (%s):**\n\n", formula))
+	if inferred && system == unitsMetric {
+		response.WriteString("_Units not given; metric (L and g) assumed from the input values._\n\n")
+	}
+	total := 0.0
+	for i, hop := range hops {
+		ibu, calcErr := hop.CalculateIBUWithFormula(batchSize, og, formula)
+		if calcErr != nil {
+			return nil, calculationError(calcErr)
+		}
+		total += ibu
+		name := hop.Name
+		if name == "" {
+			name = fmt.Sprintf("Hop %d", i+1)
+		}
+		response.WriteString(fmt.Sprintf("- **%s** (%.1f%% AA %s, %s): %.1f IBU\n",
+			name, hop.AlphaAcid, hop.Form, formatHopTiming(hop, system), ibu))
+	}
+	response.WriteString(fmt.Sprintf("\n**Total:** %.1f IBU\n", total))
+	writeCalculatorNotes(&response, notes)
+	return mcp.NewToolResult(response.String()), nil
+}
+
+// parseHopAdditions extracts the required 'hops' array in the caller's units. A hop without an
+// alpha_acid is given the middle of its variety's range when the ingredient data knows its name;
+// the returned notes say which.
+func (h *ToolHandlers) parseHopAdditions(args map[string]interface{}) ([]brewing.HopAddition, []string, error) {
+	items, ok := args["hops"].([]interface{})
+	if !ok || len(items) == 0 {
+		return nil, nil, &mcp.Error{
+			Code:    mcp.InvalidParams,
+			Message: "'hops' parameter must be a non-empty array of hop additions",
+		}
+	}
+
+	hops := make([]brewing.HopAddition, 0, len(items))
+	var notes []string
+	for i, item := range items {
+		hopArgs, isObject := item.(map[string]interface{})
+		if !isObject {
+			return nil, nil, &mcp.Error{
+				Code:    mcp.InvalidParams,
+				Message: fmt.Sprintf("hops[%d] must be an object", i),
+			}
+		}
+		hop := brewing.HopAddition{}
+		hop.Name, _ = hopArgs["name"].(string)
+		hop.DryHop, _ = hopArgs["dry_hop"].(bool)
+		formName, _ := hopArgs["form"].(string)
+		form, err := brewing.ParseHopForm(formName)
+		if err != nil {
+			return nil, nil, &mcp.Error{
+				Code:    mcp.InvalidParams,
+				Message: fmt.Sprintf("hops[%d]: %s", i, err.Error()),
+			}
+		}
+		hop.Form = form
+		alphaAcid, err := parseOptionalFloat(hopArgs, "alpha_acid")
+		if err != nil {
+			return nil, nil, &mcp.Error{
+				Code:    mcp.InvalidParams,
+				Message: fmt.Sprintf("hops[%d]: %s", i, err.Error()),
+			}
+		}
+		if alphaAcid != nil {
+			hop.AlphaAcid = *alphaAcid
+		} else {
+			var note string
+			if hop.AlphaAcid, note, err = h.varietyAlphaAcid(hop.Name, i); err != nil {
+				return nil, nil, err
+			}
+			notes = append(notes, note)
+		}
+		fields := []struct {
+			key   string
+			field *float64
+		}{
+			{"weight", &hop.Weight},
+			{"boil_time", &hop.BoilTime},
+		}
+		for _, f := range fields {
+			value, err := requireFloat(hopArgs, f.key)
+			if err != nil {
+				return nil, nil, &mcp.Error{
+					Code:    mcp.InvalidParams,
+					Message: fmt.Sprintf("hops[%d]: %s", i, err.Error()),
+				}
+			}
+			*f.field = value
+		}
+		steepTemp, err := parseOptionalFloat(hopArgs, "steep_temp")
+		if err != nil {
+			return nil, nil, err
+		}
+		if steepTemp != nil {
+			hop.SteepTemp = *steepTemp
+		}
+		hops = append(hops, hop)
+	}
+	return hops, notes, nil
+}
+
+// varietyAlphaAcid is the alpha acid to assume for hops[i], named name but given no alpha_acid: the
+// middle of the variety's range in the ingredient data, with a note saying so.
+func (h *ToolHandlers) varietyAlphaAcid(name string, i int) (float64, string, error) {
+	if strings.TrimSpace(name) == "" || h.ingredientService == nil {
+		return 0, "", &mcp.Error{
+			Code:    mcp.InvalidParams,
+			Message: fmt.Sprintf("hops[%d]: 'alpha_acid' parameter is required", i),
+		}
+	}
+	variety, err := h.ingredientService.FindHop(name)
+	if err != nil {
+		suggestions := ingredientSuggestions(h.ingredientService, name, data.IngredientHop)
+		message := fmt.Sprintf("hops[%d]: 'alpha_acid' parameter is required, as %s is not a known hop "+
+			"variety", i, name)
+		if len(suggestions) > 0 {
+			message += fmt.Sprintf(" (did you mean %s?)", strings.Join(suggestions, ", "))
+		}
+		return 0, "", &mcp.Error{
+			Code:    mcp.InvalidParams,
+			Message: message,
+			Data:    map[string]interface{}{"name": name, "suggestions": suggestions},
+		}
+	}
+	note := fmt.Sprintf("%s: alpha acid not given; assumed %s%%, the middle of %s's %s-%s%% range.",
+		name, formatNumber(variety.AlphaAcid()), variety.Name,
+		formatNumber(variety.AlphaAcidMin), formatNumber(variety.AlphaAcidMax))
+	return variety.AlphaAcid(), note, nil
+}
+
+func formatHopTiming(hop brewing.HopAddition, system string) string {
+	if hop.DryHop {
+		return "dry hop"
+	}
+	if hop.SteepTemp == 0 {
+		return fmt.Sprintf("%.0f min boil", hop.BoilTime)
+	}
+	if system == unitsMetric {
+		return fmt.Sprintf("%.0f min steep at %.0f°C", hop.BoilTime, hop.SteepTemp)
+	}
+	return fmt.Sprintf("%.0f min steep at %.0f°F", hop.BoilTime, hop.SteepTemp)
+}
+
+// writeCalculatorNotes lists the values a calculator filled in from the ingredient data.
+func writeCalculatorNotes(response *strings.Builder, notes []string) {
+	if len(notes) == 0 {
+		return
+	}
+	response.WriteString("\n")
+	for _, note := range notes {
+		response.WriteString(fmt.Sprintf("_%s_\n", note))
+	}
+}
+
+// SRMCalculator estimates the colour of a beer from its grain bill.
+func (h *ToolHandlers) SRMCalculator(ctx context.Context, args map[string]interface{}) (*mcp.ToolResult, error) {
+	system, err := parseUnitSystem(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	batchSize, err := requireFloat(args, "batch_size")
+	if err != nil {
+		return nil, err
+	}
+	grains, names, notes, err := h.parseColorGrains(args)
+	if err != nil {
+		return nil, err
+	}
+	calc := brewing.ColorCalculation{Grains: grains, BatchSize: batchSize, Units: brewing.UnitSystem(system)}
+	result, err := calc.Calculate()
+	if err != nil {
+		return nil, calculationError(err)
+	}
+
+	weightUnit := units.New(brewing.UnitSystem(system)).WeightUnit()
+	var response strings.Builder
+	response.WriteString("**Colour Estimate (Morey):**\n\n")
+	for i, grain := range grains {
+		response.WriteString(fmt.Sprintf("- **%s** (%s °L): %.2f %s\n",
+			names[i], formatNumber(grain.Lovibond), grain.Weight, weightUnit))
+	}
+	response.WriteString(fmt.Sprintf("\n**Colour:** %.1f SRM (%.1f EBC, %.1f MCU)\n",
+		result.SRM, result.EBC, result.MCU))
+	writeCalculatorNotes(&response, notes)
+	return mcp.NewToolResult(response.String()), nil
+}
+
+// parseColorGrains extracts the required 'fermentables' array and a display name for each. A
+// fermentable without a lovibond takes its colour from the ingredient data when it knows its name;
+// the returned notes say which.
+func (h *ToolHandlers) parseColorGrains(
+	args map[string]interface{},
+) ([]brewing.ColorGrain, []string, []string, error) {
+	items, ok := args["fermentables"].([]interface{})
+	if !ok || len(items) == 0 {
+		return nil, nil, nil, &mcp.Error{
+			Code:    mcp.InvalidParams,
+			Message: "'fermentables' parameter must be a non-empty array of {name, weight, lovibond} objects",
+		}
+	}
+
+	grains := make([]brewing.ColorGrain, 0, len(items))
+	names := make([]string, 0, len(items))
+	var notes []string
+	for i, item := range items {
+		grainArgs, isObject := item.(map[string]interface{})
+		if !isObject {
+			return nil, nil, nil, &mcp.Error{
+				Code:    mcp.InvalidParams,
+				Message: fmt.Sprintf("fermentables[%d] must be an object", i),
+			}
+		}
+		name, _ := grainArgs["name"].(string)
+		name = strings.TrimSpace(name)
+		grain := brewing.ColorGrain{}
+		var err error
+		if grain.Weight, err = requireFloat(grainArgs, "weight"); err != nil {
+			return nil, nil, nil, &mcp.Error{
+				Code:    mcp.InvalidParams,
+				Message: fmt.Sprintf("fermentables[%d]: %s", i, err.Error()),
+			}
+		}
+		lovibond, err := parseOptionalFloat(grainArgs, "lovibond")
+		if err != nil {
+			return nil, nil, nil, &mcp.Error{
+				Code:    mcp.InvalidParams,
+				Message: fmt.Sprintf("fermentables[%d]: %s", i, err.Error()),
+			}
+		}
+		if lovibond != nil {
+			grain.Lovibond = *lovibond
+		} else {
+			fermentable, lookupErr := h.findFermentable(name, i, "lovibond")
+			if lookupErr != nil {
+				return nil, nil, nil, lookupErr
+			}
+			grain.Lovibond = fermentable.Lovibond
+			notes = append(notes, fmt.Sprintf("%s: colour not given; used %s °L for %s.",
+				name, formatNumber(fermentable.Lovibond), fermentable.Name))
+		}
+		if name == "" {
+			name = fmt.Sprintf("Fermentable %d", i+1)
+		}
+		grains = append(grains, grain)
+		names = append(names, name)
+	}
+	return grains, names, notes, nil
+}
+
+// findFermentable finds the fermentable named name for fermentables[i], which gave no value for
+// param, such as "lovibond".
+func (h *ToolHandlers) findFermentable(name string, i int, param string) (*data.Fermentable, error) {
+	if name == "" || h.ingredientService == nil {
+		return nil, &mcp.Error{
+			Code:    mcp.InvalidParams,
+			Message: fmt.Sprintf("fermentables[%d]: '%s' parameter is required", i, param),
+		}
+	}
+	fermentable, err := h.ingredientService.FindFermentable(name)
+	if err != nil {
+		suggestions := ingredientSuggestions(h.ingredientService, name, data.IngredientFermentable)
+		message := fmt.Sprintf("fermentables[%d]: '%s' parameter is required, as %s is not a known "+
+			"fermentable", i, param, name)
+		if len(suggestions) > 0 {
+			message += fmt.Sprintf(" (did you mean %s?)", strings.Join(suggestions, ", "))
+		}
+		return nil, &mcp.Error{
+			Code:    mcp.InvalidParams,
+			Message: message,
+			Data:    map[string]interface{}{"name": name, "suggestions": suggestions},
+		}
+	}
+	return fermentable, nil
+}
+
+const (
+	volumeCalculationBoil     = "boil"
+	volumeCalculationDilution = "dilution"
+	volumeCalculationBlend    = "blend"
+)
+
+// VolumeCalculator plans boil volumes, dilutions, and blends.
+func (h *ToolHandlers) VolumeCalculator(ctx context.Context, args map[string]interface{}) (*mcp.ToolResult, error) {
+	system, err := parseUnitSystem(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	volumeUnit := units.New(brewing.UnitSystem(system)).VolumeUnit()
+
+	calculation, _ := args["calculation"].(string)
+	var text string
+	switch strings.ToLower(strings.TrimSpace(calculation)) {
+	case "", volumeCalculationBoil:
+		text, err = boilVolumePlan(args, system)
+	case volumeCalculationDilution:
+		text, err = dilutionPlan(args, volumeUnit)
+	case volumeCalculationBlend:
+		text, err = blendPlan(args, volumeUnit)
+	default:
+		return nil, &mcp.Error{
+			Code:    mcp.InvalidParams,
+			Message: "calculation must be 'boil', 'dilution', or 'blend'",
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return mcp.NewToolResult(text), nil
+}
+
+func boilVolumePlan(args map[string]interface{}, system string) (string, error) {
+	calc := brewing.BoilVolumeCalculation{ShrinkagePercent: brewing.DefaultShrinkagePercent}
+	var err error
+	if calc.TargetVolume, err = requireFloat(args, "target_volume"); err != nil {
+		return "", err
+	}
+	rate, err := requireFloat(args, "evaporation_rate")
+	if err != nil {
+		return "", err
+	}
+	evaporationUnit, _ := args["evaporation_unit"].(string)
+	switch strings.ToLower(strings.TrimSpace(evaporationUnit)) {
+	case "", "percent", "%":
+		calc.EvaporationPercent = rate
+	case "volume":
+		calc.EvaporationVolume = rate
+	default:
+		return "", &mcp.Error{
+			Code:    mcp.InvalidParams,
+			Message: "evaporation_unit must be 'percent' or 'volume'",
+		}
+	}
+	fields := []struct {
+		key   string
+		field *float64
+	}{
+		{"boil_time", &calc.BoilTime},
+		{"shrinkage", &calc.ShrinkagePercent},
+		{"trub_loss", &calc.TrubLoss},
+	}
+	for _, f := range fields {
+		value, parseErr := parseOptionalFloat(args, f.key)
+		if parseErr != nil {
+			return "", parseErr
+		}
+		if value != nil {
+			*f.field = *value
+		}
+	}
+
+	volume := units.New(brewing.UnitSystem(system)).Volume
+	if system == unitsMetric {
+		calc.TargetVolume /= brewing.LitersPerGallon
+		calc.EvaporationVolume /= brewing.LitersPerGallon
+		calc.TrubLoss /= brewing.LitersPerGallon
+	}
+
+	result, err := calc.Calculate()
+	if err != nil {
+		return "", calculationError(err)
+	}
+
+	var response strings.Builder
+	response.WriteString("**Boil Volume Plan:**\n\n")
+	response.WriteString(fmt.Sprintf("- **Pre-Boil Volume:** %s\n", volume(result.PreBoilVolume)))
+	response.WriteString(fmt.Sprintf("- **Evaporation:** %s\n", volume(result.Evaporation)))
+	response.WriteString(fmt.Sprintf("- **Post-Boil Volume (hot):** %s\n", volume(result.PostBoilVolume)))
+	response.WriteString(fmt.Sprintf("- **Cooling Shrinkage:** %s\n", volume(result.Shrinkage)))
+	response.WriteString(fmt.Sprintf("- **Trub Loss:** %s\n", volume(result.TrubLoss)))
+	response.WriteString(fmt.Sprintf("- **Into Fermenter:** %s\n", volume(result.FermenterVolume)))
+	return response.String(), nil
+}
+
+func dilutionPlan(args map[string]interface{}, volumeUnit string) (string, error) {
+	calc := brewing.DilutionCalculation{}
+	var err error
+	if calc.Wort.Volume, err = requireFloat(args, "volume"); err != nil {
+		return "", err
+	}
+	if calc.Wort.Gravity, err = requireFloat(args, "gravity"); err != nil {
+		return "", err
+	}
+	if calc.TargetGravity, err = requireFloat(args, "target_gravity"); err != nil {
+		return "", err
+	}
+
+	result, err := calc.Calculate()
+	if err != nil {
+		return "", calculationError(err)
+	}
+	return fmt.Sprintf("**Dilution:** add %.2f %s of water to %.2f %s at %.3f to reach %.2f %s at %.3f",
+		result.WaterToAdd, volumeUnit, calc.Wort.Volume, volumeUnit, calc.Wort.Gravity,
+		result.Final.Volume, volumeUnit, result.Final.Gravity), nil
+}
+
+func blendPlan(args map[string]interface{}, volumeUnit string) (string, error) {
+	items, ok := args["worts"].([]interface{})
+	if !ok {
+		return "", &mcp.Error{
+			Code:    mcp.InvalidParams,
+			Message: "'worts' parameter must be an array of {volume, gravity} objects",
+		}
+	}
+	worts := make([]brewing.Wort, 0, len(items))
+	for i, item := range items {
+		wortArgs, isObject := item.(map[string]interface{})
+		if !isObject {
+			return "", &mcp.Error{
+				Code:    mcp.InvalidParams,
+				Message: fmt.Sprintf("worts[%d] must be an object", i),
+			}
+		}
+		wort := brewing.Wort{}
+		var err error
+		if wort.Volume, err = requireFloat(wortArgs, "volume"); err != nil {
+			return "", &mcp.Error{Code: mcp.InvalidParams, Message: fmt.Sprintf("worts[%d]: %s", i, err.Error())}
+		}
+		if wort.Gravity, err = requireFloat(wortArgs, "gravity"); err != nil {
+			return "", &mcp.Error{Code: mcp.InvalidParams, Message: fmt.Sprintf("worts[%d]: %s", i, err.Error())}
+		}
+		worts = append(worts, wort)
+	}
+
+	blend, err := brewing.BlendWorts(worts...)
+	if err != nil {
+		return "", calculationError(err)
+	}
+	return fmt.Sprintf("**Blend:** %d worts combine to %.2f %s at %.3f",
+		len(worts), blend.Volume, volumeUnit, blend.Gravity), nil
+}
+
+// ABVCalculator reports alcohol content and calories for a beer.
+func (h *ToolHandlers) ABVCalculator(ctx context.Context, args map[string]interface{}) (*mcp.ToolResult, error) {
+	system, err := parseUnitSystem(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	calc, err := parseGravities(args)
+	if err != nil {
+		return nil, err
+	}
+	servingOz := 12.0
+	serving, err := parseOptionalFloat(args, "serving_size")
+	if err != nil {
+		return nil, err
+	}
+	if serving != nil {
+		servingOz = *serving
+		if system == unitsMetric {
+			servingOz = *serving / brewing.MillilitersPerFluidOunce
+		}
+	}
+
+	abv, err := calc.SimpleABV()
+	if err != nil {
+		return nil, calculationError(err)
+	}
+	alternate, _ := calc.AlternateABV()
+	abw, _ := calc.ABW()
+	calories, err := calc.Calories(servingOz)
+	if err != nil {
+		return nil, calculationError(err)
+	}
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("**Alcohol Content (%.3f → %.3f):**\n\n", calc.OriginalGravity, calc.FinalGravity))
+	response.WriteString(fmt.Sprintf("- **ABV:** %.2f%%\n", abv))
+	response.WriteString(fmt.Sprintf("- **ABV (alternate formula):** %.2f%%\n", alternate))
+	response.WriteString(fmt.Sprintf("- **ABW:** %.2f%%\n", abw))
+	response.WriteString(fmt.Sprintf("- **Calories:** %.0f kcal per %s\n", calories,
+		units.New(brewing.UnitSystem(system)).Serving(servingOz)))
+	return mcp.NewToolResult(response.String()), nil
+}
+
+// AttenuationCalculator reports how well a beer fermented out.
+func (h *ToolHandlers) AttenuationCalculator(_ context.Context, args map[string]interface{}) (*mcp.ToolResult, error) {
+	calc, err := parseGravities(args)
+	if err != nil {
+		return nil, err
+	}
+	result, err := brewing.AttenuationCalculation(calc).Calculate()
+	if err != nil {
+		return nil, calculationError(err)
+	}
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("**Attenuation (%.3f → %.3f):**\n\n", calc.OriginalGravity, calc.FinalGravity))
+	response.WriteString(fmt.Sprintf("- **Apparent Attenuation:** %.1f%%\n", result.ApparentAttenuation))
+	response.WriteString(fmt.Sprintf("- **Real Attenuation:** %.1f%%\n", result.RealAttenuation))
+	response.WriteString(fmt.Sprintf("- **Real Extract:** %.1f °P\n", result.RealExtract))
+	response.WriteString(fmt.Sprintf("- **ABV:** %.2f%%\n", result.ABV))
+	response.WriteString(fmt.Sprintf("- **ABW:** %.2f%%\n", result.ABW))
+	response.WriteString(fmt.Sprintf("- **Calories:** %.0f kcal per 12 oz\n", result.CaloriesPer12Oz))
+	return mcp.NewToolResult(response.String()), nil
+}
+
+// parseGravities extracts the required original and final gravity arguments.
+func parseGravities(args map[string]interface{}) (brewing.ABVCalculation, error) {
+	calc := brewing.ABVCalculation{}
+	var err error
+	if calc.OriginalGravity, err = requireFloat(args, "original_gravity"); err != nil {
+		return calc, err
+	}
+	if calc.FinalGravity, err = requireFloat(args, "final_gravity"); err != nil {
+		return calc, err
+	}
+	return calc, nil
+}
+
+// YeastStarter grows an initial cell count through a starter, planning the steps when none are given.
+func (h *ToolHandlers) YeastStarter(ctx context.Context, args map[string]interface{}) (*mcp.ToolResult, error) {
+	batchVolume, err := parseOptionalFloat(args, "batch_volume")
+	if err != nil {
+		return nil, err
+	}
+	volumeHint := 0.0
+	if batchVolume != nil {
+		volumeHint = *batchVolume
+	}
+	system, _, err := inferUnitSystem(ctx, args, volumeHint)
+	if err != nil {
+		return nil, err
+	}
+	initialCells, err := requireFloat(args, "initial_cells")
+	if err != nil {
+		return nil, err
+	}
+	target, err := parsePitchTarget(args, system)
+	if err != nil {
+		return nil, err
+	}
+	modelName, _ := args["model"].(string)
+	model := brewing.StarterModel(strings.ToLower(strings.TrimSpace(modelName)))
+
+	var results []brewing.StarterStepResult
+	if _, given := args["steps"]; given {
+		steps, stepsErr := parseStarterSteps(args)
+		if stepsErr != nil {
+			return nil, stepsErr
+		}
+		results, err = brewing.StarterCalculation{InitialCells: initialCells, Steps: steps, Model: model}.Calculate()
+	} else {
+		if target == nil {
+			return nil, &mcp.Error{
+				Code:    mcp.InvalidParams,
+				Message: "provide 'steps', 'target_cells', or 'batch_volume' and 'original_gravity'",
+			}
+		}
+		results, err = brewing.PlanStarter(initialCells, *target, model)
+	}
+	if err != nil {
+		return nil, calculationError(err)
+	}
+
+	return mcp.NewToolResult(formatStarter(initialCells, target, results)), nil
+}
+
+// parsePitchTarget reads target_cells, or derives it from the batch volume and gravity.
+func parsePitchTarget(args map[string]interface{}, system string) (*float64, error) {
+	target, err := parseOptionalFloat(args, "target_cells")
+	if err != nil || target != nil {
+		return target, err
+	}
+	batchVolume, err := parseOptionalFloat(args, "batch_volume")
+	if err != nil || batchVolume == nil {
+		return nil, err
+	}
+	og, err := requireFloat(args, "original_gravity")
+	if err != nil {
+		return nil, err
+	}
+
+	rate := brewing.YeastPitchingRate{
+		BatchVolume:     *batchVolume,
+		OriginalGravity: og,
+		Units:           brewing.UnitSystem(system),
+	}
+	yeastType, _ := args["yeast_type"].(string)
+	switch strings.ToLower(strings.TrimSpace(yeastType)) {
+	case "", "ale":
+		rate.PitchRate = brewing.PitchRateAle
+	case "hybrid":
+		rate.PitchRate = brewing.PitchRateHybrid
+	case "lager":
+		rate.PitchRate = brewing.PitchRateLager
+	default:
+		return nil, &mcp.Error{
+			Code:    mcp.InvalidParams,
+			Message: "yeast_type must be 'ale', 'hybrid', or 'lager'",
+		}
+	}
+	cells, err := rate.TargetCells()
+	if err != nil {
+		return nil, calculationError(err)
+	}
+	return &cells, nil
+}
+
+// parseStarterSteps extracts the 'steps' array.
+func parseStarterSteps(args map[string]interface{}) ([]brewing.StarterStep, error) {
+	items, ok := args["steps"].([]interface{})
+	if !ok {
+		return nil, &mcp.Error{
+			Code:    mcp.InvalidParams,
+			Message: "'steps' parameter must be an array of {volume, gravity} objects",
+		}
+	}
+	steps := make([]brewing.StarterStep, 0, len(items))
+	for i, item := range items {
+		stepArgs, isObject := item.(map[string]interface{})
+		if !isObject {
+			return nil, &mcp.Error{
+				Code:    mcp.InvalidParams,
+				Message: fmt.Sprintf("steps[%d] must be an object", i),
+			}
+		}
+		step := brewing.StarterStep{}
+		volume, err := requireFloat(stepArgs, "volume")
+		if err != nil {
+			return nil, &mcp.Error{Code: mcp.InvalidParams, Message: fmt.Sprintf("steps[%d]: %s", i, err.Error())}
+		}
+		step.Volume = volume
+		gravity, err := parseOptionalFloat(stepArgs, "gravity")
+		if err != nil {
+			return nil, err
+		}
+		if gravity != nil {
+			step.Gravity = *gravity
+		}
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
+
+func formatStarter(initialCells float64, target *float64, results []brewing.StarterStepResult) string {
+	var response strings.Builder
+	response.WriteString("**Yeast Starter Plan:**\n\n")
+	response.WriteString(fmt.Sprintf("- **Initial Cells:** %.0f billion\n", initialCells))
+	if target != nil {
+		response.WriteString(fmt.Sprintf("- **Pitch Target:** %.0f billion\n", *target))
+	}
+	finalCells := initialCells
+	for i, step := range results {
+		response.WriteString(fmt.Sprintf("- **Step %d:** %.1f L at %.3f → %.0f billion cells\n",
+			i+1, step.Volume, step.Gravity, step.EndCells))
+		finalCells = step.EndCells
+	}
+	if len(results) == 0 {
+		response.WriteString("\nNo starter needed: the initial cells already meet the pitch target.\n")
+		return response.String()
+	}
+	response.WriteString(fmt.Sprintf("\n**Final Cell Count:** %.0f billion", finalCells))
+	if target != nil {
+		if finalCells >= *target {
+			response.WriteString(" (meets target)")
+		} else {
+			response.WriteString(fmt.Sprintf(" (%.0f billion short of target)", *target-finalCells))
+		}
+	}
+	response.WriteString("\n")
+	return response.String()
+}
+
+// WaterProfile calculates the salt additions that bring source water closest to a target profile.
+func (h *ToolHandlers) WaterProfile(ctx context.Context, args map[string]interface{}) (*mcp.ToolResult, error) {
+	system, err := parseUnitSystem(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	volume, err := requireFloat(args, "volume")
+	if err != nil {
+		return nil, err
+	}
+	volumeLiters := volume
+	volumeLabel := fmt.Sprintf("%.1f L", volume)
+	if system == unitsImperial {
+		volumeLiters = volume * brewing.LitersPerGallon
+		volumeLabel = fmt.Sprintf("%.1f gal", volume)
+	}
+
+	source, err := parseWaterChemistry(args)
+	if err != nil {
+		return nil, err
+	}
+	targetName, target, err := parseWaterTarget(args)
+	if err != nil {
+		return nil, err
+	}
+	result, err := source.SaltAdditions(target, volumeLiters)
+	if err != nil {
+		return nil, calculationError(err)
+	}
+
+	text := formatWaterProfile(source, targetName, volumeLabel, result)
+	if _, given := args["grains"]; given {
+		mashPH, mashErr := parseMashPHCalculation(args, system)
+		if mashErr != nil {
+			return nil, mashErr
+		}
+		mashPH.Water = result.Achieved
+		estimate, mashErr := mashPH.Calculate()
+		if mashErr != nil {
+			return nil, calculationError(mashErr)
+		}
+		text += formatMashPH(estimate)
+	}
+	return mcp.NewToolResult(text), nil
+}
+
+// parseMashPHCalculation extracts the grain bill, mash thickness, and target pH.
+func parseMashPHCalculation(args map[string]interface{}, system string) (brewing.MashPHCalculation, error) {
+	calc := brewing.MashPHCalculation{Units: brewing.UnitSystem(system)}
+	items, ok := args["grains"].([]interface{})
+	if !ok {
+		return calc, &mcp.Error{
+			Code:    mcp.InvalidParams,
+			Message: "'grains' parameter must be an array of {weight, lovibond} objects",
+		}
+	}
+	for i, item := range items {
+		grainArgs, isObject := item.(map[string]interface{})
+		if !isObject {
+			return calc, &mcp.Error{
+				Code:    mcp.InvalidParams,
+				Message: fmt.Sprintf("grains[%d] must be an object", i),
+			}
+		}
+		grain := brewing.MashGrain{}
+		var err error
+		if grain.Weight, err = requireFloat(grainArgs, "weight"); err != nil {
+			return calc, &mcp.Error{Code: mcp.InvalidParams, Message: fmt.Sprintf("grains[%d]: %s", i, err.Error())}
+		}
+		if grain.Lovibond, err = requireFloat(grainArgs, "lovibond"); err != nil {
+			return calc, &mcp.Error{Code: mcp.InvalidParams, Message: fmt.Sprintf("grains[%d]: %s", i, err.Error())}
+		}
+		grainType, _ := grainArgs["type"].(string)
+		grain.Type = brewing.GrainType(strings.ToLower(strings.TrimSpace(grainType)))
+		calc.Grains = append(calc.Grains, grain)
+	}
+
+	ratio, err := parseOptionalFloat(args, "water_to_grist_ratio")
+	if err != nil {
+		return calc, err
+	}
+	switch {
+	case ratio != nil:
+		calc.WaterToGristRatio = *ratio
+	case system == unitsMetric:
+		calc.WaterToGristRatio = defaultWaterToGristRatio / litersPerKgToQuartsPerPound(1)
+	default:
+		calc.WaterToGristRatio = defaultWaterToGristRatio
+	}
+	targetPH, err := parseOptionalFloat(args, "target_ph")
+	if err != nil {
+		return calc, err
+	}
+	if targetPH != nil {
+		calc.TargetPH = *targetPH
+	}
+	return calc, nil
+}
+
+func formatMashPH(result *brewing.MashPHResult) string {
+	var response strings.Builder
+	response.WriteString("\n**Mash pH Estimate:**\n\n")
+	response.WriteString(fmt.Sprintf("- **Grist in Distilled Water:** %.2f\n", result.DistilledWaterPH))
+	response.WriteString(fmt.Sprintf("- **Estimated Mash pH:** %.2f (target %.2f)\n",
+		result.EstimatedPH, result.TargetPH))
+	if result.AcidMEq == 0 {
+		response.WriteString("- **Acid Addition:** none needed\n")
+	} else {
+		response.WriteString(fmt.Sprintf(
+			"- **Acid Addition:** %.1f ml of 88%% lactic acid or %.1f ml of 10%% phosphoric acid\n",
+			result.LacticAcidML, result.PhosphoricAcidML))
+	}
+	response.WriteString("\n_Estimated from grain colour and residual alkalinity; confirm with a pH meter._\n")
+	return response.String()
+}
+
+// parseWaterChemistry reads optional ion concentrations, defaulting each to zero.
+func parseWaterChemistry(args map[string]interface{}) (brewing.WaterChemistry, error) {
+	water := brewing.WaterChemistry{}
+	fields := []struct {
+		key   string
+		field *float64
+	}{
+		{"calcium", &water.Calcium},
+		{"magnesium", &water.Magnesium},
+		{"sodium", &water.Sodium},
+		{"sulfate", &water.Sulfate},
+		{"chloride", &water.Chloride},
+		{"bicarbonate", &water.Bicarbonate},
+	}
+	for _, f := range fields {
+		value, err := parseOptionalFloat(args, f.key)
+		if err != nil {
+			return water, err
+		}
+		if value != nil {
+			*f.field = *value
+		}
+	}
+	return water, nil
+}
+
+// parseWaterTarget resolves either a named preset or a custom target_profile object.
+func parseWaterTarget(args map[string]interface{}) (string, brewing.WaterChemistry, error) {
+	if custom, given := args["target_profile"]; given {
+		profileArgs, ok := custom.(map[string]interface{})
+		if !ok {
+			return "", brewing.WaterChemistry{}, &mcp.Error{
+				Code:    mcp.InvalidParams,
+				Message: "'target_profile' parameter must be an object of ion concentrations",
+			}
+		}
+		target, err := parseWaterChemistry(profileArgs)
+		return "custom", target, err
+	}
+
+	name, err := requireString(args, "target")
+	if err != nil {
+		return "", brewing.WaterChemistry{}, &mcp.Error{
+			Code:    mcp.InvalidParams,
+			Message: "provide a 'target' preset or a 'target_profile'",
+			Data:    map[string]interface{}{"available_profiles": brewing.WaterProfileNames()},
+		}
+	}
+	target, err := brewing.LookupWaterProfile(name)
+	if err != nil {
+		return "", brewing.WaterChemistry{}, &mcp.Error{
+			Code:    mcp.InvalidParams,
+			Message: err.Error(),
+			Data:    map[string]interface{}{"available_profiles": brewing.WaterProfileNames()},
+		}
+	}
+	return strings.ToLower(strings.TrimSpace(name)), target, nil
+}
+
+func formatWaterProfile(
+	source brewing.WaterChemistry, targetName, volumeLabel string, result *brewing.SaltAdditionResult,
+) string {
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("**Water Adjustment (%s target, %s):**\n\n", targetName, volumeLabel))
+	response.WriteString(fmt.Sprintf("- **Source Residual Alkalinity:** %.0f ppm as CaCO3\n",
+		source.ResidualAlkalinity()))
+	response.WriteString(fmt.Sprintf("- **Source Sulfate:Chloride:** %.2f\n", source.SulfateChlorideRatio()))
+
+	response.WriteString("\n**Salt Additions:**\n\n")
+	if len(result.Additions) == 0 {
+		response.WriteString("- None: salts cannot move this water closer to the target\n")
+	}
+	for _, addition := range result.Additions {
+		response.WriteString(fmt.Sprintf("- **%s:** %.2f g\n", addition.Salt, addition.Grams))
+	}
+
+	response.WriteString("\n**Resulting Profile (ppm):**\n\n")
+	for _, ion := range result.Ions {
+		line := fmt.Sprintf("- **%s:** %.0f (target %.0f, %+.0f)", ion.Ion, ion.Achieved, ion.Target, ion.Residual)
+		if ion.Infeasible {
+			line += " - source already exceeds target; dilute with RO water"
+		}
+		response.WriteString(line + "\n")
+	}
+	response.WriteString(fmt.Sprintf("\n- **Residual Alkalinity:** %.0f ppm as CaCO3\n",
+		result.Achieved.ResidualAlkalinity()))
+	response.WriteString(fmt.Sprintf("- **Sulfate:Chloride:** %.2f\n", result.Achieved.SulfateChlorideRatio()))
+	return response.String()
+}
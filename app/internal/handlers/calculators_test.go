@@ -0,0 +1,1219 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/handlers"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/mcp"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/units"
+	"github.com/CharlRitter/brewsource-mcp/app/pkg/brewing"
+)
+
+// callCalculator invokes a calculator tool through a server so registration is exercised too.
+func callCalculator(t *testing.T, tool string, args map[string]interface{}) (*mcp.ToolResult, *mcp.Error) {
+	t.Helper()
+	toolHandlers := handlers.NewToolHandlers(nil, nil, nil)
+	server := mcp.NewServer(toolHandlers, nil, nil)
+
+	msg := mcp.NewMessage("tools/call", mcp.CallToolRequest{Name: tool, Arguments: args})
+	msgData, err := json.Marshal(msg)
+	if err != nil {
+		t.Fatalf("failed to marshal tool request: %v", err)
+	}
+	response := server.ProcessMessage(context.Background(), msgData)
+	if response.Error != nil {
+		return nil, response.Error
+	}
+	result, ok := response.Result.(*mcp.ToolResult)
+	if !ok {
+		t.Fatalf("expected *mcp.ToolResult, got %T", response.Result)
+	}
+	return result, nil
+}
+
+func expectInvalidParams(t *testing.T, mcpErr *mcp.Error, wantContains string) {
+	t.Helper()
+	if mcpErr == nil {
+		t.Fatal("expected an error, got none")
+	}
+	if mcpErr.Code != mcp.InvalidParams {
+		t.Errorf("expected InvalidParams, got %d", mcpErr.Code)
+	}
+	if !strings.Contains(mcpErr.Message, wantContains) {
+		t.Errorf("expected error to contain %q, got %q", wantContains, mcpErr.Message)
+	}
+}
+
+func TestUnitConvert_HappyPath(t *testing.T) {
+	tests := []struct {
+		name string
+		args map[string]interface{}
+		want string
+	}{
+		{
+			name: "gallons to liters",
+			args: map[string]interface{}{"value": 5.0, "from_unit": "gal", "to_unit": "l"},
+			want: "5.00 gal = 18.93 l",
+		},
+		{
+			name: "gravity to plato",
+			args: map[string]interface{}{"value": "1.048", "from_unit": "SG", "to_unit": "°P"},
+			want: "1.048 sg = 11.9 plato",
+		},
+		{
+			name: "psi to volumes with celsius temperature",
+			args: map[string]interface{}{
+				"value": 12.0, "from_unit": "psi", "to_unit": "volumes",
+				"temperature": 3.0, "temperature_unit": "c",
+			},
+			want: "12.0 psi = 2.60 volumes",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, mcpErr := callCalculator(t, "unit_convert", tt.args)
+			if mcpErr != nil {
+				t.Fatalf("unexpected error: %v", mcpErr)
+			}
+			if !strings.Contains(result.Content[0].Text, tt.want) {
+				t.Errorf("expected %q in %q", tt.want, result.Content[0].Text)
+			}
+		})
+	}
+}
+
+func TestUnitConvert_InvalidParams(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         map[string]interface{}
+		wantContains string
+	}{
+		{
+			name:         "missing value",
+			args:         map[string]interface{}{"from_unit": "gal", "to_unit": "l"},
+			wantContains: "'value' parameter is required",
+		},
+		{
+			name:         "missing to_unit",
+			args:         map[string]interface{}{"value": 1.0, "from_unit": "gal"},
+			wantContains: "'to_unit' parameter is required",
+		},
+		{
+			name:         "mismatched dimensions",
+			args:         map[string]interface{}{"value": 1.0, "from_unit": "gal", "to_unit": "srm"},
+			wantContains: "supported units:",
+		},
+		{
+			name:         "psi without temperature",
+			args:         map[string]interface{}{"value": 12.0, "from_unit": "psi", "to_unit": "volumes"},
+			wantContains: "temperature is required",
+		},
+		{
+			name: "invalid temperature unit",
+			args: map[string]interface{}{
+				"value": 12.0, "from_unit": "psi", "to_unit": "volumes", "temperature": 38.0, "temperature_unit": "k",
+			},
+			wantContains: "temperature_unit must be",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, mcpErr := callCalculator(t, "unit_convert", tt.args)
+			expectInvalidParams(t, mcpErr, tt.wantContains)
+		})
+	}
+}
+
+func TestUnitConvert_SupportedUnitsInErrorData(t *testing.T) {
+	toolHandlers := handlers.NewToolHandlers(nil, nil, nil)
+	_, err := toolHandlers.UnitConvert(context.Background(), map[string]interface{}{
+		"value": 1.0, "from_unit": "furlongs", "to_unit": "gal",
+	})
+	var mcpErr *mcp.Error
+	if !errors.As(err, &mcpErr) {
+		t.Fatalf("expected *mcp.Error, got %v", err)
+	}
+	dataMap, ok := mcpErr.Data.(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected error data map, got %T", mcpErr.Data)
+	}
+	if _, ok := dataMap["supported_units"]; !ok {
+		t.Error("expected supported_units in error data")
+	}
+}
+
+func TestMashWater_Imperial(t *testing.T) {
+	result, mcpErr := callCalculator(t, "mash_water", map[string]interface{}{
+		"grain_weight":         10.0,
+		"grain_temp":           70.0,
+		"target_temp":          152.0,
+		"water_to_grist_ratio": 1.5,
+		"sparge_volume":        4.0,
+	})
+	if mcpErr != nil {
+		t.Fatalf("unexpected error: %v", mcpErr)
+	}
+	text := result.Content[0].Text
+	for _, want := range []string{
+		"Strike Temperature:** 162.9°F", "Strike Volume:** 3.75 gal", "Pre-Boil Volume:** 6.50 gal",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in %q", want, text)
+		}
+	}
+}
+
+func TestMashWater_MetricStepMash(t *testing.T) {
+	result, mcpErr := callCalculator(t, "mash_water", map[string]interface{}{
+		"grain_weight": 4.5,
+		"grain_temp":   20.0,
+		"target_temp":  63.0,
+		"step_temps":   []interface{}{72.0},
+		"units":        "metric",
+	})
+	if mcpErr != nil {
+		t.Fatalf("unexpected error: %v", mcpErr)
+	}
+	text := result.Content[0].Text
+	for _, want := range []string{"°C", " L", "Infusion 1:", "to reach 72.0°C"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in %q", want, text)
+		}
+	}
+}
+
+func TestMashWater_InvalidParams(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         map[string]interface{}
+		wantContains string
+	}{
+		{
+			name:         "missing grain weight",
+			args:         map[string]interface{}{"grain_temp": 70.0, "target_temp": 152.0},
+			wantContains: "'grain_weight' parameter is required",
+		},
+		{
+			name:         "zero grain weight",
+			args:         map[string]interface{}{"grain_weight": 0.0, "grain_temp": 70.0, "target_temp": 152.0},
+			wantContains: "grain weight must be greater than zero",
+		},
+		{
+			name: "negative ratio",
+			args: map[string]interface{}{
+				"grain_weight": 10.0, "grain_temp": 70.0, "target_temp": 152.0, "water_to_grist_ratio": -1.0,
+			},
+			wantContains: "water to grist ratio must be greater than zero",
+		},
+		{
+			name: "step temps not an array",
+			args: map[string]interface{}{
+				"grain_weight": 10.0, "grain_temp": 70.0, "target_temp": 152.0, "step_temps": "158",
+			},
+			wantContains: "step_temps must be an array of numbers",
+		},
+		{
+			name: "unknown unit system",
+			args: map[string]interface{}{
+				"grain_weight": 10.0, "grain_temp": 70.0, "target_temp": 152.0, "units": "cubits",
+			},
+			wantContains: "units must be 'imperial' or 'metric'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, mcpErr := callCalculator(t, "mash_water", tt.args)
+			expectInvalidParams(t, mcpErr, tt.wantContains)
+		})
+	}
+}
+
+func TestCarbonationCalculator_HappyPath(t *testing.T) {
+	tests := []struct {
+		name string
+		args map[string]interface{}
+		want []string
+	}{
+		{
+			name: "priming with corn sugar",
+			args: map[string]interface{}{"beer_volume": 5.0, "beer_temp": 68.0, "target_co2_volumes": 2.5},
+			want: []string{"Residual CO2:** 0.86 volumes", "Priming Sugar (corn sugar):** 124.5 g (4.39 oz)"},
+		},
+		{
+			name: "keg force carbonation",
+			args: map[string]interface{}{
+				"beer_volume": 5.0, "beer_temp": 38.0, "target_co2_volumes": 2.5, "method": "keg",
+			},
+			want: []string{"Regulator Pressure:**", "psi"},
+		},
+		{
+			name: "metric priming with honey",
+			args: map[string]interface{}{
+				"beer_volume": 19.0, "beer_temp": 20.0, "target_co2_volumes": 2.4,
+				"sugar_type": "honey", "units": "metric",
+			},
+			want: []string{"19.00 L at 20.0°C", "Priming Sugar (honey):**"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, mcpErr := callCalculator(t, "carbonation_calculator", tt.args)
+			if mcpErr != nil {
+				t.Fatalf("unexpected error: %v", mcpErr)
+			}
+			text := result.Content[0].Text
+			for _, want := range tt.want {
+				if !strings.Contains(text, want) {
+					t.Errorf("expected %q in %q", want, text)
+				}
+			}
+		})
+	}
+}
+
+func TestCarbonationCalculator_InvalidParams(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         map[string]interface{}
+		wantContains string
+	}{
+		{
+			name:         "missing volume",
+			args:         map[string]interface{}{"beer_temp": 68.0, "target_co2_volumes": 2.5},
+			wantContains: "'beer_volume' parameter is required",
+		},
+		{
+			name:         "target below range",
+			args:         map[string]interface{}{"beer_volume": 5.0, "beer_temp": 68.0, "target_co2_volumes": 0.2},
+			wantContains: "between 0.5 and 5.0",
+		},
+		{
+			name:         "target above range",
+			args:         map[string]interface{}{"beer_volume": 5.0, "beer_temp": 68.0, "target_co2_volumes": 6.0},
+			wantContains: "between 0.5 and 5.0",
+		},
+		{
+			name: "unknown sugar",
+			args: map[string]interface{}{
+				"beer_volume": 5.0, "beer_temp": 68.0, "target_co2_volumes": 2.5, "sugar_type": "maple",
+			},
+			wantContains: "unknown sugar type",
+		},
+		{
+			name: "unknown method",
+			args: map[string]interface{}{
+				"beer_volume": 5.0, "beer_temp": 68.0, "target_co2_volumes": 2.5, "method": "cask",
+			},
+			wantContains: "method must be 'priming' or 'keg'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, mcpErr := callCalculator(t, "carbonation_calculator", tt.args)
+			expectInvalidParams(t, mcpErr, tt.wantContains)
+		})
+	}
+}
+
+func TestRefractometerCorrection_Tool(t *testing.T) {
+	tests := []struct {
+		name string
+		args map[string]interface{}
+		want []string
+	}{
+		{
+			name: "original gravity only",
+			args: map[string]interface{}{"original_brix": 12.0, "wort_correction_factor": 1.0},
+			want: []string{"Original Gravity:** 1.048"},
+		},
+		{
+			name: "corrected final gravity",
+			args: map[string]interface{}{"original_brix": 20.0, "final_brix": 10.0},
+			want: []string{"Original Gravity:** 1.080", "Final Gravity:** 1.016", "ABV:** 8.4%"},
+		},
+		{
+			name: "linear formula",
+			args: map[string]interface{}{"original_brix": 20.0, "final_brix": 10.0, "formula": "linear"},
+			want: []string{"Final Gravity:** 1.017"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, mcpErr := callCalculator(t, "refractometer_correction", tt.args)
+			if mcpErr != nil {
+				t.Fatalf("unexpected error: %v", mcpErr)
+			}
+			text := result.Content[0].Text
+			for _, want := range tt.want {
+				if !strings.Contains(text, want) {
+					t.Errorf("expected %q in %q", want, text)
+				}
+			}
+		})
+	}
+}
+
+func TestRefractometerCorrection_InvalidParams(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         map[string]interface{}
+		wantContains string
+	}{
+		{
+			name:         "missing original",
+			args:         map[string]interface{}{"final_brix": 6.0},
+			wantContains: "'original_brix' parameter is required",
+		},
+		{
+			name:         "final exceeds original",
+			args:         map[string]interface{}{"original_brix": 8.0, "final_brix": 9.0},
+			wantContains: "cannot exceed original Brix",
+		},
+		{
+			name:         "zero correction factor",
+			args:         map[string]interface{}{"original_brix": 12.0, "wort_correction_factor": 0.0},
+			wantContains: "wort_correction_factor must be greater than zero",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, mcpErr := callCalculator(t, "refractometer_correction", tt.args)
+			expectInvalidParams(t, mcpErr, tt.wantContains)
+		})
+	}
+}
+
+func TestHydrometerCorrection_Tool(t *testing.T) {
+	tests := []struct {
+		name string
+		args map[string]interface{}
+		want string
+	}{
+		{
+			name: "fahrenheit with default calibration",
+			args: map[string]interface{}{"measured_gravity": 1.050, "sample_temp": 100.0},
+			want: "= **1.056** at 60.0°F calibration",
+		},
+		{
+			name: "celsius inputs",
+			args: map[string]interface{}{
+				"measured_gravity": 1.048, "sample_temp": 25.0, "calibration_temp": 20.0, "temperature_unit": "c",
+			},
+			want: "= **1.049** at 68.0°F calibration",
+		},
+		{
+			name: "metric units",
+			args: map[string]interface{}{
+				"measured_gravity": 1.048, "sample_temp": 25.0, "calibration_temp": 20.0, "units": "metric",
+			},
+			want: "= **1.049** at 20.0°C calibration",
+		},
+		{
+			name: "identity at calibration temperature",
+			args: map[string]interface{}{"measured_gravity": 1.050, "sample_temp": 60.0},
+			want: "(+0.000)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, mcpErr := callCalculator(t, "hydrometer_correction", tt.args)
+			if mcpErr != nil {
+				t.Fatalf("unexpected error: %v", mcpErr)
+			}
+			if !strings.Contains(result.Content[0].Text, tt.want) {
+				t.Errorf("expected %q in %q", tt.want, result.Content[0].Text)
+			}
+		})
+	}
+}
+
+// Test that the calculators default to the unit system the client prefers, which a call's units
+// argument overrides, and that the preference replaces the guess from the input values.
+func TestCalculators_ClientUnitPreference(t *testing.T) {
+	toolHandlers := handlers.NewToolHandlers(nil, nil, nil)
+	metric := units.NewContext(context.Background(), brewing.UnitsMetric)
+	tests := []struct {
+		name    string
+		handler func(context.Context, map[string]interface{}) (*mcp.ToolResult, error)
+		args    map[string]interface{}
+		want    string
+	}{
+		{"preference", toolHandlers.MashWater, map[string]interface{}{
+			"grain_weight": 4.5, "grain_temp": 20.0, "target_temp": 63.0,
+		}, "Strike Temperature:** 68.7°C"},
+		{"units argument", toolHandlers.MashWater, map[string]interface{}{
+			"grain_weight": 10.0, "grain_temp": 70.0, "target_temp": 152.0, "units": "imperial",
+		}, "Strike Volume:** 3.75 gal"},
+		{"no guess", toolHandlers.IBUCalculator, map[string]interface{}{
+			"batch_size": 10.0, "original_gravity": 1.050,
+			"hops": []interface{}{map[string]interface{}{"weight": 5.0, "alpha_acid": 10.0, "boil_time": 60.0}},
+		}, "**Total:** 11.5 IBU"},
+		{"default serving", toolHandlers.ABVCalculator, map[string]interface{}{
+			"original_gravity": 1.050, "final_gravity": 1.010,
+		}, "kcal per 355 ml"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.handler(metric, tt.args)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			text := result.Content[0].Text
+			if !strings.Contains(text, tt.want) || strings.Contains(text, "assumed from the input values") {
+				t.Errorf("expected %q in %q", tt.want, text)
+			}
+		})
+	}
+}
+
+func TestHydrometerCorrection_InvalidParams(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         map[string]interface{}
+		wantContains string
+	}{
+		{
+			name:         "missing sample temperature",
+			args:         map[string]interface{}{"measured_gravity": 1.050},
+			wantContains: "'sample_temp' parameter is required",
+		},
+		{
+			name:         "sample temperature out of range",
+			args:         map[string]interface{}{"measured_gravity": 1.050, "sample_temp": 220.0},
+			wantContains: "sample temperature must be between 32 and 212°F",
+		},
+		{
+			name: "bad temperature unit",
+			args: map[string]interface{}{
+				"measured_gravity": 1.050, "sample_temp": 20.0, "temperature_unit": "k",
+			},
+			wantContains: "temperature_unit must be 'f' or 'c'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, mcpErr := callCalculator(t, "hydrometer_correction", tt.args)
+			expectInvalidParams(t, mcpErr, tt.wantContains)
+		})
+	}
+}
+
+func TestIBUCalculator_Formulas(t *testing.T) {
+	hops := []interface{}{
+		map[string]interface{}{"name": "Magnum", "alpha_acid": 10.0, "weight": 1.0, "boil_time": 60.0},
+	}
+	tests := []struct {
+		formula string
+		want    string
+	}{
+		{formula: "", want: "**Total:** 34.6 IBU"},
+		{formula: "tinseth", want: "**Total:** 34.6 IBU"},
+		{formula: "Rager", want: "**Total:** 46.0 IBU"},
+		{formula: "garetz", want: "**Total:** 27.1 IBU"},
+	}
+	for _, tt := range tests {
+		t.Run("formula "+tt.formula, func(t *testing.T) {
+			result, mcpErr := callCalculator(t, "ibu_calculator", map[string]interface{}{
+				"batch_size": 5.0, "original_gravity": 1.050, "hops": hops, "formula": tt.formula,
+			})
+			if mcpErr != nil {
+				t.Fatalf("unexpected error: %v", mcpErr)
+			}
+			text := result.Content[0].Text
+			if !strings.Contains(text, tt.want) || !strings.Contains(text, "**Magnum** (10.0% AA whole, 60 min boil)") {
+				t.Errorf("unexpected output: %q", text)
+			}
+		})
+	}
+}
+
+func TestIBUCalculator_MetricWhirlpool(t *testing.T) {
+	result, mcpErr := callCalculator(t, "ibu_calculator", map[string]interface{}{
+		"batch_size":       20.0,
+		"original_gravity": 1.055,
+		"units":            "metric",
+		"hops": []interface{}{
+			map[string]interface{}{"alpha_acid": 12.0, "weight": 28.0, "boil_time": 60.0},
+			map[string]interface{}{
+				"name": "Citra", "alpha_acid": 12.0, "weight": 50.0, "boil_time": 20.0, "steep_temp": 80.0,
+			},
+		},
+	})
+	if mcpErr != nil {
+		t.Fatalf("unexpected error: %v", mcpErr)
+	}
+	text := result.Content[0].Text
+	for _, want := range []string{"**Hop 1**", "**Citra** (12.0% AA whole, 20 min steep at 80°C)", "**Total:**"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in %q", want, text)
+		}
+	}
+}
+
+func TestIBUCalculator_UnitSystems(t *testing.T) {
+	hops := func(weight, steepTemp float64) []interface{} {
+		return []interface{}{
+			map[string]interface{}{"alpha_acid": 10.0, "weight": weight, "boil_time": 60.0},
+			map[string]interface{}{"alpha_acid": 12.0, "weight": weight, "boil_time": 20.0, "steep_temp": steepTemp},
+		}
+	}
+	total := func(args map[string]interface{}) string {
+		t.Helper()
+		result, mcpErr := callCalculator(t, "ibu_calculator", args)
+		if mcpErr != nil {
+			t.Fatalf("unexpected error: %v", mcpErr)
+		}
+		text := result.Content[0].Text
+		return text[strings.Index(text, "**Total:**"):]
+	}
+
+	imperial := total(map[string]interface{}{
+		"batch_size": 5.0, "original_gravity": 1.060, "hops": hops(2, 176), "units": "imperial",
+	})
+	metric := total(map[string]interface{}{
+		"batch_size": 5 * brewing.LitersPerGallon, "original_gravity": 1.060,
+		"hops": hops(2*brewing.GramsPerOunce, 80), "units": "metric",
+	})
+	if imperial != metric {
+		t.Errorf("expected equal totals, got %q (imperial) and %q (metric)", imperial, metric)
+	}
+	defaulted := total(map[string]interface{}{"batch_size": 5.0, "original_gravity": 1.060, "hops": hops(2, 176)})
+	if defaulted != imperial {
+		t.Errorf("expected small values to default to imperial, got %q", defaulted)
+	}
+
+	result, mcpErr := callCalculator(t, "ibu_calculator", map[string]interface{}{
+		"batch_size": 5 * brewing.LitersPerGallon, "original_gravity": 1.060,
+		"hops": hops(2*brewing.GramsPerOunce, 80),
+	})
+	if mcpErr != nil {
+		t.Fatalf("unexpected error: %v", mcpErr)
+	}
+	text := result.Content[0].Text
+	if !strings.Contains(text, "metric (L and g) assumed") || !strings.Contains(text, metric) {
+		t.Errorf("expected inferred metric units matching %q, got %q", metric, text)
+	}
+}
+
+func TestIBUCalculator_FormAndDryHop(t *testing.T) {
+	result, mcpErr := callCalculator(t, "ibu_calculator", map[string]interface{}{
+		"batch_size":       5.0,
+		"original_gravity": 1.050,
+		"hops": []interface{}{
+			map[string]interface{}{
+				"name": "Magnum", "alpha_acid": 10.0, "weight": 1.0, "boil_time": 60.0, "form": "pellet",
+			},
+			map[string]interface{}{
+				"name": "Citra", "alpha_acid": 12.0, "weight": 2.0, "boil_time": 0.0, "form": "pellet", "dry_hop": true,
+			},
+		},
+	})
+	if mcpErr != nil {
+		t.Fatalf("unexpected error: %v", mcpErr)
+	}
+	text := result.Content[0].Text
+	for _, want := range []string{
+		"**Magnum** (10.0% AA pellet, 60 min boil): 38.0 IBU",
+		"**Citra** (12.0% AA pellet, dry hop): 0.0 IBU",
+		"**Total:** 38.0 IBU",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in %q", want, text)
+		}
+	}
+}
+
+func TestIBUCalculator_InvalidParams(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         map[string]interface{}
+		wantContains string
+	}{
+		{
+			name:         "missing hops",
+			args:         map[string]interface{}{"batch_size": 5.0, "original_gravity": 1.050},
+			wantContains: "'hops' parameter must be a non-empty array",
+		},
+		{
+			name: "hop missing alpha acid",
+			args: map[string]interface{}{
+				"batch_size": 5.0, "original_gravity": 1.050,
+				"hops": []interface{}{map[string]interface{}{"weight": 1.0, "boil_time": 60.0}},
+			},
+			wantContains: "hops[0]: 'alpha_acid' parameter is required",
+		},
+		{
+			name: "unknown formula",
+			args: map[string]interface{}{
+				"batch_size": 5.0, "original_gravity": 1.050, "formula": "daniels",
+				"hops": []interface{}{map[string]interface{}{"alpha_acid": 10.0, "weight": 1.0, "boil_time": 60.0}},
+			},
+			wantContains: "ibu formula must be tinseth, rager, or garetz",
+		},
+		{
+			name: "zero batch size",
+			args: map[string]interface{}{
+				"batch_size": 0.0, "original_gravity": 1.050,
+				"hops": []interface{}{map[string]interface{}{"alpha_acid": 10.0, "weight": 1.0, "boil_time": 60.0}},
+			},
+			wantContains: "batch size must be greater than zero",
+		},
+		{
+			name: "negative boil time",
+			args: map[string]interface{}{
+				"batch_size": 5.0, "original_gravity": 1.050,
+				"hops": []interface{}{map[string]interface{}{"alpha_acid": 10.0, "weight": 1.0, "boil_time": -10.0}},
+			},
+			wantContains: "boil time cannot be negative",
+		},
+		{
+			name: "unknown form",
+			args: map[string]interface{}{
+				"batch_size": 5.0, "original_gravity": 1.050,
+				"hops": []interface{}{
+					map[string]interface{}{"alpha_acid": 10.0, "weight": 1.0, "boil_time": 60.0, "form": "powder"},
+				},
+			},
+			wantContains: "hops[0]: unknown hop form",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, mcpErr := callCalculator(t, "ibu_calculator", tt.args)
+			expectInvalidParams(t, mcpErr, tt.wantContains)
+		})
+	}
+}
+
+func TestVolumeCalculator_HappyPath(t *testing.T) {
+	tests := []struct {
+		name string
+		args map[string]interface{}
+		want []string
+	}{
+		{
+			name: "boil with volume evaporation rate",
+			args: map[string]interface{}{
+				"target_volume": 5.0, "evaporation_rate": 1.0, "evaporation_unit": "volume",
+				"shrinkage": 0.0, "trub_loss": 0.25,
+			},
+			want: []string{
+				"Pre-Boil Volume:** 6.25 gal", "Post-Boil Volume (hot):** 5.25 gal", "Into Fermenter:** 5.00 gal",
+			},
+		},
+		{
+			name: "metric boil with percentage evaporation and default shrinkage",
+			args: map[string]interface{}{
+				"calculation": "boil", "target_volume": 20.0, "evaporation_rate": 10.0, "units": "metric",
+			},
+			want: []string{"Pre-Boil Volume:** 23.15 L", "Into Fermenter:** 20.00 L"},
+		},
+		{
+			name: "dilution",
+			args: map[string]interface{}{
+				"calculation": "dilution", "volume": 5.0, "gravity": 1.060, "target_gravity": 1.050,
+			},
+			want: []string{"add 1.00 gal of water", "reach 6.00 gal at 1.050"},
+		},
+		{
+			name: "blend falls between inputs",
+			args: map[string]interface{}{
+				"calculation": "blend", "units": "metric",
+				"worts": []interface{}{
+					map[string]interface{}{"volume": 12.0, "gravity": 1.080},
+					map[string]interface{}{"volume": 8.0, "gravity": 1.030},
+				},
+			},
+			want: []string{"2 worts combine to 20.00 L at 1.060"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, mcpErr := callCalculator(t, "volume_calculator", tt.args)
+			if mcpErr != nil {
+				t.Fatalf("unexpected error: %v", mcpErr)
+			}
+			text := result.Content[0].Text
+			for _, want := range tt.want {
+				if !strings.Contains(text, want) {
+					t.Errorf("expected %q in %q", want, text)
+				}
+			}
+		})
+	}
+}
+
+func TestVolumeCalculator_InvalidParams(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         map[string]interface{}
+		wantContains string
+	}{
+		{
+			name:         "zero target volume",
+			args:         map[string]interface{}{"target_volume": 0.0, "evaporation_rate": 10.0},
+			wantContains: "target volume must be greater than zero",
+		},
+		{
+			name:         "evaporation over 100 percent",
+			args:         map[string]interface{}{"target_volume": 5.0, "evaporation_rate": 120.0},
+			wantContains: "cannot exceed 100%",
+		},
+		{
+			name: "negative dilution volume",
+			args: map[string]interface{}{
+				"calculation": "dilution", "volume": -5.0, "gravity": 1.060, "target_gravity": 1.050,
+			},
+			wantContains: "wort volume must be greater than zero",
+		},
+		{
+			name: "dilution cannot raise gravity",
+			args: map[string]interface{}{
+				"calculation": "dilution", "volume": 5.0, "gravity": 1.040, "target_gravity": 1.050,
+			},
+			wantContains: "can only lower it",
+		},
+		{
+			name: "blend missing gravity",
+			args: map[string]interface{}{
+				"calculation": "blend",
+				"worts": []interface{}{
+					map[string]interface{}{"volume": 5.0}, map[string]interface{}{"volume": 5.0},
+				},
+			},
+			wantContains: "worts[0]: 'gravity' parameter is required",
+		},
+		{
+			name:         "unknown calculation",
+			args:         map[string]interface{}{"calculation": "mash"},
+			wantContains: "calculation must be 'boil', 'dilution', or 'blend'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, mcpErr := callCalculator(t, "volume_calculator", tt.args)
+			expectInvalidParams(t, mcpErr, tt.wantContains)
+		})
+	}
+}
+
+func TestABVCalculator(t *testing.T) {
+	tests := []struct {
+		name string
+		args map[string]interface{}
+		want []string
+	}{
+		{
+			name: "reference pale ale",
+			args: map[string]interface{}{"original_gravity": 1.050, "final_gravity": 1.010},
+			want: []string{"ABV:** 5.25%", "ABW:** 4.11%", "Calories:** 165 kcal per 12 oz"},
+		},
+		{
+			name: "pint serving",
+			args: map[string]interface{}{"original_gravity": 1.050, "final_gravity": 1.010, "serving_size": 16.0},
+			want: []string{"Calories:** 220 kcal per 16 oz"},
+		},
+		{
+			name: "metric serving",
+			args: map[string]interface{}{
+				"original_gravity": 1.050, "final_gravity": 1.010, "serving_size": 500.0, "units": "metric",
+			},
+			want: []string{"Calories:** 233 kcal per 500 ml"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, mcpErr := callCalculator(t, "abv_calculator", tt.args)
+			if mcpErr != nil {
+				t.Fatalf("unexpected error: %v", mcpErr)
+			}
+			text := result.Content[0].Text
+			for _, want := range tt.want {
+				if !strings.Contains(text, want) {
+					t.Errorf("expected %q in %q", want, text)
+				}
+			}
+		})
+	}
+}
+
+func TestAttenuationCalculator(t *testing.T) {
+	result, mcpErr := callCalculator(t, "attenuation_calculator", map[string]interface{}{
+		"original_gravity": 1.050, "final_gravity": 1.010,
+	})
+	if mcpErr != nil {
+		t.Fatalf("unexpected error: %v", mcpErr)
+	}
+	text := result.Content[0].Text
+	for _, want := range []string{
+		"Apparent Attenuation:** 80.0%", "Real Attenuation:** 65.0%", "ABV:** 5.25%", "ABW:** 4.11%",
+		"Calories:** 165 kcal per 12 oz",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in %q", want, text)
+		}
+	}
+}
+
+func TestGravityCalculators_InvalidParams(t *testing.T) {
+	tests := []struct {
+		name         string
+		tool         string
+		args         map[string]interface{}
+		wantContains string
+	}{
+		{
+			name:         "abv missing final gravity",
+			tool:         "abv_calculator",
+			args:         map[string]interface{}{"original_gravity": 1.050},
+			wantContains: "'final_gravity' parameter is required",
+		},
+		{
+			name:         "abv final above original",
+			tool:         "abv_calculator",
+			args:         map[string]interface{}{"original_gravity": 1.040, "final_gravity": 1.050},
+			wantContains: "cannot be higher than original gravity",
+		},
+		{
+			name: "abv zero serving",
+			tool: "abv_calculator",
+			args: map[string]interface{}{
+				"original_gravity": 1.050, "final_gravity": 1.010, "serving_size": 0.0,
+			},
+			wantContains: "serving volume must be greater than zero",
+		},
+		{
+			name:         "attenuation original out of range",
+			tool:         "attenuation_calculator",
+			args:         map[string]interface{}{"original_gravity": 1.300, "final_gravity": 1.010},
+			wantContains: "original gravity must be between 1.000 and 1.200 (got 1.3)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, mcpErr := callCalculator(t, tt.tool, tt.args)
+			expectInvalidParams(t, mcpErr, tt.wantContains)
+		})
+	}
+}
+
+func TestYeastStarter(t *testing.T) {
+	tests := []struct {
+		name string
+		args map[string]interface{}
+		want []string
+	}{
+		{
+			name: "planned from batch",
+			args: map[string]interface{}{"initial_cells": 100.0, "batch_volume": 5.0, "original_gravity": 1.050},
+			want: []string{"Pitch Target:** 176 billion", "Step 1:** 1.0 L at 1.036", "(meets target)"},
+		},
+		{
+			name: "metric lager batch",
+			args: map[string]interface{}{
+				"initial_cells": 100.0, "batch_volume": 20.0, "original_gravity": 1.048,
+				"yeast_type": "lager", "units": "metric",
+			},
+			want: []string{"Pitch Target:** 357 billion", "(meets target)"},
+		},
+		{
+			name: "explicit two step starter",
+			args: map[string]interface{}{
+				"initial_cells": 100.0,
+				"steps": []interface{}{
+					map[string]interface{}{"volume": 2.0},
+					map[string]interface{}{"volume": 2.0, "gravity": 1.036},
+				},
+			},
+			want: []string{"Step 1:** 2.0 L at 1.036 → 362 billion", "Step 2:** 2.0 L at 1.036 → 555 billion"},
+		},
+		{
+			name: "explicit steps short of target",
+			args: map[string]interface{}{
+				"initial_cells": 100.0, "target_cells": 300.0, "model": "white",
+				"steps": []interface{}{map[string]interface{}{"volume": 1.0}},
+			},
+			want: []string{"short of target"},
+		},
+		{
+			name: "no starter needed",
+			args: map[string]interface{}{"initial_cells": 200.0, "target_cells": 150.0},
+			want: []string{"No starter needed"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, mcpErr := callCalculator(t, "yeast_starter", tt.args)
+			if mcpErr != nil {
+				t.Fatalf("unexpected error: %v", mcpErr)
+			}
+			text := result.Content[0].Text
+			for _, want := range tt.want {
+				if !strings.Contains(text, want) {
+					t.Errorf("expected %q in %q", want, text)
+				}
+			}
+		})
+	}
+}
+
+func TestYeastStarter_UnitSystems(t *testing.T) {
+	target := func(args map[string]interface{}) string {
+		t.Helper()
+		args["initial_cells"] = 100.0
+		args["original_gravity"] = 1.050
+		result, mcpErr := callCalculator(t, "yeast_starter", args)
+		if mcpErr != nil {
+			t.Fatalf("unexpected error: %v", mcpErr)
+		}
+		for _, line := range strings.Split(result.Content[0].Text, "\n") {
+			if strings.Contains(line, "Pitch Target") {
+				return line
+			}
+		}
+		t.Fatalf("expected a pitch target in %q", result.Content[0].Text)
+		return ""
+	}
+	imperial := target(map[string]interface{}{"batch_volume": 5.0})
+	metric := target(map[string]interface{}{"batch_volume": 5 * brewing.LitersPerGallon, "units": "metric"})
+	inferred := target(map[string]interface{}{"batch_volume": 5 * brewing.LitersPerGallon})
+	if imperial != metric || metric != inferred {
+		t.Errorf("expected equal pitch targets, got %q, %q, and %q", imperial, metric, inferred)
+	}
+}
+
+func TestYeastStarter_InvalidParams(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         map[string]interface{}
+		wantContains string
+	}{
+		{
+			name:         "no target or steps",
+			args:         map[string]interface{}{"initial_cells": 100.0},
+			wantContains: "provide 'steps', 'target_cells'",
+		},
+		{
+			name: "starter gravity out of range",
+			args: map[string]interface{}{
+				"initial_cells": 100.0,
+				"steps":         []interface{}{map[string]interface{}{"volume": 1.0, "gravity": 1.080}},
+			},
+			wantContains: "must be between 1.020 and 1.060",
+		},
+		{
+			name: "zero starter volume",
+			args: map[string]interface{}{
+				"initial_cells": 100.0,
+				"steps":         []interface{}{map[string]interface{}{"volume": 0.0}},
+			},
+			wantContains: "starter volume must be greater than zero",
+		},
+		{
+			name: "unknown yeast type",
+			args: map[string]interface{}{
+				"initial_cells": 100.0, "batch_volume": 5.0, "original_gravity": 1.050, "yeast_type": "kveik",
+			},
+			wantContains: "yeast_type must be",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, mcpErr := callCalculator(t, "yeast_starter", tt.args)
+			expectInvalidParams(t, mcpErr, tt.wantContains)
+		})
+	}
+}
+
+func TestWaterProfile(t *testing.T) {
+	tests := []struct {
+		name string
+		args map[string]interface{}
+		want []string
+	}{
+		{
+			name: "burton from RO water",
+			args: map[string]interface{}{"target": "Burton", "volume": 5.0},
+			want: []string{"burton target, 5.0 gal", "**gypsum:**", "Source Residual Alkalinity:** 0 ppm"},
+		},
+		{
+			name: "custom profile in liters",
+			args: map[string]interface{}{
+				"target_profile": map[string]interface{}{"calcium": 50.0, "sulfate": 50.0, "chloride": 75.0},
+				"volume":         20.0,
+				"units":          "metric",
+			},
+			want: []string{"custom target, 20.0 L", "**calcium_chloride:**"},
+		},
+		{
+			name: "hard water against pilsen",
+			args: map[string]interface{}{
+				"calcium": 50.0, "magnesium": 10.0, "sodium": 30.0,
+				"sulfate": 40.0, "chloride": 60.0, "bicarbonate": 150.0,
+				"target": "pilsen", "volume": 5.0,
+			},
+			want: []string{"- None: salts cannot", "dilute with RO water"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, mcpErr := callCalculator(t, "water_profile", tt.args)
+			if mcpErr != nil {
+				t.Fatalf("unexpected error: %v", mcpErr)
+			}
+			text := result.Content[0].Text
+			for _, want := range tt.want {
+				if !strings.Contains(text, want) {
+					t.Errorf("expected %q in %q", want, text)
+				}
+			}
+		})
+	}
+}
+
+func TestWaterProfile_MashPH(t *testing.T) {
+	tests := []struct {
+		name string
+		args map[string]interface{}
+		want []string
+	}{
+		{
+			name: "pale grist with RO water",
+			args: map[string]interface{}{
+				"target": "pilsen", "volume": 5.0,
+				"grains": []interface{}{map[string]interface{}{"weight": 10.0, "lovibond": 2.0}},
+			},
+			want: []string{"Grist in Distilled Water:** 5.72", "ml of 88% lactic acid", "confirm with a pH meter"},
+		},
+		{
+			name: "stout with dublin water in metric",
+			args: map[string]interface{}{
+				"target": "dublin", "volume": 20.0, "units": "metric",
+				"grains": []interface{}{
+					map[string]interface{}{"weight": 4.5, "lovibond": 3.0},
+					map[string]interface{}{"weight": 0.5, "lovibond": 500.0, "type": "roasted"},
+				},
+				"target_ph": 5.5,
+			},
+			want: []string{"Grist in Distilled Water:** 5.62", "(target 5.50)"},
+		},
+		{
+			name: "target above estimate",
+			args: map[string]interface{}{
+				"target": "pilsen", "volume": 5.0, "target_ph": 5.9,
+				"grains": []interface{}{map[string]interface{}{"weight": 10.0, "lovibond": 2.0}},
+			},
+			want: []string{"Acid Addition:** none needed"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, mcpErr := callCalculator(t, "water_profile", tt.args)
+			if mcpErr != nil {
+				t.Fatalf("unexpected error: %v", mcpErr)
+			}
+			text := result.Content[0].Text
+			for _, want := range tt.want {
+				if !strings.Contains(text, want) {
+					t.Errorf("expected %q in %q", want, text)
+				}
+			}
+		})
+	}
+
+	result, _ := callCalculator(t, "water_profile", map[string]interface{}{"target": "pilsen", "volume": 5.0})
+	if strings.Contains(result.Content[0].Text, "Mash pH") {
+		t.Error("expected no mash pH estimate without a grain bill")
+	}
+}
+
+func TestWaterProfile_InvalidParams(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         map[string]interface{}
+		wantContains string
+	}{
+		{
+			name:         "unknown preset",
+			args:         map[string]interface{}{"target": "atlantis", "volume": 5.0},
+			wantContains: "unknown water profile",
+		},
+		{
+			name:         "no target",
+			args:         map[string]interface{}{"volume": 5.0},
+			wantContains: "provide a 'target' preset",
+		},
+		{
+			name:         "zero volume",
+			args:         map[string]interface{}{"target": "dublin", "volume": 0.0},
+			wantContains: "volume must be greater than zero",
+		},
+		{
+			name:         "negative source ion",
+			args:         map[string]interface{}{"calcium": -5.0, "target": "dublin", "volume": 5.0},
+			wantContains: "source calcium cannot be negative",
+		},
+		{
+			name: "grain missing lovibond",
+			args: map[string]interface{}{
+				"target": "dublin", "volume": 5.0,
+				"grains": []interface{}{map[string]interface{}{"weight": 10.0}},
+			},
+			wantContains: "grains[0]:",
+		},
+		{
+			name: "unknown grain type",
+			args: map[string]interface{}{
+				"target": "dublin", "volume": 5.0,
+				"grains": []interface{}{map[string]interface{}{"weight": 10.0, "lovibond": 5.0, "type": "smoked"}},
+			},
+			wantContains: "grains[0].type must be base, crystal, or roasted",
+		},
+		{
+			name:         "target profile not an object",
+			args:         map[string]interface{}{"target_profile": "soft", "volume": 5.0},
+			wantContains: "must be an object",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, mcpErr := callCalculator(t, "water_profile", tt.args)
+			expectInvalidParams(t, mcpErr, tt.wantContains)
+		})
+	}
+}
+
+func TestWaterProfile_UnknownPresetListsProfiles(t *testing.T) {
+	_, mcpErr := callCalculator(t, "water_profile", map[string]interface{}{"target": "atlantis", "volume": 5.0})
+	if mcpErr == nil {
+		t.Fatal("expected error")
+	}
+	data, ok := mcpErr.Data.(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected data map, got %T", mcpErr.Data)
+	}
+	if profiles, _ := data["available_profiles"].([]string); len(profiles) == 0 {
+		t.Errorf("expected available profiles, got %v", data)
+	}
+}
+
+func TestCalculatorTools_ValidationErrorData(t *testing.T) {
+	_, mcpErr := callCalculator(t, "abv_calculator", map[string]interface{}{
+		"original_gravity": 1.040, "final_gravity": 1.050,
+	})
+	expectInvalidParams(t, mcpErr, "final gravity cannot be higher than original gravity")
+	data, ok := mcpErr.Data.(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected data map, got %T", mcpErr.Data)
+	}
+	if data["field"] != "final_gravity" {
+		t.Errorf("expected field final_gravity, got %v", data["field"])
+	}
+	if data["value"] != 1.050 {
+		t.Errorf("expected value 1.050, got %v", data["value"])
+	}
+}
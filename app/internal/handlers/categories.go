@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/mcp"
+	"github.com/CharlRitter/brewsource-mcp/app/pkg/data"
+)
+
+// categorySuggestionLimit is the most close matches named when a category is not found.
+const categorySuggestionLimit = 3
+
+// categoryListing is a BJCP category's styles, as served by bjcp://categories/{name} and the
+// bjcp_lookup category argument.
+type categoryListing struct {
+	Category  string          `json:"category"`
+	Count     int             `json:"count"`
+	CodeRange string          `json:"code_range"`
+	Styles    []categoryStyle `json:"styles"`
+}
+
+// categoryStyle summarizes a style in a category listing.
+type categoryStyle struct {
+	Code   string      `json:"code"`
+	Name   string      `json:"name"`
+	Vitals data.Vitals `json:"vitals"`
+}
+
+// listCategory lists the styles of a category from a guideline version, matching its name
+// case-insensitively. An unknown category fails with notFoundCode, naming the closest categories.
+func listCategory(bjcpService *data.BJCPService, category, version string, notFoundCode int) (*categoryListing, error) {
+	styles := bjcpService.GetStylesByCategoryVersion(category, version)
+	if len(styles) == 0 {
+		suggestions := bjcpService.SearchCategoriesByNameVersion(category, version, categorySuggestionLimit)
+		message := fmt.Sprintf("BJCP category not found: %s", category)
+		if len(suggestions) > 0 {
+			message += fmt.Sprintf("; did you mean %s?", strings.Join(suggestions, ", "))
+		}
+		return nil, &mcp.Error{
+			Code:    notFoundCode,
+			Message: message,
+			Data:    map[string]interface{}{"category": category, "suggestions": suggestions},
+		}
+	}
+
+	listing := &categoryListing{
+		Category:  styles[0].Category,
+		Count:     len(styles),
+		CodeRange: styles[0].Code,
+		Styles:    make([]categoryStyle, 0, len(styles)),
+	}
+	if last := styles[len(styles)-1].Code; last != listing.CodeRange {
+		listing.CodeRange += "-" + last
+	}
+	for _, style := range styles {
+		listing.Styles = append(listing.Styles, categoryStyle{Code: style.Code, Name: style.Name, Vitals: style.Vitals})
+	}
+	return listing, nil
+}
+
+func formatCategoryListing(listing *categoryListing) string {
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("**%s** (%d style(s), %s)\n\n",
+		listing.Category, listing.Count, listing.CodeRange))
+	for _, style := range listing.Styles {
+		v := style.Vitals
+		response.WriteString(fmt.Sprintf("- **%s %s** - ABV %s · IBU %s · SRM %s · OG %s\n", style.Code, style.Name,
+			formatVitalRange("abv", v.ABVMin, v.ABVMax), formatVitalRange("ibu", float64(v.IBUMin), float64(v.IBUMax)),
+			formatVitalRange("srm", v.SRMMin, v.SRMMax), formatVitalRange("og", v.OGMin, v.OGMax)))
+	}
+	return response.String()
+}
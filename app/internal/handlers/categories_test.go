@@ -0,0 +1,127 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/handlers"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/mcp"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/services/servicestest"
+)
+
+type categoryListing struct {
+	Category  string `json:"category"`
+	Count     int    `json:"count"`
+	CodeRange string `json:"code_range"`
+	Styles    []struct {
+		Code   string `json:"code"`
+		Name   string `json:"name"`
+		Vitals struct {
+			IBUMin int `json:"ibu_min"`
+		} `json:"vitals"`
+	} `json:"styles"`
+}
+
+func TestHandleBJCPResource_Category(t *testing.T) {
+	h := handlers.NewResourceHandlers(loadStyleData(t), &servicestest.BeerService{}, &servicestest.BreweryService{})
+
+	for _, uri := range []string{
+		"bjcp://categories/Pale%20American%20Ale",
+		"bjcp://categories/pale+american+ale",
+		"bjcp://categories/PALE AMERICAN ALE",
+	} {
+		t.Run(uri, func(t *testing.T) {
+			res, err := readResource(h, uri)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if res.URI != "bjcp://categories/Pale%20American%20Ale" || res.MimeType != "application/json" {
+				t.Errorf("unexpected resource content: %+v", res)
+			}
+			var listing categoryListing
+			if err = json.Unmarshal([]byte(res.Text), &listing); err != nil {
+				t.Fatalf("invalid JSON in resource text: %v", err)
+			}
+			if listing.Category != "Pale American Ale" || listing.Count != 2 || listing.CodeRange != "18A-18B" {
+				t.Errorf("unexpected listing %+v", listing)
+			}
+			if len(listing.Styles) != 2 || listing.Styles[0].Code != "18A" || listing.Styles[0].Vitals.IBUMin == 0 {
+				t.Errorf("expected 18A first with its vitals, got %+v", listing.Styles)
+			}
+		})
+	}
+}
+
+func TestHandleBJCPResource_CategoryNotFound(t *testing.T) {
+	h := handlers.NewResourceHandlers(loadStyleData(t), &servicestest.BeerService{}, &servicestest.BreweryService{})
+
+	_, err := readResource(h, "bjcp://categories/Pale%20Amercan%20Ale")
+	expectMCPError(t, err, mcp.MethodNotFound, "did you mean Pale American Ale")
+
+	_, err = readResource(h, "bjcp://categories/xyzzy")
+	expectMCPError(t, err, mcp.MethodNotFound, "BJCP category not found: xyzzy")
+	if strings.Contains(err.Error(), "did you mean") {
+		t.Errorf("expected no suggestions, got %v", err)
+	}
+
+	_, err = readResource(h, "bjcp://categories/%zz")
+	expectMCPError(t, err, mcp.InvalidParams, "Invalid BJCP category name")
+}
+
+func TestHandleBJCPResource_CategorySummaries(t *testing.T) {
+	h := handlers.NewResourceHandlers(loadStyleData(t), &servicestest.BeerService{}, &servicestest.BreweryService{})
+
+	res, err := h.HandleBJCPResource(context.Background(), "bjcp://categories")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var parsed struct {
+		Summaries []struct {
+			Name       string `json:"name"`
+			StyleCount int    `json:"style_count"`
+			CodeRange  string `json:"code_range"`
+		} `json:"summaries"`
+	}
+	if err = json.Unmarshal([]byte(res.Text), &parsed); err != nil {
+		t.Fatalf("invalid JSON in resource text: %v", err)
+	}
+	found := false
+	for _, summary := range parsed.Summaries {
+		if summary.Name == "Strong American Ale" {
+			found = summary.StyleCount == 4 && summary.CodeRange == "22A-22D"
+		}
+	}
+	if !found {
+		t.Errorf("expected Strong American Ale with 4 styles, 22A-22D, got %+v", parsed.Summaries)
+	}
+}
+
+func TestBJCPLookup_Category(t *testing.T) {
+	h := handlers.NewToolHandlers(loadStyleData(t), newBeerService(), newBreweryService())
+
+	result, err := h.BJCPLookup(context.Background(), map[string]interface{}{"category": "pale american ale"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	text := result.Content[0].Text
+	for _, want := range []string{"**Pale American Ale** (2 style(s), 18A-18B)", "- **18A Blonde Ale** - ABV", "18B"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in:\n%s", want, text)
+		}
+	}
+
+	_, err = h.BJCPLookup(context.Background(), map[string]interface{}{"category": "Pale Amercan Ale"})
+	expectMCPError(t, err, mcp.InvalidParams, "did you mean Pale American Ale")
+
+	// A style code takes precedence over a category.
+	result, err = h.BJCPLookup(context.Background(),
+		map[string]interface{}{"style_code": "21A", "category": "Czech Lager"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(result.Content[0].Text, "American IPA") {
+		t.Errorf("expected the style, got:\n%s", result.Content[0].Text)
+	}
+}
@@ -0,0 +1,217 @@
+package handlers
+
+import (
+	"bufio"
+	"compress/gzip"
+	"mime"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// compressMinSize is the smallest response body worth compressing; smaller bodies are sent as
+// they are, since the gzip framing would outweigh the saving.
+const compressMinSize = 1024
+
+// uncompressedPaths are path prefixes never compressed: /metrics, as scrapers negotiate their own
+// encoding.
+var uncompressedPaths = []string{"/metrics"}
+
+var gzipWriters = sync.Pool{
+	New: func() interface{} { return gzip.NewWriter(nil) },
+}
+
+// Compress wraps next so responses of at least compressMinSize bytes with a textual Content-Type,
+// such as JSON, HTML, CSS or JavaScript, are gzip-encoded for clients that accept it. Responses
+// that already have a Content-Encoding, image and font assets, HEAD and range requests, and
+// uncompressedPaths are sent as they are. Apply it inside AccessLog, so the logged size is the
+// size sent.
+func Compress(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, r *http.Request) {
+		writer.Header().Add("Vary", "Accept-Encoding")
+		if !acceptsGzip(r.Header.Get("Accept-Encoding")) || r.Method == http.MethodHead ||
+			r.Header.Get("Range") != "" || hasPathPrefix(r.URL.Path, uncompressedPaths) {
+			next.ServeHTTP(writer, r)
+			return
+		}
+
+		compressor := &compressWriter{ResponseWriter: writer}
+		defer compressor.Close()
+		next.ServeHTTP(compressor, r)
+	})
+}
+
+// acceptsGzip reports whether an Accept-Encoding header value allows gzip, by name or through *,
+// with a non-zero quality.
+func acceptsGzip(acceptEncoding string) bool {
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding != "gzip" && coding != "*" {
+			continue
+		}
+		if name, value, ok := strings.Cut(strings.TrimSpace(params), "="); ok && strings.TrimSpace(name) == "q" {
+			if q, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil && q == 0 {
+				continue
+			}
+		}
+		return true
+	}
+	return false
+}
+
+func hasPathPrefix(path string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if path == prefix || strings.HasPrefix(path, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// compressible reports whether a Content-Type is text that gzip shrinks; media, fonts and
+// archives are usually compressed already.
+func compressible(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	switch {
+	case strings.HasPrefix(mediaType, "text/"),
+		strings.HasSuffix(mediaType, "+json"), strings.HasSuffix(mediaType, "+xml"):
+		return true
+	}
+	switch mediaType {
+	case "application/json", "application/javascript", "application/xml", "image/svg+xml":
+		return true
+	}
+	return false
+}
+
+// compressWriter buffers the start of a response until it knows whether to compress it: once
+// compressMinSize bytes are written, when the handler flushes, or when it finishes. The status
+// code is held back until then, since compressing changes the headers.
+type compressWriter struct {
+	http.ResponseWriter
+	status  int
+	buf     []byte
+	decided bool
+	gz      *gzip.Writer
+}
+
+func (c *compressWriter) WriteHeader(code int) {
+	if code < http.StatusOK && code != http.StatusSwitchingProtocols {
+		c.ResponseWriter.WriteHeader(code)
+		return
+	}
+	if c.status == 0 {
+		c.status = code
+	}
+}
+
+func (c *compressWriter) Write(b []byte) (int, error) {
+	if c.status == 0 {
+		c.status = http.StatusOK
+	}
+	if !c.decided {
+		c.buf = append(c.buf, b...)
+		if len(c.buf) < compressMinSize {
+			return len(b), nil
+		}
+		if err := c.decide(true); err != nil {
+			return 0, err
+		}
+		return len(b), nil
+	}
+	if c.gz != nil {
+		return c.gz.Write(b)
+	}
+	return c.ResponseWriter.Write(b)
+}
+
+// decide sends the header, compressing when large is set and the response suits it, followed by
+// the buffered body.
+func (c *compressWriter) decide(large bool) error {
+	c.decided = true
+	if c.status == 0 {
+		c.status = http.StatusOK
+	}
+	header := c.Header()
+	// Set the type from the uncompressed body, which net/http would otherwise sniff after encoding
+	if header.Get("Content-Type") == "" && len(c.buf) > 0 {
+		header.Set("Content-Type", http.DetectContentType(c.buf))
+	}
+	if large && c.status != http.StatusNoContent && c.status != http.StatusNotModified &&
+		header.Get("Content-Encoding") == "" && compressible(header.Get("Content-Type")) {
+		header.Set("Content-Encoding", "gzip")
+		header.Del("Content-Length")
+		// The encoded bytes differ from those a strong ETag promises
+		if etag := header.Get("ETag"); strings.HasPrefix(etag, `"`) {
+			header.Set("ETag", "W/"+etag)
+		}
+		c.gz, _ = gzipWriters.Get().(*gzip.Writer)
+		c.gz.Reset(c.ResponseWriter)
+	}
+	c.ResponseWriter.WriteHeader(c.status)
+
+	buffered := c.buf
+	c.buf = nil
+	if len(buffered) == 0 {
+		return nil
+	}
+	var err error
+	if c.gz != nil {
+		_, err = c.gz.Write(buffered)
+	} else {
+		_, err = c.ResponseWriter.Write(buffered)
+	}
+	return err
+}
+
+// Flush sends what has been written so far, compressed if the response is compressible: a
+// flushing handler is streaming, so the whole body may be large.
+func (c *compressWriter) Flush() {
+	if !c.decided {
+		_ = c.decide(true)
+	}
+	if c.gz != nil {
+		_ = c.gz.Flush()
+	}
+	if flusher, ok := c.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack hands the connection over to the handler. Nothing buffered is sent, so a handler should
+// hijack before writing.
+func (c *compressWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := c.ResponseWriter.(http.Hijacker)
+	if !ok || c.decided {
+		return nil, nil, http.ErrNotSupported
+	}
+	c.decided = true
+	return hijacker.Hijack()
+}
+
+// Unwrap returns the underlying writer, for http.ResponseController.
+func (c *compressWriter) Unwrap() http.ResponseWriter {
+	return c.ResponseWriter
+}
+
+// Close sends a response that stayed below compressMinSize, or ends the gzip stream.
+func (c *compressWriter) Close() {
+	if !c.decided {
+		if c.status == 0 && len(c.buf) == 0 {
+			return
+		}
+		_ = c.decide(false)
+	}
+	if c.gz != nil {
+		_ = c.gz.Close()
+		c.gz.Reset(nil)
+		gzipWriters.Put(c.gz)
+		c.gz = nil
+	}
+}
@@ -0,0 +1,230 @@
+package handlers_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/handlers"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/mcp"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/requestid"
+	"github.com/CharlRitter/brewsource-mcp/app/pkg/data"
+	logtest "github.com/sirupsen/logrus/hooks/test"
+)
+
+const readStylesMessage = `{"jsonrpc":"2.0","id":1,"method":"resources/read","params":{"uri":"bjcp://styles"}}`
+
+// loadStyleData loads the full BJCP 2021 style data.
+func loadStyleData(tb testing.TB) *data.BJCPData {
+	tb.Helper()
+	bjcpData, err := data.LoadBJCPData()
+	if err != nil {
+		tb.Fatalf("Failed to load BJCP data: %v", err)
+	}
+	return bjcpData
+}
+
+func newStylesMCPHandler(tb testing.TB) http.Handler {
+	server := mcp.NewServer(nil, handlers.NewResourceHandlers(loadStyleData(tb), nil, nil), nil)
+	return http.HandlerFunc(server.HandleHTTP)
+}
+
+// newStylesAPIHandler serves the REST API, whose /api/v1/styles lists every style in full.
+func newStylesAPIHandler(tb testing.TB) http.Handler {
+	webHandlers := handlers.NewWebHandlers(nil, nil)
+	webHandlers.SetBJCPData(loadStyleData(tb))
+	mux := http.NewServeMux()
+	webHandlers.RegisterAPIRoutes(mux)
+	return mux
+}
+
+func postMCP(handler http.Handler, acceptEncoding string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(readStylesMessage))
+	request.Header.Set("Accept-Encoding", acceptEncoding)
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func getStyles(handler http.Handler, acceptEncoding string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodGet, "/api/v1/styles", nil)
+	request.Header.Set("Accept-Encoding", acceptEncoding)
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func gunzip(t *testing.T, body []byte) []byte {
+	t.Helper()
+	reader, err := gzip.NewReader(bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("Response is not gzip: %v", err)
+	}
+	decoded, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("Failed to decompress response: %v", err)
+	}
+	return decoded
+}
+
+// assertGzipped checks that compressed is the gzip encoding of plain, with its Content-Type kept.
+func assertGzipped(t *testing.T, compressed *httptest.ResponseRecorder, plain []byte) {
+	t.Helper()
+	if compressed.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, compressed.Code)
+	}
+	if encoding := compressed.Header().Get("Content-Encoding"); encoding != "gzip" {
+		t.Fatalf("Expected gzip Content-Encoding, got %q", encoding)
+	}
+	if contentType := compressed.Header().Get("Content-Type"); contentType != "application/json" {
+		t.Errorf("Expected Content-Type application/json, got %q", contentType)
+	}
+	if vary := compressed.Header().Get("Vary"); vary != "Accept-Encoding" {
+		t.Errorf("Expected Vary: Accept-Encoding, got %q", vary)
+	}
+	if !bytes.Equal(gunzip(t, compressed.Body.Bytes()), plain) {
+		t.Error("Decompressed response differs from the uncompressed one")
+	}
+}
+
+func TestCompress_MCPResource(t *testing.T) {
+	mcpHandler := newStylesMCPHandler(t)
+	plain := postMCP(mcpHandler, "").Body.Bytes()
+	assertGzipped(t, postMCP(handlers.Compress(mcpHandler), "br;q=1.0, gzip;q=0.8"), plain)
+}
+
+func TestCompress_StyleList(t *testing.T) {
+	apiHandler := newStylesAPIHandler(t)
+	uncompressed := getStyles(apiHandler, "")
+	plain := uncompressed.Body.Bytes()
+	compressed := getStyles(handlers.Compress(apiHandler), "gzip")
+	assertGzipped(t, compressed, plain)
+	if etag := compressed.Header().Get("ETag"); etag != "W/"+uncompressed.Header().Get("ETag") {
+		t.Errorf("Expected the gzipped list to have the weak form of its ETag, got %q", etag)
+	}
+
+	if compressed.Body.Len()*3 > len(plain) {
+		t.Errorf("Expected the %d byte style list to compress below a third, got %d bytes",
+			len(plain), compressed.Body.Len())
+	}
+}
+
+func TestCompress_SentAsIs(t *testing.T) {
+	large := strings.Repeat("IPA ", 1024)
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		acceptEncoding string
+		contentType    string
+		encoding       string
+		body           string
+	}{
+		{"client does not accept gzip", http.MethodGet, "/api", "", "application/json", "", large},
+		{"gzip refused with q=0", http.MethodGet, "/api", "gzip;q=0, identity", "application/json", "", large},
+		{"tiny response", http.MethodGet, "/api", "gzip", "application/json", "", `{"ok":true}`},
+		{"already compressed image", http.MethodGet, "/static/logo.png", "gzip", "image/png", "", large},
+		{"existing encoding", http.MethodGet, "/static/app.js", "gzip", "text/javascript", "br", large},
+		{"metrics", http.MethodGet, "/metrics", "gzip", "text/plain", "", large},
+		{"HEAD request", http.MethodHead, "/api", "gzip", "application/json", "", large},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := handlers.Compress(http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+				writer.Header().Set("Content-Type", tt.contentType)
+				if tt.encoding != "" {
+					writer.Header().Set("Content-Encoding", tt.encoding)
+				}
+				_, _ = io.WriteString(writer, tt.body)
+			}))
+			request := httptest.NewRequest(tt.method, tt.path, nil)
+			request.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+
+			if encoding := recorder.Header().Get("Content-Encoding"); encoding != tt.encoding {
+				t.Errorf("Expected Content-Encoding %q, got %q", tt.encoding, encoding)
+			}
+			if recorder.Body.String() != tt.body {
+				t.Errorf("Expected the body to be sent unchanged, got %d bytes", recorder.Body.Len())
+			}
+		})
+	}
+}
+
+func TestCompress_StatusAndSniffedType(t *testing.T) {
+	page := "<!doctype html><html><body>" + strings.Repeat("<p>Hops</p>", 200) + "</body></html>"
+	handler := handlers.Compress(http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+		writer.WriteHeader(http.StatusNotFound)
+		_, _ = io.WriteString(writer, page[:10])
+		_, _ = io.WriteString(writer, page[10:])
+	}))
+	request := httptest.NewRequest(http.MethodGet, "/missing", nil)
+	request.Header.Set("Accept-Encoding", "gzip")
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+
+	if recorder.Code != http.StatusNotFound {
+		t.Errorf("Expected status code %d, got %d", http.StatusNotFound, recorder.Code)
+	}
+	if contentType := recorder.Header().Get("Content-Type"); contentType != "text/html; charset=utf-8" {
+		t.Errorf("Expected the type sniffed from the uncompressed body, got %q", contentType)
+	}
+	if string(gunzip(t, recorder.Body.Bytes())) != page {
+		t.Error("Decompressed response differs from the page")
+	}
+}
+
+func TestCompress_Flush(t *testing.T) {
+	handler := handlers.Compress(http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+		writer.Header().Set("Content-Type", "text/event-stream")
+		_, _ = io.WriteString(writer, "data: first\n\n")
+		if err := http.NewResponseController(writer).Flush(); err != nil {
+			t.Errorf("Flush failed: %v", err)
+		}
+		_, _ = io.WriteString(writer, "data: second\n\n")
+	}))
+	request := httptest.NewRequest(http.MethodGet, "/events", nil)
+	request.Header.Set("Accept-Encoding", "gzip")
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+
+	if !recorder.Flushed {
+		t.Error("Expected the flush to reach the underlying writer")
+	}
+	if got := string(gunzip(t, recorder.Body.Bytes())); got != "data: first\n\ndata: second\n\n" {
+		t.Errorf("Unexpected streamed body %q", got)
+	}
+}
+
+// Test that the access log, applied outside compression, logs the compressed size.
+func TestCompress_WithAccessLog(t *testing.T) {
+	hook := logtest.NewGlobal()
+	t.Cleanup(hook.Reset)
+
+	recorder := postMCP(handlers.AccessLog(handlers.Compress(newStylesMCPHandler(t))), "gzip")
+
+	if recorder.Header().Get(requestid.Header) == "" {
+		t.Error("Expected the request ID header on a compressed response")
+	}
+	if recorder.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatal("Expected a compressed response")
+	}
+	entry := hook.LastEntry()
+	if entry == nil || entry.Data["bytes"] != int64(recorder.Body.Len()) {
+		t.Errorf("Expected %d bytes logged, got %v", recorder.Body.Len(), entry)
+	}
+}
+
+func BenchmarkCompress_StyleList(b *testing.B) {
+	handler := handlers.Compress(newStylesAPIHandler(b))
+	b.ReportAllocs()
+	for b.Loop() {
+		getStyles(handler, "gzip")
+	}
+}
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/mcp"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/services"
+	"github.com/CharlRitter/brewsource-mcp/app/pkg/data"
+)
+
+// dataSourcesURI lists when each data source last updated the data.
+const dataSourcesURI = "meta://data-sources"
+
+// provenanceTimeout bounds the data source lookup of a provenance footer, which is left without the
+// catalog date rather than delaying the tool.
+const provenanceTimeout = 500 * time.Millisecond
+
+// HandleDataSourcesResource serves meta://data-sources: the data_sources rows, most recently updated
+// first, and the metadata and health of the default BJCP guidelines, see data.BJCPService.Health.
+func (h *ResourceHandlers) HandleDataSourcesResource(ctx context.Context, uri string) (*mcp.ResourceContent, error) {
+	if uri != dataSourcesURI {
+		return nil, mcp.NewMCPError(mcp.MethodNotFound, fmt.Sprintf("Meta resource not found: %s", uri), nil)
+	}
+	sources, err := h.breweryService.ListDataSources(ctx)
+	if err != nil {
+		return nil, serviceError(err, "failed to list data sources")
+	}
+	content, err := json.Marshal(map[string]interface{}{
+		"description": "Data Sources",
+		"sources":     sources,
+		"bjcp":        h.bjcpService.GetMetadata(),
+		"bjcp_health": h.bjcpService.Health(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal data sources: %w", err)
+	}
+	return &mcp.ResourceContent{
+		URI:      uri,
+		MimeType: "application/json",
+		Text:     string(content),
+	}, nil
+}
+
+// dataProvenance names the data a tool answered from: the BJCP guideline version and, for a catalog
+// tool, when the catalog last changed.
+type dataProvenance struct {
+	BJCPVersion string `json:"bjcp_version"`
+	Catalog     string `json:"catalog,omitempty"`
+	// CatalogUpdated is the date, YYYY-MM-DD, of the catalog's last change, when recorded.
+	CatalogUpdated string `json:"catalog_updated,omitempty"`
+}
+
+// String reads as a provenance footer, such as "BJCP 2021 guidelines, breweries updated 2024-11-02".
+func (p dataProvenance) String() string {
+	footer := fmt.Sprintf("BJCP %s guidelines", p.BJCPVersion)
+	if p.CatalogUpdated != "" {
+		footer += fmt.Sprintf(", %s updated %s", p.Catalog, p.CatalogUpdated)
+	}
+	return footer
+}
+
+// cited wraps a tool handler so that its text ends with a provenance footer, such as "BJCP 2021
+// guidelines, breweries updated 2024-11-02", naming the guideline version of the call's version
+// argument, or the default one, and, for a catalog tool, when the catalog named by catalog last
+// changed. An empty catalog cites the style guide alone. Failed calls are returned unchanged. The
+// tools building their result with toolResponse cite their data in its metadata instead.
+func (h *ToolHandlers) cited(handler mcp.ToolHandler, catalog string) mcp.ToolHandler {
+	return func(ctx context.Context, args map[string]interface{}) (*mcp.ToolResult, error) {
+		result, err := handler(ctx, args)
+		if err != nil || result == nil || result.IsError || len(result.Content) == 0 {
+			return result, err
+		}
+		last := &result.Content[len(result.Content)-1]
+		if last.Type == "text" {
+			last.Text = strings.TrimRight(last.Text, "\n") + "\n\n_" + h.provenance(ctx, args, catalog).String() + "_"
+		}
+		return result, nil
+	}
+}
+
+// provenance returns the data cited names.
+func (h *ToolHandlers) provenance(ctx context.Context, args map[string]interface{}, catalog string) dataProvenance {
+	version := h.bjcpService.GetMetadata().Version
+	if version == "" {
+		version = data.DefaultGuidelineVersion
+	}
+	switch v := args["version"].(type) {
+	case string:
+		if strings.TrimSpace(v) != "" {
+			version = strings.TrimSpace(v)
+		}
+	case float64:
+		version = strconv.Itoa(int(v))
+	}
+	source := dataProvenance{BJCPVersion: version}
+	if catalog == "" || h.breweryService == nil {
+		return source
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, provenanceTimeout)
+	defer cancel()
+	sources, err := h.breweryService.ListDataSources(ctx)
+	if err != nil {
+		return source
+	}
+	if updated, ok := services.CatalogUpdatedAt(sources); ok {
+		source.Catalog, source.CatalogUpdated = catalog, updated.UTC().Format(time.DateOnly)
+	}
+	return source
+}
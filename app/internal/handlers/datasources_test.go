@@ -0,0 +1,142 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/handlers"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/mcp"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/services"
+	"github.com/CharlRitter/brewsource-mcp/app/pkg/data"
+)
+
+func newDataSources() []*services.DataSource {
+	return []*services.DataSource{
+		{Source: services.BJCPSource, UpdatedAt: time.Date(2025, 7, 26, 0, 0, 0, 0, time.UTC), Records: 118,
+			Version: "2021"},
+		{Source: services.OpenBreweryDBSource, UpdatedAt: time.Date(2024, 11, 2, 8, 30, 0, 0, time.UTC),
+			Records: 40, Version: "https://api.openbrewerydb.org/v1"},
+		{Source: services.SeedSource, UpdatedAt: time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC), Records: 92,
+			Version: "bundled"},
+	}
+}
+
+func TestHandleDataSourcesResource(t *testing.T) {
+	breweryService := newBreweryService()
+	breweryService.DataSources = newDataSources()
+	h := handlers.NewResourceHandlers(loadStyleData(t), newBeerService(), breweryService)
+
+	res, err := readResource(h, "meta://data-sources")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var parsed struct {
+		Sources []*services.DataSource `json:"sources"`
+		BJCP    struct {
+			Version string `json:"version"`
+		} `json:"bjcp"`
+		BJCPHealth data.BJCPHealth `json:"bjcp_health"`
+	}
+	if err = json.Unmarshal([]byte(res.Text), &parsed); err != nil {
+		t.Fatalf("invalid JSON response: %v", err)
+	}
+	if len(parsed.Sources) != 3 || parsed.Sources[1].Source != "openbrewerydb" || parsed.Sources[1].Records != 40 {
+		t.Errorf("expected the three data sources, got %s", res.Text)
+	}
+	if parsed.BJCP.Version != "2021" {
+		t.Errorf("expected the BJCP 2021 metadata, got %q", parsed.BJCP.Version)
+	}
+	if parsed.BJCPHealth.Styles == 0 || parsed.BJCPHealth.Version != "2021" || parsed.BJCPHealth.LoadedAt.IsZero() {
+		t.Errorf("expected the BJCP health, got %+v", parsed.BJCPHealth)
+	}
+
+	breweryService.Err = errors.New("connection refused")
+	if _, err = h.HandleDataSourcesResource(context.Background(), "meta://data-sources"); err == nil {
+		t.Error("expected an error when the data sources cannot be listed")
+	}
+	_, err = h.HandleDataSourcesResource(context.Background(), "meta://unknown")
+	var mcpErr *mcp.Error
+	if !errors.As(err, &mcpErr) || mcpErr.Code != mcp.MethodNotFound {
+		t.Errorf("expected a not-found error for an unknown meta resource, got %v", err)
+	}
+}
+
+func TestInitializeServerMetadata(t *testing.T) {
+	toolHandlers := handlers.NewToolHandlers(loadStyleData(t), newBeerService(), newBreweryService())
+	server := mcp.NewServer(toolHandlers, nil, nil)
+	msgData, _ := json.Marshal(mcp.NewMessage("initialize", mcp.InitializeRequest{}))
+	response := server.ProcessMessage(context.Background(), msgData)
+	if response.Error != nil {
+		t.Fatalf("unexpected error: %v", response.Error)
+	}
+	encoded, _ := json.Marshal(response.Result)
+	var result mcp.InitializeResponse
+	if err := json.Unmarshal(encoded, &result); err != nil {
+		t.Fatalf("invalid initialize response: %v", err)
+	}
+	if got := result.ServerInfo.Metadata["bjcp_version"]; got != "2021" {
+		t.Errorf("expected bjcp_version 2021 in serverInfo.metadata, got %q", got)
+	}
+	if got := result.ServerInfo.Metadata["bjcp_versions"]; got != "2021" {
+		t.Errorf("expected bjcp_versions 2021, got %q", got)
+	}
+}
+
+func TestToolProvenanceFooter(t *testing.T) {
+	breweryService := newBreweryService()
+	breweryService.DataSources = newDataSources()
+	toolHandlers := handlers.NewToolHandlers(loadStyleData(t), newBeerService(), breweryService)
+	server := mcp.NewServer(toolHandlers, nil, nil)
+	call := func(tool string, args map[string]interface{}) (string, *mcp.Error) {
+		t.Helper()
+		msgData, _ := json.Marshal(mcp.NewMessage("tools/call", mcp.CallToolRequest{Name: tool, Arguments: args}))
+		response := server.ProcessMessage(context.Background(), msgData)
+		if response.Error != nil {
+			return "", response.Error
+		}
+		result := response.Result.(*mcp.ToolResult)
+		return result.Content[0].Text, nil
+	}
+
+	// The tools answering in a toolResponse cite their data in its metadata, the others in a footer
+	tests := []struct {
+		tool   string
+		args   map[string]interface{}
+		footer string
+	}{
+		{"bjcp_lookup", map[string]interface{}{"style_code": "21A"}, "\n- **Data:** BJCP 2021 guidelines\n"},
+		{"find_breweries", map[string]interface{}{"name": "Test"},
+			"\n- **Data:** BJCP 2021 guidelines, breweries updated 2024-11-02\n"},
+		{"get_brewery", map[string]interface{}{"id": 1}, "\n\n_BJCP 2021 guidelines, breweries updated 2024-11-02_"},
+		{"brewery_beers", map[string]interface{}{"brewery_id": 1},
+			"\n\n_BJCP 2021 guidelines, breweries updated 2024-11-02_"},
+		{"search_beers", map[string]interface{}{"name": "Test"},
+			"\n- **Data:** BJCP 2021 guidelines, catalog updated 2024-11-02\n"},
+	}
+	for _, tt := range tests {
+		text, mcpErr := call(tt.tool, tt.args)
+		if mcpErr != nil {
+			t.Errorf("%s: unexpected error: %v", tt.tool, mcpErr)
+			continue
+		}
+		if !strings.HasSuffix(text, tt.footer) {
+			t.Errorf("%s: expected the footer %q, got %q", tt.tool, tt.footer, text)
+		}
+	}
+
+	// Without recorded catalog updates, the style guide is cited alone
+	breweryService.DataSources = nil
+	if text, _ := call("find_breweries", map[string]interface{}{"name": "Test"}); !strings.HasSuffix(text,
+		"\n- **Data:** BJCP 2021 guidelines\n") {
+		t.Errorf("expected the style guide cited alone, got %q", text)
+	}
+	// Errors carry no footer
+	if _, mcpErr := call("bjcp_lookup", map[string]interface{}{"style_code": "99Z"}); mcpErr == nil ||
+		strings.Contains(mcpErr.Message, "guidelines") {
+		t.Errorf("expected a plain error for an unknown style, got %v", mcpErr)
+	}
+}
@@ -0,0 +1,223 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/mcp"
+)
+
+// filterDefault notes a filter applied with its default value, the argument being absent.
+const filterDefault = "default"
+
+// toolResponse builds a tool result in the envelope the lookup and search tools share: a header, the
+// body, notes on any warnings, and a metadata section listing the filters applied, after
+// normalization, the number of results, the time taken, and the data answered from. A second
+// content item holds the same as JSON, with the results themselves.
+type toolResponse struct {
+	tool    string
+	title   string
+	now     func() time.Time // the handlers' clock
+	started time.Time
+	body    strings.Builder
+	filters []appliedFilter
+	count   int         // results shown
+	total   int         // results matching, when more than count
+	results interface{} // encoded as the JSON item's results
+	// warnings are problems that did not fail the call, such as a capped limit
+	warnings []string
+}
+
+// appliedFilter is a filter a tool applied, with its value after normalization.
+type appliedFilter struct {
+	Name  string      `json:"name"`
+	Value interface{} `json:"value"`
+	// Note says how the value differs from the argument, such as "default" or "capped from 500".
+	Note string `json:"note,omitempty"`
+}
+
+// toolEnvelope is the JSON content item of a toolResponse.
+type toolEnvelope struct {
+	Tool      string          `json:"tool"`
+	Filters   []appliedFilter `json:"filters"`
+	Count     int             `json:"count"`
+	Total     int             `json:"total"`
+	ElapsedMS int64           `json:"elapsed_ms"`
+	Data      dataProvenance  `json:"data"`
+	Results   interface{}     `json:"results"`
+	Warnings  []string        `json:"warnings,omitempty"`
+}
+
+// newToolResponse starts the response of tool, headed by title, timing it by now from now on.
+func newToolResponse(tool, title string, now func() time.Time) *toolResponse {
+	return &toolResponse{tool: tool, title: title, now: now, started: now()}
+}
+
+// filter records a filter applied with value, and a note on how it was arrived at, if any. Empty
+// values, such as blank strings, empty lists, and nil bounds, were not applied and are left out.
+func (r *toolResponse) filter(name string, value interface{}, note string) {
+	switch v := value.(type) {
+	case string:
+		if v = strings.TrimSpace(v); v == "" {
+			return
+		}
+		value = v
+	case []string:
+		if len(v) == 0 {
+			return
+		}
+	case *float64:
+		if v == nil {
+			return
+		}
+		value = *v
+	case bool:
+		if !v {
+			return
+		}
+	case nil:
+		return
+	}
+	r.filters = append(r.filters, appliedFilter{Name: name, Value: value, Note: note})
+}
+
+// warn records a problem that did not fail the call, shown under the notes of the response and
+// returned as a warning of its result.
+func (r *toolResponse) warn(format string, args ...interface{}) {
+	r.warnings = append(r.warnings, fmt.Sprintf(format, args...))
+}
+
+// setResults records the results shown, count of them, out of total matching.
+func (r *toolResponse) setResults(results interface{}, count, total int) {
+	r.results, r.count, r.total = results, count, total
+}
+
+// result renders the response, citing source as the data answered from.
+func (r *toolResponse) result(source dataProvenance) (*mcp.ToolResult, error) {
+	envelope := toolEnvelope{
+		Tool:      r.tool,
+		Filters:   r.filters,
+		Count:     r.count,
+		Total:     max(r.total, r.count),
+		ElapsedMS: r.now().Sub(r.started).Milliseconds(),
+		Data:      source,
+		Results:   r.results,
+		Warnings:  r.warnings,
+	}
+	if envelope.Filters == nil {
+		envelope.Filters = []appliedFilter{}
+	}
+	encoded, err := json.Marshal(envelope)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s result: %w", r.tool, err)
+	}
+	return &mcp.ToolResult{
+		Content: []mcp.ToolContent{
+			{Type: "text", Text: r.markdown(envelope)},
+			{Type: "text", Text: string(encoded)},
+		},
+		Warnings: r.warnings,
+	}, nil
+}
+
+// markdown renders the header, body, notes, and metadata section of the envelope.
+func (r *toolResponse) markdown(envelope toolEnvelope) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("## %s\n\n", r.title))
+	sb.WriteString(strings.TrimRight(r.body.String(), "\n"))
+	if len(envelope.Warnings) > 0 {
+		sb.WriteString("\n\n### Notes\n\n- ")
+		sb.WriteString(strings.Join(envelope.Warnings, "\n- "))
+	}
+	sb.WriteString("\n\n---\n\n")
+
+	filters := make([]string, len(envelope.Filters))
+	for i, filter := range envelope.Filters {
+		filters[i] = filter.Name + " " + formatFilterValue(filter.Value)
+		if filter.Note != "" {
+			filters[i] += " (" + filter.Note + ")"
+		}
+	}
+	if len(filters) == 0 {
+		filters = []string{"none"}
+	}
+	sb.WriteString(fmt.Sprintf("- **Filters:** %s\n", strings.Join(filters, " · ")))
+	results := strconv.Itoa(envelope.Count)
+	if envelope.Total > envelope.Count {
+		results += fmt.Sprintf(" of %d", envelope.Total)
+	}
+	sb.WriteString(fmt.Sprintf("- **Results:** %s\n", results))
+	sb.WriteString(fmt.Sprintf("- **Elapsed:** %d ms\n", envelope.ElapsedMS))
+	sb.WriteString(fmt.Sprintf("- **Data:** %s\n", envelope.Data))
+	return sb.String()
+}
+
+// formatFilterValue shows an applied filter value: text quoted, lists of text as quoted values
+// joined by commas, and numbers as few digits as represent them.
+func formatFilterValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return strconv.Quote(v)
+	case []string:
+		quoted := make([]string, len(v))
+		for i, item := range v {
+			quoted[i] = strconv.Quote(item)
+		}
+		return strings.Join(quoted, ", ")
+	case float64:
+		return formatNumber(v)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// limitNote notes on the applied limit whether it was capped, and from what, or defaulted.
+func limitNote(args map[string]interface{}) string {
+	switch {
+	case limitCapped(args):
+		return "capped from " + requestedLimitText(args)
+	case args["limit"] == nil:
+		return filterDefault
+	}
+	return ""
+}
+
+// requestedLimitText shows the limit argument of args as it was given.
+func requestedLimitText(args map[string]interface{}) string {
+	if v, ok := args["limit"].(float64); ok {
+		return formatNumber(v)
+	}
+	return fmt.Sprint(args["limit"])
+}
+
+// filterArg is a filter argument of a search and whether the search applied a value of it.
+type filterArg struct {
+	key     string
+	applied bool
+}
+
+// warnSearchArgs warns of a limit in args capped at maxSearchLimit, and of each of filters that
+// args gave but that was blank once trimmed, and so was ignored.
+func warnSearchArgs(response *toolResponse, args map[string]interface{}, filters []filterArg) {
+	if limitCapped(args) {
+		response.warn("Limit capped at %d results; %s were requested.", maxSearchLimit, requestedLimitText(args))
+	}
+	for _, filter := range filters {
+		if value, ok := args[filter.key]; ok && value != nil && !filter.applied {
+			response.warn("The %s filter is blank once trimmed and was ignored.", filter.key)
+		}
+	}
+}
+
+// argNote notes that the applied value of the argument key is its default, when args lacks it.
+func argNote(args map[string]interface{}, key string) string {
+	if value, ok := args[key]; !ok || value == nil || value == "" {
+		return filterDefault
+	}
+	return ""
+}
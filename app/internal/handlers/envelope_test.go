@@ -0,0 +1,224 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/handlers"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/mcp"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/services"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/services/servicestest"
+)
+
+// updateGolden rewrites the golden files from the current output: go test ./internal/handlers -run
+// Golden -update. Review the diff before committing it.
+var updateGolden = flag.Bool("update", false, "rewrite the golden files of the tool output tests")
+
+// elapsedLine matches the time taken in a tool's metadata, which varies between runs.
+var elapsedLine = regexp.MustCompile(`(?m)^- \*\*Elapsed:\*\* \d+ ms$`)
+
+// envelopeToolHandlers returns tool handlers whose catalogs hold a few beers and breweries, with
+// recorded data sources.
+func envelopeToolHandlers(t *testing.T, beers []*services.BeerSearchResult) *handlers.ToolHandlers {
+	t.Helper()
+	breweryService := newBreweryService()
+	breweryService.DataSources = newDataSources()
+	breweryService.Results = append(breweryService.Results,
+		&services.BrewerySearchResult{ID: 2, Name: "Devil's Peak Brewing Company", BreweryType: "micro",
+			City: "Cape Town", State: "Western Cape", Country: "South Africa", FoundedYear: 2012,
+			Website: "https://www.devilspeakbrewing.co.za"},
+		&services.BrewerySearchResult{ID: 3, Name: "Clarens Brewery", BreweryType: "brewpub", City: "Clarens",
+			State: "Free State", Country: "South Africa"},
+	)
+	return handlers.NewToolHandlers(loadStyleData(t), &servicestest.BeerService{Results: beers}, breweryService)
+}
+
+// Test the rendered markdown of the tools answering in the shared envelope against golden files in
+// testdata/envelope, so that formatting changes are reviewed deliberately.
+func TestToolResponseGolden(t *testing.T) {
+	ipas := []*services.BeerSearchResult{
+		{ID: 1, Name: "Devil's Peak King's Blockhouse IPA", Style: "American IPA",
+			Brewery: "Devil's Peak Brewing Company", City: "Cape Town", Country: "South Africa", ABV: 6.5, IBU: 55},
+		{ID: 2, Name: "Jack Black's Skeleton Coast IPA", Style: "American IPA",
+			Brewery: "Jack Black's Brewing Company", City: "Cape Town", Country: "South Africa", ABV: 5.8, IBU: 40},
+		{ID: 3, Name: "Clarens Red Ale", Style: "American IPA",
+			Brewery: "Clarens Brewery", City: "Clarens", Country: "South Africa", ABV: 4.5, IBU: 80},
+	}
+	tests := []struct {
+		golden string
+		tool   string
+		beers  []*services.BeerSearchResult
+		args   map[string]interface{}
+	}{
+		{"bjcp_lookup_code", "bjcp_lookup", nil, map[string]interface{}{"style_code": "21a"}},
+		{"bjcp_lookup_suggestions", "bjcp_lookup", nil, map[string]interface{}{"style_name": "Amercan Wheet"}},
+		{"bjcp_lookup_category", "bjcp_lookup", nil,
+			map[string]interface{}{"category": "ipa", "version": 2021.0}},
+		{"search_beers", "search_beers", ipas, map[string]interface{}{
+			"name": "  IPA ", "style_code": "21a", "tags": "citrus, pine,", "abv_min": "5",
+			"limit": 500.0, "sort": "abv", "order": "desc",
+		}},
+		{"search_beers_paged", "search_beers", ipas, map[string]interface{}{
+			"brewery": "Cape Town", "group_by_brewery": true, "limit": 2.0, "sort": "relevance",
+		}},
+		{"search_beers_none", "search_beers", nil, map[string]interface{}{"name": "Nothing"}},
+		{"find_breweries", "find_breweries", nil, map[string]interface{}{
+			"name": "  ", "country": []interface{}{"South Africa", " "}, "type": "micro,brewpub", "limit": "2",
+			"page": 2.0,
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.golden, func(t *testing.T) {
+			server := mcp.NewServer(envelopeToolHandlers(t, tt.beers), nil, nil)
+			msgData, _ := json.Marshal(mcp.NewMessage("tools/call",
+				mcp.CallToolRequest{Name: tt.tool, Arguments: tt.args}))
+			response := server.ProcessMessage(context.Background(), msgData)
+			if response.Error != nil {
+				t.Fatalf("unexpected error: %v", response.Error)
+			}
+			result := response.Result.(*mcp.ToolResult)
+			if len(result.Content) != 2 {
+				t.Fatalf("expected markdown and JSON content, got %d blocks", len(result.Content))
+			}
+			got := elapsedLine.ReplaceAllString(result.Content[0].Text, "- **Elapsed:** N ms")
+
+			path := filepath.Join("testdata", "envelope", tt.golden+".md")
+			if *updateGolden {
+				if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+					t.Fatalf("failed to update %s: %v", path, err)
+				}
+			}
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("failed to read %s (run with -update to create it): %v", path, err)
+			}
+			if got != string(want) {
+				t.Errorf("%s differs from the output; run with -update and review the diff.\ngot:\n%s\nwant:\n%s",
+					path, got, want)
+			}
+		})
+	}
+}
+
+// Test the JSON content item parallels the markdown: the filters applied, the counts, and the data.
+func TestToolResponseJSON(t *testing.T) {
+	h := envelopeToolHandlers(t, []*services.BeerSearchResult{{ID: 7, Name: "Test IPA"}})
+	result, err := h.SearchBeers(context.Background(), map[string]interface{}{
+		"name": " Test ", "limit": 1000.0, "abv_max": 7.0,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var envelope struct {
+		Tool    string `json:"tool"`
+		Filters []struct {
+			Name  string      `json:"name"`
+			Value interface{} `json:"value"`
+			Note  string      `json:"note"`
+		} `json:"filters"`
+		Count     int   `json:"count"`
+		Total     int   `json:"total"`
+		ElapsedMS int64 `json:"elapsed_ms"`
+		Data      struct {
+			BJCPVersion    string `json:"bjcp_version"`
+			Catalog        string `json:"catalog"`
+			CatalogUpdated string `json:"catalog_updated"`
+		} `json:"data"`
+		Results  []services.BeerSearchResult `json:"results"`
+		Warnings []string                    `json:"warnings"`
+	}
+	if err = json.Unmarshal([]byte(result.Content[1].Text), &envelope); err != nil {
+		t.Fatalf("expected JSON content, got error: %v", err)
+	}
+	if envelope.Tool != "search_beers" || envelope.Count != 1 || envelope.Total != 1 || envelope.ElapsedMS < 0 {
+		t.Errorf("unexpected envelope: %+v", envelope)
+	}
+	if envelope.Data.BJCPVersion != "2021" || envelope.Data.Catalog != "catalog" ||
+		envelope.Data.CatalogUpdated != "2024-11-02" {
+		t.Errorf("unexpected data version: %+v", envelope.Data)
+	}
+	if len(envelope.Results) != 1 || envelope.Results[0].ID != 7 {
+		t.Errorf("expected the beer found, got %+v", envelope.Results)
+	}
+	wantWarnings := []string{"Limit capped at 100 results; 1000 were requested."}
+	if !slices.Equal(envelope.Warnings, wantWarnings) || !slices.Equal(result.Warnings, wantWarnings) {
+		t.Errorf("expected the capped limit warned of, got %v in the JSON and %v in the result",
+			envelope.Warnings, result.Warnings)
+	}
+
+	filters := map[string]string{}
+	for _, filter := range envelope.Filters {
+		filters[filter.Name] = filter.Note
+		switch filter.Name {
+		case "name":
+			if filter.Value != "Test" {
+				t.Errorf("expected the trimmed name, got %q", filter.Value)
+			}
+		case "limit":
+			if filter.Value != 100.0 || filter.Note != "capped from 1000" {
+				t.Errorf("expected the capped limit, got %v (%s)", filter.Value, filter.Note)
+			}
+		}
+	}
+	for name, note := range map[string]string{"name": "", "abv_max": "", "limit": "capped from 1000",
+		"sort": "default", "order": "default"} {
+		if got, ok := filters[name]; !ok || got != note {
+			t.Errorf("expected filter %s noted %q, got %q (listed %v)", name, note, got, ok)
+		}
+	}
+}
+
+// Test warnings are listed under the notes and returned with the result only when there are any.
+func TestToolResponseWarnings(t *testing.T) {
+	h := envelopeToolHandlers(t, []*services.BeerSearchResult{{ID: 7, Name: "Test IPA"}})
+	result, err := h.SearchBeers(context.Background(), map[string]interface{}{"name": "Test"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Warnings != nil || strings.Contains(result.Content[0].Text, "### Notes") ||
+		strings.Contains(result.Content[1].Text, `"warnings"`) {
+		t.Errorf("expected no warnings, got %v in %s", result.Warnings, result.Content[1].Text)
+	}
+
+	result, err = h.SearchBeers(context.Background(), map[string]interface{}{
+		"name": "Test", "brewery": "   ", "tags": " , ", "style": nil,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{
+		"The brewery filter is blank once trimmed and was ignored.",
+		"The tags filter is blank once trimmed and was ignored.",
+	}
+	if !slices.Equal(result.Warnings, want) {
+		t.Errorf("expected the blank filters warned of, got %v", result.Warnings)
+	}
+	if !strings.Contains(result.Content[0].Text, "### Notes\n\n- "+strings.Join(want, "\n- ")+"\n\n---") {
+		t.Errorf("expected the warnings under the notes, got %s", result.Content[0].Text)
+	}
+	if !strings.Contains(result.Content[1].Text, `"warnings":["The brewery filter`) {
+		t.Errorf("expected the warnings in the JSON, got %s", result.Content[1].Text)
+	}
+
+	result, err = h.FindBreweries(context.Background(), map[string]interface{}{
+		"country": "South Africa", "city": []interface{}{" "}, "limit": "250",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want = []string{
+		"Limit capped at 100 results; 250 were requested.",
+		"The city filter is blank once trimmed and was ignored.",
+	}
+	if !slices.Equal(result.Warnings, want) {
+		t.Errorf("expected the capped limit and blank city warned of, got %v", result.Warnings)
+	}
+}
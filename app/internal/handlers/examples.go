@@ -0,0 +1,206 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/mcp"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/services"
+	"github.com/CharlRitter/brewsource-mcp/app/pkg/data"
+)
+
+const (
+	// exampleCandidateLimit is the number of catalog beers considered for each commercial example.
+	exampleCandidateLimit = 10
+	// minExampleSimilarity is the lowest name similarity at which a beer is taken to be an example.
+	minExampleSimilarity = 0.7
+	// exampleCacheTTL is how long a style's resolved examples are reused.
+	exampleCacheTTL = services.DefaultCacheTTL
+)
+
+// styleExamples is a style's commercial examples resolved against the beer catalog.
+type styleExamples struct {
+	Code      string         `json:"code"`
+	Name      string         `json:"name"`
+	Matched   []exampleMatch `json:"matched"`
+	Unmatched []string       `json:"unmatched"`
+}
+
+// exampleMatch is the catalog beer found for a commercial example.
+type exampleMatch struct {
+	Example string                     `json:"example"`
+	Beer    *services.BeerSearchResult `json:"beer"`
+	Score   float64                    `json:"score"`
+}
+
+// exampleCache keeps resolved examples for exampleCacheTTL, since resolving a style takes a catalog
+// search per example. A nil cache caches nothing. Entries are keyed by the example names too, so a
+// reload of the style data never serves stale names, while beers added to the catalog show up once
+// the entry expires.
+type exampleCache struct {
+	mu      sync.Mutex
+	entries map[string]exampleCacheEntry
+}
+
+type exampleCacheEntry struct {
+	examples *styleExamples
+	expires  time.Time
+}
+
+func newExampleCache() *exampleCache {
+	return &exampleCache{entries: map[string]exampleCacheEntry{}}
+}
+
+// resolve returns the style's commercial examples matched to catalog beers, searching beerService
+// for each example name unless the style was resolved within exampleCacheTTL.
+func (c *exampleCache) resolve(
+	ctx context.Context,
+	beerService services.BeerServiceInterface,
+	style *data.BJCPStyle,
+	version string,
+) (*styleExamples, error) {
+	key := version + "/" + style.Code + "/" + strings.Join(style.CommercialExamples, "|")
+	if c != nil {
+		c.mu.Lock()
+		entry, ok := c.entries[key]
+		c.mu.Unlock()
+		if ok && time.Now().Before(entry.expires) {
+			return entry.examples, nil
+		}
+	}
+
+	examples := &styleExamples{
+		Code:      style.Code,
+		Name:      style.Name,
+		Matched:   []exampleMatch{},
+		Unmatched: []string{},
+	}
+	for _, example := range style.CommercialExamples {
+		match, err := matchExample(ctx, beerService, example)
+		if err != nil {
+			return nil, err
+		}
+		if match == nil {
+			examples.Unmatched = append(examples.Unmatched, example)
+			continue
+		}
+		examples.Matched = append(examples.Matched, *match)
+	}
+
+	if c != nil {
+		c.mu.Lock()
+		for cached, entry := range c.entries {
+			if time.Now().After(entry.expires) {
+				delete(c.entries, cached)
+			}
+		}
+		c.entries[key] = exampleCacheEntry{examples: examples, expires: time.Now().Add(exampleCacheTTL)}
+		c.mu.Unlock()
+	}
+	return examples, nil
+}
+
+// matchExample finds the catalog beer closest to a commercial example such as "Bell's Two Hearted
+// IPA", comparing it with each candidate's name with and without its brewery, or returns nil when
+// none is close enough.
+func matchExample(
+	ctx context.Context,
+	beerService services.BeerServiceInterface,
+	example string,
+) (*exampleMatch, error) {
+	candidates, err := beerService.SearchBeers(ctx, services.BeerSearchQuery{
+		Name:  exampleSearchTerm(example),
+		Limit: exampleCandidateLimit,
+		Sort:  services.SortByRelevance,
+	})
+	if err != nil {
+		return nil, serviceError(err, "failed to search for commercial example "+example)
+	}
+
+	var best *exampleMatch
+	for _, beer := range candidates {
+		score := max(data.NameSimilarity(example, beer.Name), data.NameSimilarity(example, beer.Brewery+" "+beer.Name))
+		if score >= minExampleSimilarity && (best == nil || score > best.Score) {
+			best = &exampleMatch{Example: example, Beer: beer, Score: score}
+		}
+	}
+	return best, nil
+}
+
+// exampleSearchTerm picks the longest word of an example, which is the most likely to appear in
+// the beer's name whether or not the example starts with the brewery.
+func exampleSearchTerm(example string) string {
+	term := ""
+	for _, word := range strings.Fields(example) {
+		word = strings.TrimFunc(word, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) })
+		if len([]rune(word)) > len([]rune(term)) {
+			term = word
+		}
+	}
+	return term
+}
+
+// StyleExamples resolves a BJCP style's commercial examples to beer records in the catalog, warning
+// of each example not found.
+func (h *ToolHandlers) StyleExamples(ctx context.Context, args map[string]interface{}) (*mcp.ToolResult, error) {
+	styleCode, ok := args["style_code"].(string)
+	if !ok {
+		return nil, &mcp.Error{Code: mcp.InvalidParams, Message: "style_code is required"}
+	}
+	styleCode = data.NormalizeStyleCode(styleCode)
+	if !data.IsStyleCode(styleCode) {
+		return nil, &mcp.Error{
+			Code:    mcp.InvalidParams,
+			Message: "invalid style_code format",
+			Data:    map[string]interface{}{"style_code": styleCode},
+		}
+	}
+	version, err := h.parseGuidelineVersion(args)
+	if err != nil {
+		return nil, err
+	}
+	style, err := h.bjcpService.GetStyleByCodeVersion(styleCode, version)
+	if err != nil {
+		return nil, &mcp.Error{
+			Code:    mcp.InvalidParams,
+			Message: fmt.Sprintf("BJCP style not found for: %s", styleCode),
+		}
+	}
+
+	examples, err := h.examples.resolve(ctx, h.beerService, style, version)
+	if err != nil {
+		return nil, err
+	}
+	result := mcp.NewToolResult(formatStyleExamples(examples))
+	for _, example := range examples.Unmatched {
+		result.Warnings = append(result.Warnings,
+			fmt.Sprintf("Commercial example %q was not found in the catalog.", example))
+	}
+	return result, nil
+}
+
+func formatStyleExamples(examples *styleExamples) string {
+	if len(examples.Matched) == 0 && len(examples.Unmatched) == 0 {
+		return fmt.Sprintf("%s %s lists no commercial examples.", examples.Code, examples.Name)
+	}
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("**Commercial examples of %s %s**\n\n", examples.Code, examples.Name))
+	if len(examples.Matched) > 0 {
+		response.WriteString("**In the catalog:**\n")
+		for _, match := range examples.Matched {
+			beer := match.Beer
+			response.WriteString(fmt.Sprintf("- %s → **%s** by %s (ABV %.1f%%, IBU %d) `beers://%d`\n",
+				match.Example, beer.Name, orUnknown(beer.Brewery), beer.ABV, beer.IBU, beer.ID))
+		}
+		response.WriteString("\n")
+	}
+	if len(examples.Unmatched) > 0 {
+		response.WriteString(fmt.Sprintf("**Not in the catalog:** %s\n", strings.Join(examples.Unmatched, ", ")))
+	}
+	return response.String()
+}
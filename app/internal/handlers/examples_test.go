@@ -0,0 +1,161 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/handlers"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/mcp"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/services"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/services/servicestest"
+	"github.com/CharlRitter/brewsource-mcp/app/pkg/data"
+)
+
+// exampleBeerService returns a fake catalog carrying Bell's Two Hearted Ale but not Stone IPA, only
+// the similarly named Stone Pale Ale.
+func exampleBeerService() *servicestest.BeerService {
+	return &servicestest.BeerService{
+		Results: []*services.BeerSearchResult{
+			{ID: 3, Name: "Stone Pale Ale", Brewery: "Stone Brewing", ABV: 5.4, IBU: 38},
+			{ID: 7, Name: "Two Hearted Ale", Brewery: "Bell's Brewery", ABV: 7, IBU: 55},
+		},
+	}
+}
+
+func exampleStyleData() *data.BJCPData {
+	bjcpData := matchStyleTestData()
+	style := bjcpData.Styles["21A"]
+	style.CommercialExamples = []string{"Bell's Two Hearted Ale", "Stone IPA"}
+	bjcpData.Styles["21A"] = style
+	return bjcpData
+}
+
+func TestStyleExamples(t *testing.T) {
+	beerService := exampleBeerService()
+	h := handlers.NewToolHandlers(exampleStyleData(), beerService, newBreweryService())
+
+	result, err := h.StyleExamples(context.Background(), map[string]interface{}{"style_code": " 21a "})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	text := result.Content[0].Text
+	for _, want := range []string{
+		"Commercial examples of 21A American IPA",
+		"Bell's Two Hearted Ale → **Two Hearted Ale** by Bell's Brewery (ABV 7.0%, IBU 55) `beers://7`",
+		"**Not in the catalog:** Stone IPA",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "beers://3") {
+		t.Errorf("expected Stone Pale Ale not to match Stone IPA:\n%s", text)
+	}
+	if want := `Commercial example "Stone IPA" was not found in the catalog.`; len(result.Warnings) != 1 ||
+		result.Warnings[0] != want {
+		t.Errorf("expected the unmatched example warned of, got %v", result.Warnings)
+	}
+
+	// A repeat request is answered from the cache.
+	searches := len(beerService.Queries)
+	if _, err = h.StyleExamples(context.Background(), map[string]interface{}{"style_code": "21A"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(beerService.Queries) != searches {
+		t.Errorf("expected cached examples, got %d more searches", len(beerService.Queries)-searches)
+	}
+}
+
+func TestStyleExamples_NoExamples(t *testing.T) {
+	beerService := exampleBeerService()
+	h := handlers.NewToolHandlers(exampleStyleData(), beerService, newBreweryService())
+
+	result, err := h.StyleExamples(context.Background(), map[string]interface{}{"style_code": "1A"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if text := result.Content[0].Text; text != "1A American Light Lager lists no commercial examples." {
+		t.Errorf("unexpected response %q", text)
+	}
+	if len(beerService.Queries) != 0 {
+		t.Errorf("expected no catalog searches, got %d", len(beerService.Queries))
+	}
+	if result.Warnings != nil {
+		t.Errorf("expected no warnings, got %v", result.Warnings)
+	}
+}
+
+func TestStyleExamples_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   map[string]interface{}
+		code   int
+		substr string
+	}{
+		{"missing style code", map[string]interface{}{}, mcp.InvalidParams, "style_code is required"},
+		{"invalid style code", map[string]interface{}{"style_code": "IPA"}, mcp.InvalidParams, "invalid style_code"},
+		{"unknown style", map[string]interface{}{"style_code": "99Z"}, mcp.InvalidParams, "not found"},
+		{
+			"unknown version",
+			map[string]interface{}{"style_code": "21A", "version": "1999"},
+			mcp.InvalidParams,
+			"unknown BJCP guideline version",
+		},
+	}
+	h := handlers.NewToolHandlers(exampleStyleData(), exampleBeerService(), newBreweryService())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := h.StyleExamples(context.Background(), tt.args)
+			expectMCPError(t, err, tt.code, tt.substr)
+		})
+	}
+
+	failing := handlers.NewToolHandlers(exampleStyleData(), failingBeerService(nil), newBreweryService())
+	if _, err := failing.StyleExamples(context.Background(), map[string]interface{}{"style_code": "21A"}); err == nil {
+		t.Error("expected the catalog failure to be returned")
+	}
+}
+
+func TestHandleBJCPResource_StyleExamples(t *testing.T) {
+	h := handlers.NewResourceHandlers(exampleStyleData(), exampleBeerService(), newBreweryService())
+
+	res, err := readResource(h, "bjcp://styles/21a/examples")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.URI != "bjcp://styles/21A/examples" || res.MimeType != "application/json" {
+		t.Errorf("unexpected resource content: %+v", res)
+	}
+	var parsed struct {
+		Code    string `json:"code"`
+		Matched []struct {
+			Example string                    `json:"example"`
+			Beer    services.BeerSearchResult `json:"beer"`
+		} `json:"matched"`
+		Unmatched []string `json:"unmatched"`
+	}
+	if err = json.Unmarshal([]byte(res.Text), &parsed); err != nil {
+		t.Fatalf("invalid JSON in resource text: %v", err)
+	}
+	if parsed.Code != "21A" || len(parsed.Matched) != 1 || parsed.Matched[0].Beer.ID != 7 {
+		t.Errorf("expected Two Hearted Ale matched, got %+v", parsed)
+	}
+	if len(parsed.Unmatched) != 1 || parsed.Unmatched[0] != "Stone IPA" {
+		t.Errorf("expected Stone IPA unmatched, got %v", parsed.Unmatched)
+	}
+
+	res, err = readResource(h, "bjcp://styles/1A/examples")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Text != `{"code":"1A","name":"American Light Lager","matched":[],"unmatched":[]}` {
+		t.Errorf("unexpected examples for a style without any: %s", res.Text)
+	}
+
+	_, err = readResource(h, "bjcp://2015/styles/21A/examples")
+	expectMCPError(t, err, mcp.InvalidParams, "unknown BJCP guideline version")
+	_, err = readResource(h, "bjcp://styles/99Z/examples")
+	expectMCPError(t, err, mcp.MethodNotFound, "BJCP style not found")
+}
@@ -0,0 +1,315 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/mcp"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/requestid"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/services"
+)
+
+// exportTools are the search tools whose results export_results writes to a file.
+var exportTools = []string{"find_breweries", "search_beers"}
+
+// exportFormats are the file formats of export_results.
+var exportFormats = []string{formatCSV, formatJSON}
+
+// ExportOptions bounds the files export_results writes.
+type ExportOptions struct {
+	TTL      time.Duration // how long an export can be downloaded
+	MaxBytes int64         // the largest file; the rows beyond it are left out
+	MaxRows  int           // the most rows of a file
+	BaseURL  string        // prefixes the download URLs, which are relative without it
+}
+
+// SetExportStore enables export_results, keeping its files in store, bounded by options.
+func (h *ToolHandlers) SetExportStore(store services.ExportStore, options ExportOptions) {
+	h.exportStore = store
+	h.exportOptions = options
+}
+
+// SetExportStore serves the files of export_results from store; see ServeExport.
+func (w *WebHandlers) SetExportStore(store services.ExportStore) {
+	w.exportStore = store
+}
+
+// exportToolDefinition describes export_results.
+func exportToolDefinition() mcp.Tool {
+	return mcp.Tool{
+		Name: "export_results",
+		Description: "Run a find_breweries or search_beers query for all its matches, beyond the usual page " +
+			"limit, and return a temporary download URL for them as a CSV or JSON file",
+		InputSchema: mcp.ObjectSchema(map[string]interface{}{
+			"tool": map[string]interface{}{
+				"type":        "string",
+				"description": "The search tool whose results to export",
+				"enum":        exportTools,
+			},
+			"arguments": map[string]interface{}{
+				"type":        "object",
+				"description": "The arguments of the search, as the tool takes them; limit and offset are ignored",
+			},
+			"format": map[string]interface{}{
+				"type":        "string",
+				"description": "The file format, 'csv' (default) or 'json'",
+				"enum":        exportFormats,
+			},
+		}, []string{"tool", "arguments"}),
+	}
+}
+
+// ExportResults runs a find_breweries or search_beers query for up to the configured number of rows,
+// writes the results as CSV or JSON into the export store, and returns the URL they can be
+// downloaded from until they expire. Results beyond the row or size cap are left out, and the
+// result says so.
+func (h *ToolHandlers) ExportResults(ctx context.Context, args map[string]interface{}) (*mcp.ToolResult, error) {
+	if h.exportStore == nil {
+		return nil, &mcp.Error{Code: mcp.InvalidRequest, Message: "exports are not available on this server"}
+	}
+	tool, _ := args["tool"].(string)
+	if !slices.Contains(exportTools, tool) {
+		return nil, &mcp.Error{
+			Code:    mcp.InvalidParams,
+			Message: fmt.Sprintf("tool must be one of: %s", strings.Join(exportTools, ", ")),
+		}
+	}
+	arguments, ok := args["arguments"].(map[string]interface{})
+	if !ok {
+		return nil, &mcp.Error{Code: mcp.InvalidParams, Message: "arguments must be an object of search arguments"}
+	}
+	format := formatCSV
+	if value, present := args["format"]; present {
+		format, _ = value.(string)
+		if !slices.Contains(exportFormats, format) {
+			return nil, &mcp.Error{
+				Code:    mcp.InvalidParams,
+				Message: fmt.Sprintf("Unsupported format: %v; expected csv or json", value),
+			}
+		}
+	}
+
+	var file *exportFile
+	var err error
+	if tool == "search_beers" {
+		file, err = h.exportBeers(ctx, arguments, format)
+	} else {
+		file, err = h.exportBreweries(ctx, arguments, format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	token := services.NewExportToken()
+	export := file.export(tool, h.now(), h.exportOptions.TTL)
+	if err = h.exportStore.SaveExport(ctx, token, export); err != nil {
+		return nil, fmt.Errorf("failed to save export: %w", err)
+	}
+	return mcp.NewToolResult(h.formatExport(token, format, export, file.truncated)), nil
+}
+
+// exportBeers writes the beers matching the search_beers arguments to a file.
+func (h *ToolHandlers) exportBeers(
+	ctx context.Context,
+	arguments map[string]interface{},
+	format string,
+) (*exportFile, error) {
+	query, err := parseBeerSearchQuery(arguments)
+	if err != nil {
+		return nil, err
+	}
+	if code, _ := arguments["style_code"].(string); strings.TrimSpace(code) != "" {
+		if _, err = resolveSearchStyle(h.bjcpService, code, &query); err != nil {
+			return nil, err
+		}
+	}
+	if !h.hasAnyBeerSearchParam(query) {
+		return nil, &mcp.Error{
+			Code: mcp.InvalidParams,
+			Message: "at least one search argument is required " +
+				"(name, style, style_code, brewery, location, description, tags, or an ABV, IBU, or SRM range)",
+		}
+	}
+	// One row past the cap tells that the results were cut
+	query.Offset, query.Limit = 0, h.exportOptions.MaxRows+1
+	beers, err := h.beerService.SearchBeersIter(ctx, query)
+	if err != nil {
+		return nil, exportSearchError(err, "failed to export beers")
+	}
+	file := newExportFile(format, beerTableHeader, h.exportOptions)
+	for beer, err := range beers {
+		if err != nil {
+			return nil, exportSearchError(err, "failed to export beers")
+		}
+		if !file.add(beerTableRow(beer), beer) {
+			break
+		}
+	}
+	return file, nil
+}
+
+// exportBreweries writes the breweries matching the find_breweries arguments to a file.
+func (h *ToolHandlers) exportBreweries(
+	ctx context.Context,
+	arguments map[string]interface{},
+	format string,
+) (*exportFile, error) {
+	query, err := parseBrewerySearchQuery(arguments)
+	if err != nil {
+		return nil, err
+	}
+	if !hasAnyBrewerySearchParam(query) {
+		return nil, &mcp.Error{
+			Code: mcp.InvalidParams,
+			Message: "at least one search argument is required " +
+				"(name, location, city, state, country, type, or latitude/longitude)",
+		}
+	}
+	query.Offset, query.Limit = 0, h.exportOptions.MaxRows+1
+	breweries, err := h.breweryService.SearchBreweriesIter(ctx, query)
+	if err != nil {
+		return nil, exportSearchError(err, "failed to export breweries")
+	}
+	file := newExportFile(format, breweryTableHeader, h.exportOptions)
+	for brewery, err := range breweries {
+		if err != nil {
+			return nil, exportSearchError(err, "failed to export breweries")
+		}
+		if !file.add(breweryTableRow(brewery), brewery) {
+			break
+		}
+	}
+	return file, nil
+}
+
+// exportSearchError maps the error of an export's search as the search tools do.
+func exportSearchError(err error, action string) error {
+	if errors.Is(err, services.ErrInvalidSearchQuery) || errors.Is(err, services.ErrInvalidLimit) {
+		return &mcp.Error{Code: mcp.InvalidParams, Message: err.Error()}
+	}
+	return serviceError(err, action)
+}
+
+// formatExport describes a saved export: its rows, format, size, expiry, and download URL.
+func (h *ToolHandlers) formatExport(token, format string, export *services.Export, truncated bool) string {
+	var response strings.Builder
+	fmt.Fprintf(&response, "**Export ready:** %d row(s) as %s (%d bytes)\n\n", export.Rows,
+		strings.ToUpper(format), len(export.Data))
+	fmt.Fprintf(&response, "**Download:** %s/exports/%s\n", strings.TrimSuffix(h.exportOptions.BaseURL, "/"), token)
+	fmt.Fprintf(&response, "**Expires:** %s\n", export.ExpiresAt.UTC().Format(time.RFC3339))
+	if truncated {
+		fmt.Fprintf(&response, "\nThe export stops at %d row(s): exports hold at most %d rows and %d bytes. "+
+			"Narrow the search to export the rest.\n", export.Rows, h.exportOptions.MaxRows,
+			h.exportOptions.MaxBytes)
+	}
+	return response.String()
+}
+
+// exportFile is the file of an export being written, a CSV table with a header row or a JSON
+// array, which stops at the last row that fits the row and size caps.
+type exportFile struct {
+	format    string
+	maxBytes  int64
+	maxRows   int
+	data      bytes.Buffer
+	rows      int
+	truncated bool // a row was left out
+}
+
+// newExportFile starts a file in format, holding the CSV header when it is CSV.
+func newExportFile(format string, header []string, options ExportOptions) *exportFile {
+	file := &exportFile{format: format, maxBytes: options.MaxBytes, maxRows: options.MaxRows}
+	if format == formatCSV {
+		writer := csv.NewWriter(&file.data)
+		_ = writer.Write(header) // writes to a buffer do not fail
+		writer.Flush()
+	} else {
+		file.data.WriteString("[")
+	}
+	return file
+}
+
+// add appends a row, given as its table cells for CSV and as the item encoded for JSON, unless it
+// would pass a cap. It returns false once the file is full, leaving the row out.
+func (f *exportFile) add(cells []string, item interface{}) bool {
+	var row bytes.Buffer
+	if f.format == formatCSV {
+		writer := csv.NewWriter(&row)
+		_ = writer.Write(cells)
+		writer.Flush()
+	} else {
+		if f.rows > 0 {
+			row.WriteString(",")
+		}
+		row.WriteString("\n  ")
+		encoded, err := json.Marshal(item)
+		if err != nil {
+			return false
+		}
+		row.Write(encoded)
+	}
+	if f.rows == f.maxRows || int64(f.data.Len()+row.Len()+len(f.closing())) > f.maxBytes {
+		f.truncated = true
+		return false
+	}
+	f.data.Write(row.Bytes())
+	f.rows++
+	return true
+}
+
+// closing is what ends the file after its last row.
+func (f *exportFile) closing() string {
+	if f.format == formatCSV {
+		return ""
+	}
+	return "\n]\n"
+}
+
+// export is the finished file of the results of tool, written at now and downloadable for ttl.
+func (f *exportFile) export(tool string, now time.Time, ttl time.Duration) *services.Export {
+	export := &services.Export{
+		Filename:    fmt.Sprintf("%s-%s.%s", tool, now.UTC().Format("20060102-150405"), f.format),
+		ContentType: "text/csv; charset=utf-8",
+		Rows:        f.rows,
+		ExpiresAt:   now.Add(ttl),
+		Data:        append(f.data.Bytes(), f.closing()...),
+	}
+	if f.format == formatJSON {
+		export.ContentType = "application/json"
+	}
+	return export
+}
+
+// ServeExport serves the file of export_results named by the token in the path as an attachment,
+// answering 404 Not Found once it has expired or when exports are not enabled.
+func (w *WebHandlers) ServeExport(writer http.ResponseWriter, r *http.Request) {
+	if w.exportStore == nil {
+		http.NotFound(writer, r)
+		return
+	}
+	export, err := w.exportStore.LoadExport(r.Context(), r.PathValue("token"))
+	if errors.Is(err, services.ErrExportNotFound) {
+		http.Error(writer, "Export not found or expired", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		requestid.Logger(r.Context()).WithError(err).Error("Failed to load export")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	writer.Header().Set("Content-Type", export.ContentType)
+	writer.Header().Set("Content-Disposition",
+		mime.FormatMediaType("attachment", map[string]string{"filename": export.Filename}))
+	writer.Header().Set("Cache-Control", "no-store")
+	writer.Header().Set("X-Content-Type-Options", "nosniff")
+	_, _ = writer.Write(export.Data)
+}
@@ -0,0 +1,196 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/handlers"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/mcp"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/services"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/services/servicestest"
+)
+
+// exportURL matches the download URL in an export_results result.
+var exportURL = regexp.MustCompile(`https://brewsource\.example/exports/([0-9a-f]{32})`)
+
+// newExportHandlers serves export_results from a file store, with the web handlers serving its
+// downloads.
+func newExportHandlers(
+	t *testing.T,
+	breweryService *servicestest.BreweryService,
+	options handlers.ExportOptions,
+) (*handlers.ToolHandlers, http.Handler) {
+	t.Helper()
+	store, err := services.NewFileExportStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create export store: %v", err)
+	}
+	toolHandlers := handlers.NewToolHandlers(nil, newBeerService(), breweryService)
+	toolHandlers.SetExportStore(store, options)
+	webHandlers := handlers.NewWebHandlers(nil, nil)
+	webHandlers.SetExportStore(store)
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /exports/{token}", webHandlers.ServeExport)
+	return toolHandlers, mux
+}
+
+// download fetches the export whose URL is in text.
+func download(t *testing.T, downloads http.Handler, text string) *httptest.ResponseRecorder {
+	t.Helper()
+	match := exportURL.FindStringSubmatch(text)
+	if match == nil {
+		t.Fatalf("Expected a download URL, got %q", text)
+	}
+	rec := httptest.NewRecorder()
+	downloads.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/exports/"+match[1], nil))
+	return rec
+}
+
+func exportBreweryService(count int) *servicestest.BreweryService {
+	breweryService := newBreweryService()
+	breweryService.Results = nil
+	for i := range count {
+		breweryService.Results = append(breweryService.Results, &services.BrewerySearchResult{
+			ID: i + 1, Name: "Brewery, " + string(rune('A'+i)), BreweryType: "micro", Country: "South Africa",
+		})
+	}
+	return breweryService
+}
+
+func TestExportResults_CSV(t *testing.T) {
+	breweryService := exportBreweryService(3)
+	toolHandlers, downloads := newExportHandlers(t, breweryService, handlers.ExportOptions{
+		TTL: time.Hour, MaxBytes: 1 << 20, MaxRows: 100, BaseURL: "https://brewsource.example/",
+	})
+
+	result, err := toolHandlers.ExportResults(context.Background(), map[string]interface{}{
+		"tool":      "find_breweries",
+		"arguments": map[string]interface{}{"country": "South Africa", "limit": 5.0, "offset": 20.0},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	text := result.Content[0].Text
+	if !strings.Contains(text, "3 row(s) as CSV") || !strings.Contains(text, "**Expires:**") ||
+		strings.Contains(text, "stops at") {
+		t.Errorf("Expected the rows, format, and expiry of a complete export, got %q", text)
+	}
+	if query := breweryService.Queries[len(breweryService.Queries)-1]; query.Limit != 101 || query.Offset != 0 {
+		t.Errorf("Expected the search re-run from the start past the row cap, got limit %d offset %d",
+			query.Limit, query.Offset)
+	}
+
+	rec := download(t, downloads, text)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", rec.Code)
+	}
+	if got := rec.Header().Get("Content-Disposition"); !strings.HasPrefix(got, "attachment; filename=find_breweries-") {
+		t.Errorf("Expected the file as an attachment, got %q", got)
+	}
+	if got := rec.Header().Get("Cache-Control"); got != "no-store" {
+		t.Errorf("Expected the download not to be cached, got %q", got)
+	}
+	rows, err := csv.NewReader(rec.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("Invalid CSV: %v", err)
+	}
+	if len(rows) != 4 || rows[0][1] != "name" || rows[1][1] != "Brewery, A" || rows[3][7] != "South Africa" {
+		t.Errorf("Expected the header and the 3 breweries, got %v", rows)
+	}
+}
+
+// Test that an export stops at the last row within the row and size caps, and says so.
+func TestExportResults_Caps(t *testing.T) {
+	tests := []struct {
+		name     string
+		maxRows  int
+		maxBytes int64
+		wantRows int
+	}{
+		{"row cap", 2, 1 << 20, 2},
+		{"size cap", 100, 300, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			toolHandlers, downloads := newExportHandlers(t, exportBreweryService(5), handlers.ExportOptions{
+				TTL: time.Hour, MaxBytes: tt.maxBytes, MaxRows: tt.maxRows, BaseURL: "https://brewsource.example",
+			})
+			result, err := toolHandlers.ExportResults(context.Background(), map[string]interface{}{
+				"tool":      "find_breweries",
+				"arguments": map[string]interface{}{"type": "micro"},
+				"format":    "json",
+			})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			text := result.Content[0].Text
+			if !strings.Contains(text, "as JSON") || !strings.Contains(text, "Narrow the search") {
+				t.Errorf("Expected a cut JSON export, got %q", text)
+			}
+			rec := download(t, downloads, text)
+			var breweries []services.BrewerySearchResult
+			if err := json.Unmarshal(rec.Body.Bytes(), &breweries); err != nil {
+				t.Fatalf("Invalid JSON: %v", err)
+			}
+			if len(breweries) != tt.wantRows || int64(rec.Body.Len()) > tt.maxBytes {
+				t.Errorf("Expected %d breweries within %d bytes, got %d in %d bytes", tt.wantRows, tt.maxBytes,
+					len(breweries), rec.Body.Len())
+			}
+		})
+	}
+}
+
+func TestExportResults_Errors(t *testing.T) {
+	toolHandlers, _ := newExportHandlers(t, exportBreweryService(1), handlers.ExportOptions{
+		TTL: time.Hour, MaxBytes: 1 << 20, MaxRows: 100,
+	})
+	tests := []struct {
+		name string
+		args map[string]interface{}
+		want string
+	}{
+		{"unknown tool", map[string]interface{}{"tool": "get_beer", "arguments": map[string]interface{}{}},
+			"tool must be one of"},
+		{"missing arguments", map[string]interface{}{"tool": "search_beers"}, "arguments must be an object"},
+		{"unknown format", map[string]interface{}{
+			"tool": "search_beers", "arguments": map[string]interface{}{"name": "IPA"}, "format": "xlsx",
+		}, "Unsupported format: xlsx"},
+		{"no search arguments", map[string]interface{}{"tool": "find_breweries", "arguments": map[string]interface{}{}},
+			"at least one search argument is required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := toolHandlers.ExportResults(context.Background(), tt.args)
+			expectMCPError(t, err, mcp.InvalidParams, tt.want)
+		})
+	}
+
+	_, err := handlers.NewToolHandlers(nil, nil, nil).ExportResults(context.Background(), map[string]interface{}{
+		"tool": "search_beers", "arguments": map[string]interface{}{"name": "IPA"},
+	})
+	expectMCPError(t, err, mcp.InvalidRequest, "exports are not available")
+}
+
+func TestServeExport_NotFound(t *testing.T) {
+	_, downloads := newExportHandlers(t, exportBreweryService(1), handlers.ExportOptions{})
+	for _, token := range []string{services.NewExportToken(), "not-a-token"} {
+		rec := httptest.NewRecorder()
+		downloads.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/exports/"+token, nil))
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404 for %s, got %d", token, rec.Code)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	handlers.NewWebHandlers(nil, nil).ServeExport(rec, httptest.NewRequest(http.MethodGet, "/exports/x", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 without an export store, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/mcp"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/services"
+	"github.com/CharlRitter/brewsource-mcp/app/pkg/data"
+)
+
+// Output formats a listing resource can be rendered in with ?format=.
+const (
+	formatJSON     = "json"
+	formatCSV      = "csv"
+	formatMarkdown = "markdown"
+)
+
+// resourceTable is a listing resource as rows, for the csv and markdown formats. The title and
+// notes head the markdown rendering only: CSV holds just the header and rows, so it pastes straight
+// into a spreadsheet.
+type resourceTable struct {
+	title  string
+	notes  []string
+	header []string
+	rows   [][]string
+}
+
+// resourceFormat reads the format query parameter, defaulting to JSON.
+func resourceFormat(params url.Values) (string, error) {
+	switch format := params.Get("format"); format {
+	case "", formatJSON:
+		return formatJSON, nil
+	case formatCSV, formatMarkdown:
+		return format, nil
+	default:
+		return "", mcp.NewMCPError(mcp.InvalidParams,
+			fmt.Sprintf("Unsupported format: %s; expected json, csv, or markdown", format), nil)
+	}
+}
+
+// renderTable renders table as CSV with a header row or as a markdown table.
+func renderTable(uri, format string, table resourceTable) (*mcp.ResourceContent, error) {
+	if format == formatCSV {
+		var buf bytes.Buffer
+		writer := csv.NewWriter(&buf)
+		if err := writer.Write(table.header); err != nil {
+			return nil, fmt.Errorf("failed to write CSV header: %w", err)
+		}
+		if err := writer.WriteAll(table.rows); err != nil {
+			return nil, fmt.Errorf("failed to write CSV rows: %w", err)
+		}
+		return &mcp.ResourceContent{URI: uri, MimeType: "text/csv", Text: buf.String()}, nil
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# %s\n\n", table.title)
+	for _, note := range table.notes {
+		fmt.Fprintf(&sb, "%s\n\n", note)
+	}
+	writeMarkdownRow(&sb, table.header)
+	separators := make([]string, len(table.header))
+	for i := range separators {
+		separators[i] = "---"
+	}
+	writeMarkdownRow(&sb, separators)
+	for _, row := range table.rows {
+		writeMarkdownRow(&sb, row)
+	}
+	return &mcp.ResourceContent{URI: uri, MimeType: "text/markdown", Text: sb.String()}, nil
+}
+
+// writeMarkdownRow writes one table row, escaping the pipes and backslashes in cells and folding
+// their line breaks into spaces so a cell cannot break the table.
+func writeMarkdownRow(sb *strings.Builder, cells []string) {
+	sb.WriteString("|")
+	for _, cell := range cells {
+		cell = strings.ReplaceAll(cell, `\`, `\\`)
+		cell = strings.ReplaceAll(cell, "|", `\|`)
+		cell = strings.Join(strings.Fields(cell), " ")
+		sb.WriteString(" " + cell + " |")
+	}
+	sb.WriteString("\n")
+}
+
+// pageNote describes the page of a listing shown, such as "Showing 21-40 of 57 breweries.", with the
+// URI of the next page when there is one.
+func pageNote(noun string, offset, count, total int, next string) string {
+	if count == 0 {
+		return fmt.Sprintf("No %s on this page (%d in total).", noun, total)
+	}
+	note := fmt.Sprintf("Showing %d-%d of %d %s.", offset+1, offset+count, total, noun)
+	if next != "" {
+		note += fmt.Sprintf(" Next page: %s", next)
+	}
+	return note
+}
+
+// styleTableHeader is the header of the style listings' tables, see styleTableRow.
+var styleTableHeader = []string{
+	"code", "name", "category", "abv_min", "abv_max", "ibu_min", "ibu_max", "srm_min", "srm_max",
+	"og_min", "og_max", "fg_min", "fg_max",
+}
+
+// styleTableRow is a style's code, name, category, and vital ranges.
+func styleTableRow(style data.BJCPStyle) []string {
+	v := style.Vitals
+	return []string{
+		style.Code, style.Name, style.Category,
+		formatNumber(v.ABVMin), formatNumber(v.ABVMax),
+		strconv.Itoa(v.IBUMin), strconv.Itoa(v.IBUMax),
+		formatNumber(v.SRMMin), formatNumber(v.SRMMax),
+		formatNumber(v.OGMin), formatNumber(v.OGMax),
+		formatNumber(v.FGMin), formatNumber(v.FGMax),
+	}
+}
+
+// beerTableHeader is the header of the beer listings' tables, see beerTableRow.
+var beerTableHeader = []string{"id", "name", "style", "brewery", "country", "abv", "ibu"}
+
+// beerTableRow is a beer's ID, name, style, brewery, country, and vitals.
+func beerTableRow(beer *services.BeerSearchResult) []string {
+	return []string{
+		strconv.Itoa(beer.ID), beer.Name, beer.Style, beer.Brewery, beer.Country,
+		formatNumber(beer.ABV), strconv.Itoa(beer.IBU),
+	}
+}
+
+// breweryTableHeader is the header of the brewery listings' tables, see breweryTableRow.
+var breweryTableHeader = []string{
+	"id", "name", "brewery_type", "street", "city", "state", "postal_code", "country", "phone", "website_url",
+	"founded_year", "email", "instagram", "facebook", "twitter",
+}
+
+// breweryTableRow is a brewery's ID, name, type, address, contact details, and founding year, which
+// is empty when unknown. The description is left to the JSON listings, to keep rows short.
+func breweryTableRow(brewery *services.BrewerySearchResult) []string {
+	founded := ""
+	if brewery.FoundedYear != 0 {
+		founded = strconv.Itoa(brewery.FoundedYear)
+	}
+	return []string{
+		strconv.Itoa(brewery.ID), brewery.Name, brewery.BreweryType, brewery.Street, brewery.City,
+		brewery.State, brewery.PostalCode, brewery.Country, brewery.Phone, brewery.Website,
+		founded, brewery.Email, brewery.Instagram, brewery.Facebook, brewery.Twitter,
+	}
+}
+
+// formatNumber formats a number with as few digits as represent it exactly, so 5.5 is "5.5" rather
+// than "5.500000".
+func formatNumber(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
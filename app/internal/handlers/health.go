@@ -0,0 +1,282 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/CharlRitter/brewsource-mcp/app/pkg/data"
+	"github.com/redis/go-redis/v9"
+)
+
+// Dependency statuses reported by ServeReadiness.
+const (
+	// DependencyOK means the dependency answered normally.
+	DependencyOK = "ok"
+	// DependencyDegraded means the database answered, but slowly or with every pooled connection
+	// already in use, or the BJCP data is stale after a failed reload.
+	DependencyDegraded = "degraded"
+	// DependencyDown means the dependency is configured but did not answer, or, for the BJCP data,
+	// holds no styles.
+	DependencyDown = "down"
+	// DependencyAbsent means the dependency is not configured. Redis is optional, so an absent
+	// Redis leaves the server healthy.
+	DependencyAbsent = "absent"
+	// DependencyPending means a startup step, such as the schema migrations, has not finished.
+	DependencyPending = "pending"
+)
+
+const (
+	// healthCheckTimeout bounds each dependency check.
+	healthCheckTimeout = 2 * time.Second
+	// slowPingThreshold is how long a database ping may take before the database is degraded.
+	slowPingThreshold = 500 * time.Millisecond
+)
+
+// DependencyHealth is the state of one dependency in the readiness response.
+type DependencyHealth struct {
+	Status    string     `json:"status"`
+	LatencyMS int64      `json:"latency_ms,omitempty"`
+	Error     string     `json:"error,omitempty"`
+	Pool      *PoolStats `json:"pool,omitempty"`
+	// Data describes the BJCP style data, for the bjcp check.
+	Data *data.BJCPHealth `json:"data,omitempty"`
+}
+
+// PoolStats describes the database connection pool, from sql.DBStats.
+type PoolStats struct {
+	MaxOpenConnections int   `json:"max_open_connections"`
+	OpenConnections    int   `json:"open_connections"`
+	InUse              int   `json:"in_use"`
+	Idle               int   `json:"idle"`
+	WaitCount          int64 `json:"wait_count"`
+	WaitDurationMS     int64 `json:"wait_duration_ms"`
+	MaxIdleClosed      int64 `json:"max_idle_closed"`
+	MaxIdleTimeClosed  int64 `json:"max_idle_time_closed"`
+	MaxLifetimeClosed  int64 `json:"max_lifetime_closed"`
+}
+
+func newPoolStats(stats sql.DBStats) *PoolStats {
+	return &PoolStats{
+		MaxOpenConnections: stats.MaxOpenConnections,
+		OpenConnections:    stats.OpenConnections,
+		InUse:              stats.InUse,
+		Idle:               stats.Idle,
+		WaitCount:          stats.WaitCount,
+		WaitDurationMS:     stats.WaitDuration.Milliseconds(),
+		MaxIdleClosed:      stats.MaxIdleClosed,
+		MaxIdleTimeClosed:  stats.MaxIdleTimeClosed,
+		MaxLifetimeClosed:  stats.MaxLifetimeClosed,
+	}
+}
+
+// CheckDatabase pings the database and reports it down when the ping fails, degraded when the ping
+// is slow or every pooled connection was in use, and ok otherwise. Pool statistics, taken before
+// the ping, are included when db provides them. A nil db is absent.
+func CheckDatabase(ctx context.Context, db interface{}) DependencyHealth {
+	if db == nil {
+		return DependencyHealth{Status: DependencyAbsent}
+	}
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	var pool *sql.DBStats
+	if s, ok := db.(interface{ Stats() sql.DBStats }); ok {
+		stats := s.Stats()
+		pool = &stats
+	}
+
+	started := time.Now()
+	var err error
+	switch p := db.(type) {
+	case interface{ PingContext(context.Context) error }:
+		err = p.PingContext(ctx)
+	case interface{ Ping() error }:
+		err = p.Ping()
+	default:
+		return DependencyHealth{Status: DependencyDown, Error: "database does not support health checks"}
+	}
+	elapsed := time.Since(started)
+
+	health := DependencyHealth{Status: DependencyOK, LatencyMS: elapsed.Milliseconds()}
+	exhausted := pool != nil && pool.MaxOpenConnections > 0 && pool.InUse >= pool.MaxOpenConnections
+	switch {
+	case err != nil:
+		health.Status = DependencyDown
+		health.Error = err.Error()
+	case elapsed >= slowPingThreshold || exhausted:
+		health.Status = DependencyDegraded
+	}
+	if pool != nil {
+		health.Pool = newPoolStats(*pool)
+	}
+	return health
+}
+
+// CheckRedis pings the Redis client and reports it ok or down. A nil client, including a nil
+// *redis.Client, is absent.
+func CheckRedis(ctx context.Context, redisClient interface{}) DependencyHealth {
+	client, ok := redisClient.(*redis.Client)
+	if redisClient == nil || (ok && client == nil) {
+		return DependencyHealth{Status: DependencyAbsent}
+	}
+	if !ok {
+		return DependencyHealth{Status: DependencyDown, Error: "redis client does not support health checks"}
+	}
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	started := time.Now()
+	health := DependencyHealth{Status: DependencyOK}
+	if err := client.Ping(ctx).Err(); err != nil {
+		health.Status = DependencyDown
+		health.Error = err.Error()
+	}
+	health.LatencyMS = time.Since(started).Milliseconds()
+	return health
+}
+
+// CheckBJCP reports the style data bjcpService serves: pending until a service is set, down when it
+// holds no styles, and degraded when its last reload failed, so that the data served is stale. The
+// check carries the service's Health.
+func CheckBJCP(bjcpService *data.BJCPService) DependencyHealth {
+	if bjcpService == nil {
+		return DependencyHealth{Status: DependencyPending}
+	}
+	bjcpHealth := bjcpService.Health()
+	health := DependencyHealth{Status: DependencyOK, Data: &bjcpHealth}
+	switch {
+	case bjcpHealth.Styles == 0:
+		health.Status, health.Error = DependencyDown, "no BJCP styles loaded"
+	case bjcpHealth.Stale():
+		health.Status, health.Error = DependencyDegraded, "last reload failed: "+bjcpHealth.LastError
+	}
+	return health
+}
+
+// SetMigrated records whether the database schema migrations completed; the server is not ready
+// until they have.
+func (w *WebHandlers) SetMigrated(migrated bool) {
+	w.migrated.Store(migrated)
+}
+
+// SetBJCPData records the loaded BJCP style guide; the server is not ready until it holds styles.
+func (w *WebHandlers) SetBJCPData(bjcpData *data.BJCPData) {
+	if bjcpData == nil {
+		w.bjcpService.Store(nil)
+		return
+	}
+	w.bjcpService.Store(data.NewBJCPServiceFromData(bjcpData))
+}
+
+// SetBJCPService is SetBJCPData for a service shared with other handlers, so that a reload of its
+// data is served here too.
+func (w *WebHandlers) SetBJCPService(bjcpService *data.BJCPService) {
+	w.bjcpService.Store(bjcpService)
+}
+
+// StartDraining marks the server as shutting down. Readiness and liveness respond 503 from then on,
+// so that load balancers stop sending traffic before the server stops accepting it.
+func (w *WebHandlers) StartDraining() {
+	w.draining.Store(true)
+}
+
+// Draining reports whether StartDraining has been called.
+func (w *WebHandlers) Draining() bool {
+	return w.draining.Load()
+}
+
+func startupCheck(done bool) DependencyHealth {
+	if done {
+		return DependencyHealth{Status: DependencyOK}
+	}
+	return DependencyHealth{Status: DependencyPending}
+}
+
+// ServeLiveness handles the /healthz endpoint. It checks no dependencies: the server is alive while
+// it can answer, and responds 503 only once it is draining for shutdown.
+func (w *WebHandlers) ServeLiveness(writer http.ResponseWriter, _ *http.Request) {
+	status, code := "alive", http.StatusOK
+	if w.Draining() {
+		status, code = "draining", http.StatusServiceUnavailable
+	}
+	writeHealth(writer, code, map[string]interface{}{
+		"status":  status,
+		"service": "brewsource-mcp",
+		"version": GetVersion(),
+	})
+}
+
+// ServeReadiness handles the /readyz endpoint, reporting the database, Redis, the schema migrations,
+// and the BJCP style guide, see CheckBJCP. It responds 503 with status "unhealthy" when the database
+// is down, startup has not finished, or no BJCP styles are loaded, and with status "draining" once
+// the server is shutting down, so that probes take the server out of service. A degraded database,
+// a configured Redis that is down, or BJCP data left stale by a failed reload responds 200 with
+// status "degraded": searches still work, without the cache or with the previous styles. An absent
+// Redis is healthy.
+func (w *WebHandlers) ServeReadiness(writer http.ResponseWriter, r *http.Request) {
+	code, response := w.readiness(r.Context())
+	writeHealth(writer, code, response)
+}
+
+// readiness checks the dependencies as ServeReadiness reports them.
+func (w *WebHandlers) readiness(ctx context.Context) (int, map[string]interface{}) {
+	checks := map[string]DependencyHealth{
+		"database":   CheckDatabase(ctx, w.db),
+		"redis":      CheckRedis(ctx, w.redisClient),
+		"migrations": startupCheck(w.migrated.Load()),
+		"bjcp":       CheckBJCP(w.bjcpService.Load()),
+	}
+
+	status, code := "healthy", http.StatusOK
+	switch {
+	case w.Draining():
+		status, code = "draining", http.StatusServiceUnavailable
+	case checks["database"].Status == DependencyDown,
+		checks["migrations"].Status == DependencyPending,
+		checks["bjcp"].Status == DependencyPending,
+		checks["bjcp"].Status == DependencyDown:
+		status, code = "unhealthy", http.StatusServiceUnavailable
+	case checks["database"].Status == DependencyDegraded,
+		checks["redis"].Status == DependencyDown,
+		checks["bjcp"].Status == DependencyDegraded:
+		status = "degraded"
+	}
+
+	return code, map[string]interface{}{
+		"status":  status,
+		"service": "brewsource-mcp",
+		"version": GetVersion(),
+		"checks":  checks,
+	}
+}
+
+// ServeHealth handles the /health endpoint, kept for existing probes as an alias of ServeReadiness.
+// Once the catalogs are set, see SetServices, it also lists when each data source last updated the
+// data, under "data_sources"; failing to list them is reported there without changing the status.
+func (w *WebHandlers) ServeHealth(writer http.ResponseWriter, r *http.Request) {
+	code, response := w.readiness(r.Context())
+	if w.breweryService != nil {
+		ctx, cancel := context.WithTimeout(r.Context(), healthCheckTimeout)
+		defer cancel()
+		if sources, err := w.breweryService.ListDataSources(ctx); err != nil {
+			response["data_sources"] = map[string]string{"error": err.Error()}
+		} else {
+			response["data_sources"] = sources
+		}
+	}
+	writeHealth(writer, code, response)
+}
+
+func writeHealth(writer http.ResponseWriter, code int, response map[string]interface{}) {
+	jsonBytes, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(code)
+	_, _ = writer.Write(jsonBytes)
+}
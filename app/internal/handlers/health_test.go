@@ -0,0 +1,296 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+
+	handlers "github.com/CharlRitter/brewsource-mcp/app/internal/handlers"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/services"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/services/servicestest"
+	"github.com/CharlRitter/brewsource-mcp/app/pkg/data"
+)
+
+type healthResponse struct {
+	Status      string                               `json:"status"`
+	Checks      map[string]handlers.DependencyHealth `json:"checks"`
+	DataSources json.RawMessage                      `json:"data_sources"`
+}
+
+// markStarted records the startup steps that readiness waits for.
+func markStarted(webHandlers *handlers.WebHandlers) {
+	webHandlers.SetMigrated(true)
+	webHandlers.SetBJCPData(&data.BJCPData{Styles: map[string]data.BJCPStyle{"21A": {Code: "21A"}}})
+}
+
+func serveHealth(t *testing.T, db interface{}, redisClient interface{}) (int, healthResponse) {
+	t.Helper()
+	webHandlers := handlers.NewWebHandlers(db, redisClient)
+	markStarted(webHandlers)
+	return serve(t, webHandlers.ServeHealth)
+}
+
+func serve(t *testing.T, handler http.HandlerFunc) (int, healthResponse) {
+	t.Helper()
+	recorder := httptest.NewRecorder()
+	handler(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	var response healthResponse
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Invalid health response %q: %v", recorder.Body.String(), err)
+	}
+	return recorder.Code, response
+}
+
+func newPingMock(t *testing.T, pingErr error) interface{} {
+	t.Helper()
+	db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	if err != nil {
+		t.Fatalf("Failed to create sqlmock: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	mock.ExpectPing().WillReturnError(pingErr)
+	return db
+}
+
+func TestServeHealth_Dependencies(t *testing.T) {
+	mr := miniredis.RunT(t)
+	liveRedis := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { liveRedis.Close() })
+	deadRedis := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1})
+	t.Cleanup(func() { deadRedis.Close() })
+
+	tests := []struct {
+		name           string
+		db             interface{}
+		redisClient    interface{}
+		expectedCode   int
+		expectedStatus string
+		expectedDB     string
+		expectedRedis  string
+	}{
+		{
+			name:           "database and redis up",
+			db:             newPingMock(t, nil),
+			redisClient:    liveRedis,
+			expectedCode:   http.StatusOK,
+			expectedStatus: "healthy",
+			expectedDB:     handlers.DependencyOK,
+			expectedRedis:  handlers.DependencyOK,
+		},
+		{
+			name:           "redis not configured",
+			db:             newPingMock(t, nil),
+			redisClient:    (*redis.Client)(nil),
+			expectedCode:   http.StatusOK,
+			expectedStatus: "healthy",
+			expectedDB:     handlers.DependencyOK,
+			expectedRedis:  handlers.DependencyAbsent,
+		},
+		{
+			name:           "redis down",
+			db:             newPingMock(t, nil),
+			redisClient:    deadRedis,
+			expectedCode:   http.StatusOK,
+			expectedStatus: "degraded",
+			expectedDB:     handlers.DependencyOK,
+			expectedRedis:  handlers.DependencyDown,
+		},
+		{
+			name:           "database down",
+			db:             newPingMock(t, errors.New("connection refused")),
+			expectedCode:   http.StatusServiceUnavailable,
+			expectedStatus: "unhealthy",
+			expectedDB:     handlers.DependencyDown,
+			expectedRedis:  handlers.DependencyAbsent,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, response := serveHealth(t, tt.db, tt.redisClient)
+			if code != tt.expectedCode {
+				t.Errorf("Expected status code %d, got %d", tt.expectedCode, code)
+			}
+			if response.Status != tt.expectedStatus {
+				t.Errorf("Expected status %q, got %q", tt.expectedStatus, response.Status)
+			}
+			database, cache := response.Checks["database"], response.Checks["redis"]
+			if database.Status != tt.expectedDB {
+				t.Errorf("Expected database %q, got %q", tt.expectedDB, database.Status)
+			}
+			if cache.Status != tt.expectedRedis {
+				t.Errorf("Expected redis %q, got %q", tt.expectedRedis, cache.Status)
+			}
+			if database.Pool == nil {
+				t.Error("Expected database pool stats")
+			}
+			if tt.expectedDB == handlers.DependencyDown && database.Error == "" {
+				t.Error("Expected the database error to be reported")
+			}
+		})
+	}
+}
+
+// Test CheckDatabase with a pool whose connections are all in use.
+func TestCheckDatabase_ExhaustedPool(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	if err != nil {
+		t.Fatalf("Failed to create sqlmock: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	mock.ExpectBegin()
+	mock.ExpectPing()
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatalf("Failed to hold the only connection: %v", err)
+	}
+	health := make(chan handlers.DependencyHealth)
+	go func() { health <- handlers.CheckDatabase(t.Context(), db) }()
+	for db.Stats().WaitCount == 0 {
+		time.Sleep(time.Millisecond) // until the ping queues behind the held connection
+	}
+	mock.ExpectRollback()
+	_ = tx.Rollback()
+
+	result := <-health
+	if result.Status != handlers.DependencyDegraded {
+		t.Errorf("Expected an exhausted pool to be degraded, got %q", result.Status)
+	}
+	if result.Pool == nil || result.Pool.InUse != 1 || result.Pool.MaxOpenConnections != 1 {
+		t.Errorf("Unexpected pool stats: %+v", result.Pool)
+	}
+}
+
+// Test ServeReadiness before startup finishes and while draining.
+func TestServeReadiness_Startup(t *testing.T) {
+	webHandlers := handlers.NewWebHandlers(nil, nil)
+	code, response := serve(t, webHandlers.ServeReadiness)
+	if code != http.StatusServiceUnavailable || response.Status != "unhealthy" {
+		t.Errorf("Expected 503 unhealthy before startup, got %d %q", code, response.Status)
+	}
+	for _, check := range []string{"migrations", "bjcp"} {
+		if response.Checks[check].Status != handlers.DependencyPending {
+			t.Errorf("Expected %s to be pending, got %q", check, response.Checks[check].Status)
+		}
+	}
+
+	webHandlers.SetMigrated(true)
+	webHandlers.SetBJCPData(&data.BJCPData{})
+	if code, response = serve(t, webHandlers.ServeReadiness); code != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503 with no BJCP styles loaded, got %d", code)
+	}
+	if bjcp := response.Checks["bjcp"]; bjcp.Status != handlers.DependencyDown || bjcp.Data == nil {
+		t.Errorf("Expected bjcp to be down with its health, got %+v", bjcp)
+	}
+
+	markStarted(webHandlers)
+	if code, response = serve(t, webHandlers.ServeReadiness); code != http.StatusOK || response.Status != "healthy" {
+		t.Errorf("Expected 200 healthy after startup, got %d %q", code, response.Status)
+	}
+
+	webHandlers.StartDraining()
+	if code, response = serve(t, webHandlers.ServeReadiness); code != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503 while draining, got %d", code)
+	}
+	if response.Status != "draining" {
+		t.Errorf("Expected status draining, got %q", response.Status)
+	}
+}
+
+// Test that a failed reload of the BJCP data degrades readiness without failing it.
+func TestServeReadiness_StaleBJCPData(t *testing.T) {
+	webHandlers := handlers.NewWebHandlers(nil, nil)
+	webHandlers.SetMigrated(true)
+	bjcpService := data.NewBJCPServiceFromData(&data.BJCPData{
+		Styles:   map[string]data.BJCPStyle{"21A": {Code: "21A"}},
+		Metadata: data.Metadata{Version: "2021"},
+	})
+	webHandlers.SetBJCPService(bjcpService)
+
+	code, response := serve(t, webHandlers.ServeReadiness)
+	bjcp := response.Checks["bjcp"]
+	if code != http.StatusOK || bjcp.Status != handlers.DependencyOK {
+		t.Errorf("Expected 200 with bjcp ok, got %d %q", code, bjcp.Status)
+	}
+	if bjcp.Data == nil || bjcp.Data.Styles != 1 || bjcp.Data.Version != "2021" || bjcp.Data.LoadedAt.IsZero() {
+		t.Errorf("Expected the BJCP health in the check, got %+v", bjcp.Data)
+	}
+
+	t.Setenv(data.BJCPDataPathEnv, filepath.Join(t.TempDir(), "missing.json"))
+	if err := bjcpService.Reload(); err == nil {
+		t.Fatal("Expected reloading a missing file to fail")
+	}
+	code, response = serve(t, webHandlers.ServeReadiness)
+	bjcp = response.Checks["bjcp"]
+	if code != http.StatusOK || response.Status != "degraded" || bjcp.Status != handlers.DependencyDegraded {
+		t.Errorf("Expected 200 degraded after a failed reload, got %d %q, bjcp %q", code, response.Status, bjcp.Status)
+	}
+	if !strings.Contains(bjcp.Error, "missing.json") || bjcp.Data.LastError == "" {
+		t.Errorf("Expected the reload error, got %+v", bjcp)
+	}
+}
+
+// Test that /health lists the data sources once the catalogs are set, and /readyz does not.
+func TestServeHealth_DataSources(t *testing.T) {
+	webHandlers := handlers.NewWebHandlers(nil, nil)
+	markStarted(webHandlers)
+	if _, response := serve(t, webHandlers.ServeHealth); response.DataSources != nil {
+		t.Errorf("Expected no data sources without the catalogs, got %s", response.DataSources)
+	}
+
+	breweryService := &servicestest.BreweryService{DataSources: []*services.DataSource{{
+		Source: services.SeedSource, UpdatedAt: time.Date(2024, 11, 2, 0, 0, 0, 0, time.UTC), Records: 92,
+		Version: "bundled",
+	}}}
+	webHandlers.SetServices(&servicestest.BeerService{}, breweryService)
+	code, response := serve(t, webHandlers.ServeHealth)
+	want := `[{"source":"seed","updated_at":"2024-11-02T00:00:00Z","records":92,"version":"bundled"}]`
+	if code != http.StatusOK || compactJSON(t, response.DataSources) != want {
+		t.Errorf("Expected 200 with the seed data source, got %d %s", code, response.DataSources)
+	}
+	if _, response = serve(t, webHandlers.ServeReadiness); response.DataSources != nil {
+		t.Errorf("Expected readiness to leave out the data sources, got %s", response.DataSources)
+	}
+
+	// A failed listing is reported without changing the status
+	breweryService.Err = errors.New("connection refused")
+	code, response = serve(t, webHandlers.ServeHealth)
+	if code != http.StatusOK || !strings.Contains(string(response.DataSources), "connection refused") {
+		t.Errorf("Expected 200 with the listing error, got %d %s", code, response.DataSources)
+	}
+}
+
+func compactJSON(t *testing.T, raw json.RawMessage) string {
+	t.Helper()
+	var compacted bytes.Buffer
+	if err := json.Compact(&compacted, raw); err != nil {
+		t.Fatalf("Invalid JSON %q: %v", raw, err)
+	}
+	return compacted.String()
+}
+
+// Test ServeLiveness ignores dependencies until the server drains.
+func TestServeLiveness(t *testing.T) {
+	webHandlers := handlers.NewWebHandlers(newPingMock(t, errors.New("connection refused")), nil)
+	if code, response := serve(t, webHandlers.ServeLiveness); code != http.StatusOK || response.Status != "alive" {
+		t.Errorf("Expected 200 alive with the database down, got %d %q", code, response.Status)
+	}
+
+	webHandlers.StartDraining()
+	if code, response := serve(t, webHandlers.ServeLiveness); code != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503 while draining, got %d %q", code, response.Status)
+	}
+}
@@ -0,0 +1,302 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/mcp"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/units"
+	"github.com/CharlRitter/brewsource-mcp/app/pkg/brewing"
+	"github.com/CharlRitter/brewsource-mcp/app/pkg/data"
+)
+
+const (
+	// defaultIngredientLimit and maxIngredientLimit bound the matches lookup_ingredient lists.
+	defaultIngredientLimit = 5
+	maxIngredientLimit     = 20
+	// ingredientSuggestionLimit is the most close matches named when an ingredient is not found.
+	ingredientSuggestionLimit = 3
+)
+
+// ingredientCollections maps the collections of the ingredients:// resources to the kind of
+// ingredient each holds.
+var ingredientCollections = map[string]string{
+	"hops":         data.IngredientHop,
+	"fermentables": data.IngredientFermentable,
+	"yeast":        data.IngredientYeast,
+}
+
+// SetIngredientService enables lookup_ingredient, and lets ibu_calculator and srm_calculator fill in
+// alpha acid and colour from ingredient names.
+func (h *ToolHandlers) SetIngredientService(ingredientService *data.IngredientService) {
+	h.ingredientService = ingredientService
+}
+
+// SetIngredientService serves the ingredients:// resources, which are not registered without it.
+// It must be called before the handlers are registered with a server.
+func (h *ResourceHandlers) SetIngredientService(ingredientService *data.IngredientService) {
+	h.ingredientService = ingredientService
+}
+
+// ingredientURI is the resource URI of an ingredient. Yeast strains are named by their code, as
+// strain names such as "Irish Ale" are shared between labs.
+func ingredientURI(match data.IngredientMatch) string {
+	switch match.Kind {
+	case data.IngredientHop:
+		return "ingredients://hops/" + url.PathEscape(match.Hop.Name)
+	case data.IngredientFermentable:
+		return "ingredients://fermentables/" + url.PathEscape(match.Fermentable.Name)
+	default:
+		return "ingredients://yeast/" + url.PathEscape(match.Yeast.Code)
+	}
+}
+
+// ingredientSuggestions lists the names of the ingredients of a kind closest to name.
+func ingredientSuggestions(ingredientService *data.IngredientService, name, kind string) []string {
+	suggestions := []string{}
+	for _, match := range ingredientService.SearchIngredients(name, kind, ingredientSuggestionLimit) {
+		suggestions = append(suggestions, match.Name)
+	}
+	return suggestions
+}
+
+// findIngredient resolves name to an ingredient of a kind, see data.IngredientService.FindIngredient.
+// An unknown name fails with notFoundCode, naming the closest ingredients.
+func findIngredient(
+	ingredientService *data.IngredientService,
+	name, kind string,
+	notFoundCode int,
+) (data.IngredientMatch, error) {
+	match, err := ingredientService.FindIngredient(name, kind)
+	if err == nil {
+		return match, nil
+	}
+	suggestions := ingredientSuggestions(ingredientService, name, kind)
+	message := fmt.Sprintf("%s%s not found: %s", strings.ToUpper(kind[:1]), kind[1:], name)
+	if len(suggestions) > 0 {
+		message += fmt.Sprintf("; did you mean %s?", strings.Join(suggestions, ", "))
+	}
+	return match, &mcp.Error{
+		Code:    notFoundCode,
+		Message: message,
+		Data:    map[string]interface{}{"name": name, "suggestions": suggestions},
+	}
+}
+
+// LookupIngredient finds hops, fermentables, and yeast strains by name, tolerating typos, and
+// describes the closest match in full.
+func (h *ToolHandlers) LookupIngredient(ctx context.Context, args map[string]interface{}) (*mcp.ToolResult, error) {
+	if h.ingredientService == nil {
+		return nil, &mcp.Error{Code: mcp.InvalidRequest, Message: "ingredient data is not available on this server"}
+	}
+	name, err := requireString(args, "name")
+	if err != nil {
+		return nil, err
+	}
+	kind, _ := args["type"].(string)
+	kind = strings.ToLower(strings.TrimSpace(kind))
+	if collectionKind, ok := ingredientCollections[kind]; ok {
+		kind = collectionKind
+	}
+	if kind != "" && !slices.Contains(data.IngredientKinds, kind) {
+		return nil, &mcp.Error{
+			Code:    mcp.InvalidParams,
+			Message: fmt.Sprintf("Unsupported ingredient type: %s; expected hop, fermentable, or yeast", kind),
+		}
+	}
+	limit := defaultIngredientLimit
+	if _, ok := args["limit"]; ok {
+		parsed, limitErr := parseLimit(args)
+		if limitErr != nil {
+			return nil, limitErr
+		}
+		limit = min(parsed, maxIngredientLimit)
+	}
+	system, err := parseUnitSystem(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := h.ingredientService.SearchIngredients(name, kind, limit)
+	if len(matches) == 0 {
+		return mcp.NewToolResult(fmt.Sprintf("No ingredients found matching '%s'.", name)), nil
+	}
+	return mcp.NewToolResult(formatIngredientMatches(matches, units.New(brewing.UnitSystem(system)))), nil
+}
+
+func formatIngredientMatches(matches []data.IngredientMatch, format units.Formatter) string {
+	var response strings.Builder
+	response.WriteString(formatIngredient(matches[0], format))
+	if len(matches) > 1 {
+		response.WriteString("\n**Other matches:**\n\n")
+		for _, match := range matches[1:] {
+			response.WriteString(fmt.Sprintf("- **%s** (%s, %.0f%% match) - %s\n",
+				match.Name, match.Kind, match.Score*100, ingredientURI(match)))
+		}
+	}
+	return response.String()
+}
+
+// formatIngredient describes an ingredient in full, giving temperatures in format's units first.
+func formatIngredient(match data.IngredientMatch, format units.Formatter) string {
+	var response strings.Builder
+	switch match.Kind {
+	case data.IngredientHop:
+		hop := match.Hop
+		response.WriteString(fmt.Sprintf("**%s** (hop, %s)\n\n", hop.Name, hop.Origin))
+		response.WriteString(fmt.Sprintf("- **Alpha Acid:** %s-%s%% (ibu_calculator assumes %s%%)\n",
+			formatNumber(hop.AlphaAcidMin), formatNumber(hop.AlphaAcidMax), formatNumber(hop.AlphaAcid())))
+		response.WriteString(fmt.Sprintf("- **Aroma:** %s\n", strings.Join(hop.Aroma, ", ")))
+		if len(hop.Substitutes) > 0 {
+			response.WriteString(fmt.Sprintf("- **Substitutes:** %s\n", strings.Join(hop.Substitutes, ", ")))
+		}
+	case data.IngredientFermentable:
+		fermentable := match.Fermentable
+		response.WriteString(fmt.Sprintf("**%s** (fermentable, %s)\n\n", fermentable.Name, fermentable.Type))
+		response.WriteString(fmt.Sprintf("- **Colour:** %s °L\n", formatNumber(fermentable.Lovibond)))
+		response.WriteString(fmt.Sprintf("- **Yield:** %s PPG\n", formatNumber(fermentable.PPG)))
+	default:
+		yeast := match.Yeast
+		response.WriteString(fmt.Sprintf("**%s** (yeast, %s)\n\n", yeast.Label(), yeast.Type))
+		response.WriteString(fmt.Sprintf("- **Attenuation:** %s-%s%%\n",
+			formatNumber(yeast.AttenuationMin), formatNumber(yeast.AttenuationMax)))
+		response.WriteString(fmt.Sprintf("- **Temperature:** %s\n",
+			format.TemperatureRange(yeast.TempMinF, yeast.TempMaxF)))
+		response.WriteString(fmt.Sprintf("- **Flocculation:** %s\n", yeast.Flocculation))
+	}
+	response.WriteString(fmt.Sprintf("- **Resource:** %s\n", ingredientURI(match)))
+	return response.String()
+}
+
+// registerIngredientResources registers the ingredients:// collections and the completion of
+// ingredient names in their templates, which resourceTemplates lists.
+func (h *ResourceHandlers) registerIngredientResources(server *mcp.Server) {
+	for collection, kind := range ingredientCollections {
+		server.RegisterResourceHandler("ingredients://"+collection, h.HandleIngredientResource)
+		server.RegisterCompletion("ingredients://"+collection+"/{name}", "name",
+			func(_ context.Context, value string) ([]string, error) {
+				return h.ingredientService.CompleteIngredientName(kind, value), nil
+			})
+	}
+}
+
+// ingredientResourceDefinitions lists the ingredients:// collections.
+func ingredientResourceDefinitions() []mcp.Resource {
+	return []mcp.Resource{
+		{
+			URI:         "ingredients://hops",
+			Name:        "Hop Varieties",
+			Description: "Hop varieties with their origin, alpha acid range, aroma descriptors, and substitutes",
+			MimeType:    "application/json",
+		},
+		{
+			URI:         "ingredients://fermentables",
+			Name:        "Fermentables",
+			Description: "Malts, adjuncts, sugars, and extracts with their type, colour in °L, and yield in PPG",
+			MimeType:    "application/json",
+		},
+		{
+			URI:  "ingredients://yeast",
+			Name: "Yeast Strains",
+			Description: "Commercial yeast strains with their lab, code, attenuation and temperature ranges, " +
+				"and flocculation",
+			MimeType: "application/json",
+		},
+	}
+}
+
+// ingredientResourceTemplates describes the single ingredient resources.
+func (h *ResourceHandlers) ingredientResourceTemplates() []resourceTemplate {
+	return []resourceTemplate{
+		{
+			ResourceTemplate: mcp.ResourceTemplate{
+				URITemplate: "ingredients://hops/{name}",
+				Name:        "Hop Details",
+				Description: "A hop variety, matched by URL-encoded name with typos tolerated " +
+					"(e.g., ingredients://hops/Citra)",
+				MimeType: "application/json",
+			},
+			handler: h.handleIngredientDetail,
+		},
+		{
+			ResourceTemplate: mcp.ResourceTemplate{
+				URITemplate: "ingredients://fermentables/{name}",
+				Name:        "Fermentable Details",
+				Description: "A malt, adjunct, sugar, or extract, matched by URL-encoded name " +
+					"(e.g., ingredients://fermentables/Crystal%2060)",
+				MimeType: "application/json",
+			},
+			handler: h.handleIngredientDetail,
+		},
+		{
+			ResourceTemplate: mcp.ResourceTemplate{
+				URITemplate: "ingredients://yeast/{name}",
+				Name:        "Yeast Strain Details",
+				Description: "A yeast strain, matched by code or name (e.g., ingredients://yeast/WLP001)",
+				MimeType:    "application/json",
+			},
+			handler: h.handleIngredientDetail,
+		},
+	}
+}
+
+// HandleIngredientResource serves an ingredients:// collection: every ingredient of its kind, in
+// the order of the data.
+func (h *ResourceHandlers) HandleIngredientResource(_ context.Context, uri string) (*mcp.ResourceContent, error) {
+	collection := strings.TrimPrefix(uri, "ingredients://")
+	ingredientData := h.ingredientService.Data()
+	var ingredients interface{}
+	count := 0
+	switch ingredientCollections[collection] {
+	case data.IngredientHop:
+		ingredients, count = ingredientData.Hops, len(ingredientData.Hops)
+	case data.IngredientFermentable:
+		ingredients, count = ingredientData.Fermentables, len(ingredientData.Fermentables)
+	case data.IngredientYeast:
+		ingredients, count = ingredientData.Yeasts, len(ingredientData.Yeasts)
+	default:
+		return nil, mcp.NewMCPError(mcp.MethodNotFound, fmt.Sprintf("Ingredient resource not found: %s", uri), nil)
+	}
+
+	content, err := json.Marshal(map[string]interface{}{collection: ingredients, "count": count})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s: %w", collection, err)
+	}
+	return &mcp.ResourceContent{URI: uri, MimeType: "application/json", Text: string(content)}, nil
+}
+
+// handleIngredientDetail serves one ingredient, named by a URL-encoded name matched as
+// lookup_ingredient matches names, with its canonical URI. A name matching no ingredient closely
+// enough fails with MethodNotFound, naming the closest ones.
+func (h *ResourceHandlers) handleIngredientDetail(
+	_ context.Context,
+	uri string,
+	params map[string]string,
+) (*mcp.ResourceContent, error) {
+	collection, _, _ := strings.Cut(strings.TrimPrefix(uri, "ingredients://"), "/")
+	name, err := url.QueryUnescape(params["name"])
+	if err != nil {
+		return nil, mcp.NewMCPError(mcp.InvalidParams, fmt.Sprintf("Invalid ingredient name: %s", params["name"]), nil)
+	}
+	match, err := findIngredient(h.ingredientService, name, ingredientCollections[collection], mcp.MethodNotFound)
+	if err != nil {
+		return nil, err
+	}
+
+	var ingredient interface{} = match.Yeast
+	switch match.Kind {
+	case data.IngredientHop:
+		ingredient = match.Hop
+	case data.IngredientFermentable:
+		ingredient = match.Fermentable
+	}
+	content, err := json.Marshal(ingredient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s: %w", match.Kind, err)
+	}
+	return &mcp.ResourceContent{URI: ingredientURI(match), MimeType: "application/json", Text: string(content)}, nil
+}
@@ -0,0 +1,173 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/handlers"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/mcp"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/services/servicestest"
+	"github.com/CharlRitter/brewsource-mcp/app/pkg/data"
+)
+
+// newIngredientServer serves the tools and resources with the embedded ingredient data.
+func newIngredientServer(t *testing.T) *mcp.Server {
+	t.Helper()
+	ingredientService, err := data.NewIngredientService()
+	if err != nil {
+		t.Fatalf("failed to load the ingredient data: %v", err)
+	}
+	toolHandlers := handlers.NewToolHandlers(nil, nil, nil)
+	toolHandlers.SetIngredientService(ingredientService)
+	resourceHandlers := handlers.NewResourceHandlers(nil, &servicestest.BeerService{}, &servicestest.BreweryService{})
+	resourceHandlers.SetIngredientService(ingredientService)
+	return mcp.NewServer(toolHandlers, resourceHandlers, nil)
+}
+
+// callTool calls a tool through server, returning its text or its error.
+func callTool(server *mcp.Server, tool string, args map[string]interface{}) (string, *mcp.Error) {
+	msg := mcp.NewMessage("tools/call", mcp.CallToolRequest{Name: tool, Arguments: args})
+	msgData, _ := json.Marshal(msg)
+	response := server.ProcessMessage(context.Background(), msgData)
+	if response.Error != nil {
+		return "", response.Error
+	}
+	return response.Result.(*mcp.ToolResult).Content[0].Text, nil
+}
+
+func TestLookupIngredient(t *testing.T) {
+	server := newIngredientServer(t)
+	tests := []struct {
+		args     map[string]interface{}
+		expected []string
+	}{
+		{
+			map[string]interface{}{"name": "cascde"},
+			[]string{"**Cascade** (hop, US)", "4.5-7% (ibu_calculator assumes 5.75%)", "**Substitutes:** Centennial",
+				"ingredients://hops/Cascade"},
+		},
+		{
+			map[string]interface{}{"name": "crystal 60", "type": "fermentable", "limit": 2},
+			[]string{"**Crystal 60** (fermentable, crystal)", "**Colour:** 60 °L", "**Other matches:**"},
+		},
+		{
+			map[string]interface{}{"name": "W-34/70", "type": "yeast"},
+			[]string{"**Fermentis W-34/70 SafLager W-34/70** (yeast, lager)", "48-59°F (9-15°C)",
+				"ingredients://yeast/W-34%2F70"},
+		},
+		{
+			map[string]interface{}{"name": "W-34/70", "type": "yeast", "units": "metric"},
+			[]string{"**Temperature:** 9-15°C (48-59°F)"},
+		},
+	}
+	for _, tt := range tests {
+		text, mcpErr := callTool(server, "lookup_ingredient", tt.args)
+		if mcpErr != nil {
+			t.Fatalf("unexpected error for %v: %v", tt.args, mcpErr)
+		}
+		for _, want := range tt.expected {
+			if !strings.Contains(text, want) {
+				t.Errorf("expected %q in the result for %v, got:\n%s", want, tt.args, text)
+			}
+		}
+	}
+
+	if text, _ := callTool(server, "lookup_ingredient", map[string]interface{}{"name": "xyzzy"}); !strings.Contains(
+		text, "No ingredients found matching 'xyzzy'") {
+		t.Errorf("expected no matches, got %q", text)
+	}
+	_, mcpErr := callTool(server, "lookup_ingredient", map[string]interface{}{"name": "Citra", "type": "spice"})
+	if mcpErr == nil || mcpErr.Code != mcp.InvalidParams {
+		t.Errorf("expected InvalidParams for an unknown type, got %v", mcpErr)
+	}
+	_, mcpErr = callTool(mcp.NewServer(handlers.NewToolHandlers(nil, nil, nil), nil, nil), "lookup_ingredient",
+		map[string]interface{}{"name": "Citra"})
+	if mcpErr == nil || mcpErr.Code != mcp.InvalidRequest {
+		t.Errorf("expected InvalidRequest without ingredient data, got %v", mcpErr)
+	}
+}
+
+func TestCalculators_IngredientNames(t *testing.T) {
+	server := newIngredientServer(t)
+
+	text, mcpErr := callTool(server, "ibu_calculator", map[string]interface{}{
+		"batch_size": 5.0, "original_gravity": 1.050,
+		"hops": []interface{}{map[string]interface{}{"name": "Citra", "weight": 1.0, "boil_time": 60.0}},
+	})
+	if mcpErr != nil {
+		t.Fatalf("unexpected error: %v", mcpErr)
+	}
+	for _, want := range []string{
+		"**Citra** (13.0% AA", "Citra: alpha acid not given; assumed 13%, the middle of Citra's 11-15% range.",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in:\n%s", want, text)
+		}
+	}
+
+	_, mcpErr = callTool(server, "ibu_calculator", map[string]interface{}{
+		"batch_size": 5.0, "original_gravity": 1.050,
+		"hops": []interface{}{map[string]interface{}{"name": "Citrus", "weight": 1.0, "boil_time": 60.0}},
+	})
+	expectInvalidParams(t, mcpErr, "hops[0]: 'alpha_acid' parameter is required, as Citrus is not a known hop "+
+		"variety (did you mean Citra")
+
+	text, mcpErr = callTool(server, "srm_calculator", map[string]interface{}{
+		"batch_size": 5.0,
+		"fermentables": []interface{}{
+			map[string]interface{}{"name": "Pale Malt", "weight": 10.0, "lovibond": 2.0},
+			map[string]interface{}{"name": "C60", "weight": 1.0},
+		},
+	})
+	if mcpErr != nil {
+		t.Fatalf("unexpected error: %v", mcpErr)
+	}
+	for _, want := range []string{"- **C60** (60 °L): 1.00 lb", "**Colour:** 10.0 SRM (19.7 EBC, 16.0 MCU)",
+		"C60: colour not given; used 60 °L for Crystal 60."} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in:\n%s", want, text)
+		}
+	}
+}
+
+func TestIngredientResources(t *testing.T) {
+	server := newIngredientServer(t)
+
+	tests := []struct {
+		uri         string
+		expectedURI string
+		contains    string
+	}{
+		{"ingredients://hops/cascde", "ingredients://hops/Cascade", `"substitutes":["Centennial","Amarillo"]`},
+		{"ingredients://fermentables/Crystal%2060", "ingredients://fermentables/Crystal%2060", `"lovibond":60`},
+		{"ingredients://yeast/wlp001", "ingredients://yeast/WLP001", `"flocculation":"medium"`},
+		{"ingredients://yeast", "ingredients://yeast", `"count":36`},
+	}
+	for _, tt := range tests {
+		content, err := server.ReadResource(context.Background(), tt.uri)
+		if err != nil {
+			t.Fatalf("unexpected error for %s: %v", tt.uri, err)
+		}
+		if content.URI != tt.expectedURI || !strings.Contains(content.Text, tt.contains) {
+			t.Errorf("%s: expected %s containing %s, got %s: %s", tt.uri, tt.expectedURI, tt.contains,
+				content.URI, content.Text)
+		}
+	}
+
+	_, err := server.ReadResource(context.Background(), "ingredients://hops/Citrus")
+	expectMCPError(t, err, mcp.MethodNotFound, "Hop not found: Citrus; did you mean Citra")
+
+	resp := server.ProcessMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":1,`+
+		`"method":"completion/complete","params":{"ref":{"type":"ref/resource","uri":"ingredients://hops/{name}"},`+
+		`"argument":{"name":"name","value":"ci"}}}`))
+	if data, _ := json.Marshal(resp.Result); !strings.Contains(string(data), `"values":["Citra"]`) {
+		t.Errorf("expected Citra to complete ci, got %s", data)
+	}
+
+	plain := handlers.NewResourceHandlers(nil, &servicestest.BeerService{}, &servicestest.BreweryService{})
+	if _, err = readResource(plain, "ingredients://hops"); err == nil {
+		t.Error("expected no ingredient resources without ingredient data")
+	}
+}
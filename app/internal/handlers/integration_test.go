@@ -0,0 +1,104 @@
+//go:build integration
+
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/mcp"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/testutil"
+)
+
+func TestMain(m *testing.M) { testutil.Main(m) }
+
+// Test the resources over a seeded PostgreSQL catalog, cached in Redis.
+func TestIntegration_Resources(t *testing.T) {
+	redisClient := testutil.Redis(t)
+	client := testutil.NewMCPServer(t, testutil.Postgres(t), redisClient)
+
+	catalog := client.ReadResource(t, "beers://catalog")
+	if !strings.Contains(catalog, "IPA") {
+		t.Errorf("Expected the seeded beers in the catalog, got:\n%.500s", catalog)
+	}
+	directory := client.ReadResource(t, "breweries://directory")
+	if !strings.Contains(directory, "South Africa") {
+		t.Errorf("Expected the seeded breweries in the directory, got:\n%.500s", directory)
+	}
+	keys, err := redisClient.Keys(context.Background(), "brewsource:resources:*").Result()
+	if err != nil || len(keys) == 0 {
+		t.Errorf("Expected the resources cached in Redis, got %v (%v)", keys, err)
+	}
+	if again := client.ReadResource(t, "beers://catalog"); again != catalog {
+		t.Error("Expected the cached catalog to match the one read from the database")
+	}
+
+	styles := client.ReadResource(t, "bjcp://styles")
+	if !strings.Contains(styles, "21A") {
+		t.Errorf("Expected the BJCP styles, got:\n%.500s", styles)
+	}
+}
+
+// Test a client session over HTTP, from initialize through searches of the catalog to a lookup of
+// a result, against PostgreSQL.
+func TestIntegration_MCPRoundTrip(t *testing.T) {
+	client := testutil.NewMCPServer(t, testutil.Postgres(t), testutil.Redis(t))
+
+	initialized := client.Call(t, "initialize", map[string]interface{}{
+		"protocolVersion": "2024-11-05",
+		"clientInfo":      map[string]interface{}{"name": "integration-test", "version": "1.0"},
+	})
+	if initialized.Error != nil {
+		t.Fatalf("Unexpected initialize error: %v", initialized.Error)
+	}
+	listed := client.Call(t, "tools/list", nil)
+	if listed.Error != nil || !strings.Contains(mustJSON(t, listed.Result), `"search_beers"`) {
+		t.Fatalf("Expected search_beers among the tools, got %v", listed)
+	}
+
+	beers := client.CallTool(t, "search_beers", map[string]interface{}{"style": "IPA", "limit": 5.0})
+	if len(beers) != 2 {
+		t.Fatalf("Expected markdown and JSON content, got %d items", len(beers))
+	}
+	var envelope struct {
+		Count   int `json:"count"`
+		Results []struct {
+			ID   int    `json:"id"`
+			Name string `json:"name"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal([]byte(beers[1]), &envelope); err != nil {
+		t.Fatalf("Expected JSON content, got error: %v", err)
+	}
+	if envelope.Count == 0 || len(envelope.Results) == 0 {
+		t.Fatalf("Expected seeded IPAs, got:\n%s", beers[0])
+	}
+
+	beer := client.CallTool(t, "get_beer", map[string]interface{}{"id": float64(envelope.Results[0].ID)})
+	if !strings.Contains(beer[0], envelope.Results[0].Name) {
+		t.Errorf("Expected %s looked up, got:\n%s", envelope.Results[0].Name, beer[0])
+	}
+
+	breweries := client.CallTool(t, "find_breweries", map[string]interface{}{"country": "South Africa", "limit": 3.0})
+	if !strings.Contains(breweries[0], "South Africa") {
+		t.Errorf("Expected South African breweries, got:\n%s", breweries[0])
+	}
+
+	missing := client.Call(t, "tools/call", mcp.CallToolRequest{
+		Name: "get_beer", Arguments: map[string]interface{}{"id": 999999.0},
+	})
+	if missing.Error == nil || missing.Error.Code != mcp.InvalidParams {
+		t.Errorf("Expected InvalidParams for a missing beer, got %+v", missing.Error)
+	}
+}
+
+func mustJSON(t *testing.T, v interface{}) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+	return string(data)
+}
@@ -0,0 +1,229 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/mcp"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/services"
+)
+
+// Job kinds, one for each admin tool that runs in the background.
+const (
+	jobSyncBreweries = "sync_breweries"
+	jobSeedCatalog   = "seed_catalog"
+	jobImportCatalog = "import_catalog"
+)
+
+// CatalogLoader seeds and imports the catalog from files on the server for the seed_catalog and
+// import_catalog admin tools, describing what each wrote. They run as jobs, and may report their
+// progress with services.ReportProgress.
+type CatalogLoader interface {
+	// Seed inserts the breweries and beers of the seed file at path missing from the catalog, or of
+	// the bundled seed data when path is empty. With reseed, the catalog is emptied first.
+	Seed(ctx context.Context, path string, reseed bool) (string, error)
+	// Import imports the brewery and beer files given; either path may be empty.
+	Import(ctx context.Context, breweriesPath, beersPath string, upsert, strict bool) (string, error)
+}
+
+// SetCatalogLoader enables the seed_catalog and import_catalog admin tools.
+func (h *ToolHandlers) SetCatalogLoader(loader CatalogLoader) {
+	h.catalogLoader = loader
+}
+
+// SetProgressNotifier sets how the admin tools that run as jobs report progress to a client that
+// sent a progress token with its call, typically mcp.Server.NotifyProgress.
+func (h *ToolHandlers) SetProgressNotifier(
+	notify func(token interface{}, progress, total float64, message string) bool,
+) {
+	h.notifyProgress = notify
+}
+
+// startJob enqueues run as a job of kind. When the call carries a progress token and a progress
+// notifier is set, every change to the job is sent to the client as a progress notification, ending
+// with its result or error. A full queue is reported as InvalidRequest.
+func (h *ToolHandlers) startJob(ctx context.Context, kind string, run services.JobFunc) (services.Job, error) {
+	var listener func(services.Job)
+	if token := mcp.ProgressToken(ctx); token != nil && h.notifyProgress != nil {
+		notify := h.notifyProgress
+		listener = func(job services.Job) {
+			message := job.Message
+			switch job.State {
+			case services.JobDone:
+				message = "done: " + job.Result
+			case services.JobFailed:
+				message = "failed: " + job.Error
+			}
+			notify(token, job.Progress, job.Total, message)
+		}
+	}
+	job, err := h.jobs.Enqueue(ctx, kind, run, listener)
+	if errors.Is(err, services.ErrJobQueueFull) {
+		return services.Job{}, &mcp.Error{Code: mcp.InvalidRequest, Message: err.Error()}
+	}
+	return job, err
+}
+
+// jobStarted describes a job just enqueued by the tool of its kind.
+func jobStarted(ctx context.Context, job services.Job, what string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Queued %s as job **%s**. It runs in the background, one job at a time; "+
+		"call get_job_status with the job ID to see how it went.", what, job.ID)
+	if mcp.ProgressToken(ctx) != nil {
+		sb.WriteString(" Its progress is sent as notifications/progress with your progress token.")
+	}
+	return sb.String()
+}
+
+// SeedCatalog enqueues seeding the catalog from a seed file on the server, see CatalogLoader.Seed.
+// Reseeding deletes the catalog, so it needs force as well.
+func (h *ToolHandlers) SeedCatalog(ctx context.Context, args map[string]interface{}) (*mcp.ToolResult, error) {
+	if err := h.checkAdminToken(args); err != nil {
+		return nil, err
+	}
+	if h.catalogLoader == nil {
+		return nil, &mcp.Error{Code: mcp.InvalidRequest, Message: "catalog seeding is not available on this server"}
+	}
+	path, _ := args["seed_file"].(string)
+	path = strings.TrimSpace(path)
+	reseed, _ := args["reseed"].(bool)
+	if force, _ := args["force"].(bool); reseed && !force {
+		return nil, &mcp.Error{
+			Code:    mcp.InvalidParams,
+			Message: "reseed deletes every brewery and beer first; pass force: true to confirm",
+		}
+	}
+
+	loader := h.catalogLoader
+	job, err := h.startJob(ctx, jobSeedCatalog, func(ctx context.Context) (string, error) {
+		return loader.Seed(ctx, path, reseed)
+	})
+	if err != nil {
+		return nil, err
+	}
+	what := "seeding the catalog from the bundled seed data"
+	if path != "" {
+		what = "seeding the catalog from " + path
+	}
+	if reseed {
+		what = "re" + what
+	}
+	return mcp.NewToolResult(jobStarted(ctx, job, what)), nil
+}
+
+// ImportCatalog enqueues importing brewery and beer files on the server, see CatalogLoader.Import.
+func (h *ToolHandlers) ImportCatalog(ctx context.Context, args map[string]interface{}) (*mcp.ToolResult, error) {
+	if err := h.checkAdminToken(args); err != nil {
+		return nil, err
+	}
+	if h.catalogLoader == nil {
+		return nil, &mcp.Error{Code: mcp.InvalidRequest, Message: "catalog import is not available on this server"}
+	}
+	breweriesPath, _ := args["breweries_file"].(string)
+	beersPath, _ := args["beers_file"].(string)
+	breweriesPath, beersPath = strings.TrimSpace(breweriesPath), strings.TrimSpace(beersPath)
+	if breweriesPath == "" && beersPath == "" {
+		return nil, &mcp.Error{
+			Code:    mcp.InvalidParams,
+			Message: "'breweries_file' or 'beers_file' parameter is required",
+		}
+	}
+	upsert, _ := args["upsert"].(bool)
+	strict, _ := args["strict"].(bool)
+
+	loader := h.catalogLoader
+	job, err := h.startJob(ctx, jobImportCatalog, func(ctx context.Context) (string, error) {
+		return loader.Import(ctx, breweriesPath, beersPath, upsert, strict)
+	})
+	if err != nil {
+		return nil, err
+	}
+	files := make([]string, 0, 2)
+	for _, path := range []string{breweriesPath, beersPath} {
+		if path != "" {
+			files = append(files, path)
+		}
+	}
+	return mcp.NewToolResult(jobStarted(ctx, job, "importing "+strings.Join(files, " and "))), nil
+}
+
+// GetJobStatus reports the job with job_id, or lists the jobs kept, newest first, without one. Jobs
+// are kept by the server rather than the connection, so any connection may ask after them.
+func (h *ToolHandlers) GetJobStatus(_ context.Context, args map[string]interface{}) (*mcp.ToolResult, error) {
+	if err := h.checkAdminToken(args); err != nil {
+		return nil, err
+	}
+	id, _ := args["job_id"].(string)
+	id = strings.TrimSpace(id)
+
+	var sb strings.Builder
+	var payload interface{}
+	if id == "" {
+		jobs := h.jobs.Jobs()
+		payload = map[string]interface{}{"jobs": jobs}
+		if len(jobs) == 0 {
+			sb.WriteString("No jobs have run since the server started.")
+		} else {
+			fmt.Fprintf(&sb, "**Jobs:** %d, newest first\n", len(jobs))
+			for _, job := range jobs {
+				sb.WriteString("\n")
+				formatJob(&sb, job)
+			}
+		}
+	} else {
+		job, ok := h.jobs.Job(id)
+		if !ok {
+			return nil, &mcp.Error{
+				Code:    mcp.InvalidParams,
+				Message: fmt.Sprintf("job %s not found; finished jobs are kept only for a while", id),
+				Data:    map[string]interface{}{"job_id": id},
+			}
+		}
+		payload = job
+		formatJob(&sb, job)
+	}
+
+	payloadJSON, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal job status: %w", err)
+	}
+	return &mcp.ToolResult{
+		Content: []mcp.ToolContent{
+			{Type: "text", Text: sb.String()},
+			{Type: "text", Text: string(payloadJSON)},
+		},
+	}, nil
+}
+
+// formatJob writes one job as a markdown list item.
+func formatJob(sb *strings.Builder, job services.Job) {
+	fmt.Fprintf(sb, "- **%s** (%s): %s", job.ID, job.Kind, job.State)
+	switch {
+	case job.State == services.JobRunning && job.Total > 0:
+		fmt.Fprintf(sb, ", %.0f of %.0f", job.Progress, job.Total)
+	case job.State == services.JobRunning && job.Progress > 0:
+		fmt.Fprintf(sb, ", %.0f done", job.Progress)
+	}
+	fmt.Fprintf(sb, "\n  - Queued: %s", job.QueuedAt.Format(time.RFC3339))
+	if job.StartedAt != nil {
+		fmt.Fprintf(sb, "\n  - Started: %s", job.StartedAt.Format(time.RFC3339))
+	}
+	if job.FinishedAt != nil {
+		fmt.Fprintf(sb, "\n  - Finished: %s", job.FinishedAt.Format(time.RFC3339))
+	}
+	switch job.State {
+	case services.JobRunning:
+		if job.Message != "" {
+			fmt.Fprintf(sb, "\n  - Progress: %s", job.Message)
+		}
+	case services.JobDone:
+		fmt.Fprintf(sb, "\n  - Result: %s", job.Result)
+	case services.JobFailed:
+		fmt.Fprintf(sb, "\n  - Error: %s", job.Error)
+	}
+	sb.WriteString("\n")
+}
@@ -0,0 +1,259 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/handlers"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/mcp"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/services"
+)
+
+// jobIDPattern finds the job ID in the output of a tool that queued a job.
+var jobIDPattern = regexp.MustCompile(`job \*\*([0-9a-f]{16})\*\*`)
+
+// waitForJobState polls get_job_status until the job with id reaches state, returning it.
+func waitForJobState(t *testing.T, toolHandlers *handlers.ToolHandlers, id, state string) services.Job {
+	t.Helper()
+	var job services.Job
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+		result, err := toolHandlers.GetJobStatus(context.Background(), map[string]interface{}{
+			"admin_token": testAdminToken, "job_id": id,
+		})
+		if err != nil {
+			t.Fatalf("Unexpected get_job_status error: %v", err)
+		}
+		if err = json.Unmarshal([]byte(result.Content[1].Text), &job); err != nil {
+			t.Fatalf("Expected JSON job status, got error: %v", err)
+		}
+		if job.State == state {
+			return job
+		}
+	}
+	t.Fatalf("Expected job %s to reach %s, got %+v", id, state, job)
+	return job
+}
+
+// mockCatalogLoader records the loads it is asked for, failing imports of a file named bad.csv.
+type mockCatalogLoader struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (m *mockCatalogLoader) record(call string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = append(m.calls, call)
+}
+
+func (m *mockCatalogLoader) Seed(_ context.Context, path string, reseed bool) (string, error) {
+	if reseed {
+		m.record("reseed " + path)
+	} else {
+		m.record("seed " + path)
+	}
+	return "Seeded from " + path + ": breweries 2 inserted", nil
+}
+
+func (m *mockCatalogLoader) Import(
+	ctx context.Context, breweriesPath, beersPath string, upsert, _ bool,
+) (string, error) {
+	m.record("import " + breweriesPath + " " + beersPath)
+	if breweriesPath == "bad.csv" {
+		return "", errors.New("failed to import breweries from bad.csv: row 2: name is required")
+	}
+	services.ReportProgress(ctx, 1, 2, "Imported breweries")
+	if upsert {
+		return "Imported breweries and beers, updating matches", nil
+	}
+	return "Imported breweries and beers", nil
+}
+
+func newJobHandlers() (*handlers.ToolHandlers, *mockCatalogLoader) {
+	toolHandlers := newAdminHandlers()
+	loader := &mockCatalogLoader{}
+	toolHandlers.SetCatalogLoader(loader)
+	return toolHandlers, loader
+}
+
+func TestSeedAndImportCatalog(t *testing.T) {
+	ctx := context.Background()
+	toolHandlers, loader := newJobHandlers()
+
+	result, err := toolHandlers.SeedCatalog(ctx, map[string]interface{}{
+		"admin_token": testAdminToken, "seed_file": " /data/seed.json ",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	text := result.Content[0].Text
+	if !strings.Contains(text, "Queued seeding the catalog from /data/seed.json as job **") ||
+		strings.Contains(text, "notifications/progress") {
+		t.Errorf("Unexpected seed_catalog output: %s", text)
+	}
+	seedJob := waitForJobState(t, toolHandlers, jobIDPattern.FindStringSubmatch(text)[1], services.JobDone)
+	if seedJob.Kind != "seed_catalog" || seedJob.Result != "Seeded from /data/seed.json: breweries 2 inserted" {
+		t.Errorf("Unexpected seed job: %+v", seedJob)
+	}
+
+	result, err = toolHandlers.ImportCatalog(ctx, map[string]interface{}{
+		"admin_token": testAdminToken, "breweries_file": "bad.csv", "beers_file": "beers.json",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	text = result.Content[0].Text
+	if !strings.Contains(text, "Queued importing bad.csv and beers.json as job **") {
+		t.Errorf("Unexpected import_catalog output: %s", text)
+	}
+	importJob := waitForJobState(t, toolHandlers, jobIDPattern.FindStringSubmatch(text)[1], services.JobFailed)
+	if !strings.Contains(importJob.Error, "row 2: name is required") {
+		t.Errorf("Expected the import error reported, got %+v", importJob)
+	}
+
+	// Without a job ID, the jobs are listed newest first
+	result, err = toolHandlers.GetJobStatus(ctx, map[string]interface{}{"admin_token": testAdminToken})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	list := result.Content[0].Text
+	for _, want := range []string{
+		"**Jobs:** 2, newest first",
+		"- **" + importJob.ID + "** (import_catalog): failed",
+		"  - Error: failed to import breweries from bad.csv: row 2: name is required",
+		"- **" + seedJob.ID + "** (seed_catalog): done",
+		"  - Result: Seeded from /data/seed.json: breweries 2 inserted",
+	} {
+		if !strings.Contains(list, want) {
+			t.Errorf("Expected %q in the job list:\n%s", want, list)
+		}
+	}
+	if strings.Index(list, importJob.ID) > strings.Index(list, seedJob.ID) {
+		t.Errorf("Expected the newest job first:\n%s", list)
+	}
+
+	loader.mu.Lock()
+	defer loader.mu.Unlock()
+	if want := []string{"seed /data/seed.json", "import bad.csv beers.json"}; strings.Join(loader.calls, "|") !=
+		strings.Join(want, "|") {
+		t.Errorf("Expected loads %v, got %v", want, loader.calls)
+	}
+}
+
+func TestJobTools_InvalidCalls(t *testing.T) {
+	ctx := context.Background()
+	toolHandlers, _ := newJobHandlers()
+	tests := []struct {
+		name         string
+		tool         func(context.Context, map[string]interface{}) (*mcp.ToolResult, error)
+		args         map[string]interface{}
+		code         int
+		wantContains string
+	}{
+		{"seed with a wrong token", toolHandlers.SeedCatalog, map[string]interface{}{"admin_token": "wrong"},
+			mcp.InvalidRequest, "invalid admin_token"},
+		{"reseed without force", toolHandlers.SeedCatalog,
+			map[string]interface{}{"admin_token": testAdminToken, "reseed": true},
+			mcp.InvalidParams, "pass force: true to confirm"},
+		{"import without files", toolHandlers.ImportCatalog,
+			map[string]interface{}{"admin_token": testAdminToken, "breweries_file": " "},
+			mcp.InvalidParams, "'breweries_file' or 'beers_file' parameter is required"},
+		{"status with a wrong token", toolHandlers.GetJobStatus, map[string]interface{}{"admin_token": "wrong"},
+			mcp.InvalidRequest, "invalid admin_token"},
+		{"unknown job", toolHandlers.GetJobStatus,
+			map[string]interface{}{"admin_token": testAdminToken, "job_id": "0123456789abcdef"},
+			mcp.InvalidParams, "job 0123456789abcdef not found"},
+		{"seed without a loader", newAdminHandlers().SeedCatalog,
+			map[string]interface{}{"admin_token": testAdminToken},
+			mcp.InvalidRequest, "catalog seeding is not available"},
+		{"import without a loader", newAdminHandlers().ImportCatalog,
+			map[string]interface{}{"admin_token": testAdminToken, "beers_file": "beers.json"},
+			mcp.InvalidRequest, "catalog import is not available"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.tool(ctx, tt.args)
+			expectMCPError(t, err, tt.code, tt.wantContains)
+		})
+	}
+
+	result, err := toolHandlers.GetJobStatus(ctx, map[string]interface{}{"admin_token": testAdminToken})
+	if err != nil || result.Content[0].Text != "No jobs have run since the server started." {
+		t.Errorf("Expected no jobs, got %v (%v)", result, err)
+	}
+}
+
+// Test a client that sends a progress token with its call is told of the job's progress, ending
+// with its result, and another connection can ask after the job.
+func TestImportCatalog_ProgressNotifications(t *testing.T) {
+	toolHandlers, _ := newJobHandlers()
+	server := mcp.NewServer(toolHandlers, nil, nil)
+	var mu sync.Mutex
+	var notifications []mcp.ProgressParams
+	server.SetNotifier(func(msg *mcp.Message) {
+		mu.Lock()
+		defer mu.Unlock()
+		if msg.Method == mcp.ProgressNotification {
+			notifications = append(notifications, msg.Params.(mcp.ProgressParams))
+		}
+	})
+	toolHandlers.SetProgressNotifier(server.NotifyProgress)
+
+	msgData, _ := json.Marshal(mcp.NewMessage("tools/call", mcp.CallToolRequest{
+		Name: "import_catalog",
+		Arguments: map[string]interface{}{
+			"admin_token": testAdminToken, "breweries_file": "breweries.csv", "upsert": true,
+		},
+		Meta: &mcp.RequestMeta{ProgressToken: "import-1"},
+	}))
+	response := server.ProcessMessage(context.Background(), msgData)
+	if response.Error != nil {
+		t.Fatalf("Unexpected error: %v", response.Error)
+	}
+	text := response.Result.(*mcp.ToolResult).Content[0].Text
+	if !strings.Contains(text, "Its progress is sent as notifications/progress with your progress token.") {
+		t.Errorf("Expected progress notifications mentioned, got: %s", text)
+	}
+
+	// Another connection, with its own context, asks after the job
+	msgData, _ = json.Marshal(mcp.NewMessage("tools/call", mcp.CallToolRequest{
+		Name: "get_job_status",
+		Arguments: map[string]interface{}{
+			"admin_token": testAdminToken, "job_id": jobIDPattern.FindStringSubmatch(text)[1],
+		},
+	}))
+	response = server.ProcessMessage(mcp.WithClientLabel(context.Background(), "other"), msgData)
+	if response.Error != nil {
+		t.Fatalf("Unexpected get_job_status error: %v", response.Error)
+	}
+	waitForJobState(t, toolHandlers, jobIDPattern.FindStringSubmatch(text)[1], services.JobDone)
+
+	// The done notification follows the job's state changing
+	received := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(notifications)
+	}
+	for deadline := time.Now().Add(time.Second); received() < 3 && time.Now().Before(deadline); {
+		time.Sleep(5 * time.Millisecond)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(notifications) != 3 {
+		t.Fatalf("Expected start, progress, and done notifications, got %+v", notifications)
+	}
+	last := notifications[len(notifications)-1]
+	if notifications[1].Progress != 1 || notifications[1].Total != 2 || notifications[1].Message != "Imported breweries" {
+		t.Errorf("Unexpected progress notification: %+v", notifications[1])
+	}
+	if last.ProgressToken != "import-1" || last.Progress != 2 || last.Total != 2 ||
+		last.Message != "done: Imported breweries and beers, updating matches" {
+		t.Errorf("Unexpected final notification: %+v", last)
+	}
+}
@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/mcp"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/requestid"
+	"github.com/sirupsen/logrus"
+)
+
+// AccessLog wraps next so every request is assigned a request ID, reusing a valid incoming
+// X-Request-Id, and logged once served with its method, path, status, size, duration and remote
+// IP. The ID is echoed in the X-Request-Id response header and carried by the request context,
+// see requestid.FromContext.
+func AccessLog(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, r *http.Request) {
+		started := time.Now()
+		id := r.Header.Get(requestid.Header)
+		if !requestid.Valid(id) {
+			id = requestid.New()
+		}
+		writer.Header().Set(requestid.Header, id)
+
+		recorder := &responseRecorder{ResponseWriter: writer}
+		next.ServeHTTP(recorder, r.WithContext(requestid.NewContext(r.Context(), id)))
+
+		logrus.WithFields(logrus.Fields{
+			requestid.Field: id,
+			"method":        r.Method,
+			"path":          r.URL.Path,
+			"status":        recorder.Status(),
+			"bytes":         recorder.bytes,
+			"duration_ms":   time.Since(started).Milliseconds(),
+			"remote_ip":     remoteIP(r),
+		}).Info("HTTP request")
+	})
+}
+
+// SetErrorReporter sets the reporter told about panics recovered by Recover. Without one, they are
+// only logged.
+func (w *WebHandlers) SetErrorReporter(reporter mcp.ErrorReporter) {
+	w.reporter = reporter
+}
+
+// Recover wraps next so a handler that panics is answered with a 500 JSON error, unless it had
+// already started its response, instead of the connection being dropped. The panic is logged with
+// its stack and the request ID, so wrap Recover in AccessLog, and reported to the error reporter.
+// http.ErrAbortHandler, which handlers panic with to abort a response on purpose, is passed on.
+func (w *WebHandlers) Recover(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, r *http.Request) {
+		recorder := &responseRecorder{ResponseWriter: writer}
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			if recovered == http.ErrAbortHandler {
+				panic(recovered)
+			}
+			panicErr := mcp.NewPanicError(recovered)
+			requestid.Logger(r.Context()).WithField("path", r.URL.Path).WithField("stack", string(panicErr.Stack)).
+				Errorf("Recovered from %v", panicErr)
+			if w.reporter != nil {
+				w.reporter.ReportError(r.Context(), panicErr)
+			}
+			if recorder.status == 0 {
+				writeAPIError(recorder, &APIError{
+					Status: http.StatusInternalServerError, Message: "Internal server error",
+				})
+			}
+		}()
+		next.ServeHTTP(recorder, r)
+	})
+}
+
+// remoteIP returns the IP address of the client connection, without its port.
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// responseRecorder records the status code and body size of a response. It passes Flush and
+// Hijack through to the underlying writer, and supports http.ResponseController through Unwrap.
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+// Status returns the status code sent: 200 when the handler wrote nothing or only flushed, and
+// 101 when it hijacked the connection without writing a header.
+func (r *responseRecorder) Status() int {
+	if r.status == 0 {
+		return http.StatusOK
+	}
+	return r.status
+}
+
+// WriteHeader records the first final status code; informational 1xx headers other than 101 may
+// precede it.
+func (r *responseRecorder) WriteHeader(code int) {
+	if r.status == 0 && (code >= http.StatusOK || code == http.StatusSwitchingProtocols) {
+		r.status = code
+	}
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	n, err := r.ResponseWriter.Write(b)
+	r.bytes += int64(n)
+	return n, err
+}
+
+// Flush sends any buffered data, which commits a 200 status if none was written.
+func (r *responseRecorder) Flush() {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack hands the connection over to the handler, after which the recorder sees no more writes.
+func (r *responseRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := r.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, http.ErrNotSupported
+	}
+	conn, rw, err := hijacker.Hijack()
+	if err == nil && r.status == 0 {
+		r.status = http.StatusSwitchingProtocols
+	}
+	return conn, rw, err
+}
+
+// Unwrap returns the underlying writer, for http.ResponseController.
+func (r *responseRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
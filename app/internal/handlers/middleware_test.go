@@ -0,0 +1,247 @@
+package handlers_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/handlers"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/mcp"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/requestid"
+	"github.com/sirupsen/logrus"
+	logtest "github.com/sirupsen/logrus/hooks/test"
+)
+
+// serveLogged serves r through AccessLog(handler) to writer and returns the fields of the access log entry.
+func serveLogged(
+	t *testing.T,
+	handler http.HandlerFunc,
+	writer http.ResponseWriter,
+	r *http.Request,
+) logrus.Fields {
+	t.Helper()
+	hook := logtest.NewGlobal()
+	t.Cleanup(hook.Reset)
+
+	handlers.AccessLog(handler).ServeHTTP(writer, r)
+
+	entry := hook.LastEntry()
+	if entry == nil || entry.Message != "HTTP request" {
+		t.Fatalf("Expected an access log entry, got %v", entry)
+	}
+	return entry.Data
+}
+
+func TestAccessLog_RequestID(t *testing.T) {
+	tests := []struct {
+		name     string
+		incoming string
+		reused   bool
+	}{
+		{"incoming ID is reused", "client-req-42", true},
+		{"missing ID is generated", "", false},
+		{"ID with spaces is replaced", "bad id", false},
+		{"overlong ID is replaced", strings.Repeat("a", 129), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var contextID string
+			handler := func(_ http.ResponseWriter, r *http.Request) {
+				contextID = requestid.FromContext(r.Context())
+			}
+			request := httptest.NewRequest(http.MethodGet, "/api/v1/styles", nil)
+			if tt.incoming != "" {
+				request.Header.Set(requestid.Header, tt.incoming)
+			}
+			recorder := httptest.NewRecorder()
+			fields := serveLogged(t, handler, recorder, request)
+
+			echoed := recorder.Header().Get(requestid.Header)
+			if tt.reused && echoed != tt.incoming {
+				t.Errorf("Expected the incoming ID %q to be echoed, got %q", tt.incoming, echoed)
+			}
+			if !tt.reused && (len(echoed) != 32 || echoed == tt.incoming) {
+				t.Errorf("Expected a generated ID, got %q", echoed)
+			}
+			if contextID != echoed || fields["request_id"] != echoed {
+				t.Errorf("Expected the context and log to carry %q, got %q and %v",
+					echoed, contextID, fields["request_id"])
+			}
+		})
+	}
+}
+
+func TestAccessLog_Fields(t *testing.T) {
+	handler := func(writer http.ResponseWriter, _ *http.Request) {
+		writer.WriteHeader(http.StatusNotFound)
+		_, _ = writer.Write([]byte("not found"))
+	}
+	request := httptest.NewRequest(http.MethodPost, "/mcp?debug=1", nil)
+	request.RemoteAddr = "203.0.113.7:51234"
+	fields := serveLogged(t, handler, httptest.NewRecorder(), request)
+
+	expected := logrus.Fields{
+		"method":    http.MethodPost,
+		"path":      "/mcp",
+		"status":    http.StatusNotFound,
+		"bytes":     int64(len("not found")),
+		"remote_ip": "203.0.113.7",
+	}
+	for key, value := range expected {
+		if fields[key] != value {
+			t.Errorf("Expected %s %v, got %v", key, value, fields[key])
+		}
+	}
+	if _, ok := fields["duration_ms"]; !ok {
+		t.Error("Expected a duration_ms field")
+	}
+}
+
+func TestAccessLog_Flush(t *testing.T) {
+	handler := func(writer http.ResponseWriter, _ *http.Request) {
+		if err := http.NewResponseController(writer).Flush(); err != nil {
+			t.Errorf("Flush failed: %v", err)
+		}
+	}
+	recorder := httptest.NewRecorder()
+	fields := serveLogged(t, handler, recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if !recorder.Flushed {
+		t.Error("Expected the flush to reach the underlying writer")
+	}
+	if fields["status"] != http.StatusOK {
+		t.Errorf("Expected a flushed response to log 200, got %v", fields["status"])
+	}
+}
+
+// hijackableRecorder is a ResponseRecorder whose connection can be hijacked.
+type hijackableRecorder struct {
+	*httptest.ResponseRecorder
+	conn net.Conn
+}
+
+func (h *hijackableRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return h.conn, bufio.NewReadWriter(bufio.NewReader(h.conn), bufio.NewWriter(h.conn)), nil
+}
+
+func TestAccessLog_Hijack(t *testing.T) {
+	server, client := net.Pipe()
+	defer client.Close()
+	writer := &hijackableRecorder{ResponseRecorder: httptest.NewRecorder(), conn: server}
+
+	handler := func(writer http.ResponseWriter, _ *http.Request) {
+		conn, _, err := http.NewResponseController(writer).Hijack()
+		if err != nil {
+			t.Errorf("Hijack failed: %v", err)
+			return
+		}
+		_ = conn.Close()
+	}
+	fields := serveLogged(t, handler, writer, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if fields["status"] != http.StatusSwitchingProtocols {
+		t.Errorf("Expected a hijacked response to log 101, got %v", fields["status"])
+	}
+
+	// A writer that cannot be hijacked reports it
+	handler = func(writer http.ResponseWriter, _ *http.Request) {
+		if _, _, err := http.NewResponseController(writer).Hijack(); err == nil {
+			t.Error("Expected an error hijacking a ResponseRecorder")
+		}
+	}
+	serveLogged(t, handler, httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+}
+
+// recordingReporter records the errors reported to it with the request IDs of their contexts.
+type recordingReporter struct {
+	mu         sync.Mutex
+	errs       []error
+	requestIDs []string
+}
+
+func (r *recordingReporter) ReportError(ctx context.Context, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.errs = append(r.errs, err)
+	r.requestIDs = append(r.requestIDs, requestid.FromContext(ctx))
+}
+
+// Test that Recover answers a panic with a 500 JSON error, reports it with the request ID, and
+// keeps serving.
+func TestRecover(t *testing.T) {
+	webHandlers := handlers.NewWebHandlers(nil, nil)
+	reporter := &recordingReporter{}
+	webHandlers.SetErrorReporter(reporter)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/panic", func(http.ResponseWriter, *http.Request) {
+		panic("catalog exploded")
+	})
+	mux.HandleFunc("/partial", func(writer http.ResponseWriter, _ *http.Request) {
+		writer.WriteHeader(http.StatusAccepted)
+		panic("catalog exploded after the header")
+	})
+	mux.HandleFunc("/abort", func(http.ResponseWriter, *http.Request) {
+		panic(http.ErrAbortHandler)
+	})
+	mux.HandleFunc("/ok", func(http.ResponseWriter, *http.Request) {})
+	server := httptest.NewServer(handlers.AccessLog(webHandlers.Recover(mux)))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/panic")
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	var body handlers.APIResponse
+	decodeErr := json.NewDecoder(resp.Body).Decode(&body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusInternalServerError || resp.Header.Get("Content-Type") != "application/json" {
+		t.Errorf("Expected a 500 JSON response, got %d %s", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	if decodeErr != nil || body.Error == nil || body.Error.Message != "Internal server error" {
+		t.Errorf("Expected an API error body without the panic value, got %+v (%v)", body.Error, decodeErr)
+	}
+	id := resp.Header.Get(requestid.Header)
+	var panicErr *mcp.PanicError
+	if len(reporter.errs) != 1 || !errors.As(reporter.errs[0], &panicErr) || panicErr.Value != "catalog exploded" {
+		t.Fatalf("Expected the panic to be reported, got %v", reporter.errs)
+	}
+	if id == "" || reporter.requestIDs[0] != id {
+		t.Errorf("Expected the report to carry request ID %q, got %q", id, reporter.requestIDs[0])
+	}
+
+	// A response already started is left as it is
+	resp, err = http.Get(server.URL + "/partial")
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted {
+		t.Errorf("Expected the status already sent, got %d", resp.StatusCode)
+	}
+
+	// An aborted response is passed on to net/http, which drops the connection without reporting it
+	if resp, err = http.Get(server.URL + "/abort"); err == nil {
+		resp.Body.Close()
+		t.Error("Expected the aborted response to drop the connection")
+	}
+	if len(reporter.errs) != 2 {
+		t.Errorf("Expected only the two panics to be reported, got %v", reporter.errs)
+	}
+
+	resp, err = http.Get(server.URL + "/ok")
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected the next request to be served, got %d", resp.StatusCode)
+	}
+}
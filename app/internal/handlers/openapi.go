@@ -0,0 +1,260 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/services"
+	"github.com/CharlRitter/brewsource-mcp/app/pkg/data"
+)
+
+// openAPIVersion is the version of the OpenAPI specification the document follows.
+const openAPIVersion = "3.1.0"
+
+// openAPISchemas are the named component schemas, derived from the types the REST API returns.
+var openAPISchemas = map[string]reflect.Type{
+	"BJCPStyle":           reflect.TypeFor[data.BJCPStyle](),
+	"BeerSearchResult":    reflect.TypeFor[services.BeerSearchResult](),
+	"BrewerySearchResult": reflect.TypeFor[services.BrewerySearchResult](),
+	"APIMeta":             reflect.TypeFor[APIMeta](),
+	"APIError":            reflect.TypeFor[APIError](),
+}
+
+// OpenAPIDocument builds the OpenAPI 3.1 description of the /api/v1 REST endpoints. Response
+// schemas are derived from the Go types the handlers encode, so the document follows them.
+func OpenAPIDocument() map[string]interface{} {
+	schemas := map[string]interface{}{}
+	for name, t := range openAPISchemas {
+		schemas[name] = jsonSchema(t)
+	}
+
+	limit := queryParam("limit", "Maximum number of results", map[string]interface{}{
+		"type":    "integer",
+		"minimum": 1,
+		"maximum": maxSearchLimit,
+		"default": defaultSearchLimit,
+	})
+	return map[string]interface{}{
+		"openapi": openAPIVersion,
+		"info": map[string]interface{}{
+			"title":       "BrewSource REST API",
+			"version":     GetVersion(),
+			"description": "BJCP styles, beers, and breweries as JSON, mirroring the BrewSource MCP tools.",
+		},
+		"paths": map[string]interface{}{
+			"/api/v1/styles/{code}": getOperation(
+				"getStyle", "Look up a BJCP style by code", schemaRef("BJCPStyle"),
+				[]interface{}{pathParam("code", "BJCP style code, such as 21A", `^[0-9]{1,2}[A-Za-z]$`)},
+				http.StatusBadRequest, http.StatusNotFound, http.StatusServiceUnavailable,
+			),
+			"/api/v1/styles": getOperation(
+				"listStyles", "List BJCP styles, ordered by code", arrayOf("BJCPStyle"),
+				[]interface{}{queryParam("category", "Only styles in this BJCP category", stringSchema())},
+				http.StatusNotFound, http.StatusServiceUnavailable,
+			),
+			"/api/v1/beers": getOperation(
+				"searchBeers", "Search beers by name or style; at least one is required", arrayOf("BeerSearchResult"),
+				[]interface{}{
+					queryParam("name", "Beer name, matched partially", stringSchema()),
+					queryParam("style", "Beer style, matched partially", stringSchema()),
+					limit,
+				},
+				http.StatusBadRequest, http.StatusInternalServerError, http.StatusServiceUnavailable,
+				http.StatusGatewayTimeout,
+			),
+			"/api/v1/breweries": getOperation(
+				"searchBreweries", "Search breweries by city or country; at least one is required",
+				arrayOf("BrewerySearchResult"),
+				[]interface{}{
+					queryParam("city", "Brewery city, matched partially", stringSchema()),
+					queryParam("country", "Brewery country, matched partially", stringSchema()),
+					limit,
+				},
+				http.StatusBadRequest, http.StatusInternalServerError, http.StatusServiceUnavailable,
+				http.StatusGatewayTimeout,
+			),
+		},
+		"components": map[string]interface{}{"schemas": schemas},
+	}
+}
+
+// ServeOpenAPI handles GET /api/openapi.json, serving OpenAPIDocument.
+func (w *WebHandlers) ServeOpenAPI(writer http.ResponseWriter, _ *http.Request) {
+	jsonBytes, err := json.MarshalIndent(OpenAPIDocument(), "", "  ")
+	if err != nil {
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(http.StatusOK)
+	_, _ = writer.Write(jsonBytes)
+}
+
+// APIDocsPageData represents the data passed to the API docs template.
+type APIDocsPageData struct {
+	ProjectName string
+	Version     string
+	SpecURL     string
+}
+
+// ServeAPIDocs handles GET /api/docs, a page that renders the OpenAPI document for people.
+func (w *WebHandlers) ServeAPIDocs(writer http.ResponseWriter, _ *http.Request) {
+	pageData := APIDocsPageData{
+		ProjectName: "BrewSource REST API",
+		Version:     GetVersion(),
+		SpecURL:     "/api/openapi.json",
+	}
+	writer.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := w.templates.ExecuteTemplate(writer, "api-docs.html", pageData); err != nil {
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+	}
+}
+
+// getOperation describes a GET path whose 200 response carries dataSchema in the APIResponse
+// envelope and whose errorCodes carry an APIError. Every operation is conditional: the 200 has an
+// ETag, and an If-None-Match holding it gets a 304.
+func getOperation(
+	id, summary string,
+	dataSchema map[string]interface{},
+	parameters []interface{},
+	errorCodes ...int,
+) map[string]interface{} {
+	etag := map[string]interface{}{
+		"ETag": map[string]interface{}{
+			"description": "Version of the response, for If-None-Match",
+			"schema":      stringSchema(),
+		},
+	}
+	success := jsonResponse("Success", envelopeSchema(dataSchema, map[string]interface{}{"type": "null"}))
+	success["headers"] = etag
+	responses := map[string]interface{}{
+		"200": success,
+		"304": map[string]interface{}{
+			"description": "Not Modified: If-None-Match holds the current ETag",
+			"headers":     etag,
+		},
+	}
+	parameters = append(parameters, map[string]interface{}{
+		"name":        "If-None-Match",
+		"in":          "header",
+		"description": "ETags of responses the client has cached; a match is answered with 304",
+		"schema":      stringSchema(),
+	})
+	for _, code := range errorCodes {
+		responses[strconv.Itoa(code)] = jsonResponse(
+			http.StatusText(code),
+			envelopeSchema(map[string]interface{}{"type": "null"}, schemaRef("APIError")),
+		)
+	}
+	return map[string]interface{}{
+		"get": map[string]interface{}{
+			"operationId": id,
+			"summary":     summary,
+			"parameters":  parameters,
+			"responses":   responses,
+		},
+	}
+}
+
+func envelopeSchema(dataSchema, errorSchema map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"type":     "object",
+		"required": []string{"data", "meta", "error"},
+		"properties": map[string]interface{}{
+			"data":  dataSchema,
+			"meta":  schemaRef("APIMeta"),
+			"error": errorSchema,
+		},
+	}
+}
+
+func jsonResponse(description string, schema map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"description": description,
+		"content": map[string]interface{}{
+			"application/json": map[string]interface{}{"schema": schema},
+		},
+	}
+}
+
+func pathParam(name, description, pattern string) map[string]interface{} {
+	return map[string]interface{}{
+		"name":        name,
+		"in":          "path",
+		"required":    true,
+		"description": description,
+		"schema":      map[string]interface{}{"type": "string", "pattern": pattern},
+	}
+}
+
+func queryParam(name, description string, schema map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"name":        name,
+		"in":          "query",
+		"description": description,
+		"schema":      schema,
+	}
+}
+
+func stringSchema() map[string]interface{} {
+	return map[string]interface{}{"type": "string"}
+}
+
+func schemaRef(name string) map[string]interface{} {
+	return map[string]interface{}{"$ref": "#/components/schemas/" + name}
+}
+
+func arrayOf(name string) map[string]interface{} {
+	return map[string]interface{}{"type": "array", "items": schemaRef(name)}
+}
+
+// jsonSchema describes how encoding/json encodes values of type t. Struct fields are named by their
+// json tags; fields without omitempty are required, and pointer fields may be null.
+func jsonSchema(t reflect.Type) map[string]interface{} {
+	if t == reflect.TypeFor[time.Time]() {
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		schema := jsonSchema(t.Elem())
+		schema["type"] = []interface{}{schema["type"], "null"}
+		return schema
+	case reflect.Struct:
+		properties := map[string]interface{}{}
+		required := []string{}
+		for i := range t.NumField() {
+			field := t.Field(i)
+			name, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if !field.IsExported() || name == "-" {
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+			properties[name] = jsonSchema(field.Type)
+			if !strings.Contains(options, "omitempty") {
+				required = append(required, name)
+			}
+		}
+		return map[string]interface{}{"type": "object", "properties": properties, "required": required}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": jsonSchema(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": jsonSchema(t.Elem())}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	default:
+		return map[string]interface{}{}
+	}
+}
@@ -0,0 +1,161 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+type openAPISpec struct {
+	OpenAPI string `json:"openapi"`
+	Info    struct {
+		Title   string `json:"title"`
+		Version string `json:"version"`
+	} `json:"info"`
+	Paths map[string]map[string]struct {
+		OperationID string `json:"operationId"`
+		Parameters  []struct {
+			Name string `json:"name"`
+			In   string `json:"in"`
+		} `json:"parameters"`
+		Responses map[string]json.RawMessage `json:"responses"`
+	} `json:"paths"`
+	Components struct {
+		Schemas map[string]struct {
+			Type       string                     `json:"type"`
+			Properties map[string]json.RawMessage `json:"properties"`
+			Required   []string                   `json:"required"`
+		} `json:"schemas"`
+	} `json:"components"`
+}
+
+func fetchOpenAPISpec(t *testing.T) (openAPISpec, string) {
+	t.Helper()
+	recorder := httptest.NewRecorder()
+	newAPIMux(newBeerService(), newBreweryService()).
+		ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/openapi.json", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, recorder.Code)
+	}
+	if contentType := recorder.Header().Get("Content-Type"); contentType != "application/json" {
+		t.Errorf("Expected Content-Type application/json, got %q", contentType)
+	}
+	var spec openAPISpec
+	if err := json.Unmarshal(recorder.Body.Bytes(), &spec); err != nil {
+		t.Fatalf("OpenAPI document is not valid JSON: %v", err)
+	}
+	return spec, recorder.Body.String()
+}
+
+func TestOpenAPIDocument(t *testing.T) {
+	spec, body := fetchOpenAPISpec(t)
+
+	if spec.OpenAPI != "3.1.0" {
+		t.Errorf("Expected OpenAPI 3.1.0, got %q", spec.OpenAPI)
+	}
+	if spec.Info.Title == "" || spec.Info.Version == "" {
+		t.Errorf("Expected an info title and version, got %+v", spec.Info)
+	}
+
+	expectedParams := map[string][]string{
+		"/api/v1/styles/{code}": {"code", "If-None-Match"},
+		"/api/v1/styles":        {"category", "If-None-Match"},
+		"/api/v1/beers":         {"name", "style", "limit", "If-None-Match"},
+		"/api/v1/breweries":     {"city", "country", "limit", "If-None-Match"},
+	}
+	if len(spec.Paths) != len(expectedParams) {
+		t.Errorf("Expected %d paths, got %d", len(expectedParams), len(spec.Paths))
+	}
+	for path, params := range expectedParams {
+		op, ok := spec.Paths[path]["get"]
+		if !ok {
+			t.Errorf("Missing GET %s", path)
+			continue
+		}
+		if op.OperationID == "" {
+			t.Errorf("GET %s has no operationId", path)
+		}
+		var names []string
+		for _, p := range op.Parameters {
+			names = append(names, p.Name)
+		}
+		if !slices.Equal(names, params) {
+			t.Errorf("GET %s: expected parameters %v, got %v", path, params, names)
+		}
+		if _, ok := op.Responses["200"]; !ok {
+			t.Errorf("GET %s has no 200 response", path)
+		}
+		if _, ok := op.Responses["304"]; !ok {
+			t.Errorf("GET %s has no 304 response", path)
+		}
+	}
+
+	// Every reference must name a component schema
+	for _, ref := range strings.Split(body, `"$ref": "#/components/schemas/`)[1:] {
+		name, _, _ := strings.Cut(ref, `"`)
+		if _, ok := spec.Components.Schemas[name]; !ok {
+			t.Errorf("Reference to undefined schema %q", name)
+		}
+	}
+}
+
+// Test that the component schemas follow the JSON encoding of the result types.
+func TestOpenAPIDocument_Schemas(t *testing.T) {
+	spec, _ := fetchOpenAPISpec(t)
+
+	tests := []struct {
+		schema      string
+		properties  []string
+		required    []string
+		notRequired []string
+	}{
+		{"BeerSearchResult", []string{"id", "name", "style", "brewery", "abv", "ibu", "score"}, []string{"name"}, []string{"score"}},
+		{"BrewerySearchResult", []string{"id", "name", "city", "country", "website_url"}, []string{"city"}, []string{"distance_km"}},
+		{"BJCPStyle", []string{"code", "name", "category", "vitals", "commercial_examples"}, []string{"code"}, nil},
+		{"APIError", []string{"status", "message", "field"}, []string{"message"}, []string{"field"}},
+	}
+	for _, tt := range tests {
+		schema, ok := spec.Components.Schemas[tt.schema]
+		if !ok {
+			t.Errorf("Missing schema %s", tt.schema)
+			continue
+		}
+		if schema.Type != "object" {
+			t.Errorf("%s: expected an object, got %q", tt.schema, schema.Type)
+		}
+		for _, property := range tt.properties {
+			if _, ok := schema.Properties[property]; !ok {
+				t.Errorf("%s: missing property %s", tt.schema, property)
+			}
+		}
+		for _, property := range tt.required {
+			if !slices.Contains(schema.Required, property) {
+				t.Errorf("%s: expected %s to be required", tt.schema, property)
+			}
+		}
+		for _, property := range tt.notRequired {
+			if slices.Contains(schema.Required, property) {
+				t.Errorf("%s: expected %s to be optional", tt.schema, property)
+			}
+		}
+	}
+}
+
+func TestServeAPIDocs(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	newAPIMux(newBeerService(), newBreweryService()).
+		ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/docs", nil))
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, recorder.Code)
+	}
+	if contentType := recorder.Header().Get("Content-Type"); contentType != "text/html; charset=utf-8" {
+		t.Errorf("Expected an HTML Content-Type, got %q", contentType)
+	}
+	if !strings.Contains(recorder.Body.String(), "openapi.json") {
+		t.Error("Docs page should load the OpenAPI document")
+	}
+}
@@ -0,0 +1,427 @@
+package handlers
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/mcp"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/services"
+	"github.com/CharlRitter/brewsource-mcp/app/pkg/data"
+)
+
+// A recommendation's score is the weighted sum of three components, each between 0 and 1, so that
+// the score is too:
+//
+//   - style: 1 for the BJCP style the recommendations are for. Another style scores
+//     0.5 / (1 + d), where d is its data.StyleDistance from that style, plus 0.25 when the two share
+//     a BJCP category. A beer whose declared style maps to no BJCP style scores 0.
+//   - vitals: the mean, over the ABV, IBU, and SRM known for both beers, of 1 - |difference| / scale,
+//     floored at 0, with scales of 2% ABV, 25 IBU, and 10 SRM. A vital the liked beer does not
+//     record, or every vital when recommending for a style alone, is taken from the middle of the
+//     style's range. 0 when no vital is known for both.
+//   - country: 1 when the beer's brewery is in the same country as the liked beer's brewery.
+const (
+	recommendStyleWeight   = 0.5
+	recommendVitalsWeight  = 0.4
+	recommendCountryWeight = 0.1
+
+	sameCategoryStyleScore = 0.25
+	recommendABVScale      = 2.0
+	recommendIBUScale      = 25.0
+	recommendSRMScale      = 10.0
+
+	// recommendOtherStyles is the number of styles outside the style's category, nearest by vitals,
+	// whose beers are scored alongside those of the styles in its category.
+	recommendOtherStyles = 5
+	// recommendNameMatches is the number of beers a beer_name lookup considers.
+	recommendNameMatches  = 10
+	defaultRecommendLimit = 5
+	maxRecommendLimit     = 20
+	recommendScorePlaces  = 1000
+)
+
+// askForStyle ends the answer to a liked beer whose style cannot be placed.
+const askForStyle = "pass style_code (e.g., '21A' for American IPA) to get recommendations for beers like it"
+
+// recommendTarget is what recommendations are scored against: a BJCP style, the vitals taken for
+// it, and the liked beer, which is nil when recommending for the style alone.
+type recommendTarget struct {
+	beer   *services.BeerDetail
+	style  *data.BJCPStyle
+	vitals [3]float64 // ABV, IBU, and SRM; 0 when unknown
+}
+
+// recommendation is a recommended beer with its score and how the score was reached.
+type recommendation struct {
+	*services.BeerCandidate
+	URI       string         `json:"uri"`
+	StyleCode string         `json:"style_code,omitempty"`
+	Score     float64        `json:"score"`
+	Breakdown scoreBreakdown `json:"breakdown"`
+}
+
+// scoreBreakdown holds a recommendation's unweighted score components.
+type scoreBreakdown struct {
+	Style float64 `json:"style"`
+	// StyleRelation is "same style", "same category", "other category", or "unmapped".
+	StyleRelation  string   `json:"style_relation"`
+	Vitals         float64  `json:"vitals"`
+	VitalsCompared []string `json:"vitals_compared"`
+	Country        float64  `json:"country"`
+}
+
+// recommendations is the JSON content of recommend_beers.
+type recommendations struct {
+	BeerID          int                `json:"beer_id,omitempty"`
+	Beer            string             `json:"beer,omitempty"`
+	StyleCode       string             `json:"style_code"`
+	StyleName       string             `json:"style_name"`
+	Weights         map[string]float64 `json:"weights"`
+	Recommendations []recommendation   `json:"recommendations"`
+}
+
+// RecommendBeers recommends catalog beers similar to a liked beer, named by beer_id or beer_name,
+// or to a BJCP style_code. The beers of the style's category and of the nearest styles outside it
+// are fetched from the catalog and ranked by score, see recommendStyleWeight; the liked beer itself
+// is left out. The first content block is a markdown list; the second is the JSON with each score's
+// breakdown. A liked beer the catalog does not carry, or whose style maps to no BJCP style, is
+// answered with a request for a style_code rather than an error.
+func (h *ToolHandlers) RecommendBeers(ctx context.Context, args map[string]interface{}) (*mcp.ToolResult, error) {
+	beerID, hasID, err := parseOptionalInt(args, "beer_id")
+	if err != nil {
+		return nil, err
+	}
+	name, _ := args["beer_name"].(string)
+	name = strings.TrimSpace(name)
+	code, _ := args["style_code"].(string)
+	code = strings.TrimSpace(code)
+	switch {
+	case hasID && name != "":
+		return nil, &mcp.Error{Code: mcp.InvalidParams, Message: "beer_id and beer_name cannot be used together"}
+	case !hasID && name == "" && code == "":
+		return nil, &mcp.Error{
+			Code:    mcp.InvalidParams,
+			Message: "one of beer_id, beer_name, or style_code is required",
+			Data:    map[string]interface{}{"provided_params": args},
+		}
+	case hasID && beerID < 1:
+		return nil, &mcp.Error{Code: mcp.InvalidParams, Message: "beer_id must be a positive integer"}
+	}
+	limit := defaultRecommendLimit
+	if _, ok := args["limit"]; ok {
+		parsed, limitErr := parseLimit(args)
+		if limitErr != nil {
+			return nil, limitErr
+		}
+		limit = min(parsed, maxRecommendLimit)
+	}
+
+	target := recommendTarget{}
+	note := ""
+	if hasID || name != "" {
+		beer, choices, lookupErr := h.findLikedBeer(ctx, beerID, name)
+		if lookupErr != nil {
+			return nil, lookupErr
+		}
+		switch {
+		case choices != "":
+			return mcp.NewToolResult(choices), nil
+		case beer != nil:
+			target.beer = beer
+		case code == "":
+			return mcp.NewToolResult(missingBeer(beerID, name) + "; " + askForStyle + "."), nil
+		default:
+			// The beer is unknown, but the style given with it is enough to go on
+			note = missingBeer(beerID, name) + "; recommending by style instead.\n\n"
+		}
+	}
+	if code != "" {
+		if target.style, err = resolveStyle(h.bjcpService, code); err != nil {
+			return nil, err
+		}
+	} else if target.style, _ = h.bjcpService.GetStyleByName(target.beer.Style); target.style == nil {
+		return mcp.NewToolResult(unmappedStylePrompt(target.beer)), nil
+	}
+	target.vitals = recommendVitals(target.style, target.beer)
+
+	excludeID := 0
+	if target.beer != nil {
+		excludeID = target.beer.ID
+	}
+	nearby := h.nearbyStyles(target.style)
+	candidates, err := h.beerService.RecommendationCandidates(ctx, recommendStyles(target, nearby), excludeID)
+	if err != nil {
+		return nil, serviceError(err, "failed to find recommendation candidates")
+	}
+
+	styles := map[string]*data.BJCPStyle{}
+	ranked := make([]recommendation, 0, len(candidates))
+	for _, candidate := range candidates {
+		style, seen := styles[candidate.Style]
+		if !seen {
+			style = h.placeStyle(candidate.Style, nearby)
+			styles[candidate.Style] = style
+		}
+		ranked = append(ranked, scoreRecommendation(target, candidate, style))
+	}
+	slices.SortFunc(ranked, func(a, b recommendation) int {
+		if c := cmp.Compare(b.Score, a.Score); c != 0 {
+			return c
+		}
+		if c := strings.Compare(a.Name, b.Name); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.ID, b.ID)
+	})
+	ranked = ranked[:min(limit, len(ranked))]
+
+	result := recommendations{
+		StyleCode: target.style.Code,
+		StyleName: target.style.Name,
+		Weights: map[string]float64{
+			"style":   recommendStyleWeight,
+			"vitals":  recommendVitalsWeight,
+			"country": recommendCountryWeight,
+		},
+		Recommendations: ranked,
+	}
+	if target.beer != nil {
+		result.BeerID, result.Beer = target.beer.ID, target.beer.Name
+	}
+	resultJSON, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal recommendations: %w", err)
+	}
+	return &mcp.ToolResult{
+		Content: []mcp.ToolContent{
+			{Type: "text", Text: note + formatRecommendations(target, ranked)},
+			{Type: "text", Text: string(resultJSON)},
+		},
+	}, nil
+}
+
+// findLikedBeer looks up the liked beer by ID, or by name, preferring an exact match. When the name
+// matches several beers and none exactly, it returns them as choices instead; when there is no such
+// beer, it returns neither.
+func (h *ToolHandlers) findLikedBeer(ctx context.Context, id int, name string) (*services.BeerDetail, string, error) {
+	if name != "" {
+		matches, err := h.beerService.SearchBeers(ctx, services.BeerSearchQuery{
+			Name:  name,
+			Sort:  services.SortByRelevance,
+			Limit: recommendNameMatches,
+		})
+		if err != nil {
+			return nil, "", serviceError(err, "failed to search beers")
+		}
+		exact := slices.IndexFunc(matches, func(beer *services.BeerSearchResult) bool {
+			return strings.EqualFold(beer.Name, name)
+		})
+		switch {
+		case len(matches) == 0:
+			return nil, "", nil
+		case exact >= 0:
+			id = matches[exact].ID
+		case len(matches) == 1:
+			id = matches[0].ID
+		default:
+			return nil, formatBeerChoices(name, matches), nil
+		}
+	}
+	beer, err := h.beerService.GetBeerByID(ctx, id)
+	if errors.Is(err, services.ErrNotFound) {
+		return nil, "", nil
+	}
+	if err != nil {
+		return nil, "", recordLookupError(err, "beer")
+	}
+	return beer, "", nil
+}
+
+// missingBeer says that the catalog does not carry the liked beer.
+func missingBeer(id int, name string) string {
+	if name != "" {
+		return fmt.Sprintf("No beer named %q is in the catalog", name)
+	}
+	return fmt.Sprintf("No beer with ID %d is in the catalog", id)
+}
+
+// formatBeerChoices asks which of the beers matching a name was meant.
+func formatBeerChoices(name string, matches []*services.BeerSearchResult) string {
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("Several beers match %q; pass the beer_id of the one you mean:\n\n", name))
+	for _, beer := range matches {
+		response.WriteString(fmt.Sprintf("- **%s** by %s (ID %d)\n", beer.Name, beer.Brewery, beer.ID))
+	}
+	return response.String()
+}
+
+// unmappedStylePrompt asks for the style of a liked beer whose declared style maps to no BJCP style.
+func unmappedStylePrompt(beer *services.BeerDetail) string {
+	if beer.Style == "" {
+		return fmt.Sprintf("%s (ID %d) does not declare a style; %s.", beer.Name, beer.ID, askForStyle)
+	}
+	return fmt.Sprintf("%s (ID %d) is labelled %q, which is not a BJCP style; %s.",
+		beer.Name, beer.ID, beer.Style, askForStyle)
+}
+
+// recommendVitals returns the liked beer's recorded ABV, IBU, and SRM, falling back to the middle of
+// the style's range for those it does not record.
+func recommendVitals(style *data.BJCPStyle, beer *services.BeerDetail) [3]float64 {
+	v := style.Vitals
+	vitals := [3]float64{
+		(v.ABVMin + v.ABVMax) / 2,
+		float64(v.IBUMin+v.IBUMax) / 2,
+		(v.SRMMin + v.SRMMax) / 2,
+	}
+	if beer != nil {
+		for i, recorded := range []float64{beer.ABV, float64(beer.IBU), beer.SRM} {
+			if recorded > 0 {
+				vitals[i] = recorded
+			}
+		}
+	}
+	return vitals
+}
+
+// nearbyStyles returns style, the other styles in its category, and the recommendOtherStyles
+// styles outside it nearest by vitals.
+func (h *ToolHandlers) nearbyStyles(style *data.BJCPStyle) []data.BJCPStyle {
+	nearby := []data.BJCPStyle{*style}
+	similar, _ := h.bjcpService.SimilarStyles(style.Code)
+	others := 0
+	for _, candidate := range similar {
+		switch {
+		case candidate.SameCategory:
+			nearby = append(nearby, candidate.Style)
+		case others < recommendOtherStyles:
+			nearby = append(nearby, candidate.Style)
+			others++
+		}
+	}
+	return nearby
+}
+
+// recommendStyles returns the declared styles whose beers are candidates: the names and aliases of
+// the nearby styles, and the liked beer's own declared style.
+func recommendStyles(target recommendTarget, nearby []data.BJCPStyle) []string {
+	styles := []string{}
+	if target.beer != nil && target.beer.Style != "" {
+		styles = append(styles, target.beer.Style)
+	}
+	for _, style := range nearby {
+		styles = append(styles, style.Name)
+		styles = append(styles, style.Aliases...)
+	}
+	return styles
+}
+
+// placeStyle maps a candidate's declared style to a BJCP style: by name, or else to the first of the
+// nearby styles whose name or an alias it contains, as the candidate query matched it. It returns
+// nil for a style it cannot place.
+func (h *ToolHandlers) placeStyle(declared string, nearby []data.BJCPStyle) *data.BJCPStyle {
+	if style, err := h.bjcpService.GetStyleByName(declared); err == nil {
+		return style
+	}
+	declared = strings.ToLower(declared)
+	for i, style := range nearby {
+		for _, name := range append([]string{style.Name}, style.Aliases...) {
+			if strings.Contains(declared, strings.ToLower(name)) {
+				return &nearby[i]
+			}
+		}
+	}
+	return nil
+}
+
+// scoreRecommendation scores a candidate against the target, see recommendStyleWeight. style is the
+// BJCP style the candidate's declared style maps to, or nil.
+func scoreRecommendation(
+	target recommendTarget,
+	candidate *services.BeerCandidate,
+	style *data.BJCPStyle,
+) recommendation {
+	r := recommendation{
+		BeerCandidate: candidate,
+		URI:           fmt.Sprintf("beers://%d", candidate.ID),
+		Breakdown:     scoreBreakdown{StyleRelation: "unmapped", VitalsCompared: []string{}},
+	}
+	b := &r.Breakdown
+	if style != nil {
+		r.StyleCode = style.Code
+		switch {
+		case style.Code == target.style.Code:
+			b.Style, b.StyleRelation = 1, "same style"
+		case strings.EqualFold(style.Category, target.style.Category):
+			b.Style = sameCategoryStyleScore + 0.5/(1+data.StyleDistance(target.style, style))
+			b.StyleRelation = "same category"
+		default:
+			b.Style, b.StyleRelation = 0.5/(1+data.StyleDistance(target.style, style)), "other category"
+		}
+	}
+
+	total := 0.0
+	for i, vital := range []struct {
+		name  string
+		value float64
+		scale float64
+	}{
+		{"abv", candidate.ABV, recommendABVScale},
+		{"ibu", float64(candidate.IBU), recommendIBUScale},
+		{"srm", candidate.SRM, recommendSRMScale},
+	} {
+		if vital.value <= 0 || target.vitals[i] <= 0 {
+			continue
+		}
+		total += math.Max(0, 1-math.Abs(vital.value-target.vitals[i])/vital.scale)
+		b.VitalsCompared = append(b.VitalsCompared, vital.name)
+	}
+	if len(b.VitalsCompared) > 0 {
+		b.Vitals = total / float64(len(b.VitalsCompared))
+	}
+
+	if target.beer != nil && target.beer.Country != "" && strings.EqualFold(candidate.Country, target.beer.Country) {
+		b.Country = 1
+	}
+
+	b.Style, b.Vitals = roundScore(b.Style), roundScore(b.Vitals)
+	r.Score = roundScore(recommendStyleWeight*b.Style + recommendVitalsWeight*b.Vitals +
+		recommendCountryWeight*b.Country)
+	return r
+}
+
+// roundScore rounds a score to three decimal places.
+func roundScore(score float64) float64 {
+	return math.Round(score*recommendScorePlaces) / recommendScorePlaces
+}
+
+func formatRecommendations(target recommendTarget, ranked []recommendation) string {
+	var response strings.Builder
+	subject := fmt.Sprintf("%s %s", target.style.Code, target.style.Name)
+	if target.beer != nil {
+		subject = fmt.Sprintf("%s (ID %d, %s)", target.beer.Name, target.beer.ID, subject)
+	}
+	if len(ranked) == 0 {
+		return fmt.Sprintf("No catalog beers in or near the style of %s were found.", subject)
+	}
+
+	response.WriteString(fmt.Sprintf("**Beers like %s:**\n\n", subject))
+	for i, r := range ranked {
+		response.WriteString(fmt.Sprintf("%d. **%s** by %s (ID %d) - %s, %.1f%% ABV, %d IBU\n",
+			i+1, r.Name, r.Brewery, r.ID, r.Style, r.ABV, r.IBU))
+		compared := "no vitals compared"
+		if len(r.Breakdown.VitalsCompared) > 0 {
+			compared = strings.ToUpper(strings.Join(r.Breakdown.VitalsCompared, ", "))
+		}
+		response.WriteString(fmt.Sprintf("   Score %.2f: style %.2f (%s), vitals %.2f (%s), country %.0f\n",
+			r.Score, r.Breakdown.Style, r.Breakdown.StyleRelation, r.Breakdown.Vitals, compared, r.Breakdown.Country))
+	}
+	response.WriteString(fmt.Sprintf("\n_Score = %.1f × style + %.1f × vitals + %.1f × same country_\n",
+		recommendStyleWeight, recommendVitalsWeight, recommendCountryWeight))
+	return response.String()
+}
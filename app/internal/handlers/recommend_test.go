@@ -0,0 +1,196 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/handlers"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/mcp"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/services"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/services/servicestest"
+)
+
+// newRecommendBeers returns catalog beers of the same style as Hop Bomb, of a style in its category,
+// of a style in another category, of a distant style, and of a style that is not a BJCP style.
+func newRecommendBeers() *servicestest.BeerService {
+	return &servicestest.BeerService{Beers: []*services.BeerDetail{
+		{ID: 1, Name: "Hop Bomb", Brewery: "Devil's Peak", Country: "South Africa",
+			Style: "American IPA", ABV: 6.5, IBU: 60, SRM: 8},
+		{ID: 2, Name: "Coastal IPA", Brewery: "Drifter", Country: "South Africa",
+			Style: "West Coast IPA", ABV: 6.8, IBU: 65, SRM: 7},
+		{ID: 3, Name: "Haze Craze", Brewery: "Hazy Days", Country: "United States",
+			Style: "Hazy IPA", ABV: 6.5, IBU: 40, SRM: 5},
+		{ID: 4, Name: "Porch Pounder", Brewery: "Devil's Peak", Country: "South Africa",
+			Style: "American Pale Ale", ABV: 5, IBU: 35, SRM: 6},
+		{ID: 5, Name: "Midnight", Brewery: "Devil's Peak", Country: "South Africa",
+			Style: "Sweet Stout", ABV: 5, IBU: 25, SRM: 35},
+		{ID: 6, Name: "Split Peel", Brewery: "Devil's Peak", Country: "South Africa",
+			Style: "Banana Lager", ABV: 5, IBU: 15, SRM: 4},
+	}}
+}
+
+// recommendedIDs decodes the JSON content of a recommend_beers result.
+func recommendedIDs(t *testing.T, result *mcp.ToolResult) ([]int, map[string]interface{}) {
+	t.Helper()
+	var decoded struct {
+		Recommendations []struct {
+			ID        int                    `json:"id"`
+			Breakdown map[string]interface{} `json:"breakdown"`
+		} `json:"recommendations"`
+	}
+	if err := json.Unmarshal([]byte(result.Content[1].Text), &decoded); err != nil {
+		t.Fatalf("failed to decode %s: %v", result.Content[1].Text, err)
+	}
+	ids := []int{}
+	for _, r := range decoded.Recommendations {
+		ids = append(ids, r.ID)
+	}
+	if len(decoded.Recommendations) == 0 {
+		return ids, nil
+	}
+	return ids, decoded.Recommendations[0].Breakdown
+}
+
+func TestRecommendBeers(t *testing.T) {
+	beers := newRecommendBeers()
+	h := handlers.NewToolHandlers(loadStyleData(t), beers, nil)
+	ctx := context.Background()
+
+	result, err := h.RecommendBeers(ctx, map[string]interface{}{"beer_id": 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ids, breakdown := recommendedIDs(t, result)
+	if want := []int{2, 3, 4}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("expected beers %v, got %v", want, ids)
+	}
+	want := map[string]interface{}{
+		"style": 1.0, "style_relation": "same style", "vitals": 0.85,
+		"vitals_compared": []interface{}{"abv", "ibu", "srm"}, "country": 1.0,
+	}
+	if !reflect.DeepEqual(breakdown, want) {
+		t.Errorf("expected breakdown %v, got %v", want, breakdown)
+	}
+	for _, want := range []string{
+		"**Beers like Hop Bomb (ID 1, 21A American IPA):**",
+		"1. **Coastal IPA** by Drifter (ID 2) - West Coast IPA, 6.8% ABV, 65 IBU",
+		"Score 0.94: style 1.00 (same style), vitals 0.85 (ABV, IBU, SRM), country 1",
+		"(same category)",
+	} {
+		if !strings.Contains(result.Content[0].Text, want) {
+			t.Errorf("expected %q in:\n%s", want, result.Content[0].Text)
+		}
+	}
+	styles := beers.CandidateStyles[0]
+	if !slices.Contains(styles, "Hazy IPA") || slices.Contains(styles, "Sweet Stout") {
+		t.Errorf("expected the candidates to be narrowed to nearby styles, got %v", styles)
+	}
+
+	// A style alone has no beer to leave out, and no country to share.
+	result, err = h.RecommendBeers(ctx, map[string]interface{}{"style_code": "21a", "limit": 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ids, breakdown = recommendedIDs(t, result)
+	if !reflect.DeepEqual(ids, []int{1, 2}) || breakdown["country"] != 0.0 {
+		t.Errorf("expected the two American IPAs without a country bonus, got %v %v", ids, breakdown)
+	}
+}
+
+func TestRecommendBeers_ByName(t *testing.T) {
+	beers := newRecommendBeers()
+	beers.Results = []*services.BeerSearchResult{
+		{ID: 7, Name: "Hop Bomb Imperial", Brewery: "Devil's Peak"},
+		{ID: 1, Name: "Hop Bomb", Brewery: "Devil's Peak"},
+	}
+	h := handlers.NewToolHandlers(loadStyleData(t), beers, nil)
+	ctx := context.Background()
+
+	result, err := h.RecommendBeers(ctx, map[string]interface{}{"beer_name": "hop bomb"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(result.Content[0].Text, "Beers like Hop Bomb (ID 1") {
+		t.Errorf("expected the exact name match to be liked, got:\n%s", result.Content[0].Text)
+	}
+
+	result, err = h.RecommendBeers(ctx, map[string]interface{}{"beer_name": "hop"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if text := result.Content[0].Text; !strings.Contains(text, `Several beers match "hop"`) ||
+		!strings.Contains(text, "- **Hop Bomb Imperial** by Devil's Peak (ID 7)") {
+		t.Errorf("expected the matching beers to choose from, got:\n%s", text)
+	}
+}
+
+// Test that a beer the catalog does not carry, or cannot place in a style, is answered with a
+// request for a style rather than an error.
+func TestRecommendBeers_ColdStart(t *testing.T) {
+	h := handlers.NewToolHandlers(loadStyleData(t), newRecommendBeers(), nil)
+	ctx := context.Background()
+
+	tests := []struct {
+		args map[string]interface{}
+		want string
+	}{
+		{map[string]interface{}{"beer_id": 99}, "No beer with ID 99 is in the catalog; pass style_code"},
+		{map[string]interface{}{"beer_name": "Nonexistent"},
+			`No beer named "Nonexistent" is in the catalog; pass style_code`},
+		{map[string]interface{}{"beer_id": 6},
+			`Split Peel (ID 6) is labelled "Banana Lager", which is not a BJCP style; pass style_code`},
+	}
+	for _, tt := range tests {
+		result, err := h.RecommendBeers(ctx, tt.args)
+		if err != nil {
+			t.Fatalf("unexpected error for %v: %v", tt.args, err)
+		}
+		if result.IsError || !strings.Contains(result.Content[0].Text, tt.want) {
+			t.Errorf("expected %q for %v, got:\n%s", tt.want, tt.args, result.Content[0].Text)
+		}
+	}
+
+	// With a style to go on, an unknown beer is recommended for by the style.
+	result, err := h.RecommendBeers(ctx, map[string]interface{}{"beer_id": 99, "style_code": "21A"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if text := result.Content[0].Text; !strings.HasPrefix(text,
+		"No beer with ID 99 is in the catalog; recommending by style instead.") ||
+		!strings.Contains(text, "**Beers like 21A American IPA:**") {
+		t.Errorf("expected recommendations by style, got:\n%s", text)
+	}
+
+	// A style code overrides a style that cannot be placed.
+	result, err = h.RecommendBeers(ctx, map[string]interface{}{"beer_id": 6, "style_code": "21A"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(result.Content[0].Text, "**Beers like Split Peel (ID 6, 21A American IPA):**") {
+		t.Errorf("expected recommendations for the given style, got:\n%s", result.Content[0].Text)
+	}
+}
+
+func TestRecommendBeers_Errors(t *testing.T) {
+	h := handlers.NewToolHandlers(loadStyleData(t), newRecommendBeers(), nil)
+	ctx := context.Background()
+
+	tests := []struct {
+		args map[string]interface{}
+		want string
+	}{
+		{map[string]interface{}{}, "one of beer_id, beer_name, or style_code is required"},
+		{map[string]interface{}{"beer_id": 1, "beer_name": "Hop Bomb"}, "cannot be used together"},
+		{map[string]interface{}{"beer_id": 0}, "beer_id must be a positive integer"},
+		{map[string]interface{}{"beer_id": "one"}, "beer_id must be an integer"},
+		{map[string]interface{}{"style_code": "99Z"}, "BJCP style not found"},
+	}
+	for _, tt := range tests {
+		_, err := h.RecommendBeers(ctx, tt.args)
+		expectMCPError(t, err, mcp.InvalidParams, tt.want)
+	}
+}
@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/mcp"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultResourceCacheTTL is how long catalog resources stay cached unless changed with SetCache.
+	DefaultResourceCacheTTL = 30 * time.Second
+	// browseResourceTTL is how long the country and region lists stay cached. They change only as
+	// breweries are added in new places, so they are kept longer than catalog pages.
+	browseResourceTTL = 5 * time.Minute
+	// styleResourceTTL is how long style guide resources stay cached. Their keys change with the
+	// style data, so the TTL only reclaims the entries of replaced data.
+	styleResourceTTL = 24 * time.Hour
+
+	resourceCachePrefix = "brewsource:resources:"
+	// resourceCacheTimeout bounds each Redis call so that an unreachable cache cannot stall a read.
+	resourceCacheTimeout = 250 * time.Millisecond
+	// resourceCacheScanCount is the number of keys requested per SCAN step during invalidation.
+	resourceCacheScanCount = 100
+)
+
+// resourceCache is a read-through Redis cache of serialized resource contents. A nil cache, or one
+// without a client, caches nothing, so resources are read unchanged when Redis is not configured.
+type resourceCache struct {
+	client     *redis.Client
+	catalogTTL time.Duration
+}
+
+// SetCache caches resource contents in Redis: the style guide resources until the style data is
+// reloaded, the country and region lists for a few minutes, and the beer catalog and brewery
+// directory pages for catalogTTL, or DefaultResourceCacheTTL when it is not positive. A nil client
+// turns the cache off.
+func (h *ResourceHandlers) SetCache(client *redis.Client, catalogTTL time.Duration) {
+	if catalogTTL <= 0 {
+		catalogTTL = DefaultResourceCacheTTL
+	}
+	h.cache = &resourceCache{client: client, catalogTTL: catalogTTL}
+}
+
+// cacheKey returns the cache key of a resource URI and how long to keep it, or false for resources
+// that are not cached. Style guide resources are keyed by the style data fingerprint, so that a
+// reload is never served stale data; the style examples match catalog beers, so they are not
+// cached, nor are single beers and breweries, whose venues say whether they are open now.
+func (h *ResourceHandlers) cacheKey(uri string) (string, time.Duration, bool) {
+	if h.cache == nil || h.cache.client == nil {
+		return "", 0, false
+	}
+	sum := sha256.Sum256([]byte(uri))
+	hash := hex.EncodeToString(sum[:])
+	path, _, _ := strings.Cut(uri, "?")
+	switch {
+	case strings.HasPrefix(path, "bjcp://") && !strings.HasSuffix(path, "/examples"):
+		return resourceCachePrefix + "bjcp:" + h.bjcpService.Fingerprint() + ":" + hash, styleResourceTTL, true
+	case path == "beers://catalog" || path == "breweries://directory":
+		return resourceCachePrefix + "catalog:" + hash, h.cache.catalogTTL, true
+	case path == breweryCountriesURI || strings.HasPrefix(path, breweryCountriesURI+"/"):
+		return resourceCachePrefix + "browse:" + hash, browseResourceTTL, true
+	default:
+		return "", 0, false
+	}
+}
+
+// cached wraps a resource handler so that its contents are served from the cache when they are
+// there, and cached when read. Errors are not cached, and a failing Redis only makes reads uncached.
+func (h *ResourceHandlers) cached(handler mcp.ResourceHandler) mcp.ResourceHandler {
+	return func(ctx context.Context, uri string) (*mcp.ResourceContent, error) {
+		key, ttl, ok := h.cacheKey(uri)
+		if !ok {
+			return handler(ctx, uri)
+		}
+		if content := h.cache.get(ctx, key); content != nil {
+			return content, nil
+		}
+		content, err := handler(ctx, uri)
+		if err == nil {
+			h.cache.set(ctx, key, content, ttl)
+		}
+		return content, err
+	}
+}
+
+// cachedTemplate is cached for resource template handlers.
+func (h *ResourceHandlers) cachedTemplate(handler mcp.ResourceTemplateHandler) mcp.ResourceTemplateHandler {
+	return func(ctx context.Context, uri string, params map[string]string) (*mcp.ResourceContent, error) {
+		return h.cached(func(ctx context.Context, uri string) (*mcp.ResourceContent, error) {
+			return handler(ctx, uri, params)
+		})(ctx, uri)
+	}
+}
+
+// get returns the cached content under key, or nil when there is none.
+func (c *resourceCache) get(ctx context.Context, key string) *mcp.ResourceContent {
+	ctx, cancel := context.WithTimeout(ctx, resourceCacheTimeout)
+	defer cancel()
+
+	raw, err := c.client.Get(ctx, key).Bytes()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			logrus.Debugf("Resource cache read failed for %s: %v", key, err)
+		}
+		return nil
+	}
+	var content mcp.ResourceContent
+	if err = json.Unmarshal(raw, &content); err != nil {
+		return nil
+	}
+	return &content
+}
+
+// set stores content under key for ttl. Failures are logged and otherwise ignored.
+func (c *resourceCache) set(ctx context.Context, key string, content *mcp.ResourceContent, ttl time.Duration) {
+	raw, err := json.Marshal(content)
+	if err != nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, resourceCacheTimeout)
+	defer cancel()
+
+	if err = c.client.Set(ctx, key, raw, ttl).Err(); err != nil {
+		logrus.Debugf("Resource cache write failed for %s: %v", key, err)
+	}
+}
+
+// InvalidateResourceCache drops every cached resource content. Call it after the catalog changes,
+// such as after seeding or an import; a style data reload needs no invalidation.
+func InvalidateResourceCache(ctx context.Context, client *redis.Client) error {
+	if client == nil {
+		return nil
+	}
+	var cursor uint64
+	for {
+		keys, next, err := client.Scan(ctx, cursor, resourceCachePrefix+"*", resourceCacheScanCount).Result()
+		if err != nil {
+			return fmt.Errorf("failed to scan resource cache: %w", err)
+		}
+		if len(keys) > 0 {
+			if err = client.Del(ctx, keys...).Err(); err != nil {
+				return fmt.Errorf("failed to clear resource cache: %w", err)
+			}
+		}
+		if next == 0 {
+			return nil
+		}
+		cursor = next
+	}
+}
@@ -0,0 +1,178 @@
+package handlers_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/handlers"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/services"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/services/servicestest"
+	"github.com/CharlRitter/brewsource-mcp/app/pkg/data"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// cachedResourceHandlers returns resource handlers for the given style name, serving beerService,
+// with their cache in mr.
+func cachedResourceHandlers(
+	mr *miniredis.Miniredis,
+	styleName string,
+	beerService services.BeerServiceInterface,
+) *handlers.ResourceHandlers {
+	h := handlers.NewResourceHandlers(&data.BJCPData{
+		Styles:     map[string]data.BJCPStyle{"21A": {Code: "21A", Name: styleName, Category: "IPA"}},
+		Categories: []string{"IPA"},
+		Metadata:   data.Metadata{Version: "2021", Source: "test"},
+	}, beerService, nil)
+	if mr != nil {
+		h.SetCache(redis.NewClient(&redis.Options{Addr: mr.Addr()}), 0)
+	}
+	return h
+}
+
+// resourceKeys returns the keys of the cached resource contents.
+func resourceKeys(mr *miniredis.Miniredis) []string {
+	var keys []string
+	for _, key := range mr.Keys() {
+		if strings.HasPrefix(key, "brewsource:resources:") {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// Test that cached style resources are served byte for byte as read, and that style data that
+// differs, as after a reload, is not served stale entries.
+func TestResourceCache_StyleResources(t *testing.T) {
+	mr := miniredis.RunT(t)
+	uncached := cachedResourceHandlers(nil, "American IPA", nil)
+	cached := cachedResourceHandlers(mr, "American IPA", nil)
+
+	uris := []string{"bjcp://styles", "bjcp://styles?format=csv", "bjcp://categories", "bjcp://styles/21A"}
+	for _, uri := range uris {
+		want, err := readResource(uncached, uri)
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", uri, err)
+		}
+		for i := 0; i < 2; i++ {
+			got, readErr := readResource(cached, uri)
+			if readErr != nil {
+				t.Fatalf("Failed to read %s: %v", uri, readErr)
+			}
+			if *got != *want {
+				t.Errorf("Expected read %d of %s to match the uncached read, got %+v", i+1, uri, got)
+			}
+		}
+	}
+	if keys := resourceKeys(mr); len(keys) != 4 {
+		t.Errorf("Expected the 4 resources to be cached, got %v", keys)
+	}
+	if ttl := mr.TTL(resourceKeys(mr)[0]); ttl <= time.Hour {
+		t.Errorf("Expected style resources to be kept until the data changes, got a TTL of %s", ttl)
+	}
+
+	// Errors are not cached
+	if _, err := readResource(cached, "bjcp://styles/99Z"); err == nil {
+		t.Error("Expected an unknown style to fail")
+	}
+	if keys := resourceKeys(mr); len(keys) != 4 {
+		t.Errorf("Expected the failed read not to be cached, got %v", keys)
+	}
+
+	reloaded := cachedResourceHandlers(mr, "Modern IPA", nil)
+	content, err := readResource(reloaded, "bjcp://styles/21A")
+	if err != nil {
+		t.Fatalf("Failed to read the reloaded style: %v", err)
+	}
+	if !strings.Contains(content.Text, "Modern IPA") {
+		t.Errorf("Expected the reloaded style data, got %s", content.Text)
+	}
+}
+
+// Test that catalog pages are cached for a short time and dropped by InvalidateResourceCache.
+func TestResourceCache_CatalogResources(t *testing.T) {
+	mr := miniredis.RunT(t)
+	beerService := &servicestest.BeerService{
+		Results: []*services.BeerSearchResult{{ID: 1, Name: "Castle Lager", Style: "Lager"}},
+	}
+	h := cachedResourceHandlers(mr, "American IPA", beerService)
+
+	first, err := readResource(h, "beers://catalog?style=Lager")
+	if err != nil {
+		t.Fatalf("Failed to read the catalog: %v", err)
+	}
+	second, err := readResource(h, "beers://catalog?style=Lager")
+	if err != nil {
+		t.Fatalf("Failed to read the catalog: %v", err)
+	}
+	if *first != *second || len(beerService.Queries) != 1 {
+		t.Errorf("Expected the second read to be served from the cache, got %d queries", len(beerService.Queries))
+	}
+	keys := resourceKeys(mr)
+	if len(keys) != 1 || mr.TTL(keys[0]) != handlers.DefaultResourceCacheTTL {
+		t.Fatalf("Expected the page cached for %s, got %v", handlers.DefaultResourceCacheTTL, keys)
+	}
+
+	// Single beers are read from the catalog each time
+	beerService.Beers = []*services.BeerDetail{{ID: 1, Name: "Castle Lager"}}
+	for i := 0; i < 2; i++ {
+		if _, err = readResource(h, "beers://1"); err != nil {
+			t.Fatalf("Failed to read the beer: %v", err)
+		}
+	}
+	if keys = resourceKeys(mr); len(keys) != 1 {
+		t.Errorf("Expected only the catalog page to be cached, got %v", keys)
+	}
+
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	if err = handlers.InvalidateResourceCache(context.Background(), client); err != nil {
+		t.Fatalf("Failed to invalidate the resource cache: %v", err)
+	}
+	if keys = resourceKeys(mr); len(keys) != 0 {
+		t.Errorf("Expected the cache to be empty, got %v", keys)
+	}
+	if _, err = readResource(h, "beers://catalog?style=Lager"); err != nil || len(beerService.Queries) != 2 {
+		t.Errorf("Expected the catalog to be queried again, got %d queries (%v)", len(beerService.Queries), err)
+	}
+}
+
+// Test that the country and region lists are cached for a few minutes.
+func TestResourceCache_BrowseResources(t *testing.T) {
+	mr := miniredis.RunT(t)
+	h := handlers.NewResourceHandlers(nil, nil, &servicestest.BreweryService{
+		Results: []*services.BrewerySearchResult{{ID: 1, Name: "Mad Giant", State: "Gauteng", Country: "South Africa"}},
+	})
+	h.SetCache(redis.NewClient(&redis.Options{Addr: mr.Addr()}), 0)
+
+	for _, uri := range []string{"breweries://countries", "breweries://countries/South%20Africa/regions"} {
+		if _, err := readResource(h, uri); err != nil {
+			t.Fatalf("Failed to read %s: %v", uri, err)
+		}
+	}
+	keys := resourceKeys(mr)
+	if len(keys) != 2 {
+		t.Fatalf("Expected both lists to be cached, got %v", keys)
+	}
+	for _, key := range keys {
+		if ttl := mr.TTL(key); ttl <= handlers.DefaultResourceCacheTTL || ttl > 10*time.Minute {
+			t.Errorf("Expected %s to be cached for a few minutes, got a TTL of %s", key, ttl)
+		}
+	}
+}
+
+// Test that reads fall back to the handlers when Redis is unreachable.
+func TestResourceCache_RedisDown(t *testing.T) {
+	mr := miniredis.RunT(t)
+	h := cachedResourceHandlers(mr, "American IPA", nil)
+	mr.Close()
+
+	content, err := readResource(h, "bjcp://styles/21A")
+	if err != nil || !strings.Contains(content.Text, "American IPA") {
+		t.Errorf("Expected the style to be read without Redis, got %v (%v)", content, err)
+	}
+	if err = handlers.InvalidateResourceCache(context.Background(), nil); err != nil {
+		t.Errorf("Expected no cache to need no invalidation, got %v", err)
+	}
+}
@@ -4,119 +4,513 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/CharlRitter/brewsource-mcp/app/internal/mcp"
 	"github.com/CharlRitter/brewsource-mcp/app/internal/services"
 	"github.com/CharlRitter/brewsource-mcp/app/pkg/data"
 )
 
-const (
-	// resourceSampleLimit is the default number of sample items to return for resource catalogs.
-	resourceSampleLimit = 10
+// beerExportLimit is the most beers beers://export returns.
+const beerExportLimit = 5000
+
+// breweryCountriesURI lists the countries in the brewery directory; each country's regions are
+// served by the breweries://countries/{country}/regions template.
+const breweryCountriesURI = "breweries://countries"
+
+// sampleBeersAlias repeats the beers of a beers://catalog page under the name the resource gave its
+// fixed sample before it served catalog pages, for clients that still read it.
+//
+// Deprecated: kept for one release; read beers instead.
+const sampleBeersAlias = "sample_beers"
+
+// Query parameters accepted by beers://catalog and breweries://directory: the arguments of the
+// search_beers and find_breweries tools, and the output format.
+var (
+	beerCatalogParams = []string{
+		"name", "style", "style_code", "brewery", "location", "abv_min", "abv_max", "ibu_min", "ibu_max", "srm_min",
+		"srm_max", "limit", "offset", "page", "sort", "order", "group_by_brewery", "format",
+	}
+	breweryDirectoryParams = []string{
+		"name", "location", "city", "state", "country", "type", "exclude_types", "latitude", "longitude", "radius_km",
+		"limit", "offset", "page", "sort", "order", "format",
+	}
 )
 
 // ResourceHandlers handles all MCP resource requests and implements ResourceHandlerRegistry.
 type ResourceHandlers struct {
 	bjcpService    *data.BJCPService
-	beerService    *services.BeerService
-	breweryService *services.BreweryService
+	beerService    services.BeerServiceInterface
+	breweryService services.BreweryServiceInterface
+	examples       *exampleCache
+	// ingredientService serves the ingredients:// resources; nil leaves them unregistered
+	ingredientService *data.IngredientService
+	cache             *resourceCache // optional; see SetCache
+	// usageLog serves usage://summary to callers with adminToken; nil leaves it unregistered
+	usageLog   *services.UsageLog
+	adminToken string
+	now        func() time.Time // the clock of opening hours; see SetClock
 }
 
 // NewResourceHandlers creates a new instance of ResourceHandlers.
 func NewResourceHandlers(
 	bjcpData *data.BJCPData,
-	beerService *services.BeerService,
-	breweryService *services.BreweryService,
+	beerService services.BeerServiceInterface,
+	breweryService services.BreweryServiceInterface,
 ) *ResourceHandlers {
 	bjcpService := data.NewBJCPServiceFromData(bjcpData)
 	return &ResourceHandlers{
 		bjcpService:    bjcpService,
 		beerService:    beerService,
 		breweryService: breweryService,
+		examples:       newExampleCache(),
+		now:            time.Now,
 	}
 }
 
-// RegisterResourceHandlers implements ResourceHandlerRegistry interface.
+// SetClock has the resources tell the time by now, such as a fixed time in tests with golden output.
+func (h *ResourceHandlers) SetClock(now func() time.Time) {
+	h.now = now
+}
+
+// SetBJCPService serves the BJCP resources from a service shared with other handlers, so that a
+// reload of its data is served here too.
+func (h *ResourceHandlers) SetBJCPService(bjcpService *data.BJCPService) {
+	h.bjcpService = bjcpService
+}
+
+// resourceTemplate is a parameterized resource with the handler serving it.
+type resourceTemplate struct {
+	mcp.ResourceTemplate
+	handler mcp.ResourceTemplateHandler
+}
+
+// RegisterResourceHandlers implements ResourceHandlerRegistry interface. The style guide and
+// catalog resources are served through the cache set with SetCache, see cacheKey.
 func (h *ResourceHandlers) RegisterResourceHandlers(server *mcp.Server) {
-	server.RegisterResourceHandler("bjcp://*", h.HandleBJCPResource)
-	server.RegisterResourceHandler("beers://*", h.HandleBeerResource)
-	server.RegisterResourceHandler("breweries://*", h.HandleBreweryResource)
+	server.RegisterResourceHandler("bjcp://*", h.cached(h.HandleBJCPResource))
+	server.RegisterResourceHandler("beers://*", h.cached(h.HandleBeerResource))
+	server.RegisterResourceHandler("breweries://*", h.cached(h.HandleBreweryResource))
+	server.RegisterResourceHandler("stats://*", h.HandleStatsResource)
+	server.RegisterResourceHandler(dataSourcesURI, h.HandleDataSourcesResource)
+	// Registered exactly, as they would otherwise match beers://{id} and breweries://{id}
+	server.RegisterResourceHandler("beers://catalog", h.cached(h.HandleBeerResource))
+	server.RegisterResourceHandler("beers://export", h.HandleBeerResource)
+	server.RegisterResourceHandler(styleViolationsURI, h.HandleBeerResource)
+	server.RegisterResourceHandler("breweries://directory", h.cached(h.HandleBreweryResource))
+	server.RegisterResourceHandler(breweryCountriesURI, h.cached(h.HandleBreweryResource))
+
+	for _, template := range h.resourceTemplates() {
+		server.RegisterResourceTemplate(template.ResourceTemplate, h.cachedTemplate(template.handler))
+	}
+	server.RegisterCompletion("bjcp://styles/{code}", "code", h.completeStyleCode)
+	server.RegisterCompletion("bjcp://styles/{code}/examples", "code", h.completeStyleCode)
+	server.RegisterCompletion("bjcp://{version}/styles/{code}", "version", h.completeGuidelineVersion)
+	server.RegisterCompletion("bjcp://{version}/styles/{code}", "code", h.completeAnyVersionStyleCode)
+	server.RegisterCompletion("bjcp://{version}/styles/{code}/examples", "version", h.completeGuidelineVersion)
+	server.RegisterCompletion("bjcp://{version}/styles/{code}/examples", "code", h.completeAnyVersionStyleCode)
+	if h.ingredientService != nil {
+		h.registerIngredientResources(server)
+	}
+	if h.usageLog != nil {
+		// A wildcard, as the summary is read with its admin_token and days in the query
+		server.RegisterResourceHandler("usage://*", h.HandleUsageResource)
+	}
+}
+
+// ResourceETag implements mcp.ResourceVersioner. The style guide resources change only when the
+// BJCP data does, so their ETag is its fingerprint; the style examples, which match catalog beers,
+// and the catalog resources are tagged by their content instead.
+func (h *ResourceHandlers) ResourceETag(uri string) string {
+	path, _, _ := strings.Cut(uri, "?")
+	if !strings.HasPrefix(path, "bjcp://") || strings.HasSuffix(path, "/examples") {
+		return ""
+	}
+	return h.bjcpService.Fingerprint()
 }
 
-// GetResourceDefinitions implements ResourceHandlerRegistry interface.
+// GetResourceDefinitions implements ResourceHandlerRegistry interface. It lists the concrete
+// resources, with the ETags known ahead of reading them; the parameterized ones are listed by
+// GetResourceTemplates.
 func (h *ResourceHandlers) GetResourceDefinitions() []mcp.Resource {
-	return []mcp.Resource{
-		{
-			URI:         "bjcp://styles",
-			Name:        "BJCP Beer Styles",
-			Description: "Complete BJCP beer style guidelines database",
-			MimeType:    "application/json",
-		},
+	resources := []mcp.Resource{
 		{
-			URI:         "bjcp://styles/{code}",
-			Name:        "BJCP Style Details",
-			Description: "Detailed information for a specific BJCP style",
-			MimeType:    "application/json",
+			URI:  "bjcp://styles",
+			Name: "BJCP Beer Styles",
+			Description: "Complete BJCP beer style guidelines database; add vital bounds such as " +
+				"?ibu_min=40&ibu_max=70 to list the styles whose ranges overlap them; ?format=csv or " +
+				"?format=markdown lists the styles as a table",
+			MimeType: "application/json",
 		},
 		{
 			URI:         "bjcp://categories",
 			Name:        "BJCP Categories",
-			Description: "List of all BJCP beer categories",
+			Description: "List of all BJCP beer categories, with each one's style count and code range",
 			MimeType:    "application/json",
 		},
 		{
-			URI:         "beers://catalog",
-			Name:        "Beer Catalog",
-			Description: "Commercial beer database",
-			MimeType:    "application/json",
+			URI:  "beers://catalog",
+			Name: "Beer Catalog",
+			Description: "Commercial beer database, a page at a time with total, offset, and next; filter and " +
+				"page with the search_beers arguments as a query " +
+				"(e.g., beers://catalog?style=IPA&offset=50&limit=50); ?format=csv or ?format=markdown " +
+				"renders the page as a table",
+			MimeType: "application/json",
+		},
+		{
+			URI:  "beers://export",
+			Name: "Beer Export",
+			Description: "Newline-delimited JSON of up to 5000 beers, sorted by name; filter with " +
+				"?name=, ?style=, ?brewery=, and ?location=",
+			MimeType: "application/x-ndjson",
+		},
+		{
+			URI:  styleViolationsURI,
+			Name: "Beer Style Violations",
+			Description: "Catalog beers whose stored ABV, IBU, or SRM fall outside the BJCP style their declared " +
+				"style maps to, with the distance of each miss",
+			MimeType: "application/json",
+		},
+		{
+			URI:  "breweries://directory",
+			Name: "Brewery Directory",
+			Description: "Directory of breweries, a page at a time with total, offset, and next; filter and " +
+				"page with the find_breweries arguments as a query " +
+				"(e.g., breweries://directory?country=South+Africa&page=2); ?format=csv or ?format=markdown " +
+				"renders the page as a table",
+			MimeType: "application/json",
+		},
+		{
+			URI:  breweryCountriesURI,
+			Name: "Brewery Countries",
+			Description: "The countries in the brewery directory with each one's brewery count, and the URI " +
+				"listing its states and provinces",
+			MimeType: "application/json",
 		},
 		{
-			URI:         "breweries://directory",
-			Name:        "Brewery Directory",
-			Description: "Searchable directory of breweries",
+			URI:  dataSourcesURI,
+			Name: "Data Sources",
+			Description: "When the seed data, file imports, Open Brewery DB sync, and BJCP guidelines last " +
+				"updated the data, with each one's record count and version",
+			MimeType: "application/json",
+		},
+		{
+			URI:         "stats://overview",
+			Name:        "Statistics Overview",
+			Description: "Brewery counts per country and beer counts, average ABV, and average IBU per style",
 			MimeType:    "application/json",
 		},
 	}
+	if h.ingredientService != nil {
+		resources = append(resources, ingredientResourceDefinitions()...)
+	}
+	if h.usageLog != nil {
+		resources = append(resources, usageResourceDefinition())
+	}
+	for i := range resources {
+		resources[i].ETag = h.ResourceETag(resources[i].URI)
+	}
+	return resources
+}
+
+// GetResourceTemplates lists the parameterized resources, served through resources/templates/list.
+func (h *ResourceHandlers) GetResourceTemplates() []mcp.ResourceTemplate {
+	templates := h.resourceTemplates()
+	definitions := make([]mcp.ResourceTemplate, 0, len(templates))
+	for _, template := range templates {
+		definitions = append(definitions, template.ResourceTemplate)
+	}
+	return definitions
+}
+
+func (h *ResourceHandlers) resourceTemplates() []resourceTemplate {
+	templates := []resourceTemplate{
+		{
+			ResourceTemplate: mcp.ResourceTemplate{
+				URITemplate: "bjcp://styles/{code}",
+				Name:        "BJCP Style Details",
+				Description: "Detailed information for a specific BJCP style",
+				MimeType:    "application/json",
+			},
+			handler: h.handleBJCPStyleDetail,
+		},
+		{
+			ResourceTemplate: mcp.ResourceTemplate{
+				URITemplate: "bjcp://styles/{code}/examples",
+				Name:        "BJCP Style Commercial Examples",
+				Description: "A style's commercial examples, split into the beers found in the catalog and " +
+					"the examples it does not carry",
+				MimeType: "application/json",
+			},
+			handler: h.handleBJCPStyleExamples,
+		},
+		{
+			ResourceTemplate: mcp.ResourceTemplate{
+				URITemplate: "bjcp://{version}/styles/{code}",
+				Name:        "BJCP Style Details by Guideline Version",
+				Description: "A BJCP style from a specific guideline version, such as bjcp://2015/styles/21A",
+				MimeType:    "application/json",
+			},
+			handler: h.handleBJCPStyleDetail,
+		},
+		{
+			ResourceTemplate: mcp.ResourceTemplate{
+				URITemplate: "bjcp://{version}/styles/{code}/examples",
+				Name:        "BJCP Style Commercial Examples by Guideline Version",
+				Description: "The commercial examples of a style from a specific guideline version, such as " +
+					"bjcp://2015/styles/21A/examples",
+				MimeType: "application/json",
+			},
+			handler: h.handleBJCPStyleExamples,
+		},
+		{
+			ResourceTemplate: mcp.ResourceTemplate{
+				URITemplate: "bjcp://categories/{name}",
+				Name:        "BJCP Category Styles",
+				Description: "The styles in a BJCP category, matched case-insensitively by URL-encoded name " +
+					"(e.g., bjcp://categories/Pale%20American%20Ale)",
+				MimeType: "application/json",
+			},
+			handler: h.handleBJCPCategory,
+		},
+		{
+			ResourceTemplate: mcp.ResourceTemplate{
+				URITemplate: "beers://{id}",
+				Name:        "Beer Details",
+				Description: "Full record for one beer, including its brewery and ingredients",
+				MimeType:    "application/json",
+			},
+			handler: h.handleBeerDetail,
+		},
+		{
+			ResourceTemplate: mcp.ResourceTemplate{
+				URITemplate: "breweries://{id}",
+				Name:        "Brewery Details",
+				Description: "Full record for one brewery, including its beer count and its venues with opening " +
+					"hours and whether each is open now in UTC",
+				MimeType: "application/json",
+			},
+			handler: h.handleBreweryDetail,
+		},
+		{
+			ResourceTemplate: mcp.ResourceTemplate{
+				URITemplate: "breweries://{id}?timezone={timezone}",
+				Name:        "Brewery Details in a Time Zone",
+				Description: "Full record for one brewery, with whether each venue is open now in a URL-encoded " +
+					"IANA time zone (e.g., breweries://8?timezone=Africa%2FJohannesburg)",
+				MimeType: "application/json",
+			},
+			handler: h.handleBreweryDetail,
+		},
+		{
+			ResourceTemplate: mcp.ResourceTemplate{
+				URITemplate: "breweries://{id}/beers",
+				Name:        "Brewery Beers",
+				Description: "Beers made by one brewery, sorted by name",
+				MimeType:    "application/json",
+			},
+			handler: h.handleBreweryBeers,
+		},
+		{
+			ResourceTemplate: mcp.ResourceTemplate{
+				URITemplate: "breweries://countries/{country}/regions",
+				Name:        "Brewery Country Regions",
+				Description: "The states and provinces of a country's breweries with each one's brewery count, " +
+					"the country matched case-insensitively by URL-encoded name " +
+					"(e.g., breweries://countries/South%20Africa/regions)",
+				MimeType: "application/json",
+			},
+			handler: h.handleBreweryRegions,
+		},
+	}
+	if h.ingredientService != nil {
+		templates = append(templates, h.ingredientResourceTemplates()...)
+	}
+	return templates
 }
 
-// HandleBJCPResource handles BJCP-related resource requests.
+// HandleBJCPResource handles the concrete BJCP resource requests; styles and categories are served
+// by the resource templates.
 func (h *ResourceHandlers) HandleBJCPResource(ctx context.Context, uri string) (*mcp.ResourceContent, error) {
+	path, rawQuery, _ := strings.Cut(uri, "?")
 	switch {
-	case uri == "bjcp://styles":
-		return h.handleAllBJCPStyles(ctx)
+	case path == "bjcp://styles":
+		params, err := url.ParseQuery(rawQuery)
+		if err != nil {
+			return nil, mcp.NewMCPError(mcp.InvalidParams, fmt.Sprintf("Invalid BJCP style query: %s", uri), nil)
+		}
+		format, err := resourceFormat(params)
+		if err != nil {
+			return nil, err
+		}
+		params.Del("format")
+		if len(params) == 0 {
+			return h.handleAllBJCPStyles(ctx, uri, format)
+		}
+		return h.handleBJCPStyleSearch(ctx, uri, params, format)
 	case uri == "bjcp://categories":
 		return h.handleBJCPCategories(ctx)
-	case strings.HasPrefix(uri, "bjcp://styles/"):
-		styleCode := strings.TrimPrefix(uri, "bjcp://styles/")
-		return h.handleBJCPStyleDetail(ctx, styleCode)
 	default:
 		return nil, mcp.NewMCPError(mcp.MethodNotFound, fmt.Sprintf("BJCP resource not found: %s", uri), nil)
 	}
 }
 
-// HandleBeerResource handles beer-related resource requests.
+// HandleBeerResource handles the beer catalog, export, and style violation requests; single beers
+// are served by the beers://{id} template.
 func (h *ResourceHandlers) HandleBeerResource(ctx context.Context, uri string) (*mcp.ResourceContent, error) {
-	switch uri {
-	case "beers://catalog":
-		return h.handleBeerCatalog(ctx)
-	default:
-		return nil, mcp.NewMCPError(mcp.MethodNotFound, fmt.Sprintf("Beer resource not found: %s", uri), nil)
+	path, rawQuery, _ := strings.Cut(uri, "?")
+	if path == "beers://catalog" {
+		args, params, err := resourceQueryArgs(uri, rawQuery, beerCatalogParams)
+		if err != nil {
+			return nil, err
+		}
+		return h.handleBeerCatalog(ctx, uri, args, params)
+	}
+	if path == "beers://export" {
+		params, err := url.ParseQuery(rawQuery)
+		if err != nil {
+			return nil, mcp.NewMCPError(mcp.InvalidParams, fmt.Sprintf("Invalid beer export query: %s", uri), nil)
+		}
+		return h.handleBeerExport(ctx, uri, params)
 	}
+	if path == styleViolationsURI {
+		return h.handleStyleViolations(ctx, uri)
+	}
+	return nil, mcp.NewMCPError(mcp.MethodNotFound, fmt.Sprintf("Beer resource not found: %s", uri), nil)
 }
 
-// HandleBreweryResource handles brewery-related resource requests.
+// HandleBreweryResource handles brewery directory and country list requests; single breweries and
+// a country's regions are served by the breweries://{id} and breweries://countries templates.
 func (h *ResourceHandlers) HandleBreweryResource(ctx context.Context, uri string) (*mcp.ResourceContent, error) {
-	switch uri {
-	case "breweries://directory":
-		return h.handleBreweryDirectory(ctx)
-	default:
-		return nil, mcp.NewMCPError(mcp.MethodNotFound, fmt.Sprintf("Brewery resource not found: %s", uri), nil)
+	path, rawQuery, _ := strings.Cut(uri, "?")
+	if path == "breweries://directory" {
+		args, params, err := resourceQueryArgs(uri, rawQuery, breweryDirectoryParams)
+		if err != nil {
+			return nil, err
+		}
+		return h.handleBreweryDirectory(ctx, uri, args, params)
+	}
+	if uri == breweryCountriesURI {
+		return h.handleBreweryCountries(ctx)
+	}
+	return nil, mcp.NewMCPError(mcp.MethodNotFound, fmt.Sprintf("Brewery resource not found: %s", uri), nil)
+}
+
+// HandleStatsResource handles aggregate statistics resource requests.
+func (h *ResourceHandlers) HandleStatsResource(ctx context.Context, uri string) (*mcp.ResourceContent, error) {
+	if uri != "stats://overview" {
+		return nil, mcp.NewMCPError(mcp.MethodNotFound, fmt.Sprintf("Stats resource not found: %s", uri), nil)
+	}
+	stats, err := h.breweryService.GetStats(ctx, services.StatsScope{})
+	if err != nil {
+		return nil, serviceError(err, "failed to get stats overview")
+	}
+	content, err := json.Marshal(stats)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal stats overview: %w", err)
+	}
+	return &mcp.ResourceContent{
+		URI:      uri,
+		MimeType: "application/json",
+		Text:     string(content),
+	}, nil
+}
+
+// resourceID parses the positive integer {id} of a detail URI such as beers://42.
+func resourceID(uri string, params map[string]string) (int, error) {
+	id, err := strconv.Atoi(params["id"])
+	if err != nil || id <= 0 {
+		return 0, mcp.NewMCPError(mcp.InvalidParams,
+			fmt.Sprintf("Invalid resource ID in %s; expected a positive integer", uri), nil)
+	}
+	return id, nil
+}
+
+// resourceQueryArgs parses the query of a resource URI into arguments for the tool parsers, such as
+// parseBeerSearchQuery, rejecting parameters not in allowed. The values of a repeated parameter are
+// joined with commas, so type=micro&type=brewpub lists both types, while a repeated number fails to
+// parse rather than one of its values being ignored.
+func resourceQueryArgs(uri, rawQuery string, allowed []string) (map[string]interface{}, url.Values, error) {
+	params, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return nil, nil, mcp.NewMCPError(mcp.InvalidParams, fmt.Sprintf("Invalid resource query: %s", uri), nil)
+	}
+	args := make(map[string]interface{}, len(params))
+	for key, values := range params {
+		if !slices.Contains(allowed, key) {
+			return nil, nil, mcp.NewMCPError(mcp.InvalidParams, fmt.Sprintf("Unknown query parameter: %s", key),
+				map[string]interface{}{"supported_params": allowed})
+		}
+		args[key] = strings.Join(values, ",")
+	}
+	return args, params, nil
+}
+
+// nextPageURI returns the URI of the page following one that ends at end, keeping the filters in
+// params and replacing its page or offset with the offset end.
+func nextPageURI(path string, params url.Values, end, limit int) string {
+	next := url.Values{}
+	for key, values := range params {
+		if key != "page" && key != "offset" {
+			next[key] = values
+		}
+	}
+	next.Set("offset", strconv.Itoa(end))
+	next.Set("limit", strconv.Itoa(limit))
+	return path + "?" + next.Encode()
+}
+
+// completeStyleCode suggests the codes of the default guideline version's styles.
+func (h *ResourceHandlers) completeStyleCode(_ context.Context, value string) ([]string, error) {
+	return h.bjcpService.CompleteStyleCode(value), nil
+}
+
+// completeAnyVersionStyleCode suggests the codes of the styles of every loaded guideline version,
+// as completion does not say which version the client has chosen.
+func (h *ResourceHandlers) completeAnyVersionStyleCode(_ context.Context, value string) ([]string, error) {
+	var codes []string
+	for _, version := range h.bjcpService.Versions() {
+		codes = append(codes, h.bjcpService.CompleteStyleCodeVersion(value, version)...)
+	}
+	slices.SortFunc(codes, data.CompareStyleCodes)
+	return slices.Compact(codes), nil
+}
+
+// completeGuidelineVersion suggests the loaded guideline versions.
+func (h *ResourceHandlers) completeGuidelineVersion(_ context.Context, value string) ([]string, error) {
+	versions := []string{}
+	for _, version := range h.bjcpService.Versions() {
+		if strings.HasPrefix(version, strings.TrimSpace(value)) {
+			versions = append(versions, version)
+		}
 	}
+	return versions, nil
 }
 
-func (h *ResourceHandlers) handleAllBJCPStyles(_ context.Context) (*mcp.ResourceContent, error) {
+// handleAllBJCPStyles serves bjcp://styles: a summary of the guidelines as JSON, or every style with
+// its vitals, ordered by code, as CSV or markdown.
+func (h *ResourceHandlers) handleAllBJCPStyles(_ context.Context, uri, format string) (*mcp.ResourceContent, error) {
+	if format != formatJSON {
+		styles := h.bjcpService.GetAllStyles()
+		codes := slices.SortedFunc(maps.Keys(styles), data.CompareStyleCodes)
+		table := resourceTable{
+			title:  fmt.Sprintf("BJCP %s Beer Styles", h.bjcpService.GetMetadata().Version),
+			notes:  []string{fmt.Sprintf("%d styles.", len(codes))},
+			header: styleTableHeader,
+		}
+		for _, code := range codes {
+			table.rows = append(table.rows, styleTableRow(styles[code]))
+		}
+		return renderTable(uri, format, table)
+	}
+
 	// For now, return a summary of available styles
 	categories := h.bjcpService.GetCategories()
 	result := map[string]interface{}{
@@ -124,9 +518,12 @@ func (h *ResourceHandlers) handleAllBJCPStyles(_ context.Context) (*mcp.Resource
 		"version":      h.bjcpService.GetMetadata().Version,
 		"categories":   categories,
 		"total_styles": len(h.bjcpService.GetAllStyles()),
+		"versions":     h.bjcpService.Versions(),
 		"usage": map[string]string{
-			"lookup_by_code": "bjcp://styles/{code}",
-			"example":        "bjcp://styles/21A",
+			"lookup_by_code":         "bjcp://styles/{code}",
+			"example":                "bjcp://styles/21A",
+			"lookup_by_version_code": "bjcp://{version}/styles/{code}",
+			"version_example":        "bjcp://2015/styles/21A",
 		},
 	}
 	content, err := json.Marshal(result)
@@ -134,7 +531,77 @@ func (h *ResourceHandlers) handleAllBJCPStyles(_ context.Context) (*mcp.Resource
 		return nil, fmt.Errorf("failed to marshal BJCP styles: %w", err)
 	}
 	return &mcp.ResourceContent{
-		URI:      "bjcp://styles",
+		URI:      uri,
+		MimeType: "application/json",
+		Text:     string(content),
+	}, nil
+}
+
+// handleBJCPStyleSearch serves bjcp://styles?ibu_min=40&ibu_max=70, taking the query parameters of
+// the bjcp_style_search tool other than limit, in the given format.
+func (h *ResourceHandlers) handleBJCPStyleSearch(
+	_ context.Context,
+	uri string,
+	params url.Values,
+	format string,
+) (*mcp.ResourceContent, error) {
+	args := make(map[string]interface{}, len(params))
+	for key := range params {
+		if vital, bound, _ := strings.Cut(key, "_"); key != "include_unspecified" &&
+			(!slices.Contains(styleSearchVitals, vital) || (bound != "min" && bound != "max")) {
+			return nil, mcp.NewMCPError(mcp.InvalidParams, fmt.Sprintf("Unknown BJCP style query parameter: %s", key),
+				nil)
+		}
+		args[key] = params.Get(key)
+	}
+	criteria, err := parseVitalsCriteria(args)
+	if err != nil {
+		return nil, err
+	}
+	results, err := h.bjcpService.SearchStylesByVitals(criteria)
+	if err != nil {
+		return nil, mcp.NewMCPError(mcp.InvalidParams, err.Error(), nil)
+	}
+
+	if format != formatJSON {
+		table := resourceTable{
+			title:  "BJCP Styles by Vitals",
+			notes:  []string{fmt.Sprintf("%d styles, the most central first.", len(results))},
+			header: append(slices.Clone(styleTableHeader), "centrality"),
+		}
+		for _, result := range results {
+			table.rows = append(table.rows, append(styleTableRow(result.Style), formatNumber(result.Centrality)))
+		}
+		return renderTable(uri, format, table)
+	}
+
+	type styleSummary struct {
+		Code       string      `json:"code"`
+		Name       string      `json:"name"`
+		Category   string      `json:"category"`
+		Vitals     data.Vitals `json:"vitals"`
+		Centrality float64     `json:"centrality"`
+	}
+	styles := make([]styleSummary, 0, len(results))
+	for _, result := range results {
+		styles = append(styles, styleSummary{
+			Code:       result.Style.Code,
+			Name:       result.Style.Name,
+			Category:   result.Style.Category,
+			Vitals:     result.Style.Vitals,
+			Centrality: result.Centrality,
+		})
+	}
+	content, err := json.Marshal(map[string]interface{}{
+		"criteria": criteria,
+		"count":    len(styles),
+		"styles":   styles,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal BJCP style search: %w", err)
+	}
+	return &mcp.ResourceContent{
+		URI:      uri,
 		MimeType: "application/json",
 		Text:     string(content),
 	}, nil
@@ -145,6 +612,7 @@ func (h *ResourceHandlers) handleBJCPCategories(_ context.Context) (*mcp.Resourc
 	result := map[string]interface{}{
 		"categories": categories,
 		"count":      len(categories),
+		"summaries":  h.bjcpService.GetCategorySummaries(),
 	}
 	content, err := json.Marshal(result)
 	if err != nil {
@@ -157,44 +625,189 @@ func (h *ResourceHandlers) handleBJCPCategories(_ context.Context) (*mcp.Resourc
 	}, nil
 }
 
-func (h *ResourceHandlers) handleBJCPStyleDetail(_ context.Context, styleCode string) (*mcp.ResourceContent, error) {
-	style, err := h.bjcpService.GetStyleByCode(styleCode)
+// handleBJCPCategory serves the styles of a category named by a URL-encoded, case-insensitive name,
+// such as bjcp://categories/Pale%20American%20Ale.
+func (h *ResourceHandlers) handleBJCPCategory(
+	_ context.Context,
+	_ string,
+	params map[string]string,
+) (*mcp.ResourceContent, error) {
+	category, err := url.QueryUnescape(params["name"])
 	if err != nil {
-		return nil, mcp.NewMCPError(mcp.MethodNotFound, fmt.Sprintf("BJCP style not found: %s", styleCode), nil)
+		return nil, mcp.NewMCPError(mcp.InvalidParams, fmt.Sprintf("Invalid BJCP category name: %s", params["name"]),
+			nil)
+	}
+	listing, err := listCategory(h.bjcpService, category, "", mcp.MethodNotFound)
+	if err != nil {
+		return nil, err
+	}
+	content, err := json.Marshal(listing)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal BJCP category: %w", err)
+	}
+	return &mcp.ResourceContent{
+		URI:      "bjcp://categories/" + url.PathEscape(listing.Category),
+		MimeType: "application/json",
+		Text:     string(content),
+	}, nil
+}
+
+// isGuidelineYear reports whether a URI segment looks like a BJCP guideline version, a four digit
+// year.
+func isGuidelineYear(segment string) bool {
+	if len(segment) != 4 {
+		return false
+	}
+	_, err := strconv.Atoi(segment)
+	return err == nil
+}
+
+// handleBJCPStyleDetail serves a style from the guideline version in the URI, or from the default
+// version when it names none. The code is normalized as bjcp_lookup normalizes it, see
+// data.NormalizeStyleCode, and the content carries the canonical URI.
+func (h *ResourceHandlers) handleBJCPStyleDetail(
+	_ context.Context,
+	uri string,
+	params map[string]string,
+) (*mcp.ResourceContent, error) {
+	style, uri, err := h.resourceStyle(uri, params)
+	if err != nil {
+		return nil, err
 	}
 	content, err := json.Marshal(style)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal BJCP style: %w", err)
 	}
 	return &mcp.ResourceContent{
-		URI:      fmt.Sprintf("bjcp://styles/%s", styleCode),
+		URI:      uri,
 		MimeType: "application/json",
 		Text:     string(content),
 	}, nil
 }
 
-func (h *ResourceHandlers) handleBeerCatalog(ctx context.Context) (*mcp.ResourceContent, error) {
-	// Return a sample of beers to show the catalog structure
-	query := services.BeerSearchQuery{
-		Limit: resourceSampleLimit,
+// handleBJCPStyleExamples serves a style's commercial examples resolved against the beer catalog.
+func (h *ResourceHandlers) handleBJCPStyleExamples(
+	ctx context.Context,
+	uri string,
+	params map[string]string,
+) (*mcp.ResourceContent, error) {
+	style, uri, err := h.resourceStyle(uri, params)
+	if err != nil {
+		return nil, err
+	}
+	examples, err := h.examples.resolve(ctx, h.beerService, style, params["version"])
+	if err != nil {
+		return nil, err
+	}
+	content, err := json.Marshal(examples)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal commercial examples: %w", err)
 	}
+	return &mcp.ResourceContent{
+		URI:      uri + "/examples",
+		MimeType: "application/json",
+		Text:     string(content),
+	}, nil
+}
 
-	// Since we need at least one search parameter, let's search for a common style
-	query.Style = "IPA"
+// resourceStyle looks up the style named by the {code} and {version} of a bjcp:// style URI,
+// returning it with the canonical URI for its normalized code. A version that is not a guideline
+// year, as in bjcp://latest/styles/21A, does not name a style.
+func (h *ResourceHandlers) resourceStyle(uri string, params map[string]string) (*data.BJCPStyle, string, error) {
+	version, versioned := params["version"]
+	if versioned && !isGuidelineYear(version) {
+		return nil, "", mcp.NewMCPError(mcp.MethodNotFound, fmt.Sprintf("BJCP resource not found: %s", uri), nil)
+	}
+	styleCode := data.NormalizeStyleCode(params["code"])
+	if !data.IsStyleCode(styleCode) {
+		return nil, "", mcp.NewMCPError(mcp.InvalidParams,
+			fmt.Sprintf("Invalid BJCP style code: %s; expected a code such as 21A", styleCode), nil)
+	}
+	uri = "bjcp://styles/" + styleCode
+	if version != "" {
+		uri = "bjcp://" + version + "/styles/" + styleCode
+	}
+	style, err := h.bjcpService.GetStyleByCodeVersion(styleCode, version)
+	if errors.Is(err, data.ErrUnknownGuidelineVersion) {
+		return nil, "", guidelineVersionError(h.bjcpService, version, err)
+	}
+	if err != nil {
+		return nil, "", mcp.NewMCPError(mcp.MethodNotFound, fmt.Sprintf("BJCP style not found: %s", styleCode), nil)
+	}
+	return style, uri, nil
+}
 
-	beers, err := h.beerService.SearchBeers(ctx, query)
+// handleBeerCatalog serves a page of the beers matching the search_beers arguments in args, every
+// beer when there are none, with the URI of the next page when there is one. The format parameter
+// renders the page as a CSV or markdown table instead of JSON, whose beers group_by_brewery nests
+// under their breweries.
+func (h *ResourceHandlers) handleBeerCatalog(
+	ctx context.Context,
+	uri string,
+	args map[string]interface{},
+	params url.Values,
+) (*mcp.ResourceContent, error) {
+	query, err := parseBeerSearchQuery(args)
+	if err != nil {
+		return nil, err
+	}
+	if code, _ := args["style_code"].(string); strings.TrimSpace(code) != "" {
+		if _, err = resolveSearchStyle(h.bjcpService, code, &query); err != nil {
+			return nil, err
+		}
+	}
+	grouped, err := parseOptionalBool(args, "group_by_brewery")
+	if err != nil {
+		return nil, err
+	}
+	format, err := resourceFormat(params)
+	if err != nil {
+		return nil, err
+	}
+	page, err := h.beerService.SearchBeersPage(ctx, query)
+	if errors.Is(err, services.ErrInvalidSearchQuery) || errors.Is(err, services.ErrInvalidLimit) {
+		return nil, mcp.NewMCPError(mcp.InvalidParams, err.Error(), nil)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to get beer catalog sample: %w", err)
+		return nil, serviceError(err, "failed to get beer catalog")
+	}
+	next := ""
+	if page.HasMore {
+		next = nextPageURI("beers://catalog", params, page.Offset+len(page.Items), query.Limit)
+	}
+	if format != formatJSON {
+		table := resourceTable{
+			title:  "Beer Catalog",
+			notes:  []string{pageNote("beers", page.Offset, len(page.Items), page.TotalCount, next)},
+			header: beerTableHeader,
+		}
+		for _, beer := range page.Items {
+			table.rows = append(table.rows, beerTableRow(beer))
+		}
+		return renderTable(uri, format, table)
 	}
 
 	result := map[string]interface{}{
-		"description":  "Commercial Beer Catalog",
-		"sample_beers": beers,
+		"description": "Commercial Beer Catalog",
+		"total":       page.TotalCount,
+		"offset":      page.Offset,
+		"limit":       query.Limit,
 		"usage": map[string]string{
 			"search_tool": "Use the search_beers tool to query specific beers",
-			"parameters":  "name, style, brewery, location",
+			"parameters":  strings.Join(beerCatalogParams, ", "),
+			"example":     "beers://catalog?style=IPA&offset=50&limit=50",
 		},
 	}
+	if grouped {
+		result["breweries"] = groupBeersByBrewery(page.Items)
+	} else {
+		result["beers"] = page.Items
+		result[sampleBeersAlias] = page.Items
+		result["deprecated"] = map[string]string{sampleBeersAlias: "alias of beers, removed in the next release"}
+	}
+	if next != "" {
+		result["next"] = next
+	}
 
 	content, err := json.Marshal(result)
 	if err != nil {
@@ -202,37 +815,104 @@ func (h *ResourceHandlers) handleBeerCatalog(ctx context.Context) (*mcp.Resource
 	}
 
 	return &mcp.ResourceContent{
-		URI:      "beers://catalog",
+		URI:      uri,
 		MimeType: "application/json",
 		Text:     string(content),
 	}, nil
 }
 
-func (h *ResourceHandlers) handleBreweryDirectory(ctx context.Context) (*mcp.ResourceContent, error) {
-	// Return a sample of breweries to show the directory structure
-	query := services.BrewerySearchQuery{
-		Limit: resourceSampleLimit,
+// handleBeerExport returns the beers matching the name, style, brewery, and location filters in
+// params as newline-delimited JSON, at most beerExportLimit of them. Each beer is encoded as it is
+// scanned, so the rows are never collected into a slice.
+func (h *ResourceHandlers) handleBeerExport(
+	ctx context.Context,
+	uri string,
+	params url.Values,
+) (*mcp.ResourceContent, error) {
+	beers, err := h.beerService.SearchBeersIter(ctx, services.BeerSearchQuery{
+		Name:     params.Get("name"),
+		Style:    params.Get("style"),
+		Brewery:  params.Get("brewery"),
+		Location: params.Get("location"),
+		Limit:    beerExportLimit,
+	})
+	if err != nil {
+		return nil, mcp.NewMCPError(mcp.InvalidParams, err.Error(), nil)
+	}
+
+	var text strings.Builder
+	encoder := json.NewEncoder(&text)
+	for beer, err := range beers {
+		if err != nil {
+			return nil, serviceError(err, "failed to export beers")
+		}
+		if err := encoder.Encode(beer); err != nil {
+			return nil, fmt.Errorf("failed to marshal beer export: %w", err)
+		}
 	}
 
-	// Search for breweries in a popular beer region
-	query.State = "California"
+	return &mcp.ResourceContent{
+		URI:      uri,
+		MimeType: "application/x-ndjson",
+		Text:     text.String(),
+	}, nil
+}
 
-	breweries, err := h.breweryService.SearchBreweries(ctx, query)
+// handleBreweryDirectory serves a page of the breweries matching the find_breweries arguments in
+// args, every brewery when there are none, with the URI of the next page when there is one, as
+// JSON or as the table the format parameter asks for.
+func (h *ResourceHandlers) handleBreweryDirectory(
+	ctx context.Context,
+	uri string,
+	args map[string]interface{},
+	params url.Values,
+) (*mcp.ResourceContent, error) {
+	query, err := parseBrewerySearchQuery(args)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get brewery directory sample: %w", err)
+		return nil, err
 	}
-	// Ensure sample_breweries is always an array, not null
-	if breweries == nil {
-		breweries = []*services.BrewerySearchResult{}
+	format, err := resourceFormat(params)
+	if err != nil {
+		return nil, err
+	}
+	page, err := h.breweryService.SearchBreweriesPage(ctx, query)
+	if errors.Is(err, services.ErrInvalidSearchQuery) || errors.Is(err, services.ErrInvalidLimit) {
+		return nil, mcp.NewMCPError(mcp.InvalidParams, err.Error(), nil)
+	}
+	if err != nil {
+		return nil, serviceError(err, "failed to get brewery directory")
 	}
+	next := ""
+	if page.HasMore {
+		next = nextPageURI("breweries://directory", params, page.Offset+len(page.Items), query.Limit)
+	}
+	if format != formatJSON {
+		table := resourceTable{
+			title:  "Brewery Directory",
+			notes:  []string{pageNote("breweries", page.Offset, len(page.Items), page.TotalCount, next)},
+			header: breweryTableHeader,
+		}
+		for _, brewery := range page.Items {
+			table.rows = append(table.rows, breweryTableRow(brewery))
+		}
+		return renderTable(uri, format, table)
+	}
+
 	result := map[string]interface{}{
-		"description":      "Brewery Directory",
-		"sample_breweries": breweries,
+		"description": "Brewery Directory",
+		"breweries":   page.Items,
+		"total":       page.TotalCount,
+		"offset":      page.Offset,
+		"limit":       query.Limit,
 		"usage": map[string]string{
 			"search_tool": "Use the find_breweries tool to query specific breweries",
-			"parameters":  "name, location, city, state, country",
+			"parameters":  strings.Join(breweryDirectoryParams, ", "),
+			"example":     "breweries://directory?country=South+Africa&page=2",
 		},
 	}
+	if next != "" {
+		result["next"] = next
+	}
 
 	content, err := json.Marshal(result)
 	if err != nil {
@@ -240,7 +920,171 @@ func (h *ResourceHandlers) handleBreweryDirectory(ctx context.Context) (*mcp.Res
 	}
 
 	return &mcp.ResourceContent{
-		URI:      "breweries://directory",
+		URI:      uri,
+		MimeType: "application/json",
+		Text:     string(content),
+	}, nil
+}
+
+func (h *ResourceHandlers) handleBeerDetail(
+	ctx context.Context,
+	uri string,
+	params map[string]string,
+) (*mcp.ResourceContent, error) {
+	id, err := resourceID(uri, params)
+	if err != nil {
+		return nil, err
+	}
+	beer, err := h.beerService.GetBeerByID(ctx, id)
+	if errors.Is(err, services.ErrNotFound) {
+		return nil, mcp.NewMCPError(mcp.MethodNotFound, fmt.Sprintf("Beer not found: %d", id), nil)
+	}
+	if err != nil {
+		return nil, serviceError(err, "failed to get beer")
+	}
+	ingredients, err := h.beerService.GetBeerIngredients(ctx, id)
+	if err != nil {
+		return nil, serviceError(err, "failed to get beer ingredients")
+	}
+	content, err := json.Marshal(struct {
+		*services.BeerDetail
+		Ingredients *services.BeerIngredients `json:"ingredients"`
+	}{beer, ingredients})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal beer: %w", err)
+	}
+	return &mcp.ResourceContent{
+		URI:      uri,
+		MimeType: "application/json",
+		Text:     string(content),
+	}, nil
+}
+
+func (h *ResourceHandlers) handleBreweryDetail(
+	ctx context.Context,
+	uri string,
+	params map[string]string,
+) (*mcp.ResourceContent, error) {
+	id, err := resourceID(uri, params)
+	if err != nil {
+		return nil, err
+	}
+	location, err := resourceTimezone(uri, params)
+	if err != nil {
+		return nil, err
+	}
+	brewery, err := h.breweryService.GetBreweryByID(ctx, id)
+	if errors.Is(err, services.ErrNotFound) {
+		return nil, mcp.NewMCPError(mcp.MethodNotFound, fmt.Sprintf("Brewery not found: %d", id), nil)
+	}
+	if err != nil {
+		return nil, serviceError(err, "failed to get brewery")
+	}
+	venues, err := h.breweryService.GetBreweryVenues(ctx, id)
+	if err != nil {
+		return nil, serviceError(err, "failed to get brewery venues")
+	}
+	content, err := json.Marshal(newBreweryDetailJSON(brewery, venues, h.now().In(location)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal brewery: %w", err)
+	}
+	return &mcp.ResourceContent{
+		URI:      uri,
+		MimeType: "application/json",
+		Text:     string(content),
+	}, nil
+}
+
+func (h *ResourceHandlers) handleBreweryBeers(
+	ctx context.Context,
+	uri string,
+	params map[string]string,
+) (*mcp.ResourceContent, error) {
+	id, err := resourceID(uri, params)
+	if err != nil {
+		return nil, err
+	}
+	beers, err := h.breweryService.GetBreweryBeers(ctx, id, 0)
+	if errors.Is(err, services.ErrNotFound) {
+		return nil, mcp.NewMCPError(mcp.MethodNotFound, fmt.Sprintf("Brewery not found: %d", id), nil)
+	}
+	if err != nil {
+		return nil, serviceError(err, "failed to get brewery beers")
+	}
+	content, err := json.Marshal(beers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal brewery beers: %w", err)
+	}
+	return &mcp.ResourceContent{
+		URI:      uri,
+		MimeType: "application/json",
+		Text:     string(content),
+	}, nil
+}
+
+// breweryCountryJSON is a country in breweries://countries, with the URI of its regions.
+type breweryCountryJSON struct {
+	*services.CountryCount
+	Regions string `json:"regions"`
+}
+
+// countryRegionsURI returns the URI listing the regions of country.
+func countryRegionsURI(country string) string {
+	return breweryCountriesURI + "/" + url.PathEscape(country) + "/regions"
+}
+
+// handleBreweryCountries serves the countries in the brewery directory, in alphabetical order.
+func (h *ResourceHandlers) handleBreweryCountries(ctx context.Context) (*mcp.ResourceContent, error) {
+	counts, err := h.breweryService.ListCountries(ctx)
+	if err != nil {
+		return nil, serviceError(err, "failed to list countries")
+	}
+	countries := make([]breweryCountryJSON, len(counts))
+	for i, count := range counts {
+		countries[i] = breweryCountryJSON{CountryCount: count, Regions: countryRegionsURI(count.Country)}
+	}
+	content, err := json.Marshal(map[string]interface{}{
+		"description": "Brewery Countries",
+		"countries":   countries,
+		"total":       len(countries),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal brewery countries: %w", err)
+	}
+	return &mcp.ResourceContent{
+		URI:      breweryCountriesURI,
+		MimeType: "application/json",
+		Text:     string(content),
+	}, nil
+}
+
+// handleBreweryRegions serves the states and provinces of a country named by a URL-encoded,
+// case-insensitive name, such as breweries://countries/South%20Africa/regions. The content carries
+// the URI of the country's name as the directory records it.
+func (h *ResourceHandlers) handleBreweryRegions(
+	ctx context.Context,
+	_ string,
+	params map[string]string,
+) (*mcp.ResourceContent, error) {
+	country, err := url.QueryUnescape(params["country"])
+	if err != nil {
+		return nil, mcp.NewMCPError(mcp.InvalidParams, fmt.Sprintf("Invalid country name: %s", params["country"]),
+			nil)
+	}
+	regions, err := h.breweryService.ListRegions(ctx, country)
+	if errors.Is(err, services.ErrNotFound) {
+		return nil, mcp.NewMCPError(mcp.MethodNotFound, fmt.Sprintf("No breweries in country: %s", country),
+			map[string]interface{}{"countries": breweryCountriesURI})
+	}
+	if err != nil {
+		return nil, serviceError(err, "failed to list regions")
+	}
+	content, err := json.Marshal(regions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal country regions: %w", err)
+	}
+	return &mcp.ResourceContent{
+		URI:      countryRegionsURI(regions.Country),
 		MimeType: "application/json",
 		Text:     string(content),
 	}, nil
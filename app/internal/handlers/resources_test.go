@@ -3,20 +3,31 @@ package handlers_test
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"reflect"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/CharlRitter/brewsource-mcp/app/internal/handlers"
 	"github.com/CharlRitter/brewsource-mcp/app/internal/mcp"
 	"github.com/CharlRitter/brewsource-mcp/app/internal/services"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/services/servicestest"
 	"github.com/CharlRitter/brewsource-mcp/app/pkg/data"
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/jmoiron/sqlx"
 	"github.com/redis/go-redis/v9"
 )
 
+// readResource reads uri through an MCP server, which serves templated URIs such as
+// bjcp://styles/{code} from their resource templates.
+func readResource(h *handlers.ResourceHandlers, uri string) (*mcp.ResourceContent, error) {
+	return mcp.NewServer(nil, h, nil).ReadResource(context.Background(), uri)
+}
+
 // Test RegisterResourceHandlers function.
 func TestRegisterResourceHandlers(t *testing.T) {
 	bjcpData := &data.BJCPData{
@@ -28,7 +39,7 @@ func TestRegisterResourceHandlers(t *testing.T) {
 	}
 
 	resourceHandlers := handlers.NewResourceHandlers(bjcpData, nil, nil)
-	server := mcp.NewServer(nil, resourceHandlers)
+	server := mcp.NewServer(nil, resourceHandlers, nil)
 
 	// Call RegisterResourceHandlers
 	resourceHandlers.RegisterResourceHandlers(server)
@@ -76,7 +87,7 @@ func newTestHandlersWithDB(db *sqlx.DB) *handlers.ResourceHandlers {
 		Categories: []string{"IPA", "Lager"},
 		Metadata:   data.Metadata{Version: "2021"},
 	}
-	redisClient := &redis.Client{}
+	var redisClient *redis.Client // No caching
 	beerService := services.NewBeerService(db, redisClient)
 	breweryService := services.NewBreweryService(db, redisClient)
 	return handlers.NewResourceHandlers(bjcpData, beerService, breweryService)
@@ -107,9 +118,7 @@ func newTestHandlers() *handlers.ResourceHandlers {
 		Categories: []string{"IPA", "Lager"},
 		Metadata:   data.Metadata{Version: "2021"},
 	}
-	beerService := &services.BeerService{}       // Not used in these tests
-	breweryService := &services.BreweryService{} // Use real type for compatibility
-	return handlers.NewResourceHandlers(bjcpData, beerService, breweryService)
+	return handlers.NewResourceHandlers(bjcpData, &servicestest.BeerService{}, &servicestest.BreweryService{})
 }
 
 func TestHandleBJCPResource_Styles(t *testing.T) {
@@ -240,10 +249,7 @@ func TestHandleBJCPResource_Categories(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			bjcpData := tt.setupData()
-			redisClient := &redis.Client{}
-			beerService := services.NewBeerService(nil, redisClient)
-			breweryService := services.NewBreweryService(nil, redisClient)
-			h := handlers.NewResourceHandlers(bjcpData, beerService, breweryService)
+			h := handlers.NewResourceHandlers(bjcpData, &servicestest.BeerService{}, &servicestest.BreweryService{})
 
 			res, err := h.HandleBJCPResource(context.Background(), "bjcp://categories")
 			if err != nil {
@@ -348,16 +354,24 @@ func getStyleDetailTestCases() []struct {
 		{
 			name:          "whitespace in style code",
 			styleCode:     "21 A",
-			expectedError: true,
+			expectedError: false,
+			checkResponse: checkValidStyleResponse,
 		},
 		{
 			name:          "leading/trailing whitespace",
 			styleCode:     " 21A ",
-			expectedError: true,
+			expectedError: false,
+			checkResponse: checkValidStyleResponse,
 		},
 		{
-			name:          "unicode characters",
+			name:          "fullwidth characters",
 			styleCode:     "21Ａ",
+			expectedError: false,
+			checkResponse: checkValidStyleResponse,
+		},
+		{
+			name:          "other unicode characters",
+			styleCode:     "21Ä",
 			expectedError: true,
 		},
 	}
@@ -369,7 +383,7 @@ func TestHandleBJCPResource_StyleDetail(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			h := newTestHandlers()
-			res, err := h.HandleBJCPResource(context.Background(), fmt.Sprintf("bjcp://styles/%s", tt.styleCode))
+			res, err := readResource(h, fmt.Sprintf("bjcp://styles/%s", tt.styleCode))
 
 			if tt.expectedError {
 				if err == nil {
@@ -410,7 +424,7 @@ func TestInvalidResourceURIs(t *testing.T) {
 			name: "malformed BJCP style code",
 			uri:  "bjcp://styles/invalid!code",
 			testFunc: func() error {
-				_, err := h.HandleBJCPResource(ctx, "bjcp://styles/invalid!code")
+				_, err := readResource(h, "bjcp://styles/invalid!code")
 				return err
 			},
 		},
@@ -452,24 +466,25 @@ func TestInvalidResourceURIs(t *testing.T) {
 
 // Helper functions for TestHandleBeerResource_Catalog.
 func setupSuccessfulBeerQuery(mock sqlmock.Sqlmock) {
-	rows := sqlmock.NewRows([]string{"id", "name", "style", "brewery", "country", "abv", "ibu"}).
-		AddRow(1, "Stone IPA", "American IPA", "Stone Brewing", "USA", 6.9, 71).
-		AddRow(2, "Pliny the Elder", "Double IPA", "Russian River", "USA", 8.0, 100).
-		AddRow(3, "Sierra Nevada Pale Ale", "American Pale Ale", "Sierra Nevada", "USA", 5.6, 38)
+	rows := sqlmock.NewRows([]string{"id", "name", "style", "brewery", "city", "country", "abv", "ibu"}).
+		AddRow(1, "Stone IPA", "American IPA", "Stone Brewing", "", "USA", 6.9, 71).
+		AddRow(2, "Pliny the Elder", "Double IPA", "Russian River", "", "USA", 8.0, 100).
+		AddRow(3, "Sierra Nevada Pale Ale", "American Pale Ale", "Sierra Nevada", "", "USA", 5.6, 38)
+	mock.ExpectQuery(`SELECT COUNT\(\*\)\s+FROM beers b`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
 	mock.ExpectQuery("SELECT (.+) FROM beers b").
 		WillReturnRows(rows)
 }
 
 func setupBeerDatabaseError(mock sqlmock.Sqlmock) {
-	mock.ExpectQuery("SELECT b.id, b.name, b.style, br.name as brewery, br.country, b.abv, b.ibu FROM beers b").
-		WithArgs("%IPA%", 10).
+	mock.ExpectQuery(`SELECT COUNT\(\*\)\s+FROM beers b`).
 		WillReturnError(errors.New("database error"))
 }
 
 func setupEmptyBeerResults(mock sqlmock.Sqlmock) {
-	mock.ExpectQuery("SELECT b.id, b.name, b.style, br.name as brewery, br.country, b.abv, b.ibu FROM beers b").
-		WithArgs("%IPA%", 10).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "style", "brewery", "country", "abv", "ibu"}))
+	// Nothing is selected once the count finds no beers
+	mock.ExpectQuery(`SELECT COUNT\(\*\)\s+FROM beers b`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
 }
 
 func checkSuccessfulBeerResult(t *testing.T, res *mcp.ResourceContent) {
@@ -477,14 +492,21 @@ func checkSuccessfulBeerResult(t *testing.T, res *mcp.ResourceContent) {
 	if err := json.Unmarshal([]byte(res.Text), &parsed); err != nil {
 		t.Errorf("invalid JSON in beer catalog: %v", err)
 	}
-	beers, ok := parsed["sample_beers"].([]interface{})
+	beers, ok := parsed["beers"].([]interface{})
 	if !ok {
-		t.Error("expected sample_beers array in response")
+		t.Error("expected beers array in response")
 		return
 	}
+	if parsed["total"] != float64(3) || parsed["offset"] != float64(0) || parsed["next"] != nil {
+		t.Errorf("expected a single page of 3 beers, got total %v, offset %v, next %v",
+			parsed["total"], parsed["offset"], parsed["next"])
+	}
 	if len(beers) != 3 {
 		t.Errorf("expected 3 beers, got %d", len(beers))
 	}
+	if !reflect.DeepEqual(parsed["sample_beers"], parsed["beers"]) || parsed["deprecated"] == nil {
+		t.Errorf("expected the deprecated sample_beers alias of beers, got %v", parsed["sample_beers"])
+	}
 
 	// Check first beer details
 	beer := beers[0].(map[string]interface{})
@@ -501,12 +523,12 @@ func checkEmptyBeerResult(t *testing.T, res *mcp.ResourceContent) {
 	if err := json.Unmarshal([]byte(res.Text), &parsed); err != nil {
 		t.Errorf("invalid JSON in beer catalog: %v", err)
 	}
-	beers, ok := parsed["sample_beers"].([]interface{})
+	beers, ok := parsed["beers"].([]interface{})
 	if !ok {
-		t.Error("expected sample_beers to be an array")
+		t.Error("expected beers to be an array")
 	}
 	if len(beers) != 0 {
-		t.Error("expected empty sample_beers array")
+		t.Error("expected empty beers array")
 	}
 }
 
@@ -617,63 +639,80 @@ func TestHandleBeerResource_Catalog(t *testing.T) {
 	})
 }
 
-// Helper functions for TestHandleBreweryResource_Directory.
-func setupSuccessfulBreweryQuery(mock sqlmock.Sqlmock) {
-	rows := sqlmock.NewRows([]string{
-		"id", "name", "brewery_type", "street", "city", "state",
-		"postal_code", "country", "phone", "website_url",
-	}).
-		AddRow(
-			1, "Stone Brewing", "regional", "1999 Citracado Pkwy",
-			"Escondido", "CA", "92029", "USA", "760-294-7866",
-			"http://www.stonebrewing.com",
-		).
-		AddRow(
-			2, "Russian River", "brewpub", "725 4th Street",
-			"Santa Rosa", "CA", "95404", "USA", "707-545-2337",
-			"http://www.russianriverbrewing.com",
-		)
-	mock.ExpectQuery(`SELECT (.+) FROM breweries WHERE 1=1`).
-		WillReturnRows(rows)
-}
+// Test that the beer catalog searches a BJCP style by its name and aliases given style_code.
+func TestHandleBeerResource_CatalogStyleCode(t *testing.T) {
+	beerService := &servicestest.BeerService{}
+	h := handlers.NewResourceHandlers(&data.BJCPData{
+		Styles: map[string]data.BJCPStyle{"10A": {
+			Code: "10A", Name: "Weissbier", Category: "German Wheat Beer", Aliases: []string{"Hefeweizen"},
+		}},
+		Categories: []string{"German Wheat Beer"},
+		Metadata:   data.Metadata{Version: "2021", Source: "test"},
+	}, beerService, nil)
 
-func setupSpecialCharactersBreweryQuery(mock sqlmock.Sqlmock) {
-	rows := sqlmock.NewRows([]string{
-		"id", "name", "brewery_type", "street", "city", "state",
-		"postal_code", "country", "phone", "website_url",
-	}).AddRow(
-		1, "Bräu & Co.", "brewpub", "123 Main St",
-		"Milwaukee", "WI", "53202", "USA", "414-555-1234",
-		"http://www.brauandco.com",
-	)
-	mock.ExpectQuery(`SELECT (.+) FROM breweries WHERE 1=1`).
-		WillReturnRows(rows)
-}
+	if _, err := readResource(h, "beers://catalog?style_code=10a"); err != nil {
+		t.Fatalf("Failed to read the catalog: %v", err)
+	}
+	if query := beerService.Queries[0]; query.Style != "Weissbier" || fmt.Sprint(query.StyleAliases) != "[Hefeweizen]" {
+		t.Errorf("Expected the style's name and aliases to be searched, got %+v", query)
+	}
 
-func setupMinimalBreweryQuery(mock sqlmock.Sqlmock) {
-	rows := sqlmock.NewRows([]string{
-		"id", "name", "brewery_type", "street", "city", "state",
-		"postal_code", "country", "phone", "website_url",
-	}).AddRow(
-		1, "Minimalist Brewing", "micro", "",
-		"Portland", "OR", "", "USA", "",
-		"",
-	)
-	mock.ExpectQuery(`SELECT (.+) FROM breweries WHERE 1=1`).
-		WillReturnRows(rows)
+	_, err := readResource(h, "beers://catalog?style_code=99Z")
+	var mcpErr *mcp.Error
+	if !errors.As(err, &mcpErr) || mcpErr.Message != "BJCP style not found for: 99Z" {
+		t.Errorf("Expected an unknown style code to be rejected, got %v", err)
+	}
 }
 
-func setupBreweryDatabaseError(mock sqlmock.Sqlmock) {
-	mock.ExpectQuery(`SELECT (.+) FROM breweries WHERE 1=1`).
-		WillReturnError(errors.New("database error"))
+// Test that group_by_brewery nests the catalog's beers under their breweries.
+func TestHandleBeerResource_CatalogGroupedByBrewery(t *testing.T) {
+	h := handlers.NewResourceHandlers(nil, &servicestest.BeerService{Results: []*services.BeerSearchResult{
+		{ID: 1, Name: "Pale Ale", Brewery: "Brewery A", City: "Cape Town", Country: "South Africa"},
+		{ID: 2, Name: "Pale Ale", Brewery: "Brewery B", Country: "United States"},
+		{ID: 3, Name: "Stout", Brewery: "Brewery A", City: "Cape Town", Country: "South Africa"},
+	}}, nil)
+
+	content, err := readResource(h, "beers://catalog?group_by_brewery=true")
+	if err != nil {
+		t.Fatalf("Failed to read the catalog: %v", err)
+	}
+	var catalog struct {
+		Beers       []interface{} `json:"beers"`
+		SampleBeers []interface{} `json:"sample_beers"`
+		Breweries   []struct {
+			Brewery string `json:"brewery"`
+			City    string `json:"city"`
+			Beers   []struct {
+				ID int `json:"id"`
+			} `json:"beers"`
+		} `json:"breweries"`
+	}
+	if err = json.Unmarshal([]byte(content.Text), &catalog); err != nil {
+		t.Fatalf("Failed to parse the catalog: %v", err)
+	}
+	if catalog.Beers != nil || catalog.SampleBeers != nil || len(catalog.Breweries) != 2 {
+		t.Fatalf("Expected the beers grouped under 2 breweries, got %s", content.Text)
+	}
+	first := catalog.Breweries[0]
+	if first.Brewery != "Brewery A" || first.City != "Cape Town" || len(first.Beers) != 2 || first.Beers[1].ID != 3 {
+		t.Errorf("Expected Brewery A's 2 beers first, got %+v", first)
+	}
 }
 
-func setupEmptyBreweryResults(mock sqlmock.Sqlmock) {
-	mock.ExpectQuery(`SELECT (.+) FROM breweries WHERE 1=1`).
-		WillReturnRows(sqlmock.NewRows([]string{
-			"id", "name", "brewery_type", "street", "city", "state",
-			"postal_code", "country", "phone", "website_url",
-		}))
+// Helper functions for TestHandleBreweryResource_Directory.
+func directoryBreweries() []*services.BrewerySearchResult {
+	return []*services.BrewerySearchResult{
+		{
+			ID: 1, Name: "Stone Brewing", BreweryType: "regional", Street: "1999 Citracado Pkwy", City: "Escondido",
+			State: "CA", PostalCode: "92029", Country: "USA", Phone: "760-294-7866",
+			Website: "http://www.stonebrewing.com",
+		},
+		{
+			ID: 2, Name: "Russian River", BreweryType: "brewpub", Street: "725 4th Street", City: "Santa Rosa",
+			State: "CA", PostalCode: "95404", Country: "USA", Phone: "707-545-2337",
+			Website: "http://www.russianriverbrewing.com",
+		},
+	}
 }
 
 func checkSuccessfulBreweryResult(t *testing.T, res *mcp.ResourceContent) {
@@ -681,14 +720,17 @@ func checkSuccessfulBreweryResult(t *testing.T, res *mcp.ResourceContent) {
 	if err := json.Unmarshal([]byte(res.Text), &parsed); err != nil {
 		t.Errorf("invalid JSON in brewery directory: %v", err)
 	}
-	breweries, ok := parsed["sample_breweries"].([]interface{})
+	breweries, ok := parsed["breweries"].([]interface{})
 	if !ok {
-		t.Error("expected sample_breweries to be an array")
+		t.Error("expected breweries to be an array")
 		return
 	}
 	if len(breweries) != 2 {
 		t.Errorf("expected 2 breweries, got %d", len(breweries))
 	}
+	if parsed["total"] != 2.0 || parsed["offset"] != 0.0 {
+		t.Errorf("expected total 2 at offset 0, got %v at %v", parsed["total"], parsed["offset"])
+	}
 
 	// Check first brewery details
 	brewery := breweries[0].(map[string]interface{})
@@ -714,7 +756,7 @@ func checkSpecialCharactersBreweryResult(t *testing.T, res *mcp.ResourceContent)
 	if err := json.Unmarshal([]byte(res.Text), &parsed); err != nil {
 		t.Errorf("invalid JSON in brewery directory: %v", err)
 	}
-	breweries := parsed["sample_breweries"].([]interface{})
+	breweries := parsed["breweries"].([]interface{})
 	brewery := breweries[0].(map[string]interface{})
 	if brewery["name"] != "Bräu & Co." {
 		t.Errorf("expected Bräu & Co., got %v", brewery["name"])
@@ -726,20 +768,21 @@ func checkMinimalBreweryResult(t *testing.T, res *mcp.ResourceContent) {
 	if err := json.Unmarshal([]byte(res.Text), &parsed); err != nil {
 		t.Errorf("invalid JSON in brewery directory: %v", err)
 	}
-	breweries := parsed["sample_breweries"].([]interface{})
+	breweries := parsed["breweries"].([]interface{})
 	brewery := breweries[0].(map[string]interface{})
 	if brewery["name"] != "Minimalist Brewing" {
 		t.Errorf("expected Minimalist Brewing, got %v", brewery["name"])
 	}
 	// Check that optional fields are empty but present
-	if brewery["street"] != "" {
-		t.Error("expected empty street")
-	}
-	if brewery["phone"] != "" {
-		t.Error("expected empty phone")
+	for _, field := range []string{
+		"street", "phone", "website_url", "description", "instagram", "facebook", "twitter", "email",
+	} {
+		if brewery[field] != "" {
+			t.Errorf("expected empty %s, got %v", field, brewery[field])
+		}
 	}
-	if brewery["website_url"] != "" {
-		t.Error("expected empty website_url")
+	if brewery["founded_year"] != 0.0 {
+		t.Errorf("expected founded_year 0, got %v", brewery["founded_year"])
 	}
 }
 
@@ -748,68 +791,98 @@ func checkEmptyBreweryResult(t *testing.T, res *mcp.ResourceContent) {
 	if err := json.Unmarshal([]byte(res.Text), &parsed); err != nil {
 		t.Errorf("invalid JSON in brewery directory: %v", err)
 	}
-	breweries, ok := parsed["sample_breweries"].([]interface{})
+	breweries, ok := parsed["breweries"].([]interface{})
 	if !ok {
-		t.Error("expected sample_breweries to be an array")
+		t.Error("expected breweries to be an array")
 	}
 	if len(breweries) != 0 {
-		t.Error("expected empty sample_breweries array")
+		t.Error("expected empty breweries array")
+	}
+}
+
+// checkFilteredBreweryQuery checks the URI's filters and paging reach the service as the query of
+// find_breweries would.
+func checkFilteredBreweryQuery(t *testing.T, query services.BrewerySearchQuery) {
+	if !reflect.DeepEqual(query.Country, []string{"South Africa"}) ||
+		!reflect.DeepEqual(query.City, []string{"Cape Town"}) {
+		t.Errorf("expected the country and city filters, got %v and %v", query.Country, query.City)
+	}
+	if !reflect.DeepEqual(query.Types, []string{"micro", "brewpub"}) {
+		t.Errorf("expected the type filter, got %v", query.Types)
+	}
+	if query.Limit != 5 || query.Offset != 5 {
+		t.Errorf("expected limit 5 at offset 5, got %d at %d", query.Limit, query.Offset)
 	}
 }
 
-func getBreweryDirectoryTestCases() []struct {
+type breweryDirectoryTestCase struct {
 	name          string
-	setupMock     func(mock sqlmock.Sqlmock)
+	service       *servicestest.BreweryService
 	uri           string
 	expectedError bool
+	expectedCode  int // the mcp.Error code of the expected error, if any
 	checkResult   func(t *testing.T, res *mcp.ResourceContent)
-} {
-	return []struct {
-		name          string
-		setupMock     func(mock sqlmock.Sqlmock)
-		uri           string
-		expectedError bool
-		checkResult   func(t *testing.T, res *mcp.ResourceContent)
-	}{
+	checkQuery    func(t *testing.T, query services.BrewerySearchQuery)
+}
+
+func getBreweryDirectoryTestCases() []breweryDirectoryTestCase {
+	return []breweryDirectoryTestCase{
 		{
-			name:          "successful query with multiple results",
-			setupMock:     setupSuccessfulBreweryQuery,
-			uri:           "breweries://directory",
-			expectedError: false,
-			checkResult:   checkSuccessfulBreweryResult,
+			name:        "successful query with multiple results",
+			service:     &servicestest.BreweryService{Results: directoryBreweries()},
+			uri:         "breweries://directory",
+			checkResult: checkSuccessfulBreweryResult,
 		},
 		{
-			name:          "special characters in brewery name",
-			setupMock:     setupSpecialCharactersBreweryQuery,
-			uri:           "breweries://directory",
-			expectedError: false,
-			checkResult:   checkSpecialCharactersBreweryResult,
+			name: "special characters in brewery name",
+			service: &servicestest.BreweryService{Results: []*services.BrewerySearchResult{{
+				ID: 1, Name: "Bräu & Co.", BreweryType: "brewpub", City: "Milwaukee", State: "WI", Country: "USA",
+			}}},
+			uri:         "breweries://directory",
+			checkResult: checkSpecialCharactersBreweryResult,
 		},
 		{
-			name:          "missing optional fields",
-			setupMock:     setupMinimalBreweryQuery,
-			uri:           "breweries://directory",
-			expectedError: false,
-			checkResult:   checkMinimalBreweryResult,
+			name: "missing optional fields",
+			service: &servicestest.BreweryService{Results: []*services.BrewerySearchResult{{
+				ID: 1, Name: "Minimalist Brewing", BreweryType: "micro", City: "Portland", State: "OR", Country: "USA",
+			}}},
+			uri:         "breweries://directory",
+			checkResult: checkMinimalBreweryResult,
 		},
 		{
-			name:          "database error",
-			setupMock:     setupBreweryDatabaseError,
+			name:          "service error",
+			service:       &servicestest.BreweryService{Err: errors.New("database error")},
 			uri:           "breweries://directory",
 			expectedError: true,
 		},
 		{
-			name:          "empty result set",
-			setupMock:     setupEmptyBreweryResults,
-			uri:           "breweries://directory",
-			expectedError: false,
-			checkResult:   checkEmptyBreweryResult,
+			name:        "empty result set",
+			service:     &servicestest.BreweryService{},
+			uri:         "breweries://directory",
+			checkResult: checkEmptyBreweryResult,
+		},
+		{
+			name:    "country, city, type, and paging in the URI query",
+			service: &servicestest.BreweryService{Results: directoryBreweries()},
+			uri: "breweries://directory?country=South+Africa&city=Cape+Town&type=micro,brewpub" +
+				"&limit=5&offset=5",
+			checkQuery: checkFilteredBreweryQuery,
+		},
+		{
+			name: "unknown brewery type",
+			service: &servicestest.BreweryService{
+				Err: fmt.Errorf("%w: unknown brewery type", services.ErrInvalidSearchQuery),
+			},
+			uri:           "breweries://directory?type=winery",
+			expectedError: true,
+			expectedCode:  mcp.InvalidParams,
 		},
 		{
 			name:          "invalid resource URI",
+			service:       &servicestest.BreweryService{},
 			uri:           "breweries://unknown",
 			expectedError: true,
-			setupMock:     func(_ sqlmock.Sqlmock) {},
+			expectedCode:  mcp.MethodNotFound,
 		},
 	}
 }
@@ -820,35 +893,24 @@ func createBreweryTestHandlers(sqlxdb *sqlx.DB) *handlers.ResourceHandlers {
 		Categories: []string{"IPA", "Lager"},
 		Metadata:   data.Metadata{Version: "2021"},
 	}
-	redisClient := &redis.Client{}
+	var redisClient *redis.Client // No caching
 	beerService := services.NewBeerService(sqlxdb, redisClient)
 	breweryService := services.NewBreweryService(sqlxdb, redisClient)
 	return handlers.NewResourceHandlers(bjcpData, beerService, breweryService)
 }
 
-func runBreweryDirectoryTestCase(t *testing.T, tt struct {
-	name          string
-	setupMock     func(mock sqlmock.Sqlmock)
-	uri           string
-	expectedError bool
-	checkResult   func(t *testing.T, res *mcp.ResourceContent)
-},
-) {
-	db, mock, err := sqlmock.New()
-	if err != nil {
-		t.Fatalf("failed to create sqlmock: %v", err)
-	}
-	defer db.Close()
-
-	sqlxdb := sqlx.NewDb(db, "sqlmock")
-	tt.setupMock(mock)
-
-	h := createBreweryTestHandlers(sqlxdb)
+func runBreweryDirectoryTestCase(t *testing.T, tt breweryDirectoryTestCase) {
+	bjcpData := &data.BJCPData{Metadata: data.Metadata{Version: "2021"}}
+	h := handlers.NewResourceHandlers(bjcpData, &servicestest.BeerService{}, tt.service)
 	res, err := h.HandleBreweryResource(context.Background(), tt.uri)
 
 	if tt.expectedError {
-		if err == nil {
+		var mcpErr *mcp.Error
+		switch {
+		case err == nil:
 			t.Error("expected error but got none")
+		case tt.expectedCode != 0 && (!errors.As(err, &mcpErr) || mcpErr.Code != tt.expectedCode):
+			t.Errorf("expected an error with code %d, got %v", tt.expectedCode, err)
 		}
 		return
 	}
@@ -862,9 +924,11 @@ func runBreweryDirectoryTestCase(t *testing.T, tt struct {
 	if tt.checkResult != nil {
 		tt.checkResult(t, res)
 	}
-
-	if mockErr := mock.ExpectationsWereMet(); mockErr != nil {
-		t.Errorf("unmet sqlmock expectations: %v", mockErr)
+	if tt.checkQuery != nil {
+		if len(tt.service.Queries) != 1 {
+			t.Fatalf("expected one search of the service, got %d", len(tt.service.Queries))
+		}
+		tt.checkQuery(t, tt.service.Queries[0])
 	}
 }
 
@@ -887,20 +951,286 @@ func TestHandleBreweryResource_Directory(t *testing.T) {
 	}
 }
 
-func TestResourceHandlersRegistry(t *testing.T) {
-	db, _, err := sqlmock.New()
+func TestHandleBreweryResource_Countries(t *testing.T) {
+	breweryService := &servicestest.BreweryService{Results: []*services.BrewerySearchResult{
+		{ID: 1, Name: "Devil's Peak", State: "Western Cape", Country: "South Africa"},
+		{ID: 2, Name: "Mad Giant", State: "Gauteng", Country: "south africa"},
+		{ID: 3, Name: "Drifter", State: "western cape", Country: "South Africa"},
+		{ID: 4, Name: "Windhoek", Country: "Namibia"},
+		{ID: 5, Name: "Nowhere Brewing"},
+	}}
+	h := handlers.NewResourceHandlers(nil, &servicestest.BeerService{}, breweryService)
+
+	res, err := readResource(h, "breweries://countries")
 	if err != nil {
-		t.Fatalf("failed to create sqlmock: %v", err)
+		t.Fatalf("Failed to read the countries: %v", err)
+	}
+	want := `{"countries":[` +
+		`{"country":"Namibia","breweries":1,"regions":"breweries://countries/Namibia/regions"},` +
+		`{"country":"South Africa","breweries":3,"regions":"breweries://countries/South%20Africa/regions"}],` +
+		`"description":"Brewery Countries","total":2}`
+	if res.Text != want {
+		t.Errorf("Expected %s, got %s", want, res.Text)
 	}
-	defer db.Close()
 
-	h := newTestHandlersWithDB(sqlx.NewDb(db, "sqlmock"))
+	for _, uri := range []string{
+		"breweries://countries/South%20Africa/regions",
+		"breweries://countries/SOUTH+africa/regions",
+	} {
+		res, err = readResource(h, uri)
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", uri, err)
+		}
+		if res.URI != "breweries://countries/South%20Africa/regions" {
+			t.Errorf("Expected the canonical URI for %s, got %s", uri, res.URI)
+		}
+		want = `{"country":"South Africa","breweries":3,"regions":[` +
+			`{"region":"Gauteng","breweries":1},{"region":"Western Cape","breweries":2}],"unspecified":0}`
+		if res.Text != want {
+			t.Errorf("Expected %s for %s, got %s", want, uri, res.Text)
+		}
+	}
+
+	res, err = readResource(h, "breweries://countries/Namibia/regions")
+	if err != nil || !strings.Contains(res.Text, `"regions":[],"unspecified":1`) {
+		t.Errorf("Expected Namibia's brewery without a region, got %v (%v)", res, err)
+	}
+
+	for uri, code := range map[string]int{
+		"breweries://countries/Atlantis/regions": mcp.MethodNotFound,
+		"breweries://countries/%20/regions":      mcp.MethodNotFound,
+		"breweries://countries/%zz/regions":      mcp.InvalidParams,
+	} {
+		_, err = readResource(h, uri)
+		var mcpErr *mcp.Error
+		if !errors.As(err, &mcpErr) || mcpErr.Code != code {
+			t.Errorf("Expected error code %d for %s, got %v", code, uri, err)
+		}
+	}
+}
+
+func TestListingResources_Paging(t *testing.T) {
+	beerService := &servicestest.BeerService{}
+	for i := range 5 {
+		beerService.Results = append(beerService.Results, &services.BeerSearchResult{ID: i + 1})
+	}
+	breweryService := &servicestest.BreweryService{}
+	for i := range 25 {
+		breweryService.Results = append(breweryService.Results, &services.BrewerySearchResult{ID: i + 1})
+	}
+	h := handlers.NewResourceHandlers(nil, beerService, breweryService)
+
+	tests := []struct {
+		uri            string
+		key            string
+		expectedItems  int
+		expectedOffset int
+		expectedNext   string
+	}{
+		{"beers://catalog?style=IPA&offset=1&limit=2", "beers", 2, 1, "beers://catalog?limit=2&offset=3&style=IPA"},
+		{"beers://catalog?style=IPA&page=3&limit=2", "beers", 1, 4, ""},
+		{"breweries://directory", "breweries", 20, 0, "breweries://directory?limit=20&offset=20"},
+		{"breweries://directory?country=South+Africa&page=2", "breweries", 5, 20, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.uri, func(t *testing.T) {
+			res, err := readResource(h, tt.uri)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var result struct {
+				Beers     []json.RawMessage `json:"beers"`
+				Breweries []json.RawMessage `json:"breweries"`
+				Offset    int               `json:"offset"`
+				Next      string            `json:"next"`
+			}
+			if err := json.Unmarshal([]byte(res.Text), &result); err != nil {
+				t.Fatalf("failed to unmarshal result: %v", err)
+			}
+			items := result.Beers
+			if tt.key == "breweries" {
+				items = result.Breweries
+			}
+			if len(items) != tt.expectedItems || result.Offset != tt.expectedOffset {
+				t.Errorf("expected %d %s from offset %d, got %d from %d",
+					tt.expectedItems, tt.key, tt.expectedOffset, len(items), result.Offset)
+			}
+			if result.Next != tt.expectedNext {
+				t.Errorf("expected next %q, got %q", tt.expectedNext, result.Next)
+			}
+		})
+	}
+
+	if query := beerService.Queries[0]; query.Style != "IPA" || query.Offset != 1 || query.Limit != 2 {
+		t.Errorf("expected the catalog filters to reach the search, got %+v", query)
+	}
+	if query := breweryService.Queries[1]; fmt.Sprint(query.Country) != "[South Africa]" || query.Offset != 20 {
+		t.Errorf("expected the directory filters to reach the search, got %+v", query)
+	}
+
+	for _, uri := range []string{
+		"beers://catalog?colour=dark",
+		"beers://catalog?limit=abc",
+		"beers://catalog?offset=-1",
+		"beers://catalog?%zz",
+		"breweries://directory?latitude=north",
+		"breweries://directory?offset=1&page=2",
+	} {
+		_, err := readResource(h, uri)
+		expectMCPError(t, err, mcp.InvalidParams, "")
+	}
+}
+
+func TestListingResources_Formats(t *testing.T) {
+	breweryService := &servicestest.BreweryService{Results: []*services.BrewerySearchResult{{
+		ID: 1, Name: `Bräu & Co., "Zum Löwen" | Taproom`, BreweryType: "brewpub", Street: "123 Main St",
+		City: "Milwaukee", State: "WI", PostalCode: "53202", Country: "USA", Phone: "414-555-1234",
+		Website: "http://www.brauandco.com",
+	}}}
+	beerService := &servicestest.BeerService{Results: []*services.BeerSearchResult{
+		{
+			ID: 7, Name: "Hop, Skip\nand Jump", Style: "American IPA", Brewery: "Bräu & Co.", Country: "USA",
+			ABV: 6.5, IBU: 60,
+		},
+	}}
+	bjcpData := &data.BJCPData{
+		Styles: map[string]data.BJCPStyle{
+			"21A": {Code: "21A", Name: "American IPA", Category: "IPA", Vitals: data.Vitals{
+				ABVMin: 5.5, ABVMax: 7.5, IBUMin: 40, IBUMax: 70, SRMMin: 6, SRMMax: 14,
+				OGMin: 1.056, OGMax: 1.070, FGMin: 1.008, FGMax: 1.014,
+			}},
+			"3A": {Code: "3A", Name: "Czech Pale Lager", Category: "Czech Lager"},
+		},
+		Categories: []string{"Czech Lager", "IPA"},
+		Metadata:   data.Metadata{Version: "2021"},
+	}
+	h := handlers.NewResourceHandlers(bjcpData, beerService, breweryService)
+
+	tests := []struct {
+		uri              string
+		expectedMimeType string
+		expectedText     []string
+	}{
+		{
+			"breweries://directory?format=csv", "text/csv",
+			[]string{
+				"id,name,brewery_type,street,city,state,postal_code,country,phone,website_url," +
+					"founded_year,email,instagram,facebook,twitter\n" +
+					`1,"Bräu & Co., ""Zum Löwen"" | Taproom",brewpub,123 Main St,Milwaukee,WI,53202,USA,` +
+					"414-555-1234,http://www.brauandco.com,,,,,\n",
+			},
+		},
+		{
+			"breweries://directory?format=markdown", "text/markdown",
+			[]string{
+				"# Brewery Directory\n\nShowing 1-1 of 1 breweries.\n\n",
+				"| id | name | brewery_type |",
+				"| --- | --- | --- |",
+				`| 1 | Bräu & Co., "Zum Löwen" \| Taproom | brewpub |`,
+			},
+		},
+		{
+			"beers://catalog?format=csv&style=IPA", "text/csv",
+			[]string{
+				"id,name,style,brewery,country,abv,ibu\n" +
+					"7,\"Hop, Skip\nand Jump\",American IPA,Bräu & Co.,USA,6.5,60\n",
+			},
+		},
+		{
+			"beers://catalog?format=markdown&limit=1", "text/markdown",
+			[]string{"| 7 | Hop, Skip and Jump | American IPA | Bräu & Co. | USA | 6.5 | 60 |"},
+		},
+		{
+			"bjcp://styles?format=csv", "text/csv",
+			[]string{
+				"code,name,category,abv_min,abv_max,ibu_min,ibu_max,srm_min,srm_max,og_min,og_max,fg_min,fg_max\n" +
+					"3A,Czech Pale Lager,Czech Lager,0,0,0,0,0,0,0,0,0,0\n" +
+					"21A,American IPA,IPA,5.5,7.5,40,70,6,14,1.056,1.07,1.008,1.014\n",
+			},
+		},
+		{
+			"bjcp://styles?ibu_min=40&format=markdown", "text/markdown",
+			[]string{"# BJCP Styles by Vitals", "| fg_max | centrality |", "| 21A | American IPA | IPA |"},
+		},
+		{"bjcp://styles?format=json", "application/json", []string{`"total_styles":2`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.uri, func(t *testing.T) {
+			res, err := readResource(h, tt.uri)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if res.MimeType != tt.expectedMimeType {
+				t.Errorf("expected MIME type %s, got %s", tt.expectedMimeType, res.MimeType)
+			}
+			for _, expected := range tt.expectedText {
+				if !strings.Contains(res.Text, expected) {
+					t.Errorf("expected %q in:\n%s", expected, res.Text)
+				}
+			}
+		})
+	}
+
+	for _, uri := range []string{
+		"beers://catalog?format=xml", "breweries://directory?format=tsv", "bjcp://styles?format=pdf",
+	} {
+		_, err := readResource(h, uri)
+		expectMCPError(t, err, mcp.InvalidParams, "format")
+	}
+}
+
+func TestResourceETags(t *testing.T) {
+	h := newTestHandlers()
+	bjcpService := data.NewBJCPServiceFromData(&data.BJCPData{
+		Styles:   map[string]data.BJCPStyle{"21A": {Code: "21A", Name: "American IPA", Category: "IPA"}},
+		Metadata: data.Metadata{Version: "2021"},
+	})
+	h.SetBJCPService(bjcpService)
+	fingerprint := bjcpService.Fingerprint()
+
+	// The style guide resources are versioned by the data; the others are tagged by content once read
+	for uri, versioned := range map[string]bool{
+		"bjcp://styles":                true,
+		"bjcp://styles/21A":            true,
+		"bjcp://styles?format=csv":     true,
+		"bjcp://styles/21A/examples":   false,
+		"beers://catalog":              false,
+		"breweries://directory?page=2": false,
+	} {
+		expected := ""
+		if versioned {
+			expected = fingerprint
+		}
+		if got := h.ResourceETag(uri); got != expected {
+			t.Errorf("%s: expected ETag %q, got %q", uri, expected, got)
+		}
+	}
+
+	res, err := readResource(h, "bjcp://styles/21A")
+	if err != nil || res.ETag != fingerprint {
+		t.Errorf("expected the style to carry the data's fingerprint, got %+v (error: %v)", res, err)
+	}
+	for _, resource := range h.GetResourceDefinitions() {
+		if strings.HasPrefix(resource.URI, "bjcp://") != (resource.ETag == fingerprint) {
+			t.Errorf("%s: unexpected ETag %q in the definition", resource.URI, resource.ETag)
+		}
+	}
+
+	resp := mcp.NewServer(nil, h, nil).ProcessMessage(context.Background(), []byte(
+		`{"jsonrpc":"2.0","id":1,"method":"resources/read","params":{"uri":"bjcp://styles","ifNoneMatch":"`+
+			fingerprint+`"}}`))
+	if result, ok := resp.Result.(map[string]interface{}); !ok || result["notModified"] != true {
+		t.Errorf("expected bjcp://styles to be not modified, got %+v", resp)
+	}
+}
+
+func TestResourceHandlersRegistry(t *testing.T) {
+	h := newTestHandlers()
 
 	// Test GetResourceDefinitions
 	defs := h.GetResourceDefinitions()
 	requiredURIs := []string{
 		"bjcp://styles",
-		"bjcp://styles/{code}",
 		"bjcp://categories",
 		"beers://catalog",
 		"breweries://directory",
@@ -927,12 +1257,7 @@ func TestResourceHandlersRegistry(t *testing.T) {
 }
 
 func TestGetResourceDefinitions(t *testing.T) {
-	db, _, err := sqlmock.New()
-	if err != nil {
-		t.Fatalf("failed to create sqlmock: %v", err)
-	}
-	sqlxdb := sqlx.NewDb(db, "sqlmock")
-	h := newTestHandlersWithDB(sqlxdb)
+	h := newTestHandlers()
 	defs := h.GetResourceDefinitions()
 	if len(defs) == 0 {
 		t.Fatal("expected resource definitions, got none")
@@ -945,28 +1270,109 @@ func TestGetResourceDefinitions(t *testing.T) {
 		}
 	}
 	// Check for required URIs
-	for _, want := range []string{"bjcp://styles", "bjcp://styles/{code}", "bjcp://categories", "beers://catalog", "breweries://directory"} {
+	for _, want := range []string{
+		"bjcp://styles", "bjcp://categories", "beers://catalog", "beers://export", "beers://style-violations",
+		"breweries://directory", "breweries://countries", "meta://data-sources",
+	} {
 		if !uris[want] {
 			t.Errorf("expected resource definition for %s", want)
 		}
 	}
+	for uri := range uris {
+		if strings.Contains(uri, "{") {
+			t.Errorf("expected templated URI %s among the resource templates, not the resources", uri)
+		}
+	}
 }
 
-func TestHandleBJCPResource_EmptyCategories(t *testing.T) {
-	db, _, err := sqlmock.New()
-	if err != nil {
-		t.Fatalf("failed to create sqlmock: %v", err)
+func TestGetResourceTemplates(t *testing.T) {
+	h := newTestHandlers()
+	templates := map[string]bool{}
+	for _, template := range h.GetResourceTemplates() {
+		templates[template.URITemplate] = true
+		if template.Name == "" || template.MimeType == "" {
+			t.Errorf("template %s missing name or MIME type", template.URITemplate)
+		}
 	}
-	sqlxdb := sqlx.NewDb(db, "sqlmock")
+	for _, want := range []string{
+		"bjcp://styles/{code}", "bjcp://styles/{code}/examples", "bjcp://{version}/styles/{code}",
+		"bjcp://categories/{name}", "beers://{id}", "breweries://{id}", "breweries://{id}?timezone={timezone}",
+		"breweries://{id}/beers", "breweries://countries/{country}/regions",
+	} {
+		if !templates[want] {
+			t.Errorf("expected resource template %s", want)
+		}
+	}
+
+	// The server lists the templates the handlers register
+	server := mcp.NewServer(nil, h, nil)
+	resp := server.ProcessMessage(context.Background(),
+		[]byte(`{"jsonrpc":"2.0","id":1,"method":"resources/templates/list"}`))
+	listed, _ := json.Marshal(resp.Result)
+	for uriTemplate := range templates {
+		if !strings.Contains(string(listed), `"uriTemplate":"`+uriTemplate+`"`) {
+			t.Errorf("expected resources/templates/list to list %s, got %s", uriTemplate, listed)
+		}
+	}
+}
+
+// Test that concrete URIs are not read as the ID of a detail template.
+func TestReadResource_ConcreteBeforeTemplates(t *testing.T) {
+	for _, uri := range []string{
+		"beers://catalog", "breweries://directory", "breweries://countries", "bjcp://categories",
+		"meta://data-sources",
+	} {
+		res, err := readResource(newTestHandlers(), uri)
+		if err != nil || res.URI != uri {
+			t.Errorf("expected %s to be served, got %+v (error: %v)", uri, res, err)
+		}
+	}
+}
+
+func TestStyleCodeCompletion(t *testing.T) {
+	h := handlers.NewResourceHandlers(nil, nil, nil)
+	h.SetBJCPService(newGuidelineService())
+	server := mcp.NewServer(nil, h, nil)
+	complete := func(uriTemplate, argument, value string) mcp.Completion {
+		t.Helper()
+		request, _ := json.Marshal(mcp.NewMessage("completion/complete", mcp.CompleteRequest{
+			Ref:      mcp.CompletionReference{Type: "ref/resource", URI: uriTemplate},
+			Argument: mcp.CompletionArgument{Name: argument, Value: value},
+		}))
+		resp := server.ProcessMessage(context.Background(), request)
+		if resp.Error != nil {
+			t.Fatalf("unexpected error: %v", resp.Error)
+		}
+		return resp.Result.(mcp.CompleteResult).Completion
+	}
+
+	if got := complete("bjcp://styles/{code}", "code", "").Values; !slices.Equal(got, []string{"21A"}) {
+		t.Errorf("expected the default version's 21A, got %v", got)
+	}
+	if got := complete("bjcp://styles/{code}/examples", "code", " ２"); !slices.Equal(got.Values, []string{"21A"}) ||
+		got.Total != 1 || got.HasMore {
+		t.Errorf("expected a normalized prefix to complete to 21A, got %+v", got)
+	}
+	if got := complete("bjcp://{version}/styles/{code}", "code", "").Values; !slices.Equal(got,
+		[]string{"14B", "21A"}) {
+		t.Errorf("expected the codes of every version, got %v", got)
+	}
+	if got := complete("bjcp://{version}/styles/{code}", "version", "20").Values; !slices.Equal(got,
+		[]string{"2021", "2015"}) {
+		t.Errorf("expected the loaded versions, got %v", got)
+	}
+	if got := complete("beers://{id}", "id", "1"); len(got.Values) != 0 || got.Total != 0 {
+		t.Errorf("expected no suggestions for a beer ID, got %+v", got)
+	}
+}
+
+func TestHandleBJCPResource_EmptyCategories(t *testing.T) {
 	bjcpData := &data.BJCPData{
 		Styles:     map[string]data.BJCPStyle{},
 		Categories: []string{},
 		Metadata:   data.Metadata{Version: "2021"},
 	}
-	redisClient := &redis.Client{} // Use a mock or test Redis client
-	beerService := services.NewBeerService(sqlxdb, redisClient)
-	breweryService := &services.BreweryService{} // Use real type for compatibility
-	h := handlers.NewResourceHandlers(bjcpData, beerService, breweryService)
+	h := handlers.NewResourceHandlers(bjcpData, &servicestest.BeerService{}, &servicestest.BreweryService{})
 	res, err := h.HandleBJCPResource(context.Background(), "bjcp://styles")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -982,3 +1388,257 @@ func TestHandleBJCPResource_EmptyCategories(t *testing.T) {
 		t.Errorf("expected total_styles 0, got %v", parsed["total_styles"])
 	}
 }
+
+func TestHandleStatsResource(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer db.Close()
+	h := createBreweryTestHandlers(sqlx.NewDb(db, "sqlmock"))
+	ctx := context.Background()
+
+	mock.ExpectQuery(`FROM breweries br\s+WHERE 1=1 AND br\.deleted_at IS NULL\s+GROUP BY 1`).
+		WillReturnRows(sqlmock.NewRows([]string{"country", "breweries"}).AddRow("South Africa", 2))
+	mock.ExpectQuery(`FROM beers b\s+JOIN breweries br ON b\.brewery_id = br\.id\s+WHERE 1=1` +
+		` AND br\.deleted_at IS NULL AND b\.deleted_at IS NULL\s+GROUP BY 1`).
+		WillReturnRows(sqlmock.NewRows([]string{"style", "beers", "avg_abv", "avg_ibu"}).
+			AddRow("Pale Lager", 3, 5.0, 18.0))
+	res, err := h.HandleStatsResource(ctx, "stats://overview")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{
+		`"total_breweries":2`, `"total_beers":3`,
+		`"beers_by_style":[{"style":"Pale Lager","beers":3,"avg_abv":5,"avg_ibu":18}]`,
+	} {
+		if !strings.Contains(res.Text, want) {
+			t.Errorf("expected stats resource to contain %s, got %s", want, res.Text)
+		}
+	}
+
+	_, err = h.HandleStatsResource(ctx, "stats://unknown")
+	var mcpErr *mcp.Error
+	if !errors.As(err, &mcpErr) || mcpErr.Code != mcp.MethodNotFound {
+		t.Errorf("expected a not-found error for an unknown stats resource, got %v", err)
+	}
+	if mockErr := mock.ExpectationsWereMet(); mockErr != nil {
+		t.Errorf("unmet sqlmock expectations: %v", mockErr)
+	}
+}
+
+func TestHandleDetailResources(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer db.Close()
+	h := createBreweryTestHandlers(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectQuery(`SELECT b\.id, b\.name, .* WHERE b\.id = \$1`).
+		WithArgs(5).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "brewery_id", "brewery"}).
+			AddRow(5, "Castle Lager", 1, "SAB - Newlands Brewery"))
+	ingredientColumns := []string{"name", "amount", "unit", "timing"}
+	mock.ExpectQuery(`FROM beer_hops\s+WHERE beer_id = \$1`).WithArgs(5).
+		WillReturnRows(sqlmock.NewRows(ingredientColumns).AddRow("Saaz", 30.0, "g", "60 min"))
+	mock.ExpectQuery(`FROM beer_fermentables\s+WHERE beer_id = \$1`).WithArgs(5).
+		WillReturnRows(sqlmock.NewRows(ingredientColumns))
+	mock.ExpectQuery(`FROM beer_yeast\s+WHERE beer_id = \$1`).WithArgs(5).
+		WillReturnRows(sqlmock.NewRows(ingredientColumns))
+	res, err := readResource(h, "beers://5")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.URI != "beers://5" || !strings.Contains(res.Text, `"name":"Castle Lager"`) {
+		t.Errorf("unexpected beer resource: %+v", res)
+	}
+	if !strings.Contains(res.Text, `"ingredients":{"hops":[{"name":"Saaz","amount":30,"unit":"g","timing":"60 min"}],`+
+		`"fermentables":[],"yeast":[]}`) {
+		t.Errorf("expected the beer's ingredients in the beer resource, got %s", res.Text)
+	}
+
+	mock.ExpectQuery(`SELECT br\.id, br\.name, .* WHERE br\.id = \$1`).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "beer_count"}).AddRow(1, "SAB - Newlands Brewery", 3))
+	mock.ExpectQuery(`FROM venues v\s+JOIN breweries br ON br\.id = v\.brewery_id\s+WHERE v\.brewery_id = \$1`).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "brewery_id", "name", "hours", "is_primary"}).
+			AddRow(2, 1, "Newlands Taproom", `{"mon": "00:00-24:00"}`, true))
+	res, err = readResource(h, "breweries://1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(res.Text, `"beer_count":3`) {
+		t.Errorf("expected beer_count in brewery resource, got %s", res.Text)
+	}
+	if !strings.Contains(res.Text, `"name":"Newlands Taproom"`) ||
+		!strings.Contains(res.Text, `"hours":{"mon":"00:00-24:00"}`) ||
+		!strings.Contains(res.Text, `"timezone":"UTC"`) {
+		t.Errorf("expected the brewery's venues in the brewery resource, got %s", res.Text)
+	}
+
+	mock.ExpectQuery(`FROM breweries br\s+LEFT JOIN beers b ON b\.brewery_id = br\.id`+
+		` AND b\.deleted_at IS NULL\s+WHERE br\.id = \$1`).
+		WithArgs(1, 50).
+		WillReturnRows(sqlmock.NewRows([]string{"name", "id", "name", "style", "abv", "ibu", "total_count"}).
+			AddRow("SAB - Newlands Brewery", 5, "Castle Lager", "Pale Lager", 5.0, 18, 1))
+	res, err = readResource(h, "breweries://1/beers")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.URI != "breweries://1/beers" || !strings.Contains(res.Text, `"beers":[{"id":5,"name":"Castle Lager"`) {
+		t.Errorf("unexpected brewery beers resource: %+v", res)
+	}
+
+	mock.ExpectQuery(`WHERE b\.id = \$1`).WithArgs(404).WillReturnError(sql.ErrNoRows)
+	_, err = readResource(h, "beers://404")
+	var mcpErr *mcp.Error
+	if !errors.As(err, &mcpErr) || mcpErr.Code != mcp.MethodNotFound || mcpErr.Message != "Beer not found: 404" {
+		t.Errorf("expected a not-found error for a missing beer, got %v", err)
+	}
+
+	for _, uri := range []string{"beers://0", "beers://-3", "beers://abc"} {
+		if _, err = readResource(h, uri); err == nil {
+			t.Errorf("expected %s to be rejected", uri)
+		}
+	}
+	if _, err = readResource(h, "breweries://1a"); err == nil {
+		t.Error("expected breweries://1a to be rejected")
+	}
+
+	if mockErr := mock.ExpectationsWereMet(); mockErr != nil {
+		t.Errorf("unmet sqlmock expectations: %v", mockErr)
+	}
+}
+
+func TestHandleBeerResource_Export(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer db.Close()
+	h := newTestHandlersWithDB(sqlx.NewDb(db, "sqlmock"))
+	ctx := context.Background()
+
+	mock.ExpectQuery(`WHERE 1=1 AND b\.deleted_at IS NULL AND br\.deleted_at IS NULL`+
+		` AND b\.style ILIKE \$1 AND NOT EXISTS \(.*\)\s+ORDER BY b\.name, b\.id LIMIT \$2`).
+		WithArgs("%IPA%", 5000).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "style", "brewery", "city", "country", "abv", "ibu"}).
+			AddRow(1, "Hazy IPA", "IPA", "Test Brewery", "", "South Africa", 6.5, 45).
+			AddRow(2, "West Coast IPA", "IPA", "Test Brewery", "", "South Africa", 7.0, 65)).
+		RowsWillBeClosed()
+	res, err := h.HandleBeerResource(ctx, "beers://export?style=IPA")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.MimeType != "application/x-ndjson" {
+		t.Errorf("unexpected MIME type: %s", res.MimeType)
+	}
+	lines := strings.Split(strings.TrimSuffix(res.Text, "\n"), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[1], `{"id":2,"name":"West Coast IPA"`) {
+		t.Errorf("expected one beer per line, got %q", res.Text)
+	}
+
+	mock.ExpectQuery(`FROM beers b`).WillReturnError(errors.New("connection refused"))
+	if _, err = h.HandleBeerResource(ctx, "beers://export"); err == nil {
+		t.Error("expected a database error to fail the export")
+	}
+
+	_, err = h.HandleBeerResource(ctx, "beers://export?name=%zz")
+	var mcpErr *mcp.Error
+	if !errors.As(err, &mcpErr) || mcpErr.Code != mcp.InvalidParams {
+		t.Errorf("expected an invalid params error for a malformed query, got %v", err)
+	}
+	if mockErr := mock.ExpectationsWereMet(); mockErr != nil {
+		t.Errorf("unmet sqlmock expectations: %v", mockErr)
+	}
+}
+
+func TestHandleBJCPResource_RelatedStyles(t *testing.T) {
+	h := handlers.NewResourceHandlers(loadStyleData(t), &servicestest.BeerService{}, &servicestest.BreweryService{})
+
+	res, err := readResource(h, "bjcp://styles/21A")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var style data.BJCPStyle
+	if err = json.Unmarshal([]byte(res.Text), &style); err != nil {
+		t.Fatalf("invalid JSON response: %v", err)
+	}
+	if !slices.Equal(style.RelatedStyles, []string{"21C", "21B", "12C"}) {
+		t.Errorf("expected 21A related to 21C, 21B, and 12C, got %v", style.RelatedStyles)
+	}
+}
+
+func TestHandleBJCPResource_GuidelineVersion(t *testing.T) {
+	h := handlers.NewResourceHandlers(nil, nil, nil)
+	h.SetBJCPService(newGuidelineService())
+	ctx := context.Background()
+
+	res, err := readResource(h, "bjcp://2015/styles/14B")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.URI != "bjcp://2015/styles/14B" || !strings.Contains(res.Text, "Scottish Heavy") {
+		t.Errorf("expected 2015 style 14B, got %s: %s", res.URI, res.Text)
+	}
+
+	_, err = readResource(h, "bjcp://2015/styles/21A")
+	expectMCPError(t, err, mcp.MethodNotFound, "BJCP style not found: 21A")
+	_, err = readResource(h, "bjcp://2008/styles/21A")
+	expectMCPError(t, err, mcp.InvalidParams, "loaded versions are 2021, 2015")
+	_, err = readResource(h, "bjcp://latest/styles/21A")
+	expectMCPError(t, err, mcp.MethodNotFound, "BJCP resource not found")
+
+	res, err = h.HandleBJCPResource(ctx, "bjcp://styles")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var summary struct {
+		Versions []string `json:"versions"`
+	}
+	if err = json.Unmarshal([]byte(res.Text), &summary); err != nil {
+		t.Fatalf("invalid JSON response: %v", err)
+	}
+	if strings.Join(summary.Versions, ",") != "2021,2015" {
+		t.Errorf("expected versions [2021 2015], got %v", summary.Versions)
+	}
+}
+
+func TestHandleBJCPResource_StyleSearch(t *testing.T) {
+	h := handlers.NewResourceHandlers(matchStyleTestData(), nil, nil)
+	ctx := context.Background()
+
+	uri := "bjcp://styles?ibu_min=40&ibu_max=70"
+	res, err := h.HandleBJCPResource(ctx, uri)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var parsed struct {
+		Count  int `json:"count"`
+		Styles []struct {
+			Code       string  `json:"code"`
+			Centrality float64 `json:"centrality"`
+		} `json:"styles"`
+	}
+	if err = json.Unmarshal([]byte(res.Text), &parsed); err != nil {
+		t.Fatalf("invalid JSON response: %v", err)
+	}
+	if res.URI != uri || parsed.Count != 1 || parsed.Styles[0].Code != "21A" || parsed.Styles[0].Centrality != 1 {
+		t.Errorf("expected 21A at the centre of its IBU range, got %s: %s", res.URI, res.Text)
+	}
+
+	res, err = h.HandleBJCPResource(ctx, "bjcp://styles?srm_max=20&include_unspecified=true")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(res.Text, `"count":3`) {
+		t.Errorf("expected the style without vitals to be included, got: %s", res.Text)
+	}
+
+	for _, query := range []string{"ibu_min=40&colour=dark", "ibu_max=bitter", "include_unspecified=true"} {
+		_, err = h.HandleBJCPResource(ctx, "bjcp://styles?"+query)
+		expectMCPError(t, err, mcp.InvalidParams, "")
+	}
+}
@@ -0,0 +1,241 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/mcp"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/services"
+	"github.com/CharlRitter/brewsource-mcp/app/pkg/data"
+)
+
+// styleViolationsURI lists the catalog beers whose stored stats fall outside their declared style.
+const styleViolationsURI = "beers://style-violations"
+
+// styleViolation is a catalog beer whose stored stats miss the ranges of its declared style.
+type styleViolation struct {
+	ID        int               `json:"id"`
+	URI       string            `json:"uri"`
+	Name      string            `json:"name"`
+	Brewery   string            `json:"brewery"`
+	Style     string            `json:"style"`
+	StyleCode string            `json:"style_code"`
+	StyleName string            `json:"style_name"`
+	Misses    []data.VitalCheck `json:"misses"`
+}
+
+// styleViolations is the content of beers://style-violations. Checked counts the beers whose style
+// was found and that have at least one stored stat; Unmapped lists the declared styles that were not.
+type styleViolations struct {
+	Violations []styleViolation `json:"violations"`
+	Count      int              `json:"count"`
+	Checked    int              `json:"checked"`
+	Unmapped   []string         `json:"unmapped_styles"`
+}
+
+// CheckBeerStyle checks a catalog beer's stored stats, or raw vitals, against the ranges of a
+// claimed BJCP style. For a catalog beer the style defaults to its declared one, and vitals passed
+// alongside the beer replace its stored stats.
+func (h *ToolHandlers) CheckBeerStyle(ctx context.Context, args map[string]interface{}) (*mcp.ToolResult, error) {
+	query, err := parseVitalsQuery(args)
+	if err != nil {
+		return nil, err
+	}
+	beerID, hasBeer, err := parseOptionalInt(args, "beer_id")
+	if err != nil {
+		return nil, err
+	}
+	claimed, _ := args["style"].(string)
+
+	subject := "Vitals"
+	if hasBeer {
+		if beerID < 1 {
+			return nil, &mcp.Error{Code: mcp.InvalidParams, Message: "beer_id must be a positive integer"}
+		}
+		beer, lookupErr := h.beerService.GetBeerByID(ctx, beerID)
+		if lookupErr != nil {
+			return nil, recordLookupError(lookupErr, "beer")
+		}
+		query = mergeVitals(query, beerVitals(beer.ABV, beer.IBU, beer.SRM))
+		if strings.TrimSpace(claimed) == "" {
+			claimed = beer.Style
+		}
+		subject = fmt.Sprintf("%s (ID %d)", beer.Name, beer.ID)
+	}
+
+	if strings.TrimSpace(claimed) == "" {
+		return nil, &mcp.Error{
+			Code:    mcp.InvalidParams,
+			Message: "'style' is required unless beer_id names a beer with a declared style",
+			Data:    map[string]interface{}{"provided_params": args},
+		}
+	}
+	if query.IsEmpty() {
+		message := data.ErrNoVitalsProvided.Error()
+		if hasBeer {
+			message = fmt.Sprintf(
+				"beer %d has no stored ABV, IBU, or SRM; pass og, fg, abv, ibu, or srm to check", beerID)
+		}
+		return nil, &mcp.Error{
+			Code:    mcp.InvalidParams,
+			Message: message,
+			Data:    map[string]interface{}{"provided_params": args},
+		}
+	}
+
+	style, err := resolveStyle(h.bjcpService, claimed)
+	if err != nil {
+		return nil, err
+	}
+	match := data.CheckStyle(*style, query)
+	matchJSON, err := json.Marshal(match)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal style check: %w", err)
+	}
+
+	return &mcp.ToolResult{
+		Content: []mcp.ToolContent{
+			{Type: "text", Text: formatStyleCheck(subject, match)},
+			{Type: "text", Text: string(matchJSON)},
+		},
+	}, nil
+}
+
+// beerVitals returns a beer's stored stats as vitals, leaving out the ones not recorded.
+func beerVitals(abv float64, ibu int, srm float64) data.VitalsQuery {
+	query := data.VitalsQuery{}
+	if abv > 0 {
+		query.ABV = &abv
+	}
+	if ibu > 0 {
+		value := float64(ibu)
+		query.IBU = &value
+	}
+	if srm > 0 {
+		query.SRM = &srm
+	}
+	return query
+}
+
+// mergeVitals fills the vitals missing from query with those of fallback.
+func mergeVitals(query, fallback data.VitalsQuery) data.VitalsQuery {
+	for _, pair := range []struct{ value, fallback **float64 }{
+		{&query.OG, &fallback.OG},
+		{&query.FG, &fallback.FG},
+		{&query.ABV, &fallback.ABV},
+		{&query.IBU, &fallback.IBU},
+		{&query.SRM, &fallback.SRM},
+	} {
+		if *pair.value == nil {
+			*pair.value = *pair.fallback
+		}
+	}
+	return query
+}
+
+// styleMisses returns the checks that fall outside a range the style publishes.
+func styleMisses(match data.StyleMatch) []data.VitalCheck {
+	misses := []data.VitalCheck{}
+	for _, check := range match.Checks {
+		if !check.InRange && (check.Min != 0 || check.Max != 0) {
+			misses = append(misses, check)
+		}
+	}
+	return misses
+}
+
+func formatStyleCheck(subject string, match data.StyleMatch) string {
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("**%s vs %s %s**\n\n", subject, match.Style.Code, match.Style.Name))
+
+	misses := styleMisses(match)
+	if len(misses) == 0 {
+		response.WriteString(fmt.Sprintf("**Verdict:** fits the style (%d/%d vitals in range)\n\n",
+			match.Matched, match.Checked))
+	} else {
+		names := make([]string, len(misses))
+		for i, miss := range misses {
+			names[i] = strings.ToUpper(miss.Vital)
+		}
+		response.WriteString(fmt.Sprintf("**Verdict:** outside the style on %s (%d/%d vitals in range)\n\n",
+			strings.Join(names, ", "), match.Matched, match.Checked))
+	}
+
+	for _, check := range match.Checks {
+		vital, value := strings.ToUpper(check.Vital), formatVital(check.Vital, check.Value)
+		switch {
+		case check.Min == 0 && check.Max == 0:
+			response.WriteString(fmt.Sprintf("- **%s:** %s (no range published)\n", vital, value))
+		case check.InRange:
+			response.WriteString(fmt.Sprintf("- ✓ **%s:** %s (style: %s - %s)\n", vital, value,
+				formatVital(check.Vital, check.Min), formatVital(check.Vital, check.Max)))
+		default:
+			direction := "above"
+			if check.Value < check.Min {
+				direction = "below"
+			}
+			response.WriteString(fmt.Sprintf("- ✗ **%s:** %s (style: %s - %s; %s %s)\n", vital, value,
+				formatVital(check.Vital, check.Min), formatVital(check.Vital, check.Max),
+				formatVital(check.Vital, check.Distance), direction))
+		}
+	}
+	return response.String()
+}
+
+// handleStyleViolations checks every catalog beer that declares a style against the BJCP style its
+// name maps to, listing the beers with a stored stat outside the style's range.
+func (h *ResourceHandlers) handleStyleViolations(ctx context.Context, uri string) (*mcp.ResourceContent, error) {
+	beers, err := h.beerService.ListBeerStats(ctx)
+	if err != nil {
+		return nil, serviceError(err, "failed to list beer stats")
+	}
+
+	result := styleViolations{Violations: []styleViolation{}, Unmapped: []string{}}
+	styles := map[string]*data.BJCPStyle{}
+	for _, beer := range beers {
+		style, seen := styles[beer.Style]
+		if !seen {
+			style, _ = h.bjcpService.GetStyleByName(beer.Style)
+			styles[beer.Style] = style
+			if style == nil {
+				result.Unmapped = append(result.Unmapped, beer.Style)
+			}
+		}
+		query := beerVitals(beer.ABV, beer.IBU, beer.SRM)
+		if style == nil || query.IsEmpty() {
+			continue
+		}
+		result.Checked++
+		if misses := styleMisses(data.CheckStyle(*style, query)); len(misses) > 0 {
+			result.Violations = append(result.Violations, newStyleViolation(beer, style, misses))
+		}
+	}
+	result.Count = len(result.Violations)
+	slices.Sort(result.Unmapped)
+
+	content, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal style violations: %w", err)
+	}
+	return &mcp.ResourceContent{
+		URI:      uri,
+		MimeType: "application/json",
+		Text:     string(content),
+	}, nil
+}
+
+func newStyleViolation(beer *services.BeerStats, style *data.BJCPStyle, misses []data.VitalCheck) styleViolation {
+	return styleViolation{
+		ID:        beer.ID,
+		URI:       fmt.Sprintf("beers://%d", beer.ID),
+		Name:      beer.Name,
+		Brewery:   beer.Brewery,
+		Style:     beer.Style,
+		StyleCode: style.Code,
+		StyleName: style.Name,
+		Misses:    misses,
+	}
+}
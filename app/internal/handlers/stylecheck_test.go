@@ -0,0 +1,131 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/handlers"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/mcp"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/services"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/services/servicestest"
+)
+
+// newStyleCheckBeers returns catalog beers that fit, miss, cannot map, and do not record their style.
+func newStyleCheckBeers() *servicestest.BeerService {
+	return &servicestest.BeerService{Beers: []*services.BeerDetail{
+		{ID: 1, Name: "Hop Bomb", Brewery: "Test Brewery", Style: "American IPA", ABV: 6.5, IBU: 90, SRM: 8},
+		{ID: 2, Name: "Porch Pounder", Brewery: "Test Brewery", Style: "American Pale Ale", ABV: 5, IBU: 35, SRM: 6},
+		{ID: 3, Name: "Island Time", Brewery: "Test Brewery", Style: "Coconut Ale", ABV: 5, IBU: 20},
+		{ID: 4, Name: "Mystery IPA", Brewery: "Test Brewery", Style: "American IPA"},
+		{ID: 5, Name: "Unlabelled", Brewery: "Test Brewery", ABV: 12},
+	}}
+}
+
+func TestCheckBeerStyle(t *testing.T) {
+	h := handlers.NewToolHandlers(loadStyleData(t), newStyleCheckBeers(), nil)
+	ctx := context.Background()
+
+	result, err := h.CheckBeerStyle(ctx, map[string]interface{}{"beer_id": 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	text := result.Content[0].Text
+	for _, want := range []string{
+		"**Hop Bomb (ID 1) vs 21A American IPA**",
+		"**Verdict:** outside the style on IBU (2/3 vitals in range)",
+		"- ✓ **ABV:** 6.5 (style: 5.5 - 7.5)",
+		"- ✗ **IBU:** 90 (style: 40 - 70; 20 above)",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in:\n%s", want, text)
+		}
+	}
+	if !strings.Contains(result.Content[1].Text, `"distance":20`) {
+		t.Errorf("expected the checks as JSON, got %s", result.Content[1].Text)
+	}
+
+	// A claimed style and raw vitals replace the declared style and stored stats.
+	result, err = h.CheckBeerStyle(ctx, map[string]interface{}{"beer_id": 1, "style": "18B", "ibu": 45})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if text = result.Content[0].Text; !strings.Contains(text, "vs 18B American Pale Ale") ||
+		!strings.Contains(text, "outside the style on ABV (2/3 vitals in range)") {
+		t.Errorf("expected the beer's ABV to miss American Pale Ale, got:\n%s", text)
+	}
+
+	result, err = h.CheckBeerStyle(ctx, map[string]interface{}{"style": "American IPA", "abv": 6, "srm": "4"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if text = result.Content[0].Text; !strings.Contains(text, "**Vitals vs 21A American IPA**") ||
+		!strings.Contains(text, "- ✗ **SRM:** 4.0 (style: 6.0 - 14.0; 2.0 below)") {
+		t.Errorf("expected raw vitals to be checked, got:\n%s", text)
+	}
+}
+
+func TestCheckBeerStyle_Errors(t *testing.T) {
+	h := handlers.NewToolHandlers(loadStyleData(t), newStyleCheckBeers(), nil)
+	ctx := context.Background()
+
+	tests := []struct {
+		args map[string]interface{}
+		want string
+	}{
+		{map[string]interface{}{"abv": 6}, "'style' is required"},
+		{map[string]interface{}{"beer_id": 5}, "'style' is required"},
+		{map[string]interface{}{"style": "21A"}, "at least one vital"},
+		{map[string]interface{}{"beer_id": 4}, "beer 4 has no stored ABV, IBU, or SRM"},
+		{map[string]interface{}{"beer_id": 0, "style": "21A"}, "beer_id must be a positive integer"},
+		{map[string]interface{}{"beer_id": 99}, "beer 99 not found"},
+		{map[string]interface{}{"style": "Banana Lager", "abv": 5}, "BJCP style not found"},
+		{map[string]interface{}{"style": "21A", "ibu": "lots"}, "ibu must be a number"},
+	}
+	for _, tt := range tests {
+		_, err := h.CheckBeerStyle(ctx, tt.args)
+		expectMCPError(t, err, mcp.InvalidParams, tt.want)
+	}
+}
+
+func TestStyleViolationsResource(t *testing.T) {
+	h := handlers.NewResourceHandlers(loadStyleData(t), newStyleCheckBeers(), nil)
+
+	res, err := h.HandleBeerResource(context.Background(), "beers://style-violations")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var report struct {
+		Violations []struct {
+			ID        int    `json:"id"`
+			URI       string `json:"uri"`
+			StyleCode string `json:"style_code"`
+			Misses    []struct {
+				Vital    string  `json:"vital"`
+				Distance float64 `json:"distance"`
+			} `json:"misses"`
+		} `json:"violations"`
+		Count    int      `json:"count"`
+		Checked  int      `json:"checked"`
+		Unmapped []string `json:"unmapped_styles"`
+	}
+	if err = json.Unmarshal([]byte(res.Text), &report); err != nil {
+		t.Fatalf("failed to decode %s: %v", res.Text, err)
+	}
+
+	if report.Count != 1 || len(report.Violations) != 1 {
+		t.Fatalf("expected only Hop Bomb to violate its style, got %s", res.Text)
+	}
+	violation := report.Violations[0]
+	if violation.ID != 1 || violation.URI != "beers://1" || violation.StyleCode != "21A" ||
+		len(violation.Misses) != 1 || violation.Misses[0].Vital != "ibu" || violation.Misses[0].Distance != 20 {
+		t.Errorf("expected Hop Bomb to miss 21A's IBU range by 20, got %+v", violation)
+	}
+	if report.Checked != 2 {
+		t.Errorf("expected the two mapped beers with stats to be checked, got %d", report.Checked)
+	}
+	if len(report.Unmapped) != 1 || report.Unmapped[0] != "Coconut Ale" {
+		t.Errorf("expected Coconut Ale to be unmapped, got %v", report.Unmapped)
+	}
+}
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/mcp"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/services"
+	"github.com/CharlRitter/brewsource-mcp/app/pkg/data"
+)
+
+// suggestTimeout bounds a suggest call, which type-ahead clients make on each keystroke.
+const suggestTimeout = 500 * time.Millisecond
+
+// Kinds of names the suggest tool completes.
+const (
+	suggestBrewery = "brewery"
+	suggestBeer    = "beer"
+	suggestStyle   = "style"
+)
+
+var suggestKinds = []string{suggestBrewery, suggestBeer, suggestStyle}
+
+// suggestToolDefinition describes suggest.
+func suggestToolDefinition() mcp.Tool {
+	return mcp.Tool{
+		Name:        "suggest",
+		Description: "Suggest brewery, beer, or BJCP style names starting with a prefix, for type-ahead",
+		InputSchema: mcp.ObjectSchema(map[string]interface{}{
+			"kind": map[string]interface{}{
+				"type":        "string",
+				"description": "What to suggest: 'brewery', 'beer', or 'style'",
+				"enum":        suggestKinds,
+			},
+			"prefix": mcp.StringSchema("Start of the name, ignoring case (e.g., 'devil'); styles also match by code "+
+				"or alias (e.g., '21' or 'hefe')", true),
+			"limit": mcp.IntegerRangeSchema(fmt.Sprintf(
+				"Maximum number of suggestions (default: %d, max: %d)", services.DefaultSuggestLimit,
+				services.MaxSuggestLimit), 1, services.MaxSuggestLimit),
+		}, []string{"kind", "prefix"}),
+	}
+}
+
+// Suggest lists the brewery, beer, or style names starting with the prefix argument. Catalog names
+// are looked up within suggestTimeout; styles are matched in memory.
+func (h *ToolHandlers) Suggest(ctx context.Context, args map[string]interface{}) (*mcp.ToolResult, error) {
+	text, _ := args["kind"].(string)
+	kind := strings.ToLower(strings.TrimSpace(text))
+	if !slices.Contains(suggestKinds, kind) {
+		return nil, &mcp.Error{
+			Code:    mcp.InvalidParams,
+			Message: "kind must be one of: " + strings.Join(suggestKinds, ", "),
+			Data:    map[string]interface{}{"kind": args["kind"]},
+		}
+	}
+	prefix, _ := args["prefix"].(string)
+	if strings.TrimSpace(prefix) == "" {
+		return nil, &mcp.Error{Code: mcp.InvalidParams, Message: "prefix is required"}
+	}
+	limit, _, err := parseOptionalInt(args, "limit")
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := args["limit"]; ok && limit < 1 {
+		return nil, &mcp.Error{Code: mcp.InvalidParams, Message: "limit must be at least 1"}
+	}
+	limit = services.SuggestLimit(limit)
+
+	if kind == suggestStyle {
+		return mcp.NewToolResult(formatStyleSuggestNames(prefix, h.bjcpService.SuggestStyles(prefix, limit))), nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, suggestTimeout)
+	defer cancel()
+	var names []string
+	if kind == suggestBrewery {
+		names, err = h.breweryService.SuggestNames(ctx, prefix, limit)
+	} else {
+		names, err = h.beerService.SuggestNames(ctx, prefix, limit)
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return nil, &mcp.Error{
+			Code:    mcp.RequestTimeout,
+			Message: fmt.Sprintf("suggestions took longer than %s; try a longer prefix", suggestTimeout),
+		}
+	}
+	if err != nil {
+		return nil, serviceError(err, "failed to suggest names")
+	}
+	return mcp.NewToolResult(formatSuggestNames(kind, prefix, names)), nil
+}
+
+// formatSuggestNames lists the suggested brewery or beer names, one per line.
+func formatSuggestNames(kind, prefix string, names []string) string {
+	if len(names) == 0 {
+		return fmt.Sprintf("No %s names start with %q.", kind, strings.TrimSpace(prefix))
+	}
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("**%d %s name(s) starting with %q:**\n\n", len(names), kind,
+		strings.TrimSpace(prefix)))
+	for _, name := range names {
+		response.WriteString("- " + name + "\n")
+	}
+	return response.String()
+}
+
+// formatStyleSuggestNames lists the suggested styles with their codes.
+func formatStyleSuggestNames(prefix string, styles []data.BJCPStyle) string {
+	names := make([]string, len(styles))
+	for i, style := range styles {
+		names[i] = fmt.Sprintf("%s (%s)", style.Name, style.Code)
+	}
+	return formatSuggestNames(suggestStyle, prefix, names)
+}
@@ -0,0 +1,120 @@
+package handlers_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/handlers"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/mcp"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/services/servicestest"
+)
+
+// slowBreweryService is a brewery service whose name suggestions wait until they are cancelled.
+type slowBreweryService struct {
+	servicestest.BreweryService
+}
+
+func (s *slowBreweryService) SuggestNames(ctx context.Context, _ string, _ int) ([]string, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestSuggest(t *testing.T) {
+	h := handlers.NewToolHandlers(loadStyleData(t), newBeerService(), newBreweryService())
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		args     map[string]interface{}
+		contains []string
+		excludes []string
+	}{
+		{
+			name:     "Brewery names",
+			args:     map[string]interface{}{"kind": "brewery", "prefix": " test"},
+			contains: []string{`**1 brewery name(s) starting with "test":**`, "- Test Brewery"},
+			excludes: []string{"Alpha Brewing"},
+		},
+		{
+			name:     "Beer names",
+			args:     map[string]interface{}{"kind": "Beer", "prefix": "TEST B"},
+			contains: []string{"- Test Beer"},
+		},
+		{
+			name:     "Style names by code",
+			args:     map[string]interface{}{"kind": "style", "prefix": "21", "limit": 2},
+			contains: []string{`**2 style name(s) starting with "21":**`, "- American IPA (21A)"},
+		},
+		{
+			name:     "Style names by alias",
+			args:     map[string]interface{}{"kind": "style", "prefix": "hefe"},
+			contains: []string{"- Weissbier (10A)"},
+		},
+		{
+			name:     "No matches",
+			args:     map[string]interface{}{"kind": "brewery", "prefix": "zzz"},
+			contains: []string{`No brewery names start with "zzz".`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := h.Suggest(ctx, tt.args)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			text := result.Content[0].Text
+			for _, want := range tt.contains {
+				if !strings.Contains(text, want) {
+					t.Errorf("Expected %q in:\n%s", want, text)
+				}
+			}
+			for _, unwanted := range tt.excludes {
+				if strings.Contains(text, unwanted) {
+					t.Errorf("Expected no %q in:\n%s", unwanted, text)
+				}
+			}
+		})
+	}
+}
+
+func TestSuggest_InvalidParams(t *testing.T) {
+	h := handlers.NewToolHandlers(loadStyleData(t), newBeerService(), newBreweryService())
+
+	tests := []struct {
+		name        string
+		args        map[string]interface{}
+		errContains string
+	}{
+		{"Unknown kind", map[string]interface{}{"kind": "hop", "prefix": "c"}, "kind must be one of: brewery, beer, style"},
+		{"Missing kind", map[string]interface{}{"prefix": "c"}, "kind must be one of"},
+		{"Blank prefix", map[string]interface{}{"kind": "beer", "prefix": "  "}, "prefix is required"},
+		{"Zero limit", map[string]interface{}{"kind": "beer", "prefix": "c", "limit": 0}, "limit must be at least 1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := h.Suggest(context.Background(), tt.args)
+			var mcpErr *mcp.Error
+			if !errors.As(err, &mcpErr) || mcpErr.Code != mcp.InvalidParams {
+				t.Fatalf("Expected an InvalidParams error, got %v", err)
+			}
+			if !strings.Contains(mcpErr.Message, tt.errContains) {
+				t.Errorf("Expected error containing %q, got %q", tt.errContains, mcpErr.Message)
+			}
+		})
+	}
+}
+
+func TestSuggest_Timeout(t *testing.T) {
+	h := handlers.NewToolHandlers(nil, newBeerService(), &slowBreweryService{})
+
+	_, err := h.Suggest(context.Background(), map[string]interface{}{"kind": "brewery", "prefix": "d"})
+	var mcpErr *mcp.Error
+	if !errors.As(err, &mcpErr) || mcpErr.Code != mcp.RequestTimeout {
+		t.Fatalf("Expected a RequestTimeout error, got %v", err)
+	}
+	if !strings.Contains(mcpErr.Message, "500ms") {
+		t.Errorf("Unexpected timeout message: %s", mcpErr.Message)
+	}
+}
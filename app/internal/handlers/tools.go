@@ -2,29 +2,79 @@
 package handlers
 
 import (
+	"cmp"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"regexp"
+	"math"
+	"math/rand/v2"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/CharlRitter/brewsource-mcp/app/internal/mcp"
 	"github.com/CharlRitter/brewsource-mcp/app/internal/services"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/units"
 	"github.com/CharlRitter/brewsource-mcp/app/pkg/data"
 )
 
 const (
 	// defaultSearchLimit is the default number of results when no limit is specified.
-	defaultSearchLimit = 20
+	defaultSearchLimit = services.DefaultSearchLimit
 	// maxSearchLimit is the maximum allowed number of results.
-	maxSearchLimit = 100
+	maxSearchLimit = services.MaxSearchLimit
+	// defaultMatchLimit is the default number of styles returned by match_style.
+	defaultMatchLimit = 5
+	// maxMatchLimit is the maximum number of styles returned by match_style.
+	maxMatchLimit = 20
+	// styleSuggestionLimit is the number of similarly named styles bjcp_lookup suggests.
+	styleSuggestionLimit = 5
+	// defaultStyleSearchLimit is the default number of styles listed by bjcp_style_search.
+	defaultStyleSearchLimit = 20
+	// maxStyleSearchLimit is the maximum number of styles listed by bjcp_style_search.
+	maxStyleSearchLimit = 50
+	// defaultRelatedStylesLimit is the default number of styles listed by related_styles.
+	defaultRelatedStylesLimit = 5
+	// maxRelatedStylesLimit is the maximum number of styles listed by related_styles.
+	maxRelatedStylesLimit = 20
+	// statsListLimit is the number of countries and styles listed by brewery_stats.
+	statsListLimit = 10
+	// maxSurpriseCount is the maximum number of suggestions returned by surprise_me.
+	maxSurpriseCount = services.MaxRandomBeers
 )
 
+// Values accepted in surprise_me's kind argument.
+const (
+	surpriseBeer  = "beer"
+	surpriseStyle = "style"
+)
+
+var surpriseKinds = []string{surpriseBeer, surpriseStyle}
+
 // ToolHandlers handles all MCP tool requests and implements ToolHandlerRegistry.
 type ToolHandlers struct {
-	bjcpData       *data.BJCPData
+	bjcpService    *data.BJCPService
 	beerService    services.BeerServiceInterface
 	breweryService services.BreweryServiceInterface
+	adminToken     string                          // enables the admin tools when set; see SetAdminToken
+	brewerySyncer  services.BrewerySyncerInterface // backs sync_breweries; nil disables it
+	notifyResource func(uri string) bool           // tells subscribers a resource changed; see SetResourceNotifier
+	examples       *exampleCache                   // resolved commercial examples for style_examples
+	// ingredientService backs lookup_ingredient and the calculators' ingredient names; nil disables them
+	ingredientService *data.IngredientService
+	// exportStore keeps the files of export_results, bounded by exportOptions; nil disables it
+	exportStore   services.ExportStore
+	exportOptions ExportOptions
+	// jobs runs sync_breweries, seed_catalog, and import_catalog in the background, for any
+	// connection to query with get_job_status
+	jobs *services.JobRunner
+	// notifyProgress sends the progress of a job to the client that queued it; see SetProgressNotifier
+	notifyProgress func(token interface{}, progress, total float64, message string) bool
+	catalogLoader  CatalogLoader // backs seed_catalog and import_catalog; nil disables them
+	// now is the clock of opening hours, export expiry, and elapsed times; see SetClock
+	now func() time.Time
 }
 
 // NewToolHandlers creates a new instance of ToolHandlers.
@@ -34,104 +84,384 @@ func NewToolHandlers(
 	breweryService services.BreweryServiceInterface,
 ) *ToolHandlers {
 	return &ToolHandlers{
-		bjcpData:       bjcpData,
+		bjcpService:    data.NewBJCPServiceFromData(bjcpData),
 		beerService:    beerService,
 		breweryService: breweryService,
+		examples:       newExampleCache(),
+		jobs:           services.NewJobRunner(0, 0),
+		now:            time.Now,
 	}
 }
 
-// RegisterToolHandlers implements ToolHandlerRegistry interface.
+// SetClock has the tools tell the time by now, such as a fixed time in tests with golden output.
+func (h *ToolHandlers) SetClock(now func() time.Time) {
+	h.now = now
+}
+
+// SetBJCPService answers the style tools from a service shared with other handlers, so that a
+// reload of its data is served here too.
+func (h *ToolHandlers) SetBJCPService(bjcpService *data.BJCPService) {
+	h.bjcpService = bjcpService
+}
+
+// RegisterToolHandlers implements ToolHandlerRegistry interface. The style guide and catalog lookups
+// cite their data, see cited, or in the metadata of their toolResponse.
 func (h *ToolHandlers) RegisterToolHandlers(server *mcp.Server) {
 	server.RegisterToolHandler("bjcp_lookup", h.BJCPLookup)
 	server.RegisterToolHandler("search_beers", h.SearchBeers)
 	server.RegisterToolHandler("find_breweries", h.FindBreweries)
+	server.RegisterToolHandler("get_beer", h.cited(h.GetBeer, "catalog"))
+	server.RegisterToolHandler("get_brewery", h.cited(h.GetBrewery, "breweries"))
+	server.RegisterToolHandler("brewery_beers", h.cited(h.BreweryBeers, "breweries"))
+	server.RegisterToolHandler("venues_near", h.VenuesNear)
+	server.RegisterToolHandler("brewery_stats", h.BreweryStats)
+	server.RegisterToolHandler("match_style", h.MatchStyle)
+	server.RegisterToolHandler("check_beer_style", h.CheckBeerStyle)
+	server.RegisterToolHandler("bjcp_style_search", h.StyleSearch)
+	server.RegisterToolHandler("compare_styles", h.CompareStyles)
+	server.RegisterToolHandler("style_examples", h.StyleExamples)
+	server.RegisterToolHandler("related_styles", h.RelatedStyles)
+	server.RegisterToolHandler("recommend_beers", h.RecommendBeers)
+	server.RegisterToolHandler("surprise_me", h.SurpriseMe)
+	server.RegisterToolHandler("lookup_ingredient", h.LookupIngredient)
+	server.RegisterToolHandler("suggest", h.Suggest)
+	server.RegisterToolHandler("export_results", h.ExportResults)
+	h.registerCalculatorTools(server)
+	h.registerAdminTools(server)
+}
+
+// ServerMetadata implements mcp.ServerDescriber, naming the BJCP guideline version served by default
+// as bjcp_version and every version loaded, newest first, as bjcp_versions.
+func (h *ToolHandlers) ServerMetadata() map[string]string {
+	version := h.bjcpService.GetMetadata().Version
+	if version == "" {
+		version = data.DefaultGuidelineVersion
+	}
+	return map[string]string{
+		"bjcp_version":  version,
+		"bjcp_versions": strings.Join(h.bjcpService.Versions(), ", "),
+	}
 }
 
 func (h *ToolHandlers) GetToolDefinitions() []mcp.Tool {
-	return []mcp.Tool{
+	tools := []mcp.Tool{
 		{
 			Name:        "bjcp_lookup",
-			Description: "Look up BJCP beer style information by style code or name",
+			Description: "Look up BJCP beer style information by style code or name, or list a category's styles",
 			InputSchema: mcp.ObjectSchema(map[string]interface{}{
 				"style_code": mcp.StringSchema("BJCP style code (e.g., '21A' for American IPA)", false),
 				"style_name": mcp.StringSchema("BJCP style name (e.g., 'American IPA')", false),
+				"category": mcp.StringSchema(
+					"BJCP category to list the styles of (e.g., 'IPA') when neither style_code nor style_name is given",
+					false),
+				"version": mcp.StringSchema(
+					"BJCP guideline version to look the style up in, '2021' (default) or '2015' when loaded", false),
 			}, []string{}),
 		},
 		{
-			Name:        "search_beers",
-			Description: "Search for commercial beers by name, style, brewery, or location",
+			Name: "search_beers",
+			Description: "Search for commercial beers by name, style or BJCP style code, brewery, location, " +
+				"description keywords, or flavour tags",
 			InputSchema: mcp.ObjectSchema(map[string]interface{}{
 				"name":     mcp.StringSchema("Beer name to search for", false),
 				"style":    mcp.StringSchema("Beer style to filter by", false),
 				"brewery":  mcp.StringSchema("Brewery name to filter by", false),
 				"location": mcp.StringSchema("Location (city, state, country) to filter by", false),
-				"limit": map[string]interface{}{
-					"type":        "integer",
-					"description": "Maximum number of results (default: 20, max: 100)",
+				"description": mcp.StringSchema(
+					"Words the beer's description mentions (e.g., 'dark chocolate')", false),
+				"tags": stringListSchema(
+					"Flavour tag, or a list of tags all of which the beer must have, derived from its description "+
+						"(e.g., 'chocolate', 'tropical', 'sour')",
+					nil,
+				),
+				"abv_min": mcp.NumberSchema("Minimum ABV in percent, inclusive (e.g., 4.0)"),
+				"abv_max": mcp.NumberSchema("Maximum ABV in percent, inclusive (e.g., 5.0)"),
+				"ibu_min": mcp.NumberSchema("Minimum bitterness in IBU, inclusive"),
+				"ibu_max": mcp.NumberSchema("Maximum bitterness in IBU, inclusive"),
+				"srm_min": mcp.NumberSchema("Minimum colour in SRM, inclusive"),
+				"srm_max": mcp.NumberSchema("Maximum colour in SRM, inclusive"),
+				"style_code": mcp.StringSchema(
+					"BJCP style code (e.g., 21A) to filter by the style's name and aliases instead of style; "+
+						"results are checked against the style's ABV and IBU ranges",
+					false,
+				),
+				"limit":  limitSchema(),
+				"offset": mcp.IntegerSchema("Number of results to skip, for paging (default: 0)"),
+				"page":   mcp.IntegerSchema("1-based page number of size limit; an alternative to offset"),
+				"sort": sortSchema(
+					"Result order: 'name' (default), 'relevance' to rank by similarity to the name filter, "+
+						"or a column: 'abv', 'ibu', 'style', or 'brewery'",
+					services.BeerSortKeys,
+				),
+				"order": sortSchema("Direction of a name or column sort (default: 'asc')", services.SortOrders),
+				"group_by_brewery": map[string]interface{}{
+					"type":        "boolean",
+					"description": "List the results under their breweries (default: false)",
 				},
 			}, []string{}),
 		},
 		{
-			Name:        "find_breweries",
-			Description: "Find breweries by name, location, city, state, or country",
+			Name: "find_breweries",
+			Description: "Find breweries by name, location, city, state, or country; read breweries://countries " +
+				"and breweries://countries/{country}/regions to see the countries and states in the directory",
 			InputSchema: mcp.ObjectSchema(map[string]interface{}{
 				"name":     mcp.StringSchema("Brewery name to search for", false),
 				"location": mcp.StringSchema("General location search (city, state, country)", false),
-				"city":     mcp.StringSchema("City to filter by", false),
-				"state":    mcp.StringSchema("State to filter by", false),
-				"country":  mcp.StringSchema("Country to filter by", false),
-				"limit": map[string]interface{}{
-					"type":        "integer",
-					"description": "Maximum number of results (default: 20, max: 100)",
+				"city":     stringListSchema("City, or a list of cities any of which may match", nil),
+				"state":    stringListSchema("State, or a list of states any of which may match", nil),
+				"country":  stringListSchema("Country, or a list of countries any of which may match", nil),
+				"type": stringListSchema(
+					"Brewery type, or a list of types any of which may match", services.BreweryTypes,
+				),
+				"exclude_types": stringListSchema(
+					"Brewery types to leave out, such as ['brewpub']", services.BreweryTypes,
+				),
+				"latitude":  mcp.NumberSchema("Latitude to search near in decimal degrees; sorts results by distance"),
+				"longitude": mcp.NumberSchema("Longitude to search near in decimal degrees"),
+				"radius_km": mcp.NumberSchema("Maximum distance in km from latitude/longitude"),
+				"limit":     limitSchema(),
+				"offset":    mcp.IntegerSchema("Number of results to skip, for paging (default: 0)"),
+				"page":      mcp.IntegerSchema("1-based page number of size limit; an alternative to offset"),
+				"sort": sortSchema(
+					"Result order: 'name' (default), 'relevance' to rank by similarity to the name filter, "+
+						"or a column: 'city', 'country', or 'type'; ignored when latitude/longitude are given",
+					services.BrewerySortKeys,
+				),
+				"order": sortSchema("Direction of a name or column sort (default: 'asc')", services.SortOrders),
+			}, []string{}),
+		},
+		{
+			Name:        "get_beer",
+			Description: "Get one beer with its brewery and ingredients by the ID from search_beers",
+			InputSchema: mcp.ObjectSchema(map[string]interface{}{
+				"id": mcp.IntegerSchema("Beer ID"),
+			}, []string{"id"}),
+		},
+		{
+			Name: "get_brewery",
+			Description: "Get the full record for one brewery, including its beer count and its venues with " +
+				"opening hours, by the ID from find_breweries",
+			InputSchema: mcp.ObjectSchema(map[string]interface{}{
+				"id":       mcp.IntegerSchema("Brewery ID"),
+				"timezone": timezoneSchema(),
+			}, []string{"id"}),
+		},
+		{
+			Name:        "brewery_beers",
+			Description: "List the beers a brewery makes, sorted by name, with style, ABV, and IBU",
+			InputSchema: mcp.ObjectSchema(map[string]interface{}{
+				"brewery_id":   mcp.IntegerSchema("Brewery ID from find_breweries"),
+				"brewery_name": mcp.StringSchema("Brewery name, used when brewery_id is not given", false),
+				"limit":        mcp.IntegerSchema("Maximum number of beers (default: 50, max: 200)"),
+			}, []string{}),
+		},
+		venuesNearToolDefinition(),
+		{
+			Name:        "brewery_stats",
+			Description: "Summarise breweries per country and beers per style, with average ABV and IBU per style",
+			InputSchema: mcp.ObjectSchema(map[string]interface{}{
+				"country": mcp.StringSchema("Only count breweries in, and beers from, this country", false),
+				"style":   mcp.StringSchema("Only count beers of this style, and the breweries that make them", false),
+			}, []string{}),
+		},
+		{
+			Name:        "match_style",
+			Description: "Suggest BJCP styles that fit measured or planned recipe vitals (OG, FG, ABV, IBU, SRM)",
+			InputSchema: mcp.ObjectSchema(map[string]interface{}{
+				"og":    mcp.NumberSchema("Original gravity (e.g., 1.060)"),
+				"fg":    mcp.NumberSchema("Final gravity (e.g., 1.012)"),
+				"abv":   mcp.NumberSchema("Alcohol by volume in percent (e.g., 6.3)"),
+				"ibu":   mcp.NumberSchema("Bitterness in IBU (e.g., 55)"),
+				"srm":   mcp.NumberSchema("Colour in SRM (e.g., 8)"),
+				"limit": mcp.IntegerSchema("Maximum number of styles to return (default: 5, max: 20)"),
+			}, []string{}),
+		},
+		{
+			Name: "check_beer_style",
+			Description: "Check a catalog beer's stored stats, or raw vitals, against a claimed BJCP style's ranges, " +
+				"with how far each miss falls outside",
+			InputSchema: mcp.ObjectSchema(map[string]interface{}{
+				"beer_id": mcp.IntegerSchema("Catalog beer to check, using its stored ABV, IBU, and SRM"),
+				"style": mcp.StringSchema(
+					"Claimed BJCP style code or name (e.g., '21A' or 'American IPA'); defaults to the beer's style",
+					false),
+				"og":  mcp.NumberSchema("Original gravity (e.g., 1.060)"),
+				"fg":  mcp.NumberSchema("Final gravity (e.g., 1.012)"),
+				"abv": mcp.NumberSchema("Alcohol by volume in percent, instead of the beer's (e.g., 6.3)"),
+				"ibu": mcp.NumberSchema("Bitterness in IBU, instead of the beer's (e.g., 55)"),
+				"srm": mcp.NumberSchema("Colour in SRM, instead of the beer's (e.g., 8)"),
+			}, []string{}),
+		},
+		{
+			Name: "bjcp_style_search",
+			Description: "Find BJCP styles whose vital ranges overlap the requested ranges, e.g. IBU 40-70 and SRM " +
+				"under 10, the most central fit first",
+			InputSchema: mcp.ObjectSchema(map[string]interface{}{
+				"abv_min": mcp.NumberSchema("Minimum ABV in percent (e.g., 5.0)"),
+				"abv_max": mcp.NumberSchema("Maximum ABV in percent (e.g., 7.5)"),
+				"ibu_min": mcp.NumberSchema("Minimum bitterness in IBU (e.g., 40)"),
+				"ibu_max": mcp.NumberSchema("Maximum bitterness in IBU (e.g., 70)"),
+				"srm_min": mcp.NumberSchema("Minimum colour in SRM"),
+				"srm_max": mcp.NumberSchema("Maximum colour in SRM (e.g., 10)"),
+				"og_min":  mcp.NumberSchema("Minimum original gravity (e.g., 1.050)"),
+				"og_max":  mcp.NumberSchema("Maximum original gravity (e.g., 1.070)"),
+				"include_unspecified": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Also list styles that publish no range for a requested vital (default: false)",
+				},
+				"limit": mcp.IntegerSchema("Maximum number of styles to return (default: 20, max: 50)"),
+			}, []string{}),
+		},
+		{
+			Name:        "compare_styles",
+			Description: "Compare two BJCP styles side by side, including vitals overlap and midpoint differences",
+			InputSchema: mcp.ObjectSchema(map[string]interface{}{
+				"style_a": mcp.StringSchema("First BJCP style code or name (e.g., '21A' or 'American IPA')", true),
+				"style_b": mcp.StringSchema("Second BJCP style code or name (e.g., '12C' or 'English IPA')", true),
+			}, []string{"style_a", "style_b"}),
+		},
+		{
+			Name:        "style_examples",
+			Description: "List a BJCP style's commercial examples, linked to the matching beers in the catalog",
+			InputSchema: mcp.ObjectSchema(map[string]interface{}{
+				"style_code": mcp.StringSchema("BJCP style code (e.g., '21A' for American IPA)", true),
+				"version": mcp.StringSchema(
+					"BJCP guideline version to take the examples from, '2021' (default) or '2015' when loaded", false),
+			}, []string{"style_code"}),
+		},
+		{
+			Name:        "related_styles",
+			Description: "List the BJCP styles closest to a style by vitals, within its category and across the others",
+			InputSchema: mcp.ObjectSchema(map[string]interface{}{
+				"style_code": mcp.StringSchema("BJCP style code (e.g., '21A' for American IPA)", true),
+				"limit":      mcp.IntegerSchema("Maximum number of styles to return (default: 5, max: 20)"),
+				"version": mcp.StringSchema(
+					"BJCP guideline version to compare within, '2021' (default) or '2015' when loaded", false),
+			}, []string{"style_code"}),
+		},
+		{
+			Name: "recommend_beers",
+			Description: "Recommend catalog beers like a beer you enjoy, or in or near a BJCP style, ranked by a " +
+				"score of style proximity, ABV/IBU/SRM closeness, and the same brewery country, with each score's " +
+				"breakdown",
+			InputSchema: mcp.ObjectSchema(map[string]interface{}{
+				"beer_id":   mcp.IntegerSchema("Catalog ID of the beer you like"),
+				"beer_name": mcp.StringSchema("Name of the beer you like, instead of beer_id", false),
+				"style_code": mcp.StringSchema(
+					"BJCP style code (e.g., '21A') to recommend beers of, or the style of a liked beer whose own "+
+						"style is not a BJCP style", false),
+				"limit": mcp.IntegerSchema("Maximum number of beers to return (default: 5, max: 20)"),
+			}, []string{}),
+		},
+		{
+			Name:        "surprise_me",
+			Description: "Suggest a random commercial beer or a random BJCP style",
+			InputSchema: mcp.ObjectSchema(map[string]interface{}{
+				"kind": map[string]interface{}{
+					"type":        "string",
+					"description": "What to suggest: 'beer' (default) or 'style'",
+					"enum":        surpriseKinds,
 				},
+				"count": mcp.IntegerSchema("Number of suggestions (default: 1, max: 5)"),
+				"style": mcp.StringSchema("Only suggest beers of this style; ignored for styles", false),
+				"country": mcp.StringSchema(
+					"Only suggest beers from breweries in this country; ignored for styles", false,
+				),
 			}, []string{}),
 		},
+		{
+			Name: "lookup_ingredient",
+			Description: "Look up hops, fermentables, and yeast strains by name, with alpha acid, colour, " +
+				"attenuation, and substitutes",
+			InputSchema: mcp.ObjectSchema(map[string]interface{}{
+				"name": mcp.StringSchema(
+					"Ingredient name, tolerating typos (e.g., 'Citra', 'Crystal 60', 'WLP001')", true),
+				"type": map[string]interface{}{
+					"type":        "string",
+					"description": "Only match this kind of ingredient",
+					"enum":        data.IngredientKinds,
+				},
+				"limit": mcp.IntegerSchema("Number of matches to list (default: 5, max: 20)"),
+				"units": mcp.StringSchema(unitsDescription, false),
+			}, []string{"name"}),
+		},
+		suggestToolDefinition(),
+		exportToolDefinition(),
 	}
+	tools = append(tools, calculatorToolDefinitions()...)
+	return append(tools, adminToolDefinitions()...)
 }
 
-// isValidBJCPStyleCode validates BJCP style codes (e.g., 21A, 1B, 33C).
-func isValidBJCPStyleCode(code string) bool {
-	matched, _ := regexp.MatchString(`^[0-9]{1,2}[A-Z]$`, code)
-	return matched
-}
-
-// BJCPLookup handles BJCP style lookup functionality.
-func (h *ToolHandlers) BJCPLookup(_ context.Context, args map[string]interface{}) (*mcp.ToolResult, error) {
+// BJCPLookup handles BJCP style lookup functionality: a style by code or name, with the closest names
+// when none matches, or the styles of a category.
+func (h *ToolHandlers) BJCPLookup(ctx context.Context, args map[string]interface{}) (*mcp.ToolResult, error) {
+	response := newToolResponse("bjcp_lookup", "BJCP style lookup", h.now)
 	styleCode, hasCode := args["style_code"].(string)
 	styleName, hasName := args["style_name"].(string)
+	category, hasCategory := args["category"].(string)
 
-	if !hasCode && !hasName {
+	if !hasCode && !hasName && !hasCategory {
 		return nil, &mcp.Error{
 			Code:    mcp.InvalidParams,
-			Message: "either 'style_code' or 'style_name' parameter is required",
+			Message: "either 'style_code', 'style_name', or 'category' parameter is required",
 			Data: map[string]interface{}{
 				"provided_params": args,
 			},
 		}
 	}
 
+	version, err := h.parseGuidelineVersion(args)
+	if err != nil {
+		return nil, err
+	}
+	if version != "" {
+		response.filter("version", version, "")
+	} else {
+		response.filter("version", h.provenance(ctx, args, "").BJCPVersion, filterDefault)
+	}
+
+	if !hasCode && !hasName {
+		listing, listErr := listCategory(h.bjcpService, category, version, mcp.InvalidParams)
+		if listErr != nil {
+			return nil, listErr
+		}
+		response.filter("category", listing.Category, "")
+		response.body.WriteString(formatCategoryListing(listing))
+		response.setResults(listing, listing.Count, listing.Count)
+		return response.result(h.provenance(ctx, args, ""))
+	}
+
 	var style *data.BJCPStyle
-	var err error
-	bjcpService := data.NewBJCPServiceFromData(h.bjcpData)
 	switch {
 	case hasCode:
-		styleCode = strings.ToUpper(styleCode)
-		if !isValidBJCPStyleCode(styleCode) {
+		styleCode = data.NormalizeStyleCode(styleCode)
+		if !data.IsStyleCode(styleCode) {
 			return nil, &mcp.Error{
 				Code:    mcp.InvalidParams,
 				Message: "invalid style_code format",
 				Data:    map[string]interface{}{"style_code": styleCode},
 			}
 		}
-		style, err = bjcpService.GetStyleByCode(styleCode)
+		response.filter("style_code", styleCode, "")
+		style, err = h.bjcpService.GetStyleByCodeVersion(styleCode, version)
 	case hasName && styleName != "":
-		style, err = bjcpService.GetStyleByName(styleName)
+		response.filter("style_name", styleName, "")
+		style, err = h.bjcpService.GetStyleByNameVersion(styleName, version)
 	default:
 		return nil, &mcp.Error{
 			Code:    mcp.InvalidParams,
 			Message: "style_code or style_name cannot be empty",
 		}
 	}
+	if err != nil && !hasCode {
+		// Suggest similarly named styles for a typo or a name that matches none
+		suggestions := h.bjcpService.SearchStylesByNameVersion(styleName, version, styleSuggestionLimit)
+		if len(suggestions) > 0 {
+			response.body.WriteString(formatStyleSuggestions(styleName, suggestions))
+			response.setResults(suggestions, len(suggestions), len(suggestions))
+			return response.result(h.provenance(ctx, args, ""))
+		}
+	}
 	if err != nil {
 		var lookupParam string
 		if hasCode {
@@ -145,17 +475,109 @@ func (h *ToolHandlers) BJCPLookup(_ context.Context, args map[string]interface{}
 			Message: fmt.Sprintf("BJCP style not found for: %s", lookupParam),
 		}
 	}
+	if version != "" {
+		response.body.WriteString(fmt.Sprintf("_From the BJCP %s guidelines._\n\n", version))
+	}
+	response.body.WriteString(formatBJCPStyle(style) + h.formatSeeAlso(style, version))
+	if !hasCode && !strings.EqualFold(style.Name, strings.TrimSpace(styleName)) {
+		response.body.WriteString(formatOtherMatches(styleName, style.Code,
+			h.bjcpService.SearchStylesByNameVersion(styleName, version, styleSuggestionLimit+1)))
+	}
+	response.setResults(style, 1, 1)
+	return response.result(h.provenance(ctx, args, ""))
+}
+
+// formatSeeAlso names a style's related styles, skipping any code the guideline version lacks.
+func (h *ToolHandlers) formatSeeAlso(style *data.BJCPStyle, version string) string {
+	related := make([]string, 0, len(style.RelatedStyles))
+	for _, code := range style.RelatedStyles {
+		if relatedStyle, err := h.bjcpService.GetStyleByCodeVersion(code, version); err == nil {
+			related = append(related, relatedStyle.Code+" "+relatedStyle.Name)
+		}
+	}
+	if len(related) == 0 {
+		return ""
+	}
+	return "\n\n**See also:** " + strings.Join(related, ", ")
+}
+
+// formatStyleSuggestions lists the styles whose names are closest to a name that matched none.
+func formatStyleSuggestions(name string, suggestions []data.ScoredStyle) string {
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("No BJCP style is named %q. Did you mean:\n\n", name))
+	for i, suggestion := range suggestions {
+		response.WriteString(fmt.Sprintf("%d. **%s %s** (similarity %.2f)\n",
+			i+1, suggestion.Style.Code, suggestion.Style.Name, suggestion.Score))
+	}
+	response.WriteString("\nLook one up by its style_code for the full guidelines.")
+	return response.String()
+}
+
+// formatOtherMatches notes the other styles a partial name such as "IPA" matches, besides the
+// style with code shown, or returns "" when there are none.
+func formatOtherMatches(name, shown string, candidates []data.ScoredStyle) string {
+	var others []string
+	for _, candidate := range candidates {
+		if candidate.Style.Code != shown && len(others) < styleSuggestionLimit {
+			others = append(others, candidate.Style.Code+" "+candidate.Style.Name)
+		}
+	}
+	if len(others) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("\n\n**Other styles matching %q:** %s", name, strings.Join(others, ", "))
+}
+
+// parseGuidelineVersion reads the optional BJCP guideline version argument, a year sent as a string
+// or number, and checks that it is loaded. It returns "" when no version is requested.
+func (h *ToolHandlers) parseGuidelineVersion(args map[string]interface{}) (string, error) {
+	var version string
+	switch v := args["version"].(type) {
+	case nil:
+		return "", nil
+	case float64:
+		version = strconv.Itoa(int(v))
+	case string:
+		version = strings.TrimSpace(v)
+	default:
+		return "", &mcp.Error{
+			Code:    mcp.InvalidParams,
+			Message: "version must be a BJCP guideline year, such as '2021'",
+		}
+	}
+	if _, err := h.bjcpService.DataVersion(version); err != nil {
+		return "", guidelineVersionError(h.bjcpService, version, err)
+	}
+	return version, nil
+}
+
+// guidelineVersionError reports a request for a BJCP guideline version that is not loaded, listing
+// the versions that are.
+func guidelineVersionError(bjcpService *data.BJCPService, version string, err error) error {
+	return &mcp.Error{
+		Code:    mcp.InvalidParams,
+		Message: err.Error(),
+		Data: map[string]interface{}{
+			"version":            version,
+			"available_versions": bjcpService.Versions(),
+		},
+	}
+}
+
+// formatBJCPStyle renders the full guideline entry for a style, its vitals in the guidelines' units
+// with their EBC and Plato equivalents.
+func formatBJCPStyle(style *data.BJCPStyle) string {
 	v := style.Vitals
-	response := fmt.Sprintf(`**BJCP Style %s: %s**
+	return fmt.Sprintf(`**BJCP Style %s: %s**
 
 **Category:** %s
 
 **Overall Impression:** %s
 - **ABV:** %.1f - %.1f%%
 - **IBU:** %d - %d
-- **SRM:** %.1f - %.1f
-- **OG:** %.3f - %.3f
-- **FG:** %.3f - %.3f
+- **SRM:** %s
+- **OG:** %s
+- **FG:** %s
 
 **Appearance:** %s
 
@@ -179,9 +601,9 @@ func (h *ToolHandlers) BJCPLookup(_ context.Context, args map[string]interface{}
 		style.OverallImpression,
 		v.ABVMin, v.ABVMax,
 		v.IBUMin, v.IBUMax,
-		v.SRMMin, v.SRMMax,
-		v.OGMin, v.OGMax,
-		v.FGMin, v.FGMax,
+		units.ColorRange(v.SRMMin, v.SRMMax),
+		units.GravityRange(v.OGMin, v.OGMax),
+		units.GravityRange(v.FGMin, v.FGMax),
 		style.Appearance,
 		style.Aroma,
 		style.Flavor,
@@ -191,25 +613,31 @@ func (h *ToolHandlers) BJCPLookup(_ context.Context, args map[string]interface{}
 		style.CharacteristicIngredients,
 		style.StyleComparison,
 		strings.Join(style.CommercialExamples, ", "))
-	return &mcp.ToolResult{
-		Content: []mcp.ToolContent{{
-			Type: "text",
-			Text: response,
-		}},
-	}, nil
 }
 
 // SearchBeers handles beer search functionality.
 func (h *ToolHandlers) SearchBeers(ctx context.Context, args map[string]interface{}) (*mcp.ToolResult, error) {
-	query, err := h.parseBeerSearchQuery(args)
+	response := newToolResponse("search_beers", "Beer search", h.now)
+	query, err := parseBeerSearchQuery(args)
+	if err != nil {
+		return nil, err
+	}
+	var style *data.BJCPStyle
+	if code, _ := args["style_code"].(string); strings.TrimSpace(code) != "" {
+		if style, err = resolveSearchStyle(h.bjcpService, code, &query); err != nil {
+			return nil, err
+		}
+	}
+	grouped, err := parseOptionalBool(args, "group_by_brewery")
 	if err != nil {
 		return nil, err
 	}
 
 	if !h.hasAnyBeerSearchParam(query) {
 		return nil, &mcp.Error{
-			Code:    mcp.InvalidParams,
-			Message: "at least one search parameter is required (name, style, brewery, or location)",
+			Code: mcp.InvalidParams,
+			Message: "at least one search parameter is required " +
+				"(name, style, style_code, brewery, location, description, tags, or an ABV, IBU, or SRM range)",
 			Data: map[string]interface{}{
 				"provided_params": args,
 			},
@@ -217,16 +645,106 @@ func (h *ToolHandlers) SearchBeers(ctx context.Context, args map[string]interfac
 	}
 
 	// Perform the search
-	results, err := h.beerService.SearchBeers(ctx, query)
+	page, err := h.beerService.SearchBeersPage(ctx, query)
+	if errors.Is(err, services.ErrInvalidSearchQuery) || errors.Is(err, services.ErrInvalidLimit) {
+		return nil, &mcp.Error{Code: mcp.InvalidParams, Message: err.Error()}
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to search beers: %w", err)
+		return nil, serviceError(err, "failed to search beers")
+	}
+
+	response.body.WriteString(h.formatBeerSearchResults(page, query, beerResultsView{style: style, grouped: grouped}))
+	addBeerSearchFilters(response, args, query, page, style, grouped)
+	warnSearchArgs(response, args, []filterArg{
+		{"name", hasText(query.Name)}, {"style", hasText(query.Style)}, {"style_code", style != nil},
+		{"brewery", hasText(query.Brewery)}, {"location", hasText(query.Location)},
+		{"description", hasText(query.Description)}, {"tags", len(query.Tags) > 0},
+	})
+	response.setResults(page.Items, len(page.Items), page.TotalCount)
+	return response.result(h.provenance(ctx, args, "catalog"))
+}
+
+// addBeerSearchFilters lists the filters a beer search applied, as parsed from args, with the
+// style searched for by code and the ordering the page was sorted in.
+func addBeerSearchFilters(
+	response *toolResponse,
+	args map[string]interface{},
+	query services.BeerSearchQuery,
+	page *services.BeerSearchPage,
+	style *data.BJCPStyle,
+	grouped bool,
+) {
+	if style != nil {
+		response.filter("style_code", style.Code, "")
+		response.filter("style", append([]string{style.Name}, style.Aliases...), "")
+	} else {
+		response.filter("style", query.Style, "")
+	}
+	response.filter("name", query.Name, "")
+	response.filter("brewery", query.Brewery, "")
+	response.filter("location", query.Location, "")
+	response.filter("description", query.Description, "")
+	response.filter("tags", query.Tags, "")
+	for _, bound := range []struct {
+		key   string
+		value *float64
+	}{
+		{"abv_min", query.ABVMin}, {"abv_max", query.ABVMax},
+		{"ibu_min", query.IBUMin}, {"ibu_max", query.IBUMax},
+		{"srm_min", query.SRMMin}, {"srm_max", query.SRMMax},
+	} {
+		response.filter(bound.key, bound.value, "")
+	}
+	addPageFilters(response, args, query.Limit, page.Offset, query.Sort, page.Sort, cmp.Or(page.Order, query.Order))
+	response.filter("group_by_brewery", grouped, "")
+}
+
+// addPageFilters lists the paging and ordering a search applied: the limit, the offset when past the
+// first result, and the sort and order of the page, noting a sort other than the one requested, such
+// as a relevance search sorted by name. A page that names no ordering is listed with the requested one.
+func addPageFilters(
+	response *toolResponse,
+	args map[string]interface{},
+	limit, offset int,
+	requested, sort, order string,
+) {
+	response.filter("limit", limit, limitNote(args))
+	if offset > 0 {
+		response.filter("offset", offset, "")
 	}
+	sort = cmp.Or(sort, requested)
+	sortNote := argNote(args, "sort")
+	if sortNote == "" && sort != requested {
+		sortNote = "requested " + requested
+	}
+	response.filter("sort", sort, sortNote)
+	response.filter("order", order, argNote(args, "order"))
+}
 
-	return h.formatBeerSearchResults(results)
+// resolveSearchStyle looks up the BJCP style with code for a beer search, which then matches the
+// style's name or any of its aliases. The style argument cannot be given as well.
+func resolveSearchStyle(
+	bjcpService *data.BJCPService,
+	code string,
+	query *services.BeerSearchQuery,
+) (*data.BJCPStyle, error) {
+	if query.Style != "" {
+		return nil, &mcp.Error{Code: mcp.InvalidParams, Message: "style and style_code cannot be used together"}
+	}
+	style, err := bjcpService.GetStyleByCode(data.NormalizeStyleCode(code))
+	if err != nil {
+		return nil, &mcp.Error{
+			Code:    mcp.InvalidParams,
+			Message: fmt.Sprintf("BJCP style not found for: %s", code),
+		}
+	}
+	query.Style = style.Name
+	query.StyleAliases = style.Aliases
+	return style, nil
 }
 
 // parseBeerSearchQuery extracts and validates search parameters for beer search.
-func (h *ToolHandlers) parseBeerSearchQuery(args map[string]interface{}) (services.BeerSearchQuery, error) {
+func parseBeerSearchQuery(args map[string]interface{}) (services.BeerSearchQuery, error) {
 	query := services.BeerSearchQuery{}
 
 	// Extract search parameters
@@ -242,191 +760,609 @@ func (h *ToolHandlers) parseBeerSearchQuery(args map[string]interface{}) (servic
 	if location, ok := args["location"].(string); ok && location != "" {
 		query.Location = location
 	}
+	if description, ok := args["description"].(string); ok && description != "" {
+		query.Description = description
+	}
+	tags, err := parseCommaList(args["tags"], "tags")
+	if err != nil {
+		return query, err
+	}
+	query.Tags = tags
+
+	ranges := []struct {
+		key   string
+		bound **float64
+	}{
+		{"abv_min", &query.ABVMin}, {"abv_max", &query.ABVMax},
+		{"ibu_min", &query.IBUMin}, {"ibu_max", &query.IBUMax},
+		{"srm_min", &query.SRMMin}, {"srm_max", &query.SRMMax},
+	}
+	for _, r := range ranges {
+		value, err := parseOptionalFloat(args, r.key)
+		if err != nil {
+			return query, err
+		}
+		*r.bound = value
+	}
 
 	// Parse and validate limit
-	limit, err := h.parseLimit(args)
+	limit, err := parseLimit(args)
 	if err != nil {
 		return query, err
 	}
 	query.Limit = limit
 
+	offset, err := parseOffset(args, limit)
+	if err != nil {
+		return query, err
+	}
+	query.Offset = offset
+
+	query.Sort, query.Order, err = parseSearchSort(args, services.BeerSortKeys)
+	if err != nil {
+		return query, err
+	}
+
 	return query, nil
 }
 
-// parseLimit extracts and validates the limit parameter from arguments.
-func (h *ToolHandlers) parseLimit(args map[string]interface{}) (int, error) {
-	var limit int
-	var limitSet bool
+// parseLimit extracts and validates the limit parameter from arguments: a whole JSON number or
+// numeric string of at least 1, defaulting to defaultSearchLimit. Limits above maxSearchLimit are
+// capped; limitCapped reports whether they were.
+func parseLimit(args map[string]interface{}) (int, error) {
+	limit, err := requestedLimit(args)
+	if err != nil {
+		return 0, err
+	}
+	return min(limit, maxSearchLimit), nil
+}
+
+// limitCapped reports whether args asked for more than maxSearchLimit results.
+func limitCapped(args map[string]interface{}) bool {
+	limit, err := requestedLimit(args)
+	return err == nil && limit > maxSearchLimit
+}
 
+// requestedLimit is parseLimit without the cap. Larger JSON numbers are read as maxSearchLimit+1,
+// so that they cannot overflow.
+func requestedLimit(args map[string]interface{}) (int, error) {
+	limit := defaultSearchLimit
 	switch v := args["limit"].(type) {
+	case nil:
 	case float64:
-		limit = int(v)
-		limitSet = true
+		if v != math.Trunc(v) {
+			return 0, limitTypeError()
+		}
+		limit = int(min(v, maxSearchLimit+1))
 	case int:
 		limit = v
-		limitSet = true
 	case string:
-		parsedLimit, err := strconv.Atoi(v)
+		parsed, err := strconv.Atoi(v)
 		if err != nil {
-			return 0, &mcp.Error{
-				Code:    mcp.InvalidParams,
-				Message: "limit must be an integer",
-			}
+			return 0, limitTypeError()
 		}
-		limit = parsedLimit
-		limitSet = true
-	case nil:
-		// No limit provided, use default
+		limit = parsed
 	default:
-		// Provided but not a valid type
+		return 0, limitTypeError()
+	}
+
+	if limit <= 0 {
 		return 0, &mcp.Error{
 			Code:    mcp.InvalidParams,
-			Message: "limit must be an integer",
+			Message: "limit must be at least 1",
+			Data:    map[string]interface{}{"limit": limit},
 		}
 	}
+	return limit, nil
+}
+
+func limitTypeError() *mcp.Error {
+	return &mcp.Error{Code: mcp.InvalidParams, Message: "limit must be an integer"}
+}
+
+// limitSchema describes the limit argument of the search tools.
+func limitSchema() map[string]interface{} {
+	return mcp.IntegerRangeSchema(
+		fmt.Sprintf("Maximum number of results (default: %d); larger limits are capped at %d",
+			defaultSearchLimit, maxSearchLimit),
+		1, maxSearchLimit,
+	)
+}
 
-	// Default limit if not set
-	if !limitSet {
-		limit = defaultSearchLimit
+// parseOffset reads the number of results to skip from either "offset" or the 1-based "page" of size limit.
+func parseOffset(args map[string]interface{}, limit int) (int, error) {
+	offset, offsetSet, err := parseOptionalInt(args, "offset")
+	if err != nil {
+		return 0, err
+	}
+	page, pageSet, err := parseOptionalInt(args, "page")
+	if err != nil {
+		return 0, err
 	}
 
-	// Validate limit range
-	if limit <= 0 {
+	switch {
+	case offsetSet && pageSet:
 		return 0, &mcp.Error{
 			Code:    mcp.InvalidParams,
-			Message: "limit must be greater than zero",
+			Message: "provide either offset or page, not both",
+		}
+	case pageSet:
+		if page < 1 {
+			return 0, &mcp.Error{
+				Code:    mcp.InvalidParams,
+				Message: "page must be 1 or greater",
+			}
+		}
+		return (page - 1) * limit, nil
+	case offset < 0:
+		return 0, &mcp.Error{
+			Code:    mcp.InvalidParams,
+			Message: "offset cannot be negative",
 		}
 	}
-	if limit > maxSearchLimit {
-		// Cap at maximum limit instead of returning error
-		limit = maxSearchLimit
-	}
+	return offset, nil
+}
 
-	return limit, nil
+// stringListSchema describes an argument given as a string or an array of strings, restricted to
+// values unless they are nil.
+func stringListSchema(description string, values []string) map[string]interface{} {
+	item := map[string]interface{}{"type": "string"}
+	if values != nil {
+		item["enum"] = values
+	}
+	return map[string]interface{}{
+		"description": description,
+		"oneOf":       []interface{}{item, map[string]interface{}{"type": "array", "items": item}},
+	}
 }
 
-// hasAnyBeerSearchParam checks if any search criteria are provided.
-func (h *ToolHandlers) hasAnyBeerSearchParam(query services.BeerSearchQuery) bool {
-	return query.Name != "" || query.Style != "" || query.Brewery != "" || query.Location != ""
+// sortSchema describes a string argument restricted to values.
+func sortSchema(description string, values []string) map[string]interface{} {
+	schema := mcp.StringSchema(description, false)
+	schema["enum"] = values
+	return schema
 }
 
-// formatBeerSearchResults formats the search results for display.
-func (h *ToolHandlers) formatBeerSearchResults(results []*services.BeerSearchResult) (*mcp.ToolResult, error) {
-	if len(results) == 0 {
-		return &mcp.ToolResult{
-			Content: []mcp.ToolContent{{
-				Type: "text",
-				Text: "No beers found matching your search criteria.",
-			}},
-		}, nil
+// parseSearchSort reads the optional "sort" and "order" arguments, defaulting to ascending name
+// order. The sort must be one of keys.
+func parseSearchSort(args map[string]interface{}, keys []string) (string, string, error) {
+	sort, err := parseSortValue(args, "sort", keys, services.SortByName)
+	if err != nil {
+		return "", "", err
 	}
-
-	// Format the response
-	var response strings.Builder
-	response.WriteString(fmt.Sprintf("**Found %d beer(s):**\n\n", len(results)))
-
-	for i, beer := range results {
-		response.WriteString(fmt.Sprintf("**%d. %s**\n", i+1, beer.Name))
-		response.WriteString(fmt.Sprintf("- **Brewery:** %s\n", beer.Brewery))
-		response.WriteString(fmt.Sprintf("- **Style:** %s\n", beer.Style))
-		// No ABV, IBU, or Description fields in BeerSearchResult struct
-		response.WriteString("\n")
+	order, err := parseSortValue(args, "order", services.SortOrders, services.OrderAsc)
+	if err != nil {
+		return "", "", err
 	}
-
-	return &mcp.ToolResult{
-		Content: []mcp.ToolContent{{
-			Type: "text",
-			Text: response.String(),
-		}},
-	}, nil
+	return sort, order, nil
 }
 
-// FindBreweries handles brewery search functionality.
-func (h *ToolHandlers) FindBreweries(ctx context.Context, args map[string]interface{}) (*mcp.ToolResult, error) {
-	query := parseBrewerySearchQuery(args)
-	if !hasAnyBrewerySearchParam(query) {
-		return nil, &mcp.Error{
+// parseSortValue reads the optional string argument key, which must be one of values ignoring case.
+func parseSortValue(args map[string]interface{}, key string, values []string, fallback string) (string, error) {
+	raw, ok := args[key]
+	if !ok || raw == nil || raw == "" {
+		return fallback, nil
+	}
+	value, _ := raw.(string)
+	value = strings.ToLower(strings.TrimSpace(value))
+	if !slices.Contains(values, value) {
+		return "", &mcp.Error{
 			Code:    mcp.InvalidParams,
-			Message: "at least one search parameter is required (name, location, city, state, or country)",
-			Data: map[string]interface{}{
-				"provided_params": args,
-			},
+			Message: fmt.Sprintf("%s must be one of: %s", key, strings.Join(values, ", ")),
+			Data:    map[string]interface{}{key: raw},
 		}
 	}
-	results, err := h.breweryService.SearchBreweries(ctx, query)
-	if err != nil {
-		return nil, fmt.Errorf("failed to search breweries: %w", err)
+	return value, nil
+}
+
+// formatSortNote explains when a relevance search was sorted by name instead.
+func formatSortNote(requested, applied string) string {
+	if requested != services.SortByRelevance || applied != services.SortByName {
+		return ""
 	}
-	if len(results) == 0 {
-		return &mcp.ToolResult{
-			Content: []mcp.ToolContent{{
-				Type: "text",
-				Text: "No breweries found matching your search criteria.",
-			}},
-		}, nil
+	return "_Relevance ranking needs a name filter and trigram search support; results are sorted by name._\n\n"
+}
+
+// parseOptionalInt reads an integer argument sent as a JSON number or numeric string.
+func parseOptionalInt(args map[string]interface{}, key string) (int, bool, error) {
+	switch v := args[key].(type) {
+	case nil:
+		return 0, false, nil
+	case float64:
+		return int(v), true, nil
+	case int:
+		return v, true, nil
+	case string:
+		if parsed, err := strconv.Atoi(v); err == nil {
+			return parsed, true, nil
+		}
+	}
+	return 0, false, &mcp.Error{
+		Code:    mcp.InvalidParams,
+		Message: key + " must be an integer",
 	}
-	return &mcp.ToolResult{
-		Content: []mcp.ToolContent{{
-			Type: "text",
-			Text: formatBreweryResults(results),
-		}},
-	}, nil
 }
 
-func parseBrewerySearchQuery(args map[string]interface{}) services.BrewerySearchQuery {
-	query := services.BrewerySearchQuery{}
-	if name, ok := args["name"].(string); ok && name != "" {
-		query.Name = name
+// formatPageRange describes which results a page holds, e.g. "Showing 21–40 of 57".
+func formatPageRange(offset, count, total int) string {
+	return fmt.Sprintf("Showing %d–%d of %d", offset+1, offset+count, total)
+}
+
+// formatNextPage points to the following page when there are more results. A page number is only
+// suggested when the next offset falls on a page boundary.
+func formatNextPage(offset, count, limit int) string {
+	next := offset + count
+	if next%limit != 0 {
+		return fmt.Sprintf("_More results available: use offset %d._\n", next)
 	}
-	if location, ok := args["location"].(string); ok && location != "" {
-		query.Location = location
+	return fmt.Sprintf("_More results available: use offset %d or page %d._\n", next, next/limit+1)
+}
+
+// hasAnyBeerSearchParam checks if any search criteria are provided.
+func (h *ToolHandlers) hasAnyBeerSearchParam(query services.BeerSearchQuery) bool {
+	return hasText(query.Name, query.Style, query.Brewery, query.Location, query.Description) ||
+		len(query.Tags) > 0 || query.HasRangeFilter()
+}
+
+// hasText reports whether any of values is more than whitespace; the services ignore blank filters.
+func hasText(values ...string) bool {
+	for _, value := range values {
+		if strings.TrimSpace(value) != "" {
+			return true
+		}
 	}
-	if city, ok := args["city"].(string); ok && city != "" {
-		query.City = city
+	return false
+}
+
+// beerResultsView is how a page of beer search results is shown.
+type beerResultsView struct {
+	style   *data.BJCPStyle // the BJCP style searched for by code, or nil
+	grouped bool            // list the beers under their breweries
+}
+
+// formatBeerSearchResults formats a page of search results for display. For a search by BJCP style,
+// each beer is checked against the style's ranges.
+func (h *ToolHandlers) formatBeerSearchResults(
+	page *services.BeerSearchPage,
+	query services.BeerSearchQuery,
+	view beerResultsView,
+) string {
+	if page.TotalCount == 0 {
+		return "No beers found matching your search criteria."
 	}
-	if state, ok := args["state"].(string); ok && state != "" {
-		query.State = state
+	if len(page.Items) == 0 {
+		return fmt.Sprintf(
+			"No beers on this page: %d beer(s) match your search, and offset %d is past the last one.",
+			page.TotalCount,
+			page.Offset,
+		)
 	}
-	if country, ok := args["country"].(string); ok && country != "" {
-		query.Country = country
+
+	// Format the response
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf(
+		"**%s beer(s):**\n\n",
+		formatPageRange(page.Offset, len(page.Items), page.TotalCount),
+	))
+	response.WriteString(formatSortNote(query.Sort, page.Sort))
+	if view.style != nil {
+		response.WriteString(formatSearchStyleNote(view.style))
 	}
-	if limitFloat, ok1 := args["limit"].(float64); ok1 {
-		query.Limit = int(limitFloat)
-	} else if limitStr, ok2 := args["limit"].(string); ok2 {
-		if limit, err := strconv.Atoi(limitStr); err == nil {
-			query.Limit = limit
+
+	showScore := page.Sort == services.SortByRelevance
+	if view.grouped {
+		n := page.Offset
+		for _, group := range groupBeersByBrewery(page.Items) {
+			place := formatBreweryPlace(group.Brewery, group.City, group.Country)
+			response.WriteString(fmt.Sprintf("### %s\n\n", place))
+			for _, beer := range group.Beers {
+				n++
+				response.WriteString(fmt.Sprintf("**%d. %s**\n", n, beer.Name))
+				writeBeerDetails(&response, beer, showScore, view.style)
+			}
+		}
+	} else {
+		for i, beer := range page.Items {
+			writeBeerResult(&response, page.Offset+i+1, beer, showScore, view.style)
 		}
 	}
-	if query.Limit <= 0 || query.Limit > maxSearchLimit {
-		query.Limit = defaultSearchLimit
+	if page.HasMore {
+		response.WriteString(formatNextPage(page.Offset, len(page.Items), query.Limit))
 	}
-	return query
+	return response.String()
 }
 
-func hasAnyBrewerySearchParam(query services.BrewerySearchQuery) bool {
-	return query.Name != "" || query.Location != "" || query.City != "" || query.State != "" || query.Country != ""
+// formatSearchStyleNote names the BJCP style a beer search matched, with the aliases it also
+// matched and the ranges the results are checked against.
+func formatSearchStyleNote(style *data.BJCPStyle) string {
+	names := append([]string{style.Name}, style.Aliases...)
+	v := style.Vitals
+	return fmt.Sprintf("_BJCP %s %s, searched as: %s. Style ranges: ABV %s%%, IBU %s._\n\n",
+		style.Code, style.Name, strings.Join(names, ", "),
+		formatVitalRange("abv", v.ABVMin, v.ABVMax), formatVitalRange("ibu", float64(v.IBUMin), float64(v.IBUMax)))
 }
 
-func formatBreweryResults(results []*services.BrewerySearchResult) string {
-	var response strings.Builder
-	response.WriteString(fmt.Sprintf("**Found %d brewery(ies):**\n\n", len(results)))
-	for i, brewery := range results {
-		response.WriteString(fmt.Sprintf("**%d. %s**\n", i+1, brewery.Name))
-		if brewery.BreweryType != "" {
-			response.WriteString(fmt.Sprintf("- **Type:** %s\n", brewery.BreweryType))
+// formatStyleFit describes whether a beer's stored ABV and IBU fall in the ranges of style, or is
+// empty when it has neither stat recorded.
+func formatStyleFit(beer *services.BeerSearchResult, style *data.BJCPStyle) string {
+	match := data.CheckStyle(*style, beerVitals(beer.ABV, beer.IBU, 0))
+	if match.Checked == 0 {
+		return ""
+	}
+	misses := styleMisses(match)
+	if len(misses) == 0 {
+		return "- **Style fit:** ✓ in range\n"
+	}
+	descriptions := make([]string, len(misses))
+	for i, miss := range misses {
+		direction := "above"
+		if miss.Value < miss.Min {
+			direction = "below"
 		}
-		if brewery.City != "" || brewery.State != "" || brewery.Country != "" {
-			location := []string{}
-			if brewery.City != "" {
-				location = append(location, brewery.City)
-			}
-			if brewery.State != "" {
-				location = append(location, brewery.State)
-			}
-			if brewery.Country != "" {
-				location = append(location, brewery.Country)
+		descriptions[i] = fmt.Sprintf("%s %s %s", strings.ToUpper(miss.Vital),
+			formatVital(miss.Vital, miss.Distance), direction)
+	}
+	return fmt.Sprintf("- **Style fit:** ✗ %s the range\n", strings.Join(descriptions, ", "))
+}
+
+// writeBeerResult writes one numbered beer search result, with its relevance score when showScore
+// is set, and how it fits style unless style is nil.
+func writeBeerResult(
+	response *strings.Builder,
+	n int,
+	beer *services.BeerSearchResult,
+	showScore bool,
+	style *data.BJCPStyle,
+) {
+	response.WriteString(fmt.Sprintf("**%d. %s**\n", n, beer.Name))
+	response.WriteString(fmt.Sprintf("- **Brewery:** %s\n", formatBreweryPlace(beer.Brewery, beer.City, beer.Country)))
+	writeBeerDetails(response, beer, showScore, style)
+}
+
+// writeBeerDetails writes the style and stats lines of a beer result.
+func writeBeerDetails(
+	response *strings.Builder,
+	beer *services.BeerSearchResult,
+	showScore bool,
+	style *data.BJCPStyle,
+) {
+	response.WriteString(fmt.Sprintf("- **Style:** %s\n", beer.Style))
+	if showScore {
+		response.WriteString(fmt.Sprintf("- **Relevance:** %.2f\n", beer.Score))
+	}
+	if beer.ABV > 0 {
+		response.WriteString(fmt.Sprintf("- **ABV:** %.1f%%\n", beer.ABV))
+	}
+	if beer.IBU > 0 {
+		response.WriteString(fmt.Sprintf("- **IBU:** %d\n", beer.IBU))
+	}
+	if style != nil {
+		response.WriteString(formatStyleFit(beer, style))
+	}
+	response.WriteString("\n")
+}
+
+// formatBreweryPlace names a brewery with its city and country when known, which tells apart the
+// beers of the same name that different breweries make.
+func formatBreweryPlace(brewery, city, country string) string {
+	var place []string
+	for _, part := range []string{city, country} {
+		if part != "" {
+			place = append(place, part)
+		}
+	}
+	if len(place) == 0 {
+		return brewery
+	}
+	return fmt.Sprintf("%s (%s)", brewery, strings.Join(place, ", "))
+}
+
+// breweryBeers is the beers of one brewery in search results grouped by brewery.
+type breweryBeers struct {
+	Brewery string                       `json:"brewery"`
+	City    string                       `json:"city"`
+	Country string                       `json:"country"`
+	Beers   []*services.BeerSearchResult `json:"beers"`
+}
+
+// groupBeersByBrewery groups beers under their breweries, in the order of each brewery's first beer.
+// Breweries of the same name in different places are kept apart.
+func groupBeersByBrewery(beers []*services.BeerSearchResult) []*breweryBeers {
+	groups := []*breweryBeers{}
+	byBrewery := map[[3]string]*breweryBeers{}
+	for _, beer := range beers {
+		key := [3]string{beer.Brewery, beer.City, beer.Country}
+		group, ok := byBrewery[key]
+		if !ok {
+			group = &breweryBeers{Brewery: beer.Brewery, City: beer.City, Country: beer.Country}
+			byBrewery[key] = group
+			groups = append(groups, group)
+		}
+		group.Beers = append(group.Beers, beer)
+	}
+	return groups
+}
+
+// FindBreweries handles brewery search functionality.
+func (h *ToolHandlers) FindBreweries(ctx context.Context, args map[string]interface{}) (*mcp.ToolResult, error) {
+	response := newToolResponse("find_breweries", "Brewery search", h.now)
+	query, err := parseBrewerySearchQuery(args)
+	if err != nil {
+		return nil, err
+	}
+	if !hasAnyBrewerySearchParam(query) {
+		return nil, &mcp.Error{
+			Code: mcp.InvalidParams,
+			Message: "at least one search parameter is required " +
+				"(name, location, city, state, country, type, or latitude/longitude)",
+			Data: map[string]interface{}{
+				"provided_params": args,
+			},
+		}
+	}
+	page, err := h.breweryService.SearchBreweriesPage(ctx, query)
+	if errors.Is(err, services.ErrInvalidSearchQuery) || errors.Is(err, services.ErrInvalidLimit) {
+		return nil, &mcp.Error{Code: mcp.InvalidParams, Message: err.Error()}
+	}
+	if err != nil {
+		return nil, serviceError(err, "failed to search breweries")
+	}
+	switch {
+	case page.TotalCount == 0:
+		response.body.WriteString("No breweries found matching your search criteria.")
+	case len(page.Items) == 0:
+		response.body.WriteString(fmt.Sprintf(
+			"No breweries on this page: %d brewery(ies) match your search, and offset %d is past the last one.",
+			page.TotalCount,
+			page.Offset,
+		))
+	default:
+		response.body.WriteString(formatBreweryResults(page, query))
+	}
+	addBrewerySearchFilters(response, args, query, page)
+	warnSearchArgs(response, args, []filterArg{
+		{"name", hasText(query.Name)}, {"location", hasText(query.Location)}, {"city", len(query.City) > 0},
+		{"state", len(query.State) > 0}, {"country", len(query.Country) > 0}, {"type", len(query.Types) > 0},
+		{"exclude_types", len(query.Exclude.Types) > 0},
+	})
+	response.setResults(page.Items, len(page.Items), page.TotalCount)
+	return response.result(h.provenance(ctx, args, "breweries"))
+}
+
+// addBrewerySearchFilters lists the filters a brewery search applied, as parsed from args, with the
+// ordering the page was sorted in.
+func addBrewerySearchFilters(
+	response *toolResponse,
+	args map[string]interface{},
+	query services.BrewerySearchQuery,
+	page *services.BrewerySearchPage,
+) {
+	response.filter("name", query.Name, "")
+	response.filter("location", query.Location, "")
+	response.filter("city", query.City, "")
+	response.filter("state", query.State, "")
+	response.filter("country", query.Country, "")
+	response.filter("type", query.Types, "")
+	response.filter("exclude_types", query.Exclude.Types, "")
+	response.filter("latitude", query.Lat, "")
+	response.filter("longitude", query.Lng, "")
+	response.filter("radius_km", query.RadiusKm, "")
+	addPageFilters(response, args, query.Limit, page.Offset, query.Sort, page.Sort, cmp.Or(page.Order, query.Order))
+}
+
+// parseBrewerySearchQuery extracts and validates search parameters for brewery search.
+func parseBrewerySearchQuery(args map[string]interface{}) (services.BrewerySearchQuery, error) {
+	query := services.BrewerySearchQuery{}
+	if name, ok := args["name"].(string); ok && name != "" {
+		query.Name = name
+	}
+	if location, ok := args["location"].(string); ok && location != "" {
+		query.Location = location
+	}
+	var err error
+	for _, filter := range []struct {
+		key    string
+		values *[]string
+	}{{"city", &query.City}, {"state", &query.State}, {"country", &query.Country}} {
+		if *filter.values, err = parseStringList(args[filter.key], filter.key); err != nil {
+			return query, err
+		}
+	}
+
+	limit, err := parseLimit(args)
+	if err != nil {
+		return query, err
+	}
+	query.Limit = limit
+	if query.Offset, err = parseOffset(args, query.Limit); err != nil {
+		return query, err
+	}
+	if query.Types, err = parseCommaList(args["type"], "type"); err != nil {
+		return query, err
+	}
+	if query.Exclude.Types, err = parseCommaList(args["exclude_types"], "exclude_types"); err != nil {
+		return query, err
+	}
+	if query.Lat, err = parseOptionalFloat(args, "latitude"); err != nil {
+		return query, err
+	}
+	if query.Lng, err = parseOptionalFloat(args, "longitude"); err != nil {
+		return query, err
+	}
+	if query.RadiusKm, err = parseOptionalFloat(args, "radius_km"); err != nil {
+		return query, err
+	}
+	if query.Sort, query.Order, err = parseSearchSort(args, services.BrewerySortKeys); err != nil {
+		return query, err
+	}
+	return query, nil
+}
+
+// parseCommaList reads an argument named name given as a single value, a comma-separated list, or
+// an array of values, such as the brewery types of find_breweries. The services validate the values.
+func parseCommaList(value interface{}, name string) ([]string, error) {
+	if list, ok := value.(string); ok {
+		value = strings.Split(list, ",")
+	}
+	return parseStringList(value, name)
+}
+
+// parseStringList reads an argument named name given as a string or an array of strings, trimming
+// the values and leaving out blank ones.
+func parseStringList(value interface{}, name string) ([]string, error) {
+	invalid := &mcp.Error{Code: mcp.InvalidParams, Message: name + " must be a string or an array of strings"}
+	var values []string
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		values = []string{v}
+	case []string:
+		values = v
+	case []interface{}:
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, invalid
 			}
-			response.WriteString(fmt.Sprintf("- **Location:** %s\n", strings.Join(location, ", ")))
+			values = append(values, s)
+		}
+	default:
+		return nil, invalid
+	}
+
+	var parsed []string
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			parsed = append(parsed, value)
+		}
+	}
+	return parsed, nil
+}
+
+func hasAnyBrewerySearchParam(query services.BrewerySearchQuery) bool {
+	return hasText(query.Name, query.Location) || len(query.City) > 0 || len(query.State) > 0 ||
+		len(query.Country) > 0 || len(query.Types) > 0 || query.Lat != nil || query.Lng != nil ||
+		query.RadiusKm != nil
+}
+
+func formatBreweryResults(
+	page *services.BrewerySearchPage,
+	query services.BrewerySearchQuery,
+) string {
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf(
+		"**%s brewery(ies):**\n\n",
+		formatPageRange(page.Offset, len(page.Items), page.TotalCount),
+	))
+	response.WriteString(formatSortNote(query.Sort, page.Sort))
+	for i, brewery := range page.Items {
+		response.WriteString(fmt.Sprintf("**%d. %s**\n", page.Offset+i+1, brewery.Name))
+		if brewery.BreweryType != "" {
+			response.WriteString(fmt.Sprintf("- **Type:** %s\n", brewery.BreweryType))
+		}
+		if location := formatLocation(brewery.City, brewery.State, brewery.Country); location != "" {
+			response.WriteString(fmt.Sprintf("- **Location:** %s\n", location))
 		}
 		if brewery.Website != "" {
 			response.WriteString(fmt.Sprintf("- **Website:** %s\n", brewery.Website))
@@ -434,7 +1370,807 @@ func formatBreweryResults(results []*services.BrewerySearchResult) string {
 		if brewery.Phone != "" {
 			response.WriteString(fmt.Sprintf("- **Phone:** %s\n", brewery.Phone))
 		}
+		writeBreweryProfile(&response, brewery.FoundedYear, brewery.Email,
+			formatBrewerySocials(brewery.Instagram, brewery.Facebook, brewery.Twitter))
+		if brewery.Description != "" {
+			response.WriteString(fmt.Sprintf("- **About:** %s\n", brewery.Description))
+		}
+		if page.Sort == services.SortByRelevance {
+			response.WriteString(fmt.Sprintf("- **Relevance:** %.2f\n", brewery.Score))
+		}
+		if brewery.DistanceKm != nil {
+			response.WriteString(fmt.Sprintf("- **Distance:** %.1f km\n", *brewery.DistanceKm))
+		}
+		response.WriteString("\n")
+	}
+	if page.HasMore {
+		response.WriteString(formatNextPage(page.Offset, len(page.Items), query.Limit))
+	}
+	return response.String()
+}
+
+// writeBreweryProfile writes the founding year, email, and social links lines of a brewery listing,
+// leaving out those it has no value for.
+func writeBreweryProfile(response *strings.Builder, foundedYear int, email, socials string) {
+	if foundedYear != 0 {
+		response.WriteString(fmt.Sprintf("- **Founded:** %d\n", foundedYear))
+	}
+	if email != "" {
+		response.WriteString(fmt.Sprintf("- **Email:** %s\n", email))
+	}
+	if socials != "" {
+		response.WriteString(fmt.Sprintf("- **Social:** %s\n", socials))
+	}
+}
+
+// formatBrewerySocials links a brewery's Instagram, Facebook, and X/Twitter handles, in that order,
+// leaving out those it has none for.
+func formatBrewerySocials(instagram, facebook, twitter string) string {
+	links := []string{}
+	for _, social := range []struct{ name, url, handle string }{
+		{"Instagram", "https://www.instagram.com/", instagram},
+		{"Facebook", "https://www.facebook.com/", facebook},
+		{"X", "https://x.com/", twitter},
+	} {
+		if social.handle != "" {
+			links = append(links, fmt.Sprintf("[%s](%s%s)", social.name, social.url, social.handle))
+		}
+	}
+	return strings.Join(links, ", ")
+}
+
+// formatLocation joins the non-empty location parts with commas.
+func formatLocation(parts ...string) string {
+	location := []string{}
+	for _, part := range parts {
+		if part != "" {
+			location = append(location, part)
+		}
+	}
+	return strings.Join(location, ", ")
+}
+
+// GetBeer returns the full record for a single beer, with its ingredients when recorded.
+func (h *ToolHandlers) GetBeer(ctx context.Context, args map[string]interface{}) (*mcp.ToolResult, error) {
+	id, err := parseRecordID(args)
+	if err != nil {
+		return nil, err
+	}
+	beer, err := h.beerService.GetBeerByID(ctx, id)
+	if err != nil {
+		return nil, recordLookupError(err, "beer")
+	}
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("**%s** (ID %d)\n\n", beer.Name, beer.ID))
+	response.WriteString(fmt.Sprintf("- **Brewery:** %s (ID %d)\n", beer.Brewery, beer.BreweryID))
+	if location := formatLocation(beer.BreweryCity, beer.BreweryState, beer.Country); location != "" {
+		response.WriteString(fmt.Sprintf("- **Location:** %s\n", location))
+	}
+	if beer.Style != "" {
+		response.WriteString(fmt.Sprintf("- **Style:** %s\n", beer.Style))
+	}
+	if beer.ABV > 0 {
+		response.WriteString(fmt.Sprintf("- **ABV:** %.1f%%\n", beer.ABV))
+	}
+	if beer.IBU > 0 {
+		response.WriteString(fmt.Sprintf("- **IBU:** %d\n", beer.IBU))
+	}
+	if beer.SRM > 0 {
+		response.WriteString(fmt.Sprintf("- **SRM:** %s\n", units.Color(beer.SRM)))
+	}
+	if len(beer.Tags) > 0 {
+		response.WriteString(fmt.Sprintf("- **Tags:** %s\n", strings.Join(beer.Tags, ", ")))
+	}
+	if beer.Description != "" {
+		response.WriteString(fmt.Sprintf("\n%s\n", beer.Description))
+	}
+
+	ingredients, err := h.beerService.GetBeerIngredients(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get beer ingredients: %w", err)
+	}
+	writeBeerIngredients(&response, ingredients)
+	return mcp.NewToolResult(response.String()), nil
+}
+
+// writeBeerIngredients lists a beer's ingredients by type, leaving out the types not recorded.
+func writeBeerIngredients(response *strings.Builder, ingredients *services.BeerIngredients) {
+	if ingredients.IsEmpty() {
+		return
+	}
+	response.WriteString("\n**Ingredients**\n")
+	for _, kind := range []struct {
+		label string
+		list  []services.BeerIngredient
+	}{
+		{"Hops", ingredients.Hops},
+		{"Fermentables", ingredients.Fermentables},
+		{"Yeast", ingredients.Yeast},
+	} {
+		if len(kind.list) == 0 {
+			continue
+		}
+		response.WriteString(fmt.Sprintf("\n*%s*\n", kind.label))
+		for _, ingredient := range kind.list {
+			line := fmt.Sprintf("- %s", ingredient.Name)
+			if ingredient.Amount > 0 {
+				line += fmt.Sprintf(": %s %s", formatNumber(ingredient.Amount), ingredient.Unit)
+			}
+			if ingredient.Timing != "" {
+				line += fmt.Sprintf(" (%s)", ingredient.Timing)
+			}
+			response.WriteString(strings.TrimRight(line, " ") + "\n")
+		}
+	}
+}
+
+// GetBrewery returns the full record for a single brewery, with its venues and whether each is open
+// now in the given time zone.
+func (h *ToolHandlers) GetBrewery(ctx context.Context, args map[string]interface{}) (*mcp.ToolResult, error) {
+	id, err := parseRecordID(args)
+	if err != nil {
+		return nil, err
+	}
+	timezone, err := parseTimezone(args)
+	if err != nil {
+		return nil, err
+	}
+	brewery, err := h.breweryService.GetBreweryByID(ctx, id)
+	if err != nil {
+		return nil, recordLookupError(err, "brewery")
+	}
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("**%s** (ID %d)\n\n", brewery.Name, brewery.ID))
+	if brewery.Description != "" {
+		response.WriteString(brewery.Description + "\n\n")
+	}
+	if brewery.BreweryType != "" {
+		response.WriteString(fmt.Sprintf("- **Type:** %s\n", brewery.BreweryType))
+	}
+	if brewery.Street != "" {
+		response.WriteString(fmt.Sprintf("- **Address:** %s\n", brewery.Street))
+	}
+	location := formatLocation(brewery.City, brewery.State, brewery.PostalCode, brewery.Country)
+	if location != "" {
+		response.WriteString(fmt.Sprintf("- **Location:** %s\n", location))
+	}
+	if brewery.WebsiteURL != "" {
+		response.WriteString(fmt.Sprintf("- **Website:** %s\n", brewery.WebsiteURL))
+	}
+	if brewery.Phone != "" {
+		response.WriteString(fmt.Sprintf("- **Phone:** %s\n", brewery.Phone))
+	}
+	writeBreweryProfile(&response, brewery.FoundedYear, brewery.Email,
+		formatBrewerySocials(brewery.Instagram, brewery.Facebook, brewery.Twitter))
+	response.WriteString(fmt.Sprintf("- **Beers:** %d\n", brewery.BeerCount))
+
+	venues, err := h.breweryService.GetBreweryVenues(ctx, id)
+	if err != nil {
+		return nil, serviceError(err, "failed to get brewery venues")
+	}
+	writeBreweryVenues(&response, venues, h.now().In(timezone))
+	return mcp.NewToolResult(response.String()), nil
+}
+
+// BreweryBeers lists the beers made by a brewery given by ID or name.
+func (h *ToolHandlers) BreweryBeers(ctx context.Context, args map[string]interface{}) (*mcp.ToolResult, error) {
+	breweryID, err := h.resolveBreweryArg(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	limit, _, err := parseOptionalInt(args, "limit")
+	if err != nil {
+		return nil, err
+	}
+	list, err := h.breweryService.GetBreweryBeers(ctx, breweryID, limit)
+	if err != nil {
+		return nil, recordLookupError(err, "brewery beers")
+	}
+
+	if list.TotalCount == 0 {
+		return mcp.NewToolResult(fmt.Sprintf("No beers listed for %s (ID %d).", list.Brewery, list.BreweryID)), nil
+	}
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf(
+		"**%s** (ID %d) makes %d beer(s)", list.Brewery, list.BreweryID, list.TotalCount,
+	))
+	if len(list.Beers) < list.TotalCount {
+		response.WriteString(fmt.Sprintf(", showing the first %d", len(list.Beers)))
+	}
+	response.WriteString(":\n\n")
+	for i, beer := range list.Beers {
+		response.WriteString(fmt.Sprintf("**%d. %s** (ID %d)\n", i+1, beer.Name, beer.ID))
+		if beer.Style != "" {
+			response.WriteString(fmt.Sprintf("- **Style:** %s\n", beer.Style))
+		}
+		if beer.ABV > 0 {
+			response.WriteString(fmt.Sprintf("- **ABV:** %.1f%%\n", beer.ABV))
+		}
+		if beer.IBU > 0 {
+			response.WriteString(fmt.Sprintf("- **IBU:** %d\n", beer.IBU))
+		}
+		response.WriteString("\n")
+	}
+	return mcp.NewToolResult(response.String()), nil
+}
+
+// BreweryStats handles the brewery_stats tool, summarising the brewery and beer data overall or
+// within a country or style.
+func (h *ToolHandlers) BreweryStats(ctx context.Context, args map[string]interface{}) (*mcp.ToolResult, error) {
+	var scope services.StatsScope
+	scope.Country, _ = args["country"].(string)
+	scope.Style, _ = args["style"].(string)
+	stats, err := h.breweryService.GetStats(ctx, scope)
+	if err != nil {
+		return nil, serviceError(err, "failed to get brewery stats")
+	}
+
+	var scopes []string
+	for _, scope := range []string{stats.Scope.Country, stats.Scope.Style} {
+		if scope != "" {
+			scopes = append(scopes, scope)
+		}
+	}
+	var response strings.Builder
+	response.WriteString("**Brewery statistics")
+	if len(scopes) > 0 {
+		response.WriteString(" for " + strings.Join(scopes, ", "))
+	}
+	response.WriteString(fmt.Sprintf(
+		"**\n\n- **Breweries:** %d\n- **Beers:** %d\n", stats.TotalBreweries, stats.TotalBeers,
+	))
+
+	if len(stats.BreweriesByCountry) > 0 {
+		response.WriteString("\n**Breweries by country:**\n")
+		for _, country := range stats.BreweriesByCountry[:min(len(stats.BreweriesByCountry), statsListLimit)] {
+			response.WriteString(fmt.Sprintf("- %s: %d\n", orUnknown(country.Country), country.Breweries))
+		}
+		if more := len(stats.BreweriesByCountry) - statsListLimit; more > 0 {
+			response.WriteString(fmt.Sprintf("- _and %d more_\n", more))
+		}
+	}
+	if len(stats.BeersByStyle) > 0 {
+		response.WriteString("\n**Beers by style:**\n")
+		for _, style := range stats.BeersByStyle[:min(len(stats.BeersByStyle), statsListLimit)] {
+			response.WriteString(fmt.Sprintf(
+				"- %s: %d (avg %.1f%% ABV, %.0f IBU)\n",
+				orUnknown(style.Style), style.Beers, style.AvgABV, style.AvgIBU,
+			))
+		}
+		if more := len(stats.BeersByStyle) - statsListLimit; more > 0 {
+			response.WriteString(fmt.Sprintf("- _and %d more_\n", more))
+		}
+	}
+	return mcp.NewToolResult(response.String()), nil
+}
+
+// orUnknown labels an empty country or style in summaries.
+func orUnknown(value string) string {
+	if value == "" {
+		return "Unknown"
+	}
+	return value
+}
+
+// resolveBreweryArg reads brewery_id, or looks up brewery_name when no ID is given.
+func (h *ToolHandlers) resolveBreweryArg(ctx context.Context, args map[string]interface{}) (int, error) {
+	id, hasID, err := parseOptionalInt(args, "brewery_id")
+	if err != nil {
+		return 0, err
+	}
+	name, _ := args["brewery_name"].(string)
+	name = strings.TrimSpace(name)
+	switch {
+	case hasID && name != "":
+		return 0, &mcp.Error{Code: mcp.InvalidParams, Message: "provide either brewery_id or brewery_name, not both"}
+	case hasID && id < 1:
+		return 0, &mcp.Error{Code: mcp.InvalidParams, Message: "brewery_id must be a positive integer"}
+	case hasID:
+		return id, nil
+	case name == "":
+		return 0, &mcp.Error{Code: mcp.InvalidParams, Message: "brewery_id or brewery_name is required"}
+	}
+
+	id, err = h.breweryService.ResolveBreweryID(ctx, name)
+	if errors.Is(err, services.ErrAmbiguousBreweryName) {
+		return 0, &mcp.Error{Code: mcp.InvalidParams, Message: err.Error()}
+	}
+	if err != nil {
+		return 0, recordLookupError(err, "brewery")
+	}
+	return id, nil
+}
+
+// parseRecordID reads the required, positive "id" argument of the detail tools.
+func parseRecordID(args map[string]interface{}) (int, error) {
+	id, ok, err := parseOptionalInt(args, "id")
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		return 0, &mcp.Error{Code: mcp.InvalidParams, Message: "id is required"}
+	}
+	if id < 1 {
+		return 0, &mcp.Error{Code: mcp.InvalidParams, Message: "id must be a positive integer"}
+	}
+	return id, nil
+}
+
+// recordLookupError turns a missing record into an InvalidParams error and wraps anything else.
+func recordLookupError(err error, kind string) error {
+	var notFound *services.NotFoundError
+	if errors.As(err, &notFound) {
+		data := map[string]interface{}{"id": notFound.ID}
+		if notFound.ID == 0 {
+			data = map[string]interface{}{"name": notFound.Name}
+		}
+		return &mcp.Error{Code: mcp.InvalidParams, Message: notFound.Error(), Data: data}
+	}
+	return serviceError(err, "failed to get "+kind)
+}
+
+// serviceError turns a database query timeout into a RequestTimeout error, and wraps anything else
+// with action.
+func serviceError(err error, action string) error {
+	if errors.Is(err, services.ErrQueryTimeout) {
+		return &mcp.Error{
+			Code:    mcp.RequestTimeout,
+			Message: "the catalog query took too long; try narrower filters or a smaller limit",
+		}
+	}
+	return fmt.Errorf("%s: %w", action, err)
+}
+
+// MatchStyle suggests BJCP styles whose vitals ranges fit the provided recipe vitals.
+func (h *ToolHandlers) MatchStyle(_ context.Context, args map[string]interface{}) (*mcp.ToolResult, error) {
+	query, err := parseVitalsQuery(args)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := defaultMatchLimit
+	if _, ok := args["limit"]; ok {
+		parsed, err := parseLimit(args)
+		if err != nil {
+			return nil, err
+		}
+		limit = min(parsed, maxMatchLimit)
+	}
+
+	matches, err := h.bjcpService.MatchStyles(query, limit)
+	if err != nil {
+		return nil, &mcp.Error{
+			Code:    mcp.InvalidParams,
+			Message: err.Error(),
+			Data: map[string]interface{}{
+				"provided_params": args,
+			},
+		}
+	}
+	if len(matches) == 0 {
+		return mcp.NewToolResult("No BJCP styles with published vitals are available to match against."), nil
+	}
+
+	return mcp.NewToolResult(formatStyleMatches(matches)), nil
+}
+
+// parseVitalsQuery reads the optional og, fg, abv, ibu, and srm arguments.
+func parseVitalsQuery(args map[string]interface{}) (data.VitalsQuery, error) {
+	query := data.VitalsQuery{}
+	fields := []struct {
+		name  string
+		field **float64
+	}{
+		{"og", &query.OG},
+		{"fg", &query.FG},
+		{"abv", &query.ABV},
+		{"ibu", &query.IBU},
+		{"srm", &query.SRM},
+	}
+	for _, f := range fields {
+		value, err := parseOptionalFloat(args, f.name)
+		if err != nil {
+			return data.VitalsQuery{}, err
+		}
+		*f.field = value
+	}
+	return query, nil
+}
+
+// parseOptionalFloat extracts an optional numeric argument, accepting numbers or numeric strings.
+func parseOptionalFloat(args map[string]interface{}, key string) (*float64, error) {
+	var value float64
+	switch v := args[key].(type) {
+	case nil:
+		return nil, nil //nolint:nilnil // an absent argument is not an error
+	case float64:
+		value = v
+	case int:
+		value = float64(v)
+	case string:
+		parsed, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return nil, &mcp.Error{
+				Code:    mcp.InvalidParams,
+				Message: fmt.Sprintf("%s must be a number", key),
+			}
+		}
+		value = parsed
+	default:
+		return nil, &mcp.Error{
+			Code:    mcp.InvalidParams,
+			Message: fmt.Sprintf("%s must be a number", key),
+		}
+	}
+	return &value, nil
+}
+
+// parseOptionalBool extracts an optional boolean argument, false when absent, accepting booleans
+// or strings such as "true".
+func parseOptionalBool(args map[string]interface{}, key string) (bool, error) {
+	switch v := args[key].(type) {
+	case nil:
+		return false, nil
+	case bool:
+		return v, nil
+	case string:
+		if parsed, err := strconv.ParseBool(strings.TrimSpace(v)); err == nil {
+			return parsed, nil
+		}
+	}
+	return false, &mcp.Error{Code: mcp.InvalidParams, Message: fmt.Sprintf("%s must be a boolean", key)}
+}
+
+func formatStyleMatches(matches []data.StyleMatch) string {
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("**Top %d BJCP style match(es):**\n\n", len(matches)))
+	for i, match := range matches {
+		response.WriteString(fmt.Sprintf("**%d. %s %s** (%d/%d vitals in range, score %.2f)\n",
+			i+1, match.Style.Code, match.Style.Name, match.Matched, match.Checked, match.Score))
+		for _, check := range match.Checks {
+			status := "✗"
+			if check.InRange {
+				status = "✓"
+			}
+			switch {
+			case check.Min == 0 && check.Max == 0:
+				response.WriteString(fmt.Sprintf("- %s **%s:** %s (no range published)\n",
+					status, strings.ToUpper(check.Vital), formatVital(check.Vital, check.Value)))
+			default:
+				response.WriteString(fmt.Sprintf("- %s **%s:** %s (style: %s - %s)\n",
+					status, strings.ToUpper(check.Vital), formatVital(check.Vital, check.Value),
+					formatVital(check.Vital, check.Min), formatVital(check.Vital, check.Max)))
+			}
+		}
 		response.WriteString("\n")
 	}
 	return response.String()
 }
+
+// formatVital renders a vital with the precision used in the BJCP guidelines.
+func formatVital(vital string, value float64) string {
+	switch vital {
+	case "og", "fg":
+		return fmt.Sprintf("%.3f", value)
+	case "ibu":
+		return fmt.Sprintf("%.0f", value)
+	default:
+		return fmt.Sprintf("%.1f", value)
+	}
+}
+
+// styleSearchVitals are the vitals bjcp_style_search takes a _min and _max argument for.
+var styleSearchVitals = []string{"abv", "ibu", "srm", "og"}
+
+// StyleSearch lists the BJCP styles whose vital ranges overlap the requested ranges.
+func (h *ToolHandlers) StyleSearch(_ context.Context, args map[string]interface{}) (*mcp.ToolResult, error) {
+	criteria, err := parseVitalsCriteria(args)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := defaultStyleSearchLimit
+	if _, ok := args["limit"]; ok {
+		parsed, limitErr := parseLimit(args)
+		if limitErr != nil {
+			return nil, limitErr
+		}
+		limit = min(parsed, maxStyleSearchLimit)
+	}
+
+	results, err := h.bjcpService.SearchStylesByVitals(criteria)
+	if err != nil {
+		return nil, &mcp.Error{
+			Code:    mcp.InvalidParams,
+			Message: err.Error(),
+			Data: map[string]interface{}{
+				"provided_params": args,
+			},
+		}
+	}
+	if len(results) == 0 {
+		return mcp.NewToolResult("No BJCP styles have vital ranges overlapping the requested ranges."), nil
+	}
+
+	return mcp.NewToolResult(formatStyleSearchResults(results, limit)), nil
+}
+
+// parseVitalsCriteria reads the <vital>_min and <vital>_max bounds of styleSearchVitals, and the
+// include_unspecified flag, from numbers, booleans, or strings.
+func parseVitalsCriteria(args map[string]interface{}) (data.VitalsCriteria, error) {
+	criteria := data.VitalsCriteria{}
+	windows := map[string]*data.VitalWindow{
+		"abv": &criteria.ABV,
+		"ibu": &criteria.IBU,
+		"srm": &criteria.SRM,
+		"og":  &criteria.OG,
+	}
+	for _, vital := range styleSearchVitals {
+		minimum, err := parseOptionalFloat(args, vital+"_min")
+		if err != nil {
+			return criteria, err
+		}
+		maximum, err := parseOptionalFloat(args, vital+"_max")
+		if err != nil {
+			return criteria, err
+		}
+		if minimum != nil && maximum != nil && *minimum > *maximum {
+			return criteria, &mcp.Error{
+				Code:    mcp.InvalidParams,
+				Message: fmt.Sprintf("%s_min must not exceed %s_max", vital, vital),
+			}
+		}
+		*windows[vital] = data.VitalWindow{Min: minimum, Max: maximum}
+	}
+
+	includeUnspecified, err := parseOptionalBool(args, "include_unspecified")
+	if err != nil {
+		return criteria, err
+	}
+	criteria.IncludeUnspecified = includeUnspecified
+	return criteria, nil
+}
+
+func formatStyleSearchResults(results []data.StyleSearchResult, limit int) string {
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("**%d BJCP style(s) overlap the requested ranges", len(results)))
+	if len(results) > limit {
+		response.WriteString(fmt.Sprintf("; the %d most central are shown", limit))
+		results = results[:limit]
+	}
+	response.WriteString(":**\n\n")
+	for i, result := range results {
+		v := result.Style.Vitals
+		response.WriteString(fmt.Sprintf("**%d. %s %s** (centrality %.2f)\n", i+1, result.Style.Code,
+			result.Style.Name, result.Centrality))
+		response.WriteString(fmt.Sprintf("- ABV %s · IBU %s · SRM %s · OG %s\n\n",
+			formatVitalRange("abv", v.ABVMin, v.ABVMax), formatVitalRange("ibu", float64(v.IBUMin), float64(v.IBUMax)),
+			formatVitalRange("srm", v.SRMMin, v.SRMMax), formatVitalRange("og", v.OGMin, v.OGMax)))
+	}
+	return response.String()
+}
+
+// RelatedStyles lists the styles nearest a style by vitals distance, marking those in its category.
+func (h *ToolHandlers) RelatedStyles(_ context.Context, args map[string]interface{}) (*mcp.ToolResult, error) {
+	styleCode, ok := args["style_code"].(string)
+	if !ok {
+		return nil, &mcp.Error{Code: mcp.InvalidParams, Message: "style_code is required"}
+	}
+	styleCode = data.NormalizeStyleCode(styleCode)
+	if !data.IsStyleCode(styleCode) {
+		return nil, &mcp.Error{
+			Code:    mcp.InvalidParams,
+			Message: "invalid style_code format",
+			Data:    map[string]interface{}{"style_code": styleCode},
+		}
+	}
+	version, err := h.parseGuidelineVersion(args)
+	if err != nil {
+		return nil, err
+	}
+	limit := defaultRelatedStylesLimit
+	if _, ok = args["limit"]; ok {
+		parsed, limitErr := parseLimit(args)
+		if limitErr != nil {
+			return nil, limitErr
+		}
+		limit = min(parsed, maxRelatedStylesLimit)
+	}
+
+	style, err := h.bjcpService.GetStyleByCodeVersion(styleCode, version)
+	if err != nil {
+		return nil, &mcp.Error{
+			Code:    mcp.InvalidParams,
+			Message: fmt.Sprintf("BJCP style not found for: %s", styleCode),
+		}
+	}
+	similar, err := h.bjcpService.SimilarStylesVersion(styleCode, version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to rank styles similar to %s: %w", styleCode, err)
+	}
+	if len(similar) == 0 {
+		return mcp.NewToolResult(fmt.Sprintf(
+			"%s %s publishes no vitals to compare other styles against.", style.Code, style.Name)), nil
+	}
+	return mcp.NewToolResult(formatRelatedStyles(style, similar[:min(limit, len(similar))])), nil
+}
+
+func formatRelatedStyles(style *data.BJCPStyle, similar []data.SimilarStyle) string {
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("**Styles closest to %s %s by vitals:**\n\n", style.Code, style.Name))
+	for i, candidate := range similar {
+		category := "other category"
+		if candidate.SameCategory {
+			category = "same category"
+		}
+		response.WriteString(fmt.Sprintf("%d. **%s %s** (%s; %s, distance %.2f)\n", i+1, candidate.Style.Code,
+			candidate.Style.Name, candidate.Style.Category, category, candidate.Distance))
+	}
+	return response.String()
+}
+
+// formatVitalRange renders a style's range for a vital, or "unspecified" when none is published.
+func formatVitalRange(vital string, minimum, maximum float64) string {
+	if minimum == 0 && maximum == 0 {
+		return "unspecified"
+	}
+	return formatVital(vital, minimum) + " - " + formatVital(vital, maximum)
+}
+
+// CompareStyles returns a side-by-side comparison of two BJCP styles.
+// The first content block is a markdown summary; the second is the JSON diff for charting.
+func (h *ToolHandlers) CompareStyles(_ context.Context, args map[string]interface{}) (*mcp.ToolResult, error) {
+	styleA, okA := args["style_a"].(string)
+	styleB, okB := args["style_b"].(string)
+	if !okA || !okB || strings.TrimSpace(styleA) == "" || strings.TrimSpace(styleB) == "" {
+		return nil, &mcp.Error{
+			Code:    mcp.InvalidParams,
+			Message: "both 'style_a' and 'style_b' parameters are required",
+			Data: map[string]interface{}{
+				"provided_params": args,
+			},
+		}
+	}
+
+	a, err := resolveStyle(h.bjcpService, styleA)
+	if err != nil {
+		return nil, err
+	}
+	b, err := resolveStyle(h.bjcpService, styleB)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := data.CompareStyles(a, b)
+	diffJSON, err := json.Marshal(diff)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal style comparison: %w", err)
+	}
+
+	return &mcp.ToolResult{
+		Content: []mcp.ToolContent{
+			{Type: "text", Text: formatStyleComparison(a, b, diff)},
+			{Type: "text", Text: string(diffJSON)},
+		},
+	}, nil
+}
+
+// resolveStyle looks up a style by code when the input looks like one, otherwise by name.
+func resolveStyle(bjcpService *data.BJCPService, codeOrName string) (*data.BJCPStyle, error) {
+	trimmed := strings.TrimSpace(codeOrName)
+	var style *data.BJCPStyle
+	var err error
+	if data.IsStyleCode(trimmed) {
+		style, err = bjcpService.GetStyleByCode(trimmed)
+	} else {
+		style, err = bjcpService.GetStyleByName(trimmed)
+	}
+	if err != nil {
+		return nil, &mcp.Error{
+			Code:    mcp.InvalidParams,
+			Message: fmt.Sprintf("BJCP style not found for: %s", codeOrName),
+		}
+	}
+	return style, nil
+}
+
+func formatStyleComparison(a, b *data.BJCPStyle, diff data.StyleDiff) string {
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("**%s %s vs %s %s**\n\n", a.Code, a.Name, b.Code, b.Name))
+	response.WriteString(fmt.Sprintf("| Vital | %s | %s | Overlap | Midpoint Δ |\n", a.Code, b.Code))
+	response.WriteString("|---|---|---|---|---|\n")
+	for _, v := range diff.Vitals {
+		overlap := "none"
+		if v.Overlaps {
+			overlap = formatVital(v.Vital, v.OverlapMin) + " - " + formatVital(v.Vital, v.OverlapMax)
+		}
+		response.WriteString(fmt.Sprintf("| %s | %s - %s | %s - %s | %s | %s |\n",
+			strings.ToUpper(v.Vital),
+			formatVital(v.Vital, v.AMin), formatVital(v.Vital, v.AMax),
+			formatVital(v.Vital, v.BMin), formatVital(v.Vital, v.BMax),
+			overlap, formatVitalDelta(v.Vital, v.MidpointDelta)))
+	}
+	response.WriteString(fmt.Sprintf("\n**%s Style Comparison:** %s\n", a.Code, a.StyleComparison))
+	response.WriteString(fmt.Sprintf("\n**%s Style Comparison:** %s\n", b.Code, b.StyleComparison))
+	return response.String()
+}
+
+// formatVitalDelta renders a signed vital difference.
+func formatVitalDelta(vital string, delta float64) string {
+	if delta >= 0 {
+		return "+" + formatVital(vital, delta)
+	}
+	return formatVital(vital, delta)
+}
+
+// SurpriseMe suggests random beers from the database, or random BJCP styles from the in-memory
+// guidelines, which need no database access.
+func (h *ToolHandlers) SurpriseMe(ctx context.Context, args map[string]interface{}) (*mcp.ToolResult, error) {
+	kind := surpriseBeer
+	if value, ok := args["kind"]; ok {
+		text, _ := value.(string)
+		kind = strings.ToLower(strings.TrimSpace(text))
+		if !slices.Contains(surpriseKinds, kind) {
+			return nil, &mcp.Error{
+				Code:    mcp.InvalidParams,
+				Message: "kind must be one of: " + strings.Join(surpriseKinds, ", "),
+				Data:    map[string]interface{}{"kind": value},
+			}
+		}
+	}
+	count, hasCount, err := parseOptionalInt(args, "count")
+	if err != nil {
+		return nil, err
+	}
+	if !hasCount {
+		count = 1
+	}
+	if count < 1 || count > maxSurpriseCount {
+		return nil, &mcp.Error{
+			Code:    mcp.InvalidParams,
+			Message: fmt.Sprintf("count must be between 1 and %d", maxSurpriseCount),
+			Data:    map[string]interface{}{"count": count},
+		}
+	}
+
+	if kind == surpriseStyle {
+		return mcp.NewToolResult(h.randomStyles(count)), nil
+	}
+
+	style, _ := args["style"].(string)
+	country, _ := args["country"].(string)
+	beers, err := h.beerService.RandomBeers(ctx, style, country, count)
+	if err != nil {
+		return nil, serviceError(err, "failed to pick random beers")
+	}
+	if len(beers) == 0 {
+		return mcp.NewToolResult("No beers found matching your criteria."), nil
+	}
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("**%d random beer(s):**\n\n", len(beers)))
+	for i, beer := range beers {
+		writeBeerResult(&response, i+1, beer, false, nil)
+	}
+	return mcp.NewToolResult(response.String()), nil
+}
+
+// randomStyles formats count distinct BJCP styles picked at random.
+func (h *ToolHandlers) randomStyles(count int) string {
+	styles := h.bjcpService.GetAllStyles()
+	if len(styles) == 0 {
+		return "No BJCP styles are available."
+	}
+	codes := make([]string, 0, len(styles))
+	for code := range styles {
+		codes = append(codes, code)
+	}
+	entries := []string{}
+	for _, i := range rand.Perm(len(codes))[:min(count, len(codes))] { //nolint:gosec // not security sensitive
+		style := styles[codes[i]]
+		entries = append(entries, formatBJCPStyle(&style))
+	}
+	return strings.Join(entries, "\n\n---\n\n")
+}
@@ -6,12 +6,14 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"reflect"
 	"strings"
 	"testing"
 
 	"github.com/CharlRitter/brewsource-mcp/app/internal/handlers"
 	"github.com/CharlRitter/brewsource-mcp/app/internal/mcp"
 	"github.com/CharlRitter/brewsource-mcp/app/internal/services"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/services/servicestest"
 	"github.com/CharlRitter/brewsource-mcp/app/pkg/data"
 )
 
@@ -26,7 +28,7 @@ func TestRegisterToolHandlers(t *testing.T) {
 	}
 
 	toolHandlers := handlers.NewToolHandlers(bjcpData, nil, nil)
-	server := mcp.NewServer(toolHandlers, nil)
+	server := mcp.NewServer(toolHandlers, nil, nil)
 
 	// Call RegisterToolHandlers
 	toolHandlers.RegisterToolHandlers(server)
@@ -64,7 +66,7 @@ func TestBJCPLookup_EmptyStyleName(t *testing.T) {
 	}
 
 	toolHandlers := handlers.NewToolHandlers(bjcpData, nil, nil)
-	server := mcp.NewServer(toolHandlers, nil)
+	server := mcp.NewServer(toolHandlers, nil, nil)
 	ctx := context.Background()
 
 	// Test with empty style_name
@@ -98,8 +100,8 @@ func TestParseLimit_DefaultCase(t *testing.T) {
 		Metadata:   data.Metadata{Version: "2021", Source: "test"},
 	}
 
-	toolHandlers := handlers.NewToolHandlers(bjcpData, &mockBeerService{}, nil)
-	server := mcp.NewServer(toolHandlers, nil)
+	toolHandlers := handlers.NewToolHandlers(bjcpData, newBeerService(), nil)
+	server := mcp.NewServer(toolHandlers, nil, nil)
 	ctx := context.Background()
 
 	// Test with invalid limit type (boolean)
@@ -131,7 +133,17 @@ func TestToolDefinitions(t *testing.T) {
 
 	tools := handlers.GetToolDefinitions()
 
-	expectedTools := []string{"bjcp_lookup", "search_beers", "find_breweries"}
+	expectedTools := []string{
+		"bjcp_lookup", "search_beers", "find_breweries", "get_beer", "get_brewery",
+		"brewery_beers", "venues_near", "brewery_stats", "match_style", "check_beer_style", "bjcp_style_search",
+		"compare_styles",
+		"style_examples", "related_styles", "recommend_beers", "surprise_me", "lookup_ingredient", "suggest",
+		"export_results", "unit_convert", "mash_water", "carbonation_calculator",
+		"refractometer_correction", "hydrometer_correction", "ibu_calculator", "srm_calculator",
+		"volume_calculator", "abv_calculator", "attenuation_calculator",
+		"yeast_starter", "water_profile", "brew_day_sheet", "add_brewery", "add_beer", "delete_brewery",
+		"restore_brewery", "sync_breweries", "seed_catalog", "import_catalog", "get_job_status", "reload_data",
+	}
 
 	if len(tools) != len(expectedTools) {
 		t.Errorf("Expected %d tools, got %d", len(expectedTools), len(tools))
@@ -268,51 +280,95 @@ func TestSearchBeersTool_Definition(t *testing.T) {
 	if limit["type"] != "integer" {
 		t.Error("Expected limit type to be integer")
 	}
+	if limit["minimum"] != 1 || limit["maximum"] != 100 {
+		t.Errorf("Expected limit to range from 1 to 100, got %v to %v", limit["minimum"], limit["maximum"])
+	}
 }
 
-// mockBeerService implements a mock for BeerService for testing.
-type mockBeerService struct{}
-
-func (m *mockBeerService) SearchBeers(
-	_ context.Context,
-	_ services.BeerSearchQuery,
-) ([]*services.BeerSearchResult, error) {
-	return []*services.BeerSearchResult{
-		{
-			Name:    "Test Beer",
-			Brewery: "Test Brewery",
-			Style:   "Test Style",
+// newBeerService returns a fake beer service holding "Test Beer" (ID 1) from "Test Brewery" (ID 1),
+// which accepts new beers for brewery 1 only, as ID 42.
+func newBeerService() *servicestest.BeerService {
+	return &servicestest.BeerService{
+		Results: []*services.BeerSearchResult{
+			{Name: "Test Beer", Brewery: "Test Brewery", Style: "Test Style"},
 		},
-	}, nil
+		Beers: []*services.BeerDetail{{
+			ID:          1,
+			Name:        "Test Beer",
+			Style:       "Test Style",
+			ABV:         5.5,
+			IBU:         30,
+			SRM:         6,
+			Description: "A test beer.",
+			Tags:        services.TagList{"citrus", "pine"},
+			BreweryID:   1,
+			Brewery:     "Test Brewery",
+			BreweryCity: "Test City",
+			Country:     "Test Country",
+		}},
+		BreweryIDs: []int{1},
+		CreatedID:  42,
+	}
 }
 
-// mockBeerServiceWithError implements a mock that returns errors for testing error paths.
-type mockBeerServiceWithError struct{}
-
-func (m *mockBeerServiceWithError) SearchBeers(
-	_ context.Context,
-	_ services.BeerSearchQuery,
-) ([]*services.BeerSearchResult, error) {
-	return nil, errors.New("database connection failed")
+// failingBeerService returns a fake beer service whose every call fails with err, or with a
+// database connection failure when err is nil.
+func failingBeerService(err error) *servicestest.BeerService {
+	if err == nil {
+		err = errors.New("database connection failed")
+	}
+	return &servicestest.BeerService{Err: err}
 }
 
-// mockBreweryService implements a mock for BreweryService for testing.
-type mockBreweryService struct{}
-
-func (m *mockBreweryService) SearchBreweries(
-	_ context.Context,
-	_ services.BrewerySearchQuery,
-) ([]*services.BrewerySearchResult, error) {
-	return []*services.BrewerySearchResult{
-		{
+// newBreweryService returns a fake brewery service holding "Test Brewery" (ID 1), which makes two
+// beers, and "Alpha Brewing" and "Beta Brewing", so that the name "brewing" is ambiguous. It
+// accepts new breweries as ID 7.
+func newBreweryService() *servicestest.BreweryService {
+	stats := services.Stats{
+		TotalBreweries: 3,
+		BreweriesByCountry: []*services.CountryCount{
+			{Country: "Test Country", Breweries: 2},
+			{Country: "", Breweries: 1},
+		},
+		TotalBeers:   2,
+		BeersByStyle: []*services.StyleStats{{Style: "Test Style", Beers: 2, AvgABV: 5.35, AvgIBU: 29}},
+	}
+	for i := range 12 {
+		stats.BeersByStyle = append(stats.BeersByStyle, &services.StyleStats{Style: fmt.Sprintf("Style %d", i)})
+	}
+	return &servicestest.BreweryService{
+		Results: []*services.BrewerySearchResult{{
 			ID:          1,
 			Name:        "Test Brewery",
 			BreweryType: "micro",
 			City:        "Test City",
 			State:       "Test State",
 			Country:     "Test Country",
+		}},
+		Breweries: []*services.BreweryDetail{
+			{
+				Brewery: services.Brewery{
+					ID:          1,
+					Name:        "Test Brewery",
+					BreweryType: "micro",
+					City:        "Test City",
+					Country:     "Test Country",
+					WebsiteURL:  "https://example.com",
+				},
+				BeerCount: 4,
+			},
+			{Brewery: services.Brewery{ID: 2, Name: "Alpha Brewing", BreweryType: "micro"}},
+			{Brewery: services.Brewery{ID: 3, Name: "Beta Brewing", BreweryType: "brewpub"}},
 		},
-	}, nil
+		Beers: map[int][]*services.BreweryBeer{
+			1: {
+				{ID: 3, Name: "Amber Ale", Style: "American Amber Ale", ABV: 5.2, IBU: 28},
+				{ID: 1, Name: "Test Beer", Style: "Test Style", ABV: 5.5, IBU: 30},
+			},
+		},
+		Stats:     stats,
+		CreatedID: 7,
+	}
 }
 
 func TestSearchBeers_EdgeCases(t *testing.T) {
@@ -342,6 +398,13 @@ func TestSearchBeers_EdgeCases(t *testing.T) {
 			errCode:     mcp.InvalidParams,
 			errContains: "at least one search parameter is required",
 		},
+		{
+			name:        "whitespace-only strings",
+			args:        map[string]interface{}{"name": "  ", "style": "\t\n"},
+			wantErr:     true,
+			errCode:     mcp.InvalidParams,
+			errContains: "at least one search parameter is required",
+		},
 		{
 			name: "invalid limit type",
 			args: map[string]interface{}{
@@ -352,6 +415,16 @@ func TestSearchBeers_EdgeCases(t *testing.T) {
 			errCode:     mcp.InvalidParams,
 			errContains: "limit must be an integer",
 		},
+		{
+			name: "fractional limit",
+			args: map[string]interface{}{
+				"name":  "Test Beer",
+				"limit": 2.5,
+			},
+			wantErr:     true,
+			errCode:     mcp.InvalidParams,
+			errContains: "limit must be an integer",
+		},
 		{
 			name: "negative limit",
 			args: map[string]interface{}{
@@ -360,15 +433,15 @@ func TestSearchBeers_EdgeCases(t *testing.T) {
 			},
 			wantErr:     true,
 			errCode:     mcp.InvalidParams,
-			errContains: "limit must be greater than zero",
+			errContains: "limit must be at least 1",
 		},
 		{
-			name: "excessive limit",
+			name: "excessive limit is capped",
 			args: map[string]interface{}{
 				"name":  "Test Beer",
 				"limit": 1000,
 			},
-			wantErr: false, // Should cap at max limit (100)
+			wantErr: false,
 		},
 		{
 			name: "valid search with all parameters",
@@ -383,9 +456,7 @@ func TestSearchBeers_EdgeCases(t *testing.T) {
 		},
 	}
 
-	// Create a mock service and properly initialize handlers
-	mockService := &mockBeerService{}
-	handlers := handlers.NewToolHandlers(nil, mockService, nil)
+	handlers := handlers.NewToolHandlers(nil, newBeerService(), nil)
 	ctx := context.Background()
 
 	for _, tt := range tests {
@@ -422,6 +493,144 @@ func TestSearchBeers_EdgeCases(t *testing.T) {
 	}
 }
 
+// Test that both search tools cap a limit above 100 and say so in the result.
+func TestSearchTools_CapLimit(t *testing.T) {
+	beerService := newBeerService()
+	breweryService := newBreweryService()
+	h := handlers.NewToolHandlers(nil, beerService, breweryService)
+	ctx := context.Background()
+
+	for _, limit := range []interface{}{1000.0, 1e300, "250"} {
+		beerResult, err := h.SearchBeers(ctx, map[string]interface{}{"name": "Test", "limit": limit})
+		if err != nil {
+			t.Fatalf("Expected limit %v to be capped, got %v", limit, err)
+		}
+		breweryResult, err := h.FindBreweries(ctx, map[string]interface{}{"name": "Test", "limit": limit})
+		if err != nil {
+			t.Fatalf("Expected limit %v to be capped, got %v", limit, err)
+		}
+		for _, result := range []*mcp.ToolResult{beerResult, breweryResult} {
+			if !strings.Contains(result.Content[0].Text, "Limit capped at 100 results") {
+				t.Errorf("Expected the cap to be noted, got: %s", result.Content[0].Text)
+			}
+		}
+	}
+	for _, query := range beerService.Queries {
+		if query.Limit != 100 {
+			t.Errorf("Expected beers to be searched with limit 100, got %d", query.Limit)
+		}
+	}
+	for _, query := range breweryService.Queries {
+		if query.Limit != 100 {
+			t.Errorf("Expected breweries to be searched with limit 100, got %d", query.Limit)
+		}
+	}
+
+	result, err := h.SearchBeers(ctx, map[string]interface{}{"name": "Test", "limit": 100.0})
+	if err != nil || strings.Contains(result.Content[0].Text, "capped") {
+		t.Errorf("Expected the maximum limit not to be noted, got %v (%v)", result, err)
+	}
+}
+
+// Test that search_beers resolves style_code to the style's name and aliases, and checks each beer
+// against the style's ranges.
+func TestSearchBeers_StyleCode(t *testing.T) {
+	bjcpData := &data.BJCPData{
+		Styles: map[string]data.BJCPStyle{"22A": {
+			Code: "22A", Name: "Double IPA", Category: "Strong American Ale",
+			Vitals:  data.Vitals{ABVMin: 7.5, ABVMax: 10, IBUMin: 60, IBUMax: 100},
+			Aliases: []string{"Imperial IPA"},
+		}},
+		Categories: []string{"Strong American Ale"},
+		Metadata:   data.Metadata{Version: "2021", Source: "test"},
+	}
+	beerService := &servicestest.BeerService{Results: []*services.BeerSearchResult{
+		{Name: "Pliny", Brewery: "Russian River", Style: "Double IPA", ABV: 8, IBU: 100},
+		{Name: "Session Imperial", Brewery: "Test Brewery", Style: "Imperial IPA", ABV: 6.5, IBU: 110},
+		{Name: "Unmeasured", Brewery: "Test Brewery", Style: "Imperial IPA"},
+	}}
+	h := handlers.NewToolHandlers(bjcpData, beerService, nil)
+	ctx := context.Background()
+
+	result, err := h.SearchBeers(ctx, map[string]interface{}{"style_code": " 22a "})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	query := beerService.Queries[0]
+	if query.Style != "Double IPA" || !reflect.DeepEqual(query.StyleAliases, []string{"Imperial IPA"}) {
+		t.Errorf("Expected the style's name and aliases to be searched, got %q and %v", query.Style, query.StyleAliases)
+	}
+	text := result.Content[0].Text
+	for _, want := range []string{
+		"_BJCP 22A Double IPA, searched as: Double IPA, Imperial IPA. Style ranges: ABV 7.5 - 10.0%, IBU 60 - 100._",
+		"- **Style fit:** ✓ in range",
+		"- **Style fit:** ✗ ABV 1.0 below, IBU 10 above the range",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, text)
+		}
+	}
+	if strings.Count(text, "Style fit") != 2 {
+		t.Errorf("Expected no style fit for the beer without stats, got:\n%s", text)
+	}
+
+	for wantErr, args := range map[string]map[string]interface{}{
+		"BJCP style not found for: 99Z":                {"style_code": "99Z"},
+		"style and style_code cannot be used together": {"style_code": "22A", "style": "IPA"},
+	} {
+		_, err = h.SearchBeers(ctx, args)
+		var mcpErr *mcp.Error
+		if !errors.As(err, &mcpErr) || mcpErr.Code != mcp.InvalidParams || mcpErr.Message != wantErr {
+			t.Errorf("Expected InvalidParams %q, got %v", wantErr, err)
+		}
+	}
+}
+
+// Test that beers of the same name name their brewery's place, and that group_by_brewery lists them
+// under their breweries.
+func TestSearchBeers_SameNameBeers(t *testing.T) {
+	beerService := &servicestest.BeerService{Results: []*services.BeerSearchResult{
+		{Name: "Pale Ale", Brewery: "Brewery A", City: "Cape Town", Country: "South Africa"},
+		{Name: "Pale Ale", Brewery: "Brewery B", Country: "United States"},
+		{Name: "Stout", Brewery: "Brewery A", City: "Cape Town", Country: "South Africa"},
+	}}
+	h := handlers.NewToolHandlers(nil, beerService, nil)
+	ctx := context.Background()
+
+	result, err := h.SearchBeers(ctx, map[string]interface{}{"name": "ale"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	text := result.Content[0].Text
+	for _, want := range []string{
+		"**1. Pale Ale**\n- **Brewery:** Brewery A (Cape Town, South Africa)\n",
+		"**2. Pale Ale**\n- **Brewery:** Brewery B (United States)\n",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, text)
+		}
+	}
+
+	result, err = h.SearchBeers(ctx, map[string]interface{}{"name": "ale", "group_by_brewery": "true"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	text = result.Content[0].Text
+	want := "### Brewery A (Cape Town, South Africa)\n\n**1. Pale Ale**\n- **Style:** \n\n**2. Stout**\n"
+	if !strings.Contains(text, want) || !strings.Contains(text, "### Brewery B (United States)\n\n**3. Pale Ale**") {
+		t.Errorf("Expected the beers under their breweries, got:\n%s", text)
+	}
+	if strings.Contains(text, "**Brewery:**") {
+		t.Errorf("Expected no brewery lines under the brewery headings, got:\n%s", text)
+	}
+
+	_, err = h.SearchBeers(ctx, map[string]interface{}{"name": "ale", "group_by_brewery": "sometimes"})
+	var mcpErr *mcp.Error
+	if !errors.As(err, &mcpErr) || mcpErr.Message != "group_by_brewery must be a boolean" {
+		t.Errorf("Expected an invalid group_by_brewery to be rejected, got %v", err)
+	}
+}
+
 func TestFindBreweriesTool_Definition(t *testing.T) {
 	handlers := &handlers.ToolHandlers{}
 	tools := handlers.GetToolDefinitions()
@@ -500,13 +709,22 @@ func getFindBreweriesEdgeCaseTests() []struct {
 			errCode:     mcp.InvalidParams,
 			errContains: "at least one search parameter is required",
 		},
+		{
+			name:        "whitespace-only strings",
+			args:        map[string]interface{}{"name": "   ", "city": "\t"},
+			wantErr:     true,
+			errCode:     mcp.InvalidParams,
+			errContains: "at least one search parameter is required",
+		},
 		{
 			name: "invalid limit type",
 			args: map[string]interface{}{
 				"name":  "Test Brewery",
 				"limit": "invalid",
 			},
-			wantErr: false, // Should use default limit
+			wantErr:     true,
+			errCode:     mcp.InvalidParams,
+			errContains: "limit must be an integer",
 		},
 		{
 			name: "search by city only",
@@ -539,12 +757,32 @@ func getFindBreweriesEdgeCaseTests() []struct {
 			wantErr: false,
 		},
 		{
-			name: "excessive limit",
+			name: "fractional limit",
+			args: map[string]interface{}{
+				"name":  "Test Brewery",
+				"limit": 2.5,
+			},
+			wantErr:     true,
+			errCode:     mcp.InvalidParams,
+			errContains: "limit must be an integer",
+		},
+		{
+			name: "zero limit",
+			args: map[string]interface{}{
+				"name":  "Test Brewery",
+				"limit": 0,
+			},
+			wantErr:     true,
+			errCode:     mcp.InvalidParams,
+			errContains: "limit must be at least 1",
+		},
+		{
+			name: "excessive limit is capped",
 			args: map[string]interface{}{
 				"name":  "Test Brewery",
 				"limit": 1000,
 			},
-			wantErr: false, // Should cap at max limit (100)
+			wantErr: false,
 		},
 	}
 }
@@ -599,10 +837,7 @@ func validateFindBreweriesError(t *testing.T, err error, expectedCode int, expec
 func TestFindBreweries_EdgeCases(t *testing.T) {
 	tests := getFindBreweriesEdgeCaseTests()
 
-	// Create mock services and properly initialize handlers
-	mockBeerService := &mockBeerService{}
-	mockBreweryService := &mockBreweryService{}
-	handlers := handlers.NewToolHandlers(nil, mockBeerService, mockBreweryService)
+	handlers := handlers.NewToolHandlers(nil, newBeerService(), newBreweryService())
 	ctx := context.Background()
 
 	for _, tt := range tests {
@@ -791,7 +1026,7 @@ func getBJCPLookupEdgeCaseTests() []struct {
 			args:        map[string]interface{}{},
 			wantErr:     true,
 			errCode:     mcp.InvalidParams,
-			errContains: "either 'style_code' or 'style_name' parameter is required",
+			errContains: "either 'style_code', 'style_name', or 'category' parameter is required",
 		},
 		{
 			name: "empty style code",
@@ -836,7 +1071,7 @@ func getBJCPLookupEdgeCaseTests() []struct {
 			},
 			wantErr:     true,
 			errCode:     mcp.InvalidParams,
-			errContains: "either 'style_code' or 'style_name' parameter is required",
+			errContains: "either 'style_code', 'style_name', or 'category' parameter is required",
 		},
 		{
 			name: "both parameters empty",
@@ -848,6 +1083,15 @@ func getBJCPLookupEdgeCaseTests() []struct {
 			errCode:     mcp.InvalidParams,
 			errContains: "invalid style_code format",
 		},
+		{
+			name: "code in another script",
+			args: map[string]interface{}{
+				"style_code": "٢١A",
+			},
+			wantErr:     true,
+			errCode:     mcp.InvalidParams,
+			errContains: "invalid style_code format",
+		},
 		{
 			name: "extremely long style code",
 			args: map[string]interface{}{
@@ -929,6 +1173,113 @@ func TestBJCPLookup_EdgeCases(t *testing.T) {
 	}
 }
 
+// newGuidelineService returns a style guide with 21A in the 2021 guidelines and 14B in the 2015 ones.
+func newGuidelineService() *data.BJCPService {
+	return data.NewBJCPServiceFromGuidelines(map[string]*data.BJCPData{
+		"2021": {Styles: map[string]data.BJCPStyle{"21A": {Code: "21A", Name: "American IPA"}}},
+		"2015": {Styles: map[string]data.BJCPStyle{"14B": {Code: "14B", Name: "Scottish Heavy"}}},
+	})
+}
+
+func TestBJCPLookup_GuidelineVersion(t *testing.T) {
+	toolHandlers := handlers.NewToolHandlers(nil, nil, nil)
+	toolHandlers.SetBJCPService(newGuidelineService())
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		args     map[string]interface{}
+		expected string
+	}{
+		{"code in 2015", map[string]interface{}{"style_code": "14B", "version": "2015"}, "Scottish Heavy"},
+		{"numeric version", map[string]interface{}{"style_code": "14B", "version": float64(2015)}, "Scottish Heavy"},
+		{"name in 2015", map[string]interface{}{"style_name": "Scottish", "version": "2015"}, "14B"},
+		{"default version", map[string]interface{}{"style_code": "21A"}, "American IPA"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := toolHandlers.BJCPLookup(ctx, tt.args)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if text := result.Content[0].Text; !strings.Contains(text, tt.expected) {
+				t.Errorf("Expected the result to contain %q, got: %s", tt.expected, text)
+			}
+		})
+	}
+
+	_, err := toolHandlers.BJCPLookup(ctx, map[string]interface{}{"style_code": "21A", "version": "2015"})
+	expectMCPError(t, err, mcp.InvalidParams, "BJCP style not found")
+	_, err = toolHandlers.BJCPLookup(ctx, map[string]interface{}{"style_code": "21A", "version": "2008"})
+	expectMCPError(t, err, mcp.InvalidParams, `unknown BJCP guideline version "2008": loaded versions are 2021, 2015`)
+	_, err = toolHandlers.BJCPLookup(ctx, map[string]interface{}{"style_code": "21A", "version": true})
+	expectMCPError(t, err, mcp.InvalidParams, "version must be a BJCP guideline year")
+}
+
+// Test that bjcp_lookup accepts the codes the bjcp://styles/{code} resource accepts.
+func TestBJCPLookup_NormalizesStyleCode(t *testing.T) {
+	toolHandlers := handlers.NewToolHandlers(matchStyleTestData(), nil, nil)
+	resourceHandlers := handlers.NewResourceHandlers(matchStyleTestData(), nil, nil)
+	ctx := context.Background()
+
+	for _, code := range []string{" 21A ", "21 a", "２１Ａ"} {
+		result, err := toolHandlers.BJCPLookup(ctx, map[string]interface{}{"style_code": code})
+		if err != nil || !strings.Contains(result.Content[0].Text, "BJCP Style 21A") {
+			t.Errorf("Expected %q to find 21A, got %v (error: %v)", code, result, err)
+		}
+		res, err := readResource(resourceHandlers, "bjcp://styles/"+code)
+		if err != nil || res.URI != "bjcp://styles/21A" {
+			t.Errorf("Expected the resource for %q to be 21A, got %v (error: %v)", code, res, err)
+		}
+	}
+}
+
+func TestBJCPLookup_NameSuggestions(t *testing.T) {
+	bjcpData := &data.BJCPData{Styles: map[string]data.BJCPStyle{
+		"21A": {Code: "21A", Name: "American IPA"},
+		"12C": {Code: "12C", Name: "English IPA"},
+		"20C": {Code: "20C", Name: "Imperial Stout"},
+	}}
+	toolHandlers := handlers.NewToolHandlers(bjcpData, nil, nil)
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		style    string
+		expected []string
+		excluded []string
+	}{
+		{"typo suggests candidates", "Amercan IPA", []string{"Did you mean", "1. **21A American IPA**"}, nil},
+		{"exact name has no others", "american ipa", []string{"BJCP Style 21A"}, []string{"Other styles"}},
+		{
+			"partial name lists the others", "IPA",
+			[]string{"**Other styles matching \"IPA\":**", "English IPA"}, []string{"Imperial Stout"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := toolHandlers.BJCPLookup(ctx, map[string]interface{}{"style_name": tt.style})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			text := result.Content[0].Text
+			for _, want := range tt.expected {
+				if !strings.Contains(text, want) {
+					t.Errorf("Expected %q in the result, got: %s", want, text)
+				}
+			}
+			for _, unwanted := range tt.excluded {
+				if strings.Contains(text, unwanted) {
+					t.Errorf("Expected no %q in the result, got: %s", unwanted, text)
+				}
+			}
+		})
+	}
+
+	_, err := toolHandlers.BJCPLookup(ctx, map[string]interface{}{"style_name": "Gruit"})
+	expectMCPError(t, err, mcp.InvalidParams, "BJCP style not found for: Gruit")
+}
+
 func TestArgumentExtraction_HelperFunctions(t *testing.T) {
 	// Test argument extraction patterns that would be used in handlers
 	args := map[string]interface{}{
@@ -1007,9 +1358,7 @@ func TestSearchBeers_DatabaseError(t *testing.T) {
 		Metadata:   data.Metadata{Version: "2021", Source: "test"},
 	}
 
-	// Use the error mock to test error handling
-	errorService := &mockBeerServiceWithError{}
-	toolHandlers := handlers.NewToolHandlers(bjcpData, errorService, nil)
+	toolHandlers := handlers.NewToolHandlers(bjcpData, failingBeerService(nil), nil)
 
 	ctx := context.Background()
 	args := map[string]interface{}{
@@ -1049,3 +1398,1195 @@ func TestParseLimit_StringError(t *testing.T) {
 		t.Error("Expected error for invalid limit")
 	}
 }
+
+func matchStyleTestData() *data.BJCPData {
+	return &data.BJCPData{
+		Styles: map[string]data.BJCPStyle{
+			"21A": {
+				Code: "21A", Name: "American IPA", Category: "IPA",
+				Vitals: data.Vitals{
+					ABVMin: 5.5, ABVMax: 7.5, IBUMin: 40, IBUMax: 70, SRMMin: 6, SRMMax: 14,
+					OGMin: 1.056, OGMax: 1.070, FGMin: 1.008, FGMax: 1.014,
+				},
+			},
+			"1A": {
+				Code: "1A", Name: "American Light Lager", Category: "Standard American Beer",
+				Vitals: data.Vitals{
+					ABVMin: 2.8, ABVMax: 4.2, IBUMin: 8, IBUMax: 12, SRMMin: 2, SRMMax: 3,
+					OGMin: 1.028, OGMax: 1.040, FGMin: 0.998, FGMax: 1.008,
+				},
+			},
+			"29A": {Code: "29A", Name: "Fruit Beer", Category: "Fruit Beer"},
+		},
+		Categories: []string{"IPA", "Standard American Beer", "Fruit Beer"},
+		Metadata:   data.Metadata{Version: "2021", Source: "test"},
+	}
+}
+
+func TestMatchStyle_HappyPath(t *testing.T) {
+	toolHandlers := handlers.NewToolHandlers(matchStyleTestData(), nil, nil)
+
+	result, err := toolHandlers.MatchStyle(context.Background(), map[string]interface{}{
+		"og":    1.062,
+		"fg":    "1.011",
+		"ibu":   float64(60),
+		"limit": float64(1),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	text := result.Content[0].Text
+	if !strings.Contains(text, "21A American IPA") {
+		t.Errorf("expected American IPA as the top match, got: %s", text)
+	}
+	if strings.Contains(text, "American Light Lager") {
+		t.Errorf("expected limit to restrict results to one style, got: %s", text)
+	}
+	if !strings.Contains(text, "3/3 vitals in range") {
+		t.Errorf("expected per-vital summary, got: %s", text)
+	}
+	if strings.Contains(text, "Fruit Beer") {
+		t.Errorf("expected styles without vitals to be excluded, got: %s", text)
+	}
+}
+
+func TestMatchStyle_InvalidParams(t *testing.T) {
+	tests := []struct {
+		name string
+		args map[string]interface{}
+	}{
+		{name: "no vitals", args: map[string]interface{}{}},
+		{name: "only limit", args: map[string]interface{}{"limit": float64(3)}},
+		{name: "non-numeric vital", args: map[string]interface{}{"og": "heavy"}},
+		{name: "invalid vital type", args: map[string]interface{}{"abv": true}},
+		{name: "zero limit", args: map[string]interface{}{"abv": 5.0, "limit": float64(0)}},
+	}
+
+	toolHandlers := handlers.NewToolHandlers(matchStyleTestData(), nil, nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := toolHandlers.MatchStyle(context.Background(), tt.args)
+			var mcpErr *mcp.Error
+			if !errors.As(err, &mcpErr) {
+				t.Fatalf("expected *mcp.Error, got %v", err)
+			}
+			if mcpErr.Code != mcp.InvalidParams {
+				t.Errorf("expected InvalidParams, got %d", mcpErr.Code)
+			}
+		})
+	}
+}
+
+func TestStyleSearch(t *testing.T) {
+	toolHandlers := handlers.NewToolHandlers(matchStyleTestData(), nil, nil)
+	ctx := context.Background()
+
+	result, err := toolHandlers.StyleSearch(ctx, map[string]interface{}{"ibu_min": float64(40), "srm_max": "10"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	text := result.Content[0].Text
+	if !strings.Contains(text, "1 BJCP style(s)") || !strings.Contains(text, "21A American IPA") {
+		t.Errorf("expected only American IPA, got: %s", text)
+	}
+	if !strings.Contains(text, "IBU 40 - 70") {
+		t.Errorf("expected the style's ranges, got: %s", text)
+	}
+
+	result, err = toolHandlers.StyleSearch(ctx, map[string]interface{}{
+		"abv_max": 8.0, "include_unspecified": true, "limit": float64(2),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	text = result.Content[0].Text
+	if !strings.Contains(text, "3 BJCP style(s)") || !strings.Contains(text, "the 2 most central are shown") {
+		t.Errorf("expected 3 styles limited to 2, got: %s", text)
+	}
+	if strings.Contains(text, "Fruit Beer") {
+		t.Errorf("expected the style without vitals to rank last and be cut, got: %s", text)
+	}
+
+	result, err = toolHandlers.StyleSearch(ctx, map[string]interface{}{"og_min": 1.2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if text = result.Content[0].Text; !strings.Contains(text, "No BJCP styles") {
+		t.Errorf("expected no styles, got: %s", text)
+	}
+}
+
+func TestStyleSearch_InvalidParams(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     map[string]interface{}
+		expected string
+	}{
+		{"no bounds", map[string]interface{}{"include_unspecified": true}, "at least one vital bound"},
+		{"inverted range", map[string]interface{}{"ibu_min": 70, "ibu_max": 40}, "ibu_min must not exceed ibu_max"},
+		{"non-numeric bound", map[string]interface{}{"srm_max": "dark"}, "srm_max must be a number"},
+		{"invalid flag", map[string]interface{}{"abv_min": 5.0, "include_unspecified": "maybe"}, "must be a boolean"},
+	}
+
+	toolHandlers := handlers.NewToolHandlers(matchStyleTestData(), nil, nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := toolHandlers.StyleSearch(context.Background(), tt.args)
+			expectMCPError(t, err, mcp.InvalidParams, tt.expected)
+		})
+	}
+}
+
+func TestCompareStyles_HappyPath(t *testing.T) {
+	toolHandlers := handlers.NewToolHandlers(matchStyleTestData(), nil, nil)
+
+	result, err := toolHandlers.CompareStyles(context.Background(), map[string]interface{}{
+		"style_a": "21a",
+		"style_b": "American Light Lager",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Content) != 2 {
+		t.Fatalf("expected markdown and JSON content, got %d blocks", len(result.Content))
+	}
+	if !strings.Contains(result.Content[0].Text, "21A American IPA vs 1A American Light Lager") {
+		t.Errorf("unexpected comparison header: %s", result.Content[0].Text)
+	}
+
+	var diff data.StyleDiff
+	if err := json.Unmarshal([]byte(result.Content[1].Text), &diff); err != nil {
+		t.Fatalf("expected JSON diff, got error: %v", err)
+	}
+	if diff.StyleA != "21A" || diff.StyleB != "1A" || len(diff.Vitals) != 5 {
+		t.Errorf("unexpected diff: %+v", diff)
+	}
+}
+
+func TestCompareStyles_Errors(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         map[string]interface{}
+		wantContains string
+	}{
+		{
+			name:         "missing style_b",
+			args:         map[string]interface{}{"style_a": "21A"},
+			wantContains: "required",
+		},
+		{
+			name:         "unknown code",
+			args:         map[string]interface{}{"style_a": "21A", "style_b": "99Z"},
+			wantContains: "BJCP style not found for: 99Z",
+		},
+		{
+			name:         "unknown name",
+			args:         map[string]interface{}{"style_a": "Mystery Ale", "style_b": "21A"},
+			wantContains: "BJCP style not found for: Mystery Ale",
+		},
+	}
+
+	toolHandlers := handlers.NewToolHandlers(matchStyleTestData(), nil, nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := toolHandlers.CompareStyles(context.Background(), tt.args)
+			var mcpErr *mcp.Error
+			if !errors.As(err, &mcpErr) {
+				t.Fatalf("expected *mcp.Error, got %v", err)
+			}
+			if mcpErr.Code != mcp.InvalidParams || !strings.Contains(mcpErr.Message, tt.wantContains) {
+				t.Errorf("unexpected error: %d %s", mcpErr.Code, mcpErr.Message)
+			}
+		})
+	}
+}
+
+func TestRelatedStyles(t *testing.T) {
+	h := handlers.NewToolHandlers(loadStyleData(t), nil, nil)
+
+	result, err := h.RelatedStyles(context.Background(), map[string]interface{}{"style_code": "21a", "limit": 3.0})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	text := result.Content[0].Text
+	for _, want := range []string{
+		"**Styles closest to 21A American IPA by vitals:**",
+		"1. **12C English IPA** (Pale Commonwealth Beer; other category",
+		"2. **17A British Strong Ale**",
+		"3. **21C Hazy IPA** (Ipa; same category",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "4. ") {
+		t.Errorf("expected the limit to apply:\n%s", text)
+	}
+
+	result, err = h.RelatedStyles(context.Background(), map[string]interface{}{"style_code": "34A"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(result.Content[0].Text, "publishes no vitals") {
+		t.Errorf("unexpected response for a style without vitals: %s", result.Content[0].Text)
+	}
+
+	_, err = h.RelatedStyles(context.Background(), map[string]interface{}{})
+	expectMCPError(t, err, mcp.InvalidParams, "style_code is required")
+	_, err = h.RelatedStyles(context.Background(), map[string]interface{}{"style_code": "99Z"})
+	expectMCPError(t, err, mcp.InvalidParams, "BJCP style not found for: 99Z")
+}
+
+func TestBJCPLookup_SeeAlso(t *testing.T) {
+	h := handlers.NewToolHandlers(loadStyleData(t), nil, nil)
+
+	result, err := h.BJCPLookup(context.Background(), map[string]interface{}{"style_code": "21A"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "**See also:** 21C Hazy IPA, 21B Specialty IPA, 12C English IPA"; !strings.Contains(
+		result.Content[0].Text, want) {
+		t.Errorf("expected %q in:\n%s", want, result.Content[0].Text)
+	}
+}
+
+// pagedBeerService returns a fixed page of beers and records the query it was given.
+type pagedBeerService struct {
+	servicestest.BeerService
+
+	total     int
+	relevance bool // rank relevance searches instead of falling back to name order
+	query     services.BeerSearchQuery
+}
+
+func (m *pagedBeerService) SearchBeersPage(
+	_ context.Context,
+	query services.BeerSearchQuery,
+) (*services.BeerSearchPage, error) {
+	m.query = query
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+	page := &services.BeerSearchPage{
+		Items:      []*services.BeerSearchResult{},
+		Offset:     query.Offset,
+		TotalCount: m.total,
+		Sort:       services.SortByName,
+	}
+	if m.relevance && query.Sort == services.SortByRelevance {
+		page.Sort = services.SortByRelevance
+	}
+	for i := query.Offset; i < m.total && i < query.Offset+query.Limit; i++ {
+		beer := &services.BeerSearchResult{Name: fmt.Sprintf("Beer %d", i+1)}
+		if page.Sort == services.SortByRelevance {
+			beer.Score = 1 / float64(i+2)
+		}
+		page.Items = append(page.Items, beer)
+	}
+	page.HasMore = query.Offset+len(page.Items) < m.total
+	return page, nil
+}
+
+// pagedBreweryService returns a fixed page of breweries and records the query it was given.
+type pagedBreweryService struct {
+	servicestest.BreweryService
+
+	total     int
+	relevance bool // rank relevance searches instead of falling back to name order
+	query     services.BrewerySearchQuery
+}
+
+func (m *pagedBreweryService) SearchBreweriesPage(
+	_ context.Context,
+	query services.BrewerySearchQuery,
+) (*services.BrewerySearchPage, error) {
+	m.query = query
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+	page := &services.BrewerySearchPage{
+		Items:      []*services.BrewerySearchResult{},
+		Offset:     query.Offset,
+		TotalCount: m.total,
+		Sort:       services.SortByName,
+	}
+	if m.relevance && query.Sort == services.SortByRelevance {
+		page.Sort = services.SortByRelevance
+	}
+	for i := query.Offset; i < m.total && i < query.Offset+query.Limit; i++ {
+		brewery := &services.BrewerySearchResult{Name: fmt.Sprintf("Brewery %d", i+1)}
+		if page.Sort == services.SortByRelevance {
+			brewery.Score = 1 / float64(i+2)
+		}
+		if query.Lat != nil {
+			distance := float64(i+1) * 2.5
+			brewery.DistanceKm = &distance
+		}
+		page.Items = append(page.Items, brewery)
+	}
+	page.HasMore = query.Offset+len(page.Items) < m.total
+	return page, nil
+}
+
+func TestSearchBeers_Pagination(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         map[string]interface{}
+		wantOffset   int
+		wantContains []string
+		wantMissing  []string
+	}{
+		{
+			name:         "first page",
+			args:         map[string]interface{}{"style": "IPA"},
+			wantOffset:   0,
+			wantContains: []string{"**Showing 1–20 of 45 beer(s):**", "**1. Beer 1**", "use offset 20 or page 2"},
+		},
+		{
+			name:         "page argument",
+			args:         map[string]interface{}{"style": "IPA", "page": 3},
+			wantOffset:   40,
+			wantContains: []string{"**Showing 41–45 of 45 beer(s):**", "**41. Beer 41**"},
+			wantMissing:  []string{"More results available"},
+		},
+		{
+			name:         "offset argument off a page boundary",
+			args:         map[string]interface{}{"style": "IPA", "offset": "5", "limit": 10},
+			wantOffset:   5,
+			wantContains: []string{"**Showing 6–15 of 45 beer(s):**", "use offset 15._"},
+		},
+		{
+			name:         "offset beyond the last row",
+			args:         map[string]interface{}{"style": "IPA", "offset": 100},
+			wantOffset:   100,
+			wantContains: []string{"45 beer(s) match your search, and offset 100 is past the last one"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &pagedBeerService{total: 45}
+			toolHandlers := handlers.NewToolHandlers(nil, service, nil)
+
+			result, err := toolHandlers.SearchBeers(context.Background(), tt.args)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if service.query.Offset != tt.wantOffset {
+				t.Errorf("Expected offset %d, got %d", tt.wantOffset, service.query.Offset)
+			}
+			text := result.Content[0].Text
+			for _, want := range tt.wantContains {
+				if !strings.Contains(text, want) {
+					t.Errorf("Expected output to contain %q, got:\n%s", want, text)
+				}
+			}
+			for _, unwanted := range tt.wantMissing {
+				if strings.Contains(text, unwanted) {
+					t.Errorf("Expected output not to contain %q, got:\n%s", unwanted, text)
+				}
+			}
+		})
+	}
+}
+
+func TestFindBreweries_Pagination(t *testing.T) {
+	service := &pagedBreweryService{total: 3}
+	toolHandlers := handlers.NewToolHandlers(nil, nil, service)
+
+	result, err := toolHandlers.FindBreweries(context.Background(), map[string]interface{}{
+		"country": "South Africa",
+		"limit":   2.0,
+		"page":    2.0,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if service.query.Offset != 2 {
+		t.Errorf("Expected offset 2, got %d", service.query.Offset)
+	}
+	text := result.Content[0].Text
+	for _, want := range []string{"**Showing 3–3 of 3 brewery(ies):**", "**3. Brewery 3**"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "More results available") {
+		t.Errorf("Expected no next-page hint on the last page, got:\n%s", text)
+	}
+}
+
+func TestPagination_InvalidArguments(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        map[string]interface{}
+		errContains string
+	}{
+		{"offset and page together", map[string]interface{}{"offset": 20, "page": 2}, "either offset or page"},
+		{"page zero", map[string]interface{}{"page": 0}, "page must be 1 or greater"},
+		{"negative offset", map[string]interface{}{"offset": -1}, "offset cannot be negative"},
+		{"non-numeric offset", map[string]interface{}{"offset": "next"}, "offset must be an integer"},
+		{"non-numeric page", map[string]interface{}{"page": true}, "page must be an integer"},
+	}
+
+	toolHandlers := handlers.NewToolHandlers(nil, &pagedBeerService{}, &pagedBreweryService{})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			beerArgs := map[string]interface{}{"name": "IPA"}
+			breweryArgs := map[string]interface{}{"name": "Stone"}
+			for k, v := range tt.args {
+				beerArgs[k] = v
+				breweryArgs[k] = v
+			}
+
+			_, beerErr := toolHandlers.SearchBeers(context.Background(), beerArgs)
+			_, breweryErr := toolHandlers.FindBreweries(context.Background(), breweryArgs)
+			for tool, err := range map[string]error{"search_beers": beerErr, "find_breweries": breweryErr} {
+				mcpErr := &mcp.Error{}
+				if !errors.As(err, &mcpErr) || mcpErr.Code != mcp.InvalidParams {
+					t.Errorf("%s: expected InvalidParams error, got %v", tool, err)
+					continue
+				}
+				if !strings.Contains(mcpErr.Message, tt.errContains) {
+					t.Errorf("%s: expected error containing %q, got %q", tool, tt.errContains, mcpErr.Message)
+				}
+			}
+		})
+	}
+}
+
+func TestSearchTools_Sort(t *testing.T) {
+	t.Run("relevance shows scores", func(t *testing.T) {
+		beers := &pagedBeerService{total: 2, relevance: true}
+		breweries := &pagedBreweryService{total: 2, relevance: true}
+		toolHandlers := handlers.NewToolHandlers(nil, beers, breweries)
+
+		beerResult, err := toolHandlers.SearchBeers(context.Background(), map[string]interface{}{
+			"name": "stone",
+			"sort": "Relevance",
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		breweryResult, err := toolHandlers.FindBreweries(context.Background(), map[string]interface{}{
+			"name": "stone",
+			"sort": "relevance",
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if beers.query.Sort != services.SortByRelevance || breweries.query.Sort != services.SortByRelevance {
+			t.Errorf("Expected relevance sort, got %q and %q", beers.query.Sort, breweries.query.Sort)
+		}
+		for _, text := range []string{beerResult.Content[0].Text, breweryResult.Content[0].Text} {
+			if !strings.Contains(text, "- **Relevance:** 0.50") {
+				t.Errorf("Expected the first result's score in output, got:\n%s", text)
+			}
+			if strings.Contains(text, "sorted by name") {
+				t.Errorf("Expected no fallback note, got:\n%s", text)
+			}
+		}
+	})
+
+	t.Run("relevance falls back to name order", func(t *testing.T) {
+		toolHandlers := handlers.NewToolHandlers(nil, &pagedBeerService{total: 2}, nil)
+
+		result, err := toolHandlers.SearchBeers(context.Background(), map[string]interface{}{
+			"name": "stone",
+			"sort": "relevance",
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		text := result.Content[0].Text
+		if !strings.Contains(text, "results are sorted by name") {
+			t.Errorf("Expected fallback note, got:\n%s", text)
+		}
+		if strings.Contains(text, "Relevance:") {
+			t.Errorf("Expected no scores without relevance ranking, got:\n%s", text)
+		}
+	})
+
+	t.Run("name sort is the default", func(t *testing.T) {
+		beers := &pagedBeerService{total: 1, relevance: true}
+		toolHandlers := handlers.NewToolHandlers(nil, beers, nil)
+
+		_, err := toolHandlers.SearchBeers(context.Background(), map[string]interface{}{"name": "stone"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if beers.query.Sort != services.SortByName {
+			t.Errorf("Expected default sort %q, got %q", services.SortByName, beers.query.Sort)
+		}
+	})
+
+	t.Run("unknown sort is rejected", func(t *testing.T) {
+		toolHandlers := handlers.NewToolHandlers(nil, &pagedBeerService{}, &pagedBreweryService{})
+
+		for _, sort := range []interface{}{"popularity", 1} {
+			args := map[string]interface{}{"name": "x", "sort": sort}
+			_, beerErr := toolHandlers.SearchBeers(context.Background(), args)
+			_, breweryErr := toolHandlers.FindBreweries(context.Background(), args)
+			for _, err := range []error{beerErr, breweryErr} {
+				mcpErr := &mcp.Error{}
+				if !errors.As(err, &mcpErr) || mcpErr.Code != mcp.InvalidParams ||
+					!strings.Contains(mcpErr.Message, "sort must be") {
+					t.Errorf("sort %v: expected InvalidParams sort error, got %v", sort, err)
+				}
+			}
+		}
+	})
+}
+
+func TestSearchTools_SortAndOrder(t *testing.T) {
+	t.Run("passes column sorts and direction through", func(t *testing.T) {
+		beers, breweries := &pagedBeerService{}, &pagedBreweryService{}
+		toolHandlers := handlers.NewToolHandlers(nil, beers, breweries)
+
+		_, err := toolHandlers.SearchBeers(context.Background(), map[string]interface{}{
+			"style": "IPA", "sort": "ABV", "order": "desc",
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if beers.query.Sort != services.SortByABV || beers.query.Order != services.OrderDesc {
+			t.Errorf("Expected abv desc, got %q %q", beers.query.Sort, beers.query.Order)
+		}
+
+		_, err = toolHandlers.FindBreweries(context.Background(), map[string]interface{}{
+			"country": "South Africa", "sort": "city",
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if breweries.query.Sort != services.SortByCity || breweries.query.Order != services.OrderAsc {
+			t.Errorf("Expected city asc, got %q %q", breweries.query.Sort, breweries.query.Order)
+		}
+	})
+
+	t.Run("rejects sorts the tool does not support", func(t *testing.T) {
+		toolHandlers := handlers.NewToolHandlers(nil, &pagedBeerService{}, &pagedBreweryService{})
+		ctx := context.Background()
+
+		_, beerErr := toolHandlers.SearchBeers(ctx, map[string]interface{}{"style": "IPA", "sort": "city"})
+		_, breweryErr := toolHandlers.FindBreweries(ctx, map[string]interface{}{"name": "x", "sort": "abv"})
+		_, orderErr := toolHandlers.SearchBeers(ctx, map[string]interface{}{"style": "IPA", "order": "random"})
+		for _, err := range []error{beerErr, breweryErr, orderErr} {
+			mcpErr := &mcp.Error{}
+			if !errors.As(err, &mcpErr) || mcpErr.Code != mcp.InvalidParams ||
+				!strings.Contains(mcpErr.Message, "must be one of") {
+				t.Errorf("Expected InvalidParams sort error, got %v", err)
+			}
+		}
+	})
+
+	t.Run("schemas list the accepted values", func(t *testing.T) {
+		toolHandlers := handlers.NewToolHandlers(nil, nil, nil)
+		want := map[string][]string{
+			"search_beers":   services.BeerSortKeys,
+			"find_breweries": services.BrewerySortKeys,
+		}
+		for _, tool := range toolHandlers.GetToolDefinitions() {
+			keys, ok := want[tool.Name]
+			if !ok {
+				continue
+			}
+			schema, _ := tool.InputSchema.(map[string]interface{})
+			properties, _ := schema["properties"].(map[string]interface{})
+			sort, _ := properties["sort"].(map[string]interface{})
+			order, _ := properties["order"].(map[string]interface{})
+			if !reflect.DeepEqual(sort["enum"], keys) || !reflect.DeepEqual(order["enum"], services.SortOrders) {
+				t.Errorf("%s: unexpected sort enums %v and %v", tool.Name, sort["enum"], order["enum"])
+			}
+		}
+	})
+}
+
+func TestSearchBeers_RangeFilters(t *testing.T) {
+	t.Run("maps JSON numbers and strings to bounds", func(t *testing.T) {
+		service := &pagedBeerService{total: 1}
+		toolHandlers := handlers.NewToolHandlers(nil, service, nil)
+
+		_, err := toolHandlers.SearchBeers(context.Background(), map[string]interface{}{
+			"abv_max": 5.0,
+			"ibu_min": 40,
+			"srm_max": "10",
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		q := service.query
+		if q.ABVMin != nil || q.IBUMax != nil || q.SRMMin != nil {
+			t.Errorf("Expected unset bounds to stay nil, got %+v", q)
+		}
+		if q.ABVMax == nil || *q.ABVMax != 5 || q.IBUMin == nil || *q.IBUMin != 40 ||
+			q.SRMMax == nil || *q.SRMMax != 10 {
+			t.Errorf("Expected abv_max 5, ibu_min 40, srm_max 10, got %+v", q)
+		}
+	})
+
+	tests := []struct {
+		name        string
+		args        map[string]interface{}
+		errContains string
+	}{
+		{
+			name:        "non-numeric bound",
+			args:        map[string]interface{}{"abv_min": "strong"},
+			errContains: "abv_min must be a number",
+		},
+		{
+			name:        "minimum above maximum",
+			args:        map[string]interface{}{"ibu_min": 60.0, "ibu_max": 30.0},
+			errContains: "ibu_min (60) is greater than ibu_max (30)",
+		},
+		{
+			name:        "negative bound",
+			args:        map[string]interface{}{"style": "Stout", "srm_min": -1.0},
+			errContains: "srm_min cannot be negative",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			toolHandlers := handlers.NewToolHandlers(nil, &pagedBeerService{}, nil)
+
+			_, err := toolHandlers.SearchBeers(context.Background(), tt.args)
+			var mcpErr *mcp.Error
+			if !errors.As(err, &mcpErr) || mcpErr.Code != mcp.InvalidParams {
+				t.Fatalf("Expected an InvalidParams error, got %v", err)
+			}
+			if !strings.Contains(mcpErr.Message, tt.errContains) {
+				t.Errorf("Expected error containing %q, got %q", tt.errContains, mcpErr.Message)
+			}
+		})
+	}
+}
+
+func TestSearchBeers_DescriptionAndTags(t *testing.T) {
+	valid := []struct {
+		name     string
+		args     map[string]interface{}
+		wantTags []string
+	}{
+		{"description alone", map[string]interface{}{"description": "dark chocolate"}, nil},
+		{"comma-separated tags", map[string]interface{}{"tags": "chocolate, coffee"}, []string{"chocolate", "coffee"}},
+		{"JSON array of tags", map[string]interface{}{"tags": []interface{}{"sour"}}, []string{"sour"}},
+	}
+	for _, tt := range valid {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &pagedBeerService{total: 1}
+			toolHandlers := handlers.NewToolHandlers(nil, service, nil)
+
+			if _, err := toolHandlers.SearchBeers(context.Background(), tt.args); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			wantDescription, _ := tt.args["description"].(string)
+			if service.query.Description != wantDescription || !reflect.DeepEqual(service.query.Tags, tt.wantTags) {
+				t.Errorf("Expected description %q and tags %v, got %q and %v", wantDescription, tt.wantTags,
+					service.query.Description, service.query.Tags)
+			}
+		})
+	}
+
+	toolHandlers := handlers.NewToolHandlers(nil, &pagedBeerService{}, nil)
+	_, err := toolHandlers.SearchBeers(context.Background(), map[string]interface{}{"tags": 3.0})
+	expectMCPError(t, err, mcp.InvalidParams, "tags must be a string or an array of strings")
+	_, err = toolHandlers.SearchBeers(context.Background(), map[string]interface{}{"tags": "dark fruit"})
+	expectMCPError(t, err, mcp.InvalidParams, `tag "dark fruit" must be lowercase words joined by hyphens`)
+	_, err = toolHandlers.SearchBeers(context.Background(), map[string]interface{}{"tags": " , "})
+	expectMCPError(t, err, mcp.InvalidParams, "description, tags,")
+}
+
+func TestFindBreweries_TypeFilter(t *testing.T) {
+	valid := []struct {
+		name      string
+		value     interface{}
+		wantTypes []string
+	}{
+		{name: "single type", value: "micro", wantTypes: []string{"micro"}},
+		{name: "comma-separated string", value: "micro, brewpub", wantTypes: []string{"micro", "brewpub"}},
+		{name: "JSON array", value: []interface{}{"nano", "regional"}, wantTypes: []string{"nano", "regional"}},
+	}
+	for _, tt := range valid {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &pagedBreweryService{total: 1}
+			toolHandlers := handlers.NewToolHandlers(nil, nil, service)
+
+			_, err := toolHandlers.FindBreweries(context.Background(), map[string]interface{}{"type": tt.value})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(service.query.Types, tt.wantTypes) {
+				t.Errorf("Expected types %v, got %v", tt.wantTypes, service.query.Types)
+			}
+		})
+	}
+
+	invalid := []struct {
+		name        string
+		value       interface{}
+		errContains string
+	}{
+		{name: "unknown type", value: "winery", errContains: `unknown brewery type "winery"`},
+		{name: "non-string item", value: []interface{}{"micro", 3.0}, errContains: "type must be a string or an array"},
+		{name: "wrong type", value: true, errContains: "type must be a string or an array"},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			toolHandlers := handlers.NewToolHandlers(nil, nil, &pagedBreweryService{})
+
+			_, err := toolHandlers.FindBreweries(context.Background(), map[string]interface{}{"type": tt.value})
+			var mcpErr *mcp.Error
+			if !errors.As(err, &mcpErr) || mcpErr.Code != mcp.InvalidParams {
+				t.Fatalf("Expected an InvalidParams error, got %v", err)
+			}
+			if !strings.Contains(mcpErr.Message, tt.errContains) {
+				t.Errorf("Expected error containing %q, got %q", tt.errContains, mcpErr.Message)
+			}
+		})
+	}
+}
+
+// Test that find_breweries passes several values of a location filter, and excluded types, to the
+// service, keeping single strings working.
+func TestFindBreweries_MultiValueFilters(t *testing.T) {
+	service := &pagedBreweryService{total: 1}
+	toolHandlers := handlers.NewToolHandlers(nil, nil, service)
+
+	_, err := toolHandlers.FindBreweries(context.Background(), map[string]interface{}{
+		"state":         []interface{}{"Western Cape", " ", "Gauteng"},
+		"country":       "South Africa, the Rainbow Nation",
+		"exclude_types": []interface{}{"brewpub"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	query := service.query
+	if !reflect.DeepEqual(query.State, []string{"Western Cape", "Gauteng"}) {
+		t.Errorf("Expected both states, got %v", query.State)
+	}
+	if !reflect.DeepEqual(query.Country, []string{"South Africa, the Rainbow Nation"}) {
+		t.Errorf("Expected a single country string to be one value, got %v", query.Country)
+	}
+	if !reflect.DeepEqual(query.Exclude.Types, []string{"brewpub"}) {
+		t.Errorf("Expected brewpubs to be excluded, got %v", query.Exclude.Types)
+	}
+
+	_, err = toolHandlers.FindBreweries(context.Background(), map[string]interface{}{
+		"city": []interface{}{"Durban", 3.0},
+	})
+	var mcpErr *mcp.Error
+	if !errors.As(err, &mcpErr) || !strings.Contains(mcpErr.Message, "city must be a string or an array") {
+		t.Errorf("Expected a non-string city to be rejected, got %v", err)
+	}
+}
+
+func TestFindBreweries_Distance(t *testing.T) {
+	service := &pagedBreweryService{total: 2}
+	toolHandlers := handlers.NewToolHandlers(nil, nil, service)
+
+	result, err := toolHandlers.FindBreweries(context.Background(), map[string]interface{}{
+		"latitude": -33.93, "longitude": "18.42", "radius_km": 25.0,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if service.query.Lat == nil || *service.query.Lat != -33.93 ||
+		service.query.Lng == nil || *service.query.Lng != 18.42 ||
+		service.query.RadiusKm == nil || *service.query.RadiusKm != 25 {
+		t.Errorf("Expected coordinates and radius to be passed through, got %+v", service.query)
+	}
+	for _, want := range []string{"- **Distance:** 2.5 km", "- **Distance:** 5.0 km"} {
+		if !strings.Contains(result.Content[0].Text, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, result.Content[0].Text)
+		}
+	}
+
+	invalid := []struct {
+		name        string
+		args        map[string]interface{}
+		errContains string
+	}{
+		{
+			name:        "radius without coordinates",
+			args:        map[string]interface{}{"name": "Brew", "radius_km": 10.0},
+			errContains: "radius_km needs latitude and longitude",
+		},
+		{
+			name:        "latitude only",
+			args:        map[string]interface{}{"latitude": -33.93},
+			errContains: "latitude and longitude must be given together",
+		},
+		{
+			name:        "latitude out of range",
+			args:        map[string]interface{}{"latitude": 95.0, "longitude": 18.42},
+			errContains: "latitude must be between -90 and 90",
+		},
+		{
+			name:        "non-numeric longitude",
+			args:        map[string]interface{}{"latitude": -33.93, "longitude": "east"},
+			errContains: "longitude",
+		},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			toolHandlers := handlers.NewToolHandlers(nil, nil, &pagedBreweryService{})
+
+			_, err := toolHandlers.FindBreweries(context.Background(), tt.args)
+			var mcpErr *mcp.Error
+			if !errors.As(err, &mcpErr) || mcpErr.Code != mcp.InvalidParams {
+				t.Fatalf("Expected an InvalidParams error, got %v", err)
+			}
+			if !strings.Contains(mcpErr.Message, tt.errContains) {
+				t.Errorf("Expected error containing %q, got %q", tt.errContains, mcpErr.Message)
+			}
+		})
+	}
+}
+
+// Test that find_breweries and get_brewery show the profile fields a brewery has, and leave out those
+// it has not.
+func TestBreweryProfiles(t *testing.T) {
+	ctx := context.Background()
+	breweryService := newBreweryService()
+	breweryService.Results[0].FoundedYear = 2012
+	breweryService.Results[0].Instagram = "testbrewery"
+	breweryService.Results[0].Description = "Hoppy ales by the sea."
+	breweryService.Breweries[0].Email = "hello@test.example"
+	breweryService.Breweries[0].Facebook = "testbrewery"
+	breweryService.Breweries[0].Twitter = "test_brew"
+	breweryService.Breweries[0].Description = "Hoppy ales by the sea."
+	toolHandlers := handlers.NewToolHandlers(nil, newBeerService(), breweryService)
+
+	result, err := toolHandlers.FindBreweries(ctx, map[string]interface{}{"name": "Test"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := "- **Founded:** 2012\n- **Social:** [Instagram](https://www.instagram.com/testbrewery)\n" +
+		"- **About:** Hoppy ales by the sea.\n"
+	if !strings.Contains(result.Content[0].Text, want) {
+		t.Errorf("Expected find_breweries output to contain %q, got:\n%s", want, result.Content[0].Text)
+	}
+
+	result, err = toolHandlers.GetBrewery(ctx, map[string]interface{}{"id": 1.0})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	text := result.Content[0].Text
+	for _, want := range []string{
+		"**Test Brewery** (ID 1)\n\nHoppy ales by the sea.\n\n- **Type:** micro",
+		"- **Email:** hello@test.example\n- **Social:** [Facebook](https://www.facebook.com/testbrewery), " +
+			"[X](https://x.com/test_brew)\n",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected get_brewery output to contain %q, got:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "Founded") {
+		t.Errorf("Expected no founding year when it is not known, got:\n%s", text)
+	}
+}
+
+func TestBreweryStats(t *testing.T) {
+	toolHandlers := handlers.NewToolHandlers(nil, nil, newBreweryService())
+
+	result, err := toolHandlers.BreweryStats(context.Background(), map[string]interface{}{"country": "Test Country"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, want := range []string{
+		"**Brewery statistics for Test Country**", "- **Breweries:** 3", "- **Beers:** 2",
+		"- Test Country: 2", "- Unknown: 1", "- Test Style: 2 (avg 5.3% ABV, 29 IBU)", "- _and 3 more_",
+	} {
+		if !strings.Contains(result.Content[0].Text, want) {
+			t.Errorf("Expected brewery_stats output to contain %q, got:\n%s", want, result.Content[0].Text)
+		}
+	}
+	if strings.Contains(result.Content[0].Text, "Style 9") {
+		t.Errorf("Expected the style list to be truncated, got:\n%s", result.Content[0].Text)
+	}
+}
+
+func TestSurpriseMe(t *testing.T) {
+	beerService := &servicestest.BeerService{}
+	for i := range 5 {
+		beerService.Results = append(beerService.Results, &services.BeerSearchResult{
+			ID: i + 1, Name: fmt.Sprintf("Random Beer %d", i+1), Brewery: "Test Brewery", Style: "Stout",
+		})
+	}
+	toolHandlers := handlers.NewToolHandlers(matchStyleTestData(), beerService, nil)
+	ctx := context.Background()
+
+	t.Run("Suggests one beer by default", func(t *testing.T) {
+		result, err := toolHandlers.SurpriseMe(ctx, map[string]interface{}{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		text := result.Content[0].Text
+		if !strings.Contains(text, "**1 random beer(s):**") || !strings.Contains(text, "**1. Random Beer 1**") {
+			t.Errorf("Unexpected surprise_me output:\n%s", text)
+		}
+	})
+
+	t.Run("Passes the count and filters to the beer service", func(t *testing.T) {
+		result, err := toolHandlers.SurpriseMe(ctx, map[string]interface{}{
+			"kind": "Beer", "count": float64(3), "style": "Stout", "country": "Ireland",
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		text := result.Content[0].Text
+		for _, want := range []string{"**3 random beer(s):**", "**3. Random Beer 3**", "- **Style:** Stout"} {
+			if !strings.Contains(text, want) {
+				t.Errorf("Expected surprise_me output to contain %q, got:\n%s", want, text)
+			}
+		}
+		if got := beerService.RandomFilters[len(beerService.RandomFilters)-1]; got != [2]string{"Stout", "Ireland"} {
+			t.Errorf("Expected the style and country to be passed on, got %v", got)
+		}
+	})
+
+	t.Run("Suggests distinct styles without the database", func(t *testing.T) {
+		styleHandlers := handlers.NewToolHandlers(matchStyleTestData(), nil, nil)
+		result, err := styleHandlers.SurpriseMe(ctx, map[string]interface{}{"kind": "style", "count": 2})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if count := strings.Count(result.Content[0].Text, "**BJCP Style "); count != 2 {
+			t.Errorf("Expected two style entries, got %d:\n%s", count, result.Content[0].Text)
+		}
+
+		// Asking for more styles than exist returns each of them once.
+		result, err = styleHandlers.SurpriseMe(ctx, map[string]interface{}{"kind": "style", "count": 5})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		for _, want := range []string{"21A: American IPA", "1A: American Light Lager", "29A: Fruit Beer"} {
+			if strings.Count(result.Content[0].Text, want) != 1 {
+				t.Errorf("Expected %q exactly once, got:\n%s", want, result.Content[0].Text)
+			}
+		}
+	})
+
+	t.Run("Rejects invalid arguments", func(t *testing.T) {
+		for _, args := range []map[string]interface{}{
+			{"kind": "wine"},
+			{"kind": 3},
+			{"count": 0},
+			{"count": 6},
+			{"count": "several"},
+		} {
+			_, err := toolHandlers.SurpriseMe(ctx, args)
+			var mcpErr *mcp.Error
+			if !errors.As(err, &mcpErr) || mcpErr.Code != mcp.InvalidParams {
+				t.Errorf("Expected InvalidParams for %v, got %v", args, err)
+			}
+		}
+	})
+
+	t.Run("Surfaces service errors", func(t *testing.T) {
+		errorHandlers := handlers.NewToolHandlers(nil, failingBeerService(nil), nil)
+		if _, err := errorHandlers.SurpriseMe(ctx, map[string]interface{}{}); err == nil {
+			t.Error("Expected an error when the beer service fails")
+		}
+	})
+}
+
+func TestDetailTools(t *testing.T) {
+	beers := newBeerService()
+	toolHandlers := handlers.NewToolHandlers(nil, beers, newBreweryService())
+	ctx := context.Background()
+
+	beer, err := toolHandlers.GetBeer(ctx, map[string]interface{}{"id": 1.0})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, want := range []string{
+		"**Test Beer** (ID 1)", "- **Brewery:** Test Brewery (ID 1)", "- **Location:** Test City, Test Country",
+		"- **ABV:** 5.5%", "- **SRM:** 6.0", "- **Tags:** citrus, pine", "A test beer.",
+	} {
+		if !strings.Contains(beer.Content[0].Text, want) {
+			t.Errorf("Expected get_beer output to contain %q, got:\n%s", want, beer.Content[0].Text)
+		}
+	}
+	if strings.Contains(beer.Content[0].Text, "Ingredients") {
+		t.Errorf("Expected no ingredients section for a beer without ingredients, got:\n%s", beer.Content[0].Text)
+	}
+
+	beers.Ingredients = map[int]*services.BeerIngredients{1: {
+		Hops:  []services.BeerIngredient{{Name: "Cascade", Amount: 25, Unit: "g", Timing: "60 min"}},
+		Yeast: []services.BeerIngredient{{Name: "US-05", Amount: 1, Unit: "packet"}},
+	}}
+	beer, err = toolHandlers.GetBeer(ctx, map[string]interface{}{"id": 1.0})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, want := range []string{"**Ingredients**", "*Hops*\n- Cascade: 25 g (60 min)", "*Yeast*\n- US-05: 1 packet"} {
+		if !strings.Contains(beer.Content[0].Text, want) {
+			t.Errorf("Expected get_beer output to contain %q, got:\n%s", want, beer.Content[0].Text)
+		}
+	}
+	if strings.Contains(beer.Content[0].Text, "*Fermentables*") {
+		t.Errorf("Expected no fermentables list when none are recorded, got:\n%s", beer.Content[0].Text)
+	}
+
+	brewery, err := toolHandlers.GetBrewery(ctx, map[string]interface{}{"id": "1"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, want := range []string{"**Test Brewery** (ID 1)", "- **Type:** micro", "- **Beers:** 4"} {
+		if !strings.Contains(brewery.Content[0].Text, want) {
+			t.Errorf("Expected get_brewery output to contain %q, got:\n%s", want, brewery.Content[0].Text)
+		}
+	}
+
+	errorCases := []struct {
+		name        string
+		call        func(context.Context, map[string]interface{}) (*mcp.ToolResult, error)
+		args        map[string]interface{}
+		errContains string
+	}{
+		{"missing id", toolHandlers.GetBeer, map[string]interface{}{}, "id is required"},
+		{"zero id", toolHandlers.GetBrewery, map[string]interface{}{"id": 0.0}, "id must be a positive integer"},
+		{"non-numeric id", toolHandlers.GetBeer, map[string]interface{}{"id": "abc"}, "id must be an integer"},
+		{"unknown beer", toolHandlers.GetBeer, map[string]interface{}{"id": 42.0}, "beer 42 not found"},
+		{"unknown brewery", toolHandlers.GetBrewery, map[string]interface{}{"id": 42.0}, "brewery 42 not found"},
+	}
+	for _, tt := range errorCases {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.call(ctx, tt.args)
+			if result != nil {
+				t.Errorf("Expected no result, got %+v", result)
+			}
+			var mcpErr *mcp.Error
+			if !errors.As(err, &mcpErr) || mcpErr.Code != mcp.InvalidParams {
+				t.Fatalf("Expected an InvalidParams error, got %v", err)
+			}
+			if !strings.Contains(mcpErr.Message, tt.errContains) {
+				t.Errorf("Expected error containing %q, got %q", tt.errContains, mcpErr.Message)
+			}
+		})
+	}
+
+	t.Run("service failure", func(t *testing.T) {
+		failing := handlers.NewToolHandlers(nil, failingBeerService(nil), nil)
+		_, err := failing.GetBeer(ctx, map[string]interface{}{"id": 1.0})
+		var mcpErr *mcp.Error
+		if err == nil || errors.As(err, &mcpErr) {
+			t.Fatalf("Expected a wrapped internal error, got %v", err)
+		}
+	})
+}
+
+func TestBreweryBeers(t *testing.T) {
+	toolHandlers := handlers.NewToolHandlers(nil, nil, newBreweryService())
+	ctx := context.Background()
+
+	tests := []struct {
+		name         string
+		args         map[string]interface{}
+		wantContains []string
+	}{
+		{
+			name: "by ID",
+			args: map[string]interface{}{"brewery_id": 1.0},
+			wantContains: []string{
+				"**Test Brewery** (ID 1) makes 2 beer(s):", "**1. Amber Ale** (ID 3)",
+				"- **Style:** American Amber Ale", "- **ABV:** 5.2%", "- **IBU:** 28", "**2. Test Beer** (ID 1)",
+			},
+		},
+		{
+			name:         "by name with a limit",
+			args:         map[string]interface{}{"brewery_name": "Test Brewery", "limit": 1.0},
+			wantContains: []string{"makes 2 beer(s), showing the first 1:", "**1. Amber Ale**"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := toolHandlers.BreweryBeers(ctx, tt.args)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			for _, want := range tt.wantContains {
+				if !strings.Contains(result.Content[0].Text, want) {
+					t.Errorf("Expected output to contain %q, got:\n%s", want, result.Content[0].Text)
+				}
+			}
+		})
+	}
+
+	errorCases := []struct {
+		name        string
+		args        map[string]interface{}
+		errContains string
+	}{
+		{"no brewery", map[string]interface{}{}, "brewery_id or brewery_name is required"},
+		{"both", map[string]interface{}{"brewery_id": 1.0, "brewery_name": "Test"}, "not both"},
+		{"negative ID", map[string]interface{}{"brewery_id": -1.0}, "brewery_id must be a positive integer"},
+		{"unknown ID", map[string]interface{}{"brewery_id": 9.0}, "brewery 9 not found"},
+		{"unknown name", map[string]interface{}{"brewery_name": "Nowhere"}, `brewery "Nowhere" not found`},
+		{"ambiguous name", map[string]interface{}{"brewery_name": "Brewing"}, "matches more than one brewery"},
+	}
+	for _, tt := range errorCases {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := toolHandlers.BreweryBeers(ctx, tt.args)
+			var mcpErr *mcp.Error
+			if !errors.As(err, &mcpErr) || mcpErr.Code != mcp.InvalidParams {
+				t.Fatalf("Expected an InvalidParams error, got %v", err)
+			}
+			if !strings.Contains(mcpErr.Message, tt.errContains) {
+				t.Errorf("Expected error containing %q, got %q", tt.errContains, mcpErr.Message)
+			}
+		})
+	}
+}
+
+func TestQueryTimeoutErrors(t *testing.T) {
+	ctx := context.Background()
+	timeout := fmt.Errorf("%w: search_beers took longer than 5s", services.ErrQueryTimeout)
+	toolHandlers := handlers.NewToolHandlers(nil, failingBeerService(timeout), nil)
+
+	calls := map[string]func() (*mcp.ToolResult, error){
+		"search_beers": func() (*mcp.ToolResult, error) {
+			return toolHandlers.SearchBeers(ctx, map[string]interface{}{"name": "IPA"})
+		},
+		"get_beer": func() (*mcp.ToolResult, error) {
+			return toolHandlers.GetBeer(ctx, map[string]interface{}{"id": float64(1)})
+		},
+		"surprise_me": func() (*mcp.ToolResult, error) {
+			return toolHandlers.SurpriseMe(ctx, map[string]interface{}{})
+		},
+	}
+	for name, call := range calls {
+		t.Run(name, func(t *testing.T) {
+			_, err := call()
+			var mcpErr *mcp.Error
+			if !errors.As(err, &mcpErr) || mcpErr.Code != mcp.RequestTimeout {
+				t.Fatalf("Expected a RequestTimeout error, got %v", err)
+			}
+			if !strings.Contains(mcpErr.Message, "took too long") {
+				t.Errorf("Unexpected timeout message: %s", mcpErr.Message)
+			}
+		})
+	}
+}
+
+// Test that a style's vitals keep the guidelines' units and gain their EBC and Plato equivalents.
+func TestBJCPLookup_VitalsConversions(t *testing.T) {
+	h := handlers.NewToolHandlers(loadStyleData(t), newBeerService(), newBreweryService())
+	result, err := h.BJCPLookup(context.Background(), map[string]interface{}{"style_code": "21A"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	text := result.Content[0].Text
+	for _, want := range []string{
+		"- **SRM:** 6.0 - 14.0 (11.8 - 27.6 EBC)",
+		"- **OG:** 1.056 - 1.070 (",
+		"- **FG:** 1.008 - 1.014 (",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in:\n%s", want, text)
+		}
+	}
+}
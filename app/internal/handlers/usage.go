@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/mcp"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/services"
+)
+
+// usageSummaryURI summarizes tool and resource usage for admins.
+const usageSummaryURI = "usage://summary"
+
+// maxUsageSummaryDays bounds the days a usage summary covers.
+const maxUsageSummaryDays = 90
+
+// usageRecorder adapts a services.UsageLog to mcp.UsageRecorder.
+type usageRecorder struct {
+	log *services.UsageLog
+}
+
+// NewUsageRecorder returns an mcp.UsageRecorder queuing each event on log, which drops events
+// rather than delaying requests when its buffer is full.
+func NewUsageRecorder(log *services.UsageLog) mcp.UsageRecorder {
+	return usageRecorder{log: log}
+}
+
+// RecordUsage implements mcp.UsageRecorder.
+func (r usageRecorder) RecordUsage(_ context.Context, event mcp.UsageEvent) {
+	r.log.Record(services.UsageEvent{
+		OccurredAt: event.At,
+		Kind:       event.Kind,
+		Name:       event.Name,
+		DurationMS: event.Duration.Milliseconds(),
+		ErrorCode:  event.ErrorCode,
+		Client:     event.Client,
+	})
+}
+
+// SetUsageLog enables usage://summary, summarizing log for callers passing adminToken as its
+// admin_token query parameter. The resource stays unregistered without a log, and disabled while
+// the token is empty.
+func (h *ResourceHandlers) SetUsageLog(log *services.UsageLog, adminToken string) {
+	h.usageLog = log
+	h.adminToken = adminToken
+}
+
+// usageResourceDefinition describes usage://summary.
+func usageResourceDefinition() mcp.Resource {
+	return mcp.Resource{
+		URI:  usageSummaryURI,
+		Name: "Usage Summary",
+		Description: fmt.Sprintf("Tool calls and resource reads per day with their error counts and average "+
+			"duration, and the events dropped under load; admin only, read as "+
+			"usage://summary?admin_token=...&days=7 (up to %d days)", maxUsageSummaryDays),
+		MimeType: "application/json",
+	}
+}
+
+// HandleUsageResource serves usage://summary: the tool calls and resource reads of the last days
+// days, 7 unless the days query parameter says otherwise, counted per day and tool or resource, with
+// totals per tool or resource and the events the usage log dropped or failed to write. It needs
+// the admin token as the admin_token query parameter, which the content URI leaves out.
+func (h *ResourceHandlers) HandleUsageResource(ctx context.Context, uri string) (*mcp.ResourceContent, error) {
+	path, rawQuery, _ := strings.Cut(uri, "?")
+	if path != usageSummaryURI {
+		return nil, mcp.NewMCPError(mcp.MethodNotFound, fmt.Sprintf("Usage resource not found: %s", path), nil)
+	}
+	params, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return nil, mcp.NewMCPError(mcp.InvalidParams, "Invalid usage summary query", nil)
+	}
+	if h.adminToken == "" {
+		return nil, &mcp.Error{Code: mcp.InvalidRequest, Message: "admin resources are disabled on this server"}
+	}
+	if subtle.ConstantTimeCompare([]byte(params.Get("admin_token")), []byte(h.adminToken)) != 1 {
+		return nil, &mcp.Error{Code: mcp.InvalidRequest, Message: "invalid admin_token"}
+	}
+	days := services.DefaultUsageSummaryDays
+	if value := params.Get("days"); value != "" {
+		days, err = strconv.Atoi(value)
+		if err != nil || days < 1 || days > maxUsageSummaryDays {
+			return nil, mcp.NewMCPError(mcp.InvalidParams,
+				fmt.Sprintf("days must be a whole number from 1 to %d", maxUsageSummaryDays), nil)
+		}
+	}
+
+	counts, err := h.usageLog.Summary(ctx, days)
+	if err != nil {
+		return nil, serviceError(err, "failed to summarize usage")
+	}
+	content, err := json.Marshal(map[string]interface{}{
+		"description": "Usage Summary",
+		"days":        days,
+		"by_day":      counts,
+		"totals":      usageTotals(counts),
+		"dropped":     h.usageLog.Dropped(),
+		"failed":      h.usageLog.Failed(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal usage summary: %w", err)
+	}
+	return &mcp.ResourceContent{
+		URI:      usageSummaryURI,
+		MimeType: "application/json",
+		Text:     string(content),
+	}, nil
+}
+
+// usageTotal is the usage of one tool or resource over a summary's days.
+type usageTotal struct {
+	Kind   string `json:"kind"`
+	Name   string `json:"name"`
+	Calls  int    `json:"calls"`
+	Errors int    `json:"errors"`
+}
+
+// usageTotals adds up the daily counts of each tool and resource, in the order they first appear:
+// the most called on the latest day first.
+func usageTotals(counts []*services.UsageCount) []*usageTotal {
+	totals := []*usageTotal{}
+	byName := make(map[[2]string]*usageTotal)
+	for _, count := range counts {
+		key := [2]string{count.Kind, count.Name}
+		total, ok := byName[key]
+		if !ok {
+			total = &usageTotal{Kind: count.Kind, Name: count.Name}
+			byName[key] = total
+			totals = append(totals, total)
+		}
+		total.Calls += count.Calls
+		total.Errors += count.Errors
+	}
+	return totals
+}
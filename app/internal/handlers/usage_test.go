@@ -0,0 +1,129 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/handlers"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/mcp"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/services"
+)
+
+// stubUsageSink summarizes usage as counts, recording the events written to it and the start of
+// the last summary asked for.
+type stubUsageSink struct {
+	counts []*services.UsageCount
+	events []services.UsageEvent
+	since  time.Time
+}
+
+func (s *stubUsageSink) WriteUsage(_ context.Context, events []services.UsageEvent) error {
+	s.events = append(s.events, events...)
+	return nil
+}
+
+func (s *stubUsageSink) UsageSummary(_ context.Context, since time.Time) ([]*services.UsageCount, error) {
+	s.since = since
+	return s.counts, nil
+}
+
+func TestHandleUsageResource(t *testing.T) {
+	sink := &stubUsageSink{counts: []*services.UsageCount{
+		{Day: "2026-10-16", Kind: "tool", Name: "bjcp_lookup", Calls: 3, Errors: 1, AvgDurationMS: 2.5},
+		{Day: "2026-10-15", Kind: "tool", Name: "bjcp_lookup", Calls: 2, AvgDurationMS: 4},
+		{Day: "2026-10-15", Kind: "resource", Name: "bjcp://styles", Calls: 1, AvgDurationMS: 1},
+	}}
+	usageLog := services.NewUsageLog(sink, 1, time.Hour)
+	usageLog.Record(services.UsageEvent{Kind: "tool", Name: "search_beers"})
+	usageLog.Record(services.UsageEvent{Kind: "tool", Name: "search_beers"}) // dropped
+	h := handlers.NewResourceHandlers(loadStyleData(t), newBeerService(), newBreweryService())
+
+	if _, err := readResource(h, "usage://summary"); err == nil {
+		t.Error("expected usage://summary to be unregistered without a usage log")
+	}
+	h.SetUsageLog(usageLog, testAdminToken)
+	listed := false
+	for _, resource := range h.GetResourceDefinitions() {
+		listed = listed || resource.URI == "usage://summary"
+	}
+	if !listed {
+		t.Error("expected usage://summary among the resource definitions")
+	}
+
+	res, err := readResource(h, "usage://summary?admin_token="+testAdminToken+"&days=2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.URI != "usage://summary" || strings.Contains(res.Text, testAdminToken) {
+		t.Errorf("expected the admin token left out of the content, got %q: %s", res.URI, res.Text)
+	}
+	var parsed struct {
+		Days   int                    `json:"days"`
+		ByDay  []*services.UsageCount `json:"by_day"`
+		Totals []struct {
+			Kind   string `json:"kind"`
+			Name   string `json:"name"`
+			Calls  int    `json:"calls"`
+			Errors int    `json:"errors"`
+		} `json:"totals"`
+		Dropped int `json:"dropped"`
+	}
+	if err = json.Unmarshal([]byte(res.Text), &parsed); err != nil {
+		t.Fatalf("invalid JSON response: %v", err)
+	}
+	if parsed.Days != 2 || len(parsed.ByDay) != 3 || parsed.Dropped != 1 {
+		t.Errorf("expected 2 days of counts and 1 dropped event, got %s", res.Text)
+	}
+	if len(parsed.Totals) != 2 || parsed.Totals[0].Name != "bjcp_lookup" || parsed.Totals[0].Calls != 5 ||
+		parsed.Totals[0].Errors != 1 || parsed.Totals[1].Name != "bjcp://styles" {
+		t.Errorf("expected totals per tool and resource, got %+v", parsed.Totals)
+	}
+	if today := time.Now().UTC().Truncate(24 * time.Hour); !sink.since.Equal(today.AddDate(0, 0, -1)) {
+		t.Errorf("expected the summary to start yesterday, got %v", sink.since)
+	}
+
+	tests := []struct {
+		uri     string
+		code    int
+		message string
+	}{
+		{"usage://summary", mcp.InvalidRequest, "invalid admin_token"},
+		{"usage://summary?admin_token=wrong", mcp.InvalidRequest, "invalid admin_token"},
+		{"usage://summary?admin_token=" + testAdminToken + "&days=91", mcp.InvalidParams, "days must be"},
+		{"usage://summary?admin_token=" + testAdminToken + "&days=week", mcp.InvalidParams, "days must be"},
+		{"usage://events", mcp.MethodNotFound, "Usage resource not found"},
+	}
+	for _, tt := range tests {
+		_, err = readResource(h, tt.uri)
+		expectMCPError(t, err, tt.code, tt.message)
+	}
+
+	h.SetUsageLog(usageLog, "")
+	_, err = readResource(h, "usage://summary?admin_token=")
+	expectMCPError(t, err, mcp.InvalidRequest, "admin resources are disabled")
+}
+
+func TestNewUsageRecorder(t *testing.T) {
+	sink := &stubUsageSink{}
+	usageLog := services.NewUsageLog(sink, 10, time.Hour)
+	at := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+
+	handlers.NewUsageRecorder(usageLog).RecordUsage(context.Background(), mcp.UsageEvent{
+		Kind: mcp.UsageTool, Name: "get_beer", At: at, Duration: 1500 * time.Microsecond,
+		ErrorCode: mcp.InvalidParams, Client: "brew-client/1.2",
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	usageLog.Run(ctx)
+
+	expected := services.UsageEvent{
+		OccurredAt: at, Kind: "tool", Name: "get_beer", DurationMS: 1, ErrorCode: mcp.InvalidParams,
+		Client: "brew-client/1.2",
+	}
+	if len(sink.events) != 1 || sink.events[0] != expected {
+		t.Errorf("expected %+v written, got %+v", expected, sink.events)
+	}
+}
@@ -0,0 +1,219 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/mcp"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/services"
+)
+
+// venuesNearToolDefinition describes venues_near.
+func venuesNearToolDefinition() mcp.Tool {
+	return mcp.Tool{
+		Name: "venues_near",
+		Description: "Find the taprooms and other brewery venues nearest a point, with their opening hours " +
+			"and whether each is open now",
+		InputSchema: mcp.ObjectSchema(map[string]interface{}{
+			"latitude":  mcp.NumberSchema("Latitude to search near in decimal degrees"),
+			"longitude": mcp.NumberSchema("Longitude to search near in decimal degrees"),
+			"radius_km": mcp.NumberSchema("Maximum distance in km from latitude/longitude"),
+			"limit":     mcp.IntegerSchema("Maximum number of venues (default: 20, max: 100)"),
+			"timezone":  timezoneSchema(),
+		}, []string{"latitude", "longitude"}),
+	}
+}
+
+// timezoneSchema describes the timezone argument of the tools reporting whether venues are open.
+func timezoneSchema() map[string]interface{} {
+	return mcp.StringSchema(
+		"IANA time zone the venues' hours are in, used to tell whether each is open now "+
+			"(e.g., 'Africa/Johannesburg'; default: UTC)",
+		false)
+}
+
+// parseTimezone reads the optional timezone argument, an IANA time zone name, defaulting to UTC.
+func parseTimezone(args map[string]interface{}) (*time.Location, error) {
+	name, _ := args["timezone"].(string)
+	return loadTimezone(name)
+}
+
+// loadTimezone loads the named IANA time zone, or UTC when name is empty.
+func loadTimezone(name string) (*time.Location, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return time.UTC, nil
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, &mcp.Error{
+			Code:    mcp.InvalidParams,
+			Message: fmt.Sprintf("unknown timezone %q; use an IANA name such as Africa/Johannesburg", name),
+		}
+	}
+	return location, nil
+}
+
+// VenuesNear handles the venues_near tool, listing the venues nearest a point with their hours.
+func (h *ToolHandlers) VenuesNear(ctx context.Context, args map[string]interface{}) (*mcp.ToolResult, error) {
+	var query services.VenueSearchQuery
+	for _, coordinate := range []struct {
+		key   string
+		value *float64
+	}{
+		{"latitude", &query.Lat},
+		{"longitude", &query.Lng},
+	} {
+		value, err := parseOptionalFloat(args, coordinate.key)
+		if err != nil {
+			return nil, err
+		}
+		if value == nil {
+			return nil, &mcp.Error{Code: mcp.InvalidParams, Message: coordinate.key + " is required"}
+		}
+		*coordinate.value = *value
+	}
+	radius, err := parseOptionalFloat(args, "radius_km")
+	if err != nil {
+		return nil, err
+	}
+	query.RadiusKm = radius
+	if query.Limit, err = parseLimit(args); err != nil {
+		return nil, err
+	}
+	location, err := parseTimezone(args)
+	if err != nil {
+		return nil, err
+	}
+
+	venues, err := h.breweryService.SearchVenuesNear(ctx, query)
+	if errors.Is(err, services.ErrInvalidSearchQuery) || errors.Is(err, services.ErrInvalidLimit) {
+		return nil, &mcp.Error{Code: mcp.InvalidParams, Message: err.Error()}
+	}
+	if err != nil {
+		return nil, serviceError(err, "failed to search venues")
+	}
+	if len(venues) == 0 {
+		return mcp.NewToolResult("No venues found near that point."), nil
+	}
+
+	now := h.now().In(location)
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("Found %d venue(s), nearest first:\n\n", len(venues)))
+	for i, venue := range venues {
+		response.WriteString(fmt.Sprintf("**%d. %s** (%s, brewery ID %d)\n", i+1, venue.Name, venue.Brewery,
+			venue.BreweryID))
+		writeVenueDetails(&response, &venue.Venue, now)
+		response.WriteString(fmt.Sprintf("- **Distance:** %.1f km\n\n", venue.DistanceKm))
+	}
+	response.WriteString(formatOpenNowNote(now))
+	return mcp.NewToolResult(response.String()), nil
+}
+
+// writeBreweryVenues lists a brewery's venues for get_brewery, leaving the section out when it has
+// none.
+func writeBreweryVenues(response *strings.Builder, venues []*services.Venue, now time.Time) {
+	if len(venues) == 0 {
+		return
+	}
+	response.WriteString("\n**Venues**\n")
+	for _, venue := range venues {
+		name := venue.Name
+		if venue.IsPrimary {
+			name += " (primary)"
+		}
+		response.WriteString(fmt.Sprintf("\n*%s*\n", name))
+		writeVenueDetails(response, venue, now)
+	}
+	response.WriteString("\n" + formatOpenNowNote(now))
+}
+
+// writeVenueDetails writes a venue's address, hours, and whether it is open at now.
+func writeVenueDetails(response *strings.Builder, venue *services.Venue, now time.Time) {
+	if address := formatLocation(venue.Street, venue.City, venue.State, venue.Country); address != "" {
+		response.WriteString(fmt.Sprintf("- **Address:** %s\n", address))
+	}
+	if venue.Hours == nil {
+		response.WriteString("- **Hours:** not known\n")
+		return
+	}
+	response.WriteString(fmt.Sprintf("- **Hours:** %s\n", formatVenueHours(venue.Hours)))
+	if venue.Hours.OpenAt(now) {
+		response.WriteString("- **Open now:** yes\n")
+	} else {
+		response.WriteString("- **Open now:** no\n")
+	}
+}
+
+// formatVenueHours lists the hours of each day from Monday, e.g. "Mon closed; Tue 12:00-22:00".
+func formatVenueHours(hours services.VenueHours) string {
+	days := make([]string, 0, len(services.VenueDays))
+	for _, day := range services.VenueDays {
+		value := strings.TrimSpace(hours[day])
+		if value == "" {
+			value = "closed"
+		}
+		days = append(days, fmt.Sprintf("%s%s %s", strings.ToUpper(day[:1]), day[1:], value))
+	}
+	return strings.Join(days, "; ")
+}
+
+// formatOpenNowNote states the local time "open now" was judged at.
+func formatOpenNowNote(now time.Time) string {
+	return fmt.Sprintf("_Open now as of %s (%s)._\n", now.Format("Mon 15:04"), now.Location())
+}
+
+// venueJSON is a venue in the breweries://{id} resource, with whether it is open at the time of
+// reading; open_now is left out when its hours are not known.
+type venueJSON struct {
+	*services.Venue
+	OpenNow *bool `json:"open_now,omitempty"`
+}
+
+// breweryDetailJSON is the breweries://{id} resource: the brewery with its venues and the time zone
+// their open_now is judged in.
+type breweryDetailJSON struct {
+	*services.BreweryDetail
+	Venues   []venueJSON `json:"venues"`
+	Timezone string      `json:"timezone"`
+}
+
+// newBreweryDetailJSON pairs a brewery with its venues, judging whether each is open at now.
+func newBreweryDetailJSON(
+	brewery *services.BreweryDetail,
+	venues []*services.Venue,
+	now time.Time,
+) breweryDetailJSON {
+	detail := breweryDetailJSON{
+		BreweryDetail: brewery,
+		Venues:        make([]venueJSON, 0, len(venues)),
+		Timezone:      now.Location().String(),
+	}
+	for _, venue := range venues {
+		entry := venueJSON{Venue: venue}
+		if venue.Hours != nil {
+			open := venue.Hours.OpenAt(now)
+			entry.OpenNow = &open
+		}
+		detail.Venues = append(detail.Venues, entry)
+	}
+	return detail
+}
+
+// resourceTimezone loads the percent-encoded {timezone} of a breweries://{id}?timezone={timezone}
+// URI, or UTC when the URI has none.
+func resourceTimezone(uri string, params map[string]string) (*time.Location, error) {
+	name, err := url.QueryUnescape(params["timezone"])
+	if err != nil {
+		return nil, mcp.NewMCPError(mcp.InvalidParams, fmt.Sprintf("Invalid timezone in %s", uri), nil)
+	}
+	location, err := loadTimezone(name)
+	if err != nil {
+		return nil, mcp.NewMCPError(mcp.InvalidParams, err.Error(), nil)
+	}
+	return location, nil
+}
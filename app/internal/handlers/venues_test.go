@@ -0,0 +1,181 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/handlers"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/mcp"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/services"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/services/servicestest"
+)
+
+// newVenueBreweryService returns the fake brewery service with venues for brewery 1: a primary
+// taproom that never closes and a beer garden that is always closed, so that whether each is open
+// now does not depend on when the tests run.
+func newVenueBreweryService() *servicestest.BreweryService {
+	lat, lng := -33.93, 18.42
+	always := services.VenueHours{}
+	for _, day := range services.VenueDays {
+		always[day] = "00:00-24:00"
+	}
+	taproom := &services.Venue{
+		ID: 1, BreweryID: 1, Name: "Test Taproom", Street: "1 Main Road", City: "Test City",
+		Latitude: &lat, Longitude: &lng, Hours: always, IsPrimary: true,
+	}
+	garden := &services.Venue{ID: 2, BreweryID: 1, Name: "Beer Garden", Hours: services.VenueHours{"sat": "closed"}}
+	cellar := &services.Venue{ID: 3, BreweryID: 1, Name: "Cellar Door"}
+
+	breweryService := newBreweryService()
+	breweryService.Venues = map[int][]*services.Venue{1: {taproom, garden, cellar}}
+	breweryService.NearbyVenues = []*services.VenueSearchResult{
+		{Venue: *taproom, Brewery: "Test Brewery", DistanceKm: 1.25},
+		{Venue: *garden, Brewery: "Test Brewery", DistanceKm: 3},
+	}
+	return breweryService
+}
+
+func TestGetBrewery_Venues(t *testing.T) {
+	ctx := context.Background()
+	toolHandlers := handlers.NewToolHandlers(nil, newBeerService(), newVenueBreweryService())
+
+	result, err := toolHandlers.GetBrewery(ctx, map[string]interface{}{"id": 1.0, "timezone": "Africa/Johannesburg"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	text := result.Content[0].Text
+	for _, want := range []string{
+		"**Venues**",
+		"*Test Taproom (primary)*\n- **Address:** 1 Main Road, Test City\n- **Hours:** Mon 00:00-24:00;",
+		"- **Open now:** yes",
+		"*Beer Garden*\n- **Hours:** Mon closed; Tue closed; Wed closed; Thu closed; Fri closed; Sat closed; " +
+			"Sun closed\n- **Open now:** no",
+		"*Cellar Door*\n- **Hours:** not known",
+		"(Africa/Johannesburg)",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected get_brewery output to contain %q, got:\n%s", want, text)
+		}
+	}
+
+	result, err = toolHandlers.GetBrewery(ctx, map[string]interface{}{"id": 2.0})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if strings.Contains(result.Content[0].Text, "Venues") {
+		t.Errorf("Expected no venues section for a brewery without venues, got:\n%s", result.Content[0].Text)
+	}
+
+	_, err = toolHandlers.GetBrewery(ctx, map[string]interface{}{"id": 1.0, "timezone": "Mars/Olympus_Mons"})
+	var mcpErr *mcp.Error
+	if !errors.As(err, &mcpErr) || mcpErr.Code != mcp.InvalidParams || !strings.Contains(mcpErr.Message, "timezone") {
+		t.Errorf("Expected an InvalidParams error for an unknown timezone, got %v", err)
+	}
+}
+
+func TestVenuesNear(t *testing.T) {
+	ctx := context.Background()
+	breweryService := newVenueBreweryService()
+	toolHandlers := handlers.NewToolHandlers(nil, newBeerService(), breweryService)
+
+	result, err := toolHandlers.VenuesNear(ctx, map[string]interface{}{
+		"latitude": -33.9, "longitude": "18.4", "radius_km": 5.0, "limit": 10.0,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	text := result.Content[0].Text
+	for _, want := range []string{
+		"Found 2 venue(s), nearest first",
+		"**1. Test Taproom** (Test Brewery, brewery ID 1)",
+		"- **Open now:** yes\n- **Distance:** 1.2 km",
+		"**2. Beer Garden**",
+		"(UTC)",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected venues_near output to contain %q, got:\n%s", want, text)
+		}
+	}
+	radius := 5.0
+	want := services.VenueSearchQuery{Lat: -33.9, Lng: 18.4, RadiusKm: &radius, Limit: 10}
+	if got := breweryService.VenueQueries; len(got) != 1 || got[0].Lat != want.Lat || got[0].Lng != want.Lng ||
+		got[0].RadiusKm == nil || *got[0].RadiusKm != radius || got[0].Limit != want.Limit {
+		t.Errorf("Expected the search query %+v, got %+v", want, got)
+	}
+
+	breweryService.NearbyVenues = nil
+	result, err = toolHandlers.VenuesNear(ctx, map[string]interface{}{"latitude": 0.0, "longitude": 0.0})
+	if err != nil || !strings.Contains(result.Content[0].Text, "No venues found") {
+		t.Errorf("Expected no venues, got %+v (error: %v)", result, err)
+	}
+
+	for _, tt := range []struct {
+		name        string
+		args        map[string]interface{}
+		errContains string
+	}{
+		{"missing latitude", map[string]interface{}{"longitude": 18.4}, "latitude is required"},
+		{"missing longitude", map[string]interface{}{"latitude": -33.9}, "longitude is required"},
+		{"latitude out of range", map[string]interface{}{"latitude": 95.0, "longitude": 18.4}, "latitude must be"},
+		{"negative radius", map[string]interface{}{"latitude": 1.0, "longitude": 1.0, "radius_km": -1.0}, "radius_km"},
+		{"unknown timezone", map[string]interface{}{"latitude": 1.0, "longitude": 1.0, "timezone": "Nowhere"}, "timezone"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := toolHandlers.VenuesNear(ctx, tt.args)
+			var mcpErr *mcp.Error
+			if !errors.As(err, &mcpErr) || mcpErr.Code != mcp.InvalidParams {
+				t.Fatalf("Expected an InvalidParams error, got %v", err)
+			}
+			if !strings.Contains(mcpErr.Message, tt.errContains) {
+				t.Errorf("Expected error containing %q, got %q", tt.errContains, mcpErr.Message)
+			}
+		})
+	}
+}
+
+func TestBreweryDetailResource_Venues(t *testing.T) {
+	h := handlers.NewResourceHandlers(nil, &servicestest.BeerService{}, newVenueBreweryService())
+
+	res, err := readResource(h, "breweries://1?timezone=Africa%2FJohannesburg")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var detail struct {
+		Name     string `json:"name"`
+		Timezone string `json:"timezone"`
+		Venues   []struct {
+			Name    string            `json:"name"`
+			Hours   map[string]string `json:"hours"`
+			OpenNow *bool             `json:"open_now"`
+		} `json:"venues"`
+	}
+	if err = json.Unmarshal([]byte(res.Text), &detail); err != nil {
+		t.Fatalf("invalid brewery resource: %v", err)
+	}
+	if detail.Name != "Test Brewery" || detail.Timezone != "Africa/Johannesburg" || len(detail.Venues) != 3 {
+		t.Fatalf("unexpected brewery resource: %s", res.Text)
+	}
+	if open := detail.Venues[0].OpenNow; open == nil || !*open || detail.Venues[0].Hours["mon"] != "00:00-24:00" {
+		t.Errorf("expected the taproom to be open with its hours, got %s", res.Text)
+	}
+	if open := detail.Venues[1].OpenNow; open == nil || *open {
+		t.Errorf("expected the beer garden to be closed, got %s", res.Text)
+	}
+	if detail.Venues[2].OpenNow != nil {
+		t.Errorf("expected open_now to be left out for unknown hours, got %s", res.Text)
+	}
+
+	res, err = readResource(h, "breweries://2")
+	if err != nil || !strings.Contains(res.Text, `"venues":[]`) || !strings.Contains(res.Text, `"timezone":"UTC"`) {
+		t.Errorf("expected an empty venue list in UTC, got %+v (error: %v)", res, err)
+	}
+
+	_, err = readResource(h, "breweries://1?timezone=Nowhere")
+	var mcpErr *mcp.Error
+	if !errors.As(err, &mcpErr) || mcpErr.Code != mcp.InvalidParams {
+		t.Errorf("expected an InvalidParams error for an unknown timezone, got %v", err)
+	}
+}
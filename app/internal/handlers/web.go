@@ -10,10 +10,14 @@ import (
 	"io"
 	"io/fs"
 	"net/http"
-	"os"
 	"regexp"
+	"sync/atomic"
 	"time"
 
+	"github.com/CharlRitter/brewsource-mcp/app/internal/mcp"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/services"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/version"
+	"github.com/CharlRitter/brewsource-mcp/app/pkg/data"
 	"github.com/microcosm-cc/bluemonday"
 	"github.com/redis/go-redis/v9"
 	"github.com/russross/blackfriday/v2"
@@ -22,11 +26,32 @@ import (
 //go:embed templates/*
 var templateFS embed.FS
 
+// staticFS holds the web assets served at /static/.
+//
+//go:embed static/*
+var staticFS embed.FS
+
+// staticFiles serves staticFS with the static/ prefix removed.
+var staticFiles = http.FileServer(http.FS(mustSub(staticFS, "static")))
+
 // WebHandlers provides HTTP handlers for web pages.
 type WebHandlers struct {
 	templates   *template.Template
 	db          interface{}
 	redisClient interface{}
+
+	// Catalogs served by the REST API; see SetServices
+	beerService    services.BeerServiceInterface
+	breweryService services.BreweryServiceInterface
+
+	// Readiness state; see SetMigrated, SetBJCPService, and StartDraining
+	migrated    atomic.Bool
+	bjcpService atomic.Pointer[data.BJCPService]
+	draining    atomic.Bool
+
+	reporter mcp.ErrorReporter // optional; see SetErrorReporter
+
+	exportStore services.ExportStore // serves the files of export_results; see SetExportStore
 }
 
 // NewWebHandlers creates a new instance of WebHandlers.
@@ -123,14 +148,18 @@ func IsRedisHealthy(redisClient interface{}) bool {
 	return false
 }
 
-// ServeStatic serves static assets (favicon, SVGs, etc.) from the embedded templates directory at /static/.
+// ServeStatic serves static assets (favicon, SVGs, etc.) embedded from the static directory. Mounted at
+// /static/, it expects the prefix stripped.
 func (w *WebHandlers) ServeStatic(writer http.ResponseWriter, r *http.Request) {
-	staticFS, err := fs.Sub(templateFS, "templates")
+	staticFiles.ServeHTTP(writer, r)
+}
+
+func mustSub(fsys fs.FS, dir string) fs.FS {
+	sub, err := fs.Sub(fsys, dir)
 	if err != nil {
-		http.Error(writer, "Static assets not found", http.StatusInternalServerError)
-		return
+		panic(err)
 	}
-	http.FileServer(http.FS(staticFS)).ServeHTTP(writer, r)
+	return sub
 }
 
 // UpdateReadmeLinks updates relative links to absolute GitHub URLs and sets target="_blank" for all links.
@@ -181,21 +210,73 @@ func (w *WebHandlers) ServeAPI(writer http.ResponseWriter, r *http.Request) {
 		"version":     GetVersion(),
 		"description": "Model Context Protocol server for brewing resources",
 		"endpoints": map[string]string{
-			"mcp":    "/mcp",
-			"health": "/health",
-			"api":    "/api",
+			"mcp":       "/mcp",
+			"health":    "/health",
+			"liveness":  "/healthz",
+			"readiness": "/readyz",
+			"api":       "/api",
+			"rest":      "/api/v1",
+			"openapi":   "/api/openapi.json",
+			"docs":      "/api/docs",
 		},
 		"phase": "Phase 1 MVP",
 		"tools": []string{
 			"bjcp_lookup",
 			"search_beers",
 			"find_breweries",
+			"get_beer",
+			"get_brewery",
+			"brewery_beers",
+			"venues_near",
+			"brewery_stats",
+			"match_style",
+			"check_beer_style",
+			"bjcp_style_search",
+			"compare_styles",
+			"style_examples",
+			"related_styles",
+			"recommend_beers",
+			"surprise_me",
+			"lookup_ingredient",
+			"unit_convert",
+			"mash_water",
+			"carbonation_calculator",
+			"refractometer_correction",
+			"hydrometer_correction",
+			"ibu_calculator",
+			"srm_calculator",
+			"volume_calculator",
+			"abv_calculator",
+			"attenuation_calculator",
+			"yeast_starter",
+			"water_profile",
+			"brew_day_sheet",
+			"add_brewery",
+			"add_beer",
+			"delete_brewery",
+			"restore_brewery",
+			"sync_breweries",
+			"seed_catalog",
+			"import_catalog",
+			"get_job_status",
+			"reload_data",
 		},
 		"resources": []string{
 			"bjcp://styles",
+			"bjcp://styles/{code}/examples",
 			"bjcp://categories",
+			"bjcp://categories/{name}",
 			"beers://catalog",
+			"beers://export",
+			"beers://style-violations",
+			"beers://{id}",
 			"breweries://directory",
+			"breweries://{id}",
+			"breweries://{id}/beers",
+			"stats://overview",
+			"ingredients://hops/{name}",
+			"ingredients://fermentables/{name}",
+			"ingredients://yeast/{name}",
 		},
 		"connection": map[string]interface{}{
 			"http":                "https://" + r.Host + "/mcp",
@@ -210,43 +291,17 @@ func (w *WebHandlers) ServeAPI(writer http.ResponseWriter, r *http.Request) {
 	_, _ = writer.Write(jsonBytes)
 }
 
-// ServeHealth handles the /health endpoint for both JSON requests.
-func (w *WebHandlers) ServeHealth(writer http.ResponseWriter, _ *http.Request) {
-	writer.Header().Set("Content-Type", "application/json")
-	writer.WriteHeader(http.StatusOK)
-	response := map[string]interface{}{
-		"status":  "healthy",
-		"service": "brewsource-mcp",
-		"version": GetVersion(),
-	}
-	jsonBytes, err := json.MarshalIndent(response, "", "  ")
-	if err != nil {
-		http.Error(writer, "Internal server error", http.StatusInternalServerError)
-		return
-	}
-	_, _ = writer.Write(jsonBytes)
-}
-
-// GetVersion reads the application version from the VERSION file.
+// GetVersion returns the application version; see version.Get.
 func GetVersion() string {
-	// Read version from VERSION file
-	version := "dev"
-	if vbytes, verr := os.ReadFile("VERSION"); verr == nil {
-		version = string(vbytes)
-		version = string([]byte(version))
-		version = string([]rune(version)[0 : len(version)-1]) // Remove trailing newline
-	}
-	return version
+	return version.Get().Version
 }
 
-// ServeVersion handles the /version endpoint for both JSON requests.
+// ServeVersion handles the /version endpoint, reporting the version, commit, build date, and Go
+// version of the binary.
 func (w *WebHandlers) ServeVersion(writer http.ResponseWriter, _ *http.Request) {
 	writer.Header().Set("Content-Type", "application/json")
 	writer.WriteHeader(http.StatusOK)
-	response := map[string]interface{}{
-		"version": GetVersion(),
-	}
-	jsonBytes, err := json.MarshalIndent(response, "", "  ")
+	jsonBytes, err := json.MarshalIndent(version.Get(), "", "  ")
 	if err != nil {
 		http.Error(writer, "Internal server error", http.StatusInternalServerError)
 		return
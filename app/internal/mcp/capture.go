@@ -0,0 +1,238 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/config"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/requestid"
+)
+
+// redactedValue replaces the values of redacted fields in captured exchanges.
+const redactedValue = "[REDACTED]"
+
+// Levels of logging/setLevel, from RFC 5424 as the MCP specification has them.
+var logLevels = []string{"debug", "info", "notice", "warning", "error", "critical", "alert", "emergency"}
+
+// Exchange is a request to the MCP endpoint and its response, with the values of redacted fields
+// replaced and each cut to the capture's size limit.
+type Exchange struct {
+	At         time.Time `json:"at"`
+	RequestID  string    `json:"request_id,omitempty"`
+	Client     string    `json:"client,omitempty"`
+	Method     string    `json:"method,omitempty"`
+	DurationMS int64     `json:"duration_ms"`
+	Request    string    `json:"request"`
+	Response   string    `json:"response,omitempty"` // empty for notifications
+}
+
+// capture redacts and cuts exchanges for logging, and keeps the last of them for /debug/mcp-trace.
+type capture struct {
+	all      bool // capture every exchange, not only those of clients asking for it
+	redact   []string
+	query    *regexp.Regexp // matches the redacted query parameters in URIs; nil without any
+	maxBytes int
+
+	mu      sync.Mutex
+	clients map[string]bool // the client labels that turned capture on with logging/setLevel
+	trace   []Exchange      // a ring of the last exchanges
+	next    int             // where the next exchange goes in trace, once it is full
+}
+
+// newCapture creates the capture cfg configures.
+func newCapture(cfg config.CaptureConfig) *capture {
+	c := &capture{
+		all:      cfg.Enabled,
+		maxBytes: cfg.MaxBytes,
+		clients:  make(map[string]bool),
+		trace:    make([]Exchange, 0, cfg.TraceSize),
+	}
+	for _, field := range strings.Split(cfg.Redact, ",") {
+		if field = strings.ToLower(strings.TrimSpace(field)); field != "" {
+			c.redact = append(c.redact, field)
+		}
+	}
+	if len(c.redact) > 0 {
+		names := make([]string, len(c.redact))
+		for i, field := range c.redact {
+			names[i] = regexp.QuoteMeta(field)
+		}
+		c.query = regexp.MustCompile(`(?i)([?&](?:` + strings.Join(names, "|") + `)=)[^&#\s"]*`)
+	}
+	return c
+}
+
+// enabled reports whether the exchanges of the caller of ctx are captured.
+func (c *capture) enabled(ctx context.Context) bool {
+	if c.all {
+		return true
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.clients[ClientLabel(ctx)]
+}
+
+// setClient turns capture on or off for the caller of ctx.
+func (c *capture) setClient(ctx context.Context, on bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if on {
+		c.clients[ClientLabel(ctx)] = true
+	} else {
+		delete(c.clients, ClientLabel(ctx))
+	}
+}
+
+// clean returns the JSON data with the values of the redacted fields, and of the redacted query
+// parameters of URIs, replaced, cut to the size limit. Data that is not JSON is only cut.
+func (c *capture) clean(data []byte) string {
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber() // keeping large IDs exact
+	if err := decoder.Decode(&value); err == nil {
+		var redacted bytes.Buffer
+		encoder := json.NewEncoder(&redacted)
+		encoder.SetEscapeHTML(false) // keeping URIs readable
+		if err = encoder.Encode(c.redactValue(value)); err == nil {
+			data = bytes.TrimSuffix(redacted.Bytes(), []byte("\n"))
+		}
+	}
+	return c.cut(string(data))
+}
+
+// redactValue replaces the values of the redacted fields of value, at any depth.
+func (c *capture) redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if slices.Contains(c.redact, strings.ToLower(key)) {
+				v[key] = redactedValue
+			} else {
+				v[key] = c.redactValue(field)
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = c.redactValue(item)
+		}
+	case string:
+		if c.query != nil && strings.ContainsAny(v, "?&") {
+			return c.query.ReplaceAllString(v, "${1}"+redactedValue)
+		}
+	}
+	return value
+}
+
+// cut shortens text to the size limit on a rune boundary, noting how much was left out.
+func (c *capture) cut(text string) string {
+	if len(text) <= c.maxBytes {
+		return text
+	}
+	end := c.maxBytes
+	for end > 0 && !utf8.RuneStart(text[end]) {
+		end--
+	}
+	return fmt.Sprintf("%s... (%d more bytes)", text[:end], len(text)-end)
+}
+
+// add keeps exchange in the trace, in place of the oldest once it is full.
+func (c *capture) add(exchange Exchange) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if cap(c.trace) == 0 {
+		return
+	}
+	if len(c.trace) < cap(c.trace) {
+		c.trace = append(c.trace, exchange)
+		return
+	}
+	c.trace[c.next] = exchange
+	c.next = (c.next + 1) % len(c.trace)
+}
+
+// exchanges returns the trace, oldest first.
+func (c *capture) exchanges() []Exchange {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append(slices.Clone(c.trace[c.next:]), c.trace[:c.next]...)
+}
+
+// captureExchange logs the request data and its response, and keeps them in the trace, when the
+// exchanges of the caller of ctx are captured.
+func (s *Server) captureExchange(ctx context.Context, data []byte, response *Message, started time.Time) {
+	if !s.capture.enabled(ctx) {
+		return
+	}
+	exchange := Exchange{
+		At:         started,
+		RequestID:  requestid.FromContext(ctx),
+		Client:     ClientLabel(ctx),
+		DurationMS: time.Since(started).Milliseconds(),
+		Request:    s.capture.clean(data),
+	}
+	var msg struct {
+		Method string `json:"method"`
+	}
+	if json.Unmarshal(data, &msg) == nil {
+		exchange.Method = msg.Method
+	}
+	if response != nil {
+		if responseData, err := json.Marshal(response); err == nil {
+			exchange.Response = s.capture.clean(responseData)
+		}
+	}
+	requestid.Logger(ctx).WithField("client", exchange.Client).WithField("method", exchange.Method).
+		WithField("request", exchange.Request).WithField("response", exchange.Response).
+		WithField("duration_ms", exchange.DurationMS).Info("MCP exchange")
+	s.capture.add(exchange)
+}
+
+// handleSetLevel answers logging/setLevel. The server logs no messages to clients, but a client
+// setting the debug level has its exchanges captured, see Exchange, and any other level stops
+// that. Clients are told apart by their label, see WithClientLabel.
+func (s *Server) handleSetLevel(ctx context.Context, msg *Message) *Message {
+	var req SetLevelRequest
+	if msg.Params != nil {
+		paramData, _ := json.Marshal(msg.Params)
+		if err := json.Unmarshal(paramData, &req); err != nil {
+			return NewErrorResponse(msg.ID, NewMCPError(InvalidParams, "Invalid setLevel parameters", nil))
+		}
+	}
+	if !slices.Contains(logLevels, req.Level) {
+		return NewErrorResponse(msg.ID, NewMCPError(InvalidParams,
+			fmt.Sprintf("Unknown log level %q, expected one of: %s", req.Level, strings.Join(logLevels, ", ")), nil))
+	}
+	s.capture.setClient(ctx, req.Level == "debug")
+	return NewResponse(msg.ID, map[string]interface{}{})
+}
+
+// TraceHandler serves the last captured exchanges as JSON, oldest first, to callers sending
+// adminToken as a bearer token in the Authorization header. It answers 404 Not Found while the
+// token is empty, and 401 Unauthorized to callers without it.
+func (s *Server) TraceHandler(adminToken string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if adminToken == "" {
+			http.NotFound(w, r)
+			return
+		}
+		token, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(token), []byte(adminToken)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="brewsource"`)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"exchanges": s.capture.exchanges()})
+	})
+}
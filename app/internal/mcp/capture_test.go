@@ -0,0 +1,160 @@
+package mcp_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/config"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/mcp"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/requestid"
+	logtest "github.com/sirupsen/logrus/hooks/test"
+)
+
+const testTraceToken = "s3cret"
+
+// captureServer serves an echo tool, capturing exchanges as capture configures.
+func captureServer(capture config.CaptureConfig) *mcp.Server {
+	cfg := config.Default()
+	cfg.MCP.Capture = capture
+	s := mcp.NewServer(nil, nil, cfg)
+	s.RegisterToolHandler("echo", func(_ context.Context, args map[string]interface{}) (*mcp.ToolResult, error) {
+		text, _ := args["text"].(string)
+		return &mcp.ToolResult{Content: []mcp.ToolContent{{Type: "text", Text: text}}}, nil
+	})
+	return s
+}
+
+// readTrace reads the captured exchanges from the trace endpoint.
+func readTrace(t *testing.T, s *mcp.Server) []mcp.Exchange {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/debug/mcp-trace", nil)
+	req.Header.Set("Authorization", "Bearer "+testTraceToken)
+	rec := httptest.NewRecorder()
+	s.TraceHandler(testTraceToken).ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", rec.Code)
+	}
+	var trace struct {
+		Exchanges []mcp.Exchange `json:"exchanges"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &trace); err != nil {
+		t.Fatalf("Invalid trace JSON: %v", err)
+	}
+	return trace.Exchanges
+}
+
+// Test that captured exchanges are logged and traced with their request ID, with redacted fields
+// and query parameters replaced and long payloads cut, and that the trace keeps the last ones.
+func TestCapture_RedactsAndTraces(t *testing.T) {
+	hook := logtest.NewGlobal()
+	t.Cleanup(hook.Reset)
+	s := captureServer(config.CaptureConfig{
+		Enabled: true, Redact: "admin_token, Password", MaxBytes: 300, TraceSize: 2,
+	})
+	ctx := requestid.NewContext(context.Background(), "req-capture")
+
+	s.ProcessMessage(ctx, []byte(`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`))
+	s.ProcessMessage(ctx, []byte(`{"jsonrpc":"2.0","id":9007199254740993,"method":"tools/call",`+
+		`"params":{"name":"echo","arguments":{"text":"hi","admin_token":"abc","nested":{"password":"pw"}}}}`))
+	s.ProcessMessage(ctx, []byte(`{"jsonrpc":"2.0","id":3,"method":"tools/call",`+
+		`"params":{"name":"echo","arguments":{"text":"usage://summary?days=2&admin_token=abc&x=`+
+		strings.Repeat("é", 200)+`"}}}`))
+
+	exchanges := readTrace(t, s)
+	if len(exchanges) != 2 {
+		t.Fatalf("Expected the last 2 exchanges, got %d", len(exchanges))
+	}
+	call, long := exchanges[0], exchanges[1]
+	if call.RequestID != "req-capture" || call.Method != "tools/call" {
+		t.Errorf("Expected the tools/call tagged with its request ID, got %+v", call)
+	}
+	if strings.Contains(call.Request, "abc") || strings.Contains(call.Request, `"pw"`) ||
+		!strings.Contains(call.Request, `"admin_token":"[REDACTED]"`) ||
+		!strings.Contains(call.Request, `"password":"[REDACTED]"`) {
+		t.Errorf("Expected the token and password redacted, got %s", call.Request)
+	}
+	if !strings.Contains(call.Request, "9007199254740993") || !strings.Contains(call.Response, `"text":"hi"`) {
+		t.Errorf("Expected the exact ID and the response, got %s and %s", call.Request, call.Response)
+	}
+	for _, payload := range []string{long.Request, long.Response} {
+		if strings.Contains(payload, "abc") || !strings.Contains(payload, "admin_token=[REDACTED]&x=") {
+			t.Errorf("Expected the admin_token query parameter redacted, got %s", payload)
+		}
+		if !strings.Contains(payload, "more bytes)") || len(payload) > 330 {
+			t.Errorf("Expected the payload cut to 300 bytes, got %d: %s", len(payload), payload)
+		}
+	}
+
+	entry := hook.LastEntry()
+	if entry == nil || entry.Message != "MCP exchange" || entry.Data[requestid.Field] != "req-capture" ||
+		entry.Data["request"] != long.Request {
+		t.Errorf("Expected the exchange logged with its request ID, got %+v", entry)
+	}
+}
+
+// Test that a client turns capture on for itself with logging/setLevel debug, and off with another
+// level, without capturing other clients.
+func TestCapture_SetLevel(t *testing.T) {
+	s := captureServer(config.Default().MCP.Capture)
+	alice := mcp.WithClientLabel(context.Background(), "alice")
+	bob := mcp.WithClientLabel(context.Background(), "bob")
+	setLevel := func(ctx context.Context, level string) *mcp.Message {
+		data, _ := json.Marshal(mcp.NewMessage("logging/setLevel", mcp.SetLevelRequest{Level: level}))
+		return s.ProcessMessage(ctx, data)
+	}
+	call := []byte(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"echo"}}`)
+
+	s.ProcessMessage(alice, call)
+	if len(readTrace(t, s)) != 0 {
+		t.Fatal("Expected nothing captured by default")
+	}
+	if response := setLevel(alice, "debug"); response.Error != nil {
+		t.Fatalf("Unexpected error: %v", response.Error)
+	}
+	s.ProcessMessage(alice, call)
+	s.ProcessMessage(bob, call)
+	exchanges := readTrace(t, s)
+	if len(exchanges) != 2 || exchanges[0].Method != "logging/setLevel" || exchanges[1].Client != "alice" ||
+		exchanges[1].Method != "tools/call" {
+		t.Errorf("Expected alice's setLevel and call alone captured, got %+v", exchanges)
+	}
+
+	setLevel(alice, "info")
+	s.ProcessMessage(alice, call)
+	if exchanges = readTrace(t, s); len(exchanges) != 2 {
+		t.Errorf("Expected capture to stop once the info level was set, got %+v", exchanges)
+	}
+	if response := setLevel(bob, "verbose"); response.Error == nil || response.Error.Code != mcp.InvalidParams {
+		t.Errorf("Expected an InvalidParams error for an unknown level, got %+v", response)
+	}
+}
+
+func TestTraceHandler_RequiresAdminToken(t *testing.T) {
+	s := captureServer(config.Default().MCP.Capture)
+	tests := []struct {
+		name          string
+		adminToken    string
+		authorization string
+		wantStatus    int
+	}{
+		{"disabled without an admin token", "", "Bearer ", http.StatusNotFound},
+		{"missing token", testTraceToken, "", http.StatusUnauthorized},
+		{"wrong token", testTraceToken, "Bearer wrong", http.StatusUnauthorized},
+		{"admin token", testTraceToken, "Bearer " + testTraceToken, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/debug/mcp-trace", nil)
+			req.Header.Set("Authorization", tt.authorization)
+			rec := httptest.NewRecorder()
+			s.TraceHandler(tt.adminToken).ServeHTTP(rec, req)
+			if rec.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+		})
+	}
+}
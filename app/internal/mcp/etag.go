@@ -0,0 +1,45 @@
+package mcp
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// ResourceVersioner is implemented by resource registries that know the ETag of some resources
+// without reading them, typically because the resource derives from data with a version such as a
+// fingerprint. The server then answers a read whose ifNoneMatch holds that ETag without calling the
+// handler, and lists the ETag in resources/list. Resources it returns "" for are tagged by a hash of
+// their content once read.
+type ResourceVersioner interface {
+	ResourceETag(uri string) string
+}
+
+// ContentETag returns an ETag for content: a hash of its MIME type, text, and blob.
+func ContentETag(content *ResourceContent) string {
+	hash := sha256.New()
+	for _, part := range []string{content.MimeType, content.Text, content.Blob} {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil)[:16])
+}
+
+// ETagMatches reports whether the If-None-Match value ifNoneMatch holds etag. Like the HTTP header,
+// it may list several comma-separated ETags, quoted or weak (W/"..."), or be * to match any.
+func ETagMatches(ifNoneMatch, etag string) bool {
+	if etag == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" {
+			return true
+		}
+		candidate = strings.Trim(strings.TrimPrefix(candidate, "W/"), `"`)
+		if candidate == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,109 @@
+package mcp_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/mcp"
+)
+
+// versionedRegistry serves mock:// resources, knowing the ETag of mock://versioned without reading
+// it, and counts the reads.
+type versionedRegistry struct {
+	reads int
+}
+
+func (r *versionedRegistry) RegisterResourceHandlers(s *mcp.Server) {
+	s.RegisterResourceHandler("mock://*", func(_ context.Context, uri string) (*mcp.ResourceContent, error) {
+		r.reads++
+		return &mcp.ResourceContent{URI: uri, MimeType: "text/plain", Text: "resource"}, nil
+	})
+}
+
+func (r *versionedRegistry) GetResourceDefinitions() []mcp.Resource { return nil }
+
+func (r *versionedRegistry) ResourceETag(uri string) string {
+	if uri == "mock://versioned" || uri == "bjcp://styles" {
+		return "v1"
+	}
+	return ""
+}
+
+func TestETagMatches(t *testing.T) {
+	tests := []struct {
+		ifNoneMatch string
+		expected    bool
+	}{
+		{"abc", true},
+		{`"abc"`, true},
+		{`W/"abc"`, true},
+		{`"xyz", "abc"`, true},
+		{"*", true},
+		{`"xyz"`, false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := mcp.ETagMatches(tt.ifNoneMatch, "abc"); got != tt.expected {
+			t.Errorf("ETagMatches(%q): expected %v, got %v", tt.ifNoneMatch, tt.expected, got)
+		}
+	}
+	if mcp.ETagMatches("*", "") {
+		t.Error("expected no match without an ETag")
+	}
+}
+
+func TestReadResource_ETags(t *testing.T) {
+	registry := &versionedRegistry{}
+	s := mcp.NewServer(nil, registry, nil)
+	read := func(params string) map[string]interface{} {
+		resp := s.ProcessMessage(context.Background(),
+			[]byte(`{"jsonrpc":"2.0","id":1,"method":"resources/read","params":`+params+`}`))
+		if resp.Error != nil {
+			t.Fatalf("unexpected error: %+v", resp.Error)
+		}
+		data, _ := json.Marshal(resp.Result)
+		var result map[string]interface{}
+		_ = json.Unmarshal(data, &result)
+		return result
+	}
+
+	result := read(`{"uri":"mock://versioned","ifNoneMatch":"\"v1\""}`)
+	if result["notModified"] != true || result["etag"] != "v1" || registry.reads != 0 {
+		t.Errorf("expected a known ETag to answer without a read, got %v after %d reads", result, registry.reads)
+	}
+
+	result = read(`{"uri":"mock://versioned","ifNoneMatch":"v0"}`)
+	contents, _ := result["contents"].([]interface{})
+	if len(contents) != 1 || contents[0].(map[string]interface{})["etag"] != "v1" {
+		t.Errorf("expected the content with ETag v1, got %v", result)
+	}
+
+	content, err := s.ReadResource(context.Background(), "mock://plain")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if content.ETag != mcp.ContentETag(&mcp.ResourceContent{MimeType: "text/plain", Text: "resource"}) {
+		t.Errorf("expected a content hash ETag, got %q", content.ETag)
+	}
+	result = read(`{"uri":"mock://plain","ifNoneMatch":"` + content.ETag + `"}`)
+	if result["notModified"] != true {
+		t.Errorf("expected a matching content hash to be not modified, got %v", result)
+	}
+
+	resp := s.ProcessMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":1,"method":"resources/list"}`))
+	if data, _ := json.Marshal(resp.Result); strings.Count(string(data), `"etag":"v1"`) != 1 {
+		t.Errorf("expected resources/list to carry the known ETag, got %s", data)
+	}
+}
+
+func TestContentETag(t *testing.T) {
+	a := mcp.ContentETag(&mcp.ResourceContent{MimeType: "text/csv", Text: "a,b"})
+	if a != mcp.ContentETag(&mcp.ResourceContent{URI: "other://", MimeType: "text/csv", Text: "a,b"}) {
+		t.Error("expected the ETag to depend only on the content")
+	}
+	if a == mcp.ContentETag(&mcp.ResourceContent{MimeType: "text/markdown", Text: "a,b"}) {
+		t.Error("expected the ETag to change with the MIME type")
+	}
+}
@@ -0,0 +1,107 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/config"
+)
+
+// ToolLoad is the number of calls of a tool running and waiting for a slot, for gauges.
+type ToolLoad struct {
+	InFlight int64 `json:"in_flight"`
+	Queued   int64 `json:"queued"`
+}
+
+// toolLimiter caps the concurrent calls of one tool, queueing a bounded number of calls beyond the
+// cap for up to a timeout.
+type toolLimiter struct {
+	slots    chan struct{} // one element per running call
+	queue    int64
+	timeout  time.Duration
+	inFlight atomic.Int64
+	queued   atomic.Int64
+}
+
+// newToolLimiter creates a limiter for limit, queueing calls for up to timeout.
+func newToolLimiter(limit config.ToolLimit, timeout time.Duration) *toolLimiter {
+	return &toolLimiter{
+		slots:   make(chan struct{}, limit.Concurrency),
+		queue:   int64(limit.Queue),
+		timeout: timeout,
+	}
+}
+
+// acquire takes a slot for a call of tool, waiting in the queue when they are all taken and the
+// queue has room. It returns a ServerBusy error when the queue is full, or when the wait times out
+// or ctx is done first. Every successful acquire must be followed by release.
+func (l *toolLimiter) acquire(ctx context.Context, tool string) error {
+	select {
+	case l.slots <- struct{}{}:
+		l.inFlight.Add(1)
+		return nil
+	default:
+	}
+	if l.queued.Add(1) > l.queue {
+		l.queued.Add(-1)
+		return l.busy(tool)
+	}
+	defer l.queued.Add(-1)
+
+	timer := time.NewTimer(l.timeout)
+	defer timer.Stop()
+	select {
+	case l.slots <- struct{}{}:
+		l.inFlight.Add(1)
+		return nil
+	case <-timer.C:
+		return l.busy(tool)
+	case <-ctx.Done():
+		return l.busy(tool)
+	}
+}
+
+// release frees the slot taken by acquire.
+func (l *toolLimiter) release() {
+	l.inFlight.Add(-1)
+	<-l.slots
+}
+
+// busy is the error refusing a call of tool, suggesting a retry once a queued call would have
+// given up waiting.
+func (l *toolLimiter) busy(tool string) *Error {
+	return NewMCPError(ServerBusy, fmt.Sprintf("Server busy: too many concurrent %s calls, retry later", tool),
+		map[string]interface{}{"retry_after_ms": l.timeout.Milliseconds()})
+}
+
+// load returns the calls running and queued.
+func (l *toolLimiter) load() ToolLoad {
+	return ToolLoad{InFlight: l.inFlight.Load(), Queued: l.queued.Load()}
+}
+
+// callTool calls handler for the tool name, within its concurrency limit if it has one.
+func (s *Server) callTool(
+	ctx context.Context,
+	name string,
+	handler ToolHandler,
+	arguments map[string]interface{},
+) (*ToolResult, error) {
+	if limiter := s.limiters[name]; limiter != nil {
+		if err := limiter.acquire(ctx, name); err != nil {
+			return nil, err
+		}
+		defer limiter.release()
+	}
+	return handler(ctx, arguments)
+}
+
+// ToolLoad returns the calls running and queued of each tool with a concurrency limit.
+func (s *Server) ToolLoad() map[string]ToolLoad {
+	load := make(map[string]ToolLoad, len(s.limiters))
+	for name, limiter := range s.limiters {
+		load[name] = limiter.load()
+	}
+	return load
+}
@@ -0,0 +1,119 @@
+package mcp_test
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/config"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/mcp"
+)
+
+// blockingTool is a tool handler that blocks each call until release is closed, telling started.
+type blockingTool struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func newBlockingTool() *blockingTool {
+	return &blockingTool{started: make(chan struct{}, 10), release: make(chan struct{})}
+}
+
+func (b *blockingTool) handle(_ context.Context, _ map[string]interface{}) (*mcp.ToolResult, error) {
+	b.started <- struct{}{}
+	<-b.release
+	return &mcp.ToolResult{Content: []mcp.ToolContent{{Type: "text", Text: "ok"}}}, nil
+}
+
+// limitedServer serves a blocking search_beers tool limited as limit, queueing calls for up to
+// timeout, and an unlimited bjcp_lookup tool.
+func limitedServer(limit config.ToolLimit, timeout time.Duration) (*mcp.Server, *blockingTool) {
+	cfg := config.Default()
+	cfg.MCP.ToolLimits = config.ToolLimits{"search_beers": limit}
+	cfg.MCP.ToolQueueTimeout = timeout
+	s := mcp.NewServer(nil, nil, cfg)
+	tool := newBlockingTool()
+	s.RegisterToolHandler("search_beers", tool.handle)
+	s.RegisterToolHandler("bjcp_lookup", func(_ context.Context, _ map[string]interface{}) (*mcp.ToolResult, error) {
+		return &mcp.ToolResult{Content: []mcp.ToolContent{{Type: "text", Text: "ok"}}}, nil
+	})
+	return s, tool
+}
+
+func callTool(s *mcp.Server, name string) *mcp.Message {
+	data, _ := json.Marshal(mcp.NewMessage("tools/call", mcp.CallToolRequest{Name: name}))
+	return s.ProcessMessage(context.Background(), data)
+}
+
+// waitForLoad waits until the load of search_beers is want.
+func waitForLoad(t *testing.T, s *mcp.Server, want mcp.ToolLoad) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for s.ToolLoad()["search_beers"] != want {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected search_beers load %+v, got %+v", want, s.ToolLoad()["search_beers"])
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// Test that the call beyond the concurrency limit queues until a running call finishes, and that
+// calls beyond the queue are refused as busy at once.
+func TestToolLimit_Queues(t *testing.T) {
+	s, tool := limitedServer(config.ToolLimit{Concurrency: 2, Queue: 1}, time.Minute)
+
+	var wg sync.WaitGroup
+	responses := make(chan *mcp.Message, 3)
+	for range 3 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			responses <- callTool(s, "search_beers")
+		}()
+	}
+	<-tool.started
+	<-tool.started
+	waitForLoad(t, s, mcp.ToolLoad{InFlight: 2, Queued: 1})
+
+	busy := callTool(s, "search_beers")
+	if busy.Error == nil || busy.Error.Code != mcp.ServerBusy {
+		t.Fatalf("Expected a ServerBusy error with the queue full, got %+v", busy)
+	}
+	if data, _ := busy.Error.Data.(map[string]interface{}); data["retry_after_ms"] != int64(60000) {
+		t.Errorf("Expected a suggested retry delay, got %v", busy.Error.Data)
+	}
+	if response := callTool(s, "bjcp_lookup"); response.Error != nil {
+		t.Errorf("Expected tools without a limit to be called at once, got %+v", response.Error)
+	}
+
+	close(tool.release)
+	wg.Wait()
+	close(responses)
+	for response := range responses {
+		if response.Error != nil {
+			t.Errorf("Expected the running and queued calls to succeed, got %+v", response.Error)
+		}
+	}
+	waitForLoad(t, s, mcp.ToolLoad{})
+}
+
+// Test that a queued call is refused as busy once the queue timeout passes.
+func TestToolLimit_QueueTimeout(t *testing.T) {
+	s, tool := limitedServer(config.ToolLimit{Concurrency: 1, Queue: 1}, 20*time.Millisecond)
+	defer close(tool.release)
+
+	go callTool(s, "search_beers")
+	<-tool.started
+
+	started := time.Now()
+	response := callTool(s, "search_beers")
+	if response.Error == nil || response.Error.Code != mcp.ServerBusy {
+		t.Fatalf("Expected a ServerBusy error once the queue timeout passed, got %+v", response)
+	}
+	if waited := time.Since(started); waited < 20*time.Millisecond {
+		t.Errorf("Expected the call to wait out the queue timeout, waited %v", waited)
+	}
+	waitForLoad(t, s, mcp.ToolLoad{InFlight: 1})
+}
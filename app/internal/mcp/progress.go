@@ -0,0 +1,35 @@
+package mcp
+
+import "context"
+
+type progressTokenKey struct{}
+
+// WithProgressToken returns a copy of ctx carrying the progress token the client sent with a tool
+// call, for tools whose work outlives the call to report their progress against.
+func WithProgressToken(ctx context.Context, token interface{}) context.Context {
+	return context.WithValue(ctx, progressTokenKey{}, token)
+}
+
+// ProgressToken returns the progress token carried by ctx, or nil when the client sent none.
+func ProgressToken(ctx context.Context) interface{} {
+	return ctx.Value(progressTokenKey{})
+}
+
+// NotifyProgress sends a notifications/progress notification tagged with token, and reports whether
+// one was sent: it is not when token is nil or the transport cannot push notifications, see
+// SetNotifier.
+func (s *Server) NotifyProgress(token interface{}, progress, total float64, message string) bool {
+	s.mu.RLock()
+	notify := s.notifier
+	s.mu.RUnlock()
+	if notify == nil || token == nil {
+		return false
+	}
+	notify(NewMessage(ProgressNotification, ProgressParams{
+		ProgressToken: token,
+		Progress:      progress,
+		Total:         total,
+		Message:       message,
+	}))
+	return true
+}
@@ -0,0 +1,65 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/requestid"
+)
+
+// ErrorReporter is told about failures worth a developer's attention, such as panics recovered while
+// serving a request, for forwarding to an error tracker such as Sentry. ctx is the context of the
+// request, carrying its request ID, see requestid.FromContext. ReportError must be safe to call
+// concurrently.
+type ErrorReporter interface {
+	ReportError(ctx context.Context, err error)
+}
+
+// PanicError is a panic recovered while serving a request, with the stack of the goroutine that
+// panicked.
+type PanicError struct {
+	Value interface{}
+	Stack []byte
+}
+
+// NewPanicError returns a PanicError for the value recovered from a panic, with the current stack.
+// Call it in the deferred function that recovered, so that the stack shows where the panic began.
+func NewPanicError(value interface{}) *PanicError {
+	return &PanicError{Value: value, Stack: debug.Stack()}
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Unwrap returns the value the handler panicked with when it is an error, such as a runtime error.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// SetErrorReporter sets the reporter told about panics recovered while processing messages. Without
+// one, they are only logged.
+func (s *Server) SetErrorReporter(reporter ErrorReporter) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reporter = reporter
+}
+
+// recoverPanic logs a panic recovered while processing msg with its stack and the request ID carried
+// by ctx, reports it, and returns the InternalError response sent instead of a result. The panic
+// value is not sent, as it may describe the server's internals.
+func (s *Server) recoverPanic(ctx context.Context, msg *Message, recovered interface{}) *Message {
+	panicErr := NewPanicError(recovered)
+	requestid.Logger(ctx).WithField("method", msg.Method).WithField("stack", string(panicErr.Stack)).
+		Errorf("Recovered from %v", panicErr)
+
+	s.mu.RLock()
+	reporter := s.reporter
+	s.mu.RUnlock()
+	if reporter != nil {
+		reporter.ReportError(ctx, panicErr)
+	}
+	return NewErrorResponse(msg.ID, NewMCPError(InternalError, "Internal error", nil))
+}
@@ -0,0 +1,93 @@
+package mcp_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/mcp"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/requestid"
+)
+
+// recordingReporter records the errors reported to it with the request IDs of their contexts.
+type recordingReporter struct {
+	mu         sync.Mutex
+	errs       []error
+	requestIDs []string
+}
+
+func (r *recordingReporter) ReportError(ctx context.Context, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.errs = append(r.errs, err)
+	r.requestIDs = append(r.requestIDs, requestid.FromContext(ctx))
+}
+
+// Test that a panicking tool handler gets an InternalError response over HTTP, is reported, and
+// does not stop the server serving the next request.
+func TestProcessMessage_RecoversPanic(t *testing.T) {
+	s := mcp.NewServer(nil, nil, nil)
+	s.RegisterToolHandler("panicking_tool", func(_ context.Context, _ map[string]interface{}) (*mcp.ToolResult, error) {
+		var result *mcp.ToolResult
+		result.IsError = true // nil pointer dereference
+		return result, nil
+	})
+	s.RegisterToolHandler("working_tool", func(_ context.Context, _ map[string]interface{}) (*mcp.ToolResult, error) {
+		return &mcp.ToolResult{Content: []mcp.ToolContent{{Type: "text", Text: "ok"}}}, nil
+	})
+	reporter := &recordingReporter{}
+	s.SetErrorReporter(reporter)
+
+	post := func(body string) (*http.Response, *mcp.Message) {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPost, "/mcp", bytes.NewBufferString(body))
+		req = req.WithContext(requestid.NewContext(req.Context(), "req-panic"))
+		rec := httptest.NewRecorder()
+		s.HandleHTTP(rec, req)
+		var msg mcp.Message
+		if err := json.Unmarshal(rec.Body.Bytes(), &msg); err != nil {
+			t.Fatalf("Expected a JSON-RPC response, got %q: %v", rec.Body.String(), err)
+		}
+		return rec.Result(), &msg
+	}
+
+	resp, msg := post(`{"jsonrpc":"2.0","id":7,"method":"tools/call","params":{"name":"panicking_tool"}}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected status 200 carrying the error response, got %d", resp.StatusCode)
+	}
+	if msg.Error == nil || msg.Error.Code != mcp.InternalError || msg.Error.Message != "Internal error" {
+		t.Fatalf("Expected an InternalError response, got %+v", msg)
+	}
+	if id, _ := msg.ID.(float64); id != 7 {
+		t.Errorf("Expected the response to answer request 7, got %v", msg.ID)
+	}
+	if data, _ := msg.Error.Data.(map[string]interface{}); data["request_id"] != "req-panic" {
+		t.Errorf("Expected the request ID in the error data, got %v", msg.Error.Data)
+	}
+	if strings.Contains(msg.Error.Message, "nil pointer") {
+		t.Error("The panic value should not be sent to the client")
+	}
+
+	if len(reporter.errs) != 1 || reporter.requestIDs[0] != "req-panic" {
+		t.Fatalf("Expected one report with the request ID, got %v for %v", reporter.errs, reporter.requestIDs)
+	}
+	var panicErr *mcp.PanicError
+	if !errors.As(reporter.errs[0], &panicErr) || !bytes.Contains(panicErr.Stack, []byte("recover_test.go")) {
+		t.Errorf("Expected a PanicError with the stack of the handler, got %v", reporter.errs[0])
+	}
+	var runtimeErr interface{ RuntimeError() }
+	if !errors.As(reporter.errs[0], &runtimeErr) {
+		t.Errorf("Expected the runtime error to be unwrapped from %v", reporter.errs[0])
+	}
+
+	_, msg = post(`{"jsonrpc":"2.0","id":8,"method":"tools/call","params":{"name":"working_tool"}}`)
+	if msg.Error != nil || msg.Result == nil {
+		t.Errorf("Expected the next request to be served, got %+v", msg)
+	}
+}
@@ -9,15 +9,39 @@ import (
 	"io"
 	"net/http"
 	"sync"
+	"time"
 
+	"github.com/CharlRitter/brewsource-mcp/app/internal/config"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/requestid"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/version"
+	"github.com/CharlRitter/brewsource-mcp/app/pkg/brewing"
 	"github.com/sirupsen/logrus"
 )
 
 // Server represents the MCP server.
 type Server struct {
-	tools     map[string]ToolHandler
-	resources map[string]ResourceHandler
-	mu        sync.RWMutex
+	tools        map[string]ToolHandler
+	resources    map[string]ResourceHandler
+	templates    []*resourceTemplate
+	toolRegistry ToolHandlerRegistry
+	versioner    ResourceVersioner
+	describer    ServerDescriber // optional; the tool registry, when it implements it
+	maxRequest   int64           // bytes
+	reporter     ErrorReporter   // optional; see SetErrorReporter
+	usage        UsageRecorder   // optional; see SetUsageRecorder
+	versionInfo  *version.Info   // reported by initialize in place of version.Get; see SetVersionInfo
+	mu           sync.RWMutex
+
+	// Unit systems preferred by client label, set during initialize; see setUnitPreference
+	units map[string]brewing.UnitSystem
+
+	// Concurrency limits by tool name, set up by NewServer and read-only after
+	limiters map[string]*toolLimiter
+	capture  *capture // exchanges logged for debugging; see captureExchange
+
+	// Resource subscriptions, offered only when a transport can push notifications; see SetNotifier
+	notifier      func(*Message)
+	subscriptions map[string]bool
 }
 
 // ToolHandlerRegistry defines the interface for tool handler registration.
@@ -32,35 +56,70 @@ type ResourceHandlerRegistry interface {
 	GetResourceDefinitions() []Resource
 }
 
-// NewServer creates a new MCP server instance with optional tool and resource registries.
-func NewServer(toolRegistry ToolHandlerRegistry, resourceRegistry ResourceHandlerRegistry) *Server {
+// ServerDescriber is implemented by tool registries that describe the data the server serves, such
+// as the version of a data set, as string pairs listed under serverInfo.metadata of the initialize
+// response. It is asked at each initialize, so that it may follow data reloaded since startup.
+type ServerDescriber interface {
+	ServerMetadata() map[string]string
+}
+
+// NewServer creates a new MCP server instance with optional tool and resource registries, limiting
+// requests and concurrent tool calls as cfg sets, or as config.Default does when cfg is nil.
+func NewServer(
+	toolRegistry ToolHandlerRegistry,
+	resourceRegistry ResourceHandlerRegistry,
+	cfg *config.Config,
+) *Server {
+	if cfg == nil {
+		cfg = config.Default()
+	}
 	server := &Server{
-		tools:     make(map[string]ToolHandler),
-		resources: make(map[string]ResourceHandler),
+		tools:         make(map[string]ToolHandler),
+		resources:     make(map[string]ResourceHandler),
+		toolRegistry:  toolRegistry,
+		maxRequest:    cfg.MCP.MaxRequestBytes,
+		subscriptions: make(map[string]bool),
+		limiters:      make(map[string]*toolLimiter),
+		units:         make(map[string]brewing.UnitSystem),
+		capture:       newCapture(cfg.MCP.Capture),
 	}
 
 	// Register handlers if registries are provided
 	if toolRegistry != nil {
 		toolRegistry.RegisterToolHandlers(server)
+		server.describer, _ = toolRegistry.(ServerDescriber)
+	}
+	for name, limit := range cfg.MCP.ToolLimits {
+		if _, ok := server.tools[name]; !ok && toolRegistry != nil {
+			logrus.Warnf("Concurrency limit set for unknown tool %s", name)
+		}
+		server.limiters[name] = newToolLimiter(limit, cfg.MCP.ToolQueueTimeout)
 	}
 	if resourceRegistry != nil {
 		resourceRegistry.RegisterResourceHandlers(server)
+		server.versioner, _ = resourceRegistry.(ResourceVersioner)
 	}
 
 	return server
 }
 
-// HandleHTTP handles MCP requests over HTTP POST.
+// HandleHTTP handles MCP requests over HTTP POST. A request body larger than the server's limit is
+// rejected with 413 Request Entity Too Large.
 func (s *Server) HandleHTTP(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
 		return
 	}
 	defer r.Body.Close()
-	ctx := r.Context()
+	ctx := WithClientLabel(r.Context(), httpClientLabel(r))
 	var data []byte
 	var err error
-	data, err = io.ReadAll(r.Body)
+	data, err = io.ReadAll(http.MaxBytesReader(w, r.Body, s.maxRequest))
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		http.Error(w, "Request body too large", http.StatusRequestEntityTooLarge)
+		return
+	}
 	if err != nil {
 		http.Error(w, "Failed to read request body", http.StatusBadRequest)
 		return
@@ -104,9 +163,50 @@ func (s *Server) RegisterResourceHandler(pattern string, handler ResourceHandler
 	logrus.Debugf("Registered resource handler: %s", pattern)
 }
 
-// ProcessMessage processes a single MCP message and returns the response message.
+// SetNotifier sets how notifications are sent to the client, for transports that can push messages
+// outside a response. Resource subscriptions are offered only once it is set.
+func (s *Server) SetNotifier(notify func(*Message)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.notifier = notify
+}
+
+// SetVersionInfo sets the build information initialize reports in serverInfo, which is otherwise
+// that of the running binary, such as a fixed version in tests with golden output.
+func (s *Server) SetVersionInfo(info version.Info) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.versionInfo = &info
+}
+
+// NotifyResourceUpdated sends a notifications/resources/updated notification for uri when the
+// client has subscribed to it, and reports whether one was sent.
+func (s *Server) NotifyResourceUpdated(uri string) bool {
+	s.mu.RLock()
+	notify, subscribed := s.notifier, s.subscriptions[uri]
+	s.mu.RUnlock()
+	if notify == nil || !subscribed {
+		return false
+	}
+	notify(NewMessage(ResourceUpdatedNotification, SubscribeRequest{URI: uri}))
+	return true
+}
+
+// ProcessMessage processes a single MCP message and returns the response message. When ctx carries
+// a request ID, it is added to the data of an error response, see withRequestID. A handler that
+// panics gets an InternalError response, see recoverPanic, and the server keeps serving. The
+// exchange is logged when it is captured, see captureExchange.
 func (s *Server) ProcessMessage(ctx context.Context, data []byte) *Message {
-	logrus.Debugf("Processing message: %s", string(data))
+	started := time.Now()
+	response := withRequestID(ctx, s.processMessage(ctx, data))
+	s.captureExchange(ctx, data, response, started)
+	return response
+}
+
+func (s *Server) processMessage(ctx context.Context, data []byte) (response *Message) {
+	if logrus.IsLevelEnabled(logrus.DebugLevel) {
+		requestid.Logger(ctx).Debugf("Processing message: %s", s.capture.clean(data))
+	}
 
 	msg, err := ValidateMessage(data)
 	if err != nil {
@@ -118,23 +218,39 @@ func (s *Server) ProcessMessage(ctx context.Context, data []byte) *Message {
 		return NewErrorResponse(nil, mcpErr)
 	}
 
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			response = s.recoverPanic(ctx, msg, recovered)
+		}
+	}()
+
 	switch msg.Method {
 	case "initialize":
-		return s.handleInitialize(msg)
+		return s.handleInitialize(ctx, msg)
 	case "tools/list":
 		return s.handleToolsList(msg)
 	case "tools/call":
 		return s.handleToolsCall(ctx, msg)
 	case "resources/list":
 		return s.handleResourcesList(msg)
+	case "resources/templates/list":
+		return s.handleResourceTemplatesList(msg)
 	case "resources/read":
 		return s.handleResourcesRead(ctx, msg)
+	case "resources/subscribe":
+		return s.handleResourcesSubscribe(msg, true)
+	case "resources/unsubscribe":
+		return s.handleResourcesSubscribe(msg, false)
+	case "completion/complete":
+		return s.handleComplete(ctx, msg)
+	case "logging/setLevel":
+		return s.handleSetLevel(ctx, msg)
 	default:
 		return NewErrorResponse(msg.ID, NewMCPError(MethodNotFound, "Method not found", nil))
 	}
 }
 
-func (s *Server) handleInitialize(msg *Message) *Message {
+func (s *Server) handleInitialize(ctx context.Context, msg *Message) *Message {
 	var req InitializeRequest
 	if msg.Params != nil {
 		paramData, _ := json.Marshal(msg.Params)
@@ -143,7 +259,21 @@ func (s *Server) handleInitialize(msg *Message) *Message {
 		}
 	}
 
-	logrus.Infof("Initialize request from client: %s v%s", req.ClientInfo.Name, req.ClientInfo.Version)
+	requestid.Logger(ctx).Infof("Initialize request from client: %s v%s", req.ClientInfo.Name, req.ClientInfo.Version)
+	s.setUnitPreference(ctx, req.ClientInfo)
+
+	s.mu.RLock()
+	canNotify := s.notifier != nil
+	versionInfo := s.versionInfo
+	canComplete := false
+	for _, template := range s.templates {
+		canComplete = canComplete || len(template.completions) > 0
+	}
+	s.mu.RUnlock()
+	if versionInfo == nil {
+		info := version.Get()
+		versionInfo = &info
+	}
 
 	response := InitializeResponse{
 		ProtocolVersion: "2024-11-05",
@@ -152,60 +282,32 @@ func (s *Server) handleInitialize(msg *Message) *Message {
 				ListChanged: false,
 			},
 			Resources: &ResourcesCapability{
-				Subscribe:   false,
+				Subscribe:   canNotify,
 				ListChanged: false,
 			},
+			// logging/setLevel debug captures the client's exchanges
+			Logging: &LoggingCapability{},
 		},
 		ServerInfo: ServerInfo{
-			Name:    "BrewSource MCP Server",
-			Version: "1.0.0",
+			Name: "BrewSource MCP Server",
+			Info: *versionInfo,
 		},
 	}
+	if canComplete {
+		response.Capabilities.Completions = &CompletionsCapability{}
+	}
+	if s.describer != nil {
+		response.ServerInfo.Metadata = s.describer.ServerMetadata()
+	}
 
 	return NewResponse(msg.ID, response)
 }
 
 func (s *Server) handleToolsList(msg *Message) *Message {
-	// Get tool definitions from registered handlers - for now use the hardcoded ones
-	// In the future, this could be made dynamic
-	tools := []Tool{
-		{
-			Name:        "bjcp_lookup",
-			Description: "Look up BJCP beer style information by style code or name",
-			InputSchema: ObjectSchema(map[string]interface{}{
-				"style_code": StringSchema("BJCP style code (e.g., '21A' for American IPA)", false),
-				"style_name": StringSchema("BJCP style name (e.g., 'American IPA')", false),
-			}, []string{}),
-		},
-		{
-			Name:        "search_beers",
-			Description: "Search for commercial beers by name, style, brewery, or location",
-			InputSchema: ObjectSchema(map[string]interface{}{
-				"name":     StringSchema("Beer name to search for", false),
-				"style":    StringSchema("Beer style to filter by", false),
-				"brewery":  StringSchema("Brewery name to filter by", false),
-				"location": StringSchema("Location (city, state, country) to filter by", false),
-				"limit": map[string]interface{}{
-					"type":        "integer",
-					"description": "Maximum number of results (default: 20, max: 100)",
-				},
-			}, []string{}),
-		},
-		{
-			Name:        "find_breweries",
-			Description: "Find breweries by name, location, city, state, or country",
-			InputSchema: ObjectSchema(map[string]interface{}{
-				"name":     StringSchema("Brewery name to search for", false),
-				"location": StringSchema("General location search (city, state, country)", false),
-				"city":     StringSchema("City to filter by", false),
-				"state":    StringSchema("State/province to filter by", false),
-				"country":  StringSchema("Country to filter by", false),
-				"limit": map[string]interface{}{
-					"type":        "integer",
-					"description": "Maximum number of results (default: 20, max: 100)",
-				},
-			}, []string{}),
-		},
+	// Always return an array, even when no registry is configured
+	tools := []Tool{}
+	if s.toolRegistry != nil {
+		tools = append(tools, s.toolRegistry.GetToolDefinitions()...)
 	}
 
 	return NewResponse(msg.ID, map[string]interface{}{
@@ -235,12 +337,19 @@ func (s *Server) handleToolsCall(ctx context.Context, msg *Message) *Message {
 		return NewErrorResponse(msg.ID, NewMCPError(MethodNotFound, fmt.Sprintf("Tool not found: %s", req.Name), nil))
 	}
 
-	result, err := handler(ctx, req.Arguments)
+	toolCtx := s.withUnitPreference(ctx)
+	if req.Meta != nil && req.Meta.ProgressToken != nil {
+		toolCtx = WithProgressToken(toolCtx, req.Meta.ProgressToken)
+	}
+	started := time.Now()
+	result, err := s.callTool(toolCtx, req.Name, handler, req.Arguments)
+	s.recordUsage(ctx, UsageTool, req.Name, started, err)
 	if err != nil {
 		mcpErr := &Error{}
 		if errors.As(err, &mcpErr) {
 			return NewErrorResponse(msg.ID, mcpErr)
 		}
+		requestid.Logger(ctx).WithError(err).WithField("tool", req.Name).Error("Tool call failed")
 		return NewErrorResponse(msg.ID, NewMCPError(InternalError, err.Error(), nil))
 	}
 
@@ -275,6 +384,10 @@ func (s *Server) handleResourcesList(msg *Message) *Message {
 		},
 	}
 
+	for i := range resources {
+		resources[i].ETag = s.knownETag(resources[i].URI)
+	}
+
 	return NewResponse(msg.ID, map[string]interface{}{
 		"resources": resources,
 	})
@@ -299,32 +412,28 @@ func (s *Server) handleResourcesRead(ctx context.Context, msg *Message) *Message
 		return NewErrorResponse(msg.ID, NewMCPError(InvalidParams, "Malformed resource URI", nil))
 	}
 
-	// Simple pattern matching for resources
-	var handler ResourceHandler
-	s.mu.RLock()
-	for pattern, h := range s.resources {
-		if matchesPattern(pattern, req.URI) {
-			handler = h
-			break
+	// A known ETag answers a conditional read without rendering the resource
+	started := time.Now()
+	if req.IfNoneMatch != "" && s.resolveResource(req.URI) != nil {
+		if etag := s.knownETag(req.URI); ETagMatches(req.IfNoneMatch, etag) {
+			s.recordUsage(ctx, UsageResource, req.URI, started, nil)
+			return notModified(msg.ID, etag)
 		}
 	}
-	s.mu.RUnlock()
-
-	if handler == nil {
-		return NewErrorResponse(
-			msg.ID,
-			NewMCPError(MethodNotFound, fmt.Sprintf("Resource not found: %s", req.URI), nil),
-		)
-	}
 
-	content, err := handler(ctx, req.URI)
+	content, err := s.ReadResource(ctx, req.URI)
+	s.recordUsage(ctx, UsageResource, req.URI, started, err)
 	if err != nil {
 		mcpErr := &Error{}
 		if errors.As(err, &mcpErr) {
 			return NewErrorResponse(msg.ID, mcpErr)
 		}
+		requestid.Logger(ctx).WithError(err).WithField("uri", req.URI).Error("Resource read failed")
 		return NewErrorResponse(msg.ID, NewMCPError(InternalError, err.Error(), nil))
 	}
+	if ETagMatches(req.IfNoneMatch, content.ETag) {
+		return notModified(msg.ID, content.ETag)
+	}
 
 	return NewResponse(msg.ID, map[string]interface{}{
 		"contents": []interface{}{
@@ -333,11 +442,131 @@ func (s *Server) handleResourcesRead(ctx context.Context, msg *Message) *Message
 				"mimeType": content.MimeType,
 				"text":     content.Text,
 				"blob":     content.Blob,
+				"etag":     content.ETag,
 			},
 		},
 	})
 }
 
+// notModified is the result of a resources/read whose ifNoneMatch holds the resource's current
+// ETag: no contents, only the ETag the client already has.
+func notModified(id interface{}, etag string) *Message {
+	return NewResponse(id, map[string]interface{}{
+		"contents":    []interface{}{},
+		"notModified": true,
+		"etag":        etag,
+	})
+}
+
+// knownETag returns the ETag the resource registry knows for uri without reading it, or "".
+func (s *Server) knownETag(uri string) string {
+	if s.versioner == nil {
+		return ""
+	}
+	return s.versioner.ResourceETag(uri)
+}
+
+// ReadResource reads the resource at uri from the handler registered for exactly that URI, else
+// from the first resource template it matches, else from a handler registered for a prefix pattern
+// such as bjcp://*. A URI no handler serves is a MethodNotFound error. Content without an ETag gets
+// the one the registry knows, see ResourceVersioner, or else ContentETag.
+func (s *Server) ReadResource(ctx context.Context, uri string) (*ResourceContent, error) {
+	read := s.resolveResource(uri)
+	if read == nil {
+		return nil, NewMCPError(MethodNotFound, fmt.Sprintf("Resource not found: %s", uri), nil)
+	}
+	content, err := read(ctx)
+	if err != nil || content == nil {
+		return content, err
+	}
+	if content.ETag == "" {
+		content.ETag = s.knownETag(uri)
+	}
+	if content.ETag == "" {
+		content.ETag = ContentETag(content)
+	}
+	return content, nil
+}
+
+// resolveResource finds the handler serving uri, as ReadResource describes, and returns a call of
+// it, or nil when no handler serves uri.
+func (s *Server) resolveResource(uri string) func(ctx context.Context) (*ResourceContent, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if handler, exact := s.resources[uri]; exact {
+		return func(ctx context.Context) (*ResourceContent, error) { return handler(ctx, uri) }
+	}
+	for _, template := range s.templates {
+		if params, ok := template.match(uri); ok {
+			handler := template.handler
+			return func(ctx context.Context) (*ResourceContent, error) { return handler(ctx, uri, params) }
+		}
+	}
+	for pattern, handler := range s.resources {
+		if matchesPattern(pattern, uri) {
+			return func(ctx context.Context) (*ResourceContent, error) { return handler(ctx, uri) }
+		}
+	}
+	return nil
+}
+
+// handleResourcesSubscribe records (or with subscribe unset, removes) a subscription to updates of
+// a resource URI.
+func (s *Server) handleResourcesSubscribe(msg *Message, subscribe bool) *Message {
+	var req SubscribeRequest
+	if msg.Params != nil {
+		paramData, _ := json.Marshal(msg.Params)
+		if err := json.Unmarshal(paramData, &req); err != nil {
+			return NewErrorResponse(msg.ID, NewMCPError(InvalidParams, "Invalid subscription parameters", nil))
+		}
+	}
+	if req.URI == "" {
+		return NewErrorResponse(msg.ID, NewMCPError(InvalidParams, "Missing resource URI", nil))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.notifier == nil {
+		return NewErrorResponse(
+			msg.ID,
+			NewMCPError(MethodNotFound, "Resource subscriptions are not supported by this transport", nil),
+		)
+	}
+	if subscribe {
+		s.subscriptions[req.URI] = true
+	} else {
+		delete(s.subscriptions, req.URI)
+	}
+	return NewResponse(msg.ID, map[string]interface{}{})
+}
+
+// withRequestID adds the request ID carried by ctx to the data of an error response, as the
+// request_id key of a map, so a client can quote it when reporting the error. Data that is neither
+// empty nor a map is left as it is.
+func withRequestID(ctx context.Context, response *Message) *Message {
+	id := requestid.FromContext(ctx)
+	if id == "" || response == nil || response.Error == nil {
+		return response
+	}
+	// Handlers may return shared errors, so the error is copied rather than changed
+	mcpErr := *response.Error
+	switch data := mcpErr.Data.(type) {
+	case nil:
+		mcpErr.Data = map[string]interface{}{requestid.Field: id}
+	case map[string]interface{}:
+		withID := make(map[string]interface{}, len(data)+1)
+		for key, value := range data {
+			withID[key] = value
+		}
+		withID[requestid.Field] = id
+		mcpErr.Data = withID
+	default:
+		return response
+	}
+	response.Error = &mcpErr
+	return response
+}
+
 // Simple pattern matching - in production, use a proper router.
 func matchesPattern(pattern, uri string) bool {
 	if pattern == "*" {
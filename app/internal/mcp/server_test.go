@@ -12,7 +12,9 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/CharlRitter/brewsource-mcp/app/internal/config"
 	"github.com/CharlRitter/brewsource-mcp/app/internal/mcp"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/requestid"
 )
 
 // Helper function to validate tools list response structure.
@@ -105,7 +107,7 @@ func (m *mockResourceRegistry) RegisterResourceHandlers(s *mcp.Server) {
 func (m *mockResourceRegistry) GetResourceDefinitions() []mcp.Resource { return nil }
 
 func TestNewServer_RegistersHandlers(t *testing.T) {
-	s := mcp.NewServer(&mockToolRegistry{}, &mockResourceRegistry{})
+	s := mcp.NewServer(&mockToolRegistry{}, &mockResourceRegistry{}, nil)
 
 	// Test that tools are registered by trying to call the tools/list method
 	msg := &mcp.Message{JSONRPC: "2.0", ID: "test", Method: "tools/list"}
@@ -162,7 +164,7 @@ func TestProcessMessage_Initialize(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			s := mcp.NewServer(&mockToolRegistry{}, &mockResourceRegistry{})
+			s := mcp.NewServer(&mockToolRegistry{}, &mockResourceRegistry{}, nil)
 			msg := &mcp.Message{
 				JSONRPC: "2.0",
 				ID:      "1",
@@ -200,7 +202,7 @@ func TestProcessMessage_ToolsList(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			s := mcp.NewServer(&tt.mock, &mockResourceRegistry{})
+			s := mcp.NewServer(&tt.mock, &mockResourceRegistry{}, nil)
 			msg := &mcp.Message{JSONRPC: "2.0", ID: "2", Method: "tools/list"}
 			data, _ := json.Marshal(msg)
 			resp := s.ProcessMessage(context.Background(), data)
@@ -210,6 +212,44 @@ func TestProcessMessage_ToolsList(t *testing.T) {
 	}
 }
 
+type definedToolRegistry struct {
+	mockToolRegistry
+}
+
+func (m *definedToolRegistry) GetToolDefinitions() []mcp.Tool {
+	return []mcp.Tool{{
+		Name:        "mock_tool",
+		Description: "A mock tool",
+		InputSchema: mcp.ObjectSchema(map[string]interface{}{}, nil),
+	}}
+}
+
+func TestProcessMessage_ToolsListUsesRegistryDefinitions(t *testing.T) {
+	s := mcp.NewServer(&definedToolRegistry{}, nil, nil)
+	msg := &mcp.Message{JSONRPC: "2.0", ID: "2", Method: "tools/list"}
+	data, _ := json.Marshal(msg)
+	resp := s.ProcessMessage(context.Background(), data)
+
+	validateToolsListResponse(t, resp)
+	result, ok := resp.Result.(map[string]interface{})
+	if !ok {
+		t.Fatal("expected map result")
+	}
+	tools, ok := result["tools"].([]mcp.Tool)
+	if !ok || len(tools) != 1 || tools[0].Name != "mock_tool" {
+		t.Errorf("expected registry tool definitions, got %v", result["tools"])
+	}
+}
+
+func TestProcessMessage_ToolsListWithoutRegistry(t *testing.T) {
+	s := mcp.NewServer(nil, nil, nil)
+	msg := &mcp.Message{JSONRPC: "2.0", ID: "2", Method: "tools/list"}
+	data, _ := json.Marshal(msg)
+	resp := s.ProcessMessage(context.Background(), data)
+
+	validateToolsListResponse(t, resp)
+}
+
 func TestProcessMessage_ToolsCall(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -270,7 +310,7 @@ func TestProcessMessage_ToolsCall(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			s := mcp.NewServer(&mockToolRegistry{}, &mockResourceRegistry{})
+			s := mcp.NewServer(&mockToolRegistry{}, &mockResourceRegistry{}, nil)
 			msg := &mcp.Message{
 				JSONRPC: "2.0",
 				ID:      "3",
@@ -297,7 +337,7 @@ func TestProcessMessage_ToolsCall(t *testing.T) {
 }
 
 func TestProcessMessage_ResourcesList(t *testing.T) {
-	s := mcp.NewServer(&mockToolRegistry{}, &mockResourceRegistry{})
+	s := mcp.NewServer(&mockToolRegistry{}, &mockResourceRegistry{}, nil)
 	msg := &mcp.Message{JSONRPC: "2.0", ID: "4", Method: "resources/list"}
 	data, _ := json.Marshal(msg)
 	resp := s.ProcessMessage(context.Background(), data)
@@ -396,7 +436,7 @@ func TestProcessMessage_ResourcesRead(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			s := mcp.NewServer(&mockToolRegistry{}, &mockResourceRegistry{})
+			s := mcp.NewServer(&mockToolRegistry{}, &mockResourceRegistry{}, nil)
 			params := map[string]interface{}{"uri": tt.uri}
 			msg := &mcp.Message{JSONRPC: "2.0", ID: "5", Method: "resources/read", Params: params}
 			data, _ := json.Marshal(msg)
@@ -412,7 +452,7 @@ func TestProcessMessage_ResourcesRead(t *testing.T) {
 }
 
 func TestHandleHTTP(t *testing.T) {
-	s := mcp.NewServer(&mockToolRegistry{}, &mockResourceRegistry{})
+	s := mcp.NewServer(&mockToolRegistry{}, &mockResourceRegistry{}, nil)
 	handler := http.HandlerFunc(s.HandleHTTP)
 	server := httptest.NewServer(handler)
 	defer server.Close()
@@ -520,8 +560,27 @@ func testHandleHTTPWrongMethod(t *testing.T, url string) {
 	}
 }
 
+func TestHandleHTTP_MaxRequestBytes(t *testing.T) {
+	cfg := config.Default()
+	cfg.MCP.MaxRequestBytes = 64
+	s := mcp.NewServer(&mockToolRegistry{}, &mockResourceRegistry{}, cfg)
+
+	body := `{"jsonrpc": "2.0", "id": "1", "method": "ping", "params": {"padding": "` + strings.Repeat("x", 64) + `"}}`
+	rec := httptest.NewRecorder()
+	s.HandleHTTP(rec, httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(body)))
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("Expected status %d for a body over the limit, got %d", http.StatusRequestEntityTooLarge, rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	s.HandleHTTP(rec, httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(`{"jsonrpc": "2.0", "id": "2"}`)))
+	if rec.Code == http.StatusRequestEntityTooLarge {
+		t.Error("Expected a body within the limit to be read")
+	}
+}
+
 func TestConcurrentToolCalls(t *testing.T) {
-	s := mcp.NewServer(&mockToolRegistry{}, &mockResourceRegistry{})
+	s := mcp.NewServer(&mockToolRegistry{}, &mockResourceRegistry{}, nil)
 	concurrency := 10
 	done := make(chan bool)
 
@@ -553,7 +612,7 @@ func TestConcurrentToolCalls(t *testing.T) {
 }
 
 func TestProcessMessage_MethodNotFound(t *testing.T) {
-	s := mcp.NewServer(&mockToolRegistry{}, &mockResourceRegistry{})
+	s := mcp.NewServer(&mockToolRegistry{}, &mockResourceRegistry{}, nil)
 	msg := &mcp.Message{JSONRPC: "2.0", ID: "6", Method: "unknown/method"}
 	data, _ := json.Marshal(msg)
 	resp := s.ProcessMessage(context.Background(), data)
@@ -563,7 +622,7 @@ func TestProcessMessage_MethodNotFound(t *testing.T) {
 }
 
 func TestProcessMessage_InvalidMessage(t *testing.T) {
-	s := mcp.NewServer(&mockToolRegistry{}, &mockResourceRegistry{})
+	s := mcp.NewServer(&mockToolRegistry{}, &mockResourceRegistry{}, nil)
 	// Invalid JSON
 	resp := s.ProcessMessage(context.Background(), []byte(`{"jsonrpc":"2.0",`))
 	if resp == nil || resp.Error == nil {
@@ -571,9 +630,38 @@ func TestProcessMessage_InvalidMessage(t *testing.T) {
 	}
 }
 
+func TestProcessMessage_ErrorDataCarriesRequestID(t *testing.T) {
+	shared := &mcp.Error{Code: mcp.InvalidParams, Message: "bad", Data: map[string]interface{}{"field": "name"}}
+	s := mcp.NewServer(nil, nil, nil)
+	s.RegisterToolHandler("failing_tool", func(_ context.Context, _ map[string]interface{}) (*mcp.ToolResult, error) {
+		return nil, shared
+	})
+	ctx := requestid.NewContext(context.Background(), "req-123")
+
+	resp := s.ProcessMessage(ctx, []byte(`{"jsonrpc":"2.0","id":1,"method":"unknown/method"}`))
+	if data, _ := resp.Error.Data.(map[string]interface{}); data["request_id"] != "req-123" {
+		t.Errorf("Expected the request ID in the error data, got %v", resp.Error.Data)
+	}
+
+	call := `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"failing_tool"}}`
+	resp = s.ProcessMessage(ctx, []byte(call))
+	data, _ := resp.Error.Data.(map[string]interface{})
+	if data["request_id"] != "req-123" || data["field"] != "name" {
+		t.Errorf("Expected the request ID added to the handler's data, got %v", resp.Error.Data)
+	}
+	if _, ok := shared.Data.(map[string]interface{})["request_id"]; ok {
+		t.Error("The handler's error should not be changed")
+	}
+
+	resp = s.ProcessMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":3,"method":"unknown/method"}`))
+	if resp.Error.Data != nil {
+		t.Errorf("Expected no error data without a request ID, got %v", resp.Error.Data)
+	}
+}
+
 // Test utility functions: contains, indexOf, isValidURI.
 func TestUtilityFunctions(t *testing.T) {
-	s := mcp.NewServer(&mockToolRegistry{}, &mockResourceRegistry{})
+	s := mcp.NewServer(&mockToolRegistry{}, &mockResourceRegistry{}, nil)
 
 	// Test through ProcessMessage with different scenarios that exercise these functions
 
@@ -635,7 +723,7 @@ func TestErrorHandling(t *testing.T) {
 // Helper for error handling test cases.
 func runErrorHandlingCase(t *testing.T, name, message string, expectError bool, expectedCode int) {
 	t.Run(name, func(t *testing.T) {
-		s := mcp.NewServer(&mockToolRegistry{}, &mockResourceRegistry{})
+		s := mcp.NewServer(&mockToolRegistry{}, &mockResourceRegistry{}, nil)
 		resp := s.ProcessMessage(context.Background(), []byte(message))
 
 		if expectError {
@@ -674,7 +762,7 @@ func checkNonErrorCase(t *testing.T, resp *mcp.Message, name string) {
 
 // Test JSON-RPC validation.
 func TestJSONRPCValidation(t *testing.T) {
-	s := mcp.NewServer(&mockToolRegistry{}, &mockResourceRegistry{})
+	s := mcp.NewServer(&mockToolRegistry{}, &mockResourceRegistry{}, nil)
 
 	// Test various message validation scenarios
 	tests := []struct {
@@ -747,7 +835,7 @@ func TestJSONRPCValidation(t *testing.T) {
 // Test matchesPattern function through resource handling.
 func TestMatchesPattern(t *testing.T) {
 	// We can test matchesPattern indirectly by testing resource URI matching
-	s := mcp.NewServer(&mockToolRegistry{}, &mockResourceRegistry{})
+	s := mcp.NewServer(&mockToolRegistry{}, &mockResourceRegistry{}, nil)
 	ctx := context.Background()
 
 	tests := []struct {
@@ -783,3 +871,99 @@ func TestMatchesPattern(t *testing.T) {
 		})
 	}
 }
+
+func TestResourceSubscriptions(t *testing.T) {
+	ctx := context.Background()
+	subscribe := func(s *mcp.Server, method, uri string) *mcp.Message {
+		data, _ := json.Marshal(mcp.NewMessage(method, mcp.SubscribeRequest{URI: uri}))
+		return s.ProcessMessage(ctx, data)
+	}
+	subscribeCapability := func(s *mcp.Server) bool {
+		resp := s.ProcessMessage(ctx, []byte(`{"jsonrpc":"2.0","id":1,"method":"initialize"}`))
+		return resp.Result.(mcp.InitializeResponse).Capabilities.Resources.Subscribe
+	}
+
+	// Without a transport that can push notifications, subscriptions are not offered
+	s := mcp.NewServer(nil, &mockResourceRegistry{}, nil)
+	if subscribeCapability(s) {
+		t.Error("Expected no subscribe capability without a notifier")
+	}
+	if resp := subscribe(s, "resources/subscribe", "bjcp://styles"); resp.Error == nil {
+		t.Error("Expected subscribing to fail without a notifier")
+	}
+	if s.NotifyResourceUpdated("bjcp://styles") {
+		t.Error("Expected no notification without a notifier")
+	}
+
+	var sent []*mcp.Message
+	s.SetNotifier(func(msg *mcp.Message) { sent = append(sent, msg) })
+	if !subscribeCapability(s) {
+		t.Error("Expected the subscribe capability with a notifier")
+	}
+	if resp := subscribe(s, "resources/subscribe", ""); resp.Error == nil || resp.Error.Code != mcp.InvalidParams {
+		t.Errorf("Expected InvalidParams for a missing URI, got %+v", resp.Error)
+	}
+	if resp := subscribe(s, "resources/subscribe", "bjcp://styles"); resp.Error != nil {
+		t.Fatalf("Expected the subscription to succeed, got %v", resp.Error)
+	}
+
+	if s.NotifyResourceUpdated("beers://catalog") {
+		t.Error("Expected no notification for a resource without subscribers")
+	}
+	if !s.NotifyResourceUpdated("bjcp://styles") || len(sent) != 1 {
+		t.Fatalf("Expected one notification, got %d", len(sent))
+	}
+	notification, _ := json.Marshal(sent[0])
+	expected := `{"jsonrpc":"2.0","method":"notifications/resources/updated","params":{"uri":"bjcp://styles"}}`
+	if string(notification) != expected {
+		t.Errorf("Expected %s, got %s", expected, notification)
+	}
+
+	if resp := subscribe(s, "resources/unsubscribe", "bjcp://styles"); resp.Error != nil {
+		t.Fatalf("Expected unsubscribing to succeed, got %v", resp.Error)
+	}
+	if s.NotifyResourceUpdated("bjcp://styles") {
+		t.Error("Expected no notification after unsubscribing")
+	}
+}
+
+func TestProgressNotifications(t *testing.T) {
+	s := mcp.NewServer(nil, &mockResourceRegistry{}, nil)
+	var token interface{}
+	s.RegisterToolHandler("long_tool", func(ctx context.Context, _ map[string]interface{}) (*mcp.ToolResult, error) {
+		token = mcp.ProgressToken(ctx)
+		return &mcp.ToolResult{}, nil
+	})
+	call := `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"long_tool"}}`
+	callWithToken := `{"jsonrpc":"2.0","id":2,"method":"tools/call",` +
+		`"params":{"name":"long_tool","_meta":{"progressToken":"import-1"}}}`
+
+	if resp := s.ProcessMessage(context.Background(), []byte(call)); resp.Error != nil {
+		t.Fatalf("unexpected error: %v", resp.Error)
+	}
+	if token != nil {
+		t.Errorf("Expected no progress token without _meta, got %v", token)
+	}
+	s.ProcessMessage(context.Background(), []byte(callWithToken))
+	if token != "import-1" {
+		t.Fatalf("Expected the progress token in the tool's context, got %v", token)
+	}
+
+	if s.NotifyProgress(token, 1, 2, "page 1") {
+		t.Error("Expected no notification without a notifier")
+	}
+	var sent []*mcp.Message
+	s.SetNotifier(func(msg *mcp.Message) { sent = append(sent, msg) })
+	if s.NotifyProgress(nil, 1, 2, "page 1") {
+		t.Error("Expected no notification without a progress token")
+	}
+	if !s.NotifyProgress(token, 1, 2, "page 1") || len(sent) != 1 {
+		t.Fatalf("Expected one notification, got %d", len(sent))
+	}
+	notification, _ := json.Marshal(sent[0])
+	expected := `{"jsonrpc":"2.0","method":"notifications/progress",` +
+		`"params":{"progressToken":"import-1","progress":1,"total":2,"message":"page 1"}}`
+	if string(notification) != expected {
+		t.Errorf("Expected %s, got %s", expected, notification)
+	}
+}
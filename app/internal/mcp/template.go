@@ -0,0 +1,184 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/requestid"
+	"github.com/sirupsen/logrus"
+)
+
+// maxCompletionValues is the most values a completion/complete response may carry.
+const maxCompletionValues = 100
+
+// templateVariable matches an expression of a URI template, such as {code}.
+var templateVariable = regexp.MustCompile(`\{([A-Za-z0-9_]+)\}`)
+
+// resourceTemplate is a registered resource template with the matcher compiled from its URI template.
+type resourceTemplate struct {
+	ResourceTemplate
+	pattern     *regexp.Regexp
+	variables   []string
+	handler     ResourceTemplateHandler
+	completions map[string]CompletionHandler
+}
+
+// compileURITemplate compiles a URI template into a regular expression matching the URIs it
+// expands to. Only simple expressions such as {code} are supported, each matching one path segment:
+// a value never contains a slash, question mark or hash.
+func compileURITemplate(uriTemplate string) (*regexp.Regexp, []string, error) {
+	var pattern strings.Builder
+	var variables []string
+	pattern.WriteString("^")
+	literalStart := 0
+	for _, match := range templateVariable.FindAllStringSubmatchIndex(uriTemplate, -1) {
+		pattern.WriteString(regexp.QuoteMeta(uriTemplate[literalStart:match[0]]))
+		pattern.WriteString("([^/?#]+)")
+		variables = append(variables, uriTemplate[match[2]:match[3]])
+		literalStart = match[1]
+	}
+	pattern.WriteString(regexp.QuoteMeta(uriTemplate[literalStart:]))
+	pattern.WriteString("$")
+
+	if len(variables) == 0 {
+		return nil, nil, fmt.Errorf("URI template %q has no variables", uriTemplate)
+	}
+	if literals := templateVariable.ReplaceAllString(uriTemplate, ""); strings.ContainsAny(literals, "{}") {
+		return nil, nil, fmt.Errorf("URI template %q has an unsupported expression", uriTemplate)
+	}
+	return regexp.MustCompile(pattern.String()), variables, nil
+}
+
+// match extracts the template's variables from uri, as they appear in it: values are not
+// percent-decoded, so each handler decodes them as suits the resource.
+func (t *resourceTemplate) match(uri string) (map[string]string, bool) {
+	values := t.pattern.FindStringSubmatch(uri)
+	if values == nil {
+		return nil, false
+	}
+	params := make(map[string]string, len(t.variables))
+	for i, variable := range t.variables {
+		params[variable] = values[i+1]
+	}
+	return params, true
+}
+
+// RegisterResourceTemplate registers a handler for the resources a URI template such as
+// bjcp://styles/{code} describes, and lists the template in resources/templates/list. A URI
+// matching several templates is served by the first registered, and registering a template again
+// replaces its handler. It panics if the template has no variables or uses expressions other than
+// simple ones such as {code}.
+func (s *Server) RegisterResourceTemplate(template ResourceTemplate, handler ResourceTemplateHandler) {
+	pattern, variables, err := compileURITemplate(template.URITemplate)
+	if err != nil {
+		panic(err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if registered := s.findTemplate(template.URITemplate); registered != nil {
+		registered.ResourceTemplate, registered.handler = template, handler
+		return
+	}
+	s.templates = append(s.templates, &resourceTemplate{
+		ResourceTemplate: template,
+		pattern:          pattern,
+		variables:        variables,
+		handler:          handler,
+		completions:      make(map[string]CompletionHandler),
+	})
+	logrus.Debugf("Registered resource template: %s", template.URITemplate)
+}
+
+// RegisterCompletion registers a handler suggesting values for the argument of a registered
+// resource template, answering completion/complete. It panics if the template is not registered.
+func (s *Server) RegisterCompletion(uriTemplate, argument string, handler CompletionHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	template := s.findTemplate(uriTemplate)
+	if template == nil {
+		panic(fmt.Sprintf("resource template %q is not registered", uriTemplate))
+	}
+	template.completions[argument] = handler
+}
+
+// findTemplate returns the registered template with the given URI template, or nil. The caller
+// holds s.mu.
+func (s *Server) findTemplate(uriTemplate string) *resourceTemplate {
+	for _, template := range s.templates {
+		if template.URITemplate == uriTemplate {
+			return template
+		}
+	}
+	return nil
+}
+
+func (s *Server) handleResourceTemplatesList(msg *Message) *Message {
+	s.mu.RLock()
+	templates := make([]ResourceTemplate, 0, len(s.templates))
+	for _, template := range s.templates {
+		templates = append(templates, template.ResourceTemplate)
+	}
+	s.mu.RUnlock()
+
+	return NewResponse(msg.ID, map[string]interface{}{
+		"resourceTemplates": templates,
+	})
+}
+
+// handleComplete suggests values for an argument of a resource template. An argument without a
+// registered completion has no suggestions.
+func (s *Server) handleComplete(ctx context.Context, msg *Message) *Message {
+	var req CompleteRequest
+	if msg.Params != nil {
+		paramData, _ := json.Marshal(msg.Params)
+		if err := json.Unmarshal(paramData, &req); err != nil {
+			return NewErrorResponse(msg.ID, NewMCPError(InvalidParams, "Invalid completion parameters", nil))
+		}
+	}
+	if req.Ref.Type != "ref/resource" {
+		return NewErrorResponse(
+			msg.ID,
+			NewMCPError(InvalidParams, fmt.Sprintf("Unsupported completion reference: %q", req.Ref.Type), nil),
+		)
+	}
+
+	s.mu.RLock()
+	var handler CompletionHandler
+	template := s.findTemplate(req.Ref.URI)
+	if template != nil {
+		handler = template.completions[req.Argument.Name]
+	}
+	s.mu.RUnlock()
+
+	if template == nil {
+		return NewErrorResponse(
+			msg.ID,
+			NewMCPError(InvalidParams, fmt.Sprintf("Resource template not found: %s", req.Ref.URI), nil),
+		)
+	}
+
+	values := []string{}
+	if handler != nil {
+		suggestions, err := handler(ctx, req.Argument.Value)
+		if err != nil {
+			mcpErr := &Error{}
+			if errors.As(err, &mcpErr) {
+				return NewErrorResponse(msg.ID, mcpErr)
+			}
+			requestid.Logger(ctx).WithError(err).WithField("template", req.Ref.URI).Error("Completion failed")
+			return NewErrorResponse(msg.ID, NewMCPError(InternalError, err.Error(), nil))
+		}
+		values = append(values, suggestions...)
+	}
+
+	completion := Completion{Values: values, Total: len(values)}
+	if len(values) > maxCompletionValues {
+		completion.Values = values[:maxCompletionValues]
+		completion.HasMore = true
+	}
+	return NewResponse(msg.ID, CompleteResult{Completion: completion})
+}
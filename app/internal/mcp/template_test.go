@@ -0,0 +1,145 @@
+package mcp_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/mcp"
+)
+
+// newTemplateServer serves mock://items/{id} and mock://{group}/items/{id}, answering with the
+// variables the template extracted, and mock://* for every other mock URI.
+func newTemplateServer() *mcp.Server {
+	s := mcp.NewServer(nil, &mockResourceRegistry{}, nil)
+	echo := func(_ context.Context, uri string, params map[string]string) (*mcp.ResourceContent, error) {
+		text, _ := json.Marshal(params)
+		return &mcp.ResourceContent{URI: uri, MimeType: "application/json", Text: string(text)}, nil
+	}
+	s.RegisterResourceTemplate(mcp.ResourceTemplate{URITemplate: "mock://items/{id}", Name: "Item"}, echo)
+	s.RegisterResourceTemplate(mcp.ResourceTemplate{URITemplate: "mock://{group}/items/{id}", Name: "Group Item"}, echo)
+	s.RegisterResourceHandler("mock://items/all", func(_ context.Context, uri string) (*mcp.ResourceContent, error) {
+		return &mcp.ResourceContent{URI: uri, MimeType: "text/plain", Text: "all"}, nil
+	})
+	s.RegisterCompletion("mock://items/{id}", "id", func(_ context.Context, value string) ([]string, error) {
+		values := make([]string, 0, 150)
+		for i := range 150 {
+			if id := fmt.Sprint(i); strings.HasPrefix(id, value) {
+				values = append(values, id)
+			}
+		}
+		return values, nil
+	})
+	return s
+}
+
+func TestReadResource_Templates(t *testing.T) {
+	s := newTemplateServer()
+	tests := []struct {
+		uri      string
+		expected string
+	}{
+		{"mock://items/42", `{"id":"42"}`},
+		{"mock://hops/items/a%20b", `{"group":"hops","id":"a%20b"}`},
+		{"mock://items/all", "all"},
+		{"mock://items/42/extra", "resource"},
+		{"mock://items/42?verbose=true", "resource"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.uri, func(t *testing.T) {
+			content, err := s.ReadResource(context.Background(), tt.uri)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if content.Text != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, content.Text)
+			}
+		})
+	}
+
+	_, err := s.ReadResource(context.Background(), "other://items/42")
+	if mcpErr, ok := err.(*mcp.Error); !ok || mcpErr.Code != mcp.MethodNotFound {
+		t.Errorf("expected MethodNotFound for an unserved URI, got %v", err)
+	}
+}
+
+func TestRegisterResourceTemplate_Invalid(t *testing.T) {
+	for _, uriTemplate := range []string{"mock://items", "mock://items/{+path}", "mock://items/{id"} {
+		t.Run(uriTemplate, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected %s to be rejected", uriTemplate)
+				}
+			}()
+			mcp.NewServer(nil, nil, nil).RegisterResourceTemplate(mcp.ResourceTemplate{URITemplate: uriTemplate}, nil)
+		})
+	}
+}
+
+func TestProcessMessage_ResourceTemplatesList(t *testing.T) {
+	s := newTemplateServer()
+	request := []byte(`{"jsonrpc":"2.0","id":1,"method":"resources/templates/list"}`)
+	resp := s.ProcessMessage(context.Background(), request)
+	data, _ := json.Marshal(resp.Result)
+	expected := `{"resourceTemplates":[{"uriTemplate":"mock://items/{id}","name":"Item"},` +
+		`{"uriTemplate":"mock://{group}/items/{id}","name":"Group Item"}]}`
+	if string(data) != expected {
+		t.Errorf("expected %s, got %s", expected, data)
+	}
+
+	resp = mcp.NewServer(nil, nil, nil).ProcessMessage(context.Background(), request)
+	if data, _ = json.Marshal(resp.Result); string(data) != `{"resourceTemplates":[]}` {
+		t.Errorf("expected an empty list without templates, got %s", data)
+	}
+}
+
+func TestProcessMessage_Complete(t *testing.T) {
+	s := newTemplateServer()
+	complete := func(params string) *mcp.Message {
+		return s.ProcessMessage(context.Background(),
+			[]byte(`{"jsonrpc":"2.0","id":1,"method":"completion/complete","params":`+params+`}`))
+	}
+
+	resp := complete(`{"ref":{"type":"ref/resource","uri":"mock://items/{id}"},"argument":{"name":"id","value":"14"}}`)
+	data, _ := json.Marshal(resp.Result)
+	if expected := `{"completion":{"values":["14","140","141","142","143","144","145","146","147","148","149"],` +
+		`"total":11,"hasMore":false}}`; string(data) != expected {
+		t.Errorf("expected %s, got %s", expected, data)
+	}
+
+	resp = complete(`{"ref":{"type":"ref/resource","uri":"mock://items/{id}"},"argument":{"name":"id","value":""}}`)
+	completion := resp.Result.(mcp.CompleteResult).Completion
+	if len(completion.Values) != 100 || completion.Total != 150 || !completion.HasMore {
+		t.Errorf("expected the first 100 of 150 values, got %d of %d", len(completion.Values), completion.Total)
+	}
+
+	resp = complete(`{"ref":{"type":"ref/resource","uri":"mock://{group}/items/{id}"},"argument":{"name":"group"}}`)
+	if data, _ = json.Marshal(resp.Result); !strings.Contains(string(data), `"values":[]`) {
+		t.Errorf("expected no values for an argument without a completion, got %s", data)
+	}
+
+	for _, params := range []string{
+		`{"ref":{"type":"ref/prompt","name":"brew"},"argument":{"name":"style"}}`,
+		`{"ref":{"type":"ref/resource","uri":"mock://unknown/{id}"},"argument":{"name":"id"}}`,
+		`"not an object"`,
+	} {
+		if resp = complete(params); resp.Error == nil || resp.Error.Code != mcp.InvalidParams {
+			t.Errorf("expected InvalidParams for %s, got %+v", params, resp)
+		}
+	}
+}
+
+func TestProcessMessage_InitializeCompletions(t *testing.T) {
+	capabilities := func(s *mcp.Server) mcp.ServerCapabilities {
+		resp := s.ProcessMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":1,"method":"initialize"}`))
+		return resp.Result.(mcp.InitializeResponse).Capabilities
+	}
+	if capabilities(mcp.NewServer(nil, &mockResourceRegistry{}, nil)).Completions != nil {
+		t.Error("expected no completions capability without completions")
+	}
+	if capabilities(newTemplateServer()).Completions == nil {
+		t.Error("expected the completions capability once a completion is registered")
+	}
+}
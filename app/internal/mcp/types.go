@@ -4,6 +4,8 @@ package mcp
 import (
 	"context"
 	"encoding/json"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/version"
 )
 
 // MCP Protocol Types
@@ -47,6 +49,15 @@ const (
 	InternalError  = -32603
 )
 
+// Server error codes, from the range JSON-RPC 2.0 reserves for implementations.
+const (
+	// RequestTimeout reports a request that took too long to answer, such as a slow database query.
+	RequestTimeout = -32001
+	// ServerBusy refuses a call of a tool already running at its concurrency limit, with the
+	// suggested wait before retrying as retry_after_ms in its data.
+	ServerBusy = -32002
+)
+
 // MCP-specific message types
 
 type InitializeRequest struct {
@@ -67,10 +78,11 @@ type ClientCapabilities struct {
 }
 
 type ServerCapabilities struct {
-	Logging   *LoggingCapability   `json:"logging,omitempty"`
-	Prompts   *PromptsCapability   `json:"prompts,omitempty"`
-	Resources *ResourcesCapability `json:"resources,omitempty"`
-	Tools     *ToolsCapability     `json:"tools,omitempty"`
+	Completions *CompletionsCapability `json:"completions,omitempty"`
+	Logging     *LoggingCapability     `json:"logging,omitempty"`
+	Prompts     *PromptsCapability     `json:"prompts,omitempty"`
+	Resources   *ResourcesCapability   `json:"resources,omitempty"`
+	Tools       *ToolsCapability       `json:"tools,omitempty"`
 }
 
 type RootsCapability struct {
@@ -81,6 +93,14 @@ type SamplingCapability struct{}
 
 type LoggingCapability struct{}
 
+// SetLevelRequest are the parameters of logging/setLevel.
+type SetLevelRequest struct {
+	Level string `json:"level"`
+}
+
+// CompletionsCapability advertises completion/complete, offered once a completion is registered.
+type CompletionsCapability struct{}
+
 type PromptsCapability struct {
 	ListChanged bool `json:"listChanged,omitempty"`
 }
@@ -97,11 +117,18 @@ type ToolsCapability struct {
 type ClientInfo struct {
 	Name    string `json:"name"`
 	Version string `json:"version"`
+	// Units and Locale set the unit system of the client's tool output, see setUnitPreference.
+	// Neither is part of the MCP specification; clients may send them as extra fields.
+	Units  string `json:"units,omitempty"`
+	Locale string `json:"locale,omitempty"`
 }
 
+// ServerInfo names the server and its build in the initialize response, and describes the data it
+// serves under Metadata, see ServerDescriber.
 type ServerInfo struct {
-	Name    string `json:"name"`
-	Version string `json:"version"`
+	Name string `json:"name"`
+	version.Info
+	Metadata map[string]string `json:"metadata,omitempty"`
 }
 
 // Tool definitions
@@ -115,6 +142,10 @@ type Tool struct {
 type ToolResult struct {
 	Content []ToolContent `json:"content"`
 	IsError bool          `json:"isError,omitempty"`
+	// Warnings are problems that did not fail the call, such as a capped limit or an ignored
+	// filter, for clients to surface; the tool's text mentions them too. Not part of the MCP
+	// specification, so clients unaware of it see the text alone.
+	Warnings []string `json:"warnings,omitempty"`
 }
 
 type ToolContent struct {
@@ -126,6 +157,13 @@ type ToolContent struct {
 type CallToolRequest struct {
 	Name      string                 `json:"name"`
 	Arguments map[string]interface{} `json:"arguments,omitempty"`
+	Meta      *RequestMeta           `json:"_meta,omitempty"`
+}
+
+// RequestMeta is the _meta of a request. A ProgressToken, a string or number chosen by the client,
+// asks for notifications/progress about the work the request starts, tagged with the token.
+type RequestMeta struct {
+	ProgressToken interface{} `json:"progressToken,omitempty"`
 }
 
 // Resource definitions
@@ -135,6 +173,8 @@ type Resource struct {
 	Name        string `json:"name"`
 	Description string `json:"description,omitempty"`
 	MimeType    string `json:"mimeType,omitempty"`
+	// ETag is the resource's current ETag when it is known without reading it, see ResourceVersioner.
+	ETag string `json:"etag,omitempty"`
 }
 
 type ResourceContent struct {
@@ -142,10 +182,81 @@ type ResourceContent struct {
 	MimeType string `json:"mimeType"`
 	Text     string `json:"text,omitempty"`
 	Blob     string `json:"blob,omitempty"`
+	// ETag identifies this version of the content. ReadResource sets it when the handler leaves it
+	// empty, see ResourceVersioner.
+	ETag string `json:"etag,omitempty"`
+}
+
+// ResourceTemplate describes a family of resources by a URI template such as bjcp://styles/{code},
+// listed by resources/templates/list.
+type ResourceTemplate struct {
+	URITemplate string `json:"uriTemplate"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mimeType,omitempty"`
 }
 
 type ReadResourceRequest struct {
 	URI string `json:"uri"`
+	// IfNoneMatch lists ETags the client holds content for, as in an HTTP If-None-Match header. When
+	// the resource's ETag is among them, the read returns no contents and notModified instead.
+	IfNoneMatch string `json:"ifNoneMatch,omitempty"`
+}
+
+// SubscribeRequest is the params of resources/subscribe and resources/unsubscribe.
+type SubscribeRequest struct {
+	URI string `json:"uri"`
+}
+
+// CompleteRequest is the params of completion/complete, asking for values of an argument of the
+// resource template Ref names, such as the code of bjcp://styles/{code}.
+type CompleteRequest struct {
+	Ref      CompletionReference `json:"ref"`
+	Argument CompletionArgument  `json:"argument"`
+}
+
+// CompletionReference names what completion/complete completes: a resource template by its URI
+// template when Type is ref/resource.
+type CompletionReference struct {
+	Type string `json:"type"`
+	URI  string `json:"uri,omitempty"`
+	Name string `json:"name,omitempty"`
+}
+
+// CompletionArgument is the argument being completed and the value typed so far.
+type CompletionArgument struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// CompleteResult is the result of completion/complete.
+type CompleteResult struct {
+	Completion Completion `json:"completion"`
+}
+
+// Completion lists suggested values for an argument. Total counts every suggestion, and HasMore is
+// set when Values holds only the first of them.
+type Completion struct {
+	Values  []string `json:"values"`
+	Total   int      `json:"total"`
+	HasMore bool     `json:"hasMore"`
+}
+
+// ResourceUpdatedNotification is the method of the notification sent to clients subscribed to a
+// resource that changed.
+const ResourceUpdatedNotification = "notifications/resources/updated"
+
+// ProgressNotification is the method of the notification reporting the progress of work a client
+// asked to follow with a progress token.
+const ProgressNotification = "notifications/progress"
+
+// ProgressParams are the params of a progress notification. Progress increases with every
+// notification for a token; Total is zero when it is not known.
+type ProgressParams struct {
+	ProgressToken interface{} `json:"progressToken"`
+	Progress      float64     `json:"progress"`
+	Total         float64     `json:"total,omitempty"`
+	Message       string      `json:"message,omitempty"`
 }
 
 // Handler function types
@@ -153,6 +264,11 @@ type ReadResourceRequest struct {
 type (
 	ToolHandler     func(ctx context.Context, args map[string]interface{}) (*ToolResult, error)
 	ResourceHandler func(ctx context.Context, uri string) (*ResourceContent, error)
+	// ResourceTemplateHandler serves a URI matching a resource template, with the values of the
+	// template's variables by name.
+	ResourceTemplateHandler func(ctx context.Context, uri string, params map[string]string) (*ResourceContent, error)
+	// CompletionHandler suggests values for an argument starting from the value typed so far.
+	CompletionHandler func(ctx context.Context, value string) ([]string, error)
 )
 
 // Helper functions for creating responses
@@ -212,6 +328,28 @@ func StringSchema(description string, _ bool) map[string]interface{} {
 	return schema
 }
 
+func NumberSchema(description string) map[string]interface{} {
+	return map[string]interface{}{
+		"type":        "number",
+		"description": description,
+	}
+}
+
+func IntegerSchema(description string) map[string]interface{} {
+	return map[string]interface{}{
+		"type":        "integer",
+		"description": description,
+	}
+}
+
+// IntegerRangeSchema is IntegerSchema for an integer between minimum and maximum, inclusive.
+func IntegerRangeSchema(description string, minimum, maximum int) map[string]interface{} {
+	schema := IntegerSchema(description)
+	schema["minimum"] = minimum
+	schema["maximum"] = maximum
+	return schema
+}
+
 func ObjectSchema(properties map[string]interface{}, required []string) map[string]interface{} {
 	schema := map[string]interface{}{
 		"type":       "object",
@@ -592,6 +592,34 @@ func TestStringSchema(t *testing.T) {
 	}
 }
 
+func TestNumericSchemas(t *testing.T) {
+	tests := []struct {
+		name     string
+		schema   map[string]interface{}
+		wantType string
+	}{
+		{name: "number schema", schema: mcp.NumberSchema("gravity"), wantType: "number"},
+		{name: "integer schema", schema: mcp.IntegerSchema("limit"), wantType: "integer"},
+		{name: "integer range schema", schema: mcp.IntegerRangeSchema("limit", 1, 100), wantType: "integer"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.schema["type"] != tt.wantType {
+				t.Errorf("type = %v, want %q", tt.schema["type"], tt.wantType)
+			}
+			if tt.schema["description"] == "" {
+				t.Error("expected description to be set")
+			}
+		})
+	}
+
+	schema := mcp.IntegerRangeSchema("limit", 1, 100)
+	if schema["minimum"] != 1 || schema["maximum"] != 100 {
+		t.Errorf("expected the range 1 to 100, got %v to %v", schema["minimum"], schema["maximum"])
+	}
+}
+
 func TestObjectSchema(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -866,6 +894,55 @@ func TestToolContent(t *testing.T) {
 	}
 }
 
+func TestToolResult_Warnings(t *testing.T) {
+	tests := []struct {
+		name     string
+		result   *mcp.ToolResult
+		wantJSON string
+	}{
+		{
+			name:     "without warnings",
+			result:   mcp.NewToolResult("3 beers"),
+			wantJSON: `{"content":[{"type":"text","text":"3 beers"}]}`,
+		},
+		{
+			name:     "with empty warnings",
+			result:   &mcp.ToolResult{Content: []mcp.ToolContent{{Type: "text", Text: "3 beers"}}, Warnings: []string{}},
+			wantJSON: `{"content":[{"type":"text","text":"3 beers"}]}`,
+		},
+		{
+			name: "with warnings",
+			result: &mcp.ToolResult{
+				Content:  []mcp.ToolContent{{Type: "text", Text: "3 beers"}},
+				Warnings: []string{"Limit capped at 100 results", "name is blank and was ignored"},
+			},
+			wantJSON: `{"content":[{"type":"text","text":"3 beers"}],` +
+				`"warnings":["Limit capped at 100 results","name is blank and was ignored"]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.result)
+			if err != nil {
+				t.Fatalf("json.Marshal() error = %v", err)
+			}
+			if string(data) != tt.wantJSON {
+				t.Errorf("json.Marshal() = %s, want %s", data, tt.wantJSON)
+			}
+
+			var unmarshaled mcp.ToolResult
+			if err = json.Unmarshal(data, &unmarshaled); err != nil {
+				t.Fatalf("json.Unmarshal() error = %v", err)
+			}
+			if len(unmarshaled.Warnings) != len(tt.result.Warnings) ||
+				(len(tt.result.Warnings) > 0 && !reflect.DeepEqual(unmarshaled.Warnings, tt.result.Warnings)) {
+				t.Errorf("Unmarshaled Warnings = %v, want %v", unmarshaled.Warnings, tt.result.Warnings)
+			}
+		})
+	}
+}
+
 // Benchmark tests for performance validation.
 func BenchmarkValidateMessage(b *testing.B) {
 	validJSON := []byte(`{"jsonrpc":"2.0","method":"test","id":1}`)
@@ -0,0 +1,55 @@
+package mcp
+
+import (
+	"context"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/requestid"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/units"
+)
+
+// maxUnitPreferences bounds the clients whose unit preference is remembered, as client labels come
+// from the request.
+const maxUnitPreferences = 1000
+
+// setUnitPreference remembers the unit system the client of ctx asks for in its initialize
+// clientInfo: its units, or else the customary units of its locale. A client initializing without
+// either forgets its earlier preference, and an invalid units value is logged and ignored. Clients
+// are told apart by their label, see WithClientLabel.
+func (s *Server) setUnitPreference(ctx context.Context, info ClientInfo) {
+	system, err := units.Parse(info.Units)
+	if err != nil {
+		requestid.Logger(ctx).WithError(err).WithField("units", info.Units).
+			Warn("Ignoring the client's unit preference")
+	}
+	if system == "" {
+		system = units.ForLocale(info.Locale)
+	}
+
+	label := ClientLabel(ctx)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if system == "" {
+		delete(s.units, label)
+		return
+	}
+	if _, known := s.units[label]; !known && len(s.units) >= maxUnitPreferences {
+		// Forget an arbitrary client rather than grow without bound; it gets the default units
+		for other := range s.units {
+			delete(s.units, other)
+			break
+		}
+	}
+	s.units[label] = system
+}
+
+// withUnitPreference returns a copy of ctx carrying the unit system the client of ctx prefers, for
+// tools to default to, or ctx itself when the client has no preference.
+func (s *Server) withUnitPreference(ctx context.Context) context.Context {
+	s.mu.RLock()
+	system, ok := s.units[ClientLabel(ctx)]
+	s.mu.RUnlock()
+	if !ok {
+		return ctx
+	}
+	return units.NewContext(ctx, system)
+}
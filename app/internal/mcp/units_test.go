@@ -0,0 +1,62 @@
+package mcp_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/mcp"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/units"
+	"github.com/CharlRitter/brewsource-mcp/app/pkg/brewing"
+)
+
+// Test that the unit system a client asks for during initialize reaches its tool calls, and only
+// its own.
+func TestServer_UnitPreference(t *testing.T) {
+	s := mcp.NewServer(nil, nil, nil)
+	s.RegisterToolHandler("units_tool", func(ctx context.Context, _ map[string]interface{}) (*mcp.ToolResult, error) {
+		return mcp.NewToolResult(string(units.FromContext(ctx))), nil
+	})
+	call := func(ctx context.Context) string {
+		t.Helper()
+		resp := s.ProcessMessage(ctx, []byte(`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"units_tool"}}`))
+		if resp == nil || resp.Error != nil {
+			t.Fatalf("Unexpected tool call response: %+v", resp)
+		}
+		return resp.Result.(*mcp.ToolResult).Content[0].Text
+	}
+	initialize := func(ctx context.Context, clientInfo string) {
+		t.Helper()
+		resp := s.ProcessMessage(ctx, []byte(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"clientInfo":`+
+			clientInfo+`}}`))
+		if resp == nil || resp.Error != nil {
+			t.Fatalf("Unexpected initialize response: %+v", resp)
+		}
+	}
+	brewer := mcp.WithClientLabel(context.Background(), "brewer")
+	other := mcp.WithClientLabel(context.Background(), "other")
+
+	tests := []struct {
+		name       string
+		clientInfo string
+		want       brewing.UnitSystem
+	}{
+		{"units", `{"name":"c","version":"1","units":"Metric"}`, brewing.UnitsMetric},
+		{"units over locale", `{"name":"c","version":"1","units":"imperial","locale":"de-DE"}`, brewing.UnitsImperial},
+		{"metric locale", `{"name":"c","version":"1","locale":"en_ZA"}`, brewing.UnitsMetric},
+		{"US locale", `{"name":"c","version":"1","locale":"en-US"}`, brewing.UnitsImperial},
+		{"invalid units fall back to the locale", `{"name":"c","version":"1","units":"cubits","locale":"fr-FR"}`,
+			brewing.UnitsMetric},
+		{"neither", `{"name":"c","version":"1"}`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			initialize(brewer, tt.clientInfo)
+			if got := call(brewer); got != string(tt.want) {
+				t.Errorf("Expected units %q, got %q", tt.want, got)
+			}
+			if got := call(other); got != "" {
+				t.Errorf("Expected no units for another client, got %q", got)
+			}
+		})
+	}
+}
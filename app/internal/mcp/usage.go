@@ -0,0 +1,108 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Kinds of UsageEvent.
+const (
+	UsageTool     = "tool"
+	UsageResource = "resource"
+)
+
+// UsageEvent is one tool call or resource read, told to the UsageRecorder once it is answered.
+type UsageEvent struct {
+	Kind string // UsageTool or UsageResource
+	// Name is the tool name, or the resource URI without its query, which may carry a token.
+	Name     string
+	At       time.Time
+	Duration time.Duration
+	// ErrorCode is the JSON-RPC error code of a failed call, or 0 when it succeeded.
+	ErrorCode int
+	// Client labels the caller, see WithClientLabel, or is empty for anonymous callers.
+	Client string
+}
+
+// Success reports whether the call succeeded.
+func (e UsageEvent) Success() bool {
+	return e.ErrorCode == 0
+}
+
+// UsageRecorder is told about every tool call and resource read, for usage analytics. RecordUsage
+// is called on the request's goroutine, so it must return at once, dropping the event rather than
+// waiting, and be safe to call concurrently.
+type UsageRecorder interface {
+	RecordUsage(ctx context.Context, event UsageEvent)
+}
+
+type clientLabelKey struct{}
+
+// WithClientLabel returns a copy of ctx labelling the caller as label in the usage events of the
+// requests served with it, for transports that authenticate their callers.
+func WithClientLabel(ctx context.Context, label string) context.Context {
+	return context.WithValue(ctx, clientLabelKey{}, label)
+}
+
+// ClientLabel returns the caller label carried by ctx, or "" when there is none.
+func ClientLabel(ctx context.Context) string {
+	label, _ := ctx.Value(clientLabelKey{}).(string)
+	return label
+}
+
+// maxClientLabel bounds client labels, which come from the request.
+const maxClientLabel = 255
+
+// httpClientLabel labels the caller of r by the common name of its verified client certificate
+// when it has one, and otherwise by its User-Agent.
+func httpClientLabel(r *http.Request) string {
+	label := r.UserAgent()
+	if r.TLS != nil && len(r.TLS.VerifiedChains) > 0 {
+		label = r.TLS.VerifiedChains[0][0].Subject.CommonName
+	}
+	if len(label) > maxClientLabel {
+		label = label[:maxClientLabel]
+	}
+	// PostgreSQL rejects invalid UTF-8, which a header or a cut through a rune may hold
+	return strings.ToValidUTF8(label, "")
+}
+
+// SetUsageRecorder sets the recorder told about every tool call and resource read. Without one,
+// usage is not recorded.
+func (s *Server) SetUsageRecorder(recorder UsageRecorder) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.usage = recorder
+}
+
+// recordUsage tells the usage recorder, if any, about a call of kind to name that started at
+// started and failed with err, or succeeded when err is nil.
+func (s *Server) recordUsage(ctx context.Context, kind, name string, started time.Time, err error) {
+	s.mu.RLock()
+	recorder := s.usage
+	s.mu.RUnlock()
+	if recorder == nil {
+		return
+	}
+	event := UsageEvent{
+		Kind:     kind,
+		Name:     name,
+		At:       started,
+		Duration: time.Since(started),
+		Client:   ClientLabel(ctx),
+	}
+	if kind == UsageResource {
+		event.Name, _, _ = strings.Cut(name, "?")
+	}
+	if err != nil {
+		event.ErrorCode = InternalError
+		mcpErr := &Error{}
+		if errors.As(err, &mcpErr) {
+			event.ErrorCode = mcpErr.Code
+		}
+	}
+	recorder.RecordUsage(ctx, event)
+}
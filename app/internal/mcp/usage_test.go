@@ -0,0 +1,82 @@
+package mcp_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/mcp"
+)
+
+// recordingUsage records the usage events told to it.
+type recordingUsage struct {
+	mu     sync.Mutex
+	events []mcp.UsageEvent
+}
+
+func (r *recordingUsage) RecordUsage(_ context.Context, event mcp.UsageEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+// Test that tool calls and resource reads are recorded with their outcome and caller, and that
+// resource URIs are recorded without their query.
+func TestServer_RecordsUsage(t *testing.T) {
+	s := mcp.NewServer(nil, nil, nil)
+	s.RegisterToolHandler("working_tool", func(_ context.Context, _ map[string]interface{}) (*mcp.ToolResult, error) {
+		return &mcp.ToolResult{Content: []mcp.ToolContent{{Type: "text", Text: "ok"}}}, nil
+	})
+	s.RegisterToolHandler("failing_tool", func(_ context.Context, _ map[string]interface{}) (*mcp.ToolResult, error) {
+		return nil, errors.New("database unavailable")
+	})
+	s.RegisterResourceHandler("usage://*", func(_ context.Context, _ string) (*mcp.ResourceContent, error) {
+		return nil, mcp.NewMCPError(mcp.InvalidRequest, "invalid admin_token", nil)
+	})
+	usage := &recordingUsage{}
+	s.SetUsageRecorder(usage)
+
+	post := func(body string) {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPost, "/mcp", bytes.NewBufferString(body))
+		req.Header.Set("User-Agent", "brew-client/1.2")
+		s.HandleHTTP(httptest.NewRecorder(), req)
+	}
+	post(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"working_tool"}}`)
+	post(`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"failing_tool"}}`)
+	post(`{"jsonrpc":"2.0","id":3,"method":"resources/read","params":{"uri":"usage://summary?admin_token=secret"}}`)
+	post(`{"jsonrpc":"2.0","id":4,"method":"tools/list"}`)
+
+	expected := []mcp.UsageEvent{
+		{Kind: mcp.UsageTool, Name: "working_tool", Client: "brew-client/1.2"},
+		{Kind: mcp.UsageTool, Name: "failing_tool", ErrorCode: mcp.InternalError, Client: "brew-client/1.2"},
+		{Kind: mcp.UsageResource, Name: "usage://summary", ErrorCode: mcp.InvalidRequest, Client: "brew-client/1.2"},
+	}
+	if len(usage.events) != len(expected) {
+		t.Fatalf("Expected %d usage events, got %+v", len(expected), usage.events)
+	}
+	for i, event := range usage.events {
+		if event.At.IsZero() || event.Duration < 0 {
+			t.Errorf("Expected event %d to be timed, got %+v", i, event)
+		}
+		event.At, event.Duration = expected[i].At, expected[i].Duration
+		if event != expected[i] {
+			t.Errorf("Expected event %d to be %+v, got %+v", i, expected[i], event)
+		}
+	}
+	if !usage.events[0].Success() || usage.events[1].Success() {
+		t.Error("Expected only the working tool's call to succeed")
+	}
+
+	// The client label set by a transport is kept
+	data, _ := json.Marshal(mcp.NewMessage("tools/call", mcp.CallToolRequest{Name: "working_tool"}))
+	s.ProcessMessage(mcp.WithClientLabel(context.Background(), "brewery-app"), data)
+	if last := usage.events[len(usage.events)-1]; last.Client != "brewery-app" {
+		t.Errorf("Expected the call labelled brewery-app, got %q", last.Client)
+	}
+}
@@ -0,0 +1,86 @@
+// Package models defines the data models and database schema for Brewsource MCP.
+package models
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	// batchInsertRows is the most rows one multi-row INSERT carries when seeding and importing. At
+	// the 17 columns of seedBreweryColumns, the widest list, that is 8,500 parameters, well inside
+	// maxBatchParameters, so SQLite needs no row-at-a-time fallback.
+	batchInsertRows = 500
+	// maxBatchParameters is the most parameters one statement may bind in the SQLite bundled with
+	// go-sqlite3; PostgreSQL allows 65,535. BatchInserts refuses batches that could exceed it.
+	maxBatchParameters = 32766
+)
+
+// BatchInsert is one multi-row INSERT statement and its arguments, in placeholder order.
+type BatchInsert struct {
+	Query string
+	Args  []interface{}
+}
+
+// BatchInserts builds the INSERT statements writing rows into the columns of table, size rows to a
+// statement, the last taking the remainder. Each row holds one value per column, numbered $1 onwards
+// within its statement, and suffix, such as "ON CONFLICT DO NOTHING", ends every statement. It
+// returns no statements for no rows, and panics when a row's length does not match the columns,
+// size is not positive, or a full batch would bind more than maxBatchParameters, all of which are
+// programming errors; the last is checked whatever the number of rows, so that tests catch a
+// column list grown too wide for its batch size.
+func BatchInserts(table string, columns []string, rows [][]interface{}, size int, suffix string) []BatchInsert {
+	if size < 1 {
+		panic(fmt.Sprintf("batch size must be positive, got %d", size))
+	}
+	if size*len(columns) > maxBatchParameters {
+		panic(fmt.Sprintf("batches of %d rows of %d columns into %s exceed %d parameters",
+			size, len(columns), table, maxBatchParameters))
+	}
+	head := "INSERT INTO " + table + " (" + strings.Join(columns, ", ") + ") VALUES "
+	var batches []BatchInsert
+	for start := 0; start < len(rows); start += size {
+		chunk := rows[start:min(start+size, len(rows))]
+		values := make([]string, len(chunk))
+		args := make([]interface{}, 0, len(chunk)*len(columns))
+		for i, row := range chunk {
+			if len(row) != len(columns) {
+				panic(fmt.Sprintf("row %d of %s has %d values for %d columns", start+i, table, len(row), len(columns)))
+			}
+			params := make([]string, len(row))
+			for j := range row {
+				params[j] = "$" + strconv.Itoa(len(args)+j+1)
+			}
+			values[i] = "(" + strings.Join(params, ", ") + ")"
+			args = append(args, row...)
+		}
+		query := head + strings.Join(values, ", ")
+		if suffix != "" {
+			query += " " + suffix
+		}
+		batches = append(batches, BatchInsert{Query: query, Args: args})
+	}
+	return batches
+}
+
+// execBatchInserts runs the statements in order, returning how many rows they inserted. A statement
+// that fails stops the rest; run them in a transaction to undo the ones before it.
+func execBatchInserts(ctx context.Context, db sqlx.ExecerContext, batches []BatchInsert) (int, error) {
+	inserted := 0
+	for _, batch := range batches {
+		result, err := db.ExecContext(ctx, batch.Query, batch.Args...)
+		if err != nil {
+			return inserted, err
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return inserted, err
+		}
+		inserted += int(affected)
+	}
+	return inserted, nil
+}
@@ -0,0 +1,76 @@
+package models_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// batchRows returns n rows of two values, the row number and its square.
+func batchRows(n int) [][]interface{} {
+	rows := make([][]interface{}, n)
+	for i := range rows {
+		rows[i] = []interface{}{i, i * i}
+	}
+	return rows
+}
+
+func TestBatchInserts(t *testing.T) {
+	columns := []string{"a", "b"}
+
+	t.Run("Splits rows into full batches and a remainder", func(t *testing.T) {
+		batches := models.BatchInserts("t", columns, batchRows(5), 2, "ON CONFLICT DO NOTHING")
+
+		require.Len(t, batches, 3)
+		assert.Equal(t, "INSERT INTO t (a, b) VALUES ($1, $2), ($3, $4) ON CONFLICT DO NOTHING", batches[0].Query)
+		assert.Equal(t, []interface{}{0, 0, 1, 1}, batches[0].Args)
+		assert.Equal(t, []interface{}{2, 4, 3, 9}, batches[1].Args, "Placeholders should restart at $1 per batch")
+		assert.Equal(t, "INSERT INTO t (a, b) VALUES ($1, $2) ON CONFLICT DO NOTHING", batches[2].Query)
+		assert.Equal(t, []interface{}{4, 16}, batches[2].Args)
+	})
+
+	t.Run("Leaves no partial batch at an exact multiple", func(t *testing.T) {
+		batches := models.BatchInserts("t", columns, batchRows(4), 2, "")
+
+		require.Len(t, batches, 2)
+		assert.Equal(t, "INSERT INTO t (a, b) VALUES ($1, $2), ($3, $4)", batches[1].Query)
+	})
+
+	t.Run("Numbers one placeholder per argument", func(t *testing.T) {
+		for _, n := range []int{1, 499, 500, 501, 1000, 1001} {
+			batches := models.BatchInserts("t", columns, batchRows(n), 500, "")
+			total := 0
+			for _, batch := range batches {
+				rows := len(batch.Args) / len(columns)
+				assert.LessOrEqual(t, rows, 500)
+				assert.Contains(t, batch.Query, "$"+strconv.Itoa(len(batch.Args))+")")
+				assert.NotContains(t, batch.Query, "$"+strconv.Itoa(len(batch.Args)+1))
+				total += rows
+			}
+			assert.Equal(t, n, total, "Every row should be inserted once for %d rows", n)
+			assert.Len(t, batches, (n+499)/500)
+		}
+	})
+
+	t.Run("Builds nothing for no rows", func(t *testing.T) {
+		assert.Empty(t, models.BatchInserts("t", columns, nil, 500, ""))
+	})
+
+	t.Run("Rejects rows that do not match the columns", func(t *testing.T) {
+		assert.Panics(t, func() { models.BatchInserts("t", columns, [][]interface{}{{1}}, 500, "") })
+		assert.Panics(t, func() { models.BatchInserts("t", columns, batchRows(1), 0, "") })
+	})
+
+	t.Run("Rejects batches over the SQLite parameter limit", func(t *testing.T) {
+		wide := make([]string, 66)
+		for i := range wide {
+			wide[i] = "c" + strconv.Itoa(i)
+		}
+		assert.Panics(t, func() { models.BatchInserts("t", wide, nil, 500, "") },
+			"500 rows of 66 columns bind 33,000 parameters")
+		assert.NotPanics(t, func() { models.BatchInserts("t", wide[:65], nil, 500, "") })
+	})
+}
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// sqliteURLPrefix starts a DATABASE_URL naming a SQLite database, such as sqlite:///data/brewsource.db
+// for an absolute path, sqlite://brewsource.db for one relative to the working directory, or
+// sqlite://:memory: for a database that lasts as long as the process.
+const sqliteURLPrefix = "sqlite://"
+
+// sqliteDefaults are the connection parameters a SQLite database is opened with unless its URL sets
+// them: foreign keys enforced as on PostgreSQL, a wait for a locked database instead of failing at
+// once, and write-ahead logging so that reads need not wait for writes.
+var sqliteDefaults = map[string]string{
+	"_foreign_keys": "1",
+	"_busy_timeout": "5000",
+	"_journal_mode": "WAL",
+}
+
+// ParseDatabaseURL returns the driver name and data source name for a DATABASE_URL. A sqlite://
+// URL opens SQLite with the sqliteDefaults its query does not override; anything else is handed to
+// the PostgreSQL driver as it is.
+func ParseDatabaseURL(databaseURL string) (driverName, dataSourceName string, err error) {
+	path, found := strings.CutPrefix(databaseURL, sqliteURLPrefix)
+	if !found {
+		return "postgres", databaseURL, nil
+	}
+	path, rawQuery, _ := strings.Cut(path, "?")
+	params, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return "", "", err
+	}
+	if path == ":memory:" {
+		// Connections share one in-memory database rather than each opening its own
+		path = "file::memory:"
+		params.Set("cache", "shared")
+	}
+	for name, value := range sqliteDefaults {
+		if !params.Has(name) {
+			params.Set(name, value)
+		}
+	}
+	return sqliteDriver, path + "?" + params.Encode(), nil
+}
+
+// IsSQLite reports whether db is a SQLite database.
+func IsSQLite(db *sqlx.DB) bool {
+	return db.DriverName() == sqliteDriver
+}
@@ -0,0 +1,571 @@
+// Package models defines the data models and database schema for Brewsource MCP.
+package models
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/services"
+	"github.com/jmoiron/sqlx"
+)
+
+// ImportFormat is the encoding of a brewery or beer import file.
+type ImportFormat string
+
+// Import formats. CSV files have a header row naming the columns; JSON files hold an array of
+// objects. Both use the field names of services.Brewery and services.Beer, such as "brewery_type"
+// or "abv". Beer rows name their brewery with "brewery_id" or a "brewery" name.
+const (
+	ImportCSV  ImportFormat = "csv"
+	ImportJSON ImportFormat = "json"
+)
+
+// ErrImportRows is wrapped by the error returned when a strict import has rows that cannot be
+// imported.
+var ErrImportRows = errors.New("import has invalid rows")
+
+var (
+	breweryImportColumns = []string{
+		"name", "brewery_type", "street", "city", "state", "postal_code", "country", "phone", "website_url",
+		"latitude", "longitude",
+	}
+	beerImportColumns = []string{"brewery_id", "name", "style", "abv", "ibu", "srm", "description"}
+	// beerImportFields adds the brewery name, which is resolved to brewery_id.
+	beerImportFields = append(slices.Clone(beerImportColumns), "brewery")
+	// beerWriteColumns adds the flavour tags derived from each beer's description.
+	beerWriteColumns = append(slices.Clone(beerImportColumns), "tags")
+)
+
+// ImportFormatFromPath returns the format named by a file's extension, ".csv" or ".json".
+func ImportFormatFromPath(path string) (ImportFormat, error) {
+	switch format := ImportFormat(strings.ToLower(strings.TrimPrefix(filepath.Ext(path), "."))); format {
+	case ImportCSV, ImportJSON:
+		return format, nil
+	default:
+		return "", fmt.Errorf("cannot tell the import format of %q; use a .csv or .json file", path)
+	}
+}
+
+// ImportOptions controls how rows matching existing records, and invalid rows, are handled.
+type ImportOptions struct {
+	// Upsert updates a brewery with the same name and city, or a beer with the same name at the same
+	// brewery, instead of skipping the row.
+	Upsert bool
+	// Strict imports nothing when any row is invalid.
+	Strict bool
+}
+
+// RowError reports a row that could not be imported. Rows are numbered from 1, not counting a CSV
+// header.
+type RowError struct {
+	Row  int
+	Name string
+	Err  error
+}
+
+// Error returns the row number, the name in the row if any, and the reason.
+func (e RowError) Error() string {
+	if e.Name == "" {
+		return fmt.Sprintf("row %d: %v", e.Row, e.Err)
+	}
+	return fmt.Sprintf("row %d (%s): %v", e.Row, e.Name, e.Err)
+}
+
+// ImportSummary counts the outcome of each row of an import.
+type ImportSummary struct {
+	Inserted int
+	Updated  int
+	Skipped  int // rows matching an existing record when not upserting
+	Errors   []RowError
+	// Elapsed is the time from reading the file to committing the import; zero when nothing was
+	// committed.
+	Elapsed time.Duration
+}
+
+// Rows returns the number of rows read: those inserted, updated, skipped, and errored.
+func (s *ImportSummary) Rows() int {
+	return s.Inserted + s.Updated + s.Skipped + len(s.Errors)
+}
+
+// RowsPerSecond returns the rows read per second of Elapsed, or 0 when Elapsed is zero.
+func (s *ImportSummary) RowsPerSecond() float64 {
+	if s.Elapsed <= 0 {
+		return 0
+	}
+	return float64(s.Rows()) / s.Elapsed.Seconds()
+}
+
+// String returns the counts, e.g. "12 inserted, 3 updated, 0 skipped, 1 errored".
+func (s *ImportSummary) String() string {
+	return fmt.Sprintf(
+		"%d inserted, %d updated, %d skipped, %d errored", s.Inserted, s.Updated, s.Skipped, len(s.Errors),
+	)
+}
+
+// ImportBreweriesFromReader imports breweries from r in one transaction. Invalid rows are collected
+// in the summary and the rest are imported, unless opts.Strict is set, in which case nothing is
+// written and the error wraps ErrImportRows. A brewery matches an existing one by name and city,
+// ignoring case. An unreadable file or a failed write returns an error and imports nothing.
+func ImportBreweriesFromReader(
+	ctx context.Context,
+	db *sqlx.DB,
+	r io.Reader,
+	format ImportFormat,
+	opts ImportOptions,
+) (*ImportSummary, error) {
+	started := time.Now()
+	records, err := readImportRecords(r, format, breweryImportColumns)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start brewery import: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	existing := map[string]int{}
+	rows, err := tx.QueryxContext(ctx, `SELECT id, name, COALESCE(city, '') FROM breweries`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load existing breweries: %w", err)
+	}
+	for rows.Next() {
+		var (
+			id         int
+			name, city string
+		)
+		if err = rows.Scan(&id, &name, &city); err != nil {
+			_ = rows.Close()
+			return nil, fmt.Errorf("failed to load existing breweries: %w", err)
+		}
+		existing[breweryImportKey(name, city)] = id
+	}
+	if err = closeRows(rows); err != nil {
+		return nil, fmt.Errorf("failed to load existing breweries: %w", err)
+	}
+
+	plan := &importPlan{opts: opts, started: started, seen: map[string]int{}}
+	for i, record := range records {
+		brewery, recordErr := breweryFromRecord(record)
+		if recordErr == nil {
+			brewery.Normalize()
+			recordErr = brewery.Validate()
+		}
+		if recordErr != nil {
+			plan.fail(i+1, record.name(), recordErr)
+			continue
+		}
+		key := breweryImportKey(brewery.Name, brewery.City)
+		plan.add(i+1, brewery.Name, key, existing[key], []interface{}{
+			brewery.Name, brewery.BreweryType, brewery.Street, brewery.City, brewery.State, brewery.PostalCode,
+			brewery.Country, brewery.Phone, brewery.WebsiteURL, brewery.Latitude, brewery.Longitude,
+		})
+	}
+	return plan.apply(ctx, tx, "breweries", breweryImportColumns)
+}
+
+// ImportBeersFromReader imports beers from r as ImportBreweriesFromReader imports breweries. Each
+// row's brewery must already exist; a "brewery" name must match one brewery exactly, ignoring case.
+// A beer matches an existing one by brewery and name, ignoring case. Each beer is tagged with the
+// flavour tags of services.LoadBeerTags that its description mentions.
+func ImportBeersFromReader(
+	ctx context.Context,
+	db *sqlx.DB,
+	r io.Reader,
+	format ImportFormat,
+	opts ImportOptions,
+) (*ImportSummary, error) {
+	started := time.Now()
+	records, err := readImportRecords(r, format, beerImportFields)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := services.LoadBeerTags()
+	if err != nil {
+		return nil, err
+	}
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start beer import: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	breweries, err := loadImportBreweries(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	existing := map[string]int{}
+	rows, err := tx.QueryxContext(ctx, `SELECT id, brewery_id, name FROM beers`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load existing beers: %w", err)
+	}
+	for rows.Next() {
+		var (
+			id, breweryID int
+			name          string
+		)
+		if err = rows.Scan(&id, &breweryID, &name); err != nil {
+			_ = rows.Close()
+			return nil, fmt.Errorf("failed to load existing beers: %w", err)
+		}
+		existing[beerImportKey(breweryID, name)] = id
+	}
+	if err = closeRows(rows); err != nil {
+		return nil, fmt.Errorf("failed to load existing beers: %w", err)
+	}
+
+	plan := &importPlan{opts: opts, started: started, seen: map[string]int{}}
+	for i, record := range records {
+		beer, recordErr := beerFromRecord(record, breweries)
+		if recordErr == nil {
+			beer.Normalize()
+			recordErr = beer.Validate()
+		}
+		if recordErr != nil {
+			plan.fail(i+1, record.name(), recordErr)
+			continue
+		}
+		key := beerImportKey(beer.BreweryID, beer.Name)
+		plan.add(i+1, beer.Name, key, existing[key], []interface{}{
+			beer.BreweryID, beer.Name, beer.Style, beer.ABV, beer.IBU, beer.SRM, beer.Description,
+			services.TagList(tags.Extract(beer.Description)),
+		})
+	}
+	return plan.apply(ctx, tx, "beers", beerWriteColumns)
+}
+
+func breweryImportKey(name, city string) string {
+	return strings.ToLower(name) + "\x00" + strings.ToLower(city)
+}
+
+func beerImportKey(breweryID int, name string) string {
+	return strconv.Itoa(breweryID) + "\x00" + strings.ToLower(name)
+}
+
+// importBreweries indexes existing breweries for resolving beer rows: ids holds every brewery ID,
+// and byName the IDs of the breweries with each lowercased name.
+type importBreweries struct {
+	ids    map[int]bool
+	byName map[string][]int
+}
+
+func loadImportBreweries(ctx context.Context, tx *sqlx.Tx) (*importBreweries, error) {
+	breweries := &importBreweries{ids: map[int]bool{}, byName: map[string][]int{}}
+	rows, err := tx.QueryxContext(ctx, `SELECT id, name FROM breweries`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load breweries: %w", err)
+	}
+	for rows.Next() {
+		var (
+			id   int
+			name string
+		)
+		if err = rows.Scan(&id, &name); err != nil {
+			_ = rows.Close()
+			return nil, fmt.Errorf("failed to load breweries: %w", err)
+		}
+		breweries.ids[id] = true
+		key := strings.ToLower(name)
+		breweries.byName[key] = append(breweries.byName[key], id)
+	}
+	if err = closeRows(rows); err != nil {
+		return nil, fmt.Errorf("failed to load breweries: %w", err)
+	}
+	return breweries, nil
+}
+
+// resolve returns the brewery a beer row refers to by ID or by name.
+func (b *importBreweries) resolve(id int, name string) (int, error) {
+	switch {
+	case id != 0 && name != "":
+		return 0, errors.New("give brewery_id or brewery, not both")
+	case id != 0 && !b.ids[id]:
+		return 0, fmt.Errorf("brewery %d not found", id)
+	case id != 0:
+		return id, nil
+	case name == "":
+		return 0, errors.New("brewery_id or brewery is required")
+	}
+	switch ids := b.byName[strings.ToLower(name)]; len(ids) {
+	case 0:
+		return 0, fmt.Errorf("brewery %q not found", name)
+	case 1:
+		return ids[0], nil
+	default:
+		return 0, fmt.Errorf("brewery name %q matches %d breweries; use brewery_id", name, len(ids))
+	}
+}
+
+func closeRows(rows *sqlx.Rows) error {
+	if err := rows.Err(); err != nil {
+		_ = rows.Close()
+		return err
+	}
+	return rows.Close()
+}
+
+// importPlan sorts validated rows into inserts, updates, and skips, and collects row errors.
+type importPlan struct {
+	opts    ImportOptions
+	started time.Time // when reading the file began, for the summary's Elapsed
+	summary ImportSummary
+	seen    map[string]int // row number of each key in the file, to catch duplicates within it
+	inserts [][]interface{}
+	updates [][]interface{} // column values followed by the ID to update
+}
+
+func (p *importPlan) fail(row int, name string, err error) {
+	p.summary.Errors = append(p.summary.Errors, RowError{Row: row, Name: name, Err: err})
+}
+
+// add plans a valid row with the given key, which matches the record existingID, or none when zero.
+func (p *importPlan) add(row int, name, key string, existingID int, values []interface{}) {
+	if first, ok := p.seen[key]; ok {
+		p.fail(row, name, fmt.Errorf("duplicates row %d", first))
+		return
+	}
+	p.seen[key] = row
+	switch {
+	case existingID == 0:
+		p.inserts = append(p.inserts, values)
+	case p.opts.Upsert:
+		p.updates = append(p.updates, append(values, existingID))
+	default:
+		p.summary.Skipped++
+	}
+}
+
+// apply writes the planned rows to table, inserting them in batches of batchInsertRows, and commits
+// tx.
+func (p *importPlan) apply(ctx context.Context, tx *sqlx.Tx, table string, columns []string) (*ImportSummary, error) {
+	if p.opts.Strict && len(p.summary.Errors) > 0 {
+		return &p.summary, fmt.Errorf(
+			"%w: %d row(s) failed, so nothing was imported", ErrImportRows, len(p.summary.Errors),
+		)
+	}
+	if _, err := execBatchInserts(ctx, tx, BatchInserts(table, columns, p.inserts, batchInsertRows, "")); err != nil {
+		return nil, fmt.Errorf("failed to insert %s: %w", table, err)
+	}
+
+	assignments := make([]string, len(columns))
+	for i, column := range columns {
+		assignments[i] = column + " = $" + strconv.Itoa(i+1)
+	}
+	update := "UPDATE " + table + " SET " + strings.Join(assignments, ", ") +
+		" WHERE id = $" + strconv.Itoa(len(columns)+1)
+	for _, row := range p.updates {
+		if _, err := tx.ExecContext(ctx, update, row...); err != nil {
+			return nil, fmt.Errorf("failed to update %s: %w", table, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit %s import: %w", table, err)
+	}
+	p.summary.Inserted, p.summary.Updated = len(p.inserts), len(p.updates)
+	p.summary.Elapsed = time.Since(p.started)
+	return &p.summary, nil
+}
+
+// importRecord is one row of an import file: its fields by column name, or the error that stopped
+// the row from being read.
+type importRecord struct {
+	fields map[string]interface{}
+	err    error
+}
+
+// name returns the row's name field for error reports, if it has a readable one.
+func (r importRecord) name() string {
+	name, _ := r.fields["name"].(string)
+	return strings.TrimSpace(name)
+}
+
+// readImportRecords reads the rows of an import file. A file that cannot be parsed, or that names a
+// column outside fields, is an error; a row that cannot be read is returned with its error set.
+func readImportRecords(r io.Reader, format ImportFormat, fields []string) ([]importRecord, error) {
+	switch format {
+	case ImportCSV:
+		return readCSVRecords(r, fields)
+	case ImportJSON:
+		return readJSONRecords(r, fields)
+	default:
+		return nil, fmt.Errorf("unknown import format %q; use %s or %s", format, ImportCSV, ImportJSON)
+	}
+}
+
+func readCSVRecords(r io.Reader, fields []string) ([]importRecord, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+	for i, column := range header {
+		header[i] = strings.ToLower(strings.TrimSpace(column))
+		if !slices.Contains(fields, header[i]) {
+			return nil, fmt.Errorf("unknown CSV column %q (columns may be %s)", column, strings.Join(fields, ", "))
+		}
+	}
+
+	records := []importRecord{}
+	for {
+		row, readErr := reader.Read()
+		if errors.Is(readErr, io.EOF) {
+			return records, nil
+		}
+		var parseErr *csv.ParseError
+		if readErr != nil && !(errors.As(readErr, &parseErr) && errors.Is(parseErr.Err, csv.ErrFieldCount)) {
+			return nil, fmt.Errorf("failed to read CSV: %w", readErr)
+		}
+		record := importRecord{fields: map[string]interface{}{}, err: readErr}
+		for i, value := range row {
+			// Empty cells are left unset, so that optional numbers are not parsed from them.
+			if i < len(header) && strings.TrimSpace(value) != "" {
+				record.fields[header[i]] = value
+			}
+		}
+		if record.err != nil {
+			record.err = fmt.Errorf("expected %d columns, got %d", len(header), len(row))
+		}
+		records = append(records, record)
+	}
+}
+
+func readJSONRecords(r io.Reader, fields []string) ([]importRecord, error) {
+	var rows []json.RawMessage
+	if err := json.NewDecoder(r).Decode(&rows); err != nil {
+		return nil, fmt.Errorf("failed to read JSON array: %w", err)
+	}
+	records := make([]importRecord, 0, len(rows))
+	for _, row := range rows {
+		record := importRecord{}
+		decoder := json.NewDecoder(strings.NewReader(string(row)))
+		decoder.UseNumber()
+		if err := decoder.Decode(&record.fields); err != nil || record.fields == nil {
+			record.err = errors.New("expected a JSON object")
+		}
+		for key := range record.fields {
+			if !slices.Contains(fields, key) {
+				record.err = fmt.Errorf("unknown field %q", key)
+			}
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// fieldReader converts record fields to Go values, keeping the first conversion error.
+type fieldReader struct {
+	fields map[string]interface{}
+	err    error
+}
+
+func (f *fieldReader) text(key string) string {
+	switch v := f.fields[key].(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	default:
+		f.fail(fmt.Errorf("%s must be text", key))
+		return ""
+	}
+}
+
+// optionalNumber returns nil for an absent field.
+func (f *fieldReader) optionalNumber(key string) *float64 {
+	var text string
+	switch v := f.fields[key].(type) {
+	case nil:
+		return nil
+	case string:
+		text = strings.TrimSpace(v)
+	case json.Number:
+		text = v.String()
+	default:
+		f.fail(fmt.Errorf("%s must be a number", key))
+		return nil
+	}
+	value, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		f.fail(fmt.Errorf("%s must be a number (got %q)", key, text))
+		return nil
+	}
+	return &value
+}
+
+func (f *fieldReader) number(key string) float64 {
+	if value := f.optionalNumber(key); value != nil {
+		return *value
+	}
+	return 0
+}
+
+func (f *fieldReader) integer(key string) int {
+	value := f.number(key)
+	if value != float64(int(value)) {
+		f.fail(fmt.Errorf("%s must be a whole number (got %g)", key, value))
+	}
+	return int(value)
+}
+
+func (f *fieldReader) fail(err error) {
+	if f.err == nil {
+		f.err = err
+	}
+}
+
+func breweryFromRecord(record importRecord) (services.Brewery, error) {
+	if record.err != nil {
+		return services.Brewery{}, record.err
+	}
+	f := &fieldReader{fields: record.fields}
+	brewery := services.Brewery{
+		Name:        f.text("name"),
+		BreweryType: f.text("brewery_type"),
+		Street:      f.text("street"),
+		City:        f.text("city"),
+		State:       f.text("state"),
+		PostalCode:  f.text("postal_code"),
+		Country:     f.text("country"),
+		Phone:       f.text("phone"),
+		WebsiteURL:  f.text("website_url"),
+		Latitude:    f.optionalNumber("latitude"),
+		Longitude:   f.optionalNumber("longitude"),
+	}
+	return brewery, f.err
+}
+
+func beerFromRecord(record importRecord, breweries *importBreweries) (services.Beer, error) {
+	if record.err != nil {
+		return services.Beer{}, record.err
+	}
+	f := &fieldReader{fields: record.fields}
+	beer := services.Beer{
+		BreweryID:   f.integer("brewery_id"),
+		Name:        f.text("name"),
+		Style:       f.text("style"),
+		ABV:         f.number("abv"),
+		IBU:         f.integer("ibu"),
+		SRM:         f.number("srm"),
+		Description: f.text("description"),
+	}
+	breweryName := strings.TrimSpace(f.text("brewery"))
+	if f.err != nil {
+		return beer, f.err
+	}
+	var err error
+	beer.BreweryID, err = breweries.resolve(beer.BreweryID, breweryName)
+	return beer, err
+}
@@ -0,0 +1,186 @@
+package models_test
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/models"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/services"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupImportDB returns a test database holding one brewery, "Existing Brewery" in Cape Town with ID
+// 1, which has one beer, "Existing Lager".
+func setupImportDB(t *testing.T) *sqlx.DB {
+	db := setupTestDB(t)
+	// Each connection to an in-memory database sees its own database.
+	db.SetMaxOpenConns(1)
+	_, err := db.Exec(`INSERT INTO breweries (name, brewery_type, city, country)
+		VALUES ('Existing Brewery', 'micro', 'Cape Town', 'South Africa')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO beers (brewery_id, name, style, abv, ibu)
+		VALUES (1, 'Existing Lager', 'Lager', 4.5, 20)`)
+	require.NoError(t, err)
+	return db
+}
+
+func TestImportFormatFromPath(t *testing.T) {
+	format, err := models.ImportFormatFromPath("data/Breweries.CSV")
+	require.NoError(t, err)
+	assert.Equal(t, models.ImportCSV, format)
+
+	format, err = models.ImportFormatFromPath("beers.json")
+	require.NoError(t, err)
+	assert.Equal(t, models.ImportJSON, format)
+
+	_, err = models.ImportFormatFromPath("beers.xlsx")
+	assert.Error(t, err)
+}
+
+func TestImportBreweriesFromReader_CSV(t *testing.T) {
+	ctx := context.Background()
+	input := `Name,brewery_type,city,country,latitude,longitude
+New Brewery,Micro,Durban,South Africa,-29.85,31.02
+existing brewery,brewpub,cape town,South Africa,,
+Bad Type,winery,Durban,South Africa,,
+Half Located,micro,Durban,South Africa,-29.85,
+New Brewery,micro,Durban,South Africa,,
+`
+
+	t.Run("skips existing breweries and collects row errors", func(t *testing.T) {
+		db := setupImportDB(t)
+		defer teardownTestDB(t, db)
+
+		summary, err := models.ImportBreweriesFromReader(
+			ctx, db, strings.NewReader(input), models.ImportCSV, models.ImportOptions{},
+		)
+		require.NoError(t, err)
+		assert.Equal(t, "1 inserted, 0 updated, 1 skipped, 3 errored", summary.String())
+		require.Len(t, summary.Errors, 3)
+		assert.Equal(t, 3, summary.Errors[0].Row)
+		assert.Contains(t, summary.Errors[0].Error(), "row 3 (Bad Type)")
+		assert.Contains(t, summary.Errors[1].Error(), "latitude and longitude must be given together")
+		assert.Contains(t, summary.Errors[2].Error(), "duplicates row 1")
+
+		var breweryType string
+		require.NoError(t, db.Get(&breweryType, "SELECT brewery_type FROM breweries WHERE name = 'New Brewery'"))
+		assert.Equal(t, "micro", breweryType)
+		require.NoError(t, db.Get(&breweryType, "SELECT brewery_type FROM breweries WHERE id = 1"))
+		assert.Equal(t, "micro", breweryType)
+	})
+
+	t.Run("upsert updates existing breweries", func(t *testing.T) {
+		db := setupImportDB(t)
+		defer teardownTestDB(t, db)
+
+		summary, err := models.ImportBreweriesFromReader(
+			ctx, db, strings.NewReader(input), models.ImportCSV, models.ImportOptions{Upsert: true},
+		)
+		require.NoError(t, err)
+		assert.Equal(t, 1, summary.Inserted)
+		assert.Equal(t, 1, summary.Updated)
+
+		var breweryType string
+		require.NoError(t, db.Get(&breweryType, "SELECT brewery_type FROM breweries WHERE id = 1"))
+		assert.Equal(t, "brewpub", breweryType)
+	})
+
+	t.Run("strict mode imports nothing when a row fails", func(t *testing.T) {
+		db := setupImportDB(t)
+		defer teardownTestDB(t, db)
+
+		summary, err := models.ImportBreweriesFromReader(
+			ctx, db, strings.NewReader(input), models.ImportCSV, models.ImportOptions{Strict: true},
+		)
+		require.ErrorIs(t, err, models.ErrImportRows)
+		assert.Len(t, summary.Errors, 3)
+
+		var count int
+		require.NoError(t, db.Get(&count, "SELECT COUNT(*) FROM breweries"))
+		assert.Equal(t, 1, count)
+	})
+
+	t.Run("rejects unknown columns", func(t *testing.T) {
+		db := setupImportDB(t)
+		defer teardownTestDB(t, db)
+
+		_, err := models.ImportBreweriesFromReader(
+			ctx, db, strings.NewReader("name,founded\nNew Brewery,1999\n"), models.ImportCSV, models.ImportOptions{},
+		)
+		assert.ErrorContains(t, err, `unknown CSV column "founded"`)
+	})
+}
+
+func TestImportBeersFromReader_JSON(t *testing.T) {
+	ctx := context.Background()
+	input := `[
+		{"brewery": "EXISTING BREWERY", "name": "New IPA", "style": "IPA", "abv": 6.5, "ibu": 60,
+		 "description": "Juicy mango and resinous pine"},
+		{"brewery_id": 1, "name": "existing lager", "style": "Pilsner", "abv": "5.0", "ibu": 35},
+		{"brewery": "Missing Brewery", "name": "Lost Stout"},
+		{"brewery_id": 1, "name": "Strong Ale", "abv": 35},
+		{"brewery_id": 1, "name": "Odd Beer", "ibu": 12.5},
+		{"brewery_id": 1, "name": "Extra", "colour": "gold"},
+		"not an object"
+	]`
+
+	db := setupImportDB(t)
+	defer teardownTestDB(t, db)
+
+	summary, err := models.ImportBeersFromReader(
+		ctx, db, strings.NewReader(input), models.ImportJSON, models.ImportOptions{Upsert: true},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, "1 inserted, 1 updated, 0 skipped, 5 errored", summary.String())
+	wantErrors := []string{
+		`brewery "Missing Brewery" not found`,
+		"abv must be between 0 and 20",
+		"ibu must be a whole number",
+		`unknown field "colour"`,
+		"expected a JSON object",
+	}
+	for i, want := range wantErrors {
+		assert.Contains(t, summary.Errors[i].Error(), want)
+	}
+
+	var style string
+	require.NoError(t, db.Get(&style, "SELECT style FROM beers WHERE id = 1"))
+	assert.Equal(t, "Pilsner", style)
+	var breweryID int
+	require.NoError(t, db.Get(&breweryID, "SELECT brewery_id FROM beers WHERE name = 'New IPA'"))
+	assert.Equal(t, 1, breweryID)
+	var tags services.TagList
+	require.NoError(t, db.Get(&tags, "SELECT tags FROM beers WHERE name = 'New IPA'"))
+	assert.Equal(t, services.TagList{"pine", "tropical"}, tags, "Imported beers should be tagged from their description")
+}
+
+func TestImportBeersFromReader_Batches(t *testing.T) {
+	db := setupImportDB(t)
+	defer teardownTestDB(t, db)
+
+	var input strings.Builder
+	input.WriteString("brewery_id,name,abv\n")
+	// Two full batches and one row over
+	for i := range 1001 {
+		input.WriteString("1,Batch Beer " + strconv.Itoa(i) + ",5\n")
+	}
+	summary, err := models.ImportBeersFromReader(
+		context.Background(), db, strings.NewReader(input.String()), models.ImportCSV, models.ImportOptions{},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, 1001, summary.Inserted)
+	assert.Equal(t, 1001, summary.Rows())
+	assert.Positive(t, summary.Elapsed)
+	assert.Positive(t, summary.RowsPerSecond())
+
+	var count int
+	require.NoError(t, db.Get(&count, "SELECT COUNT(*) FROM beers"))
+	assert.Equal(t, 1002, count)
+	var last string
+	require.NoError(t, db.Get(&last, "SELECT name FROM beers ORDER BY id DESC LIMIT 1"))
+	assert.Equal(t, "Batch Beer 1000", last, "The row after the last full batch should be inserted")
+}
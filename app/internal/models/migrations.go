@@ -0,0 +1,702 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// baselineVersion is the last migration a database created before migrations were tracked is
+	// known to have: such a database is stamped with it instead of running it. The later migrations
+	// tolerate finding their changes already made.
+	baselineVersion = 1
+	// migrationLockID keys the PostgreSQL advisory lock that keeps two servers starting together from
+	// applying the same migration.
+	migrationLockID = 7_201_944
+	// sqliteDriver is the driver name of SQLite, which the schema supports alongside PostgreSQL.
+	sqliteDriver = "sqlite3"
+)
+
+// ErrInvalidMigrationSteps is returned by MigrateDown for a step count below one.
+var ErrInvalidMigrationSteps = errors.New("the number of migrations to revert must be at least 1")
+
+// Migration is one versioned change to the database schema. Up applies it and Down reverts it, each
+// in the transaction that records or removes its row in schema_migrations.
+type Migration struct {
+	Version int
+	Name    string
+	Up      MigrationFunc
+	Down    MigrationFunc
+}
+
+// MigrationFunc changes the schema within a migration's transaction.
+type MigrationFunc func(ctx context.Context, tx *sqlx.Tx) error
+
+// AppliedMigration is a row of schema_migrations. A baseline migration was recorded without running,
+// for a database whose schema predates migration tracking.
+type AppliedMigration struct {
+	Version   int       `db:"version"`
+	Name      string    `db:"name"`
+	Baseline  bool      `db:"baseline"`
+	AppliedAt time.Time `db:"applied_at"`
+}
+
+// migrations are the schema changes in the order they apply. Add new ones at the end with the next
+// version; never edit or renumber one that has been released.
+var migrations = []Migration{
+	{
+		Version: 1,
+		Name:    "create breweries and beers",
+		Up:      createCatalogTables,
+		Down:    dropCatalogTables,
+	},
+	{
+		Version: 2,
+		Name:    "add brewery coordinates",
+		Up: func(ctx context.Context, tx *sqlx.Tx) error {
+			if err := addColumn(ctx, tx, "breweries", "latitude", "DOUBLE PRECISION"); err != nil {
+				return err
+			}
+			return addColumn(ctx, tx, "breweries", "longitude", "DOUBLE PRECISION")
+		},
+		Down: execSQL(
+			`ALTER TABLE breweries DROP COLUMN longitude`,
+			`ALTER TABLE breweries DROP COLUMN latitude`,
+		),
+	},
+	{
+		// The brewery's ID in Open Brewery DB, set by the brewery sync
+		Version: 3,
+		Name:    "add brewery external id",
+		Up: func(ctx context.Context, tx *sqlx.Tx) error {
+			if err := addColumn(ctx, tx, "breweries", "external_id", "VARCHAR(255)"); err != nil {
+				return err
+			}
+			return execSQL(`CREATE UNIQUE INDEX IF NOT EXISTS idx_breweries_external_id ON breweries(external_id)`)(
+				ctx, tx)
+		},
+		Down: execSQL(
+			`DROP INDEX IF EXISTS idx_breweries_external_id`,
+			`ALTER TABLE breweries DROP COLUMN external_id`,
+		),
+	},
+	{
+		// Outcome of the last sync from each external data source
+		Version: 4,
+		Name:    "create sync state",
+		Up: execSQL(`CREATE TABLE IF NOT EXISTS sync_state (
+			source VARCHAR(50) PRIMARY KEY,
+			last_attempt_at TIMESTAMP NOT NULL,
+			last_success_at TIMESTAMP,
+			records_synced INTEGER NOT NULL DEFAULT 0,
+			last_error TEXT NOT NULL DEFAULT ''
+		)`),
+		Down: execSQL(`DROP TABLE IF EXISTS sync_state`),
+	},
+	{
+		// Relevance search is optional: managed databases may not allow installing pg_trgm. The
+		// trigram indexes also serve the ILIKE '%term%' search filters.
+		Version: 5,
+		Name:    "add trigram name indexes",
+		Up: postgresOnly(func(ctx context.Context, tx *sqlx.Tx) error {
+			if err := tryExec(ctx, tx, `CREATE EXTENSION IF NOT EXISTS pg_trgm`); err != nil {
+				logrus.Warnf("Trigram search unavailable, searches will sort by name: %v", err)
+				return nil
+			}
+			return execSQL(
+				`CREATE INDEX IF NOT EXISTS idx_breweries_name_trgm ON breweries USING gin(name gin_trgm_ops)`,
+				`CREATE INDEX IF NOT EXISTS idx_beers_name_trgm ON beers USING gin(name gin_trgm_ops)`,
+			)(ctx, tx)
+		}),
+		Down: postgresOnly(execSQL(
+			`DROP INDEX IF EXISTS idx_beers_name_trgm`,
+			`DROP INDEX IF EXISTS idx_breweries_name_trgm`,
+		)),
+	},
+	{
+		// The write API reports duplicates from these indexes. A database that already holds
+		// duplicate rows cannot build them; writes then succeed without the duplicate check.
+		Version: 6,
+		Name:    "add duplicate checks",
+		Up: func(ctx context.Context, tx *sqlx.Tx) error {
+			for _, query := range []string{
+				`CREATE UNIQUE INDEX IF NOT EXISTS idx_breweries_name_city_unique
+					ON breweries (LOWER(name), LOWER(COALESCE(city, '')))`,
+				`CREATE UNIQUE INDEX IF NOT EXISTS idx_beers_brewery_name_unique ON beers (brewery_id, LOWER(name))`,
+			} {
+				if err := tryExec(ctx, tx, query); err != nil {
+					logrus.Warnf("Duplicate checks unavailable, existing rows are not unique: %v", err)
+				}
+			}
+			return nil
+		},
+		Down: execSQL(
+			`DROP INDEX IF EXISTS idx_beers_brewery_name_unique`,
+			`DROP INDEX IF EXISTS idx_breweries_name_city_unique`,
+		),
+	},
+	{
+		Version: 7,
+		Name:    "create beer ingredient tables",
+		Up:      createIngredientTables,
+		Down: execSQL(
+			`DROP TABLE IF EXISTS beer_yeast`,
+			`DROP TABLE IF EXISTS beer_fermentables`,
+			`DROP TABLE IF EXISTS beer_hops`,
+		),
+	},
+	{
+		// Soft-deleted rows are hidden from the services rather than removed. The updated_at
+		// triggers are recreated for databases whose schema predates migration 1.
+		Version: 8,
+		Name:    "add soft deletes",
+		Up: func(ctx context.Context, tx *sqlx.Tx) error {
+			for _, table := range []string{"breweries", "beers"} {
+				if err := addColumn(ctx, tx, table, "deleted_at", "TIMESTAMP"); err != nil {
+					return err
+				}
+			}
+			return postgresOnly(execSQL(updatedAtTriggers...))(ctx, tx)
+		},
+		Down: execSQL(
+			`ALTER TABLE beers DROP COLUMN deleted_at`,
+			`ALTER TABLE breweries DROP COLUMN deleted_at`,
+		),
+	},
+	{
+		Version: 9,
+		Name:    "create venues",
+		Up:      createVenuesTable,
+		Down:    execSQL(`DROP TABLE IF EXISTS venues`),
+	},
+	{
+		// Name suggestions match LOWER(name) LIKE 'prefix%', which text_pattern_ops indexes serve
+		// whatever the database collation. SQLite catalogs are small enough to scan.
+		Version: 10,
+		Name:    "add name prefix indexes",
+		Up: postgresOnly(execSQL(
+			`CREATE INDEX IF NOT EXISTS idx_breweries_name_prefix ON breweries (LOWER(name) text_pattern_ops)`,
+			`CREATE INDEX IF NOT EXISTS idx_beers_name_prefix ON beers (LOWER(name) text_pattern_ops)`,
+		)),
+		Down: execSQL(
+			`DROP INDEX IF EXISTS idx_beers_name_prefix`,
+			`DROP INDEX IF EXISTS idx_breweries_name_prefix`,
+		),
+	},
+	{
+		// When each source last updated the data, cited by the tools. Open Brewery DB starts from
+		// its last successful sync.
+		Version: 11,
+		Name:    "create data sources",
+		Up: execSQL(
+			`CREATE TABLE IF NOT EXISTS data_sources (
+				source VARCHAR(50) PRIMARY KEY,
+				updated_at TIMESTAMP NOT NULL,
+				records INTEGER NOT NULL DEFAULT 0,
+				version VARCHAR(255) NOT NULL DEFAULT ''
+			)`,
+			`INSERT INTO data_sources (source, updated_at, records, version)
+				SELECT source, last_success_at, records_synced, '' FROM sync_state
+				WHERE last_success_at IS NOT NULL`,
+		),
+		Down: execSQL(`DROP TABLE IF EXISTS data_sources`),
+	},
+	{
+		Version: 12,
+		Name:    "create usage events",
+		Up:      createUsageEventsTable,
+		Down:    execSQL(`DROP TABLE IF EXISTS usage_events`),
+	},
+	{
+		// The flavour tags seeding and imports derive from beer descriptions, and on PostgreSQL the
+		// indexes that tag filters and full-text description searches use. SQLite stores the tags
+		// as their array literal.
+		Version: 13,
+		Name:    "add beer tags",
+		Up:      addBeerTags,
+		Down: execSQL(
+			`DROP INDEX IF EXISTS idx_beers_description_fts`,
+			`DROP INDEX IF EXISTS idx_beers_tags`,
+			`ALTER TABLE beers DROP COLUMN tags`,
+		),
+	},
+	{
+		// The description, founding year, and contact details get_brewery and the directory show.
+		Version: 14,
+		Name:    "add brewery profiles",
+		Up:      addBreweryProfiles,
+		Down: execSQL(
+			`ALTER TABLE breweries DROP COLUMN email`,
+			`ALTER TABLE breweries DROP COLUMN twitter`,
+			`ALTER TABLE breweries DROP COLUMN facebook`,
+			`ALTER TABLE breweries DROP COLUMN instagram`,
+			`ALTER TABLE breweries DROP COLUMN founded_year`,
+			`ALTER TABLE breweries DROP COLUMN description`,
+		),
+	},
+}
+
+// ingredientTables are the child tables of beers holding their hop bills, grain bills, and yeast.
+var ingredientTables = []string{"beer_hops", "beer_fermentables", "beer_yeast"}
+
+// Migrations returns the schema migrations in the order they apply.
+func Migrations() []Migration {
+	return slices.Clone(migrations)
+}
+
+// MigrateDatabase brings the database schema up to date, as the server does on startup.
+func MigrateDatabase(db *sqlx.DB) error {
+	_, err := Migrate(context.Background(), db)
+	return err
+}
+
+// Migrate applies, in order, each migration not yet recorded in schema_migrations, returning those
+// it applied. A database holding the catalog tables but no record of migrations predates them; it
+// is stamped with the migrations up to baselineVersion rather than running them again.
+func Migrate(ctx context.Context, db *sqlx.DB) ([]Migration, error) {
+	var applied []Migration
+	err := withMigrationLock(ctx, db, func(conn *sqlx.Conn) error {
+		done, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if len(done) == 0 {
+			if done, err = stampBaseline(ctx, conn, db.DriverName()); err != nil {
+				return err
+			}
+		}
+		for _, migration := range migrations {
+			if done[migration.Version] {
+				continue
+			}
+			if err = runMigration(ctx, conn, migration, migration.Up,
+				`INSERT INTO schema_migrations (version, name) VALUES (?, ?)`,
+				migration.Version, migration.Name); err != nil {
+				return fmt.Errorf("migration %d (%s) failed: %w", migration.Version, migration.Name, err)
+			}
+			logrus.Infof("Applied database migration %d: %s", migration.Version, migration.Name)
+			applied = append(applied, migration)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// MigrateDown reverts the steps most recently applied migrations, newest first, returning those it
+// reverted. It stops at the first failure, keeping the migrations reverted before it.
+func MigrateDown(ctx context.Context, db *sqlx.DB, steps int) ([]Migration, error) {
+	if steps < 1 {
+		return nil, ErrInvalidMigrationSteps
+	}
+	var reverted []Migration
+	err := withMigrationLock(ctx, db, func(conn *sqlx.Conn) error {
+		done, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for i := len(migrations) - 1; i >= 0 && len(reverted) < steps; i-- {
+			migration := migrations[i]
+			if !done[migration.Version] {
+				continue
+			}
+			if err = runMigration(ctx, conn, migration, migration.Down,
+				`DELETE FROM schema_migrations WHERE version = ?`, migration.Version); err != nil {
+				return fmt.Errorf("reverting migration %d (%s) failed: %w", migration.Version, migration.Name, err)
+			}
+			logrus.Infof("Reverted database migration %d: %s", migration.Version, migration.Name)
+			reverted = append(reverted, migration)
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// AppliedMigrations returns the rows of schema_migrations, oldest version first.
+func AppliedMigrations(ctx context.Context, db *sqlx.DB) ([]AppliedMigration, error) {
+	applied := []AppliedMigration{}
+	err := db.SelectContext(ctx, &applied,
+		`SELECT version, name, baseline, applied_at FROM schema_migrations ORDER BY version`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list applied migrations: %w", err)
+	}
+	return applied, nil
+}
+
+// withMigrationLock runs fn on a single connection, holding the migration lock on PostgreSQL, after
+// creating schema_migrations when it does not exist yet.
+func withMigrationLock(ctx context.Context, db *sqlx.DB, fn func(conn *sqlx.Conn) error) error {
+	conn, err := db.Connx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to connect for migrations: %w", err)
+	}
+	defer func() {
+		if closeErr := conn.Close(); closeErr != nil {
+			logrus.Warnf("Failed to close the migration connection: %v", closeErr)
+		}
+	}()
+
+	if db.DriverName() != sqliteDriver {
+		if _, err = conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, migrationLockID); err != nil {
+			return fmt.Errorf("failed to take the migration lock: %w", err)
+		}
+		defer func() {
+			// Released even when ctx is done, since the connection goes back to the pool
+			if _, unlockErr := conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`,
+				migrationLockID); unlockErr != nil {
+				logrus.Warnf("Failed to release the migration lock: %v", unlockErr)
+			}
+		}()
+	}
+
+	_, err = conn.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INTEGER PRIMARY KEY,
+		name VARCHAR(255) NOT NULL,
+		baseline BOOLEAN NOT NULL DEFAULT FALSE,
+		applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+	)`)
+	if err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+	return fn(conn)
+}
+
+// appliedVersions returns the versions recorded in schema_migrations.
+func appliedVersions(ctx context.Context, conn *sqlx.Conn) (map[int]bool, error) {
+	var versions []int
+	if err := conn.SelectContext(ctx, &versions, `SELECT version FROM schema_migrations`); err != nil {
+		return nil, fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
+	done := map[int]bool{}
+	for _, version := range versions {
+		done[version] = true
+	}
+	return done, nil
+}
+
+// stampBaseline records the migrations up to baselineVersion as applied when the catalog tables
+// already exist, returning the versions it recorded.
+func stampBaseline(ctx context.Context, conn *sqlx.Conn, driverName string) (map[int]bool, error) {
+	done := map[int]bool{}
+	exists, err := tableExists(ctx, conn, driverName, "breweries")
+	if err != nil || !exists {
+		return done, err
+	}
+
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, migration := range migrations {
+		if migration.Version > baselineVersion {
+			break
+		}
+		if _, err = tx.ExecContext(ctx,
+			tx.Rebind(`INSERT INTO schema_migrations (version, name, baseline) VALUES (?, ?, TRUE)`),
+			migration.Version, migration.Name); err != nil {
+			_ = tx.Rollback()
+			return nil, fmt.Errorf("failed to stamp the baseline migrations: %w", err)
+		}
+		done[migration.Version] = true
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	logrus.Infof("Existing database stamped at baseline migration %d", baselineVersion)
+	return done, nil
+}
+
+// runMigration runs change and the schema_migrations statement record in one transaction.
+func runMigration(
+	ctx context.Context,
+	conn *sqlx.Conn,
+	migration Migration,
+	change MigrationFunc,
+	record string,
+	args ...interface{},
+) error {
+	if change == nil {
+		return fmt.Errorf("migration %d cannot run in this direction", migration.Version)
+	}
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err = change(ctx, tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if _, err = tx.ExecContext(ctx, tx.Rebind(record), args...); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// execSQL returns a MigrationFunc running the queries in order.
+func execSQL(queries ...string) MigrationFunc {
+	return func(ctx context.Context, tx *sqlx.Tx) error {
+		for _, query := range queries {
+			if _, err := tx.ExecContext(ctx, query); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// postgresOnly returns a MigrationFunc running fn on PostgreSQL and doing nothing on SQLite.
+func postgresOnly(fn MigrationFunc) MigrationFunc {
+	return func(ctx context.Context, tx *sqlx.Tx) error {
+		if tx.DriverName() == sqliteDriver {
+			return nil
+		}
+		return fn(ctx, tx)
+	}
+}
+
+// tryExec runs query under a savepoint, so that its failure, which it returns, leaves the rest of
+// the transaction able to commit.
+func tryExec(ctx context.Context, tx *sqlx.Tx, query string) error {
+	if _, err := tx.ExecContext(ctx, `SAVEPOINT try_exec`); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, query); err != nil {
+		if _, rollbackErr := tx.ExecContext(ctx, `ROLLBACK TO SAVEPOINT try_exec`); rollbackErr != nil {
+			return errors.Join(err, rollbackErr)
+		}
+		return err
+	}
+	_, err := tx.ExecContext(ctx, `RELEASE SAVEPOINT try_exec`)
+	return err
+}
+
+// addColumn adds a column to a table unless it is already there.
+func addColumn(ctx context.Context, tx *sqlx.Tx, table, column, definition string) error {
+	query := `SELECT EXISTS (SELECT 1 FROM information_schema.columns
+		WHERE table_schema = current_schema() AND table_name = ? AND column_name = ?)`
+	if tx.DriverName() == sqliteDriver {
+		query = `SELECT EXISTS (SELECT 1 FROM pragma_table_info(?) WHERE name = ?)`
+	}
+	var exists bool
+	if err := tx.GetContext(ctx, &exists, tx.Rebind(query), table, column); err != nil {
+		return fmt.Errorf("failed to check for %s.%s: %w", table, column, err)
+	}
+	if exists {
+		return nil
+	}
+	_, err := tx.ExecContext(ctx, fmt.Sprintf(`ALTER TABLE %s ADD COLUMN %s %s`, table, column, definition))
+	return err
+}
+
+// tableExists reports whether the table is in the current schema.
+func tableExists(ctx context.Context, conn *sqlx.Conn, driverName, table string) (bool, error) {
+	query := `SELECT EXISTS (SELECT 1 FROM information_schema.tables
+		WHERE table_schema = current_schema() AND table_name = ?)`
+	if driverName == sqliteDriver {
+		query = `SELECT EXISTS (SELECT 1 FROM sqlite_master WHERE type = 'table' AND name = ?)`
+	}
+	var exists bool
+	if err := conn.GetContext(ctx, &exists, conn.Rebind(query), table); err != nil {
+		return false, fmt.Errorf("failed to check for the %s table: %w", table, err)
+	}
+	return exists, nil
+}
+
+// createCatalogTables creates the breweries and beers tables as first released, with their indexes
+// and, on PostgreSQL, the triggers keeping updated_at current.
+func createCatalogTables(ctx context.Context, tx *sqlx.Tx) error {
+	id := "SERIAL PRIMARY KEY"
+	if tx.DriverName() == sqliteDriver {
+		id = "INTEGER PRIMARY KEY AUTOINCREMENT"
+	}
+	err := execSQL(
+		`CREATE TABLE IF NOT EXISTS breweries (
+			id `+id+`,
+			name VARCHAR(255) NOT NULL,
+			brewery_type VARCHAR(50),
+			street VARCHAR(255),
+			city VARCHAR(255),
+			state VARCHAR(255),
+			postal_code VARCHAR(20),
+			country VARCHAR(255) DEFAULT 'United States',
+			phone VARCHAR(50),
+			website_url VARCHAR(255),
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE TABLE IF NOT EXISTS beers (
+			id `+id+`,
+			name VARCHAR(255) NOT NULL,
+			brewery_id INTEGER REFERENCES breweries(id),
+			style VARCHAR(255),
+			abv DECIMAL(4,2),
+			ibu INTEGER,
+			srm DECIMAL(4,1),
+			description TEXT,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_breweries_location ON breweries(city, state, country)`,
+		`CREATE INDEX IF NOT EXISTS idx_beers_brewery ON beers(brewery_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_beers_style ON beers(style)`,
+	)(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	err = postgresOnly(execSQL(
+		`CREATE INDEX IF NOT EXISTS idx_breweries_name ON breweries USING gin(to_tsvector('english', name))`,
+		`CREATE INDEX IF NOT EXISTS idx_beers_name ON beers USING gin(to_tsvector('english', name))`,
+	))(ctx, tx)
+	if err != nil {
+		return err
+	}
+	return postgresOnly(execSQL(updatedAtTriggers...))(ctx, tx)
+}
+
+// updatedAtTriggers keep updated_at current on PostgreSQL whenever a brewery or beer row changes.
+// SQLite has no such triggers; the services set updated_at in their UPDATE statements.
+var updatedAtTriggers = []string{
+	`CREATE OR REPLACE FUNCTION update_updated_at_column()
+	RETURNS TRIGGER AS $$
+	BEGIN
+		NEW.updated_at = CURRENT_TIMESTAMP;
+		RETURN NEW;
+	END;
+	$$ language 'plpgsql'`,
+	`DROP TRIGGER IF EXISTS update_breweries_updated_at ON breweries`,
+	`CREATE TRIGGER update_breweries_updated_at
+		BEFORE UPDATE ON breweries
+		FOR EACH ROW EXECUTE FUNCTION update_updated_at_column()`,
+	`DROP TRIGGER IF EXISTS update_beers_updated_at ON beers`,
+	`CREATE TRIGGER update_beers_updated_at
+		BEFORE UPDATE ON beers
+		FOR EACH ROW EXECUTE FUNCTION update_updated_at_column()`,
+}
+
+// createIngredientTables creates the ingredient tables of beers. Each row is one ingredient of a
+// beer: its amount in unit, and its timing, such as "60 min" for a hop or "mash" for a malt. Rows
+// are deleted with their beer.
+func createIngredientTables(ctx context.Context, tx *sqlx.Tx) error {
+	id := "SERIAL PRIMARY KEY"
+	if tx.DriverName() == sqliteDriver {
+		id = "INTEGER PRIMARY KEY AUTOINCREMENT"
+	}
+	for _, table := range ingredientTables {
+		err := execSQL(
+			`CREATE TABLE IF NOT EXISTS `+table+` (
+				id `+id+`,
+				beer_id INTEGER NOT NULL REFERENCES beers(id) ON DELETE CASCADE,
+				name VARCHAR(255) NOT NULL,
+				amount DECIMAL(10,3),
+				unit VARCHAR(20),
+				timing VARCHAR(100)
+			)`,
+			`CREATE INDEX IF NOT EXISTS idx_`+table+`_beer_id ON `+table+`(beer_id)`,
+		)(ctx, tx)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createVenuesTable creates the venues table: the taprooms and other places a brewery pours its
+// beer, each with its own address, coordinates, and opening hours as a JSON object of weekdays.
+// Rows are deleted with their brewery.
+func createVenuesTable(ctx context.Context, tx *sqlx.Tx) error {
+	id := "SERIAL PRIMARY KEY"
+	if tx.DriverName() == sqliteDriver {
+		id = "INTEGER PRIMARY KEY AUTOINCREMENT"
+	}
+	return execSQL(
+		`CREATE TABLE IF NOT EXISTS venues (
+			id `+id+`,
+			brewery_id INTEGER NOT NULL REFERENCES breweries(id) ON DELETE CASCADE,
+			name VARCHAR(255) NOT NULL,
+			street VARCHAR(255),
+			city VARCHAR(255),
+			state VARCHAR(255),
+			postal_code VARCHAR(20),
+			country VARCHAR(255),
+			latitude DOUBLE PRECISION,
+			longitude DOUBLE PRECISION,
+			hours TEXT,
+			is_primary BOOLEAN NOT NULL DEFAULT FALSE
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_venues_brewery_id ON venues(brewery_id)`,
+	)(ctx, tx)
+}
+
+// addBeerTags adds the tags column of beers, a text[] on PostgreSQL with its indexes, and text on
+// SQLite.
+func addBeerTags(ctx context.Context, tx *sqlx.Tx) error {
+	if tx.DriverName() == sqliteDriver {
+		return addColumn(ctx, tx, "beers", "tags", "TEXT")
+	}
+	if err := addColumn(ctx, tx, "beers", "tags", "TEXT[]"); err != nil {
+		return err
+	}
+	return execSQL(
+		`CREATE INDEX IF NOT EXISTS idx_beers_tags ON beers USING GIN (tags)`,
+		`CREATE INDEX IF NOT EXISTS idx_beers_description_fts
+			ON beers USING GIN (to_tsvector('english', COALESCE(description, '')))`,
+	)(ctx, tx)
+}
+
+// breweryProfileColumns are the columns addBreweryProfiles adds to breweries, in order.
+var breweryProfileColumns = [][2]string{
+	{"description", "TEXT"},
+	{"founded_year", "INTEGER"},
+	{"instagram", "VARCHAR(255)"},
+	{"facebook", "VARCHAR(255)"},
+	{"twitter", "VARCHAR(255)"},
+	{"email", "VARCHAR(255)"},
+}
+
+// addBreweryProfiles adds the brewery profile columns. Like the address columns they are nullable,
+// and read back as empty strings, or a zero founding year, when unset.
+func addBreweryProfiles(ctx context.Context, tx *sqlx.Tx) error {
+	for _, column := range breweryProfileColumns {
+		if err := addColumn(ctx, tx, "breweries", column[0], column[1]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createUsageEventsTable creates the usage_events table: one row per tool call or resource read,
+// with the UTC day it fell on, which usage summaries group by on PostgreSQL and SQLite alike.
+func createUsageEventsTable(ctx context.Context, tx *sqlx.Tx) error {
+	id := "BIGSERIAL PRIMARY KEY"
+	if tx.DriverName() == sqliteDriver {
+		id = "INTEGER PRIMARY KEY AUTOINCREMENT"
+	}
+	return execSQL(
+		`CREATE TABLE IF NOT EXISTS usage_events (
+			id `+id+`,
+			occurred_at TIMESTAMP NOT NULL,
+			day VARCHAR(10) NOT NULL,
+			kind VARCHAR(20) NOT NULL,
+			name TEXT NOT NULL,
+			duration_ms INTEGER NOT NULL,
+			error_code INTEGER NOT NULL DEFAULT 0,
+			client VARCHAR(255) NOT NULL DEFAULT ''
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_usage_events_day ON usage_events(day, kind, name)`,
+	)(ctx, tx)
+}
+
+// dropCatalogTables reverts createCatalogTables; the tables' indexes and triggers go with them.
+func dropCatalogTables(ctx context.Context, tx *sqlx.Tx) error {
+	if err := execSQL(`DROP TABLE IF EXISTS beers`, `DROP TABLE IF EXISTS breweries`)(ctx, tx); err != nil {
+		return err
+	}
+	return postgresOnly(execSQL(`DROP FUNCTION IF EXISTS update_updated_at_column()`))(ctx, tx)
+}
@@ -0,0 +1,177 @@
+package models_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/models"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// openMigrationDB opens an empty in-memory SQLite database. It is held to one connection, as each
+// connection to :memory: opens a database of its own.
+func openMigrationDB(t *testing.T) *sqlx.DB {
+	t.Helper()
+	db, err := sqlx.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+// appliedVersions returns the versions recorded in schema_migrations and which were baseline stamps.
+func appliedVersions(t *testing.T, db *sqlx.DB) ([]int, map[int]bool) {
+	t.Helper()
+	applied, err := models.AppliedMigrations(context.Background(), db)
+	require.NoError(t, err)
+	versions, baseline := []int{}, map[int]bool{}
+	for _, migration := range applied {
+		versions = append(versions, migration.Version)
+		baseline[migration.Version] = migration.Baseline
+		assert.False(t, migration.AppliedAt.IsZero(), "migration %d has no applied_at", migration.Version)
+	}
+	return versions, baseline
+}
+
+func versionsOf(migrations []models.Migration) []int {
+	versions := []int{}
+	for _, migration := range migrations {
+		versions = append(versions, migration.Version)
+	}
+	return versions
+}
+
+func hasSchemaObject(t *testing.T, db *sqlx.DB, kind, name string) bool {
+	t.Helper()
+	var count int
+	require.NoError(t, db.Get(&count, `SELECT COUNT(*) FROM sqlite_master WHERE type = ? AND name = ?`, kind, name))
+	return count > 0
+}
+
+func TestMigrations_Ordered(t *testing.T) {
+	for i, migration := range models.Migrations() {
+		assert.Equal(t, i+1, migration.Version, "migrations must be numbered 1, 2, 3, ... in order")
+		assert.NotEmpty(t, migration.Name, "migration %d has no name", migration.Version)
+		assert.NotNil(t, migration.Up, "migration %d cannot be applied", migration.Version)
+		assert.NotNil(t, migration.Down, "migration %d cannot be reverted", migration.Version)
+	}
+}
+
+func TestMigrate_FreshDatabase(t *testing.T) {
+	db := openMigrationDB(t)
+	ctx := context.Background()
+	all := versionsOf(models.Migrations())
+
+	applied, err := models.Migrate(ctx, db)
+	require.NoError(t, err)
+	assert.Equal(t, all, versionsOf(applied))
+
+	versions, baseline := appliedVersions(t, db)
+	assert.Equal(t, all, versions)
+	for _, version := range versions {
+		assert.False(t, baseline[version], "migration %d was run, not stamped", version)
+	}
+
+	// The migrated schema takes every column the services write
+	db.MustExec(`INSERT INTO breweries (name, city, latitude, longitude, external_id)
+		VALUES ('Devil''s Peak', 'Cape Town', -33.9, 18.4, 'obdb-1')`)
+	db.MustExec(`INSERT INTO beers (name, brewery_id, style, abv, ibu, srm, tags)
+		VALUES ('King''s Blockhouse', 1, 'IPA', 6, 60, 8, '{citrus,pine}')`)
+	db.MustExec(`INSERT INTO sync_state (source, last_attempt_at) VALUES ('openbrewerydb', CURRENT_TIMESTAMP)`)
+	db.MustExec(`INSERT INTO beer_hops (beer_id, name, amount, unit, timing) VALUES (1, 'Cascade', 30, 'g', '60 min')`)
+	db.MustExec(`UPDATE breweries SET deleted_at = CURRENT_TIMESTAMP WHERE id = 1`)
+	_, err = db.Exec(`INSERT INTO breweries (name, city) VALUES ('devil''s peak', 'CAPE TOWN')`)
+	assert.Error(t, err, "expected the duplicate check to reject the same brewery in the same city")
+
+	// Starting again applies nothing
+	applied, err = models.Migrate(ctx, db)
+	require.NoError(t, err)
+	assert.Empty(t, applied)
+	versions, _ = appliedVersions(t, db)
+	assert.Equal(t, all, versions)
+}
+
+func TestMigrate_StampsExistingDatabase(t *testing.T) {
+	db := openMigrationDB(t)
+	ctx := context.Background()
+
+	// The schema as created before migrations were tracked, with rows that the duplicate check
+	// cannot index
+	db.MustExec(`CREATE TABLE breweries (
+		id INTEGER PRIMARY KEY AUTOINCREMENT, name VARCHAR(255) NOT NULL, brewery_type VARCHAR(50),
+		street VARCHAR(255), city VARCHAR(255), state VARCHAR(255), postal_code VARCHAR(20),
+		country VARCHAR(255), phone VARCHAR(50), website_url VARCHAR(255), latitude DOUBLE PRECISION,
+		longitude DOUBLE PRECISION, external_id VARCHAR(255),
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP, updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP)`)
+	db.MustExec(`CREATE TABLE beers (
+		id INTEGER PRIMARY KEY AUTOINCREMENT, name VARCHAR(255) NOT NULL, brewery_id INTEGER REFERENCES breweries(id),
+		style VARCHAR(255), abv DECIMAL(4,2), ibu INTEGER, srm DECIMAL(4,1), description TEXT,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP, updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP)`)
+	db.MustExec(`INSERT INTO breweries (name, city) VALUES ('Jack Black', 'Cape Town'), ('Jack Black', 'Cape Town')`)
+
+	applied, err := models.Migrate(ctx, db)
+	require.NoError(t, err)
+	assert.Equal(t, []int{2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14}, versionsOf(applied),
+		"expected the initial schema to be stamped, not run")
+
+	versions, baseline := appliedVersions(t, db)
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14}, versions)
+	assert.True(t, baseline[1])
+	assert.False(t, baseline[2])
+
+	var breweries int
+	require.NoError(t, db.Get(&breweries, `SELECT COUNT(*) FROM breweries`))
+	assert.Equal(t, 2, breweries)
+	assert.True(t, hasSchemaObject(t, db, "table", "sync_state"))
+	assert.True(t, hasSchemaObject(t, db, "table", "data_sources"))
+	assert.True(t, hasSchemaObject(t, db, "table", "usage_events"))
+	assert.True(t, hasSchemaObject(t, db, "index", "idx_breweries_external_id"))
+	assert.False(t, hasSchemaObject(t, db, "index", "idx_breweries_name_city_unique"),
+		"expected the duplicate breweries to leave their check out")
+	assert.True(t, hasSchemaObject(t, db, "index", "idx_beers_brewery_name_unique"))
+}
+
+func TestMigrateDown(t *testing.T) {
+	db := openMigrationDB(t)
+	ctx := context.Background()
+	_, err := models.Migrate(ctx, db)
+	require.NoError(t, err)
+
+	reverted, err := models.MigrateDown(ctx, db, 11)
+	require.NoError(t, err)
+	assert.Equal(t, []int{14, 13, 12, 11, 10, 9, 8, 7, 6, 5, 4}, versionsOf(reverted))
+	versions, _ := appliedVersions(t, db)
+	assert.Equal(t, []int{1, 2, 3}, versions)
+	assert.False(t, hasSchemaObject(t, db, "table", "sync_state"))
+	assert.False(t, hasSchemaObject(t, db, "table", "beer_hops"))
+	assert.False(t, hasSchemaObject(t, db, "table", "venues"))
+	assert.False(t, hasSchemaObject(t, db, "table", "data_sources"))
+	assert.False(t, hasSchemaObject(t, db, "table", "usage_events"))
+	_, err = db.Exec(`SELECT deleted_at FROM breweries`)
+	assert.Error(t, err, "expected deleted_at to be dropped")
+	_, err = db.Exec(`SELECT tags FROM beers`)
+	assert.Error(t, err, "expected tags to be dropped")
+	_, err = db.Exec(`SELECT founded_year FROM breweries`)
+	assert.Error(t, err, "expected the brewery profile columns to be dropped")
+	assert.False(t, hasSchemaObject(t, db, "index", "idx_breweries_name_city_unique"))
+
+	// Asking for more steps than were applied reverts everything
+	reverted, err = models.MigrateDown(ctx, db, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []int{3, 2, 1}, versionsOf(reverted))
+	versions, _ = appliedVersions(t, db)
+	assert.Empty(t, versions)
+	assert.False(t, hasSchemaObject(t, db, "table", "breweries"))
+	assert.False(t, hasSchemaObject(t, db, "table", "beers"))
+
+	// The whole chain applies again on the emptied database
+	applied, err := models.Migrate(ctx, db)
+	require.NoError(t, err)
+	assert.Equal(t, versionsOf(models.Migrations()), versionsOf(applied))
+
+	_, err = models.MigrateDown(ctx, db, 0)
+	assert.ErrorIs(t, err, models.ErrInvalidMigrationSteps)
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
 )
 
 // ErrStringArrayEmpty represents an error when StringArray is empty.
@@ -67,75 +68,34 @@ type Brewery struct {
 	Country     string    `json:"country"      db:"country"`
 	Phone       string    `json:"phone"        db:"phone"`
 	WebsiteURL  string    `json:"website_url"  db:"website_url"`
+	Latitude    *float64  `json:"latitude"     db:"latitude"`
+	Longitude   *float64  `json:"longitude"    db:"longitude"`
 	CreatedAt   time.Time `json:"created_at"   db:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"   db:"updated_at"`
 }
 
-// MigrateDatabase creates the necessary database tables and indexes for the brewsource application.
-func MigrateDatabase(db *sqlx.DB) error {
-	queries := []string{
-		// Breweries table
-		`CREATE TABLE IF NOT EXISTS breweries (
-			id SERIAL PRIMARY KEY,
-			name VARCHAR(255) NOT NULL,
-			brewery_type VARCHAR(50),
-			street VARCHAR(255),
-			city VARCHAR(255),
-			state VARCHAR(255),
-			postal_code VARCHAR(20),
-			country VARCHAR(255) DEFAULT 'United States',
-			phone VARCHAR(50),
-			website_url VARCHAR(255),
-			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-		)`,
-
-		// Beers table
-		`CREATE TABLE IF NOT EXISTS beers (
-			id SERIAL PRIMARY KEY,
-			name VARCHAR(255) NOT NULL,
-			brewery_id INTEGER REFERENCES breweries(id),
-			style VARCHAR(255),
-			abv DECIMAL(4,2),
-			ibu INTEGER,
-			srm DECIMAL(4,1),
-			description TEXT,
-			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-		)`,
-
-		`CREATE INDEX IF NOT EXISTS idx_breweries_name ON breweries USING gin(to_tsvector('english', name))`,
-		`CREATE INDEX IF NOT EXISTS idx_breweries_location ON breweries(city, state, country)`,
-
-		`CREATE INDEX IF NOT EXISTS idx_beers_name ON beers USING gin(to_tsvector('english', name))`,
-		`CREATE INDEX IF NOT EXISTS idx_beers_brewery ON beers(brewery_id)`,
-		`CREATE INDEX IF NOT EXISTS idx_beers_style ON beers(style)`,
-
-		// Update triggers for updated_at timestamps
-		`CREATE OR REPLACE FUNCTION update_updated_at_column()
-		RETURNS TRIGGER AS $$
-		BEGIN
-			NEW.updated_at = CURRENT_TIMESTAMP;
-			RETURN NEW;
-		END;
-		$$ language 'plpgsql'`,
-
-		`DROP TRIGGER IF EXISTS update_breweries_updated_at ON breweries`,
-		`CREATE TRIGGER update_breweries_updated_at
-			BEFORE UPDATE ON breweries
-			FOR EACH ROW EXECUTE FUNCTION update_updated_at_column()`,
-
-		`DROP TRIGGER IF EXISTS update_beers_updated_at ON beers`,
-		`CREATE TRIGGER update_beers_updated_at
-			BEFORE UPDATE ON beers
-			FOR EACH ROW EXECUTE FUNCTION update_updated_at_column()`,
+// HasTrigramSearch reports whether the pg_trgm extension is installed, so that beer and brewery
+// searches can rank results by similarity. SQLite never has it.
+func HasTrigramSearch(db *sqlx.DB) bool {
+	if IsSQLite(db) {
+		return false
 	}
-
-	for _, query := range queries {
-		if _, err := db.Exec(query); err != nil {
-			return err
-		}
+	var installed bool
+	if err := db.Get(&installed, `SELECT EXISTS (SELECT 1 FROM pg_extension WHERE extname = 'pg_trgm')`); err != nil {
+		logrus.Warnf("Failed to check for the pg_trgm extension: %v", err)
+		return false
 	}
+	return installed
+}
 
-	return nil
+// HasDistanceSearch reports whether the database has the math functions that brewery searches by
+// distance use. PostgreSQL always does; SQLite only when built with its math functions.
+func HasDistanceSearch(db *sqlx.DB) bool {
+	var distance float64
+	err := db.Get(&distance, `SELECT ASIN(SQRT(POWER(SIN(RADIANS(1)), 2))) + COS(RADIANS(1))`)
+	if err != nil {
+		logrus.Infof("Searching breweries by distance is unavailable: %v", err)
+		return false
+	}
+	return true
 }
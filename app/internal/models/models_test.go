@@ -262,53 +262,32 @@ func TestBrewery_JSONMarshaling(t *testing.T) {
 	}
 }
 
-// Test MigrateDatabase function.
-func TestMigrateDatabase(t *testing.T) {
+func TestHasTrigramSearch(t *testing.T) {
 	tests := []struct {
 		name      string
 		setupMock func(sqlmock.Sqlmock)
-		expectErr bool
+		want      bool
 	}{
 		{
-			name: "successful migration",
+			name: "extension installed",
 			setupMock: func(mock sqlmock.Sqlmock) {
-				// Expect all migration queries to succeed
-				mock.ExpectExec("CREATE TABLE IF NOT EXISTS breweries").WillReturnResult(sqlmock.NewResult(0, 0))
-				mock.ExpectExec("CREATE TABLE IF NOT EXISTS beers").WillReturnResult(sqlmock.NewResult(0, 0))
-				mock.ExpectExec("CREATE INDEX IF NOT EXISTS idx_breweries_name").
-					WillReturnResult(sqlmock.NewResult(0, 0))
-				mock.ExpectExec("CREATE INDEX IF NOT EXISTS idx_breweries_location").
-					WillReturnResult(sqlmock.NewResult(0, 0))
-				mock.ExpectExec("CREATE INDEX IF NOT EXISTS idx_beers_name").WillReturnResult(sqlmock.NewResult(0, 0))
-				mock.ExpectExec("CREATE INDEX IF NOT EXISTS idx_beers_brewery").
-					WillReturnResult(sqlmock.NewResult(0, 0))
-				mock.ExpectExec("CREATE INDEX IF NOT EXISTS idx_beers_style").WillReturnResult(sqlmock.NewResult(0, 0))
-				mock.ExpectExec("CREATE OR REPLACE FUNCTION update_updated_at_column").
-					WillReturnResult(sqlmock.NewResult(0, 0))
-				mock.ExpectExec("DROP TRIGGER IF EXISTS update_breweries_updated_at").
-					WillReturnResult(sqlmock.NewResult(0, 0))
-				mock.ExpectExec("CREATE TRIGGER update_breweries_updated_at").WillReturnResult(sqlmock.NewResult(0, 0))
-				mock.ExpectExec("DROP TRIGGER IF EXISTS update_beers_updated_at").
-					WillReturnResult(sqlmock.NewResult(0, 0))
-				mock.ExpectExec("CREATE TRIGGER update_beers_updated_at").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery("SELECT EXISTS").WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
 			},
-			expectErr: false,
+			want: true,
 		},
 		{
-			name: "failed table creation",
+			name: "extension missing",
 			setupMock: func(mock sqlmock.Sqlmock) {
-				mock.ExpectExec("CREATE TABLE IF NOT EXISTS breweries").WillReturnError(sql.ErrConnDone)
+				mock.ExpectQuery("SELECT EXISTS").WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
 			},
-			expectErr: true,
+			want: false,
 		},
 		{
-			name: "failed index creation",
+			name: "query fails",
 			setupMock: func(mock sqlmock.Sqlmock) {
-				mock.ExpectExec("CREATE TABLE IF NOT EXISTS breweries").WillReturnResult(sqlmock.NewResult(0, 0))
-				mock.ExpectExec("CREATE TABLE IF NOT EXISTS beers").WillReturnResult(sqlmock.NewResult(0, 0))
-				mock.ExpectExec("CREATE INDEX IF NOT EXISTS idx_breweries_name").WillReturnError(sql.ErrConnDone)
+				mock.ExpectQuery("SELECT EXISTS").WillReturnError(sql.ErrConnDone)
 			},
-			expectErr: true,
+			want: false,
 		},
 	}
 
@@ -319,16 +298,11 @@ func TestMigrateDatabase(t *testing.T) {
 				t.Fatalf("Failed to create mock: %v", err)
 			}
 			defer db.Close()
-
-			sqlxDB := sqlx.NewDb(db, "postgres")
 			tt.setupMock(mock)
 
-			err = models.MigrateDatabase(sqlxDB)
-
-			if (err != nil) != tt.expectErr {
-				t.Errorf("MigrateDatabase() error = %v, expectErr = %v", err, tt.expectErr)
+			if got := models.HasTrigramSearch(sqlx.NewDb(db, "postgres")); got != tt.want {
+				t.Errorf("HasTrigramSearch() = %v, want %v", got, tt.want)
 			}
-
 			if mockErr := mock.ExpectationsWereMet(); mockErr != nil {
 				t.Errorf("Unfulfilled expectations: %s", mockErr)
 			}
@@ -3,104 +3,304 @@ package models
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/CharlRitter/brewsource-mcp/app/internal/services"
 	"github.com/jmoiron/sqlx"
 	"github.com/sirupsen/logrus"
 )
 
-// SeedDatabase populates the database with initial data for Phase 1.
-// It seeds both breweries and beers tables with sample data if they are empty.
-// Returns an error if any seeding step fails.
-func SeedDatabase(db *sqlx.DB) error {
-	ctx := context.Background()
+// SeedResult counts the seed rows a seeding step inserted and those it skipped. Inserted plus
+// Skipped is the number of seed rows.
+type SeedResult struct {
+	Inserted int `json:"inserted"`
+	Skipped  int `json:"skipped"` // already present, or a beer whose brewery is missing
+}
+
+// String returns the counts, e.g. "3 inserted, 23 skipped".
+func (r SeedResult) String() string {
+	return fmt.Sprintf("%d inserted, %d skipped", r.Inserted, r.Skipped)
+}
 
+// SeedDatabase populates the database with initial data for Phase 1.
+// It loads the seed data with services.LoadSeedData and inserts the sample breweries and beers the
+// database does not already hold, so it can run on every startup and repairs a partially seeded
+// database. The seeding runs in one transaction, so a failure leaves the database as it was.
+// Returns an error, naming each invalid row, if the seed data is invalid or any seeding step fails,
+// including when ctx is done first.
+func SeedDatabase(ctx context.Context, db *sqlx.DB) error {
 	logrus.Info("Starting database seeding...")
 
-	// Seed breweries
-	if err := seedBreweries(ctx, db); err != nil {
-		return fmt.Errorf("failed to seed breweries: %w", err)
+	seed, err := services.LoadSeedData()
+	if err != nil {
+		return fmt.Errorf("failed to load seed data: %w", err)
 	}
-
-	// Seed beers
-	if err := seedBeers(ctx, db); err != nil {
-		return fmt.Errorf("failed to seed beers: %w", err)
+	breweries, beers, err := Seed(ctx, db, seed)
+	if err != nil {
+		return err
 	}
+	logrus.Infof("Seeded breweries: %s", breweries)
+	logrus.Infof("Seeded beers: %s", beers)
 
 	logrus.Info("Database seeding completed successfully")
 	return nil
 }
 
-// seedBreweries inserts a set of sample breweries into the database if none exist.
-// It checks for existing data to ensure idempotency.
-// Returns an error if the operation fails.
-func seedBreweries(ctx context.Context, db *sqlx.DB) error {
-	// Check if breweries already exist
-	var count int
-	err := db.GetContext(ctx, &count, "SELECT COUNT(*) FROM breweries")
-	if err != nil {
+// Seed runs SeedFrom in one transaction, committing only when every step succeeds, so that a failure
+// part way through inserts nothing rather than leaving some breweries without their beers.
+func Seed(ctx context.Context, db *sqlx.DB, seed *services.SeedData) (breweries, beers SeedResult, err error) {
+	err = inSeedTx(ctx, db, "seed", func(tx *sqlx.Tx) (err error) {
+		breweries, beers, err = SeedFrom(ctx, tx, seed)
 		return err
+	})
+	return breweries, beers, err
+}
+
+// SeedFrom inserts the breweries and then the beers of seed that the database does not already
+// hold, reporting the counts of each. Beers resolve their brewery by name among all breweries in
+// the database, and every beer is then tagged with seed.Tags, when set. The seed venues of each
+// brewery are inserted when it has no venues yet, and the profile fields a brewery has unset are
+// filled from its seed record. When it inserts any, the seed is recorded as the
+// services.SeedSource data source. It writes through db as it goes; Seed runs it in a transaction.
+func SeedFrom(
+	ctx context.Context,
+	db sqlx.ExtContext,
+	seed *services.SeedData,
+) (breweries, beers SeedResult, err error) {
+	if breweries, err = seedBreweries(ctx, db, seed.Breweries); err != nil {
+		return breweries, beers, fmt.Errorf("failed to seed breweries: %w", err)
 	}
-	if count > 0 {
-		logrus.Info("Breweries already exist, skipping seeding")
-		return nil
+	if err = seedVenues(ctx, db, seed.Venues); err != nil {
+		return breweries, beers, fmt.Errorf("failed to seed venues: %w", err)
 	}
-	logrus.Info("Seeding breweries...")
-	breweries := services.GetSeedBreweries()
-	if insertErr := insertBreweries(ctx, db, breweries); insertErr != nil {
-		return insertErr
+	if beers, err = seedBeers(ctx, db, seed.Beers); err != nil {
+		return breweries, beers, fmt.Errorf("failed to seed beers: %w", err)
 	}
-	logrus.Infof("Seeded %d breweries", len(breweries))
-	return nil
+	if seed.Tags != nil {
+		if _, err = TagBeers(ctx, db, seed.Tags); err != nil {
+			return breweries, beers, err
+		}
+	}
+	if breweries.Inserted+beers.Inserted > 0 {
+		err = services.RecordDataSource(ctx, db, services.DataSource{
+			Source:    services.SeedSource,
+			UpdatedAt: time.Now(),
+			Records:   len(seed.Breweries) + len(seed.Beers),
+			Version:   seed.Source,
+		})
+	}
+	return breweries, beers, err
 }
 
-func insertBreweries(ctx context.Context, db *sqlx.DB, breweries []services.Brewery) error {
-	for _, brewery := range breweries {
-		query := `
-			INSERT INTO breweries (
-				name, brewery_type, street, city, state, postal_code, country, phone, website_url
-			) VALUES (
-				:name, :brewery_type, :street, :city, :state, :postal_code, :country, :phone, :website_url
-			)
-		`
-		if _, insertErr := db.NamedExecContext(ctx, query, brewery); insertErr != nil {
-			return fmt.Errorf("failed to insert brewery %s: %w", brewery.Name, insertErr)
+// Reseed deletes every brewery and beer with their ingredients, and on PostgreSQL the rows of other
+// tables referencing them, then seeds the database from seed, all in one transaction, so a failure
+// leaves the catalog as it was. It is meant for local development.
+func Reseed(ctx context.Context, db *sqlx.DB, seed *services.SeedData) (breweries, beers SeedResult, err error) {
+	err = inSeedTx(ctx, db, "reseed", func(tx *sqlx.Tx) (err error) {
+		truncate := []string{"TRUNCATE beers, breweries RESTART IDENTITY CASCADE"}
+		if db.DriverName() == sqliteDriver {
+			// SQLite leaves foreign keys unenforced by default, so the ingredients are deleted first
+			truncate = []string{
+				"DELETE FROM beer_hops", "DELETE FROM beer_fermentables", "DELETE FROM beer_yeast",
+				"DELETE FROM beers", "DELETE FROM venues", "DELETE FROM breweries",
+			}
 		}
+		for _, query := range truncate {
+			if _, err = tx.ExecContext(ctx, query); err != nil {
+				return fmt.Errorf("failed to clear the catalog: %w", err)
+			}
+		}
+		breweries, beers, err = SeedFrom(ctx, tx, seed)
+		return err
+	})
+	return breweries, beers, err
+}
+
+// inSeedTx runs fn in a transaction, committing it when fn succeeds and rolling it back otherwise.
+// action names the seeding in the errors.
+func inSeedTx(ctx context.Context, db *sqlx.DB, action string, fn func(tx *sqlx.Tx) error) (err error) {
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin %s: %w", action, err)
+	}
+	defer func() {
+		if err != nil {
+			if rollbackErr := tx.Rollback(); rollbackErr != nil && !errors.Is(rollbackErr, sql.ErrTxDone) {
+				logrus.Warnf("Failed to roll back %s: %v", action, rollbackErr)
+			}
+		}
+	}()
+
+	if err = fn(tx); err != nil {
+		return err
+	}
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit %s: %w", action, err)
 	}
 	return nil
 }
 
-// seedBeers inserts a set of sample beers into the database if none exist.
-// It looks up brewery IDs to associate beers with breweries.
+// seedBreweries inserts the breweries missing from the database.
+// A brewery matches an existing one by name and city, ignoring case, the key of
+// idx_breweries_name_city_unique. Existing breweries are skipped, but get the profile fields they
+// have unset from the seed, so that profiles added to the seed data reach breweries seeded before.
 // Returns an error if the operation fails.
-func seedBeers(ctx context.Context, db *sqlx.DB) error {
-	// Check if beers already exist
-	var count int
-	err := db.GetContext(ctx, &count, "SELECT COUNT(*) FROM beers")
+func seedBreweries(ctx context.Context, db sqlx.ExtContext, breweries []services.Brewery) (SeedResult, error) {
+	existing, err := existingKeys(ctx, db, "SELECT LOWER(name), LOWER(COALESCE(city, '')) FROM breweries")
 	if err != nil {
-		return err
+		return SeedResult{}, err
+	}
+	result := SeedResult{}
+	var rows [][]interface{}
+	for _, brewery := range breweries {
+		if existing[seedKey(brewery.Name, brewery.City)] {
+			if err = fillBreweryProfile(ctx, db, brewery); err != nil {
+				return result, err
+			}
+			result.Skipped++
+			continue
+		}
+		rows = append(rows, []interface{}{
+			brewery.Name, brewery.BreweryType, brewery.Street, brewery.City, brewery.State, brewery.PostalCode,
+			brewery.Country, brewery.Phone, brewery.WebsiteURL, brewery.Latitude, brewery.Longitude,
+			brewery.Description, nullIfZero(brewery.FoundedYear), brewery.Instagram, brewery.Facebook,
+			brewery.Twitter, brewery.Email,
+		})
+	}
+	if err = insertSeedRows(ctx, db, "breweries", seedBreweryColumns, rows, &result); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// seedBreweryColumns and seedBeerColumns are the columns seeding inserts.
+var (
+	seedBreweryColumns = []string{
+		"name", "brewery_type", "street", "city", "state", "postal_code", "country", "phone", "website_url",
+		"latitude", "longitude", "description", "founded_year", "instagram", "facebook", "twitter", "email",
+	}
+	seedBeerColumns = []string{"brewery_id", "name", "style", "abv", "ibu", "srm", "description"}
+)
+
+// insertSeedRows inserts rows into table in batches of batchInsertRows, counting into result the
+// rows inserted and, as skipped, those that would break a unique index.
+func insertSeedRows(
+	ctx context.Context,
+	db sqlx.ExecerContext,
+	table string,
+	columns []string,
+	rows [][]interface{},
+	result *SeedResult,
+) error {
+	inserted, err := execBatchInserts(ctx, db,
+		BatchInserts(table, columns, rows, batchInsertRows, "ON CONFLICT DO NOTHING"))
+	if err != nil {
+		return fmt.Errorf("failed to insert %s: %w", table, err)
+	}
+	result.Inserted += inserted
+	result.Skipped += len(rows) - inserted
+	return nil
+}
+
+// nullIfZero returns nil for 0, which the seed data uses for an unknown value, and the value
+// otherwise.
+func nullIfZero(value int) interface{} {
+	if value == 0 {
+		return nil
 	}
-	if count > 0 {
-		logrus.Info("Beers already exist, skipping seeding")
+	return value
+}
+
+// fillBreweryProfile sets the profile fields of the brewery matching the seed brewery by name and
+// city that are unset, leaving those already recorded as they are. A brewery with nothing to fill is
+// not updated, so that its updated_at stays put.
+func fillBreweryProfile(ctx context.Context, db sqlx.ExtContext, brewery services.Brewery) error {
+	query := `
+		UPDATE breweries SET
+			description = COALESCE(NULLIF(description, ''), NULLIF(:description, '')),
+			founded_year = COALESCE(founded_year, NULLIF(:founded_year, 0)),
+			instagram = COALESCE(NULLIF(instagram, ''), NULLIF(:instagram, '')),
+			facebook = COALESCE(NULLIF(facebook, ''), NULLIF(:facebook, '')),
+			twitter = COALESCE(NULLIF(twitter, ''), NULLIF(:twitter, '')),
+			email = COALESCE(NULLIF(email, ''), NULLIF(:email, ''))
+		WHERE LOWER(name) = LOWER(:name) AND LOWER(COALESCE(city, '')) = LOWER(:city) AND (
+			(COALESCE(description, '') = '' AND :description <> '') OR
+			(founded_year IS NULL AND :founded_year <> 0) OR
+			(COALESCE(instagram, '') = '' AND :instagram <> '') OR
+			(COALESCE(facebook, '') = '' AND :facebook <> '') OR
+			(COALESCE(twitter, '') = '' AND :twitter <> '') OR
+			(COALESCE(email, '') = '' AND :email <> '')
+		)
+	`
+	if _, err := sqlx.NamedExecContext(ctx, db, query, brewery); err != nil {
+		return fmt.Errorf("failed to fill the profile of brewery %s: %w", brewery.Name, err)
+	}
+	return nil
+}
+
+// seedVenues inserts the venues of the breweries that have none, resolving each venue's brewery by
+// name, so that venues added to the seed data reach breweries seeded before them without
+// duplicating venues recorded since.
+func seedVenues(ctx context.Context, db sqlx.ExtContext, venues []services.SeedVenue) error {
+	if len(venues) == 0 {
 		return nil
 	}
-	logrus.Info("Seeding beers...")
-	breweries, breweryErr := GetBreweryIDs(ctx, db)
-	if breweryErr != nil {
-		return breweryErr
+	breweries, err := GetBreweryIDs(ctx, db)
+	if err != nil {
+		return err
 	}
-	beers := services.GetSeedBeers()
-	if insertErr := insertBeers(ctx, db, breweries, beers); insertErr != nil {
-		return insertErr
+	var withVenues []int
+	if err = sqlx.SelectContext(ctx, db, &withVenues, `SELECT DISTINCT brewery_id FROM venues`); err != nil {
+		return fmt.Errorf("failed to list breweries with venues: %w", err)
+	}
+	for _, venue := range venues {
+		breweryID, exists := breweries[venue.BreweryName]
+		if !exists {
+			logrus.Warnf("Brewery not found: %s, skipping venue: %s", venue.BreweryName, venue.Name)
+			continue
+		}
+		if slices.Contains(withVenues, breweryID) {
+			continue
+		}
+		_, err = db.ExecContext(ctx, `
+			INSERT INTO venues (
+				brewery_id, name, street, city, state, postal_code, country, latitude, longitude, hours, is_primary
+			) VALUES (
+				$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+			)`,
+			breweryID, venue.Name, venue.Street, venue.City, venue.State, venue.PostalCode, venue.Country,
+			venue.Latitude, venue.Longitude, venue.Hours, venue.IsPrimary)
+		if err != nil {
+			return fmt.Errorf("failed to insert venue %s: %w", venue.Name, err)
+		}
 	}
-	logrus.Infof("Seeded %d beers", len(beers))
 	return nil
 }
 
+// seedBeers inserts the beers missing from the database.
+// It looks up brewery IDs to associate beers with breweries, and a beer matches an existing one
+// of its brewery by name, ignoring case, the key of idx_beers_brewery_name_unique.
+// Returns an error if the operation fails.
+func seedBeers(ctx context.Context, db sqlx.ExtContext, beers []services.SeedBeer) (SeedResult, error) {
+	breweries, err := GetBreweryIDs(ctx, db)
+	if err != nil {
+		return SeedResult{}, err
+	}
+	existing, err := existingKeys(ctx, db, "SELECT CAST(brewery_id AS TEXT), LOWER(name) FROM beers")
+	if err != nil {
+		return SeedResult{}, err
+	}
+	return insertBeers(ctx, db, breweries, existing, beers)
+}
+
 // GetBreweryIDs retrieves a mapping of brewery names to their IDs from the database.
 // It returns a map of brewery name to ID, or an error if the query fails.
-func GetBreweryIDs(ctx context.Context, db *sqlx.DB) (map[string]int, error) {
+func GetBreweryIDs(ctx context.Context, db sqlx.QueryerContext) (map[string]int, error) {
 	breweries := map[string]int{}
 	rows, err := db.QueryxContext(ctx, "SELECT id, name FROM breweries")
 	if err != nil {
@@ -127,33 +327,167 @@ func GetBreweryIDs(ctx context.Context, db *sqlx.DB) (map[string]int, error) {
 	return breweries, nil
 }
 
-// SeedBreweries exports the internal seedBreweries function for testing.
-func SeedBreweries(ctx context.Context, db *sqlx.DB) error {
-	return seedBreweries(ctx, db)
+// SeedBreweries exports the internal seedBreweries function, with the bundled seed breweries, for
+// testing. db may be a transaction.
+func SeedBreweries(ctx context.Context, db sqlx.ExtContext) (SeedResult, error) {
+	return seedBreweries(ctx, db, services.GetSeedBreweries())
 }
 
-// SeedBeers exports the internal seedBeers function for testing.
-func SeedBeers(ctx context.Context, db *sqlx.DB) error {
-	return seedBeers(ctx, db)
+// SeedBeers exports the internal seedBeers function, with the bundled seed beers, for testing. db
+// may be a transaction.
+func SeedBeers(ctx context.Context, db sqlx.ExtContext) (SeedResult, error) {
+	return seedBeers(ctx, db, services.GetSeedBeers())
 }
 
-func insertBeers(ctx context.Context, db *sqlx.DB, breweries map[string]int, beers []services.SeedBeer) error {
+// insertBeers inserts, in batches, the beers whose brewery is in breweries and that existing does not
+// hold, and then the ingredients of every seed beer with ingredients.
+func insertBeers(
+	ctx context.Context,
+	db sqlx.ExtContext,
+	breweries map[string]int,
+	existing map[string]bool,
+	beers []services.SeedBeer,
+) (SeedResult, error) {
+	result := SeedResult{}
+	var rows [][]interface{}
+	var withIngredients []services.SeedBeer
 	for _, beer := range beers {
 		breweryID, exists := breweries[beer.BreweryName]
 		if !exists {
 			logrus.Warnf("Brewery not found: %s, skipping beer: %s", beer.BreweryName, beer.Name)
+			result.Skipped++
 			continue
 		}
-		query := `
-			INSERT INTO beers (
-				brewery_id, name, style, abv, ibu, srm, description
-			) VALUES (
-				$1, $2, $3, $4, $5, $6, $7
-			)
-		`
-		if _, insertErr := db.ExecContext(ctx, query, breweryID, beer.Name, beer.Style, beer.ABV, beer.IBU, beer.SRM, beer.Description); insertErr != nil {
-			return fmt.Errorf("failed to insert beer %s: %w", beer.Name, insertErr)
+		if existing[seedKey(fmt.Sprint(breweryID), beer.Name)] {
+			result.Skipped++
+		} else {
+			rows = append(rows, []interface{}{
+				breweryID, beer.Name, beer.Style, beer.ABV, beer.IBU, beer.SRM, beer.Description,
+			})
+		}
+		if !beer.Ingredients.IsEmpty() {
+			withIngredients = append(withIngredients, beer)
+		}
+	}
+	if err := insertSeedRows(ctx, db, "beers", seedBeerColumns, rows, &result); err != nil {
+		return result, err
+	}
+	// The ingredients follow the inserts, which give the new beers their IDs
+	for _, beer := range withIngredients {
+		if err := seedIngredients(ctx, db, breweries[beer.BreweryName], beer); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// TagBeers sets the flavour tags of every beer to those tags extracts from its description, reporting
+// how many beers it retagged. Beers already tagged alike are left as they are, so that it can run
+// on every seed and retags beers only when their description or the vocabulary changes.
+func TagBeers(ctx context.Context, db sqlx.ExtContext, tags services.BeerTags) (int, error) {
+	rows, err := db.QueryxContext(ctx, `SELECT id, COALESCE(description, ''), tags FROM beers`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list beers to tag: %w", err)
+	}
+	changed := map[int]services.TagList{}
+	for rows.Next() {
+		var (
+			id          int
+			description string
+			current     services.TagList
+		)
+		if err = rows.Scan(&id, &description, &current); err != nil {
+			_ = rows.Close()
+			return 0, fmt.Errorf("failed to list beers to tag: %w", err)
+		}
+		extracted := services.TagList(tags.Extract(description))
+		if current == nil || !slices.Equal(current, extracted) {
+			changed[id] = extracted
+		}
+	}
+	if err = closeRows(rows); err != nil {
+		return 0, fmt.Errorf("failed to list beers to tag: %w", err)
+	}
+
+	// Updated once the rows are closed, as SQLite transactions have a single connection
+	for id, extracted := range changed {
+		if _, err = db.ExecContext(ctx, `UPDATE beers SET tags = $1 WHERE id = $2`, extracted, id); err != nil {
+			return 0, fmt.Errorf("failed to tag beer %d: %w", id, err)
+		}
+	}
+	return len(changed), nil
+}
+
+// seedIngredients inserts the ingredients of a seed beer, of each type the beer has none recorded,
+// so that ingredients added to the seed data reach beers seeded before them.
+func seedIngredients(ctx context.Context, db sqlx.ExtContext, breweryID int, beer services.SeedBeer) error {
+	var beerID int
+	err := sqlx.GetContext(ctx, db, &beerID,
+		`SELECT id FROM beers WHERE brewery_id = $1 AND LOWER(name) = LOWER($2) ORDER BY id LIMIT 1`,
+		breweryID, beer.Name)
+	if err != nil {
+		return fmt.Errorf("failed to find beer %s: %w", beer.Name, err)
+	}
+
+	for _, kind := range []struct {
+		table string
+		list  []services.BeerIngredient
+	}{
+		{"beer_hops", beer.Ingredients.Hops},
+		{"beer_fermentables", beer.Ingredients.Fermentables},
+		{"beer_yeast", beer.Ingredients.Yeast},
+	} {
+		if len(kind.list) == 0 {
+			continue
+		}
+		var count int
+		if err = sqlx.GetContext(ctx, db, &count,
+			`SELECT COUNT(*) FROM `+kind.table+` WHERE beer_id = $1`, beerID); err != nil {
+			return fmt.Errorf("failed to count %s of beer %s: %w", kind.table, beer.Name, err)
+		}
+		if count > 0 {
+			continue
+		}
+		for _, ingredient := range kind.list {
+			_, err = db.ExecContext(ctx,
+				`INSERT INTO `+kind.table+` (beer_id, name, amount, unit, timing) VALUES ($1, $2, $3, $4, $5)`,
+				beerID, ingredient.Name, ingredient.Amount, ingredient.Unit, ingredient.Timing)
+			if err != nil {
+				return fmt.Errorf("failed to insert %s %s of beer %s: %w", kind.table, ingredient.Name, beer.Name, err)
+			}
 		}
 	}
 	return nil
 }
+
+// existingKeys returns the natural keys, built by seedKey, of the rows query selects as two columns.
+// Seeding checks them first because the unique indexes are missing from a database that held
+// duplicates before they were added; ON CONFLICT DO NOTHING only covers the rest.
+func existingKeys(ctx context.Context, db sqlx.QueryerContext, query string) (map[string]bool, error) {
+	rows, err := db.QueryxContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			logrus.Warnf("Failed to close rows: %v", closeErr)
+		}
+	}()
+
+	keys := map[string]bool{}
+	for rows.Next() {
+		var first, second string
+		if scanErr := rows.Scan(&first, &second); scanErr != nil {
+			return nil, scanErr
+		}
+		keys[seedKey(first, second)] = true
+	}
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, rowsErr
+	}
+	return keys, nil
+}
+
+func seedKey(first, second string) string {
+	return strings.ToLower(first) + "\x00" + strings.ToLower(second)
+}
@@ -4,6 +4,7 @@ package models_test
 import (
 	"context"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/CharlRitter/brewsource-mcp/app/internal/models"
@@ -41,6 +42,14 @@ func createTables(t *testing.T, db *sqlx.DB) {
 			country TEXT,
 			phone TEXT,
 			website_url TEXT,
+			latitude REAL,
+			longitude REAL,
+			description TEXT,
+			founded_year INTEGER,
+			instagram TEXT,
+			facebook TEXT,
+			twitter TEXT,
+			email TEXT,
 			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
 		);
@@ -59,6 +68,7 @@ func createTables(t *testing.T, db *sqlx.DB) {
 			ibu INTEGER,
 			srm REAL,
 			description TEXT,
+			tags TEXT,
 			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 			FOREIGN KEY (brewery_id) REFERENCES breweries (id)
@@ -66,6 +76,54 @@ func createTables(t *testing.T, db *sqlx.DB) {
 	`
 	_, err = db.Exec(beersSchema)
 	require.NoError(t, err, "Failed to create beers table")
+
+	// Ingredient tables, as the migrations add them
+	for _, table := range []string{"beer_hops", "beer_fermentables", "beer_yeast"} {
+		_, err = db.Exec(`CREATE TABLE ` + table + ` (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			beer_id INTEGER NOT NULL REFERENCES beers (id) ON DELETE CASCADE,
+			name TEXT NOT NULL,
+			amount REAL,
+			unit TEXT,
+			timing TEXT
+		)`)
+		require.NoError(t, err, "Failed to create %s table", table)
+	}
+
+	// Venues table, as the migrations add it
+	_, err = db.Exec(`CREATE TABLE venues (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		brewery_id INTEGER NOT NULL REFERENCES breweries (id) ON DELETE CASCADE,
+		name TEXT NOT NULL,
+		street TEXT,
+		city TEXT,
+		state TEXT,
+		postal_code TEXT,
+		country TEXT,
+		latitude REAL,
+		longitude REAL,
+		hours TEXT,
+		is_primary BOOLEAN NOT NULL DEFAULT FALSE
+	)`)
+	require.NoError(t, err, "Failed to create venues table")
+
+	// Data sources table, as the migrations add it
+	_, err = db.Exec(`CREATE TABLE data_sources (
+		source TEXT PRIMARY KEY,
+		updated_at TIMESTAMP NOT NULL,
+		records INTEGER NOT NULL DEFAULT 0,
+		version TEXT NOT NULL DEFAULT ''
+	)`)
+	require.NoError(t, err, "Failed to create data_sources table")
+
+	// The natural keys seeding matches on, as the migrations add them
+	for _, index := range []string{
+		`CREATE UNIQUE INDEX idx_breweries_name_city_unique ON breweries (LOWER(name), LOWER(COALESCE(city, '')))`,
+		`CREATE UNIQUE INDEX idx_beers_brewery_name_unique ON beers (brewery_id, LOWER(name))`,
+	} {
+		_, err = db.Exec(index)
+		require.NoError(t, err, "Failed to create unique index")
+	}
 }
 
 func teardownTestDB(t *testing.T, db *sqlx.DB) {
@@ -123,7 +181,7 @@ func TestSeedDatabase_HappyPath(t *testing.T) {
 		defer teardownTestDB(t, db)
 
 		// When
-		err := models.SeedDatabase(db)
+		err := models.SeedDatabase(context.Background(), db)
 
 		// Then
 		require.NoError(t, err, "models.SeedDatabase should not return an error")
@@ -159,15 +217,25 @@ func TestSeedDatabase_Idempotency(t *testing.T) {
 		defer teardownTestDB(t, db)
 
 		// When - seed multiple times
-		err1 := models.SeedDatabase(db)
-		err2 := models.SeedDatabase(db)
-		err3 := models.SeedDatabase(db)
+		err1 := models.SeedDatabase(context.Background(), db)
+		var seeded services.DataSource
+		require.NoError(t, db.Get(&seeded, `SELECT source, updated_at, records, version FROM data_sources`))
+		err2 := models.SeedDatabase(context.Background(), db)
+		err3 := models.SeedDatabase(context.Background(), db)
 
 		// Then
 		require.NoError(t, err1, "First seeding should not return an error")
 		require.NoError(t, err2, "Second seeding should not return an error")
 		require.NoError(t, err3, "Third seeding should not return an error")
 
+		// The seed is recorded once, when it inserted rows
+		assert.Equal(t, services.SeedSource, seeded.Source)
+		assert.Equal(t, 26+66, seeded.Records)
+		assert.Equal(t, "bundled", seeded.Version)
+		var reseeded services.DataSource
+		require.NoError(t, db.Get(&reseeded, `SELECT source, updated_at, records, version FROM data_sources`))
+		assert.True(t, reseeded.UpdatedAt.Equal(seeded.UpdatedAt), "Seeding nothing should not update the source")
+
 		// Verify counts remain the same
 		var breweryCount int
 		err := db.Get(&breweryCount, "SELECT COUNT(*) FROM breweries")
@@ -188,11 +256,11 @@ func TestSeedDatabase_DatabaseConnectionError(t *testing.T) {
 		db.Close() // Close the connection to simulate connection error
 
 		// When
-		err := models.SeedDatabase(db)
+		err := models.SeedDatabase(context.Background(), db)
 
 		// Then
 		require.Error(t, err, "Should return error when database connection is invalid")
-		assert.Contains(t, err.Error(), "failed to seed breweries", "Error should indicate brewery seeding failure")
+		assert.Contains(t, err.Error(), "failed to begin seed", "Error should come from starting the seed transaction")
 	})
 }
 
@@ -235,7 +303,7 @@ func TestSeedBreweries_HappyPath(t *testing.T) {
 
 		// Verify specific brewery data
 		var brewery services.Brewery
-		err = db.Get(&brewery, "SELECT * FROM breweries WHERE name = ?", "Test Brewery 1")
+		err = db.Get(&brewery, "SELECT brewery_type, city, state, country FROM breweries WHERE name = ?", "Test Brewery 1")
 		require.NoError(t, err)
 		assert.Equal(t, "micro", brewery.BreweryType)
 		assert.Equal(t, "Test City", brewery.City)
@@ -245,28 +313,68 @@ func TestSeedBreweries_HappyPath(t *testing.T) {
 }
 
 func TestSeedBreweries_SkipWhenDataExists(t *testing.T) {
-	t.Run("should skip seeding when breweries already exist", func(t *testing.T) {
+	t.Run("should skip existing breweries and insert the missing ones", func(t *testing.T) {
 		// Given
 		db := setupTestDB(t)
 		defer teardownTestDB(t, db)
 		ctx := context.Background()
 
-		// Insert one brewery manually
+		// Insert one seed brewery manually, with a different case, and one that is not a seed brewery
+		seed := services.GetSeedBreweries()[0]
 		_, err := db.Exec("INSERT INTO breweries (name, brewery_type, city, country) VALUES (?, ?, ?, ?)",
+			strings.ToUpper(seed.Name), seed.BreweryType, seed.City, seed.Country)
+		require.NoError(t, err)
+		_, err = db.Exec("INSERT INTO breweries (name, brewery_type, city, country) VALUES (?, ?, ?, ?)",
 			"Test Brewery", "micro", "Test City", "Test Country")
 		require.NoError(t, err)
 
 		// When
-		err = models.SeedBreweries(ctx, db)
+		result, err := models.SeedBreweries(ctx, db)
 
 		// Then
 		require.NoError(t, err, "models.SeedBreweries should not return an error")
+		total := len(services.GetSeedBreweries())
+		assert.Equal(t, models.SeedResult{Inserted: total - 1, Skipped: 1}, result)
 
-		// Verify count remains 1 (only the manually inserted brewery)
 		var count int
 		err = db.Get(&count, "SELECT COUNT(*) FROM breweries")
 		require.NoError(t, err)
-		assert.Equal(t, 1, count, "Should still have only 1 brewery after skipping seed")
+		assert.Equal(t, total+1, count, "Should hold every seed brewery once plus the manual one")
+
+		// Seeding again inserts nothing
+		result, err = models.SeedBreweries(ctx, db)
+		require.NoError(t, err)
+		assert.Equal(t, models.SeedResult{Skipped: total}, result)
+	})
+}
+
+func TestSeedBreweries_FillsProfiles(t *testing.T) {
+	t.Run("should fill the profile fields an existing brewery has unset", func(t *testing.T) {
+		// Given - a seed brewery recorded before profiles, with a description of its own
+		db := setupTestDB(t)
+		defer teardownTestDB(t, db)
+		ctx := context.Background()
+		db.MustExec(`INSERT INTO breweries (name, brewery_type, city, description, instagram)
+			VALUES ('Darling Brew', 'micro', 'Darling', 'Our own words', '')`)
+
+		// When
+		result, err := models.SeedBreweries(ctx, db)
+
+		// Then
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Skipped)
+		var profile struct {
+			Description string `db:"description"`
+			FoundedYear int    `db:"founded_year"`
+			Instagram   string `db:"instagram"`
+			Email       string `db:"email"`
+		}
+		require.NoError(t, db.Get(&profile, `SELECT description, founded_year, instagram, COALESCE(email, '') AS email
+			FROM breweries WHERE name = 'Darling Brew'`))
+		assert.Equal(t, "Our own words", profile.Description, "A recorded description should be kept")
+		assert.Equal(t, 2010, profile.FoundedYear)
+		assert.Equal(t, "darlingbrew", profile.Instagram, "An empty handle should be filled")
+		assert.Empty(t, profile.Email)
 	})
 }
 
@@ -280,7 +388,7 @@ func TestSeedBreweries_DatabaseError(t *testing.T) {
 		ctx := context.Background()
 
 		// When
-		err = models.SeedBreweries(ctx, db)
+		_, err = models.SeedBreweries(ctx, db)
 
 		// Then
 		assert.Error(t, err, "Should return error when breweries table doesn't exist")
@@ -379,47 +487,92 @@ func TestSeedBeers_HappyPath(t *testing.T) {
 }
 
 func TestSeedBeers_SkipWhenDataExists(t *testing.T) {
-	t.Run("should skip seeding when beers already exist", func(t *testing.T) {
+	t.Run("should skip existing beers and insert the missing ones", func(t *testing.T) {
 		// Given
 		db := setupTestDB(t)
 		defer teardownTestDB(t, db)
 		ctx := context.Background()
-
-		// Seed breweries first using test function
-		breweries := []services.Brewery{
-			{
-				Name:        "Test Brewery 1",
-				BreweryType: "micro",
-				City:        "Test City",
-				State:       "Test State",
-				Country:     "Test Country",
-			},
-			{
-				Name:        "Test Brewery 2",
-				BreweryType: "micro",
-				City:        "Test City",
-				State:       "Test State",
-				Country:     "Test Country",
-			},
-		}
-		err := insertBreweriesForTest(ctx, db, breweries)
+		_, err := models.SeedBreweries(ctx, db)
+		require.NoError(t, err)
+		breweryIDs, err := models.GetBreweryIDs(ctx, db)
 		require.NoError(t, err)
 
-		// Insert one beer manually
-		_, err = db.Exec("INSERT INTO beers (brewery_id, name, style) VALUES (?, ?, ?)", 1, "Test Beer", "Test Style")
+		// Insert one seed beer manually
+		seed := services.GetSeedBeers()[0]
+		_, err = db.Exec("INSERT INTO beers (brewery_id, name, style) VALUES (?, ?, ?)",
+			breweryIDs[seed.BreweryName], seed.Name, seed.Style)
 		require.NoError(t, err)
 
 		// When
-		err = models.SeedBeers(ctx, db)
+		result, err := models.SeedBeers(ctx, db)
 
 		// Then
 		require.NoError(t, err, "models.SeedBeers should not return an error")
+		assert.Equal(t, len(services.GetSeedBeers()), result.Inserted+result.Skipped, "Should count every seed beer")
 
-		// Verify count remains 1
 		var count int
 		err = db.Get(&count, "SELECT COUNT(*) FROM beers")
 		require.NoError(t, err)
-		assert.Equal(t, 1, count, "Should still have only 1 beer after skipping seed")
+		assert.Equal(t, result.Inserted+1, count, "Should hold the manual beer and the inserted ones")
+		assert.Equal(t, 66, count, "Should hold every seed beer whose brewery exists once")
+
+		// Seeding again inserts nothing
+		result, err = models.SeedBeers(ctx, db)
+		require.NoError(t, err)
+		assert.Zero(t, result.Inserted)
+	})
+}
+
+func TestSeedDatabase_RepairsPartialSeed(t *testing.T) {
+	t.Run("should finish seeding a database seeded only in part", func(t *testing.T) {
+		// Given - breweries seeded, but the process stopped before the beers
+		db := setupTestDB(t)
+		defer teardownTestDB(t, db)
+		ctx := context.Background()
+		_, err := models.SeedBreweries(ctx, db)
+		require.NoError(t, err)
+		_, err = db.Exec("DELETE FROM breweries WHERE id = (SELECT MAX(id) FROM breweries)")
+		require.NoError(t, err)
+
+		// When
+		err = models.SeedDatabase(context.Background(), db)
+
+		// Then
+		require.NoError(t, err)
+		var breweryCount, beerCount int
+		require.NoError(t, db.Get(&breweryCount, "SELECT COUNT(*) FROM breweries"))
+		require.NoError(t, db.Get(&beerCount, "SELECT COUNT(*) FROM beers"))
+		assert.Equal(t, len(services.GetSeedBreweries()), breweryCount, "The deleted brewery should be restored")
+		assert.Equal(t, 66, beerCount, "Every seed beer whose brewery exists should be inserted")
+	})
+}
+
+func TestSeedDatabase_RollsBackOnFailure(t *testing.T) {
+	t.Run("should leave no rows when inserting a beer fails", func(t *testing.T) {
+		// Given - a trigger failing every beer insert, after the breweries are inserted
+		db := setupTestDB(t)
+		defer teardownTestDB(t, db)
+		db.SetMaxOpenConns(1)
+		db.MustExec(`CREATE TRIGGER fail_beer_insert BEFORE INSERT ON beers
+			BEGIN SELECT RAISE(ABORT, 'injected beer insert failure'); END`)
+
+		// When
+		err := models.SeedDatabase(context.Background(), db)
+
+		// Then
+		require.ErrorContains(t, err, "injected beer insert failure")
+		for _, table := range []string{"breweries", "venues", "beers", "data_sources"} {
+			var count int
+			require.NoError(t, db.Get(&count, "SELECT COUNT(*) FROM "+table))
+			assert.Zero(t, count, "Expected the failed seed to leave no rows in %s", table)
+		}
+
+		// Seeding again once the failure is gone completes the seed
+		db.MustExec("DROP TRIGGER fail_beer_insert")
+		require.NoError(t, models.SeedDatabase(context.Background(), db))
+		var beerCount int
+		require.NoError(t, db.Get(&beerCount, "SELECT COUNT(*) FROM beers"))
+		assert.Equal(t, 66, beerCount)
 	})
 }
 
@@ -432,10 +585,11 @@ func TestSeedBeers_NoBreweriesExist(t *testing.T) {
 		// Note: Not seeding breweries first
 
 		// When
-		err := models.SeedBeers(ctx, db)
+		result, err := models.SeedBeers(ctx, db)
 
 		// Then
 		require.NoError(t, err, "models.SeedBeers should not return an error even when no breweries exist")
+		assert.Equal(t, models.SeedResult{Skipped: len(services.GetSeedBeers())}, result)
 
 		// Verify no beers were inserted
 		var count int
@@ -715,6 +869,143 @@ func TestInsertBeers_MissingBrewery(t *testing.T) {
 	})
 }
 
+func TestSeedBeers_Ingredients(t *testing.T) {
+	t.Run("should seed ingredients once, including for beers seeded before them", func(t *testing.T) {
+		// Given - the seed beers, seeded without their ingredients
+		db := setupTestDB(t)
+		defer teardownTestDB(t, db)
+		ctx := context.Background()
+		require.NoError(t, models.SeedDatabase(ctx, db))
+		db.MustExec("DELETE FROM beer_hops")
+
+		// When
+		require.NoError(t, models.SeedDatabase(ctx, db))
+		require.NoError(t, models.SeedDatabase(ctx, db))
+
+		// Then
+		var hops []struct {
+			Name   string  `db:"name"`
+			Amount float64 `db:"amount"`
+			Timing string  `db:"timing"`
+		}
+		require.NoError(t, db.Select(&hops, `SELECT h.name, h.amount, h.timing FROM beer_hops h
+			JOIN beers b ON b.id = h.beer_id WHERE b.name = 'King''s Blockhouse IPA' ORDER BY h.id`))
+		require.Len(t, hops, 4, "The hop bill should be restored once")
+		assert.Equal(t, "Columbus", hops[0].Name)
+		assert.InDelta(t, 20.0, hops[0].Amount, 0.001)
+		assert.Equal(t, "60 min", hops[0].Timing)
+
+		var yeast int
+		require.NoError(t, db.Get(&yeast, "SELECT COUNT(*) FROM beer_yeast"))
+		assert.Equal(t, 4, yeast, "Each beer with ingredients should have its yeast once")
+	})
+}
+
+func TestTagBeers(t *testing.T) {
+	t.Run("should tag the seeded beers and retag those whose description changed", func(t *testing.T) {
+		// Given
+		db := setupTestDB(t)
+		defer teardownTestDB(t, db)
+		ctx := context.Background()
+		require.NoError(t, models.SeedDatabase(ctx, db))
+		tags, err := services.LoadBeerTags()
+		require.NoError(t, err)
+
+		var stout services.TagList
+		require.NoError(t, db.Get(&stout, "SELECT tags FROM beers WHERE name = 'Castle Milk Stout'"))
+		assert.Equal(t, services.TagList{"caramel", "chocolate", "coffee"}, stout)
+		var lager services.TagList
+		require.NoError(t, db.Get(&lager, "SELECT tags FROM beers WHERE name = 'Castle Lager'"))
+		assert.Equal(t, services.TagList{}, lager, "A beer without flavour keywords should be tagged with none")
+
+		// When
+		db.MustExec("UPDATE beers SET description = 'Tart cherry sour' WHERE name = 'Castle Lager'")
+		retagged, err := models.TagBeers(ctx, db, tags)
+
+		// Then
+		require.NoError(t, err)
+		assert.Equal(t, 1, retagged, "Only the changed beer should be retagged")
+		require.NoError(t, db.Get(&lager, "SELECT tags FROM beers WHERE name = 'Castle Lager'"))
+		assert.Equal(t, services.TagList{"fruity", "sour"}, lager)
+	})
+}
+
+func TestSeedDatabase_Venues(t *testing.T) {
+	t.Run("should seed the venues of breweries that have none", func(t *testing.T) {
+		// Given - the seed data, with one brewery's venues replaced by a venue of its own
+		db := setupTestDB(t)
+		defer teardownTestDB(t, db)
+		ctx := context.Background()
+		require.NoError(t, models.SeedDatabase(ctx, db))
+		db.MustExec(`DELETE FROM venues WHERE brewery_id = (SELECT id FROM breweries WHERE name = 'Woodstock Brewery')`)
+		db.MustExec(`INSERT INTO venues (brewery_id, name)
+			SELECT id, 'Pop-up Bar' FROM breweries WHERE name = 'Mad Giant Brewery'`)
+		db.MustExec(`DELETE FROM venues WHERE name LIKE 'Mad Giant%'`)
+
+		// When
+		require.NoError(t, models.SeedDatabase(ctx, db))
+
+		// Then
+		seed, err := services.LoadSeedData()
+		require.NoError(t, err)
+		var names []string
+		require.NoError(t, db.Select(&names, "SELECT name FROM venues ORDER BY id"))
+		assert.Len(t, names, len(seed.Venues)-1, "Mad Giant's venues should not be restored beside its own")
+		assert.Contains(t, names, "Woodstock Brewery Taproom")
+		assert.Contains(t, names, "Pop-up Bar")
+
+		var hours services.VenueHours
+		require.NoError(t, db.Get(&hours, "SELECT hours FROM venues WHERE name = 'Woodstock Brewery Taproom'"))
+		assert.Equal(t, "11:00-02:00", hours["fri"])
+	})
+}
+
+func TestReseed(t *testing.T) {
+	t.Run("should replace the catalog with the seed data", func(t *testing.T) {
+		// Given
+		db := setupTestDB(t)
+		defer teardownTestDB(t, db)
+		db.SetMaxOpenConns(1)
+		ctx := context.Background()
+		_, err := db.Exec("INSERT INTO breweries (name, brewery_type, city) VALUES ('Local', 'micro', 'Durban')")
+		require.NoError(t, err)
+		seed, err := services.LoadSeedData()
+		require.NoError(t, err)
+
+		// When
+		breweries, beers, err := models.Reseed(ctx, db, seed)
+
+		// Then
+		require.NoError(t, err)
+		assert.Equal(t, models.SeedResult{Inserted: len(seed.Breweries)}, breweries)
+		assert.Equal(t, models.SeedResult{Inserted: len(seed.Beers)}, beers)
+		var count int
+		require.NoError(t, db.Get(&count, "SELECT COUNT(*) FROM breweries WHERE name = 'Local'"))
+		assert.Zero(t, count, "Breweries outside the seed data should be deleted")
+	})
+
+	t.Run("should leave the catalog as it was when seeding fails", func(t *testing.T) {
+		// Given - a beers table without a style column, so inserting the beers fails
+		db := setupTestDB(t)
+		defer teardownTestDB(t, db)
+		db.SetMaxOpenConns(1)
+		db.MustExec("DROP TABLE beers")
+		db.MustExec("CREATE TABLE beers (id INTEGER PRIMARY KEY, brewery_id INTEGER, name TEXT)")
+		db.MustExec("INSERT INTO breweries (name, brewery_type, city) VALUES ('Local', 'micro', 'Durban')")
+		seed, err := services.LoadSeedData()
+		require.NoError(t, err)
+
+		// When
+		_, _, err = models.Reseed(context.Background(), db, seed)
+
+		// Then
+		require.ErrorContains(t, err, "failed to seed beers")
+		var count int
+		require.NoError(t, db.Get(&count, "SELECT COUNT(*) FROM breweries"))
+		assert.Equal(t, 1, count, "The failed reseed should be rolled back")
+	})
+}
+
 // Edge Case Tests
 
 func TestSeedDatabase_ContextCancellation(t *testing.T) {
@@ -728,7 +1019,7 @@ func TestSeedDatabase_ContextCancellation(t *testing.T) {
 		cancel() // Cancel immediately
 
 		// When
-		err := models.SeedBreweries(ctx, db)
+		_, err := models.SeedBreweries(ctx, db)
 		// Then
 		// The behavior depends on the database driver and when the context is checked
 		// Some drivers may not immediately respect context cancellation
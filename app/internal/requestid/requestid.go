@@ -0,0 +1,63 @@
+// Package requestid carries the ID of the HTTP request being served through a context, so log
+// lines and errors written while serving it can be matched to its access log entry.
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Header is the HTTP header a request ID is read from and echoed back in.
+const Header = "X-Request-Id"
+
+// Field is the log field and error data key holding the request ID.
+const Field = "request_id"
+
+// maxLength bounds the length of an incoming request ID.
+const maxLength = 128
+
+type contextKey struct{}
+
+// New returns a random request ID of 32 hex characters.
+func New() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Valid reports whether an incoming request ID is safe to adopt and log: non-empty, at most 128
+// characters, and only printable ASCII without spaces.
+func Valid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+	for i := range len(id) {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// NewContext returns a copy of ctx carrying the request ID id.
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the request ID carried by ctx, or "" when there is none.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
+
+// Logger returns the standard logger with the request ID carried by ctx, if any, as a field.
+func Logger(ctx context.Context) *logrus.Entry {
+	entry := logrus.NewEntry(logrus.StandardLogger())
+	if id := FromContext(ctx); id != "" {
+		return entry.WithField(Field, id)
+	}
+	return entry
+}
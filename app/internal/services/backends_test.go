@@ -0,0 +1,306 @@
+package services_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/models"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/services"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testBackends opens a migrated database seeded with the embedded seed data for each backend: a
+// SQLite file always, and the PostgreSQL database named by TEST_DATABASE_URL when it is set, whose
+// catalog is replaced.
+func testBackends(t *testing.T) map[string]*sqlx.DB {
+	t.Helper()
+	urls := map[string]string{"sqlite": "sqlite://" + filepath.Join(t.TempDir(), "brewsource.db")}
+	if url := os.Getenv("TEST_DATABASE_URL"); url != "" {
+		urls["postgres"] = url
+	}
+
+	seed, err := services.LoadSeedData()
+	require.NoError(t, err)
+	backends := map[string]*sqlx.DB{}
+	for name, url := range urls {
+		driverName, dataSourceName, err := models.ParseDatabaseURL(url)
+		require.NoError(t, err)
+		db, err := sqlx.Connect(driverName, dataSourceName)
+		require.NoError(t, err, "Failed to open the %s database", name)
+		t.Cleanup(func() { db.Close() })
+
+		require.NoError(t, models.MigrateDatabase(db))
+		_, _, err = models.Reseed(context.Background(), db, seed)
+		require.NoError(t, err)
+		backends[name] = db
+	}
+	return backends
+}
+
+func TestSeedAndSearch_Backends(t *testing.T) {
+	for name, db := range testBackends(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			beerService := services.NewBeerService(db, nil)
+			breweryService := services.NewBreweryService(db, nil)
+			breweryService.EnableDistanceSearch(models.HasDistanceSearch(db))
+
+			beers, err := beerService.SearchBeersPage(ctx, services.BeerSearchQuery{Style: "ipa", Limit: 5})
+			require.NoError(t, err)
+			require.NotEmpty(t, beers.Items, "Style filters should ignore case")
+			for _, beer := range beers.Items {
+				assert.Contains(t, strings.ToLower(beer.Style), "ipa")
+			}
+			assert.GreaterOrEqual(t, beers.TotalCount, len(beers.Items))
+
+			detail, err := beerService.GetBeerByID(ctx, beers.Items[0].ID)
+			require.NoError(t, err)
+			assert.Equal(t, beers.Items[0].Name, detail.Name)
+			_, err = beerService.GetBeerIngredients(ctx, detail.ID)
+			require.NoError(t, err)
+
+			random, err := beerService.RandomBeers(ctx, "", "", 3)
+			require.NoError(t, err)
+			assert.Len(t, random, 3)
+
+			breweries, err := breweryService.SearchBreweriesPage(ctx, services.BrewerySearchQuery{
+				Country: []string{"south africa"}, Types: []string{"micro", "brewpub"},
+			})
+			require.NoError(t, err)
+			require.NotEmpty(t, breweries.Items)
+			for _, brewery := range breweries.Items {
+				assert.Contains(t, []string{"micro", "brewpub"}, brewery.BreweryType)
+			}
+
+			excluded, err := breweryService.SearchBreweriesPage(ctx, services.BrewerySearchQuery{
+				Country: []string{"south africa", "namibia"},
+				Exclude: services.BreweryExclusions{Types: []string{"brewpub"}},
+			})
+			require.NoError(t, err)
+			require.NotEmpty(t, excluded.Items)
+			for _, brewery := range excluded.Items {
+				assert.NotEqual(t, "brewpub", brewery.BreweryType)
+			}
+
+			countries, err := breweryService.ListCountries(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, []*services.CountryCount{{Country: "South Africa", Breweries: 26}}, countries)
+			regions, err := breweryService.ListRegions(ctx, "SOUTH africa")
+			require.NoError(t, err)
+			assert.Equal(t, "South Africa", regions.Country)
+			assert.Equal(t, 26, regions.Breweries)
+			require.Len(t, regions.Regions, 6)
+			assert.Equal(t, &services.RegionCount{Region: "Western Cape", Breweries: 15}, regions.Regions[5])
+			_, err = breweryService.ListRegions(ctx, "Atlantis")
+			require.ErrorIs(t, err, services.ErrNotFound)
+
+			stats, err := breweryService.GetStats(ctx, services.StatsScope{})
+			require.NoError(t, err)
+			assert.Equal(t, 26, stats.TotalBreweries)
+			assert.Equal(t, 66, stats.TotalBeers)
+
+			lat, lng, radius := -33.93, 18.42, 50.0
+			near := services.BrewerySearchQuery{Lat: &lat, Lng: &lng, RadiusKm: &radius}
+			if models.HasDistanceSearch(db) {
+				nearby, err := breweryService.SearchBreweries(ctx, near)
+				require.NoError(t, err)
+				assert.NotEmpty(t, nearby)
+			} else {
+				_, err = breweryService.SearchBreweries(ctx, near)
+				require.ErrorIs(t, err, services.ErrInvalidSearchQuery)
+			}
+
+			madGiant, err := breweryService.ResolveBreweryID(ctx, "Mad Giant Brewery")
+			require.NoError(t, err)
+			venues, err := breweryService.GetBreweryVenues(ctx, madGiant)
+			require.NoError(t, err)
+			require.Len(t, venues, 2)
+			assert.True(t, venues[0].IsPrimary, "The primary venue should come first")
+			assert.Equal(t, "11:30-23:00", venues[0].Hours["fri"])
+			nearVenues := services.VenueSearchQuery{Lat: -26.2, Lng: 28.04, RadiusKm: &radius}
+			if models.HasDistanceSearch(db) {
+				nearby, err := breweryService.SearchVenuesNear(ctx, nearVenues)
+				require.NoError(t, err)
+				require.NotEmpty(t, nearby)
+				assert.Equal(t, "Mad Giant Brewery", nearby[0].Brewery)
+			} else {
+				_, err = breweryService.SearchVenuesNear(ctx, nearVenues)
+				require.ErrorIs(t, err, services.ErrInvalidSearchQuery)
+			}
+
+			id, err := breweryService.CreateBrewery(ctx, services.Brewery{
+				Name: "Backend Test Brewery", BreweryType: "micro", City: "Durban", Country: "South Africa",
+			})
+			require.NoError(t, err)
+			_, err = breweryService.CreateBrewery(ctx, services.Brewery{
+				Name: "backend test brewery", BreweryType: "micro", City: "DURBAN",
+			})
+			require.ErrorIs(t, err, services.ErrDuplicate)
+			_, err = beerService.CreateBeer(ctx, services.Beer{BreweryID: id + 1000, Name: "Lost Lager", ABV: 5})
+			require.ErrorIs(t, err, services.ErrNotFound)
+
+			require.NoError(t, breweryService.SoftDeleteBrewery(ctx, id))
+			found, err := breweryService.SearchBreweries(ctx, services.BrewerySearchQuery{Name: "Backend Test"})
+			require.NoError(t, err)
+			assert.Empty(t, found, "A soft-deleted brewery should not be found")
+		})
+	}
+}
+
+// Test that the seeded beers are tagged from their descriptions, and that searches match their
+// descriptions and tags.
+func TestSearchBeers_DescriptionAndTagsBackends(t *testing.T) {
+	for name, db := range testBackends(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			beerService := services.NewBeerService(db, nil)
+			names := func(query services.BeerSearchQuery) []string {
+				t.Helper()
+				beers, err := beerService.SearchBeers(ctx, query)
+				require.NoError(t, err)
+				var names []string
+				for _, beer := range beers {
+					names = append(names, beer.Name)
+				}
+				return names
+			}
+
+			assert.Equal(t, []string{"Castle Milk Stout", "The Stout"},
+				names(services.BeerSearchQuery{Tags: []string{"coffee", "chocolate"}}))
+			assert.Equal(t, []string{"Oud Bruin"}, names(services.BeerSearchQuery{Tags: []string{"sour"}}))
+			assert.Len(t, names(services.BeerSearchQuery{Tags: []string{"tropical"}}), 5)
+			assert.Equal(t, []string{"Belgian Wit", "Bone Crusher", "White Anvil"},
+				names(services.BeerSearchQuery{Description: "Orange Peel"}))
+			assert.Equal(t, []string{"White Anvil"},
+				names(services.BeerSearchQuery{Description: "orange peel", Location: "Dullstroom"}))
+			assert.Empty(t, names(services.BeerSearchQuery{Tags: []string{"smoky"}}))
+
+			beers, err := beerService.SearchBeers(ctx, services.BeerSearchQuery{Name: "Jungle Paradise"})
+			require.NoError(t, err)
+			require.Len(t, beers, 1)
+			detail, err := beerService.GetBeerByID(ctx, beers[0].ID)
+			require.NoError(t, err)
+			assert.Equal(t, services.TagList{"citrus", "tropical"}, detail.Tags)
+		})
+	}
+}
+
+func TestRecommendationCandidates_Backends(t *testing.T) {
+	for name, db := range testBackends(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			beerService := services.NewBeerService(db, nil)
+			liked, err := beerService.SearchBeers(ctx, services.BeerSearchQuery{Name: "Capital IPA"})
+			require.NoError(t, err)
+			require.Len(t, liked, 1)
+
+			candidates, err := beerService.RecommendationCandidates(ctx, []string{"American IPA", "Hazy IPA"}, liked[0].ID)
+			require.NoError(t, err)
+			var names []string
+			for _, candidate := range candidates {
+				names = append(names, candidate.Name)
+				assert.NotZero(t, candidate.SRM)
+				assert.Equal(t, "South Africa", candidate.Country)
+			}
+			assert.ElementsMatch(t, []string{"Jungle Paradise", "Giant's IPA", "Clarens IPA", "King's Blockhouse IPA",
+				"Skeleton Coast IPA", "Urban Legend IPA"}, names)
+		})
+	}
+}
+
+// Test that brewery profiles are written and read back, unset fields as empty strings.
+func TestBreweryProfiles_Backends(t *testing.T) {
+	for name, db := range testBackends(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			breweryService := services.NewBreweryService(db, nil)
+			results, err := breweryService.SearchBreweries(ctx, services.BrewerySearchQuery{Name: "Darling Brew"})
+			require.NoError(t, err)
+			require.Len(t, results, 1)
+			assert.Equal(t, 2010, results[0].FoundedYear)
+			assert.Equal(t, "darlingbrew", results[0].Instagram)
+			assert.Empty(t, results[0].Email)
+
+			brewery := services.Brewery{Name: "Profile Brewing", BreweryType: "micro", City: "Durban",
+				Description: "Test ales.", FoundedYear: 2019, Twitter: "@profilebrew", Email: "beer@profile.test"}
+			id, err := breweryService.CreateBrewery(ctx, brewery)
+			require.NoError(t, err)
+			detail, err := breweryService.GetBreweryByID(ctx, id)
+			require.NoError(t, err)
+			assert.Equal(t, "Test ales.", detail.Description)
+			assert.Equal(t, 2019, detail.FoundedYear)
+			assert.Equal(t, "profilebrew", detail.Twitter)
+			assert.Equal(t, "beer@profile.test", detail.Email)
+
+			brewery.ID, brewery.FoundedYear, brewery.Twitter = id, 0, ""
+			require.NoError(t, breweryService.UpdateBrewery(ctx, brewery))
+			detail, err = breweryService.GetBreweryByID(ctx, id)
+			require.NoError(t, err)
+			assert.Zero(t, detail.FoundedYear)
+			assert.Empty(t, detail.Twitter)
+			assert.Empty(t, detail.Facebook)
+		})
+	}
+}
+
+// Test that a beer stored twice under the same name, as in a database that predates the duplicate
+// checks, is found once.
+func TestSearchBeers_DuplicatesBackends(t *testing.T) {
+	for name, db := range testBackends(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			_, err := db.ExecContext(ctx, `DROP INDEX idx_beers_brewery_name_unique`)
+			require.NoError(t, err)
+			t.Cleanup(func() {
+				_, _ = db.Exec(`DELETE FROM beers WHERE name = 'KING''S BLOCKHOUSE IPA'`)
+				_, _ = db.Exec(`CREATE UNIQUE INDEX idx_beers_brewery_name_unique ON beers (brewery_id, LOWER(name))`)
+			})
+			_, err = db.ExecContext(ctx, `
+				INSERT INTO beers (brewery_id, name, style, abv)
+				SELECT brewery_id, UPPER(name), style, abv FROM beers WHERE name = 'King''s Blockhouse IPA'`)
+			require.NoError(t, err)
+
+			beers, err := services.NewBeerService(db, nil).SearchBeersPage(ctx, services.BeerSearchQuery{
+				Name: "blockhouse",
+			})
+			require.NoError(t, err)
+			assert.Equal(t, 1, beers.TotalCount)
+			require.Len(t, beers.Items, 1)
+			assert.Equal(t, "King's Blockhouse IPA", beers.Items[0].Name, "The earliest beer should be kept")
+			assert.NotEmpty(t, beers.Items[0].City)
+		})
+	}
+}
+
+// Test that name suggestions match prefixes, not substrings, and treat LIKE wildcards literally.
+func TestSuggestNames_Backends(t *testing.T) {
+	for name, db := range testBackends(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			breweryService := services.NewBreweryService(db, nil)
+
+			names, err := breweryService.SuggestNames(ctx, "MAD", 0)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"Mad Giant Brewery"}, names)
+
+			names, err = breweryService.SuggestNames(ctx, "giant", 0)
+			require.NoError(t, err)
+			assert.Empty(t, names, "Names should only match at the start")
+
+			names, err = breweryService.SuggestNames(ctx, "%", 0)
+			require.NoError(t, err)
+			assert.Empty(t, names, "Wildcards should match themselves")
+
+			beers, err := services.NewBeerService(db, nil).SuggestNames(ctx, "king's", 1)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"King's Blockhouse IPA"}, beers)
+		})
+	}
+}
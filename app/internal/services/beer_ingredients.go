@@ -0,0 +1,75 @@
+// Package services provides business logic and service layer functions for Brewsource MCP, including beer and brewery operations.
+package services
+
+import (
+	"context"
+	"fmt"
+)
+
+// BeerIngredient is one hop, fermentable, or yeast of a beer. Amount is in Unit, such as "g" or
+// "kg", and Timing says when it is added, such as "60 min" or "dry hop" for a hop and "mash" for a
+// malt. Unit and Timing are empty when not recorded.
+type BeerIngredient struct {
+	Name   string  `db:"name"   json:"name"`
+	Amount float64 `db:"amount" json:"amount"`
+	Unit   string  `db:"unit"   json:"unit,omitempty"`
+	Timing string  `db:"timing" json:"timing,omitempty"`
+}
+
+// BeerIngredients is the hop bill, grain bill, and yeast of a beer, each in the order recorded.
+type BeerIngredients struct {
+	Hops         []BeerIngredient `json:"hops"`
+	Fermentables []BeerIngredient `json:"fermentables"`
+	Yeast        []BeerIngredient `json:"yeast"`
+}
+
+// IsEmpty reports whether no ingredient is recorded.
+func (i *BeerIngredients) IsEmpty() bool {
+	return i == nil || len(i.Hops)+len(i.Fermentables)+len(i.Yeast) == 0
+}
+
+// GetBeerIngredients returns the ingredients of the beer with the given ID, with one query per
+// ingredient type. A beer without ingredients, or with no such beer, has empty lists.
+func (s *BeerService) GetBeerIngredients(ctx context.Context, beerID int) (*BeerIngredients, error) {
+	ingredients := &BeerIngredients{}
+	for _, kind := range []struct {
+		table string
+		list  *[]BeerIngredient
+	}{
+		{"beer_hops", &ingredients.Hops},
+		{"beer_fermentables", &ingredients.Fermentables},
+		{"beer_yeast", &ingredients.Yeast},
+	} {
+		list, err := s.selectIngredients(ctx, kind.table, beerID)
+		if err != nil {
+			return nil, err
+		}
+		*kind.list = list
+	}
+	return ingredients, nil
+}
+
+// selectIngredients selects the rows of one ingredient table for a beer; table is one of the
+// fixed ingredient table names, never user input.
+func (s *BeerService) selectIngredients(ctx context.Context, table string, beerID int) (_ []BeerIngredient, err error) {
+	q := `
+		  SELECT name, COALESCE(amount, 0) AS amount, COALESCE(unit, '') AS unit,
+		         COALESCE(timing, '') AS timing
+		  FROM ` + table + `
+		  WHERE beer_id = $1
+		  ORDER BY id`
+
+	name := "get_" + table
+	ctx, finish := s.queries.begin(ctx, name, q, beerID)
+	defer finish(&err)
+
+	var list []BeerIngredient
+	err = s.queries.retry(ctx, name, func() error {
+		list = []BeerIngredient{}
+		return s.db.SelectContext(ctx, &list, q, beerID)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s of beer %d: %w", table, beerID, err)
+	}
+	return list, nil
+}
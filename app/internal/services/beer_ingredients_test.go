@@ -0,0 +1,55 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetBeerIngredients(t *testing.T) {
+	columns := []string{"name", "amount", "unit", "timing"}
+	query := func(table string) string {
+		return `SELECT name, COALESCE\(amount, 0\) AS amount, .* FROM ` + table +
+			`\s+WHERE beer_id = \$1\s+ORDER BY id$`
+	}
+
+	t.Run("Returns each ingredient type with one query", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		mock.ExpectQuery(query("beer_hops")).WithArgs(7).WillReturnRows(sqlmock.NewRows(columns).
+			AddRow("Columbus", 20.0, "g", "60 min").
+			AddRow("Cascade", 50.0, "g", "dry hop"))
+		mock.ExpectQuery(query("beer_fermentables")).WithArgs(7).WillReturnRows(sqlmock.NewRows(columns).
+			AddRow("Pale Ale Malt", 5.2, "kg", "mash"))
+		mock.ExpectQuery(query("beer_yeast")).WithArgs(7).WillReturnRows(sqlmock.NewRows(columns))
+
+		ingredients, err := setupBeerService(db).GetBeerIngredients(context.Background(), 7)
+
+		require.NoError(t, err)
+		require.Len(t, ingredients.Hops, 2)
+		assert.Equal(t, "dry hop", ingredients.Hops[1].Timing)
+		require.Len(t, ingredients.Fermentables, 1)
+		assert.InDelta(t, 5.2, ingredients.Fermentables[0].Amount, 0.001)
+		assert.NotNil(t, ingredients.Yeast, "an empty type should be an empty list")
+		assert.Empty(t, ingredients.Yeast)
+		assert.False(t, ingredients.IsEmpty())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Database errors are wrapped", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		mock.ExpectQuery(query("beer_hops")).WillReturnError(errors.New("connection reset"))
+
+		_, err := setupBeerService(db).GetBeerIngredients(context.Background(), 7)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to get beer_hops of beer 7: connection reset")
+	})
+}
@@ -0,0 +1,160 @@
+// Package services provides business logic and service layer functions for Brewsource MCP, including beer and brewery operations.
+package services
+
+import (
+	"database/sql/driver"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// BeerTagsPathEnv points LoadBeerTags at a custom flavour tag vocabulary in place of the embedded one.
+const BeerTagsPathEnv = "BEER_TAGS_PATH"
+
+// ErrInvalidBeerTags is wrapped by the errors for a flavour tag vocabulary that fails Validate.
+var ErrInvalidBeerTags = errors.New("invalid beer tags")
+
+// beerTagsFile holds the bundled flavour tag vocabulary.
+//
+//go:embed data/beer_tags.json
+var beerTagsFile []byte
+
+// tagName matches a flavour tag: lowercase words joined by hyphens, such as "dark-fruit", which
+// need no quoting in an array literal.
+var tagName = regexp.MustCompile(`^[a-z]+(-[a-z]+)*$`)
+
+// BeerTags is a flavour tag vocabulary: the keywords that give a beer each tag when its description
+// mentions one, such as "mango" and "pineapple" for "tropical". Files hold it as a JSON object of
+// tag to keyword array.
+type BeerTags map[string][]string
+
+// LoadBeerTags loads and validates the flavour tag vocabulary: the file named by BEER_TAGS_PATH if
+// it is set, and the embedded vocabulary otherwise.
+func LoadBeerTags() (BeerTags, error) {
+	raw, name := beerTagsFile, "bundled beer tags"
+	if path := os.Getenv(BeerTagsPathEnv); path != "" {
+		var err error
+		name = path
+		raw, err = os.ReadFile(filepath.Clean(path)) // #nosec G304 - the path is deployment configuration
+		if err != nil {
+			return nil, fmt.Errorf("failed to read beer tags file: %w", err)
+		}
+	}
+	var tags BeerTags
+	if err := json.Unmarshal(raw, &tags); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", name, err)
+	}
+	if err := tags.Validate(); err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// Validate checks that there is at least one tag, that each tag is named in lowercase words joined
+// by hyphens, and that each has at least one keyword with a letter or digit in it.
+func (t BeerTags) Validate() error {
+	if len(t) == 0 {
+		return fmt.Errorf("%w: no tags", ErrInvalidBeerTags)
+	}
+	for _, tag := range t.Names() {
+		if !tagName.MatchString(tag) {
+			return fmt.Errorf("%w: tag %q must be lowercase words joined by hyphens", ErrInvalidBeerTags, tag)
+		}
+		if len(t[tag]) == 0 {
+			return fmt.Errorf("%w: tag %q has no keywords", ErrInvalidBeerTags, tag)
+		}
+		for _, keyword := range t[tag] {
+			if len(descriptionWords(keyword)) == 0 {
+				return fmt.Errorf("%w: tag %q has a blank keyword", ErrInvalidBeerTags, tag)
+			}
+		}
+	}
+	return nil
+}
+
+// Names returns the tags in alphabetical order.
+func (t BeerTags) Names() []string {
+	names := make([]string, 0, len(t))
+	for tag := range t {
+		names = append(names, tag)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Extract returns, in alphabetical order, the tags whose keywords a description mentions. Keywords
+// match whole words, ignoring case and punctuation, so that "pine" does not match "pineapple" and
+// "orange peel" matches "orange-peel".
+func (t BeerTags) Extract(description string) []string {
+	words := descriptionWords(description)
+	tags := []string{}
+	for _, tag := range t.Names() {
+		for _, keyword := range t[tag] {
+			if containsWords(words, descriptionWords(keyword)) {
+				tags = append(tags, tag)
+				break
+			}
+		}
+	}
+	return tags
+}
+
+// descriptionWords splits text into lowercase words of letters and digits.
+func descriptionWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// containsWords reports whether phrase appears in words as a run of consecutive words.
+func containsWords(words, phrase []string) bool {
+	for i := 0; i+len(phrase) <= len(words); i++ {
+		if slices.Equal(words[i:i+len(phrase)], phrase) {
+			return true
+		}
+	}
+	return false
+}
+
+// TagList is the flavour tags of a beer, stored in the tags column: a text[] on PostgreSQL, and its
+// array literal, such as "{chocolate,coffee}", on SQLite. A nil TagList is a beer not yet tagged.
+type TagList []string
+
+// Value implements driver.Valuer, writing the tags as an array literal, which PostgreSQL casts to
+// text[], or NULL for nil.
+func (l TagList) Value() (driver.Value, error) {
+	if l == nil {
+		return nil, nil
+	}
+	return "{" + strings.Join(l, ",") + "}", nil
+}
+
+// Scan implements sql.Scanner, reading an array literal or NULL.
+func (l *TagList) Scan(src interface{}) error {
+	var literal string
+	switch v := src.(type) {
+	case nil:
+		*l = nil
+		return nil
+	case []byte:
+		literal = string(v)
+	case string:
+		literal = v
+	default:
+		return fmt.Errorf("cannot scan %T into a tag list", src)
+	}
+	*l = TagList{}
+	for _, tag := range strings.Split(strings.Trim(literal, "{}"), ",") {
+		if tag = strings.Trim(tag, `"`); tag != "" {
+			*l = append(*l, tag)
+		}
+	}
+	return nil
+}
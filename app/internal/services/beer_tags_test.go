@@ -0,0 +1,117 @@
+package services_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test that the bundled vocabulary tags the seed beers by the flavours their descriptions name.
+func TestBeerTags_SeedDescriptions(t *testing.T) {
+	tags, err := services.LoadBeerTags()
+	require.NoError(t, err)
+	seed, err := services.LoadSeedData()
+	require.NoError(t, err)
+	descriptions := map[string]string{}
+	for _, beer := range seed.Beers {
+		descriptions[beer.Name] = beer.Description
+	}
+
+	tests := map[string][]string{
+		"Castle Milk Stout":    {"caramel", "chocolate", "coffee"},
+		"The Stout":            {"chocolate", "coffee"},
+		"Jungle Paradise":      {"citrus", "tropical"},
+		"The Stranded Coconut": {"tropical"},
+		"Giant's IPA":          {"citrus", "pine"},
+		"White Anvil":          {"citrus", "spicy"},
+		"Weiss":                {"banana", "clove"},
+		"The Vannie Hout":      {"oak", "spicy", "vanilla"},
+		"Oud Bruin":            {"fruity", "oak", "sour"},
+		"Black Mist":           {"hoppy", "roasty"},
+		"Hoenderhok Bock":      {"caramel", "malty"},
+		"Car Park John":        {"biscuit", "citrus"},
+		"Live Culture":         {"earthy", "fruity"},
+		"Castle Lager":         {},
+	}
+	for name, want := range tests {
+		t.Run(name, func(t *testing.T) {
+			description, ok := descriptions[name]
+			require.True(t, ok, "%s is not a seed beer", name)
+			assert.Equal(t, want, tags.Extract(description))
+		})
+	}
+}
+
+func TestBeerTags_Extract(t *testing.T) {
+	tags := services.BeerTags{
+		"citrus":   {"orange peel", "grapefruit"},
+		"pine":     {"pine"},
+		"tropical": {"pineapple"},
+	}
+	assert.Equal(t, []string{"tropical"}, tags.Extract("Pineapple juice"), "keywords should match whole words")
+	assert.Equal(t, []string{"citrus", "pine"}, tags.Extract("PINE, and a twist of orange-peel"))
+	assert.Equal(t, []string{}, tags.Extract("A peel of orange"), "phrases should match their words in order")
+	assert.Equal(t, []string{}, tags.Extract(""))
+}
+
+func TestBeerTags_Validate(t *testing.T) {
+	tests := []struct {
+		name string
+		tags services.BeerTags
+		want string
+	}{
+		{"empty", services.BeerTags{}, "no tags"},
+		{"uppercase tag", services.BeerTags{"Chocolate": {"cocoa"}}, `tag "Chocolate" must be lowercase`},
+		{"spaced tag", services.BeerTags{"dark fruit": {"plum"}}, `tag "dark fruit" must be lowercase`},
+		{"no keywords", services.BeerTags{"sour": {}}, `tag "sour" has no keywords`},
+		{"blank keyword", services.BeerTags{"sour": {"tart", " - "}}, `tag "sour" has a blank keyword`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.tags.Validate()
+			require.ErrorIs(t, err, services.ErrInvalidBeerTags)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+	assert.NoError(t, services.BeerTags{"dark-fruit": {"plum", "dark fruit"}}.Validate())
+}
+
+func TestLoadBeerTags_Path(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tags.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"smoky": ["smoke", "peat"]}`), 0o600))
+	t.Setenv(services.BeerTagsPathEnv, path)
+
+	tags, err := services.LoadBeerTags()
+	require.NoError(t, err)
+	assert.Equal(t, services.BeerTags{"smoky": {"smoke", "peat"}}, tags)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"Smoky": ["smoke"]}`), 0o600))
+	_, err = services.LoadBeerTags()
+	require.ErrorIs(t, err, services.ErrInvalidBeerTags)
+
+	t.Setenv(services.BeerTagsPathEnv, filepath.Join(t.TempDir(), "missing.json"))
+	_, err = services.LoadBeerTags()
+	require.ErrorContains(t, err, "failed to read beer tags file")
+}
+
+func TestTagList(t *testing.T) {
+	value, err := services.TagList{"chocolate", "coffee"}.Value()
+	require.NoError(t, err)
+	assert.Equal(t, "{chocolate,coffee}", value)
+	value, err = services.TagList(nil).Value()
+	require.NoError(t, err)
+	assert.Nil(t, value, "an untagged beer should be stored as NULL")
+
+	var tags services.TagList
+	require.NoError(t, tags.Scan([]byte(`{citrus,"pine"}`)))
+	assert.Equal(t, services.TagList{"citrus", "pine"}, tags)
+	require.NoError(t, tags.Scan("{}"))
+	assert.Equal(t, services.TagList{}, tags)
+	require.NoError(t, tags.Scan(nil))
+	assert.Nil(t, tags)
+	assert.Error(t, tags.Scan(42))
+}
@@ -3,108 +3,602 @@ package services
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"iter"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/redis/go-redis/v9"
 )
 
-// BeerServiceInterface abstracts beer search for handler injection and testing.
+// BeerServiceInterface abstracts the beer catalog for handler injection and testing; see the
+// servicestest package for a fake.
 type BeerServiceInterface interface {
 	SearchBeers(ctx context.Context, query BeerSearchQuery) ([]*BeerSearchResult, error)
+	SearchBeersPage(ctx context.Context, query BeerSearchQuery) (*BeerSearchPage, error)
+	SearchBeersIter(ctx context.Context, query BeerSearchQuery) (iter.Seq2[*BeerSearchResult, error], error)
+	GetBeerByID(ctx context.Context, id int) (*BeerDetail, error)
+	GetBeerIngredients(ctx context.Context, beerID int) (*BeerIngredients, error)
+	ListBeerStats(ctx context.Context) ([]*BeerStats, error)
+	RandomBeers(ctx context.Context, style, country string, count int) ([]*BeerSearchResult, error)
+	SuggestNames(ctx context.Context, prefix string, limit int) ([]string, error)
+	CreateBeer(ctx context.Context, beer Beer) (int, error)
+	RecommendationCandidates(ctx context.Context, styles []string, excludeID int) ([]*BeerCandidate, error)
 }
 
 // BeerSearchQuery represents search parameters for beer lookup.
+// The range bounds are inclusive; a nil bound leaves that side of the range open.
 type BeerSearchQuery struct {
 	Name     string
 	Style    string
 	Brewery  string
 	Location string
-	Limit    int
+	ABVMin   *float64 // percent
+	ABVMax   *float64
+	IBUMin   *float64
+	IBUMax   *float64
+	SRMMin   *float64
+	SRMMax   *float64
+	Limit    int    // at most MaxSearchLimit; zero for no limit
+	Offset   int    // rows to skip, for paging through results
+	Sort     string // one of BeerSortKeys; SortByName by default
+	Order    string // OrderAsc (default) or OrderDesc
+	// Description matches the beers whose description mentions its words: by full-text search on
+	// PostgreSQL, so that "chocolates" matches "chocolate", and as a substring on SQLite.
+	Description string
+	// Tags are flavour tags, such as "chocolate" or "tropical", every one of which a beer must have;
+	// see BeerTags.
+	Tags []string
+	// StyleAliases are other names for Style, any of which may match instead, such as the aliases
+	// of a BJCP style searched for by code.
+	StyleAliases []string
+	// FoldDiacritics matches the text filters ignoring accents, so that "Bräu" matches "Brau".
+	FoldDiacritics bool
+	// IncludeDeleted also matches soft-deleted beers and the beers of soft-deleted breweries, which
+	// are hidden by default.
+	IncludeDeleted bool
 }
 
-// BeerSearchResult represents a beer search result.
+// descriptionDocument is the full-text document of a beer's description on PostgreSQL, which the
+// beer description index is built on.
+const descriptionDocument = "to_tsvector('english', COALESCE(b.description, ''))"
+
+// liveBeers is the condition, for queries joining beers b to breweries br, that leaves out
+// soft-deleted beers and the beers of soft-deleted breweries.
+const liveBeers = " AND b.deleted_at IS NULL AND br.deleted_at IS NULL"
+
+// HasRangeFilter reports whether any ABV, IBU, or SRM bound is set.
+func (q BeerSearchQuery) HasRangeFilter() bool {
+	for _, bound := range []*float64{q.ABVMin, q.ABVMax, q.IBUMin, q.IBUMax, q.SRMMin, q.SRMMax} {
+		if bound != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// beerRange is one numeric column filter of a beer search.
+type beerRange struct {
+	field    string // snake_case name used in validation errors
+	column   string
+	min, max *float64
+}
+
+func (q BeerSearchQuery) ranges() []beerRange {
+	return []beerRange{
+		{field: "abv", column: "b.abv", min: q.ABVMin, max: q.ABVMax},
+		{field: "ibu", column: "b.ibu", min: q.IBUMin, max: q.IBUMax},
+		{field: "srm", column: "b.srm", min: q.SRMMin, max: q.SRMMax},
+	}
+}
+
+// Validate checks that range bounds are non-negative, that each minimum does not exceed its maximum,
+// that Sort and Order are known values, that tags are well formed, and that Limit is in range.
+func (q BeerSearchQuery) Validate() error {
+	if err := validateLimit(q.Limit); err != nil {
+		return err
+	}
+	if err := validateSort(q.Sort, q.Order, BeerSortKeys); err != nil {
+		return err
+	}
+	for _, tag := range q.Tags {
+		if tag = strings.ToLower(strings.TrimSpace(tag)); tag != "" && !tagName.MatchString(tag) {
+			return fmt.Errorf("%w: tag %q must be lowercase words joined by hyphens", ErrInvalidSearchQuery, tag)
+		}
+	}
+	for _, r := range q.ranges() {
+		switch {
+		case r.min != nil && *r.min < 0:
+			return fmt.Errorf("%w: %s_min cannot be negative (got %g)", ErrInvalidSearchQuery, r.field, *r.min)
+		case r.max != nil && *r.max < 0:
+			return fmt.Errorf("%w: %s_max cannot be negative (got %g)", ErrInvalidSearchQuery, r.field, *r.max)
+		case r.min != nil && r.max != nil && *r.min > *r.max:
+			return fmt.Errorf(
+				"%w: %s_min (%g) is greater than %s_max (%g)",
+				ErrInvalidSearchQuery, r.field, *r.min, r.field, *r.max,
+			)
+		}
+	}
+	return nil
+}
+
+// BeerSearchResult represents a beer search result. City and Country are the brewery's, which tell
+// apart the beers of the same name that different breweries make.
 type BeerSearchResult struct {
 	ID      int     `json:"id"`
 	Name    string  `json:"name"`
 	Style   string  `json:"style"`
 	Brewery string  `json:"brewery"`
+	City    string  `json:"city"`
 	Country string  `json:"country"`
 	ABV     float64 `json:"abv"`
 	IBU     int     `json:"ibu"`
+	Score   float64 `json:"score,omitempty"` // name similarity 0-1, set for SortByRelevance
+}
+
+// BeerSearchPage is one page of beer search results and the number of beers matching the filters.
+type BeerSearchPage struct {
+	Items      []*BeerSearchResult `json:"items"`
+	Offset     int                 `json:"offset"`
+	TotalCount int                 `json:"total_count"`
+	HasMore    bool                `json:"has_more"`
+	Sort       string              `json:"sort"` // the ordering applied, which may fall back to SortByName
+	Order      string              `json:"order"`
+}
+
+// BeerDetail is a full beer record with the brewery that makes it.
+type BeerDetail struct {
+	ID           int       `db:"id"            json:"id"`
+	Name         string    `db:"name"          json:"name"`
+	Style        string    `db:"style"         json:"style"`
+	ABV          float64   `db:"abv"           json:"abv"`
+	IBU          int       `db:"ibu"           json:"ibu"`
+	SRM          float64   `db:"srm"           json:"srm"`
+	Description  string    `db:"description"   json:"description"`
+	Tags         TagList   `db:"tags"          json:"tags,omitempty"` // flavour tags from the description
+	BreweryID    int       `db:"brewery_id"    json:"brewery_id"`
+	Brewery      string    `db:"brewery"       json:"brewery"`
+	BreweryCity  string    `db:"brewery_city"  json:"brewery_city"`
+	BreweryState string    `db:"brewery_state" json:"brewery_state"`
+	Country      string    `db:"country"       json:"country"`
+	CreatedAt    time.Time `db:"created_at"    json:"created_at"`
+	UpdatedAt    time.Time `db:"updated_at"    json:"updated_at"`
 }
 
+// BeerStats is a beer's declared style and stored stats. A zero stat was not recorded.
+type BeerStats struct {
+	ID      int     `db:"id"      json:"id"`
+	Name    string  `db:"name"    json:"name"`
+	Brewery string  `db:"brewery" json:"brewery"`
+	Style   string  `db:"style"   json:"style"`
+	ABV     float64 `db:"abv"     json:"abv"`
+	IBU     int     `db:"ibu"     json:"ibu"`
+	SRM     float64 `db:"srm"     json:"srm"`
+}
+
+// ErrInvalidSearchQuery is wrapped by errors for search queries that can never match, such as a
+// range whose minimum exceeds its maximum.
+var ErrInvalidSearchQuery = errors.New("invalid search query")
+
+// beerCachePrefix namespaces beer search results in Redis.
+const beerCachePrefix = "brewsource:search:beers:"
+
 // BeerService handles beer-related operations.
 type BeerService struct {
-	db          *sqlx.DB
-	redisClient *redis.Client // Optional caching
+	db         *sqlDB
+	cache      *searchCache // Optional caching; nil-safe
+	statsCache *searchCache // Brewery statistics, only invalidated here since they count beers
+	relevance  bool         // pg_trgm is installed, so SortByRelevance can rank by similarity
+	queries    *queryGuard  // Query timeout and slow query logging; nil-safe
 }
 
-// NewBeerService creates a new BeerService instance.
+// NewBeerService creates a new BeerService instance. Search results are cached in Redis for
+// DefaultCacheTTL when redisClient is not nil.
 func NewBeerService(db *sqlx.DB, redisClient *redis.Client) *BeerService {
 	return &BeerService{
-		db:          db,
-		redisClient: redisClient,
+		db:         newSQLDB(db),
+		cache:      newSearchCache(redisClient, beerCachePrefix),
+		statsCache: newSearchCache(redisClient, statsCachePrefix),
+		queries:    newQueryGuard(),
 	}
 }
 
+// EnableRelevanceSearch turns on SortByRelevance. Enable it only when the pg_trgm extension is
+// installed; while disabled, relevance searches are sorted by name.
+func (s *BeerService) EnableRelevanceSearch(enabled bool) {
+	s.relevance = enabled
+}
+
+// SetCacheTTL changes how long new search results stay cached. Non-positive values are ignored.
+func (s *BeerService) SetCacheTTL(ttl time.Duration) {
+	s.cache.setTTL(ttl)
+}
+
+// CacheStats returns the search cache hit and miss counts.
+func (s *BeerService) CacheStats() CacheStats {
+	return s.cache.stats()
+}
+
+// InvalidateBeerCache drops all cached beer searches. Call it after beers or breweries change.
+func (s *BeerService) InvalidateBeerCache(ctx context.Context) error {
+	return s.cache.invalidate(ctx)
+}
+
 // SearchBeers performs a search for beers based on the provided criteria.
-// Requires a *sqlx.DB to be available (add as a field to BeerService if needed).
+// It returns a single page without counting the total matches; use SearchBeersPage for paging.
+// A beer is matched once per brewery: of the beers a brewery has under the same name, ignoring
+// case, such as a beer imported twice, only the earliest added is returned.
 func (s *BeerService) SearchBeers(ctx context.Context, query BeerSearchQuery) ([]*BeerSearchResult, error) {
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+	query = s.normalizeBeerQuery(query)
+	key := s.cache.key("list", beerCacheQuery(query))
+	var cached []*BeerSearchResult
+	if s.cache.get(ctx, key, &cached) {
+		return cached, nil
+	}
+
+	results, err := s.selectBeers(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	s.cache.set(ctx, key, results)
+	return results, nil
+}
+
+// SearchBeersPage returns the page of beers selected by query.Limit and query.Offset, along with
+// the total number of beers matching the filters.
+func (s *BeerService) SearchBeersPage(ctx context.Context, query BeerSearchQuery) (*BeerSearchPage, error) {
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+	query = s.normalizeBeerQuery(query)
+	key := s.cache.key("page", beerCacheQuery(query))
+	var cached BeerSearchPage
+	if s.cache.get(ctx, key, &cached) {
+		return &cached, nil
+	}
+
+	page, err := s.countAndSelectBeers(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	s.cache.set(ctx, key, page)
+	return page, nil
+}
+
+// SearchBeersIter returns an iterator over the beers matching query, running the search when the
+// iterator is used and scanning each row as it is yielded, so that large result sets are never held
+// in memory. Limit may exceed MaxSearchLimit, and zero returns every match. Results are not cached.
+// A failed query or scan is yielded once, as the last pair, with a nil result; stopping early closes
+// the rows.
+func (s *BeerService) SearchBeersIter(
+	ctx context.Context,
+	query BeerSearchQuery,
+) (iter.Seq2[*BeerSearchResult, error], error) {
+	limit := query.Limit
+	query.Limit = 0
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+	if err := validateIterLimit(limit); err != nil {
+		return nil, err
+	}
+	query = s.normalizeBeerQuery(query)
+	query.Limit = limit
+	return s.beerRows(ctx, query), nil
+}
+
+// normalizeBeerQuery tidies the whitespace in the text filters, lowercases and sorts the tags,
+// treats a negative offset as zero, and resolves Sort and Order to the ordering that will be applied.
+func (s *BeerService) normalizeBeerQuery(query BeerSearchQuery) BeerSearchQuery {
+	normalizeSearchFields(query.FoldDiacritics, &query.Name, &query.Style, &query.Brewery, &query.Location)
+	normalizeSearchFields(false, &query.Description)
+	if tags := lowerAll(normalizeSearchValues(false, query.Tags)); len(tags) > 0 {
+		slices.Sort(tags)
+		query.Tags = slices.Compact(tags)
+	} else {
+		query.Tags = nil
+	}
+	query.StyleAliases = normalizeSearchValues(query.FoldDiacritics, query.StyleAliases)
+	query.Sort, query.Order = resolveSort(s.relevance, query.Sort, query.Order, query.Name)
+	if query.Offset < 0 {
+		query.Offset = 0
+	}
+	return query
+}
+
+// beerCacheQuery lowercases the filters so that searches differing only in case share a cache
+// entry; filters are matched with ILIKE, so case does not change the results.
+func beerCacheQuery(query BeerSearchQuery) BeerSearchQuery {
+	query.Name = strings.ToLower(query.Name)
+	query.Style = strings.ToLower(query.Style)
+	query.StyleAliases = lowerAll(query.StyleAliases)
+	query.Brewery = strings.ToLower(query.Brewery)
+	query.Location = strings.ToLower(query.Location)
+	query.Description = strings.ToLower(query.Description)
+	return query
+}
+
+// GetBeerByID returns the beer with the given ID. It returns a *NotFoundError, matching
+// ErrNotFound, when no beer has that ID or the beer or its brewery is soft-deleted.
+func (s *BeerService) GetBeerByID(ctx context.Context, id int) (_ *BeerDetail, err error) {
+	q := `
+		  SELECT b.id, b.name, COALESCE(b.style, '') AS style, COALESCE(b.abv, 0) AS abv,
+		         COALESCE(b.ibu, 0) AS ibu, COALESCE(b.srm, 0) AS srm,
+		         COALESCE(b.description, '') AS description, b.tags, b.brewery_id, br.name AS brewery,
+		         COALESCE(br.city, '') AS brewery_city, COALESCE(br.state, '') AS brewery_state,
+		         COALESCE(br.country, '') AS country, b.created_at, b.updated_at
+		  FROM beers b
+		  JOIN breweries br ON b.brewery_id = br.id
+		  WHERE b.id = $1` + liveBeers
+
+	ctx, finish := s.queries.begin(ctx, "get_beer", q, id)
+	defer finish(&err)
+
+	var beer BeerDetail
+	err = s.queries.retry(ctx, "get_beer", func() error { return s.db.GetContext(ctx, &beer, q, id) })
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, &NotFoundError{Kind: "beer", ID: id}
+		}
+		return nil, fmt.Errorf("failed to get beer %d: %w", id, err)
+	}
+	return &beer, nil
+}
+
+// ListBeerStats returns the style and stats of every beer that declares a style, ordered by id,
+// leaving out soft-deleted beers and breweries.
+func (s *BeerService) ListBeerStats(ctx context.Context) (_ []*BeerStats, err error) {
 	q := `
-		  SELECT b.id, b.name, b.style, br.name as brewery, br.country, b.abv, b.ibu
+		  SELECT b.id, b.name, br.name AS brewery, b.style, COALESCE(b.abv, 0) AS abv,
+		         COALESCE(b.ibu, 0) AS ibu, COALESCE(b.srm, 0) AS srm
+		  FROM beers b
+		  JOIN breweries br ON b.brewery_id = br.id
+		  WHERE COALESCE(b.style, '') <> ''` + liveBeers + `
+		  ORDER BY b.id`
+
+	ctx, finish := s.queries.begin(ctx, "list_beer_stats", q, nil)
+	defer finish(&err)
+
+	var stats []*BeerStats
+	err = s.queries.retry(ctx, "list_beer_stats", func() error {
+		stats = []*BeerStats{}
+		return s.db.SelectContext(ctx, &stats, q)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list beer stats: %w", err)
+	}
+	return stats, nil
+}
+
+// countAndSelectBeers counts the beers matching the filters and selects the requested page.
+func (s *BeerService) countAndSelectBeers(ctx context.Context, query BeerSearchQuery) (*BeerSearchPage, error) {
+	filters, args := beerSearchFilters(query, s.db.sqlite)
+	countQuery := `
+		  SELECT COUNT(*)
 		  FROM beers b
 		  JOIN breweries br ON b.brewery_id = br.id
-		  WHERE 1=1
-	  `
+		  WHERE 1=1` + filters
+
+	page := &BeerSearchPage{
+		Items:  []*BeerSearchResult{},
+		Offset: query.Offset,
+		Sort:   query.Sort,
+		Order:  query.Order,
+	}
+	if err := s.countBeers(ctx, countQuery, args, query, &page.TotalCount); err != nil {
+		return nil, err
+	}
+	if query.Offset >= page.TotalCount {
+		return page, nil
+	}
+
+	items, err := s.selectBeers(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	page.Items = items
+	page.HasMore = query.Offset+len(items) < page.TotalCount
+	return page, nil
+}
+
+// countBeers runs the count query of a beer search.
+func (s *BeerService) countBeers(
+	ctx context.Context,
+	countQuery string,
+	args []interface{},
+	query BeerSearchQuery,
+	count *int,
+) (err error) {
+	ctx, finish := s.queries.begin(ctx, "count_beers", countQuery, query)
+	defer finish(&err)
+	return s.queries.retry(ctx, "count_beers", func() error { return s.db.GetContext(ctx, count, countQuery, args...) })
+}
+
+// beerSearchFilters builds the WHERE conditions for a beer search, numbering arguments from $1,
+// searching descriptions and matching tags as the database allows. Soft-deleted beers and
+// breweries are left out unless the query includes them, and so are the duplicates of a beer: the
+// later beers of its brewery with the same name, ignoring case.
+func beerSearchFilters(query BeerSearchQuery, sqlite bool) (string, []interface{}) {
+	filters := ""
+	if !query.IncludeDeleted {
+		filters = liveBeers
+	}
 	args := []interface{}{}
 	argIdx := 1
 
+	column := func(name string) string {
+		if query.FoldDiacritics {
+			return foldedColumn(name)
+		}
+		return name
+	}
 	if query.Name != "" {
-		q += " AND b.name ILIKE $" + strconv.Itoa(argIdx)
+		filters += " AND " + column("b.name") + " ILIKE $" + strconv.Itoa(argIdx)
 		args = append(args, "%"+query.Name+"%")
 		argIdx++
 	}
+	styles := query.StyleAliases
 	if query.Style != "" {
-		q += " AND b.style ILIKE $" + strconv.Itoa(argIdx)
-		args = append(args, "%"+query.Style+"%")
-		argIdx++
+		styles = append([]string{query.Style}, styles...)
+	}
+	if len(styles) > 0 {
+		matches := make([]string, len(styles))
+		for i, style := range styles {
+			matches[i] = column("b.style") + " ILIKE $" + strconv.Itoa(argIdx)
+			args = append(args, "%"+style+"%")
+			argIdx++
+		}
+		if len(matches) == 1 {
+			filters += " AND " + matches[0]
+		} else {
+			filters += " AND (" + strings.Join(matches, " OR ") + ")"
+		}
 	}
 	if query.Brewery != "" {
-		q += " AND br.name ILIKE $" + strconv.Itoa(argIdx)
+		filters += " AND " + column("br.name") + " ILIKE $" + strconv.Itoa(argIdx)
 		args = append(args, "%"+query.Brewery+"%")
 		argIdx++
 	}
 	if query.Location != "" {
-		q += " AND br.city ILIKE $" + strconv.Itoa(argIdx)
+		filters += " AND " + column("br.city") + " ILIKE $" + strconv.Itoa(argIdx)
 		args = append(args, "%"+query.Location+"%")
 		argIdx++
 	}
+	if query.Description != "" {
+		if sqlite {
+			filters += " AND b.description ILIKE $" + strconv.Itoa(argIdx)
+			args = append(args, "%"+query.Description+"%")
+		} else {
+			filters += " AND " + descriptionDocument + " @@ plainto_tsquery('english', $" + strconv.Itoa(argIdx) + ")"
+			args = append(args, query.Description)
+		}
+		argIdx++
+	}
+	for _, tag := range query.Tags {
+		// SQLite holds the tags as an array literal, in which each tag is delimited by commas once
+		// its braces become commas
+		if sqlite {
+			filters += " AND REPLACE(REPLACE(b.tags, '{', ','), '}', ',') LIKE $" + strconv.Itoa(argIdx)
+			args = append(args, "%,"+tag+",%")
+		} else {
+			filters += " AND b.tags @> ARRAY[$" + strconv.Itoa(argIdx) + "::text]"
+			args = append(args, tag)
+		}
+		argIdx++
+	}
+	for _, r := range query.ranges() {
+		if r.min != nil {
+			filters += " AND " + r.column + " >= $" + strconv.Itoa(argIdx)
+			args = append(args, *r.min)
+			argIdx++
+		}
+		if r.max != nil {
+			filters += " AND " + r.column + " <= $" + strconv.Itoa(argIdx)
+			args = append(args, *r.max)
+			argIdx++
+		}
+	}
+	duplicates := " AND NOT EXISTS (SELECT 1 FROM beers d WHERE d.brewery_id = b.brewery_id" +
+		" AND LOWER(d.name) = LOWER(b.name) AND d.id < b.id"
+	if !query.IncludeDeleted {
+		duplicates += " AND d.deleted_at IS NULL"
+	}
+	filters += duplicates + ")"
+	return filters, args
+}
+
+// selectBeers runs a normalized beer search and collects the results.
+func (s *BeerService) selectBeers(ctx context.Context, query BeerSearchQuery) ([]*BeerSearchResult, error) {
+	results := []*BeerSearchResult{}
+	for r, err := range s.beerRows(ctx, query) {
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, r)
+	}
+	return results, nil
+}
+
+// beerRows runs a normalized beer search when iterated, yielding each result as it is scanned. A
+// failed query or scan is yielded once, as the last pair, with a nil result.
+func (s *BeerService) beerRows(ctx context.Context, query BeerSearchQuery) iter.Seq2[*BeerSearchResult, error] {
+	return func(yield func(*BeerSearchResult, error) bool) {
+		if err := s.scanBeers(ctx, query, yield); err != nil {
+			yield(nil, err)
+		}
+	}
+}
+
+// scanBeers runs a normalized beer search, passing each result to yield until it returns false.
+// Results are ordered by the Sort column in the Order direction, or by similarity to the name filter
+// for SortByRelevance, with ties broken by name and id so that pages are stable.
+func (s *BeerService) scanBeers(
+	ctx context.Context,
+	query BeerSearchQuery,
+	yield func(*BeerSearchResult, error) bool,
+) (err error) {
+	filters, args := beerSearchFilters(query, s.db.sqlite)
+	relevance := query.Sort == SortByRelevance
+	columns, order := "", orderBy("b.name", query.Order, "b.id")
+	switch {
+	case relevance:
+		args = append(args, query.Name)
+		columns = ", similarity(b.name, $" + strconv.Itoa(len(args)) + ") AS score"
+		order = "score DESC, b.name, b.id"
+	case query.Sort != SortByName:
+		order = orderBy(beerSortColumns[query.Sort], query.Order, "b.name", "b.id")
+	}
+	q := `
+		  SELECT b.id, b.name, b.style, br.name as brewery, COALESCE(br.city, '') AS city,
+		         COALESCE(br.country, '') AS country, b.abv, b.ibu` + columns + `
+		  FROM beers b
+		  JOIN breweries br ON b.brewery_id = br.id
+		  WHERE 1=1` + filters + `
+		  ORDER BY ` + order
+
 	if query.Limit > 0 {
-		q += " LIMIT $" + strconv.Itoa(argIdx)
 		args = append(args, query.Limit)
+		q += " LIMIT $" + strconv.Itoa(len(args))
+	}
+	if query.Offset > 0 {
+		args = append(args, query.Offset)
+		q += " OFFSET $" + strconv.Itoa(len(args))
 	}
 
-	rows, err := s.db.QueryxContext(ctx, q, args...)
+	ctx, finish := s.queries.begin(ctx, "search_beers", q, query)
+	defer finish(&err)
+
+	var rows *sqlx.Rows
+	err = s.queries.retry(ctx, "search_beers", func() (err error) {
+		rows, err = s.db.QueryxContext(ctx, q, args...)
+		return err
+	})
 	if err != nil {
-		return nil, err
+		return err
 	}
-	defer func() {
-		if rows != nil {
-			_ = rows.Close()
-		}
-	}()
+	// Closing the rows also releases the connection when the caller stops iterating early.
+	defer rows.Close()
 
-	results := []*BeerSearchResult{}
 	for rows.Next() {
 		var r BeerSearchResult
-		if scanErr := rows.Scan(&r.ID, &r.Name, &r.Style, &r.Brewery, &r.Country, &r.ABV, &r.IBU); scanErr != nil {
-			return nil, scanErr
+		dest := []interface{}{&r.ID, &r.Name, &r.Style, &r.Brewery, &r.City, &r.Country, &r.ABV, &r.IBU}
+		if relevance {
+			dest = append(dest, &r.Score)
+		}
+		if err = rows.Scan(dest...); err != nil {
+			return err
+		}
+		if !yield(&r, nil) {
+			return nil
 		}
-		results = append(results, &r)
-	}
-	if rows.Err() != nil {
-		return nil, rows.Err()
 	}
-	return results, nil
+	return rows.Err()
 }
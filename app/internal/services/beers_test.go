@@ -26,12 +26,17 @@ const (
 // getMockBeerRows returns mock data for testing.
 func getMockBeerRows() [][]driver.Value {
 	return [][]driver.Value{
-		{1, "King's Blockhouse IPA", "American IPA", "Devil's Peak Brewing Company", "South Africa", 6.0, 60},
-		{2, "Hazy Pale Ale", "American Pale Ale", "Jack Black Brewing Co", "South Africa", 5.0, 35},
-		{3, "Lager", "Pilsner", "Castle Lager", "South Africa", 4.5, 20},
+		{1, "King's Blockhouse IPA", "American IPA", "Devil's Peak Brewing Company", "Cape Town", "South Africa", 6.0, 60},
+		{2, "Hazy Pale Ale", "American Pale Ale", "Jack Black Brewing Co", "Cape Town", "South Africa", 5.0, 35},
+		{3, "Lager", "Pilsner", "Castle Lager", "Johannesburg", "South Africa", 4.5, 20},
 	}
 }
 
+// distinctBeers matches the condition of beer searches that leaves out the later beers of a brewery
+// with the same name.
+const distinctBeers = `\s+AND NOT EXISTS \(SELECT 1 FROM beers d WHERE d\.brewery_id = b\.brewery_id` +
+	` AND LOWER\(d\.name\) = LOWER\(b\.name\) AND d\.id < b\.id AND d\.deleted_at IS NULL\)`
+
 func setupMockDB(t *testing.T) (*sqlx.DB, sqlmock.Sqlmock) {
 	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
 	require.NoError(t, err)
@@ -40,8 +45,8 @@ func setupMockDB(t *testing.T) (*sqlx.DB, sqlmock.Sqlmock) {
 }
 
 func setupBeerService(db *sqlx.DB) *services.BeerService {
-	redisClient := &redis.Client{} // Mock Redis client
-	return services.NewBeerService(db, redisClient)
+	// Caching is covered in cache_test.go
+	return services.NewBeerService(db, nil)
 }
 
 // Test services.NewBeerService constructor.
@@ -105,6 +110,7 @@ func TestBeerSearchResult_Fields(t *testing.T) {
 			Name:    "King's Blockhouse IPA",
 			Style:   "American IPA",
 			Brewery: "Devil's Peak Brewing Company",
+			City:    "Cape Town",
 			Country: "South Africa",
 		}
 
@@ -112,6 +118,7 @@ func TestBeerSearchResult_Fields(t *testing.T) {
 		assert.Equal(t, "King's Blockhouse IPA", r.Name)
 		assert.Equal(t, "American IPA", r.Style)
 		assert.Equal(t, "Devil's Peak Brewing Company", r.Brewery)
+		assert.Equal(t, "Cape Town", r.City)
 		assert.Equal(t, "South Africa", r.Country)
 	})
 }
@@ -123,9 +130,9 @@ func TestSearchBeers_HappyPath(t *testing.T) {
 		defer db.Close()
 		svc := setupBeerService(db)
 
-		expectedQuery := `SELECT b.id, b.name, b.style, br.name as brewery, br.country, b.abv, b.ibu\s+FROM beers b\s+JOIN breweries br ON b.brewery_id = br.id\s+WHERE 1=1\s+AND b.name ILIKE \$1`
+		expectedQuery := `SELECT b.id, b.name, b.style, br.name as brewery, COALESCE\(br.city, ''\) AS city,\s+COALESCE\(br.country, ''\) AS country, b.abv, b.ibu\s+FROM beers b\s+JOIN breweries br ON b.brewery_id = br.id\s+WHERE 1=1 AND b\.deleted_at IS NULL AND br\.deleted_at IS NULL\s+AND b.name ILIKE \$1`
 
-		rows := sqlmock.NewRows([]string{"id", "name", "style", "brewery", "country", "abv", "ibu"}).
+		rows := sqlmock.NewRows([]string{"id", "name", "style", "brewery", "city", "country", "abv", "ibu"}).
 			AddRow(getMockBeerRows()[0]...).
 			AddRow(getMockBeerRows()[1]...)
 
@@ -148,9 +155,9 @@ func TestSearchBeers_HappyPath(t *testing.T) {
 		defer db.Close()
 		svc := setupBeerService(db)
 
-		expectedQuery := `SELECT b.id, b.name, b.style, br.name as brewery, br.country, b.abv, b.ibu\s+FROM beers b\s+JOIN breweries br ON b.brewery_id = br.id\s+WHERE 1=1\s+AND b.name ILIKE \$1\s+AND b.style ILIKE \$2\s+AND br.name ILIKE \$3\s+AND br.city ILIKE \$4\s+LIMIT \$5`
+		expectedQuery := `SELECT b.id, b.name, b.style, br.name as brewery, COALESCE\(br.city, ''\) AS city,\s+COALESCE\(br.country, ''\) AS country, b.abv, b.ibu\s+FROM beers b\s+JOIN breweries br ON b.brewery_id = br.id\s+WHERE 1=1 AND b\.deleted_at IS NULL AND br\.deleted_at IS NULL\s+AND b.name ILIKE \$1\s+AND b.style ILIKE \$2\s+AND br.name ILIKE \$3\s+AND br.city ILIKE \$4` + distinctBeers + `\s+ORDER BY b.name, b.id\s+LIMIT \$5`
 
-		rows := sqlmock.NewRows([]string{"id", "name", "style", "brewery", "country", "abv", "ibu"}).
+		rows := sqlmock.NewRows([]string{"id", "name", "style", "brewery", "city", "country", "abv", "ibu"}).
 			AddRow(getMockBeerRows()[0]...)
 
 		mock.ExpectQuery(expectedQuery).
@@ -177,9 +184,9 @@ func TestSearchBeers_HappyPath(t *testing.T) {
 		defer db.Close()
 		svc := setupBeerService(db)
 
-		expectedQuery := `SELECT b.id, b.name, b.style, br.name as brewery, br.country, b.abv, b.ibu\s+FROM beers b\s+JOIN breweries br ON b.brewery_id = br.id\s+WHERE 1=1\s+LIMIT \$1`
+		expectedQuery := `SELECT b.id, b.name, b.style, br.name as brewery, COALESCE\(br.city, ''\) AS city,\s+COALESCE\(br.country, ''\) AS country, b.abv, b.ibu\s+FROM beers b\s+JOIN breweries br ON b.brewery_id = br.id\s+WHERE 1=1 AND b\.deleted_at IS NULL AND br\.deleted_at IS NULL` + distinctBeers + `\s+ORDER BY b.name, b.id\s+LIMIT \$1`
 
-		rows := sqlmock.NewRows([]string{"id", "name", "style", "brewery", "country", "abv", "ibu"})
+		rows := sqlmock.NewRows([]string{"id", "name", "style", "brewery", "city", "country", "abv", "ibu"})
 		for i := range 3 {
 			rows.AddRow(getMockBeerRows()[i]...)
 		}
@@ -201,9 +208,9 @@ func TestSearchBeers_HappyPath(t *testing.T) {
 		defer db.Close()
 		svc := setupBeerService(db)
 
-		expectedQuery := `SELECT b.id, b.name, b.style, br.name as brewery, br.country, b.abv, b.ibu\s+FROM beers b\s+JOIN breweries br ON b.brewery_id = br.id\s+WHERE 1=1\s+AND b.name ILIKE \$1`
+		expectedQuery := `SELECT b.id, b.name, b.style, br.name as brewery, COALESCE\(br.city, ''\) AS city,\s+COALESCE\(br.country, ''\) AS country, b.abv, b.ibu\s+FROM beers b\s+JOIN breweries br ON b.brewery_id = br.id\s+WHERE 1=1 AND b\.deleted_at IS NULL AND br\.deleted_at IS NULL\s+AND b.name ILIKE \$1`
 
-		rows := sqlmock.NewRows([]string{"id", "name", "style", "brewery", "country", "abv", "ibu"})
+		rows := sqlmock.NewRows([]string{"id", "name", "style", "brewery", "city", "country", "abv", "ibu"})
 
 		mock.ExpectQuery(expectedQuery).
 			WithArgs("%NONEXISTENT%").
@@ -226,9 +233,9 @@ func TestSearchBeers_EdgeCases(t *testing.T) {
 		defer db.Close()
 		svc := setupBeerService(db)
 
-		expectedQuery := `SELECT b.id, b.name, b.style, br.name as brewery, br.country, b.abv, b.ibu\s+FROM beers b\s+JOIN breweries br ON b.brewery_id = br.id\s+WHERE 1=1`
+		expectedQuery := `SELECT b.id, b.name, b.style, br.name as brewery, COALESCE\(br.city, ''\) AS city,\s+COALESCE\(br.country, ''\) AS country, b.abv, b.ibu\s+FROM beers b\s+JOIN breweries br ON b.brewery_id = br.id\s+WHERE 1=1 AND b\.deleted_at IS NULL AND br\.deleted_at IS NULL`
 
-		rows := sqlmock.NewRows([]string{"id", "name", "style", "brewery", "country", "abv", "ibu"})
+		rows := sqlmock.NewRows([]string{"id", "name", "style", "brewery", "city", "country", "abv", "ibu"})
 
 		mock.ExpectQuery(expectedQuery).
 			WithArgs().
@@ -248,49 +255,17 @@ func TestSearchBeers_EdgeCases(t *testing.T) {
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
-	t.Run("Very large limit", func(t *testing.T) {
-		db, mock := setupMockDB(t)
-		defer db.Close()
-		svc := setupBeerService(db)
-
-		expectedQuery := `SELECT b.id, b.name, b.style, br.name as brewery, br.country, b.abv, b.ibu\s+FROM beers b\s+JOIN breweries br ON b.brewery_id = br.id\s+WHERE 1=1\s+LIMIT \$1`
-
-		rows := sqlmock.NewRows([]string{"id", "name", "style", "brewery", "country", "abv", "ibu"}).
-			AddRow(getMockBeerRows()[0]...)
-
-		mock.ExpectQuery(expectedQuery).
-			WithArgs(999999).
-			WillReturnRows(rows)
-
-		query := services.BeerSearchQuery{Limit: 999999}
-		results, err := svc.SearchBeers(context.Background(), query)
-
-		require.NoError(t, err)
-		assert.Len(t, results, 1)
-		assert.NoError(t, mock.ExpectationsWereMet())
-	})
-
-	t.Run("Negative limit", func(t *testing.T) {
+	t.Run("Out-of-range limits", func(t *testing.T) {
 		db, mock := setupMockDB(t)
 		defer db.Close()
 		svc := setupBeerService(db)
 
-		// Negative limit should not add LIMIT clause
-		expectedQuery := `SELECT b.id, b.name, b.style, br.name as brewery, br.country, b.abv, b.ibu\s+FROM beers b\s+JOIN breweries br ON b.brewery_id = br.id\s+WHERE 1=1`
-
-		rows := sqlmock.NewRows([]string{"id", "name", "style", "brewery", "country", "abv", "ibu"}).
-			AddRow(getMockBeerRows()[0]...)
-
-		mock.ExpectQuery(expectedQuery).
-			WithArgs().
-			WillReturnRows(rows)
-
-		query := services.BeerSearchQuery{Limit: -5}
-		results, err := svc.SearchBeers(context.Background(), query)
-
-		require.NoError(t, err)
-		assert.Len(t, results, 1)
-		assert.NoError(t, mock.ExpectationsWereMet())
+		for _, limit := range []int{-5, services.MaxSearchLimit + 1, 999999} {
+			_, err := svc.SearchBeers(context.Background(), services.BeerSearchQuery{Limit: limit})
+			require.ErrorIs(t, err, services.ErrInvalidLimit)
+			assert.Contains(t, err.Error(), "limit must be between 1 and 100")
+		}
+		assert.NoError(t, mock.ExpectationsWereMet(), "out-of-range limits should not reach the database")
 	})
 
 	t.Run("Unicode and special characters", func(t *testing.T) {
@@ -298,10 +273,10 @@ func TestSearchBeers_EdgeCases(t *testing.T) {
 		defer db.Close()
 		svc := setupBeerService(db)
 
-		expectedQuery := `SELECT b.id, b.name, b.style, br.name as brewery, br.country, b.abv, b.ibu\s+FROM beers b\s+JOIN breweries br ON b.brewery_id = br.id\s+WHERE 1=1\s+AND b.name ILIKE \$1`
+		expectedQuery := `SELECT b.id, b.name, b.style, br.name as brewery, COALESCE\(br.city, ''\) AS city,\s+COALESCE\(br.country, ''\) AS country, b.abv, b.ibu\s+FROM beers b\s+JOIN breweries br ON b.brewery_id = br.id\s+WHERE 1=1 AND b\.deleted_at IS NULL AND br\.deleted_at IS NULL\s+AND b.name ILIKE \$1`
 
-		rows := sqlmock.NewRows([]string{"id", "name", "style", "brewery", "country", "abv", "ibu"}).
-			AddRow(1, "Øl & Bière", "Lager", "Brewery café", "Norway", 5.0, 25)
+		rows := sqlmock.NewRows([]string{"id", "name", "style", "brewery", "city", "country", "abv", "ibu"}).
+			AddRow(1, "Øl & Bière", "Lager", "Brewery café", "", "Norway", 5.0, 25)
 
 		mock.ExpectQuery(expectedQuery).
 			WithArgs("%Øl & Bière%").
@@ -326,9 +301,9 @@ func TestSearchBeers_EdgeCases(t *testing.T) {
 			longString = longString[:i] + "a" + longString[i+1:]
 		}
 
-		expectedQuery := `SELECT b.id, b.name, b.style, br.name as brewery, br.country, b.abv, b.ibu\s+FROM beers b\s+JOIN breweries br ON b.brewery_id = br.id\s+WHERE 1=1\s+AND b.name ILIKE \$1`
+		expectedQuery := `SELECT b.id, b.name, b.style, br.name as brewery, COALESCE\(br.city, ''\) AS city,\s+COALESCE\(br.country, ''\) AS country, b.abv, b.ibu\s+FROM beers b\s+JOIN breweries br ON b.brewery_id = br.id\s+WHERE 1=1 AND b\.deleted_at IS NULL AND br\.deleted_at IS NULL\s+AND b.name ILIKE \$1`
 
-		rows := sqlmock.NewRows([]string{"id", "name", "style", "brewery", "country", "abv", "ibu"})
+		rows := sqlmock.NewRows([]string{"id", "name", "style", "brewery", "city", "country", "abv", "ibu"})
 
 		mock.ExpectQuery(expectedQuery).
 			WithArgs("%" + longString + "%").
@@ -349,8 +324,9 @@ func TestSearchBeers_ErrorHandling(t *testing.T) {
 		db, mock := setupMockDB(t)
 		defer db.Close()
 		svc := setupBeerService(db)
+		svc.SetQueryRetries(1, 0)
 
-		expectedQuery := `SELECT b.id, b.name, b.style, br.name as brewery, br.country, b.abv, b.ibu\s+FROM beers b\s+JOIN breweries br ON b.brewery_id = br.id\s+WHERE 1=1\s+AND b.name ILIKE \$1`
+		expectedQuery := `SELECT b.id, b.name, b.style, br.name as brewery, COALESCE\(br.city, ''\) AS city,\s+COALESCE\(br.country, ''\) AS country, b.abv, b.ibu\s+FROM beers b\s+JOIN breweries br ON b.brewery_id = br.id\s+WHERE 1=1 AND b\.deleted_at IS NULL AND br\.deleted_at IS NULL\s+AND b.name ILIKE \$1`
 
 		mock.ExpectQuery(expectedQuery).
 			WithArgs("%IPA%").
@@ -370,7 +346,7 @@ func TestSearchBeers_ErrorHandling(t *testing.T) {
 		defer db.Close()
 		svc := setupBeerService(db)
 
-		expectedQuery := `SELECT b\.id, b\.name, b\.style, br\.name as brewery, br\.country, b\.abv, b\.ibu\s+FROM beers b\s+JOIN breweries br ON b\.brewery_id = br\.id\s+WHERE 1=1\s+AND b\.name ILIKE \$1`
+		expectedQuery := `SELECT b\.id, b\.name, b\.style, br\.name as brewery, COALESCE\(br\.city, ''\) AS city,\s+COALESCE\(br\.country, ''\) AS country, b\.abv, b\.ibu\s+FROM beers b\s+JOIN breweries br ON b\.brewery_id = br\.id\s+WHERE 1=1 AND b\.deleted_at IS NULL AND br\.deleted_at IS NULL\s+AND b\.name ILIKE \$1`
 
 		// Return wrong number of columns to trigger scan error
 		rows := sqlmock.NewRows([]string{"id", "name", "style"}).
@@ -393,11 +369,11 @@ func TestSearchBeers_ErrorHandling(t *testing.T) {
 		defer db.Close()
 		svc := setupBeerService(db)
 
-		expectedQuery := `SELECT b\.id, b\.name, b\.style, br\.name as brewery, br\.country, b\.abv, b\.ibu\s+FROM beers b\s+JOIN breweries br ON b\.brewery_id = br\.id\s+WHERE 1=1\s+AND b\.name ILIKE \$1`
+		expectedQuery := `SELECT b\.id, b\.name, b\.style, br\.name as brewery, COALESCE\(br\.city, ''\) AS city,\s+COALESCE\(br\.country, ''\) AS country, b\.abv, b\.ibu\s+FROM beers b\s+JOIN breweries br ON b\.brewery_id = br\.id\s+WHERE 1=1 AND b\.deleted_at IS NULL AND br\.deleted_at IS NULL\s+AND b\.name ILIKE \$1`
 
-		rows := sqlmock.NewRows([]string{"id", "name", "style", "brewery", "country", "abv", "ibu"}).
+		rows := sqlmock.NewRows([]string{"id", "name", "style", "brewery", "city", "country", "abv", "ibu"}).
 			AddRow(getMockBeerRows()[0]...).
-			AddRow(1, "Second Beer", "IPA", "Test Brewery", "USA", 5.5, 45).
+			AddRow(1, "Second Beer", "IPA", "Test Brewery", "", "USA", 5.5, 45).
 			RowError(1, errors.New("row iteration error"))
 
 		mock.ExpectQuery(expectedQuery).
@@ -458,12 +434,12 @@ func TestSearchBeers_Performance(t *testing.T) {
 		defer db.Close()
 		svc := setupBeerService(db)
 
-		expectedQuery := `SELECT b\.id, b\.name, b\.style, br\.name as brewery, br\.country, b\.abv, b\.ibu\s+FROM beers b\s+JOIN breweries br ON b\.brewery_id = br\.id\s+WHERE 1=1\s+LIMIT \$1`
+		expectedQuery := `SELECT b\.id, b\.name, b\.style, br\.name as brewery, COALESCE\(br\.city, ''\) AS city,\s+COALESCE\(br\.country, ''\) AS country, b\.abv, b\.ibu\s+FROM beers b\s+JOIN breweries br ON b\.brewery_id = br\.id\s+WHERE 1=1 AND b\.deleted_at IS NULL AND br\.deleted_at IS NULL` + distinctBeers + `\s+ORDER BY b\.name, b\.id\s+LIMIT \$1`
 
-		rows := sqlmock.NewRows([]string{"id", "name", "style", "brewery", "country", "abv", "ibu"})
+		rows := sqlmock.NewRows([]string{"id", "name", "style", "brewery", "city", "country", "abv", "ibu"})
 		// Simulate 100 results instead of 1000 to avoid excessive output
 		for i := range 100 {
-			rows.AddRow(i, fmt.Sprintf("Beer %d", i), "Style", "Brewery", "Country", 5.0, 30)
+			rows.AddRow(i, fmt.Sprintf("Beer %d", i), "Style", "Brewery", "", "Country", 5.0, 30)
 		}
 
 		mock.ExpectQuery(expectedQuery).
@@ -490,9 +466,9 @@ func TestSearchBeers_SQLInjectionPrevention(t *testing.T) {
 		svc := setupBeerService(db)
 
 		maliciousInput := "'; DROP TABLE beers; --"
-		expectedQuery := `SELECT b.id, b.name, b.style, br.name as brewery, br.country, b.abv, b.ibu\s+FROM beers b\s+JOIN breweries br ON b.brewery_id = br.id\s+WHERE 1=1\s+AND b.name ILIKE \$1`
+		expectedQuery := `SELECT b.id, b.name, b.style, br.name as brewery, COALESCE\(br.city, ''\) AS city,\s+COALESCE\(br.country, ''\) AS country, b.abv, b.ibu\s+FROM beers b\s+JOIN breweries br ON b.brewery_id = br.id\s+WHERE 1=1 AND b\.deleted_at IS NULL AND br\.deleted_at IS NULL\s+AND b.name ILIKE \$1`
 
-		rows := sqlmock.NewRows([]string{"id", "name", "style", "brewery", "country", "abv", "ibu"})
+		rows := sqlmock.NewRows([]string{"id", "name", "style", "brewery", "city", "country", "abv", "ibu"})
 
 		mock.ExpectQuery(expectedQuery).
 			WithArgs("%" + maliciousInput + "%").
@@ -548,9 +524,9 @@ func BenchmarkSearchBeers(b *testing.B) {
 	defer db.Close()
 	svc := setupBeerService(db)
 
-	expectedQuery := `SELECT b.id, b.name, b.style, br.name as brewery, br.country, b.abv, b.ibu\s+FROM beers b\s+JOIN breweries br ON b.brewery_id = br.id\s+WHERE 1=1\s+AND b.name ILIKE \$1`
+	expectedQuery := `SELECT b.id, b.name, b.style, br.name as brewery, COALESCE\(br.city, ''\) AS city,\s+COALESCE\(br.country, ''\) AS country, b.abv, b.ibu\s+FROM beers b\s+JOIN breweries br ON b.brewery_id = br.id\s+WHERE 1=1 AND b\.deleted_at IS NULL AND br\.deleted_at IS NULL\s+AND b.name ILIKE \$1`
 
-	rows := sqlmock.NewRows([]string{"id", "name", "style", "brewery", "country", "abv", "ibu"}).
+	rows := sqlmock.NewRows([]string{"id", "name", "style", "brewery", "city", "country", "abv", "ibu"}).
 		AddRow(getMockBeerRows()[0]...)
 
 	for range b.N {
@@ -566,3 +542,656 @@ func BenchmarkSearchBeers(b *testing.B) {
 		_, _ = svc.SearchBeers(context.Background(), query)
 	}
 }
+
+func TestSearchBeersPage(t *testing.T) {
+	beerColumns := []string{"id", "name", "style", "brewery", "city", "country", "abv", "ibu"}
+	// The count query must repeat the search filters exactly, with no ORDER BY, LIMIT, or OFFSET.
+	countQuery := `^\s*SELECT COUNT\(\*\)\s+FROM beers b\s+JOIN breweries br ON b\.brewery_id = br\.id\s+` +
+		`WHERE 1=1 AND b\.deleted_at IS NULL AND br\.deleted_at IS NULL` +
+		`\s+AND b\.style ILIKE \$1\s+AND br\.city ILIKE \$2` + distinctBeers + `$`
+	dataQuery := `SELECT b\.id, .*\s+WHERE 1=1 AND b\.deleted_at IS NULL AND br\.deleted_at IS NULL` +
+		`\s+AND b\.style ILIKE \$1\s+AND br\.city ILIKE \$2` + distinctBeers +
+		`\s+ORDER BY b\.name, b\.id\s+LIMIT \$3 OFFSET \$4$`
+
+	t.Run("Middle page reports total and more results", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		svc := setupBeerService(db)
+
+		mock.ExpectQuery(countQuery).
+			WithArgs("%IPA%", "%Cape Town%").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(7))
+		mock.ExpectQuery(dataQuery).
+			WithArgs("%IPA%", "%Cape Town%", 2, 2).
+			WillReturnRows(sqlmock.NewRows(beerColumns).AddRow(getMockBeerRows()[0]...).AddRow(getMockBeerRows()[1]...))
+
+		page, err := svc.SearchBeersPage(
+			context.Background(),
+			services.BeerSearchQuery{Style: "IPA", Location: "Cape Town", Limit: 2, Offset: 2},
+		)
+
+		require.NoError(t, err)
+		assert.Len(t, page.Items, 2)
+		assert.Equal(t, 2, page.Offset)
+		assert.Equal(t, 7, page.TotalCount)
+		assert.True(t, page.HasMore)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Last page has no more results", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		svc := setupBeerService(db)
+
+		mock.ExpectQuery(countQuery).
+			WithArgs("%IPA%", "%Cape Town%").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+		mock.ExpectQuery(dataQuery).
+			WithArgs("%IPA%", "%Cape Town%", 2, 2).
+			WillReturnRows(sqlmock.NewRows(beerColumns).AddRow(getMockBeerRows()[2]...))
+
+		page, err := svc.SearchBeersPage(
+			context.Background(),
+			services.BeerSearchQuery{Style: "IPA", Location: "Cape Town", Limit: 2, Offset: 2},
+		)
+
+		require.NoError(t, err)
+		assert.Len(t, page.Items, 1)
+		assert.Equal(t, 3, page.TotalCount)
+		assert.False(t, page.HasMore)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Offset beyond the last row skips the data query", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		svc := setupBeerService(db)
+
+		mock.ExpectQuery(countQuery).
+			WithArgs("%IPA%", "%Cape Town%").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+		page, err := svc.SearchBeersPage(
+			context.Background(),
+			services.BeerSearchQuery{Style: "IPA", Location: "Cape Town", Limit: 2, Offset: 10},
+		)
+
+		require.NoError(t, err)
+		assert.NotNil(t, page.Items)
+		assert.Empty(t, page.Items)
+		assert.Equal(t, 10, page.Offset)
+		assert.Equal(t, 3, page.TotalCount)
+		assert.False(t, page.HasMore)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Count error is returned", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		svc := setupBeerService(db)
+
+		mock.ExpectQuery(countQuery).WillReturnError(errors.New("connection reset"))
+
+		page, err := svc.SearchBeersPage(
+			context.Background(),
+			services.BeerSearchQuery{Style: "IPA", Location: "Cape Town", Limit: 2},
+		)
+
+		require.Error(t, err)
+		assert.Nil(t, page)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestSearchBeers_RelevanceSort(t *testing.T) {
+	t.Run("Ranks by name similarity when enabled", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		svc := setupBeerService(db)
+		svc.EnableRelevanceSearch(true)
+
+		expectedQuery := `SELECT b\.id, b\.name, b\.style, br\.name as brewery, COALESCE\(br\.city, ''\) AS city,\s+COALESCE\(br\.country, ''\) AS country, b\.abv, b\.ibu, ` +
+			`similarity\(b\.name, \$2\) AS score\s+FROM beers b\s+JOIN breweries br ON b\.brewery_id = br\.id\s+` +
+			`WHERE 1=1 AND b\.deleted_at IS NULL AND br\.deleted_at IS NULL` +
+			`\s+AND b\.name ILIKE \$1` + distinctBeers + `\s+ORDER BY score DESC, b\.name, b\.id\s+LIMIT \$3$`
+		rows := sqlmock.NewRows([]string{"id", "name", "style", "brewery", "city", "country", "abv", "ibu", "score"}).
+			AddRow(4, "Stone IPA", "American IPA", "Stone Brewing", "", "United States", 6.9, 71, 0.6).
+			AddRow(5, "Keystone Light", "American Light Lager", "Coors", "", "United States", 4.1, 8, 0.3)
+		mock.ExpectQuery(expectedQuery).
+			WithArgs("%Stone%", "Stone", 5).
+			WillReturnRows(rows)
+
+		results, err := svc.SearchBeers(
+			context.Background(),
+			services.BeerSearchQuery{Name: "Stone", Limit: 5, Sort: services.SortByRelevance},
+		)
+
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		assert.Equal(t, "Stone IPA", results[0].Name)
+		assert.InDelta(t, 0.6, results[0].Score, 1e-9)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Falls back to name order when disabled", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		svc := setupBeerService(db)
+
+		mock.ExpectQuery(`SELECT COUNT\(\*\)`).
+			WithArgs("%Stone%").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		expectedQuery := `SELECT b\.id, b\.name, b\.style, br\.name as brewery, COALESCE\(br\.city, ''\) AS city,\s+COALESCE\(br\.country, ''\) AS country, b\.abv, b\.ibu\s+` +
+			`FROM .*ORDER BY b\.name, b\.id\s+LIMIT \$2$`
+		mock.ExpectQuery(expectedQuery).
+			WithArgs("%Stone%", 5).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "style", "brewery", "city", "country", "abv", "ibu"}).
+				AddRow(4, "Stone IPA", "American IPA", "Stone Brewing", "", "United States", 6.9, 71))
+
+		page, err := svc.SearchBeersPage(
+			context.Background(),
+			services.BeerSearchQuery{Name: "Stone", Limit: 5, Sort: services.SortByRelevance},
+		)
+
+		require.NoError(t, err)
+		assert.Equal(t, services.SortByName, page.Sort)
+		assert.Zero(t, page.Items[0].Score)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Falls back to name order without a name filter", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		svc := setupBeerService(db)
+		svc.EnableRelevanceSearch(true)
+
+		mock.ExpectQuery(`WHERE 1=1 AND b\.deleted_at IS NULL`+
+			` AND br\.deleted_at IS NULL\s+AND b\.style ILIKE \$1`+distinctBeers+
+			`\s+ORDER BY b\.name, b\.id\s+LIMIT \$2$`).
+			WithArgs("%IPA%", 5).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "style", "brewery", "city", "country", "abv", "ibu"}))
+
+		_, err := svc.SearchBeers(
+			context.Background(),
+			services.BeerSearchQuery{Style: "IPA", Limit: 5, Sort: services.SortByRelevance},
+		)
+
+		require.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestSearchBeers_Sort(t *testing.T) {
+	tests := []struct {
+		sort, order string
+		wantOrder   string
+	}{
+		{sort: "", order: "", wantOrder: `b\.name, b\.id`},
+		{sort: services.SortByName, order: services.OrderDesc, wantOrder: `b\.name DESC NULLS LAST, b\.id`},
+		{sort: services.SortByABV, order: "", wantOrder: `b\.abv, b\.name, b\.id`},
+		{sort: services.SortByABV, order: "DESC", wantOrder: `b\.abv DESC NULLS LAST, b\.name, b\.id`},
+		{sort: services.SortByIBU, order: services.OrderAsc, wantOrder: `b\.ibu, b\.name, b\.id`},
+		{sort: services.SortByStyle, order: "", wantOrder: `b\.style, b\.name, b\.id`},
+		{sort: "Brewery", order: services.OrderDesc, wantOrder: `br\.name DESC NULLS LAST, b\.name, b\.id`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.sort+" "+tt.order, func(t *testing.T) {
+			db, mock := setupMockDB(t)
+			defer db.Close()
+			svc := setupBeerService(db)
+
+			expectedQuery := `WHERE 1=1 AND b\.deleted_at IS NULL AND br\.deleted_at IS NULL` +
+				`\s+AND b\.style ILIKE \$1` + distinctBeers + `\s+ORDER BY ` + tt.wantOrder + `\s+LIMIT \$2$`
+			mock.ExpectQuery(expectedQuery).
+				WithArgs("%IPA%", 5).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "name", "style", "brewery", "city", "country", "abv", "ibu"}))
+
+			_, err := svc.SearchBeers(
+				context.Background(),
+				services.BeerSearchQuery{Style: "IPA", Limit: 5, Sort: tt.sort, Order: tt.order},
+			)
+
+			require.NoError(t, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Run("Rejects unknown sort keys and orders", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		svc := setupBeerService(db)
+
+		for _, query := range []services.BeerSearchQuery{
+			{Style: "IPA", Sort: "abv; DROP TABLE beers"},
+			{Style: "IPA", Sort: services.SortByCity},
+			{Style: "IPA", Sort: services.SortByABV, Order: "sideways"},
+		} {
+			_, err := svc.SearchBeersPage(context.Background(), query)
+			require.ErrorIs(t, err, services.ErrInvalidSearchQuery)
+		}
+		assert.NoError(t, mock.ExpectationsWereMet(), "invalid sorts should not reach the database")
+	})
+}
+
+func TestSearchBeers_TextNormalization(t *testing.T) {
+	t.Run("Trims and collapses whitespace", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		svc := setupBeerService(db)
+
+		mock.ExpectQuery(`WHERE 1=1 AND b\.deleted_at IS NULL`+
+			` AND br\.deleted_at IS NULL\s+AND b\.name ILIKE \$1\s+AND b\.style ILIKE \$2`+distinctBeers+
+			`\s+ORDER BY`).
+			WithArgs("%Pale Ale%", "%IPA%", 5).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "style", "brewery", "city", "country", "abv", "ibu"}))
+
+		_, err := svc.SearchBeers(context.Background(), services.BeerSearchQuery{
+			Name:    "  Pale \t Ale ",
+			Style:   "\nIPA",
+			Brewery: "  ",
+			Limit:   5,
+		})
+
+		require.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Folds diacritics when asked", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		svc := setupBeerService(db)
+
+		mock.ExpectQuery(`WHERE 1=1 AND b\.deleted_at IS NULL`+
+			` AND br\.deleted_at IS NULL\s+AND TRANSLATE\(LOWER\(br\.name\), '[^']+', '[^']+'\) ILIKE \$1`+distinctBeers+
+			`\s+ORDER BY`).
+			WithArgs("%brau%", 5).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "style", "brewery", "city", "country", "abv", "ibu"}).
+				AddRow(1, "Märzen", "Märzen", "Bräu Haus", "", "Germany", 5.8, 24))
+
+		results, err := svc.SearchBeers(
+			context.Background(),
+			services.BeerSearchQuery{Brewery: "Bräu", Limit: 5, FoldDiacritics: true},
+		)
+
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, "Bräu Haus", results[0].Brewery)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestSearchBeers_RangeFilters(t *testing.T) {
+	abvMax, ibuMin, ibuMax, srmMin := 5.0, 40.0, 70.0, 2.0
+
+	t.Run("Binds each bound as a parameter after the text filters", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		svc := setupBeerService(db)
+
+		expectedQuery := `WHERE 1=1 AND b\.deleted_at IS NULL AND br\.deleted_at IS NULL` +
+			`\s+AND b\.style ILIKE \$1\s+AND b\.abv <= \$2 AND b\.ibu >= \$3 AND b\.ibu <= \$4 ` +
+			`AND b\.srm >= \$5` + distinctBeers + `\s+ORDER BY b\.name, b\.id\s+LIMIT \$6$`
+		mock.ExpectQuery(expectedQuery).
+			WithArgs("%IPA%", abvMax, ibuMin, ibuMax, srmMin, 20).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "style", "brewery", "city", "country", "abv", "ibu"}).
+				AddRow(getMockBeerRows()[0]...))
+
+		results, err := svc.SearchBeers(context.Background(), services.BeerSearchQuery{
+			Style:  "IPA",
+			ABVMax: &abvMax,
+			IBUMin: &ibuMin,
+			IBUMax: &ibuMax,
+			SRMMin: &srmMin,
+			Limit:  20,
+		})
+
+		require.NoError(t, err)
+		assert.Len(t, results, 1)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Matches the style or any of its aliases", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		svc := setupBeerService(db)
+
+		expectedQuery := `WHERE 1=1 AND b\.deleted_at IS NULL AND br\.deleted_at IS NULL` +
+			`\s+AND \(b\.style ILIKE \$1 OR b\.style ILIKE \$2 OR b\.style ILIKE \$3\)` +
+			`\s+AND b\.abv <= \$4` + distinctBeers + `\s+ORDER BY b\.name, b\.id\s+LIMIT \$5$`
+		mock.ExpectQuery(expectedQuery).
+			WithArgs("%Double IPA%", "%Imperial IPA%", "%DIPA%", abvMax, 20).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "style", "brewery", "city", "country", "abv", "ibu"}))
+
+		results, err := svc.SearchBeers(context.Background(), services.BeerSearchQuery{
+			Style:        "Double IPA",
+			StyleAliases: []string{"Imperial IPA", " ", "DIPA"},
+			ABVMax:       &abvMax,
+			Limit:        20,
+		})
+
+		require.NoError(t, err)
+		assert.Empty(t, results)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Range filters alone count and page", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		svc := setupBeerService(db)
+
+		mock.ExpectQuery(`SELECT COUNT\(\*\).*WHERE 1=1 AND b\.deleted_at IS NULL AND br\.deleted_at IS NULL` +
+			`\s+AND b\.abv <= \$1` + distinctBeers + `$`).
+			WithArgs(abvMax).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		mock.ExpectQuery(`WHERE 1=1 AND b\.deleted_at IS NULL`+
+			` AND br\.deleted_at IS NULL\s+AND b\.abv <= \$1`+distinctBeers+
+			`\s+ORDER BY b\.name, b\.id\s+LIMIT \$2$`).
+			WithArgs(abvMax, 10).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "style", "brewery", "city", "country", "abv", "ibu"}).
+				AddRow(getMockBeerRows()[0]...))
+
+		page, err := svc.SearchBeersPage(context.Background(), services.BeerSearchQuery{ABVMax: &abvMax, Limit: 10})
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, page.TotalCount)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Equal bounds are allowed", func(t *testing.T) {
+		bound := 5.0
+		query := services.BeerSearchQuery{ABVMin: &bound, ABVMax: &bound}
+		assert.NoError(t, query.Validate())
+	})
+
+	invalid := []struct {
+		name    string
+		query   services.BeerSearchQuery
+		message string
+	}{
+		{
+			name:    "ABV minimum above maximum",
+			query:   services.BeerSearchQuery{ABVMin: &ibuMin, ABVMax: &abvMax},
+			message: "abv_min (40) is greater than abv_max (5)",
+		},
+		{
+			name:    "IBU minimum above maximum",
+			query:   services.BeerSearchQuery{IBUMin: &ibuMax, IBUMax: &ibuMin},
+			message: "ibu_min (70) is greater than ibu_max (40)",
+		},
+		{
+			name:    "Negative SRM",
+			query:   services.BeerSearchQuery{SRMMax: func() *float64 { v := -1.0; return &v }()},
+			message: "srm_max cannot be negative",
+		},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := setupMockDB(t)
+			defer db.Close()
+			svc := setupBeerService(db)
+
+			_, err := svc.SearchBeers(context.Background(), tt.query)
+			require.ErrorIs(t, err, services.ErrInvalidSearchQuery)
+			assert.Contains(t, err.Error(), tt.message)
+
+			_, err = svc.SearchBeersPage(context.Background(), tt.query)
+			require.ErrorIs(t, err, services.ErrInvalidSearchQuery)
+			assert.NoError(t, mock.ExpectationsWereMet(), "an invalid query should not reach the database")
+		})
+	}
+}
+
+func TestSearchBeers_DescriptionAndTags(t *testing.T) {
+	t.Run("Searches descriptions by full text and requires every tag", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		svc := setupBeerService(db)
+
+		mock.ExpectQuery(`WHERE 1=1 AND b\.deleted_at IS NULL AND br\.deleted_at IS NULL`+
+			`\s+AND to_tsvector\('english', COALESCE\(b\.description, ''\)\) @@ plainto_tsquery\('english', \$1\)`+
+			` AND b\.tags @> ARRAY\[\$2::text\] AND b\.tags @> ARRAY\[\$3::text\]`+distinctBeers+
+			`\s+ORDER BY b\.name, b\.id\s+LIMIT \$4$`).
+			WithArgs("dark chocolate", "chocolate", "coffee", 10).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "style", "brewery", "city", "country", "abv", "ibu"}).
+				AddRow(5, "Castle Milk Stout", "Milk Stout", "South African Breweries", "Johannesburg", "South Africa",
+					6.0, 25))
+
+		results, err := svc.SearchBeers(context.Background(), services.BeerSearchQuery{
+			Description: "  dark   chocolate ",
+			Tags:        []string{"Coffee", "chocolate", " ", "coffee"},
+			Limit:       10,
+		})
+
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Rejects malformed tags", func(t *testing.T) {
+		query := services.BeerSearchQuery{Tags: []string{"dark fruit"}}
+		err := query.Validate()
+		require.ErrorIs(t, err, services.ErrInvalidSearchQuery)
+		assert.Contains(t, err.Error(), `tag "dark fruit" must be lowercase words joined by hyphens`)
+	})
+}
+
+func TestSearchBeers_Duplicates(t *testing.T) {
+	t.Run("Leaves out the later beers of a brewery with the same name", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		svc := setupBeerService(db)
+
+		mock.ExpectQuery(`SELECT COUNT\(\*\)\s+FROM beers b\s+JOIN breweries br ON b\.brewery_id = br\.id\s+` +
+			`WHERE 1=1 AND b\.deleted_at IS NULL AND br\.deleted_at IS NULL\s+AND b\.name ILIKE \$1` +
+			distinctBeers + `$`).
+			WithArgs("%Pale Ale%").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+		mock.ExpectQuery(`SELECT b\.id, b\.name, b\.style, br\.name as brewery, COALESCE\(br\.city, ''\) AS city,`+
+			`\s+COALESCE\(br\.country, ''\) AS country, b\.abv, b\.ibu\s+FROM beers b\s+`+
+			`JOIN breweries br ON b\.brewery_id = br\.id\s+`+
+			`WHERE 1=1 AND b\.deleted_at IS NULL AND br\.deleted_at IS NULL\s+AND b\.name ILIKE \$1`+
+			distinctBeers+`\s+ORDER BY b\.name, b\.id\s+LIMIT \$2$`).
+			WithArgs("%Pale Ale%", 20).
+			WillReturnRows(sqlmock.NewRows(beerSearchColumns).
+				AddRow(1, "Pale Ale", "American Pale Ale", "Brewery A", "Cape Town", "South Africa", 5.0, 35).
+				AddRow(7, "Pale Ale", "English Pale Ale", "Brewery B", "Portland", "United States", 4.8, 30))
+
+		page, err := svc.SearchBeersPage(context.Background(), services.BeerSearchQuery{Name: "Pale Ale", Limit: 20})
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, page.TotalCount)
+		require.Len(t, page.Items, 2)
+		assert.Equal(t, "Cape Town", page.Items[0].City)
+		assert.Equal(t, "Portland", page.Items[1].City)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Compares with deleted beers when they are included", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		svc := setupBeerService(db)
+
+		mock.ExpectQuery(`WHERE 1=1\s+AND b\.name ILIKE \$1 AND NOT EXISTS \(SELECT 1 FROM beers d ` +
+			`WHERE d\.brewery_id = b\.brewery_id AND LOWER\(d\.name\) = LOWER\(b\.name\) AND d\.id < b\.id\)` +
+			`\s+ORDER BY`).
+			WithArgs("%Pale Ale%").
+			WillReturnRows(sqlmock.NewRows(beerSearchColumns))
+
+		_, err := svc.SearchBeers(context.Background(), services.BeerSearchQuery{Name: "Pale Ale", IncludeDeleted: true})
+
+		require.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestGetBeerByID(t *testing.T) {
+	columns := []string{
+		"id", "name", "style", "abv", "ibu", "srm", "description", "brewery_id", "brewery",
+		"brewery_city", "brewery_state", "country", "created_at", "updated_at",
+	}
+	expectedQuery := `SELECT b\.id, b\.name, .* FROM beers b\s+JOIN breweries br ON b\.brewery_id = br\.id\s+` +
+		`WHERE b\.id = \$1 AND b\.deleted_at IS NULL AND br\.deleted_at IS NULL$`
+
+	t.Run("Returns the beer joined with its brewery", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		svc := setupBeerService(db)
+		created := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+		mock.ExpectQuery(expectedQuery).
+			WithArgs(3).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(
+				3, "King's Blockhouse IPA", "American IPA", 6.0, 55, 8.0, "Citrus and pine.", 7,
+				"Devil's Peak Brewing Company", "Cape Town", "Western Cape", "South Africa", created, created,
+			))
+
+		beer, err := svc.GetBeerByID(context.Background(), 3)
+
+		require.NoError(t, err)
+		assert.Equal(t, "King's Blockhouse IPA", beer.Name)
+		assert.Equal(t, 7, beer.BreweryID)
+		assert.Equal(t, "Devil's Peak Brewing Company", beer.Brewery)
+		assert.InDelta(t, 8.0, beer.SRM, 0.001)
+		assert.Equal(t, created, beer.CreatedAt)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Missing ID returns ErrNotFound", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		svc := setupBeerService(db)
+
+		mock.ExpectQuery(expectedQuery).WithArgs(99).WillReturnError(sql.ErrNoRows)
+
+		beer, err := svc.GetBeerByID(context.Background(), 99)
+
+		assert.Nil(t, beer)
+		require.ErrorIs(t, err, services.ErrNotFound)
+		var notFound *services.NotFoundError
+		require.ErrorAs(t, err, &notFound)
+		assert.Equal(t, "beer 99 not found", notFound.Error())
+	})
+
+	t.Run("Database errors are wrapped", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		svc := setupBeerService(db)
+
+		mock.ExpectQuery(expectedQuery).WithArgs(3).WillReturnError(errors.New("connection reset"))
+
+		_, err := svc.GetBeerByID(context.Background(), 3)
+
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, services.ErrNotFound)
+		assert.Contains(t, err.Error(), "failed to get beer 3: connection reset")
+	})
+}
+
+func TestListBeerStats(t *testing.T) {
+	expectedQuery := `SELECT b\.id, b\.name, br\.name AS brewery, .* FROM beers b\s+` +
+		`JOIN breweries br ON b\.brewery_id = br\.id\s+WHERE COALESCE\(b\.style, ''\) <> '' AND b\.deleted_at IS NULL` +
+		` AND br\.deleted_at IS NULL\s+ORDER BY b\.id$`
+
+	t.Run("Returns the stats of the beers with a style", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		mock.ExpectQuery(expectedQuery).WillReturnRows(
+			sqlmock.NewRows([]string{"id", "name", "brewery", "style", "abv", "ibu", "srm"}).
+				AddRow(1, "King's Blockhouse IPA", "Devil's Peak Brewing Company", "American IPA", 6.0, 55, 8.0).
+				AddRow(2, "Lager", "Castle Lager", "Pilsner", 4.5, 0, 0.0),
+		)
+
+		stats, err := setupBeerService(db).ListBeerStats(context.Background())
+
+		require.NoError(t, err)
+		require.Len(t, stats, 2)
+		assert.Equal(t, "Devil's Peak Brewing Company", stats[0].Brewery)
+		assert.Equal(t, 55, stats[0].IBU)
+		assert.Zero(t, stats[1].SRM)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Database errors are wrapped", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		mock.ExpectQuery(expectedQuery).WillReturnError(errors.New("connection reset"))
+
+		_, err := setupBeerService(db).ListBeerStats(context.Background())
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to list beer stats: connection reset")
+	})
+}
+
+func TestSearchBeersIter(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("stopping early closes the rows", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		rows := sqlmock.NewRows(beerSearchColumns)
+		for _, row := range getMockBeerRows() {
+			rows.AddRow(row...)
+		}
+		mock.ExpectQuery(`FROM beers b.*ORDER BY b\.name, b\.id LIMIT \$1`).
+			WithArgs(1000).
+			WillReturnRows(rows).
+			RowsWillBeClosed()
+
+		// Limit may exceed MaxSearchLimit
+		beers, err := setupBeerService(db).SearchBeersIter(ctx, services.BeerSearchQuery{Limit: 1000})
+		require.NoError(t, err)
+		assert.Error(t, mock.ExpectationsWereMet(), "the query should not run until the iterator is used")
+
+		var names []string
+		for beer, err := range beers {
+			require.NoError(t, err)
+			names = append(names, beer.Name)
+			if len(names) == 2 {
+				break
+			}
+		}
+		assert.Equal(t, []string{"King's Blockhouse IPA", "Hazy Pale Ale"}, names)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("a scan error ends the iteration", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		mock.ExpectQuery(`FROM beers b`).
+			WillReturnRows(sqlmock.NewRows(beerSearchColumns).
+				AddRow(getMockBeerRows()[0]...).
+				AddRow(2, "Broken", "Stout", "Brewery", "", "ZA", "not a number", 30)).
+			RowsWillBeClosed()
+
+		beers, err := setupBeerService(db).SearchBeersIter(ctx, services.BeerSearchQuery{})
+		require.NoError(t, err)
+		var errs []error
+		count := 0
+		for beer, err := range beers {
+			if err != nil {
+				errs = append(errs, err)
+				assert.Nil(t, beer)
+				continue
+			}
+			count++
+		}
+		assert.Equal(t, 1, count)
+		assert.Len(t, errs, 1)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("rejects invalid queries up front", func(t *testing.T) {
+		db, _ := setupMockDB(t)
+		defer db.Close()
+		svc := setupBeerService(db)
+
+		_, err := svc.SearchBeersIter(ctx, services.BeerSearchQuery{Limit: -1})
+		assert.ErrorIs(t, err, services.ErrInvalidLimit)
+		abvMin, abvMax := 8.0, 4.0
+		_, err = svc.SearchBeersIter(ctx, services.BeerSearchQuery{ABVMin: &abvMin, ABVMax: &abvMax})
+		assert.ErrorIs(t, err, services.ErrInvalidSearchQuery)
+	})
+}
@@ -3,96 +3,614 @@ package services
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	"iter"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/redis/go-redis/v9"
 )
 
-// BreweryServiceInterface abstracts brewery search for handler injection and testing.
+// BreweryServiceInterface abstracts the brewery directory for handler injection and testing; see
+// the servicestest package for a fake.
 type BreweryServiceInterface interface {
 	SearchBreweries(ctx context.Context, query BrewerySearchQuery) ([]*BrewerySearchResult, error)
+	SearchBreweriesPage(ctx context.Context, query BrewerySearchQuery) (*BrewerySearchPage, error)
+	SearchBreweriesIter(
+		ctx context.Context,
+		query BrewerySearchQuery,
+	) (iter.Seq2[*BrewerySearchResult, error], error)
+	GetBreweryByID(ctx context.Context, id int) (*BreweryDetail, error)
+	GetBreweryBeers(ctx context.Context, breweryID, limit int) (*BreweryBeers, error)
+	ResolveBreweryID(ctx context.Context, name string) (int, error)
+	SuggestNames(ctx context.Context, prefix string, limit int) ([]string, error)
+	ListCountries(ctx context.Context) ([]*CountryCount, error)
+	ListRegions(ctx context.Context, country string) (*CountryRegions, error)
+	ListDataSources(ctx context.Context) ([]*DataSource, error)
+	GetStats(ctx context.Context, scope StatsScope) (*Stats, error)
+	CreateBrewery(ctx context.Context, brewery Brewery) (int, error)
+	SoftDeleteBrewery(ctx context.Context, id int) error
+	RestoreBrewery(ctx context.Context, id int) error
+	GetBreweryVenues(ctx context.Context, breweryID int) ([]*Venue, error)
+	SearchVenuesNear(ctx context.Context, query VenueSearchQuery) ([]*VenueSearchResult, error)
 }
 
 // BrewerySearchQuery represents search parameters for brewery lookup.
 type BrewerySearchQuery struct {
 	Name     string
 	Location string
-	City     string
-	State    string
-	Country  string
-	Limit    int
+	City     []string // cities, any of which may match, as for the other text filters
+	State    []string
+	Country  []string
+	Types    []string // brewery_type values, any of which may match; see BreweryTypes
+	// Exclude leaves out the breweries matching any of its values.
+	Exclude  BreweryExclusions
+	Lat      *float64 // with Lng, limits results to breweries with known coordinates, nearest first
+	Lng      *float64
+	RadiusKm *float64 // maximum distance from Lat/Lng; needs both
+	Limit    int      // at most MaxSearchLimit; zero for DefaultSearchLimit
+	Offset   int      // rows to skip, for paging through results
+	Sort     string   // one of BrewerySortKeys, SortByName by default; SortByDistance when coordinates are given
+	Order    string   // OrderAsc (default) or OrderDesc
+	// FoldDiacritics matches the text filters ignoring accents, so that "Bräu" matches "Brau".
+	FoldDiacritics bool
+	// IncludeDeleted also matches soft-deleted breweries, which are hidden by default.
+	IncludeDeleted bool
 }
 
+// BreweryExclusions lists values that leave breweries out of a search. City, State, and Country
+// match case-insensitive substrings, as the query filters do, and Types brewery_type values.
+// Breweries with no value in a field are never excluded by it.
+type BreweryExclusions struct {
+	City    []string
+	State   []string
+	Country []string
+	Types   []string
+}
+
+// hasCoordinates reports whether the query gives a point to measure distance from.
+func (q BrewerySearchQuery) hasCoordinates() bool {
+	return q.Lat != nil && q.Lng != nil
+}
+
+// BreweryTypes lists the brewery_type values accepted in BrewerySearchQuery.Types.
+var BreweryTypes = []string{
+	"micro", "nano", "regional", "brewpub", "large", "macro",
+	"planning", "bar", "contract", "proprietor", "closed",
+}
+
+// Validate checks that every entry in Types and Exclude.Types is one of BreweryTypes, ignoring
+// case, that the coordinates and radius are complete and in range, that Sort and Order are known
+// values, and that Limit is in range.
+func (q BrewerySearchQuery) Validate() error {
+	if err := validateLimit(q.Limit); err != nil {
+		return err
+	}
+	if err := validateSort(q.Sort, q.Order, BrewerySortKeys); err != nil {
+		return err
+	}
+	switch {
+	case (q.Lat == nil) != (q.Lng == nil):
+		return fmt.Errorf("%w: latitude and longitude must be given together", ErrInvalidSearchQuery)
+	case q.RadiusKm != nil && !q.hasCoordinates():
+		return fmt.Errorf("%w: radius_km needs latitude and longitude", ErrInvalidSearchQuery)
+	case q.Lat != nil && (*q.Lat < -90 || *q.Lat > 90):
+		return fmt.Errorf("%w: latitude must be between -90 and 90 (got %g)", ErrInvalidSearchQuery, *q.Lat)
+	case q.Lng != nil && (*q.Lng < -180 || *q.Lng > 180):
+		return fmt.Errorf("%w: longitude must be between -180 and 180 (got %g)", ErrInvalidSearchQuery, *q.Lng)
+	case q.RadiusKm != nil && *q.RadiusKm <= 0:
+		return fmt.Errorf("%w: radius_km must be greater than zero (got %g)", ErrInvalidSearchQuery, *q.RadiusKm)
+	}
+	for _, breweryType := range slices.Concat(q.Types, q.Exclude.Types) {
+		if !slices.Contains(BreweryTypes, strings.ToLower(strings.TrimSpace(breweryType))) {
+			return fmt.Errorf(
+				"%w: unknown brewery type %q (must be one of %s)",
+				ErrInvalidSearchQuery, breweryType, strings.Join(BreweryTypes, ", "),
+			)
+		}
+	}
+	return nil
+}
+
+// BreweryDetail is a full brewery record with the number of beers it makes.
+type BreweryDetail struct {
+	Brewery
+	BeerCount int `db:"beer_count" json:"beer_count"`
+}
+
+// Limits for GetBreweryBeers.
+const (
+	DefaultBreweryBeersLimit = 50
+	MaxBreweryBeersLimit     = 200
+)
+
+// BreweryBeers is a brewery's beers, sorted by name.
+type BreweryBeers struct {
+	BreweryID  int            `json:"brewery_id"`
+	Brewery    string         `json:"brewery"`
+	Beers      []*BreweryBeer `json:"beers"`
+	TotalCount int            `json:"total_count"` // all of the brewery's beers, which may exceed len(Beers)
+}
+
+// BreweryBeer is one beer in a brewery's list.
+type BreweryBeer struct {
+	ID    int     `json:"id"`
+	Name  string  `json:"name"`
+	Style string  `json:"style"`
+	ABV   float64 `json:"abv"`
+	IBU   int     `json:"ibu"`
+}
+
+// breweryProfileColumns selects a brewery's profile, unset fields as empty strings and a zero year.
+const breweryProfileColumns = `COALESCE(description, '') AS description, ` +
+	`COALESCE(founded_year, 0) AS founded_year, COALESCE(instagram, '') AS instagram, ` +
+	`COALESCE(facebook, '') AS facebook, COALESCE(twitter, '') AS twitter, COALESCE(email, '') AS email`
+
+// ErrAmbiguousBreweryName is wrapped by ResolveBreweryID when a name matches more than one brewery.
+var ErrAmbiguousBreweryName = errors.New("brewery name matches more than one brewery")
+
 // BrewerySearchResult represents a brewery search result.
 type BrewerySearchResult struct {
-	ID          int    `db:"id"           json:"id"`
-	Name        string `db:"name"         json:"name"`
-	BreweryType string `db:"brewery_type" json:"brewery_type"`
-	Street      string `db:"street"       json:"street"`
-	City        string `db:"city"         json:"city"`
-	State       string `db:"state"        json:"state"`
-	PostalCode  string `db:"postal_code"  json:"postal_code"`
-	Country     string `db:"country"      json:"country"`
-	Phone       string `db:"phone"        json:"phone"`
-	Website     string `db:"website_url"  json:"website_url"`
+	ID          int      `db:"id"           json:"id"`
+	Name        string   `db:"name"         json:"name"`
+	BreweryType string   `db:"brewery_type" json:"brewery_type"`
+	Street      string   `db:"street"       json:"street"`
+	City        string   `db:"city"         json:"city"`
+	State       string   `db:"state"        json:"state"`
+	PostalCode  string   `db:"postal_code"  json:"postal_code"`
+	Country     string   `db:"country"      json:"country"`
+	Phone       string   `db:"phone"        json:"phone"`
+	Website     string   `db:"website_url"  json:"website_url"`
+	Description string   `db:"description"  json:"description"`
+	FoundedYear int      `db:"founded_year" json:"founded_year"`
+	Instagram   string   `db:"instagram"    json:"instagram"`
+	Facebook    string   `db:"facebook"     json:"facebook"`
+	Twitter     string   `db:"twitter"      json:"twitter"`
+	Email       string   `db:"email"        json:"email"`
+	Score       float64  `db:"score"        json:"score,omitempty"`       // 0-1 name similarity, for SortByRelevance
+	DistanceKm  *float64 `db:"distance_km"  json:"distance_km,omitempty"` // from Lat/Lng, for SortByDistance
+	// DeletedAt is when the brewery was soft-deleted; only set for IncludeDeleted searches.
+	DeletedAt *time.Time `db:"deleted_at" json:"deleted_at,omitempty"`
+}
+
+// BrewerySearchPage is one page of brewery search results and the number of breweries matching the filters.
+type BrewerySearchPage struct {
+	Items      []*BrewerySearchResult `json:"items"`
+	Offset     int                    `json:"offset"`
+	TotalCount int                    `json:"total_count"`
+	HasMore    bool                   `json:"has_more"`
+	Sort       string                 `json:"sort"` // the ordering applied, which may fall back to SortByName
+	Order      string                 `json:"order"`
 }
 
+// breweryCachePrefix namespaces brewery search results in Redis.
+const breweryCachePrefix = "brewsource:search:breweries:"
+
 // BreweryService handles brewery-related operations.
 type BreweryService struct {
-	db          *sqlx.DB
-	redisClient *redis.Client // Optional caching
+	db         *sqlDB
+	cache      *searchCache // Optional caching; nil-safe
+	statsCache *searchCache // Caches GetStats for StatsCacheTTL; nil-safe
+	beerCache  *searchCache // Beer searches, only invalidated here since they show brewery details
+	relevance  bool         // pg_trgm is installed, so SortByRelevance can rank by similarity
+	distance   bool         // the database has the math functions the distance searches use
+	queries    *queryGuard  // Query timeout and slow query logging; nil-safe
 }
 
-// NewBreweryService creates a new BreweryService instance.
+// NewBreweryService creates a new BreweryService instance. Search results are cached in Redis for
+// DefaultCacheTTL when redisClient is not nil. Distance searches start enabled except on SQLite.
 func NewBreweryService(db *sqlx.DB, redisClient *redis.Client) *BreweryService {
+	statsCache := newSearchCache(redisClient, statsCachePrefix)
+	statsCache.setTTL(StatsCacheTTL)
 	return &BreweryService{
-		db:          db,
-		redisClient: redisClient,
+		db:         newSQLDB(db),
+		cache:      newSearchCache(redisClient, breweryCachePrefix),
+		statsCache: statsCache,
+		beerCache:  newSearchCache(redisClient, beerCachePrefix),
+		distance:   !isSQLite(db),
+		queries:    newQueryGuard(),
 	}
 }
 
+// EnableRelevanceSearch turns on SortByRelevance. Enable it only when the pg_trgm extension is
+// installed; while disabled, relevance searches are sorted by name.
+func (s *BreweryService) EnableRelevanceSearch(enabled bool) {
+	s.relevance = enabled
+}
+
+// EnableDistanceSearch turns searches by latitude and longitude on or off. Enable it only when the
+// database has the ASIN, SQRT, POWER, SIN, COS, and RADIANS functions, which SQLite lacks unless
+// built with its math functions; while disabled, such searches fail with ErrInvalidSearchQuery.
+func (s *BreweryService) EnableDistanceSearch(enabled bool) {
+	s.distance = enabled
+}
+
+// SetCacheTTL changes how long new search results stay cached. Non-positive values are ignored.
+func (s *BreweryService) SetCacheTTL(ttl time.Duration) {
+	s.cache.setTTL(ttl)
+}
+
+// CacheStats returns the search cache hit and miss counts.
+func (s *BreweryService) CacheStats() CacheStats {
+	return s.cache.stats()
+}
+
+// InvalidateBreweryCache drops all cached brewery searches and catalog statistics. Call it after
+// breweries or beers change.
+func (s *BreweryService) InvalidateBreweryCache(ctx context.Context) error {
+	return errors.Join(s.cache.invalidate(ctx), s.statsCache.invalidate(ctx))
+}
+
 // SearchBreweries performs a search for breweries based on the provided criteria.
+// It returns a single page without counting the total matches; use SearchBreweriesPage for paging.
 func (s *BreweryService) SearchBreweries(
 	ctx context.Context,
 	query BrewerySearchQuery,
 ) ([]*BrewerySearchResult, error) {
-	if query.Limit <= 0 || query.Limit > 100 {
-		query.Limit = 20
+	if err := s.validateBreweryQuery(query); err != nil {
+		return nil, err
+	}
+	query = s.normalizeBreweryQuery(query)
+	key := s.cache.key("list", breweryCacheQuery(query))
+	var cached []*BrewerySearchResult
+	if s.cache.get(ctx, key, &cached) {
+		return cached, nil
 	}
 
+	results, err := s.selectBreweries(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	s.cache.set(ctx, key, results)
+	return results, nil
+}
+
+// SearchBreweriesIter returns an iterator over the breweries matching query, running the search
+// when the iterator is used and scanning each row as it is yielded, so that large result sets are
+// never held in memory. Limit may exceed MaxSearchLimit, and zero returns every match. Results are
+// not cached. A failed query or scan is yielded once, as the last pair, with a nil result; stopping
+// early closes the rows.
+func (s *BreweryService) SearchBreweriesIter(
+	ctx context.Context,
+	query BrewerySearchQuery,
+) (iter.Seq2[*BrewerySearchResult, error], error) {
+	limit := query.Limit
+	query.Limit = 0
+	if err := s.validateBreweryQuery(query); err != nil {
+		return nil, err
+	}
+	if err := validateIterLimit(limit); err != nil {
+		return nil, err
+	}
+	query = s.normalizeBreweryQuery(query)
+	query.Limit = limit
+	return s.breweryRows(ctx, query), nil
+}
+
+// SearchBreweriesPage returns the page of breweries selected by query.Limit and query.Offset, along
+// with the total number of breweries matching the filters.
+func (s *BreweryService) SearchBreweriesPage(
+	ctx context.Context,
+	query BrewerySearchQuery,
+) (*BrewerySearchPage, error) {
+	if err := s.validateBreweryQuery(query); err != nil {
+		return nil, err
+	}
+	query = s.normalizeBreweryQuery(query)
+	key := s.cache.key("page", breweryCacheQuery(query))
+	var cached BrewerySearchPage
+	if s.cache.get(ctx, key, &cached) {
+		return &cached, nil
+	}
+
+	page, err := s.countAndSelectBreweries(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	s.cache.set(ctx, key, page)
+	return page, nil
+}
+
+// GetBreweryByID returns the brewery with the given ID and the number of beers it makes. It returns a
+// *NotFoundError, matching ErrNotFound, when no brewery has that ID or the brewery is soft-deleted.
+// Soft-deleted beers are not counted.
+func (s *BreweryService) GetBreweryByID(ctx context.Context, id int) (_ *BreweryDetail, err error) {
+	query := `
+		SELECT br.id, br.name, COALESCE(br.brewery_type, '') AS brewery_type,
+		       COALESCE(br.street, '') AS street, COALESCE(br.city, '') AS city,
+		       COALESCE(br.state, '') AS state, COALESCE(br.postal_code, '') AS postal_code,
+		       COALESCE(br.country, '') AS country, COALESCE(br.phone, '') AS phone,
+		       COALESCE(br.website_url, '') AS website_url, br.latitude, br.longitude,
+		       COALESCE(br.description, '') AS description, COALESCE(br.founded_year, 0) AS founded_year,
+		       COALESCE(br.instagram, '') AS instagram, COALESCE(br.facebook, '') AS facebook,
+		       COALESCE(br.twitter, '') AS twitter, COALESCE(br.email, '') AS email,
+		       br.created_at, br.updated_at,
+		       (SELECT COUNT(*) FROM beers b WHERE b.brewery_id = br.id AND b.deleted_at IS NULL) AS beer_count
+		FROM breweries br
+		WHERE br.id = $1 AND br.deleted_at IS NULL`
+
+	ctx, finish := s.queries.begin(ctx, "get_brewery", query, id)
+	defer finish(&err)
+
+	var brewery BreweryDetail
+	err = s.queries.retry(ctx, "get_brewery", func() error { return s.db.GetContext(ctx, &brewery, query, id) })
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, &NotFoundError{Kind: "brewery", ID: id}
+		}
+		return nil, fmt.Errorf("failed to get brewery %d: %w", id, err)
+	}
+	return &brewery, nil
+}
+
+// GetBreweryBeers returns up to limit of a brewery's beers, sorted by name, along with how many it
+// makes in total, leaving out soft-deleted beers. A limit outside 1 to MaxBreweryBeersLimit falls
+// back to DefaultBreweryBeersLimit. It returns a *NotFoundError, matching ErrNotFound, when no
+// brewery has that ID or the brewery is soft-deleted.
+func (s *BreweryService) GetBreweryBeers(ctx context.Context, breweryID, limit int) (_ *BreweryBeers, err error) {
+	if limit <= 0 || limit > MaxBreweryBeersLimit {
+		limit = DefaultBreweryBeersLimit
+	}
+	// The LEFT JOIN returns a single row with NULL beer columns for a brewery with no beers, and no
+	// rows at all for an unknown brewery.
+	query := `
+		SELECT br.name, b.id, b.name, COALESCE(b.style, ''), COALESCE(b.abv, 0), COALESCE(b.ibu, 0),
+		       COUNT(b.id) OVER () AS total_count
+		FROM breweries br
+		LEFT JOIN beers b ON b.brewery_id = br.id AND b.deleted_at IS NULL
+		WHERE br.id = $1 AND br.deleted_at IS NULL
+		ORDER BY b.name, b.id
+		LIMIT $2`
+
+	ctx, finish := s.queries.begin(ctx, "brewery_beers", query, breweryID)
+	defer finish(&err)
+
+	var rows *sqlx.Rows
+	err = s.queries.retry(ctx, "brewery_beers", func() (err error) {
+		rows, err = s.db.QueryxContext(ctx, query, breweryID, limit)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list beers for brewery %d: %w", breweryID, err)
+	}
+	defer rows.Close()
+
+	found := false
+	result := &BreweryBeers{BreweryID: breweryID, Beers: []*BreweryBeer{}}
+	for rows.Next() {
+		found = true
+		var (
+			beerID   sql.NullInt64
+			beerName sql.NullString
+			beer     BreweryBeer
+		)
+		if err = rows.Scan(
+			&result.Brewery, &beerID, &beerName, &beer.Style, &beer.ABV, &beer.IBU, &result.TotalCount,
+		); err != nil {
+			return nil, fmt.Errorf("failed to list beers for brewery %d: %w", breweryID, err)
+		}
+		if beerID.Valid {
+			beer.ID, beer.Name = int(beerID.Int64), beerName.String
+			result.Beers = append(result.Beers, &beer)
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list beers for brewery %d: %w", breweryID, err)
+	}
+	if !found {
+		return nil, &NotFoundError{Kind: "brewery", ID: breweryID}
+	}
+	return result, nil
+}
+
+// ResolveBreweryID finds the brewery with the given name, ignoring case and soft-deleted breweries. A
+// name that matches no brewery exactly may still match a single brewery by substring. It returns a *NotFoundError when
+// nothing matches and wraps ErrAmbiguousBreweryName when the substring matches several breweries.
+func (s *BreweryService) ResolveBreweryID(ctx context.Context, name string) (_ int, err error) {
+	const (
+		exactQuery = `SELECT id FROM breweries
+			WHERE LOWER(name) = LOWER($1) AND deleted_at IS NULL ORDER BY id LIMIT 1`
+		partialQuery = `SELECT id FROM breweries
+			WHERE LOWER(name) LIKE LOWER($1) AND deleted_at IS NULL ORDER BY id LIMIT 2`
+	)
+	ctx, finish := s.queries.begin(ctx, "resolve_brewery", partialQuery, name)
+	defer finish(&err)
+
+	var ids []int
+	err = s.queries.retry(ctx, "resolve_brewery", func() error {
+		ids = nil
+		if err := s.db.SelectContext(ctx, &ids, exactQuery, name); err != nil || len(ids) > 0 {
+			return err
+		}
+		return s.db.SelectContext(ctx, &ids, partialQuery, "%"+name+"%")
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to resolve brewery %q: %w", name, err)
+	}
+	switch len(ids) {
+	case 0:
+		return 0, &NotFoundError{Kind: "brewery", Name: name}
+	case 1:
+		return ids[0], nil
+	default:
+		return 0, fmt.Errorf("%w: %q; use the brewery ID instead", ErrAmbiguousBreweryName, name)
+	}
+}
+
+// countAndSelectBreweries counts the breweries matching the filters and selects the requested page.
+func (s *BreweryService) countAndSelectBreweries(
+	ctx context.Context,
+	query BrewerySearchQuery,
+) (*BrewerySearchPage, error) {
+	conditions, args := brewerySearchConditions(query)
+	countQuery := `
+		SELECT COUNT(*)
+		FROM breweries
+		WHERE 1=1` + conditions
+
+	page := &BrewerySearchPage{
+		Items:  []*BrewerySearchResult{},
+		Offset: query.Offset,
+		Sort:   query.Sort,
+		Order:  query.Order,
+	}
+	if err := s.countBreweries(ctx, countQuery, args, query, &page.TotalCount); err != nil {
+		return nil, err
+	}
+	if query.Offset >= page.TotalCount {
+		return page, nil
+	}
+
+	items, err := s.selectBreweries(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	page.Items = items
+	page.HasMore = query.Offset+len(items) < page.TotalCount
+	return page, nil
+}
+
+// countBreweries runs the count query of a brewery search.
+func (s *BreweryService) countBreweries(
+	ctx context.Context,
+	countQuery string,
+	args []interface{},
+	query BrewerySearchQuery,
+	count *int,
+) (err error) {
+	ctx, finish := s.queries.begin(ctx, "count_breweries", countQuery, query)
+	defer finish(&err)
+	err = s.queries.retry(ctx, "count_breweries", func() error {
+		return s.db.GetContext(ctx, count, countQuery, args...)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to count breweries: %w", err)
+	}
+	return nil
+}
+
+// validateBreweryQuery validates query and checks that a search by distance can run.
+func (s *BreweryService) validateBreweryQuery(query BrewerySearchQuery) error {
+	if err := query.Validate(); err != nil {
+		return err
+	}
+	if query.hasCoordinates() && !s.distance {
+		return fmt.Errorf("%w: searching by distance is not available on this database", ErrInvalidSearchQuery)
+	}
+	return nil
+}
+
+// normalizeBreweryQuery tidies the whitespace in the text filters, dropping blank values, applies
+// the default page size to a zero limit, clamps a negative offset, resolves Sort and Order to the
+// ordering that will be applied, and lowercases, sorts, and deduplicates the types.
+func (s *BreweryService) normalizeBreweryQuery(query BrewerySearchQuery) BrewerySearchQuery {
+	normalizeSearchFields(query.FoldDiacritics, &query.Name, &query.Location)
+	for _, values := range []*[]string{
+		&query.City, &query.State, &query.Country,
+		&query.Exclude.City, &query.Exclude.State, &query.Exclude.Country,
+	} {
+		*values = normalizeSearchValues(query.FoldDiacritics, *values)
+	}
+	query.Sort, query.Order = resolveSort(s.relevance, query.Sort, query.Order, query.Name)
+	if query.hasCoordinates() {
+		query.Sort, query.Order = SortByDistance, OrderAsc
+	}
+	query.Types = normalizeBreweryTypes(query.Types)
+	query.Exclude.Types = normalizeBreweryTypes(query.Exclude.Types)
+	if query.Limit == 0 {
+		query.Limit = DefaultSearchLimit
+	}
+	if query.Offset < 0 {
+		query.Offset = 0
+	}
+	return query
+}
+
+// normalizeBreweryTypes lowercases, sorts, and deduplicates types.
+func normalizeBreweryTypes(types []string) []string {
+	if len(types) == 0 {
+		return nil
+	}
+	normalized := make([]string, 0, len(types))
+	for _, breweryType := range types {
+		normalized = append(normalized, strings.ToLower(strings.TrimSpace(breweryType)))
+	}
+	slices.Sort(normalized)
+	return slices.Compact(normalized)
+}
+
+// breweryCacheQuery lowercases the filters so that searches differing only in case share a cache
+// entry; filters are compared with LOWER, so case does not change the results.
+func breweryCacheQuery(query BrewerySearchQuery) BrewerySearchQuery {
+	query.Name = strings.ToLower(query.Name)
+	query.Location = strings.ToLower(query.Location)
+	for _, values := range []*[]string{
+		&query.City, &query.State, &query.Country,
+		&query.Exclude.City, &query.Exclude.State, &query.Exclude.Country,
+	} {
+		*values = lowerAll(*values)
+	}
+	return query
+}
+
+// brewerySearchConditions builds the WHERE conditions for a brewery search, numbering arguments from
+// $1. Soft-deleted breweries are left out unless the query includes them. The values of a filter
+// are ORed together, and the filters ANDed.
+func brewerySearchConditions(query BrewerySearchQuery) (string, []interface{}) {
 	var conditions []string
 	var args []interface{}
 	argCount := 0
 
-	baseQuery := `
-		SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url
-		FROM breweries
-		WHERE 1=1`
-
-	if query.Name != "" {
-		argCount++
-		conditions = append(conditions, fmt.Sprintf("LOWER(name) LIKE LOWER($%d)", argCount))
-		args = append(args, "%"+query.Name+"%")
+	if !query.IncludeDeleted {
+		conditions = append(conditions, "deleted_at IS NULL")
 	}
 
-	if query.City != "" {
-		argCount++
-		conditions = append(conditions, fmt.Sprintf("LOWER(city) LIKE LOWER($%d)", argCount))
-		args = append(args, "%"+query.City+"%")
+	column := func(name string) string {
+		if query.FoldDiacritics {
+			return foldedColumn(name)
+		}
+		return "LOWER(" + name + ")"
+	}
+	// like returns the conditions matching column against each of values as a substring.
+	like := func(name string, values []string) []string {
+		matches := make([]string, len(values))
+		for i, value := range values {
+			argCount++
+			matches[i] = fmt.Sprintf("%s LIKE LOWER($%d)", column(name), argCount)
+			args = append(args, "%"+value+"%")
+		}
+		return matches
+	}
+	// in returns the placeholders of an IN list of values.
+	in := func(values []string) string {
+		placeholders := make([]string, len(values))
+		for i, value := range values {
+			argCount++
+			placeholders[i] = fmt.Sprintf("$%d", argCount)
+			args = append(args, value)
+		}
+		return strings.Join(placeholders, ", ")
 	}
 
-	if query.State != "" {
-		argCount++
-		conditions = append(conditions, fmt.Sprintf("LOWER(state) LIKE LOWER($%d)", argCount))
-		args = append(args, "%"+query.State+"%")
+	if query.Name != "" {
+		conditions = append(conditions, like("name", []string{query.Name})...)
 	}
 
-	if query.Country != "" {
-		argCount++
-		conditions = append(conditions, fmt.Sprintf("LOWER(country) LIKE LOWER($%d)", argCount))
-		args = append(args, "%"+query.Country+"%")
+	for _, filter := range []struct {
+		column string
+		values []string
+	}{{"city", query.City}, {"state", query.State}, {"country", query.Country}} {
+		switch matches := like(filter.column, filter.values); len(matches) {
+		case 0:
+		case 1:
+			conditions = append(conditions, matches[0])
+		default:
+			conditions = append(conditions, "("+strings.Join(matches, " OR ")+")")
+		}
 	}
 
 	if query.Location != "" {
@@ -100,29 +618,157 @@ func (s *BreweryService) SearchBreweries(
 		conditions = append(
 			conditions,
 			fmt.Sprintf(
-				"(LOWER(city) LIKE LOWER($%d) OR LOWER(state) LIKE LOWER($%d) OR LOWER(country) LIKE LOWER($%d))",
-				argCount,
-				argCount,
-				argCount,
+				"(%s LIKE LOWER($%d) OR %s LIKE LOWER($%d) OR %s LIKE LOWER($%d))",
+				column("city"), argCount,
+				column("state"), argCount,
+				column("country"), argCount,
 			),
 		)
 		args = append(args, "%"+query.Location+"%")
 	}
 
-	if len(conditions) > 0 {
-		baseQuery += " AND " + strings.Join(conditions, " AND ")
+	if len(query.Types) > 0 {
+		conditions = append(conditions, "brewery_type IN ("+in(query.Types)+")")
 	}
 
-	baseQuery += " ORDER BY name"
-	argCount++
-	baseQuery += fmt.Sprintf(" LIMIT $%d", argCount)
-	args = append(args, query.Limit)
+	for _, filter := range []struct {
+		column string
+		values []string
+	}{{"city", query.Exclude.City}, {"state", query.Exclude.State}, {"country", query.Exclude.Country}} {
+		if matches := like(filter.column, filter.values); len(matches) > 0 {
+			conditions = append(conditions, fmt.Sprintf(
+				"(%s IS NULL OR NOT (%s))", filter.column, strings.Join(matches, " OR "),
+			))
+		}
+	}
+	if len(query.Exclude.Types) > 0 {
+		conditions = append(conditions, "(brewery_type IS NULL OR brewery_type NOT IN ("+in(query.Exclude.Types)+"))")
+	}
 
-	var results []*BrewerySearchResult
-	err := s.db.SelectContext(ctx, &results, baseQuery, args...)
-	if err != nil {
-		return nil, fmt.Errorf("failed to search breweries: %w", err)
+	if query.hasCoordinates() {
+		conditions = append(conditions, "latitude IS NOT NULL AND longitude IS NOT NULL")
+		if query.RadiusKm != nil {
+			args = append(args, *query.Lat, *query.Lng, *query.RadiusKm)
+			argCount += 3
+			conditions = append(
+				conditions,
+				fmt.Sprintf("%s <= $%d", haversineKm("", argCount-2, argCount-1), argCount),
+			)
+		}
+	}
+
+	if len(conditions) == 0 {
+		return "", args
 	}
+	return " AND " + strings.Join(conditions, " AND "), args
+}
+
+// haversineKm returns the SQL for the great-circle distance in kilometres between the latitude and
+// longitude columns, qualified by prefix such as "v.", and the point whose latitude and longitude
+// are bound to the given argument numbers.
+func haversineKm(prefix string, latArg, lngArg int) string {
+	return fmt.Sprintf(
+		"(%g * 2 * ASIN(SQRT(POWER(SIN(RADIANS(%[4]slatitude - $%[2]d) / 2), 2) + "+
+			"COS(RADIANS($%[2]d)) * COS(RADIANS(%[4]slatitude)) * "+
+			"POWER(SIN(RADIANS(%[4]slongitude - $%[3]d) / 2), 2))))",
+		earthRadiusKm, latArg, lngArg, prefix,
+	)
+}
 
+// earthRadiusKm is the mean radius of the Earth used for haversine distances.
+const earthRadiusKm = 6371.0
+
+// selectBreweries runs a normalized brewery search and collects the results.
+func (s *BreweryService) selectBreweries(
+	ctx context.Context,
+	query BrewerySearchQuery,
+) ([]*BrewerySearchResult, error) {
+	results := []*BrewerySearchResult{}
+	for r, err := range s.breweryRows(ctx, query) {
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, r)
+	}
 	return results, nil
 }
+
+// breweryRows runs a normalized brewery search when iterated, yielding each result as it is
+// scanned. A failed query or scan is yielded once, as the last pair, with a nil result.
+func (s *BreweryService) breweryRows(
+	ctx context.Context,
+	query BrewerySearchQuery,
+) iter.Seq2[*BrewerySearchResult, error] {
+	return func(yield func(*BrewerySearchResult, error) bool) {
+		if err := s.scanBreweries(ctx, query, yield); err != nil {
+			yield(nil, fmt.Errorf("failed to search breweries: %w", err))
+		}
+	}
+}
+
+// scanBreweries runs a normalized brewery search, passing each result to yield until it returns
+// false. Results are ordered by the Sort column in the Order direction with ties broken by name, by
+// similarity to the name filter for SortByRelevance, or nearest first for SortByDistance.
+func (s *BreweryService) scanBreweries(
+	ctx context.Context,
+	query BrewerySearchQuery,
+	yield func(*BrewerySearchResult, error) bool,
+) (err error) {
+	conditions, args := brewerySearchConditions(query)
+	columns, order := "", orderBy("name", query.Order)
+	switch {
+	case query.Sort == SortByRelevance:
+		args = append(args, query.Name)
+		columns = fmt.Sprintf(", similarity(name, $%d) AS score", len(args))
+		order = "score DESC, name"
+	case query.Sort == SortByDistance:
+		args = append(args, *query.Lat, *query.Lng)
+		columns = fmt.Sprintf(", %s AS distance_km", haversineKm("", len(args)-1, len(args)))
+		order = "distance_km, name"
+	case query.Sort != SortByName:
+		order = orderBy(brewerySortColumns[query.Sort], query.Order, "name")
+	}
+	if query.IncludeDeleted {
+		columns += ", deleted_at"
+	}
+	baseQuery := `
+		SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url,
+		       ` + breweryProfileColumns + columns + `
+		FROM breweries
+		WHERE 1=1` + conditions
+
+	baseQuery += " ORDER BY " + order
+	if query.Limit > 0 {
+		args = append(args, query.Limit)
+		baseQuery += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+	if query.Offset > 0 {
+		args = append(args, query.Offset)
+		baseQuery += fmt.Sprintf(" OFFSET $%d", len(args))
+	}
+
+	ctx, finish := s.queries.begin(ctx, "search_breweries", baseQuery, query)
+	defer finish(&err)
+
+	var rows *sqlx.Rows
+	err = s.queries.retry(ctx, "search_breweries", func() (err error) {
+		rows, err = s.db.QueryxContext(ctx, baseQuery, args...)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	// Closing the rows also releases the connection when the caller stops iterating early.
+	defer rows.Close()
+
+	for rows.Next() {
+		var r BrewerySearchResult
+		if err = rows.StructScan(&r); err != nil {
+			return err
+		}
+		if !yield(&r, nil) {
+			return nil
+		}
+	}
+	return rows.Err()
+}
@@ -105,33 +105,44 @@ func TestBrewerySearchQuery_DefaultLimits(t *testing.T) {
 		name          string
 		inputLimit    int
 		expectedLimit int
+		wantErr       bool
 	}{
-		{"zero limit defaults to 20", 0, 20},
-		{"negative limit defaults to 20", -5, 20},
-		{"too large limit defaults to 20", 150, 20},
-		{"valid limit preserved", 15, 15},
-		{"max valid limit", 100, 100},
-		{"boundary case - limit 101", 101, 20},
-		{"boundary case - limit 1", 1, 1},
+		{"zero limit defaults to 20", 0, 20, false},
+		{"negative limit is rejected", -5, 0, true},
+		{"too large limit is rejected", 150, 0, true},
+		{"valid limit preserved", 15, 15, false},
+		{"max valid limit", 100, 100, false},
+		{"boundary case - limit 101", 101, 0, true},
+		{"boundary case - limit 1", 1, 1, false},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			query := services.BrewerySearchQuery{
-				Limit: tt.inputLimit,
-			}
+			db, mock := setupMockDB(t)
+			defer db.Close()
+			service := setupBreweryService(db)
 
-			// Simulate the limit adjustment logic that happens in SearchBreweries
-			if query.Limit <= 0 || query.Limit > 100 {
-				query.Limit = 20
+			if !tt.wantErr {
+				mock.ExpectQuery(`ORDER BY name LIMIT \$2$`).
+					WithArgs("%Stone%", tt.expectedLimit).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
 			}
 
-			assert.Equal(t, tt.expectedLimit, query.Limit)
+			_, err := service.SearchBreweries(
+				context.Background(),
+				services.BrewerySearchQuery{Name: "Stone", Limit: tt.inputLimit},
+			)
+
+			if tt.wantErr {
+				require.ErrorIs(t, err, services.ErrInvalidLimit)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
 		})
 	}
 }
 
-// Integration-style tests for query building logic.
 func TestSearchBreweries_QueryBuildingLogic(t *testing.T) {
 	db, mock := setupMockDB(t)
 	defer db.Close()
@@ -150,7 +161,7 @@ func TestSearchBreweries_QueryBuildingLogic(t *testing.T) {
 			query: services.BrewerySearchQuery{
 				Limit: 10,
 			},
-			expectedSQL:  `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url\s+FROM breweries\s+WHERE 1=1\s+ORDER BY name\s+LIMIT \$1`,
+			expectedSQL:  `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url,.* AS email\s+FROM breweries\s+WHERE 1=1 AND deleted_at IS NULL\s+ORDER BY name\s+LIMIT \$1`,
 			expectedArgs: []interface{}{10},
 		},
 		{
@@ -159,7 +170,7 @@ func TestSearchBreweries_QueryBuildingLogic(t *testing.T) {
 				Name:  "Stone",
 				Limit: 15,
 			},
-			expectedSQL:  `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url\s+FROM breweries\s+WHERE 1=1 AND LOWER\(name\) LIKE LOWER\(\$1\)\s+ORDER BY name\s+LIMIT \$2`,
+			expectedSQL:  `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url,.* AS email\s+FROM breweries\s+WHERE 1=1 AND deleted_at IS NULL AND LOWER\(name\) LIKE LOWER\(\$1\)\s+ORDER BY name\s+LIMIT \$2`,
 			expectedArgs: []interface{}{"%Stone%", 15},
 		},
 		{
@@ -168,18 +179,18 @@ func TestSearchBreweries_QueryBuildingLogic(t *testing.T) {
 				Location: "California",
 				Limit:    25,
 			},
-			expectedSQL:  `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url\s+FROM breweries\s+WHERE 1=1 AND \(LOWER\(city\) LIKE LOWER\(\$1\) OR LOWER\(state\) LIKE LOWER\(\$1\) OR LOWER\(country\) LIKE LOWER\(\$1\)\)\s+ORDER BY name\s+LIMIT \$2`,
+			expectedSQL:  `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url,.* AS email\s+FROM breweries\s+WHERE 1=1 AND deleted_at IS NULL AND \(LOWER\(city\) LIKE LOWER\(\$1\) OR LOWER\(state\) LIKE LOWER\(\$1\) OR LOWER\(country\) LIKE LOWER\(\$1\)\)\s+ORDER BY name\s+LIMIT \$2`,
 			expectedArgs: []interface{}{"%California%", 25},
 		},
 		{
 			name: "multiple filters combined",
 			query: services.BrewerySearchQuery{
 				Name:  "Stone",
-				City:  "San Diego",
-				State: "California",
+				City:  []string{"San Diego"},
+				State: []string{"California"},
 				Limit: 10,
 			},
-			expectedSQL:  `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url\s+FROM breweries\s+WHERE 1=1 AND LOWER\(name\) LIKE LOWER\(\$1\) AND LOWER\(city\) LIKE LOWER\(\$2\) AND LOWER\(state\) LIKE LOWER\(\$3\)\s+ORDER BY name\s+LIMIT \$4`,
+			expectedSQL:  `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url,.* AS email\s+FROM breweries\s+WHERE 1=1 AND deleted_at IS NULL AND LOWER\(name\) LIKE LOWER\(\$1\) AND LOWER\(city\) LIKE LOWER\(\$2\) AND LOWER\(state\) LIKE LOWER\(\$3\)\s+ORDER BY name\s+LIMIT \$4`,
 			expectedArgs: []interface{}{"%Stone%", "%San Diego%", "%California%", 10},
 		},
 	}
@@ -232,9 +243,9 @@ func TestSearchBreweries_ResultMapping(t *testing.T) {
 		"https://www.very-long-brewery-name-with-hyphens-and-subdomains.brewery.com/path?param=value",
 	)
 
-	expectedSQL := `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url
+	expectedSQL := `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url,.* AS email
 		FROM breweries
-		WHERE 1=1 AND LOWER\(name\) LIKE LOWER\(\$1\) ORDER BY name LIMIT \$2`
+		WHERE 1=1 AND deleted_at IS NULL AND LOWER\(name\) LIKE LOWER\(\$1\) ORDER BY name LIMIT \$2`
 
 	mock.ExpectQuery(expectedSQL).
 		WithArgs("%Test%", 1).
@@ -303,9 +314,9 @@ func TestSearchBreweries_MemoryLeakPrevention(t *testing.T) {
 			)
 		}
 
-		expectedSQL := `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url
+		expectedSQL := `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url,.* AS email
 		FROM breweries
-		WHERE 1=1 AND LOWER\(name\) LIKE LOWER\(\$1\) ORDER BY name LIMIT \$2`
+		WHERE 1=1 AND deleted_at IS NULL AND LOWER\(name\) LIKE LOWER\(\$1\) ORDER BY name LIMIT \$2`
 
 		mock.ExpectQuery(expectedSQL).
 			WithArgs("%"+fmt.Sprintf("Test%d", iteration)+"%", 50).
@@ -374,9 +385,9 @@ func TestSearchBreweries_PartialMatching(t *testing.T) {
 				)
 			}
 
-			expectedSQL := `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url
+			expectedSQL := `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url,.* AS email
 		FROM breweries
-		WHERE 1=1 AND LOWER\(name\) LIKE LOWER\(\$1\) ORDER BY name LIMIT \$2`
+		WHERE 1=1 AND deleted_at IS NULL AND LOWER\(name\) LIKE LOWER\(\$1\) ORDER BY name LIMIT \$2`
 
 			mock.ExpectQuery(expectedSQL).
 				WithArgs("%"+tc.searchTerm+"%", 20).
@@ -447,9 +458,9 @@ func TestSearchBreweries_ByName_Success(t *testing.T) {
 		getMockBreweryData()[1].Website,
 	)
 
-	expectedSQL := `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url
+	expectedSQL := `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url,.* AS email
 		FROM breweries
-		WHERE 1=1 AND LOWER\(name\) LIKE LOWER\(\$1\) ORDER BY name LIMIT \$2`
+		WHERE 1=1 AND deleted_at IS NULL AND LOWER\(name\) LIKE LOWER\(\$1\) ORDER BY name LIMIT \$2`
 
 	mock.ExpectQuery(expectedSQL).
 		WithArgs("%Stone%", 20).
@@ -488,9 +499,9 @@ func TestSearchBreweries_LocationOrLogic(t *testing.T) {
 			2, "Francisco Brewing", "micro", "456 Oak St", "San Francisco", "CA", "94102", "USA", "+1234567891", "https://sf.com",
 		)
 
-	expectedSQL := `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url
+	expectedSQL := `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url,.* AS email
 		FROM breweries
-		WHERE 1=1 AND \(LOWER\(city\) LIKE LOWER\(\$1\) OR LOWER\(state\) LIKE LOWER\(\$1\) OR LOWER\(country\) LIKE LOWER\(\$1\)\) ORDER BY name LIMIT \$2`
+		WHERE 1=1 AND deleted_at IS NULL AND \(LOWER\(city\) LIKE LOWER\(\$1\) OR LOWER\(state\) LIKE LOWER\(\$1\) OR LOWER\(country\) LIKE LOWER\(\$1\)\) ORDER BY name LIMIT \$2`
 
 	mock.ExpectQuery(expectedSQL).
 		WithArgs("%San%", 20).
@@ -515,9 +526,9 @@ func TestSearchBreweries_MultipleConditionsAndLogic(t *testing.T) {
 
 	query := services.BrewerySearchQuery{
 		Name:     "Stone",
-		City:     "Escondido",
-		State:    "California",
-		Country:  "United States",
+		City:     []string{"Escondido"},
+		State:    []string{"California"},
+		Country:  []string{"United States"},
 		Location: "West Coast", // This should also be included
 		Limit:    20,
 	}
@@ -532,9 +543,9 @@ func TestSearchBreweries_MultipleConditionsAndLogic(t *testing.T) {
 	)
 
 	// All conditions should be ANDed together
-	expectedSQL := `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url
+	expectedSQL := `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url,.* AS email
 		FROM breweries
-		WHERE 1=1 AND LOWER\(name\) LIKE LOWER\(\$1\) AND LOWER\(city\) LIKE LOWER\(\$2\) AND LOWER\(state\) LIKE LOWER\(\$3\) AND LOWER\(country\) LIKE LOWER\(\$4\) AND \(LOWER\(city\) LIKE LOWER\(\$5\) OR LOWER\(state\) LIKE LOWER\(\$5\) OR LOWER\(country\) LIKE LOWER\(\$5\)\) ORDER BY name LIMIT \$6`
+		WHERE 1=1 AND deleted_at IS NULL AND LOWER\(name\) LIKE LOWER\(\$1\) AND LOWER\(city\) LIKE LOWER\(\$2\) AND LOWER\(state\) LIKE LOWER\(\$3\) AND LOWER\(country\) LIKE LOWER\(\$4\) AND \(LOWER\(city\) LIKE LOWER\(\$5\) OR LOWER\(state\) LIKE LOWER\(\$5\) OR LOWER\(country\) LIKE LOWER\(\$5\)\) ORDER BY name LIMIT \$6`
 
 	mock.ExpectQuery(expectedSQL).
 		WithArgs("%Stone%", "%Escondido%", "%California%", "%United States%", "%West Coast%", 20).
@@ -585,9 +596,9 @@ func TestSearchBreweries_PerformanceRegression(t *testing.T) {
 		)
 	}
 
-	expectedSQL := `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url
+	expectedSQL := `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url,.* AS email
 		FROM breweries
-		WHERE 1=1 AND LOWER\(name\) LIKE LOWER\(\$1\) ORDER BY name LIMIT \$2`
+		WHERE 1=1 AND deleted_at IS NULL AND LOWER\(name\) LIKE LOWER\(\$1\) ORDER BY name LIMIT \$2`
 
 	mock.ExpectQuery(expectedSQL).
 		WithArgs("%Test%", 50).
@@ -623,9 +634,9 @@ func TestSearchBreweries_ErrorWrapping(t *testing.T) {
 
 	originalErr := sql.ErrTxDone
 
-	expectedSQL := `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url
+	expectedSQL := `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url,.* AS email
 		FROM breweries
-		WHERE 1=1 AND LOWER\(name\) LIKE LOWER\(\$1\) ORDER BY name LIMIT \$2`
+		WHERE 1=1 AND deleted_at IS NULL AND LOWER\(name\) LIKE LOWER\(\$1\) ORDER BY name LIMIT \$2`
 
 	mock.ExpectQuery(expectedSQL).
 		WithArgs("%Test%", 20).
@@ -660,10 +671,11 @@ func TestBrewerySearchResult_DatabaseTagConsistency(t *testing.T) {
 		"country":      brewery.Country,
 		"phone":        brewery.Phone,
 		"website_url":  brewery.Website,
+		"score":        brewery.Score,
 	}
 
 	// Verify we have all expected fields
-	assert.Len(t, expectedFields, 10, "services.BrewerySearchResult should have exactly 10 fields")
+	assert.Len(t, expectedFields, 11, "services.BrewerySearchResult should have exactly 11 fields")
 }
 
 // Table-driven test for all search combinations.
@@ -686,7 +698,7 @@ func TestSearchBreweries_AllSearchCombinations(t *testing.T) {
 		{
 			name: "city only",
 			query: services.BrewerySearchQuery{
-				City:  "Escondido",
+				City:  []string{"Escondido"},
 				Limit: 20,
 			},
 			expectedArgs: []interface{}{"%Escondido%", 20},
@@ -695,7 +707,7 @@ func TestSearchBreweries_AllSearchCombinations(t *testing.T) {
 		{
 			name: "state only",
 			query: services.BrewerySearchQuery{
-				State: "California",
+				State: []string{"California"},
 				Limit: 20,
 			},
 			expectedArgs: []interface{}{"%California%", 20},
@@ -704,7 +716,7 @@ func TestSearchBreweries_AllSearchCombinations(t *testing.T) {
 		{
 			name: "country only",
 			query: services.BrewerySearchQuery{
-				Country: "United States",
+				Country: []string{"United States"},
 				Limit:   20,
 			},
 			expectedArgs: []interface{}{"%United States%", 20},
@@ -723,7 +735,7 @@ func TestSearchBreweries_AllSearchCombinations(t *testing.T) {
 			name: "name and city",
 			query: services.BrewerySearchQuery{
 				Name:  "Stone",
-				City:  "Escondido",
+				City:  []string{"Escondido"},
 				Limit: 20,
 			},
 			expectedArgs: []interface{}{"%Stone%", "%Escondido%", 20},
@@ -745,18 +757,18 @@ func TestSearchBreweries_AllSearchCombinations(t *testing.T) {
 			// Build the expected SQL based on the query parameters
 			var expectedSQL string
 			switch {
-			case tc.query.Name != "" && tc.query.City != "":
-				expectedSQL = `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url\s+FROM breweries\s+WHERE 1=1 AND LOWER\(name\) LIKE LOWER\(\$1\) AND LOWER\(city\) LIKE LOWER\(\$2\)\s+ORDER BY name\s+LIMIT \$3`
+			case tc.query.Name != "" && len(tc.query.City) > 0:
+				expectedSQL = `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url,.* AS email\s+FROM breweries\s+WHERE 1=1 AND deleted_at IS NULL AND LOWER\(name\) LIKE LOWER\(\$1\) AND LOWER\(city\) LIKE LOWER\(\$2\)\s+ORDER BY name\s+LIMIT \$3`
 			case tc.query.Name != "":
-				expectedSQL = `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url\s+FROM breweries\s+WHERE 1=1 AND LOWER\(name\) LIKE LOWER\(\$1\)\s+ORDER BY name\s+LIMIT \$2`
-			case tc.query.City != "":
-				expectedSQL = `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url\s+FROM breweries\s+WHERE 1=1 AND LOWER\(city\) LIKE LOWER\(\$1\)\s+ORDER BY name\s+LIMIT \$2`
-			case tc.query.State != "":
-				expectedSQL = `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url\s+FROM breweries\s+WHERE 1=1 AND LOWER\(state\) LIKE LOWER\(\$1\)\s+ORDER BY name\s+LIMIT \$2`
-			case tc.query.Country != "":
-				expectedSQL = `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url\s+FROM breweries\s+WHERE 1=1 AND LOWER\(country\) LIKE LOWER\(\$1\)\s+ORDER BY name\s+LIMIT \$2`
+				expectedSQL = `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url,.* AS email\s+FROM breweries\s+WHERE 1=1 AND deleted_at IS NULL AND LOWER\(name\) LIKE LOWER\(\$1\)\s+ORDER BY name\s+LIMIT \$2`
+			case len(tc.query.City) > 0:
+				expectedSQL = `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url,.* AS email\s+FROM breweries\s+WHERE 1=1 AND deleted_at IS NULL AND LOWER\(city\) LIKE LOWER\(\$1\)\s+ORDER BY name\s+LIMIT \$2`
+			case len(tc.query.State) > 0:
+				expectedSQL = `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url,.* AS email\s+FROM breweries\s+WHERE 1=1 AND deleted_at IS NULL AND LOWER\(state\) LIKE LOWER\(\$1\)\s+ORDER BY name\s+LIMIT \$2`
+			case len(tc.query.Country) > 0:
+				expectedSQL = `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url,.* AS email\s+FROM breweries\s+WHERE 1=1 AND deleted_at IS NULL AND LOWER\(country\) LIKE LOWER\(\$1\)\s+ORDER BY name\s+LIMIT \$2`
 			case tc.query.Location != "":
-				expectedSQL = `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url\s+FROM breweries\s+WHERE 1=1 AND \(LOWER\(city\) LIKE LOWER\(\$1\) OR LOWER\(state\) LIKE LOWER\(\$1\) OR LOWER\(country\) LIKE LOWER\(\$1\)\)\s+ORDER BY name\s+LIMIT \$2`
+				expectedSQL = `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url,.* AS email\s+FROM breweries\s+WHERE 1=1 AND deleted_at IS NULL AND \(LOWER\(city\) LIKE LOWER\(\$1\) OR LOWER\(state\) LIKE LOWER\(\$1\) OR LOWER\(country\) LIKE LOWER\(\$1\)\)\s+ORDER BY name\s+LIMIT \$2`
 			}
 
 			mock.ExpectQuery(expectedSQL).
@@ -780,7 +792,7 @@ func TestSearchBreweries_ByCity_Success(t *testing.T) {
 	service := setupBreweryService(db)
 
 	query := services.BrewerySearchQuery{
-		City:  "Woodstock",
+		City:  []string{"Woodstock"},
 		Limit: 10,
 	}
 
@@ -793,9 +805,9 @@ func TestSearchBreweries_ByCity_Success(t *testing.T) {
 		getMockBreweryData()[0].Website,
 	)
 
-	expectedSQL := `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url
+	expectedSQL := `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url,.* AS email
 		FROM breweries
-		WHERE 1=1 AND LOWER\(city\) LIKE LOWER\(\$1\) ORDER BY name LIMIT \$2`
+		WHERE 1=1 AND deleted_at IS NULL AND LOWER\(city\) LIKE LOWER\(\$1\) ORDER BY name LIMIT \$2`
 
 	mock.ExpectQuery(expectedSQL).
 		WithArgs("%Woodstock%", 10).
@@ -830,9 +842,9 @@ func TestSearchBreweries_ByLocation_Success(t *testing.T) {
 		getMockBreweryData()[1].Website,
 	)
 
-	expectedSQL := `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url
+	expectedSQL := `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url,.* AS email
 		FROM breweries
-		WHERE 1=1 AND \(LOWER\(city\) LIKE LOWER\(\$1\) OR LOWER\(state\) LIKE LOWER\(\$1\) OR LOWER\(country\) LIKE LOWER\(\$1\)\) ORDER BY name LIMIT \$2`
+		WHERE 1=1 AND deleted_at IS NULL AND \(LOWER\(city\) LIKE LOWER\(\$1\) OR LOWER\(state\) LIKE LOWER\(\$1\) OR LOWER\(country\) LIKE LOWER\(\$1\)\) ORDER BY name LIMIT \$2`
 
 	mock.ExpectQuery(expectedSQL).
 		WithArgs("%California%", 5).
@@ -855,8 +867,8 @@ func TestSearchBreweries_MultipleFilters_Success(t *testing.T) {
 
 	query := services.BrewerySearchQuery{
 		Name:    "Stone",
-		State:   "California",
-		Country: "United States",
+		State:   []string{"California"},
+		Country: []string{"United States"},
 		Limit:   15,
 	}
 
@@ -869,9 +881,9 @@ func TestSearchBreweries_MultipleFilters_Success(t *testing.T) {
 		getMockBreweryData()[1].Website,
 	)
 
-	expectedSQL := `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url
+	expectedSQL := `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url,.* AS email
 		FROM breweries
-		WHERE 1=1 AND LOWER\(name\) LIKE LOWER\(\$1\) AND LOWER\(state\) LIKE LOWER\(\$2\) AND LOWER\(country\) LIKE LOWER\(\$3\) ORDER BY name LIMIT \$4`
+		WHERE 1=1 AND deleted_at IS NULL AND LOWER\(name\) LIKE LOWER\(\$1\) AND LOWER\(state\) LIKE LOWER\(\$2\) AND LOWER\(country\) LIKE LOWER\(\$3\) ORDER BY name LIMIT \$4`
 
 	mock.ExpectQuery(expectedSQL).
 		WithArgs("%Stone%", "%California%", "%United States%", 15).
@@ -901,7 +913,7 @@ func TestSearchBreweries_NoResults(t *testing.T) {
 		"id", "name", "brewery_type", "street", "city", "state", "postal_code", "country", "phone", "website_url",
 	})
 
-	expectedSQL := `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url\s+FROM breweries\s+WHERE 1=1 AND LOWER\(name\) LIKE LOWER\(\$1\)\s+ORDER BY name\s+LIMIT \$2`
+	expectedSQL := `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url,.* AS email\s+FROM breweries\s+WHERE 1=1 AND deleted_at IS NULL AND LOWER\(name\) LIKE LOWER\(\$1\)\s+ORDER BY name\s+LIMIT \$2`
 
 	mock.ExpectQuery(expectedSQL).
 		WithArgs("%NonexistentBrewery%", 20).
@@ -929,7 +941,7 @@ func TestSearchBreweries_DatabaseError(t *testing.T) {
 		Limit: 20,
 	}
 
-	expectedSQL := `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url\s+FROM breweries\s+WHERE 1=1 AND LOWER\(name\) LIKE LOWER\(\$1\)\s+ORDER BY name\s+LIMIT \$2`
+	expectedSQL := `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url,.* AS email\s+FROM breweries\s+WHERE 1=1 AND deleted_at IS NULL AND LOWER\(name\) LIKE LOWER\(\$1\)\s+ORDER BY name\s+LIMIT \$2`
 
 	mock.ExpectQuery(expectedSQL).
 		WithArgs("%Test%", 20).
@@ -967,9 +979,9 @@ func TestSearchBreweries_EmptyFilters(t *testing.T) {
 		)
 	}
 
-	expectedSQL := `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url
+	expectedSQL := `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url,.* AS email
 		FROM breweries
-		WHERE 1=1 ORDER BY name LIMIT \$1`
+		WHERE 1=1 AND deleted_at IS NULL ORDER BY name LIMIT \$1`
 
 	mock.ExpectQuery(expectedSQL).
 		WithArgs(10).
@@ -1003,9 +1015,9 @@ func TestSearchBreweries_CaseInsensitive(t *testing.T) {
 		getMockBreweryData()[1].Website,
 	)
 
-	expectedSQL := `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url
+	expectedSQL := `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url,.* AS email
 		FROM breweries
-		WHERE 1=1 AND LOWER\(name\) LIKE LOWER\(\$1\) ORDER BY name LIMIT \$2`
+		WHERE 1=1 AND deleted_at IS NULL AND LOWER\(name\) LIKE LOWER\(\$1\) ORDER BY name LIMIT \$2`
 
 	mock.ExpectQuery(expectedSQL).
 		WithArgs("%STONE%", 20).
@@ -1040,9 +1052,9 @@ func TestSearchBreweries_SpecialCharacters(t *testing.T) {
 		getMockBreweryData()[0].Website,
 	)
 
-	expectedSQL := `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url
+	expectedSQL := `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url,.* AS email
 		FROM breweries
-		WHERE 1=1 AND LOWER\(name\) LIKE LOWER\(\$1\) ORDER BY name LIMIT \$2`
+		WHERE 1=1 AND deleted_at IS NULL AND LOWER\(name\) LIKE LOWER\(\$1\) ORDER BY name LIMIT \$2`
 
 	mock.ExpectQuery(expectedSQL).
 		WithArgs("%Devil's%", 20).
@@ -1072,9 +1084,9 @@ func TestSearchBreweries_UnicodeCharacters(t *testing.T) {
 		"id", "name", "brewery_type", "street", "city", "state", "postal_code", "country", "phone", "website_url",
 	})
 
-	expectedSQL := `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url
+	expectedSQL := `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url,.* AS email
 		FROM breweries
-		WHERE 1=1 AND LOWER\(name\) LIKE LOWER\(\$1\) ORDER BY name LIMIT \$2`
+		WHERE 1=1 AND deleted_at IS NULL AND LOWER\(name\) LIKE LOWER\(\$1\) ORDER BY name LIMIT \$2`
 
 	mock.ExpectQuery(expectedSQL).
 		WithArgs("%Bières%", 20).
@@ -1100,9 +1112,9 @@ func TestSearchBreweries_ContextTimeout(t *testing.T) {
 		Limit: 20,
 	}
 
-	expectedSQL := `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url
+	expectedSQL := `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url,.* AS email
 		FROM breweries
-		WHERE 1=1 AND LOWER\(name\) LIKE LOWER\(\$1\) ORDER BY name LIMIT \$2`
+		WHERE 1=1 AND deleted_at IS NULL AND LOWER\(name\) LIKE LOWER\(\$1\) ORDER BY name LIMIT \$2`
 
 	mock.ExpectQuery(expectedSQL).
 		WithArgs("%Test%", 20).
@@ -1131,9 +1143,9 @@ func TestSearchBreweries_ContextCancellation(t *testing.T) {
 		Limit: 20,
 	}
 
-	expectedSQL := `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url
+	expectedSQL := `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url,.* AS email
 		FROM breweries
-		WHERE 1=1 AND LOWER\(name\) LIKE LOWER\(\$1\) ORDER BY name LIMIT \$2`
+		WHERE 1=1 AND deleted_at IS NULL AND LOWER\(name\) LIKE LOWER\(\$1\) ORDER BY name LIMIT \$2`
 
 	mock.ExpectQuery(expectedSQL).
 		WithArgs("%Test%", 20).
@@ -1178,9 +1190,9 @@ func TestSearchBreweries_LargeResultSet(t *testing.T) {
 		)
 	}
 
-	expectedSQL := `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url
+	expectedSQL := `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url,.* AS email
 		FROM breweries
-		WHERE 1=1 AND LOWER\(name\) LIKE LOWER\(\$1\) ORDER BY name LIMIT \$2`
+		WHERE 1=1 AND deleted_at IS NULL AND LOWER\(name\) LIKE LOWER\(\$1\) ORDER BY name LIMIT \$2`
 
 	mock.ExpectQuery(expectedSQL).
 		WithArgs("%Brewing%", 100).
@@ -1221,9 +1233,9 @@ func TestSearchBreweries_ComplexLocationSearch(t *testing.T) {
 		)
 	}
 
-	expectedSQL := `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url
+	expectedSQL := `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url,.* AS email
 		FROM breweries
-		WHERE 1=1 AND \(LOWER\(city\) LIKE LOWER\(\$1\) OR LOWER\(state\) LIKE LOWER\(\$1\) OR LOWER\(country\) LIKE LOWER\(\$1\)\) ORDER BY name LIMIT \$2`
+		WHERE 1=1 AND deleted_at IS NULL AND \(LOWER\(city\) LIKE LOWER\(\$1\) OR LOWER\(state\) LIKE LOWER\(\$1\) OR LOWER\(country\) LIKE LOWER\(\$1\)\) ORDER BY name LIMIT \$2`
 
 	mock.ExpectQuery(expectedSQL).
 		WithArgs("%United%", 20).
@@ -1253,9 +1265,9 @@ func TestSearchBreweries_AllEmptyFields(t *testing.T) {
 	query := services.BrewerySearchQuery{
 		Name:     "",
 		Location: "",
-		City:     "",
-		State:    "",
-		Country:  "",
+		City:     []string{""},
+		State:    []string{""},
+		Country:  []string{""},
 		Limit:    0, // This should default to 20
 	}
 
@@ -1263,7 +1275,7 @@ func TestSearchBreweries_AllEmptyFields(t *testing.T) {
 		"id", "name", "brewery_type", "street", "city", "state", "postal_code", "country", "phone", "website_url",
 	})
 
-	expectedSQL := `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url\s+FROM breweries\s+WHERE 1=1\s+ORDER BY name\s+LIMIT \$1`
+	expectedSQL := `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url,.* AS email\s+FROM breweries\s+WHERE 1=1 AND deleted_at IS NULL\s+ORDER BY name\s+LIMIT \$1`
 
 	mock.ExpectQuery(expectedSQL).
 		WithArgs(20). // Should default to 20
@@ -1298,7 +1310,7 @@ func TestSearchBreweries_SQLInjectionAttempts(t *testing.T) {
 		{
 			name: "SQL injection in city field",
 			query: services.BrewerySearchQuery{
-				City:  "' OR 1=1 --",
+				City:  []string{"' OR 1=1 --"},
 				Limit: 20,
 			},
 			expectErr: false,
@@ -1306,7 +1318,7 @@ func TestSearchBreweries_SQLInjectionAttempts(t *testing.T) {
 		{
 			name: "SQL injection in state field",
 			query: services.BrewerySearchQuery{
-				State: "' UNION SELECT * FROM users --",
+				State: []string{"' UNION SELECT * FROM users --"},
 				Limit: 20,
 			},
 			expectErr: false,
@@ -1314,7 +1326,7 @@ func TestSearchBreweries_SQLInjectionAttempts(t *testing.T) {
 		{
 			name: "SQL injection in country field",
 			query: services.BrewerySearchQuery{
-				Country: "' OR '1'='1",
+				Country: []string{"' OR '1'='1"},
 				Limit:   20,
 			},
 			expectErr: false,
@@ -1347,19 +1359,19 @@ func TestSearchBreweries_SQLInjectionAttempts(t *testing.T) {
 
 			switch {
 			case tc.query.Name != "":
-				expectedSQL = `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url\s+FROM breweries\s+WHERE 1=1 AND LOWER\(name\) LIKE LOWER\(\$1\)\s+ORDER BY name\s+LIMIT \$2`
+				expectedSQL = `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url,.* AS email\s+FROM breweries\s+WHERE 1=1 AND deleted_at IS NULL AND LOWER\(name\) LIKE LOWER\(\$1\)\s+ORDER BY name\s+LIMIT \$2`
 				expectedArgs = []interface{}{"%" + tc.query.Name + "%", 20}
-			case tc.query.City != "":
-				expectedSQL = `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url\s+FROM breweries\s+WHERE 1=1 AND LOWER\(city\) LIKE LOWER\(\$1\)\s+ORDER BY name\s+LIMIT \$2`
-				expectedArgs = []interface{}{"%" + tc.query.City + "%", 20}
-			case tc.query.State != "":
-				expectedSQL = `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url\s+FROM breweries\s+WHERE 1=1 AND LOWER\(state\) LIKE LOWER\(\$1\)\s+ORDER BY name\s+LIMIT \$2`
-				expectedArgs = []interface{}{"%" + tc.query.State + "%", 20}
-			case tc.query.Country != "":
-				expectedSQL = `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url\s+FROM breweries\s+WHERE 1=1 AND LOWER\(country\) LIKE LOWER\(\$1\)\s+ORDER BY name\s+LIMIT \$2`
-				expectedArgs = []interface{}{"%" + tc.query.Country + "%", 20}
+			case len(tc.query.City) > 0:
+				expectedSQL = `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url,.* AS email\s+FROM breweries\s+WHERE 1=1 AND deleted_at IS NULL AND LOWER\(city\) LIKE LOWER\(\$1\)\s+ORDER BY name\s+LIMIT \$2`
+				expectedArgs = []interface{}{"%" + tc.query.City[0] + "%", 20}
+			case len(tc.query.State) > 0:
+				expectedSQL = `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url,.* AS email\s+FROM breweries\s+WHERE 1=1 AND deleted_at IS NULL AND LOWER\(state\) LIKE LOWER\(\$1\)\s+ORDER BY name\s+LIMIT \$2`
+				expectedArgs = []interface{}{"%" + tc.query.State[0] + "%", 20}
+			case len(tc.query.Country) > 0:
+				expectedSQL = `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url,.* AS email\s+FROM breweries\s+WHERE 1=1 AND deleted_at IS NULL AND LOWER\(country\) LIKE LOWER\(\$1\)\s+ORDER BY name\s+LIMIT \$2`
+				expectedArgs = []interface{}{"%" + tc.query.Country[0] + "%", 20}
 			case tc.query.Location != "":
-				expectedSQL = `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url\s+FROM breweries\s+WHERE 1=1 AND \(LOWER\(city\) LIKE LOWER\(\$1\) OR LOWER\(state\) LIKE LOWER\(\$1\) OR LOWER\(country\) LIKE LOWER\(\$1\)\)\s+ORDER BY name\s+LIMIT \$2`
+				expectedSQL = `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url,.* AS email\s+FROM breweries\s+WHERE 1=1 AND deleted_at IS NULL AND \(LOWER\(city\) LIKE LOWER\(\$1\) OR LOWER\(state\) LIKE LOWER\(\$1\) OR LOWER\(country\) LIKE LOWER\(\$1\)\)\s+ORDER BY name\s+LIMIT \$2`
 				expectedArgs = []interface{}{"%" + tc.query.Location + "%", 20}
 			}
 
@@ -1403,9 +1415,9 @@ func TestSearchBreweries_NullValueHandling(t *testing.T) {
 		2, "Another Brewery", "", "123 Main St", "", "CA", "", "", "+1234567890", "https://test.com",
 	)
 
-	expectedSQL := `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url
+	expectedSQL := `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url,.* AS email
 		FROM breweries
-		WHERE 1=1 AND LOWER\(name\) LIKE LOWER\(\$1\) ORDER BY name LIMIT \$2`
+		WHERE 1=1 AND deleted_at IS NULL AND LOWER\(name\) LIKE LOWER\(\$1\) ORDER BY name LIMIT \$2`
 
 	mock.ExpectQuery(expectedSQL).
 		WithArgs("%Test%", 20).
@@ -1438,7 +1450,7 @@ func TestSearchBreweries_LongFieldValues(t *testing.T) {
 
 	query := services.BrewerySearchQuery{
 		Name:  longName,
-		City:  longCity,
+		City:  []string{longCity},
 		Limit: 20,
 	}
 
@@ -1446,10 +1458,10 @@ func TestSearchBreweries_LongFieldValues(t *testing.T) {
 		"id", "name", "brewery_type", "street", "city", "state", "postal_code", "country", "phone", "website_url",
 	}).AddRow(1, "Test Brewery", "micro", "123 Test St", "Test City", "Test State", "12345", "Test Country", "123-456-7890", "https://test.com")
 
-	expectedSQL := `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url\s+FROM breweries\s+WHERE 1=1 AND LOWER\(name\) LIKE LOWER\(\$1\) AND LOWER\(city\) LIKE LOWER\(\$2\)\s+ORDER BY name\s+LIMIT \$3`
+	expectedSQL := `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url,.* AS email\s+FROM breweries\s+WHERE 1=1 AND deleted_at IS NULL AND LOWER\(name\) LIKE LOWER\(\$1\) AND LOWER\(city\) LIKE LOWER\(\$2\)\s+ORDER BY name\s+LIMIT \$3`
 
 	mock.ExpectQuery(expectedSQL).
-		WithArgs("%"+longName+"%", "%"+longCity+"%", 20).
+		WithArgs("%"+strings.TrimSpace(longName)+"%", "%"+strings.TrimSpace(longCity)+"%", 20).
 		WillReturnRows(rows)
 
 	ctx := context.Background()
@@ -1496,9 +1508,9 @@ func TestSearchBreweries_ConcurrentRequests(t *testing.T) {
 				1, fmt.Sprintf("Test Brewery %d", routineID), "micro", "123 Main St", "Test City", "CA", "12345", "USA", "+1234567890", "https://test.com",
 			)
 
-			expectedSQL := `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url
+			expectedSQL := `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url,.* AS email
 				FROM breweries
-				WHERE 1=1 AND LOWER\(name\) LIKE LOWER\(\$1\) ORDER BY name LIMIT \$2`
+				WHERE 1=1 AND deleted_at IS NULL AND LOWER\(name\) LIKE LOWER\(\$1\) ORDER BY name LIMIT \$2`
 
 			mock.ExpectQuery(expectedSQL).
 				WithArgs("%Test%", 20).
@@ -1569,9 +1581,9 @@ func TestSearchBreweries_MemoryUsage(t *testing.T) {
 		)
 	}
 
-	expectedSQL := `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url
+	expectedSQL := `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url,.* AS email
 		FROM breweries
-		WHERE 1=1 AND LOWER\(name\) LIKE LOWER\(\$1\) ORDER BY name LIMIT \$2`
+		WHERE 1=1 AND deleted_at IS NULL AND LOWER\(name\) LIKE LOWER\(\$1\) ORDER BY name LIMIT \$2`
 
 	mock.ExpectQuery(expectedSQL).
 		WithArgs("%Test%", 100).
@@ -1612,9 +1624,9 @@ func TestSearchBreweries_OrderByName(t *testing.T) {
 			3, "Charlie Brewing", "micro", "789 Pine St", "Test City", "CA", "12345", "USA", "+1234567892", "https://charlie.com",
 		)
 
-	expectedSQL := `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url
+	expectedSQL := `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url,.* AS email
 		FROM breweries
-		WHERE 1=1 AND LOWER\(name\) LIKE LOWER\(\$1\) ORDER BY name LIMIT \$2`
+		WHERE 1=1 AND deleted_at IS NULL AND LOWER\(name\) LIKE LOWER\(\$1\) ORDER BY name LIMIT \$2`
 
 	mock.ExpectQuery(expectedSQL).
 		WithArgs("%Brewing%", 20).
@@ -1642,9 +1654,10 @@ func TestSearchBreweries_WhitespaceHandling(t *testing.T) {
 	service := setupBreweryService(db)
 
 	query := services.BrewerySearchQuery{
-		Name:  "  Stone  ",     // Leading and trailing whitespace
-		City:  "\tEscondido\n", // Tab and newline characters
-		Limit: 20,
+		Name:    "  Stone   Brewing  ",     // Leading, trailing, and repeated whitespace
+		City:    []string{"\tEscondido\n"}, // Tab and newline characters
+		Country: []string{"   "},           // Blank after trimming, so not filtered on
+		Limit:   20,
 	}
 
 	rows := sqlmock.NewRows([]string{
@@ -1656,12 +1669,13 @@ func TestSearchBreweries_WhitespaceHandling(t *testing.T) {
 		getMockBreweryData()[1].Website,
 	)
 
-	expectedSQL := `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url
+	expectedSQL := `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url,.* AS email
 		FROM breweries
-		WHERE 1=1 AND LOWER\(name\) LIKE LOWER\(\$1\) AND LOWER\(city\) LIKE LOWER\(\$2\) ORDER BY name LIMIT \$3`
+		WHERE 1=1 AND deleted_at IS NULL AND LOWER\(name\) LIKE LOWER\(\$1\) AND LOWER\(city\) LIKE LOWER\(\$2\)
+		ORDER BY name LIMIT \$3`
 
 	mock.ExpectQuery(expectedSQL).
-		WithArgs("%  Stone  %", "%\tEscondido\n%", 20).
+		WithArgs("%Stone Brewing%", "%Escondido%", 20).
 		WillReturnRows(rows)
 
 	ctx := context.Background()
@@ -1672,6 +1686,31 @@ func TestSearchBreweries_WhitespaceHandling(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestSearchBreweries_FoldDiacritics(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+	service := setupBreweryService(db)
+
+	expectedSQL := `WHERE 1=1 AND deleted_at IS NULL` +
+		` AND TRANSLATE\(LOWER\(name\), '[^']+', '[^']+'\) LIKE LOWER\(\$1\) ` +
+		`AND \(TRANSLATE\(LOWER\(city\), .*\) LIKE LOWER\(\$2\) OR TRANSLATE\(LOWER\(state\), .*\) LIKE LOWER\(\$2\) ` +
+		`OR TRANSLATE\(LOWER\(country\), .*\) LIKE LOWER\(\$2\)\) ORDER BY name LIMIT \$3$`
+	mock.ExpectQuery(expectedSQL).
+		WithArgs("%brau haus%", "%zurich%", 20).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "city"}).AddRow(1, "Bräu Haus", "Zürich"))
+
+	results, err := service.SearchBreweries(context.Background(), services.BrewerySearchQuery{
+		Name:           " Bräu  Haus ",
+		Location:       "ZÜRICH",
+		FoldDiacritics: true,
+	})
+
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "Bräu Haus", results[0].Name)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 // Database Error Scenarios.
 func TestSearchBreweries_DatabaseConnectionError(t *testing.T) {
 	db, mock := setupMockDB(t)
@@ -1684,9 +1723,9 @@ func TestSearchBreweries_DatabaseConnectionError(t *testing.T) {
 		Limit: 20,
 	}
 
-	expectedSQL := `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url
+	expectedSQL := `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url,.* AS email
 		FROM breweries
-		WHERE 1=1 AND LOWER\(name\) LIKE LOWER\(\$1\) ORDER BY name LIMIT \$2`
+		WHERE 1=1 AND deleted_at IS NULL AND LOWER\(name\) LIKE LOWER\(\$1\) ORDER BY name LIMIT \$2`
 
 	mock.ExpectQuery(expectedSQL).
 		WithArgs("%Test%", 20).
@@ -1712,9 +1751,9 @@ func TestSearchBreweries_QuerySyntaxError(t *testing.T) {
 		Limit: 20,
 	}
 
-	expectedSQL := `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url
+	expectedSQL := `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url,.* AS email
 		FROM breweries
-		WHERE 1=1 AND LOWER\(name\) LIKE LOWER\(\$1\) ORDER BY name LIMIT \$2`
+		WHERE 1=1 AND deleted_at IS NULL AND LOWER\(name\) LIKE LOWER\(\$1\) ORDER BY name LIMIT \$2`
 
 	mock.ExpectQuery(expectedSQL).
 		WithArgs("%Test%", 20).
@@ -1747,9 +1786,9 @@ func TestSearchBreweries_SingleCharacterSearch(t *testing.T) {
 		1, "Alpha Brewing", "micro", "123 Main St", "Test City", "CA", "12345", "USA", "+1234567890", "https://alpha.com",
 	)
 
-	expectedSQL := `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url
+	expectedSQL := `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url,.* AS email
 		FROM breweries
-		WHERE 1=1 AND LOWER\(name\) LIKE LOWER\(\$1\) ORDER BY name LIMIT \$2`
+		WHERE 1=1 AND deleted_at IS NULL AND LOWER\(name\) LIKE LOWER\(\$1\) ORDER BY name LIMIT \$2`
 
 	mock.ExpectQuery(expectedSQL).
 		WithArgs("%A%", 20).
@@ -1772,7 +1811,7 @@ func TestSearchBreweries_StateFilter(t *testing.T) {
 	service := setupBreweryService(db)
 
 	query := services.BrewerySearchQuery{
-		State: "California",
+		State: []string{"California"},
 		Limit: 20,
 	}
 
@@ -1785,9 +1824,9 @@ func TestSearchBreweries_StateFilter(t *testing.T) {
 		getMockBreweryData()[1].Website,
 	)
 
-	expectedSQL := `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url
+	expectedSQL := `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url,.* AS email
 		FROM breweries
-		WHERE 1=1 AND LOWER\(state\) LIKE LOWER\(\$1\) ORDER BY name LIMIT \$2`
+		WHERE 1=1 AND deleted_at IS NULL AND LOWER\(state\) LIKE LOWER\(\$1\) ORDER BY name LIMIT \$2`
 
 	mock.ExpectQuery(expectedSQL).
 		WithArgs("%California%", 20).
@@ -1809,7 +1848,7 @@ func TestSearchBreweries_CountryFilter(t *testing.T) {
 	service := setupBreweryService(db)
 
 	query := services.BrewerySearchQuery{
-		Country: "South Africa",
+		Country: []string{"South Africa"},
 		Limit:   20,
 	}
 
@@ -1822,9 +1861,9 @@ func TestSearchBreweries_CountryFilter(t *testing.T) {
 		getMockBreweryData()[0].Website,
 	)
 
-	expectedSQL := `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url
+	expectedSQL := `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url,.* AS email
 		FROM breweries
-		WHERE 1=1 AND LOWER\(country\) LIKE LOWER\(\$1\) ORDER BY name LIMIT \$2`
+		WHERE 1=1 AND deleted_at IS NULL AND LOWER\(country\) LIKE LOWER\(\$1\) ORDER BY name LIMIT \$2`
 
 	mock.ExpectQuery(expectedSQL).
 		WithArgs("%South Africa%", 20).
@@ -1857,9 +1896,9 @@ func BenchmarkSearchBreweries_SingleField(b *testing.B) {
 		1, "Test Brewery", "micro", "123 Main St", "Test City", "CA", "12345", "USA", "+1234567890", "https://test.com",
 	)
 
-	expectedSQL := `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url
+	expectedSQL := `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url,.* AS email
 		FROM breweries
-		WHERE 1=1 AND LOWER\(name\) LIKE LOWER\(\$1\) ORDER BY name LIMIT \$2`
+		WHERE 1=1 AND deleted_at IS NULL AND LOWER\(name\) LIKE LOWER\(\$1\) ORDER BY name LIMIT \$2`
 
 	for range b.N {
 		mock.ExpectQuery(expectedSQL).
@@ -1881,66 +1920,46 @@ func BenchmarkSearchBreweries_SingleField(b *testing.B) {
 // Helper function tests.
 func TestBrewerySearchQuery_ValidationLogic(t *testing.T) {
 	tests := []struct {
-		name     string
-		query    services.BrewerySearchQuery
-		expected services.BrewerySearchQuery
+		name    string
+		query   services.BrewerySearchQuery
+		wantErr error
 	}{
 		{
 			name: "all valid fields",
 			query: services.BrewerySearchQuery{
 				Name:     "Stone",
 				Location: "California",
-				City:     "Escondido",
-				State:    "CA",
-				Country:  "USA",
-				Limit:    50,
-			},
-			expected: services.BrewerySearchQuery{
-				Name:     "Stone",
-				Location: "California",
-				City:     "Escondido",
-				State:    "CA",
-				Country:  "USA",
+				City:     []string{"Escondido"},
+				State:    []string{"CA"},
+				Country:  []string{"USA"},
 				Limit:    50,
 			},
 		},
 		{
-			name: "limit boundary correction",
-			query: services.BrewerySearchQuery{
-				Name:  "Test",
-				Limit: -10,
-			},
-			expected: services.BrewerySearchQuery{
-				Name:  "Test",
-				Limit: 20, // Should be corrected
-			},
+			name:    "negative limit",
+			query:   services.BrewerySearchQuery{Name: "Test", Limit: -10},
+			wantErr: services.ErrInvalidLimit,
 		},
 		{
-			name: "limit too high correction",
-			query: services.BrewerySearchQuery{
-				Name:  "Test",
-				Limit: 1000,
-			},
-			expected: services.BrewerySearchQuery{
-				Name:  "Test",
-				Limit: 20, // Should be corrected
-			},
+			name:    "limit too high",
+			query:   services.BrewerySearchQuery{Name: "Test", Limit: 1000},
+			wantErr: services.ErrInvalidLimit,
+		},
+		{
+			name:    "unknown sort",
+			query:   services.BrewerySearchQuery{Name: "Test", Sort: "abv"},
+			wantErr: services.ErrInvalidSearchQuery,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			// Simulate limit validation logic
-			if tt.query.Limit <= 0 || tt.query.Limit > 100 {
-				tt.query.Limit = 20
+			err := tt.query.Validate()
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+				return
 			}
-
-			assert.Equal(t, tt.expected.Name, tt.query.Name)
-			assert.Equal(t, tt.expected.Location, tt.query.Location)
-			assert.Equal(t, tt.expected.City, tt.query.City)
-			assert.Equal(t, tt.expected.State, tt.query.State)
-			assert.Equal(t, tt.expected.Country, tt.query.Country)
-			assert.Equal(t, tt.expected.Limit, tt.query.Limit)
+			assert.ErrorIs(t, err, tt.wantErr)
 		})
 	}
 }
@@ -2002,7 +2021,7 @@ func TestSearchBreweries_EmptyDatabase(t *testing.T) {
 		"id", "name", "brewery_type", "street", "city", "state", "postal_code", "country", "phone", "website_url",
 	})
 
-	expectedSQL := `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url\s+FROM breweries\s+WHERE 1=1 AND LOWER\(name\) LIKE LOWER\(\$1\)\s+ORDER BY name\s+LIMIT \$2`
+	expectedSQL := `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, website_url,.* AS email\s+FROM breweries\s+WHERE 1=1 AND deleted_at IS NULL AND LOWER\(name\) LIKE LOWER\(\$1\)\s+ORDER BY name\s+LIMIT \$2`
 
 	mock.ExpectQuery(expectedSQL).
 		WithArgs("%AnyName%", 20).
@@ -2018,3 +2037,661 @@ func TestSearchBreweries_EmptyDatabase(t *testing.T) {
 	}
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestSearchBreweriesPage(t *testing.T) {
+	breweryColumns := []string{
+		"id", "name", "brewery_type", "street", "city", "state", "postal_code", "country", "phone", "website_url",
+	}
+	addBrewery := func(rows *sqlmock.Rows, b *services.BrewerySearchResult) *sqlmock.Rows {
+		return rows.AddRow(
+			b.ID, b.Name, b.BreweryType, b.Street, b.City, b.State, b.PostalCode, b.Country, b.Phone, b.Website,
+		)
+	}
+	// The count query must repeat the search filters exactly, with no ORDER BY, LIMIT, or OFFSET.
+	countQuery := `^\s*SELECT COUNT\(\*\)\s+FROM breweries\s+WHERE 1=1 AND deleted_at IS NULL` +
+		` AND LOWER\(name\) LIKE LOWER\(\$1\) AND ` +
+		`\(LOWER\(city\) LIKE LOWER\(\$2\) OR LOWER\(state\) LIKE LOWER\(\$2\) OR LOWER\(country\) LIKE LOWER\(\$2\)\)$`
+	dataQuery := `SELECT id, name, .*\s+FROM breweries\s+WHERE 1=1 AND deleted_at IS NULL` +
+		` AND LOWER\(name\) LIKE LOWER\(\$1\) AND .* ` +
+		`ORDER BY name LIMIT \$3 OFFSET \$4$`
+
+	t.Run("Middle page reports total and more results", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		service := setupBreweryService(db)
+
+		mock.ExpectQuery(countQuery).
+			WithArgs("%Brewing%", "%United States%").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(5))
+		mock.ExpectQuery(dataQuery).
+			WithArgs("%Brewing%", "%United States%", 1, 2).
+			WillReturnRows(addBrewery(sqlmock.NewRows(breweryColumns), getMockBreweryData()[1]))
+
+		page, err := service.SearchBreweriesPage(
+			context.Background(),
+			services.BrewerySearchQuery{Name: "Brewing", Location: "United States", Limit: 1, Offset: 2},
+		)
+
+		require.NoError(t, err)
+		require.Len(t, page.Items, 1)
+		assert.Equal(t, "Stone Brewing", page.Items[0].Name)
+		assert.Equal(t, 5, page.TotalCount)
+		assert.True(t, page.HasMore)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Offset beyond the last row skips the data query", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		service := setupBreweryService(db)
+
+		mock.ExpectQuery(countQuery).
+			WithArgs("%Brewing%", "%United States%").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+
+		page, err := service.SearchBreweriesPage(
+			context.Background(),
+			services.BrewerySearchQuery{Name: "Brewing", Location: "United States", Offset: 40},
+		)
+
+		require.NoError(t, err)
+		assert.NotNil(t, page.Items)
+		assert.Empty(t, page.Items)
+		assert.Equal(t, 2, page.TotalCount)
+		assert.False(t, page.HasMore)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("First page omits OFFSET", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		service := setupBreweryService(db)
+
+		mock.ExpectQuery(`SELECT COUNT\(\*\)\s+FROM breweries\s+WHERE 1=1 AND deleted_at IS NULL` +
+			` AND LOWER\(city\) LIKE LOWER\(\$1\)$`).
+			WithArgs("%Escondido%").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		mock.ExpectQuery(`WHERE 1=1 AND deleted_at IS NULL AND LOWER\(city\) LIKE LOWER\(\$1\) ORDER BY name`+
+			` LIMIT \$2$`).
+			WithArgs("%Escondido%", 20).
+			WillReturnRows(addBrewery(sqlmock.NewRows(breweryColumns), getMockBreweryData()[1]))
+
+		page, err := service.SearchBreweriesPage(
+			context.Background(),
+			services.BrewerySearchQuery{City: []string{"Escondido"}, Offset: -5},
+		)
+
+		require.NoError(t, err)
+		assert.Len(t, page.Items, 1)
+		assert.Equal(t, 0, page.Offset)
+		assert.False(t, page.HasMore)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Count error is wrapped", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		service := setupBreweryService(db)
+		service.SetQueryRetries(1, 0)
+
+		mock.ExpectQuery(`SELECT COUNT`).WillReturnError(sql.ErrConnDone)
+
+		page, err := service.SearchBreweriesPage(context.Background(), services.BrewerySearchQuery{Name: "Stone"})
+
+		require.Error(t, err)
+		assert.Nil(t, page)
+		assert.Contains(t, err.Error(), "failed to count breweries")
+		assert.ErrorIs(t, err, sql.ErrConnDone)
+	})
+}
+
+func TestSearchBreweries_RelevanceSort(t *testing.T) {
+	t.Run("Ranks by name similarity when enabled", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		service := setupBreweryService(db)
+		service.EnableRelevanceSearch(true)
+
+		mock.ExpectQuery(`SELECT COUNT\(\*\)\s+FROM breweries\s+WHERE 1=1 AND deleted_at IS NULL` +
+			` AND LOWER\(name\) LIKE LOWER\(\$1\)$`).
+			WithArgs("%stone%").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+		expectedSQL := `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, ` +
+			`website_url,.* AS email, similarity\(name, \$2\) AS score\s+FROM breweries\s+` +
+			`WHERE 1=1 AND deleted_at IS NULL AND LOWER\(name\) LIKE LOWER\(\$1\) ORDER BY score DESC, name LIMIT \$3$`
+		mock.ExpectQuery(expectedSQL).
+			WithArgs("%stone%", "stone", 20).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "score"}).
+				AddRow(2, "Stone Brewing", 0.5).
+				AddRow(7, "Firestone Walker Brewing Company", 0.2).
+				AddRow(8, "Keystone Brewing", 0.18))
+
+		page, err := service.SearchBreweriesPage(
+			context.Background(),
+			services.BrewerySearchQuery{Name: "stone", Sort: services.SortByRelevance},
+		)
+
+		require.NoError(t, err)
+		assert.Equal(t, services.SortByRelevance, page.Sort)
+		require.Len(t, page.Items, 3)
+		assert.Equal(t, "Stone Brewing", page.Items[0].Name)
+		assert.InDelta(t, 0.5, page.Items[0].Score, 1e-9)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Falls back to name order when disabled", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		service := setupBreweryService(db)
+
+		expectedSQL := `SELECT id, name, brewery_type, street, city, state, postal_code, country, phone, ` +
+			`website_url,.* AS email\s+FROM breweries\s+WHERE 1=1 AND deleted_at IS NULL AND LOWER\(name\) LIKE LOWER\(\$1\)` +
+			` ORDER BY name LIMIT \$2$`
+		mock.ExpectQuery(expectedSQL).
+			WithArgs("%stone%", 20).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(7, "Firestone Walker Brewing Company"))
+
+		results, err := service.SearchBreweries(
+			context.Background(),
+			services.BrewerySearchQuery{Name: "stone", Sort: services.SortByRelevance},
+		)
+
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Zero(t, results[0].Score)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestSearchBreweries_Sort(t *testing.T) {
+	tests := []struct {
+		sort, order string
+		wantOrder   string
+	}{
+		{sort: "", order: "", wantOrder: `name`},
+		{sort: services.SortByName, order: services.OrderDesc, wantOrder: `name DESC NULLS LAST`},
+		{sort: services.SortByCity, order: "", wantOrder: `city, name`},
+		{sort: services.SortByCountry, order: "Desc", wantOrder: `country DESC NULLS LAST, name`},
+		{sort: services.SortByType, order: services.OrderAsc, wantOrder: `brewery_type, name`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.sort+" "+tt.order, func(t *testing.T) {
+			db, mock := setupMockDB(t)
+			defer db.Close()
+			service := setupBreweryService(db)
+
+			expectedQuery := `WHERE 1=1 AND deleted_at IS NULL AND LOWER\(country\) LIKE LOWER\(\$1\)` +
+				` ORDER BY ` + tt.wantOrder + ` LIMIT \$2$`
+			mock.ExpectQuery(expectedQuery).
+				WithArgs("%South Africa%", 20).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+
+			_, err := service.SearchBreweries(
+				context.Background(),
+				services.BrewerySearchQuery{Country: []string{"South Africa"}, Sort: tt.sort, Order: tt.order},
+			)
+
+			require.NoError(t, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Run("Rejects unknown sort keys and orders", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		service := setupBreweryService(db)
+
+		for _, query := range []services.BrewerySearchQuery{
+			{Country: []string{"South Africa"}, Sort: "name; DROP TABLE breweries"},
+			{Country: []string{"South Africa"}, Sort: services.SortByABV},
+			{Country: []string{"South Africa"}, Sort: services.SortByCity, Order: "up"},
+		} {
+			_, err := service.SearchBreweriesPage(context.Background(), query)
+			require.ErrorIs(t, err, services.ErrInvalidSearchQuery)
+		}
+		assert.NoError(t, mock.ExpectationsWereMet(), "invalid sorts should not reach the database")
+	})
+}
+
+func TestSearchBreweries_TypeFilter(t *testing.T) {
+	breweryColumns := []string{
+		"id", "name", "brewery_type", "street", "city", "state", "postal_code", "country", "phone", "website_url",
+	}
+
+	t.Run("Matches any of the normalized types", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		service := setupBreweryService(db)
+
+		expectedQuery := `WHERE 1=1 AND deleted_at IS NULL AND LOWER\(country\) LIKE LOWER\(\$1\)` +
+			` AND brewery_type IN \(\$2, \$3\) ` +
+			`ORDER BY name LIMIT \$4$`
+		mock.ExpectQuery(expectedQuery).
+			WithArgs("%United States%", "brewpub", "micro", 20).
+			WillReturnRows(sqlmock.NewRows(breweryColumns).
+				AddRow(1, "Stone Brewing", "micro", "", "Escondido", "California", "", "United States", "", ""))
+
+		results, err := service.SearchBreweries(context.Background(), services.BrewerySearchQuery{
+			Country: []string{"United States"},
+			Types:   []string{"Micro", " brewpub", "micro"},
+		})
+
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, "micro", results[0].BreweryType)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Counts with the type filter", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		service := setupBreweryService(db)
+
+		mock.ExpectQuery(`SELECT COUNT\(\*\)\s+FROM breweries\s+WHERE 1=1 AND deleted_at IS NULL` +
+			` AND brewery_type IN \(\$1\)$`).
+			WithArgs("nano").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+		page, err := service.SearchBreweriesPage(
+			context.Background(),
+			services.BrewerySearchQuery{Types: []string{"nano"}},
+		)
+
+		require.NoError(t, err)
+		assert.Zero(t, page.TotalCount)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Rejects unknown types before querying", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		service := setupBreweryService(db)
+
+		_, err := service.SearchBreweries(
+			context.Background(),
+			services.BrewerySearchQuery{Types: []string{"micro", "winery"}},
+		)
+
+		require.ErrorIs(t, err, services.ErrInvalidSearchQuery)
+		assert.Contains(t, err.Error(), `unknown brewery type "winery"`)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestSearchBreweries_MultiValueAndExclusions(t *testing.T) {
+	breweryColumns := []string{
+		"id", "name", "brewery_type", "street", "city", "state", "postal_code", "country", "phone", "website_url",
+	}
+
+	t.Run("ORs the values of a filter and ANDs the filters", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		service := setupBreweryService(db)
+
+		expectedQuery := `WHERE 1=1 AND deleted_at IS NULL` +
+			` AND \(LOWER\(state\) LIKE LOWER\(\$1\) OR LOWER\(state\) LIKE LOWER\(\$2\)\)` +
+			` AND LOWER\(country\) LIKE LOWER\(\$3\)` +
+			` AND \(brewery_type IS NULL OR brewery_type NOT IN \(\$4, \$5\)\) ` +
+			`ORDER BY name LIMIT \$6$`
+		mock.ExpectQuery(expectedQuery).
+			WithArgs("%Western Cape%", "%Gauteng%", "%South Africa%", "brewpub", "contract", 20).
+			WillReturnRows(sqlmock.NewRows(breweryColumns).
+				AddRow(1, "Devil's Peak", "micro", "", "Woodstock", "Western Cape", "", "South Africa", "", ""))
+
+		results, err := service.SearchBreweries(context.Background(), services.BrewerySearchQuery{
+			State:   []string{"Western Cape", " ", "Gauteng"},
+			Country: []string{"South Africa"},
+			Exclude: services.BreweryExclusions{Types: []string{"Contract", "brewpub"}},
+		})
+
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Numbers the arguments of every clause in order", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		service := setupBreweryService(db)
+
+		expectedQuery := `WHERE 1=1 AND deleted_at IS NULL AND LOWER\(name\) LIKE LOWER\(\$1\)` +
+			` AND \(LOWER\(city\) LIKE LOWER\(\$2\) OR LOWER\(city\) LIKE LOWER\(\$3\)\)` +
+			` AND brewery_type IN \(\$4, \$5\)` +
+			` AND \(city IS NULL OR NOT \(LOWER\(city\) LIKE LOWER\(\$6\)\)\)` +
+			` AND \(country IS NULL OR NOT \(LOWER\(country\) LIKE LOWER\(\$7\) OR ` +
+			`LOWER\(country\) LIKE LOWER\(\$8\)\)\) ` +
+			`ORDER BY name LIMIT \$9$`
+		mock.ExpectQuery(expectedQuery).
+			WithArgs("%brew%", "%cape town%", "%durban%", "micro", "nano", "%woodstock%", "%usa%", "%uk%", 20).
+			WillReturnRows(sqlmock.NewRows(breweryColumns))
+
+		results, err := service.SearchBreweries(context.Background(), services.BrewerySearchQuery{
+			Name:  "brew",
+			City:  []string{"cape town", "durban"},
+			Types: []string{"nano", "micro"},
+			Exclude: services.BreweryExclusions{
+				City:    []string{"woodstock"},
+				Country: []string{"usa", "uk"},
+			},
+		})
+
+		require.NoError(t, err)
+		assert.Empty(t, results)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Rejects unknown excluded types before querying", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		service := setupBreweryService(db)
+
+		_, err := service.SearchBreweries(context.Background(), services.BrewerySearchQuery{
+			Country: []string{"South Africa"},
+			Exclude: services.BreweryExclusions{Types: []string{"winery"}},
+		})
+
+		require.ErrorIs(t, err, services.ErrInvalidSearchQuery)
+		assert.Contains(t, err.Error(), `unknown brewery type "winery"`)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestGetBreweryByID(t *testing.T) {
+	columns := []string{
+		"id", "name", "brewery_type", "street", "city", "state", "postal_code", "country", "phone",
+		"website_url", "created_at", "updated_at", "beer_count",
+	}
+	expectedQuery := `SELECT br\.id, br\.name, .*\(SELECT COUNT\(\*\) FROM beers b WHERE b\.brewery_id = br\.id ` +
+		`AND b\.deleted_at IS NULL\) AS beer_count\s+FROM breweries br\s+WHERE br\.id = \$1 AND br\.deleted_at IS NULL$`
+
+	t.Run("Returns the brewery with its beer count", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		service := setupBreweryService(db)
+		created := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+		mock.ExpectQuery(expectedQuery).
+			WithArgs(2).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(
+				2, "Stone Brewing", "regional", "1999 Citracado Pkwy", "Escondido", "California", "92029",
+				"United States", "760-294-7866", "https://www.stonebrewing.com", created, created, 12,
+			))
+
+		brewery, err := service.GetBreweryByID(context.Background(), 2)
+
+		require.NoError(t, err)
+		assert.Equal(t, "Stone Brewing", brewery.Name)
+		assert.Equal(t, "regional", brewery.BreweryType)
+		assert.Equal(t, 12, brewery.BeerCount)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Missing ID returns ErrNotFound", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		service := setupBreweryService(db)
+
+		mock.ExpectQuery(expectedQuery).WithArgs(404).WillReturnError(sql.ErrNoRows)
+
+		brewery, err := service.GetBreweryByID(context.Background(), 404)
+
+		assert.Nil(t, brewery)
+		require.ErrorIs(t, err, services.ErrNotFound)
+		assert.EqualError(t, err, "brewery 404 not found")
+	})
+}
+
+func TestGetBreweryBeers(t *testing.T) {
+	columns := []string{"name", "id", "name", "style", "abv", "ibu", "total_count"}
+	expectedQuery := `SELECT br\.name, b\.id, b\.name, .* COUNT\(b\.id\) OVER \(\) AS total_count\s+` +
+		`FROM breweries br\s+LEFT JOIN beers b ON b\.brewery_id = br\.id AND b\.deleted_at IS NULL\s+` +
+		`WHERE br\.id = \$1 AND br\.deleted_at IS NULL\s+` +
+		`ORDER BY b\.name, b\.id\s+LIMIT \$2$`
+
+	t.Run("Lists beers sorted by name", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		service := setupBreweryService(db)
+
+		mock.ExpectQuery(expectedQuery).
+			WithArgs(7, 2).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow("Devil's Peak Brewing Company", 12, "First Light", "Golden Ale", 4.5, 18, 3).
+				AddRow("Devil's Peak Brewing Company", 11, "King's Blockhouse IPA", "American IPA", 6.0, 55, 3))
+
+		list, err := service.GetBreweryBeers(context.Background(), 7, 2)
+
+		require.NoError(t, err)
+		assert.Equal(t, "Devil's Peak Brewing Company", list.Brewery)
+		assert.Equal(t, 3, list.TotalCount)
+		require.Len(t, list.Beers, 2)
+		assert.Equal(t, &services.BreweryBeer{ID: 12, Name: "First Light", Style: "Golden Ale", ABV: 4.5, IBU: 18},
+			list.Beers[0])
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Brewery without beers and default limit", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		service := setupBreweryService(db)
+
+		mock.ExpectQuery(expectedQuery).
+			WithArgs(8, services.DefaultBreweryBeersLimit).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow("New Brewery", nil, nil, "", 0, 0, 0))
+
+		list, err := service.GetBreweryBeers(context.Background(), 8, 0)
+
+		require.NoError(t, err)
+		assert.Equal(t, "New Brewery", list.Brewery)
+		assert.Empty(t, list.Beers)
+		assert.NotNil(t, list.Beers, "beers should encode as an empty array")
+		assert.Zero(t, list.TotalCount)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Unknown brewery returns ErrNotFound", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		service := setupBreweryService(db)
+
+		mock.ExpectQuery(expectedQuery).WithArgs(99, services.DefaultBreweryBeersLimit).
+			WillReturnRows(sqlmock.NewRows(columns))
+
+		_, err := service.GetBreweryBeers(context.Background(), 99, 1000)
+
+		require.ErrorIs(t, err, services.ErrNotFound)
+		assert.EqualError(t, err, "brewery 99 not found")
+	})
+}
+
+func TestResolveBreweryID(t *testing.T) {
+	exactQuery := `SELECT id FROM breweries WHERE LOWER\(name\) = LOWER\(\$1\) AND deleted_at IS NULL ORDER BY id` +
+		` LIMIT 1`
+	partialQuery := `SELECT id FROM breweries WHERE LOWER\(name\) LIKE LOWER\(\$1\) AND deleted_at IS NULL` +
+		` ORDER BY id LIMIT 2`
+
+	tests := []struct {
+		name      string
+		setup     func(mock sqlmock.Sqlmock)
+		wantID    int
+		wantError error
+	}{
+		{
+			name: "Exact match",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(exactQuery).WithArgs("Stone").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+			},
+			wantID: 2,
+		},
+		{
+			name: "Single partial match",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(exactQuery).WithArgs("Stone").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				mock.ExpectQuery(partialQuery).WithArgs("%Stone%").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+			},
+			wantID: 2,
+		},
+		{
+			name: "Several partial matches",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(exactQuery).WithArgs("Stone").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				mock.ExpectQuery(partialQuery).WithArgs("%Stone%").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2).AddRow(5))
+			},
+			wantError: services.ErrAmbiguousBreweryName,
+		},
+		{
+			name: "No match",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(exactQuery).WithArgs("Stone").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				mock.ExpectQuery(partialQuery).WithArgs("%Stone%").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+			},
+			wantError: services.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := setupMockDB(t)
+			defer db.Close()
+			service := setupBreweryService(db)
+			tt.setup(mock)
+
+			id, err := service.ResolveBreweryID(context.Background(), "Stone")
+
+			if tt.wantError != nil {
+				require.ErrorIs(t, err, tt.wantError)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantID, id)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSearchBreweries_Distance(t *testing.T) {
+	lat, lng, radius := -33.9249, 18.4241, 25.0
+	columns := []string{
+		"id", "name", "brewery_type", "street", "city", "state", "postal_code", "country", "phone", "website_url",
+		"distance_km",
+	}
+	haversine := func(latArg, lngArg int) string {
+		return fmt.Sprintf(`\(6371 \* 2 \* ASIN\(SQRT\(POWER\(SIN\(RADIANS\(latitude - \$%[1]d\) / 2\), 2\) \+ `+
+			`COS\(RADIANS\(\$%[1]d\)\) \* COS\(RADIANS\(latitude\)\) \* `+
+			`POWER\(SIN\(RADIANS\(longitude - \$%[2]d\) / 2\), 2\)\)\)\)`, latArg, lngArg)
+	}
+
+	t.Run("Radius filters and orders by distance", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		service := setupBreweryService(db)
+
+		expectedQuery := `SELECT id, name, .*, ` + haversine(5, 6) + ` AS distance_km\s+FROM breweries\s+` +
+			`WHERE 1=1 AND deleted_at IS NULL AND brewery_type IN \(\$1\) AND latitude IS NOT NULL` +
+			` AND longitude IS NOT NULL AND ` +
+			haversine(2, 3) + ` <= \$4 ORDER BY distance_km, name LIMIT \$7$`
+		mock.ExpectQuery(expectedQuery).
+			WithArgs("micro", lat, lng, radius, lat, lng, 20).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(1, "Devil's Peak", "micro", "", "Woodstock", "", "", "South Africa", "", "", 2.1).
+				AddRow(2, "Jack Black's", "micro", "", "Diep River", "", "", "South Africa", "", "", 13.4))
+
+		results, err := service.SearchBreweries(context.Background(), services.BrewerySearchQuery{
+			Types:    []string{"micro"},
+			Lat:      &lat,
+			Lng:      &lng,
+			RadiusKm: &radius,
+		})
+
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		require.NotNil(t, results[0].DistanceKm)
+		assert.InDelta(t, 2.1, *results[0].DistanceKm, 0.001)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Coordinates alone sort nearest first", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		service := setupBreweryService(db)
+
+		mock.ExpectQuery(`SELECT COUNT\(\*\)\s+FROM breweries\s+` +
+			`WHERE 1=1 AND deleted_at IS NULL AND latitude IS NOT NULL AND longitude IS NOT NULL$`).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(26))
+		dataQuery := haversine(1, 2) + ` AS distance_km\s+FROM breweries\s+` +
+			`WHERE 1=1 AND deleted_at IS NULL AND latitude IS NOT NULL AND longitude IS NOT NULL` +
+			` ORDER BY distance_km, name LIMIT \$3$`
+		mock.ExpectQuery(dataQuery).
+			WithArgs(lat, lng, 5).
+			WillReturnRows(sqlmock.NewRows(columns))
+
+		page, err := service.SearchBreweriesPage(
+			context.Background(),
+			services.BrewerySearchQuery{Lat: &lat, Lng: &lng, Limit: 5, Sort: services.SortByRelevance},
+		)
+
+		require.NoError(t, err)
+		assert.Equal(t, services.SortByDistance, page.Sort)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	outOfRange := 91.0
+	invalid := []struct {
+		name    string
+		query   services.BrewerySearchQuery
+		message string
+	}{
+		{"Radius without coordinates", services.BrewerySearchQuery{RadiusKm: &radius}, "radius_km needs latitude"},
+		{"Latitude without longitude", services.BrewerySearchQuery{Lat: &lat}, "must be given together"},
+		{"Latitude out of range", services.BrewerySearchQuery{Lat: &outOfRange, Lng: &lng}, "latitude must be between"},
+		{
+			"Non-positive radius",
+			services.BrewerySearchQuery{Lat: &lat, Lng: &lng, RadiusKm: func() *float64 { v := 0.0; return &v }()},
+			"radius_km must be greater than zero",
+		},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := setupMockDB(t)
+			defer db.Close()
+			service := setupBreweryService(db)
+
+			_, err := service.SearchBreweriesPage(context.Background(), tt.query)
+
+			require.ErrorIs(t, err, services.ErrInvalidSearchQuery)
+			assert.Contains(t, err.Error(), tt.message)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSearchBreweriesIter(t *testing.T) {
+	columns := []string{
+		"id", "name", "brewery_type", "street", "city", "state", "postal_code", "country", "phone", "website_url",
+	}
+	db, mock := setupMockDB(t)
+	defer db.Close()
+	mock.ExpectQuery(`FROM breweries\s+WHERE 1=1 AND deleted_at IS NULL` +
+		` AND LOWER\(country\) LIKE LOWER\(\$1\)\s+ORDER BY name$`).
+		WithArgs("%South Africa%").
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(1, "Devil's Peak", "micro", "", "Cape Town", "", "", "South Africa", "", "").
+			AddRow(2, "Jack Black", "micro", "", "Cape Town", "", "", "South Africa", "", "").
+			AddRow(3, "Newlands", "large", "", "Cape Town", "", "", "South Africa", "", "")).
+		RowsWillBeClosed()
+
+	// A zero limit returns every match
+	breweries, err := setupBreweryService(db).SearchBreweriesIter(
+		context.Background(), services.BrewerySearchQuery{Country: []string{"South Africa"}},
+	)
+	require.NoError(t, err)
+	for brewery, err := range breweries {
+		require.NoError(t, err)
+		assert.Equal(t, "Devil's Peak", brewery.Name)
+		break
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
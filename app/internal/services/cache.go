@@ -0,0 +1,146 @@
+// Package services provides business logic and service layer functions for Brewsource MCP, including beer and brewery operations.
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultCacheTTL is how long search results stay cached unless changed with SetCacheTTL.
+	DefaultCacheTTL = 10 * time.Minute
+	// cacheTimeout bounds each Redis call so that an unreachable cache cannot stall a search.
+	cacheTimeout = 250 * time.Millisecond
+	// cacheScanCount is the number of keys requested per SCAN step during invalidation.
+	cacheScanCount = 100
+)
+
+// CacheStats counts search cache lookups since the service was created.
+type CacheStats struct {
+	Hits   uint64 `json:"hits"`
+	Misses uint64 `json:"misses"`
+	Errors uint64 `json:"errors"` // Redis failures; each also counts as a miss or a skipped write
+}
+
+// searchCache is a read-through Redis cache for search results. A nil cache, or one without a
+// client, caches nothing, so services work unchanged when Redis is not configured.
+type searchCache struct {
+	client *redis.Client
+	prefix string
+	ttl    atomic.Int64 // time.Duration
+
+	hits   atomic.Uint64
+	misses atomic.Uint64
+	errors atomic.Uint64
+}
+
+func newSearchCache(client *redis.Client, prefix string) *searchCache {
+	c := &searchCache{client: client, prefix: prefix}
+	c.ttl.Store(int64(DefaultCacheTTL))
+	return c
+}
+
+func (c *searchCache) enabled() bool {
+	return c != nil && c.client != nil
+}
+
+func (c *searchCache) setTTL(ttl time.Duration) {
+	if c != nil && ttl > 0 {
+		c.ttl.Store(int64(ttl))
+	}
+}
+
+// key derives the cache key for a query from a hash of its JSON encoding. Callers normalize the
+// query first so that equivalent searches share a key.
+func (c *searchCache) key(kind string, query interface{}) string {
+	encoded, err := json.Marshal(query)
+	if err != nil {
+		// Query structs are plain strings and ints; fall back to the Go syntax just in case.
+		encoded = []byte(fmt.Sprintf("%#v", query))
+	}
+	sum := sha256.Sum256(encoded)
+	return c.prefix + kind + ":" + hex.EncodeToString(sum[:])
+}
+
+// get loads a cached value into dest and reports whether it was found.
+func (c *searchCache) get(ctx context.Context, key string, dest interface{}) bool {
+	if !c.enabled() {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(ctx, cacheTimeout)
+	defer cancel()
+
+	data, err := c.client.Get(ctx, key).Bytes()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			c.errors.Add(1)
+			logrus.Debugf("Search cache read failed for %s: %v", key, err)
+		}
+		c.misses.Add(1)
+		return false
+	}
+	if err = json.Unmarshal(data, dest); err != nil {
+		c.errors.Add(1)
+		c.misses.Add(1)
+		return false
+	}
+	c.hits.Add(1)
+	return true
+}
+
+// set stores value under key. Failures are logged and otherwise ignored.
+func (c *searchCache) set(ctx context.Context, key string, value interface{}) {
+	if !c.enabled() {
+		return
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, cacheTimeout)
+	defer cancel()
+
+	if err = c.client.Set(ctx, key, data, time.Duration(c.ttl.Load())).Err(); err != nil {
+		c.errors.Add(1)
+		logrus.Debugf("Search cache write failed for %s: %v", key, err)
+	}
+}
+
+// invalidate deletes every key under the cache's prefix.
+func (c *searchCache) invalidate(ctx context.Context) error {
+	if !c.enabled() {
+		return nil
+	}
+	var cursor uint64
+	for {
+		keys, next, err := c.client.Scan(ctx, cursor, c.prefix+"*", cacheScanCount).Result()
+		if err != nil {
+			return fmt.Errorf("failed to scan search cache: %w", err)
+		}
+		if len(keys) > 0 {
+			if err = c.client.Del(ctx, keys...).Err(); err != nil {
+				return fmt.Errorf("failed to clear search cache: %w", err)
+			}
+		}
+		if next == 0 {
+			return nil
+		}
+		cursor = next
+	}
+}
+
+func (c *searchCache) stats() CacheStats {
+	if c == nil {
+		return CacheStats{}
+	}
+	return CacheStats{Hits: c.hits.Load(), Misses: c.misses.Load(), Errors: c.errors.Load()}
+}
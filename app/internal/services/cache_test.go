@@ -0,0 +1,170 @@
+// Package services_test contains tests for the business logic and service layer functions in Brewsource MCP.
+package services_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/services"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const breweryNameQuery = `SELECT id, name, .* FROM breweries\s+` +
+	`WHERE 1=1 AND deleted_at IS NULL AND LOWER\(name\) LIKE LOWER\(\$1\) ORDER BY name`
+
+func setupCachedServices(
+	t *testing.T,
+) (*miniredis.Miniredis, sqlmock.Sqlmock, *services.BeerService, *services.BreweryService) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	redisClient := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = redisClient.Close() })
+
+	db, mock := setupMockDB(t)
+	t.Cleanup(func() { _ = db.Close() })
+	return mr, mock, services.NewBeerService(db, redisClient), services.NewBreweryService(db, redisClient)
+}
+
+func expectBreweryRows(mock sqlmock.Sqlmock, name string) {
+	mock.ExpectQuery(breweryNameQuery).
+		WithArgs("%"+name+"%", 20).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "city"}).AddRow(2, "Stone Brewing", "Escondido"))
+}
+
+func TestSearchCache_MissThenHit(t *testing.T) {
+	_, mock, _, breweryService := setupCachedServices(t)
+	ctx := context.Background()
+	expectBreweryRows(mock, "Stone")
+
+	first, err := breweryService.SearchBreweries(ctx, services.BrewerySearchQuery{Name: "Stone"})
+	require.NoError(t, err)
+	// Differs only in case and the explicit default limit, so it must share the cache entry.
+	second, err := breweryService.SearchBreweries(ctx, services.BrewerySearchQuery{Name: "STONE", Limit: 20})
+	require.NoError(t, err)
+
+	assert.Equal(t, first, second)
+	assert.Equal(t, services.CacheStats{Hits: 1, Misses: 1}, breweryService.CacheStats())
+	assert.NoError(t, mock.ExpectationsWereMet(), "the second search should not reach the database")
+}
+
+func TestSearchCache_PagesAreCachedSeparately(t *testing.T) {
+	_, mock, beerService, _ := setupCachedServices(t)
+	ctx := context.Background()
+
+	mock.ExpectQuery(`SELECT b\.id, .* ORDER BY b\.name, b\.id\s+LIMIT \$2$`).
+		WithArgs("%IPA%", 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "style", "brewery", "city", "country", "abv", "ibu"}).
+			AddRow(getMockBeerRows()[0]...))
+	mock.ExpectQuery(`SELECT COUNT\(\*\)`).
+		WithArgs("%IPA%").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(`SELECT b\.id, .* ORDER BY b\.name, b\.id\s+LIMIT \$2$`).
+		WithArgs("%IPA%", 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "style", "brewery", "city", "country", "abv", "ibu"}).
+			AddRow(getMockBeerRows()[0]...))
+
+	query := services.BeerSearchQuery{Style: "IPA", Limit: 10}
+	for range 2 {
+		results, err := beerService.SearchBeers(ctx, query)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+
+		page, err := beerService.SearchBeersPage(ctx, query)
+		require.NoError(t, err)
+		assert.Equal(t, 1, page.TotalCount)
+		assert.Equal(t, "King's Blockhouse IPA", page.Items[0].Name)
+	}
+
+	assert.Equal(t, services.CacheStats{Hits: 2, Misses: 2}, beerService.CacheStats())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSearchCache_TTLExpiry(t *testing.T) {
+	mr, mock, _, breweryService := setupCachedServices(t)
+	ctx := context.Background()
+	breweryService.SetCacheTTL(time.Minute)
+	expectBreweryRows(mock, "Stone")
+	expectBreweryRows(mock, "Stone")
+
+	_, err := breweryService.SearchBreweries(ctx, services.BrewerySearchQuery{Name: "Stone"})
+	require.NoError(t, err)
+	keys := mr.Keys()
+	require.Len(t, keys, 1)
+	assert.True(t, strings.HasPrefix(keys[0], "brewsource:search:breweries:"))
+	assert.Equal(t, time.Minute, mr.TTL(keys[0]))
+
+	mr.FastForward(2 * time.Minute)
+	_, err = breweryService.SearchBreweries(ctx, services.BrewerySearchQuery{Name: "Stone"})
+	require.NoError(t, err)
+
+	assert.Equal(t, services.CacheStats{Misses: 2}, breweryService.CacheStats())
+	assert.NoError(t, mock.ExpectationsWereMet(), "an expired entry should be reloaded from the database")
+}
+
+func TestSearchCache_RedisDownFallsBackToDatabase(t *testing.T) {
+	mr, mock, _, breweryService := setupCachedServices(t)
+	ctx := context.Background()
+	mr.Close()
+	expectBreweryRows(mock, "Stone")
+	expectBreweryRows(mock, "Stone")
+
+	for range 2 {
+		results, err := breweryService.SearchBreweries(ctx, services.BrewerySearchQuery{Name: "Stone"})
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, "Stone Brewing", results[0].Name)
+	}
+
+	stats := breweryService.CacheStats()
+	assert.Zero(t, stats.Hits)
+	assert.Equal(t, uint64(2), stats.Misses)
+	assert.Equal(t, uint64(4), stats.Errors, "each search should fail one read and one write")
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	invalidateCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	assert.Error(t, breweryService.InvalidateBreweryCache(invalidateCtx))
+}
+
+func TestSearchCache_Invalidate(t *testing.T) {
+	mr, mock, beerService, breweryService := setupCachedServices(t)
+	ctx := context.Background()
+	require.NoError(t, mr.Set("brewsource:search:beers:list:cached", "[]"))
+	expectBreweryRows(mock, "Stone")
+	expectBreweryRows(mock, "Stone")
+
+	_, err := breweryService.SearchBreweries(ctx, services.BrewerySearchQuery{Name: "Stone"})
+	require.NoError(t, err)
+	require.NoError(t, breweryService.InvalidateBreweryCache(ctx))
+	assert.Equal(t, []string{"brewsource:search:beers:list:cached"}, mr.Keys(), "beer entries should be kept")
+
+	_, err = breweryService.SearchBreweries(ctx, services.BrewerySearchQuery{Name: "Stone"})
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet(), "an invalidated entry should be reloaded from the database")
+
+	require.NoError(t, beerService.InvalidateBeerCache(ctx))
+	assert.False(t, mr.Exists("brewsource:search:beers:list:cached"))
+}
+
+func TestSearchCache_DisabledWithoutRedis(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+	breweryService := services.NewBreweryService(db, nil)
+	expectBreweryRows(mock, "Stone")
+	expectBreweryRows(mock, "Stone")
+
+	for range 2 {
+		_, err := breweryService.SearchBreweries(context.Background(), services.BrewerySearchQuery{Name: "Stone"})
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, services.CacheStats{}, breweryService.CacheStats())
+	assert.NoError(t, breweryService.InvalidateBreweryCache(context.Background()))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,95 @@
+// Package services provides business logic and service layer functions for Brewsource MCP, including beer and brewery operations.
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// RegionCount is the number of breweries in one state or province.
+type RegionCount struct {
+	Region    string `json:"region"`
+	Breweries int    `json:"breweries"`
+}
+
+// CountryRegions is the number of breweries in each state or province of one country.
+type CountryRegions struct {
+	Country   string         `json:"country"`
+	Breweries int            `json:"breweries"`
+	Regions   []*RegionCount `json:"regions"`
+	// Unspecified is the number of the country's breweries that record no state or province.
+	Unspecified int `json:"unspecified"`
+}
+
+// ListCountries returns the countries breweries are in, with the number of breweries in each, in
+// alphabetical order. Countries differing only in case or surrounding whitespace are counted as
+// one, and breweries recording no country and soft-deleted breweries are left out.
+func (s *BreweryService) ListCountries(ctx context.Context) (_ []*CountryCount, err error) {
+	const query = `
+		SELECT MIN(TRIM(country)) AS country, COUNT(*) AS breweries
+		FROM breweries
+		WHERE TRIM(COALESCE(country, '')) <> '' AND deleted_at IS NULL
+		GROUP BY LOWER(TRIM(country))
+		ORDER BY LOWER(TRIM(country))`
+
+	ctx, finish := s.queries.begin(ctx, "list_countries", query, nil)
+	defer finish(&err)
+
+	var counts []*CountryCount
+	err = s.queries.retry(ctx, "list_countries", func() error {
+		counts = []*CountryCount{}
+		return s.db.SelectContext(ctx, &counts, query)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list countries: %w", err)
+	}
+	return counts, nil
+}
+
+// ListRegions returns the states and provinces of the breweries in country, matched ignoring case
+// and surrounding whitespace, with the number of breweries in each, in alphabetical order. Regions
+// differing only in case are counted as one. It returns a *NotFoundError, matching ErrNotFound,
+// when no brewery is in the country; soft-deleted breweries are left out.
+func (s *BreweryService) ListRegions(ctx context.Context, country string) (_ *CountryRegions, err error) {
+	const query = `
+		SELECT MIN(TRIM(country)) AS country, MIN(TRIM(COALESCE(state, ''))) AS region, COUNT(*) AS breweries
+		FROM breweries
+		WHERE LOWER(TRIM(country)) = $1 AND deleted_at IS NULL
+		GROUP BY LOWER(TRIM(COALESCE(state, '')))
+		ORDER BY LOWER(TRIM(COALESCE(state, '')))`
+
+	country = strings.TrimSpace(country)
+	if country == "" {
+		return nil, &NotFoundError{Kind: "country", Name: country}
+	}
+
+	ctx, finish := s.queries.begin(ctx, "list_regions", query, country)
+	defer finish(&err)
+
+	var rows []struct {
+		Country string `db:"country"`
+		RegionCount
+	}
+	err = s.queries.retry(ctx, "list_regions", func() error {
+		rows = rows[:0]
+		return s.db.SelectContext(ctx, &rows, query, strings.ToLower(country))
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list regions of %q: %w", country, err)
+	}
+	if len(rows) == 0 {
+		return nil, &NotFoundError{Kind: "country", Name: country}
+	}
+
+	regions := &CountryRegions{Country: rows[0].Country, Regions: []*RegionCount{}}
+	for _, row := range rows {
+		regions.Breweries += row.Breweries
+		if row.Region == "" {
+			regions.Unspecified = row.Breweries
+			continue
+		}
+		regions.Regions = append(regions.Regions, &RegionCount{Region: row.Region, Breweries: row.Breweries})
+	}
+	return regions, nil
+}
@@ -0,0 +1,100 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/services"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListCountries(t *testing.T) {
+	t.Run("Counts breweries per country", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		mock.ExpectQuery(`SELECT MIN\(TRIM\(country\)\) AS country, COUNT\(\*\) AS breweries\s+FROM breweries\s+` +
+			`WHERE TRIM\(COALESCE\(country, ''\)\) <> '' AND deleted_at IS NULL\s+` +
+			`GROUP BY LOWER\(TRIM\(country\)\)\s+ORDER BY LOWER\(TRIM\(country\)\)$`).
+			WillReturnRows(sqlmock.NewRows([]string{"country", "breweries"}).
+				AddRow("Namibia", 2).
+				AddRow("South Africa", 20))
+
+		countries, err := setupBreweryService(db).ListCountries(context.Background())
+
+		require.NoError(t, err)
+		assert.Equal(t, []*services.CountryCount{
+			{Country: "Namibia", Breweries: 2},
+			{Country: "South Africa", Breweries: 20},
+		}, countries)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Query errors are returned", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		mock.ExpectQuery(`SELECT MIN\(TRIM\(country\)\)`).WillReturnError(errors.New("connection refused"))
+
+		_, err := setupBreweryService(db).ListCountries(context.Background())
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to list countries: connection refused")
+	})
+}
+
+func TestListRegions(t *testing.T) {
+	regionsQuery := `SELECT MIN\(TRIM\(country\)\) AS country, MIN\(TRIM\(COALESCE\(state, ''\)\)\) AS region, ` +
+		`COUNT\(\*\) AS breweries\s+FROM breweries\s+WHERE LOWER\(TRIM\(country\)\) = \$1 AND deleted_at IS NULL\s+` +
+		`GROUP BY LOWER\(TRIM\(COALESCE\(state, ''\)\)\)\s+ORDER BY LOWER\(TRIM\(COALESCE\(state, ''\)\)\)$`
+
+	t.Run("Counts breweries per region, matching the country ignoring case", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		mock.ExpectQuery(regionsQuery).
+			WithArgs("south africa").
+			WillReturnRows(sqlmock.NewRows([]string{"country", "region", "breweries"}).
+				AddRow("South Africa", "", 2).
+				AddRow("South Africa", "Gauteng", 5).
+				AddRow("South Africa", "Western Cape", 15))
+
+		regions, err := setupBreweryService(db).ListRegions(context.Background(), " South AFRICA ")
+
+		require.NoError(t, err)
+		assert.Equal(t, &services.CountryRegions{
+			Country:   "South Africa",
+			Breweries: 22,
+			Regions: []*services.RegionCount{
+				{Region: "Gauteng", Breweries: 5},
+				{Region: "Western Cape", Breweries: 15},
+			},
+			Unspecified: 2,
+		}, regions)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("A country without breweries is not found", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		mock.ExpectQuery(regionsQuery).
+			WithArgs("atlantis").
+			WillReturnRows(sqlmock.NewRows([]string{"country", "region", "breweries"}))
+
+		_, err := setupBreweryService(db).ListRegions(context.Background(), "Atlantis")
+
+		require.ErrorIs(t, err, services.ErrNotFound)
+		assert.EqualError(t, err, `country "Atlantis" not found`)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("A blank country is not found without a query", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		_, err := setupBreweryService(db).ListRegions(context.Background(), "  ")
+
+		require.ErrorIs(t, err, services.ErrNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
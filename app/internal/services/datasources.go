@@ -0,0 +1,94 @@
+// Package services provides business logic and service layer functions for Brewsource MCP, including beer and brewery operations.
+package services
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Sources recorded in the data_sources table, besides OpenBreweryDBSource.
+const (
+	// SeedSource is the bundled seed data or a seed file.
+	SeedSource = "seed"
+	// ImportSource is a brewery or beer file import.
+	ImportSource = "import"
+	// BJCPSource is the BJCP style guidelines file.
+	BJCPSource = "bjcp"
+)
+
+// catalogSources are the sources writing breweries and beers, as opposed to the style guide.
+var catalogSources = []string{SeedSource, ImportSource, OpenBreweryDBSource}
+
+// DataSource is the data_sources row of one source, recording its last update. Records counts the
+// rows that update supplied: the breweries and beers of a seed or import, the breweries a sync
+// fetched, or the styles of the BJCP file. Version identifies what was loaded: the seed or import
+// files, the Open Brewery DB endpoint, or the BJCP guideline version.
+type DataSource struct {
+	Source    string    `json:"source"     db:"source"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+	Records   int       `json:"records"    db:"records"`
+	Version   string    `json:"version"    db:"version"`
+}
+
+// RecordDataSource upserts the data_sources row of source.Source. Callers record an update once it
+// has written, in the same transaction where there is one.
+func RecordDataSource(ctx context.Context, db sqlx.ExecerContext, source DataSource) error {
+	_, err := db.ExecContext(ctx, `
+		INSERT INTO data_sources (source, updated_at, records, version)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (source) DO UPDATE SET
+			updated_at = EXCLUDED.updated_at,
+			records = EXCLUDED.records,
+			version = EXCLUDED.version`,
+		source.Source, source.UpdatedAt.UTC(), source.Records, source.Version,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record data source %s: %w", source.Source, err)
+	}
+	return nil
+}
+
+// ListDataSources returns the data_sources rows, most recently updated first. Results are cached in
+// Redis for StatsCacheTTL when caching is enabled, as the tools cite them on every call.
+func (s *BreweryService) ListDataSources(ctx context.Context) (_ []*DataSource, err error) {
+	const query = `
+		SELECT source, updated_at, records, version
+		FROM data_sources
+		ORDER BY updated_at DESC, source`
+
+	key := s.statsCache.key("data_sources", nil)
+	var cached []*DataSource
+	if s.statsCache.get(ctx, key, &cached) {
+		return cached, nil
+	}
+
+	ctx, finish := s.queries.begin(ctx, "list_data_sources", query, nil)
+	defer finish(&err)
+
+	var sources []*DataSource
+	err = s.queries.retry(ctx, "list_data_sources", func() error {
+		sources = []*DataSource{}
+		return s.db.SelectContext(ctx, &sources, query)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list data sources: %w", err)
+	}
+	s.statsCache.set(ctx, key, sources)
+	return sources, nil
+}
+
+// CatalogUpdatedAt returns when the newest of sources that write breweries and beers last updated
+// the catalog, or false when none is recorded.
+func CatalogUpdatedAt(sources []*DataSource) (time.Time, bool) {
+	var updated time.Time
+	for _, source := range sources {
+		if slices.Contains(catalogSources, source.Source) && source.UpdatedAt.After(updated) {
+			updated = source.UpdatedAt
+		}
+	}
+	return updated, !updated.IsZero()
+}
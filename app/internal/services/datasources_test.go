@@ -0,0 +1,81 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/services"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordDataSource(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+	updated := time.Date(2024, 11, 2, 10, 0, 0, 0, time.FixedZone("SAST", 2*60*60))
+	query := `INSERT INTO data_sources \(source, updated_at, records, version\)\s+VALUES \(\$1, \$2, \$3, \$4\)\s+` +
+		`ON CONFLICT \(source\) DO UPDATE SET`
+	mock.ExpectExec(query).
+		WithArgs("import", updated.UTC(), 12, "breweries.csv").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := services.RecordDataSource(context.Background(), db, services.DataSource{
+		Source: services.ImportSource, UpdatedAt: updated, Records: 12, Version: "breweries.csv",
+	})
+
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListDataSources(t *testing.T) {
+	t.Run("Lists the sources, most recently updated first", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		synced := time.Date(2024, 11, 2, 8, 30, 0, 0, time.UTC)
+		seeded := time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
+		mock.ExpectQuery(`SELECT source, updated_at, records, version\s+FROM data_sources\s+` +
+			`ORDER BY updated_at DESC, source$`).
+			WillReturnRows(sqlmock.NewRows([]string{"source", "updated_at", "records", "version"}).
+				AddRow("openbrewerydb", synced, 40, "https://api.openbrewerydb.org/v1").
+				AddRow("seed", seeded, 92, "bundled"))
+
+		sources, err := setupBreweryService(db).ListDataSources(context.Background())
+
+		require.NoError(t, err)
+		assert.Equal(t, []*services.DataSource{
+			{Source: "openbrewerydb", UpdatedAt: synced, Records: 40, Version: "https://api.openbrewerydb.org/v1"},
+			{Source: "seed", UpdatedAt: seeded, Records: 92, Version: "bundled"},
+		}, sources)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Query errors are returned", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		mock.ExpectQuery(`FROM data_sources`).WillReturnError(errors.New("connection refused"))
+
+		_, err := setupBreweryService(db).ListDataSources(context.Background())
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to list data sources: connection refused")
+	})
+}
+
+func TestCatalogUpdatedAt(t *testing.T) {
+	imported := time.Date(2024, 11, 2, 0, 0, 0, 0, time.UTC)
+	sources := []*services.DataSource{
+		{Source: services.BJCPSource, UpdatedAt: time.Date(2025, 7, 26, 0, 0, 0, 0, time.UTC)},
+		{Source: services.ImportSource, UpdatedAt: imported},
+		{Source: services.SeedSource, UpdatedAt: time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	updated, ok := services.CatalogUpdatedAt(sources)
+	assert.True(t, ok)
+	assert.Equal(t, imported, updated, "the style guide does not date the catalog")
+
+	_, ok = services.CatalogUpdatedAt(sources[:1])
+	assert.False(t, ok)
+}
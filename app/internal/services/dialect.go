@@ -0,0 +1,76 @@
+// Package services provides business logic and service layer functions for Brewsource MCP, including beer and brewery operations.
+package services
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// sqliteDriver is the driver name of SQLite, which the services support alongside PostgreSQL.
+const sqliteDriver = "sqlite3"
+
+// sqlDB is the database of a service. The queries are written for PostgreSQL; on SQLite each is
+// rewritten by rebind before it runs.
+type sqlDB struct {
+	*sqlx.DB
+	sqlite bool
+}
+
+// newSQLDB wraps db, which may be nil for services that are never queried.
+func newSQLDB(db *sqlx.DB) *sqlDB {
+	return &sqlDB{DB: db, sqlite: isSQLite(db)}
+}
+
+// isSQLite reports whether db uses the SQLite driver.
+func isSQLite(db *sqlx.DB) bool {
+	return db != nil && db.DriverName() == sqliteDriver
+}
+
+var (
+	ilikeOperator   = regexp.MustCompile(`\bILIKE\b`)
+	numberedBindvar = regexp.MustCompile(`\$(\d+)`)
+)
+
+// rebind rewrites a PostgreSQL query for SQLite: ILIKE becomes LIKE, which SQLite already matches
+// without regard to case for ASCII letters, and each $N bindvar becomes SQLite's ?N, which binds
+// the same argument. sqlx.Rebind only converts from ? bindvars, so it cannot renumber these.
+func rebind(sqlite bool, query string) string {
+	if !sqlite {
+		return query
+	}
+	query = ilikeOperator.ReplaceAllString(query, "LIKE")
+	return numberedBindvar.ReplaceAllString(query, "?$1")
+}
+
+// SelectContext runs query, rewritten for the database, into dest.
+func (db *sqlDB) SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return db.DB.SelectContext(ctx, dest, rebind(db.sqlite, query), args...)
+}
+
+// GetContext runs query, rewritten for the database, into dest.
+func (db *sqlDB) GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return db.DB.GetContext(ctx, dest, rebind(db.sqlite, query), args...)
+}
+
+// ExecContext runs query, rewritten for the database.
+func (db *sqlDB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return db.DB.ExecContext(ctx, rebind(db.sqlite, query), args...)
+}
+
+// QueryContext runs query, rewritten for the database.
+func (db *sqlDB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return db.DB.QueryContext(ctx, rebind(db.sqlite, query), args...)
+}
+
+// QueryxContext runs query, rewritten for the database.
+func (db *sqlDB) QueryxContext(ctx context.Context, query string, args ...interface{}) (*sqlx.Rows, error) {
+	return db.DB.QueryxContext(ctx, rebind(db.sqlite, query), args...)
+}
+
+// QueryRowxContext runs query, rewritten for the database.
+func (db *sqlDB) QueryRowxContext(ctx context.Context, query string, args ...interface{}) *sqlx.Row {
+	return db.DB.QueryRowxContext(ctx, rebind(db.sqlite, query), args...)
+}
@@ -0,0 +1,44 @@
+// Package services provides business logic and service layer functions for Brewsource MCP, including beer and brewery operations.
+package services
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNotFound matches, with errors.Is, every NotFoundError.
+var ErrNotFound = errors.New("not found")
+
+// ErrInvalidRecord is wrapped by errors for a brewery or beer that fails validation before it is
+// written.
+var ErrInvalidRecord = errors.New("invalid record")
+
+// ErrDuplicate is wrapped by errors for a write that would duplicate an existing brewery or beer.
+var ErrDuplicate = errors.New("duplicate record")
+
+// ErrQueryTimeout is wrapped by errors for a database query that ran longer than the service's
+// query timeout.
+var ErrQueryTimeout = errors.New("query timed out")
+
+// ErrSyncInProgress is returned when a brewery sync is started while another is running.
+var ErrSyncInProgress = errors.New("a brewery sync is already running")
+
+// NotFoundError reports a lookup by ID or name that matched no row.
+type NotFoundError struct {
+	Kind string // "beer", "brewery", or "country"
+	ID   int    // zero for lookups by name
+	Name string
+}
+
+// Error returns the kind and the ID or name that was not found.
+func (e *NotFoundError) Error() string {
+	if e.ID == 0 {
+		return fmt.Sprintf("%s %q not found", e.Kind, e.Name)
+	}
+	return fmt.Sprintf("%s %d not found", e.Kind, e.ID)
+}
+
+// Is reports whether target is ErrNotFound.
+func (e *NotFoundError) Is(target error) bool {
+	return target == ErrNotFound
+}
@@ -0,0 +1,218 @@
+// Package services provides business logic and service layer functions for Brewsource MCP, including beer and brewery operations.
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Where exported search results are kept until they expire, see ExportStore.
+const (
+	// ExportStoreFile keeps exports as files in a directory.
+	ExportStoreFile = "file"
+	// ExportStoreRedis keeps exports in Redis, expiring with their keys, so that every replica can
+	// serve them.
+	ExportStoreRedis = "redis"
+)
+
+// ExportStores are the accepted export stores.
+var ExportStores = []string{ExportStoreFile, ExportStoreRedis}
+
+const (
+	// DefaultExportTTL is how long an export can be downloaded unless changed with EXPORT_TTL.
+	DefaultExportTTL = time.Hour
+	// DefaultExportMaxBytes caps the size of an export unless changed with EXPORT_MAX_BYTES.
+	DefaultExportMaxBytes = 10 << 20
+	// DefaultExportMaxRows caps the rows of an export unless changed with EXPORT_MAX_ROWS.
+	DefaultExportMaxRows = 10000
+
+	// exportKeyPrefix prefixes the Redis keys of exports.
+	exportKeyPrefix = "brewsource:export:"
+	// exportMetaSuffix names the file holding an export's metadata, beside the file of its data.
+	exportMetaSuffix = ".meta.json"
+)
+
+// ErrExportNotFound is returned for an export that does not exist or has expired.
+var ErrExportNotFound = errors.New("export not found")
+
+// Export is a file of exported search results, downloadable until ExpiresAt.
+type Export struct {
+	Filename    string    `json:"filename"`
+	ContentType string    `json:"content_type"`
+	Rows        int       `json:"rows"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	Data        []byte    `json:"data,omitempty"`
+}
+
+// ExportStore keeps exports under random tokens until they expire.
+type ExportStore interface {
+	SaveExport(ctx context.Context, token string, export *Export) error
+	// LoadExport returns the export saved under token, or ErrExportNotFound once it has expired.
+	LoadExport(ctx context.Context, token string) (*Export, error)
+}
+
+// NewExportToken returns a random token of 32 hex characters, naming an export in its URL.
+func NewExportToken() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// ValidExportToken reports whether token has the form of NewExportToken's, so that it is safe to
+// use in a file name or key.
+func ValidExportToken(token string) bool {
+	if len(token) != 32 {
+		return false
+	}
+	_, err := hex.DecodeString(token)
+	return err == nil && strings.ToLower(token) == token
+}
+
+// fileExportStore keeps each export as a data file and a metadata file in a directory.
+type fileExportStore struct {
+	dir string
+}
+
+// NewFileExportStore returns an ExportStore keeping exports in dir, created when missing.
+func NewFileExportStore(dir string) (ExportStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create export directory: %w", err)
+	}
+	return &fileExportStore{dir: dir}, nil
+}
+
+// SaveExport writes the export's files, removing those of expired exports first so that the
+// directory does not grow without bound.
+func (s *fileExportStore) SaveExport(_ context.Context, token string, export *Export) error {
+	if !ValidExportToken(token) {
+		return fmt.Errorf("invalid export token %q", token)
+	}
+	s.prune(time.Now())
+	meta := *export
+	meta.Data = nil
+	encoded, err := json.Marshal(meta)
+	if err != nil {
+		return fmt.Errorf("failed to encode export metadata: %w", err)
+	}
+	path := filepath.Join(s.dir, token)
+	if err = os.WriteFile(path, export.Data, 0o600); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+	// Written last, as an export without its metadata cannot be loaded
+	if err = os.WriteFile(path+exportMetaSuffix, encoded, 0o600); err != nil {
+		_ = os.Remove(path)
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+	return nil
+}
+
+// LoadExport implements ExportStore, removing the files of an expired export.
+func (s *fileExportStore) LoadExport(_ context.Context, token string) (*Export, error) {
+	if !ValidExportToken(token) {
+		return nil, ErrExportNotFound
+	}
+	path := filepath.Join(s.dir, token)
+	export, err := readExportMeta(path + exportMetaSuffix)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrExportNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !time.Now().Before(export.ExpiresAt) {
+		removeExport(path)
+		return nil, ErrExportNotFound
+	}
+	if export.Data, err = os.ReadFile(path); err != nil { // #nosec G304 - the token is validated
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrExportNotFound
+		}
+		return nil, fmt.Errorf("failed to read export: %w", err)
+	}
+	return export, nil
+}
+
+// prune removes the files of the exports expired at now.
+func (s *fileExportStore) prune(now time.Time) {
+	metas, _ := filepath.Glob(filepath.Join(s.dir, "*"+exportMetaSuffix))
+	for _, metaPath := range metas {
+		if export, err := readExportMeta(metaPath); err == nil && !now.Before(export.ExpiresAt) {
+			removeExport(strings.TrimSuffix(metaPath, exportMetaSuffix))
+		}
+	}
+}
+
+// readExportMeta reads the metadata file of an export.
+func readExportMeta(path string) (*Export, error) {
+	encoded, err := os.ReadFile(path) // #nosec G304 - the path is built from a validated token
+	if err != nil {
+		return nil, err
+	}
+	var export Export
+	if err = json.Unmarshal(encoded, &export); err != nil {
+		return nil, fmt.Errorf("failed to read export metadata: %w", err)
+	}
+	return &export, nil
+}
+
+// removeExport removes the data and metadata files of the export at path.
+func removeExport(path string) {
+	_ = os.Remove(path + exportMetaSuffix)
+	_ = os.Remove(path)
+}
+
+// redisExportStore keeps each export under a Redis key expiring with it.
+type redisExportStore struct {
+	client *redis.Client
+}
+
+// NewRedisExportStore returns an ExportStore keeping exports in Redis.
+func NewRedisExportStore(client *redis.Client) ExportStore {
+	return &redisExportStore{client: client}
+}
+
+// SaveExport implements ExportStore.
+func (s *redisExportStore) SaveExport(ctx context.Context, token string, export *Export) error {
+	ttl := time.Until(export.ExpiresAt)
+	if ttl <= 0 {
+		// A key set without a TTL would never expire
+		return fmt.Errorf("export expired at %s", export.ExpiresAt.Format(time.RFC3339))
+	}
+	encoded, err := json.Marshal(export)
+	if err != nil {
+		return fmt.Errorf("failed to encode export: %w", err)
+	}
+	if err = s.client.Set(ctx, exportKeyPrefix+token, encoded, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to save export: %w", err)
+	}
+	return nil
+}
+
+// LoadExport implements ExportStore.
+func (s *redisExportStore) LoadExport(ctx context.Context, token string) (*Export, error) {
+	if !ValidExportToken(token) {
+		return nil, ErrExportNotFound
+	}
+	encoded, err := s.client.Get(ctx, exportKeyPrefix+token).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrExportNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load export: %w", err)
+	}
+	var export Export
+	if err = json.Unmarshal(encoded, &export); err != nil {
+		return nil, fmt.Errorf("failed to decode export: %w", err)
+	}
+	return &export, nil
+}
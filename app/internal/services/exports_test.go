@@ -0,0 +1,100 @@
+package services_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/services"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportToken(t *testing.T) {
+	token := services.NewExportToken()
+	assert.Len(t, token, 32)
+	assert.True(t, services.ValidExportToken(token))
+	assert.NotEqual(t, token, services.NewExportToken())
+
+	for _, token := range []string{"", "abc", "../../../../etc/passwd.meta.json", "0123456789ABCDEF0123456789abcdef"} {
+		assert.False(t, services.ValidExportToken(token), token)
+	}
+}
+
+// testExport is an export of two breweries, expiring after ttl.
+func testExport(ttl time.Duration) *services.Export {
+	return &services.Export{
+		Filename:    "find_breweries.csv",
+		ContentType: "text/csv; charset=utf-8",
+		Rows:        2,
+		ExpiresAt:   time.Now().Add(ttl).Truncate(time.Second),
+		Data:        []byte("id,name\n1,Devil's Peak\n2,Jack Black\n"),
+	}
+}
+
+// Test that a store saves and loads exports, and no longer serves them once they expire.
+func TestExportStores(t *testing.T) {
+	mr := miniredis.RunT(t)
+	stores := map[string]services.ExportStore{
+		"redis": services.NewRedisExportStore(redis.NewClient(&redis.Options{Addr: mr.Addr()})),
+	}
+	fileStore, err := services.NewFileExportStore(filepath.Join(t.TempDir(), "exports"))
+	require.NoError(t, err)
+	stores["file"] = fileStore
+
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			token := services.NewExportToken()
+			export := testExport(time.Hour)
+			require.NoError(t, store.SaveExport(ctx, token, export))
+
+			loaded, err := store.LoadExport(ctx, token)
+			require.NoError(t, err)
+			assert.Equal(t, export.Filename, loaded.Filename)
+			assert.Equal(t, export.Rows, loaded.Rows)
+			assert.True(t, export.ExpiresAt.Equal(loaded.ExpiresAt))
+			assert.Equal(t, export.Data, loaded.Data)
+
+			_, err = store.LoadExport(ctx, services.NewExportToken())
+			assert.ErrorIs(t, err, services.ErrExportNotFound)
+			_, err = store.LoadExport(ctx, "../"+token)
+			assert.ErrorIs(t, err, services.ErrExportNotFound)
+		})
+	}
+
+	t.Run("file expiry", func(t *testing.T) {
+		ctx := context.Background()
+		dir := t.TempDir()
+		store, err := services.NewFileExportStore(dir)
+		require.NoError(t, err)
+		expired, live := services.NewExportToken(), services.NewExportToken()
+		require.NoError(t, store.SaveExport(ctx, expired, testExport(-time.Minute)))
+
+		_, err = store.LoadExport(ctx, expired)
+		assert.ErrorIs(t, err, services.ErrExportNotFound)
+		_, err = os.Stat(filepath.Join(dir, expired))
+		assert.ErrorIs(t, err, os.ErrNotExist, "an expired export's files are removed when it is loaded")
+
+		require.NoError(t, store.SaveExport(ctx, expired, testExport(-time.Minute)))
+		require.NoError(t, store.SaveExport(ctx, live, testExport(time.Hour)))
+		files, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		assert.Len(t, files, 2, "saving an export removes the expired ones")
+	})
+
+	t.Run("redis expiry", func(t *testing.T) {
+		ctx := context.Background()
+		store := stores["redis"]
+		token := services.NewExportToken()
+		require.NoError(t, store.SaveExport(ctx, token, testExport(time.Hour)))
+		mr.FastForward(time.Hour + time.Second)
+		_, err := store.LoadExport(ctx, token)
+		assert.ErrorIs(t, err, services.ErrExportNotFound)
+		assert.Error(t, store.SaveExport(ctx, token, testExport(-time.Minute)))
+	})
+}
@@ -0,0 +1,114 @@
+//go:build integration
+
+package services_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/models"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/services"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) { testutil.Main(m) }
+
+func TestIntegration_SearchBreweries(t *testing.T) {
+	ctx := context.Background()
+	db := testutil.Postgres(t)
+	breweryService := services.NewBreweryService(db, testutil.Redis(t))
+	breweryService.EnableRelevanceSearch(models.HasTrigramSearch(db))
+	breweryService.EnableDistanceSearch(models.HasDistanceSearch(db))
+
+	page, err := breweryService.SearchBreweriesPage(ctx, services.BrewerySearchQuery{
+		Country: []string{"south africa"}, Types: []string{"micro", "brewpub"}, Limit: 5,
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, page.Items)
+	assert.Greater(t, page.TotalCount, len(page.Items), "the total counts every match, not just the page")
+	for _, brewery := range page.Items {
+		assert.Equal(t, "South Africa", brewery.Country)
+		assert.Contains(t, []string{"micro", "brewpub"}, brewery.BreweryType)
+	}
+
+	named, err := breweryService.SearchBreweries(ctx, services.BrewerySearchQuery{Name: "devil's peak"})
+	require.NoError(t, err)
+	require.NotEmpty(t, named, "name searches ignore case and quote apostrophes safely")
+	assert.Contains(t, named[0].Name, "Devil's Peak")
+
+	city, err := breweryService.SearchBreweries(ctx, services.BrewerySearchQuery{
+		City: []string{"Cape Town", "Johannesburg"}, Exclude: services.BreweryExclusions{Types: []string{"brewpub"}},
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, city)
+	for _, brewery := range city {
+		assert.Contains(t, []string{"Cape Town", "Johannesburg"}, brewery.City)
+		assert.NotEqual(t, "brewpub", brewery.BreweryType)
+	}
+
+	lat, lng, radius := -33.93, 18.42, 50.0
+	nearby, err := breweryService.SearchBreweries(ctx,
+		services.BrewerySearchQuery{Lat: &lat, Lng: &lng, RadiusKm: &radius})
+	require.NoError(t, err, "PostgreSQL supports distance searches")
+	assert.NotEmpty(t, nearby)
+}
+
+func TestIntegration_SearchBeers(t *testing.T) {
+	ctx := context.Background()
+	db := testutil.Postgres(t)
+	beerService := services.NewBeerService(db, testutil.Redis(t))
+	beerService.EnableRelevanceSearch(models.HasTrigramSearch(db))
+
+	page, err := beerService.SearchBeersPage(ctx, services.BeerSearchQuery{Style: "ipa", Limit: 3})
+	require.NoError(t, err)
+	require.NotEmpty(t, page.Items)
+	assert.GreaterOrEqual(t, page.TotalCount, len(page.Items))
+	for _, beer := range page.Items {
+		assert.Contains(t, strings.ToLower(beer.Style), "ipa")
+	}
+
+	abvMin, abvMax := 4.0, 6.0
+	ranged, err := beerService.SearchBeers(ctx, services.BeerSearchQuery{ABVMin: &abvMin, ABVMax: &abvMax, Limit: 100})
+	require.NoError(t, err)
+	require.NotEmpty(t, ranged)
+	for _, beer := range ranged {
+		assert.GreaterOrEqual(t, beer.ABV, abvMin)
+		assert.LessOrEqual(t, beer.ABV, abvMax)
+	}
+
+	detail, err := beerService.GetBeerByID(ctx, page.Items[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, page.Items[0].Name, detail.Name)
+}
+
+// Test search results are cached in Redis, and that writing to the catalog drops them.
+func TestIntegration_SearchCache(t *testing.T) {
+	ctx := context.Background()
+	db := testutil.Postgres(t)
+	redisClient := testutil.Redis(t)
+	beerService := services.NewBeerService(db, redisClient)
+	breweryService := services.NewBreweryService(db, redisClient)
+	query := services.BeerSearchQuery{Name: "integration lager"}
+
+	beers, err := beerService.SearchBeers(ctx, query)
+	require.NoError(t, err)
+	assert.Empty(t, beers)
+	keys, err := redisClient.Keys(ctx, "brewsource:search:beers:*").Result()
+	require.NoError(t, err)
+	assert.NotEmpty(t, keys, "the search is cached")
+
+	breweryID, err := breweryService.CreateBrewery(ctx, services.Brewery{
+		Name: "Integration Brewing", BreweryType: "micro", City: "Durban", Country: "South Africa",
+	})
+	require.NoError(t, err)
+	_, err = beerService.CreateBeer(ctx, services.Beer{BreweryID: breweryID, Name: "Integration Lager", ABV: 4.8})
+	require.NoError(t, err)
+
+	beers, err = beerService.SearchBeers(ctx, query)
+	require.NoError(t, err)
+	require.Len(t, beers, 1, "the cached empty result was dropped when the beer was added")
+	assert.Equal(t, "Integration Brewing", beers[0].Brewery)
+}
@@ -0,0 +1,257 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Job states, in the order a job passes through them.
+const (
+	JobQueued  = "queued"
+	JobRunning = "running"
+	JobDone    = "done"
+	JobFailed  = "failed"
+)
+
+const (
+	// DefaultJobQueueSize is the number of jobs that may wait behind the running one.
+	DefaultJobQueueSize = 8
+	// DefaultJobTimeout bounds each job once it starts running.
+	DefaultJobTimeout = 30 * time.Minute
+	// maxFinishedJobs is the number of finished jobs kept for status queries; older ones are dropped.
+	maxFinishedJobs = 50
+)
+
+// ErrJobQueueFull is returned when a job is enqueued while the queue holds as many jobs as it may.
+var ErrJobQueueFull = errors.New("too many jobs are waiting; try again once some have finished")
+
+// JobFunc runs the operation of a job and summarises what it did. It may report its progress with
+// ReportProgress on ctx.
+type JobFunc func(ctx context.Context) (string, error)
+
+// Job is a snapshot of a job run by a JobRunner. Progress counts units of work done, such as pages
+// or rows, out of Total when that is known, and zero otherwise.
+type Job struct {
+	ID         string     `json:"id"`
+	Kind       string     `json:"kind"`
+	State      string     `json:"state"`
+	Progress   float64    `json:"progress"`
+	Total      float64    `json:"total,omitempty"`
+	Message    string     `json:"message,omitempty"`
+	Result     string     `json:"result,omitempty"`
+	Error      string     `json:"error,omitempty"`
+	QueuedAt   time.Time  `json:"queued_at"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// Active reports whether the job is queued or running.
+func (j *Job) Active() bool {
+	return j.State == JobQueued || j.State == JobRunning
+}
+
+// jobEntry is a job with what the runner needs to run it.
+type jobEntry struct {
+	job      Job
+	run      JobFunc
+	ctx      context.Context // supplies values such as loggers; its cancellation is ignored
+	listener func(Job)       // told of every change to the job; may be nil
+}
+
+// JobRunner runs long admin operations, such as catalog imports and brewery syncs, in the
+// background one at a time, so that no two of them write to the database at once, in the order
+// they were enqueued. Jobs are kept by ID in the runner rather than with the connection that
+// enqueued them, so any connection to the server can ask after them, until maxFinishedJobs later
+// jobs have finished.
+type JobRunner struct {
+	mu        sync.Mutex
+	jobs      map[string]*jobEntry
+	order     []string    // job IDs, oldest first
+	queue     []*jobEntry // jobs waiting to run
+	working   bool        // a goroutine is running the queue
+	queueSize int
+	timeout   time.Duration
+}
+
+// NewJobRunner creates a JobRunner letting queueSize jobs wait behind the running one, each
+// bounded by timeout once it runs. Non-positive values take DefaultJobQueueSize and
+// DefaultJobTimeout.
+func NewJobRunner(queueSize int, timeout time.Duration) *JobRunner {
+	if queueSize <= 0 {
+		queueSize = DefaultJobQueueSize
+	}
+	if timeout <= 0 {
+		timeout = DefaultJobTimeout
+	}
+	return &JobRunner{jobs: make(map[string]*jobEntry), queueSize: queueSize, timeout: timeout}
+}
+
+// Enqueue queues run as a job of kind, returning the queued job straight away. The job runs with
+// the values of ctx but not its cancellation, as the request that enqueued it ends first. listener,
+// when not nil, is called with a snapshot of the job on every change, from the goroutine running
+// the jobs. It returns ErrJobQueueFull when queueSize jobs are already waiting.
+func (r *JobRunner) Enqueue(ctx context.Context, kind string, run JobFunc, listener func(Job)) (Job, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.queue) >= r.queueSize {
+		return Job{}, ErrJobQueueFull
+	}
+	entry := &jobEntry{
+		job:      Job{ID: newJobID(), Kind: kind, State: JobQueued, QueuedAt: time.Now().UTC()},
+		run:      run,
+		ctx:      context.WithoutCancel(ctx),
+		listener: listener,
+	}
+	r.jobs[entry.job.ID] = entry
+	r.order = append(r.order, entry.job.ID)
+	r.queue = append(r.queue, entry)
+	if !r.working {
+		r.working = true
+		go r.work()
+	}
+	return entry.job, nil
+}
+
+// Job returns the job with id, reporting whether there is one.
+func (r *JobRunner) Job(id string) (Job, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entry, ok := r.jobs[id]
+	if !ok {
+		return Job{}, false
+	}
+	return entry.job, true
+}
+
+// Jobs returns the jobs kept, newest first.
+func (r *JobRunner) Jobs() []Job {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	jobs := make([]Job, 0, len(r.order))
+	for i := len(r.order) - 1; i >= 0; i-- {
+		jobs = append(jobs, r.jobs[r.order[i]].job)
+	}
+	return jobs
+}
+
+// ActiveJob returns the queued or running job of kind, reporting whether there is one.
+func (r *JobRunner) ActiveJob(kind string) (Job, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, id := range r.order {
+		if job := r.jobs[id].job; job.Kind == kind && job.Active() {
+			return job, true
+		}
+	}
+	return Job{}, false
+}
+
+// work runs the queued jobs in turn, returning once the queue is empty.
+func (r *JobRunner) work() {
+	for {
+		r.mu.Lock()
+		if len(r.queue) == 0 {
+			r.working = false
+			r.mu.Unlock()
+			return
+		}
+		entry := r.queue[0]
+		r.queue = r.queue[1:]
+		r.mu.Unlock()
+		r.runJob(entry)
+	}
+}
+
+// runJob runs one job, recording its outcome. A job that panics fails rather than stopping the
+// runner.
+func (r *JobRunner) runJob(entry *jobEntry) {
+	r.update(entry, func(job *Job) {
+		now := time.Now().UTC()
+		job.State, job.StartedAt = JobRunning, &now
+	})
+	ctx, cancel := context.WithTimeout(entry.ctx, r.timeout)
+	defer cancel()
+	ctx = context.WithValue(ctx, progressKey{}, func(progress, total float64, message string) {
+		r.update(entry, func(job *Job) {
+			job.Progress, job.Total, job.Message = progress, total, message
+		})
+	})
+
+	result, err := func() (result string, err error) {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				err = fmt.Errorf("job panicked: %v", recovered)
+			}
+		}()
+		return entry.run(ctx)
+	}()
+	r.update(entry, func(job *Job) {
+		now := time.Now().UTC()
+		job.FinishedAt = &now
+		if err != nil {
+			job.State, job.Error = JobFailed, err.Error()
+			return
+		}
+		job.State, job.Result = JobDone, result
+		if job.Total > 0 {
+			job.Progress = job.Total
+		}
+	})
+	r.dropFinished()
+}
+
+// update changes the job of entry and tells its listener.
+func (r *JobRunner) update(entry *jobEntry, change func(job *Job)) {
+	r.mu.Lock()
+	change(&entry.job)
+	job := entry.job
+	r.mu.Unlock()
+	if entry.listener != nil {
+		entry.listener(job)
+	}
+}
+
+// dropFinished forgets the oldest finished jobs beyond maxFinishedJobs.
+func (r *JobRunner) dropFinished() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	finished := 0
+	for _, id := range r.order {
+		if !r.jobs[id].job.Active() {
+			finished++
+		}
+	}
+	kept := r.order[:0]
+	for _, id := range r.order {
+		if finished > maxFinishedJobs && !r.jobs[id].job.Active() {
+			delete(r.jobs, id)
+			finished--
+			continue
+		}
+		kept = append(kept, id)
+	}
+	r.order = kept
+}
+
+// newJobID returns a random job ID of 16 hex characters.
+func newJobID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+type progressKey struct{}
+
+// ReportProgress records the progress of the job running with ctx: progress units of work done, out
+// of total when that is known and zero otherwise, described by message. It does nothing outside a
+// job, so operations also run from the command line may call it freely.
+func ReportProgress(ctx context.Context, progress, total float64, message string) {
+	if report, ok := ctx.Value(progressKey{}).(func(float64, float64, string)); ok {
+		report(progress, total, message)
+	}
+}
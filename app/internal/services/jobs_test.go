@@ -0,0 +1,139 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// waitForJob waits for the job with id to reach state, returning it.
+func waitForJob(t *testing.T, runner *services.JobRunner, id, state string) services.Job {
+	t.Helper()
+	var job services.Job
+	require.Eventually(t, func() bool {
+		job, _ = runner.Job(id)
+		return job.State == state
+	}, time.Second, 5*time.Millisecond, "job %s never reached %s", id, state)
+	return job
+}
+
+func TestJobRunner_RunsJobsOneAtATime(t *testing.T) {
+	runner := services.NewJobRunner(0, 0)
+	release := make(chan struct{})
+	var mu sync.Mutex
+	var updates []services.Job
+	listener := func(job services.Job) {
+		mu.Lock()
+		defer mu.Unlock()
+		updates = append(updates, job)
+	}
+
+	first, err := runner.Enqueue(context.Background(), "import", func(ctx context.Context) (string, error) {
+		services.ReportProgress(ctx, 1, 2, "first half")
+		<-release
+		return "imported", nil
+	}, listener)
+	require.NoError(t, err)
+	assert.Equal(t, services.JobQueued, first.State)
+	second, err := runner.Enqueue(context.Background(), "seed", func(context.Context) (string, error) {
+		return "", errors.New("seed file missing")
+	}, nil)
+	require.NoError(t, err)
+
+	running := waitForJob(t, runner, first.ID, services.JobRunning)
+	assert.Equal(t, 1.0, running.Progress)
+	assert.Equal(t, "first half", running.Message)
+	// The second job waits for the first, and is the active job of its kind meanwhile
+	queued, ok := runner.ActiveJob("seed")
+	require.True(t, ok)
+	assert.Equal(t, second.ID, queued.ID)
+	assert.Equal(t, services.JobQueued, queued.State)
+
+	close(release)
+	done := waitForJob(t, runner, first.ID, services.JobDone)
+	assert.Equal(t, "imported", done.Result)
+	assert.Equal(t, 2.0, done.Progress, "a finished job has done all its work")
+	require.NotNil(t, done.FinishedAt)
+	failed := waitForJob(t, runner, second.ID, services.JobFailed)
+	assert.Equal(t, "seed file missing", failed.Error)
+	assert.False(t, done.StartedAt.After(*failed.StartedAt), "jobs run in the order enqueued")
+
+	_, ok = runner.ActiveJob("seed")
+	assert.False(t, ok)
+	jobs := runner.Jobs()
+	require.Len(t, jobs, 2)
+	assert.Equal(t, second.ID, jobs[0].ID, "newest first")
+
+	mu.Lock()
+	defer mu.Unlock()
+	states := make([]string, 0, len(updates))
+	for _, update := range updates {
+		states = append(states, update.State)
+	}
+	assert.Equal(t, []string{services.JobRunning, services.JobRunning, services.JobDone}, states)
+}
+
+func TestJobRunner_QueueFull(t *testing.T) {
+	runner := services.NewJobRunner(1, 0)
+	release := make(chan struct{})
+	defer close(release)
+	block := func(context.Context) (string, error) {
+		<-release
+		return "", nil
+	}
+
+	running, err := runner.Enqueue(context.Background(), "import", block, nil)
+	require.NoError(t, err)
+	waitForJob(t, runner, running.ID, services.JobRunning)
+	_, err = runner.Enqueue(context.Background(), "import", block, nil)
+	require.NoError(t, err)
+	_, err = runner.Enqueue(context.Background(), "import", block, nil)
+	assert.ErrorIs(t, err, services.ErrJobQueueFull)
+}
+
+func TestJobRunner_FailsJobs(t *testing.T) {
+	tests := []struct {
+		name    string
+		timeout time.Duration
+		run     services.JobFunc
+		wantErr string
+	}{
+		{
+			name:    "panic",
+			run:     func(context.Context) (string, error) { panic("bad row") },
+			wantErr: "job panicked: bad row",
+		},
+		{
+			name:    "timeout",
+			timeout: 10 * time.Millisecond,
+			run: func(ctx context.Context) (string, error) {
+				<-ctx.Done()
+				return "", ctx.Err()
+			},
+			wantErr: context.DeadlineExceeded.Error(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := services.NewJobRunner(0, tt.timeout)
+			// The job outlives the cancelled request that enqueued it
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			job, err := runner.Enqueue(ctx, "import", tt.run, nil)
+			require.NoError(t, err)
+			failed := waitForJob(t, runner, job.ID, services.JobFailed)
+			assert.Equal(t, tt.wantErr, failed.Error)
+
+			// The runner carries on after a failed job
+			next, err := runner.Enqueue(ctx, "import", func(context.Context) (string, error) { return "ok", nil }, nil)
+			require.NoError(t, err)
+			waitForJob(t, runner, next.ID, services.JobDone)
+		})
+	}
+}
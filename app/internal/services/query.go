@@ -0,0 +1,175 @@
+// Package services provides business logic and service layer functions for Brewsource MCP, including beer and brewery operations.
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/requestid"
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultQueryTimeout bounds each database query unless changed with SetQueryTimeout.
+	DefaultQueryTimeout = 5 * time.Second
+	// DefaultSlowQueryThreshold is how long a query may run before it is logged, unless changed
+	// with SetSlowQueryThreshold.
+	DefaultSlowQueryThreshold = time.Second
+	// maxLoggedSQLLength truncates the SQL of slow queries in the log.
+	maxLoggedSQLLength = 500
+)
+
+// queryGuard bounds database queries with a timeout, logs slow ones, and retries reads that fail
+// with a transient error. A nil guard applies the defaults.
+type queryGuard struct {
+	timeout  atomic.Int64 // time.Duration
+	slow     atomic.Int64 // time.Duration
+	attempts atomic.Int64
+	backoff  atomic.Int64 // time.Duration
+}
+
+func newQueryGuard() *queryGuard {
+	g := &queryGuard{}
+	g.timeout.Store(int64(DefaultQueryTimeout))
+	g.slow.Store(int64(DefaultSlowQueryThreshold))
+	g.attempts.Store(DefaultQueryAttempts)
+	g.backoff.Store(int64(DefaultQueryRetryBackoff))
+	return g
+}
+
+func (g *queryGuard) setTimeout(timeout time.Duration) {
+	if g != nil && timeout > 0 {
+		g.timeout.Store(int64(timeout))
+	}
+}
+
+func (g *queryGuard) setSlowThreshold(threshold time.Duration) {
+	if g != nil && threshold > 0 {
+		g.slow.Store(int64(threshold))
+	}
+}
+
+func (g *queryGuard) limits() (time.Duration, time.Duration) {
+	if g == nil {
+		return DefaultQueryTimeout, DefaultSlowQueryThreshold
+	}
+	return time.Duration(g.timeout.Load()), time.Duration(g.slow.Load())
+}
+
+// begin starts a query named name, returning a context bounded by the query timeout and a finish
+// function to defer with the address of the caller's error. Once the rows are read, finish cancels
+// the context, logs the query when it ran longer than the slow threshold, and replaces an error
+// caused by the timeout with one wrapping ErrQueryTimeout. A deadline or cancellation from the
+// caller's own context is left as it is.
+func (g *queryGuard) begin(
+	ctx context.Context,
+	name, query string,
+	filters interface{},
+) (context.Context, func(*error)) {
+	timeout, slow := g.limits()
+	queryCtx, cancel := context.WithTimeout(ctx, timeout)
+	started := time.Now()
+	return queryCtx, func(errp *error) {
+		elapsed := time.Since(started)
+		timedOut := *errp != nil && ctx.Err() == nil && errors.Is(queryCtx.Err(), context.DeadlineExceeded)
+		cancel()
+		if elapsed >= slow || timedOut {
+			requestid.Logger(ctx).WithFields(logrus.Fields{
+				"query":       name,
+				"sql":         sanitizeSQL(query),
+				"duration_ms": elapsed.Milliseconds(),
+				"filters":     filterSummary(filters),
+				"timed_out":   timedOut,
+			}).Warn("Slow database query")
+		}
+		if timedOut {
+			*errp = fmt.Errorf("%w: %s took longer than %s", ErrQueryTimeout, name, timeout)
+		}
+	}
+}
+
+// sanitizeSQL collapses the whitespace in a query and truncates it for logging. Queries take their
+// values as arguments, so the SQL itself holds no user input.
+func sanitizeSQL(query string) string {
+	query = strings.Join(strings.Fields(query), " ")
+	if len(query) > maxLoggedSQLLength {
+		query = query[:maxLoggedSQLLength] + "..."
+	}
+	return query
+}
+
+// filterSummary describes a query's filters for logging, e.g. `Name="ipa" Limit=20` for a search
+// query. Struct fields that are unset are left out; other values are printed as they are.
+func filterSummary(filters interface{}) string {
+	v := reflect.ValueOf(filters)
+	if !v.IsValid() {
+		return ""
+	}
+	if v.Kind() != reflect.Struct {
+		return fmt.Sprint(filters)
+	}
+	parts := []string{}
+	for i := range v.NumField() {
+		field, value := v.Type().Field(i), v.Field(i)
+		if !field.IsExported() || value.IsZero() {
+			continue
+		}
+		if value.Kind() == reflect.Pointer {
+			value = value.Elem()
+		}
+		format := "%s=%v"
+		if value.Kind() == reflect.String {
+			format = "%s=%q"
+		}
+		parts = append(parts, fmt.Sprintf(format, field.Name, value.Interface()))
+	}
+	return strings.Join(parts, " ")
+}
+
+// SetQueryTimeout changes how long each beer query may run. Non-positive values are ignored.
+func (s *BeerService) SetQueryTimeout(timeout time.Duration) {
+	s.queries.setTimeout(timeout)
+}
+
+// SetSlowQueryThreshold changes how long a beer query may run before it is logged. Non-positive
+// values are ignored.
+func (s *BeerService) SetSlowQueryThreshold(threshold time.Duration) {
+	s.queries.setSlowThreshold(threshold)
+}
+
+// SetQueryTimeout changes how long each brewery query may run. Non-positive values are ignored.
+func (s *BreweryService) SetQueryTimeout(timeout time.Duration) {
+	s.queries.setTimeout(timeout)
+}
+
+// SetSlowQueryThreshold changes how long a brewery query may run before it is logged. Non-positive
+// values are ignored.
+func (s *BreweryService) SetSlowQueryThreshold(threshold time.Duration) {
+	s.queries.setSlowThreshold(threshold)
+}
+
+// ping checks that the database answers within the query timeout.
+func (g *queryGuard) ping(ctx context.Context, db *sqlx.DB) (err error) {
+	ctx, finish := g.begin(ctx, "ping", "", nil)
+	defer finish(&err)
+	if err = db.PingContext(ctx); err != nil {
+		return fmt.Errorf("database ping failed: %w", err)
+	}
+	return nil
+}
+
+// Ping checks that the beer database answers within the query timeout.
+func (s *BeerService) Ping(ctx context.Context) error {
+	return s.queries.ping(ctx, s.db.DB)
+}
+
+// Ping checks that the brewery database answers within the query timeout.
+func (s *BreweryService) Ping(ctx context.Context) error {
+	return s.queries.ping(ctx, s.db.DB)
+}
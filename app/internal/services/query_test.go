@@ -0,0 +1,103 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/services"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+	logtest "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var beerSearchColumns = []string{"id", "name", "style", "brewery", "city", "country", "abv", "ibu"}
+
+func TestQueryTimeout(t *testing.T) {
+	hook := logtest.NewGlobal()
+	t.Cleanup(hook.Reset)
+
+	t.Run("a query running past the timeout returns ErrQueryTimeout", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		beerService := setupBeerService(db)
+		beerService.SetQueryTimeout(20 * time.Millisecond)
+		mock.ExpectQuery(`FROM beers b`).
+			WillDelayFor(time.Second).
+			WillReturnRows(sqlmock.NewRows(beerSearchColumns))
+
+		_, err := beerService.SearchBeers(context.Background(), services.BeerSearchQuery{Name: "IPA", Limit: 5})
+		require.ErrorIs(t, err, services.ErrQueryTimeout)
+		assert.Contains(t, err.Error(), "search_beers took longer than 20ms")
+
+		entry := hook.LastEntry()
+		require.NotNil(t, entry)
+		assert.Equal(t, logrus.WarnLevel, entry.Level)
+		assert.Equal(t, "search_beers", entry.Data["query"])
+		assert.Equal(t, true, entry.Data["timed_out"])
+		assert.Equal(t, `Name="IPA" Limit=5 Sort="name" Order="asc"`, entry.Data["filters"])
+		assert.NotContains(t, entry.Data["sql"], "\n")
+	})
+
+	t.Run("the caller's own deadline is not reported as a query timeout", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		breweryService := setupBreweryService(db)
+		mock.ExpectQuery(`FROM breweries`).WillDelayFor(time.Second).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		_, err := breweryService.GetBreweryByID(ctx, 1)
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, services.ErrQueryTimeout)
+	})
+
+	t.Run("slow queries are logged", func(t *testing.T) {
+		hook.Reset()
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		breweryService := setupBreweryService(db)
+		breweryService.SetSlowQueryThreshold(10 * time.Millisecond)
+		mock.ExpectQuery(`FROM breweries br\s+LEFT JOIN beers b`).
+			WillDelayFor(30 * time.Millisecond).
+			WillReturnRows(sqlmock.NewRows([]string{"name", "id", "name", "style", "abv", "ibu", "total_count"}).
+				AddRow("Test Brewery", nil, nil, "", 0, 0, 0))
+
+		_, err := breweryService.GetBreweryBeers(context.Background(), 7, 10)
+		require.NoError(t, err)
+		entry := hook.LastEntry()
+		require.NotNil(t, entry)
+		assert.Equal(t, "brewery_beers", entry.Data["query"])
+		assert.Equal(t, "7", entry.Data["filters"])
+		assert.Equal(t, false, entry.Data["timed_out"])
+	})
+
+	t.Run("fast queries are not logged", func(t *testing.T) {
+		hook.Reset()
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		mock.ExpectQuery(`FROM beers b`).WillReturnRows(sqlmock.NewRows(beerSearchColumns))
+
+		_, err := setupBeerService(db).SearchBeers(context.Background(), services.BeerSearchQuery{Name: "IPA"})
+		require.NoError(t, err)
+		assert.Nil(t, hook.LastEntry())
+	})
+}
+
+func TestPing(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	require.NoError(t, err)
+	defer db.Close()
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	mock.ExpectPing()
+	mock.ExpectPing().WillReturnError(errors.New("connection refused"))
+
+	require.NoError(t, services.NewBeerService(sqlxDB, nil).Ping(context.Background()))
+	err = services.NewBreweryService(sqlxDB, nil).Ping(context.Background())
+	require.ErrorContains(t, err, "database ping failed: connection refused")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,129 @@
+// Package services provides business logic and service layer functions for Brewsource MCP, including beer and brewery operations.
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+)
+
+const (
+	// MaxRandomBeers is the largest count RandomBeers accepts.
+	MaxRandomBeers = 5
+	// randomBeerWindow is the number of consecutive matching beers, by id, that RandomBeers shuffles.
+	randomBeerWindow = 100
+)
+
+// RandomBeers returns up to count beers picked at random, optionally narrowed to a style and to a
+// brewery country. Both filters are matched as substrings, ignoring case. Fewer beers are returned
+// when fewer match, and none when nothing matches.
+//
+// A plain ORDER BY random() would read and sort every matching row. Instead the query picks a random
+// starting id between MIN(id) and MAX(id), which the primary key index answers without a scan, walks
+// the index from there to collect at most randomBeerWindow matching beers, and shuffles only that
+// window. When the start falls too close to the end of the table to fill count, a second pass takes
+// the window from the lowest ids instead. Beers near each other by id are therefore more likely to
+// be returned together, which is an acceptable trade for a suggestion.
+func (s *BeerService) RandomBeers(ctx context.Context, style, country string, count int) ([]*BeerSearchResult, error) {
+	if count < 1 || count > MaxRandomBeers {
+		return nil, fmt.Errorf(
+			"%w: count must be between 1 and %d (got %d)", ErrInvalidLimit, MaxRandomBeers, count,
+		)
+	}
+	normalizeSearchFields(false, &style, &country)
+
+	beers, err := s.randomBeerWindow(ctx, style, country, count, rand.Float64()) //nolint:gosec // not security sensitive
+	if err != nil {
+		return nil, err
+	}
+	if len(beers) < count {
+		wrapped, wrapErr := s.randomBeerWindow(ctx, style, country, count, 0)
+		if wrapErr != nil {
+			return nil, wrapErr
+		}
+		seen := make(map[int]bool, len(beers))
+		for _, beer := range beers {
+			seen[beer.ID] = true
+		}
+		for _, beer := range wrapped {
+			if len(beers) == count {
+				break
+			}
+			if !seen[beer.ID] {
+				beers = append(beers, beer)
+			}
+		}
+	}
+	return beers, nil
+}
+
+// randomBeerWindow shuffles the window of matching beers starting at the id the given fraction of
+// the way from the lowest id to the highest, and returns the first count of them.
+func (s *BeerService) randomBeerWindow(
+	ctx context.Context,
+	style, country string,
+	count int,
+	start float64,
+) (_ []*BeerSearchResult, err error) {
+	args := []interface{}{start}
+	filters := ""
+	if style != "" {
+		args = append(args, "%"+style+"%")
+		filters += " AND b.style ILIKE $" + strconv.Itoa(len(args))
+	}
+	if country != "" {
+		args = append(args, "%"+country+"%")
+		filters += " AND br.country ILIKE $" + strconv.Itoa(len(args))
+	}
+	args = append(args, count)
+	startID := "MIN(id) + FLOOR($1 * (MAX(id) - MIN(id) + 1))::bigint"
+	if s.db.sqlite {
+		// SQLite has no FLOOR without its math functions; its CAST truncates, which for this
+		// non-negative offset is the same
+		startID = "MIN(id) + CAST($1 * (MAX(id) - MIN(id) + 1) AS INTEGER)"
+	}
+	q := `
+		  SELECT id, name, style, brewery, city, country, abv, ibu
+		  FROM (
+		    SELECT b.id, b.name, COALESCE(b.style, '') AS style, br.name AS brewery,
+		           COALESCE(br.city, '') AS city, COALESCE(br.country, '') AS country,
+		           COALESCE(b.abv, 0) AS abv, COALESCE(b.ibu, 0) AS ibu
+		    FROM beers b
+		    JOIN breweries br ON b.brewery_id = br.id
+		    WHERE b.id >= (SELECT ` + startID + ` FROM beers)` +
+		liveBeers + filters + `
+		    ORDER BY b.id
+		    LIMIT ` + strconv.Itoa(randomBeerWindow) + `
+		  ) candidates
+		  ORDER BY RANDOM()
+		  LIMIT $` + strconv.Itoa(len(args))
+
+	filterValues := struct{ Style, Country string }{style, country}
+	ctx, finish := s.queries.begin(ctx, "random_beers", q, filterValues)
+	defer finish(&err)
+
+	var rows *sql.Rows
+	err = s.queries.retry(ctx, "random_beers", func() (err error) {
+		rows, err = s.db.QueryContext(ctx, q, args...)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to pick random beers: %w", err)
+	}
+	defer rows.Close()
+
+	beers := []*BeerSearchResult{}
+	for rows.Next() {
+		var r BeerSearchResult
+		if err = rows.Scan(&r.ID, &r.Name, &r.Style, &r.Brewery, &r.City, &r.Country, &r.ABV, &r.IBU); err != nil {
+			return nil, fmt.Errorf("failed to pick random beers: %w", err)
+		}
+		beers = append(beers, &r)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to pick random beers: %w", err)
+	}
+	return beers, nil
+}
@@ -0,0 +1,121 @@
+// Package services_test contains tests for the business logic and service layer functions in Brewsource MCP.
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/services"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const randomBeerQuery = `WHERE b\.id >= \(SELECT MIN\(id\) \+ FLOOR\(\$1 \* \(MAX\(id\) - MIN\(id\) \+ 1\)\)::bigint FROM beers\)` +
+	` AND b\.deleted_at IS NULL AND br\.deleted_at IS NULL`
+
+func randomBeerRows(ids ...int) *sqlmock.Rows {
+	rows := sqlmock.NewRows([]string{"id", "name", "style", "brewery", "city", "country", "abv", "ibu"})
+	for _, id := range ids {
+		rows.AddRow(id, "Beer", "Stout", "Brewery", "", "Ireland", 4.2, 45)
+	}
+	return rows
+}
+
+func TestRandomBeers(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Shuffles a bounded window from a random start", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		service := setupBeerService(db)
+		query := randomBeerQuery + `\s+ORDER BY b\.id\s+LIMIT 100\s+\) candidates\s+ORDER BY RANDOM\(\)\s+LIMIT \$2$`
+		mock.ExpectQuery(query).
+			WithArgs(sqlmock.AnyArg(), 2).
+			WillReturnRows(randomBeerRows(7, 3))
+
+		beers, err := service.RandomBeers(ctx, "", "", 2)
+
+		require.NoError(t, err)
+		require.Len(t, beers, 2)
+		assert.Equal(t, 7, beers[0].ID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Filters by style and country", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		service := setupBeerService(db)
+		query := randomBeerQuery + ` AND b\.style ILIKE \$2 AND br\.country ILIKE \$3\s+ORDER BY b\.id`
+		mock.ExpectQuery(query).
+			WithArgs(sqlmock.AnyArg(), "%Dry Stout%", "%Ireland%", 1).
+			WillReturnRows(randomBeerRows(5))
+
+		beers, err := service.RandomBeers(ctx, " Dry  Stout ", "Ireland", 1)
+
+		require.NoError(t, err)
+		require.Len(t, beers, 1)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Wraps around to the lowest ids when the window is short", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		service := setupBeerService(db)
+		mock.ExpectQuery(randomBeerQuery).
+			WithArgs(sqlmock.AnyArg(), 3).
+			WillReturnRows(randomBeerRows(9))
+		mock.ExpectQuery(randomBeerQuery).
+			WithArgs(0.0, 3).
+			WillReturnRows(randomBeerRows(9, 1, 2))
+
+		beers, err := service.RandomBeers(ctx, "", "", 3)
+
+		require.NoError(t, err)
+		ids := []int{}
+		for _, beer := range beers {
+			ids = append(ids, beer.ID)
+		}
+		assert.Equal(t, []int{9, 1, 2}, ids, "beers from the first window should not be repeated")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Returns no beers when nothing matches", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		service := setupBeerService(db)
+		mock.ExpectQuery(randomBeerQuery).WillReturnRows(randomBeerRows())
+		mock.ExpectQuery(randomBeerQuery).WillReturnRows(randomBeerRows())
+
+		beers, err := service.RandomBeers(ctx, "Nonexistent", "", 1)
+
+		require.NoError(t, err)
+		assert.Empty(t, beers)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Rejects out-of-range counts", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		service := setupBeerService(db)
+
+		for _, count := range []int{0, -1, services.MaxRandomBeers + 1} {
+			_, err := service.RandomBeers(ctx, "", "", count)
+			require.ErrorIs(t, err, services.ErrInvalidLimit, "count %d", count)
+		}
+		assert.NoError(t, mock.ExpectationsWereMet(), "invalid counts should not reach the database")
+	})
+
+	t.Run("Wraps query errors", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		service := setupBeerService(db)
+		mock.ExpectQuery(randomBeerQuery).WillReturnError(errors.New("connection reset"))
+
+		_, err := service.RandomBeers(ctx, "", "", 1)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to pick random beers")
+	})
+}
@@ -0,0 +1,77 @@
+// Package services provides business logic and service layer functions for Brewsource MCP, including beer and brewery operations.
+package services
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+)
+
+// MaxRecommendationCandidates caps the beers RecommendationCandidates returns, so that scoring them
+// stays cheap however many beers share a popular style.
+const MaxRecommendationCandidates = 500
+
+// BeerCandidate is a beer considered for a recommendation: its declared style and stored stats,
+// and the country of its brewery. A zero stat was not recorded.
+type BeerCandidate struct {
+	ID      int     `db:"id"      json:"id"`
+	Name    string  `db:"name"    json:"name"`
+	Style   string  `db:"style"   json:"style"`
+	Brewery string  `db:"brewery" json:"brewery"`
+	Country string  `db:"country" json:"country"`
+	ABV     float64 `db:"abv"     json:"abv"`
+	IBU     int     `db:"ibu"     json:"ibu"`
+	SRM     float64 `db:"srm"     json:"srm"`
+}
+
+// RecommendationCandidates returns the beers whose declared style matches any of styles, as the
+// style filter of a search does, leaving out the beer with excludeID and soft-deleted beers and
+// breweries. At most MaxRecommendationCandidates are returned, the earliest added first; the caller
+// scores and ranks them. Narrowing to the styles first keeps the query from reading the whole table,
+// so at least one style is required.
+func (s *BeerService) RecommendationCandidates(
+	ctx context.Context,
+	styles []string,
+	excludeID int,
+) (_ []*BeerCandidate, err error) {
+	styles = lowerAll(normalizeSearchValues(false, styles))
+	if len(styles) == 0 {
+		return nil, fmt.Errorf("%w: at least one style is required", ErrInvalidSearchQuery)
+	}
+	slices.Sort(styles)
+	styles = slices.Compact(styles)
+
+	filters, args := beerSearchFilters(BeerSearchQuery{StyleAliases: styles}, s.db.sqlite)
+	if excludeID > 0 {
+		args = append(args, excludeID)
+		filters += " AND b.id <> $" + strconv.Itoa(len(args))
+	}
+	args = append(args, MaxRecommendationCandidates)
+	q := `
+		  SELECT b.id, b.name, COALESCE(b.style, '') AS style, br.name AS brewery,
+		         COALESCE(br.country, '') AS country, COALESCE(b.abv, 0) AS abv,
+		         COALESCE(b.ibu, 0) AS ibu, COALESCE(b.srm, 0) AS srm
+		  FROM beers b
+		  JOIN breweries br ON b.brewery_id = br.id
+		  WHERE 1=1` + filters + `
+		  ORDER BY b.id
+		  LIMIT $` + strconv.Itoa(len(args))
+
+	filterValues := struct {
+		Styles    []string
+		ExcludeID int
+	}{styles, excludeID}
+	ctx, finish := s.queries.begin(ctx, "recommend_beers", q, filterValues)
+	defer finish(&err)
+
+	var candidates []*BeerCandidate
+	err = s.queries.retry(ctx, "recommend_beers", func() error {
+		candidates = []*BeerCandidate{}
+		return s.db.SelectContext(ctx, &candidates, q, args...)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find recommendation candidates: %w", err)
+	}
+	return candidates, nil
+}
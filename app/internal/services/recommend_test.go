@@ -0,0 +1,59 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/services"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecommendationCandidates(t *testing.T) {
+	ctx := context.Background()
+	expectedQuery := `FROM beers b\s+JOIN breweries br ON b\.brewery_id = br\.id\s+` +
+		`WHERE 1=1 AND b\.deleted_at IS NULL AND br\.deleted_at IS NULL` +
+		` AND \(b\.style ILIKE \$1 OR b\.style ILIKE \$2\)` + distinctBeers +
+		` AND b\.id <> \$3\s+ORDER BY b\.id\s+LIMIT \$4$`
+
+	t.Run("Narrows to the styles, leaving out the liked beer", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		mock.ExpectQuery(expectedQuery).
+			WithArgs("%american ipa%", "%hazy ipa%", 7, services.MaxRecommendationCandidates).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "style", "brewery", "country", "abv", "ibu", "srm"}).
+				AddRow(2, "Capital IPA", "American IPA", "Capital Craft", "South Africa", 6.0, 55, 7.0))
+
+		candidates, err := setupBeerService(db).RecommendationCandidates(ctx,
+			[]string{"American IPA", " hazy  ipa", "american ipa", ""}, 7)
+
+		require.NoError(t, err)
+		require.Len(t, candidates, 1)
+		assert.Equal(t, "South Africa", candidates[0].Country)
+		assert.InDelta(t, 7.0, candidates[0].SRM, 0.001)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Requires a style", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		_, err := setupBeerService(db).RecommendationCandidates(ctx, []string{" "}, 0)
+
+		require.ErrorIs(t, err, services.ErrInvalidSearchQuery)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Database errors are wrapped", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		mock.ExpectQuery(`ORDER BY b\.id\s+LIMIT \$2$`).WillReturnError(errors.New("connection reset"))
+
+		_, err := setupBeerService(db).RecommendationCandidates(ctx, []string{"Stout"}, 0)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to find recommendation candidates: connection reset")
+	})
+}
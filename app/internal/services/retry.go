@@ -0,0 +1,122 @@
+// Package services provides business logic and service layer functions for Brewsource MCP, including beer and brewery operations.
+package services
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/requestid"
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultQueryAttempts is how many times a read is tried, unless changed with SetQueryRetries.
+	DefaultQueryAttempts = 3
+	// DefaultQueryRetryBackoff is the wait before the first retry, unless changed with
+	// SetQueryRetries. Each later retry waits twice as long, with jitter.
+	DefaultQueryRetryBackoff = 100 * time.Millisecond
+)
+
+func (g *queryGuard) setRetries(attempts int, backoff time.Duration) {
+	if g == nil {
+		return
+	}
+	if attempts > 0 {
+		g.attempts.Store(int64(attempts))
+	}
+	if backoff > 0 {
+		g.backoff.Store(int64(backoff))
+	}
+}
+
+func (g *queryGuard) retryLimits() (int, time.Duration) {
+	if g == nil {
+		return DefaultQueryAttempts, DefaultQueryRetryBackoff
+	}
+	return int(g.attempts.Load()), time.Duration(g.backoff.Load())
+}
+
+// retry runs a read-only query named name, running it again after a jittered, doubling backoff
+// while it fails with a transient error. No retry is made once ctx is done, so a cancelled caller
+// or an expired query timeout ends the query with the error of the last attempt.
+func (g *queryGuard) retry(ctx context.Context, name string, run func() error) error {
+	attempts, backoff := g.retryLimits()
+	for attempt := 1; ; attempt++ {
+		err := run()
+		if err == nil || attempt >= attempts || ctx.Err() != nil || !isTransientError(err) {
+			return err
+		}
+
+		delay := backoff << (attempt - 1)
+		delay = delay/2 + rand.N(delay/2+1) //nolint:gosec // jitter is not security sensitive
+		requestid.Logger(ctx).WithFields(logrus.Fields{
+			"query":    name,
+			"attempt":  attempt,
+			"delay_ms": delay.Milliseconds(),
+			"error":    err.Error(),
+		}).Warn("Retrying database query after transient error")
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// isTransientError reports whether a failed read is worth retrying: the connection was lost, the
+// server asked for the transaction to be run again, or a SQLite database was locked by a writer.
+// Context cancellations and deadlines are never transient.
+func isTransientError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	for _, transient := range []error{
+		sql.ErrConnDone, driver.ErrBadConn, io.EOF, io.ErrUnexpectedEOF, syscall.ECONNRESET, syscall.ECONNREFUSED,
+	} {
+		if errors.Is(err, transient) {
+			return true
+		}
+	}
+	// SQLite's busy and locked errors, recognised by message since the driver's error type is only
+	// built with cgo
+	for _, locked := range []string{"database is locked", "database table is locked"} {
+		if strings.HasPrefix(err.Error(), locked) {
+			return true
+		}
+	}
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return false
+	}
+	if pqErr.Code.Class().Name() == "connection_exception" {
+		return true
+	}
+	switch pqErr.Code.Name() {
+	case "serialization_failure", "deadlock_detected", "admin_shutdown", "crash_shutdown", "cannot_connect_now":
+		return true
+	}
+	return false
+}
+
+// SetQueryRetries changes how many times a beer read is tried and the wait before the first retry.
+// Non-positive values are ignored; one attempt turns retries off.
+func (s *BeerService) SetQueryRetries(attempts int, backoff time.Duration) {
+	s.queries.setRetries(attempts, backoff)
+}
+
+// SetQueryRetries changes how many times a brewery read is tried and the wait before the first
+// retry. Non-positive values are ignored; one attempt turns retries off.
+func (s *BreweryService) SetQueryRetries(attempts int, backoff time.Duration) {
+	s.queries.setRetries(attempts, backoff)
+}
@@ -0,0 +1,78 @@
+package services_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/services"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryRetries(t *testing.T) {
+	t.Run("a transient failure is retried", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		beerService := setupBeerService(db)
+		beerService.SetQueryRetries(3, time.Millisecond)
+		mock.ExpectQuery(`FROM beers b`).WillReturnError(sql.ErrConnDone)
+		mock.ExpectQuery(`FROM beers b`).WillReturnError(&pq.Error{Code: "40001"})
+		mock.ExpectQuery(`FROM beers b`).
+			WillReturnRows(sqlmock.NewRows(beerSearchColumns).AddRow(1, "Test IPA", "IPA", "Brewery", "", "ZA", 6.5, 60))
+
+		results, err := beerService.SearchBeers(context.Background(), services.BeerSearchQuery{Name: "IPA"})
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, "Test IPA", results[0].Name)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("gives up after the configured attempts", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		breweryService := setupBreweryService(db)
+		breweryService.SetQueryRetries(2, time.Millisecond)
+		mock.ExpectQuery(`FROM breweries`).WillReturnError(sql.ErrConnDone)
+		mock.ExpectQuery(`FROM breweries`).WillReturnError(sql.ErrConnDone)
+
+		_, err := breweryService.GetBreweryByID(context.Background(), 1)
+		require.ErrorIs(t, err, sql.ErrConnDone)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("other errors are not retried", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		breweryService := setupBreweryService(db)
+		breweryService.SetQueryRetries(3, time.Millisecond)
+		mock.ExpectQuery(`FROM breweries`).WillReturnError(errors.New("syntax error"))
+		mock.ExpectQuery(`FROM breweries`).WillReturnError(sql.ErrConnDone)
+
+		_, err := breweryService.GetBreweryByID(context.Background(), 1)
+		require.ErrorContains(t, err, "syntax error")
+		assert.Error(t, mock.ExpectationsWereMet(), "the second query should not have run")
+	})
+
+	t.Run("the caller's cancellation stops the retries", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		beerService := setupBeerService(db)
+		beerService.SetQueryRetries(3, time.Minute)
+		mock.ExpectQuery(`FROM beers b`).WillReturnError(sql.ErrConnDone)
+		mock.ExpectQuery(`FROM beers b`).WillReturnRows(sqlmock.NewRows(beerSearchColumns))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		started := time.Now()
+		_, err := beerService.GetBeerByID(ctx, 1)
+		require.ErrorIs(t, err, sql.ErrConnDone)
+		assert.NotErrorIs(t, err, services.ErrQueryTimeout)
+		assert.Less(t, time.Since(started), time.Second)
+		assert.Error(t, mock.ExpectationsWereMet(), "the second query should not have run")
+	})
+}
@@ -0,0 +1,207 @@
+// Package services provides business logic and service layer functions for Brewsource MCP, including beer and brewery operations.
+package services
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Page sizes for BeerSearchQuery.Limit and BrewerySearchQuery.Limit.
+const (
+	// DefaultSearchLimit is the brewery page size used when no limit is given.
+	DefaultSearchLimit = 20
+	// MaxSearchLimit is the largest limit a search accepts.
+	MaxSearchLimit = 100
+)
+
+// ErrInvalidLimit is wrapped by errors for a search limit that is negative or above MaxSearchLimit.
+// Searches reject such limits rather than silently changing the page size.
+var ErrInvalidLimit = errors.New("invalid search limit")
+
+// validateLimit checks that limit is zero, meaning the default, or between 1 and MaxSearchLimit.
+func validateLimit(limit int) error {
+	if limit < 0 || limit > MaxSearchLimit {
+		return fmt.Errorf("%w: limit must be between 1 and %d (got %d)", ErrInvalidLimit, MaxSearchLimit, limit)
+	}
+	return nil
+}
+
+// validateIterLimit checks the limit of a search iterator, which may exceed MaxSearchLimit since its
+// results are not held in memory.
+func validateIterLimit(limit int) error {
+	if limit < 0 {
+		return fmt.Errorf("%w: limit cannot be negative (got %d)", ErrInvalidLimit, limit)
+	}
+	return nil
+}
+
+// Search result orderings accepted in BeerSearchQuery.Sort and BrewerySearchQuery.Sort.
+const (
+	// SortByName orders results alphabetically. It is the default.
+	SortByName = "name"
+	// SortByRelevance orders results by pg_trgm similarity between the name and the name filter,
+	// best match first. It needs relevance search enabled and a name filter; otherwise results are
+	// sorted by name.
+	SortByRelevance = "relevance"
+	// SortByDistance orders brewery results nearest first. It is applied, in place of the requested
+	// order, whenever a brewery search gives coordinates.
+	SortByDistance = "distance"
+
+	// SortByABV, SortByIBU, SortByStyle, and SortByBrewery order beer results by that column.
+	SortByABV     = "abv"
+	SortByIBU     = "ibu"
+	SortByStyle   = "style"
+	SortByBrewery = "brewery"
+	// SortByCity, SortByCountry, and SortByType order brewery results by that column.
+	SortByCity    = "city"
+	SortByCountry = "country"
+	SortByType    = "type"
+)
+
+// Sort directions accepted in BeerSearchQuery.Order and BrewerySearchQuery.Order. Relevance and
+// distance orderings are always best match or nearest first and ignore the direction.
+const (
+	OrderAsc  = "asc" // the default
+	OrderDesc = "desc"
+)
+
+// BeerSortKeys lists the values accepted in BeerSearchQuery.Sort.
+var BeerSortKeys = []string{SortByName, SortByRelevance, SortByABV, SortByIBU, SortByStyle, SortByBrewery}
+
+// BrewerySortKeys lists the values accepted in BrewerySearchQuery.Sort.
+var BrewerySortKeys = []string{SortByName, SortByRelevance, SortByCity, SortByCountry, SortByType}
+
+// SortOrders lists the values accepted in BeerSearchQuery.Order and BrewerySearchQuery.Order.
+var SortOrders = []string{OrderAsc, OrderDesc}
+
+// beerSortColumns and brewerySortColumns whitelist the columns a search can be ordered by, so that
+// no caller-supplied text reaches the ORDER BY clause.
+var (
+	beerSortColumns = map[string]string{
+		SortByName: "b.name", SortByABV: "b.abv", SortByIBU: "b.ibu", SortByStyle: "b.style", SortByBrewery: "br.name",
+	}
+	brewerySortColumns = map[string]string{
+		SortByName: "name", SortByCity: "city", SortByCountry: "country", SortByType: "brewery_type",
+	}
+)
+
+// validateSort checks that sort is empty or one of keys and that order is empty or one of
+// SortOrders, ignoring case.
+func validateSort(sort, order string, keys []string) error {
+	if sort = normalizeSortValue(sort); sort != "" && !slices.Contains(keys, sort) {
+		return fmt.Errorf(
+			"%w: unknown sort %q (must be one of %s)", ErrInvalidSearchQuery, sort, strings.Join(keys, ", "),
+		)
+	}
+	if order = normalizeSortValue(order); order != "" && !slices.Contains(SortOrders, order) {
+		return fmt.Errorf("%w: unknown order %q (must be asc or desc)", ErrInvalidSearchQuery, order)
+	}
+	return nil
+}
+
+func normalizeSortValue(value string) string {
+	return strings.ToLower(strings.TrimSpace(value))
+}
+
+// resolveSort returns the ordering and direction a validated search will use: name when no sort is
+// given or a relevance search cannot be ranked, and ascending unless the sort is by a column and
+// descending order was asked for.
+func resolveSort(relevanceEnabled bool, sort, order, name string) (string, string) {
+	sort, order = normalizeSortValue(sort), normalizeSortValue(order)
+	switch {
+	case sort == SortByRelevance && relevanceSort(relevanceEnabled, sort, name):
+		return SortByRelevance, OrderAsc
+	case sort == "" || sort == SortByRelevance:
+		sort = SortByName
+	}
+	if order != OrderDesc {
+		order = OrderAsc
+	}
+	return sort, order
+}
+
+// diacriticLetters lists lowercase letters with diacritics, and plainLetters the letter each one
+// folds to at the same position. Both feed TRANSLATE in SQL and foldDiacritics in Go, so that
+// columns and search terms fold the same way.
+const (
+	diacriticLetters = "àáâãäåāăąçćčďđèéêëēėęěìíîïīįłñńňòóôõöøōőŕřśšşťţùúûüūůűųýÿźżž"
+	plainLetters     = "aaaaaaaaacccddeeeeeeeeiiiiiilnnnoooooooorrsssttuuuuuuuuyyzzz"
+)
+
+var diacriticFolds = func() map[rune]rune {
+	folds := make(map[rune]rune)
+	plain := []rune(plainLetters)
+	for i, letter := range []rune(diacriticLetters) {
+		folds[letter] = plain[i]
+	}
+	return folds
+}()
+
+// normalizeSearchFields trims each field and collapses internal runs of whitespace to one space,
+// leaving blank fields empty so that they are treated as unset. With fold set it also lowercases
+// the fields and folds their diacritics.
+func normalizeSearchFields(fold bool, fields ...*string) {
+	for _, field := range fields {
+		*field = strings.Join(strings.Fields(*field), " ")
+		if fold {
+			*field = foldDiacritics(*field)
+		}
+	}
+}
+
+// normalizeSearchValues is normalizeSearchFields for the values of a multi-valued filter, leaving
+// out those that are blank.
+func normalizeSearchValues(fold bool, values []string) []string {
+	var normalized []string
+	for _, value := range values {
+		normalizeSearchFields(fold, &value)
+		if value != "" {
+			normalized = append(normalized, value)
+		}
+	}
+	return normalized
+}
+
+// lowerAll returns a lowercased copy of values.
+func lowerAll(values []string) []string {
+	if values == nil {
+		return nil
+	}
+	lowered := make([]string, len(values))
+	for i, value := range values {
+		lowered[i] = strings.ToLower(value)
+	}
+	return lowered
+}
+
+// foldDiacritics lowercases text and replaces each letter in diacriticLetters with its plain form.
+func foldDiacritics(text string) string {
+	return strings.Map(func(r rune) rune {
+		if plain, ok := diacriticFolds[r]; ok {
+			return plain
+		}
+		return r
+	}, strings.ToLower(text))
+}
+
+// foldedColumn returns the SQL for column lowercased with its diacritics folded, for matching
+// against terms folded with foldDiacritics.
+func foldedColumn(column string) string {
+	return "TRANSLATE(LOWER(" + column + "), '" + diacriticLetters + "', '" + plainLetters + "')"
+}
+
+// relevanceSort reports whether a search should be ranked by trigram similarity to name.
+func relevanceSort(enabled bool, sort, name string) bool {
+	return enabled && sort == SortByRelevance && name != ""
+}
+
+// orderBy returns an ORDER BY list sorting column in the given direction, followed by tieBreakers.
+// Descending sorts keep rows with a NULL column last, as ascending sorts do.
+func orderBy(column, order string, tieBreakers ...string) string {
+	if order == OrderDesc {
+		column += " DESC NULLS LAST"
+	}
+	return strings.Join(append([]string{column}, tieBreakers...), ", ")
+}
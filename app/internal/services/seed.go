@@ -0,0 +1,234 @@
+// Package services provides business logic and service layer functions for Brewsource MCP, including beer and brewery operations.
+package services
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Environment variables that point LoadSeedData at custom seed files in place of the embedded ones.
+const (
+	SeedBreweriesPathEnv = "SEED_BREWERIES_PATH"
+	SeedBeersPathEnv     = "SEED_BEERS_PATH"
+)
+
+const (
+	seedBreweriesFile = "seed_breweries.json"
+	seedBeersFile     = "seed_beers.json"
+)
+
+// ErrInvalidSeedData is returned, joined with every problem found, for seed data that fails
+// ValidateSeedData.
+var ErrInvalidSeedData = errors.New("invalid seed data")
+
+// seedFiles holds the bundled seed data, embedded so the binary does not depend on its working
+// directory.
+//
+//go:embed data/seed_breweries.json data/seed_beers.json
+var seedFiles embed.FS
+
+// SeedData is the sample breweries, venues, and beers the database is seeded with. Seed files hold a
+// JSON object with any of a "breweries", a "venues", and a "beers" array, using the field names of
+// Brewery, SeedVenue, and SeedBeer.
+type SeedData struct {
+	Breweries []Brewery   `json:"breweries"`
+	Venues    []SeedVenue `json:"venues"`
+	Beers     []SeedBeer  `json:"beers"`
+	// Source names the files the data was loaded from, or "bundled" for the embedded data; seeding
+	// records it as the seed version in data_sources.
+	Source string `json:"-"`
+	// Tags is the flavour tag vocabulary that seeding tags every beer with, loaded by LoadBeerTags;
+	// nil leaves the tags as they are.
+	Tags BeerTags `json:"-"`
+}
+
+// SeedVenue is a seed venue, naming its brewery, which is resolved to an ID when seeding.
+type SeedVenue struct {
+	Venue
+	BreweryName string `json:"brewery"`
+}
+
+// LoadSeedData loads and validates the seed data: the files named by SEED_BREWERIES_PATH and
+// SEED_BEERS_PATH if they are set, and the embedded data otherwise, with the tags of LoadBeerTags.
+func LoadSeedData() (*SeedData, error) {
+	seed := SeedData{Source: "bundled"}
+	var paths []string
+	for _, file := range []struct{ name, env string }{
+		{seedBreweriesFile, SeedBreweriesPathEnv},
+		{seedBeersFile, SeedBeersPathEnv},
+	} {
+		var raw []byte
+		var err error
+		name := file.name
+		if path := os.Getenv(file.env); path != "" {
+			name = path
+			paths = append(paths, path)
+			raw, err = os.ReadFile(filepath.Clean(path)) // #nosec G304 - the path is deployment configuration
+		} else {
+			raw, err = seedFiles.ReadFile("data/" + file.name)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read seed data file: %w", err)
+		}
+		var part SeedData
+		if err = json.Unmarshal(raw, &part); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", name, err)
+		}
+		seed.Breweries = append(seed.Breweries, part.Breweries...)
+		seed.Venues = append(seed.Venues, part.Venues...)
+		seed.Beers = append(seed.Beers, part.Beers...)
+	}
+	if len(paths) > 0 {
+		seed.Source = strings.Join(paths, ", ")
+	}
+	if err := ValidateSeedData(&seed); err != nil {
+		return nil, err
+	}
+	tags, err := LoadBeerTags()
+	if err != nil {
+		return nil, err
+	}
+	seed.Tags = tags
+	return &seed, nil
+}
+
+// LoadSeedFile loads and validates the seed data of one file holding both breweries and beers, with
+// the tags of LoadBeerTags.
+func LoadSeedFile(path string) (*SeedData, error) {
+	raw, err := os.ReadFile(filepath.Clean(path)) // #nosec G304 - the path is given by the operator
+	if err != nil {
+		return nil, fmt.Errorf("failed to read seed file: %w", err)
+	}
+	var seed SeedData
+	if err = json.Unmarshal(raw, &seed); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if err = ValidateSeedData(&seed); err != nil {
+		return nil, err
+	}
+	if seed.Tags, err = LoadBeerTags(); err != nil {
+		return nil, err
+	}
+	seed.Source = path
+	return &seed, nil
+}
+
+// ValidateSeedData checks each seed brewery and beer as CreateBrewery and CreateBeer would, and
+// also that beers have a style, that no brewery or beer is listed twice under its natural key, and
+// that each beer's brewery is among the seed breweries and its ingredients have a name and an
+// amount that is not negative. Venues must name a seed brewery, have a name, valid coordinates and
+// hours, and be their brewery's only primary venue. It reports every problem found, by row numbered from 1, joined with
+// ErrInvalidSeedData, or nil.
+func ValidateSeedData(seed *SeedData) error {
+	var problems []error
+	fail := func(kind string, row int, name string, err error) {
+		reason := strings.TrimPrefix(err.Error(), ErrInvalidRecord.Error()+": ")
+		if name == "" {
+			problems = append(problems, fmt.Errorf("%s %d: %s", kind, row, reason))
+			return
+		}
+		problems = append(problems, fmt.Errorf("%s %d (%s): %s", kind, row, name, reason))
+	}
+
+	breweries := map[string]bool{}
+	seen := map[string]bool{}
+	for i, brewery := range seed.Breweries {
+		key := strings.ToLower(brewery.Name) + "\x00" + strings.ToLower(brewery.City)
+		switch err := brewery.Validate(); {
+		case err != nil:
+			fail("brewery", i+1, brewery.Name, err)
+		case seen[key]:
+			fail("brewery", i+1, brewery.Name, fmt.Errorf("listed more than once in %s", brewery.City))
+		}
+		seen[key] = true
+		breweries[brewery.Name] = true
+	}
+
+	seen = map[string]bool{}
+	primary := map[string]bool{}
+	for i, venue := range seed.Venues {
+		key := venue.BreweryName + "\x00" + strings.ToLower(venue.Name)
+		switch err := venue.Validate(); {
+		case err != nil:
+			fail("venue", i+1, venue.Name, err)
+		case venue.BreweryName == "":
+			fail("venue", i+1, venue.Name, errors.New("brewery is required"))
+		case !breweries[venue.BreweryName]:
+			fail("venue", i+1, venue.Name,
+				fmt.Errorf("brewery %q is not among the seed breweries", venue.BreweryName))
+		case seen[key]:
+			fail("venue", i+1, venue.Name, fmt.Errorf("listed more than once for %s", venue.BreweryName))
+		case venue.IsPrimary && primary[venue.BreweryName]:
+			fail("venue", i+1, venue.Name, fmt.Errorf("%s already has a primary venue", venue.BreweryName))
+		}
+		seen[key] = true
+		primary[venue.BreweryName] = primary[venue.BreweryName] || venue.IsPrimary
+	}
+
+	seen = map[string]bool{}
+	for i, seedBeer := range seed.Beers {
+		key := seedBeer.BreweryName + "\x00" + strings.ToLower(seedBeer.Name)
+		beer := Beer{
+			BreweryID: 1, // resolved when seeding
+			Name:      seedBeer.Name,
+			Style:     seedBeer.Style,
+			ABV:       seedBeer.ABV,
+			IBU:       seedBeer.IBU,
+			SRM:       seedBeer.SRM,
+		}
+		switch err := beer.Validate(); {
+		case err != nil:
+			fail("beer", i+1, seedBeer.Name, err)
+		case seedBeer.Style == "":
+			fail("beer", i+1, seedBeer.Name, errors.New("style is required"))
+		case seedBeer.BreweryName == "":
+			fail("beer", i+1, seedBeer.Name, errors.New("brewery is required"))
+		case !breweries[seedBeer.BreweryName]:
+			fail("beer", i+1, seedBeer.Name,
+				fmt.Errorf("brewery %q is not among the seed breweries", seedBeer.BreweryName))
+		case seen[key]:
+			fail("beer", i+1, seedBeer.Name, fmt.Errorf("listed more than once for %s", seedBeer.BreweryName))
+		}
+		seen[key] = true
+		for _, problem := range ingredientProblems(seedBeer.Ingredients) {
+			fail("beer", i+1, seedBeer.Name, problem)
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %w", ErrInvalidSeedData, errors.Join(problems...))
+}
+
+// ingredientProblems lists the ingredients of a seed beer without a name or with a negative amount.
+func ingredientProblems(ingredients *BeerIngredients) []error {
+	if ingredients == nil {
+		return nil
+	}
+	var problems []error
+	for _, kind := range []struct {
+		name string
+		list []BeerIngredient
+	}{
+		{"hop", ingredients.Hops},
+		{"fermentable", ingredients.Fermentables},
+		{"yeast", ingredients.Yeast},
+	} {
+		for j, ingredient := range kind.list {
+			switch {
+			case ingredient.Name == "":
+				problems = append(problems, fmt.Errorf("%s %d has no name", kind.name, j+1))
+			case ingredient.Amount < 0:
+				problems = append(problems, fmt.Errorf("%s %s: amount cannot be negative (got %g)",
+					kind.name, ingredient.Name, ingredient.Amount))
+			}
+		}
+	}
+	return problems
+}
@@ -0,0 +1,116 @@
+package services_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadSeedData_Embedded(t *testing.T) {
+	t.Setenv(services.SeedBreweriesPathEnv, "")
+	t.Setenv(services.SeedBeersPathEnv, "")
+
+	seed, err := services.LoadSeedData()
+	require.NoError(t, err)
+	assert.Len(t, seed.Breweries, 26)
+	assert.Len(t, seed.Beers, 66)
+	assert.Len(t, seed.Venues, 5)
+	assert.Equal(t, "SAB - Newlands Brewery", seed.Breweries[0].Name)
+	assert.Equal(t, "SAB - Newlands Brewery", seed.Beers[0].BreweryName)
+}
+
+func TestLoadSeedData_Override(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "beers.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"beers": [
+		{"name": "Test Lager", "brewery": "SAB - Newlands Brewery", "style": "Pale Lager", "abv": 4.5, "ibu": 15}
+	]}`), 0o600))
+	t.Setenv(services.SeedBeersPathEnv, path)
+
+	seed, err := services.LoadSeedData()
+	require.NoError(t, err)
+	assert.Len(t, seed.Breweries, 26, "The embedded breweries should still load")
+	require.Len(t, seed.Beers, 1)
+	assert.Equal(t, "Test Lager", seed.Beers[0].Name)
+}
+
+func TestLoadSeedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "seed.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{
+		"breweries": [{"name": "Test Brewery", "brewery_type": "micro", "city": "Durban"}],
+		"beers": [
+			{"name": "Good Beer", "brewery": "Test Brewery", "style": "IPA", "abv": 6},
+			{"name": "Strong Beer", "brewery": "Test Brewery", "style": "IPA", "abv": 35},
+			{"name": "Lost Beer", "brewery": "Elsewhere", "style": "IPA", "abv": 5}
+		]
+	}`), 0o600))
+
+	_, err := services.LoadSeedFile(path)
+	require.ErrorIs(t, err, services.ErrInvalidSeedData)
+	assert.Contains(t, err.Error(), "beer 2 (Strong Beer): abv must be between 0 and 20 (got 35)")
+	assert.Contains(t, err.Error(), `beer 3 (Lost Beer): brewery "Elsewhere" is not among the seed breweries`)
+	assert.NotContains(t, err.Error(), "Good Beer")
+
+	_, err = services.LoadSeedFile(filepath.Join(t.TempDir(), "missing.json"))
+	assert.ErrorContains(t, err, "failed to read seed file")
+}
+
+func TestValidateSeedData(t *testing.T) {
+	seed := &services.SeedData{
+		Breweries: []services.Brewery{
+			{Name: "Test Brewery", BreweryType: "micro", City: "Durban"},
+			{Name: "test brewery", BreweryType: "micro", City: "DURBAN"},
+			{BreweryType: "micro"},
+			{Name: "Odd Brewery", BreweryType: "castle"},
+		},
+		Venues: []services.SeedVenue{
+			{BreweryName: "Test Brewery", Venue: services.Venue{Name: "Taproom", IsPrimary: true}},
+			{BreweryName: "Test Brewery", Venue: services.Venue{Name: "Beer Hall", IsPrimary: true}},
+			{BreweryName: "Lost Brewery", Venue: services.Venue{Name: "Lost Bar"}},
+			{BreweryName: "Test Brewery", Venue: services.Venue{
+				Name: "Late Bar", Hours: services.VenueHours{"fri": "18:00-25:00"},
+			}},
+			{BreweryName: "Test Brewery", Venue: services.Venue{
+				Name: "Odd Days", Hours: services.VenueHours{"friday": "closed"},
+			}},
+		},
+		Beers: []services.SeedBeer{
+			{Name: "Pale", BreweryName: "Test Brewery", Style: "Pale Ale", ABV: 5},
+			{Name: "PALE", BreweryName: "Test Brewery", Style: "Pale Ale", ABV: 5},
+			{Name: "No Style", BreweryName: "Test Brewery", ABV: 5},
+			{Name: "Bitter", BreweryName: "Test Brewery", Style: "IPA", IBU: 250},
+			{Name: "No Brewery", Style: "IPA"},
+			{Name: "Odd Bill", BreweryName: "Test Brewery", Style: "IPA", Ingredients: &services.BeerIngredients{
+				Hops:         []services.BeerIngredient{{Amount: 20, Unit: "g"}},
+				Fermentables: []services.BeerIngredient{{Name: "Pale Ale Malt", Amount: -1, Unit: "kg"}},
+			}},
+		},
+	}
+
+	err := services.ValidateSeedData(seed)
+	require.ErrorIs(t, err, services.ErrInvalidSeedData)
+	for _, want := range []string{
+		"brewery 2 (test brewery): listed more than once in DURBAN",
+		"brewery 3: brewery name is required",
+		`brewery 4 (Odd Brewery): brewery type "castle" must be one of`,
+		"venue 2 (Beer Hall): Test Brewery already has a primary venue",
+		`venue 3 (Lost Bar): brewery "Lost Brewery" is not among the seed breweries`,
+		`venue 4 (Late Bar): hours for fri: "25:00" is not a time from 00:00 to 24:00`,
+		`venue 5 (Odd Days): unknown day "friday" in hours`,
+		"beer 2 (PALE): listed more than once for Test Brewery",
+		"beer 3 (No Style): style is required",
+		"beer 4 (Bitter): ibu must be between 0 and 200 (got 250)",
+		"beer 5 (No Brewery): brewery is required",
+		"beer 6 (Odd Bill): hop 1 has no name",
+		"beer 6 (Odd Bill): fermentable Pale Ale Malt: amount cannot be negative (got -1)",
+	} {
+		assert.Contains(t, err.Error(), want)
+	}
+	assert.NotContains(t, err.Error(), "beer 1 ")
+	assert.NotContains(t, err.Error(), "venue 1 ")
+
+	assert.NoError(t, services.ValidateSeedData(&services.SeedData{}))
+}
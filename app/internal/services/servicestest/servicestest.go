@@ -0,0 +1,594 @@
+// Package servicestest provides in-memory fakes of the services interfaces for handler tests.
+package servicestest
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/services"
+)
+
+var (
+	_ services.BeerServiceInterface    = (*BeerService)(nil)
+	_ services.BreweryServiceInterface = (*BreweryService)(nil)
+)
+
+// BeerService is a fake services.BeerServiceInterface. Searches ignore their filters and return
+// Results, paged by the query's Offset and Limit. When Err is set, every method returns it instead.
+type BeerService struct {
+	// Results are returned by the searches and, up to the requested count, by RandomBeers.
+	Results []*services.BeerSearchResult
+	// Beers are found by GetBeerByID and listed by ListBeerStats. CreateBeer rejects a beer with the
+	// name of one of them at the same brewery.
+	Beers []*services.BeerDetail
+	// Ingredients are returned by GetBeerIngredients, keyed by beer ID; other beers have none.
+	Ingredients map[int]*services.BeerIngredients
+	// BreweryIDs are the breweries CreateBeer accepts; any brewery is accepted when it is empty.
+	BreweryIDs []int
+	// CreatedID is the ID CreateBeer returns.
+	CreatedID int
+	// Err, when set, is returned by every method.
+	Err error
+
+	// Queries records the search queries, in order.
+	Queries []services.BeerSearchQuery
+	// RandomFilters records the style and country of each RandomBeers call.
+	RandomFilters [][2]string
+	// CandidateStyles records the styles of each RecommendationCandidates call.
+	CandidateStyles [][]string
+	// Created records the beers CreateBeer accepted.
+	Created []services.Beer
+}
+
+// SearchBeers returns the page of Results selected by query.Offset and query.Limit.
+func (s *BeerService) SearchBeers(
+	_ context.Context,
+	query services.BeerSearchQuery,
+) ([]*services.BeerSearchResult, error) {
+	s.Queries = append(s.Queries, query)
+	if s.Err != nil {
+		return nil, s.Err
+	}
+	return page(s.Results, query.Offset, query.Limit), nil
+}
+
+// SearchBeersPage returns the page of Results selected by query.Offset and query.Limit, counting all
+// of Results as matches.
+func (s *BeerService) SearchBeersPage(
+	ctx context.Context,
+	query services.BeerSearchQuery,
+) (*services.BeerSearchPage, error) {
+	items, err := s.SearchBeers(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return &services.BeerSearchPage{
+		Items:      items,
+		Offset:     query.Offset,
+		TotalCount: len(s.Results),
+		HasMore:    query.Offset+len(items) < len(s.Results),
+		Sort:       query.Sort,
+		Order:      query.Order,
+	}, nil
+}
+
+// SearchBeersIter yields the page of Results selected by query.Offset and query.Limit.
+func (s *BeerService) SearchBeersIter(
+	ctx context.Context,
+	query services.BeerSearchQuery,
+) (iter.Seq2[*services.BeerSearchResult, error], error) {
+	items, err := s.SearchBeers(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return seq(items), nil
+}
+
+// GetBeerByID returns the beer in Beers with the given ID.
+func (s *BeerService) GetBeerByID(_ context.Context, id int) (*services.BeerDetail, error) {
+	if s.Err != nil {
+		return nil, s.Err
+	}
+	for _, beer := range s.Beers {
+		if beer.ID == id {
+			return beer, nil
+		}
+	}
+	return nil, &services.NotFoundError{Kind: "beer", ID: id}
+}
+
+// GetBeerIngredients returns the Ingredients of the beer with the given ID.
+func (s *BeerService) GetBeerIngredients(_ context.Context, beerID int) (*services.BeerIngredients, error) {
+	if s.Err != nil {
+		return nil, s.Err
+	}
+	if ingredients, ok := s.Ingredients[beerID]; ok {
+		return ingredients, nil
+	}
+	return &services.BeerIngredients{
+		Hops:         []services.BeerIngredient{},
+		Fermentables: []services.BeerIngredient{},
+		Yeast:        []services.BeerIngredient{},
+	}, nil
+}
+
+// ListBeerStats returns the stats of the Beers that declare a style.
+func (s *BeerService) ListBeerStats(_ context.Context) ([]*services.BeerStats, error) {
+	if s.Err != nil {
+		return nil, s.Err
+	}
+	stats := []*services.BeerStats{}
+	for _, beer := range s.Beers {
+		if beer.Style == "" {
+			continue
+		}
+		stats = append(stats, &services.BeerStats{
+			ID:      beer.ID,
+			Name:    beer.Name,
+			Brewery: beer.Brewery,
+			Style:   beer.Style,
+			ABV:     beer.ABV,
+			IBU:     beer.IBU,
+			SRM:     beer.SRM,
+		})
+	}
+	return stats, nil
+}
+
+// RandomBeers returns the first count of Results, whatever the style and country.
+func (s *BeerService) RandomBeers(
+	_ context.Context,
+	style, country string,
+	count int,
+) ([]*services.BeerSearchResult, error) {
+	s.RandomFilters = append(s.RandomFilters, [2]string{style, country})
+	if s.Err != nil {
+		return nil, s.Err
+	}
+	return page(s.Results, 0, count), nil
+}
+
+// SuggestNames returns the distinct names of Results starting with prefix, as the service does.
+func (s *BeerService) SuggestNames(_ context.Context, prefix string, limit int) ([]string, error) {
+	if s.Err != nil {
+		return nil, s.Err
+	}
+	names := make([]string, len(s.Results))
+	for i, result := range s.Results {
+		names[i] = result.Name
+	}
+	return suggest(names, prefix, limit), nil
+}
+
+// CreateBeer validates the beer, checks its brewery against BreweryIDs and its name against Beers,
+// and returns CreatedID.
+func (s *BeerService) CreateBeer(_ context.Context, beer services.Beer) (int, error) {
+	if err := beer.Validate(); err != nil {
+		return 0, err
+	}
+	if s.Err != nil {
+		return 0, s.Err
+	}
+	if len(s.BreweryIDs) > 0 && !slices.Contains(s.BreweryIDs, beer.BreweryID) {
+		return 0, &services.NotFoundError{Kind: "brewery", ID: beer.BreweryID}
+	}
+	for _, existing := range s.Beers {
+		if existing.BreweryID == beer.BreweryID && strings.EqualFold(existing.Name, beer.Name) {
+			return 0, fmt.Errorf(
+				"%w: brewery %d already has a beer named %q", services.ErrDuplicate, beer.BreweryID, beer.Name,
+			)
+		}
+	}
+	s.Created = append(s.Created, beer)
+	return s.CreatedID, nil
+}
+
+// RecommendationCandidates returns the Beers, other than the one with excludeID, whose style
+// contains one of styles, ignoring case, as the service matches them.
+func (s *BeerService) RecommendationCandidates(
+	_ context.Context,
+	styles []string,
+	excludeID int,
+) ([]*services.BeerCandidate, error) {
+	s.CandidateStyles = append(s.CandidateStyles, styles)
+	if s.Err != nil {
+		return nil, s.Err
+	}
+	candidates := []*services.BeerCandidate{}
+	for _, beer := range s.Beers {
+		if beer.ID == excludeID || !slices.ContainsFunc(styles, func(style string) bool {
+			return strings.Contains(strings.ToLower(beer.Style), strings.ToLower(style))
+		}) {
+			continue
+		}
+		candidates = append(candidates, &services.BeerCandidate{
+			ID:      beer.ID,
+			Name:    beer.Name,
+			Style:   beer.Style,
+			Brewery: beer.Brewery,
+			Country: beer.Country,
+			ABV:     beer.ABV,
+			IBU:     beer.IBU,
+			SRM:     beer.SRM,
+		})
+	}
+	return candidates, nil
+}
+
+// BreweryService is a fake services.BreweryServiceInterface. Searches ignore their filters and
+// return Results, paged by the query's Offset and Limit. When Err is set, every method returns it
+// instead.
+type BreweryService struct {
+	// Results are returned by the searches.
+	Results []*services.BrewerySearchResult
+	// Breweries are found by GetBreweryByID and ResolveBreweryID. CreateBrewery rejects a brewery
+	// with the name of one of them.
+	Breweries []*services.BreweryDetail
+	// Beers lists each brewery's beers, by brewery ID, for GetBreweryBeers.
+	Beers map[int][]*services.BreweryBeer
+	// Venues lists each brewery's venues, by brewery ID, for GetBreweryVenues.
+	Venues map[int][]*services.Venue
+	// NearbyVenues are returned by SearchVenuesNear, up to the query's Limit.
+	NearbyVenues []*services.VenueSearchResult
+	// Stats is returned by GetStats, with the requested scope.
+	Stats services.Stats
+	// DataSources are returned by ListDataSources.
+	DataSources []*services.DataSource
+	// CreatedID is the ID CreateBrewery returns.
+	CreatedID int
+	// Deleted holds the IDs of the soft-deleted breweries, which the searches and lookups leave out
+	// unless the query includes them. SoftDeleteBrewery and RestoreBrewery update it.
+	Deleted map[int]bool
+	// Err, when set, is returned by every method.
+	Err error
+
+	// Queries records the search queries, in order.
+	Queries []services.BrewerySearchQuery
+	// VenueQueries records the queries of SearchVenuesNear, in order.
+	VenueQueries []services.VenueSearchQuery
+	// Created records the breweries CreateBrewery accepted.
+	Created []services.Brewery
+}
+
+// SearchBreweries returns the page of Results selected by query.Offset and query.Limit.
+func (s *BreweryService) SearchBreweries(
+	_ context.Context,
+	query services.BrewerySearchQuery,
+) ([]*services.BrewerySearchResult, error) {
+	s.Queries = append(s.Queries, query)
+	if s.Err != nil {
+		return nil, s.Err
+	}
+	return page(s.results(query), query.Offset, query.Limit), nil
+}
+
+// results returns the Results a query can see.
+func (s *BreweryService) results(query services.BrewerySearchQuery) []*services.BrewerySearchResult {
+	if query.IncludeDeleted || len(s.Deleted) == 0 {
+		return s.Results
+	}
+	results := []*services.BrewerySearchResult{}
+	for _, result := range s.Results {
+		if !s.Deleted[result.ID] {
+			results = append(results, result)
+		}
+	}
+	return results
+}
+
+// SearchBreweriesPage returns the page of Results selected by query.Offset and query.Limit, counting
+// all of the Results the query can see as matches.
+func (s *BreweryService) SearchBreweriesPage(
+	ctx context.Context,
+	query services.BrewerySearchQuery,
+) (*services.BrewerySearchPage, error) {
+	items, err := s.SearchBreweries(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	total := len(s.results(query))
+	return &services.BrewerySearchPage{
+		Items:      items,
+		Offset:     query.Offset,
+		TotalCount: total,
+		HasMore:    query.Offset+len(items) < total,
+		Sort:       query.Sort,
+		Order:      query.Order,
+	}, nil
+}
+
+// SearchBreweriesIter yields the page of Results selected by query.Offset and query.Limit.
+func (s *BreweryService) SearchBreweriesIter(
+	ctx context.Context,
+	query services.BrewerySearchQuery,
+) (iter.Seq2[*services.BrewerySearchResult, error], error) {
+	items, err := s.SearchBreweries(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return seq(items), nil
+}
+
+// GetBreweryByID returns the brewery in Breweries with the given ID.
+func (s *BreweryService) GetBreweryByID(_ context.Context, id int) (*services.BreweryDetail, error) {
+	if s.Err != nil {
+		return nil, s.Err
+	}
+	for _, brewery := range s.Breweries {
+		if brewery.ID == id && !s.Deleted[id] {
+			return brewery, nil
+		}
+	}
+	return nil, &services.NotFoundError{Kind: "brewery", ID: id}
+}
+
+// GetBreweryBeers returns up to limit of the brewery's Beers, in order, for a brewery in Breweries.
+func (s *BreweryService) GetBreweryBeers(
+	ctx context.Context,
+	breweryID, limit int,
+) (*services.BreweryBeers, error) {
+	brewery, err := s.GetBreweryByID(ctx, breweryID)
+	if err != nil {
+		return nil, err
+	}
+	beers := s.Beers[breweryID]
+	if limit <= 0 || limit > services.MaxBreweryBeersLimit {
+		limit = services.DefaultBreweryBeersLimit
+	}
+	return &services.BreweryBeers{
+		BreweryID:  breweryID,
+		Brewery:    brewery.Name,
+		Beers:      page(beers, 0, limit),
+		TotalCount: len(beers),
+	}, nil
+}
+
+// ResolveBreweryID finds the brewery in Breweries with the given name, ignoring case, or else the
+// single brewery whose name contains it.
+func (s *BreweryService) ResolveBreweryID(_ context.Context, name string) (int, error) {
+	if s.Err != nil {
+		return 0, s.Err
+	}
+	var matches []int
+	for _, brewery := range s.Breweries {
+		if s.Deleted[brewery.ID] {
+			continue
+		}
+		if strings.EqualFold(brewery.Name, name) {
+			return brewery.ID, nil
+		}
+		if strings.Contains(strings.ToLower(brewery.Name), strings.ToLower(name)) {
+			matches = append(matches, brewery.ID)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return 0, &services.NotFoundError{Kind: "brewery", Name: name}
+	case 1:
+		return matches[0], nil
+	default:
+		return 0, fmt.Errorf("%w: %q; use the brewery ID instead", services.ErrAmbiguousBreweryName, name)
+	}
+}
+
+// SuggestNames returns the distinct names of the Results that are not deleted and start with
+// prefix, as the service does.
+func (s *BreweryService) SuggestNames(_ context.Context, prefix string, limit int) ([]string, error) {
+	if s.Err != nil {
+		return nil, s.Err
+	}
+	var names []string
+	for _, result := range s.results(services.BrewerySearchQuery{}) {
+		names = append(names, result.Name)
+	}
+	return suggest(names, prefix, limit), nil
+}
+
+// ListCountries counts the Results that are not deleted by country, as the service does.
+func (s *BreweryService) ListCountries(_ context.Context) ([]*services.CountryCount, error) {
+	if s.Err != nil {
+		return nil, s.Err
+	}
+	counts := []*services.CountryCount{}
+	for _, result := range s.results(services.BrewerySearchQuery{}) {
+		country := strings.TrimSpace(result.Country)
+		if country == "" {
+			continue
+		}
+		i := slices.IndexFunc(counts, func(count *services.CountryCount) bool {
+			return strings.EqualFold(count.Country, country)
+		})
+		if i < 0 {
+			counts = append(counts, &services.CountryCount{Country: country})
+			i = len(counts) - 1
+		}
+		counts[i].Breweries++
+	}
+	slices.SortFunc(counts, func(a, b *services.CountryCount) int {
+		return strings.Compare(strings.ToLower(a.Country), strings.ToLower(b.Country))
+	})
+	return counts, nil
+}
+
+// ListRegions counts the Results in country that are not deleted by state, as the service does.
+func (s *BreweryService) ListRegions(_ context.Context, country string) (*services.CountryRegions, error) {
+	if s.Err != nil {
+		return nil, s.Err
+	}
+	country = strings.TrimSpace(country)
+	var regions *services.CountryRegions
+	for _, result := range s.results(services.BrewerySearchQuery{}) {
+		if country == "" || !strings.EqualFold(strings.TrimSpace(result.Country), country) {
+			continue
+		}
+		if regions == nil {
+			regions = &services.CountryRegions{
+				Country: strings.TrimSpace(result.Country),
+				Regions: []*services.RegionCount{},
+			}
+		}
+		regions.Breweries++
+		state := strings.TrimSpace(result.State)
+		if state == "" {
+			regions.Unspecified++
+			continue
+		}
+		i := slices.IndexFunc(regions.Regions, func(count *services.RegionCount) bool {
+			return strings.EqualFold(count.Region, state)
+		})
+		if i < 0 {
+			regions.Regions = append(regions.Regions, &services.RegionCount{Region: state})
+			i = len(regions.Regions) - 1
+		}
+		regions.Regions[i].Breweries++
+	}
+	if regions == nil {
+		return nil, &services.NotFoundError{Kind: "country", Name: country}
+	}
+	slices.SortFunc(regions.Regions, func(a, b *services.RegionCount) int {
+		return strings.Compare(strings.ToLower(a.Region), strings.ToLower(b.Region))
+	})
+	return regions, nil
+}
+
+// ListDataSources returns DataSources.
+func (s *BreweryService) ListDataSources(_ context.Context) ([]*services.DataSource, error) {
+	if s.Err != nil {
+		return nil, s.Err
+	}
+	if s.DataSources == nil {
+		return []*services.DataSource{}, nil
+	}
+	return s.DataSources, nil
+}
+
+// GetStats returns a copy of Stats with the given scope.
+func (s *BreweryService) GetStats(_ context.Context, scope services.StatsScope) (*services.Stats, error) {
+	if s.Err != nil {
+		return nil, s.Err
+	}
+	stats := s.Stats
+	stats.Scope = scope
+	return &stats, nil
+}
+
+// CreateBrewery validates the brewery, checks its name against Breweries, and returns CreatedID.
+func (s *BreweryService) CreateBrewery(_ context.Context, brewery services.Brewery) (int, error) {
+	if err := brewery.Validate(); err != nil {
+		return 0, err
+	}
+	if s.Err != nil {
+		return 0, s.Err
+	}
+	for _, existing := range s.Breweries {
+		if strings.EqualFold(existing.Name, brewery.Name) {
+			return 0, fmt.Errorf("%w: a brewery named %q already exists", services.ErrDuplicate, brewery.Name)
+		}
+	}
+	s.Created = append(s.Created, brewery)
+	return s.CreatedID, nil
+}
+
+// SoftDeleteBrewery marks a brewery in Breweries as Deleted.
+func (s *BreweryService) SoftDeleteBrewery(_ context.Context, id int) error {
+	return s.setDeleted(id, true)
+}
+
+// RestoreBrewery clears the Deleted mark of a brewery in Breweries.
+func (s *BreweryService) RestoreBrewery(_ context.Context, id int) error {
+	return s.setDeleted(id, false)
+}
+
+// setDeleted marks or unmarks a brewery, failing as the real service does when it is not in
+// Breweries or already in the requested state.
+func (s *BreweryService) setDeleted(id int, deleted bool) error {
+	if s.Err != nil {
+		return s.Err
+	}
+	known := slices.ContainsFunc(s.Breweries, func(brewery *services.BreweryDetail) bool {
+		return brewery.ID == id
+	})
+	if !known || s.Deleted[id] == deleted {
+		return &services.NotFoundError{Kind: "brewery", ID: id}
+	}
+	if s.Deleted == nil {
+		s.Deleted = map[int]bool{}
+	}
+	if deleted {
+		s.Deleted[id] = true
+	} else {
+		delete(s.Deleted, id)
+	}
+	return nil
+}
+
+// GetBreweryVenues returns the brewery's Venues, in order, leaving out those of Deleted breweries.
+func (s *BreweryService) GetBreweryVenues(_ context.Context, breweryID int) ([]*services.Venue, error) {
+	if s.Err != nil {
+		return nil, s.Err
+	}
+	if s.Deleted[breweryID] || s.Venues[breweryID] == nil {
+		return []*services.Venue{}, nil
+	}
+	return s.Venues[breweryID], nil
+}
+
+// SearchVenuesNear validates query and returns up to query.Limit of NearbyVenues, ignoring the
+// point searched.
+func (s *BreweryService) SearchVenuesNear(
+	_ context.Context,
+	query services.VenueSearchQuery,
+) ([]*services.VenueSearchResult, error) {
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+	s.VenueQueries = append(s.VenueQueries, query)
+	if s.Err != nil {
+		return nil, s.Err
+	}
+	return page(s.NearbyVenues, 0, query.Limit), nil
+}
+
+// page returns the items from offset, at most limit of them; a limit of zero or less is no limit.
+func page[T any](items []T, offset, limit int) []T {
+	if offset < 0 || offset >= len(items) {
+		return []T{}
+	}
+	items = items[offset:]
+	if limit > 0 && limit < len(items) {
+		items = items[:limit]
+	}
+	return items
+}
+
+// seq yields each item with a nil error.
+func seq[T any](items []T) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for _, item := range items {
+			if !yield(item, nil) {
+				return
+			}
+		}
+	}
+}
+
+// suggest returns the distinct names starting with prefix, ignoring case and leading whitespace,
+// sorted, up to services.SuggestLimit(limit) of them; none for a blank prefix.
+func suggest(names []string, prefix string, limit int) []string {
+	prefix = strings.ToLower(strings.TrimLeftFunc(prefix, unicode.IsSpace))
+	suggestions := []string{}
+	if strings.TrimSpace(prefix) == "" {
+		return suggestions
+	}
+	for _, name := range names {
+		if strings.HasPrefix(strings.ToLower(name), prefix) && !slices.Contains(suggestions, name) {
+			suggestions = append(suggestions, name)
+		}
+	}
+	slices.Sort(suggestions)
+	return page(suggestions, 0, services.SuggestLimit(limit))
+}
@@ -0,0 +1,182 @@
+// Package services provides business logic and service layer functions for Brewsource MCP, including beer and brewery operations.
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// StatsCacheTTL is how long aggregate statistics stay cached. It is short because the counts
+	// change whenever breweries or beers are added.
+	StatsCacheTTL = time.Minute
+	// statsCachePrefix namespaces aggregate statistics in Redis.
+	statsCachePrefix = "brewsource:stats:"
+)
+
+// StatsScope narrows aggregate statistics. Both fields are matched as substrings, ignoring case, as
+// in the search filters; empty fields leave the statistics unscoped.
+type StatsScope struct {
+	Country string `json:"country,omitempty"` // brewery country; beers are scoped by their brewery's country
+	Style   string `json:"style,omitempty"`   // beer style; breweries are scoped to those making a beer of the style
+}
+
+// CountryCount is the number of breweries in one country.
+type CountryCount struct {
+	Country   string `json:"country"`
+	Breweries int    `json:"breweries"`
+}
+
+// StyleStats is the number of beers of one style and their average strength and bitterness.
+type StyleStats struct {
+	Style  string  `json:"style"`
+	Beers  int     `json:"beers"`
+	AvgABV float64 `json:"avg_abv"`
+	AvgIBU float64 `json:"avg_ibu"`
+}
+
+// Stats is an overview of the brewery and beer data. Countries and styles are ordered by count,
+// largest first, then by name.
+type Stats struct {
+	Scope              StatsScope      `json:"scope"`
+	TotalBreweries     int             `json:"total_breweries"`
+	BreweriesByCountry []*CountryCount `json:"breweries_by_country"`
+	TotalBeers         int             `json:"total_beers"`
+	BeersByStyle       []*StyleStats   `json:"beers_by_style"`
+}
+
+// GetStats returns brewery and beer counts, per country and per style, within scope. Results are
+// cached in Redis for StatsCacheTTL when caching is enabled. The queries run under ctx, so a caller's
+// deadline bounds them.
+func (s *BreweryService) GetStats(ctx context.Context, scope StatsScope) (*Stats, error) {
+	normalizeSearchFields(false, &scope.Country, &scope.Style)
+	key := s.statsCache.key(
+		"overview",
+		StatsScope{Country: strings.ToLower(scope.Country), Style: strings.ToLower(scope.Style)},
+	)
+	var cached Stats
+	if s.statsCache.get(ctx, key, &cached) {
+		return &cached, nil
+	}
+
+	stats := &Stats{Scope: scope}
+	var err error
+	if stats.BreweriesByCountry, err = s.breweriesByCountry(ctx, scope); err != nil {
+		return nil, err
+	}
+	if stats.BeersByStyle, err = s.beersByStyle(ctx, scope); err != nil {
+		return nil, err
+	}
+	for _, country := range stats.BreweriesByCountry {
+		stats.TotalBreweries += country.Breweries
+	}
+	for _, style := range stats.BeersByStyle {
+		stats.TotalBeers += style.Beers
+	}
+	s.statsCache.set(ctx, key, stats)
+	return stats, nil
+}
+
+// statsFilters builds the WHERE conditions scoping statistics, for queries that alias breweries as
+// br, numbering arguments from $1. beerAlias is the alias of a beers table joined to br, or empty
+// when the style scope must be checked with a subquery. Soft-deleted breweries and beers are not
+// counted.
+func statsFilters(scope StatsScope, beerAlias string) (string, []interface{}) {
+	filters := " AND br.deleted_at IS NULL"
+	if beerAlias != "" {
+		filters += " AND " + beerAlias + ".deleted_at IS NULL"
+	}
+	args := []interface{}{}
+	if scope.Country != "" {
+		args = append(args, "%"+scope.Country+"%")
+		filters += " AND LOWER(br.country) LIKE LOWER($" + strconv.Itoa(len(args)) + ")"
+	}
+	if scope.Style != "" {
+		args = append(args, "%"+scope.Style+"%")
+		if beerAlias != "" {
+			filters += " AND " + beerAlias + ".style ILIKE $" + strconv.Itoa(len(args))
+		} else {
+			filters += " AND EXISTS (SELECT 1 FROM beers b WHERE b.brewery_id = br.id AND b.deleted_at IS NULL" +
+				" AND b.style ILIKE $" + strconv.Itoa(len(args)) + ")"
+		}
+	}
+	return filters, args
+}
+
+func (s *BreweryService) breweriesByCountry(ctx context.Context, scope StatsScope) (_ []*CountryCount, err error) {
+	filters, args := statsFilters(scope, "")
+	query := `
+		SELECT COALESCE(br.country, '') AS country, COUNT(*) AS breweries
+		FROM breweries br
+		WHERE 1=1` + filters + `
+		GROUP BY 1
+		ORDER BY 2 DESC, 1`
+
+	ctx, finish := s.queries.begin(ctx, "breweries_by_country", query, scope)
+	defer finish(&err)
+
+	var rows *sql.Rows
+	err = s.queries.retry(ctx, "breweries_by_country", func() (err error) {
+		rows, err = s.db.QueryContext(ctx, query, args...)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to count breweries by country: %w", err)
+	}
+	defer rows.Close()
+
+	counts := []*CountryCount{}
+	for rows.Next() {
+		var count CountryCount
+		if err = rows.Scan(&count.Country, &count.Breweries); err != nil {
+			return nil, fmt.Errorf("failed to count breweries by country: %w", err)
+		}
+		counts = append(counts, &count)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to count breweries by country: %w", err)
+	}
+	return counts, nil
+}
+
+func (s *BreweryService) beersByStyle(ctx context.Context, scope StatsScope) (_ []*StyleStats, err error) {
+	filters, args := statsFilters(scope, "b")
+	query := `
+		SELECT COALESCE(b.style, '') AS style, COUNT(*) AS beers,
+		       COALESCE(AVG(b.abv), 0) AS avg_abv, COALESCE(AVG(b.ibu), 0) AS avg_ibu
+		FROM beers b
+		JOIN breweries br ON b.brewery_id = br.id
+		WHERE 1=1` + filters + `
+		GROUP BY 1
+		ORDER BY 2 DESC, 1`
+
+	ctx, finish := s.queries.begin(ctx, "beers_by_style", query, scope)
+	defer finish(&err)
+
+	var rows *sql.Rows
+	err = s.queries.retry(ctx, "beers_by_style", func() (err error) {
+		rows, err = s.db.QueryContext(ctx, query, args...)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to count beers by style: %w", err)
+	}
+	defer rows.Close()
+
+	styles := []*StyleStats{}
+	for rows.Next() {
+		var style StyleStats
+		if err = rows.Scan(&style.Style, &style.Beers, &style.AvgABV, &style.AvgIBU); err != nil {
+			return nil, fmt.Errorf("failed to count beers by style: %w", err)
+		}
+		styles = append(styles, &style)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to count beers by style: %w", err)
+	}
+	return styles, nil
+}
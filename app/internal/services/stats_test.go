@@ -0,0 +1,108 @@
+// Package services_test contains tests for the business logic and service layer functions in Brewsource MCP.
+package services_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/services"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func expectStatsQueries(mock sqlmock.Sqlmock, countryFilter, styleFilter string, args ...driver.Value) {
+	countryQuery := `SELECT COALESCE\(br\.country, ''\) AS country, COUNT\(\*\) AS breweries\s+FROM breweries br\s+` +
+		`WHERE 1=1 AND br\.deleted_at IS NULL` + countryFilter + `\s+GROUP BY 1\s+ORDER BY 2 DESC, 1$`
+	mock.ExpectQuery(countryQuery).
+		WithArgs(args...).
+		WillReturnRows(sqlmock.NewRows([]string{"country", "breweries"}).
+			AddRow("South Africa", 20).
+			AddRow("United States", 6))
+	styleQuery := `SELECT COALESCE\(b\.style, ''\) AS style, COUNT\(\*\) AS beers,.*FROM beers b\s+` +
+		`JOIN breweries br ON b\.brewery_id = br\.id\s+WHERE 1=1 AND br\.deleted_at IS NULL` +
+		` AND b\.deleted_at IS NULL` + styleFilter + `\s+GROUP BY 1\s+ORDER BY 2 DESC, 1$`
+	mock.ExpectQuery(styleQuery).
+		WithArgs(args...).
+		WillReturnRows(sqlmock.NewRows([]string{"style", "beers", "avg_abv", "avg_ibu"}).
+			AddRow("American IPA", 4, 6.5, 62.5).
+			AddRow("Pale Lager", 3, 4.8, 15.0))
+}
+
+func TestGetStats(t *testing.T) {
+	t.Run("Totals the per-country and per-style counts", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		service := setupBreweryService(db)
+		expectStatsQueries(mock, "", "")
+
+		stats, err := service.GetStats(context.Background(), services.StatsScope{})
+
+		require.NoError(t, err)
+		assert.Equal(t, 26, stats.TotalBreweries)
+		assert.Equal(t, 7, stats.TotalBeers)
+		assert.Equal(t, &services.CountryCount{Country: "South Africa", Breweries: 20}, stats.BreweriesByCountry[0])
+		assert.Equal(
+			t,
+			&services.StyleStats{Style: "American IPA", Beers: 4, AvgABV: 6.5, AvgIBU: 62.5},
+			stats.BeersByStyle[0],
+		)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Scopes by country and style", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		service := setupBreweryService(db)
+		expectStatsQueries(
+			mock,
+			` AND LOWER\(br\.country\) LIKE LOWER\(\$1\) AND EXISTS \(SELECT 1 FROM beers b `+
+				`WHERE b\.brewery_id = br\.id AND b\.deleted_at IS NULL AND b\.style ILIKE \$2\)`,
+			` AND LOWER\(br\.country\) LIKE LOWER\(\$1\) AND b\.style ILIKE \$2`,
+			"%South Africa%", "%IPA%",
+		)
+
+		stats, err := service.GetStats(
+			context.Background(),
+			services.StatsScope{Country: " South  Africa ", Style: "IPA"},
+		)
+
+		require.NoError(t, err)
+		assert.Equal(t, services.StatsScope{Country: "South Africa", Style: "IPA"}, stats.Scope)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Respects the caller's deadline", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		service := setupBreweryService(db)
+		mock.ExpectQuery(`FROM breweries br`).
+			WillDelayFor(time.Second).
+			WillReturnRows(sqlmock.NewRows([]string{"country", "breweries"}))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, err := service.GetStats(ctx, services.StatsScope{})
+
+		require.Error(t, err, "the query should be cancelled when the deadline passes")
+		assert.Contains(t, err.Error(), "failed to count breweries by country")
+	})
+
+	t.Run("Caches results briefly", func(t *testing.T) {
+		mr, mock, _, service := setupCachedServices(t)
+		expectStatsQueries(mock, "", "")
+
+		first, err := service.GetStats(context.Background(), services.StatsScope{})
+		require.NoError(t, err)
+		second, err := service.GetStats(context.Background(), services.StatsScope{})
+		require.NoError(t, err)
+
+		assert.Equal(t, first, second)
+		assert.NoError(t, mock.ExpectationsWereMet(), "the second call should not reach the database")
+		keys := mr.Keys()
+		require.Len(t, keys, 1)
+		assert.Equal(t, services.StatsCacheTTL, mr.TTL(keys[0]))
+	})
+}
@@ -0,0 +1,87 @@
+// Package services provides business logic and service layer functions for Brewsource MCP, including beer and brewery operations.
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Numbers of names SuggestNames returns.
+const (
+	// DefaultSuggestLimit is the number of names suggested when no limit is given.
+	DefaultSuggestLimit = 10
+	// MaxSuggestLimit caps the names suggested, keeping type-ahead responses small.
+	MaxSuggestLimit = 20
+)
+
+// likeEscaper escapes the LIKE wildcards in a name prefix, for patterns with ESCAPE '\'.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// SuggestLimit returns the number of names SuggestNames returns when asked for limit:
+// DefaultSuggestLimit when it is not positive, and at most MaxSuggestLimit.
+func SuggestLimit(limit int) int {
+	if limit <= 0 {
+		return DefaultSuggestLimit
+	}
+	return min(limit, MaxSuggestLimit)
+}
+
+// SuggestNames returns the distinct names of breweries starting with prefix, ignoring case and
+// leading whitespace, in alphabetical order, leaving out soft-deleted breweries. It returns at most
+// SuggestLimit(limit) names, and none for a blank prefix. On PostgreSQL the search uses the name
+// prefix index.
+func (s *BreweryService) SuggestNames(ctx context.Context, prefix string, limit int) ([]string, error) {
+	const q = `
+		SELECT DISTINCT name
+		FROM breweries
+		WHERE LOWER(name) LIKE $1 || '%' ESCAPE '\' AND deleted_at IS NULL
+		ORDER BY name
+		LIMIT $2`
+	return suggestNames(ctx, s.db, s.queries, "suggest_breweries", q, prefix, limit)
+}
+
+// SuggestNames returns the distinct names of beers starting with prefix, as
+// BreweryService.SuggestNames does for breweries, leaving out soft-deleted beers and the beers of
+// soft-deleted breweries.
+func (s *BeerService) SuggestNames(ctx context.Context, prefix string, limit int) ([]string, error) {
+	const q = `
+		SELECT DISTINCT b.name
+		FROM beers b
+		JOIN breweries br ON b.brewery_id = br.id
+		WHERE LOWER(b.name) LIKE $1 || '%' ESCAPE '\'` + liveBeers + `
+		ORDER BY b.name
+		LIMIT $2`
+	return suggestNames(ctx, s.db, s.queries, "suggest_beers", q, prefix, limit)
+}
+
+// suggestNames runs a name suggestion query q, named name, which takes the lowercased, escaped
+// prefix as $1 and the limit as $2.
+func suggestNames(
+	ctx context.Context,
+	db *sqlDB,
+	queries *queryGuard,
+	name, q, prefix string,
+	limit int,
+) (_ []string, err error) {
+	prefix = strings.TrimLeftFunc(prefix, unicode.IsSpace)
+	if strings.TrimSpace(prefix) == "" {
+		return []string{}, nil
+	}
+	pattern := likeEscaper.Replace(strings.ToLower(prefix))
+	limit = SuggestLimit(limit)
+
+	ctx, finish := queries.begin(ctx, name, q, prefix)
+	defer finish(&err)
+
+	var names []string
+	err = queries.retry(ctx, name, func() error {
+		names = []string{}
+		return db.SelectContext(ctx, &names, q, pattern, limit)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to suggest names for %q: %w", prefix, err)
+	}
+	return names, nil
+}
@@ -0,0 +1,81 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/services"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSuggestNames(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Brewery names match the lowercased prefix", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		query := `SELECT DISTINCT name\s+FROM breweries\s+` +
+			`WHERE LOWER\(name\) LIKE \$1 \|\| '%' ESCAPE '\\' AND deleted_at IS NULL\s+ORDER BY name\s+LIMIT \$2$`
+		mock.ExpectQuery(query).
+			WithArgs("devil's", services.DefaultSuggestLimit).
+			WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("Devil's Peak Brewing Company"))
+
+		names, err := services.NewBreweryService(db, nil).SuggestNames(ctx, "  Devil's", 0)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Devil's Peak Brewing Company"}, names)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Beer names leave out deleted beers and escape wildcards", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		query := `SELECT DISTINCT b\.name\s+FROM beers b\s+JOIN breweries br ON b\.brewery_id = br\.id\s+` +
+			`WHERE LOWER\(b\.name\) LIKE \$1 \|\| '%' ESCAPE '\\' AND b\.deleted_at IS NULL AND br\.deleted_at IS NULL` +
+			`\s+ORDER BY b\.name\s+LIMIT \$2$`
+		mock.ExpectQuery(query).
+			WithArgs(`100\% pale\_ale`, services.MaxSuggestLimit).
+			WillReturnRows(sqlmock.NewRows([]string{"name"}))
+
+		names, err := setupBeerService(db).SuggestNames(ctx, "100% Pale_Ale", 500)
+
+		require.NoError(t, err)
+		assert.Empty(t, names)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("A blank prefix suggests nothing without a query", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		names, err := setupBeerService(db).SuggestNames(ctx, " \t", 5)
+
+		require.NoError(t, err)
+		assert.NotNil(t, names)
+		assert.Empty(t, names)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Query errors are returned", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		mock.ExpectQuery(`SELECT DISTINCT name`).WillReturnError(errors.New("syntax error"))
+
+		_, err := services.NewBreweryService(db, nil).SuggestNames(ctx, "dev", 5)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `failed to suggest names for "dev": syntax error`)
+	})
+}
+
+func TestSuggestLimit(t *testing.T) {
+	assert.Equal(t, services.DefaultSuggestLimit, services.SuggestLimit(0))
+	assert.Equal(t, services.DefaultSuggestLimit, services.SuggestLimit(-3))
+	assert.Equal(t, 5, services.SuggestLimit(5))
+	assert.Equal(t, services.MaxSuggestLimit, services.SuggestLimit(services.MaxSuggestLimit+1))
+}
@@ -0,0 +1,457 @@
+// Package services provides business logic and service layer functions for Brewsource MCP, including beer and brewery operations.
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// OpenBreweryDBURL is the Open Brewery DB endpoint that lists breweries page by page.
+	OpenBreweryDBURL = "https://api.openbrewerydb.org/v1/breweries"
+	// OpenBreweryDBSource names Open Brewery DB in the sync_state and data_sources tables.
+	OpenBreweryDBSource = "openbrewerydb"
+	// DefaultSyncPageSize is the number of breweries requested per page, the most the API allows.
+	DefaultSyncPageSize = 200
+	// DefaultSyncRequestInterval is the least time between two requests to the API.
+	DefaultSyncRequestInterval = 500 * time.Millisecond
+
+	// maxSyncRetries is the number of times a rate-limited or unavailable page is retried.
+	maxSyncRetries = 5
+	// maxSyncRetryDelay caps the wait before a retry, including waits asked for with Retry-After.
+	maxSyncRetryDelay = time.Minute
+	// syncHTTPTimeout bounds each request made with the default HTTP client.
+	syncHTTPTimeout = 30 * time.Second
+)
+
+// BrewerySyncerInterface runs brewery syncs and reports on the last one.
+type BrewerySyncerInterface interface {
+	Sync(ctx context.Context) (*SyncResult, error)
+	LastSync(ctx context.Context) (*SyncState, error)
+}
+
+// SyncResult counts the outcome of a sync. Skipped breweries are those that fail Brewery.Validate,
+// such as ones with a brewery type outside BreweryTypes, or that duplicate another brewery's name
+// and city.
+type SyncResult struct {
+	Pages    int `json:"pages"`
+	Inserted int `json:"inserted"`
+	Updated  int `json:"updated"`
+	Skipped  int `json:"skipped"`
+}
+
+// String returns the counts, e.g. "3 pages: 410 inserted, 12 updated, 9 skipped".
+func (r *SyncResult) String() string {
+	return fmt.Sprintf("%d pages: %d inserted, %d updated, %d skipped", r.Pages, r.Inserted, r.Updated, r.Skipped)
+}
+
+// SyncState is the sync_state row of one data source. LastSuccessAt is nil until a sync succeeds,
+// Records counts the breweries written by the last successful sync, and LastError is empty when the
+// last attempt succeeded.
+type SyncState struct {
+	Source        string     `json:"source"          db:"source"`
+	LastAttemptAt time.Time  `json:"last_attempt_at" db:"last_attempt_at"`
+	LastSuccessAt *time.Time `json:"last_success_at" db:"last_success_at"`
+	Records       int        `json:"records"         db:"records_synced"`
+	LastError     string     `json:"last_error"      db:"last_error"`
+}
+
+// BrewerySyncer copies breweries from Open Brewery DB into the breweries table. Breweries are
+// matched by their Open Brewery DB ID, stored in external_id; a brewery without one that has the
+// same name and city, such as a seeded brewery, is adopted instead of duplicated.
+type BrewerySyncer struct {
+	db        *sqlDB
+	client    *http.Client
+	baseURL   string
+	pageSize  int
+	interval  time.Duration
+	caches    []*searchCache // dropped after a sync writes; nil-safe
+	running   sync.Mutex     // held for the duration of a sync
+	lastRanAt time.Time      // when the last request was sent, for rate limiting
+}
+
+// NewBrewerySyncer creates a BrewerySyncer that requests pages with client, or with a client
+// timing out after 30 seconds when client is nil. The brewery, beer, and statistics caches in Redis
+// are dropped after each sync when redisClient is not nil.
+func NewBrewerySyncer(db *sqlx.DB, redisClient *redis.Client, client *http.Client) *BrewerySyncer {
+	if client == nil {
+		client = &http.Client{Timeout: syncHTTPTimeout}
+	}
+	return &BrewerySyncer{
+		db:       newSQLDB(db),
+		client:   client,
+		baseURL:  OpenBreweryDBURL,
+		pageSize: DefaultSyncPageSize,
+		interval: DefaultSyncRequestInterval,
+		caches: []*searchCache{
+			newSearchCache(redisClient, breweryCachePrefix),
+			newSearchCache(redisClient, statsCachePrefix),
+			newSearchCache(redisClient, beerCachePrefix),
+		},
+	}
+}
+
+// SetBaseURL changes the endpoint breweries are listed from, for mirrors of the API.
+func (s *BrewerySyncer) SetBaseURL(baseURL string) {
+	s.baseURL = baseURL
+}
+
+// SetPageSize changes the number of breweries requested per page. Non-positive values are ignored.
+func (s *BrewerySyncer) SetPageSize(size int) {
+	if size > 0 {
+		s.pageSize = size
+	}
+}
+
+// SetRequestInterval changes the least time between two requests. Retries without a Retry-After
+// header back off from this interval, doubling each time. Negative values are ignored.
+func (s *BrewerySyncer) SetRequestInterval(interval time.Duration) {
+	if interval >= 0 {
+		s.interval = interval
+	}
+}
+
+// Sync copies every page of breweries from Open Brewery DB, writing each page in one transaction,
+// and records the outcome in sync_state. Pages written before a failure are kept, and each page
+// written is reported with ReportProgress. It returns ErrSyncInProgress while another sync is
+// running.
+func (s *BrewerySyncer) Sync(ctx context.Context) (*SyncResult, error) {
+	if !s.running.TryLock() {
+		return nil, ErrSyncInProgress
+	}
+	defer s.running.Unlock()
+	return s.sync(ctx)
+}
+
+// LastSync returns the sync_state row for Open Brewery DB, or nil when no sync has been attempted.
+func (s *BrewerySyncer) LastSync(ctx context.Context) (*SyncState, error) {
+	var state SyncState
+	err := s.db.GetContext(ctx, &state, `
+		SELECT source, last_attempt_at, last_success_at, records_synced, last_error
+		FROM sync_state
+		WHERE source = $1`, OpenBreweryDBSource)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil //nolint:nilnil // no sync has been attempted
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get last brewery sync: %w", err)
+	}
+	return &state, nil
+}
+
+func (s *BrewerySyncer) sync(ctx context.Context) (*SyncResult, error) {
+	started := time.Now().UTC()
+	result := &SyncResult{}
+	err := s.syncPages(ctx, result)
+	if result.Inserted+result.Updated > 0 {
+		invalidateCaches(ctx, s.caches...)
+	}
+	if recordErr := s.recordSync(ctx, started, result, err); recordErr != nil {
+		logrus.Warnf("Failed to record brewery sync: %v", recordErr)
+	}
+	if err != nil {
+		return result, fmt.Errorf("brewery sync stopped after %s: %w", result, err)
+	}
+	return result, nil
+}
+
+func (s *BrewerySyncer) syncPages(ctx context.Context, result *SyncResult) error {
+	for page := 1; ; page++ {
+		breweries, err := s.fetchPage(ctx, page)
+		if err != nil {
+			return err
+		}
+		if len(breweries) > 0 {
+			if err = s.writePage(ctx, breweries, result); err != nil {
+				return err
+			}
+			result.Pages++
+			ReportProgress(ctx, float64(result.Pages), 0, result.String())
+		}
+		if len(breweries) < s.pageSize {
+			return nil
+		}
+	}
+}
+
+// fetchPage requests one page of breweries, retrying when the API is rate limiting or unavailable.
+func (s *BrewerySyncer) fetchPage(ctx context.Context, page int) ([]openBrewery, error) {
+	pageURL, err := url.Parse(s.baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid brewery sync URL %q: %w", s.baseURL, err)
+	}
+	query := pageURL.Query()
+	query.Set("page", strconv.Itoa(page))
+	query.Set("per_page", strconv.Itoa(s.pageSize))
+	pageURL.RawQuery = query.Encode()
+
+	for attempt := 0; ; attempt++ {
+		if err = sleepContext(ctx, time.Until(s.lastRanAt.Add(s.interval))); err != nil {
+			return nil, err
+		}
+		s.lastRanAt = time.Now()
+		breweries, retryAfter, fetchErr := s.getPage(ctx, pageURL.String())
+		if fetchErr == nil || retryAfter < 0 {
+			return breweries, fetchErr
+		}
+		if attempt == maxSyncRetries {
+			return nil, fmt.Errorf("%w (gave up after %d retries)", fetchErr, maxSyncRetries)
+		}
+		if retryAfter == 0 {
+			retryAfter = s.interval << attempt
+		}
+		logrus.Warnf("Retrying brewery sync page %d in %s: %v", page, min(retryAfter, maxSyncRetryDelay), fetchErr)
+		if err = sleepContext(ctx, min(retryAfter, maxSyncRetryDelay)); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// getPage sends one request. A failed request that can be retried returns a non-negative delay:
+// the Retry-After delay when the response has one, and zero otherwise.
+func (s *BrewerySyncer) getPage(ctx context.Context, pageURL string) ([]openBrewery, time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL, nil)
+	if err != nil {
+		return nil, -1, fmt.Errorf("failed to build brewery sync request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "brewsource-mcp")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, -1, fmt.Errorf("failed to fetch %s: %w", pageURL, err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable:
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil, retryAfterDelay(resp.Header.Get("Retry-After")), fmt.Errorf("%s returned %s", pageURL, resp.Status)
+	case resp.StatusCode != http.StatusOK:
+		return nil, -1, fmt.Errorf("%s returned %s", pageURL, resp.Status)
+	}
+	var breweries []openBrewery
+	if err = json.NewDecoder(resp.Body).Decode(&breweries); err != nil {
+		return nil, -1, fmt.Errorf("failed to decode %s: %w", pageURL, err)
+	}
+	return breweries, 0, nil
+}
+
+// retryAfterDelay reads a Retry-After header given in seconds, returning zero when it is absent or
+// given as a date.
+func retryAfterDelay(header string) time.Duration {
+	seconds, err := strconv.Atoi(strings.TrimSpace(header))
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// writePage upserts one page of breweries in a transaction.
+func (s *BrewerySyncer) writePage(ctx context.Context, breweries []openBrewery, result *SyncResult) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start brewery sync transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	inserted, updated, skipped := 0, 0, 0
+	for _, source := range breweries {
+		brewery := source.brewery()
+		brewery.Normalize()
+		if err = brewery.Validate(); err != nil || source.ID == "" {
+			logrus.Debugf("Skipping Open Brewery DB brewery %q: %v", source.ID, err)
+			skipped++
+			continue
+		}
+		outcome, writeErr := upsertSyncedBrewery(ctx, tx, source.ID, brewery)
+		if writeErr != nil {
+			return writeErr
+		}
+		switch outcome {
+		case syncInserted:
+			inserted++
+		case syncUpdated:
+			updated++
+		default:
+			skipped++
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit synced breweries: %w", err)
+	}
+	result.Inserted += inserted
+	result.Updated += updated
+	result.Skipped += skipped
+	return nil
+}
+
+type syncOutcome int
+
+const (
+	syncSkipped syncOutcome = iota
+	syncInserted
+	syncUpdated
+)
+
+// upsertSyncedBrewery updates the brewery with the external ID, else adopts a brewery with the same
+// name and city and no external ID, else inserts one. An insert that would duplicate another
+// brewery's name and city is skipped, which also keeps the transaction usable.
+func upsertSyncedBrewery(ctx context.Context, tx *sqlx.Tx, externalID string, b Brewery) (syncOutcome, error) {
+	args := append(breweryWriteArgs(b), externalID)
+	set := `name = $1, brewery_type = $2, street = $3, city = $4, state = $5, postal_code = $6,
+		country = $7, phone = $8, website_url = $9, latitude = $10, longitude = $11,
+		updated_at = CURRENT_TIMESTAMP`
+	sqlite := tx.DriverName() == sqliteDriver
+
+	for _, query := range []string{
+		`UPDATE breweries SET ` + set + ` WHERE external_id = $12`,
+		`UPDATE breweries SET ` + set + `, external_id = $12
+		WHERE external_id IS NULL AND LOWER(name) = LOWER($1) AND LOWER(COALESCE(city, '')) = LOWER($4)`,
+	} {
+		result, err := tx.ExecContext(ctx, rebind(sqlite, query), args...)
+		if err != nil {
+			return syncSkipped, fmt.Errorf("failed to update synced brewery %q: %w", b.Name, err)
+		}
+		if affected, affectedErr := result.RowsAffected(); affectedErr != nil || affected > 0 {
+			return syncUpdated, affectedErr
+		}
+	}
+
+	result, err := tx.ExecContext(ctx, rebind(sqlite, `
+		INSERT INTO breweries (
+			name, brewery_type, street, city, state, postal_code, country, phone, website_url,
+			latitude, longitude, external_id
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		ON CONFLICT DO NOTHING`), args...)
+	if err != nil {
+		return syncSkipped, fmt.Errorf("failed to insert synced brewery %q: %w", b.Name, err)
+	}
+	if affected, affectedErr := result.RowsAffected(); affectedErr != nil || affected == 0 {
+		return syncSkipped, affectedErr
+	}
+	return syncInserted, nil
+}
+
+// recordSync upserts the sync_state row, and after a successful sync the Open Brewery DB
+// data_sources row. A failed sync keeps the last success time and count.
+func (s *BrewerySyncer) recordSync(ctx context.Context, started time.Time, result *SyncResult, syncErr error) error {
+	var (
+		succeededAt *time.Time
+		lastError   string
+	)
+	if syncErr == nil {
+		succeededAt = &started
+	} else {
+		lastError = syncErr.Error()
+	}
+	_, err := s.db.ExecContext(context.WithoutCancel(ctx), `
+		INSERT INTO sync_state (source, last_attempt_at, last_success_at, records_synced, last_error)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (source) DO UPDATE SET
+			last_attempt_at = EXCLUDED.last_attempt_at,
+			last_success_at = COALESCE(EXCLUDED.last_success_at, sync_state.last_success_at),
+			records_synced = CASE
+				WHEN EXCLUDED.last_success_at IS NULL THEN sync_state.records_synced
+				ELSE EXCLUDED.records_synced
+			END,
+			last_error = EXCLUDED.last_error`,
+		OpenBreweryDBSource, started, succeededAt, result.Inserted+result.Updated, lastError,
+	)
+	if err != nil || syncErr != nil {
+		return err
+	}
+	return RecordDataSource(context.WithoutCancel(ctx), s.db, DataSource{
+		Source:    OpenBreweryDBSource,
+		UpdatedAt: started,
+		Records:   result.Inserted + result.Updated + result.Skipped,
+		Version:   s.baseURL,
+	})
+}
+
+// openBrewery is a brewery as listed by Open Brewery DB. Missing and null fields decode as empty.
+type openBrewery struct {
+	ID            string           `json:"id"`
+	Name          string           `json:"name"`
+	BreweryType   string           `json:"brewery_type"`
+	Address1      string           `json:"address_1"`
+	Street        string           `json:"street"`
+	City          string           `json:"city"`
+	StateProvince string           `json:"state_province"`
+	State         string           `json:"state"`
+	PostalCode    string           `json:"postal_code"`
+	Country       string           `json:"country"`
+	Phone         string           `json:"phone"`
+	WebsiteURL    string           `json:"website_url"`
+	Latitude      openBreweryCoord `json:"latitude"`
+	Longitude     openBreweryCoord `json:"longitude"`
+}
+
+// brewery maps the listing to our schema. Coordinates are kept only when both are given.
+func (o openBrewery) brewery() Brewery {
+	brewery := Brewery{
+		Name:        o.Name,
+		BreweryType: o.BreweryType,
+		Street:      firstNonEmpty(o.Address1, o.Street),
+		City:        o.City,
+		State:       firstNonEmpty(o.StateProvince, o.State),
+		PostalCode:  o.PostalCode,
+		Country:     o.Country,
+		Phone:       o.Phone,
+		WebsiteURL:  o.WebsiteURL,
+	}
+	if o.Latitude.value != nil && o.Longitude.value != nil {
+		brewery.Latitude, brewery.Longitude = o.Latitude.value, o.Longitude.value
+	}
+	return brewery
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if strings.TrimSpace(value) != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// openBreweryCoord decodes a coordinate the API gives as a number, a numeric string, or null.
+type openBreweryCoord struct {
+	value *float64
+}
+
+// UnmarshalJSON leaves the coordinate unset for null, empty, or non-numeric values.
+func (c *openBreweryCoord) UnmarshalJSON(data []byte) error {
+	text := strings.Trim(strings.TrimSpace(string(data)), `"`)
+	if value, err := strconv.ParseFloat(text, 64); err == nil {
+		c.value = &value
+	}
+	return nil
+}
@@ -0,0 +1,193 @@
+package services_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/services"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// syncPages are canned Open Brewery DB pages of two breweries each.
+var syncPages = map[string]string{
+	"1": `[
+		{"id": "obdb-1", "name": "Mountain Sun", "brewery_type": "brewpub", "address_1": "1535 Pearl St",
+		 "city": "Boulder", "state_province": "Colorado", "postal_code": "80302", "country": "United States",
+		 "phone": "3035460886", "website_url": "http://www.mountainsunpub.com",
+		 "latitude": "40.0192", "longitude": -105.2735},
+		{"id": "obdb-2", "name": "Devil's Peak Brewing", "brewery_type": "micro", "city": "Cape Town",
+		 "country": "South Africa", "latitude": null, "longitude": null}
+	]`,
+	"2": `[{"id": "obdb-3", "name": "Corner Taproom", "brewery_type": "taproom", "city": "Denver"}]`,
+}
+
+// newSyncer returns a syncer reading two breweries per page from a server running handler, without
+// waiting between requests.
+func newSyncer(t *testing.T, handler http.HandlerFunc) (*services.BrewerySyncer, sqlmock.Sqlmock) {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	db, mock := setupMockDB(t)
+	t.Cleanup(func() { _ = db.Close() })
+
+	syncer := services.NewBrewerySyncer(db, nil, server.Client())
+	syncer.SetBaseURL(server.URL + "/v1/breweries")
+	syncer.SetPageSize(2)
+	syncer.SetRequestInterval(time.Millisecond)
+	return syncer, mock
+}
+
+func TestBrewerySyncer_Sync(t *testing.T) {
+	var pageTwoRequests atomic.Int32
+	syncer, mock := newSyncer(t, func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		assert.Equal(t, "2", r.URL.Query().Get("per_page"))
+		// The first request for page 2 is rate limited
+		if page == "2" && pageTwoRequests.Add(1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		fmt.Fprint(w, syncPages[page])
+	})
+
+	mock.ExpectBegin()
+	// Mountain Sun is new: neither matched by external ID nor adopted by name and city
+	mock.ExpectExec(`UPDATE breweries SET .* WHERE external_id = \$12`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`UPDATE breweries SET .*, external_id = \$12\s+WHERE external_id IS NULL`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`INSERT INTO breweries .* ON CONFLICT DO NOTHING`).
+		WithArgs(
+			"Mountain Sun", "brewpub", "1535 Pearl St", "Boulder", "Colorado", "80302", "United States",
+			"3035460886", "http://www.mountainsunpub.com", 40.0192, -105.2735, "obdb-1",
+		).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	// Devil's Peak was seeded without an external ID and is adopted
+	mock.ExpectExec(`UPDATE breweries SET .* WHERE external_id = \$12`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`UPDATE breweries SET .*, external_id = \$12\s+WHERE external_id IS NULL`).
+		WithArgs(
+			"Devil's Peak Brewing", "micro", "", "Cape Town", "", "", "South Africa", "", "", nil, nil, "obdb-2",
+		).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	// The taproom type is not one of ours, so page 2 writes nothing
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+	mock.ExpectExec(`INSERT INTO sync_state .* ON CONFLICT \(source\) DO UPDATE`).
+		WithArgs(services.OpenBreweryDBSource, sqlmock.AnyArg(), sqlmock.AnyArg(), 2, "").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	// The 3 breweries fetched are recorded as the source's last update
+	mock.ExpectExec(`INSERT INTO data_sources .* ON CONFLICT \(source\) DO UPDATE`).
+		WithArgs(services.OpenBreweryDBSource, sqlmock.AnyArg(), 3, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	result, err := syncer.Sync(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, &services.SyncResult{Pages: 2, Inserted: 1, Updated: 1, Skipped: 1}, result)
+	assert.Equal(t, int32(2), pageTwoRequests.Load())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestBrewerySyncer_SyncFailures(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		wantErr string
+	}{
+		{"server error", http.StatusInternalServerError, "500 Internal Server Error"},
+		{"rate limited on every retry", http.StatusTooManyRequests, "gave up after 5 retries"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			syncer, mock := newSyncer(t, func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(tt.status)
+			})
+			mock.ExpectExec(`INSERT INTO sync_state`).
+				WithArgs(services.OpenBreweryDBSource, sqlmock.AnyArg(), nil, 0, sqlmock.AnyArg()).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			_, err := syncer.Sync(context.Background())
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestBrewerySyncer_SyncAsJob(t *testing.T) {
+	release := make(chan struct{})
+	syncer, mock := newSyncer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "2" {
+			<-release
+		}
+		fmt.Fprint(w, syncPages[r.URL.Query().Get("page")])
+	})
+	mock.ExpectBegin()
+	for range 2 {
+		mock.ExpectExec(`UPDATE breweries SET .* WHERE external_id = \$12`).WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+	mock.ExpectExec(`INSERT INTO sync_state`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO data_sources`).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	runner := services.NewJobRunner(0, 0)
+	ctx, cancel := context.WithCancel(context.Background())
+	job, err := runner.Enqueue(ctx, "sync_breweries", func(ctx context.Context) (string, error) {
+		result, syncErr := syncer.Sync(ctx)
+		if syncErr != nil {
+			return "", syncErr
+		}
+		return result.String(), nil
+	}, nil)
+	require.NoError(t, err)
+	// Cancelling the enqueuing request does not stop the job
+	cancel()
+
+	// The first page is reported while the sync waits on the second, and blocks another sync
+	assert.Eventually(t, func() bool {
+		current, _ := runner.Job(job.ID)
+		return current.State == services.JobRunning && current.Progress == 1
+	}, time.Second, 5*time.Millisecond)
+	_, err = syncer.Sync(context.Background())
+	assert.ErrorIs(t, err, services.ErrSyncInProgress)
+
+	close(release)
+	assert.Eventually(t, func() bool {
+		current, _ := runner.Job(job.ID)
+		return current.State == services.JobDone
+	}, time.Second, 5*time.Millisecond)
+	done, _ := runner.Job(job.ID)
+	assert.Equal(t, "2 pages: 0 inserted, 2 updated, 1 skipped", done.Result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestBrewerySyncer_LastSync(t *testing.T) {
+	syncer, mock := newSyncer(t, func(http.ResponseWriter, *http.Request) {})
+	ctx := context.Background()
+
+	mock.ExpectQuery(`SELECT source, last_attempt_at, last_success_at, records_synced, last_error\s+FROM sync_state`).
+		WithArgs(services.OpenBreweryDBSource).
+		WillReturnRows(sqlmock.NewRows([]string{"source"}))
+	state, err := syncer.LastSync(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, state)
+
+	attempted := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	mock.ExpectQuery(`FROM sync_state`).
+		WillReturnRows(sqlmock.NewRows(
+			[]string{"source", "last_attempt_at", "last_success_at", "records_synced", "last_error"},
+		).AddRow(services.OpenBreweryDBSource, attempted, attempted, 8123, ""))
+	state, err = syncer.LastSync(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 8123, state.Records)
+	assert.Equal(t, attempted, *state.LastSuccessAt)
+}
@@ -0,0 +1,322 @@
+// Package services provides business logic and service layer functions for Brewsource MCP, including beer and brewery operations.
+package services
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+// Where usage events are written, see UsageLog.
+const (
+	// UsageSinkOff records no usage.
+	UsageSinkOff = "off"
+	// UsageSinkDatabase writes usage events to the usage_events table.
+	UsageSinkDatabase = "database"
+	// UsageSinkRedis appends usage events to the UsageStream Redis stream, for deployments where
+	// database writes on every request are undesirable.
+	UsageSinkRedis = "redis"
+)
+
+// UsageSinks are the accepted usage sinks.
+var UsageSinks = []string{UsageSinkOff, UsageSinkDatabase, UsageSinkRedis}
+
+const (
+	// DefaultUsageBufferSize is the number of usage events held for the writer before new ones are
+	// dropped, unless changed with USAGE_BUFFER_SIZE.
+	DefaultUsageBufferSize = 1000
+	// DefaultUsageFlushInterval is the longest a usage event waits to be written, unless changed
+	// with USAGE_FLUSH_INTERVAL.
+	DefaultUsageFlushInterval = 5 * time.Second
+	// DefaultUsageSummaryDays is the number of days usage summaries cover unless asked otherwise.
+	DefaultUsageSummaryDays = 7
+	// UsageStream is the Redis stream usage events are appended to by the Redis sink.
+	UsageStream = "brewsource:usage"
+
+	// usageBatchSize is the most usage events written at once.
+	usageBatchSize = 100
+	// usageWriteTimeout bounds writing one batch, so that a stalled sink cannot hold the writer.
+	usageWriteTimeout = 5 * time.Second
+	// usageStreamMaxLen caps the usage stream, trimming its oldest entries.
+	usageStreamMaxLen = 100000
+)
+
+// UsageEvent is one tool call or resource read, written to the usage_events table or stream.
+type UsageEvent struct {
+	OccurredAt time.Time
+	Kind       string // "tool" or "resource"
+	Name       string // the tool name or resource URI
+	DurationMS int64
+	ErrorCode  int // the JSON-RPC error code, or 0 on success
+	Client     string
+}
+
+// UsageCount is the number of calls of one tool or resource on one day, in UTC.
+type UsageCount struct {
+	Day           string  `json:"day"             db:"day"`
+	Kind          string  `json:"kind"            db:"kind"`
+	Name          string  `json:"name"            db:"name"`
+	Calls         int     `json:"calls"           db:"calls"`
+	Errors        int     `json:"errors"          db:"errors"`
+	AvgDurationMS float64 `json:"avg_duration_ms" db:"avg_duration_ms"`
+}
+
+// UsageSink stores usage events and summarizes them.
+type UsageSink interface {
+	WriteUsage(ctx context.Context, events []UsageEvent) error
+	// UsageSummary counts the events since since by day, kind, and name, the latest day first and
+	// the most called first within a day.
+	UsageSummary(ctx context.Context, since time.Time) ([]*UsageCount, error)
+}
+
+// UsageLog buffers usage events and writes them to a sink in batches from a background worker,
+// see Run. Record never blocks: when the buffer is full, the event is dropped and counted.
+type UsageLog struct {
+	sink          UsageSink
+	events        chan UsageEvent
+	flushInterval time.Duration
+
+	dropped atomic.Uint64
+	failed  atomic.Uint64
+}
+
+// NewUsageLog creates a UsageLog holding up to bufferSize events for sink, or
+// DefaultUsageBufferSize when it is not positive, and writing them at least every flushInterval,
+// or DefaultUsageFlushInterval when it is not positive.
+func NewUsageLog(sink UsageSink, bufferSize int, flushInterval time.Duration) *UsageLog {
+	if bufferSize <= 0 {
+		bufferSize = DefaultUsageBufferSize
+	}
+	if flushInterval <= 0 {
+		flushInterval = DefaultUsageFlushInterval
+	}
+	return &UsageLog{sink: sink, events: make(chan UsageEvent, bufferSize), flushInterval: flushInterval}
+}
+
+// Record queues event for writing, or drops it when the buffer is full, reporting which.
+func (l *UsageLog) Record(event UsageEvent) bool {
+	select {
+	case l.events <- event:
+		return true
+	default:
+		l.dropped.Add(1)
+		return false
+	}
+}
+
+// Dropped returns the number of events dropped because the buffer was full.
+func (l *UsageLog) Dropped() uint64 {
+	return l.dropped.Load()
+}
+
+// Failed returns the number of events lost because the sink failed to write them.
+func (l *UsageLog) Failed() uint64 {
+	return l.failed.Load()
+}
+
+// Summary counts the usage of the last days days, including today, or of DefaultUsageSummaryDays
+// when days is not positive.
+func (l *UsageLog) Summary(ctx context.Context, days int) ([]*UsageCount, error) {
+	if days <= 0 {
+		days = DefaultUsageSummaryDays
+	}
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	return l.sink.UsageSummary(ctx, today.AddDate(0, 0, 1-days))
+}
+
+// Run writes the queued events in batches of up to usageBatchSize, as soon as a batch is full and
+// otherwise every flush interval, until ctx is done; it then writes what is still queued and
+// returns. A failed write is logged and its events are lost, so that a failing sink cannot back up
+// the buffer.
+func (l *UsageLog) Run(ctx context.Context) {
+	ticker := time.NewTicker(l.flushInterval)
+	defer ticker.Stop()
+	batch := make([]UsageEvent, 0, usageBatchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		// Written apart from ctx, so that the events queued at shutdown are not lost with it
+		writeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), usageWriteTimeout)
+		defer cancel()
+		if err := l.sink.WriteUsage(writeCtx, batch); err != nil {
+			l.failed.Add(uint64(len(batch)))
+			logrus.WithError(err).WithField("events", len(batch)).Warn("Failed to write usage events")
+		}
+		batch = batch[:0]
+	}
+	add := func(event UsageEvent) {
+		if batch = append(batch, event); len(batch) == usageBatchSize {
+			flush()
+		}
+	}
+	for {
+		select {
+		case event := <-l.events:
+			add(event)
+		case <-ticker.C:
+			flush()
+		case <-ctx.Done():
+			for {
+				select {
+				case event := <-l.events:
+					add(event)
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+// usageDay is the UTC day of t as the usage_events table and stream record it.
+func usageDay(t time.Time) string {
+	return t.UTC().Format(time.DateOnly)
+}
+
+// dbUsageSink writes usage events to the usage_events table.
+type dbUsageSink struct {
+	db *sqlDB
+}
+
+// NewDBUsageSink returns a UsageSink writing to the usage_events table of db.
+func NewDBUsageSink(db *sqlx.DB) UsageSink {
+	return &dbUsageSink{db: newSQLDB(db)}
+}
+
+// WriteUsage inserts events in one statement. Each row records its UTC day, so that summaries
+// group by a column on PostgreSQL and SQLite alike.
+func (s *dbUsageSink) WriteUsage(ctx context.Context, events []UsageEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	const columns = 7
+	rows := make([]string, len(events))
+	args := make([]interface{}, 0, len(events)*columns)
+	for i, event := range events {
+		placeholders := make([]string, columns)
+		for j := range placeholders {
+			placeholders[j] = "$" + strconv.Itoa(i*columns+j+1)
+		}
+		rows[i] = "(" + strings.Join(placeholders, ", ") + ")"
+		args = append(args, event.OccurredAt.UTC(), usageDay(event.OccurredAt), event.Kind, event.Name,
+			event.DurationMS, event.ErrorCode, event.Client)
+	}
+	query := `INSERT INTO usage_events (occurred_at, day, kind, name, duration_ms, error_code, client) VALUES ` +
+		strings.Join(rows, ", ")
+	if _, err := s.db.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to write %d usage events: %w", len(events), err)
+	}
+	return nil
+}
+
+// UsageSummary implements UsageSink.
+func (s *dbUsageSink) UsageSummary(ctx context.Context, since time.Time) ([]*UsageCount, error) {
+	const query = `
+		SELECT day, kind, name, COUNT(*) AS calls,
+			SUM(CASE WHEN error_code <> 0 THEN 1 ELSE 0 END) AS errors,
+			AVG(duration_ms) AS avg_duration_ms
+		FROM usage_events
+		WHERE day >= $1
+		GROUP BY day, kind, name
+		ORDER BY day DESC, calls DESC, kind, name`
+
+	counts := []*UsageCount{}
+	if err := s.db.SelectContext(ctx, &counts, query, usageDay(since)); err != nil {
+		return nil, fmt.Errorf("failed to summarize usage: %w", err)
+	}
+	return counts, nil
+}
+
+// redisUsageSink appends usage events to the UsageStream Redis stream, capped at about
+// usageStreamMaxLen entries.
+type redisUsageSink struct {
+	client *redis.Client
+}
+
+// NewRedisUsageSink returns a UsageSink appending to the UsageStream stream of client.
+func NewRedisUsageSink(client *redis.Client) UsageSink {
+	return &redisUsageSink{client: client}
+}
+
+// WriteUsage appends events to the stream in one pipeline.
+func (s *redisUsageSink) WriteUsage(ctx context.Context, events []UsageEvent) error {
+	_, err := s.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, event := range events {
+			pipe.XAdd(ctx, &redis.XAddArgs{
+				Stream: UsageStream,
+				MaxLen: usageStreamMaxLen,
+				Approx: true,
+				Values: map[string]interface{}{
+					"occurred_at": event.OccurredAt.UTC().Format(time.RFC3339Nano),
+					"kind":        event.Kind,
+					"name":        event.Name,
+					"duration_ms": event.DurationMS,
+					"error_code":  event.ErrorCode,
+					"client":      event.Client,
+				},
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write %d usage events: %w", len(events), err)
+	}
+	return nil
+}
+
+// UsageSummary reads the stream entries added since since, which the stream's IDs order by time,
+// and counts those of events since then as the database sink does.
+func (s *redisUsageSink) UsageSummary(ctx context.Context, since time.Time) ([]*UsageCount, error) {
+	entries, err := s.client.XRange(ctx, UsageStream, strconv.FormatInt(since.UnixMilli(), 10), "+").Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize usage: %w", err)
+	}
+	type key struct{ day, kind, name string }
+	byKey := make(map[key]*UsageCount)
+	counts := []*UsageCount{}
+	first := usageDay(since)
+	for _, entry := range entries {
+		occurred, _ := time.Parse(time.RFC3339Nano, fmt.Sprint(entry.Values["occurred_at"]))
+		// The IDs date the writes, a little after the events they hold
+		day := usageDay(occurred)
+		if day < first {
+			continue
+		}
+		k := key{day, fmt.Sprint(entry.Values["kind"]), fmt.Sprint(entry.Values["name"])}
+		count, ok := byKey[k]
+		if !ok {
+			count = &UsageCount{Day: k.day, Kind: k.kind, Name: k.name}
+			byKey[k] = count
+			counts = append(counts, count)
+		}
+		duration, _ := strconv.ParseFloat(fmt.Sprint(entry.Values["duration_ms"]), 64)
+		count.AvgDurationMS += (duration - count.AvgDurationMS) / float64(count.Calls+1)
+		count.Calls++
+		if fmt.Sprint(entry.Values["error_code"]) != "0" {
+			count.Errors++
+		}
+	}
+	slices.SortFunc(counts, func(a, b *UsageCount) int {
+		if c := strings.Compare(b.Day, a.Day); c != 0 {
+			return c
+		}
+		if a.Calls != b.Calls {
+			return b.Calls - a.Calls
+		}
+		if c := strings.Compare(a.Kind, b.Kind); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	return counts, nil
+}
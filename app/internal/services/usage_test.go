@@ -0,0 +1,153 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/services"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeUsageSink records the batches written to it, failing them while err is set.
+type fakeUsageSink struct {
+	mu      sync.Mutex
+	batches [][]services.UsageEvent
+	err     error
+}
+
+func (s *fakeUsageSink) WriteUsage(_ context.Context, events []services.UsageEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	s.batches = append(s.batches, append([]services.UsageEvent(nil), events...))
+	return nil
+}
+
+func (s *fakeUsageSink) UsageSummary(_ context.Context, _ time.Time) ([]*services.UsageCount, error) {
+	return nil, nil
+}
+
+func (s *fakeUsageSink) written() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	written := 0
+	for _, batch := range s.batches {
+		written += len(batch)
+	}
+	return written
+}
+
+func toolEvent(name string, at time.Time, durationMS int64, errorCode int) services.UsageEvent {
+	return services.UsageEvent{
+		OccurredAt: at, Kind: "tool", Name: name, DurationMS: durationMS, ErrorCode: errorCode, Client: "test",
+	}
+}
+
+func TestUsageLog_Record(t *testing.T) {
+	t.Run("Events beyond the buffer are dropped and counted", func(t *testing.T) {
+		log := services.NewUsageLog(&fakeUsageSink{}, 2, time.Hour)
+
+		assert.True(t, log.Record(toolEvent("bjcp_lookup", time.Now(), 1, 0)))
+		assert.True(t, log.Record(toolEvent("bjcp_lookup", time.Now(), 1, 0)))
+		assert.False(t, log.Record(toolEvent("bjcp_lookup", time.Now(), 1, 0)))
+		assert.Equal(t, uint64(1), log.Dropped())
+	})
+
+	t.Run("Queued events are written when the log stops", func(t *testing.T) {
+		sink := &fakeUsageSink{}
+		log := services.NewUsageLog(sink, 10, time.Hour)
+		for range 3 {
+			log.Record(toolEvent("search_beers", time.Now(), 4, 0))
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		log.Run(ctx)
+
+		assert.Equal(t, 3, sink.written())
+		assert.Zero(t, log.Dropped())
+	})
+
+	t.Run("Events are written every flush interval", func(t *testing.T) {
+		sink := &fakeUsageSink{}
+		log := services.NewUsageLog(sink, 10, 10*time.Millisecond)
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			log.Run(ctx)
+		}()
+		defer func() {
+			cancel()
+			<-done
+		}()
+
+		log.Record(toolEvent("find_breweries", time.Now(), 2, 0))
+		assert.Eventually(t, func() bool { return sink.written() == 1 }, time.Second, 5*time.Millisecond)
+	})
+
+	t.Run("Failed writes are counted and do not stop the log", func(t *testing.T) {
+		sink := &fakeUsageSink{err: errors.New("connection refused")}
+		log := services.NewUsageLog(sink, 10, time.Hour)
+		log.Record(toolEvent("get_beer", time.Now(), 2, 0))
+		log.Record(toolEvent("get_beer", time.Now(), 2, 0))
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		log.Run(ctx)
+
+		assert.Equal(t, uint64(2), log.Failed())
+	})
+}
+
+// testUsageSummary writes usage events to sink and checks their summary.
+func testUsageSummary(t *testing.T, sink services.UsageSink) {
+	t.Helper()
+	ctx := context.Background()
+	now := time.Now().UTC()
+	yesterday := now.AddDate(0, 0, -1)
+	require.NoError(t, sink.WriteUsage(ctx, []services.UsageEvent{
+		toolEvent("bjcp_lookup", now, 10, 0),
+		toolEvent("bjcp_lookup", now, 20, -32602),
+		toolEvent("search_beers", now, 30, 0),
+		toolEvent("search_beers", yesterday, 5, 0),
+		toolEvent("search_beers", now.AddDate(0, 0, -10), 5, 0),
+		{OccurredAt: now, Kind: "resource", Name: "bjcp://styles", DurationMS: 1},
+	}))
+
+	counts, err := services.NewUsageLog(sink, 0, 0).Summary(ctx, 2)
+	require.NoError(t, err)
+	today, previous := now.Format(time.DateOnly), yesterday.Format(time.DateOnly)
+	assert.Equal(t, []*services.UsageCount{
+		{Day: today, Kind: "tool", Name: "bjcp_lookup", Calls: 2, Errors: 1, AvgDurationMS: 15},
+		{Day: today, Kind: "resource", Name: "bjcp://styles", Calls: 1, AvgDurationMS: 1},
+		{Day: today, Kind: "tool", Name: "search_beers", Calls: 1, AvgDurationMS: 30},
+		{Day: previous, Kind: "tool", Name: "search_beers", Calls: 1, AvgDurationMS: 5},
+	}, counts, "events older than the summary's days are left out")
+}
+
+func TestDBUsageSink(t *testing.T) {
+	for name, db := range testBackends(t) {
+		t.Run(name, func(t *testing.T) {
+			_, err := db.Exec("DELETE FROM usage_events")
+			require.NoError(t, err)
+			testUsageSummary(t, services.NewDBUsageSink(db))
+		})
+	}
+}
+
+func TestRedisUsageSink(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	testUsageSummary(t, services.NewRedisUsageSink(client))
+}
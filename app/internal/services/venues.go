@@ -0,0 +1,272 @@
+// Package services provides business logic and service layer functions for Brewsource MCP, including beer and brewery operations.
+package services
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Venue is a taproom, bar, or other place a brewery pours its beer, whose address may differ from
+// the brewery's registered one. A brewery has at most one primary venue, its main taproom.
+type Venue struct {
+	ID         int        `json:"id"          db:"id"`
+	BreweryID  int        `json:"brewery_id"  db:"brewery_id"`
+	Name       string     `json:"name"        db:"name"`
+	Street     string     `json:"street"      db:"street"`
+	City       string     `json:"city"        db:"city"`
+	State      string     `json:"state"       db:"state"`
+	PostalCode string     `json:"postal_code" db:"postal_code"`
+	Country    string     `json:"country"     db:"country"`
+	Latitude   *float64   `json:"latitude"    db:"latitude"` // nil when unknown
+	Longitude  *float64   `json:"longitude"   db:"longitude"`
+	Hours      VenueHours `json:"hours"       db:"hours"` // nil when unknown
+	IsPrimary  bool       `json:"is_primary"  db:"is_primary"`
+}
+
+// Validate checks that the venue has a name, that its coordinates are given together and in range,
+// and that its hours are valid.
+func (v Venue) Validate() error {
+	switch {
+	case v.Name == "":
+		return fmt.Errorf("%w: venue name is required", ErrInvalidRecord)
+	case len(v.Name) > maxNameLength:
+		return fmt.Errorf("%w: venue name must be at most %d characters", ErrInvalidRecord, maxNameLength)
+	case (v.Latitude == nil) != (v.Longitude == nil):
+		return fmt.Errorf("%w: latitude and longitude must be given together", ErrInvalidRecord)
+	case v.Latitude != nil && (*v.Latitude < -90 || *v.Latitude > 90):
+		return fmt.Errorf("%w: latitude must be between -90 and 90 (got %g)", ErrInvalidRecord, *v.Latitude)
+	case v.Longitude != nil && (*v.Longitude < -180 || *v.Longitude > 180):
+		return fmt.Errorf("%w: longitude must be between -180 and 180 (got %g)", ErrInvalidRecord, *v.Longitude)
+	}
+	return v.Hours.Validate()
+}
+
+// VenueSearchResult is a venue found by SearchVenuesNear, with its brewery's name and its distance
+// from the point searched.
+type VenueSearchResult struct {
+	Venue
+	Brewery    string  `json:"brewery"     db:"brewery"`
+	DistanceKm float64 `json:"distance_km" db:"distance_km"`
+}
+
+// VenueSearchQuery finds the venues with known coordinates nearest a point, within RadiusKm of it
+// when that is given.
+type VenueSearchQuery struct {
+	Lat      float64
+	Lng      float64
+	RadiusKm *float64
+	Limit    int // at most MaxSearchLimit; zero for DefaultSearchLimit
+}
+
+// Validate checks that the point and radius are in range and that Limit is in range.
+func (q VenueSearchQuery) Validate() error {
+	if err := validateLimit(q.Limit); err != nil {
+		return err
+	}
+	switch {
+	case q.Lat < -90 || q.Lat > 90:
+		return fmt.Errorf("%w: latitude must be between -90 and 90 (got %g)", ErrInvalidSearchQuery, q.Lat)
+	case q.Lng < -180 || q.Lng > 180:
+		return fmt.Errorf("%w: longitude must be between -180 and 180 (got %g)", ErrInvalidSearchQuery, q.Lng)
+	case q.RadiusKm != nil && *q.RadiusKm <= 0:
+		return fmt.Errorf("%w: radius_km must be greater than zero (got %g)", ErrInvalidSearchQuery, *q.RadiusKm)
+	}
+	return nil
+}
+
+// VenueHours are the opening hours of a venue in its local time, keyed by lowercase three-letter
+// weekday, "mon" to "sun". Each value is "closed" or one or more comma-separated "HH:MM-HH:MM"
+// ranges, such as "12:00-15:00, 17:00-23:00"; a range ending at or before its start closes after
+// midnight, and "24:00" ends a day. A day without an entry is closed. The hours are stored as a JSON
+// object.
+type VenueHours map[string]string
+
+// VenueDays are the VenueHours keys, from Monday.
+var VenueDays = []string{"mon", "tue", "wed", "thu", "fri", "sat", "sun"}
+
+// weekdays are the VenueHours keys, indexed by time.Weekday.
+var weekdays = [...]string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// Validate checks that every key is a weekday and every value is "closed" or a list of ranges.
+func (h VenueHours) Validate() error {
+	for day, value := range h {
+		if !slices.Contains(VenueDays, day) {
+			return fmt.Errorf("%w: unknown day %q in hours (must be one of %s)",
+				ErrInvalidRecord, day, strings.Join(VenueDays, ", "))
+		}
+		if _, err := openingRanges(value); err != nil {
+			return fmt.Errorf("%w: hours for %s: %w", ErrInvalidRecord, day, err)
+		}
+	}
+	return nil
+}
+
+// OpenAt reports whether the venue is open at t, read as the venue's local time: t should be in
+// the venue's time zone. Invalid hours are treated as closed.
+func (h VenueHours) OpenAt(t time.Time) bool {
+	minute := t.Hour()*60 + t.Minute()
+	today, _ := openingRanges(h[weekdays[t.Weekday()]])
+	for _, r := range today {
+		if minute >= r.open && (minute < r.close || r.close <= r.open) {
+			return true
+		}
+	}
+	// A range of the day before that runs past midnight
+	yesterday, _ := openingRanges(h[weekdays[(t.Weekday()+6)%7]])
+	for _, r := range yesterday {
+		if r.close <= r.open && minute < r.close {
+			return true
+		}
+	}
+	return false
+}
+
+// Scan reads hours stored as a JSON object; NULL is nil.
+func (h *VenueHours) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*h = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, h)
+	case string:
+		return json.Unmarshal([]byte(v), h)
+	default:
+		return fmt.Errorf("cannot scan %T into VenueHours", value)
+	}
+}
+
+// Value stores the hours as a JSON object, or NULL when they are unknown.
+func (h VenueHours) Value() (driver.Value, error) {
+	if h == nil {
+		return nil, nil
+	}
+	raw, err := json.Marshal(map[string]string(h))
+	return string(raw), err
+}
+
+// openingRange is one span of opening hours, in minutes after midnight.
+type openingRange struct {
+	open, close int
+}
+
+// openingRanges parses a VenueHours value; "closed" and the empty string have no ranges.
+func openingRanges(value string) ([]openingRange, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || strings.EqualFold(value, "closed") {
+		return nil, nil
+	}
+	var ranges []openingRange
+	for _, span := range strings.Split(value, ",") {
+		open, closing, found := strings.Cut(strings.TrimSpace(span), "-")
+		if !found {
+			return nil, fmt.Errorf("%q is not closed or HH:MM-HH:MM", span)
+		}
+		openMinute, err := clockMinute(open)
+		if err != nil {
+			return nil, err
+		}
+		closeMinute, err := clockMinute(closing)
+		if err != nil {
+			return nil, err
+		}
+		ranges = append(ranges, openingRange{open: openMinute, close: closeMinute})
+	}
+	return ranges, nil
+}
+
+// clockMinute parses an HH:MM time, from 00:00 to 24:00, into minutes after midnight.
+func clockMinute(clock string) (int, error) {
+	hours, minutes, found := strings.Cut(strings.TrimSpace(clock), ":")
+	h, hourErr := strconv.Atoi(hours)
+	m, minuteErr := strconv.Atoi(minutes)
+	if !found || len(minutes) != 2 || hourErr != nil || minuteErr != nil ||
+		h < 0 || m < 0 || m > 59 || h*60+m > 24*60 {
+		return 0, fmt.Errorf("%q is not a time from 00:00 to 24:00", clock)
+	}
+	return h*60 + m, nil
+}
+
+// venueColumns selects a Venue from venues v.
+const venueColumns = `v.id, v.brewery_id, v.name, COALESCE(v.street, '') AS street, COALESCE(v.city, '') AS city,
+		       COALESCE(v.state, '') AS state, COALESCE(v.postal_code, '') AS postal_code,
+		       COALESCE(v.country, '') AS country, v.latitude, v.longitude, v.hours, v.is_primary`
+
+// GetBreweryVenues returns the venues of the brewery with the given ID, the primary venue first and
+// the rest by name. A brewery without venues, or with no such brewery or a soft-deleted one, has
+// none.
+func (s *BreweryService) GetBreweryVenues(ctx context.Context, breweryID int) (_ []*Venue, err error) {
+	query := `
+		SELECT ` + venueColumns + `
+		FROM venues v
+		JOIN breweries br ON br.id = v.brewery_id
+		WHERE v.brewery_id = $1 AND br.deleted_at IS NULL
+		ORDER BY v.is_primary DESC, v.name, v.id`
+
+	ctx, finish := s.queries.begin(ctx, "get_brewery_venues", query, breweryID)
+	defer finish(&err)
+
+	var venues []*Venue
+	err = s.queries.retry(ctx, "get_brewery_venues", func() error {
+		venues = []*Venue{}
+		return s.db.SelectContext(ctx, &venues, query, breweryID)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get venues of brewery %d: %w", breweryID, err)
+	}
+	return venues, nil
+}
+
+// SearchVenuesNear returns the venues with known coordinates nearest the point of query, with their
+// distance, rather than measuring from the breweries' registered addresses as SearchBreweries does.
+// Venues of soft-deleted breweries are left out. Like brewery distance searches, it fails with
+// ErrInvalidSearchQuery while distance search is disabled.
+func (s *BreweryService) SearchVenuesNear(
+	ctx context.Context,
+	query VenueSearchQuery,
+) (_ []*VenueSearchResult, err error) {
+	if err = query.Validate(); err != nil {
+		return nil, err
+	}
+	if !s.distance {
+		return nil, fmt.Errorf("%w: searching by distance is not available on this database", ErrInvalidSearchQuery)
+	}
+	if query.Limit == 0 {
+		query.Limit = DefaultSearchLimit
+	}
+
+	args := []interface{}{query.Lat, query.Lng}
+	distance := haversineKm("v.", 1, 2)
+	filter := ""
+	if query.RadiusKm != nil {
+		args = append(args, *query.RadiusKm)
+		filter = fmt.Sprintf(" AND %s <= $%d", distance, len(args))
+	}
+	args = append(args, query.Limit)
+	q := `
+		SELECT ` + venueColumns + `, br.name AS brewery, ` + distance + ` AS distance_km
+		FROM venues v
+		JOIN breweries br ON br.id = v.brewery_id
+		WHERE br.deleted_at IS NULL AND v.latitude IS NOT NULL AND v.longitude IS NOT NULL` + filter + `
+		ORDER BY distance_km, v.name, v.id
+		LIMIT $` + strconv.Itoa(len(args))
+
+	ctx, finish := s.queries.begin(ctx, "search_venues_near", q, query)
+	defer finish(&err)
+
+	var venues []*VenueSearchResult
+	err = s.queries.retry(ctx, "search_venues_near", func() error {
+		venues = []*VenueSearchResult{}
+		return s.db.SelectContext(ctx, &venues, q, args...)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search venues: %w", err)
+	}
+	return venues, nil
+}
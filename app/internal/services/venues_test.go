@@ -0,0 +1,181 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/services"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVenueHours_OpenAt(t *testing.T) {
+	hours := services.VenueHours{
+		"mon": "closed",
+		"tue": "12:00-15:00, 17:00-22:00",
+		"thu": "20:00-01:00",
+		"fri": "16:00-02:00",
+		"sat": "00:00-24:00",
+	}
+	// 2026-10-12 is a Monday
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, 10, 11+day, hour, minute, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		name string
+		at   time.Time
+		want bool
+	}{
+		{"closed day", at(1, 13, 0), false},
+		{"first range", at(2, 12, 0), true},
+		{"between ranges", at(2, 15, 0), false},
+		{"second range", at(2, 21, 59), true},
+		{"at closing", at(2, 22, 0), false},
+		{"day without hours", at(3, 13, 0), false},
+		{"overnight range before midnight", at(5, 23, 30), true},
+		{"overnight range after midnight", at(6, 1, 59), true},
+		{"all day", at(6, 23, 59), true},
+		{"all day range does not run into the next day", at(7, 0, 30), false},
+		{"overnight range into a day with hours", at(5, 0, 30), true},
+		{"overnight range at closing", at(5, 1, 0), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, hours.OpenAt(tt.at))
+		})
+	}
+
+	assert.False(t, services.VenueHours(nil).OpenAt(at(2, 13, 0)))
+	assert.False(t, services.VenueHours{"tue": "noon-late"}.OpenAt(at(2, 13, 0)),
+		"Invalid hours should be treated as closed")
+}
+
+func TestVenueHours_Validate(t *testing.T) {
+	valid := services.VenueHours{"mon": "Closed", "tue": "09:00-24:00", "fri": "18:00-02:00, 10:00-12:00"}
+	require.NoError(t, valid.Validate())
+	require.NoError(t, services.VenueHours(nil).Validate())
+
+	for hours, want := range map[string]services.VenueHours{
+		`unknown day "monday"`:                       {"monday": "closed"},
+		`"9:00am" is not a time from 00:00 to 24:00`: {"tue": "9:00am-17:00"},
+		`"24:30" is not a time from 00:00 to 24:00`:  {"tue": "18:00-24:30"},
+		`"late" is not closed or HH:MM-HH:MM`:        {"tue": "late"},
+	} {
+		err := want.Validate()
+		require.ErrorIs(t, err, services.ErrInvalidRecord)
+		assert.ErrorContains(t, err, hours)
+	}
+}
+
+func TestVenueHours_ScanValue(t *testing.T) {
+	hours := services.VenueHours{"fri": "16:00-02:00"}
+	value, err := hours.Value()
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"fri": "16:00-02:00"}`, value.(string))
+
+	var scanned services.VenueHours
+	require.NoError(t, scanned.Scan([]byte(`{"fri": "16:00-02:00"}`)))
+	assert.Equal(t, hours, scanned)
+	require.NoError(t, scanned.Scan(nil))
+	assert.Nil(t, scanned)
+	assert.Error(t, scanned.Scan(42))
+
+	value, err = services.VenueHours(nil).Value()
+	require.NoError(t, err)
+	assert.Nil(t, value)
+}
+
+func TestGetBreweryVenues(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+	service := setupBreweryService(db)
+
+	columns := []string{
+		"id", "brewery_id", "name", "street", "city", "state", "postal_code", "country",
+		"latitude", "longitude", "hours", "is_primary",
+	}
+	mock.ExpectQuery(`SELECT v\.id, v\.brewery_id, v\.name, .* v\.hours, v\.is_primary\s+` +
+		`FROM venues v\s+JOIN breweries br ON br\.id = v\.brewery_id\s+` +
+		`WHERE v\.brewery_id = \$1 AND br\.deleted_at IS NULL\s+` +
+		`ORDER BY v\.is_primary DESC, v\.name, v\.id$`).
+		WithArgs(7).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(3, 7, "Taproom", "95 Durham Avenue", "Cape Town", "Western Cape", "7925", "South Africa",
+				-33.9272, 18.4626, `{"fri": "12:00-23:00"}`, true).
+			AddRow(4, 7, "Beer Garden", "", "", "", "", "", nil, nil, nil, false))
+
+	venues, err := service.GetBreweryVenues(context.Background(), 7)
+
+	require.NoError(t, err)
+	require.Len(t, venues, 2)
+	assert.Equal(t, "Taproom", venues[0].Name)
+	assert.True(t, venues[0].IsPrimary)
+	assert.Equal(t, services.VenueHours{"fri": "12:00-23:00"}, venues[0].Hours)
+	assert.InDelta(t, -33.9272, *venues[0].Latitude, 0.0001)
+	assert.Nil(t, venues[1].Latitude)
+	assert.Nil(t, venues[1].Hours)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSearchVenuesNear(t *testing.T) {
+	columns := []string{
+		"id", "brewery_id", "name", "street", "city", "state", "postal_code", "country",
+		"latitude", "longitude", "hours", "is_primary", "brewery", "distance_km",
+	}
+
+	t.Run("Nearest venues within the radius", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		service := setupBreweryService(db)
+
+		radius := 10.0
+		mock.ExpectQuery(`SELECT v\.id, .*, br\.name AS brewery, \(6371 \* 2 \* ASIN\(.*v\.latitude.*\) AS distance_km\s+`+
+			`FROM venues v\s+JOIN breweries br ON br\.id = v\.brewery_id\s+`+
+			`WHERE br\.deleted_at IS NULL AND v\.latitude IS NOT NULL AND v\.longitude IS NOT NULL `+
+			`AND \(6371 .*\) <= \$3\s+ORDER BY distance_km, v\.name, v\.id\s+LIMIT \$4$`).
+			WithArgs(-33.93, 18.42, radius, 5).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(3, 7, "Taproom", "", "Cape Town", "", "", "South Africa", -33.9272, 18.4626, nil, true,
+					"Devil's Peak Brewing Company", 3.9))
+
+		venues, err := service.SearchVenuesNear(context.Background(), services.VenueSearchQuery{
+			Lat: -33.93, Lng: 18.42, RadiusKm: &radius, Limit: 5,
+		})
+
+		require.NoError(t, err)
+		require.Len(t, venues, 1)
+		assert.Equal(t, "Devil's Peak Brewing Company", venues[0].Brewery)
+		assert.InDelta(t, 3.9, venues[0].DistanceKm, 0.001)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Invalid queries", func(t *testing.T) {
+		db, _ := setupMockDB(t)
+		defer db.Close()
+		service := setupBreweryService(db)
+
+		radius := 0.0
+		for _, query := range []services.VenueSearchQuery{
+			{Lat: 91},
+			{Lng: -181},
+			{RadiusKm: &radius},
+		} {
+			_, err := service.SearchVenuesNear(context.Background(), query)
+			assert.ErrorIs(t, err, services.ErrInvalidSearchQuery)
+		}
+		_, err := service.SearchVenuesNear(context.Background(), services.VenueSearchQuery{Limit: -1})
+		assert.ErrorIs(t, err, services.ErrInvalidLimit)
+	})
+
+	t.Run("Distance search disabled", func(t *testing.T) {
+		db, _ := setupMockDB(t)
+		defer db.Close()
+		service := setupBreweryService(db)
+		service.EnableDistanceSearch(false)
+
+		_, err := service.SearchVenuesNear(context.Background(), services.VenueSearchQuery{Lat: -33.9, Lng: 18.4})
+		assert.ErrorIs(t, err, services.ErrInvalidSearchQuery)
+	})
+}
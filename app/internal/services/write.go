@@ -0,0 +1,359 @@
+// Package services provides business logic and service layer functions for Brewsource MCP, including beer and brewery operations.
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/mail"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+)
+
+// Limits applied by Beer.Validate.
+const (
+	// MaxBeerABV is the highest ABV, in percent, a beer can be written with.
+	MaxBeerABV = 20
+	// MaxBeerIBU is the highest bitterness a beer can be written with.
+	MaxBeerIBU = 200
+	// maxNameLength matches the VARCHAR(255) name columns.
+	maxNameLength = 255
+	// MinFoundedYear is the earliest founding year a brewery can be written with, that of the oldest
+	// brewery still operating.
+	MinFoundedYear = 1040
+)
+
+// socialHandle matches an Instagram, Facebook, or X/Twitter handle, stored without its @.
+var socialHandle = regexp.MustCompile(`^[A-Za-z0-9._-]{1,100}$`)
+
+// Validate checks that the brewery has a name, that its type is one of BreweryTypes, and that its
+// coordinates, when given, are complete and in range. A founding year, social handles, and email,
+// when given, must be between MinFoundedYear and the current year, plain handles, and a bare
+// address. Call it on a normalized brewery; CreateBrewery and UpdateBrewery do so.
+func (b Brewery) Validate() error {
+	switch {
+	case b.Name == "":
+		return fmt.Errorf("%w: brewery name is required", ErrInvalidRecord)
+	case len(b.Name) > maxNameLength:
+		return fmt.Errorf("%w: brewery name must be at most %d characters", ErrInvalidRecord, maxNameLength)
+	case !slices.Contains(BreweryTypes, b.BreweryType):
+		return fmt.Errorf(
+			"%w: brewery type %q must be one of %s", ErrInvalidRecord, b.BreweryType, strings.Join(BreweryTypes, ", "),
+		)
+	case (b.Latitude == nil) != (b.Longitude == nil):
+		return fmt.Errorf("%w: latitude and longitude must be given together", ErrInvalidRecord)
+	case b.Latitude != nil && (*b.Latitude < -90 || *b.Latitude > 90):
+		return fmt.Errorf("%w: latitude must be between -90 and 90 (got %g)", ErrInvalidRecord, *b.Latitude)
+	case b.Longitude != nil && (*b.Longitude < -180 || *b.Longitude > 180):
+		return fmt.Errorf("%w: longitude must be between -180 and 180 (got %g)", ErrInvalidRecord, *b.Longitude)
+	case b.FoundedYear != 0 && (b.FoundedYear < MinFoundedYear || b.FoundedYear > time.Now().Year()):
+		return fmt.Errorf("%w: founded year must be between %d and %d (got %d)",
+			ErrInvalidRecord, MinFoundedYear, time.Now().Year(), b.FoundedYear)
+	}
+	for _, handle := range [][2]string{{"instagram", b.Instagram}, {"facebook", b.Facebook}, {"twitter", b.Twitter}} {
+		if handle[1] != "" && !socialHandle.MatchString(handle[1]) {
+			return fmt.Errorf("%w: %s must be a handle of letters, digits, '.', '_', or '-' (got %q)",
+				ErrInvalidRecord, handle[0], handle[1])
+		}
+	}
+	if b.Email != "" {
+		if addr, err := mail.ParseAddress(b.Email); err != nil || addr.Address != b.Email {
+			return fmt.Errorf("%w: email %q is not an email address", ErrInvalidRecord, b.Email)
+		}
+	}
+	return nil
+}
+
+// Validate checks that the beer has a name and a brewery, that its ABV is between 0 and MaxBeerABV,
+// its IBU between 0 and MaxBeerIBU, and its SRM not negative. Whether the brewery exists is checked
+// when the beer is written.
+func (b Beer) Validate() error {
+	switch {
+	case b.Name == "":
+		return fmt.Errorf("%w: beer name is required", ErrInvalidRecord)
+	case len(b.Name) > maxNameLength:
+		return fmt.Errorf("%w: beer name must be at most %d characters", ErrInvalidRecord, maxNameLength)
+	case b.BreweryID < 1:
+		return fmt.Errorf("%w: brewery ID must be a positive integer", ErrInvalidRecord)
+	case b.ABV < 0 || b.ABV > MaxBeerABV:
+		return fmt.Errorf("%w: abv must be between 0 and %d (got %g)", ErrInvalidRecord, MaxBeerABV, b.ABV)
+	case b.IBU < 0 || b.IBU > MaxBeerIBU:
+		return fmt.Errorf("%w: ibu must be between 0 and %d (got %d)", ErrInvalidRecord, MaxBeerIBU, b.IBU)
+	case b.SRM < 0:
+		return fmt.Errorf("%w: srm cannot be negative (got %g)", ErrInvalidRecord, b.SRM)
+	}
+	return nil
+}
+
+// Normalize trims the brewery's text fields, collapsing internal whitespace except in the
+// description, lowercases its type, and drops the @ from its social handles.
+func (b *Brewery) Normalize() {
+	normalizeSearchFields(
+		false, &b.Name, &b.BreweryType, &b.Street, &b.City, &b.State, &b.PostalCode, &b.Country, &b.Phone,
+		&b.WebsiteURL, &b.Instagram, &b.Facebook, &b.Twitter, &b.Email,
+	)
+	b.BreweryType = strings.ToLower(b.BreweryType)
+	b.Description = strings.TrimSpace(b.Description)
+	for _, handle := range []*string{&b.Instagram, &b.Facebook, &b.Twitter} {
+		*handle = strings.TrimPrefix(*handle, "@")
+	}
+}
+
+// Normalize trims the beer's text fields, collapsing internal whitespace except in the description.
+func (b *Beer) Normalize() {
+	normalizeSearchFields(false, &b.Name, &b.Style)
+	b.Description = strings.TrimSpace(b.Description)
+}
+
+// CreateBrewery validates and inserts a brewery, returning its ID. It wraps ErrInvalidRecord when
+// validation fails and ErrDuplicate when a brewery of the same name already exists in the same city.
+func (s *BreweryService) CreateBrewery(ctx context.Context, brewery Brewery) (_ int, err error) {
+	brewery.Normalize()
+	if err := brewery.Validate(); err != nil {
+		return 0, err
+	}
+	query := `
+		INSERT INTO breweries (
+			name, brewery_type, street, city, state, postal_code, country, phone, website_url,
+			latitude, longitude, description, founded_year, instagram, facebook, twitter, email
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, NULLIF($13, 0), $14, $15, $16, $17)
+		RETURNING id`
+	ctx, finish := s.queries.begin(ctx, "create_brewery", query, brewery.Name)
+	defer finish(&err)
+
+	var id int
+	args := append(breweryWriteArgs(brewery), breweryProfileArgs(brewery)...)
+	if err = s.db.QueryRowxContext(ctx, query, args...).Scan(&id); err != nil {
+		return 0, breweryWriteError(err, brewery, "create")
+	}
+	s.invalidateAfterWrite(ctx)
+	return id, nil
+}
+
+// UpdateBrewery validates a brewery and replaces the stored record with the same ID. It returns a
+// *NotFoundError when no brewery has that ID, and otherwise fails as CreateBrewery does.
+func (s *BreweryService) UpdateBrewery(ctx context.Context, brewery Brewery) (err error) {
+	brewery.Normalize()
+	if err := brewery.Validate(); err != nil {
+		return err
+	}
+	query := `
+		UPDATE breweries
+		SET name = $1, brewery_type = $2, street = $3, city = $4, state = $5, postal_code = $6,
+		    country = $7, phone = $8, website_url = $9, latitude = $10, longitude = $11,
+		    description = $12, founded_year = NULLIF($13, 0), instagram = $14, facebook = $15,
+		    twitter = $16, email = $17, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $18`
+	ctx, finish := s.queries.begin(ctx, "update_brewery", query, brewery.ID)
+	defer finish(&err)
+
+	args := append(append(breweryWriteArgs(brewery), breweryProfileArgs(brewery)...), brewery.ID)
+	result, err := s.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return breweryWriteError(err, brewery, "update")
+	}
+	if err = checkRowsAffected(result, "brewery", brewery.ID); err != nil {
+		return err
+	}
+	s.invalidateAfterWrite(ctx)
+	return nil
+}
+
+// SoftDeleteBrewery hides the brewery with the given ID, and with it its beers, from searches and
+// lookups until RestoreBrewery is called; nothing is removed. It returns a *NotFoundError when no
+// brewery that is not already deleted has that ID.
+func (s *BreweryService) SoftDeleteBrewery(ctx context.Context, id int) error {
+	return s.setBreweryDeleted(ctx, "soft_delete_brewery", `
+		UPDATE breweries SET deleted_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND deleted_at IS NULL`, id)
+}
+
+// RestoreBrewery undoes SoftDeleteBrewery. It returns a *NotFoundError when no soft-deleted brewery
+// has that ID.
+func (s *BreweryService) RestoreBrewery(ctx context.Context, id int) error {
+	return s.setBreweryDeleted(ctx, "restore_brewery", `
+		UPDATE breweries SET deleted_at = NULL, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND deleted_at IS NOT NULL`, id)
+}
+
+// setBreweryDeleted runs a soft delete or restore of one brewery. updated_at is set explicitly for
+// databases without the PostgreSQL trigger.
+func (s *BreweryService) setBreweryDeleted(ctx context.Context, name, query string, id int) (err error) {
+	ctx, finish := s.queries.begin(ctx, name, query, id)
+	defer finish(&err)
+
+	result, err := s.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to update brewery %d: %w", id, err)
+	}
+	if err = checkRowsAffected(result, "brewery", id); err != nil {
+		return err
+	}
+	s.invalidateAfterWrite(ctx)
+	return nil
+}
+
+func breweryWriteArgs(b Brewery) []interface{} {
+	return []interface{}{
+		b.Name, b.BreweryType, b.Street, b.City, b.State, b.PostalCode, b.Country, b.Phone, b.WebsiteURL,
+		b.Latitude, b.Longitude,
+	}
+}
+
+// breweryProfileArgs are the profile fields, which follow breweryWriteArgs in CreateBrewery and
+// UpdateBrewery. Syncs leave them as they are.
+func breweryProfileArgs(b Brewery) []interface{} {
+	return []interface{}{b.Description, b.FoundedYear, b.Instagram, b.Facebook, b.Twitter, b.Email}
+}
+
+// breweryWriteError translates a unique index violation into ErrDuplicate.
+func breweryWriteError(err error, b Brewery, action string) error {
+	if violation(err) == "unique_violation" {
+		location := ""
+		if b.City != "" {
+			location = " in " + b.City
+		}
+		return fmt.Errorf("%w: a brewery named %q already exists%s", ErrDuplicate, b.Name, location)
+	}
+	return fmt.Errorf("failed to %s brewery %q: %w", action, b.Name, err)
+}
+
+// invalidateAfterWrite drops the cached searches and statistics a brewery write can change. Beer
+// results show brewery names and countries, so cached beer searches are dropped too.
+func (s *BreweryService) invalidateAfterWrite(ctx context.Context) {
+	invalidateCaches(ctx, s.cache, s.statsCache, s.beerCache)
+}
+
+// CreateBeer validates and inserts a beer, returning its ID. It wraps ErrInvalidRecord when
+// validation fails and ErrDuplicate when the brewery already has a beer of the same name, and
+// returns a *NotFoundError when the brewery does not exist.
+func (s *BeerService) CreateBeer(ctx context.Context, beer Beer) (_ int, err error) {
+	beer.Normalize()
+	if err := beer.Validate(); err != nil {
+		return 0, err
+	}
+	query := `
+		INSERT INTO beers (brewery_id, name, style, abv, ibu, srm, description)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id`
+	ctx, finish := s.queries.begin(ctx, "create_beer", query, beer.Name)
+	defer finish(&err)
+
+	var id int
+	if err = s.db.QueryRowxContext(ctx, query, beerWriteArgs(beer)...).Scan(&id); err != nil {
+		return 0, beerWriteError(err, beer, "create")
+	}
+	s.invalidateAfterWrite(ctx)
+	return id, nil
+}
+
+// UpdateBeer validates a beer and replaces the stored record with the same ID. It returns a
+// *NotFoundError when no beer has that ID, and otherwise fails as CreateBeer does.
+func (s *BeerService) UpdateBeer(ctx context.Context, beer Beer) (err error) {
+	beer.Normalize()
+	if err := beer.Validate(); err != nil {
+		return err
+	}
+	query := `
+		UPDATE beers
+		SET brewery_id = $1, name = $2, style = $3, abv = $4, ibu = $5, srm = $6, description = $7,
+		    updated_at = CURRENT_TIMESTAMP
+		WHERE id = $8`
+	ctx, finish := s.queries.begin(ctx, "update_beer", query, beer.ID)
+	defer finish(&err)
+
+	result, err := s.db.ExecContext(ctx, query, append(beerWriteArgs(beer), beer.ID)...)
+	if err != nil {
+		return beerWriteError(err, beer, "update")
+	}
+	if err = checkRowsAffected(result, "beer", beer.ID); err != nil {
+		return err
+	}
+	s.invalidateAfterWrite(ctx)
+	return nil
+}
+
+// DeleteBeer deletes the beer with the given ID. It returns a *NotFoundError when no beer has that
+// ID.
+func (s *BeerService) DeleteBeer(ctx context.Context, id int) (err error) {
+	const query = `DELETE FROM beers WHERE id = $1`
+	ctx, finish := s.queries.begin(ctx, "delete_beer", query, id)
+	defer finish(&err)
+
+	result, err := s.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete beer %d: %w", id, err)
+	}
+	if err = checkRowsAffected(result, "beer", id); err != nil {
+		return err
+	}
+	s.invalidateAfterWrite(ctx)
+	return nil
+}
+
+func beerWriteArgs(b Beer) []interface{} {
+	return []interface{}{b.BreweryID, b.Name, b.Style, b.ABV, b.IBU, b.SRM, b.Description}
+}
+
+// beerWriteError translates a unique index violation into ErrDuplicate and a foreign key violation
+// into a *NotFoundError for the brewery.
+func beerWriteError(err error, b Beer, action string) error {
+	switch violation(err) {
+	case "unique_violation":
+		return fmt.Errorf("%w: brewery %d already has a beer named %q", ErrDuplicate, b.BreweryID, b.Name)
+	case "foreign_key_violation":
+		return &NotFoundError{Kind: "brewery", ID: b.BreweryID}
+	}
+	return fmt.Errorf("failed to %s beer %q: %w", action, b.Name, err)
+}
+
+// invalidateAfterWrite drops the cached searches and statistics a beer write can change.
+func (s *BeerService) invalidateAfterWrite(ctx context.Context) {
+	invalidateCaches(ctx, s.cache, s.statsCache)
+}
+
+// violation returns the PostgreSQL condition name, such as "unique_violation", of a failed
+// statement, or an empty string for other errors. SQLite's unique and foreign key constraint errors
+// are given the same names; they are recognised by message, since the driver's error type is only
+// built with cgo.
+func violation(err error) string {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Code.Name()
+	}
+	switch {
+	case strings.HasPrefix(err.Error(), "UNIQUE constraint failed"):
+		return "unique_violation"
+	case err.Error() == "FOREIGN KEY constraint failed":
+		return "foreign_key_violation"
+	}
+	return ""
+}
+
+// checkRowsAffected returns a *NotFoundError when an UPDATE or DELETE by ID matched no row.
+func checkRowsAffected(result sql.Result, kind string, id int) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to write %s %d: %w", kind, id, err)
+	}
+	if affected == 0 {
+		return &NotFoundError{Kind: kind, ID: id}
+	}
+	return nil
+}
+
+// invalidateCaches drops the given caches after a write. A failure only leaves stale results until
+// they expire, so it is logged rather than failing the write.
+func invalidateCaches(ctx context.Context, caches ...*searchCache) {
+	for _, cache := range caches {
+		if err := cache.invalidate(ctx); err != nil {
+			logrus.Warnf("Failed to invalidate cache after a write: %v", err)
+		}
+	}
+}
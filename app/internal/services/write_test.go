@@ -0,0 +1,366 @@
+// Package services_test contains tests for the business logic and service layer functions in Brewsource MCP.
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/services"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func validBrewery() services.Brewery {
+	return services.Brewery{Name: "Devil's Peak Brewing", BreweryType: "micro", City: "Cape Town"}
+}
+
+func validBeer() services.Beer {
+	return services.Beer{BreweryID: 3, Name: "King's Blockhouse IPA", Style: "American IPA", ABV: 6.0, IBU: 55}
+}
+
+func TestBreweryValidate(t *testing.T) {
+	lat, lng, far := -33.9, 18.4, 200.0
+	tests := []struct {
+		name    string
+		edit    func(*services.Brewery)
+		wantErr string
+	}{
+		{name: "Valid", edit: func(*services.Brewery) {}},
+		{name: "Valid with coordinates", edit: func(b *services.Brewery) { b.Latitude, b.Longitude = &lat, &lng }},
+		{name: "Missing name", edit: func(b *services.Brewery) { b.Name = "" }, wantErr: "name is required"},
+		{name: "Unknown type", edit: func(b *services.Brewery) { b.BreweryType = "winery" }, wantErr: "must be one of"},
+		{name: "Missing type", edit: func(b *services.Brewery) { b.BreweryType = "" }, wantErr: "must be one of"},
+		{
+			name:    "Half a coordinate",
+			edit:    func(b *services.Brewery) { b.Latitude = &lat },
+			wantErr: "latitude and longitude must be given together",
+		},
+		{
+			name:    "Longitude out of range",
+			edit:    func(b *services.Brewery) { b.Latitude, b.Longitude = &lat, &far },
+			wantErr: "longitude must be between -180 and 180",
+		},
+		{
+			name: "Valid with a profile",
+			edit: func(b *services.Brewery) {
+				b.FoundedYear, b.Instagram, b.Twitter, b.Email = 2012, "devilspeakbeer", "devils.peak", "hello@devilspeak.beer"
+			},
+		},
+		{
+			name:    "Founded in the future",
+			edit:    func(b *services.Brewery) { b.FoundedYear = 3000 },
+			wantErr: "founded year must be between 1040 and",
+		},
+		{
+			name:    "Social link instead of a handle",
+			edit:    func(b *services.Brewery) { b.Facebook = "https://facebook.com/devilspeak" },
+			wantErr: "facebook must be a handle",
+		},
+		{
+			name:    "Email with a display name",
+			edit:    func(b *services.Brewery) { b.Email = "Devil's Peak <hello@devilspeak.beer>" },
+			wantErr: "is not an email address",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			brewery := validBrewery()
+			tt.edit(&brewery)
+			err := brewery.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, services.ErrInvalidRecord)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestBeerValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		edit    func(*services.Beer)
+		wantErr string
+	}{
+		{name: "Valid", edit: func(*services.Beer) {}},
+		{name: "Valid at the limits", edit: func(b *services.Beer) { b.ABV, b.IBU = 20, 200 }},
+		{name: "Missing name", edit: func(b *services.Beer) { b.Name = "" }, wantErr: "name is required"},
+		{name: "Missing brewery", edit: func(b *services.Beer) { b.BreweryID = 0 }, wantErr: "brewery ID"},
+		{name: "ABV too high", edit: func(b *services.Beer) { b.ABV = 20.5 }, wantErr: "abv must be between 0 and 20"},
+		{name: "Negative ABV", edit: func(b *services.Beer) { b.ABV = -1 }, wantErr: "abv must be between 0 and 20"},
+		{name: "IBU too high", edit: func(b *services.Beer) { b.IBU = 201 }, wantErr: "ibu must be between 0 and 200"},
+		{name: "Negative SRM", edit: func(b *services.Beer) { b.SRM = -2 }, wantErr: "srm cannot be negative"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			beer := validBeer()
+			tt.edit(&beer)
+			err := beer.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, services.ErrInvalidRecord)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestCreateBrewery(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Inserts a normalized brewery and returns its ID", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		service := setupBreweryService(db)
+		mock.ExpectQuery(`INSERT INTO breweries \(.*\) VALUES \(\$1, .*NULLIF\(\$13, 0\), .*\$17\)\s+RETURNING id`).
+			WithArgs("Devil's Peak Brewing", "micro", "", "Cape Town", "", "", "", "", "", nil, nil,
+				"Craft beer in Woodstock.", 2012, "devilspeakbeer", "", "", "").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(12))
+
+		brewery := validBrewery()
+		brewery.Name, brewery.BreweryType = "  Devil's  Peak Brewing ", "Micro"
+		brewery.Description, brewery.FoundedYear, brewery.Instagram = " Craft beer in Woodstock.\n", 2012, " @devilspeakbeer"
+		id, err := service.CreateBrewery(ctx, brewery)
+
+		require.NoError(t, err)
+		assert.Equal(t, 12, id)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Rejects invalid breweries before writing", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		service := setupBreweryService(db)
+
+		_, err := service.CreateBrewery(ctx, services.Brewery{Name: "   ", BreweryType: "micro"})
+
+		require.ErrorIs(t, err, services.ErrInvalidRecord)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Reports duplicate names", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		service := setupBreweryService(db)
+		mock.ExpectQuery(`INSERT INTO breweries`).
+			WillReturnError(&pq.Error{Code: "23505", Message: "duplicate key value violates unique constraint"})
+
+		_, err := service.CreateBrewery(ctx, validBrewery())
+
+		require.ErrorIs(t, err, services.ErrDuplicate)
+		assert.Contains(t, err.Error(), `a brewery named "Devil's Peak Brewing" already exists in Cape Town`)
+	})
+
+	t.Run("Wraps other database errors", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		service := setupBreweryService(db)
+		mock.ExpectQuery(`INSERT INTO breweries`).WillReturnError(errors.New("connection reset"))
+
+		_, err := service.CreateBrewery(ctx, validBrewery())
+
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, services.ErrDuplicate)
+		assert.Contains(t, err.Error(), "failed to create brewery")
+	})
+}
+
+func TestUpdateBrewery(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Updates the brewery with the given ID", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		service := setupBreweryService(db)
+		brewery := validBrewery()
+		brewery.ID = 4
+		mock.ExpectExec(`UPDATE breweries\s+SET name = \$1, .* updated_at = CURRENT_TIMESTAMP\s+WHERE id = \$18`).
+			WithArgs("Devil's Peak Brewing", "micro", "", "Cape Town", "", "", "", "", "", nil, nil,
+				"", 0, "", "", "", "", 4).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		require.NoError(t, service.UpdateBrewery(ctx, brewery))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Reports unknown IDs", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		service := setupBreweryService(db)
+		brewery := validBrewery()
+		brewery.ID = 404
+		mock.ExpectExec(`UPDATE breweries`).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err := service.UpdateBrewery(ctx, brewery)
+
+		var notFound *services.NotFoundError
+		require.ErrorAs(t, err, &notFound)
+		assert.Equal(t, &services.NotFoundError{Kind: "brewery", ID: 404}, notFound)
+	})
+}
+
+func TestCreateBeer(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Inserts the beer and returns its ID", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		service := setupBeerService(db)
+		mock.ExpectQuery(`INSERT INTO beers \(brewery_id, .*\)\s+VALUES \(\$1, .*\$7\)\s+RETURNING id`).
+			WithArgs(3, "King's Blockhouse IPA", "American IPA", 6.0, 55, 0.0, "Hoppy.").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(99))
+
+		beer := validBeer()
+		beer.Description = "  Hoppy.\n"
+		id, err := service.CreateBeer(ctx, beer)
+
+		require.NoError(t, err)
+		assert.Equal(t, 99, id)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Rejects out-of-range vitals before writing", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		service := setupBeerService(db)
+		beer := validBeer()
+		beer.IBU = 250
+
+		_, err := service.CreateBeer(ctx, beer)
+
+		require.ErrorIs(t, err, services.ErrInvalidRecord)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Reports duplicate names at the same brewery", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		service := setupBeerService(db)
+		mock.ExpectQuery(`INSERT INTO beers`).WillReturnError(&pq.Error{Code: "23505"})
+
+		_, err := service.CreateBeer(ctx, validBeer())
+
+		require.ErrorIs(t, err, services.ErrDuplicate)
+		assert.Contains(t, err.Error(), `brewery 3 already has a beer named "King's Blockhouse IPA"`)
+	})
+
+	t.Run("Reports a missing brewery", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		service := setupBeerService(db)
+		mock.ExpectQuery(`INSERT INTO beers`).
+			WillReturnError(&pq.Error{Code: "23503", Message: "insert or update violates foreign key constraint"})
+
+		_, err := service.CreateBeer(ctx, validBeer())
+
+		var notFound *services.NotFoundError
+		require.ErrorAs(t, err, &notFound)
+		assert.Equal(t, "brewery 3 not found", notFound.Error())
+	})
+}
+
+func TestUpdateBeer(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+	service := setupBeerService(db)
+	beer := validBeer()
+	beer.ID = 8
+	mock.ExpectExec(`UPDATE beers\s+SET brewery_id = \$1, .* updated_at = CURRENT_TIMESTAMP\s+WHERE id = \$8`).
+		WithArgs(3, "King's Blockhouse IPA", "American IPA", 6.0, 55, 0.0, "", 8).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE beers`).WillReturnError(&pq.Error{Code: "23503"})
+
+	require.NoError(t, service.UpdateBeer(context.Background(), beer))
+	beer.BreweryID = 77
+	err := service.UpdateBeer(context.Background(), beer)
+	assert.ErrorIs(t, err, services.ErrNotFound, "moving a beer to a missing brewery should report the brewery")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteBeer(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+	service := setupBeerService(db)
+	mock.ExpectExec(`DELETE FROM beers WHERE id = \$1`).WithArgs(8).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`DELETE FROM beers WHERE id = \$1`).WithArgs(9).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	require.NoError(t, service.DeleteBeer(context.Background(), 8))
+	err := service.DeleteBeer(context.Background(), 9)
+	assert.Equal(t, &services.NotFoundError{Kind: "beer", ID: 9}, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSoftDeleteBrewery(t *testing.T) {
+	ctx := context.Background()
+	deleteQuery := `UPDATE breweries SET deleted_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP\s+` +
+		`WHERE id = \$1 AND deleted_at IS NULL`
+	restoreQuery := `UPDATE breweries SET deleted_at = NULL, updated_at = CURRENT_TIMESTAMP\s+` +
+		`WHERE id = \$1 AND deleted_at IS NOT NULL`
+
+	t.Run("Deletes and restores a brewery", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		service := setupBreweryService(db)
+		mock.ExpectExec(deleteQuery).WithArgs(4).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(restoreQuery).WithArgs(4).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		require.NoError(t, service.SoftDeleteBrewery(ctx, 4))
+		require.NoError(t, service.RestoreBrewery(ctx, 4))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Reports unknown and already deleted or restored breweries", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		service := setupBreweryService(db)
+		mock.ExpectExec(deleteQuery).WithArgs(404).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(restoreQuery).WithArgs(5).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		assert.Equal(t, &services.NotFoundError{Kind: "brewery", ID: 404}, service.SoftDeleteBrewery(ctx, 404))
+		assert.Equal(t, &services.NotFoundError{Kind: "brewery", ID: 5}, service.RestoreBrewery(ctx, 5))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Wraps database errors", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		service := setupBreweryService(db)
+		mock.ExpectExec(deleteQuery).WillReturnError(errors.New("connection reset"))
+
+		err := service.SoftDeleteBrewery(ctx, 4)
+		assert.EqualError(t, err, "failed to update brewery 4: connection reset")
+	})
+}
+
+func TestWritesInvalidateCaches(t *testing.T) {
+	mr, mock, beerService, breweryService := setupCachedServices(t)
+	ctx := context.Background()
+	for _, key := range []string{
+		"brewsource:search:beers:list:cached",
+		"brewsource:search:breweries:list:cached",
+		"brewsource:stats:overview:cached",
+	} {
+		require.NoError(t, mr.Set(key, "[]"))
+	}
+	mock.ExpectQuery(`INSERT INTO beers`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectQuery(`INSERT INTO breweries`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+
+	_, err := beerService.CreateBeer(ctx, validBeer())
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		[]string{"brewsource:search:breweries:list:cached"},
+		mr.Keys(),
+		"a beer write should drop beer searches and statistics",
+	)
+
+	_, err = breweryService.CreateBrewery(ctx, validBrewery())
+	require.NoError(t, err)
+	assert.Empty(t, mr.Keys())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,125 @@
+// Package testutil provides the fixtures of the integration tests, which run against PostgreSQL and
+// Redis in Docker containers rather than sqlmock and miniredis. The tests are built with the
+// integration tag:
+//
+//	go test -tags integration ./...
+//
+// The containers are started with testcontainers-go on first use and terminated when the test binary
+// exits, see Main; should the binary die first, the testcontainers reaper removes them.
+// INTEGRATION_POSTGRES_URL and INTEGRATION_REDIS_URL name running servers to use instead, as CI
+// services; the PostgreSQL user must be allowed to create databases.
+package testutil
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/testcontainers/testcontainers-go"
+	tcpostgres "github.com/testcontainers/testcontainers-go/modules/postgres"
+	tcredis "github.com/testcontainers/testcontainers-go/modules/redis"
+)
+
+// Environment variables naming servers to use in place of containers.
+const (
+	PostgresURLEnv = "INTEGRATION_POSTGRES_URL"
+	RedisURLEnv    = "INTEGRATION_REDIS_URL"
+)
+
+const (
+	postgresImage = "postgres:15-alpine"
+	redisImage    = "redis:7-alpine"
+	// startTimeout bounds pulling an image and waiting for its server to accept connections.
+	startTimeout = 2 * time.Minute
+)
+
+var (
+	cleanupMu sync.Mutex
+	cleanups  []func() // run by Main, last first
+)
+
+// Main runs the tests of m and then removes the containers and databases they created, exiting
+// with the tests' status. Call it from the TestMain of each package with integration tests:
+//
+//	func TestMain(m *testing.M) { testutil.Main(m) }
+func Main(m *testing.M) {
+	code := m.Run()
+	cleanupMu.Lock()
+	defer cleanupMu.Unlock()
+	for i := len(cleanups) - 1; i >= 0; i-- {
+		cleanups[i]()
+	}
+	os.Exit(code)
+}
+
+// addCleanup has Main call cleanup once the tests have run.
+func addCleanup(cleanup func()) {
+	cleanupMu.Lock()
+	defer cleanupMu.Unlock()
+	cleanups = append(cleanups, cleanup)
+}
+
+// terminateOnExit has Main terminate container, which may be nil when it failed to start.
+func terminateOnExit(container testcontainers.Container) {
+	addCleanup(func() {
+		if err := testcontainers.TerminateContainer(container); err != nil {
+			fmt.Fprintf(os.Stderr, "testutil: failed to terminate container: %v\n", err)
+		}
+	})
+}
+
+// startPostgresContainer runs PostgreSQL and returns the URL of its postgres database, once the
+// server accepts connections.
+func startPostgresContainer() (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), startTimeout)
+	defer cancel()
+	container, err := tcpostgres.Run(ctx, postgresImage,
+		tcpostgres.WithDatabase("postgres"),
+		tcpostgres.WithUsername("brewsource"),
+		tcpostgres.WithPassword("brewsource"),
+		tcpostgres.BasicWaitStrategies(),
+	)
+	terminateOnExit(container)
+	if err != nil {
+		return "", fmt.Errorf("failed to start %s, or set %s: %w", postgresImage, PostgresURLEnv, err)
+	}
+	return container.ConnectionString(ctx, "sslmode=disable")
+}
+
+// startRedisContainer runs Redis and returns the URL of its database 0, once the server accepts
+// connections.
+func startRedisContainer() (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), startTimeout)
+	defer cancel()
+	container, err := tcredis.Run(ctx, redisImage)
+	terminateOnExit(container)
+	if err != nil {
+		return "", fmt.Errorf("failed to start %s, or set %s: %w", redisImage, RedisURLEnv, err)
+	}
+	address, err := container.ConnectionString(ctx)
+	if err != nil {
+		return "", err
+	}
+	return address + "/0", nil
+}
+
+// waitFor calls ready until it succeeds or startTimeout passes, returning its last error then. The
+// servers named by the environment may still be starting, as CI services do.
+func waitFor(what string, ready func(ctx context.Context) error) error {
+	deadline := time.Now().Add(startTimeout)
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		err := ready(ctx)
+		cancel()
+		if err == nil {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("%s was not ready after %s: %w", what, startTimeout, err)
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+}
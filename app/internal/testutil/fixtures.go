@@ -0,0 +1,288 @@
+package testutil
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/handlers"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/mcp"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/models"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/services"
+	"github.com/CharlRitter/brewsource-mcp/app/pkg/data"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq" // PostgreSQL driver
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	postgresOnce sync.Once
+	postgresURL  string // URL of the server's postgres database, for creating the test databases
+	postgresErr  error
+	databases    atomic.Int64 // numbers the databases created by Postgres
+
+	redisOnce sync.Once
+	redisURL  string
+	redisErr  error
+)
+
+// templateDatabase is migrated and seeded once per test binary, and copied for each test. It is
+// named after the process, so test binaries sharing a server do not clash.
+var templateDatabase = fmt.Sprintf("brewsource_template_%d", os.Getpid())
+
+// Postgres returns a connection to a PostgreSQL database of its own, migrated with
+// models.MigrateDatabase and seeded with models.SeedDatabase, which is dropped when the test ends.
+// Each database is a copy of one migrated and seeded on first use, so tests may change their
+// catalog freely and run in parallel.
+func Postgres(t testing.TB) *sqlx.DB {
+	t.Helper()
+	postgresOnce.Do(func() { postgresURL, postgresErr = startPostgres() })
+	if postgresErr != nil {
+		t.Fatalf("PostgreSQL is unavailable: %v", postgresErr)
+	}
+
+	admin, err := sqlx.Connect("postgres", postgresURL)
+	if err != nil {
+		t.Fatalf("Failed to connect to PostgreSQL: %v", err)
+	}
+	defer admin.Close()
+	name := fmt.Sprintf("brewsource_test_%d_%d", os.Getpid(), databases.Add(1))
+	if _, err = admin.Exec(fmt.Sprintf("CREATE DATABASE %s TEMPLATE %s", name, templateDatabase)); err != nil {
+		t.Fatalf("Failed to create database %s: %v", name, err)
+	}
+	db, err := sqlx.Connect("postgres", databaseURL(postgresURL, name))
+	if err != nil {
+		t.Fatalf("Failed to connect to database %s: %v", name, err)
+	}
+	t.Cleanup(func() {
+		_ = db.Close()
+		dropDatabase(name)
+	})
+	return db
+}
+
+// startPostgres starts PostgreSQL, or uses the server named by INTEGRATION_POSTGRES_URL, and creates
+// the template database, returning the URL of the server's postgres database.
+func startPostgres() (string, error) {
+	serverURL := os.Getenv(PostgresURLEnv)
+	if serverURL == "" {
+		var err error
+		if serverURL, err = startPostgresContainer(); err != nil {
+			return "", err
+		}
+	}
+	serverURL = databaseURL(serverURL, "postgres")
+
+	var admin *sqlx.DB
+	err := waitFor("PostgreSQL", func(ctx context.Context) error {
+		var err error
+		admin, err = sqlx.ConnectContext(ctx, "postgres", serverURL)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	defer admin.Close()
+	if _, err = admin.Exec("DROP DATABASE IF EXISTS " + templateDatabase); err != nil {
+		return "", fmt.Errorf("failed to drop an earlier template database: %w", err)
+	}
+	if _, err = admin.Exec("CREATE DATABASE " + templateDatabase); err != nil {
+		return "", fmt.Errorf("failed to create the template database: %w", err)
+	}
+	addCleanup(func() { dropDatabase(templateDatabase) })
+
+	// A template must have no connections while it is copied, so this one is closed before any test
+	template, err := sqlx.Connect("postgres", databaseURL(serverURL, templateDatabase))
+	if err != nil {
+		return "", fmt.Errorf("failed to connect to the template database: %w", err)
+	}
+	defer template.Close()
+	if err = models.MigrateDatabase(template); err != nil {
+		return "", fmt.Errorf("failed to migrate the template database: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), startTimeout)
+	defer cancel()
+	if err = models.SeedDatabase(ctx, template); err != nil {
+		return "", fmt.Errorf("failed to seed the template database: %w", err)
+	}
+	return serverURL, nil
+}
+
+// databaseURL returns serverURL naming database instead.
+func databaseURL(serverURL, database string) string {
+	parsed, err := url.Parse(serverURL)
+	if err != nil {
+		return serverURL
+	}
+	parsed.Path = "/" + database
+	return parsed.String()
+}
+
+// dropDatabase drops a database created by the fixtures, reporting a failure on stderr.
+func dropDatabase(name string) {
+	admin, err := sqlx.Connect("postgres", postgresURL)
+	if err == nil {
+		defer admin.Close()
+		_, err = admin.Exec("DROP DATABASE IF EXISTS " + name + " WITH (FORCE)")
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "testutil: failed to drop database %s: %v\n", name, err)
+	}
+}
+
+// Redis returns a client of an empty Redis database, closed when the test ends. The database is
+// shared by the tests of the binary and emptied for each, so tests using it must not run in
+// parallel; nor may packages sharing a server named by INTEGRATION_REDIS_URL, so run them with -p 1.
+func Redis(t testing.TB) *redis.Client {
+	t.Helper()
+	redisOnce.Do(func() { redisURL, redisErr = startRedis() })
+	if redisErr != nil {
+		t.Fatalf("Redis is unavailable: %v", redisErr)
+	}
+	options, err := redis.ParseURL(redisURL)
+	if err != nil {
+		t.Fatalf("Invalid Redis URL: %v", err)
+	}
+	client := redis.NewClient(options)
+	t.Cleanup(func() { _ = client.Close() })
+	if err = client.FlushDB(context.Background()).Err(); err != nil {
+		t.Fatalf("Failed to empty Redis: %v", err)
+	}
+	return client
+}
+
+// startRedis starts Redis, or uses the server named by INTEGRATION_REDIS_URL, returning its URL.
+func startRedis() (string, error) {
+	serverURL := os.Getenv(RedisURLEnv)
+	if serverURL == "" {
+		var err error
+		if serverURL, err = startRedisContainer(); err != nil {
+			return "", err
+		}
+	}
+	options, err := redis.ParseURL(serverURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid %s: %w", RedisURLEnv, err)
+	}
+	client := redis.NewClient(options)
+	defer client.Close()
+	return serverURL, waitFor("Redis", func(ctx context.Context) error { return client.Ping(ctx).Err() })
+}
+
+// MCPClient calls an MCP server over HTTP, as a client of /mcp does.
+type MCPClient struct {
+	URL    string // of the /mcp endpoint
+	nextID atomic.Int64
+}
+
+// NewMCPServer serves /mcp from an MCP server with the tool and resource handlers of the server
+// binary, answering from db and caching in redisClient, which may be nil. The server is closed when
+// the test ends.
+func NewMCPServer(t testing.TB, db *sqlx.DB, redisClient *redis.Client) *MCPClient {
+	t.Helper()
+	guidelines, err := data.LoadBJCPGuidelines()
+	if err != nil {
+		t.Fatalf("Failed to load BJCP data: %v", err)
+	}
+	ingredientService, err := data.NewIngredientService()
+	if err != nil {
+		t.Fatalf("Failed to load ingredient data: %v", err)
+	}
+	bjcpData := guidelines[data.DefaultGuidelineVersion]
+	bjcpService := data.NewBJCPServiceFromGuidelines(guidelines)
+	beerService := services.NewBeerService(db, redisClient)
+	breweryService := services.NewBreweryService(db, redisClient)
+	relevance := models.HasTrigramSearch(db)
+	beerService.EnableRelevanceSearch(relevance)
+	breweryService.EnableRelevanceSearch(relevance)
+	breweryService.EnableDistanceSearch(models.HasDistanceSearch(db))
+
+	toolHandlers := handlers.NewToolHandlers(bjcpData, beerService, breweryService)
+	toolHandlers.SetBJCPService(bjcpService)
+	toolHandlers.SetIngredientService(ingredientService)
+	resourceHandlers := handlers.NewResourceHandlers(bjcpData, beerService, breweryService)
+	resourceHandlers.SetBJCPService(bjcpService)
+	resourceHandlers.SetIngredientService(ingredientService)
+	resourceHandlers.SetCache(redisClient, 0)
+	mcpServer := mcp.NewServer(toolHandlers, resourceHandlers, nil)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/mcp", mcpServer.HandleHTTP)
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return &MCPClient{URL: server.URL + "/mcp"}
+}
+
+// Call sends a request for method with params and returns the response, failing the test when the
+// exchange fails at the HTTP level. An MCP error is left in the response's Error.
+func (c *MCPClient) Call(t testing.TB, method string, params interface{}) *mcp.Message {
+	t.Helper()
+	request := mcp.NewMessage(method, params)
+	request.ID = c.nextID.Add(1)
+	body, err := json.Marshal(request)
+	if err != nil {
+		t.Fatalf("Failed to marshal %s request: %v", method, err)
+	}
+	resp, err := http.Post(c.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("%s request failed: %v", method, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("%s request returned %s", method, resp.Status)
+	}
+	var response mcp.Message
+	if err = json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode %s response: %v", method, err)
+	}
+	return &response
+}
+
+// CallTool calls the tool name with args and returns the text of its content, failing the test on
+// an MCP error.
+func (c *MCPClient) CallTool(t testing.TB, name string, args map[string]interface{}) []string {
+	t.Helper()
+	var result mcp.ToolResult
+	c.result(t, c.Call(t, "tools/call", mcp.CallToolRequest{Name: name, Arguments: args}), &result)
+	texts := make([]string, 0, len(result.Content))
+	for _, content := range result.Content {
+		texts = append(texts, content.Text)
+	}
+	return texts
+}
+
+// ReadResource reads the resource at uri and returns its text, failing the test on an MCP error.
+func (c *MCPClient) ReadResource(t testing.TB, uri string) string {
+	t.Helper()
+	var result struct {
+		Contents []mcp.ResourceContent `json:"contents"`
+	}
+	c.result(t, c.Call(t, "resources/read", mcp.ReadResourceRequest{URI: uri}), &result)
+	if len(result.Contents) != 1 {
+		t.Fatalf("Expected one content item for %s, got %d", uri, len(result.Contents))
+	}
+	return result.Contents[0].Text
+}
+
+// result decodes the result of response into v, failing the test on an MCP error.
+func (c *MCPClient) result(t testing.TB, response *mcp.Message, v interface{}) {
+	t.Helper()
+	if response.Error != nil {
+		t.Fatalf("Unexpected MCP error %d: %s", response.Error.Code, response.Error.Message)
+	}
+	raw, err := json.Marshal(response.Result)
+	if err == nil {
+		err = json.Unmarshal(raw, v)
+	}
+	if err != nil {
+		t.Fatalf("Failed to decode the result: %v", err)
+	}
+}
@@ -0,0 +1,165 @@
+// Package units renders brewing quantities in tool output in the unit system a client prefers.
+// Quantities are given in the US units the brewing calculations work in, and colours and gravities,
+// which BJCP publishes in SRM and specific gravity, gain their EBC and Plato equivalents.
+package units
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/CharlRitter/brewsource-mcp/app/pkg/brewing"
+)
+
+// usRegions are the regions of the locales whose customary units are US units; every other
+// region measures in metric.
+var usRegions = []string{"US", "LR", "MM"}
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying the unit system the client prefers, used by tools
+// when a call does not name one.
+func NewContext(ctx context.Context, system brewing.UnitSystem) context.Context {
+	return context.WithValue(ctx, contextKey{}, system)
+}
+
+// FromContext returns the unit system carried by ctx, or the zero UnitSystem when there is none.
+func FromContext(ctx context.Context) brewing.UnitSystem {
+	system, _ := ctx.Value(contextKey{}).(brewing.UnitSystem)
+	return system
+}
+
+// Parse reads a unit system name, ignoring case and surrounding space. An empty name is the zero
+// UnitSystem, leaving the choice to the caller.
+func Parse(name string) (brewing.UnitSystem, error) {
+	switch system := brewing.UnitSystem(strings.ToLower(strings.TrimSpace(name))); system {
+	case "", brewing.UnitsImperial, brewing.UnitsMetric:
+		return system, nil
+	default:
+		return "", errors.New("units must be 'imperial' or 'metric'")
+	}
+}
+
+// ForLocale returns the customary unit system of a BCP 47 or POSIX locale such as en-US or
+// de_DE.UTF-8: imperial in the United States, Liberia, and Myanmar, and metric elsewhere. A locale
+// without a region, such as en, has no customary system, and the zero UnitSystem is returned.
+func ForLocale(locale string) brewing.UnitSystem {
+	locale, _, _ = strings.Cut(locale, ".") // a POSIX codeset, and any modifier after it
+	locale, _, _ = strings.Cut(locale, "@")
+	subtags := strings.FieldsFunc(locale, func(r rune) bool { return r == '-' || r == '_' })
+	for _, subtag := range subtags[min(1, len(subtags)):] {
+		if !isRegion(subtag) {
+			continue
+		}
+		for _, region := range usRegions {
+			if strings.EqualFold(subtag, region) {
+				return brewing.UnitsImperial
+			}
+		}
+		return brewing.UnitsMetric
+	}
+	return ""
+}
+
+// isRegion reports whether a locale subtag after the language is a region: two letters, or three
+// digits such as 419 for Latin America.
+func isRegion(subtag string) bool {
+	if len(subtag) == 3 {
+		return strings.Trim(subtag, "0123456789") == ""
+	}
+	return len(subtag) == 2
+}
+
+// Formatter renders volumes, temperatures, and weights in a unit system, and names the units of
+// the values callers give in it.
+type Formatter struct {
+	metric bool
+}
+
+// New returns a Formatter for system; the zero UnitSystem renders US units.
+func New(system brewing.UnitSystem) Formatter {
+	return Formatter{metric: system == brewing.UnitsMetric}
+}
+
+// Metric reports whether f renders metric units.
+func (f Formatter) Metric() bool {
+	return f.metric
+}
+
+// Temperature renders a temperature given in °F, such as "152.0°F" or "66.7°C".
+func (f Formatter) Temperature(fahrenheit float64) string {
+	if f.metric {
+		return fmt.Sprintf("%.1f°C", brewing.FahrenheitToCelsius(fahrenheit))
+	}
+	return fmt.Sprintf("%.1f°F", fahrenheit)
+}
+
+// TemperatureRange renders a range given in °F in whole degrees, followed by the other system's,
+// such as "48-59°F (9-15°C)" or "9-15°C (48-59°F)".
+func (f Formatter) TemperatureRange(minF, maxF float64) string {
+	fahrenheit := fmt.Sprintf("%.0f-%.0f°F", minF, maxF)
+	celsius := fmt.Sprintf("%.0f-%.0f°C", brewing.FahrenheitToCelsius(minF), brewing.FahrenheitToCelsius(maxF))
+	if f.metric {
+		return fmt.Sprintf("%s (%s)", celsius, fahrenheit)
+	}
+	return fmt.Sprintf("%s (%s)", fahrenheit, celsius)
+}
+
+// Volume renders a volume given in US gallons, such as "5.00 gal" or "18.93 L".
+func (f Formatter) Volume(gallons float64) string {
+	if f.metric {
+		return fmt.Sprintf("%.2f L", gallons*brewing.LitersPerGallon)
+	}
+	return fmt.Sprintf("%.2f gal", gallons)
+}
+
+// VolumeUnit is the unit of Volume, for values the caller gave in it: "gal" or "L".
+func (f Formatter) VolumeUnit() string {
+	if f.metric {
+		return "L"
+	}
+	return "gal"
+}
+
+// WeightUnit is the unit of grain bill weights the caller gave: "lb" or "kg".
+func (f Formatter) WeightUnit() string {
+	if f.metric {
+		return "kg"
+	}
+	return "lb"
+}
+
+// SmallWeight renders a hop or sugar weight given in ounces, such as "1.50 oz" or "42.5 g".
+func (f Formatter) SmallWeight(ounces float64) string {
+	if f.metric {
+		return fmt.Sprintf("%.1f g", ounces*brewing.GramsPerOunce)
+	}
+	return fmt.Sprintf("%.2f oz", ounces)
+}
+
+// Serving renders a serving size given in US fluid ounces, such as "12 oz" or "355 ml".
+func (f Formatter) Serving(fluidOunces float64) string {
+	if f.metric {
+		return fmt.Sprintf("%.0f ml", fluidOunces*brewing.MillilitersPerFluidOunce)
+	}
+	return fmt.Sprintf("%.0f oz", fluidOunces)
+}
+
+// Color renders a colour in SRM with its EBC equivalent, such as "6.0 (11.8 EBC)".
+func Color(srm float64) string {
+	return fmt.Sprintf("%.1f (%.1f EBC)", srm, brewing.SRMToEBC(srm))
+}
+
+// ColorRange renders a colour range in SRM with its EBC equivalent, such as "4.0 - 6.0 (7.9 - 11.8 EBC)".
+func ColorRange(minSRM, maxSRM float64) string {
+	return fmt.Sprintf("%.1f - %.1f (%.1f - %.1f EBC)", minSRM, maxSRM,
+		brewing.SRMToEBC(minSRM), brewing.SRMToEBC(maxSRM))
+}
+
+// GravityRange renders a specific gravity range with its Plato equivalent, such as
+// "1.044 - 1.052 (11.0 - 12.9 °P)".
+func GravityRange(minSG, maxSG float64) string {
+	return fmt.Sprintf("%.3f - %.3f (%.1f - %.1f °P)", minSG, maxSG,
+		brewing.GravityToPlato(minSG), brewing.GravityToPlato(maxSG))
+}
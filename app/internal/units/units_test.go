@@ -0,0 +1,94 @@
+package units_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/units"
+	"github.com/CharlRitter/brewsource-mcp/app/pkg/brewing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    brewing.UnitSystem
+		wantErr bool
+	}{
+		{"", "", false},
+		{"imperial", brewing.UnitsImperial, false},
+		{" Metric ", brewing.UnitsMetric, false},
+		{"si", "", true},
+	}
+	for _, tt := range tests {
+		got, err := units.Parse(tt.name)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("Parse(%q) = %q, %v; want %q, error %v", tt.name, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestForLocale(t *testing.T) {
+	tests := []struct {
+		locale string
+		want   brewing.UnitSystem
+	}{
+		{"en-US", brewing.UnitsImperial},
+		{"en_us", brewing.UnitsImperial},
+		{"my-MM", brewing.UnitsImperial},
+		{"en-GB", brewing.UnitsMetric},
+		{"de_DE.UTF-8", brewing.UnitsMetric},
+		{"zh-Hant-TW", brewing.UnitsMetric},
+		{"es-419", brewing.UnitsMetric},
+		{"en", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := units.ForLocale(tt.locale); got != tt.want {
+			t.Errorf("ForLocale(%q) = %q, want %q", tt.locale, got, tt.want)
+		}
+	}
+}
+
+func TestContext(t *testing.T) {
+	if got := units.FromContext(context.Background()); got != "" {
+		t.Errorf("Expected no unit system, got %q", got)
+	}
+	ctx := units.NewContext(context.Background(), brewing.UnitsMetric)
+	if got := units.FromContext(ctx); got != brewing.UnitsMetric {
+		t.Errorf("Expected metric, got %q", got)
+	}
+}
+
+// Test the rendered strings, which tool output and its tests depend on.
+func TestFormatter(t *testing.T) {
+	imperial, metric := units.New(""), units.New(brewing.UnitsMetric)
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"temperature", imperial.Temperature(152), "152.0°F"},
+		{"metric temperature", metric.Temperature(152), "66.7°C"},
+		{"temperature range", imperial.TemperatureRange(48, 59), "48-59°F (9-15°C)"},
+		{"metric temperature range", metric.TemperatureRange(48, 59), "9-15°C (48-59°F)"},
+		{"volume", imperial.Volume(5), "5.00 gal"},
+		{"metric volume", metric.Volume(5), "18.93 L"},
+		{"volume unit", imperial.VolumeUnit() + " " + metric.VolumeUnit(), "gal L"},
+		{"weight unit", imperial.WeightUnit() + " " + metric.WeightUnit(), "lb kg"},
+		{"small weight", imperial.SmallWeight(1.5), "1.50 oz"},
+		{"metric small weight", metric.SmallWeight(1.5), "42.5 g"},
+		{"serving", imperial.Serving(12), "12 oz"},
+		{"metric serving", metric.Serving(12), "355 ml"},
+		{"colour", units.Color(6), "6.0 (11.8 EBC)"},
+		{"colour range", units.ColorRange(4, 6), "4.0 - 6.0 (7.9 - 11.8 EBC)"},
+		{"gravity range", units.GravityRange(1.044, 1.052), "1.044 - 1.052 (11.0 - 12.9 °P)"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, tt.got, tt.want)
+		}
+	}
+	if imperial.Metric() || !metric.Metric() {
+		t.Errorf("Expected only the metric formatter to be metric")
+	}
+}
@@ -0,0 +1,96 @@
+// Package version reports the version metadata of the running Brewsource MCP binary.
+package version
+
+import (
+	"os"
+	"runtime"
+	"runtime/debug"
+	"strings"
+)
+
+// Build metadata, set at link time, e.g.
+//
+//	go build -ldflags "-X github.com/CharlRitter/brewsource-mcp/app/internal/version.Version=1.2.0"
+var (
+	// Version is the release version.
+	Version string
+	// Commit is the VCS revision the binary was built from.
+	Commit string
+	// BuildDate is when the binary was built, in RFC 3339 form.
+	BuildDate string
+)
+
+// Dev is the version reported when none is known.
+const Dev = "dev"
+
+// versionFile is read for the version of binaries built without -ldflags; the container image
+// ships it next to the binary.
+const versionFile = "VERSION"
+
+// Info is the version metadata of a build.
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit,omitempty"`
+	BuildDate string `json:"build_date,omitempty"`
+	GoVersion string `json:"go_version"`
+}
+
+// Get returns the version metadata of the running binary. A version not set with -ldflags is read
+// from the VERSION file in the working directory, if there is one, and otherwise falls back, like
+// the commit and build date, to the build information Go records in the binary.
+func Get() Info {
+	buildInfo, _ := debug.ReadBuildInfo()
+	info := FromBuildInfo(buildInfo)
+	if Version == "" {
+		if contents, err := os.ReadFile(versionFile); err == nil && strings.TrimSpace(string(contents)) != "" {
+			info.Version = strings.TrimSpace(string(contents))
+		}
+	}
+	return info
+}
+
+// FromBuildInfo returns the -ldflags metadata, filling what was not set from buildInfo: the main
+// module version, unless it is a development build, and the VCS revision and commit time stamped by
+// go build. buildInfo may be nil. The version is Dev when neither gives one.
+func FromBuildInfo(buildInfo *debug.BuildInfo) Info {
+	info := Info{Version: Version, Commit: Commit, BuildDate: BuildDate, GoVersion: runtime.Version()}
+	if buildInfo != nil {
+		if info.Version == "" && buildInfo.Main.Version != "(devel)" {
+			info.Version = buildInfo.Main.Version
+		}
+		if buildInfo.GoVersion != "" {
+			info.GoVersion = buildInfo.GoVersion
+		}
+		for _, setting := range buildInfo.Settings {
+			switch {
+			case setting.Key == "vcs.revision" && info.Commit == "":
+				info.Commit = setting.Value
+			case setting.Key == "vcs.time" && info.BuildDate == "":
+				info.BuildDate = setting.Value
+			}
+		}
+	}
+	if info.Version == "" {
+		info.Version = Dev
+	}
+	return info
+}
+
+// String describes the build on one line, e.g. "1.2.0 (commit abc1234, built 2025-01-02T03:04:05Z,
+// go1.24.7)", leaving out what is unknown.
+func (i Info) String() string {
+	details := []string{}
+	if i.Commit != "" {
+		details = append(details, "commit "+i.Commit)
+	}
+	if i.BuildDate != "" {
+		details = append(details, "built "+i.BuildDate)
+	}
+	if i.GoVersion != "" {
+		details = append(details, i.GoVersion)
+	}
+	if len(details) == 0 {
+		return i.Version
+	}
+	return i.Version + " (" + strings.Join(details, ", ") + ")"
+}
@@ -0,0 +1,102 @@
+package version_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"testing"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/version"
+)
+
+// setLinkerFlags sets the -ldflags variables for the test, restoring them afterwards.
+func setLinkerFlags(t *testing.T, v, commit, buildDate string) {
+	t.Helper()
+	saved := [3]string{version.Version, version.Commit, version.BuildDate}
+	t.Cleanup(func() { version.Version, version.Commit, version.BuildDate = saved[0], saved[1], saved[2] })
+	version.Version, version.Commit, version.BuildDate = v, commit, buildDate
+}
+
+func stampedBuildInfo(mainVersion string) *debug.BuildInfo {
+	return &debug.BuildInfo{
+		GoVersion: "go1.24.7",
+		Main:      debug.Module{Path: "github.com/CharlRitter/brewsource-mcp", Version: mainVersion},
+		Settings: []debug.BuildSetting{
+			{Key: "vcs", Value: "git"},
+			{Key: "vcs.revision", Value: "abc1234"},
+			{Key: "vcs.time", Value: "2025-01-02T03:04:05Z"},
+		},
+	}
+}
+
+func TestFromBuildInfo(t *testing.T) {
+	tests := []struct {
+		name      string
+		flags     [3]string
+		buildInfo *debug.BuildInfo
+		expected  version.Info
+	}{
+		{
+			name:      "falls back to the build info",
+			buildInfo: stampedBuildInfo("v1.2.0"),
+			expected: version.Info{
+				Version: "v1.2.0", Commit: "abc1234", BuildDate: "2025-01-02T03:04:05Z", GoVersion: "go1.24.7",
+			},
+		},
+		{
+			name:      "a development build has no module version",
+			buildInfo: stampedBuildInfo("(devel)"),
+			expected: version.Info{
+				Version: version.Dev, Commit: "abc1234", BuildDate: "2025-01-02T03:04:05Z", GoVersion: "go1.24.7",
+			},
+		},
+		{
+			name:     "no build info",
+			expected: version.Info{Version: version.Dev, GoVersion: runtime.Version()},
+		},
+		{
+			name:      "linker flags take precedence",
+			flags:     [3]string{"1.3.0", "def5678", "2025-02-03T04:05:06Z"},
+			buildInfo: stampedBuildInfo("v1.2.0"),
+			expected: version.Info{
+				Version: "1.3.0", Commit: "def5678", BuildDate: "2025-02-03T04:05:06Z", GoVersion: "go1.24.7",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setLinkerFlags(t, tt.flags[0], tt.flags[1], tt.flags[2])
+			if got := version.FromBuildInfo(tt.buildInfo); got != tt.expected {
+				t.Errorf("Expected %+v, got %+v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestGet_VersionFile(t *testing.T) {
+	setLinkerFlags(t, "", "", "")
+	t.Chdir(t.TempDir())
+	if err := os.WriteFile(filepath.Join(".", "VERSION"), []byte("1.1.5\n"), 0o600); err != nil {
+		t.Fatalf("Failed to write VERSION: %v", err)
+	}
+	if got := version.Get().Version; got != "1.1.5" {
+		t.Errorf("Expected the VERSION file's 1.1.5, got %q", got)
+	}
+
+	setLinkerFlags(t, "2.0.0", "", "")
+	if got := version.Get().Version; got != "2.0.0" {
+		t.Errorf("Expected the linker flag version to win over the VERSION file, got %q", got)
+	}
+}
+
+func TestInfo_String(t *testing.T) {
+	info := version.Info{Version: "1.2.0", Commit: "abc1234", BuildDate: "2025-01-02T03:04:05Z", GoVersion: "go1.24.7"}
+	if got, expected := info.String(), "1.2.0 (commit abc1234, built 2025-01-02T03:04:05Z, go1.24.7)"; got != expected {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+	if got := (version.Info{Version: "dev"}).String(); got != "dev" {
+		t.Errorf("Expected just the version, got %q", got)
+	}
+}
@@ -0,0 +1,95 @@
+// Package brewing provides brewing calculations and unit conversions for Brewsource MCP.
+package brewing
+
+import (
+	"fmt"
+)
+
+const (
+	// abvFactor converts an OG-FG gravity drop to percent ABV.
+	abvFactor = 131.25
+	// ethanolDensity is the specific gravity of ethanol, used to convert ABV to ABW.
+	ethanolDensity = 0.79
+	// minGravity and maxGravity bound the gravities accepted by the ABV and attenuation calculations.
+	minGravity = 0.980
+	maxGravity = 1.200
+	// referenceServingOz is the serving size the calorie formula is expressed in.
+	referenceServingOz = 12
+)
+
+// ABVCalculation estimates alcohol content from original and final gravity.
+type ABVCalculation struct {
+	OriginalGravity float64
+	FinalGravity    float64
+}
+
+// SimpleABV returns percent alcohol by volume using (OG - FG) × 131.25.
+func (c ABVCalculation) SimpleABV() (float64, error) {
+	if err := validateGravities(c.OriginalGravity, c.FinalGravity); err != nil {
+		return 0, err
+	}
+	return (c.OriginalGravity - c.FinalGravity) * abvFactor, nil
+}
+
+// AlternateABV returns percent alcohol by volume using the formula that is more accurate for strong beers:
+// 76.08(OG - FG)/(1.775 - OG) × FG/0.794.
+func (c ABVCalculation) AlternateABV() (float64, error) {
+	if err := validateGravities(c.OriginalGravity, c.FinalGravity); err != nil {
+		return 0, err
+	}
+	return 76.08 * (c.OriginalGravity - c.FinalGravity) / (1.775 - c.OriginalGravity) *
+		(c.FinalGravity / 0.794), nil
+}
+
+// ABW returns percent alcohol by weight, converted from SimpleABV using the densities of ethanol and the beer.
+func (c ABVCalculation) ABW() (float64, error) {
+	abv, err := c.SimpleABV()
+	if err != nil {
+		return 0, err
+	}
+	return abv * ethanolDensity / c.FinalGravity, nil
+}
+
+// Calories estimates the kilocalories in volumeOz US fluid ounces of the beer, counting
+// both alcohol and residual extract:
+//
+//	alcohol = 1881.22 × FG × (OG - FG)/(1.775 - OG)
+//	extract = 3550.0 × FG × (0.1808 × OG + 0.8192 × FG - 1.0004)
+//
+// Both terms are per 12 oz and are scaled to volumeOz.
+func (c ABVCalculation) Calories(volumeOz float64) (float64, error) {
+	if err := validateGravities(c.OriginalGravity, c.FinalGravity); err != nil {
+		return 0, err
+	}
+	if volumeOz <= 0 {
+		return 0, &ValidationError{Field: "serving_volume", Value: volumeOz, Constraint: "must be greater than zero"}
+	}
+	og, fg := c.OriginalGravity, c.FinalGravity
+	alcohol := 1881.22 * fg * (og - fg) / (1.775 - og)
+	extract := 3550.0 * fg * (realExtractOriginalWeight*og + realExtractApparentWeight*fg - 1.0004)
+	return (alcohol + extract) * volumeOz / referenceServingOz, nil
+}
+
+func validateGravities(og, fg float64) error {
+	switch {
+	case og < 1 || og > maxGravity:
+		return &ValidationError{
+			Field:      "original_gravity",
+			Value:      og,
+			Constraint: fmt.Sprintf("must be between 1.000 and %.3f", maxGravity),
+		}
+	case fg < minGravity || fg > maxGravity:
+		return &ValidationError{
+			Field:      "final_gravity",
+			Value:      fg,
+			Constraint: fmt.Sprintf("must be between %.3f and %.3f", minGravity, maxGravity),
+		}
+	case fg > og:
+		return &ValidationError{
+			Field:      "final_gravity",
+			Value:      fg,
+			Constraint: fmt.Sprintf("cannot be higher than original gravity %.3f", og),
+		}
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package brewing_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/CharlRitter/brewsource-mcp/app/pkg/brewing"
+)
+
+func TestABVCalculation(t *testing.T) {
+	tests := []struct {
+		name         string
+		og, fg       float64
+		wantABV      float64
+		wantAlt      float64
+		wantABW      float64
+		wantCalories float64 // per 12 oz
+	}{
+		{
+			name: "pale ale 1.050/1.010", og: 1.050, fg: 1.010,
+			wantABV: 5.25, wantAlt: 5.34, wantABW: 4.11, wantCalories: 165,
+		},
+		{
+			name: "light lager 1.040/1.008", og: 1.040, fg: 1.008,
+			wantABV: 4.20, wantAlt: 4.21, wantABW: 3.29, wantCalories: 130,
+		},
+		{
+			name: "strong ale 1.080/1.020", og: 1.080, fg: 1.020,
+			wantABV: 7.88, wantAlt: 8.44, wantABW: 6.10, wantCalories: 276,
+		},
+		{
+			name: "barleywine 1.100/1.025", og: 1.100, fg: 1.025,
+			wantABV: 9.84, wantAlt: 10.91, wantABW: 7.59, wantCalories: 353,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calc := brewing.ABVCalculation{OriginalGravity: tt.og, FinalGravity: tt.fg}
+			abv, err := calc.SimpleABV()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assertClose(t, abv, tt.wantABV, 0.01)
+			alt, _ := calc.AlternateABV()
+			assertClose(t, alt, tt.wantAlt, 0.01)
+			abw, _ := calc.ABW()
+			assertClose(t, abw, tt.wantABW, 0.01)
+			calories, _ := calc.Calories(12)
+			assertClose(t, calories, tt.wantCalories, 1)
+		})
+	}
+}
+
+func TestABVCalculation_CaloriesScaleWithVolume(t *testing.T) {
+	calc := brewing.ABVCalculation{OriginalGravity: 1.050, FinalGravity: 1.010}
+	twelve, _ := calc.Calories(12)
+	pint, _ := calc.Calories(16)
+	assertClose(t, pint, twelve*16/12, 1e-9)
+
+	if _, err := calc.Calories(0); err == nil {
+		t.Error("expected an error for a zero serving volume")
+	}
+}
+
+func TestABVCalculation_Validation(t *testing.T) {
+	tests := []struct {
+		name    string
+		og, fg  float64
+		wantErr string
+	}{
+		{name: "fg above og", og: 1.040, fg: 1.050, wantErr: "cannot be higher than original gravity"},
+		{name: "og below water", og: 0.990, fg: 0.990, wantErr: "original gravity"},
+		{name: "og too high", og: 1.250, fg: 1.020, wantErr: "original gravity"},
+		{name: "fg too low", og: 1.050, fg: 0.950, wantErr: "final gravity"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calc := brewing.ABVCalculation{OriginalGravity: tt.og, FinalGravity: tt.fg}
+			_, simpleErr := calc.SimpleABV()
+			_, altErr := calc.AlternateABV()
+			_, abwErr := calc.ABW()
+			_, calErr := calc.Calories(12)
+			for _, err := range []error{simpleErr, altErr, abwErr, calErr} {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+			}
+		})
+	}
+}
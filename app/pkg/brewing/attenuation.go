@@ -0,0 +1,53 @@
+// Package brewing provides brewing calculations and unit conversions for Brewsource MCP.
+package brewing
+
+// realExtractOriginalWeight and realExtractApparentWeight are Balling's coefficients for real extract.
+const (
+	realExtractOriginalWeight = 0.1808
+	realExtractApparentWeight = 0.8192
+)
+
+// AttenuationCalculation measures how much of the wort's extract the yeast fermented.
+type AttenuationCalculation struct {
+	OriginalGravity float64
+	FinalGravity    float64
+}
+
+// AttenuationResult holds apparent and real attenuation alongside the alcohol and calorie estimates.
+type AttenuationResult struct {
+	ApparentAttenuation float64 `json:"apparent_attenuation"`
+	RealAttenuation     float64 `json:"real_attenuation"`
+	RealExtract         float64 `json:"real_extract"` // °Plato
+	ABV                 float64 `json:"abv"`
+	ABW                 float64 `json:"abw"`
+	CaloriesPer12Oz     float64 `json:"calories_per_12oz"`
+}
+
+// Calculate returns apparent attenuation, (OG - FG)/(OG - 1), and real attenuation, which corrects
+// the final reading for the alcohol that makes it read low.
+func (c AttenuationCalculation) Calculate() (*AttenuationResult, error) {
+	abvCalc := ABVCalculation(c)
+	abv, err := abvCalc.SimpleABV()
+	if err != nil {
+		return nil, err
+	}
+	abw, err := abvCalc.ABW()
+	if err != nil {
+		return nil, err
+	}
+	calories, err := abvCalc.Calories(referenceServingOz)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &AttenuationResult{ABV: abv, ABW: abw, CaloriesPer12Oz: calories}
+	if c.OriginalGravity == 1 {
+		return result, nil
+	}
+	originalExtract := GravityToPlato(c.OriginalGravity)
+	apparentExtract := GravityToPlato(c.FinalGravity)
+	result.RealExtract = realExtractOriginalWeight*originalExtract + realExtractApparentWeight*apparentExtract
+	result.ApparentAttenuation = (c.OriginalGravity - c.FinalGravity) / (c.OriginalGravity - 1) * 100
+	result.RealAttenuation = (originalExtract - result.RealExtract) / originalExtract * 100
+	return result, nil
+}
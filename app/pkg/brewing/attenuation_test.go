@@ -0,0 +1,44 @@
+package brewing_test
+
+import (
+	"testing"
+
+	"github.com/CharlRitter/brewsource-mcp/app/pkg/brewing"
+)
+
+func TestAttenuationCalculation(t *testing.T) {
+	tests := []struct {
+		name         string
+		og, fg       float64
+		wantApparent float64
+		wantReal     float64
+		wantCalories float64
+	}{
+		{name: "1.050/1.010", og: 1.050, fg: 1.010, wantApparent: 80, wantReal: 65.0, wantCalories: 165},
+		{name: "1.048/1.012", og: 1.048, fg: 1.012, wantApparent: 75, wantReal: 60.8, wantCalories: 159},
+		{name: "unfermented", og: 1.050, fg: 1.050, wantApparent: 0, wantReal: 0, wantCalories: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := brewing.AttenuationCalculation{OriginalGravity: tt.og, FinalGravity: tt.fg}.Calculate()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assertClose(t, result.ApparentAttenuation, tt.wantApparent, 0.01)
+			assertClose(t, result.RealAttenuation, tt.wantReal, 0.1)
+			if result.RealAttenuation > result.ApparentAttenuation {
+				t.Errorf("real attenuation %.1f should not exceed apparent %.1f",
+					result.RealAttenuation, result.ApparentAttenuation)
+			}
+			if tt.wantCalories > 0 {
+				assertClose(t, result.CaloriesPer12Oz, tt.wantCalories, 1)
+			}
+		})
+	}
+}
+
+func TestAttenuationCalculation_Validation(t *testing.T) {
+	if _, err := (brewing.AttenuationCalculation{OriginalGravity: 1.040, FinalGravity: 1.050}).Calculate(); err == nil {
+		t.Error("expected an error when FG exceeds OG")
+	}
+}
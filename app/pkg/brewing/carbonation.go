@@ -0,0 +1,140 @@
+// Package brewing provides brewing calculations and unit conversions for Brewsource MCP.
+package brewing
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	// MinCO2Volumes is the lowest carbonation target accepted by CarbonationCalculation.
+	MinCO2Volumes = 0.5
+	// MaxCO2Volumes is the highest carbonation target accepted by CarbonationCalculation.
+	MaxCO2Volumes = 5.0
+	// dextroseGramsPerGallonVolume is the corn sugar (dextrose monohydrate) needed to add one volume
+	// of CO2 to a US gallon, from Tinseth's priming formula.
+	dextroseGramsPerGallonVolume = 15.195
+)
+
+// SugarType identifies a priming sugar.
+type SugarType string
+
+// Supported priming sugars.
+const (
+	SugarCorn  SugarType = "corn_sugar"
+	SugarTable SugarType = "table_sugar"
+	SugarDME   SugarType = "dme"
+	SugarHoney SugarType = "honey"
+)
+
+// sugarFactors is the weight of each sugar needed relative to corn sugar, based on fermentable
+// extract: sucrose ferments fully where dextrose monohydrate is 91% fermentable sugar, and DME and
+// honey yield 68% and 74% of the CO2 of sucrose.
+//
+//nolint:gochecknoglobals // static lookup table
+var sugarFactors = map[SugarType]float64{
+	SugarCorn:  1.0,
+	SugarTable: 0.91,
+	SugarDME:   0.91 / 0.68,
+	SugarHoney: 0.91 / 0.74,
+}
+
+// SugarTypes returns the supported priming sugars in sorted order.
+func SugarTypes() []string {
+	types := make([]string, 0, len(sugarFactors))
+	for sugar := range sugarFactors {
+		types = append(types, string(sugar))
+	}
+	sort.Strings(types)
+	return types
+}
+
+// ParseSugarType normalises a sugar name, accepting a few common aliases.
+func ParseSugarType(name string) (SugarType, error) {
+	normalized := strings.ReplaceAll(strings.ToLower(strings.TrimSpace(name)), " ", "_")
+	switch normalized {
+	case "", "corn", "dextrose":
+		return SugarCorn, nil
+	case "table", "sucrose", "cane_sugar":
+		return SugarTable, nil
+	case "dry_malt_extract":
+		return SugarDME, nil
+	}
+	if _, ok := sugarFactors[SugarType(normalized)]; ok {
+		return SugarType(normalized), nil
+	}
+	return "", fmt.Errorf("unknown sugar type %q; supported types: %s", name, strings.Join(SugarTypes(), ", "))
+}
+
+// CarbonationCalculation holds the inputs for bottle priming or keg force carbonation, in US units.
+type CarbonationCalculation struct {
+	BeerVolume       float64   // gallons
+	TargetCO2Volumes float64   // volumes of CO2
+	SugarType        SugarType // defaults to SugarCorn
+
+	// BeerTemp is in °F: the warmest temperature since fermentation finished when priming,
+	// or the serving temperature when force carbonating a keg.
+	BeerTemp float64
+}
+
+// CarbonationResult holds the priming sugar and keg pressure needed to reach the target.
+type CarbonationResult struct {
+	ResidualCO2 float64   `json:"residual_co2"`
+	SugarType   SugarType `json:"sugar_type"`
+	SugarGrams  float64   `json:"sugar_grams"`
+	SugarOunces float64   `json:"sugar_ounces"`
+	KegPSI      float64   `json:"keg_psi"`
+}
+
+// Calculate returns the priming sugar needed on top of the CO2 already dissolved in the beer,
+// along with the regulator pressure that would force carbonate it at BeerTemp.
+//
+// Residual CO2 uses 3.0378 - 0.050062T + 0.00026555T² with T in °F.
+func (c CarbonationCalculation) Calculate() (*CarbonationResult, error) {
+	if err := c.validate(); err != nil {
+		return nil, err
+	}
+	sugar := c.SugarType
+	if sugar == "" {
+		sugar = SugarCorn
+	}
+	factor, ok := sugarFactors[sugar]
+	if !ok {
+		return nil, &ValidationError{
+			Field:      "sugar_type",
+			Value:      sugar,
+			Constraint: "must be one of " + strings.Join(SugarTypes(), ", "),
+		}
+	}
+
+	result := &CarbonationResult{
+		ResidualCO2: ResidualCO2(c.BeerTemp),
+		SugarType:   sugar,
+		KegPSI:      CO2VolumesToPSI(c.TargetCO2Volumes, c.BeerTemp),
+	}
+	if needed := c.TargetCO2Volumes - result.ResidualCO2; needed > 0 {
+		result.SugarGrams = dextroseGramsPerGallonVolume * c.BeerVolume * needed * factor
+	}
+	result.SugarOunces = result.SugarGrams / GramsPerOunce
+	return result, nil
+}
+
+// ResidualCO2 returns the CO2 volumes left in solution after fermentation at the given temperature (°F).
+func ResidualCO2(tempF float64) float64 {
+	return 3.0378 - 0.050062*tempF + 0.00026555*tempF*tempF
+}
+
+func (c CarbonationCalculation) validate() error {
+	switch {
+	case c.BeerVolume <= 0:
+		return &ValidationError{Field: "beer_volume", Value: c.BeerVolume, Constraint: "must be greater than zero"}
+	case c.TargetCO2Volumes < MinCO2Volumes || c.TargetCO2Volumes > MaxCO2Volumes:
+		return &ValidationError{
+			Field:      "target_co2_volumes",
+			Value:      c.TargetCO2Volumes,
+			Constraint: fmt.Sprintf("must be between %.1f and %.1f", MinCO2Volumes, MaxCO2Volumes),
+		}
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package brewing_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/CharlRitter/brewsource-mcp/app/pkg/brewing"
+)
+
+func TestCarbonationCalculation_Priming(t *testing.T) {
+	// 5 gallons fermented at 68°F primed to 2.5 volumes: Tinseth's priming calculator gives 4.4 oz
+	// (124 g) of corn sugar and 4.0 oz (113 g) of table sugar.
+	tests := []struct {
+		name      string
+		sugar     brewing.SugarType
+		wantGrams float64
+		tolerance float64
+	}{
+		{name: "default corn sugar", sugar: "", wantGrams: 124, tolerance: 1},
+		{name: "table sugar", sugar: brewing.SugarTable, wantGrams: 113, tolerance: 1},
+		// DME and honey by their extract relative to table sugar, 68% and 74%
+		{name: "dme", sugar: brewing.SugarDME, wantGrams: 113.28 / 0.68, tolerance: 0.01},
+		{name: "honey", sugar: brewing.SugarHoney, wantGrams: 113.28 / 0.74, tolerance: 0.01},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calc := brewing.CarbonationCalculation{
+				BeerVolume:       5,
+				BeerTemp:         68,
+				TargetCO2Volumes: 2.5,
+				SugarType:        tt.sugar,
+			}
+			result, err := calc.Calculate()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assertClose(t, result.ResidualCO2, 0.8615, 0.0001)
+			assertClose(t, result.SugarGrams, tt.wantGrams, tt.tolerance)
+			assertClose(t, result.SugarOunces, result.SugarGrams/brewing.GramsPerOunce, 0.001)
+		})
+	}
+}
+
+func TestCarbonationCalculation_KegPressure(t *testing.T) {
+	result, err := brewing.CarbonationCalculation{BeerVolume: 5, BeerTemp: 38, TargetCO2Volumes: 2.5}.Calculate()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertClose(t, result.KegPSI, brewing.CO2VolumesToPSI(2.5, 38), 1e-9)
+	assertClose(t, result.KegPSI, 10.9, 0.5)
+}
+
+func TestCarbonationCalculation_ResidualExceedsTarget(t *testing.T) {
+	// Beer kept cold holds more CO2 than a low target needs.
+	result, err := brewing.CarbonationCalculation{BeerVolume: 5, BeerTemp: 35, TargetCO2Volumes: 1.0}.Calculate()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.SugarGrams != 0 {
+		t.Errorf("expected no priming sugar, got %.2f g", result.SugarGrams)
+	}
+}
+
+func TestCarbonationCalculation_Validation(t *testing.T) {
+	tests := []struct {
+		name    string
+		calc    brewing.CarbonationCalculation
+		wantErr string
+	}{
+		{
+			name:    "zero volume",
+			calc:    brewing.CarbonationCalculation{BeerVolume: 0, BeerTemp: 68, TargetCO2Volumes: 2.5},
+			wantErr: "beer volume",
+		},
+		{
+			name:    "target too low",
+			calc:    brewing.CarbonationCalculation{BeerVolume: 5, BeerTemp: 68, TargetCO2Volumes: 0.4},
+			wantErr: "between 0.5 and 5.0",
+		},
+		{
+			name:    "target too high",
+			calc:    brewing.CarbonationCalculation{BeerVolume: 5, BeerTemp: 68, TargetCO2Volumes: 5.1},
+			wantErr: "between 0.5 and 5.0",
+		},
+		{
+			name: "unknown sugar",
+			calc: brewing.CarbonationCalculation{
+				BeerVolume: 5, BeerTemp: 68, TargetCO2Volumes: 2.5, SugarType: "maple",
+			},
+			wantErr: "sugar type must be one of",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.calc.Calculate()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestParseSugarType(t *testing.T) {
+	tests := map[string]brewing.SugarType{
+		"":            brewing.SugarCorn,
+		"Dextrose":    brewing.SugarCorn,
+		"table sugar": brewing.SugarTable,
+		"sucrose":     brewing.SugarTable,
+		"DME":         brewing.SugarDME,
+		"honey":       brewing.SugarHoney,
+	}
+	for input, want := range tests {
+		got, err := brewing.ParseSugarType(input)
+		if err != nil || got != want {
+			t.Errorf("ParseSugarType(%q) = %q, %v; want %q", input, got, err, want)
+		}
+	}
+	if _, err := brewing.ParseSugarType("maple syrup"); err == nil {
+		t.Error("expected error for unsupported sugar")
+	}
+}
@@ -0,0 +1,84 @@
+// Package brewing provides brewing calculations and unit conversions for Brewsource MCP.
+package brewing
+
+import (
+	"fmt"
+	"math"
+)
+
+// Morey's fit of beer colour to malt colour units.
+const (
+	moreyFactor   = 1.4922
+	moreyExponent = 0.6859
+)
+
+// ColorGrain is one fermentable in the grain bill.
+type ColorGrain struct {
+	Weight   float64 // pounds, or kilograms when the calculation is metric
+	Lovibond float64 // °L
+}
+
+// ColorCalculation estimates beer colour from the grain bill.
+type ColorCalculation struct {
+	Grains    []ColorGrain
+	BatchSize float64    // post-boil volume in gallons, or liters when metric
+	Units     UnitSystem // defaults to UnitsImperial
+}
+
+// ColorResult is the estimated colour of the beer.
+type ColorResult struct {
+	MCU float64 `json:"mcu"` // malt colour units: pounds × °L per gallon
+	SRM float64 `json:"srm"`
+	EBC float64 `json:"ebc"`
+}
+
+// Calculate estimates colour with the Morey equation, SRM = 1.4922 × MCU^0.6859, where MCU sums
+// each grain's weight in pounds times its colour in °L over the batch size in gallons. Unlike
+// MCU itself, which only tracks colour for pale beers, the fit holds up to about 50 SRM.
+func (c ColorCalculation) Calculate() (*ColorResult, error) {
+	if err := c.validate(); err != nil {
+		return nil, err
+	}
+	gallons, weightToPounds := c.BatchSize, 1.0
+	if c.Units == UnitsMetric {
+		gallons, weightToPounds = c.BatchSize/LitersPerGallon, 1000/GramsPerPound
+	}
+
+	colorUnits := 0.0
+	for _, grain := range c.Grains {
+		colorUnits += grain.Weight * weightToPounds * grain.Lovibond
+	}
+	result := &ColorResult{MCU: colorUnits / gallons}
+	result.SRM = moreyFactor * math.Pow(result.MCU, moreyExponent)
+	result.EBC = SRMToEBC(result.SRM)
+	return result, nil
+}
+
+func (c ColorCalculation) validate() error {
+	if err := c.Units.validate(); err != nil {
+		return err
+	}
+	if c.BatchSize <= 0 {
+		return &ValidationError{Field: "batch_size", Value: c.BatchSize, Constraint: "must be greater than zero"}
+	}
+	if len(c.Grains) == 0 {
+		return &ValidationError{Field: "grains", Value: 0, Constraint: "must include at least one grain"}
+	}
+	for i, grain := range c.Grains {
+		switch {
+		case grain.Weight <= 0:
+			return &ValidationError{
+				Field:      fmt.Sprintf("grains[%d].weight", i),
+				Value:      grain.Weight,
+				Constraint: "must be greater than zero",
+			}
+		case grain.Lovibond < 0:
+			return &ValidationError{
+				Field:      fmt.Sprintf("grains[%d].lovibond", i),
+				Value:      grain.Lovibond,
+				Constraint: "cannot be negative",
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package brewing_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/CharlRitter/brewsource-mcp/app/pkg/brewing"
+)
+
+func TestColorCalculation(t *testing.T) {
+	// 10 lb of 2 °L pale malt and 1 lb of crystal 60 in 5 gallons is 16 MCU, about 10 SRM.
+	imperial := brewing.ColorCalculation{
+		Grains:    []brewing.ColorGrain{{Weight: 10, Lovibond: 2}, {Weight: 1, Lovibond: 60}},
+		BatchSize: 5,
+	}
+	result, err := imperial.Calculate()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertClose(t, result.MCU, 16, 0.001)
+	assertClose(t, result.SRM, 10.0, 0.05)
+	assertClose(t, result.EBC, brewing.SRMToEBC(result.SRM), 0.001)
+
+	metric := brewing.ColorCalculation{
+		Grains: []brewing.ColorGrain{
+			{Weight: 10 * brewing.GramsPerPound / 1000, Lovibond: 2},
+			{Weight: brewing.GramsPerPound / 1000, Lovibond: 60},
+		},
+		BatchSize: 5 * brewing.LitersPerGallon,
+		Units:     brewing.UnitsMetric,
+	}
+	metricResult, err := metric.Calculate()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertClose(t, metricResult.SRM, result.SRM, 0.001)
+}
+
+func TestColorCalculation_Validation(t *testing.T) {
+	tests := []struct {
+		name  string
+		calc  brewing.ColorCalculation
+		field string
+	}{
+		{
+			"no batch size",
+			brewing.ColorCalculation{Grains: []brewing.ColorGrain{{Weight: 1, Lovibond: 2}}},
+			"batch_size",
+		},
+		{"no grains", brewing.ColorCalculation{BatchSize: 5}, "grains"},
+		{
+			"negative colour",
+			brewing.ColorCalculation{Grains: []brewing.ColorGrain{{Weight: 1, Lovibond: -2}}, BatchSize: 5},
+			"grains[0].lovibond",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.calc.Calculate()
+			var validationErr *brewing.ValidationError
+			if !errors.As(err, &validationErr) || validationErr.Field != tt.field {
+				t.Errorf("expected a validation error for %s, got %v", tt.field, err)
+			}
+		})
+	}
+}
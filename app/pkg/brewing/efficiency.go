@@ -0,0 +1,107 @@
+// Package brewing provides brewing calculations and unit conversions for Brewsource MCP.
+package brewing
+
+import "fmt"
+
+// maxPPG bounds the extract potential of a fermentable: pure sucrose yields 46 points per pound per
+// gallon, and nothing yields more.
+const maxPPG = 46
+
+// EfficiencyGrain is one fermentable in the grain bill, with its extract potential.
+type EfficiencyGrain struct {
+	Weight float64 // pounds, or kilograms when the calculation is metric
+	PPG    float64 // gravity points per pound per gallon at full extraction
+}
+
+// EfficiencyCalculation relates a grain bill to the gravity of the wort it makes.
+type EfficiencyCalculation struct {
+	Grains    []EfficiencyGrain
+	BatchSize float64    // volume into the fermenter in gallons, or liters when metric
+	Units     UnitSystem // defaults to UnitsImperial
+}
+
+// Efficiency returns the brewhouse efficiency, in percent, of a batch measured at originalGravity:
+// the gravity points reached over those the grain bill could give at full extraction.
+func (c EfficiencyCalculation) Efficiency(originalGravity float64) (float64, error) {
+	potential, err := c.potentialPoints()
+	if err != nil {
+		return 0, err
+	}
+	if originalGravity <= 1 || originalGravity > maxGravity {
+		return 0, &ValidationError{
+			Field:      "original_gravity",
+			Value:      originalGravity,
+			Constraint: fmt.Sprintf("must be greater than 1.000 and at most %.3f", maxGravity),
+		}
+	}
+	return (originalGravity - 1) * 1000 / potential * 100, nil
+}
+
+// OriginalGravity inverts Efficiency, predicting the original gravity the grain bill reaches at
+// efficiency percent.
+func (c EfficiencyCalculation) OriginalGravity(efficiency float64) (float64, error) {
+	potential, err := c.potentialPoints()
+	if err != nil {
+		return 0, err
+	}
+	if efficiency <= 0 || efficiency > 100 {
+		return 0, &ValidationError{Field: "efficiency", Value: efficiency, Constraint: "must be between 0 and 100"}
+	}
+	gravity := 1 + potential*efficiency/100/1000
+	if gravity > maxGravity {
+		return 0, &ValidationError{
+			Field:      "original_gravity",
+			Value:      gravity,
+			Constraint: fmt.Sprintf("must be at most %.3f; the grain bill is too large for the batch", maxGravity),
+		}
+	}
+	return gravity, nil
+}
+
+// potentialPoints is the gravity points per gallon of the batch at full extraction.
+func (c EfficiencyCalculation) potentialPoints() (float64, error) {
+	if err := c.validate(); err != nil {
+		return 0, err
+	}
+	gallons, weightToPounds := c.BatchSize, 1.0
+	if c.Units == UnitsMetric {
+		gallons, weightToPounds = c.BatchSize/LitersPerGallon, 1000/GramsPerPound
+	}
+	points := 0.0
+	for _, grain := range c.Grains {
+		points += grain.Weight * weightToPounds * grain.PPG
+	}
+	if points == 0 {
+		return 0, &ValidationError{Field: "grains", Value: 0, Constraint: "must include a grain that yields extract"}
+	}
+	return points / gallons, nil
+}
+
+func (c EfficiencyCalculation) validate() error {
+	if err := c.Units.validate(); err != nil {
+		return err
+	}
+	if c.BatchSize <= 0 {
+		return &ValidationError{Field: "batch_size", Value: c.BatchSize, Constraint: "must be greater than zero"}
+	}
+	if len(c.Grains) == 0 {
+		return &ValidationError{Field: "grains", Value: 0, Constraint: "must include at least one grain"}
+	}
+	for i, grain := range c.Grains {
+		switch {
+		case grain.Weight <= 0:
+			return &ValidationError{
+				Field:      fmt.Sprintf("grains[%d].weight", i),
+				Value:      grain.Weight,
+				Constraint: "must be greater than zero",
+			}
+		case grain.PPG < 0 || grain.PPG > maxPPG:
+			return &ValidationError{
+				Field:      fmt.Sprintf("grains[%d].ppg", i),
+				Value:      grain.PPG,
+				Constraint: fmt.Sprintf("must be between 0 and %d", maxPPG),
+			}
+		}
+	}
+	return nil
+}
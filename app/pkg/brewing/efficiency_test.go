@@ -0,0 +1,81 @@
+package brewing_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/CharlRitter/brewsource-mcp/app/pkg/brewing"
+)
+
+func TestEfficiencyCalculation(t *testing.T) {
+	// 10 lb of 37 PPG pale malt and 1 lb of 34 PPG crystal in 5 gallons could reach 80.8 points;
+	// at 75% that is 1.0606.
+	imperial := brewing.EfficiencyCalculation{
+		Grains:    []brewing.EfficiencyGrain{{Weight: 10, PPG: 37}, {Weight: 1, PPG: 34}},
+		BatchSize: 5,
+	}
+	og, err := imperial.OriginalGravity(75)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertClose(t, og, 1.0606, 0.0001)
+
+	efficiency, err := imperial.Efficiency(og)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertClose(t, efficiency, 75, 0.001)
+
+	metric := brewing.EfficiencyCalculation{
+		Grains: []brewing.EfficiencyGrain{
+			{Weight: 10 * brewing.GramsPerPound / 1000, PPG: 37},
+			{Weight: brewing.GramsPerPound / 1000, PPG: 34},
+		},
+		BatchSize: 5 * brewing.LitersPerGallon,
+		Units:     brewing.UnitsMetric,
+	}
+	metricOG, err := metric.OriginalGravity(75)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertClose(t, metricOG, og, 0.00001)
+}
+
+func TestEfficiencyCalculation_Validation(t *testing.T) {
+	grains := []brewing.EfficiencyGrain{{Weight: 10, PPG: 37}}
+	tests := []struct {
+		name       string
+		calc       brewing.EfficiencyCalculation
+		efficiency float64
+		field      string
+	}{
+		{"no batch size", brewing.EfficiencyCalculation{Grains: grains}, 75, "batch_size"},
+		{"no grains", brewing.EfficiencyCalculation{BatchSize: 5}, 75, "grains"},
+		{
+			"no extract",
+			brewing.EfficiencyCalculation{Grains: []brewing.EfficiencyGrain{{Weight: 1}}, BatchSize: 5},
+			75, "grains",
+		},
+		{
+			"impossible yield",
+			brewing.EfficiencyCalculation{Grains: []brewing.EfficiencyGrain{{Weight: 1, PPG: 60}}, BatchSize: 5},
+			75, "grains[0].ppg",
+		},
+		{"no efficiency", brewing.EfficiencyCalculation{Grains: grains, BatchSize: 5}, 0, "efficiency"},
+		{"too much grain", brewing.EfficiencyCalculation{Grains: grains, BatchSize: 0.5}, 75, "original_gravity"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.calc.OriginalGravity(tt.efficiency)
+			var validationErr *brewing.ValidationError
+			if !errors.As(err, &validationErr) || validationErr.Field != tt.field {
+				t.Errorf("expected a validation error for %s, got %v", tt.field, err)
+			}
+		})
+	}
+
+	_, err := brewing.EfficiencyCalculation{Grains: grains, BatchSize: 5}.Efficiency(1.000)
+	if err == nil || err.Error() != "original gravity must be greater than 1.000 and at most 1.200 (got 1)" {
+		t.Errorf("expected a gravity of 1.000 to be rejected, got %v", err)
+	}
+}
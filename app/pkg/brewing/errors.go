@@ -0,0 +1,20 @@
+// Package brewing provides brewing calculations and unit conversions for Brewsource MCP.
+package brewing
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidationError reports a calculation input that is out of range or inconsistent with another input.
+// Callers can recover it with errors.As to find which field to fix.
+type ValidationError struct {
+	Field      string      // snake_case input name, e.g. "original_gravity"
+	Value      interface{} // the rejected value
+	Constraint string      // the rule the value broke, e.g. "must be greater than zero"
+}
+
+// Error returns the field, constraint, and rejected value as a sentence.
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s %s (got %v)", strings.ReplaceAll(e.Field, "_", " "), e.Constraint, e.Value)
+}
@@ -0,0 +1,109 @@
+package brewing_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/CharlRitter/brewsource-mcp/app/pkg/brewing"
+)
+
+func TestValidationError_Error(t *testing.T) {
+	err := &brewing.ValidationError{
+		Field:      "original_gravity",
+		Value:      1.3,
+		Constraint: "must be between 1.000 and 1.200",
+	}
+	want := "original gravity must be between 1.000 and 1.200 (got 1.3)"
+	if err.Error() != want {
+		t.Errorf("got %q, want %q", err.Error(), want)
+	}
+}
+
+func TestValidationError_ErrorsAs(t *testing.T) {
+	tests := []struct {
+		name      string
+		call      func() error
+		wantField string
+	}{
+		{
+			name: "abv original gravity",
+			call: func() error {
+				_, err := brewing.ABVCalculation{OriginalGravity: 1.3, FinalGravity: 1.010}.SimpleABV()
+				return err
+			},
+			wantField: "original_gravity",
+		},
+		{
+			name: "abv final gravity above original",
+			call: func() error {
+				_, err := brewing.ABVCalculation{OriginalGravity: 1.040, FinalGravity: 1.050}.SimpleABV()
+				return err
+			},
+			wantField: "final_gravity",
+		},
+		{
+			name: "attenuation",
+			call: func() error {
+				_, err := brewing.AttenuationCalculation{OriginalGravity: 0.9, FinalGravity: 1.010}.Calculate()
+				return err
+			},
+			wantField: "original_gravity",
+		},
+		{
+			name: "hop alpha acid",
+			call: func() error {
+				_, err := brewing.HopAddition{AlphaAcid: -1, Weight: 1, BoilTime: 60}.CalculateIBU(5, 1.050)
+				return err
+			},
+			wantField: "alpha_acid",
+		},
+		{
+			name: "hop batch size",
+			call: func() error {
+				_, err := brewing.HopAddition{AlphaAcid: 5, Weight: 1, BoilTime: 60}.CalculateIBU(0, 1.050)
+				return err
+			},
+			wantField: "batch_size",
+		},
+		{
+			name: "pitching rate batch volume",
+			call: func() error {
+				_, err := brewing.YeastPitchingRate{OriginalGravity: 1.050}.TargetCells()
+				return err
+			},
+			wantField: "batch_volume",
+		},
+		{
+			name: "wrapped starter step",
+			call: func() error {
+				_, err := brewing.StarterCalculation{
+					InitialCells: 100,
+					Steps:        []brewing.StarterStep{{Volume: 1, Gravity: 1.100}},
+				}.Calculate()
+				return err
+			},
+			wantField: "starter_gravity",
+		},
+		{
+			name: "boil volume",
+			call: func() error {
+				_, err := brewing.BoilVolumeCalculation{TargetVolume: -1}.Calculate()
+				return err
+			},
+			wantField: "target_volume",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			var validationErr *brewing.ValidationError
+			if !errors.As(fmt.Errorf("wrapped: %w", err), &validationErr) {
+				t.Fatalf("expected a *brewing.ValidationError, got %T: %v", err, err)
+			}
+			if validationErr.Field != tt.wantField {
+				t.Errorf("expected field %q, got %q", tt.wantField, validationErr.Field)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+// Package brewing provides brewing calculations and unit conversions for Brewsource MCP.
+package brewing
+
+import (
+	"fmt"
+)
+
+const (
+	// DefaultHydrometerCalibrationF is the calibration temperature printed on most hydrometers.
+	DefaultHydrometerCalibrationF = 60
+	// minHydrometerTempF and maxHydrometerTempF bound the range of liquid water the correction is valid for.
+	minHydrometerTempF = 32
+	maxHydrometerTempF = 212
+)
+
+// HydrometerCorrection adjusts a hydrometer reading taken at sampleTempF to the hydrometer's
+// calibration temperature, using the density of water polynomial
+// 1.00130346 - 0.000134722124T + 0.00000204052596T² - 0.00000000232820948T³.
+func HydrometerCorrection(measuredSG, sampleTempF, calibrationTempF float64) (float64, error) {
+	if measuredSG <= 0 {
+		return 0, &ValidationError{
+			Field:      "measured_gravity",
+			Value:      measuredSG,
+			Constraint: "must be greater than zero",
+		}
+	}
+	for _, temp := range []struct {
+		name  string
+		value float64
+	}{
+		{"sample_temperature", sampleTempF},
+		{"calibration_temperature", calibrationTempF},
+	} {
+		if temp.value < minHydrometerTempF || temp.value > maxHydrometerTempF {
+			return 0, &ValidationError{
+				Field:      temp.name,
+				Value:      temp.value,
+				Constraint: fmt.Sprintf("must be between %d and %d°F", minHydrometerTempF, maxHydrometerTempF),
+			}
+		}
+	}
+	return measuredSG * waterDensityFactor(sampleTempF) / waterDensityFactor(calibrationTempF), nil
+}
+
+func waterDensityFactor(tempF float64) float64 {
+	return 1.00130346 - 0.000134722124*tempF + 0.00000204052596*tempF*tempF - 0.00000000232820948*tempF*tempF*tempF
+}
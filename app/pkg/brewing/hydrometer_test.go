@@ -0,0 +1,58 @@
+package brewing_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/CharlRitter/brewsource-mcp/app/pkg/brewing"
+)
+
+func TestHydrometerCorrection(t *testing.T) {
+	tests := []struct {
+		name        string
+		measured    float64
+		sampleF     float64
+		calibration float64
+		want        float64
+	}{
+		{name: "sample at calibration temperature", measured: 1.050, sampleF: 68, calibration: 68, want: 1.050},
+		{name: "sample at 60F default calibration", measured: 1.042, sampleF: 60, calibration: 60, want: 1.042},
+		{name: "warm wort", measured: 1.050, sampleF: 100, calibration: 60, want: 1.0561},
+		{name: "slightly warm", measured: 1.050, sampleF: 80, calibration: 60, want: 1.0524},
+		{name: "hot wort", measured: 1.040, sampleF: 150, calibration: 60, want: 1.0598},
+		{name: "20C calibrated hydrometer", measured: 1.048, sampleF: 77, calibration: 68, want: 1.0492},
+		{name: "cold sample reads high", measured: 1.050, sampleF: 40, calibration: 60, want: 1.0490},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := brewing.HydrometerCorrection(tt.measured, tt.sampleF, tt.calibration)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assertClose(t, got, tt.want, 0.0001)
+		})
+	}
+}
+
+func TestHydrometerCorrection_Validation(t *testing.T) {
+	tests := []struct {
+		name        string
+		measured    float64
+		sampleF     float64
+		calibration float64
+		wantErr     string
+	}{
+		{name: "zero gravity", measured: 0, sampleF: 68, calibration: 60, wantErr: "measured gravity"},
+		{name: "frozen sample", measured: 1.050, sampleF: 31, calibration: 60, wantErr: "sample temperature"},
+		{name: "above boiling", measured: 1.050, sampleF: 213, calibration: 60, wantErr: "sample temperature"},
+		{name: "bad calibration", measured: 1.050, sampleF: 68, calibration: 250, wantErr: "calibration temperature"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := brewing.HydrometerCorrection(tt.measured, tt.sampleF, tt.calibration)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
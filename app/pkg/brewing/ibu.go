@@ -0,0 +1,205 @@
+// Package brewing provides brewing calculations and unit conversions for Brewsource MCP.
+package brewing
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// IBUFormula selects the hop utilization model used to estimate bitterness.
+type IBUFormula string
+
+// Supported IBU formulas.
+const (
+	IBUTinseth IBUFormula = "tinseth"
+	IBURager   IBUFormula = "rager"
+	IBUGaretz  IBUFormula = "garetz"
+)
+
+// HopForm is the physical form of a hop addition, which affects how readily alpha acids isomerize.
+type HopForm string
+
+// Supported hop forms.
+const (
+	HopWhole   HopForm = "whole"
+	HopPellet  HopForm = "pellet"
+	HopExtract HopForm = "extract"
+)
+
+// hopFormFactors scales utilization relative to whole cones, which the IBU formulas were fitted against.
+// Pellets release alpha acids faster and are conventionally credited with 10% more utilization; CO2
+// extract is treated like whole cones.
+//
+//nolint:gochecknoglobals // static lookup table
+var hopFormFactors = map[HopForm]float64{
+	HopWhole:   1.0,
+	HopPellet:  1.1,
+	HopExtract: 1.0,
+}
+
+// ParseHopForm normalises a hop form name. An empty name means whole hops.
+func ParseHopForm(name string) (HopForm, error) {
+	normalized := strings.ToLower(strings.TrimSpace(name))
+	switch normalized {
+	case "", "leaf", "cone":
+		return HopWhole, nil
+	case "pellets":
+		return HopPellet, nil
+	}
+	if _, ok := hopFormFactors[HopForm(normalized)]; ok {
+		return HopForm(normalized), nil
+	}
+	return "", fmt.Errorf("unknown hop form %q; supported forms: whole, pellet, extract", name)
+}
+
+const (
+	// alphaAcidMgPerOunceGallon converts ounces of alpha acid per US gallon to mg/L (Tinseth).
+	alphaAcidMgPerOunceGallon = 7490
+	// ragerMgPerOunceGallon is Rager's rounding of the same conversion.
+	ragerMgPerOunceGallon = 7462
+	// garetzIterations is enough passes for Garetz's hopping-rate factor to converge.
+	garetzIterations = 20
+	// isomerizationActivation is the Arrhenius temperature constant (K) for alpha acid isomerization (Malowicki).
+	isomerizationActivation = 11858
+	boilingPointK           = 373.15
+)
+
+// garetzUtilization is Garetz's utilization table: percent utilization up to each boil time in minutes.
+//
+//nolint:gochecknoglobals // static lookup table
+var garetzUtilization = []struct {
+	maxMinutes  float64
+	utilization float64
+}{
+	{10, 0}, {15, 2}, {20, 5}, {25, 8}, {30, 11}, {35, 14}, {40, 16},
+	{45, 18}, {50, 19}, {60, 20}, {70, 21}, {80, 22}, {90, 23},
+}
+
+// HopAddition is a single hop addition to the boil or whirlpool.
+type HopAddition struct {
+	Name      string
+	AlphaAcid float64    // percent
+	Weight    float64    // ounces, or grams when Units is metric
+	BoilTime  float64    // minutes in the boil, or steep duration when SteepTemp is set
+	SteepTemp float64    // °F (°C when metric) of a whirlpool or hop stand; zero for a boil addition
+	Form      HopForm    // defaults to HopWhole
+	DryHop    bool       // dry hops are added after fermentation and contribute no IBU
+	Units     UnitSystem // units of Weight, SteepTemp, and the batch size; defaults to UnitsImperial
+}
+
+// CalculateIBU returns the Tinseth IBU contribution of the addition to batchSize gallons (liters when
+// metric) of wort at og.
+func (h HopAddition) CalculateIBU(batchSize, og float64) (float64, error) {
+	return h.CalculateIBUWithFormula(batchSize, og, IBUTinseth)
+}
+
+// CalculateIBUWithFormula returns the IBU contribution of the addition using the chosen formula.
+// Whirlpool additions scale utilization by the isomerization rate at SteepTemp relative to boiling.
+func (h HopAddition) CalculateIBUWithFormula(batchSize, og float64, formula IBUFormula) (float64, error) {
+	if err := h.validate(batchSize, og); err != nil {
+		return 0, err
+	}
+	h, batchSize = h.imperial(batchSize)
+	form := h.Form
+	if form == "" {
+		form = HopWhole
+	}
+	formFactor, ok := hopFormFactors[form]
+	if !ok {
+		return 0, &ValidationError{Field: "hop_form", Value: h.Form, Constraint: "must be whole, pellet, or extract"}
+	}
+	if h.DryHop {
+		return 0, nil
+	}
+
+	var ibu float64
+	switch formula {
+	case "", IBUTinseth:
+		bigness := 1.65 * math.Pow(0.000125, og-1)
+		boilFactor := (1 - math.Exp(-0.04*h.BoilTime)) / 4.15
+		ibu = bigness * boilFactor * h.AlphaAcid / 100 * h.Weight * alphaAcidMgPerOunceGallon / batchSize
+	case IBURager:
+		utilization := (18.11 + 13.86*math.Tanh((h.BoilTime-31.32)/18.27)) / 100
+		gravityAdjustment := 0.0
+		if og > 1.050 {
+			gravityAdjustment = (og - 1.050) / 0.2
+		}
+		ibu = h.Weight * utilization * h.AlphaAcid / 100 * ragerMgPerOunceGallon / (batchSize * (1 + gravityAdjustment))
+	case IBUGaretz:
+		ibu = h.garetzIBU(batchSize, og)
+	default:
+		return 0, &ValidationError{
+			Field:      "ibu_formula",
+			Value:      formula,
+			Constraint: "must be tinseth, rager, or garetz",
+		}
+	}
+	return ibu * formFactor * steepFactor(h.SteepTemp), nil
+}
+
+// imperial returns the addition and batch size converted to the US units the formulas expect.
+func (h HopAddition) imperial(batchSize float64) (HopAddition, float64) {
+	if h.Units != UnitsMetric {
+		return h, batchSize
+	}
+	h.Weight /= GramsPerOunce
+	if h.SteepTemp != 0 {
+		h.SteepTemp = CelsiusToFahrenheit(h.SteepTemp)
+	}
+	h.Units = UnitsImperial
+	return h, batchSize / LitersPerGallon
+}
+
+func (h HopAddition) validate(batchSize, og float64) error {
+	if err := h.Units.validate(); err != nil {
+		return err
+	}
+	switch {
+	case batchSize <= 0:
+		return &ValidationError{Field: "batch_size", Value: batchSize, Constraint: "must be greater than zero"}
+	case og < 1:
+		return &ValidationError{Field: "original_gravity", Value: og, Constraint: "must be at least 1.000"}
+	case h.AlphaAcid < 0:
+		return &ValidationError{Field: "alpha_acid", Value: h.AlphaAcid, Constraint: "cannot be negative"}
+	case h.Weight < 0:
+		return &ValidationError{Field: "hop_weight", Value: h.Weight, Constraint: "cannot be negative"}
+	case h.BoilTime < 0:
+		return &ValidationError{Field: "boil_time", Value: h.BoilTime, Constraint: "cannot be negative"}
+	}
+	return nil
+}
+
+// garetzIBU applies Garetz's table utilization with gravity and hopping-rate corrections. The
+// hopping-rate factor depends on the IBU being solved for, so it is iterated to a fixed point.
+// Boil volume is assumed equal to batch size and the brewery is assumed to be at sea level.
+func (h HopAddition) garetzIBU(batchSize, og float64) float64 {
+	utilization := garetzUtilization[len(garetzUtilization)-1].utilization
+	for _, step := range garetzUtilization {
+		if h.BoilTime <= step.maxMinutes {
+			utilization = step.utilization
+			break
+		}
+	}
+	gravityFactor := 1.0
+	if og > 1.050 {
+		gravityFactor = (og-1.050)/0.2 + 1
+	}
+
+	base := utilization / 100 * h.AlphaAcid / 100 * h.Weight * alphaAcidMgPerOunceGallon / batchSize
+	ibu := base / gravityFactor
+	for range garetzIterations {
+		hoppingRateFactor := ibu/260 + 1
+		ibu = base / (gravityFactor * hoppingRateFactor)
+	}
+	return ibu
+}
+
+// steepFactor returns the isomerization rate at tempF relative to boiling, or 1 for boil additions.
+func steepFactor(tempF float64) float64 {
+	if tempF <= 0 {
+		return 1
+	}
+	tempK := FahrenheitToCelsius(tempF) + 273.15
+	return math.Min(1, math.Exp(isomerizationActivation*(1/boilingPointK-1/tempK)))
+}
@@ -0,0 +1,218 @@
+package brewing_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/CharlRitter/brewsource-mcp/app/pkg/brewing"
+)
+
+func TestHopAddition_CalculateIBU(t *testing.T) {
+	tests := []struct {
+		name      string
+		hop       brewing.HopAddition
+		batchSize float64
+		og        float64
+		want      float64
+	}{
+		{
+			name:      "60 minute bittering addition",
+			hop:       brewing.HopAddition{Name: "Magnum", AlphaAcid: 10, Weight: 1, BoilTime: 60},
+			batchSize: 5, og: 1.050, want: 34.6,
+		},
+		{
+			name:      "15 minute flavour addition",
+			hop:       brewing.HopAddition{Name: "Cascade", AlphaAcid: 5.5, Weight: 1, BoilTime: 15},
+			batchSize: 5, og: 1.050, want: 9.4,
+		},
+		{
+			name:      "higher gravity lowers utilization",
+			hop:       brewing.HopAddition{Name: "Magnum", AlphaAcid: 10, Weight: 1, BoilTime: 60},
+			batchSize: 5, og: 1.080, want: 26.4,
+		},
+		{
+			name:      "flameout addition",
+			hop:       brewing.HopAddition{Name: "Citra", AlphaAcid: 12, Weight: 2, BoilTime: 0},
+			batchSize: 5, og: 1.060, want: 0,
+		},
+		{
+			name: "pellets add 10% utilization",
+			hop: brewing.HopAddition{
+				Name: "Magnum", AlphaAcid: 10, Weight: 1, BoilTime: 60, Form: brewing.HopPellet,
+			},
+			batchSize: 5, og: 1.050, want: 38.0,
+		},
+		{
+			name: "whole hops match the default",
+			hop: brewing.HopAddition{
+				Name: "Magnum", AlphaAcid: 10, Weight: 1, BoilTime: 60, Form: brewing.HopWhole,
+			},
+			batchSize: 5, og: 1.050, want: 34.6,
+		},
+		{
+			name: "extract",
+			hop: brewing.HopAddition{
+				Name: "Hop Shot", AlphaAcid: 50, Weight: 0.2, BoilTime: 60, Form: brewing.HopExtract,
+			},
+			batchSize: 5, og: 1.050, want: 34.6,
+		},
+		{
+			name: "dry hop contributes nothing regardless of time",
+			hop: brewing.HopAddition{
+				Name: "Citra", AlphaAcid: 12, Weight: 2, BoilTime: 60, Form: brewing.HopPellet, DryHop: true,
+			},
+			batchSize: 5, og: 1.060, want: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ibu, err := tt.hop.CalculateIBU(tt.batchSize, tt.og)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assertClose(t, ibu, tt.want, 0.1)
+		})
+	}
+}
+
+func TestHopAddition_CalculateIBUWithFormula_Compare(t *testing.T) {
+	// The same 1 oz, 10% AA, 60 minute addition in 5 gallons of 1.050 wort under each model.
+	hop := brewing.HopAddition{Name: "Magnum", AlphaAcid: 10, Weight: 1, BoilTime: 60}
+	want := map[brewing.IBUFormula]float64{
+		brewing.IBUTinseth: 34.6,
+		brewing.IBURager:   46.0,
+		brewing.IBUGaretz:  27.1,
+	}
+
+	results := map[brewing.IBUFormula]float64{}
+	for formula, expected := range want {
+		ibu, err := hop.CalculateIBUWithFormula(5, 1.050, formula)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", formula, err)
+		}
+		assertClose(t, ibu, expected, 0.1)
+		results[formula] = ibu
+	}
+
+	rager, tinseth, garetz := results[brewing.IBURager], results[brewing.IBUTinseth], results[brewing.IBUGaretz]
+	if !(rager > tinseth && tinseth > garetz) {
+		t.Errorf("expected Rager > Tinseth > Garetz, got %v", results)
+	}
+	defaultIBU, _ := hop.CalculateIBUWithFormula(5, 1.050, "")
+	if tinsethIBU, _ := hop.CalculateIBU(5, 1.050); defaultIBU != tinsethIBU {
+		t.Errorf("expected the default formula to match CalculateIBU")
+	}
+}
+
+func TestHopAddition_CalculateIBUWithFormula_GravityAdjustment(t *testing.T) {
+	hop := brewing.HopAddition{AlphaAcid: 10, Weight: 1, BoilTime: 60}
+	for _, formula := range []brewing.IBUFormula{brewing.IBUTinseth, brewing.IBURager, brewing.IBUGaretz} {
+		normal, _ := hop.CalculateIBUWithFormula(5, 1.050, formula)
+		big, _ := hop.CalculateIBUWithFormula(5, 1.090, formula)
+		if big >= normal {
+			t.Errorf("%s: expected lower IBU at 1.090 (%.1f) than at 1.050 (%.1f)", formula, big, normal)
+		}
+	}
+}
+
+func TestHopAddition_Whirlpool(t *testing.T) {
+	boil := brewing.HopAddition{AlphaAcid: 12, Weight: 2, BoilTime: 20}
+	hot := brewing.HopAddition{AlphaAcid: 12, Weight: 2, BoilTime: 20, SteepTemp: 200}
+	cool := brewing.HopAddition{AlphaAcid: 12, Weight: 2, BoilTime: 20, SteepTemp: 170}
+
+	boilIBU, _ := boil.CalculateIBU(5, 1.060)
+	hotIBU, _ := hot.CalculateIBU(5, 1.060)
+	coolIBU, _ := cool.CalculateIBU(5, 1.060)
+	if !(boilIBU > hotIBU && hotIBU > coolIBU && coolIBU > 0) {
+		t.Errorf("expected boil > 200°F steep > 170°F steep > 0, got %.2f, %.2f, %.2f", boilIBU, hotIBU, coolIBU)
+	}
+	// At or above boiling a steep behaves like a boil addition.
+	atBoil := brewing.HopAddition{AlphaAcid: 12, Weight: 2, BoilTime: 20, SteepTemp: 212}
+	atBoilIBU, _ := atBoil.CalculateIBU(5, 1.060)
+	assertClose(t, atBoilIBU, boilIBU, 0.01)
+}
+
+func TestHopAddition_CalculateIBUWithFormula_Errors(t *testing.T) {
+	valid := brewing.HopAddition{AlphaAcid: 10, Weight: 1, BoilTime: 60}
+	tests := []struct {
+		name      string
+		hop       brewing.HopAddition
+		batchSize float64
+		og        float64
+		formula   brewing.IBUFormula
+		wantErr   string
+	}{
+		{name: "unknown formula", hop: valid, batchSize: 5, og: 1.050, formula: "daniels",
+			wantErr: "ibu formula must be"},
+		{name: "zero batch", hop: valid, batchSize: 0, og: 1.050, formula: brewing.IBUTinseth,
+			wantErr: "batch size"},
+		{name: "gravity below water", hop: valid, batchSize: 5, og: 0.99, formula: brewing.IBURager,
+			wantErr: "original gravity"},
+		{name: "negative boil time", hop: brewing.HopAddition{AlphaAcid: 10, Weight: 1, BoilTime: -5},
+			batchSize: 5, og: 1.050, formula: brewing.IBUTinseth, wantErr: "boil time cannot be negative"},
+		{name: "negative boil time on dry hop",
+			hop:       brewing.HopAddition{AlphaAcid: 10, Weight: 1, BoilTime: -5, DryHop: true},
+			batchSize: 5, og: 1.050, formula: brewing.IBUGaretz, wantErr: "boil time cannot be negative"},
+		{name: "negative weight", hop: brewing.HopAddition{AlphaAcid: 10, Weight: -1, BoilTime: 60},
+			batchSize: 5, og: 1.050, formula: brewing.IBUTinseth, wantErr: "hop weight"},
+		{name: "negative alpha acid", hop: brewing.HopAddition{AlphaAcid: -1, Weight: 1, BoilTime: 60},
+			batchSize: 5, og: 1.050, formula: brewing.IBUTinseth, wantErr: "alpha acid"},
+		{name: "unknown form", hop: brewing.HopAddition{AlphaAcid: 10, Weight: 1, BoilTime: 60, Form: "plug"},
+			batchSize: 5, og: 1.050, formula: brewing.IBUTinseth, wantErr: "hop form must be"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.hop.CalculateIBUWithFormula(tt.batchSize, tt.og, tt.formula)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestParseHopForm(t *testing.T) {
+	tests := map[string]brewing.HopForm{
+		"":        brewing.HopWhole,
+		"Leaf":    brewing.HopWhole,
+		"whole":   brewing.HopWhole,
+		"pellets": brewing.HopPellet,
+		"PELLET":  brewing.HopPellet,
+		"extract": brewing.HopExtract,
+	}
+	for input, want := range tests {
+		got, err := brewing.ParseHopForm(input)
+		if err != nil || got != want {
+			t.Errorf("ParseHopForm(%q) = %q, %v; want %q", input, got, err, want)
+		}
+	}
+	if _, err := brewing.ParseHopForm("powder"); err == nil {
+		t.Error("expected error for unsupported hop form")
+	}
+}
+
+func TestHopAddition_MetricMatchesImperial(t *testing.T) {
+	imperial := brewing.HopAddition{AlphaAcid: 12, Weight: 1.5, BoilTime: 20, SteepTemp: 176}
+	metric := brewing.HopAddition{
+		AlphaAcid: 12,
+		Weight:    1.5 * brewing.GramsPerOunce,
+		BoilTime:  20,
+		SteepTemp: brewing.FahrenheitToCelsius(176),
+		Units:     brewing.UnitsMetric,
+	}
+	for _, formula := range []brewing.IBUFormula{brewing.IBUTinseth, brewing.IBURager, brewing.IBUGaretz} {
+		want, err := imperial.CalculateIBUWithFormula(5, 1.060, formula)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got, err := metric.CalculateIBUWithFormula(5*brewing.LitersPerGallon, 1.060, formula)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		assertClose(t, got, want, 1e-9)
+	}
+
+	if _, err := (brewing.HopAddition{AlphaAcid: 5, Weight: 1, Units: "cubits"}).CalculateIBU(5, 1.050); err == nil ||
+		!strings.Contains(err.Error(), "units must be imperial or metric") {
+		t.Errorf("expected units error, got %v", err)
+	}
+}
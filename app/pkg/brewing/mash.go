@@ -0,0 +1,128 @@
+// Package brewing provides brewing calculations and unit conversions for Brewsource MCP.
+package brewing
+
+import (
+	"fmt"
+)
+
+const (
+	// grainSpecificHeat is the thermal mass of grain relative to water used by the infusion equations.
+	grainSpecificHeat = 0.2
+	// quartsPerGallon is the number of US quarts in a US gallon.
+	quartsPerGallon = 4
+	// DefaultGrainAbsorption is the typical water retained by spent grain, in gallons per pound.
+	DefaultGrainAbsorption = 0.125
+	// DefaultInfusionWaterTempF is the assumed temperature of near-boiling infusion water.
+	DefaultInfusionWaterTempF = 210
+)
+
+// StrikeWaterCalculation holds the inputs for a single or stepped infusion mash, in US units.
+type StrikeWaterCalculation struct {
+	GrainWeight       float64   // pounds
+	GrainTemp         float64   // °F
+	TargetMashTemp    float64   // °F, first rest temperature
+	WaterToGristRatio float64   // quarts per pound
+	StepTemps         []float64 // °F, additional rests reached by boiling-water infusions
+	InfusionWaterTemp float64   // °F, defaults to DefaultInfusionWaterTempF
+	SpargeVolume      float64   // gallons
+	GrainAbsorption   float64   // gallons per pound, defaults to DefaultGrainAbsorption
+}
+
+// InfusionAddition is the water needed to raise the mash to the next rest.
+type InfusionAddition struct {
+	TargetTemp float64 `json:"target_temp"`
+	Volume     float64 `json:"volume"` // gallons
+}
+
+// MashWaterResult summarises the water plan for a mash.
+type MashWaterResult struct {
+	StrikeTemp      float64            `json:"strike_temp"`
+	StrikeVolume    float64            `json:"strike_volume"`
+	Infusions       []InfusionAddition `json:"infusions"`
+	SpargeVolume    float64            `json:"sparge_volume"`
+	TotalWater      float64            `json:"total_water"`
+	GrainAbsorption float64            `json:"grain_absorption"`
+	PreBoilVolume   float64            `json:"pre_boil_volume"`
+}
+
+// Calculate returns the strike temperature, infusion additions, and the resulting water volumes.
+//
+// Strike temperature uses Tw = (0.2/r)(T2 - T1) + T2 and each infusion uses
+// Wa = (T2 - T1)(0.2G + Wm) / (Tw - T2), where r is quarts per pound, G is grain pounds,
+// and Wm is the quarts of water already in the mash.
+func (c StrikeWaterCalculation) Calculate() (*MashWaterResult, error) {
+	if err := c.validate(); err != nil {
+		return nil, err
+	}
+
+	infusionTemp := c.InfusionWaterTemp
+	if infusionTemp == 0 {
+		infusionTemp = DefaultInfusionWaterTempF
+	}
+	absorptionRate := c.GrainAbsorption
+	if absorptionRate == 0 {
+		absorptionRate = DefaultGrainAbsorption
+	}
+
+	result := &MashWaterResult{
+		StrikeTemp:   (grainSpecificHeat/c.WaterToGristRatio)*(c.TargetMashTemp-c.GrainTemp) + c.TargetMashTemp,
+		StrikeVolume: c.GrainWeight * c.WaterToGristRatio / quartsPerGallon,
+		Infusions:    []InfusionAddition{},
+		SpargeVolume: c.SpargeVolume,
+	}
+
+	mashQuarts := c.GrainWeight * c.WaterToGristRatio
+	currentTemp := c.TargetMashTemp
+	for _, target := range c.StepTemps {
+		if target <= currentTemp {
+			return nil, &ValidationError{
+				Field:      "step_temps",
+				Value:      target,
+				Constraint: fmt.Sprintf("must be above the previous rest (%.1f°F)", currentTemp),
+			}
+		}
+		if target >= infusionTemp {
+			return nil, &ValidationError{
+				Field:      "step_temps",
+				Value:      target,
+				Constraint: fmt.Sprintf("must be below the infusion water temperature (%.1f°F)", infusionTemp),
+			}
+		}
+		quarts := (target - currentTemp) * (grainSpecificHeat*c.GrainWeight + mashQuarts) / (infusionTemp - target)
+		result.Infusions = append(result.Infusions, InfusionAddition{
+			TargetTemp: target,
+			Volume:     quarts / quartsPerGallon,
+		})
+		mashQuarts += quarts
+		currentTemp = target
+	}
+
+	result.TotalWater = mashQuarts/quartsPerGallon + c.SpargeVolume
+	result.GrainAbsorption = c.GrainWeight * absorptionRate
+	result.PreBoilVolume = result.TotalWater - result.GrainAbsorption
+	return result, nil
+}
+
+func (c StrikeWaterCalculation) validate() error {
+	switch {
+	case c.GrainWeight <= 0:
+		return &ValidationError{Field: "grain_weight", Value: c.GrainWeight, Constraint: "must be greater than zero"}
+	case c.WaterToGristRatio <= 0:
+		return &ValidationError{
+			Field:      "water_to_grist_ratio",
+			Value:      c.WaterToGristRatio,
+			Constraint: "must be greater than zero",
+		}
+	case c.TargetMashTemp <= 0:
+		return &ValidationError{
+			Field:      "target_mash_temp",
+			Value:      c.TargetMashTemp,
+			Constraint: "must be greater than zero",
+		}
+	case c.SpargeVolume < 0:
+		return &ValidationError{Field: "sparge_volume", Value: c.SpargeVolume, Constraint: "cannot be negative"}
+	case c.GrainAbsorption < 0:
+		return &ValidationError{Field: "grain_absorption", Value: c.GrainAbsorption, Constraint: "cannot be negative"}
+	}
+	return nil
+}
@@ -0,0 +1,182 @@
+// Package brewing provides brewing calculations and unit conversions for Brewsource MCP.
+package brewing
+
+import (
+	"fmt"
+	"math"
+)
+
+// GrainType groups malts by their effect on mash pH.
+type GrainType string
+
+// Supported grain types.
+const (
+	GrainBase    GrainType = "base"
+	GrainCrystal GrainType = "crystal"
+	GrainRoasted GrainType = "roasted"
+)
+
+const (
+	// baseMaltDistilledPH is the mash pH of pale base malt in distilled water.
+	baseMaltDistilledPH = 5.72
+	// roastedMaltDistilledPH is the mash pH of roasted malts and grains in distilled water.
+	roastedMaltDistilledPH = 4.71
+	// maltBufferCapacity is the acid or base, in mEq per kg of grist, that moves mash pH by one unit.
+	maltBufferCapacity = 35
+	// maxBaseLovibond and minRoastedLovibond split grains into types when none is given.
+	maxBaseLovibond    = 15
+	minRoastedLovibond = 200
+	// DefaultMashTargetPH is the middle of the commonly recommended 5.2-5.6 range.
+	DefaultMashTargetPH = 5.4
+	// lacticAcidMmolPerML is 88% lactic acid: 1.209 g/ml × 0.88 / 90.08 g/mol.
+	lacticAcidMmolPerML = 11.81
+	lacticAcidPKa       = 3.86
+	// phosphoricAcidMmolPerML is 10% phosphoric acid: 1.053 g/ml × 0.10 / 98.00 g/mol.
+	phosphoricAcidMmolPerML = 1.074
+	phosphoricAcidPKa       = 2.15
+	// alkalinityPerMEq is ppm as CaCO3 per mEq/L.
+	alkalinityPerMEq = 50
+	// litersPerKgPerQuartsPerPound converts mash thickness from quarts per pound to liters per kg.
+	litersPerKgPerQuartsPerPound = LitersPerGallon / quartsPerGallon / (GramsPerPound / 1000)
+)
+
+// MashGrain is one malt in the grain bill.
+type MashGrain struct {
+	Weight   float64   // pounds, or kilograms when the calculation is metric
+	Lovibond float64   // °L
+	Type     GrainType // inferred from Lovibond when empty
+}
+
+// MashPHCalculation estimates mash pH from the grain bill and brewing water.
+type MashPHCalculation struct {
+	Grains            []MashGrain
+	Water             WaterChemistry
+	WaterToGristRatio float64    // quarts per pound, or liters per kilogram when metric
+	TargetPH          float64    // defaults to DefaultMashTargetPH
+	Units             UnitSystem // defaults to UnitsImperial
+}
+
+// MashPHResult is the estimated mash pH and the acid needed to reach the target.
+type MashPHResult struct {
+	DistilledWaterPH float64 `json:"distilled_water_ph"` // the grist mashed in distilled water
+	EstimatedPH      float64 `json:"estimated_ph"`
+	TargetPH         float64 `json:"target_ph"`
+	AcidMEq          float64 `json:"acid_meq"` // zero when the mash is already at or below the target
+	LacticAcidML     float64 `json:"lactic_acid_ml"`
+	PhosphoricAcidML float64 `json:"phosphoric_acid_ml"`
+}
+
+// Calculate estimates the mash pH using Kai Troester's distilled water pH and buffer capacity model.
+//
+// Each grain's distilled water pH comes from its type: 5.72 for base malt, 5.22 - 0.00504 × °L for
+// crystal malt, and 4.71 for roasted grains. With a shared buffer capacity of 35 mEq/(kg·pH) the grist's
+// distilled water pH is the weight-averaged pH. The water's residual alkalinity then raises it by
+// (RA / 50) × R / 35, where R is the mash thickness in L/kg. Acid additions supply the mEq needed to
+// move the estimate down to the target: 88% lactic acid and 10% phosphoric acid, each counted by the
+// fraction dissociated at the target pH. The model is an estimate; expect ±0.1-0.2 pH against a meter.
+func (c MashPHCalculation) Calculate() (*MashPHResult, error) {
+	if err := c.validate(); err != nil {
+		return nil, err
+	}
+	ratio, weightToKg := c.WaterToGristRatio*litersPerKgPerQuartsPerPound, GramsPerPound/1000
+	if c.Units == UnitsMetric {
+		ratio, weightToKg = c.WaterToGristRatio, 1
+	}
+	target := c.TargetPH
+	if target == 0 {
+		target = DefaultMashTargetPH
+	}
+
+	totalKg, weightedPH := 0.0, 0.0
+	for _, grain := range c.Grains {
+		kg := grain.Weight * weightToKg
+		totalKg += kg
+		weightedPH += kg * grain.distilledWaterPH()
+	}
+	result := &MashPHResult{DistilledWaterPH: weightedPH / totalKg, TargetPH: target}
+	alkalinity := c.Water.ResidualAlkalinity() / alkalinityPerMEq * ratio // mEq per kg of grist
+	result.EstimatedPH = result.DistilledWaterPH + alkalinity/maltBufferCapacity
+
+	if result.EstimatedPH > target {
+		result.AcidMEq = (result.EstimatedPH - target) * maltBufferCapacity * totalKg
+		result.LacticAcidML = result.AcidMEq / (lacticAcidMmolPerML * dissociated(lacticAcidPKa, target))
+		result.PhosphoricAcidML = result.AcidMEq / (phosphoricAcidMmolPerML * dissociated(phosphoricAcidPKa, target))
+	}
+	return result, nil
+}
+
+// dissociated returns the fraction of an acid's first proton released at pH (Henderson-Hasselbalch).
+func dissociated(pKa, pH float64) float64 {
+	return 1 / (1 + math.Pow(10, pKa-pH))
+}
+
+// grainType returns the grain's type, inferring it from colour when none is set: up to 15 °L is base
+// malt (including Munich and Vienna), over 200 °L is roasted, and anything in between is crystal.
+func (g MashGrain) grainType() GrainType {
+	switch {
+	case g.Type != "":
+		return g.Type
+	case g.Lovibond <= maxBaseLovibond:
+		return GrainBase
+	case g.Lovibond > minRoastedLovibond:
+		return GrainRoasted
+	default:
+		return GrainCrystal
+	}
+}
+
+func (g MashGrain) distilledWaterPH() float64 {
+	switch g.grainType() {
+	case GrainCrystal:
+		return 5.22 - 0.00504*g.Lovibond
+	case GrainRoasted:
+		return roastedMaltDistilledPH
+	default:
+		return baseMaltDistilledPH
+	}
+}
+
+func (c MashPHCalculation) validate() error {
+	if err := c.Units.validate(); err != nil {
+		return err
+	}
+	if len(c.Grains) == 0 {
+		return &ValidationError{Field: "grains", Value: 0, Constraint: "must include at least one grain"}
+	}
+	for i, grain := range c.Grains {
+		switch {
+		case grain.Weight <= 0:
+			return &ValidationError{
+				Field:      fmt.Sprintf("grains[%d].weight", i),
+				Value:      grain.Weight,
+				Constraint: "must be greater than zero",
+			}
+		case grain.Lovibond < 0:
+			return &ValidationError{
+				Field:      fmt.Sprintf("grains[%d].lovibond", i),
+				Value:      grain.Lovibond,
+				Constraint: "cannot be negative",
+			}
+		}
+		switch grain.Type {
+		case "", GrainBase, GrainCrystal, GrainRoasted:
+		default:
+			return &ValidationError{
+				Field:      fmt.Sprintf("grains[%d].type", i),
+				Value:      string(grain.Type),
+				Constraint: "must be base, crystal, or roasted",
+			}
+		}
+	}
+	switch {
+	case c.WaterToGristRatio <= 0:
+		return &ValidationError{
+			Field:      "water_to_grist_ratio",
+			Value:      c.WaterToGristRatio,
+			Constraint: "must be greater than zero",
+		}
+	case c.TargetPH != 0 && (c.TargetPH < 4 || c.TargetPH > 7):
+		return &ValidationError{Field: "target_ph", Value: c.TargetPH, Constraint: "must be between 4.0 and 7.0"}
+	}
+	return c.Water.validate("water")
+}
@@ -0,0 +1,187 @@
+package brewing_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/CharlRitter/brewsource-mcp/app/pkg/brewing"
+)
+
+// phTolerance is the agreement expected between the model and published examples.
+const phTolerance = 0.15
+
+func TestMashPHCalculation_PublishedExamples(t *testing.T) {
+	tests := []struct {
+		name string
+		calc brewing.MashPHCalculation
+		want float64
+	}{
+		{
+			// Pale base malt mashes to about 5.7 in distilled water (Troester; Palmer, How to Brew).
+			name: "pale malt in distilled water",
+			calc: brewing.MashPHCalculation{Grains: []brewing.MashGrain{{Weight: 10, Lovibond: 2}}, WaterToGristRatio: 1.5},
+			want: 5.7,
+		},
+		{
+			// Roasted barley alone mashes to about 4.7 in distilled water (Troester).
+			name: "roasted barley in distilled water",
+			calc: brewing.MashPHCalculation{
+				Grains:            []brewing.MashGrain{{Weight: 1, Lovibond: 500}},
+				WaterToGristRatio: 1.5,
+			},
+			want: 4.7,
+		},
+		{
+			// Crystal malt in distilled water falls with colour: about 4.9 for 60 °L (Troester).
+			name: "crystal 60 in distilled water",
+			calc: brewing.MashPHCalculation{
+				Grains:            []brewing.MashGrain{{Weight: 1, Lovibond: 60}},
+				WaterToGristRatio: 1.5,
+			},
+			want: 4.9,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.calc.Calculate()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assertClose(t, result.EstimatedPH, tt.want, phTolerance)
+		})
+	}
+}
+
+func TestMashPHCalculation_Alkalinity(t *testing.T) {
+	grains := []brewing.MashGrain{{Weight: 9, Lovibond: 2}, {Weight: 1, Lovibond: 500}}
+	soft, err := brewing.MashPHCalculation{Grains: grains, WaterToGristRatio: 1.5}.Calculate()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	dublin, _ := brewing.LookupWaterProfile("dublin")
+	hard, err := brewing.MashPHCalculation{Grains: grains, Water: dublin, WaterToGristRatio: 1.5}.Calculate()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if hard.DistilledWaterPH != soft.DistilledWaterPH || hard.EstimatedPH <= soft.EstimatedPH+0.2 {
+		t.Errorf("expected alkaline water to raise pH, got %.2f (RO) and %.2f (Dublin)",
+			soft.EstimatedPH, hard.EstimatedPH)
+	}
+
+	// Thinner mashes carry more alkalinity per kilogram of grain.
+	thin, _ := brewing.MashPHCalculation{Grains: grains, Water: dublin, WaterToGristRatio: 2.5}.Calculate()
+	if thin.EstimatedPH <= hard.EstimatedPH {
+		t.Errorf("expected a thinner mash to raise pH, got %.2f and %.2f", hard.EstimatedPH, thin.EstimatedPH)
+	}
+}
+
+func TestMashPHCalculation_AcidAdditions(t *testing.T) {
+	// A common rule of thumb is that 1 ml of 88% lactic acid lowers a 5 gallon (about 10 lb) mash by
+	// about 0.1 pH.
+	calc := brewing.MashPHCalculation{
+		Grains:            []brewing.MashGrain{{Weight: 10, Lovibond: 2}},
+		WaterToGristRatio: 1.5,
+	}
+	result, err := calc.Calculate()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertClose(t, result.TargetPH, brewing.DefaultMashTargetPH, 0)
+	perML := (result.EstimatedPH - result.TargetPH) / result.LacticAcidML
+	assertClose(t, perML, 0.1, phTolerance)
+	if result.PhosphoricAcidML <= result.LacticAcidML {
+		t.Errorf("expected more 10%% phosphoric than 88%% lactic, got %.1f and %.1f ml",
+			result.PhosphoricAcidML, result.LacticAcidML)
+	}
+
+	// No acid is needed when the estimate is already at or below the target.
+	calc.TargetPH = 5.8
+	result, _ = calc.Calculate()
+	if result.AcidMEq != 0 || result.LacticAcidML != 0 || result.PhosphoricAcidML != 0 {
+		t.Errorf("expected no acid above the estimate, got %+v", result)
+	}
+}
+
+func TestMashPHCalculation_MetricMatchesImperial(t *testing.T) {
+	imperial, err := brewing.MashPHCalculation{
+		Grains:            []brewing.MashGrain{{Weight: 10, Lovibond: 2}, {Weight: 1, Lovibond: 60}},
+		Water:             brewing.WaterChemistry{Calcium: 50, Bicarbonate: 150},
+		WaterToGristRatio: 1.5,
+	}.Calculate()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	kgPerPound := brewing.GramsPerPound / 1000
+	metric, err := brewing.MashPHCalculation{
+		Grains: []brewing.MashGrain{
+			{Weight: 10 * kgPerPound, Lovibond: 2},
+			{Weight: 1 * kgPerPound, Lovibond: 60},
+		},
+		Water:             brewing.WaterChemistry{Calcium: 50, Bicarbonate: 150},
+		WaterToGristRatio: 1.5 * brewing.LitersPerGallon / 4 / kgPerPound,
+		Units:             brewing.UnitsMetric,
+	}.Calculate()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertClose(t, metric.EstimatedPH, imperial.EstimatedPH, 1e-9)
+	assertClose(t, metric.LacticAcidML, imperial.LacticAcidML, 1e-9)
+}
+
+func TestMashPHCalculation_Validation(t *testing.T) {
+	tests := []struct {
+		name      string
+		calc      brewing.MashPHCalculation
+		wantField string
+	}{
+		{name: "no grains", calc: brewing.MashPHCalculation{WaterToGristRatio: 1.5}, wantField: "grains"},
+		{
+			name: "zero weight",
+			calc: brewing.MashPHCalculation{
+				Grains:            []brewing.MashGrain{{Weight: 10}, {Weight: 0}},
+				WaterToGristRatio: 1.5,
+			},
+			wantField: "grains[1].weight",
+		},
+		{
+			name: "unknown type",
+			calc: brewing.MashPHCalculation{
+				Grains:            []brewing.MashGrain{{Weight: 10, Type: "smoked"}},
+				WaterToGristRatio: 1.5,
+			},
+			wantField: "grains[0].type",
+		},
+		{
+			name:      "no mash thickness",
+			calc:      brewing.MashPHCalculation{Grains: []brewing.MashGrain{{Weight: 10}}},
+			wantField: "water_to_grist_ratio",
+		},
+		{
+			name: "target out of range",
+			calc: brewing.MashPHCalculation{
+				Grains:            []brewing.MashGrain{{Weight: 10}},
+				WaterToGristRatio: 1.5,
+				TargetPH:          8,
+			},
+			wantField: "target_ph",
+		},
+		{
+			name: "negative ion",
+			calc: brewing.MashPHCalculation{
+				Grains:            []brewing.MashGrain{{Weight: 10}},
+				Water:             brewing.WaterChemistry{Bicarbonate: -1},
+				WaterToGristRatio: 1.5,
+			},
+			wantField: "water_bicarbonate",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.calc.Calculate()
+			var validationErr *brewing.ValidationError
+			if !errors.As(err, &validationErr) || validationErr.Field != tt.wantField {
+				t.Errorf("expected a validation error for %q, got %v", tt.wantField, err)
+			}
+		})
+	}
+}
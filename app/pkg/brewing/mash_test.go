@@ -0,0 +1,106 @@
+package brewing_test
+
+import (
+	"testing"
+
+	"github.com/CharlRitter/brewsource-mcp/app/pkg/brewing"
+)
+
+func TestStrikeWaterCalculation_SingleInfusion(t *testing.T) {
+	calc := brewing.StrikeWaterCalculation{
+		GrainWeight:       10,
+		GrainTemp:         70,
+		TargetMashTemp:    152,
+		WaterToGristRatio: 1.5,
+		SpargeVolume:      4,
+	}
+
+	result, err := calc.Calculate()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Tw = (0.2/1.5)(152-70)+152 = 162.93°F
+	assertClose(t, result.StrikeTemp, 162.93, 0.01)
+	assertClose(t, result.StrikeVolume, 3.75, 1e-9)
+	assertClose(t, result.TotalWater, 7.75, 1e-9)
+	assertClose(t, result.GrainAbsorption, 1.25, 1e-9)
+	assertClose(t, result.PreBoilVolume, 6.5, 1e-9)
+	if len(result.Infusions) != 0 {
+		t.Errorf("expected no infusions, got %d", len(result.Infusions))
+	}
+}
+
+func TestStrikeWaterCalculation_TwoStep(t *testing.T) {
+	calc := brewing.StrikeWaterCalculation{
+		GrainWeight:       8,
+		GrainTemp:         72,
+		TargetMashTemp:    140,
+		WaterToGristRatio: 1.0,
+		StepTemps:         []float64{158},
+		InfusionWaterTemp: 210,
+	}
+
+	result, err := calc.Calculate()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Tw = (0.2/1)(140-72)+140 = 153.6°F
+	assertClose(t, result.StrikeTemp, 153.6, 1e-9)
+	if len(result.Infusions) != 1 {
+		t.Fatalf("expected one infusion, got %d", len(result.Infusions))
+	}
+	// Wa = (158-140)(0.2*8 + 8)/(210-158) = 3.32 qt = 0.83 gal
+	assertClose(t, result.Infusions[0].Volume, 0.8308, 0.0001)
+	assertClose(t, result.TotalWater, 2+0.8308, 0.0001)
+}
+
+func TestStrikeWaterCalculation_Defaults(t *testing.T) {
+	calc := brewing.StrikeWaterCalculation{
+		GrainWeight:       10,
+		GrainTemp:         68,
+		TargetMashTemp:    148,
+		WaterToGristRatio: 1.25,
+		StepTemps:         []float64{158, 168},
+	}
+
+	result, err := calc.Calculate()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Infusions) != 2 {
+		t.Fatalf("expected two infusions, got %d", len(result.Infusions))
+	}
+	if result.Infusions[1].Volume <= result.Infusions[0].Volume {
+		t.Error("expected the second infusion to need more water as the mash thickens")
+	}
+	assertClose(t, result.GrainAbsorption, 10*brewing.DefaultGrainAbsorption, 1e-9)
+}
+
+func TestStrikeWaterCalculation_Validation(t *testing.T) {
+	valid := brewing.StrikeWaterCalculation{
+		GrainWeight: 10, GrainTemp: 70, TargetMashTemp: 152, WaterToGristRatio: 1.5,
+	}
+	tests := []struct {
+		name   string
+		mutate func(c *brewing.StrikeWaterCalculation)
+	}{
+		{name: "zero grain weight", mutate: func(c *brewing.StrikeWaterCalculation) { c.GrainWeight = 0 }},
+		{name: "negative grain weight", mutate: func(c *brewing.StrikeWaterCalculation) { c.GrainWeight = -5 }},
+		{name: "zero ratio", mutate: func(c *brewing.StrikeWaterCalculation) { c.WaterToGristRatio = 0 }},
+		{name: "negative ratio", mutate: func(c *brewing.StrikeWaterCalculation) { c.WaterToGristRatio = -1 }},
+		{name: "negative sparge", mutate: func(c *brewing.StrikeWaterCalculation) { c.SpargeVolume = -1 }},
+		{name: "step below rest", mutate: func(c *brewing.StrikeWaterCalculation) { c.StepTemps = []float64{150} }},
+		{name: "step above infusion", mutate: func(c *brewing.StrikeWaterCalculation) { c.StepTemps = []float64{215} }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calc := valid
+			tt.mutate(&calc)
+			if _, err := calc.Calculate(); err == nil {
+				t.Error("expected validation error, got nil")
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+// Package brewing provides brewing calculations and unit conversions for Brewsource MCP.
+package brewing
+
+import (
+	"fmt"
+)
+
+// DefaultWortCorrectionFactor is the typical ratio between refractometer Brix and true Plato for wort.
+const DefaultWortCorrectionFactor = 1.04
+
+// RefractometerFormula selects the final gravity correction used for fermented samples.
+type RefractometerFormula string
+
+// Supported refractometer correction formulas, both from Sean Terrill.
+const (
+	RefractometerCubic  RefractometerFormula = "cubic"
+	RefractometerLinear RefractometerFormula = "linear"
+)
+
+// RefractometerCorrection corrects refractometer readings for the presence of alcohol.
+type RefractometerCorrection struct {
+	OriginalBrix         float64              // reading of the unfermented wort
+	FinalBrix            *float64             // reading after fermentation, nil when only OG is needed
+	WortCorrectionFactor float64              // defaults to DefaultWortCorrectionFactor
+	Formula              RefractometerFormula // defaults to RefractometerCubic
+}
+
+// RefractometerResult holds the corrected gravities. FG and ABV are only set when a final reading was given.
+type RefractometerResult struct {
+	OriginalGravity float64  `json:"original_gravity"`
+	FinalGravity    *float64 `json:"final_gravity,omitempty"`
+	ABV             *float64 `json:"abv,omitempty"`
+}
+
+// Calculate returns the original gravity and, when a final reading is present, the corrected FG and ABV.
+func (r RefractometerCorrection) Calculate() (*RefractometerResult, error) {
+	wcf := r.WortCorrectionFactor
+	if wcf == 0 {
+		wcf = DefaultWortCorrectionFactor
+	}
+	if err := r.validate(wcf); err != nil {
+		return nil, err
+	}
+
+	result := &RefractometerResult{OriginalGravity: BrixToGravity(r.OriginalBrix, wcf)}
+	if r.FinalBrix == nil {
+		return result, nil
+	}
+
+	ob := r.OriginalBrix / wcf
+	fb := *r.FinalBrix / wcf
+	var fg float64
+	switch r.Formula {
+	case "", RefractometerCubic:
+		fg = 1 - 0.0044993*ob + 0.011774*fb +
+			0.00027581*ob*ob - 0.0012717*fb*fb -
+			0.0000072800*ob*ob*ob + 0.000063293*fb*fb*fb
+	case RefractometerLinear:
+		fg = 1 - 0.00085683*ob + 0.0034941*fb
+	default:
+		return nil, &ValidationError{
+			Field:      "refractometer_formula",
+			Value:      r.Formula,
+			Constraint: "must be cubic or linear",
+		}
+	}
+	abv := (result.OriginalGravity - fg) * abvFactor
+	result.FinalGravity = &fg
+	result.ABV = &abv
+	return result, nil
+}
+
+// BrixToGravity converts an unfermented refractometer reading to specific gravity using the given
+// wort correction factor.
+func BrixToGravity(brix, wortCorrectionFactor float64) float64 {
+	corrected := brix / wortCorrectionFactor
+	return 1 + corrected/(258.6-(corrected/258.2)*227.1)
+}
+
+func (r RefractometerCorrection) validate(wcf float64) error {
+	switch {
+	case r.OriginalBrix <= 0:
+		return &ValidationError{Field: "original_brix", Value: r.OriginalBrix, Constraint: "must be greater than zero"}
+	case wcf < 0:
+		return &ValidationError{Field: "wort_correction_factor", Value: wcf, Constraint: "must be greater than zero"}
+	case r.FinalBrix == nil:
+		return nil
+	case *r.FinalBrix < 0:
+		return &ValidationError{Field: "final_brix", Value: *r.FinalBrix, Constraint: "cannot be negative"}
+	case *r.FinalBrix > r.OriginalBrix:
+		return &ValidationError{
+			Field:      "final_brix",
+			Value:      *r.FinalBrix,
+			Constraint: fmt.Sprintf("cannot exceed original Brix (%.1f)", r.OriginalBrix),
+		}
+	}
+	return nil
+}
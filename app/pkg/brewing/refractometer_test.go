@@ -0,0 +1,114 @@
+package brewing_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/CharlRitter/brewsource-mcp/app/pkg/brewing"
+)
+
+func TestBrixToGravity(t *testing.T) {
+	tests := []struct {
+		brix float64
+		wcf  float64
+		want float64
+	}{
+		{brix: 12, wcf: 1.0, want: 1.0484},
+		{brix: 12, wcf: 1.04, want: 1.0464},
+		{brix: 20, wcf: 1.04, want: 1.0796},
+		{brix: 0, wcf: 1.04, want: 1.0},
+	}
+	for _, tt := range tests {
+		assertClose(t, brewing.BrixToGravity(tt.brix, tt.wcf), tt.want, 0.0001)
+	}
+}
+
+func TestRefractometerCorrection_FinalGravity(t *testing.T) {
+	// Reference values from Sean Terrill's calculator with a 1.04 wort correction factor.
+	tests := []struct {
+		name         string
+		originalBrix float64
+		finalBrix    float64
+		formula      brewing.RefractometerFormula
+		wantOG       float64
+		wantFG       float64
+		wantABV      float64
+	}{
+		{name: "cubic 20/10", originalBrix: 20, finalBrix: 10, formula: brewing.RefractometerCubic,
+			wantOG: 1.080, wantFG: 1.0156, wantABV: 8.4},
+		{name: "cubic 12/6", originalBrix: 12, finalBrix: 6, formula: "",
+			wantOG: 1.046, wantFG: 1.0114, wantABV: 4.6},
+		{name: "linear 20/10", originalBrix: 20, finalBrix: 10, formula: brewing.RefractometerLinear,
+			wantOG: 1.080, wantFG: 1.0171, wantABV: 8.2},
+		{name: "linear 12/6", originalBrix: 12, finalBrix: 6, formula: brewing.RefractometerLinear,
+			wantOG: 1.046, wantFG: 1.0103, wantABV: 4.7},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := brewing.RefractometerCorrection{
+				OriginalBrix: tt.originalBrix,
+				FinalBrix:    float64Ptr(tt.finalBrix),
+				Formula:      tt.formula,
+			}.Calculate()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assertClose(t, result.OriginalGravity, tt.wantOG, 0.001)
+			assertClose(t, *result.FinalGravity, tt.wantFG, 0.0001)
+			assertClose(t, *result.ABV, tt.wantABV, 0.05)
+		})
+	}
+}
+
+func TestRefractometerCorrection_OriginalOnly(t *testing.T) {
+	result, err := brewing.RefractometerCorrection{OriginalBrix: 12, WortCorrectionFactor: 1.0}.Calculate()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertClose(t, result.OriginalGravity, 1.0484, 0.0001)
+	if result.FinalGravity != nil || result.ABV != nil {
+		t.Error("expected no FG or ABV without a final reading")
+	}
+}
+
+func TestRefractometerCorrection_Validation(t *testing.T) {
+	tests := []struct {
+		name    string
+		calc    brewing.RefractometerCorrection
+		wantErr string
+	}{
+		{
+			name:    "final exceeds original",
+			calc:    brewing.RefractometerCorrection{OriginalBrix: 10, FinalBrix: float64Ptr(12)},
+			wantErr: "cannot exceed original Brix",
+		},
+		{
+			name:    "zero original",
+			calc:    brewing.RefractometerCorrection{OriginalBrix: 0},
+			wantErr: "original brix must be greater than zero",
+		},
+		{
+			name:    "negative final",
+			calc:    brewing.RefractometerCorrection{OriginalBrix: 12, FinalBrix: float64Ptr(-1)},
+			wantErr: "final brix cannot be negative",
+		},
+		{
+			name:    "negative correction factor",
+			calc:    brewing.RefractometerCorrection{OriginalBrix: 12, WortCorrectionFactor: -1},
+			wantErr: "wort correction factor",
+		},
+		{
+			name:    "unknown formula",
+			calc:    brewing.RefractometerCorrection{OriginalBrix: 12, FinalBrix: float64Ptr(6), Formula: "quartic"},
+			wantErr: "refractometer formula must be",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.calc.Calculate()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,257 @@
+// Package brewing provides brewing calculations and unit conversions for Brewsource MCP.
+package brewing
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+const (
+	// LitersPerGallon is the number of liters in a US gallon.
+	LitersPerGallon = 3.785411784
+	// GramsPerOunce is the number of grams in an avoirdupois ounce.
+	GramsPerOunce = 28.349523125
+	// GramsPerPound is the number of grams in an avoirdupois pound.
+	GramsPerPound = 453.59237
+	// MillilitersPerFluidOunce is the number of milliliters in a US fluid ounce.
+	MillilitersPerFluidOunce = LitersPerGallon * 1000 / 128
+)
+
+// UnitSystem selects the units a calculation's inputs are given in. Calculations work in US units
+// internally and convert metric inputs on entry.
+type UnitSystem string
+
+// Supported unit systems. The zero value is treated as UnitsImperial.
+const (
+	UnitsImperial UnitSystem = "imperial"
+	UnitsMetric   UnitSystem = "metric"
+)
+
+func (u UnitSystem) validate() error {
+	switch u {
+	case "", UnitsImperial, UnitsMetric:
+		return nil
+	}
+	return &ValidationError{Field: "units", Value: string(u), Constraint: "must be imperial or metric"}
+}
+
+// ErrTemperatureRequired is returned when a conversion depends on beer temperature but none was given.
+var ErrTemperatureRequired = errors.New("temperature is required for CO2 pressure conversions")
+
+// UnsupportedConversionError is returned when a unit is unknown or two units measure different things.
+type UnsupportedConversionError struct {
+	From      string
+	To        string
+	Supported []string
+}
+
+// Error implements the error interface.
+func (e *UnsupportedConversionError) Error() string {
+	return fmt.Sprintf("cannot convert from %q to %q", e.From, e.To)
+}
+
+// Conditions carries optional context needed by some conversions.
+type Conditions struct {
+	// TemperatureF is the beer temperature in °F, required for psi ↔ CO2 volumes.
+	TemperatureF *float64
+}
+
+// unit describes how to convert a unit to and from the base unit of its dimension.
+type unit struct {
+	dimension string
+	precision int
+	toBase    func(value float64, c Conditions) (float64, error)
+	fromBase  func(value float64, c Conditions) (float64, error)
+}
+
+// Base units per dimension: SG, °F, US gallons, ounces, SRM, and CO2 volumes.
+//
+//nolint:gochecknoglobals // read-only conversion table
+var units = map[string]unit{
+	"sg":     {dimension: "gravity", precision: 3, toBase: identity, fromBase: identity},
+	"plato":  {dimension: "gravity", precision: 1, toBase: pure(PlatoToGravity), fromBase: pure(GravityToPlato)},
+	"brix":   {dimension: "gravity", precision: 1, toBase: pure(PlatoToGravity), fromBase: pure(GravityToPlato)},
+	"points": {dimension: "gravity", precision: 0, toBase: pure(PointsToGravity), fromBase: pure(GravityPoint)},
+	"f":      {dimension: "temperature", precision: 1, toBase: identity, fromBase: identity},
+	"c": {
+		dimension: "temperature", precision: 1,
+		toBase: pure(CelsiusToFahrenheit), fromBase: pure(FahrenheitToCelsius),
+	},
+	"gal": {dimension: "volume", precision: 2, toBase: identity, fromBase: identity},
+	"qt":  {dimension: "volume", precision: 2, toBase: scale(1.0 / 4), fromBase: scale(4)},
+	"l":   {dimension: "volume", precision: 2, toBase: scale(1 / LitersPerGallon), fromBase: scale(LitersPerGallon)},
+	"ml": {
+		dimension: "volume", precision: 0,
+		toBase: scale(1 / (LitersPerGallon * 1000)), fromBase: scale(LitersPerGallon * 1000),
+	},
+	"oz": {dimension: "weight", precision: 2, toBase: identity, fromBase: identity},
+	"lb": {dimension: "weight", precision: 2, toBase: scale(16), fromBase: scale(1.0 / 16)},
+	"g":  {dimension: "weight", precision: 1, toBase: scale(1 / GramsPerOunce), fromBase: scale(GramsPerOunce)},
+	"kg": {
+		dimension: "weight", precision: 3,
+		toBase: scale(1000 / GramsPerOunce), fromBase: scale(GramsPerOunce / 1000),
+	},
+	"srm":      {dimension: "color", precision: 1, toBase: identity, fromBase: identity},
+	"ebc":      {dimension: "color", precision: 1, toBase: pure(EBCToSRM), fromBase: pure(SRMToEBC)},
+	"lovibond": {dimension: "color", precision: 1, toBase: pure(LovibondToSRM), fromBase: pure(SRMToLovibond)},
+	"volumes":  {dimension: "carbonation", precision: 2, toBase: identity, fromBase: identity},
+	"psi":      {dimension: "carbonation", precision: 1, toBase: psiToVolumes, fromBase: volumesToPSI},
+}
+
+// unitAliases maps accepted spellings onto canonical unit names.
+//
+//nolint:gochecknoglobals // read-only alias table
+var unitAliases = map[string]string{
+	"specific_gravity": "sg", "gravity": "sg", "°p": "plato", "p": "plato", "°bx": "brix", "bx": "brix",
+	"gravity_points": "points", "gu": "points",
+	"°f": "f", "fahrenheit": "f", "°c": "c", "celsius": "c",
+	"gallon": "gal", "gallons": "gal", "quart": "qt", "quarts": "qt",
+	"liter": "l", "liters": "l", "litre": "l", "litres": "l", "milliliter": "ml", "milliliters": "ml",
+	"ounce": "oz", "ounces": "oz", "pound": "lb", "pounds": "lb", "lbs": "lb",
+	"gram": "g", "grams": "g", "kilogram": "kg", "kilograms": "kg",
+	"°l": "lovibond", "degrees_lovibond": "lovibond",
+	"vol": "volumes", "co2_volumes": "volumes",
+}
+
+// SupportedUnits returns the canonical unit names accepted by ConvertUnits, sorted by dimension.
+func SupportedUnits() []string {
+	names := make([]string, 0, len(units))
+	for name := range units {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if units[names[i]].dimension != units[names[j]].dimension {
+			return units[names[i]].dimension < units[names[j]].dimension
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+// NormalizeUnit resolves aliases and casing to a canonical unit name.
+func NormalizeUnit(name string) string {
+	normalized := strings.ToLower(strings.TrimSpace(name))
+	if canonical, ok := unitAliases[normalized]; ok {
+		return canonical
+	}
+	return normalized
+}
+
+// UnitPrecision returns the number of decimal places conventionally used for a unit.
+func UnitPrecision(name string) int {
+	return units[NormalizeUnit(name)].precision
+}
+
+// ConvertUnits converts a value between two units of the same dimension.
+func ConvertUnits(value float64, from, to string, c Conditions) (float64, error) {
+	fromUnit, okFrom := units[NormalizeUnit(from)]
+	toUnit, okTo := units[NormalizeUnit(to)]
+	if !okFrom || !okTo || fromUnit.dimension != toUnit.dimension {
+		return 0, &UnsupportedConversionError{From: from, To: to, Supported: SupportedUnits()}
+	}
+
+	base, err := fromUnit.toBase(value, c)
+	if err != nil {
+		return 0, err
+	}
+	return toUnit.fromBase(base, c)
+}
+
+// GravityToPlato converts specific gravity to degrees Plato.
+func GravityToPlato(sg float64) float64 {
+	return -616.868 + 1111.14*sg - 630.272*sg*sg + 135.997*sg*sg*sg
+}
+
+// PlatoToGravity converts degrees Plato to specific gravity.
+func PlatoToGravity(plato float64) float64 {
+	return 1 + plato/(258.6-(plato/258.2)*227.1)
+}
+
+// GravityPoint converts specific gravity to gravity points (e.g., 1.050 → 50).
+func GravityPoint(sg float64) float64 {
+	return (sg - 1) * 1000
+}
+
+// PointsToGravity converts gravity points to specific gravity (e.g., 50 → 1.050).
+func PointsToGravity(points float64) float64 {
+	return 1 + points/1000
+}
+
+// SRMToEBC converts SRM colour to EBC.
+func SRMToEBC(srm float64) float64 {
+	return srm * 1.97
+}
+
+// EBCToSRM converts EBC colour to SRM.
+func EBCToSRM(ebc float64) float64 {
+	return ebc * 0.508
+}
+
+// SRMToLovibond converts SRM colour to degrees Lovibond.
+func SRMToLovibond(srm float64) float64 {
+	return (srm + 0.76) / 1.3546
+}
+
+// LovibondToSRM converts degrees Lovibond to SRM colour.
+func LovibondToSRM(lovibond float64) float64 {
+	return 1.3546*lovibond - 0.76
+}
+
+// FahrenheitToCelsius converts °F to °C.
+func FahrenheitToCelsius(f float64) float64 {
+	return (f - 32) * 5 / 9
+}
+
+// CelsiusToFahrenheit converts °C to °F.
+func CelsiusToFahrenheit(c float64) float64 {
+	return c*9/5 + 32
+}
+
+// CO2VolumesToPSI returns the keg head pressure (psi gauge) that holds the given CO2 volumes
+// at the given beer temperature, using the standard regression of the ASBC carbonation chart.
+func CO2VolumesToPSI(volumes, tempF float64) float64 {
+	return -16.6999 - 0.0101059*tempF + 0.00116512*tempF*tempF +
+		0.173354*tempF*volumes + 4.24267*volumes - 0.0684226*volumes*volumes
+}
+
+// PSIToCO2Volumes inverts CO2VolumesToPSI, returning the CO2 volumes held at the given pressure and temperature.
+func PSIToCO2Volumes(psi, tempF float64) float64 {
+	// Solve a*v^2 + b*v + c = 0 and take the root within the chart's range.
+	a := -0.0684226
+	b := 0.173354*tempF + 4.24267
+	c := -16.6999 - 0.0101059*tempF + 0.00116512*tempF*tempF - psi
+	return (-b + math.Sqrt(b*b-4*a*c)) / (2 * a)
+}
+
+func identity(value float64, _ Conditions) (float64, error) {
+	return value, nil
+}
+
+func pure(fn func(float64) float64) func(float64, Conditions) (float64, error) {
+	return func(value float64, _ Conditions) (float64, error) {
+		return fn(value), nil
+	}
+}
+
+func scale(factor float64) func(float64, Conditions) (float64, error) {
+	return func(value float64, _ Conditions) (float64, error) {
+		return value * factor, nil
+	}
+}
+
+func psiToVolumes(psi float64, c Conditions) (float64, error) {
+	if c.TemperatureF == nil {
+		return 0, ErrTemperatureRequired
+	}
+	return PSIToCO2Volumes(psi, *c.TemperatureF), nil
+}
+
+func volumesToPSI(volumes float64, c Conditions) (float64, error) {
+	if c.TemperatureF == nil {
+		return 0, ErrTemperatureRequired
+	}
+	return CO2VolumesToPSI(volumes, *c.TemperatureF), nil
+}
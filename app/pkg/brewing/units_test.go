@@ -0,0 +1,118 @@
+// Package brewing_test contains tests for the brewing calculations in Brewsource MCP.
+package brewing_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/CharlRitter/brewsource-mcp/app/pkg/brewing"
+)
+
+func float64Ptr(v float64) *float64 {
+	return &v
+}
+
+func assertClose(t *testing.T, got, want, tolerance float64) {
+	t.Helper()
+	if math.Abs(got-want) > tolerance {
+		t.Errorf("got %v, want %v (±%v)", got, want, tolerance)
+	}
+}
+
+func TestGravityPlatoConversions(t *testing.T) {
+	tests := []struct {
+		sg    float64
+		plato float64
+	}{
+		{sg: 1.000, plato: 0},
+		{sg: 1.040, plato: 10.0},
+		{sg: 1.050, plato: 12.4},
+		{sg: 1.080, plato: 19.3},
+	}
+	for _, tt := range tests {
+		assertClose(t, brewing.GravityToPlato(tt.sg), tt.plato, 0.1)
+		assertClose(t, brewing.PlatoToGravity(tt.plato), tt.sg, 0.001)
+	}
+}
+
+func TestColorAndPointHelpers(t *testing.T) {
+	assertClose(t, brewing.SRMToEBC(10), 19.7, 1e-9)
+	assertClose(t, brewing.EBCToSRM(19.7), 10, 0.01)
+	assertClose(t, brewing.LovibondToSRM(brewing.SRMToLovibond(12)), 12, 1e-9)
+	assertClose(t, brewing.GravityPoint(1.050), 50, 1e-9)
+	assertClose(t, brewing.PointsToGravity(50), 1.050, 1e-12)
+}
+
+func TestCO2PressureRoundTrip(t *testing.T) {
+	// 2.5 volumes at 38°F needs roughly 11-12 psi on a typical carbonation chart.
+	psi := brewing.CO2VolumesToPSI(2.5, 38)
+	assertClose(t, psi, 11.3, 0.5)
+	assertClose(t, brewing.PSIToCO2Volumes(psi, 38), 2.5, 1e-9)
+}
+
+func TestConvertUnits(t *testing.T) {
+	tests := []struct {
+		name  string
+		value float64
+		from  string
+		to    string
+		cond  brewing.Conditions
+		want  float64
+		tol   float64
+	}{
+		{name: "fahrenheit to celsius", value: 152, from: "F", to: "c", want: 66.67, tol: 0.01},
+		{name: "celsius alias", value: 100, from: "celsius", to: "°F", want: 212, tol: 1e-9},
+		{name: "gallons to liters", value: 5, from: "gal", to: "L", want: 18.93, tol: 0.01},
+		{name: "liters to quarts", value: 1, from: "liters", to: "qt", want: 1.057, tol: 0.001},
+		{name: "ounces to grams", value: 1, from: "oz", to: "g", want: 28.35, tol: 0.01},
+		{name: "pounds to kilograms", value: 10, from: "lb", to: "kg", want: 4.536, tol: 0.001},
+		{name: "sg to plato", value: 1.048, from: "sg", to: "plato", want: 11.9, tol: 0.1},
+		{name: "brix to sg", value: 12, from: "brix", to: "sg", want: 1.048, tol: 0.001},
+		{name: "sg to points", value: 1.065, from: "sg", to: "gu", want: 65, tol: 1e-9},
+		{name: "srm to ebc", value: 5, from: "srm", to: "ebc", want: 9.85, tol: 1e-9},
+		{name: "same unit", value: 3, from: "volumes", to: "vol", want: 3, tol: 0},
+		{
+			name: "volumes to psi", value: 2.5, from: "volumes", to: "psi",
+			cond: brewing.Conditions{TemperatureF: float64Ptr(38)}, want: 11.3, tol: 0.5,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := brewing.ConvertUnits(tt.value, tt.from, tt.to, tt.cond)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assertClose(t, got, tt.want, tt.tol)
+		})
+	}
+}
+
+func TestConvertUnits_Errors(t *testing.T) {
+	_, err := brewing.ConvertUnits(1, "gal", "kg", brewing.Conditions{})
+	var unsupported *brewing.UnsupportedConversionError
+	if !errors.As(err, &unsupported) {
+		t.Fatalf("expected UnsupportedConversionError, got %v", err)
+	}
+	if len(unsupported.Supported) != len(brewing.SupportedUnits()) {
+		t.Errorf("expected supported units to be listed, got %v", unsupported.Supported)
+	}
+
+	if _, err = brewing.ConvertUnits(1, "furlongs", "gal", brewing.Conditions{}); !errors.As(err, &unsupported) {
+		t.Errorf("expected UnsupportedConversionError for unknown unit, got %v", err)
+	}
+
+	_, err = brewing.ConvertUnits(12, "psi", "volumes", brewing.Conditions{})
+	if !errors.Is(err, brewing.ErrTemperatureRequired) {
+		t.Errorf("expected ErrTemperatureRequired, got %v", err)
+	}
+}
+
+func TestUnitPrecision(t *testing.T) {
+	if brewing.UnitPrecision("SG") != 3 {
+		t.Errorf("expected 3 decimals for SG, got %d", brewing.UnitPrecision("SG"))
+	}
+	if brewing.UnitPrecision("liters") != 2 {
+		t.Errorf("expected 2 decimals for liters, got %d", brewing.UnitPrecision("liters"))
+	}
+}
@@ -0,0 +1,205 @@
+// Package brewing provides brewing calculations and unit conversions for Brewsource MCP.
+package brewing
+
+import (
+	"fmt"
+)
+
+const (
+	// DefaultBoilTime is the boil length assumed when none is given, in minutes.
+	DefaultBoilTime = 60
+	// DefaultShrinkagePercent is the volume lost as hot wort cools to pitching temperature.
+	DefaultShrinkagePercent = 4
+	minutesPerHour          = 60
+)
+
+// BoilVolumeCalculation works back from the volume wanted in the fermenter to the pre-boil volume, in US units.
+// Exactly one of EvaporationPercent or EvaporationVolume should be set.
+type BoilVolumeCalculation struct {
+	TargetVolume       float64 // gallons into the fermenter
+	BoilTime           float64 // minutes, defaults to DefaultBoilTime
+	EvaporationPercent float64 // percent of pre-boil volume boiled off per hour
+	EvaporationVolume  float64 // gallons boiled off per hour
+	ShrinkagePercent   float64 // percent volume lost on cooling
+	TrubLoss           float64 // gallons left behind in the kettle
+}
+
+// BoilVolumeResult breaks down the volumes from kettle to fermenter.
+type BoilVolumeResult struct {
+	PreBoilVolume   float64 `json:"pre_boil_volume"`
+	Evaporation     float64 `json:"evaporation"`
+	PostBoilVolume  float64 `json:"post_boil_volume"` // hot, at the end of the boil
+	Shrinkage       float64 `json:"shrinkage"`
+	TrubLoss        float64 `json:"trub_loss"`
+	FermenterVolume float64 `json:"fermenter_volume"`
+}
+
+// Calculate returns the pre-boil volume needed to hit TargetVolume after trub loss, cooling shrinkage,
+// and evaporation. Percentage evaporation is applied to the pre-boil volume.
+func (c BoilVolumeCalculation) Calculate() (*BoilVolumeResult, error) {
+	if err := c.validate(); err != nil {
+		return nil, err
+	}
+	boilTime := c.BoilTime
+	if boilTime == 0 {
+		boilTime = DefaultBoilTime
+	}
+	hours := boilTime / minutesPerHour
+
+	cooled := c.TargetVolume + c.TrubLoss
+	postBoil := cooled / (1 - c.ShrinkagePercent/100)
+
+	var preBoil float64
+	if c.EvaporationPercent > 0 {
+		boiledOff := c.EvaporationPercent / 100 * hours
+		if boiledOff >= 1 {
+			return nil, &ValidationError{
+				Field:      "evaporation_percent",
+				Value:      c.EvaporationPercent,
+				Constraint: fmt.Sprintf("would boil the kettle dry over %.0f minutes", boilTime),
+			}
+		}
+		preBoil = postBoil / (1 - boiledOff)
+	} else {
+		preBoil = postBoil + c.EvaporationVolume*hours
+	}
+
+	return &BoilVolumeResult{
+		PreBoilVolume:   preBoil,
+		Evaporation:     preBoil - postBoil,
+		PostBoilVolume:  postBoil,
+		Shrinkage:       postBoil - cooled,
+		TrubLoss:        c.TrubLoss,
+		FermenterVolume: c.TargetVolume,
+	}, nil
+}
+
+func (c BoilVolumeCalculation) validate() error {
+	switch {
+	case c.TargetVolume <= 0:
+		return &ValidationError{Field: "target_volume", Value: c.TargetVolume, Constraint: "must be greater than zero"}
+	case c.BoilTime < 0:
+		return &ValidationError{Field: "boil_time", Value: c.BoilTime, Constraint: "cannot be negative"}
+	case c.EvaporationPercent < 0:
+		return &ValidationError{
+			Field:      "evaporation_percent",
+			Value:      c.EvaporationPercent,
+			Constraint: "cannot be negative",
+		}
+	case c.EvaporationVolume < 0:
+		return &ValidationError{
+			Field:      "evaporation_volume",
+			Value:      c.EvaporationVolume,
+			Constraint: "cannot be negative",
+		}
+	case c.EvaporationPercent > 100:
+		return &ValidationError{
+			Field:      "evaporation_percent",
+			Value:      c.EvaporationPercent,
+			Constraint: "cannot exceed 100% per hour",
+		}
+	case c.EvaporationPercent > 0 && c.EvaporationVolume > 0:
+		return &ValidationError{
+			Field:      "evaporation_volume",
+			Value:      c.EvaporationVolume,
+			Constraint: "cannot be set together with a percentage evaporation rate",
+		}
+	case c.ShrinkagePercent < 0 || c.ShrinkagePercent >= 100:
+		return &ValidationError{
+			Field:      "shrinkage_percent",
+			Value:      c.ShrinkagePercent,
+			Constraint: "must be between 0 and 100 percent",
+		}
+	case c.TrubLoss < 0:
+		return &ValidationError{Field: "trub_loss", Value: c.TrubLoss, Constraint: "cannot be negative"}
+	}
+	return nil
+}
+
+// Wort is a volume of wort (gallons or liters, consistently) at a specific gravity.
+type Wort struct {
+	Volume  float64 `json:"volume"`
+	Gravity float64 `json:"gravity"`
+}
+
+// DilutionCalculation finds the water needed to bring a wort down to a target gravity.
+type DilutionCalculation struct {
+	Wort          Wort
+	TargetGravity float64
+}
+
+// DilutionResult holds the water to add and the diluted wort.
+type DilutionResult struct {
+	WaterToAdd float64 `json:"water_to_add"`
+	Final      Wort    `json:"final"`
+}
+
+// Calculate returns the water to add, in the same volume unit as the input wort.
+// Gravity points are conserved: V1 × P1 = V2 × P2.
+func (d DilutionCalculation) Calculate() (*DilutionResult, error) {
+	if err := d.Wort.validate(); err != nil {
+		return nil, err
+	}
+	switch {
+	case d.TargetGravity < 1:
+		return nil, &ValidationError{
+			Field:      "target_gravity",
+			Value:      d.TargetGravity,
+			Constraint: "must be at least 1.000",
+		}
+	case d.TargetGravity > d.Wort.Gravity:
+		return nil, &ValidationError{
+			Field: "target_gravity",
+			Value: d.TargetGravity,
+			Constraint: fmt.Sprintf(
+				"cannot be above the current gravity %.3f; adding water can only lower it", d.Wort.Gravity),
+		}
+	case d.TargetGravity == 1 && d.Wort.Gravity > 1:
+		return nil, &ValidationError{
+			Field:      "target_gravity",
+			Value:      d.TargetGravity,
+			Constraint: "must be above 1.000; no volume of water dilutes wort to plain water",
+		}
+	}
+
+	finalVolume := d.Wort.Volume
+	if d.TargetGravity < d.Wort.Gravity {
+		finalVolume = d.Wort.Volume * GravityPoint(d.Wort.Gravity) / GravityPoint(d.TargetGravity)
+	}
+	return &DilutionResult{
+		WaterToAdd: finalVolume - d.Wort.Volume,
+		Final:      Wort{Volume: finalVolume, Gravity: d.TargetGravity},
+	}, nil
+}
+
+// BlendWorts returns the volume and gravity of two or more worts mixed together.
+func BlendWorts(worts ...Wort) (Wort, error) {
+	if len(worts) < 2 {
+		return Wort{}, &ValidationError{
+			Field:      "worts",
+			Value:      len(worts),
+			Constraint: "must include at least two worts to blend",
+		}
+	}
+	blend := Wort{}
+	points := 0.0
+	for _, w := range worts {
+		if err := w.validate(); err != nil {
+			return Wort{}, err
+		}
+		blend.Volume += w.Volume
+		points += w.Volume * GravityPoint(w.Gravity)
+	}
+	blend.Gravity = PointsToGravity(points / blend.Volume)
+	return blend, nil
+}
+
+func (w Wort) validate() error {
+	switch {
+	case w.Volume <= 0:
+		return &ValidationError{Field: "wort_volume", Value: w.Volume, Constraint: "must be greater than zero"}
+	case w.Gravity < 1:
+		return &ValidationError{Field: "wort_gravity", Value: w.Gravity, Constraint: "must be at least 1.000"}
+	}
+	return nil
+}
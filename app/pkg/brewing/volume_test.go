@@ -0,0 +1,146 @@
+package brewing_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/CharlRitter/brewsource-mcp/app/pkg/brewing"
+)
+
+func TestBoilVolumeCalculation(t *testing.T) {
+	tests := []struct {
+		name        string
+		calc        brewing.BoilVolumeCalculation
+		wantPreBoil float64
+		wantEvap    float64
+		wantPost    float64
+	}{
+		{
+			name:        "volume rate",
+			calc:        brewing.BoilVolumeCalculation{TargetVolume: 5, EvaporationVolume: 1, TrubLoss: 0.25},
+			wantPreBoil: 6.25, wantEvap: 1, wantPost: 5.25,
+		},
+		{
+			name: "volume rate with shrinkage and a 90 minute boil",
+			calc: brewing.BoilVolumeCalculation{
+				TargetVolume: 5, BoilTime: 90, EvaporationVolume: 1, ShrinkagePercent: 4, TrubLoss: 0.25,
+			},
+			wantPreBoil: 6.96875, wantEvap: 1.5, wantPost: 5.46875,
+		},
+		{
+			name:        "percentage rate",
+			calc:        brewing.BoilVolumeCalculation{TargetVolume: 5, EvaporationPercent: 10},
+			wantPreBoil: 5.5556, wantEvap: 0.5556, wantPost: 5,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.calc.Calculate()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assertClose(t, result.PreBoilVolume, tt.wantPreBoil, 0.0001)
+			assertClose(t, result.Evaporation, tt.wantEvap, 0.0001)
+			assertClose(t, result.PostBoilVolume, tt.wantPost, 0.0001)
+			assertClose(t, result.FermenterVolume, tt.calc.TargetVolume, 1e-9)
+		})
+	}
+}
+
+func TestBoilVolumeCalculation_Validation(t *testing.T) {
+	tests := []struct {
+		name    string
+		calc    brewing.BoilVolumeCalculation
+		wantErr string
+	}{
+		{name: "zero volume", calc: brewing.BoilVolumeCalculation{EvaporationVolume: 1},
+			wantErr: "target volume"},
+		{name: "negative volume", calc: brewing.BoilVolumeCalculation{TargetVolume: -5},
+			wantErr: "target volume"},
+		{name: "evaporation over 100%", calc: brewing.BoilVolumeCalculation{TargetVolume: 5, EvaporationPercent: 101},
+			wantErr: "cannot exceed 100%"},
+		{name: "boils dry", calc: brewing.BoilVolumeCalculation{TargetVolume: 5, BoilTime: 120, EvaporationPercent: 50},
+			wantErr: "boil the kettle dry"},
+		{name: "negative rate", calc: brewing.BoilVolumeCalculation{TargetVolume: 5, EvaporationVolume: -1},
+			wantErr: "cannot be negative"},
+		{name: "both rates", calc: brewing.BoilVolumeCalculation{
+			TargetVolume: 5, EvaporationVolume: 1, EvaporationPercent: 10},
+			wantErr: "cannot be set together with a percentage"},
+		{name: "negative trub loss", calc: brewing.BoilVolumeCalculation{TargetVolume: 5, TrubLoss: -1},
+			wantErr: "trub loss"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.calc.Calculate()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestDilutionCalculation(t *testing.T) {
+	result, err := brewing.DilutionCalculation{
+		Wort:          brewing.Wort{Volume: 5, Gravity: 1.060},
+		TargetGravity: 1.050,
+	}.Calculate()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertClose(t, result.WaterToAdd, 1.0, 1e-9)
+	assertClose(t, result.Final.Volume, 6.0, 1e-9)
+
+	unchanged, err := brewing.DilutionCalculation{
+		Wort:          brewing.Wort{Volume: 5, Gravity: 1.050},
+		TargetGravity: 1.050,
+	}.Calculate()
+	if err != nil || unchanged.WaterToAdd != 0 {
+		t.Errorf("expected no water for a wort already at target, got %+v, %v", unchanged, err)
+	}
+}
+
+func TestDilutionCalculation_Validation(t *testing.T) {
+	tests := []struct {
+		name    string
+		calc    brewing.DilutionCalculation
+		wantErr string
+	}{
+		{name: "target above current", calc: brewing.DilutionCalculation{
+			Wort: brewing.Wort{Volume: 5, Gravity: 1.050}, TargetGravity: 1.060}, wantErr: "can only lower it"},
+		{name: "zero volume", calc: brewing.DilutionCalculation{
+			Wort: brewing.Wort{Volume: 0, Gravity: 1.050}, TargetGravity: 1.040}, wantErr: "wort volume"},
+		{name: "target of water", calc: brewing.DilutionCalculation{
+			Wort: brewing.Wort{Volume: 5, Gravity: 1.050}, TargetGravity: 1.000}, wantErr: "no volume of water"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.calc.Calculate()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestBlendWorts(t *testing.T) {
+	blend, err := brewing.BlendWorts(
+		brewing.Wort{Volume: 3, Gravity: 1.080},
+		brewing.Wort{Volume: 2, Gravity: 1.030},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertClose(t, blend.Volume, 5, 1e-9)
+	assertClose(t, blend.Gravity, 1.060, 1e-9)
+	if blend.Gravity <= 1.030 || blend.Gravity >= 1.080 {
+		t.Errorf("expected blended gravity between the inputs, got %.3f", blend.Gravity)
+	}
+
+	if _, err = brewing.BlendWorts(brewing.Wort{Volume: 3, Gravity: 1.080}); err == nil {
+		t.Error("expected an error when blending a single wort")
+	}
+	_, err = brewing.BlendWorts(brewing.Wort{Volume: 3, Gravity: 1.080}, brewing.Wort{Volume: -1, Gravity: 1.040})
+	if err == nil {
+		t.Error("expected an error for a negative volume")
+	}
+}
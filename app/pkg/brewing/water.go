@@ -0,0 +1,216 @@
+// Package brewing provides brewing calculations and unit conversions for Brewsource MCP.
+package brewing
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// WaterChemistry is a water profile in ppm (mg/L).
+type WaterChemistry struct {
+	Calcium     float64 `json:"calcium"`
+	Magnesium   float64 `json:"magnesium"`
+	Sodium      float64 `json:"sodium"`
+	Sulfate     float64 `json:"sulfate"`
+	Chloride    float64 `json:"chloride"`
+	Bicarbonate float64 `json:"bicarbonate"`
+}
+
+// ResidualAlkalinity returns RA in ppm as CaCO3: alkalinity - (Ca/3.5 + Mg/7).
+func (w WaterChemistry) ResidualAlkalinity() float64 {
+	alkalinity := w.Bicarbonate * 50 / 61
+	return alkalinity - (w.Calcium/3.5 + w.Magnesium/7)
+}
+
+// SulfateChlorideRatio returns the sulfate to chloride ratio, or 0 when the water has no chloride.
+func (w WaterChemistry) SulfateChlorideRatio() float64 {
+	if w.Chloride == 0 {
+		return 0
+	}
+	return w.Sulfate / w.Chloride
+}
+
+// Salt is a brewing salt that can be added to adjust a water profile.
+type Salt string
+
+// Supported brewing salts.
+const (
+	SaltGypsum          Salt = "gypsum"
+	SaltCalciumChloride Salt = "calcium_chloride"
+	SaltEpsom           Salt = "epsom_salt"
+	SaltBakingSoda      Salt = "baking_soda"
+	SaltChalk           Salt = "chalk"
+)
+
+// saltContributions is the ppm each salt adds per gram dissolved in one liter. Calcium chloride is the
+// dihydrate and chalk is assumed fully dissolved as bicarbonate.
+//
+//nolint:gochecknoglobals // static lookup table
+var saltContributions = []struct {
+	salt Salt
+	ions WaterChemistry
+}{
+	{SaltGypsum, WaterChemistry{Calcium: 232.8, Sulfate: 557.9}},
+	{SaltCalciumChloride, WaterChemistry{Calcium: 272.6, Chloride: 482.3}},
+	{SaltEpsom, WaterChemistry{Magnesium: 98.6, Sulfate: 389.7}},
+	{SaltBakingSoda, WaterChemistry{Sodium: 273.6, Bicarbonate: 726.4}},
+	{SaltChalk, WaterChemistry{Calcium: 400.4, Bicarbonate: 1219.3}},
+}
+
+const (
+	// saltSolverIterations is ample for coordinate descent to converge on five salts.
+	saltSolverIterations = 500
+	// minSaltGrams drops additions too small to weigh on a typical brewing scale.
+	minSaltGrams = 0.01
+)
+
+// SaltAddition is the weight of one salt to add.
+type SaltAddition struct {
+	Salt  Salt    `json:"salt"`
+	Grams float64 `json:"grams"`
+}
+
+// IonResult compares the achieved and target concentration of one ion.
+type IonResult struct {
+	Ion        string  `json:"ion"`
+	Source     float64 `json:"source"`
+	Target     float64 `json:"target"`
+	Achieved   float64 `json:"achieved"`
+	Residual   float64 `json:"residual"`   // achieved - target
+	Infeasible bool    `json:"infeasible"` // the source already exceeds the target
+}
+
+// SaltAdditionResult holds the salts to add and how close they get to the target.
+type SaltAdditionResult struct {
+	Additions []SaltAddition `json:"additions"`
+	Achieved  WaterChemistry `json:"achieved"`
+	Ions      []IonResult    `json:"ions"`
+}
+
+// SaltAdditions solves for the salts that move the water toward target when dissolved in volumeLiters.
+//
+// The solver is a non-negative least squares fit of the five salts to the ion shortfall, in ppm,
+// using projected coordinate descent. Salts can only add ions, so an ion whose source concentration
+// already exceeds the target is reported as infeasible rather than failing the whole calculation;
+// the other ions are still matched as closely as possible.
+func (w WaterChemistry) SaltAdditions(target WaterChemistry, volumeLiters float64) (*SaltAdditionResult, error) {
+	if volumeLiters <= 0 {
+		return nil, &ValidationError{Field: "volume", Value: volumeLiters, Constraint: "must be greater than zero"}
+	}
+	if err := w.validate("source"); err != nil {
+		return nil, err
+	}
+	if err := target.validate("target"); err != nil {
+		return nil, err
+	}
+
+	// residual starts as the shortfall of each ion and shrinks as salts are dosed.
+	source := w.ions()
+	residual := target.ions()
+	for i := range residual {
+		residual[i] = math.Max(0, residual[i]-source[i])
+	}
+
+	dosages := make([]float64, len(saltContributions)) // grams per liter
+	for range saltSolverIterations {
+		for j, salt := range saltContributions {
+			column := salt.ions.ions()
+			dot, norm := 0.0, 0.0
+			for i := range column {
+				dot += column[i] * residual[i]
+				norm += column[i] * column[i]
+			}
+			updated := math.Max(0, dosages[j]+dot/norm)
+			for i := range column {
+				residual[i] -= column[i] * (updated - dosages[j])
+			}
+			dosages[j] = updated
+		}
+	}
+
+	result := &SaltAdditionResult{Achieved: w}
+	for j, salt := range saltContributions {
+		if dosages[j]*volumeLiters < minSaltGrams {
+			continue
+		}
+		result.Additions = append(result.Additions, SaltAddition{Salt: salt.salt, Grams: dosages[j] * volumeLiters})
+		result.Achieved = result.Achieved.add(salt.ions, dosages[j])
+	}
+	achieved, targets := result.Achieved.ions(), target.ions()
+	for i, name := range ionNames {
+		result.Ions = append(result.Ions, IonResult{
+			Ion:        name,
+			Source:     source[i],
+			Target:     targets[i],
+			Achieved:   achieved[i],
+			Residual:   achieved[i] - targets[i],
+			Infeasible: source[i] > targets[i],
+		})
+	}
+	return result, nil
+}
+
+// ionNames is the order used by WaterChemistry.ions.
+//
+//nolint:gochecknoglobals // static lookup table
+var ionNames = []string{"calcium", "magnesium", "sodium", "sulfate", "chloride", "bicarbonate"}
+
+func (w WaterChemistry) ions() []float64 {
+	return []float64{w.Calcium, w.Magnesium, w.Sodium, w.Sulfate, w.Chloride, w.Bicarbonate}
+}
+
+func (w WaterChemistry) add(ions WaterChemistry, gramsPerLiter float64) WaterChemistry {
+	return WaterChemistry{
+		Calcium:     w.Calcium + ions.Calcium*gramsPerLiter,
+		Magnesium:   w.Magnesium + ions.Magnesium*gramsPerLiter,
+		Sodium:      w.Sodium + ions.Sodium*gramsPerLiter,
+		Sulfate:     w.Sulfate + ions.Sulfate*gramsPerLiter,
+		Chloride:    w.Chloride + ions.Chloride*gramsPerLiter,
+		Bicarbonate: w.Bicarbonate + ions.Bicarbonate*gramsPerLiter,
+	}
+}
+
+func (w WaterChemistry) validate(label string) error {
+	for i, value := range w.ions() {
+		if value < 0 {
+			return &ValidationError{Field: label + "_" + ionNames[i], Value: value, Constraint: "cannot be negative"}
+		}
+	}
+	return nil
+}
+
+// waterProfiles are classic brewing city profiles, in ppm.
+//
+//nolint:gochecknoglobals // static lookup table
+var waterProfiles = map[string]WaterChemistry{
+	"burton":    {Calcium: 352, Magnesium: 24, Sodium: 54, Sulfate: 820, Chloride: 16, Bicarbonate: 320},
+	"dortmund":  {Calcium: 225, Magnesium: 40, Sodium: 60, Sulfate: 120, Chloride: 60, Bicarbonate: 220},
+	"dublin":    {Calcium: 118, Magnesium: 4, Sodium: 12, Sulfate: 54, Chloride: 19, Bicarbonate: 319},
+	"edinburgh": {Calcium: 120, Magnesium: 25, Sodium: 55, Sulfate: 140, Chloride: 65, Bicarbonate: 225},
+	"london":    {Calcium: 52, Magnesium: 32, Sodium: 86, Sulfate: 32, Chloride: 34, Bicarbonate: 104},
+	"munich":    {Calcium: 77, Magnesium: 17, Sodium: 4, Sulfate: 18, Chloride: 8, Bicarbonate: 295},
+	"pilsen":    {Calcium: 7, Magnesium: 2, Sodium: 2, Sulfate: 5, Chloride: 5, Bicarbonate: 15},
+	"vienna":    {Calcium: 200, Magnesium: 60, Sodium: 8, Sulfate: 125, Chloride: 12, Bicarbonate: 120},
+}
+
+// WaterProfileNames returns the names of the preset water profiles in sorted order.
+func WaterProfileNames() []string {
+	names := make([]string, 0, len(waterProfiles))
+	for name := range waterProfiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LookupWaterProfile returns a preset water profile by case-insensitive name.
+func LookupWaterProfile(name string) (WaterChemistry, error) {
+	profile, ok := waterProfiles[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return WaterChemistry{}, fmt.Errorf("unknown water profile %q; available profiles: %s",
+			name, strings.Join(WaterProfileNames(), ", "))
+	}
+	return profile, nil
+}
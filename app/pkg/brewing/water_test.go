@@ -0,0 +1,136 @@
+package brewing_test
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/CharlRitter/brewsource-mcp/app/pkg/brewing"
+)
+
+func TestWaterChemistry_Analysis(t *testing.T) {
+	dublin, err := brewing.LookupWaterProfile("Dublin")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertClose(t, dublin.ResidualAlkalinity(), 227.2, 0.1)
+	assertClose(t, dublin.SulfateChlorideRatio(), 2.84, 0.01)
+
+	if ratio := (brewing.WaterChemistry{Sulfate: 50}).SulfateChlorideRatio(); ratio != 0 {
+		t.Errorf("expected 0 ratio without chloride, got %v", ratio)
+	}
+}
+
+func TestWaterChemistry_SaltAdditions(t *testing.T) {
+	tests := []struct {
+		name      string
+		target    string
+		tolerance float64 // largest acceptable |residual| in ppm
+	}{
+		{name: "burton from RO", target: "burton", tolerance: 6},
+		{name: "dublin from RO", target: "dublin", tolerance: 1},
+		{name: "pilsen from RO", target: "pilsen", tolerance: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target, err := brewing.LookupWaterProfile(tt.target)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			result, err := brewing.WaterChemistry{}.SaltAdditions(target, 20)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(result.Additions) == 0 {
+				t.Fatal("expected salt additions")
+			}
+			for _, addition := range result.Additions {
+				if addition.Grams <= 0 {
+					t.Errorf("expected positive grams for %s, got %v", addition.Salt, addition.Grams)
+				}
+			}
+			for _, ion := range result.Ions {
+				if ion.Infeasible {
+					t.Errorf("%s should be feasible from RO water", ion.Ion)
+				}
+				if math.Abs(ion.Residual) > tt.tolerance {
+					t.Errorf("%s residual %.1f ppm exceeds %.1f", ion.Ion, ion.Residual, tt.tolerance)
+				}
+			}
+		})
+	}
+}
+
+func TestWaterChemistry_SaltAdditionsScaleWithVolume(t *testing.T) {
+	target, _ := brewing.LookupWaterProfile("dublin")
+	small, _ := brewing.WaterChemistry{}.SaltAdditions(target, 10)
+	large, _ := brewing.WaterChemistry{}.SaltAdditions(target, 20)
+	if len(small.Additions) != len(large.Additions) {
+		t.Fatalf("expected the same salts, got %v and %v", small.Additions, large.Additions)
+	}
+	for i := range small.Additions {
+		assertClose(t, large.Additions[i].Grams, small.Additions[i].Grams*2, 1e-6)
+	}
+}
+
+func TestWaterChemistry_SaltAdditionsInfeasibleIons(t *testing.T) {
+	// Hard source water cannot be brought down to Pilsen by adding salts; each ion is reported
+	// rather than failing the call.
+	source := brewing.WaterChemistry{
+		Calcium: 50, Magnesium: 10, Sodium: 30, Sulfate: 40, Chloride: 60, Bicarbonate: 150,
+	}
+	pilsen, _ := brewing.LookupWaterProfile("pilsen")
+	result, err := source.SaltAdditions(pilsen, 20)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Additions) != 0 {
+		t.Errorf("expected no additions, got %v", result.Additions)
+	}
+	for _, ion := range result.Ions {
+		if !ion.Infeasible || ion.Residual <= 0 {
+			t.Errorf("expected %s to be infeasible with a positive residual, got %+v", ion.Ion, ion)
+		}
+	}
+
+	// Only sulfate is infeasible here; chloride is still brought close to target.
+	partial, err := brewing.WaterChemistry{Sulfate: 100}.SaltAdditions(
+		brewing.WaterChemistry{Calcium: 50, Sulfate: 50, Chloride: 75}, 20)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, ion := range partial.Ions {
+		switch ion.Ion {
+		case "sulfate":
+			if !ion.Infeasible {
+				t.Error("expected sulfate to be infeasible")
+			}
+		case "chloride":
+			assertClose(t, ion.Achieved, 75, 5)
+		}
+	}
+}
+
+func TestWaterChemistry_SaltAdditionsValidation(t *testing.T) {
+	target, _ := brewing.LookupWaterProfile("burton")
+	if _, err := (brewing.WaterChemistry{}).SaltAdditions(target, 0); err == nil ||
+		!strings.Contains(err.Error(), "volume") {
+		t.Errorf("expected volume error, got %v", err)
+	}
+	if _, err := (brewing.WaterChemistry{Calcium: -1}).SaltAdditions(target, 10); err == nil ||
+		!strings.Contains(err.Error(), "source calcium cannot be negative") {
+		t.Errorf("expected negative ion error, got %v", err)
+	}
+}
+
+func TestLookupWaterProfile(t *testing.T) {
+	for _, name := range brewing.WaterProfileNames() {
+		if _, err := brewing.LookupWaterProfile(strings.ToUpper(name)); err != nil {
+			t.Errorf("expected %s to resolve: %v", name, err)
+		}
+	}
+	if _, err := brewing.LookupWaterProfile("atlantis"); err == nil ||
+		!strings.Contains(err.Error(), "available profiles") {
+		t.Errorf("expected unknown profile error, got %v", err)
+	}
+}
@@ -0,0 +1,231 @@
+// Package brewing provides brewing calculations and unit conversions for Brewsource MCP.
+package brewing
+
+import (
+	"fmt"
+	"math"
+)
+
+// Standard pitch rates in million cells per ml per °Plato.
+const (
+	PitchRateAle    = 0.75
+	PitchRateHybrid = 1.0
+	PitchRateLager  = 1.5
+)
+
+// YeastPitchingRate calculates how many yeast cells a batch needs.
+type YeastPitchingRate struct {
+	BatchVolume     float64 // gallons, or liters when Units is metric
+	OriginalGravity float64
+	PitchRate       float64    // million cells per ml per °Plato, defaults to PitchRateAle
+	Units           UnitSystem // units of BatchVolume; defaults to UnitsImperial
+}
+
+// TargetCells returns the number of cells to pitch, in billions.
+func (r YeastPitchingRate) TargetCells() (float64, error) {
+	if err := r.Units.validate(); err != nil {
+		return 0, err
+	}
+	switch {
+	case r.BatchVolume <= 0:
+		return 0, &ValidationError{Field: "batch_volume", Value: r.BatchVolume, Constraint: "must be greater than zero"}
+	case r.OriginalGravity <= 1 || r.OriginalGravity > maxGravity:
+		return 0, &ValidationError{
+			Field:      "original_gravity",
+			Value:      r.OriginalGravity,
+			Constraint: fmt.Sprintf("must be greater than 1.000 and at most %.3f", maxGravity),
+		}
+	case r.PitchRate < 0:
+		return 0, &ValidationError{Field: "pitch_rate", Value: r.PitchRate, Constraint: "cannot be negative"}
+	}
+	rate := r.PitchRate
+	if rate == 0 {
+		rate = PitchRateAle
+	}
+	liters := r.BatchVolume
+	if r.Units != UnitsMetric {
+		liters *= LitersPerGallon
+	}
+	milliliters := liters * 1000
+	// million cells → billion cells
+	return rate * milliliters * GravityToPlato(r.OriginalGravity) / 1000, nil
+}
+
+// StarterModel selects the yeast growth model used for starters.
+type StarterModel string
+
+// Supported starter growth models.
+const (
+	// StarterBraukaiser models a stir plate starter, where growth is limited by available extract.
+	StarterBraukaiser StarterModel = "braukaiser"
+	// StarterWhite models a simple (unstirred) starter using Chris White's inoculation rate curve.
+	StarterWhite StarterModel = "white"
+)
+
+const (
+	minStarterGravity = 1.020
+	maxStarterGravity = 1.060
+	// DefaultStarterGravity is the conventional starter wort gravity (about 100 g DME per liter).
+	DefaultStarterGravity = 1.036
+	// braukaiserMaxGrowth is billions of new cells per gram of extract when the starter is under-pitched.
+	braukaiserMaxGrowth = 1.4
+	// whiteMaxGrowthFactor caps new cells per pitched cell in an unstirred starter.
+	whiteMaxGrowthFactor = 6
+	// maxPlannedSteps limits how many steps PlanStarter will chain.
+	maxPlannedSteps = 3
+)
+
+// plannedStarterVolumes are the starter sizes PlanStarter chooses from, in liters.
+//
+//nolint:gochecknoglobals // static lookup table
+var plannedStarterVolumes = []float64{0.5, 1, 1.5, 2, 2.5, 3, 4, 5}
+
+// StarterStep is one step of a yeast starter.
+type StarterStep struct {
+	Volume  float64 `json:"volume"`  // liters
+	Gravity float64 `json:"gravity"` // defaults to DefaultStarterGravity
+}
+
+// StarterStepResult reports the cell count before and after a starter step.
+type StarterStepResult struct {
+	StarterStep
+	StartCells float64 `json:"start_cells"` // billions
+	EndCells   float64 `json:"end_cells"`   // billions
+	Extract    float64 `json:"extract"`     // grams of extract in the step
+}
+
+// StarterCalculation grows an initial cell count through one or more chained starter steps.
+type StarterCalculation struct {
+	InitialCells float64 // billions
+	Steps        []StarterStep
+	Model        StarterModel // defaults to StarterBraukaiser
+}
+
+// Calculate runs each step in turn, feeding the cells grown in one step into the next.
+func (c StarterCalculation) Calculate() ([]StarterStepResult, error) {
+	if c.InitialCells <= 0 {
+		return nil, &ValidationError{
+			Field:      "initial_cells",
+			Value:      c.InitialCells,
+			Constraint: "must be greater than zero",
+		}
+	}
+	if len(c.Steps) == 0 {
+		return nil, &ValidationError{
+			Field:      "steps",
+			Value:      len(c.Steps),
+			Constraint: "must include at least one starter step",
+		}
+	}
+	model := c.Model
+	if model == "" {
+		model = StarterBraukaiser
+	}
+	if model != StarterBraukaiser && model != StarterWhite {
+		return nil, &ValidationError{Field: "starter_model", Value: c.Model, Constraint: "must be braukaiser or white"}
+	}
+
+	results := make([]StarterStepResult, 0, len(c.Steps))
+	cells := c.InitialCells
+	for i, step := range c.Steps {
+		if step.Gravity == 0 {
+			step.Gravity = DefaultStarterGravity
+		}
+		if err := step.validate(); err != nil {
+			return nil, fmt.Errorf("step %d: %w", i+1, err)
+		}
+		result := StarterStepResult{StarterStep: step, StartCells: cells, Extract: step.extractGrams()}
+		result.EndCells = cells + growth(model, cells, step, result.Extract)
+		results = append(results, result)
+		cells = result.EndCells
+	}
+	return results, nil
+}
+
+// PlanStarter chooses the smallest standard starter at DefaultStarterGravity that grows initialCells
+// to targetCells, chaining up to three steps when one is not enough. No steps are returned when the
+// initial cells already meet the target.
+func PlanStarter(initialCells, targetCells float64, model StarterModel) ([]StarterStepResult, error) {
+	if targetCells <= 0 {
+		return nil, &ValidationError{Field: "target_cells", Value: targetCells, Constraint: "must be greater than zero"}
+	}
+	if initialCells <= 0 {
+		return nil, &ValidationError{
+			Field:      "initial_cells",
+			Value:      initialCells,
+			Constraint: "must be greater than zero",
+		}
+	}
+
+	steps := []StarterStep{}
+	cells := initialCells
+	for len(steps) < maxPlannedSteps && cells < targetCells {
+		var results []StarterStepResult
+		for _, volume := range plannedStarterVolumes {
+			step := StarterStep{Volume: volume, Gravity: DefaultStarterGravity}
+			var err error
+			results, err = StarterCalculation{InitialCells: cells, Steps: []StarterStep{step}, Model: model}.Calculate()
+			if err != nil {
+				return nil, err
+			}
+			if results[0].EndCells >= targetCells {
+				break
+			}
+		}
+		steps = append(steps, results[0].StarterStep)
+		cells = results[0].EndCells
+	}
+	if cells < targetCells {
+		return nil, &ValidationError{
+			Field: "initial_cells",
+			Value: initialCells,
+			Constraint: fmt.Sprintf("cannot reach %.0f billion cells in %d starter steps; pitch more yeast",
+				targetCells, maxPlannedSteps),
+		}
+	}
+	if len(steps) == 0 {
+		return []StarterStepResult{}, nil
+	}
+	return StarterCalculation{InitialCells: initialCells, Steps: steps, Model: model}.Calculate()
+}
+
+// growth returns the new cells, in billions, grown by a step.
+//
+// Braukaiser: growth is 1.4 billion cells per gram of extract while the inoculation rate is below
+// 1.4 billion cells per gram, falls linearly as 2.33 - 0.67 × rate above that, and stops at 3.5.
+// White: new cells per pitched cell = 12.54793776 × rate^-0.4594858324 - 0.9994994906, with the
+// rate in million cells per ml, capped at whiteMaxGrowthFactor.
+func growth(model StarterModel, cells float64, step StarterStep, extract float64) float64 {
+	if model == StarterWhite {
+		inoculation := cells / step.Volume // billion cells per liter is million cells per ml
+		factor := 12.54793776*math.Pow(inoculation, -0.4594858324) - 0.9994994906
+		return cells * math.Max(0, math.Min(factor, whiteMaxGrowthFactor))
+	}
+	inoculation := cells / extract
+	switch {
+	case inoculation < braukaiserMaxGrowth:
+		return braukaiserMaxGrowth * extract
+	case inoculation < 3.5:
+		return (2.33 - 0.67*inoculation) * extract
+	default:
+		return 0
+	}
+}
+
+func (s StarterStep) extractGrams() float64 {
+	return s.Volume * 1000 * s.Gravity * GravityToPlato(s.Gravity) / 100
+}
+
+func (s StarterStep) validate() error {
+	switch {
+	case s.Volume <= 0:
+		return &ValidationError{Field: "starter_volume", Value: s.Volume, Constraint: "must be greater than zero"}
+	case s.Gravity < minStarterGravity || s.Gravity > maxStarterGravity:
+		return &ValidationError{
+			Field:      "starter_gravity",
+			Value:      s.Gravity,
+			Constraint: fmt.Sprintf("must be between %.3f and %.3f", minStarterGravity, maxStarterGravity),
+		}
+	}
+	return nil
+}
@@ -0,0 +1,190 @@
+package brewing_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/CharlRitter/brewsource-mcp/app/pkg/brewing"
+)
+
+func TestYeastPitchingRate_TargetCells(t *testing.T) {
+	tests := []struct {
+		name string
+		rate brewing.YeastPitchingRate
+		want float64
+	}{
+		{
+			name: "ale default rate",
+			rate: brewing.YeastPitchingRate{BatchVolume: 5, OriginalGravity: 1.050},
+			want: 175.8,
+		},
+		{
+			name: "lager",
+			rate: brewing.YeastPitchingRate{BatchVolume: 5, OriginalGravity: 1.048, PitchRate: brewing.PitchRateLager},
+			want: 338.2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.rate.TargetCells()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assertClose(t, got, tt.want, 0.1)
+		})
+	}
+
+	if _, err := (brewing.YeastPitchingRate{BatchVolume: 0, OriginalGravity: 1.050}).TargetCells(); err == nil {
+		t.Error("expected an error for a zero batch volume")
+	}
+	_, err := (brewing.YeastPitchingRate{BatchVolume: 5, OriginalGravity: 1.000}).TargetCells()
+	if err == nil || err.Error() != "original gravity must be greater than 1.000 and at most 1.200 (got 1)" {
+		t.Errorf("expected a gravity of 1.000 to be rejected, got %v", err)
+	}
+}
+
+func TestStarterCalculation(t *testing.T) {
+	tests := []struct {
+		name      string
+		calc      brewing.StarterCalculation
+		wantCells []float64
+	}{
+		{
+			name:      "stir plate single step",
+			calc:      brewing.StarterCalculation{InitialCells: 100, Steps: []brewing.StarterStep{{Volume: 2}}},
+			wantCells: []float64{361.8},
+		},
+		{
+			name: "stir plate two steps",
+			calc: brewing.StarterCalculation{
+				InitialCells: 100, Steps: []brewing.StarterStep{{Volume: 2}, {Volume: 2, Gravity: 1.036}},
+			},
+			wantCells: []float64{361.8, 555.1},
+		},
+		{
+			name: "simple starter",
+			calc: brewing.StarterCalculation{
+				InitialCells: 100, Steps: []brewing.StarterStep{{Volume: 2}}, Model: brewing.StarterWhite,
+			},
+			wantCells: []float64{208.0},
+		},
+		{
+			name:      "over-pitched starter does not grow",
+			calc:      brewing.StarterCalculation{InitialCells: 400, Steps: []brewing.StarterStep{{Volume: 0.5}}},
+			wantCells: []float64{400},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := tt.calc.Calculate()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(results) != len(tt.wantCells) {
+				t.Fatalf("expected %d steps, got %d", len(tt.wantCells), len(results))
+			}
+			for i, want := range tt.wantCells {
+				assertClose(t, results[i].EndCells, want, 0.1)
+				if i > 0 && results[i].StartCells != results[i-1].EndCells {
+					t.Errorf("step %d should start from the previous step's cells", i+1)
+				}
+			}
+		})
+	}
+}
+
+func TestStarterCalculation_Validation(t *testing.T) {
+	tests := []struct {
+		name    string
+		calc    brewing.StarterCalculation
+		wantErr string
+	}{
+		{
+			name: "gravity too low",
+			calc: brewing.StarterCalculation{
+				InitialCells: 100,
+				Steps:        []brewing.StarterStep{{Volume: 1, Gravity: 1.010}},
+			},
+			wantErr: "must be between 1.020 and 1.060",
+		},
+		{
+			name: "gravity too high",
+			calc: brewing.StarterCalculation{
+				InitialCells: 100,
+				Steps:        []brewing.StarterStep{{Volume: 1, Gravity: 1.070}},
+			},
+			wantErr: "must be between 1.020 and 1.060",
+		},
+		{
+			name:    "zero volume",
+			calc:    brewing.StarterCalculation{InitialCells: 100, Steps: []brewing.StarterStep{{Volume: 0}}},
+			wantErr: "starter volume must be greater than zero",
+		},
+		{
+			name:    "no steps",
+			calc:    brewing.StarterCalculation{InitialCells: 100},
+			wantErr: "at least one starter step",
+		},
+		{
+			name: "unknown model",
+			calc: brewing.StarterCalculation{
+				InitialCells: 100,
+				Steps:        []brewing.StarterStep{{Volume: 1}},
+				Model:        "fast",
+			},
+			wantErr: "starter model must be",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.calc.Calculate()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestPlanStarter(t *testing.T) {
+	// One fresh pack (100B) needs a 1 L starter for a 5 gallon 1.050 ale, and two steps for 1000B.
+	ale, err := brewing.PlanStarter(100, 175.8, brewing.StarterBraukaiser)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(ale) != 1 || ale[0].Volume != 1 || ale[0].EndCells < 175.8 {
+		t.Errorf("expected a single 1 L step, got %+v", ale)
+	}
+
+	large, err := brewing.PlanStarter(100, 1000, brewing.StarterBraukaiser)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(large) != 2 || large[1].StartCells != large[0].EndCells || large[1].EndCells < 1000 {
+		t.Errorf("expected two chained steps reaching 1000B, got %+v", large)
+	}
+
+	none, err := brewing.PlanStarter(200, 175.8, brewing.StarterBraukaiser)
+	if err != nil || len(none) != 0 {
+		t.Errorf("expected no starter when already at target, got %+v, %v", none, err)
+	}
+
+	if _, err = brewing.PlanStarter(10, 5000, brewing.StarterWhite); err == nil {
+		t.Error("expected an error for an unreachable target")
+	}
+}
+
+func TestYeastPitchingRate_MetricMatchesImperial(t *testing.T) {
+	want, err := brewing.YeastPitchingRate{BatchVolume: 5, OriginalGravity: 1.050}.TargetCells()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, err := brewing.YeastPitchingRate{
+		BatchVolume:     5 * brewing.LitersPerGallon,
+		OriginalGravity: 1.050,
+		Units:           brewing.UnitsMetric,
+	}.TargetCells()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertClose(t, got, want, 1e-9)
+}
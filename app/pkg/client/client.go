@@ -0,0 +1,238 @@
+// Package client calls a BrewSource MCP server from Go, sparing services that embed its lookups from
+// writing JSON-RPC requests by hand. A Client sends the requests of the protocol (Initialize,
+// ListTools, CallTool, ReadResource) over a Transport, retrying the failures worth retrying, and
+// wraps the common lookups in typed methods such as LookupStyle and SearchBreweries:
+//
+//	c := client.New("http://localhost:8080/mcp")
+//	style, err := c.LookupStyle(ctx, "21A")
+//
+// Requests and responses are mcp.Message values, and a JSON-RPC error is returned as an *mcp.Error,
+// whose Code tells, say, a missing beer (mcp.InvalidParams) from a server fault (mcp.InternalError).
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/mcp"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/version"
+)
+
+const (
+	// ProtocolVersion is the MCP version Initialize asks for.
+	ProtocolVersion = "2024-11-05"
+	// DefaultMaxRetries is the number of times a request is retried after a failure worth retrying.
+	DefaultMaxRetries = 3
+	// DefaultBackoff is the wait before the first retry, doubled before each retry after it.
+	DefaultBackoff = 250 * time.Millisecond
+
+	// maxRetryDelay caps the wait before a retry, including waits the server asks for.
+	maxRetryDelay = 30 * time.Second
+)
+
+// Transport sends one JSON-RPC request to the server and returns the response to it. A failure
+// worth retrying, such as an overloaded server, is returned as a *RetryableError; any other error
+// ends the call. HTTPTransport is the transport of the /mcp endpoint.
+type Transport interface {
+	RoundTrip(ctx context.Context, request []byte) ([]byte, error)
+}
+
+// RetryableError is a failure of a Transport that may succeed if the request is sent again, after
+// RetryAfter when the server asked for a wait, or after the client's backoff when it is zero.
+type RetryableError struct {
+	Err        error
+	RetryAfter time.Duration
+}
+
+func (e *RetryableError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RetryableError) Unwrap() error {
+	return e.Err
+}
+
+// Client calls the methods of an MCP server over a Transport. It is safe for concurrent use once
+// configured.
+type Client struct {
+	transport  Transport
+	clientInfo mcp.ClientInfo
+	maxRetries int
+	backoff    time.Duration
+	nextID     atomic.Int64
+}
+
+// New returns a client of the server whose MCP endpoint is url, such as
+// http://localhost:8080/mcp, sending its requests with http.DefaultClient.
+func New(url string) *Client {
+	return NewWithTransport(NewHTTPTransport(url, nil))
+}
+
+// NewWithTransport returns a client sending its requests over transport.
+func NewWithTransport(transport Transport) *Client {
+	return &Client{
+		transport:  transport,
+		clientInfo: mcp.ClientInfo{Name: "brewsource-mcp-client", Version: version.Get().Version},
+		maxRetries: DefaultMaxRetries,
+		backoff:    DefaultBackoff,
+	}
+}
+
+// SetRetry sets how often a failed request is retried and the wait before the first retry, which
+// doubles before each retry after it. A maxRetries of zero sends each request once.
+func (c *Client) SetRetry(maxRetries int, backoff time.Duration) {
+	c.maxRetries, c.backoff = max(maxRetries, 0), backoff
+}
+
+// SetClientInfo sets the name and version Initialize reports, and the unit system or locale of the
+// tool output the client asks for.
+func (c *Client) SetClientInfo(info mcp.ClientInfo) {
+	c.clientInfo = info
+}
+
+// Initialize starts the session, returning the server's capabilities and build.
+func (c *Client) Initialize(ctx context.Context) (*mcp.InitializeResponse, error) {
+	var result mcp.InitializeResponse
+	err := c.Call(ctx, "initialize", mcp.InitializeRequest{ProtocolVersion: ProtocolVersion, ClientInfo: c.clientInfo},
+		&result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ListTools returns the tools the server offers.
+func (c *Client) ListTools(ctx context.Context) ([]mcp.Tool, error) {
+	var result struct {
+		Tools []mcp.Tool `json:"tools"`
+	}
+	if err := c.Call(ctx, "tools/list", nil, &result); err != nil {
+		return nil, err
+	}
+	return result.Tools, nil
+}
+
+// CallTool calls the tool name with args and returns its content.
+func (c *Client) CallTool(ctx context.Context, name string, args map[string]interface{}) (*mcp.ToolResult, error) {
+	var result mcp.ToolResult
+	if err := c.Call(ctx, "tools/call", mcp.CallToolRequest{Name: name, Arguments: args}, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ReadResource reads the resource at uri, such as bjcp://styles/21A.
+func (c *Client) ReadResource(ctx context.Context, uri string) (*mcp.ResourceContent, error) {
+	var result struct {
+		Contents []mcp.ResourceContent `json:"contents"`
+	}
+	if err := c.Call(ctx, "resources/read", mcp.ReadResourceRequest{URI: uri}, &result); err != nil {
+		return nil, err
+	}
+	if len(result.Contents) == 0 {
+		return nil, fmt.Errorf("resources/read of %s returned no contents", uri)
+	}
+	return &result.Contents[0], nil
+}
+
+// Call sends a request for method with params and decodes its result into result, which may be
+// nil to discard it. A JSON-RPC error is returned as an *mcp.Error. Failures worth retrying, see
+// retryDelay, are retried as SetRetry sets, until ctx is done.
+func (c *Client) Call(ctx context.Context, method string, params, result interface{}) error {
+	request := mcp.NewMessage(method, params)
+	request.ID = c.nextID.Add(1)
+	data, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s request: %w", method, err)
+	}
+
+	for attempt := 0; ; attempt++ {
+		raw, err := c.roundTrip(ctx, method, data)
+		if err == nil {
+			return decodeResult(method, raw, result)
+		}
+		delay, retry := c.retryDelay(err, attempt)
+		if !retry {
+			return err
+		}
+		if attempt == c.maxRetries {
+			return fmt.Errorf("%w (gave up after %d retries)", err, c.maxRetries)
+		}
+		if err = sleepContext(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+// roundTrip sends one request and returns the raw result of the response, or its error.
+func (c *Client) roundTrip(ctx context.Context, method string, request []byte) (json.RawMessage, error) {
+	data, err := c.transport.RoundTrip(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	var response struct {
+		Result json.RawMessage `json:"result"`
+		Error  *mcp.Error      `json:"error"`
+	}
+	if err = json.Unmarshal(data, &response); err != nil {
+		return nil, fmt.Errorf("failed to decode %s response: %w", method, err)
+	}
+	if response.Error != nil {
+		return nil, response.Error
+	}
+	return response.Result, nil
+}
+
+// retryDelay reports whether err is worth retrying, and the wait before retry attempt+1: the wait
+// the server asked for, or else the backoff doubled attempt times, capped at maxRetryDelay. A
+// transport's RetryableError is retried, as is a tool call refused with mcp.ServerBusy.
+func (c *Client) retryDelay(err error, attempt int) (time.Duration, bool) {
+	var delay time.Duration
+	var retryable *RetryableError
+	var mcpErr *mcp.Error
+	switch {
+	case errors.As(err, &retryable):
+		delay = retryable.RetryAfter
+	case errors.As(err, &mcpErr) && mcpErr.Code == mcp.ServerBusy:
+		if data, ok := mcpErr.Data.(map[string]interface{}); ok {
+			if ms, ok := data["retry_after_ms"].(float64); ok {
+				delay = time.Duration(ms) * time.Millisecond
+			}
+		}
+	default:
+		return 0, false
+	}
+	if delay <= 0 {
+		delay = c.backoff << attempt
+	}
+	return min(delay, maxRetryDelay), true
+}
+
+// decodeResult decodes the raw result of a response to method into result, unless result is nil.
+func decodeResult(method string, raw json.RawMessage, result interface{}) error {
+	if result == nil {
+		return nil
+	}
+	if err := json.Unmarshal(raw, result); err != nil {
+		return fmt.Errorf("failed to decode %s result: %w", method, err)
+	}
+	return nil
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
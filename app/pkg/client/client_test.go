@@ -0,0 +1,227 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/conformance"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/mcp"
+	"github.com/CharlRitter/brewsource-mcp/app/pkg/client"
+)
+
+// newServer serves /mcp from the MCP server of the conformance tests, whose catalog is faked, after
+// wrap, if any, has had its say about each request.
+func newServer(t *testing.T, wrap func(http.Handler) http.Handler) *httptest.Server {
+	t.Helper()
+	mcpServer, err := conformance.NewServer()
+	if err != nil {
+		t.Fatalf("Failed to create the MCP server: %v", err)
+	}
+	var handler http.Handler = http.HandlerFunc(mcpServer.HandleHTTP)
+	if wrap != nil {
+		handler = wrap(handler)
+	}
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// failFirst answers the first n requests with status, and passes the rest on.
+func failFirst(n int32, status int, requests *atomic.Int32) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if requests.Add(1) <= n {
+				http.Error(w, http.StatusText(status), status)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func TestClient_Session(t *testing.T) {
+	ctx := context.Background()
+	c := client.New(newServer(t, nil).URL)
+
+	initialized, err := c.Initialize(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected initialize error: %v", err)
+	}
+	if initialized.ServerInfo.Name != "BrewSource MCP Server" || initialized.ServerInfo.Version != "1.0.0-conformance" ||
+		initialized.ServerInfo.Metadata["bjcp_version"] != "2021" {
+		t.Errorf("Unexpected server info: %+v", initialized.ServerInfo)
+	}
+
+	tools, err := c.ListTools(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected tools/list error: %v", err)
+	}
+	if !slices.ContainsFunc(tools, func(tool mcp.Tool) bool { return tool.Name == "find_breweries" }) {
+		t.Errorf("Expected find_breweries among %d tools", len(tools))
+	}
+
+	beer, err := c.CallTool(ctx, "get_beer", map[string]interface{}{"id": 1})
+	if err != nil {
+		t.Fatalf("Unexpected get_beer error: %v", err)
+	}
+	if len(beer.Content) == 0 || !strings.Contains(beer.Content[0].Text, "King's Blockhouse IPA") {
+		t.Errorf("Unexpected get_beer result: %+v", beer)
+	}
+
+	resource, err := c.ReadResource(ctx, "beers://3")
+	if err != nil {
+		t.Fatalf("Unexpected resources/read error: %v", err)
+	}
+	if resource.MimeType != "application/json" || !strings.Contains(resource.Text, `"name":"Skeleton Coast IPA"`) ||
+		resource.ETag == "" {
+		t.Errorf("Unexpected resource: %+v", resource)
+	}
+
+	// A JSON-RPC error comes back as an *mcp.Error, not retried
+	_, err = c.CallTool(ctx, "get_beer", map[string]interface{}{"id": 999})
+	var mcpErr *mcp.Error
+	if !errors.As(err, &mcpErr) || mcpErr.Code != mcp.InvalidParams || mcpErr.Message != "beer 999 not found" {
+		t.Errorf("Expected InvalidParams for a missing beer, got %v", err)
+	}
+}
+
+func TestClient_LookupStyle(t *testing.T) {
+	c := client.New(newServer(t, nil).URL)
+
+	style, err := c.LookupStyle(context.Background(), " 21a ")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if style.Code != "21A" || style.Name != "American IPA" || style.Vitals.ABVMin != 5.5 {
+		t.Errorf("Unexpected style: %s %s %+v", style.Code, style.Name, style.Vitals)
+	}
+
+	_, err = c.LookupStyle(context.Background(), "99Z")
+	var mcpErr *mcp.Error
+	if !errors.As(err, &mcpErr) || mcpErr.Code != mcp.MethodNotFound {
+		t.Errorf("Expected MethodNotFound for an unknown style, got %v", err)
+	}
+}
+
+func TestClient_SearchBreweries(t *testing.T) {
+	ctx := context.Background()
+	c := client.New(newServer(t, nil).URL)
+
+	page, err := c.SearchBreweries(ctx, client.BrewerySearchQuery{
+		City: []string{"Cape Town"}, Types: []string{"micro"}, Limit: 1, Sort: "name",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if page.Count != 1 || page.Total != 2 || !page.HasMore(0) || len(page.Breweries) != 1 ||
+		page.Breweries[0].Name != "Devil's Peak Brewing Company" {
+		t.Errorf("Unexpected first page: %+v", page)
+	}
+
+	page, err = c.SearchBreweries(ctx, client.BrewerySearchQuery{City: []string{"Cape Town"}, Limit: 1, Offset: 1})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if page.HasMore(1) || len(page.Breweries) != 1 || page.Breweries[0].Name != "Jack Black's Brewing Company" {
+		t.Errorf("Unexpected second page: %+v", page)
+	}
+
+	_, err = c.SearchBreweries(ctx, client.BrewerySearchQuery{Sort: "founded"})
+	var mcpErr *mcp.Error
+	if !errors.As(err, &mcpErr) || mcpErr.Code != mcp.InvalidParams {
+		t.Errorf("Expected InvalidParams for an unknown sort, got %v", err)
+	}
+}
+
+func TestClient_Retries(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("retries an unavailable server", func(t *testing.T) {
+		var requests atomic.Int32
+		c := client.New(newServer(t, failFirst(2, http.StatusServiceUnavailable, &requests)).URL)
+		c.SetRetry(2, time.Millisecond)
+		if _, err := c.ListTools(ctx); err != nil {
+			t.Fatalf("Expected the third attempt to succeed, got %v", err)
+		}
+		if requests.Load() != 3 {
+			t.Errorf("Expected 3 requests, got %d", requests.Load())
+		}
+	})
+
+	t.Run("gives up after the retries", func(t *testing.T) {
+		var requests atomic.Int32
+		c := client.New(newServer(t, failFirst(5, http.StatusBadGateway, &requests)).URL)
+		c.SetRetry(1, time.Millisecond)
+		_, err := c.ListTools(ctx)
+		var retryable *client.RetryableError
+		if !errors.As(err, &retryable) || !strings.Contains(err.Error(), "returned 502 Bad Gateway") ||
+			!strings.HasSuffix(err.Error(), "(gave up after 1 retries)") {
+			t.Errorf("Expected the retries given up, got %v", err)
+		}
+		if requests.Load() != 2 {
+			t.Errorf("Expected 2 requests, got %d", requests.Load())
+		}
+	})
+
+	t.Run("does not retry a rejected request", func(t *testing.T) {
+		var requests atomic.Int32
+		c := client.New(newServer(t, failFirst(5, http.StatusRequestEntityTooLarge, &requests)).URL)
+		c.SetRetry(3, time.Millisecond)
+		if _, err := c.ListTools(ctx); err == nil || !strings.Contains(err.Error(), "413 Request Entity Too Large") {
+			t.Errorf("Expected the 413 returned, got %v", err)
+		}
+		if requests.Load() != 1 {
+			t.Errorf("Expected 1 request, got %d", requests.Load())
+		}
+	})
+
+	t.Run("retries a busy tool", func(t *testing.T) {
+		var requests atomic.Int32
+		busy := func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if requests.Add(1) == 1 {
+					w.Header().Set("Content-Type", "application/json")
+					_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32002,` +
+						`"message":"tool is busy","data":{"retry_after_ms":1}}}`))
+					return
+				}
+				next.ServeHTTP(w, r)
+			})
+		}
+		c := client.New(newServer(t, busy).URL)
+		if _, err := c.CallTool(ctx, "get_beer", map[string]interface{}{"id": 2}); err != nil {
+			t.Fatalf("Expected the busy tool retried, got %v", err)
+		}
+		if requests.Load() != 2 {
+			t.Errorf("Expected 2 requests, got %d", requests.Load())
+		}
+	})
+
+	t.Run("stops waiting when the context is done", func(t *testing.T) {
+		var requests atomic.Int32
+		retryLater := func(http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				requests.Add(1)
+				w.Header().Set("Retry-After", "10")
+				http.Error(w, "slow down", http.StatusTooManyRequests)
+			})
+		}
+		c := client.New(newServer(t, retryLater).URL)
+		timeout, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancel()
+		started := time.Now()
+		if _, err := c.ListTools(timeout); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Expected the deadline exceeded, got %v", err)
+		}
+		if elapsed := time.Since(started); elapsed > 5*time.Second || requests.Load() != 1 {
+			t.Errorf("Expected one request and no wait for Retry-After, got %d in %s", requests.Load(), elapsed)
+		}
+	})
+}
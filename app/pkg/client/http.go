@@ -0,0 +1,86 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxErrorBody bounds how much of the body of a failed HTTP response is quoted in its error.
+const maxErrorBody = 512
+
+// HTTPTransport sends requests to the /mcp endpoint of a server as HTTP POSTs.
+type HTTPTransport struct {
+	url    string
+	client *http.Client
+}
+
+// NewHTTPTransport returns a transport posting to url with httpClient, or with http.DefaultClient
+// when it is nil. Pass a client of your own to set timeouts, or a TLS client certificate for a
+// server that requires one.
+func NewHTTPTransport(url string, httpClient *http.Client) *HTTPTransport {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &HTTPTransport{url: url, client: httpClient}
+}
+
+// RoundTrip posts request and returns the response body. A response of 429 Too Many Requests, 502
+// Bad Gateway, 503 Service Unavailable, or 504 Gateway Timeout is a *RetryableError, after the
+// delay of its Retry-After header, as is a request that could not be sent.
+func (t *HTTPTransport) RoundTrip(ctx context.Context, request []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.url, bytes.NewReader(request))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build MCP request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	resp, err := t.client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, &RetryableError{Err: fmt.Errorf("failed to post to %s: %w", t.url, err)}
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the response of %s: %w", t.url, err)
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return body, nil
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return nil, &RetryableError{
+			Err:        statusError(t.url, resp.Status, body),
+			RetryAfter: retryAfterDelay(resp.Header.Get("Retry-After")),
+		}
+	default:
+		return nil, statusError(t.url, resp.Status, body)
+	}
+}
+
+// statusError reports a failed HTTP response, quoting the start of its body.
+func statusError(url, status string, body []byte) error {
+	message := strings.TrimSpace(string(body[:min(len(body), maxErrorBody)]))
+	if message == "" {
+		return fmt.Errorf("%s returned %s", url, status)
+	}
+	return fmt.Errorf("%s returned %s: %s", url, status, message)
+}
+
+// retryAfterDelay reads a Retry-After header given in seconds, returning zero when it is absent or
+// given as a date.
+func retryAfterDelay(header string) time.Duration {
+	seconds, err := strconv.Atoi(strings.TrimSpace(header))
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
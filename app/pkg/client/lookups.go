@@ -0,0 +1,121 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/CharlRitter/brewsource-mcp/app/internal/mcp"
+	"github.com/CharlRitter/brewsource-mcp/app/internal/services"
+	"github.com/CharlRitter/brewsource-mcp/app/pkg/data"
+)
+
+// LookupStyle returns the BJCP style with code, such as "21A", from the bjcp://styles/{code}
+// resource. An unknown code is an *mcp.Error with code mcp.MethodNotFound.
+func (c *Client) LookupStyle(ctx context.Context, code string) (*data.BJCPStyle, error) {
+	content, err := c.ReadResource(ctx, "bjcp://styles/"+url.PathEscape(strings.TrimSpace(code)))
+	if err != nil {
+		return nil, err
+	}
+	var style data.BJCPStyle
+	if err = json.Unmarshal([]byte(content.Text), &style); err != nil {
+		return nil, fmt.Errorf("failed to decode style %s: %w", code, err)
+	}
+	return &style, nil
+}
+
+// BrewerySearchQuery holds the arguments of find_breweries. Zero fields are left to the server's
+// defaults; the lists match any of their values.
+type BrewerySearchQuery struct {
+	Name         string
+	Location     string // matched against the city, state, and country
+	City         []string
+	State        []string
+	Country      []string
+	Types        []string // brewery types, such as "micro" or "brewpub"
+	ExcludeTypes []string
+	// Latitude and Longitude, given together, limit the search to RadiusKm around the point.
+	Latitude  *float64
+	Longitude *float64
+	RadiusKm  *float64
+	Limit     int
+	Offset    int
+	Sort      string // one of services.BrewerySortKeys
+	Order     string // "asc" or "desc"
+}
+
+// args returns the query as find_breweries arguments.
+func (q BrewerySearchQuery) args() map[string]interface{} {
+	args := map[string]interface{}{}
+	for name, value := range map[string]string{
+		"name": q.Name, "location": q.Location, "sort": q.Sort, "order": q.Order,
+	} {
+		if value != "" {
+			args[name] = value
+		}
+	}
+	for name, values := range map[string][]string{
+		"city": q.City, "state": q.State, "country": q.Country, "type": q.Types, "exclude_types": q.ExcludeTypes,
+	} {
+		if len(values) > 0 {
+			args[name] = values
+		}
+	}
+	for name, value := range map[string]*float64{
+		"latitude": q.Latitude, "longitude": q.Longitude, "radius_km": q.RadiusKm,
+	} {
+		if value != nil {
+			args[name] = *value
+		}
+	}
+	if q.Limit > 0 {
+		args["limit"] = q.Limit
+	}
+	if q.Offset > 0 {
+		args["offset"] = q.Offset
+	}
+	return args
+}
+
+// BreweryPage is a page of find_breweries results.
+type BreweryPage struct {
+	Breweries []*services.BrewerySearchResult `json:"results"`
+	Count     int                             `json:"count"` // breweries on the page
+	Total     int                             `json:"total"` // breweries matching the query
+	// Warnings are problems that did not fail the search, such as a capped limit.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// HasMore reports whether breweries matching the query follow the page that started at offset.
+func (p *BreweryPage) HasMore(offset int) bool {
+	return offset+p.Count < p.Total
+}
+
+// SearchBreweries calls find_breweries with query and returns the page of breweries found, decoded
+// from the JSON content of its result. Invalid arguments are an *mcp.Error with code
+// mcp.InvalidParams.
+func (c *Client) SearchBreweries(ctx context.Context, query BrewerySearchQuery) (*BreweryPage, error) {
+	result, err := c.CallTool(ctx, "find_breweries", query.args())
+	if err != nil {
+		return nil, err
+	}
+	var page BreweryPage
+	if err = decodeEnvelope(result, &page); err != nil {
+		return nil, fmt.Errorf("failed to decode find_breweries result: %w", err)
+	}
+	if page.Breweries == nil {
+		page.Breweries = []*services.BrewerySearchResult{}
+	}
+	return &page, nil
+}
+
+// decodeEnvelope decodes the JSON content item the lookup and search tools follow their markdown
+// with into v.
+func decodeEnvelope(result *mcp.ToolResult, v interface{}) error {
+	if len(result.Content) < 2 {
+		return fmt.Errorf("expected markdown and JSON content, got %d items", len(result.Content))
+	}
+	return json.Unmarshal([]byte(result.Content[1].Text), v)
+}
@@ -0,0 +1,52 @@
+package data
+
+import "strings"
+
+// styleAliases are other names that beers of a style are commonly labelled with, keyed by the
+// lowercased style name so that they apply to every guideline version using the name. Names that
+// would match other styles as a substring, such as "IPA" or "Bock", are left out.
+//
+//nolint:gochecknoglobals // read-only alias table
+var styleAliases = map[string][]string{
+	"american light lager":     {"Light Lager"},
+	"international pale lager": {"Pale Lager", "Premium Lager"},
+	"czech premium pale lager": {"Bohemian Pilsner", "Czech Pilsner"},
+	"munich helles":            {"Helles"},
+	"helles bock":              {"Maibock"},
+	"kolsch":                   {"Kölsch"},
+	"german helles exportbier": {"Dortmunder Export"},
+	"german pils":              {"German Pilsner"},
+	"marzen":                   {"Märzen", "Oktoberfest"},
+	"weissbier":                {"Hefeweizen", "Hefeweissbier"},
+	"dunkles weissbier":        {"Dunkelweizen"},
+	"strong bitter":            {"ESB", "Extra Special Bitter"},
+	"british golden ale":       {"Golden Ale"},
+	"irish stout":              {"Dry Stout"},
+	"sweet stout":              {"Milk Stout"},
+	"wee heavy":                {"Scotch Ale"},
+	"california common":        {"Steam Beer"},
+	"american ipa":             {"West Coast IPA"},
+	"hazy ipa":                 {"New England IPA", "NEIPA", "Juicy IPA"},
+	"double ipa":               {"Imperial IPA", "DIPA"},
+	"witbier":                  {"Belgian White", "White Ale"},
+	"saison":                   {"Farmhouse Ale"},
+	"belgian dubbel":           {"Dubbel"},
+	"belgian tripel":           {"Tripel"},
+	"belgian dark strong ale":  {"Quadrupel"},
+}
+
+// deriveStyleAliases fills in Aliases for the styles that have none: the names in styleAliases,
+// and for a name of the form "Historical Beer: Kellerbier", the part after the colon.
+func deriveStyleAliases(bjcpData *BJCPData) {
+	for code, style := range bjcpData.Styles {
+		if len(style.Aliases) > 0 {
+			continue
+		}
+		aliases := styleAliases[strings.ToLower(style.Name)]
+		if _, name, ok := strings.Cut(style.Name, ": "); ok {
+			aliases = append([]string{name}, aliases...)
+		}
+		style.Aliases = aliases
+		bjcpData.Styles[code] = style
+	}
+}
@@ -0,0 +1,57 @@
+package data_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/CharlRitter/brewsource-mcp/app/pkg/data"
+)
+
+func TestStyleAliases_Derived(t *testing.T) {
+	svc := newGuidelineService(t)
+
+	tests := []struct {
+		code     string
+		expected []string
+	}{
+		{"10A", []string{"Hefeweizen", "Hefeweissbier"}},
+		{"22A", []string{"Imperial IPA", "DIPA"}},
+		// Historical styles are also known by the name after the colon.
+		{"27A", []string{"Kellerbier"}},
+		{"1B", nil},
+	}
+	for _, tt := range tests {
+		style, err := svc.GetStyleByCode(tt.code)
+		if err != nil {
+			t.Fatalf("Failed to get %s: %v", tt.code, err)
+		}
+		if !reflect.DeepEqual(style.Aliases, tt.expected) {
+			t.Errorf("Expected %s aliases %v, got %v", tt.code, tt.expected, style.Aliases)
+		}
+	}
+}
+
+func TestStyleAliases_FromDataFile(t *testing.T) {
+	contents := `{"styles": {` +
+		`"1A": {"code": "1A", "name": "Weissbier", "category": "House", "aliases": ["House Wheat"]},` +
+		`"1B": {"code": "1B", "name": "Saison", "category": "House"}},` +
+		`"categories": ["House"], "metadata": {"version": "house-1"}}`
+	path := filepath.Join(t.TempDir(), "styles.json")
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatalf("Failed to write test data file: %v", err)
+	}
+	t.Setenv(data.BJCPDataPathEnv, path)
+
+	bjcpData, err := data.LoadBJCPData()
+	if err != nil {
+		t.Fatalf("Failed to load the data file: %v", err)
+	}
+	if aliases := bjcpData.Styles["1A"].Aliases; !reflect.DeepEqual(aliases, []string{"House Wheat"}) {
+		t.Errorf("Expected the file's aliases kept, got %v", aliases)
+	}
+	if aliases := bjcpData.Styles["1B"].Aliases; !reflect.DeepEqual(aliases, []string{"Farmhouse Ale"}) {
+		t.Errorf("Expected the common aliases of Saison, got %v", aliases)
+	}
+}
@@ -2,12 +2,23 @@
 package data
 
 import (
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
+	"unicode"
 )
 
 const (
@@ -18,6 +29,41 @@ const (
 // ErrSearchTermTooShort is returned when a search term is too short.
 var ErrSearchTermTooShort = errors.New("search term too short: minimum 2 characters required")
 
+// styleCodePattern matches a normalized BJCP style code: a category number of one or two digits
+// followed by a subcategory letter.
+var styleCodePattern = regexp.MustCompile(`^[0-9]{1,2}[A-Z]$`)
+
+// Fullwidth forms of the printable ASCII characters, such as "２１Ａ", sit at a fixed offset from
+// them.
+const (
+	fullwidthFirst  = '\uFF01'
+	fullwidthLast   = '\uFF5E'
+	fullwidthOffset = fullwidthFirst - '!'
+)
+
+// NormalizeStyleCode folds a style code to the form the guidelines use: fullwidth characters such
+// as "２１Ａ" become their ASCII forms, as Unicode NFKC folds them, whitespace is removed wherever it
+// appears, so " 21 A " is accepted, and letters are uppercased.
+func NormalizeStyleCode(code string) string {
+	var normalized strings.Builder
+	for _, r := range code {
+		switch {
+		case unicode.IsSpace(r):
+			continue
+		case r >= fullwidthFirst && r <= fullwidthLast:
+			r -= fullwidthOffset
+		}
+		normalized.WriteRune(unicode.ToUpper(r))
+	}
+	return normalized.String()
+}
+
+// IsStyleCode reports whether code, once normalized with NormalizeStyleCode, has the form of a BJCP
+// style code such as 21A or 1B.
+func IsStyleCode(code string) bool {
+	return styleCodePattern.MatchString(NormalizeStyleCode(code))
+}
+
 // BJCPStyle represents a beer style from the BJCP guidelines.
 type BJCPStyle struct {
 	Code                      string   `json:"code"`
@@ -34,6 +80,13 @@ type BJCPStyle struct {
 	StyleComparison           string   `json:"style_comparison"`
 	CommercialExamples        []string `json:"commercial_examples"`
 	Vitals                    Vitals   `json:"vitals"`
+	// RelatedStyles are the codes of styles to see also. Styles whose data file leaves them out get
+	// the closest styles by vitals on load, see deriveRelatedStyles.
+	RelatedStyles []string `json:"related_styles,omitempty"`
+	// Aliases are other names beers of the style are labelled with, such as "Hefeweizen" for
+	// Weissbier. Styles whose data file leaves them out get the common ones on load, see
+	// deriveStyleAliases.
+	Aliases []string `json:"aliases,omitempty"`
 }
 
 // Vitals represents the technical specifications of a beer style.
@@ -65,63 +118,275 @@ type Metadata struct {
 	TotalStyles int    `json:"total_styles"`
 }
 
-// LoadBJCPData loads and parses the BJCP style data from file.
-func LoadBJCPData() (*BJCPData, error) {
-	// Use a fixed, validated file path to prevent path traversal attacks
-	const bjcpFileName = "bjcp_2021_beer.json"
-	dataPath := filepath.Join("data", bjcpFileName)
+// DefaultGuidelineVersion is the BJCP guideline version served when a caller does not ask for one.
+const DefaultGuidelineVersion = "2021"
 
-	// Validate that the resolved path is within the expected directory
-	absDataPath, err := filepath.Abs(dataPath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to resolve data file path: %w", err)
-	}
+// guidelineVersions lists the BJCP beer guideline versions LoadBJCPGuidelines looks for.
+var guidelineVersions = []string{DefaultGuidelineVersion, "2015"}
 
-	expectedDir, err := filepath.Abs("data")
-	if err != nil {
-		return nil, fmt.Errorf("failed to resolve data directory: %w", err)
-	}
+// ErrUnknownGuidelineVersion is returned when a BJCP guideline version is requested that is not
+// loaded.
+var ErrUnknownGuidelineVersion = errors.New("unknown BJCP guideline version")
 
-	if !strings.HasPrefix(absDataPath, expectedDir+string(filepath.Separator)) {
-		return nil, errors.New("invalid data file path: path traversal detected")
+// BJCPDataPathEnv names the environment variable that points LoadBJCPData at a custom style data
+// file in place of the embedded BJCP 2021 data.
+const BJCPDataPathEnv = "BJCP_DATA_PATH"
+
+// guidelineFiles holds the bundled beer style data of each guideline version, named
+// bjcp_<year>_beer.json, embedded so the binary does not depend on its working directory.
+//
+//go:embed bjcp_*_beer.json
+var guidelineFiles embed.FS
+
+// GuidelineDataPathEnv returns the environment variable that names a style data file for a
+// guideline version in place of the bundled one: BJCP_DATA_PATH for DefaultGuidelineVersion, and
+// BJCP_<year>_DATA_PATH, such as BJCP_2015_DATA_PATH, for the others.
+func GuidelineDataPathEnv(version string) string {
+	if version == DefaultGuidelineVersion {
+		return BJCPDataPathEnv
 	}
+	return "BJCP_" + version + "_DATA_PATH"
+}
 
-	data, err := os.ReadFile(dataPath) // #nosec G304 - path is validated above
+// loadGuideline loads and parses the style data of a guideline version. An error matching
+// fs.ErrNotExist means that there is no data for the version.
+func loadGuideline(version string) (*BJCPData, error) {
+	var raw []byte
+	var err error
+	if path := os.Getenv(GuidelineDataPathEnv(version)); path != "" {
+		raw, err = os.ReadFile(filepath.Clean(path)) // #nosec G304 - the path is deployment configuration
+	} else {
+		raw, err = guidelineFiles.ReadFile("bjcp_" + version + "_beer.json")
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to read BJCP data file: %w", err)
 	}
 
 	var bjcpData BJCPData
-	if unmarshalErr := json.Unmarshal(data, &bjcpData); unmarshalErr != nil {
-		return nil, fmt.Errorf("failed to parse BJCP data: %w", unmarshalErr)
+	if err := json.Unmarshal(raw, &bjcpData); err != nil {
+		return nil, fmt.Errorf("failed to parse BJCP data: %w", err)
+	}
+	problems := dataProblems(&bjcpData)
+	if duplicates := duplicateStyleKeys(raw); len(duplicates) > 0 {
+		problems = append(problems, fmt.Errorf("styles listed more than once: %s", strings.Join(duplicates, ", ")))
+	}
+	if err := joinProblems(problems); err != nil {
+		return nil, err
 	}
+	deriveRelatedStyles(&bjcpData)
+	deriveStyleAliases(&bjcpData)
 
 	return &bjcpData, nil
 }
 
-// BJCPService provides access to BJCP style information from JSON data.
+// LoadBJCPData loads and parses the BJCP style data: the file named by BJCP_DATA_PATH if it is set,
+// and the embedded BJCP 2021 data otherwise.
+func LoadBJCPData() (*BJCPData, error) {
+	return loadGuideline(DefaultGuidelineVersion)
+}
+
+// LoadBJCPGuidelines loads the style data of every guideline version available, keyed by year.
+// The default version is required, while the others are skipped when they are neither bundled nor
+// found at the file their GuidelineDataPathEnv variable names.
+func LoadBJCPGuidelines() (map[string]*BJCPData, error) {
+	guidelines := make(map[string]*BJCPData, len(guidelineVersions))
+	for _, version := range guidelineVersions {
+		bjcpData, err := loadGuideline(version)
+		if err != nil {
+			if version != DefaultGuidelineVersion && errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return nil, fmt.Errorf("BJCP %s guidelines: %w", version, err)
+		}
+		guidelines[version] = bjcpData
+	}
+	return guidelines, nil
+}
+
+// BJCPService provides access to BJCP style information from JSON data, for one or more guideline
+// versions. It is safe for concurrent use, including while Reload swaps in new data.
 type BJCPService struct {
-	data *BJCPData
+	mu          sync.RWMutex
+	guidelines  map[string]*BJCPData
+	fingerprint string
+	loadedAt    time.Time // last successful load; see Health
+	lastErr     error     // of the last failed Reload
+	lastErrAt   time.Time
 }
 
-// NewBJCPServiceFromData creates a new BJCPService instance from BJCPData.
+// BJCPHealth describes the style data a BJCPService serves, for readiness checks. LastError and
+// LastErrorAt are those of the most recent failed Reload, kept after later reloads succeed; the
+// data is stale when LastErrorAt is after LoadedAt.
+type BJCPHealth struct {
+	// Styles is the number of styles of the DefaultGuidelineVersion loaded.
+	Styles int `json:"styles"`
+	// Version is the version named by the metadata of the DefaultGuidelineVersion data.
+	Version     string     `json:"version"`
+	LoadedAt    time.Time  `json:"loaded_at"`
+	LastError   string     `json:"last_error,omitempty"`
+	LastErrorAt *time.Time `json:"last_error_at,omitempty"`
+}
+
+// Stale reports whether the most recent reload failed, leaving older data served.
+func (h BJCPHealth) Stale() bool {
+	return h.LastErrorAt != nil && h.LastErrorAt.After(h.LoadedAt)
+}
+
+// NewBJCPServiceFromData creates a new BJCPService instance from BJCPData, served as the
+// DefaultGuidelineVersion.
 func NewBJCPServiceFromData(data *BJCPData) *BJCPService {
-	return &BJCPService{data: data}
+	return NewBJCPServiceFromGuidelines(map[string]*BJCPData{DefaultGuidelineVersion: data})
+}
+
+// NewBJCPServiceFromGuidelines creates a new BJCPService instance from the style data of each
+// guideline version, keyed by year.
+func NewBJCPServiceFromGuidelines(guidelines map[string]*BJCPData) *BJCPService {
+	return &BJCPService{guidelines: guidelines, fingerprint: fingerprint(guidelines), loadedAt: time.Now()}
 }
 
 // NewBJCPService creates a new BJCPService instance with JSON data.
 func NewBJCPService() (*BJCPService, error) {
-	data, err := LoadBJCPData()
+	guidelines, err := LoadBJCPGuidelines()
 	if err != nil {
 		return nil, err
 	}
 
-	return &BJCPService{data: data}, nil
+	return NewBJCPServiceFromGuidelines(guidelines), nil
+}
+
+// Reload loads the style data again with LoadBJCPGuidelines, so updated data files take effect,
+// and swaps it in. When the data cannot be read or parsed, or a version has no styles, the error
+// is returned and the service keeps serving its current data. Either way, the outcome is reported
+// by Health.
+func (s *BJCPService) Reload() error {
+	guidelines, err := loadReloadable()
+	if err != nil {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.lastErr, s.lastErrAt = err, time.Now()
+		return err
+	}
+
+	hash := fingerprint(guidelines)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.guidelines, s.fingerprint, s.loadedAt = guidelines, hash, time.Now()
+	return nil
+}
+
+// loadReloadable loads the guidelines as Reload swaps them in, refusing a version without styles.
+func loadReloadable() (map[string]*BJCPData, error) {
+	guidelines, err := LoadBJCPGuidelines()
+	if err != nil {
+		return nil, err
+	}
+	for version, bjcpData := range guidelines {
+		if len(bjcpData.Styles) == 0 {
+			return nil, fmt.Errorf("BJCP %s guidelines: failed to parse BJCP data: no styles found", version)
+		}
+	}
+	return guidelines, nil
+}
+
+// Health reports the style data served: how many styles of the DefaultGuidelineVersion are loaded,
+// their version, when the data was last loaded successfully, and the error of the last failed
+// Reload.
+func (s *BJCPService) Health() BJCPHealth {
+	bjcpData := s.Data()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	health := BJCPHealth{
+		Styles:   len(bjcpData.Styles),
+		Version:  bjcpData.Metadata.Version,
+		LoadedAt: s.loadedAt,
+	}
+	if s.lastErr != nil {
+		failedAt := s.lastErrAt
+		health.LastError, health.LastErrorAt = s.lastErr.Error(), &failedAt
+	}
+	return health
+}
+
+// Fingerprint identifies the style data served: it is a hash of the data of every guideline
+// version, so it changes whenever Reload swaps in data that differs, and is the same for identical
+// data in any process. Callers use it to version what they derive from the data, such as the ETags
+// of the style resources.
+func (s *BJCPService) Fingerprint() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.fingerprint
+}
+
+// fingerprint hashes the guidelines' JSON encoding, which lists map keys in order and so is the
+// same for equal data.
+func fingerprint(guidelines map[string]*BJCPData) string {
+	encoded, err := json.Marshal(guidelines)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(encoded)
+	return hex.EncodeToString(sum[:16])
+}
+
+// Versions returns the loaded guideline versions, newest first.
+func (s *BJCPService) Versions() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.versions()
+}
+
+func (s *BJCPService) versions() []string {
+	versions := make([]string, 0, len(s.guidelines))
+	for version := range s.guidelines {
+		versions = append(versions, version)
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(versions)))
+	return versions
 }
 
-// GetStyleByCode retrieves a BJCP style by its code (e.g., "21A").
+// DataVersion returns the style data of a guideline version, or of DefaultGuidelineVersion when
+// version is empty. It must not be modified. An unknown version returns an error matching
+// ErrUnknownGuidelineVersion that lists the versions loaded.
+func (s *BJCPService) DataVersion(version string) (*BJCPData, error) {
+	if version == "" {
+		version = DefaultGuidelineVersion
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	bjcpData, ok := s.guidelines[version]
+	if !ok {
+		return nil, fmt.Errorf("%w %q: loaded versions are %s",
+			ErrUnknownGuidelineVersion, version, strings.Join(s.versions(), ", "))
+	}
+	if bjcpData == nil {
+		return &BJCPData{}, nil
+	}
+	return bjcpData, nil
+}
+
+// Data returns the style data of DefaultGuidelineVersion currently served. It must not be modified.
+func (s *BJCPService) Data() *BJCPData {
+	bjcpData, err := s.DataVersion(DefaultGuidelineVersion)
+	if err != nil {
+		return &BJCPData{}
+	}
+	return bjcpData
+}
+
+// GetStyleByCode retrieves a BJCP style by its code (e.g., "21A"), normalized with
+// NormalizeStyleCode.
 func (s *BJCPService) GetStyleByCode(code string) (*BJCPStyle, error) {
-	style, exists := s.data.Styles[strings.ToUpper(code)]
+	return s.GetStyleByCodeVersion(code, DefaultGuidelineVersion)
+}
+
+// GetStyleByCodeVersion retrieves a BJCP style by its code from a guideline version, see
+// DataVersion.
+func (s *BJCPService) GetStyleByCodeVersion(code, version string) (*BJCPStyle, error) {
+	bjcpData, err := s.DataVersion(version)
+	if err != nil {
+		return nil, err
+	}
+	style, exists := bjcpData.Styles[NormalizeStyleCode(code)]
 	if !exists {
 		return nil, fmt.Errorf("BJCP style not found: %s", code)
 	}
@@ -129,8 +394,62 @@ func (s *BJCPService) GetStyleByCode(code string) (*BJCPStyle, error) {
 	return &style, nil
 }
 
+// CompleteStyleCode lists the style codes starting with prefix, normalized with
+// NormalizeStyleCode, ordered by code, so that "2" suggests 2A to 2C before 20A.
+func (s *BJCPService) CompleteStyleCode(prefix string) []string {
+	return s.CompleteStyleCodeVersion(prefix, DefaultGuidelineVersion)
+}
+
+// CompleteStyleCodeVersion is CompleteStyleCode over a guideline version, see DataVersion. An
+// unknown version has no codes.
+func (s *BJCPService) CompleteStyleCodeVersion(prefix, version string) []string {
+	bjcpData, err := s.DataVersion(version)
+	if err != nil {
+		return []string{}
+	}
+	prefix = NormalizeStyleCode(prefix)
+	codes := []string{}
+	for code := range bjcpData.Styles {
+		if strings.HasPrefix(code, prefix) {
+			codes = append(codes, code)
+		}
+	}
+	slices.SortFunc(codes, CompareStyleCodes)
+	return codes
+}
+
+// SuggestStyles returns the styles of the default guideline version whose code, name, or one of
+// whose aliases starts with prefix, ignoring case and leading whitespace, ordered by code, at most
+// limit of them. A blank prefix suggests nothing.
+func (s *BJCPService) SuggestStyles(prefix string, limit int) []BJCPStyle {
+	styles := []BJCPStyle{}
+	prefix = strings.ToLower(strings.TrimLeftFunc(prefix, unicode.IsSpace))
+	if strings.TrimSpace(prefix) == "" {
+		return styles
+	}
+	for _, style := range s.Data().Styles {
+		names := append([]string{style.Code, style.Name}, style.Aliases...)
+		if slices.ContainsFunc(names, func(name string) bool {
+			return strings.HasPrefix(strings.ToLower(name), prefix)
+		}) {
+			styles = append(styles, style)
+		}
+	}
+	slices.SortFunc(styles, func(a, b BJCPStyle) int { return CompareStyleCodes(a.Code, b.Code) })
+	if len(styles) > limit {
+		styles = styles[:limit]
+	}
+	return styles
+}
+
 // GetStyleByName retrieves a BJCP style by searching for its name.
 func (s *BJCPService) GetStyleByName(name string) (*BJCPStyle, error) {
+	return s.GetStyleByNameVersion(name, DefaultGuidelineVersion)
+}
+
+// GetStyleByNameVersion retrieves a BJCP style by searching for its name in a guideline version,
+// see DataVersion.
+func (s *BJCPService) GetStyleByNameVersion(name, version string) (*BJCPStyle, error) {
 	// Handle empty or whitespace-only strings
 	trimmed := strings.TrimSpace(name)
 	if trimmed == "" {
@@ -154,24 +473,29 @@ func (s *BJCPService) GetStyleByName(name string) (*BJCPStyle, error) {
 		return nil, errors.New("search term must contain alphabetic characters")
 	}
 
+	bjcpData, err := s.DataVersion(version)
+	if err != nil {
+		return nil, err
+	}
 	nameLower := strings.ToLower(trimmed)
+	styles := bjcpData.Styles
 
 	// First, try exact match
-	for _, style := range s.data.Styles {
+	for _, style := range styles {
 		if strings.ToLower(style.Name) == nameLower {
 			return &style, nil
 		}
 	}
 
 	// Then, try partial match (starts with)
-	for _, style := range s.data.Styles {
+	for _, style := range styles {
 		if strings.HasPrefix(strings.ToLower(style.Name), nameLower) {
 			return &style, nil
 		}
 	}
 
 	// Finally, try contains match
-	for _, style := range s.data.Styles {
+	for _, style := range styles {
 		if strings.Contains(strings.ToLower(style.Name), nameLower) {
 			return &style, nil
 		}
@@ -182,29 +506,96 @@ func (s *BJCPService) GetStyleByName(name string) (*BJCPStyle, error) {
 
 // GetAllStyles returns all BJCP styles.
 func (s *BJCPService) GetAllStyles() map[string]BJCPStyle {
-	return s.data.Styles
+	return s.Data().Styles
 }
 
 // GetCategories returns all BJCP style categories.
 func (s *BJCPService) GetCategories() []string {
-	return s.data.Categories
+	return s.Data().Categories
 }
 
-// GetStylesByCategory returns all styles in a given category.
+// GetStylesByCategory returns all styles in a given category, matched case-insensitively, ordered
+// by code.
 func (s *BJCPService) GetStylesByCategory(category string) []BJCPStyle {
+	return s.GetStylesByCategoryVersion(category, DefaultGuidelineVersion)
+}
+
+// GetStylesByCategoryVersion is GetStylesByCategory over a guideline version, see DataVersion. An
+// unknown version has no styles.
+func (s *BJCPService) GetStylesByCategoryVersion(category, version string) []BJCPStyle {
+	bjcpData, err := s.DataVersion(version)
+	if err != nil {
+		return nil
+	}
+
 	var styles []BJCPStyle
-	categoryLower := strings.ToLower(category)
+	categoryLower := strings.ToLower(strings.TrimSpace(category))
 
-	for _, style := range s.data.Styles {
+	for _, style := range bjcpData.Styles {
 		if strings.ToLower(style.Category) == categoryLower {
 			styles = append(styles, style)
 		}
 	}
 
+	slices.SortFunc(styles, func(a, b BJCPStyle) int { return CompareStyleCodes(a.Code, b.Code) })
 	return styles
 }
 
+// CategorySummary is a BJCP category with the number of styles in it and the span of their codes.
+type CategorySummary struct {
+	Name       string `json:"name"`
+	StyleCount int    `json:"style_count"`
+	// CodeRange is the first and last code of the category's styles, such as "21A-21C", or the
+	// only code when it has one style. It is empty for a category without styles.
+	CodeRange string `json:"code_range"`
+}
+
+// GetCategorySummaries summarizes every BJCP category, in the order of GetCategories.
+func (s *BJCPService) GetCategorySummaries() []CategorySummary {
+	bjcpData := s.Data()
+	codes := map[string][]string{}
+	for _, style := range bjcpData.Styles {
+		category := strings.ToLower(style.Category)
+		codes[category] = append(codes[category], style.Code)
+	}
+
+	summaries := make([]CategorySummary, 0, len(bjcpData.Categories))
+	for _, category := range bjcpData.Categories {
+		categoryCodes := codes[strings.ToLower(category)]
+		summaries = append(summaries, CategorySummary{
+			Name:       category,
+			StyleCount: len(categoryCodes),
+			CodeRange:  codeRange(categoryCodes),
+		})
+	}
+	return summaries
+}
+
+// codeRange spans style codes from the lowest to the highest.
+func codeRange(codes []string) string {
+	if len(codes) == 0 {
+		return ""
+	}
+	first := slices.MinFunc(codes, CompareStyleCodes)
+	last := slices.MaxFunc(codes, CompareStyleCodes)
+	if first == last {
+		return first
+	}
+	return first + "-" + last
+}
+
+// CompareStyleCodes orders style codes by category number and then letter, so that 2A sorts before
+// 10A.
+func CompareStyleCodes(a, b string) int {
+	numberA, _ := strconv.Atoi(strings.TrimRight(a, "ABCDEFGHIJKLMNOPQRSTUVWXYZ"))
+	numberB, _ := strconv.Atoi(strings.TrimRight(b, "ABCDEFGHIJKLMNOPQRSTUVWXYZ"))
+	if numberA != numberB {
+		return numberA - numberB
+	}
+	return strings.Compare(a, b)
+}
+
 // GetMetadata returns metadata about the BJCP data.
 func (s *BJCPService) GetMetadata() Metadata {
-	return s.data.Metadata
+	return s.Data().Metadata
 }
@@ -4,10 +4,13 @@
 package data_test
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"reflect"
+	"slices"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/CharlRitter/brewsource-mcp/app/pkg/data"
@@ -243,6 +246,97 @@ func TestGetStyleByCode_SadPath(t *testing.T) {
 	}
 }
 
+// Test that GetStyleByCode normalizes whitespace, case, and fullwidth characters.
+func TestGetStyleByCode_Normalization(t *testing.T) {
+	svc := data.NewBJCPServiceFromData(mockBJCPData())
+
+	for _, code := range []string{" 21A ", "21 a", "\t21A\n", "２１Ａ", "２1ａ", "21\u3000A"} {
+		style, err := svc.GetStyleByCode(code)
+		if err != nil || style.Code != "21A" {
+			t.Errorf("Expected %q to find 21A, got %v (error: %v)", code, style, err)
+		}
+	}
+}
+
+func TestNormalizeStyleCode(t *testing.T) {
+	tests := []struct {
+		code       string
+		normalized string
+		valid      bool
+	}{
+		{"21A", "21A", true},
+		{" 1b ", "1B", true},
+		{"21 A", "21A", true},
+		{"３４Ｃ", "34C", true},
+		{"", "", false},
+		{"21", "21", false},
+		{"123A", "123A", false},
+		{"2!A", "2!A", false},
+		{"２！Ａ", "2!A", false},
+	}
+	for _, tt := range tests {
+		if normalized := data.NormalizeStyleCode(tt.code); normalized != tt.normalized {
+			t.Errorf("Expected %q to normalize to %q, got %q", tt.code, tt.normalized, normalized)
+		}
+		if valid := data.IsStyleCode(tt.code); valid != tt.valid {
+			t.Errorf("Expected IsStyleCode(%q) to be %v", tt.code, tt.valid)
+		}
+	}
+}
+
+func TestCompleteStyleCode(t *testing.T) {
+	svc := data.NewBJCPServiceFromData(mockBJCPData())
+
+	tests := []struct {
+		prefix   string
+		expected string
+	}{
+		{"", "1A,9A,21A,34A"},
+		{"2", "21A"},
+		{" ２1a", "21A"},
+		{"3", "34A"},
+		{"5", ""},
+	}
+	for _, tt := range tests {
+		if codes := strings.Join(svc.CompleteStyleCode(tt.prefix), ","); codes != tt.expected {
+			t.Errorf("Expected %q to complete to %q, got %q", tt.prefix, tt.expected, codes)
+		}
+	}
+	if codes := svc.CompleteStyleCodeVersion("2", "2008"); len(codes) != 0 {
+		t.Errorf("Expected no codes for an unknown version, got %v", codes)
+	}
+}
+
+func TestSuggestStyles(t *testing.T) {
+	bjcpData := mockBJCPData()
+	ipa := bjcpData.Styles["21A"]
+	ipa.Aliases = []string{"West Coast IPA"}
+	bjcpData.Styles["21A"] = ipa
+	svc := data.NewBJCPServiceFromData(bjcpData)
+
+	tests := []struct {
+		prefix   string
+		limit    int
+		expected string
+	}{
+		{"american", 10, "1A,21A"},
+		{" AMERICAN", 1, "1A"},
+		{"west", 10, "21A"},
+		{"21", 10, "21A"},
+		{"lager", 10, ""},
+		{" ", 10, ""},
+	}
+	for _, tt := range tests {
+		var codes []string
+		for _, style := range svc.SuggestStyles(tt.prefix, tt.limit) {
+			codes = append(codes, style.Code)
+		}
+		if got := strings.Join(codes, ","); got != tt.expected {
+			t.Errorf("Expected %q to suggest %q, got %q", tt.prefix, tt.expected, got)
+		}
+	}
+}
+
 // Test GetStyleByCode - Edge Cases.
 func TestGetStyleByCode_EdgeCases(t *testing.T) {
 	tests := []struct {
@@ -253,7 +347,7 @@ func TestGetStyleByCode_EdgeCases(t *testing.T) {
 		{"Empty database", data.NewBJCPServiceFromData(mockEmptyBJCPData()), "21A"},
 		{"Empty string code", data.NewBJCPServiceFromData(mockBJCPData()), ""},
 		{"Whitespace only code", data.NewBJCPServiceFromData(mockBJCPData()), "   "},
-		{"Code with leading/trailing spaces", data.NewBJCPServiceFromData(mockBJCPData()), " 21A "},
+		{"Code with a space in the number", data.NewBJCPServiceFromData(mockBJCPData()), "2 1A2"},
 	}
 
 	for _, tt := range tests {
@@ -491,6 +585,52 @@ func TestGetStylesByCategory_EmptyDatabase(t *testing.T) {
 	}
 }
 
+func TestGetStylesByCategory_OrderedByCode(t *testing.T) {
+	svc := newGuidelineService(t)
+
+	styles := svc.GetStylesByCategory(" strong american ale ")
+	codes := make([]string, 0, len(styles))
+	for _, style := range styles {
+		codes = append(codes, style.Code)
+	}
+	if !reflect.DeepEqual(codes, []string{"22A", "22B", "22C", "22D"}) {
+		t.Errorf("expected 22A to 22D in order, got %v", codes)
+	}
+	if styles := svc.GetStylesByCategoryVersion("IPA", "1999"); len(styles) != 0 {
+		t.Errorf("expected no styles in an unknown version, got %d", len(styles))
+	}
+}
+
+func TestGetCategorySummaries(t *testing.T) {
+	bjcpData := &data.BJCPData{
+		Styles: map[string]data.BJCPStyle{
+			"10A": {Code: "10A", Category: "German Wheat Beer"},
+			"10C": {Code: "10C", Category: "German Wheat Beer"},
+			"10B": {Code: "10B", Category: "german wheat beer"},
+			"9A":  {Code: "9A", Category: "Strong European Beer"},
+		},
+		Categories: []string{"Strong European Beer", "German Wheat Beer", "Smoked Beer"},
+	}
+	svc := data.NewBJCPServiceFromData(bjcpData)
+
+	expected := []data.CategorySummary{
+		{Name: "Strong European Beer", StyleCount: 1, CodeRange: "9A"},
+		{Name: "German Wheat Beer", StyleCount: 3, CodeRange: "10A-10C"},
+		{Name: "Smoked Beer", StyleCount: 0, CodeRange: ""},
+	}
+	if summaries := svc.GetCategorySummaries(); !reflect.DeepEqual(summaries, expected) {
+		t.Errorf("expected %+v, got %+v", expected, summaries)
+	}
+}
+
+func TestCompareStyleCodes(t *testing.T) {
+	codes := []string{"10A", "2B", "2A", "34C", "9A"}
+	slices.SortFunc(codes, data.CompareStyleCodes)
+	if expected := []string{"2A", "2B", "9A", "10A", "34C"}; !reflect.DeepEqual(codes, expected) {
+		t.Errorf("expected %v, got %v", expected, codes)
+	}
+}
+
 // Test GetMetadata - Happy Path.
 func TestGetMetadata_HappyPath(t *testing.T) {
 	svc := data.NewBJCPServiceFromData(mockBJCPData())
@@ -699,33 +839,28 @@ func testConcurrentGetAllStyles(t *testing.T, svc *data.BJCPService, id int) {
 	}
 }
 
-// Test LoadBJCPData function.
+// Test LoadBJCPData loads the embedded BJCP 2021 data by default, from any working directory.
 func TestLoadBJCPData(t *testing.T) {
-	// Test that LoadBJCPData returns an error when file doesn't exist
-	// We can't test successful loading without the actual data file in the test environment,
-	// but we can test the error path
-	_, err := data.LoadBJCPData()
-	// In test environment, this will likely fail due to missing data file, which is expected
-	if err == nil {
-		// If it succeeds, that's actually fine too - means the data file exists
-		t.Log("LoadBJCPData succeeded - data file is available")
-	} else if !strings.Contains(err.Error(), "data file") && !strings.Contains(err.Error(), "no such file") {
-		// Expected in test environment
-		t.Errorf("Expected file-related error, got: %v", err)
-	}
-}
+	t.Setenv(data.BJCPDataPathEnv, "")
+	t.Chdir(t.TempDir())
 
-// Test LoadBJCPData with simulated data file.
-func TestLoadBJCPData_WithMockData(t *testing.T) {
-	// Create temporary directory structure
-	tempDir := t.TempDir()
-	dataDir := filepath.Join(tempDir, "data")
-	err := os.MkdirAll(dataDir, 0o755)
+	bjcpData, err := data.LoadBJCPData()
 	if err != nil {
-		t.Fatalf("Failed to create temp data directory: %v", err)
+		t.Fatalf("Expected the embedded data to load, got error: %v", err)
 	}
+	if bjcpData.Metadata.Version != "2021" {
+		t.Errorf("Expected version '2021', got '%s'", bjcpData.Metadata.Version)
+	}
+	if len(bjcpData.Styles) != bjcpData.Metadata.TotalStyles || len(bjcpData.Styles) < 100 {
+		t.Errorf("Expected the %d styles in the metadata, got %d", bjcpData.Metadata.TotalStyles, len(bjcpData.Styles))
+	}
+	if _, ok := bjcpData.Styles["21A"]; !ok {
+		t.Error("Expected style 21A in the embedded data")
+	}
+}
 
-	// Create a valid JSON file
+// Test LoadBJCPData with a custom data file named by BJCP_DATA_PATH.
+func TestLoadBJCPData_OverrideFile(t *testing.T) {
 	validJSON := `{
 		"styles": {
 			"21A": {
@@ -749,52 +884,34 @@ func TestLoadBJCPData_WithMockData(t *testing.T) {
 		},
 		"categories": ["IPA"],
 		"metadata": {
-			"version": "2021",
-			"source": "BJCP Style Guidelines",
+			"version": "custom",
+			"source": "House Style Guidelines",
 			"last_updated": "2025-01-01",
 			"total_styles": 1
 		}
 	}`
 
-	bjcpFile := filepath.Join(dataDir, "bjcp_2021_beer.json")
-	err = os.WriteFile(bjcpFile, []byte(validJSON), 0o644)
-	if err != nil {
+	bjcpFile := filepath.Join(t.TempDir(), "house_styles.json")
+	if err := os.WriteFile(bjcpFile, []byte(validJSON), 0o644); err != nil {
 		t.Fatalf("Failed to write test data file: %v", err)
 	}
+	t.Setenv(data.BJCPDataPathEnv, bjcpFile)
 
-	// Change to temp directory
-	t.Chdir(tempDir)
-
-	// Test successful loading
 	bjcpData, err := data.LoadBJCPData()
 	if err != nil {
 		t.Fatalf("Expected successful loading, got error: %v", err)
 	}
-
-	if bjcpData == nil {
-		t.Fatal("Expected non-nil BJCP data")
-	}
-
 	if len(bjcpData.Styles) != 1 {
 		t.Errorf("Expected 1 style, got %d", len(bjcpData.Styles))
 	}
-
-	if bjcpData.Metadata.Version != "2021" {
-		t.Errorf("Expected version '2021', got '%s'", bjcpData.Metadata.Version)
+	if bjcpData.Metadata.Version != "custom" {
+		t.Errorf("Expected version 'custom', got '%s'", bjcpData.Metadata.Version)
 	}
 }
 
-// Test LoadBJCPData with invalid JSON.
-func TestLoadBJCPData_InvalidJSON(t *testing.T) {
-	// Create temporary directory structure
-	tempDir := t.TempDir()
-	dataDir := filepath.Join(tempDir, "data")
-	err := os.MkdirAll(dataDir, 0o755)
-	if err != nil {
-		t.Fatalf("Failed to create temp data directory: %v", err)
-	}
-
-	// Create invalid JSON file
+// Test LoadBJCPData with an override file that is missing or not valid JSON.
+func TestLoadBJCPData_OverrideFileErrors(t *testing.T) {
+	invalidFile := filepath.Join(t.TempDir(), "invalid.json")
 	invalidJSON := `{
 		"styles": {
 			"21A": {
@@ -804,68 +921,250 @@ func TestLoadBJCPData_InvalidJSON(t *testing.T) {
 			}
 		}
 	}`
+	if err := os.WriteFile(invalidFile, []byte(invalidJSON), 0o644); err != nil {
+		t.Fatalf("Failed to write test data file: %v", err)
+	}
 
-	bjcpFile := filepath.Join(dataDir, "bjcp_2021_beer.json")
-	err = os.WriteFile(bjcpFile, []byte(invalidJSON), 0o644)
+	tests := []struct {
+		name     string
+		path     string
+		expected string
+	}{
+		{"missing file", filepath.Join(t.TempDir(), "missing.json"), "failed to read BJCP data file"},
+		{"invalid JSON", invalidFile, "failed to parse BJCP data"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(data.BJCPDataPathEnv, tt.path)
+			_, err := data.LoadBJCPData()
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("Expected an error containing %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+// Test NewBJCPService function.
+func TestNewBJCPService(t *testing.T) {
+	t.Setenv(data.BJCPDataPathEnv, "")
+	svc, err := data.NewBJCPService()
 	if err != nil {
+		t.Fatalf("Expected a service over the embedded data, got error: %v", err)
+	}
+	if _, err = svc.GetStyleByCode("21A"); err != nil {
+		t.Errorf("Expected style 21A, got error: %v", err)
+	}
+
+	// A failing load is returned
+	t.Setenv(data.BJCPDataPathEnv, filepath.Join(t.TempDir(), "missing.json"))
+	if _, err = data.NewBJCPService(); err == nil {
+		t.Error("Expected an error when the override file is missing")
+	}
+}
+
+// writeStyleFile writes style data with one style, of the given code and metadata version, and
+// returns its path.
+func writeStyleFile(t *testing.T, code, version string) string {
+	t.Helper()
+	contents := `{"styles": {"` + code + `": {"code": "` + code + `", "name": "House Ale"}},` +
+		`"categories": ["House"], "metadata": {"version": "` + version + `", "total_styles": 1}}`
+	path := filepath.Join(t.TempDir(), "styles.json")
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
 		t.Fatalf("Failed to write test data file: %v", err)
 	}
+	return path
+}
 
-	// Change to temp directory
-	t.Chdir(tempDir)
+// Test BJCPService.Reload swaps in new data and keeps the current data when loading fails.
+func TestBJCPService_Reload(t *testing.T) {
+	svc := data.NewBJCPServiceFromData(mockBJCPData())
+	original := svc.Fingerprint()
+	if original == "" || original != data.NewBJCPServiceFromData(mockBJCPData()).Fingerprint() {
+		t.Errorf("Expected equal data to have the same fingerprint, got %q", original)
+	}
 
-	// Test that invalid JSON returns parse error
-	_, err = data.LoadBJCPData()
-	if err == nil {
-		t.Fatal("Expected error for invalid JSON, got nil")
+	t.Setenv(data.BJCPDataPathEnv, writeStyleFile(t, "99Z", "house-1"))
+	if err := svc.Reload(); err != nil {
+		t.Fatalf("Expected the reload to succeed, got error: %v", err)
+	}
+	reloaded := svc.Fingerprint()
+	if reloaded == original {
+		t.Error("Expected the fingerprint to change with the data")
+	}
+	if svc.GetMetadata().Version != "house-1" {
+		t.Errorf("Expected version 'house-1', got '%s'", svc.GetMetadata().Version)
+	}
+	if _, err := svc.GetStyleByCode("99Z"); err != nil {
+		t.Errorf("Expected the reloaded style 99Z, got error: %v", err)
+	}
+	if _, err := svc.GetStyleByCode("21A"); err == nil {
+		t.Error("Expected the previous style 21A to be gone")
 	}
 
-	if !strings.Contains(err.Error(), "parse") {
-		t.Errorf("Expected parse error, got: %v", err)
+	invalidFile := filepath.Join(t.TempDir(), "invalid.json")
+	emptyFile := filepath.Join(t.TempDir(), "empty.json")
+	if err := os.WriteFile(invalidFile, []byte(`{"styles": `), 0o644); err != nil {
+		t.Fatalf("Failed to write test data file: %v", err)
+	}
+	if err := os.WriteFile(emptyFile, []byte(`{"styles": {}}`), 0o644); err != nil {
+		t.Fatalf("Failed to write test data file: %v", err)
+	}
+	for _, path := range []string{invalidFile, emptyFile, filepath.Join(t.TempDir(), "missing.json")} {
+		t.Setenv(data.BJCPDataPathEnv, path)
+		if err := svc.Reload(); err == nil {
+			t.Errorf("Expected reloading %s to fail", filepath.Base(path))
+		}
+		if svc.GetMetadata().Version != "house-1" || svc.Fingerprint() != reloaded {
+			t.Errorf("Expected a failed reload to keep version 'house-1', got '%s'", svc.GetMetadata().Version)
+		}
 	}
 }
 
-// Test LoadBJCPData path traversal protection.
-func TestLoadBJCPData_PathTraversalProtection(t *testing.T) {
-	// The LoadBJCPData function has built-in path traversal protection
-	// We can test this by temporarily changing the working directory
-	// to a location where the path validation would detect traversal
+// Test BJCPService.Health reports the data loaded and a failed reload leaving it stale.
+func TestBJCPService_Health(t *testing.T) {
+	svc := data.NewBJCPServiceFromData(mockBJCPData())
+	health := svc.Health()
+	if health.Styles != len(mockBJCPData().Styles) || health.Version != mockBJCPData().Metadata.Version {
+		t.Errorf("Expected the mock data's styles and version, got %+v", health)
+	}
+	if health.LoadedAt.IsZero() || health.LastError != "" || health.LastErrorAt != nil || health.Stale() {
+		t.Errorf("Expected a fresh load without errors, got %+v", health)
+	}
 
-	// Create a temporary directory structure that would trigger path validation
-	tempDir := t.TempDir()
+	t.Setenv(data.BJCPDataPathEnv, filepath.Join(t.TempDir(), "missing.json"))
+	if err := svc.Reload(); err == nil {
+		t.Fatal("Expected reloading a missing file to fail")
+	}
+	failed := svc.Health()
+	if failed.LastError == "" || failed.LastErrorAt == nil || !failed.Stale() {
+		t.Errorf("Expected the failed reload to be reported, got %+v", failed)
+	}
+	if failed.Styles != health.Styles || !failed.LoadedAt.Equal(health.LoadedAt) {
+		t.Errorf("Expected the previous data to be kept, got %+v", failed)
+	}
 
-	// Change to a subdirectory
-	subDir := filepath.Join(tempDir, "subdir")
-	err := os.MkdirAll(subDir, 0o755)
-	if err != nil {
-		t.Fatalf("Failed to create subdirectory: %v", err)
+	t.Setenv(data.BJCPDataPathEnv, writeStyleFile(t, "99Z", "house-1"))
+	if err := svc.Reload(); err != nil {
+		t.Fatalf("Expected the reload to succeed, got error: %v", err)
+	}
+	reloaded := svc.Health()
+	if reloaded.Styles != 1 || reloaded.Version != "house-1" || reloaded.Stale() {
+		t.Errorf("Expected the reloaded data to be current, got %+v", reloaded)
+	}
+	if reloaded.LastError != failed.LastError || !reloaded.LoadedAt.After(health.LoadedAt) {
+		t.Errorf("Expected a new load time and the earlier error kept, got %+v", reloaded)
 	}
 
-	t.Chdir(subDir)
+	if empty := data.NewBJCPServiceFromData(nil).Health(); empty.Styles != 0 {
+		t.Errorf("Expected no styles without data, got %d", empty.Styles)
+	}
+}
 
-	// Now try to load BJCP data - this should fail with file not found
-	// since there's no data directory in our temp subdirectory
-	_, err = data.LoadBJCPData()
-	if err == nil {
-		t.Error("Expected error when data file doesn't exist")
+// Test that lookups stay safe while the data is reloaded.
+func TestBJCPService_ReloadConcurrentReads(t *testing.T) {
+	svc := data.NewBJCPServiceFromData(mockBJCPData())
+	t.Setenv(data.BJCPDataPathEnv, writeStyleFile(t, "21A", "house-1"))
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				if _, err := svc.GetStyleByCode("21A"); err != nil {
+					t.Errorf("Expected style 21A in either dataset, got error: %v", err)
+					return
+				}
+				_ = svc.GetAllStyles()
+			}
+		}()
+	}
+	for range 20 {
+		if err := svc.Reload(); err != nil {
+			t.Errorf("Reload failed: %v", err)
+		}
+	}
+	wg.Wait()
+}
+
+// Test LoadBJCPGuidelines loads each guideline version available and tolerates a missing one.
+func TestLoadBJCPGuidelines(t *testing.T) {
+	t.Setenv(data.BJCPDataPathEnv, "")
+	invalidFile := filepath.Join(t.TempDir(), "invalid.json")
+	if err := os.WriteFile(invalidFile, []byte(`{"styles": `), 0o644); err != nil {
+		t.Fatalf("Failed to write test data file: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		path2015 string
+		versions []string
+		errText  string
+	}{
+		{"2015 not configured", "", []string{"2021"}, ""},
+		{"2015 file", writeStyleFile(t, "14B", "2015"), []string{"2021", "2015"}, ""},
+		{"2015 file missing", filepath.Join(t.TempDir(), "missing.json"), []string{"2021"}, ""},
+		{"2015 file invalid", invalidFile, nil, "BJCP 2015 guidelines: failed to parse BJCP data"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(data.GuidelineDataPathEnv("2015"), tt.path2015)
+			guidelines, err := data.LoadBJCPGuidelines()
+			if tt.errText != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errText) {
+					t.Errorf("Expected an error containing %q, got %v", tt.errText, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected the guidelines to load, got error: %v", err)
+			}
+			versions := data.NewBJCPServiceFromGuidelines(guidelines).Versions()
+			if !reflect.DeepEqual(versions, tt.versions) {
+				t.Errorf("Expected versions %v, got %v", tt.versions, versions)
+			}
+		})
 	}
 
-	// The error should be about file not being found, not path traversal
-	if strings.Contains(err.Error(), "path traversal") {
-		t.Error("Unexpected path traversal error in normal scenario")
+	// The default version is required
+	t.Setenv(data.GuidelineDataPathEnv("2015"), "")
+	t.Setenv(data.BJCPDataPathEnv, filepath.Join(t.TempDir(), "missing.json"))
+	if _, err := data.LoadBJCPGuidelines(); err == nil {
+		t.Error("Expected an error when the 2021 data file is missing")
 	}
 }
 
-// Test NewBJCPService function.
-func TestNewBJCPService(t *testing.T) {
-	// Test that NewBJCPService returns an error when LoadBJCPData fails
-	_, err := data.NewBJCPService()
-	// In test environment, this will likely fail due to missing data file, which is expected
-	if err == nil {
-		// If it succeeds, that's actually fine too - means the data file exists
-		t.Log("NewBJCPService succeeded - data file is available")
-	} else if !strings.Contains(err.Error(), "data file") && !strings.Contains(err.Error(), "no such file") {
-		// Expected in test environment
-		t.Errorf("Expected file-related error, got: %v", err)
+// Test lookups in a specific guideline version.
+func TestBJCPService_GuidelineVersions(t *testing.T) {
+	styles2015 := &data.BJCPData{Styles: map[string]data.BJCPStyle{
+		"14B": {Code: "14B", Name: "Scottish Heavy"},
+	}}
+	svc := data.NewBJCPServiceFromGuidelines(map[string]*data.BJCPData{
+		data.DefaultGuidelineVersion: mockBJCPData(),
+		"2015":                       styles2015,
+	})
+
+	if style, err := svc.GetStyleByCodeVersion("14b", "2015"); err != nil || style.Name != "Scottish Heavy" {
+		t.Errorf("Expected 2015 style 14B, got %v (error: %v)", style, err)
+	}
+	if style, err := svc.GetStyleByNameVersion("scottish", "2015"); err != nil || style.Code != "14B" {
+		t.Errorf("Expected 2015 style 14B by name, got %v (error: %v)", style, err)
+	}
+	if _, err := svc.GetStyleByCodeVersion("21A", "2015"); err == nil {
+		t.Error("Expected 21A to be missing from the 2015 data")
+	}
+	if style, err := svc.GetStyleByCodeVersion("21A", ""); err != nil || style.Name != "American IPA" {
+		t.Errorf("Expected an empty version to use the 2021 data, got %v (error: %v)", style, err)
+	}
+	if _, err := svc.GetStyleByCode("14B"); err == nil {
+		t.Error("Expected GetStyleByCode to use only the 2021 data")
+	}
+
+	_, err := svc.GetStyleByCodeVersion("21A", "2008")
+	if !errors.Is(err, data.ErrUnknownGuidelineVersion) {
+		t.Fatalf("Expected ErrUnknownGuidelineVersion, got %v", err)
+	}
+	if !strings.Contains(err.Error(), `"2008": loaded versions are 2021, 2015`) {
+		t.Errorf("Expected the error to list the loaded versions, got: %v", err)
 	}
 }
@@ -0,0 +1,56 @@
+package data
+
+// VitalDiff compares one vital range between two styles.
+type VitalDiff struct {
+	Vital         string  `json:"vital"`
+	AMin          float64 `json:"a_min"`
+	AMax          float64 `json:"a_max"`
+	BMin          float64 `json:"b_min"`
+	BMax          float64 `json:"b_max"`
+	Overlaps      bool    `json:"overlaps"`
+	OverlapMin    float64 `json:"overlap_min,omitempty"`
+	OverlapMax    float64 `json:"overlap_max,omitempty"`
+	MidpointDelta float64 `json:"midpoint_delta"`
+}
+
+// StyleDiff is a structured side-by-side comparison of two BJCP styles.
+// Midpoint deltas are expressed as B minus A.
+type StyleDiff struct {
+	StyleA string      `json:"style_a"`
+	StyleB string      `json:"style_b"`
+	Vitals []VitalDiff `json:"vitals"`
+}
+
+// CompareStyles diffs the vitals ranges of two styles.
+func CompareStyles(a, b *BJCPStyle) StyleDiff {
+	va, vb := a.Vitals, b.Vitals
+	return StyleDiff{
+		StyleA: a.Code,
+		StyleB: b.Code,
+		Vitals: []VitalDiff{
+			diffRange("abv", va.ABVMin, va.ABVMax, vb.ABVMin, vb.ABVMax),
+			diffRange("ibu", float64(va.IBUMin), float64(va.IBUMax), float64(vb.IBUMin), float64(vb.IBUMax)),
+			diffRange("srm", va.SRMMin, va.SRMMax, vb.SRMMin, vb.SRMMax),
+			diffRange("og", va.OGMin, va.OGMax, vb.OGMin, vb.OGMax),
+			diffRange("fg", va.FGMin, va.FGMax, vb.FGMin, vb.FGMax),
+		},
+	}
+}
+
+func diffRange(vital string, aMin, aMax, bMin, bMax float64) VitalDiff {
+	diff := VitalDiff{
+		Vital:         vital,
+		AMin:          aMin,
+		AMax:          aMax,
+		BMin:          bMin,
+		BMax:          bMax,
+		MidpointDelta: (bMin+bMax)/2 - (aMin+aMax)/2,
+	}
+	low, high := max(aMin, bMin), min(aMax, bMax)
+	if low <= high {
+		diff.Overlaps = true
+		diff.OverlapMin = low
+		diff.OverlapMax = high
+	}
+	return diff
+}
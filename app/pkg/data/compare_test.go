@@ -0,0 +1,67 @@
+package data_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/CharlRitter/brewsource-mcp/app/pkg/data"
+)
+
+func TestCompareStyles(t *testing.T) {
+	svc := data.NewBJCPServiceFromData(mockBJCPData())
+	ipa, _ := svc.GetStyleByCode("21A")
+	doppelbock, _ := svc.GetStyleByCode("9A")
+
+	diff := data.CompareStyles(ipa, doppelbock)
+	if diff.StyleA != "21A" || diff.StyleB != "9A" {
+		t.Errorf("unexpected style codes: %s vs %s", diff.StyleA, diff.StyleB)
+	}
+	if len(diff.Vitals) != 5 {
+		t.Fatalf("expected 5 vital diffs, got %d", len(diff.Vitals))
+	}
+
+	byVital := map[string]data.VitalDiff{}
+	for _, v := range diff.Vitals {
+		byVital[v.Vital] = v
+	}
+
+	tests := []struct {
+		vital            string
+		wantOverlap      bool
+		wantOverlapRange [2]float64
+		wantDelta        float64
+	}{
+		{vital: "abv", wantOverlap: true, wantOverlapRange: [2]float64{7.0, 7.5}, wantDelta: 2.0},
+		{vital: "ibu", wantOverlap: false, wantDelta: -34},
+		{vital: "srm", wantOverlap: true, wantOverlapRange: [2]float64{6, 14}, wantDelta: 5.5},
+		{vital: "og", wantOverlap: false, wantDelta: 0.029},
+		{vital: "fg", wantOverlap: false, wantDelta: 0.009},
+	}
+	for _, tt := range tests {
+		t.Run(tt.vital, func(t *testing.T) {
+			got := byVital[tt.vital]
+			if got.Overlaps != tt.wantOverlap {
+				t.Errorf("overlaps = %v, want %v", got.Overlaps, tt.wantOverlap)
+			}
+			gotRange := [2]float64{got.OverlapMin, got.OverlapMax}
+			if tt.wantOverlap && gotRange != tt.wantOverlapRange {
+				t.Errorf("overlap = %v-%v, want %v", got.OverlapMin, got.OverlapMax, tt.wantOverlapRange)
+			}
+			if math.Abs(got.MidpointDelta-tt.wantDelta) > 1e-9 {
+				t.Errorf("midpoint delta = %v, want %v", got.MidpointDelta, tt.wantDelta)
+			}
+		})
+	}
+}
+
+func TestCompareStyles_SameStyle(t *testing.T) {
+	svc := data.NewBJCPServiceFromData(mockBJCPData())
+	ipa, _ := svc.GetStyleByCode("21A")
+
+	diff := data.CompareStyles(ipa, ipa)
+	for _, v := range diff.Vitals {
+		if !v.Overlaps || v.MidpointDelta != 0 {
+			t.Errorf("%s: expected full overlap and zero delta, got %+v", v.Vital, v)
+		}
+	}
+}
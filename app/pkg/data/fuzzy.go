@@ -0,0 +1,163 @@
+package data
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// minNameSimilarity is the lowest similarity SearchStylesByName returns a style for.
+const minNameSimilarity = 0.5
+
+// ScoredStyle is a style ranked by SearchStylesByName, with its name similarity from 0 to 1.
+type ScoredStyle struct {
+	Style BJCPStyle `json:"style"`
+	Score float64   `json:"score"`
+}
+
+// SearchStylesByName ranks the styles by how closely their names match name, tolerating typos and
+// partial names, and returns up to limit of them, best first. Unlike GetStyleByName it returns
+// every plausible candidate, so an ambiguous term such as "IPA" lists each IPA style. A
+// non-positive limit returns all candidates.
+func (s *BJCPService) SearchStylesByName(name string, limit int) []ScoredStyle {
+	return s.SearchStylesByNameVersion(name, DefaultGuidelineVersion, limit)
+}
+
+// SearchStylesByNameVersion is SearchStylesByName over a guideline version, see DataVersion. An
+// unknown version has no candidates.
+func (s *BJCPService) SearchStylesByNameVersion(name, version string, limit int) []ScoredStyle {
+	query := normalizeName(name)
+	bjcpData, err := s.DataVersion(version)
+	if query == "" || err != nil {
+		return []ScoredStyle{}
+	}
+
+	candidates := []ScoredStyle{}
+	for _, style := range bjcpData.Styles {
+		if score := nameSimilarity(query, normalizeName(style.Name)); score >= minNameSimilarity {
+			candidates = append(candidates, ScoredStyle{Style: style, Score: score})
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].Score != candidates[j].Score {
+			return candidates[i].Score > candidates[j].Score
+		}
+		return candidates[i].Style.Code < candidates[j].Style.Code
+	})
+	if limit > 0 && len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+	return candidates
+}
+
+// SearchCategoriesByName lists up to limit category names closest to name, best first, for
+// suggesting the category meant by a misspelled or partial name. A non-positive limit returns all
+// candidates.
+func (s *BJCPService) SearchCategoriesByName(name string, limit int) []string {
+	return s.SearchCategoriesByNameVersion(name, DefaultGuidelineVersion, limit)
+}
+
+// SearchCategoriesByNameVersion is SearchCategoriesByName over a guideline version, see
+// DataVersion. An unknown version has no candidates.
+func (s *BJCPService) SearchCategoriesByNameVersion(name, version string, limit int) []string {
+	query := normalizeName(name)
+	bjcpData, err := s.DataVersion(version)
+	if query == "" || err != nil {
+		return []string{}
+	}
+
+	type scoredCategory struct {
+		name  string
+		score float64
+	}
+	candidates := []scoredCategory{}
+	for _, category := range bjcpData.Categories {
+		if score := nameSimilarity(query, normalizeName(category)); score >= minNameSimilarity {
+			candidates = append(candidates, scoredCategory{name: category, score: score})
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].score != candidates[j].score {
+			return candidates[i].score > candidates[j].score
+		}
+		return candidates[i].name < candidates[j].name
+	})
+	if limit > 0 && len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+	names := make([]string, 0, len(candidates))
+	for _, candidate := range candidates {
+		names = append(names, candidate.name)
+	}
+	return names
+}
+
+// NameSimilarity scores from 0 to 1 how closely name matches query, as SearchStylesByName scores
+// style names: typos lower the score in proportion to their number, and a query matching only part
+// of name scores at most 1 and at least 0.6 of its similarity to that part.
+func NameSimilarity(query, name string) float64 {
+	normalizedQuery, normalizedName := normalizeName(query), normalizeName(name)
+	if normalizedQuery == "" || normalizedName == "" {
+		return 0
+	}
+	return nameSimilarity(normalizedQuery, normalizedName)
+}
+
+// normalizeName lowercases name and reduces it to words of letters and digits separated by single
+// spaces, so "Kölsch" and "kölsch", or "Brown-Porter" and "brown porter", compare equal.
+func normalizeName(name string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
+
+// nameSimilarity scores a normalized query against a normalized style name. The whole name is
+// compared by edit distance, and so is each run of as many words as the query has, for partial
+// names; a partial match scores at most 1 and at least 0.6 of its similarity, in proportion to how
+// much of the name it covers.
+func nameSimilarity(query, name string) float64 {
+	best := similarity(query, name)
+
+	queryWords := len(strings.Fields(query))
+	nameWords := strings.Fields(name)
+	for start := 0; start+queryWords <= len(nameWords); start++ {
+		window := strings.Join(nameWords[start:start+queryWords], " ")
+		coverage := float64(len(window)) / float64(len(name))
+		best = max(best, similarity(query, window)*(0.6+0.4*coverage))
+	}
+	return best
+}
+
+// similarity is 1 minus the Levenshtein distance between a and b, relative to the longer of them.
+func similarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	longest := max(len(ra), len(rb))
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+// levenshtein returns the number of single rune insertions, deletions and substitutions that turn
+// a into b.
+func levenshtein(a, b []rune) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
@@ -0,0 +1,125 @@
+package data_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/CharlRitter/brewsource-mcp/app/pkg/data"
+)
+
+// newGuidelineService returns a service over the embedded BJCP 2021 styles.
+func newGuidelineService(t *testing.T) *data.BJCPService {
+	t.Helper()
+	t.Setenv(data.BJCPDataPathEnv, "")
+	svc, err := data.NewBJCPService()
+	if err != nil {
+		t.Fatalf("Failed to load the embedded styles: %v", err)
+	}
+	return svc
+}
+
+func TestSearchStylesByName(t *testing.T) {
+	svc := newGuidelineService(t)
+
+	tests := []struct {
+		name  string
+		query string
+		first string
+	}{
+		{"exact name", "American IPA", "21A"},
+		{"case and punctuation", "  american-ipa ", "21A"},
+		{"missing letter", "Amercan IPA", "21A"},
+		{"transposed letters", "Imperial Sotut", "20C"},
+		{"misspelled word", "Belgian Dubel", "26B"},
+		{"substring", "Oatmeal", "16B"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := svc.SearchStylesByName(tt.query, 3)
+			if len(results) == 0 || results[0].Style.Code != tt.first {
+				t.Fatalf("Expected %s first for %q, got %v", tt.first, tt.query, results)
+			}
+			if len(results) > 1 && results[1].Score > results[0].Score {
+				t.Errorf("Expected results ranked best first, got %v", results)
+			}
+		})
+	}
+
+	exact := svc.SearchStylesByName("American IPA", 1)
+	if exact[0].Score != 1 {
+		t.Errorf("Expected an exact name to score 1, got %.3f", exact[0].Score)
+	}
+}
+
+// Test that an ambiguous term lists every style it could mean.
+func TestSearchStylesByName_Ambiguous(t *testing.T) {
+	svc := newGuidelineService(t)
+
+	results := svc.SearchStylesByName("IPA", 0)
+	codes := map[string]bool{}
+	for _, result := range results {
+		codes[result.Style.Code] = true
+		if result.Score >= 1 {
+			t.Errorf("Expected a partial name to score below 1, got %s %.3f", result.Style.Code, result.Score)
+		}
+	}
+	for _, code := range []string{"12C", "21A", "21B", "21C", "22A"} {
+		if !codes[code] {
+			t.Errorf("Expected IPA style %s among the candidates, got %v", code, results)
+		}
+	}
+
+	if limited := svc.SearchStylesByName("IPA", 2); len(limited) != 2 {
+		t.Errorf("Expected the limit to apply, got %d results", len(limited))
+	}
+}
+
+func TestSearchStylesByName_NoCandidates(t *testing.T) {
+	svc := newGuidelineService(t)
+
+	for _, query := range []string{"", "  ", "xyzzy"} {
+		if results := svc.SearchStylesByName(query, 5); len(results) != 0 {
+			t.Errorf("Expected no candidates for %q, got %v", query, results)
+		}
+	}
+	if results := svc.SearchStylesByNameVersion("IPA", "2008", 5); len(results) != 0 {
+		t.Errorf("Expected no candidates in an unknown version, got %v", results)
+	}
+}
+
+func TestNameSimilarity(t *testing.T) {
+	tests := []struct {
+		query, name      string
+		minimum, maximum float64
+	}{
+		{"Stone IPA", "stone  ipa", 1, 1},
+		{"Bell's Two Hearted Ale", "Bell's Brewery Two Hearted Ale", 0.7, 0.99},
+		{"Stone IPA", "Stone Pale Ale", 0, 0.69},
+		{"Stone IPA", "", 0, 0},
+		{"!!", "Stone IPA", 0, 0},
+	}
+	for _, tt := range tests {
+		if score := data.NameSimilarity(tt.query, tt.name); score < tt.minimum || score > tt.maximum {
+			t.Errorf("NameSimilarity(%q, %q) = %.3f, want %.2f to %.2f",
+				tt.query, tt.name, score, tt.minimum, tt.maximum)
+		}
+	}
+}
+
+func TestSearchCategoriesByName(t *testing.T) {
+	svc := newGuidelineService(t)
+
+	names := svc.SearchCategoriesByName("Strong Belgain Ale", 3)
+	if len(names) == 0 || names[0] != "Strong Belgian Ale" {
+		t.Errorf("Expected Strong Belgian Ale first for a typo, got %v", names)
+	}
+	names = svc.SearchCategoriesByName("Lager", 0)
+	for _, category := range []string{"Czech Lager", "International Lager", "Dark European Lager"} {
+		if !slices.Contains(names, category) {
+			t.Errorf("Expected %s among the categories matching Lager, got %v", category, names)
+		}
+	}
+	if names = svc.SearchCategoriesByName("xyzzy", 3); len(names) != 0 {
+		t.Errorf("Expected no candidates, got %v", names)
+	}
+}
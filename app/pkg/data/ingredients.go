@@ -0,0 +1,335 @@
+package data
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Kinds of ingredient in the reference data.
+const (
+	IngredientHop         = "hop"
+	IngredientFermentable = "fermentable"
+	IngredientYeast       = "yeast"
+)
+
+// IngredientKinds lists the kinds of ingredient, in the order SearchIngredients ranks ties.
+var IngredientKinds = []string{IngredientHop, IngredientFermentable, IngredientYeast}
+
+// minIngredientMatch is the lowest similarity FindHop, FindFermentable, and FindYeast accept as the
+// ingredient meant: stricter than minNameSimilarity, since a calculator fills in values from the
+// match rather than listing it as a candidate.
+const minIngredientMatch = 0.8
+
+// ErrIngredientNotFound is returned when no ingredient closely matches a name.
+var ErrIngredientNotFound = errors.New("ingredient not found")
+
+// Hop is a hop variety.
+type Hop struct {
+	Name         string   `json:"name"`
+	Origin       string   `json:"origin"` // ISO country code of the growing region
+	AlphaAcidMin float64  `json:"alpha_acid_min"`
+	AlphaAcidMax float64  `json:"alpha_acid_max"`
+	Substitutes  []string `json:"substitutes"` // names of hops in the data
+	Aroma        []string `json:"aroma"`
+	Aliases      []string `json:"aliases,omitempty"`
+}
+
+// AlphaAcid is the midpoint of the hop's typical alpha acid range, the value to assume when a
+// recipe does not give the lot's alpha acid.
+func (h Hop) AlphaAcid() float64 {
+	return (h.AlphaAcidMin + h.AlphaAcidMax) / 2
+}
+
+// Fermentable is a malt, adjunct, sugar, or extract.
+type Fermentable struct {
+	Name     string   `json:"name"`
+	Type     string   `json:"type"` // base, crystal, specialty, roasted, adjunct, sugar, or extract
+	Lovibond float64  `json:"lovibond"`
+	PPG      float64  `json:"ppg"` // gravity points per pound per gallon
+	Aliases  []string `json:"aliases,omitempty"`
+}
+
+// Yeast is a commercial yeast strain.
+type Yeast struct {
+	Name           string  `json:"name"`
+	Lab            string  `json:"lab"`
+	Code           string  `json:"code"`
+	Type           string  `json:"type"` // ale, lager, wheat, belgian, saison, or kveik
+	AttenuationMin float64 `json:"attenuation_min"`
+	AttenuationMax float64 `json:"attenuation_max"`
+	TempMinF       float64 `json:"temp_min_f"`
+	TempMaxF       float64 `json:"temp_max_f"`
+	Flocculation   string  `json:"flocculation"`
+}
+
+// Label names the strain as brewers do, lab and code first, such as "Wyeast 1056 American Ale".
+func (y Yeast) Label() string {
+	return fmt.Sprintf("%s %s %s", y.Lab, y.Code, y.Name)
+}
+
+// IngredientData is the ingredient reference data.
+type IngredientData struct {
+	Hops         []Hop         `json:"hops"`
+	Fermentables []Fermentable `json:"fermentables"`
+	Yeasts       []Yeast       `json:"yeasts"`
+}
+
+// ingredientFiles holds the bundled ingredient data, one file per kind.
+//
+//go:embed hops.json fermentables.json yeast.json
+var ingredientFiles embed.FS
+
+// LoadIngredientData loads, parses, and validates the embedded ingredient data.
+func LoadIngredientData() (*IngredientData, error) {
+	var ingredientData IngredientData
+	for _, name := range []string{"hops.json", "fermentables.json", "yeast.json"} {
+		raw, err := ingredientFiles.ReadFile(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read ingredient data file: %w", err)
+		}
+		if err := json.Unmarshal(raw, &ingredientData); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", name, err)
+		}
+	}
+	if err := ValidateIngredientData(&ingredientData); err != nil {
+		return nil, err
+	}
+	return &ingredientData, nil
+}
+
+// ErrInvalidIngredientData is returned, joined with every problem found, for ingredient data that
+// fails ValidateIngredientData.
+var ErrInvalidIngredientData = errors.New("invalid ingredient data")
+
+// ValidateIngredientData checks ingredient data for ingredients without a name, names listed more
+// than once in a kind, ranges whose minimum exceeds their maximum, negative colours or yields, and
+// hop substitutes that are not in the data. It reports every problem found, joined with
+// ErrInvalidIngredientData, or nil.
+func ValidateIngredientData(ingredientData *IngredientData) error {
+	var problems []error
+	seen := map[string]bool{}
+	checkName := func(kind, name string) {
+		key := kind + "\x00" + normalizeName(name)
+		switch {
+		case name == "":
+			problems = append(problems, fmt.Errorf("%s without a name", kind))
+			return
+		case seen[key]:
+			problems = append(problems, fmt.Errorf("%s %s: listed more than once", kind, name))
+		}
+		seen[key] = true
+	}
+
+	for _, hop := range ingredientData.Hops {
+		checkName(IngredientHop, hop.Name)
+		if hop.AlphaAcidMin > hop.AlphaAcidMax {
+			problems = append(problems, fmt.Errorf("hop %s: alpha_acid_min %g exceeds alpha_acid_max %g",
+				hop.Name, hop.AlphaAcidMin, hop.AlphaAcidMax))
+		}
+	}
+	for _, hop := range ingredientData.Hops {
+		for _, substitute := range hop.Substitutes {
+			if !seen[IngredientHop+"\x00"+normalizeName(substitute)] {
+				problems = append(problems,
+					fmt.Errorf("hop %s: substitute %s is not in the data", hop.Name, substitute))
+			}
+		}
+	}
+	for _, fermentable := range ingredientData.Fermentables {
+		checkName(IngredientFermentable, fermentable.Name)
+		if fermentable.Lovibond < 0 || fermentable.PPG < 0 {
+			problems = append(problems, fmt.Errorf("fermentable %s: lovibond and ppg cannot be negative",
+				fermentable.Name))
+		}
+	}
+	for _, yeast := range ingredientData.Yeasts {
+		checkName(IngredientYeast, yeast.Code)
+		if yeast.Name == "" {
+			problems = append(problems, fmt.Errorf("yeast %s: no name", yeast.Code))
+		}
+		if yeast.AttenuationMin > yeast.AttenuationMax {
+			problems = append(problems, fmt.Errorf("yeast %s: attenuation_min %g exceeds attenuation_max %g",
+				yeast.Code, yeast.AttenuationMin, yeast.AttenuationMax))
+		}
+		if yeast.TempMinF > yeast.TempMaxF {
+			problems = append(problems, fmt.Errorf("yeast %s: temp_min_f %g exceeds temp_max_f %g",
+				yeast.Code, yeast.TempMinF, yeast.TempMaxF))
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %w", ErrInvalidIngredientData, errors.Join(problems...))
+}
+
+// IngredientService looks up hops, fermentables, and yeast strains in the ingredient reference
+// data, matching names as the style searches do. The data is read-only, so it is safe for
+// concurrent use.
+type IngredientService struct {
+	data *IngredientData
+}
+
+// NewIngredientServiceFromData creates an IngredientService over ingredientData.
+func NewIngredientServiceFromData(ingredientData *IngredientData) *IngredientService {
+	return &IngredientService{data: ingredientData}
+}
+
+// NewIngredientService creates an IngredientService over the embedded ingredient data.
+func NewIngredientService() (*IngredientService, error) {
+	ingredientData, err := LoadIngredientData()
+	if err != nil {
+		return nil, err
+	}
+	return NewIngredientServiceFromData(ingredientData), nil
+}
+
+// Data returns the ingredient data served. It must not be modified.
+func (s *IngredientService) Data() *IngredientData {
+	return s.data
+}
+
+// IngredientMatch is an ingredient ranked by SearchIngredients, with its name similarity from 0 to
+// 1. Exactly one of Hop, Fermentable, and Yeast is set, as Kind says.
+type IngredientMatch struct {
+	Kind        string       `json:"kind"`
+	Name        string       `json:"name"`
+	Score       float64      `json:"score"`
+	Hop         *Hop         `json:"hop,omitempty"`
+	Fermentable *Fermentable `json:"fermentable,omitempty"`
+	Yeast       *Yeast       `json:"yeast,omitempty"`
+}
+
+// SearchIngredients ranks the ingredients of a kind, or of every kind when kind is empty, by how
+// closely their names or aliases match name, tolerating typos and partial names, and returns up to
+// limit of them, best first. Yeast strains also match by code, with or without their lab, such as
+// "WLP001" or "Wyeast 1056". A non-positive limit returns all candidates.
+func (s *IngredientService) SearchIngredients(name, kind string, limit int) []IngredientMatch {
+	query := normalizeName(name)
+	if query == "" {
+		return []IngredientMatch{}
+	}
+
+	candidates := []IngredientMatch{}
+	consider := func(match IngredientMatch, names ...string) {
+		for _, candidate := range names {
+			match.Score = max(match.Score, nameSimilarity(query, normalizeName(candidate)))
+		}
+		if match.Score >= minNameSimilarity {
+			candidates = append(candidates, match)
+		}
+	}
+	if kind == "" || kind == IngredientHop {
+		for i := range s.data.Hops {
+			hop := &s.data.Hops[i]
+			consider(IngredientMatch{Kind: IngredientHop, Name: hop.Name, Hop: hop},
+				append([]string{hop.Name}, hop.Aliases...)...)
+		}
+	}
+	if kind == "" || kind == IngredientFermentable {
+		for i := range s.data.Fermentables {
+			fermentable := &s.data.Fermentables[i]
+			consider(IngredientMatch{Kind: IngredientFermentable, Name: fermentable.Name, Fermentable: fermentable},
+				append([]string{fermentable.Name}, fermentable.Aliases...)...)
+		}
+	}
+	if kind == "" || kind == IngredientYeast {
+		for i := range s.data.Yeasts {
+			yeast := &s.data.Yeasts[i]
+			consider(IngredientMatch{Kind: IngredientYeast, Name: yeast.Label(), Yeast: yeast},
+				yeast.Name, yeast.Code, yeast.Lab+" "+yeast.Code, yeast.Label())
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Score > candidates[j].Score
+	})
+	if limit > 0 && len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+	return candidates
+}
+
+// FindIngredient returns the best match of a kind for name when it is close enough to be taken as
+// the ingredient meant, see minIngredientMatch. Otherwise it fails with an error matching
+// ErrIngredientNotFound that names the closest candidates.
+func (s *IngredientService) FindIngredient(name, kind string) (IngredientMatch, error) {
+	matches := s.SearchIngredients(name, kind, 3)
+	if len(matches) > 0 && matches[0].Score >= minIngredientMatch {
+		return matches[0], nil
+	}
+	err := fmt.Errorf("%w: no %s named %q", ErrIngredientNotFound, kind, name)
+	if len(matches) > 0 {
+		names := make([]string, 0, len(matches))
+		for _, match := range matches {
+			names = append(names, match.Name)
+		}
+		err = fmt.Errorf("%w; did you mean %s?", err, strings.Join(names, ", "))
+	}
+	return IngredientMatch{}, err
+}
+
+// FindHop returns the hop that name refers to, matched as SearchIngredients matches names but only
+// when the match is close, so "citra" and "Cascde" find a hop and "IPA" does not.
+func (s *IngredientService) FindHop(name string) (*Hop, error) {
+	match, err := s.FindIngredient(name, IngredientHop)
+	if err != nil {
+		return nil, err
+	}
+	return match.Hop, nil
+}
+
+// FindFermentable returns the fermentable that name refers to, see FindHop.
+func (s *IngredientService) FindFermentable(name string) (*Fermentable, error) {
+	match, err := s.FindIngredient(name, IngredientFermentable)
+	if err != nil {
+		return nil, err
+	}
+	return match.Fermentable, nil
+}
+
+// FindYeast returns the yeast strain that name or code refers to, see FindHop.
+func (s *IngredientService) FindYeast(name string) (*Yeast, error) {
+	match, err := s.FindIngredient(name, IngredientYeast)
+	if err != nil {
+		return nil, err
+	}
+	return match.Yeast, nil
+}
+
+// CompleteIngredientName lists the names of a kind's ingredients that start with prefix, ignoring
+// case, in alphabetical order. Yeast strains complete by code.
+func (s *IngredientService) CompleteIngredientName(kind, prefix string) []string {
+	var names []string
+	switch kind {
+	case IngredientHop:
+		for _, hop := range s.data.Hops {
+			names = append(names, hop.Name)
+		}
+	case IngredientFermentable:
+		for _, fermentable := range s.data.Fermentables {
+			names = append(names, fermentable.Name)
+		}
+	case IngredientYeast:
+		for _, yeast := range s.data.Yeasts {
+			names = append(names, yeast.Code)
+		}
+	}
+
+	prefix = strings.ToLower(prefix)
+	completions := []string{}
+	for _, name := range names {
+		if strings.HasPrefix(strings.ToLower(name), prefix) {
+			completions = append(completions, name)
+		}
+	}
+	slices.SortFunc(completions, func(a, b string) int {
+		return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+	})
+	return completions
+}
@@ -0,0 +1,135 @@
+package data_test
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/CharlRitter/brewsource-mcp/app/pkg/data"
+)
+
+// newIngredientService returns a service over the embedded ingredient data.
+func newIngredientService(t *testing.T) *data.IngredientService {
+	t.Helper()
+	svc, err := data.NewIngredientService()
+	if err != nil {
+		t.Fatalf("Failed to load the embedded ingredients: %v", err)
+	}
+	return svc
+}
+
+func TestLoadIngredientData(t *testing.T) {
+	ingredientData := newIngredientService(t).Data()
+	if len(ingredientData.Hops) == 0 || len(ingredientData.Fermentables) == 0 || len(ingredientData.Yeasts) == 0 {
+		t.Fatalf("Expected every kind of ingredient, got %d hops, %d fermentables, and %d yeasts",
+			len(ingredientData.Hops), len(ingredientData.Fermentables), len(ingredientData.Yeasts))
+	}
+}
+
+func TestFindIngredients(t *testing.T) {
+	svc := newIngredientService(t)
+
+	hops := []struct {
+		query    string
+		expected string
+	}{
+		{"Citra", "Citra"},
+		{"cascde", "Cascade"},
+		{"CTZ", "Columbus"},
+		{"Hallertau", "Hallertau Mittelfrüh"},
+		{"hallertau blanc", "Hallertau Blanc"},
+	}
+	for _, tt := range hops {
+		hop, err := svc.FindHop(tt.query)
+		if err != nil || hop.Name != tt.expected {
+			t.Errorf("FindHop(%q): expected %s, got %v (%v)", tt.query, tt.expected, hop, err)
+		}
+	}
+
+	fermentable, err := svc.FindFermentable("C60")
+	if err != nil || fermentable.Name != "Crystal 60" || fermentable.Lovibond != 60 {
+		t.Errorf("Expected C60 to find Crystal 60, got %v (%v)", fermentable, err)
+	}
+	for _, query := range []string{"WLP001", "white labs wlp001", "California Ale"} {
+		if yeast, err := svc.FindYeast(query); err != nil || yeast.Code != "WLP001" {
+			t.Errorf("FindYeast(%q): expected WLP001, got %v (%v)", query, yeast, err)
+		}
+	}
+
+	_, err = svc.FindHop("Citrus")
+	if !errors.Is(err, data.ErrIngredientNotFound) || !strings.Contains(err.Error(), "did you mean Citra") {
+		t.Errorf("Expected a not found error suggesting Citra, got %v", err)
+	}
+	if _, err = svc.FindHop("Crystal 60"); err == nil {
+		t.Error("Expected a fermentable not to be found as a hop")
+	}
+}
+
+func TestSearchIngredients(t *testing.T) {
+	svc := newIngredientService(t)
+
+	matches := svc.SearchIngredients("crystal", "", 0)
+	kinds := map[string]bool{}
+	for _, match := range matches {
+		kinds[match.Kind] = true
+	}
+	if !kinds[data.IngredientHop] || !kinds[data.IngredientFermentable] {
+		t.Errorf("Expected the Crystal hop and the crystal malts, got %v", matches)
+	}
+	if matches[0].Name != "Crystal" || matches[0].Hop == nil {
+		t.Errorf("Expected the exact name first, got %v", matches[0])
+	}
+
+	if limited := svc.SearchIngredients("crystal", data.IngredientFermentable, 2); len(limited) != 2 ||
+		limited[0].Fermentable == nil {
+		t.Errorf("Expected two fermentables, got %v", limited)
+	}
+	if empty := svc.SearchIngredients("  ", "", 0); len(empty) != 0 {
+		t.Errorf("Expected no matches for a blank name, got %v", empty)
+	}
+}
+
+func TestCompleteIngredientName(t *testing.T) {
+	svc := newIngredientService(t)
+
+	if got := svc.CompleteIngredientName(data.IngredientHop, "ha"); !slices.Equal(got,
+		[]string{"Hallertau Blanc", "Hallertau Mittelfrüh"}) {
+		t.Errorf("Expected the Hallertau hops, got %v", got)
+	}
+	if got := svc.CompleteIngredientName(data.IngredientYeast, "wlp0"); len(got) == 0 || got[0] != "WLP001" {
+		t.Errorf("Expected yeast codes, got %v", got)
+	}
+	if got := svc.CompleteIngredientName("spice", ""); len(got) != 0 {
+		t.Errorf("Expected no completions for an unknown kind, got %v", got)
+	}
+}
+
+func TestValidateIngredientData(t *testing.T) {
+	ingredientData := &data.IngredientData{
+		Hops: []data.Hop{
+			{Name: "Citra", AlphaAcidMin: 15, AlphaAcidMax: 11, Substitutes: []string{"Mosaic"}},
+			{Name: "citra"},
+		},
+		Fermentables: []data.Fermentable{{Name: "Crystal 60", Lovibond: -60}, {}},
+		Yeasts:       []data.Yeast{{Name: "American Ale", Code: "1056", TempMinF: 72, TempMaxF: 60}},
+	}
+
+	err := data.ValidateIngredientData(ingredientData)
+	if !errors.Is(err, data.ErrInvalidIngredientData) {
+		t.Fatalf("Expected ErrInvalidIngredientData, got %v", err)
+	}
+	expected := []string{
+		"hop Citra: alpha_acid_min 15 exceeds alpha_acid_max 11",
+		"hop citra: listed more than once",
+		"hop Citra: substitute Mosaic is not in the data",
+		"fermentable Crystal 60: lovibond and ppg cannot be negative",
+		"fermentable without a name",
+		"yeast 1056: temp_min_f 72 exceeds temp_max_f 60",
+	}
+	for _, want := range expected {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected the error to report %q, got: %v", want, err)
+		}
+	}
+}
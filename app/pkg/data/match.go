@@ -0,0 +1,137 @@
+package data
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// ErrNoVitalsProvided is returned when a style match is requested without any vitals.
+var ErrNoVitalsProvided = errors.New("at least one vital (og, fg, abv, ibu, srm) is required")
+
+// VitalsQuery holds measured or planned recipe vitals. Nil fields are not scored.
+type VitalsQuery struct {
+	OG  *float64
+	FG  *float64
+	ABV *float64
+	IBU *float64
+	SRM *float64
+}
+
+// IsEmpty reports whether no vital is set.
+func (q VitalsQuery) IsEmpty() bool {
+	return q.OG == nil && q.FG == nil && q.ABV == nil && q.IBU == nil && q.SRM == nil
+}
+
+// VitalCheck reports how a single vital compares against a style's range.
+type VitalCheck struct {
+	Vital    string  `json:"vital"`
+	Value    float64 `json:"value"`
+	Min      float64 `json:"min"`
+	Max      float64 `json:"max"`
+	InRange  bool    `json:"in_range"`
+	Distance float64 `json:"distance"`
+}
+
+// StyleMatch is a scored candidate style for a VitalsQuery.
+type StyleMatch struct {
+	Style   BJCPStyle    `json:"style"`
+	Matched int          `json:"matched"`
+	Checked int          `json:"checked"`
+	Score   float64      `json:"score"`
+	Checks  []VitalCheck `json:"checks"`
+}
+
+// vitalRange is a single vital's value and the style range it is checked against.
+type vitalRange struct {
+	name     string
+	value    *float64
+	min, max float64
+}
+
+// MatchStyles scores every style against the provided vitals and returns the best
+// matches first. A vital scores 1 when it falls inside the style range and decays
+// linearly to 0 the further it falls outside, measured in multiples of the range width.
+// Styles without published vitals are skipped. A non-positive limit returns all matches.
+func (s *BJCPService) MatchStyles(query VitalsQuery, limit int) ([]StyleMatch, error) {
+	if query.IsEmpty() {
+		return nil, ErrNoVitalsProvided
+	}
+
+	matches := []StyleMatch{}
+	for _, style := range s.Data().Styles {
+		if !style.Vitals.HasRanges() {
+			continue
+		}
+		matches = append(matches, CheckStyle(style, query))
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Matched != matches[j].Matched {
+			return matches[i].Matched > matches[j].Matched
+		}
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return matches[i].Style.Code < matches[j].Style.Code
+	})
+
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches, nil
+}
+
+// HasRanges reports whether any vital range has been published for the style.
+// Specialty styles such as "Clone Beer" leave every vital unset.
+func (v Vitals) HasRanges() bool {
+	return v != Vitals{}
+}
+
+// CheckStyle compares the vitals against a single style's ranges, scoring them as MatchStyles
+// does. Each provided vital is checked; one the style publishes no range for is never in range.
+func CheckStyle(style BJCPStyle, query VitalsQuery) StyleMatch {
+	v := style.Vitals
+	ranges := []vitalRange{
+		{name: "og", value: query.OG, min: v.OGMin, max: v.OGMax},
+		{name: "fg", value: query.FG, min: v.FGMin, max: v.FGMax},
+		{name: "abv", value: query.ABV, min: v.ABVMin, max: v.ABVMax},
+		{name: "ibu", value: query.IBU, min: float64(v.IBUMin), max: float64(v.IBUMax)},
+		{name: "srm", value: query.SRM, min: v.SRMMin, max: v.SRMMax},
+	}
+
+	match := StyleMatch{Style: style}
+	total := 0.0
+	for _, r := range ranges {
+		if r.value == nil {
+			continue
+		}
+		match.Checked++
+		check := VitalCheck{Vital: r.name, Value: *r.value, Min: r.min, Max: r.max}
+		// An unset range (0-0) can neither pass nor contribute to the score.
+		if r.min == 0 && r.max == 0 {
+			match.Checks = append(match.Checks, check)
+			continue
+		}
+		switch {
+		case check.Value < r.min:
+			check.Distance = r.min - check.Value
+		case check.Value > r.max:
+			check.Distance = check.Value - r.max
+		default:
+			check.InRange = true
+		}
+		if check.InRange {
+			match.Matched++
+			total++
+		} else {
+			width := math.Max(r.max-r.min, math.SmallestNonzeroFloat64)
+			total += math.Max(0, 1-check.Distance/width)
+		}
+		match.Checks = append(match.Checks, check)
+	}
+	if match.Checked > 0 {
+		match.Score = total / float64(match.Checked)
+	}
+	return match
+}
@@ -0,0 +1,142 @@
+package data_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/CharlRitter/brewsource-mcp/app/pkg/data"
+)
+
+func float64Ptr(v float64) *float64 {
+	return &v
+}
+
+// mockMatchData returns the shared mock styles plus a style with no published vitals.
+func mockMatchData() *data.BJCPData {
+	bjcpData := mockBJCPData()
+	bjcpData.Styles["29A"] = data.BJCPStyle{Code: "29A", Name: "Fruit Beer", Category: "Fruit Beer"}
+	return bjcpData
+}
+
+func TestMatchStyles_HappyPath(t *testing.T) {
+	svc := data.NewBJCPServiceFromData(mockMatchData())
+
+	matches, err := svc.MatchStyles(data.VitalsQuery{
+		OG:  float64Ptr(1.062),
+		FG:  float64Ptr(1.011),
+		ABV: float64Ptr(6.7),
+		IBU: float64Ptr(60),
+		SRM: float64Ptr(8),
+	}, 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(matches) != 3 {
+		t.Fatalf("expected 3 matches, got %d", len(matches))
+	}
+
+	best := matches[0]
+	if best.Style.Code != "21A" {
+		t.Errorf("expected 21A to be the best match, got %s", best.Style.Code)
+	}
+	if best.Matched != 5 || best.Checked != 5 {
+		t.Errorf("expected 5/5 vitals in range, got %d/%d", best.Matched, best.Checked)
+	}
+	if best.Score != 1 {
+		t.Errorf("expected a perfect score, got %.2f", best.Score)
+	}
+	if len(best.Checks) != 5 {
+		t.Errorf("expected 5 vital checks, got %d", len(best.Checks))
+	}
+}
+
+func TestMatchStyles_NearMissRanking(t *testing.T) {
+	svc := data.NewBJCPServiceFromData(mockBJCPData())
+
+	// 6.9% ABV is just outside Doppelbock's range but far from American Light Lager.
+	matches, err := svc.MatchStyles(data.VitalsQuery{ABV: float64Ptr(6.9)}, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	scores := map[string]data.StyleMatch{}
+	for _, m := range matches {
+		scores[m.Style.Code] = m
+	}
+	doppelbock, lightLager := scores["9A"], scores["1A"]
+	if doppelbock.Matched != 0 || doppelbock.Checks[0].InRange {
+		t.Errorf("expected Doppelbock ABV to be out of range")
+	}
+	if doppelbock.Checks[0].Distance <= 0 {
+		t.Errorf("expected a positive distance for a near miss, got %v", doppelbock.Checks[0].Distance)
+	}
+	if doppelbock.Score <= lightLager.Score {
+		t.Errorf("expected near miss to outscore a distant style: %.2f <= %.2f", doppelbock.Score, lightLager.Score)
+	}
+}
+
+func TestMatchStyles_SkipsStylesWithoutVitals(t *testing.T) {
+	svc := data.NewBJCPServiceFromData(mockMatchData())
+
+	matches, err := svc.MatchStyles(data.VitalsQuery{IBU: float64Ptr(20)}, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, m := range matches {
+		if m.Style.Code == "29A" {
+			t.Error("expected style without vitals to be excluded")
+		}
+	}
+	if len(matches) != len(mockBJCPData().Styles) {
+		t.Errorf("expected %d matches, got %d", len(mockBJCPData().Styles), len(matches))
+	}
+}
+
+func TestMatchStyles_NoVitals(t *testing.T) {
+	svc := data.NewBJCPServiceFromData(mockBJCPData())
+
+	_, err := svc.MatchStyles(data.VitalsQuery{}, 5)
+	if !errors.Is(err, data.ErrNoVitalsProvided) {
+		t.Errorf("expected ErrNoVitalsProvided, got %v", err)
+	}
+}
+
+func TestMatchStyles_EmptyDatabase(t *testing.T) {
+	svc := data.NewBJCPServiceFromData(mockEmptyBJCPData())
+
+	matches, err := svc.MatchStyles(data.VitalsQuery{OG: float64Ptr(1.050)}, 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(matches) != 0 {
+		t.Errorf("expected no matches, got %d", len(matches))
+	}
+}
+
+func TestVitals_HasRanges(t *testing.T) {
+	if (data.Vitals{}).HasRanges() {
+		t.Error("expected zero vitals to report no ranges")
+	}
+	if !(data.Vitals{IBUMax: 10}).HasRanges() {
+		t.Error("expected vitals with an IBU range to report ranges")
+	}
+}
+
+func TestCheckStyle(t *testing.T) {
+	style := mockBJCPData().Styles["21A"]
+
+	match := data.CheckStyle(style, data.VitalsQuery{ABV: float64Ptr(6.5), IBU: float64Ptr(80)})
+	if match.Checked != 2 || match.Matched != 1 {
+		t.Fatalf("expected 1/2 vitals in range, got %d/%d", match.Matched, match.Checked)
+	}
+	ibu := match.Checks[1]
+	if ibu.Vital != "ibu" || ibu.InRange || ibu.Distance != 10 {
+		t.Errorf("expected IBU 80 to miss the 40-70 range by 10, got %+v", ibu)
+	}
+
+	// A style without published vitals cannot confirm any of them.
+	match = data.CheckStyle(data.BJCPStyle{Code: "29A"}, data.VitalsQuery{SRM: float64Ptr(8)})
+	if match.Checked != 1 || match.Matched != 0 || match.Checks[0].InRange {
+		t.Errorf("expected the unpublished SRM range to be out of range, got %+v", match)
+	}
+}
@@ -0,0 +1,118 @@
+package data
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// relatedStyleCount is the number of related styles derived for a style without any.
+const relatedStyleCount = 3
+
+// SimilarStyle is a style ranked by SimilarStyles.
+type SimilarStyle struct {
+	Style BJCPStyle `json:"style"`
+	// Distance is the mean gap between the two styles' vital midpoints, each in units of the
+	// vital's average range width; 0 means identical midpoints.
+	Distance     float64 `json:"distance"`
+	SameCategory bool    `json:"same_category"`
+}
+
+// SimilarStyles ranks the other styles by their vitals distance from the style with code, nearest
+// first, then by code. Styles that share no published vital with it are left out.
+func (s *BJCPService) SimilarStyles(code string) ([]SimilarStyle, error) {
+	return s.SimilarStylesVersion(code, DefaultGuidelineVersion)
+}
+
+// SimilarStylesVersion is SimilarStyles over a guideline version, see DataVersion.
+func (s *BJCPService) SimilarStylesVersion(code, version string) ([]SimilarStyle, error) {
+	bjcpData, err := s.DataVersion(version)
+	if err != nil {
+		return nil, err
+	}
+	code = NormalizeStyleCode(code)
+	style, ok := bjcpData.Styles[code]
+	if !ok {
+		return nil, fmt.Errorf("style with code %s not found", code)
+	}
+
+	similar := rankStyles(bjcpData, style)
+	kept := similar[:0]
+	for _, candidate := range similar {
+		if !math.IsInf(candidate.Distance, 1) {
+			kept = append(kept, candidate)
+		}
+	}
+	return kept, nil
+}
+
+// StyleDistance measures how far apart two styles' vitals are: the mean, over the vitals both
+// publish, of the gap between their range midpoints divided by the average width of the two
+// ranges. It is +Inf when they share no published vital.
+func StyleDistance(a, b *BJCPStyle) float64 {
+	total := 0.0
+	compared := 0
+	for _, diff := range CompareStyles(a, b).Vitals {
+		if (diff.AMin == 0 && diff.AMax == 0) || (diff.BMin == 0 && diff.BMax == 0) {
+			continue
+		}
+		width := math.Max(((diff.AMax-diff.AMin)+(diff.BMax-diff.BMin))/2, math.SmallestNonzeroFloat64)
+		total += math.Abs(diff.MidpointDelta) / width
+		compared++
+	}
+	if compared == 0 {
+		return math.Inf(1)
+	}
+	return total / float64(compared)
+}
+
+// rankStyles orders every other style by StyleDistance from style, then by code.
+func rankStyles(bjcpData *BJCPData, style BJCPStyle) []SimilarStyle {
+	ranked := make([]SimilarStyle, 0, len(bjcpData.Styles))
+	for code, candidate := range bjcpData.Styles {
+		if code == style.Code {
+			continue
+		}
+		ranked = append(ranked, SimilarStyle{
+			Style:        candidate,
+			Distance:     StyleDistance(&style, &candidate),
+			SameCategory: strings.EqualFold(candidate.Category, style.Category),
+		})
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].Distance != ranked[j].Distance {
+			return ranked[i].Distance < ranked[j].Distance
+		}
+		return CompareStyleCodes(ranked[i].Style.Code, ranked[j].Style.Code) < 0
+	})
+	return ranked
+}
+
+// deriveRelatedStyles fills in RelatedStyles for the styles that have none: the nearest styles by
+// vitals in the same category, then, when the category has too few, the nearest from the others.
+// Styles without published vitals relate to the rest of their category only.
+func deriveRelatedStyles(bjcpData *BJCPData) {
+	for code, style := range bjcpData.Styles {
+		if len(style.RelatedStyles) > 0 {
+			continue
+		}
+		ranked := rankStyles(bjcpData, style)
+		related := []string{}
+		for _, candidate := range ranked {
+			if candidate.SameCategory && len(related) < relatedStyleCount {
+				related = append(related, candidate.Style.Code)
+			}
+		}
+		for _, candidate := range ranked {
+			if len(related) == relatedStyleCount || math.IsInf(candidate.Distance, 1) {
+				break
+			}
+			if !candidate.SameCategory {
+				related = append(related, candidate.Style.Code)
+			}
+		}
+		style.RelatedStyles = related
+		bjcpData.Styles[code] = style
+	}
+}
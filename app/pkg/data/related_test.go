@@ -0,0 +1,114 @@
+package data_test
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/CharlRitter/brewsource-mcp/app/pkg/data"
+)
+
+func TestRelatedStyles_Derived(t *testing.T) {
+	svc := newGuidelineService(t)
+
+	tests := []struct {
+		code     string
+		expected []string
+	}{
+		// Nearest in the category first, then the nearest English IPA from another category.
+		{"21A", []string{"21C", "21B", "12C"}},
+		// A two-style category is filled up from the others.
+		{"18B", []string{"18A", "11C", "27F"}},
+		// Styles without vitals relate to their category only.
+		{"34A", []string{"34B", "34C"}},
+	}
+	for _, tt := range tests {
+		style, err := svc.GetStyleByCode(tt.code)
+		if err != nil {
+			t.Fatalf("Failed to get %s: %v", tt.code, err)
+		}
+		if !reflect.DeepEqual(style.RelatedStyles, tt.expected) {
+			t.Errorf("Expected %s related to %v, got %v", tt.code, tt.expected, style.RelatedStyles)
+		}
+	}
+}
+
+func TestRelatedStyles_FromDataFile(t *testing.T) {
+	contents := `{"styles": {` +
+		`"1A": {"code": "1A", "name": "House Lager", "category": "House", "related_styles": ["1C"]},` +
+		`"1B": {"code": "1B", "name": "House Ale", "category": "House"},` +
+		`"1C": {"code": "1C", "name": "House Stout", "category": "House"}},` +
+		`"categories": ["House"], "metadata": {"version": "house-1"}}`
+	path := filepath.Join(t.TempDir(), "styles.json")
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatalf("Failed to write test data file: %v", err)
+	}
+	t.Setenv(data.BJCPDataPathEnv, path)
+
+	bjcpData, err := data.LoadBJCPData()
+	if err != nil {
+		t.Fatalf("Failed to load the data file: %v", err)
+	}
+	if related := bjcpData.Styles["1A"].RelatedStyles; !reflect.DeepEqual(related, []string{"1C"}) {
+		t.Errorf("Expected the file's related styles kept, got %v", related)
+	}
+	if related := bjcpData.Styles["1B"].RelatedStyles; !reflect.DeepEqual(related, []string{"1A", "1C"}) {
+		t.Errorf("Expected 1B related to the rest of its category, got %v", related)
+	}
+}
+
+func TestSimilarStyles(t *testing.T) {
+	svc := newGuidelineService(t)
+
+	tests := []struct {
+		code     string
+		expected []string
+	}{
+		{"21A", []string{"12C", "17A", "21C", "11C"}},
+		{"10A", []string{"1C", "24A", "1D", "2B"}},
+	}
+	for _, tt := range tests {
+		similar, err := svc.SimilarStyles(tt.code)
+		if err != nil {
+			t.Fatalf("Failed to rank styles similar to %s: %v", tt.code, err)
+		}
+		codes := make([]string, 0, len(tt.expected))
+		for _, style := range similar[:len(tt.expected)] {
+			codes = append(codes, style.Style.Code)
+		}
+		if !reflect.DeepEqual(codes, tt.expected) {
+			t.Errorf("Expected %s nearest to %v, got %v", tt.code, tt.expected, codes)
+		}
+		for _, style := range similar {
+			if style.Style.Code == tt.code || math.IsInf(style.Distance, 1) {
+				t.Errorf("Expected neither %s itself nor styles without vitals, got %s", tt.code, style.Style.Code)
+			}
+		}
+	}
+
+	if similar, err := svc.SimilarStyles("34A"); err != nil || len(similar) != 0 {
+		t.Errorf("Expected nothing similar to a style without vitals, got %v, %v", similar, err)
+	}
+	if _, err := svc.SimilarStyles("99Z"); err == nil {
+		t.Error("Expected an error for an unknown style")
+	}
+}
+
+func TestStyleDistance(t *testing.T) {
+	svc := data.NewBJCPServiceFromData(mockBJCPData())
+	ipa, _ := svc.GetStyleByCode("21A")
+	lager, _ := svc.GetStyleByCode("1A")
+	specialty := &data.BJCPStyle{Code: "34Z", Name: "Clone Beer"}
+
+	if distance := data.StyleDistance(ipa, ipa); distance != 0 {
+		t.Errorf("Expected a style to be 0 from itself, got %v", distance)
+	}
+	if data.StyleDistance(ipa, lager) != data.StyleDistance(lager, ipa) {
+		t.Error("Expected the distance to be symmetric")
+	}
+	if distance := data.StyleDistance(ipa, specialty); !math.IsInf(distance, 1) {
+		t.Errorf("Expected no distance to a style without vitals, got %v", distance)
+	}
+}
@@ -0,0 +1,126 @@
+package data
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// ErrNoVitalRange is returned when a style search by vitals is requested without any bounds.
+var ErrNoVitalRange = errors.New("at least one vital bound (abv, ibu, srm, og) is required")
+
+// VitalWindow is a requested range for one vital. A nil bound leaves that side of the range open.
+type VitalWindow struct {
+	Min *float64 `json:"min,omitempty"`
+	Max *float64 `json:"max,omitempty"`
+}
+
+// IsSet reports whether either bound of the window is set.
+func (w VitalWindow) IsSet() bool {
+	return w.Min != nil || w.Max != nil
+}
+
+// VitalsCriteria selects styles whose vital ranges overlap the requested windows. Windows left
+// unset are not checked.
+type VitalsCriteria struct {
+	ABV VitalWindow `json:"abv"`
+	IBU VitalWindow `json:"ibu"`
+	SRM VitalWindow `json:"srm"`
+	OG  VitalWindow `json:"og"`
+	// IncludeUnspecified keeps styles that publish no range (0-0) for a requested vital, such as
+	// the specialty styles, which are left out otherwise.
+	IncludeUnspecified bool `json:"include_unspecified"`
+}
+
+// StyleSearchResult is a style found by SearchStylesByVitals. Centrality is 1 when the middle of
+// every requested window sits at the middle of the style's range and falls to 0 at its edges.
+type StyleSearchResult struct {
+	Style      BJCPStyle `json:"style"`
+	Centrality float64   `json:"centrality"`
+}
+
+// SearchStylesByVitals returns the styles whose vital ranges overlap every window set in criteria,
+// the most central first, then by code. An open side of a window takes the style's own bound when
+// locating the window's middle.
+func (s *BJCPService) SearchStylesByVitals(criteria VitalsCriteria) ([]StyleSearchResult, error) {
+	if !criteria.ABV.IsSet() && !criteria.IBU.IsSet() && !criteria.SRM.IsSet() && !criteria.OG.IsSet() {
+		return nil, ErrNoVitalRange
+	}
+
+	results := []StyleSearchResult{}
+	for _, style := range s.Data().Styles {
+		if result, ok := searchStyle(style, criteria); ok {
+			results = append(results, result)
+		}
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Centrality != results[j].Centrality {
+			return results[i].Centrality > results[j].Centrality
+		}
+		return results[i].Style.Code < results[j].Style.Code
+	})
+	return results, nil
+}
+
+// searchStyle checks a style against criteria and scores how centrally the windows fall within it.
+func searchStyle(style BJCPStyle, criteria VitalsCriteria) (StyleSearchResult, bool) {
+	v := style.Vitals
+	ranges := []struct {
+		window   VitalWindow
+		min, max float64
+	}{
+		{criteria.ABV, v.ABVMin, v.ABVMax},
+		{criteria.IBU, float64(v.IBUMin), float64(v.IBUMax)},
+		{criteria.SRM, v.SRMMin, v.SRMMax},
+		{criteria.OG, v.OGMin, v.OGMax},
+	}
+
+	total := 0.0
+	scored := 0
+	for _, r := range ranges {
+		if !r.window.IsSet() {
+			continue
+		}
+		if r.min == 0 && r.max == 0 {
+			if !criteria.IncludeUnspecified {
+				return StyleSearchResult{}, false
+			}
+			continue
+		}
+		low, high := r.min, r.max
+		if r.window.Min != nil {
+			if *r.window.Min > r.max {
+				return StyleSearchResult{}, false
+			}
+			low = *r.window.Min
+		}
+		if r.window.Max != nil {
+			if *r.window.Max < r.min {
+				return StyleSearchResult{}, false
+			}
+			high = *r.window.Max
+		}
+		total += centrality((low+high)/2, r.min, r.max)
+		scored++
+	}
+
+	result := StyleSearchResult{Style: style}
+	if scored > 0 {
+		result.Centrality = total / float64(scored)
+	}
+	return result, true
+}
+
+// centrality scores point by its distance from the middle of [min, max]: 1 at the middle, 0 at an
+// edge or beyond.
+func centrality(point, minimum, maximum float64) float64 {
+	halfWidth := (maximum - minimum) / 2
+	if halfWidth <= 0 {
+		if point == minimum {
+			return 1
+		}
+		return 0
+	}
+	return math.Max(0, 1-math.Abs(point-(minimum+halfWidth))/halfWidth)
+}
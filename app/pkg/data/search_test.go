@@ -0,0 +1,96 @@
+package data_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/CharlRitter/brewsource-mcp/app/pkg/data"
+)
+
+func styleCodes(results []data.StyleSearchResult) []string {
+	codes := make([]string, 0, len(results))
+	for _, result := range results {
+		codes = append(codes, result.Style.Code)
+	}
+	return codes
+}
+
+func TestSearchStylesByVitals(t *testing.T) {
+	svc := data.NewBJCPServiceFromData(mockMatchData())
+
+	tests := []struct {
+		name     string
+		criteria data.VitalsCriteria
+		expected []string
+	}{
+		{
+			name: "IBU window and SRM ceiling",
+			criteria: data.VitalsCriteria{
+				IBU: data.VitalWindow{Min: float64Ptr(40), Max: float64Ptr(70)},
+				SRM: data.VitalWindow{Max: float64Ptr(10)},
+			},
+			expected: []string{"21A", "34A"},
+		},
+		{
+			name:     "unspecified vitals included on request",
+			criteria: data.VitalsCriteria{IBU: data.VitalWindow{Min: float64Ptr(40)}, IncludeUnspecified: true},
+			expected: []string{"21A", "34A", "29A"},
+		},
+		{
+			name:     "ranges touching at an edge overlap",
+			criteria: data.VitalsCriteria{ABV: data.VitalWindow{Min: float64Ptr(4.2), Max: float64Ptr(5.5)}},
+			expected: []string{"34A", "1A", "21A"},
+		},
+		{
+			name:     "no overlap",
+			criteria: data.VitalsCriteria{OG: data.VitalWindow{Min: float64Ptr(1.3)}},
+			expected: []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := svc.SearchStylesByVitals(tt.criteria)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			codes := styleCodes(results)
+			if len(codes) != len(tt.expected) {
+				t.Fatalf("expected styles %v, got %v", tt.expected, codes)
+			}
+			for i := range codes {
+				if codes[i] != tt.expected[i] {
+					t.Errorf("expected styles %v, got %v", tt.expected, codes)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestSearchStylesByVitals_Centrality(t *testing.T) {
+	svc := data.NewBJCPServiceFromData(mockBJCPData())
+
+	// 40-70 IBU is exactly American IPA's range, and 6-10 SRM is centred 2 SRM below its middle.
+	results, err := svc.SearchStylesByVitals(data.VitalsCriteria{
+		IBU: data.VitalWindow{Min: float64Ptr(40), Max: float64Ptr(70)},
+		SRM: data.VitalWindow{Min: float64Ptr(6), Max: float64Ptr(10)},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) == 0 || results[0].Style.Code != "21A" {
+		t.Fatalf("expected 21A first, got %v", styleCodes(results))
+	}
+	if results[0].Centrality != 0.75 {
+		t.Errorf("expected a centrality of 0.75, got %v", results[0].Centrality)
+	}
+}
+
+func TestSearchStylesByVitals_NoBounds(t *testing.T) {
+	svc := data.NewBJCPServiceFromData(mockBJCPData())
+
+	_, err := svc.SearchStylesByVitals(data.VitalsCriteria{IncludeUnspecified: true})
+	if !errors.Is(err, data.ErrNoVitalRange) {
+		t.Errorf("expected ErrNoVitalRange, got %v", err)
+	}
+}
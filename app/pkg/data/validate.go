@@ -0,0 +1,123 @@
+package data
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ErrInvalidBJCPData is returned, joined with every problem found, for style data that fails
+// ValidateBJCPData.
+var ErrInvalidBJCPData = errors.New("invalid BJCP data")
+
+// ValidateBJCPData checks style data for inconsistencies that would otherwise only surface as odd
+// tool output: styles without a code or name, or filed under a key other than their code, vitals
+// whose minimum exceeds their maximum, categories and related styles that are not in the data, and
+// a metadata total_styles other than the number of styles. A total_styles of 0 is taken as unset.
+// It reports every problem found, joined with ErrInvalidBJCPData, or nil.
+func ValidateBJCPData(bjcpData *BJCPData) error {
+	return joinProblems(dataProblems(bjcpData))
+}
+
+// joinProblems wraps the problems found in style data with ErrInvalidBJCPData, or returns nil when
+// there are none.
+func joinProblems(problems []error) error {
+	if len(problems) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %w", ErrInvalidBJCPData, errors.Join(problems...))
+}
+
+// dataProblems lists the problems ValidateBJCPData reports, in style code order.
+func dataProblems(bjcpData *BJCPData) []error {
+	var problems []error
+	categories := make(map[string]bool, len(bjcpData.Categories))
+	for _, category := range bjcpData.Categories {
+		categories[strings.ToLower(category)] = true
+	}
+
+	keys := make([]string, 0, len(bjcpData.Styles))
+	for key := range bjcpData.Styles {
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, CompareStyleCodes)
+
+	codes := make(map[string]string, len(keys))
+	for _, key := range keys {
+		style := bjcpData.Styles[key]
+		problem := func(format string, args ...interface{}) {
+			problems = append(problems, fmt.Errorf("style %s: %s", key, fmt.Sprintf(format, args...)))
+		}
+
+		switch {
+		case style.Code == "":
+			problem("no code")
+		case style.Code != key:
+			problem("code %s differs from its key", style.Code)
+		}
+		if other, ok := codes[style.Code]; ok && style.Code != "" {
+			problem("code %s is also used by style %s", style.Code, other)
+		}
+		codes[style.Code] = key
+		if strings.TrimSpace(style.Name) == "" {
+			problem("no name")
+		}
+		if style.Category != "" && !categories[strings.ToLower(style.Category)] {
+			problem("category %q is not in the categories list", style.Category)
+		}
+		for _, diff := range CompareStyles(&style, &style).Vitals {
+			if diff.AMin > diff.AMax {
+				problem("%s_min %g exceeds %s_max %g", diff.Vital, diff.AMin, diff.Vital, diff.AMax)
+			}
+		}
+		for _, related := range style.RelatedStyles {
+			if _, ok := bjcpData.Styles[related]; !ok {
+				problem("related style %s is not in the data", related)
+			}
+		}
+	}
+
+	if total := bjcpData.Metadata.TotalStyles; total != 0 && total != len(bjcpData.Styles) {
+		problems = append(problems, fmt.Errorf("metadata total_styles is %d, but there are %d styles",
+			total, len(bjcpData.Styles)))
+	}
+	return problems
+}
+
+// duplicateStyleKeys lists the keys that appear more than once in the styles object of raw style
+// data. Decoding keeps only the last style under a repeated key, so ValidateBJCPData cannot see
+// them.
+func duplicateStyleKeys(raw []byte) []string {
+	var document struct {
+		Styles json.RawMessage `json:"styles"`
+	}
+	if json.Unmarshal(raw, &document) != nil || len(document.Styles) == 0 {
+		return nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(document.Styles))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return nil
+	}
+	seen := map[string]bool{}
+	var duplicates []string
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return duplicates
+		}
+		key, _ := token.(string)
+		if seen[key] && !slices.Contains(duplicates, key) {
+			duplicates = append(duplicates, key)
+		}
+		seen[key] = true
+		var value json.RawMessage
+		if err = decoder.Decode(&value); err != nil {
+			return duplicates
+		}
+	}
+	return duplicates
+}
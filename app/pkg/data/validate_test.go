@@ -0,0 +1,65 @@
+package data_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/CharlRitter/brewsource-mcp/app/pkg/data"
+)
+
+func TestValidateBJCPData(t *testing.T) {
+	if err := data.ValidateBJCPData(mockBJCPData()); err != nil {
+		t.Errorf("Expected the mock data to be valid, got: %v", err)
+	}
+
+	bjcpData := mockBJCPData()
+	ipa := bjcpData.Styles["21A"]
+	ipa.Vitals.IBUMin, ipa.Vitals.IBUMax = 70, 40
+	ipa.RelatedStyles = []string{"1A", "99Z"}
+	bjcpData.Styles["21A"] = ipa
+	lager := bjcpData.Styles["1A"]
+	bjcpData.Styles["1a"] = lager
+	bjcpData.Styles["2A"] = data.BJCPStyle{Name: "Nameless Code"}
+	bjcpData.Metadata.TotalStyles = 4
+
+	err := data.ValidateBJCPData(bjcpData)
+	if !errors.Is(err, data.ErrInvalidBJCPData) {
+		t.Fatalf("Expected ErrInvalidBJCPData, got %v", err)
+	}
+	expected := []string{
+		"style 1A: code 1A is also used by style 1a",
+		"style 1a: code 1A differs from its key",
+		"style 2A: no code",
+		"style 21A: ibu_min 70 exceeds ibu_max 40",
+		"style 21A: related style 99Z is not in the data",
+		"metadata total_styles is 4, but there are 6 styles",
+	}
+	for _, want := range expected {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected %q among the problems, got:\n%v", want, err)
+		}
+	}
+	if lines := strings.Count(err.Error(), "\n") + 1; lines != len(expected) {
+		t.Errorf("Expected %d problems, got %d:\n%v", len(expected), lines, err)
+	}
+}
+
+func TestLoadBJCPData_DuplicateStyles(t *testing.T) {
+	contents := `{"styles": {` +
+		`"1A": {"code": "1A", "name": "House Lager"},` +
+		`"1A": {"code": "1A", "name": "House Lager, Again"}},` +
+		`"metadata": {"version": "house-1"}}`
+	path := filepath.Join(t.TempDir(), "styles.json")
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatalf("Failed to write test data file: %v", err)
+	}
+	t.Setenv(data.BJCPDataPathEnv, path)
+
+	_, err := data.LoadBJCPData()
+	if !errors.Is(err, data.ErrInvalidBJCPData) || !strings.Contains(err.Error(), "styles listed more than once: 1A") {
+		t.Errorf("Expected the repeated style reported, got %v", err)
+	}
+}